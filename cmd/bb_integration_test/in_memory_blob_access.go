@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/slicing"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// inMemoryBlobAccess is a trivial Content Addressable Storage that
+// keeps all objects in a map. Objects are never evicted, which is
+// acceptable considering that this process only runs a small number of
+// scenarios.
+type inMemoryBlobAccess struct {
+	lock  sync.RWMutex
+	blobs map[string][]byte
+}
+
+func newInMemoryBlobAccess() blobstore.BlobAccess {
+	return &inMemoryBlobAccess{
+		blobs: map[string][]byte{},
+	}
+}
+
+func (ba *inMemoryBlobAccess) Get(ctx context.Context, blobDigest digest.Digest) buffer.Buffer {
+	ba.lock.RLock()
+	data, ok := ba.blobs[blobDigest.GetKey(digest.KeyWithoutInstance)]
+	ba.lock.RUnlock()
+	if !ok {
+		return buffer.NewBufferFromError(status.Error(codes.NotFound, "Object not found"))
+	}
+	return buffer.NewValidatedBufferFromByteSlice(data)
+}
+
+func (ba *inMemoryBlobAccess) GetFromComposite(ctx context.Context, parentDigest, childDigest digest.Digest, slicer slicing.BlobSlicer) buffer.Buffer {
+	return buffer.NewBufferFromError(status.Error(codes.Unimplemented, "Composite objects are not supported"))
+}
+
+func (ba *inMemoryBlobAccess) Put(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+	data, err := b.ToByteSlice(int(blobDigest.GetSizeBytes()))
+	if err != nil {
+		return err
+	}
+	ba.lock.Lock()
+	ba.blobs[blobDigest.GetKey(digest.KeyWithoutInstance)] = data
+	ba.lock.Unlock()
+	return nil
+}
+
+func (ba *inMemoryBlobAccess) FindMissing(ctx context.Context, digests digest.Set) (digest.Set, error) {
+	missing := digest.NewSetBuilder()
+	ba.lock.RLock()
+	for _, blobDigest := range digests.Items() {
+		if _, ok := ba.blobs[blobDigest.GetKey(digest.KeyWithoutInstance)]; !ok {
+			missing.Add(blobDigest)
+		}
+	}
+	ba.lock.RUnlock()
+	return missing.Build(), nil
+}
+
+func (ba *inMemoryBlobAccess) GetCapabilities(ctx context.Context, instanceName digest.InstanceName) (*remoteexecution.ServerCapabilities, error) {
+	return nil, status.Error(codes.Unimplemented, "Capabilities are not supported")
+}
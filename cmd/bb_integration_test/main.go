@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-remote-execution/pkg/cleaner"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	virtual_configuration "github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual/configuration"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/configuration/bb_integration_test"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/initialsizeclass"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/invocation"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/platform"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/routing"
+	"github.com/buildbarn/bb-storage/pkg/auth"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/global"
+	"github.com/buildbarn/bb-storage/pkg/program"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/google/uuid"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// bb_integration_test runs a scheduler, a worker backed by a virtual
+// file system, a runner and a Content Addressable Storage within a
+// single process. It executes the scenarios declared in its
+// configuration file against this stack, and terminates with a
+// non-zero exit code if any of them fail.
+//
+// This makes it possible to validate changes to the scheduler and the
+// worker end-to-end, without needing to deploy these components to a
+// cluster.
+
+func main() {
+	program.RunMain(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+		if len(os.Args) != 2 {
+			return status.Error(codes.InvalidArgument, "Usage: bb_integration_test bb_integration_test.jsonnet")
+		}
+		var configuration bb_integration_test.ApplicationConfiguration
+		if err := util.UnmarshalConfigurationFromFile(os.Args[1], &configuration); err != nil {
+			return util.StatusWrapf(err, "Failed to read configuration from %s", os.Args[1])
+		}
+		if _, _, err := global.ApplyConfiguration(configuration.Global); err != nil {
+			return util.StatusWrap(err, "Failed to apply global configuration options")
+		}
+		maximumMessageSizeBytes := int(configuration.MaximumMessageSizeBytes)
+		if configuration.Concurrency < 1 {
+			return status.Error(codes.InvalidArgument, "Concurrency must be positive")
+		}
+
+		instanceName, err := digest.NewInstanceName(configuration.InstanceName)
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid instance name %#v", configuration.InstanceName)
+		}
+		digestFunction, err := instanceName.GetDigestFunction(remoteexecution.DigestFunction_SHA256, 0)
+		if err != nil {
+			return util.StatusWrap(err, "Failed to obtain digest function")
+		}
+		contentAddressableStorage := newInMemoryBlobAccess()
+
+		// Scheduler.
+		allowAllAuthorizer := auth.NewStaticAuthorizer(func(digest.InstanceName) bool { return true })
+		buildQueue := scheduler.NewInMemoryBuildQueue(
+			contentAddressableStorage,
+			clock.SystemClock,
+			uuid.NewRandom,
+			&scheduler.InMemoryBuildQueueConfiguration{
+				ExecutionUpdateInterval:           time.Minute,
+				OperationWithNoWaitersTimeout:     time.Minute,
+				PlatformQueueWithNoWorkersTimeout: 15 * time.Minute,
+				BusyWorkerSynchronizationInterval: 10 * time.Second,
+				GetIdleWorkerSynchronizationInterval: func(consecutiveTimeouts int) time.Duration {
+					return time.Minute
+				},
+				WorkerTaskRetryCount:                9,
+				WorkerWithNoSynchronizationsTimeout: time.Minute,
+			},
+			maximumMessageSizeBytes,
+			routing.NewSimpleActionRouter(
+				platform.NewActionAndCommandKeyExtractor(contentAddressableStorage, maximumMessageSizeBytes),
+				[]invocation.KeyExtractor{invocation.CorrelatedInvocationsIDKeyExtractor},
+				initialsizeclass.NewFallbackAnalyzer(
+					initialsizeclass.NewActionTimeoutExtractor(time.Hour, time.Hour))),
+			allowAllAuthorizer,
+			allowAllAuthorizer,
+			allowAllAuthorizer)
+
+		// Build directory, backed by a virtual file system.
+		mount, handleAllocator, err := virtual_configuration.NewMountFromConfiguration(
+			configuration.BuildDirectoryMount,
+			"bb_integration_test",
+			/* rootDirectory = */ virtual_configuration.ShortAttributeCaching,
+			/* childDirectories = */ virtual_configuration.LongAttributeCaching,
+			/* leaves = */ virtual_configuration.LongAttributeCaching)
+		if err != nil {
+			return util.StatusWrap(err, "Failed to create build directory mount")
+		}
+		symlinkFactory := virtual.NewHandleAllocatingSymlinkFactory(
+			virtual.BaseSymlinkFactory,
+			handleAllocator.New())
+		characterDeviceFactory := virtual.NewHandleAllocatingCharacterDeviceFactory(
+			virtual.BaseCharacterDeviceFactory,
+			handleAllocator.New())
+		virtualBuildDirectory := virtual.NewInMemoryPrepopulatedDirectory(
+			virtual.NewHandleAllocatingFileAllocator(
+				virtual.NewPoolBackedFileAllocator(
+					re_filesystem.EmptyFilePool,
+					util.DefaultErrorLogger,
+					nil),
+				handleAllocator),
+			symlinkFactory,
+			util.DefaultErrorLogger,
+			handleAllocator,
+			sort.Sort,
+			func(s string) bool { return false },
+			clock.SystemClock,
+			/* caseInsensitive = */ false)
+		if err := mount.Expose(dependenciesGroup, virtualBuildDirectory); err != nil {
+			return util.StatusWrap(err, "Failed to expose build directory mount")
+		}
+
+		// Runner, executing commands within the build directory.
+		buildDirectoryPath, scopeWalker := path.EmptyBuilder.Join(path.NewAbsoluteScopeWalker(path.VoidComponentWalker))
+		if err := path.Resolve(configuration.BuildDirectoryMount.GetMountPath(), scopeWalker); err != nil {
+			return util.StatusWrap(err, "Failed to resolve build directory")
+		}
+		buildDirectoryPathString := buildDirectoryPath.String()
+		runnerServer := runner.NewLocalRunner(
+			re_filesystem.NewLazyDirectory(
+				func() (filesystem.DirectoryCloser, error) {
+					return filesystem.NewLocalDirectory(buildDirectoryPathString)
+				}),
+			buildDirectoryPath,
+			runner.NewPlainCommandCreator(&syscall.SysProcAttr{}),
+			/* setTmpdirEnvironmentVariable = */ false,
+			/* cgroupCreator = */ nil,
+			/* processTreeTracer = */ nil,
+			/* schedulingPriorities = */ nil)
+
+		// Let the components communicate with each other through
+		// gRPC, using an in-process connection.
+		listener := bufconn.Listen(1024 * 1024)
+		server := grpc.NewServer()
+		remoteexecution.RegisterExecutionServer(server, buildQueue)
+		remoteworker.RegisterOperationQueueServer(server, buildQueue)
+		runner_pb.RegisterRunnerServer(server, runnerServer)
+		dependenciesGroup.Go(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+			go func() {
+				<-ctx.Done()
+				server.Stop()
+			}()
+			return server.Serve(listener)
+		})
+		clientConnection, err := grpc.DialContext(
+			ctx,
+			"bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return util.StatusWrap(err, "Failed to create in-process gRPC client")
+		}
+
+		// Worker threads.
+		browserURL, err := url.Parse("http://localhost/")
+		if err != nil {
+			panic(err)
+		}
+		schedulerClient := remoteworker.NewOperationQueueClient(clientConnection)
+		runnerClient := runner_pb.NewRunnerClient(clientConnection)
+		directoryFetcher := cas.NewBlobAccessDirectoryFetcher(
+			contentAddressableStorage,
+			maximumMessageSizeBytes,
+			/* maximumTreeSizeBytes = */ 0)
+		buildDirectoryIdleInvoker := cleaner.NewIdleInvoker(func(ctx context.Context) error {
+			if err := virtualBuildDirectory.RemoveAllChildren(false); err != nil {
+				return util.StatusWrapWithCode(err, codes.Internal, "Failed to clean virtual build directory")
+			}
+			return nil
+		})
+		var sharedBuildDirectoryNextParallelActionID atomic.Uint64
+		for threadID := uint64(0); threadID < configuration.Concurrency; threadID++ {
+			buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(
+				builder.NewCleanBuildDirectoryCreator(
+					builder.NewRootBuildDirectoryCreator(
+						builder.NewVirtualBuildDirectory(
+							virtualBuildDirectory,
+							directoryFetcher,
+							contentAddressableStorage,
+							symlinkFactory,
+							characterDeviceFactory,
+							handleAllocator,
+							/* eagerUploadSemaphore = */ nil,
+							/* passthroughCacheDirectory = */ "",
+							/* onlyUploadModifiedOutputs = */ false,
+							/* reportOperationStatistics = */ false)),
+					buildDirectoryIdleInvoker),
+				&sharedBuildDirectoryNextParallelActionID,
+				/* randomNameGenerator = */ nil)
+			buildExecutor := builder.NewLoggingBuildExecutor(
+				builder.NewTimestampedBuildExecutor(
+					builder.NewLocalBuildExecutor(
+						contentAddressableStorage,
+						buildDirectoryCreator,
+						runnerClient,
+						clock.SystemClock,
+						/* inputRootCharacterDevices = */ nil,
+						maximumMessageSizeBytes,
+						/* environmentVariables = */ nil,
+						/* platformPropertyEnvironmentVariables = */ nil,
+						/* vcsMetadataEnvironmentVariables = */ nil,
+						/* failedActionPauser = */ nil,
+						/* forceUploadTreesAndDirectories = */ false,
+						/* outputPruner = */ nil,
+						/* actionKeepalive = */ nil,
+						/* secretEnvironmentVariables = */ nil,
+						/* fakeTime = */ nil,
+						/* commandOutputTruncation = */ nil,
+						/* outputLimits = */ nil,
+						/* actionResultVerification = */ nil,
+						/* nestedExecutionProxy = */ nil,
+						/* outputPathValidation = */ nil),
+					clock.SystemClock,
+					"bb_integration_test"),
+				browserURL)
+			workerName := strconv.FormatUint(threadID, 10)
+			buildClient := builder.NewBuildClient(
+				schedulerClient,
+				buildExecutor,
+				re_filesystem.InMemoryFilePool,
+				clock.SystemClock,
+				map[string]string{"thread": workerName},
+				instanceName,
+				/* instanceNameMapping = */ nil,
+				builder.NewAdvertisedPlatform(&remoteexecution.Platform{}, 0),
+				/* inputRootPrefetcher = */ nil,
+				/* idlePrefetcher = */ nil,
+				/* gracefulShutdownTimeout = */ 0,
+				/* workerResourcesReporter = */ nil,
+				/* terminationTimeReporter = */ nil,
+				/* concurrencyLimit = */ nil,
+				/* memoryAdmission = */ nil)
+			builder.LaunchWorkerThread(dependenciesGroup, buildClient, workerName, nil, 0)
+		}
+
+		// Run all scenarios. Once this function returns, all of
+		// the components above are shut down.
+		scenarioRunner := scenarioRunner{
+			executionClient:           remoteexecution.NewExecutionClient(clientConnection),
+			contentAddressableStorage: contentAddressableStorage,
+			digestFunction:            digestFunction,
+			maximumMessageSizeBytes:   maximumMessageSizeBytes,
+		}
+		failedScenarios := 0
+		for _, scenario := range configuration.Scenarios {
+			if err := scenarioRunner.run(ctx, scenario); err != nil {
+				log.Printf("Scenario %#v: FAILED: %s", scenario.Name, err)
+				failedScenarios++
+			} else {
+				log.Printf("Scenario %#v: PASSED", scenario.Name)
+			}
+		}
+		if failedScenarios > 0 {
+			return status.Errorf(codes.FailedPrecondition, "%d out of %d scenarios failed", failedScenarios, len(configuration.Scenarios))
+		}
+		return nil
+	})
+}
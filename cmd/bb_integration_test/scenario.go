@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/configuration/bb_integration_test"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// scenarioRunner is capable of executing the scenarios that are
+// declared in the configuration file, and validating their results.
+type scenarioRunner struct {
+	executionClient           remoteexecution.ExecutionClient
+	contentAddressableStorage blobstore.BlobAccess
+	digestFunction            digest.Function
+	maximumMessageSizeBytes   int
+}
+
+// inputDirectory is used to construct the input root of an action.
+type inputDirectory struct {
+	files       map[path.Component]*bb_integration_test.InputFile
+	directories map[path.Component]*inputDirectory
+}
+
+func newInputDirectory() *inputDirectory {
+	return &inputDirectory{
+		files:       map[path.Component]*bb_integration_test.InputFile{},
+		directories: map[path.Component]*inputDirectory{},
+	}
+}
+
+func (d *inputDirectory) addFile(file *bb_integration_test.InputFile) error {
+	components := strings.Split(file.Path, "/")
+	for _, name := range components[:len(components)-1] {
+		component, ok := path.NewComponent(name)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "Invalid pathname component %#v", name)
+		}
+		if _, ok := d.files[component]; ok {
+			return status.Errorf(codes.InvalidArgument, "Path %#v conflicts with another input file", file.Path)
+		}
+		child, ok := d.directories[component]
+		if !ok {
+			child = newInputDirectory()
+			d.directories[component] = child
+		}
+		d = child
+	}
+
+	name := components[len(components)-1]
+	component, ok := path.NewComponent(name)
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "Invalid pathname component %#v", name)
+	}
+	if _, ok := d.files[component]; ok {
+		return status.Errorf(codes.InvalidArgument, "Path %#v conflicts with another input file", file.Path)
+	}
+	if _, ok := d.directories[component]; ok {
+		return status.Errorf(codes.InvalidArgument, "Path %#v conflicts with another input file", file.Path)
+	}
+	d.files[component] = file
+	return nil
+}
+
+func sortedComponents[T any](m map[path.Component]T) []path.Component {
+	components := make([]path.Component, 0, len(m))
+	for component := range m {
+		components = append(components, component)
+	}
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].String() < components[j].String()
+	})
+	return components
+}
+
+// upload the contents of the directory and all of its children into
+// the Content Addressable Storage.
+func (r *scenarioRunner) uploadInputDirectory(ctx context.Context, d *inputDirectory) (digest.Digest, error) {
+	var directory remoteexecution.Directory
+	for _, component := range sortedComponents(d.files) {
+		file := d.files[component]
+		fileDigest, err := r.putBlob(ctx, []byte(file.Contents))
+		if err != nil {
+			return digest.BadDigest, err
+		}
+		directory.Files = append(directory.Files, &remoteexecution.FileNode{
+			Name:         component.String(),
+			Digest:       fileDigest.GetProto(),
+			IsExecutable: file.IsExecutable,
+		})
+	}
+	for _, component := range sortedComponents(d.directories) {
+		childDigest, err := r.uploadInputDirectory(ctx, d.directories[component])
+		if err != nil {
+			return digest.BadDigest, err
+		}
+		directory.Directories = append(directory.Directories, &remoteexecution.DirectoryNode{
+			Name:   component.String(),
+			Digest: childDigest.GetProto(),
+		})
+	}
+	return r.putMessage(ctx, &directory)
+}
+
+func (r *scenarioRunner) putBlob(ctx context.Context, data []byte) (digest.Digest, error) {
+	digestGenerator := r.digestFunction.NewGenerator(int64(len(data)))
+	if _, err := digestGenerator.Write(data); err != nil {
+		panic(err)
+	}
+	blobDigest := digestGenerator.Sum()
+	if err := r.contentAddressableStorage.Put(ctx, blobDigest, buffer.NewValidatedBufferFromByteSlice(data)); err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to store object")
+	}
+	return blobDigest, nil
+}
+
+func (r *scenarioRunner) putMessage(ctx context.Context, m proto.Message) (digest.Digest, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to marshal message")
+	}
+	return r.putBlob(ctx, data)
+}
+
+func (r *scenarioRunner) getBlob(ctx context.Context, blobDigest *remoteexecution.Digest) ([]byte, error) {
+	d, err := r.digestFunction.NewDigestFromProto(blobDigest)
+	if err != nil {
+		return nil, err
+	}
+	return r.contentAddressableStorage.Get(ctx, d).ToByteSlice(r.maximumMessageSizeBytes)
+}
+
+// run a single scenario, returning an error if the action could not
+// be executed, or if its results do not match the expectations.
+func (r *scenarioRunner) run(ctx context.Context, scenario *bb_integration_test.Scenario) error {
+	// Upload the input root, Command and Action.
+	inputRoot := newInputDirectory()
+	for _, file := range scenario.InputFiles {
+		if err := inputRoot.addFile(file); err != nil {
+			return err
+		}
+	}
+	inputRootDigest, err := r.uploadInputDirectory(ctx, inputRoot)
+	if err != nil {
+		return util.StatusWrap(err, "Failed to upload input root")
+	}
+
+	command := &remoteexecution.Command{
+		Arguments:   scenario.Arguments,
+		OutputPaths: append([]string(nil), scenario.OutputPaths...),
+	}
+	sort.Strings(command.OutputPaths)
+	for name, value := range scenario.EnvironmentVariables {
+		command.EnvironmentVariables = append(command.EnvironmentVariables, &remoteexecution.Command_EnvironmentVariable{
+			Name:  name,
+			Value: value,
+		})
+	}
+	sort.Slice(command.EnvironmentVariables, func(i, j int) bool {
+		return command.EnvironmentVariables[i].Name < command.EnvironmentVariables[j].Name
+	})
+	commandDigest, err := r.putMessage(ctx, command)
+	if err != nil {
+		return util.StatusWrap(err, "Failed to upload command")
+	}
+
+	timeout := durationpb.New(time.Minute)
+	if scenario.Timeout != nil {
+		if err := scenario.Timeout.CheckValid(); err != nil {
+			return util.StatusWrap(err, "Invalid timeout")
+		}
+		timeout = scenario.Timeout
+	}
+	actionDigest, err := r.putMessage(ctx, &remoteexecution.Action{
+		CommandDigest:   commandDigest.GetProto(),
+		InputRootDigest: inputRootDigest.GetProto(),
+		Timeout:         timeout,
+		DoNotCache:      true,
+	})
+	if err != nil {
+		return util.StatusWrap(err, "Failed to upload action")
+	}
+
+	// Execute the action and wait for it to complete.
+	stream, err := r.executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+		InstanceName:    r.digestFunction.GetInstanceName().String(),
+		ActionDigest:    actionDigest.GetProto(),
+		SkipCacheLookup: true,
+		DigestFunction:  r.digestFunction.GetEnumValue(),
+	})
+	if err != nil {
+		return util.StatusWrap(err, "Failed to execute action")
+	}
+	var response remoteexecution.ExecuteResponse
+	for {
+		operation, err := stream.Recv()
+		if err != nil {
+			return util.StatusWrap(err, "Failed to receive execution update")
+		}
+		if operation.Done {
+			if err := status.ErrorProto(operation.GetError()); err != nil {
+				return util.StatusWrap(err, "Execution failed")
+			}
+			if err := operation.GetResponse().UnmarshalTo(&response); err != nil {
+				return util.StatusWrap(err, "Failed to unmarshal execute response")
+			}
+			break
+		}
+	}
+	if err := status.ErrorProto(response.Status); err != nil {
+		return util.StatusWrap(err, "Execution failed")
+	}
+
+	// Validate the results against the expectations.
+	result := response.Result
+	if result.ExitCode != scenario.ExpectedExitCode {
+		return status.Errorf(codes.FailedPrecondition, "Expected exit code %d, while the action exited with code %d", scenario.ExpectedExitCode, result.ExitCode)
+	}
+	if scenario.ExpectedStdout != "" {
+		stdout := result.StdoutRaw
+		if result.StdoutDigest != nil {
+			stdout, err = r.getBlob(ctx, result.StdoutDigest)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to load standard output")
+			}
+		}
+		if !bytes.Equal(stdout, []byte(scenario.ExpectedStdout)) {
+			return status.Errorf(codes.FailedPrecondition, "Expected standard output %#v, while the action wrote %#v", scenario.ExpectedStdout, string(stdout))
+		}
+	}
+	outputFiles := map[string]*remoteexecution.OutputFile{}
+	for _, outputFile := range result.OutputFiles {
+		outputFiles[outputFile.Path] = outputFile
+	}
+	for outputPath, expectedContents := range scenario.ExpectedOutputFiles {
+		outputFile, ok := outputFiles[outputPath]
+		if !ok {
+			return status.Errorf(codes.FailedPrecondition, "Action did not create output file %#v", outputPath)
+		}
+		contents, err := r.getBlob(ctx, outputFile.Digest)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to load output file %#v", outputPath)
+		}
+		if !bytes.Equal(contents, []byte(expectedContents)) {
+			return status.Errorf(codes.FailedPrecondition, "Expected output file %#v to contain %#v, while it contains %#v", outputPath, expectedContents, string(contents))
+		}
+	}
+	return nil
+}
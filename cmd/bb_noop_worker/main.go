@@ -80,9 +80,16 @@ func main() {
 			clock.SystemClock,
 			configuration.WorkerId,
 			instanceNamePrefix,
-			configuration.Platform,
-			0)
-		builder.LaunchWorkerThread(siblingsGroup, buildClient, "noop")
+			nil,
+			builder.NewAdvertisedPlatform(configuration.Platform, 0),
+			nil,
+			nil,
+			0,
+			nil,
+			nil,
+			nil,
+			nil)
+		builder.LaunchWorkerThread(siblingsGroup, buildClient, "noop", nil, 0)
 
 		lifecycleState.MarkReadyAndWait(siblingsGroup)
 		return nil
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sort"
+	"syscall"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/configuration/bb_replay_action"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	blobstore_configuration "github.com/buildbarn/bb-storage/pkg/blobstore/configuration"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/global"
+	"github.com/buildbarn/bb-storage/pkg/program"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// bb_replay_action loads an action from the Content Addressable
+// Storage, executes it locally using the same code paths as bb_worker,
+// and compares its outputs against the result stored in the Action
+// Cache. This makes it possible to reproduce failures of actions that
+// only occur when executed remotely.
+//
+// The action digest needs to be provided in the form of a ByteStream
+// read path, e.g.:
+//
+//	bb_replay_action bb_replay_action.jsonnet my-instance/blobs/sha256/0123...cdef/123
+
+func main() {
+	program.RunMain(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+		if len(os.Args) != 3 {
+			return status.Error(codes.InvalidArgument, "Usage: bb_replay_action bb_replay_action.jsonnet instance_name/blobs/digest_function/hash/size_bytes")
+		}
+		var configuration bb_replay_action.ApplicationConfiguration
+		if err := util.UnmarshalConfigurationFromFile(os.Args[1], &configuration); err != nil {
+			return util.StatusWrapf(err, "Failed to read configuration from %s", os.Args[1])
+		}
+		_, grpcClientFactory, err := global.ApplyConfiguration(configuration.Global)
+		if err != nil {
+			return util.StatusWrap(err, "Failed to apply global configuration options")
+		}
+		maximumMessageSizeBytes := int(configuration.MaximumMessageSizeBytes)
+		actionDigest, _, err := digest.NewDigestFromByteStreamReadPath(os.Args[2])
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid action digest %#v", os.Args[2])
+		}
+
+		// Storage access.
+		contentAddressableStorage, actionCache, err := blobstore_configuration.NewCASAndACBlobAccessFromConfiguration(
+			dependenciesGroup,
+			configuration.Blobstore,
+			grpcClientFactory,
+			maximumMessageSizeBytes)
+		if err != nil {
+			return err
+		}
+		actionMessage, err := contentAddressableStorage.Get(ctx, actionDigest).ToProto(&remoteexecution.Action{}, maximumMessageSizeBytes)
+		if err != nil {
+			return util.StatusWrap(err, "Failed to obtain action")
+		}
+		action := actionMessage.(*remoteexecution.Action)
+
+		filePool, err := re_filesystem.NewFilePoolFromConfiguration(configuration.FilePool)
+		if err != nil {
+			return util.StatusWrap(err, "Failed to create file pool")
+		}
+
+		// Build directory. Contents of previous runs are
+		// removed, while contents of this run are left behind.
+		buildDirectory, err := filesystem.NewLocalDirectory(configuration.BuildDirectoryPath)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to open build directory %#v", configuration.BuildDirectoryPath)
+		}
+		if err := buildDirectory.RemoveAllChildren(); err != nil {
+			return util.StatusWrap(err, "Failed to clear build directory")
+		}
+
+		// Runner, executing the command within the build
+		// directory. Either use an external runner process, or
+		// one that is embedded into this process.
+		var runnerConnection grpc.ClientConnInterface
+		if configuration.Runner != nil {
+			runnerConnection, err = grpcClientFactory.NewClientFromConfiguration(configuration.Runner)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create runner RPC client")
+			}
+		} else {
+			buildDirectoryPath, scopeWalker := path.EmptyBuilder.Join(path.NewAbsoluteScopeWalker(path.VoidComponentWalker))
+			if err := path.Resolve(configuration.BuildDirectoryPath, scopeWalker); err != nil {
+				return util.StatusWrap(err, "Failed to resolve build directory")
+			}
+			buildDirectoryPathString := buildDirectoryPath.String()
+			runnerServer := runner.NewLocalRunner(
+				re_filesystem.NewLazyDirectory(
+					func() (filesystem.DirectoryCloser, error) {
+						return filesystem.NewLocalDirectory(buildDirectoryPathString)
+					}),
+				buildDirectoryPath,
+				runner.NewPlainCommandCreator(&syscall.SysProcAttr{}),
+				/* setTmpdirEnvironmentVariable = */ false,
+				/* cgroupCreator = */ nil,
+				/* processTreeTracer = */ nil,
+				/* schedulingPriorities = */ nil)
+
+			listener := bufconn.Listen(1024 * 1024)
+			server := grpc.NewServer()
+			runner_pb.RegisterRunnerServer(server, runnerServer)
+			dependenciesGroup.Go(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+				go func() {
+					<-ctx.Done()
+					server.Stop()
+				}()
+				return server.Serve(listener)
+			})
+			runnerConnection, err = grpc.DialContext(
+				ctx,
+				"bufnet",
+				grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+					return listener.DialContext(ctx)
+				}),
+				grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create in-process gRPC client")
+			}
+		}
+
+		buildExecutor := builder.NewLocalBuildExecutor(
+			contentAddressableStorage,
+			builder.NewRootBuildDirectoryCreator(
+				builder.NewNaiveBuildDirectory(
+					buildDirectory,
+					cas.NewBlobAccessDirectoryFetcher(
+						contentAddressableStorage,
+						maximumMessageSizeBytes,
+						/* maximumTreeSizeBytes = */ 0),
+					cas.NewBlobAccessFileFetcher(contentAddressableStorage),
+					semaphore.NewWeighted(1),
+					/* populationOptions = */ nil,
+					contentAddressableStorage,
+					/* warmInputRootPool = */ nil)),
+			runner_pb.NewRunnerClient(runnerConnection),
+			clock.SystemClock,
+			/* inputRootCharacterDevices = */ nil,
+			maximumMessageSizeBytes,
+			configuration.EnvironmentVariables,
+			/* platformPropertyEnvironmentVariables = */ nil,
+			/* vcsMetadataEnvironmentVariables = */ nil,
+			/* failedActionPauser = */ nil,
+			/* forceUploadTreesAndDirectories = */ false,
+			/* outputPruner = */ nil,
+			/* actionKeepalive = */ nil,
+			/* secretEnvironmentVariables = */ nil,
+			/* fakeTime = */ nil,
+			/* commandOutputTruncation = */ nil,
+			/* outputLimits = */ nil,
+			/* actionResultVerification = */ nil,
+			/* nestedExecutionProxy = */ nil,
+			/* outputPathValidation = */ nil)
+
+		// Execute the action, logging its progress.
+		executionStateUpdates := make(chan *remoteworker.CurrentState_Executing)
+		executionStateUpdatesDone := make(chan struct{})
+		go func() {
+			for executionStateUpdate := range executionStateUpdates {
+				log.Printf("Execution state: %s", protojson.Format(executionStateUpdate))
+			}
+			close(executionStateUpdatesDone)
+		}()
+		response := buildExecutor.Execute(
+			ctx,
+			filePool,
+			/* monitor = */ nil,
+			actionDigest.GetDigestFunction(),
+			&remoteworker.DesiredState_Executing{
+				ActionDigest:   actionDigest.GetProto(),
+				Action:         action,
+				DigestFunction: actionDigest.GetDigestFunction().GetEnumValue(),
+			},
+			executionStateUpdates)
+		close(executionStateUpdates)
+		<-executionStateUpdatesDone
+		fmt.Println(protojson.MarshalOptions{Multiline: true}.Format(response))
+		if err := status.ErrorProto(response.Status); err != nil {
+			return util.StatusWrap(err, "Failed to execute action")
+		}
+
+		// Compare the outputs against those of the result stored
+		// in the Action Cache.
+		cachedActionResultMessage, err := actionCache.Get(ctx, actionDigest).ToProto(&remoteexecution.ActionResult{}, maximumMessageSizeBytes)
+		if status.Code(err) == codes.NotFound {
+			log.Print("Action Cache does not contain a result for this action, so outputs are not compared")
+			return nil
+		} else if err != nil {
+			return util.StatusWrap(err, "Failed to obtain cached action result")
+		}
+		differences := diffActionResults(cachedActionResultMessage.(*remoteexecution.ActionResult), response.Result)
+		if len(differences) > 0 {
+			for _, difference := range differences {
+				log.Print(difference)
+			}
+			return status.Errorf(codes.FailedPrecondition, "Found %d differences compared to the cached action result", len(differences))
+		}
+		log.Print("Outputs are identical to the cached action result")
+		return nil
+	})
+}
+
+// diffActionResults returns a human readable list of differences
+// between the exit code and outputs of two action results.
+func diffActionResults(cached, local *remoteexecution.ActionResult) []string {
+	var differences []string
+	if cached.ExitCode != local.ExitCode {
+		differences = append(differences, fmt.Sprintf("Exit code: cached %d, local %d", cached.ExitCode, local.ExitCode))
+	}
+	differences = append(differences, diffOutputs("Output file", getOutputFiles(cached), getOutputFiles(local))...)
+	differences = append(differences, diffOutputs("Output directory", getOutputDirectories(cached), getOutputDirectories(local))...)
+	differences = append(differences, diffOutputs("Output symlink", getOutputSymlinks(cached), getOutputSymlinks(local))...)
+	return differences
+}
+
+func diffOutputs(kind string, cached, local map[string]string) []string {
+	paths := make([]string, 0, len(cached)+len(local))
+	for p := range cached {
+		paths = append(paths, p)
+	}
+	for p := range local {
+		if _, ok := cached[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	var differences []string
+	for _, p := range paths {
+		cachedValue, inCached := cached[p]
+		localValue, inLocal := local[p]
+		if !inCached {
+			differences = append(differences, fmt.Sprintf("%s %#v: only present locally", kind, p))
+		} else if !inLocal {
+			differences = append(differences, fmt.Sprintf("%s %#v: only present in cached result", kind, p))
+		} else if cachedValue != localValue {
+			differences = append(differences, fmt.Sprintf("%s %#v: cached %s, local %s", kind, p, cachedValue, localValue))
+		}
+	}
+	return differences
+}
+
+func getOutputFiles(actionResult *remoteexecution.ActionResult) map[string]string {
+	outputs := map[string]string{}
+	for _, outputFile := range actionResult.OutputFiles {
+		value := fmt.Sprintf("%s-%d", outputFile.Digest.GetHash(), outputFile.Digest.GetSizeBytes())
+		if outputFile.IsExecutable {
+			value += " (executable)"
+		}
+		outputs[outputFile.Path] = value
+	}
+	return outputs
+}
+
+func getOutputDirectories(actionResult *remoteexecution.ActionResult) map[string]string {
+	outputs := map[string]string{}
+	for _, outputDirectory := range actionResult.OutputDirectories {
+		outputs[outputDirectory.Path] = fmt.Sprintf("%s-%d", outputDirectory.TreeDigest.GetHash(), outputDirectory.TreeDigest.GetSizeBytes())
+	}
+	return outputs
+}
+
+func getOutputSymlinks(actionResult *remoteexecution.ActionResult) map[string]string {
+	outputs := map[string]string{}
+	for _, outputSymlinks := range [][]*remoteexecution.OutputSymlink{
+		actionResult.OutputSymlinks,
+		actionResult.OutputFileSymlinks,
+		actionResult.OutputDirectorySymlinks,
+	} {
+		for _, outputSymlink := range outputSymlinks {
+			outputs[outputSymlink.Path] = fmt.Sprintf("%#v", outputSymlink.Target)
+		}
+	}
+	return outputs
+}
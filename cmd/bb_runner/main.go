@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"os"
+	"path/filepath"
+	"regexp"
 	"time"
 
 	"github.com/buildbarn/bb-remote-execution/pkg/cleaner"
@@ -12,10 +14,12 @@ import (
 	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/tmp_installer"
 	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/clock"
 	"github.com/buildbarn/bb-storage/pkg/filesystem"
 	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
 	"github.com/buildbarn/bb-storage/pkg/global"
 	bb_grpc "github.com/buildbarn/bb-storage/pkg/grpc"
+	bb_http "github.com/buildbarn/bb-storage/pkg/http"
 	"github.com/buildbarn/bb-storage/pkg/program"
 	"github.com/buildbarn/bb-storage/pkg/util"
 
@@ -25,6 +29,12 @@ import (
 )
 
 func main() {
+	// Commands that are run in a PID namespace are launched through
+	// a copy of bb_runner that acts as the namespace's init process.
+	if len(os.Args) == 2 && os.Args[0] == runner.PIDNamespaceInitArgv0 {
+		runner.RunPIDNamespaceInit(os.Args[1])
+	}
+
 	program.RunMain(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
 		if len(os.Args) != 2 {
 			return status.Error(codes.InvalidArgument, "Usage: bb_runner bb_runner.jsonnet")
@@ -38,6 +48,10 @@ func main() {
 			return util.StatusWrap(err, "Failed to apply global configuration options")
 		}
 
+		if len(configuration.VirtualMachineLaunchCommand) > 0 && (configuration.ChrootIntoInputRoot || configuration.RunCommandsAs != nil) {
+			return status.Error(codes.InvalidArgument, "Virtual machine launch command cannot be combined with chroot_into_input_root and run_commands_as, as these options need to be set on the instance of bb_runner running inside the virtual machine")
+		}
+
 		buildDirectoryPath, scopeWalker := path.EmptyBuilder.Join(path.NewAbsoluteScopeWalker(path.VoidComponentWalker))
 		if err := path.Resolve(configuration.BuildDirectoryPath, scopeWalker); err != nil {
 			return util.StatusWrap(err, "Failed to resolve build directory")
@@ -62,12 +76,234 @@ func main() {
 		} else {
 			commandCreator = runner.NewPlainCommandCreator(sysProcAttr)
 		}
+		if configuration.RunCommandsInPidNamespace {
+			commandCreator, err = runner.NewPIDNamespaceCommandCreator(commandCreator)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create PID namespace command creator")
+			}
+		}
+		if terminationGracePeriod := configuration.TerminationGracePeriod; terminationGracePeriod != nil {
+			if err := terminationGracePeriod.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid termination grace period")
+			}
+			commandCreator, err = runner.NewGracefulTerminationCommandCreator(commandCreator, terminationGracePeriod.AsDuration())
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create graceful termination command creator")
+			}
+		}
+
+		var cgroupCreator runner.CgroupCreator
+		if cgroupConfiguration := configuration.Cgroup; cgroupConfiguration != nil {
+			settings := map[string]string{}
+			if v := cgroupConfiguration.MemorySwapMax; v != "" {
+				settings["memory.swap.max"] = v
+			}
+			if v := cgroupConfiguration.MemoryZswapMax; v != "" {
+				settings["memory.zswap.max"] = v
+			}
+			if cgroupConfiguration.DisableZswapWriteback {
+				settings["memory.zswap.writeback"] = "0"
+			}
+			if v := cgroupConfiguration.PidsMax; v != "" {
+				settings["pids.max"] = v
+			}
+			sizeClassSettings := map[uint32]map[string]string{}
+			for sizeClass, v := range cgroupConfiguration.PidsMaxPerSizeClass {
+				sizeClassSettings[sizeClass] = map[string]string{
+					"pids.max": v,
+				}
+			}
+			cgroupCreator, err = runner.NewCgroupV2Creator(cgroupConfiguration.ParentPath, settings, sizeClassSettings)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create cgroup creator")
+			}
+		}
+
+		var processTreeTracer runner.ProcessTreeTracer
+		if processTreeTracingConfiguration := configuration.ProcessTreeTracing; processTreeTracingConfiguration != nil {
+			processTreeTracer, err = runner.NewPtraceProcessTreeTracer(int(processTreeTracingConfiguration.MaximumProcesses))
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create process tree tracer")
+			}
+		}
+
+		schedulingPriorities := make([]runner.SchedulingPriorityRange, 0, len(configuration.SchedulingPriorities))
+		for i, schedulingPriorityConfiguration := range configuration.SchedulingPriorities {
+			if nice := schedulingPriorityConfiguration.Nice; nice < -20 || nice > 19 {
+				return status.Errorf(codes.InvalidArgument, "Scheduling priority at index %d has nice value %d, while it must be in range [-20, 19]", i, nice)
+			}
+			if class := schedulingPriorityConfiguration.IoPriorityClass; class < 0 || class > 3 {
+				return status.Errorf(codes.InvalidArgument, "Scheduling priority at index %d has I/O priority class %d, while it must be in range [0, 3]", i, class)
+			}
+			if level := schedulingPriorityConfiguration.IoPriorityLevel; level < 0 || level > 7 {
+				return status.Errorf(codes.InvalidArgument, "Scheduling priority at index %d has I/O priority level %d, while it must be in range [0, 7]", i, level)
+			}
+			if cpuWeight := schedulingPriorityConfiguration.CpuWeight; cpuWeight != 0 {
+				if cgroupCreator == nil {
+					return status.Errorf(codes.InvalidArgument, "Scheduling priority at index %d has a CPU weight, which requires cgroups to be configured", i)
+				}
+				if cpuWeight > 10000 {
+					return status.Errorf(codes.InvalidArgument, "Scheduling priority at index %d has CPU weight %d, while it must be in range [1, 10000]", i, cpuWeight)
+				}
+			}
+			schedulingPriorities = append(schedulingPriorities, runner.SchedulingPriorityRange{
+				MinimumPriority: schedulingPriorityConfiguration.MinimumPriority,
+				SchedulingPriority: runner.SchedulingPriority{
+					Nice:            int(schedulingPriorityConfiguration.Nice),
+					IOPriorityClass: int(schedulingPriorityConfiguration.IoPriorityClass),
+					IOPriorityLevel: int(schedulingPriorityConfiguration.IoPriorityLevel),
+					CPUWeight:       schedulingPriorityConfiguration.CpuWeight,
+				},
+			})
+		}
+
+		var r runner_pb.RunnerServer
+		if launchCommand := configuration.VirtualMachineLaunchCommand; len(launchCommand) > 0 {
+			// Run every action inside its own virtual
+			// machine, as opposed to running it locally.
+			r = runner.NewVirtualMachineRunner(
+				runner.NewCommandVirtualMachineLauncher(launchCommand[0], launchCommand[1:]))
+		} else {
+			r = runner.NewLocalRunner(
+				buildDirectory,
+				buildDirectoryPath,
+				commandCreator,
+				configuration.SetTmpdirEnvironmentVariable,
+				cgroupCreator,
+				processTreeTracer,
+				schedulingPriorities)
+
+			// Provide a hermetic /proc, /dev and /tmp inside
+			// the input root.
+			if mountsConfiguration := configuration.InputRootMounts; mountsConfiguration != nil {
+				devCharacterDevices, err := getCharacterDevices(mountsConfiguration.DevCharacterDeviceNodes)
+				if err != nil {
+					return util.StatusWrap(err, "Failed to obtain character devices for input root mounts")
+				}
+				var devShm *runner.DevShmConfiguration
+				if mountsConfiguration.DevShm {
+					tmpfsMounter, err := runner.NewLocalTmpfsMounter()
+					if err != nil {
+						return util.StatusWrap(err, "Failed to create tmpfs mounter")
+					}
+					devShm = &runner.DevShmConfiguration{
+						SizeBytes:             mountsConfiguration.DevShmSizeBytes,
+						SizeBytesPerSizeClass: mountsConfiguration.DevShmSizeBytesPerSizeClass,
+						MaximumSizeBytes:      mountsConfiguration.MaximumDevShmSizeBytes,
+						TmpfsMounter:          tmpfsMounter,
+						BuildDirectoryPath:    buildDirectoryPath,
+					}
+				}
+				r = runner.NewInputRootMountingRunner(
+					r,
+					buildDirectory,
+					mountsConfiguration.Proc,
+					devCharacterDevices,
+					devShm,
+					mountsConfiguration.Tmp)
+			}
+
+			// Provide a hermetic /etc/resolv.conf and
+			// /etc/hosts inside the input root.
+			if dnsConfiguration := configuration.Dns; dnsConfiguration != nil {
+				if !configuration.ChrootIntoInputRoot {
+					return status.Error(codes.InvalidArgument, "DNS configuration can only be provided when chrooting into the input root")
+				}
+				resolvConf, err := runner.GenerateResolvConf(
+					dnsConfiguration.Nameservers,
+					dnsConfiguration.SearchDomains,
+					dnsConfiguration.Options)
+				if err != nil {
+					return util.StatusWrap(err, "Failed to generate resolv.conf")
+				}
+				hostsEntries := make([]runner.HostsEntry, 0, len(dnsConfiguration.Hosts))
+				for _, entry := range dnsConfiguration.Hosts {
+					hostsEntries = append(hostsEntries, runner.HostsEntry{
+						Address:   entry.Address,
+						Hostnames: entry.Hostnames,
+					})
+				}
+				hosts, err := runner.GenerateHosts(hostsEntries)
+				if err != nil {
+					return util.StatusWrap(err, "Failed to generate hosts file")
+				}
+
+				// Store the generated files outside the build
+				// directory, so that they can act as the
+				// source of bind mounts.
+				dnsConfigurationDirectory, err := os.MkdirTemp("", "bb_runner_dns")
+				if err != nil {
+					return util.StatusWrap(err, "Failed to create directory for DNS configuration files")
+				}
+				resolvConfPath := filepath.Join(dnsConfigurationDirectory, "resolv.conf")
+				if err := os.WriteFile(resolvConfPath, resolvConf, 0o644); err != nil {
+					return util.StatusWrap(err, "Failed to write resolv.conf")
+				}
+				hostsPath := filepath.Join(dnsConfigurationDirectory, "hosts")
+				if err := os.WriteFile(hostsPath, hosts, 0o644); err != nil {
+					return util.StatusWrap(err, "Failed to write hosts file")
+				}
+
+				bindMounter, err := runner.NewLocalBindMounter()
+				if err != nil {
+					return util.StatusWrap(err, "Failed to create bind mounter")
+				}
+				r = runner.NewDNSConfigurationMountingRunner(
+					r,
+					buildDirectory,
+					buildDirectoryPath,
+					bindMounter,
+					resolvConfPath,
+					hostsPath)
+			}
+
+			// Mount toolchains pinned by bb_worker inside the
+			// input root, if requested by the action.
+			if configuration.PinnedToolchainsDirectoryPath != "" {
+				toolchainsDirectoryPath, scopeWalker := path.EmptyBuilder.Join(path.NewAbsoluteScopeWalker(path.VoidComponentWalker))
+				if err := path.Resolve(configuration.PinnedToolchainsDirectoryPath, scopeWalker); err != nil {
+					return util.StatusWrap(err, "Failed to resolve pinned toolchains directory")
+				}
+				toolchainsDirectoryPathString := toolchainsDirectoryPath.String()
+				toolchainsDirectory := re_filesystem.NewLazyDirectory(
+					func() (filesystem.DirectoryCloser, error) {
+						return filesystem.NewLocalDirectory(toolchainsDirectoryPathString)
+					})
 
-		r := runner.NewLocalRunner(
-			buildDirectory,
-			buildDirectoryPath,
-			commandCreator,
-			configuration.SetTmpdirEnvironmentVariable)
+				bindMounter, err := runner.NewLocalBindMounter()
+				if err != nil {
+					return util.StatusWrap(err, "Failed to create bind mounter")
+				}
+				r = runner.NewPinnedToolchainMountingRunner(
+					r,
+					buildDirectory,
+					buildDirectoryPath,
+					bindMounter,
+					toolchainsDirectory,
+					toolchainsDirectoryPath)
+			}
+
+			// Let an external sandbox binary provide
+			// isolation of actions.
+			if sandboxConfiguration := configuration.Sandbox; sandboxConfiguration != nil {
+				if configuration.ChrootIntoInputRoot {
+					return status.Error(codes.InvalidArgument, "Sandboxing cannot be combined with chrooting into the input root")
+				}
+				sandboxFailureExitCodes := map[int32]struct{}{}
+				for _, exitCode := range sandboxConfiguration.SandboxFailureExitCodes {
+					sandboxFailureExitCodes[exitCode] = struct{}{}
+				}
+				r, err = runner.NewSandboxingRunner(
+					r,
+					buildDirectoryPath,
+					sandboxConfiguration.Command,
+					sandboxFailureExitCodes,
+					sandboxConfiguration.ExitCodeMapping)
+				if err != nil {
+					return util.StatusWrap(err, "Failed to create sandboxing runner")
+				}
+			}
+		}
 
 		// Let bb_runner replace temporary directories with symbolic
 		// links pointing to the temporary directory set up by
@@ -87,22 +323,65 @@ func main() {
 			r = runner.NewTemporaryDirectoryInstallingRunner(r, tmpInstaller)
 		}
 
+		// Only consider processes belonging to the current user
+		// that were created after bb_runner is spawned, as we
+		// don't want to report or kill unrelated processes.
+		startupTime := time.Now()
+		processTable := cleaner.NewFilteringProcessTable(
+			cleaner.SystemProcessTable,
+			func(process *cleaner.Process) bool {
+				return process.UserID == processTableCleaningUserID &&
+					process.CreationTime.After(startupTime)
+			})
+
+		// Report processes that actions leave behind by
+		// daemonizing, before they are killed.
+		if leakedProcessReportingConfiguration := configuration.LeakedProcessReporting; leakedProcessReportingConfiguration != nil {
+			r = runner.NewLeakedProcessReportingRunner(
+				r,
+				processTable,
+				clock.SystemClock,
+				int(leakedProcessReportingConfiguration.MaximumCommandLines))
+		}
+
+		// Capture core dumps of processes of actions that crash.
+		if coreDumpCaptureConfiguration := configuration.CoreDumpCapture; coreDumpCaptureConfiguration != nil {
+			fileNamePattern, err := regexp.Compile(coreDumpCaptureConfiguration.FileNamePattern)
+			if err != nil {
+				return util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid core dump file name pattern")
+			}
+			r = runner.NewCoreDumpCapturingRunner(
+				r,
+				buildDirectory,
+				fileNamePattern,
+				int64(coreDumpCaptureConfiguration.MaximumSizeBytes))
+		}
+
+		// Permit actions to request that they are run under a
+		// tracer.
+		if tracingConfiguration := configuration.Tracing; tracingConfiguration != nil {
+			if configuration.ChrootIntoInputRoot {
+				return status.Error(codes.InvalidArgument, "Tracing cannot be combined with chrooting into the input root")
+			}
+			if len(tracingConfiguration.TracerArguments) == 0 {
+				return status.Error(codes.InvalidArgument, "No tracer arguments provided")
+			}
+			if err := tracingConfiguration.RateLimitInterval.CheckValid(); err != nil {
+				return util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid tracing rate limit interval")
+			}
+			r = runner.NewTracingRunner(
+				r,
+				buildDirectoryPath,
+				tracingConfiguration.TracerArguments,
+				clock.SystemClock,
+				int(tracingConfiguration.MaximumTracedActions),
+				tracingConfiguration.RateLimitInterval.AsDuration())
+		}
+
 		// Kill processes that actions leave behind by daemonizing.
-		// Ensure that we only match processes belonging to the current
-		// user that were created after bb_runner is spawned, as we
-		// don't want to kill unrelated processes.
 		var cleaners []cleaner.Cleaner
 		if configuration.CleanProcessTable {
-			startupTime := time.Now()
-			cleaners = append(
-				cleaners,
-				cleaner.NewProcessTableCleaner(
-					cleaner.NewFilteringProcessTable(
-						cleaner.SystemProcessTable,
-						func(process *cleaner.Process) bool {
-							return process.UserID == processTableCleaningUserID &&
-								process.CreationTime.After(startupTime)
-						})))
+			cleaners = append(cleaners, cleaner.NewProcessTableCleaner(processTable))
 		}
 
 		// Clean temporary directories, so that files left behind by
@@ -135,6 +414,54 @@ func main() {
 			r = runner.NewPathExistenceCheckingRunner(r, configuration.ReadinessCheckingPathnames)
 		}
 
+		// Prerequisites that need to be met for the worker to be
+		// healthy, such as specific toolchain versions.
+		if prerequisitesConfiguration := configuration.Prerequisites; prerequisitesConfiguration != nil {
+			prerequisiteChecker, err := newPrerequisiteCheckerFromConfiguration(prerequisitesConfiguration)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create prerequisite checker")
+			}
+			r = runner.NewPrerequisiteCheckingRunner(r, prerequisiteChecker)
+			bb_http.NewServersFromConfigurationAndServe(
+				prerequisitesConfiguration.HttpServers,
+				newPrerequisitesHealthHandler(prerequisiteChecker),
+				siblingsGroup)
+		}
+
+		if egressProxyConfiguration := configuration.EgressProxy; egressProxyConfiguration != nil {
+			maximumRecordedRequests := 1000
+			if egressProxyConfiguration.MaximumRecordedRequests > 0 {
+				maximumRecordedRequests = int(egressProxyConfiguration.MaximumRecordedRequests)
+			}
+			if !configuration.RunCommandsInPidNamespace {
+				return status.Error(codes.InvalidArgument, "The egress proxy can only be used in combination with run_commands_in_pid_namespace, as commands are launched in a network namespace by the init process of the PID namespace")
+			}
+			allowedConnectPorts := egressProxyConfiguration.AllowedConnectPorts
+			if len(allowedConnectPorts) == 0 {
+				allowedConnectPorts = []uint32{443}
+			}
+			r, err = runner.NewEgressProxyRunner(
+				r,
+				egressProxyConfiguration.AllowedDomains,
+				allowedConnectPorts,
+				maximumRecordedRequests)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create egress proxy runner")
+			}
+		}
+
+		if energyMeasurementConfiguration := configuration.EnergyMeasurement; energyMeasurementConfiguration != nil {
+			powercapPath := energyMeasurementConfiguration.PowercapPath
+			if powercapPath == "" {
+				powercapPath = "/sys/class/powercap"
+			}
+			energyMeter, err := runner.NewPowercapEnergyMeter(powercapPath)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create energy meter")
+			}
+			r = runner.NewEnergyMeasuringRunner(r, energyMeter)
+		}
+
 		if len(configuration.AppleXcodeDeveloperDirectories) > 0 {
 			r = runner.NewAppleXcodeResolvingRunner(
 				r,
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/configuration/bb_runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	bb_http "github.com/buildbarn/bb-storage/pkg/http"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newPrerequisiteCheckerFromConfiguration creates a PrerequisiteChecker
+// that checks all of the prerequisites provided in the configuration.
+func newPrerequisiteCheckerFromConfiguration(configuration *bb_runner.PrerequisitesConfiguration) (runner.PrerequisiteChecker, error) {
+	checkers := make([]runner.PrerequisiteChecker, 0, len(configuration.Checks))
+	for i, checkConfiguration := range configuration.Checks {
+		name := checkConfiguration.Name
+		if name == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "Prerequisite at index %d has no name", i)
+		}
+
+		var checker runner.PrerequisiteChecker
+		switch kind := checkConfiguration.Kind.(type) {
+		case *bb_runner.PrerequisiteConfiguration_Command:
+			if len(kind.Command.Arguments) < 1 {
+				return nil, status.Errorf(codes.InvalidArgument, "Prerequisite %#v has no command arguments", name)
+			}
+			var outputPattern *regexp.Regexp
+			if pattern := kind.Command.OutputPattern; pattern != "" {
+				var err error
+				outputPattern, err = regexp.Compile(pattern)
+				if err != nil {
+					return nil, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid output pattern for prerequisite %#v", name)
+				}
+			}
+			checker = runner.NewCommandPrerequisiteChecker(kind.Command.Arguments, outputPattern)
+		case *bb_runner.PrerequisiteConfiguration_PathExists:
+			checker = runner.NewPathExistencePrerequisiteChecker(kind.PathExists)
+		case *bb_runner.PrerequisiteConfiguration_TcpConnectAddress:
+			checker = runner.NewTCPConnectPrerequisiteChecker(kind.TcpConnectAddress)
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "Prerequisite %#v has no kind specified", name)
+		}
+
+		if timeout := checkConfiguration.Timeout; timeout != nil {
+			if err := timeout.CheckValid(); err != nil {
+				return nil, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid timeout for prerequisite %#v", name)
+			}
+			checker = runner.NewTimeoutPrerequisiteChecker(checker, timeout.AsDuration())
+		}
+		checkers = append(checkers, runner.NewNamedPrerequisiteChecker(checker, name))
+	}
+
+	checker := runner.NewChainedPrerequisiteChecker(checkers)
+	if cacheDuration := configuration.CacheDuration; cacheDuration != nil {
+		if err := cacheDuration.CheckValid(); err != nil {
+			return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid prerequisites cache duration")
+		}
+		checker = runner.NewCachingPrerequisiteChecker(checker, clock.SystemClock, cacheDuration.AsDuration())
+	}
+	return checker, nil
+}
+
+// newPrerequisitesHealthHandler creates an HTTP handler that reports
+// whether all prerequisites are met. It returns "SERVING" if this is
+// the case, or "NOT_SERVING" followed by the error message otherwise.
+func newPrerequisitesHealthHandler(checker runner.PrerequisiteChecker) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health/prerequisites", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if err := checker(req.Context()); err != nil {
+			w.WriteHeader(bb_http.StatusCodeFromGRPCCode(status.Code(err)))
+			fmt.Fprintf(w, "NOT_SERVING: %s\n", status.Convert(err).Message())
+			return
+		}
+		fmt.Fprintln(w, "SERVING")
+	})
+	return mux
+}
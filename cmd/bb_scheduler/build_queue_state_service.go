@@ -4,15 +4,18 @@ import (
 	"context"
 	"embed"
 	"encoding/json"
+	"fmt"
 	"html/template"
 	"log"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/buildqueuestate"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler"
 	re_util "github.com/buildbarn/bb-remote-execution/pkg/util"
 	"github.com/buildbarn/bb-storage/pkg/clock"
 	"github.com/buildbarn/bb-storage/pkg/digest"
@@ -24,6 +27,7 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -103,6 +107,18 @@ var (
 		"proto_to_json_multiline": protojson.MarshalOptions{Multiline: true}.Format,
 		"error_proto":             status.ErrorProto,
 		"stylesheet":              func() template.CSS { return stylesheet },
+		"format_bytes": func(n uint64) string {
+			const unit = 1024
+			if n < unit {
+				return fmt.Sprintf("%d B", n)
+			}
+			div, exp := uint64(unit), 0
+			for m := n / unit; m >= unit; m /= unit {
+				div *= unit
+				exp++
+			}
+			return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+		},
 		"time_future": func(t *timestamppb.Timestamp, now time.Time) string {
 			if t == nil {
 				return "∞"
@@ -155,21 +171,29 @@ func renderError(w http.ResponseWriter, err error) {
 }
 
 type buildQueueStateService struct {
-	buildQueue buildqueuestate.BuildQueueStateServer
-	clock      clock.Clock
-	browserURL *url.URL
+	buildQueue  buildqueuestate.BuildQueueStateServer
+	clock       clock.Clock
+	browserURL  *url.URL
+	snapshotter *scheduler.BuildQueueStateSnapshotter
+	history     *scheduler.BuildQueueStateHistory
 }
 
-func newBuildQueueStateService(buildQueue buildqueuestate.BuildQueueStateServer, clock clock.Clock, browserURL *url.URL, router *mux.Router) *buildQueueStateService {
+func newBuildQueueStateService(buildQueue buildqueuestate.BuildQueueStateServer, clock clock.Clock, browserURL *url.URL, snapshotter *scheduler.BuildQueueStateSnapshotter, history *scheduler.BuildQueueStateHistory, router *mux.Router) *buildQueueStateService {
 	s := &buildQueueStateService{
-		buildQueue: buildQueue,
-		clock:      clock,
-		browserURL: browserURL,
+		buildQueue:  buildQueue,
+		clock:       clock,
+		browserURL:  browserURL,
+		snapshotter: snapshotter,
+		history:     history,
 	}
 	router.HandleFunc("/", s.handleGetBuildQueueState)
 	router.HandleFunc("/add_drain", s.handleAddDrain)
+	router.HandleFunc("/create_snapshot", s.handleCreateSnapshot)
 	router.HandleFunc("/drains", s.handleListDrains)
+	router.HandleFunc("/history", s.handleGetHistory)
+	router.HandleFunc("/history.json", s.handleGetHistoryJSON)
 	router.HandleFunc("/invocation_children", s.handleListInvocationChildren)
+	router.HandleFunc("/kill_invocation_operations", s.handleKillInvocationOperations)
 	router.HandleFunc("/kill_operation", s.handleKillOperation)
 	router.HandleFunc("/operation", s.handleGetOperation)
 	router.HandleFunc("/operations", s.handleListOperations)
@@ -193,18 +217,179 @@ func (s *buildQueueStateService) handleGetBuildQueueState(w http.ResponseWriter,
 	}
 
 	if err := templates.ExecuteTemplate(w, "get_build_queue_state.html", struct {
-		Now             time.Time
-		PlatformQueues  []*buildqueuestate.PlatformQueueState
-		OperationsCount uint32
+		Now              time.Time
+		PlatformQueues   []*buildqueuestate.PlatformQueueState
+		OperationsCount  uint32
+		SnapshotsEnabled bool
+		HistoryEnabled   bool
+	}{
+		Now:              s.clock.Now(),
+		PlatformQueues:   response.PlatformQueues,
+		OperationsCount:  operationsCount.PaginationInfo.TotalEntries,
+		SnapshotsEnabled: s.snapshotter != nil,
+		HistoryEnabled:   s.history != nil,
+	}); err != nil {
+		log.Print(err)
+	}
+}
+
+func (s *buildQueueStateService) handleCreateSnapshot(w http.ResponseWriter, req *http.Request) {
+	if s.snapshotter == nil {
+		renderError(w, status.Error(codes.Unimplemented, "Build queue state snapshots are not enabled"))
+		return
+	}
+	ctx := req.Context()
+	snapshotDigest, err := s.snapshotter.CreateSnapshot(ctx)
+	if err != nil {
+		renderError(w, util.StatusWrap(err, "Failed to create build queue state snapshot"))
+		return
+	}
+	log.Printf("Created build queue state snapshot %s", snapshotDigest)
+	http.Redirect(w, req, re_util.GetBrowserURL(s.browserURL, "file", snapshotDigest), http.StatusSeeOther)
+}
+
+// Dimensions of the charts displayed on the history page, in SVG
+// user units.
+const (
+	historyChartWidth  = 600
+	historyChartHeight = 100
+)
+
+// historyChart contains the data needed to render a single time series
+// as an SVG polyline.
+type historyChart struct {
+	Title        string
+	MaximumValue string
+	Points       string
+}
+
+// newHistoryChart converts a series of values into a chart, scaling
+// values such that the largest value spans the full height of the
+// chart.
+func newHistoryChart(title string, values []float64, formatValue func(float64) string) historyChart {
+	maximum := 0.0
+	for _, v := range values {
+		if maximum < v {
+			maximum = v
+		}
+	}
+
+	var points strings.Builder
+	for i, v := range values {
+		x := float64(historyChartWidth)
+		if len(values) > 1 {
+			x = float64(i) * historyChartWidth / float64(len(values)-1)
+		}
+		y := float64(historyChartHeight)
+		if maximum > 0 {
+			y -= v / maximum * historyChartHeight
+		}
+		if i > 0 {
+			points.WriteByte(' ')
+		}
+		fmt.Fprintf(&points, "%.1f,%.1f", x, y)
+	}
+	return historyChart{
+		Title:        title,
+		MaximumValue: formatValue(maximum),
+		Points:       points.String(),
+	}
+}
+
+func (s *buildQueueStateService) getHistory(w http.ResponseWriter) ([]scheduler.SizeClassQueueHistory, bool) {
+	if s.history == nil {
+		renderError(w, status.Error(codes.Unimplemented, "Build queue state history is not enabled"))
+		return nil, false
+	}
+	return s.history.GetHistory(), true
+}
+
+func (s *buildQueueStateService) handleGetHistory(w http.ResponseWriter, req *http.Request) {
+	histories, ok := s.getHistory(w)
+	if !ok {
+		return
+	}
+
+	type sizeClassQueueCharts struct {
+		Name      *buildqueuestate.SizeClassQueueName
+		FirstTime time.Time
+		LastTime  time.Time
+		Charts    []historyChart
+	}
+	sizeClassQueues := make([]sizeClassQueueCharts, 0, len(histories))
+	for _, history := range histories {
+		queuedOperations := make([]float64, 0, len(history.Samples))
+		workerUtilization := make([]float64, 0, len(history.Samples))
+		dispatchLatency := make([]float64, 0, len(history.Samples))
+		for i := range history.Samples {
+			sample := &history.Samples[i]
+			queuedOperations = append(queuedOperations, float64(sample.QueuedOperationsCount))
+			workerUtilization = append(workerUtilization, sample.GetWorkerUtilization())
+			dispatchLatency = append(dispatchLatency, sample.DispatchLatencySeconds)
+		}
+		charts := sizeClassQueueCharts{
+			Name: history.Name,
+			Charts: []historyChart{
+				newHistoryChart("Queued operations", queuedOperations, func(v float64) string {
+					return strconv.FormatFloat(v, 'f', 0, 64)
+				}),
+				newHistoryChart("Worker utilization", workerUtilization, func(v float64) string {
+					return strconv.FormatFloat(v*100, 'f', 0, 64) + "%"
+				}),
+				newHistoryChart("Dispatch latency", dispatchLatency, func(v float64) string {
+					return time.Duration(v * float64(time.Second)).Truncate(time.Millisecond).String()
+				}),
+			},
+		}
+		if len(history.Samples) > 0 {
+			charts.FirstTime = history.Samples[0].Timestamp
+			charts.LastTime = history.Samples[len(history.Samples)-1].Timestamp
+		}
+		sizeClassQueues = append(sizeClassQueues, charts)
+	}
+
+	if err := templates.ExecuteTemplate(w, "get_build_queue_state_history.html", struct {
+		ChartWidth      int
+		ChartHeight     int
+		SizeClassQueues []sizeClassQueueCharts
 	}{
-		Now:             s.clock.Now(),
-		PlatformQueues:  response.PlatformQueues,
-		OperationsCount: operationsCount.PaginationInfo.TotalEntries,
+		ChartWidth:      historyChartWidth,
+		ChartHeight:     historyChartHeight,
+		SizeClassQueues: sizeClassQueues,
 	}); err != nil {
 		log.Print(err)
 	}
 }
 
+func (s *buildQueueStateService) handleGetHistoryJSON(w http.ResponseWriter, req *http.Request) {
+	histories, ok := s.getHistory(w)
+	if !ok {
+		return
+	}
+
+	type sizeClassQueueHistory struct {
+		Name    json.RawMessage                   `json:"name"`
+		Samples []scheduler.BuildQueueStateSample `json:"samples"`
+	}
+	response := make([]sizeClassQueueHistory, 0, len(histories))
+	for _, history := range histories {
+		name, err := protojson.Marshal(history.Name)
+		if err != nil {
+			renderError(w, util.StatusWrap(err, "Failed to marshal size class queue name"))
+			return
+		}
+		response = append(response, sizeClassQueueHistory{
+			Name:    name,
+			Samples: history.Samples,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Print(err)
+	}
+}
+
 func (s *buildQueueStateService) handleListInvocationChildren(w http.ResponseWriter, req *http.Request) {
 	query := req.URL.Query()
 	var invocationName buildqueuestate.InvocationName
@@ -228,13 +413,19 @@ func (s *buildQueueStateService) handleListInvocationChildren(w http.ResponseWri
 		renderError(w, util.StatusWrap(err, "Failed to list invocation children"))
 		return
 	}
+	var invocationID *anypb.Any
+	if ids := invocationName.Ids; len(ids) > 0 {
+		invocationID = ids[len(ids)-1]
+	}
 	if err := templates.ExecuteTemplate(w, "list_invocation_child_state.html", struct {
 		InvocationName *buildqueuestate.InvocationName
+		InvocationID   *anypb.Any
 		Children       []*buildqueuestate.InvocationChildState
 		Filter         string
 		Now            time.Time
 	}{
 		InvocationName: &invocationName,
+		InvocationID:   invocationID,
 		Children:       response.Children,
 		Filter:         filterString,
 		Now:            s.clock.Now(),
@@ -243,6 +434,28 @@ func (s *buildQueueStateService) handleListInvocationChildren(w http.ResponseWri
 	}
 }
 
+func (s *buildQueueStateService) handleKillInvocationOperations(w http.ResponseWriter, req *http.Request) {
+	req.ParseForm()
+	var invocationID anypb.Any
+	if err := protojson.Unmarshal([]byte(req.FormValue("invocation_id")), &invocationID); err != nil {
+		renderError(w, util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to extract invocation ID"))
+		return
+	}
+	ctx := req.Context()
+	if _, err := s.buildQueue.KillOperations(ctx, &buildqueuestate.KillOperationsRequest{
+		Filter: &buildqueuestate.KillOperationsRequest_Filter{
+			Type: &buildqueuestate.KillOperationsRequest_Filter_InvocationId{
+				InvocationId: &invocationID,
+			},
+		},
+		Status: status.New(codes.Unavailable, "Invocation was killed through the web interface").Proto(),
+	}); err != nil {
+		renderError(w, util.StatusWrap(err, "Failed to kill operations of invocation"))
+		return
+	}
+	http.Redirect(w, req, req.Header.Get("Referer"), http.StatusSeeOther)
+}
+
 func (s *buildQueueStateService) handleKillOperation(w http.ResponseWriter, req *http.Request) {
 	req.ParseForm()
 	ctx := req.Context()
@@ -287,6 +500,9 @@ func (s *buildQueueStateService) handleGetOperation(w http.ResponseWriter, req *
 
 func (s *buildQueueStateService) handleListOperations(w http.ResponseWriter, req *http.Request) {
 	query := req.URL.Query()
+	// Filters other than the execution stage are preserved when
+	// navigating between pages and stages.
+	filterQuery := url.Values{}
 	var filterInvocationID *anypb.Any
 	if filterInvocationIDString := query.Get("filter_invocation_id"); filterInvocationIDString != "" {
 		var invocationID anypb.Any
@@ -295,6 +511,43 @@ func (s *buildQueueStateService) handleListOperations(w http.ResponseWriter, req
 			return
 		}
 		filterInvocationID = &invocationID
+		filterQuery.Set("filter_invocation_id", filterInvocationIDString)
+	}
+	var filterWorkerID map[string]string
+	filterWorkerIDString := query.Get("filter_worker_id")
+	if filterWorkerIDString != "" {
+		if err := json.Unmarshal([]byte(filterWorkerIDString), &filterWorkerID); err != nil {
+			renderError(w, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid filter worker ID"))
+			return
+		}
+		filterQuery.Set("filter_worker_id", filterWorkerIDString)
+	}
+	var filterMinimumStageDuration *durationpb.Duration
+	filterMinimumStageDurationString := query.Get("filter_minimum_stage_duration")
+	if filterMinimumStageDurationString != "" {
+		d, err := time.ParseDuration(filterMinimumStageDurationString)
+		if err != nil {
+			renderError(w, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid filter minimum stage duration"))
+			return
+		}
+		filterMinimumStageDuration = durationpb.New(d)
+		filterQuery.Set("filter_minimum_stage_duration", filterMinimumStageDurationString)
+	}
+	filterActionMnemonic := query.Get("filter_action_mnemonic")
+	filterTargetID := query.Get("filter_target_id")
+	filterActionDigestHash := query.Get("filter_action_digest_hash")
+	for key, value := range map[string]string{
+		"filter_action_mnemonic":    filterActionMnemonic,
+		"filter_target_id":          filterTargetID,
+		"filter_action_digest_hash": filterActionDigestHash,
+	} {
+		if value != "" {
+			filterQuery.Set(key, value)
+		}
+	}
+	filterQueryString := filterQuery.Encode()
+	if filterQueryString != "" {
+		filterQueryString += "&"
 	}
 
 	filterStageString := query.Get("filter_stage")
@@ -316,10 +569,15 @@ func (s *buildQueueStateService) handleListOperations(w http.ResponseWriter, req
 
 	ctx := req.Context()
 	response, err := s.buildQueue.ListOperations(ctx, &buildqueuestate.ListOperationsRequest{
-		FilterInvocationId: filterInvocationID,
-		FilterStage:        remoteexecution.ExecutionStage_Value(filterStageValue),
-		PageSize:           pageSize,
-		StartAfter:         startAfter,
+		FilterInvocationId:         filterInvocationID,
+		FilterStage:                remoteexecution.ExecutionStage_Value(filterStageValue),
+		FilterActionMnemonic:       filterActionMnemonic,
+		FilterTargetId:             filterTargetID,
+		FilterWorkerId:             filterWorkerID,
+		FilterMinimumStageDuration: filterMinimumStageDuration,
+		FilterActionDigestHash:     filterActionDigestHash,
+		PageSize:                   pageSize,
+		StartAfter:                 startAfter,
 	})
 	if err != nil {
 		renderError(w, util.StatusWrap(err, "Failed to list operations"))
@@ -335,23 +593,35 @@ func (s *buildQueueStateService) handleListOperations(w http.ResponseWriter, req
 	}
 
 	if err := templates.ExecuteTemplate(w, "list_operation_state.html", struct {
-		BrowserURL         *url.URL
-		Now                time.Time
-		PaginationInfo     *buildqueuestate.PaginationInfo
-		EndIndex           int
-		FilterInvocationID *anypb.Any
-		FilterStage        string
-		StartAfter         *buildqueuestate.ListOperationsRequest_StartAfter
-		Operations         []*buildqueuestate.OperationState
+		BrowserURL                 *url.URL
+		Now                        time.Time
+		PaginationInfo             *buildqueuestate.PaginationInfo
+		EndIndex                   int
+		FilterInvocationID         *anypb.Any
+		FilterStage                string
+		FilterActionMnemonic       string
+		FilterTargetID             string
+		FilterWorkerID             string
+		FilterMinimumStageDuration string
+		FilterActionDigestHash     string
+		FilterQuery                template.URL
+		StartAfter                 *buildqueuestate.ListOperationsRequest_StartAfter
+		Operations                 []*buildqueuestate.OperationState
 	}{
-		BrowserURL:         s.browserURL,
-		Now:                s.clock.Now(),
-		PaginationInfo:     response.PaginationInfo,
-		EndIndex:           int(response.PaginationInfo.StartIndex) + len(response.Operations),
-		FilterInvocationID: filterInvocationID,
-		FilterStage:        filterStageString,
-		StartAfter:         nextStartAfter,
-		Operations:         response.Operations,
+		BrowserURL:                 s.browserURL,
+		Now:                        s.clock.Now(),
+		PaginationInfo:             response.PaginationInfo,
+		EndIndex:                   int(response.PaginationInfo.StartIndex) + len(response.Operations),
+		FilterInvocationID:         filterInvocationID,
+		FilterStage:                filterStageString,
+		FilterActionMnemonic:       filterActionMnemonic,
+		FilterTargetID:             filterTargetID,
+		FilterWorkerID:             filterWorkerIDString,
+		FilterMinimumStageDuration: filterMinimumStageDurationString,
+		FilterActionDigestHash:     filterActionDigestHash,
+		FilterQuery:                template.URL(filterQueryString),
+		StartAfter:                 nextStartAfter,
+		Operations:                 response.Operations,
 	}); err != nil {
 		log.Print(err)
 	}
@@ -390,9 +660,10 @@ func (s *buildQueueStateService) handleListQueuedOperations(w http.ResponseWrite
 	if l := response.QueuedOperations; len(l) > 0 {
 		o := l[len(l)-1]
 		nextStartAfter = &buildqueuestate.ListQueuedOperationsRequest_StartAfter{
-			Priority:         o.Priority,
-			ExpectedDuration: o.ExpectedDuration,
-			QueuedTimestamp:  o.QueuedTimestamp,
+			Priority:             o.Priority,
+			ExpectedDuration:     o.ExpectedDuration,
+			QueuedTimestamp:      o.QueuedTimestamp,
+			LatestStartTimestamp: o.LatestStartTimestamp,
 		}
 	}
 
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"net"
 	"net/url"
 	"os"
 	"path"
@@ -12,12 +13,17 @@ import (
 	re_blobstore "github.com/buildbarn/bb-remote-execution/pkg/blobstore"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/buildqueuestate"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/configuration/bb_scheduler"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/initialsizeclasscache"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/operationjournal"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
 	"github.com/buildbarn/bb-remote-execution/pkg/scheduler"
 	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/initialsizeclass"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/platform"
 	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/routing"
 	"github.com/buildbarn/bb-storage/pkg/auth"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
 	blobstore_configuration "github.com/buildbarn/bb-storage/pkg/blobstore/configuration"
+	"github.com/buildbarn/bb-storage/pkg/builder"
 	"github.com/buildbarn/bb-storage/pkg/capabilities"
 	"github.com/buildbarn/bb-storage/pkg/clock"
 	"github.com/buildbarn/bb-storage/pkg/digest"
@@ -30,9 +36,12 @@ import (
 	"github.com/buildbarn/bb-storage/pkg/util"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 )
 
@@ -73,6 +82,7 @@ func main() {
 		// store is only used if one or more parts of the ActionRouter
 		// are configured to use feedback driven initial size class
 		// analysis.
+		var initialSizeClassCache blobstore.BlobAccess
 		var previousExecutionStatsStore initialsizeclass.PreviousExecutionStatsStore
 		if isccConfiguration := configuration.InitialSizeClassCache; isccConfiguration != nil {
 			info, err := blobstore_configuration.NewBlobAccessFromConfiguration(
@@ -84,15 +94,31 @@ func main() {
 			if err != nil {
 				return util.StatusWrap(err, "Failed to create Initial Size Class Cache")
 			}
+			initialSizeClassCache = info.BlobAccess
 			previousExecutionStatsStore = re_blobstore.NewBlobAccessMutableProtoStore[iscc.PreviousExecutionStats](
-				info.BlobAccess,
+				initialSizeClassCache,
 				int(configuration.MaximumMessageSizeBytes))
+
+			// Statistics of completed actions are written to the
+			// ISCC lazily. Flush any pending changes upon
+			// shutdown, so that restarting the scheduler does
+			// not cause learned execution times to be lost.
+			// Statistics are reloaded on demand after startup.
+			siblingsGroup.Go(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+				<-ctx.Done()
+				flushCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				defer cancel()
+				if err := previousExecutionStatsStore.Flush(flushCtx); err != nil {
+					return util.StatusWrap(err, "Failed to flush Initial Size Class Cache")
+				}
+				return nil
+			})
 		}
 
 		// Create an action router that is responsible for analyzing
 		// incoming execution requests and determining how they are
 		// scheduled.
-		actionRouter, err := routing.NewActionRouterFromConfiguration(configuration.ActionRouter, contentAddressableStorage, int(configuration.MaximumMessageSizeBytes), previousExecutionStatsStore)
+		actionRouter, err := routing.NewActionRouterFromConfiguration(configuration.ActionRouter, contentAddressableStorage, int(configuration.MaximumMessageSizeBytes), previousExecutionStatsStore, grpcClientFactory)
 		if err != nil {
 			return util.StatusWrap(err, "Failed to create action router")
 		}
@@ -115,27 +141,263 @@ func main() {
 		if err := platformQueueWithNoWorkersTimeout.CheckValid(); err != nil {
 			return util.StatusWrap(err, "Invalid platform queue with no workers timeout")
 		}
+		var maximumExecutionDelay time.Duration
+		if configuration.MaximumExecutionDelay != nil {
+			if err := configuration.MaximumExecutionDelay.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid maximum execution delay")
+			}
+			maximumExecutionDelay = configuration.MaximumExecutionDelay.AsDuration()
+		}
+		var priorityAgingInterval time.Duration
+		if configuration.PriorityAgingInterval != nil {
+			if err := configuration.PriorityAgingInterval.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid priority aging interval")
+			}
+			priorityAgingInterval = configuration.PriorityAgingInterval.AsDuration()
+			if priorityAgingInterval <= 0 {
+				return status.Error(codes.InvalidArgument, "Priority aging interval must be positive")
+			}
+		}
+		var workerCountRecommendationWindow time.Duration
+		if configuration.WorkerCountRecommendationWindow != nil {
+			if err := configuration.WorkerCountRecommendationWindow.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid worker count recommendation window")
+			}
+			workerCountRecommendationWindow = configuration.WorkerCountRecommendationWindow.AsDuration()
+			if workerCountRecommendationWindow <= 0 {
+				return status.Error(codes.InvalidArgument, "Worker count recommendation window must be positive")
+			}
+		}
+		queueFullRetryDelay := 10 * time.Second
+		if configuration.QueueFullRetryDelay != nil {
+			if err := configuration.QueueFullRetryDelay.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid queue full retry delay")
+			}
+			queueFullRetryDelay = configuration.QueueFullRetryDelay.AsDuration()
+			if queueFullRetryDelay < 0 {
+				return status.Error(codes.InvalidArgument, "Queue full retry delay cannot be negative")
+			}
+		}
+		var workerQuarantinePolicy *scheduler.WorkerQuarantinePolicy
+		if workerQuarantine := configuration.WorkerQuarantine; workerQuarantine != nil {
+			if workerQuarantine.WindowSize == 0 {
+				return status.Error(codes.InvalidArgument, "Worker quarantine window size must be positive")
+			}
+			if rate := workerQuarantine.MaximumInfrastructureErrorRate; rate < 0 || rate >= 1 {
+				return status.Error(codes.InvalidArgument, "Worker quarantine maximum infrastructure error rate must be in range [0, 1)")
+			}
+			if err := workerQuarantine.QuarantineDuration.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid worker quarantine duration")
+			}
+			workerQuarantinePolicy = &scheduler.WorkerQuarantinePolicy{
+				WindowSize:                     int(workerQuarantine.WindowSize),
+				MaximumInfrastructureErrorRate: workerQuarantine.MaximumInfrastructureErrorRate,
+				QuarantineDuration:             workerQuarantine.QuarantineDuration.AsDuration(),
+			}
+		}
+		var workerReservationPolicy *scheduler.WorkerReservationPolicy
+		if workerReservation := configuration.WorkerReservation; workerReservation != nil {
+			if workerReservation.MaximumWorkersPerInvocation == 0 {
+				return status.Error(codes.InvalidArgument, "Worker reservation maximum workers per invocation must be positive")
+			}
+			if ratio := workerReservation.MaximumReservedWorkersRatio; ratio <= 0 || ratio > 1 {
+				return status.Error(codes.InvalidArgument, "Worker reservation maximum reserved workers ratio must be in range (0, 1]")
+			}
+			if err := workerReservation.IdleTimeout.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid worker reservation idle timeout")
+			}
+			workerReservationPolicy = &scheduler.WorkerReservationPolicy{
+				MaximumWorkersPerInvocation: int(workerReservation.MaximumWorkersPerInvocation),
+				MaximumReservedWorkersRatio: workerReservation.MaximumReservedWorkersRatio,
+				IdleTimeout:                 workerReservation.IdleTimeout.AsDuration(),
+			}
+		}
+		platformPropertyMetricLabels := map[string]struct{}{}
+		for _, propertyName := range configuration.PlatformPropertyMetricLabels {
+			if propertyName == "" {
+				return status.Error(codes.InvalidArgument, "Platform property metric labels cannot be empty")
+			}
+			if _, ok := platformPropertyMetricLabels[propertyName]; ok {
+				return status.Errorf(codes.InvalidArgument, "Platform property metric label %#v is specified multiple times", propertyName)
+			}
+			platformPropertyMetricLabels[propertyName] = struct{}{}
+		}
+
+		// Restrictions on digest functions per instance name.
+		digestFunctionsTrie := digest.NewInstanceNameTrie()
+		var digestFunctionsList [][]remoteexecution.DigestFunction_Value
+		for _, instanceNameDigestFunctions := range configuration.InstanceNameDigestFunctions {
+			instanceNamePrefix, err := digest.NewInstanceName(instanceNameDigestFunctions.InstanceNamePrefix)
+			if err != nil {
+				return util.StatusWrapf(err, "Invalid instance name prefix %#v", instanceNameDigestFunctions.InstanceNamePrefix)
+			}
+			if len(instanceNameDigestFunctions.DigestFunctions) == 0 {
+				return status.Errorf(codes.InvalidArgument, "No digest functions provided for instance name prefix %#v", instanceNameDigestFunctions.InstanceNamePrefix)
+			}
+			for _, digestFunction := range instanceNameDigestFunctions.DigestFunctions {
+				if _, err := instanceNamePrefix.GetDigestFunction(digestFunction, 0); err != nil {
+					return util.StatusWrapf(err, "Unsupported digest function %s for instance name prefix %#v", digestFunction, instanceNameDigestFunctions.InstanceNamePrefix)
+				}
+			}
+			digestFunctionsTrie.Set(instanceNamePrefix, len(digestFunctionsList))
+			digestFunctionsList = append(digestFunctionsList, instanceNameDigestFunctions.DigestFunctions)
+		}
+
+		// Instance names for which remote execution is disabled.
+		cacheOnlyTrie := digest.NewInstanceNameTrie()
+		var cacheOnlyMessages []string
+		for _, cacheOnlyInstanceName := range configuration.CacheOnlyInstanceNames {
+			instanceNamePrefix, err := digest.NewInstanceName(cacheOnlyInstanceName.InstanceNamePrefix)
+			if err != nil {
+				return util.StatusWrapf(err, "Invalid instance name prefix %#v", cacheOnlyInstanceName.InstanceNamePrefix)
+			}
+			cacheOnlyTrie.Set(instanceNamePrefix, len(cacheOnlyMessages))
+			cacheOnlyMessages = append(cacheOnlyMessages, cacheOnlyInstanceName.Message)
+		}
+
+		// Weights of invocations, used for fair scheduling.
+		type invocationWeight struct {
+			matcher auth.Authorizer
+			weight  float64
+		}
+		invocationWeights := make([]invocationWeight, 0, len(configuration.InvocationWeights))
+		for i, invocationWeightConfiguration := range configuration.InvocationWeights {
+			matcher, err := authorizerFactory.NewAuthorizerFromConfiguration(invocationWeightConfiguration.Matcher)
+			if err != nil {
+				return util.StatusWrapf(err, "Failed to create matcher for invocation weight at index %d", i)
+			}
+			if invocationWeightConfiguration.Weight <= 0 {
+				return status.Errorf(codes.InvalidArgument, "Invocation weight at index %d must be positive", i)
+			}
+			invocationWeights = append(invocationWeights, invocationWeight{
+				matcher: matcher,
+				weight:  invocationWeightConfiguration.Weight,
+			})
+		}
+
+		// Quotas of clients, used to limit the amount of work
+		// that may be submitted by individual users.
+		type clientQuota struct {
+			matcher auth.Authorizer
+			quota   scheduler.ClientQuota
+		}
+		clientQuotas := make([]clientQuota, 0, len(configuration.ClientQuotas))
+		for i, clientQuotaConfiguration := range configuration.ClientQuotas {
+			matcher, err := authorizerFactory.NewAuthorizerFromConfiguration(clientQuotaConfiguration.Matcher)
+			if err != nil {
+				return util.StatusWrapf(err, "Failed to create matcher for client quota at index %d", i)
+			}
+			clientQuotas = append(clientQuotas, clientQuota{
+				matcher: matcher,
+				quota: scheduler.ClientQuota{
+					MaximumRequestsPerMinute:    int(clientQuotaConfiguration.MaximumRequestsPerMinute),
+					MaximumConcurrentOperations: int(clientQuotaConfiguration.MaximumConcurrentOperations),
+				},
+			})
+		}
 
 		// Create in-memory build queue.
 		// TODO: Make timeouts configurable.
 		generator := random.NewFastSingleThreadedGenerator()
+		getIdleWorkerSynchronizationInterval := func(consecutiveTimeouts int) time.Duration {
+			// Let synchronization calls block somewhere
+			// between 0 and 2 minutes. Add jitter to
+			// prevent recurring traffic spikes.
+			return random.Duration(generator, 2*time.Minute)
+		}
+		if idleWorkerSynchronization := configuration.IdleWorkerSynchronization; idleWorkerSynchronization != nil {
+			if err := idleWorkerSynchronization.InitialInterval.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid idle worker synchronization initial interval")
+			}
+			if err := idleWorkerSynchronization.MaximumInterval.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid idle worker synchronization maximum interval")
+			}
+			initialInterval := idleWorkerSynchronization.InitialInterval.AsDuration()
+			maximumInterval := idleWorkerSynchronization.MaximumInterval.AsDuration()
+			if initialInterval <= 0 || maximumInterval < initialInterval {
+				return status.Error(codes.InvalidArgument, "Idle worker synchronization intervals must be positive, and the maximum interval may not be smaller than the initial interval")
+			}
+			if idleWorkerSynchronization.Multiplier < 1 {
+				return status.Error(codes.InvalidArgument, "Idle worker synchronization multiplier must be at least 1.0")
+			}
+			getIdleWorkerSynchronizationInterval = scheduler.NewExponentialIdleWorkerSynchronizationInterval(
+				generator,
+				initialInterval,
+				maximumInterval,
+				idleWorkerSynchronization.Multiplier)
+		}
+		var operationJournal scheduler.OperationJournal
+		var recoveredOperations []*operationjournal.Operation
+		if operationJournalPath := configuration.OperationJournalPath; operationJournalPath != "" {
+			var err error
+			operationJournal, recoveredOperations, err = scheduler.NewFileOperationJournal(operationJournalPath, util.DefaultErrorLogger, dependenciesGroup)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to open operation journal")
+			}
+		}
+		// Report whether platform queues have workers that are
+		// capable of executing operations through
+		// grpc.health.v1.Health, if enabled.
+		var platformQueueHealthServer *health.Server
+		var platformQueueHealthReporter scheduler.PlatformQueueHealthReporter
+		if len(configuration.PlatformQueueHealthGrpcListenAddresses) > 0 {
+			platformQueueHealthServer = health.NewServer()
+			platformQueueHealthReporter = platformQueueHealthServer
+		}
+
 		buildQueue := scheduler.NewInMemoryBuildQueue(
 			contentAddressableStorage,
 			clock.SystemClock,
 			uuid.NewRandom,
 			&scheduler.InMemoryBuildQueueConfiguration{
-				ExecutionUpdateInterval:           time.Minute,
-				OperationWithNoWaitersTimeout:     time.Minute,
-				PlatformQueueWithNoWorkersTimeout: platformQueueWithNoWorkersTimeout.AsDuration(),
-				BusyWorkerSynchronizationInterval: 10 * time.Second,
-				GetIdleWorkerSynchronizationInterval: func() time.Duration {
-					// Let synchronization calls block somewhere
-					// between 0 and 2 minutes. Add jitter to
-					// prevent recurring traffic spikes.
-					return random.Duration(generator, 2*time.Minute)
+				ExecutionUpdateInterval:              time.Minute,
+				OperationWithNoWaitersTimeout:        time.Minute,
+				PlatformQueueWithNoWorkersTimeout:    platformQueueWithNoWorkersTimeout.AsDuration(),
+				BusyWorkerSynchronizationInterval:    10 * time.Second,
+				GetIdleWorkerSynchronizationInterval: getIdleWorkerSynchronizationInterval,
+				WorkerTaskRetryCount:                 9,
+				WorkerWithNoSynchronizationsTimeout:  time.Minute,
+				MaximumExecutionDelay:                maximumExecutionDelay,
+				GetDigestFunctions: func(instanceName digest.InstanceName) []remoteexecution.DigestFunction_Value {
+					if i := digestFunctionsTrie.GetLongestPrefix(instanceName); i >= 0 {
+						return digestFunctionsList[i]
+					}
+					return nil
+				},
+				GetCacheOnlyMessage: func(instanceName digest.InstanceName) (string, bool) {
+					if i := cacheOnlyTrie.GetLongestPrefix(instanceName); i >= 0 {
+						return cacheOnlyMessages[i], true
+					}
+					return "", false
+				},
+				GetInvocationWeight: func(ctx context.Context, instanceName digest.InstanceName) float64 {
+					for _, invocationWeight := range invocationWeights {
+						if auth.AuthorizeSingleInstanceName(ctx, invocationWeight.matcher, instanceName) == nil {
+							return invocationWeight.weight
+						}
+					}
+					return 1
+				},
+				PriorityAgingInterval:                    priorityAgingInterval,
+				DeadlineAwareScheduling:                  configuration.DeadlineAwareScheduling,
+				WorkerInputRootAffinityCount:             int(configuration.WorkerInputRootAffinityCount),
+				InFlightDeduplicationAcrossInstanceNames: configuration.InFlightDeduplicationAcrossInstanceNames,
+				OperationJournal:                         operationJournal,
+				PlatformQueueHealthReporter:              platformQueueHealthReporter,
+				WorkerCountRecommendationWindow:          workerCountRecommendationWindow,
+				QueueFullRetryDelay:                      queueFullRetryDelay,
+				MaximumExecutingOperationsPerInvocation:  int(configuration.MaximumExecutingOperationsPerInvocation),
+				GetClientQuota: func(ctx context.Context, instanceName digest.InstanceName) *scheduler.ClientQuota {
+					for i := range clientQuotas {
+						if auth.AuthorizeSingleInstanceName(ctx, clientQuotas[i].matcher, instanceName) == nil {
+							return &clientQuotas[i].quota
+						}
+					}
+					return nil
 				},
-				WorkerTaskRetryCount:                9,
-				WorkerWithNoSynchronizationsTimeout: time.Minute,
+				WorkerQuarantinePolicy:       workerQuarantinePolicy,
+				WorkerReservationPolicy:      workerReservationPolicy,
+				PlatformPropertyMetricLabels: configuration.PlatformPropertyMetricLabels,
 			},
 			int(configuration.MaximumMessageSizeBytes),
 			actionRouter,
@@ -156,6 +418,44 @@ func main() {
 				}
 				workerInvocationStickinessLimits = append(workerInvocationStickinessLimits, d.AsDuration())
 			}
+			var preemptionPolicy *scheduler.PreemptionPolicy
+			if preemption := platformQueue.Preemption; preemption != nil {
+				if err := preemption.QueuedDuration.CheckValid(); err != nil {
+					return util.StatusWrap(err, "Invalid preemption queued duration")
+				}
+				preemptionPolicy = &scheduler.PreemptionPolicy{
+					MaximumPriority: preemption.MaximumPriority,
+					QueuedDuration:  preemption.QueuedDuration.AsDuration(),
+				}
+			}
+			workerResourceRequirements := make(map[uint32]scheduler.WorkerResourceRequirements, len(platformQueue.WorkerResourceRequirements))
+			for _, requirements := range platformQueue.WorkerResourceRequirements {
+				if _, ok := workerResourceRequirements[requirements.SizeClass]; ok {
+					return status.Errorf(codes.InvalidArgument, "Multiple worker resource requirements provided for size class %d", requirements.SizeClass)
+				}
+				workerResourceRequirements[requirements.SizeClass] = scheduler.WorkerResourceRequirements{
+					MinimumBuildDirectoryFreeBytes: requirements.MinimumBuildDirectoryFreeBytes,
+					MinimumAvailableMemoryBytes:    requirements.MinimumAvailableMemoryBytes,
+					MaximumLoadAverage:             requirements.MaximumLoadAverage,
+				}
+			}
+			var sizeClassEscalationPolicy *scheduler.SizeClassEscalationPolicy
+			if escalation := platformQueue.SizeClassEscalation; escalation != nil {
+				sizeClassEscalationPolicy = &scheduler.SizeClassEscalationPolicy{
+					TimeoutMultiplier:        1,
+					ResourceLimitsMultiplier: 1,
+				}
+				if m := escalation.TimeoutMultiplier; m < 0 {
+					return status.Errorf(codes.InvalidArgument, "Size class escalation timeout multiplier %f is negative", m)
+				} else if m > 0 {
+					sizeClassEscalationPolicy.TimeoutMultiplier = m
+				}
+				if m := escalation.ResourceLimitsMultiplier; m < 0 {
+					return status.Errorf(codes.InvalidArgument, "Size class escalation resource limits multiplier %f is negative", m)
+				} else if m > 0 {
+					sizeClassEscalationPolicy.ResourceLimitsMultiplier = m
+				}
+			}
 
 			if err := buildQueue.RegisterPredeclaredPlatformQueue(
 				instanceName,
@@ -164,19 +464,107 @@ func main() {
 				int(platformQueue.MaximumQueuedBackgroundLearningOperations),
 				platformQueue.BackgroundLearningOperationPriority,
 				platformQueue.MaximumSizeClass,
+				int(platformQueue.MaximumQueuedOperations),
+				preemptionPolicy,
+				workerResourceRequirements,
+				sizeClassEscalationPolicy,
 			); err != nil {
 				return util.StatusWrap(err, "Failed to register predeclared platform queue")
 			}
 		}
 
+		// Recover operations that were queued or executing prior to
+		// the scheduler restarting.
+		if err := buildQueue.RecoverOperations(recoveredOperations); err != nil {
+			return util.StatusWrap(err, "Failed to recover operations from operation journal")
+		}
+
+		// Recover statistics that were gathered prior to the
+		// scheduler restarting, and persist them periodically and
+		// upon shutdown.
+		if statisticsPath := configuration.StatisticsPath; statisticsPath != "" {
+			statisticsPersistenceInterval := time.Minute
+			if interval := configuration.StatisticsPersistenceInterval; interval != nil {
+				if err := interval.CheckValid(); err != nil {
+					return util.StatusWrap(err, "Invalid statistics persistence interval")
+				}
+				statisticsPersistenceInterval = interval.AsDuration()
+				if statisticsPersistenceInterval <= 0 {
+					return status.Error(codes.InvalidArgument, "Statistics persistence interval must be positive")
+				}
+			}
+
+			statistics, err := scheduler.ReadStatisticsFile(statisticsPath)
+			if err != nil {
+				return util.StatusWrapf(err, "Failed to read statistics from %#v", statisticsPath)
+			}
+			if err := buildQueue.RecoverStatistics(statistics); err != nil {
+				return util.StatusWrap(err, "Failed to recover statistics")
+			}
+			siblingsGroup.Go(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+				return buildQueue.WriteStatisticsFilePeriodically(ctx, statisticsPath, statisticsPersistenceInterval, util.DefaultErrorLogger)
+			})
+		}
+
+		// Expose the recommended number of workers per size class
+		// queue, so that it can be used to drive autoscaling.
+		if workerCountRecommendationWindow > 0 {
+			prometheus.MustRegister(buildQueue.NewRecommendedWorkersCollector())
+		}
+
+		// Optional: Forward execution requests to other scheduler
+		// clusters.
+		var clientBuildQueue builder.BuildQueue = buildQueue
+		if federationConfiguration := configuration.Federation; federationConfiguration != nil {
+			platformKeyExtractor, err := platform.NewKeyExtractorFromConfiguration(
+				federationConfiguration.PlatformKeyExtractor,
+				contentAddressableStorage,
+				int(configuration.MaximumMessageSizeBytes))
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create federation platform key extractor")
+			}
+			clusters := make([]scheduler.FederatedCluster, 0, len(federationConfiguration.Clusters))
+			for _, clusterConfiguration := range federationConfiguration.Clusters {
+				endpoint, err := grpcClientFactory.NewClientFromConfiguration(clusterConfiguration.Endpoint)
+				if err != nil {
+					return util.StatusWrapf(err, "Failed to create gRPC client for federated cluster %#v", clusterConfiguration.Name)
+				}
+				matchers := make([]scheduler.FederatedClusterMatcher, 0, len(clusterConfiguration.Matchers))
+				for _, matcherConfiguration := range clusterConfiguration.Matchers {
+					instanceNamePrefix, err := digest.NewInstanceName(matcherConfiguration.InstanceNamePrefix)
+					if err != nil {
+						return util.StatusWrapf(err, "Invalid instance name prefix %#v for federated cluster %#v", matcherConfiguration.InstanceNamePrefix, clusterConfiguration.Name)
+					}
+					matchers = append(matchers, scheduler.FederatedClusterMatcher{
+						InstanceNamePrefix: instanceNamePrefix,
+						PlatformProperties: matcherConfiguration.PlatformProperties,
+					})
+				}
+				clusters = append(clusters, scheduler.FederatedCluster{
+					Name:       clusterConfiguration.Name,
+					BuildQueue: builder.NewForwardingBuildQueue(endpoint),
+					Matchers:   matchers,
+				})
+			}
+			clientBuildQueue, err = scheduler.NewFederatingBuildQueue(
+				buildQueue,
+				clusters,
+				contentAddressableStorage,
+				int(configuration.MaximumMessageSizeBytes),
+				platformKeyExtractor)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create federating build queue")
+			}
+		}
+
 		// Spawn gRPC servers for client and worker traffic.
 		if err := bb_grpc.NewServersFromConfigurationAndServe(
 			configuration.ClientGrpcServers,
 			func(s grpc.ServiceRegistrar) {
 				remoteexecution.RegisterCapabilitiesServer(
 					s,
-					capabilities.NewServer(buildQueue))
-				remoteexecution.RegisterExecutionServer(s, buildQueue)
+					capabilities.NewServer(clientBuildQueue))
+				remoteexecution.RegisterExecutionServer(s, clientBuildQueue)
 			},
 			siblingsGroup,
 		); err != nil {
@@ -200,6 +588,107 @@ func main() {
 		); err != nil {
 			return util.StatusWrap(err, "Build queue state gRPC server failure")
 		}
+		if platformQueueHealthServer != nil {
+			// Servers created through bb_grpc already
+			// provide grpc.health.v1.Health, reporting the
+			// health of the process as a whole. Launch a
+			// separate server for platform queues.
+			s := grpc.NewServer()
+			grpc_health_v1.RegisterHealthServer(s, platformQueueHealthServer)
+			siblingsGroup.Go(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+				<-ctx.Done()
+				platformQueueHealthServer.Shutdown()
+				s.Stop()
+				return nil
+			})
+			for _, listenAddressIter := range configuration.PlatformQueueHealthGrpcListenAddresses {
+				listenAddress := listenAddressIter
+				sock, err := net.Listen("tcp", listenAddress)
+				if err != nil {
+					return util.StatusWrapf(err, "Failed to create listening socket for %#v", listenAddress)
+				}
+				siblingsGroup.Go(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+					if err := s.Serve(sock); err != nil {
+						return util.StatusWrapf(err, "Platform queue health gRPC server failed for %#v", listenAddress)
+					}
+					return nil
+				})
+			}
+		}
+		if len(configuration.InitialSizeClassCacheGrpcServers) > 0 {
+			if initialSizeClassCache == nil {
+				return status.Error(codes.InvalidArgument, "Initial Size Class Cache gRPC servers can only be enabled if an Initial Size Class Cache (ISCC) is configured")
+			}
+			if err := bb_grpc.NewServersFromConfigurationAndServe(
+				configuration.InitialSizeClassCacheGrpcServers,
+				func(s grpc.ServiceRegistrar) {
+					initialsizeclasscache.RegisterInitialSizeClassCacheServer(
+						s,
+						initialsizeclass.NewInitialSizeClassCacheServer(
+							initialSizeClassCache,
+							contentAddressableStorage,
+							int(configuration.MaximumMessageSizeBytes)))
+				},
+				siblingsGroup,
+			); err != nil {
+				return util.StatusWrap(err, "Initial Size Class Cache gRPC server failure")
+			}
+		}
+
+		// Optional: Snapshots of the state of the build queue that
+		// are written into the Content Addressable Storage.
+		var snapshotter *scheduler.BuildQueueStateSnapshotter
+		if snapshotConfiguration := configuration.BuildQueueStateSnapshots; snapshotConfiguration != nil {
+			instanceName, err := digest.NewInstanceName(snapshotConfiguration.InstanceName)
+			if err != nil {
+				return util.StatusWrapf(err, "Invalid build queue state snapshot instance name %#v", snapshotConfiguration.InstanceName)
+			}
+			digestFunction, err := instanceName.GetDigestFunction(snapshotConfiguration.DigestFunction, 0)
+			if err != nil {
+				return util.StatusWrap(err, "Invalid build queue state snapshot digest function")
+			}
+			snapshotter = scheduler.NewBuildQueueStateSnapshotter(
+				buildQueue,
+				contentAddressableStorage,
+				digestFunction,
+				clock.SystemClock)
+			if interval := snapshotConfiguration.Interval; interval != nil {
+				if err := interval.CheckValid(); err != nil {
+					return util.StatusWrap(err, "Invalid build queue state snapshot interval")
+				}
+				if interval.AsDuration() <= 0 {
+					return status.Error(codes.InvalidArgument, "Build queue state snapshot interval must be positive")
+				}
+				siblingsGroup.Go(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+					snapshotter.CreateSnapshotsPeriodically(ctx, interval.AsDuration(), util.DefaultErrorLogger)
+					return nil
+				})
+			}
+		}
+
+		// Optional: History of the state of the build queue that is
+		// displayed as charts in the web UI.
+		var history *scheduler.BuildQueueStateHistory
+		if historyConfiguration := configuration.BuildQueueStateHistory; historyConfiguration != nil {
+			if err := historyConfiguration.Interval.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid build queue state history interval")
+			}
+			interval := historyConfiguration.Interval.AsDuration()
+			if interval <= 0 {
+				return status.Error(codes.InvalidArgument, "Build queue state history interval must be positive")
+			}
+			if historyConfiguration.MaximumSamples == 0 {
+				return status.Error(codes.InvalidArgument, "Build queue state history maximum samples must be positive")
+			}
+			history = scheduler.NewBuildQueueStateHistory(
+				buildQueue,
+				clock.SystemClock,
+				int(historyConfiguration.MaximumSamples))
+			siblingsGroup.Go(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+				history.RecordSamplesPeriodically(ctx, interval, util.DefaultErrorLogger)
+				return nil
+			})
+		}
 
 		// Web server for metrics and profiling.
 		router := mux.NewRouter()
@@ -208,7 +697,7 @@ func main() {
 			routePrefix += "/"
 		}
 		subrouter := router.PathPrefix(routePrefix).Subrouter()
-		newBuildQueueStateService(buildQueue, clock.SystemClock, browserURL, subrouter)
+		newBuildQueueStateService(buildQueue, clock.SystemClock, browserURL, snapshotter, history, subrouter)
 		http.NewServersFromConfigurationAndServe(
 			configuration.AdminHttpServers,
 			http.NewMetricsHandler(router, "SchedulerUI"),
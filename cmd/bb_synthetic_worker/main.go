@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_blobstore "github.com/buildbarn/bb-remote-execution/pkg/blobstore"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/configuration/bb_synthetic_worker"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	blobstore_configuration "github.com/buildbarn/bb-storage/pkg/blobstore/configuration"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/global"
+	"github.com/buildbarn/bb-storage/pkg/program"
+	"github.com/buildbarn/bb-storage/pkg/random"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// This is an implementation of a remote execution worker that does not
+// execute actions. Instead, it registers a configurable number of
+// synthetic workers with the scheduler, each of which waits for an
+// amount of time sampled from a distribution before returning a canned
+// action result. This worker may be used to load test the scheduler
+// with large numbers of workers.
+
+func newDurationSampler(configuration *bb_synthetic_worker.ExecutionDurationDistribution) (func() time.Duration, error) {
+	switch kind := configuration.GetKind().(type) {
+	case nil:
+		return func() time.Duration { return 0 }, nil
+	case *bb_synthetic_worker.ExecutionDurationDistribution_Fixed:
+		if err := kind.Fixed.CheckValid(); err != nil {
+			return nil, util.StatusWrap(err, "Invalid fixed execution duration")
+		}
+		d := kind.Fixed.AsDuration()
+		return func() time.Duration { return d }, nil
+	case *bb_synthetic_worker.ExecutionDurationDistribution_Uniform_:
+		if err := kind.Uniform.Minimum.CheckValid(); err != nil {
+			return nil, util.StatusWrap(err, "Invalid minimum execution duration")
+		}
+		if err := kind.Uniform.Maximum.CheckValid(); err != nil {
+			return nil, util.StatusWrap(err, "Invalid maximum execution duration")
+		}
+		minimum, maximum := kind.Uniform.Minimum.AsDuration(), kind.Uniform.Maximum.AsDuration()
+		if maximum <= minimum {
+			return nil, status.Error(codes.InvalidArgument, "Maximum execution duration must exceed the minimum execution duration")
+		}
+		return func() time.Duration {
+			return minimum + random.Duration(random.FastThreadSafeGenerator, maximum-minimum)
+		}, nil
+	case *bb_synthetic_worker.ExecutionDurationDistribution_ExponentialMean:
+		if err := kind.ExponentialMean.CheckValid(); err != nil {
+			return nil, util.StatusWrap(err, "Invalid exponential mean execution duration")
+		}
+		mean := float64(kind.ExponentialMean.AsDuration())
+		return func() time.Duration {
+			return time.Duration(-math.Log(1-random.FastThreadSafeGenerator.Float64()) * mean)
+		}, nil
+	default:
+		return nil, status.Error(codes.InvalidArgument, "Unknown execution duration distribution")
+	}
+}
+
+func main() {
+	program.RunMain(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+		if len(os.Args) != 2 {
+			return status.Error(codes.InvalidArgument, "Usage: bb_synthetic_worker bb_synthetic_worker.jsonnet")
+		}
+		var configuration bb_synthetic_worker.ApplicationConfiguration
+		if err := util.UnmarshalConfigurationFromFile(os.Args[1], &configuration); err != nil {
+			return util.StatusWrapf(err, "Failed to read configuration from %s", os.Args[1])
+		}
+		lifecycleState, grpcClientFactory, err := global.ApplyConfiguration(configuration.Global)
+		if err != nil {
+			return util.StatusWrap(err, "Failed to apply global configuration options")
+		}
+
+		// Storage access is optional. It is only needed to permit
+		// actions to override their execution duration.
+		var contentAddressableStorage blobstore.BlobAccess
+		if configuration.ContentAddressableStorage != nil {
+			info, err := blobstore_configuration.NewBlobAccessFromConfiguration(
+				dependenciesGroup,
+				configuration.ContentAddressableStorage,
+				blobstore_configuration.NewCASBlobAccessCreator(
+					grpcClientFactory,
+					int(configuration.MaximumMessageSizeBytes)))
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create Content Adddressable Storage")
+			}
+			contentAddressableStorage = re_blobstore.NewExistencePreconditionBlobAccess(info.BlobAccess)
+		}
+
+		durationSampler, err := newDurationSampler(configuration.ExecutionDuration)
+		if err != nil {
+			return util.StatusWrap(err, "Failed to create execution duration sampler")
+		}
+		actionResult := configuration.ActionResult
+		if actionResult == nil {
+			actionResult = &remoteexecution.ActionResult{}
+		}
+		buildExecutor := builder.NewSyntheticBuildExecutor(
+			clock.SystemClock,
+			durationSampler,
+			actionResult,
+			contentAddressableStorage,
+			int(configuration.MaximumMessageSizeBytes))
+
+		schedulerConnection, err := grpcClientFactory.NewClientFromConfiguration(configuration.Scheduler)
+		if err != nil {
+			return util.StatusWrap(err, "Failed to create scheduler RPC client")
+		}
+		schedulerClient := remoteworker.NewOperationQueueClient(schedulerConnection)
+
+		instanceNamePrefix, err := digest.NewInstanceName(configuration.InstanceNamePrefix)
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid instance name prefix %#v", configuration.InstanceNamePrefix)
+		}
+		advertisedPlatform := builder.NewAdvertisedPlatform(configuration.Platform, configuration.SizeClass)
+
+		workerCount := int(configuration.WorkerCount)
+		if workerCount == 0 {
+			workerCount = 1
+		}
+		concurrencyLength := len(strconv.FormatInt(int64(workerCount-1), 10))
+		for threadID := 0; threadID < workerCount; threadID++ {
+			// Give every synthetic worker a unique worker ID.
+			workerID := map[string]string{}
+			if workerCount > 1 {
+				workerID["thread"] = fmt.Sprintf("%0*d", concurrencyLength, threadID)
+			}
+			for k, v := range configuration.WorkerId {
+				workerID[k] = v
+			}
+			workerName, err := json.Marshal(workerID)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to marshal worker ID")
+			}
+
+			buildClient := builder.NewBuildClient(
+				schedulerClient,
+				buildExecutor,
+				re_filesystem.EmptyFilePool,
+				clock.SystemClock,
+				workerID,
+				instanceNamePrefix,
+				/* instanceNameMapping = */ nil,
+				advertisedPlatform,
+				/* inputRootPrefetcher = */ nil,
+				/* idlePrefetcher = */ nil,
+				/* gracefulShutdownTimeout = */ 0,
+				/* workerResourcesReporter = */ nil,
+				/* terminationTimeReporter = */ nil,
+				/* concurrencyLimit = */ nil,
+				/* memoryAdmission = */ nil)
+			builder.LaunchWorkerThread(siblingsGroup, buildClient, string(workerName), nil, threadID)
+		}
+
+		lifecycleState.MarkReadyAndWait(siblingsGroup)
+		return nil
+	})
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/configuration/bb_upload_directory"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	blobstore_configuration "github.com/buildbarn/bb-storage/pkg/blobstore/configuration"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/global"
+	"github.com/buildbarn/bb-storage/pkg/program"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// bb_upload_directory walks a local directory, uploads its contents to
+// the Content Addressable Storage in the form of an REv2 Merkle tree,
+// and prints the digest of the root Directory message. This can be
+// used to seed toolchains that are provided to actions as part of their
+// input root.
+//
+// The digest is printed in the form of a ByteStream read path, e.g.:
+//
+//	my-instance/blobs/sha256/0123...cdef/123
+
+func main() {
+	program.RunMain(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+		if len(os.Args) != 3 {
+			return status.Error(codes.InvalidArgument, "Usage: bb_upload_directory bb_upload_directory.jsonnet directory")
+		}
+		var configuration bb_upload_directory.ApplicationConfiguration
+		if err := util.UnmarshalConfigurationFromFile(os.Args[1], &configuration); err != nil {
+			return util.StatusWrapf(err, "Failed to read configuration from %s", os.Args[1])
+		}
+		_, grpcClientFactory, err := global.ApplyConfiguration(configuration.Global)
+		if err != nil {
+			return util.StatusWrap(err, "Failed to apply global configuration options")
+		}
+
+		instanceName, err := digest.NewInstanceName(configuration.InstanceName)
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid instance name %#v", configuration.InstanceName)
+		}
+		digestFunction, err := instanceName.GetDigestFunction(configuration.DigestFunction, 0)
+		if err != nil {
+			return util.StatusWrap(err, "Invalid digest function")
+		}
+		batchSize := blobstore.RecommendedFindMissingDigestsCount
+		if configuration.FindMissingBatchSize > 0 {
+			batchSize = int(configuration.FindMissingBatchSize)
+		}
+
+		info, err := blobstore_configuration.NewBlobAccessFromConfiguration(
+			dependenciesGroup,
+			configuration.ContentAddressableStorage,
+			blobstore_configuration.NewCASBlobAccessCreator(
+				grpcClientFactory,
+				int(configuration.MaximumMessageSizeBytes)))
+		if err != nil {
+			return util.StatusWrap(err, "Failed to create Content Adddressable Storage")
+		}
+
+		directory, err := filesystem.NewLocalDirectory(os.Args[2])
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to open directory %#v", os.Args[2])
+		}
+		defer directory.Close()
+
+		var chunkedBlobUploader *cas.ChunkedBlobUploader
+		if chunkingConfiguration := configuration.Chunking; chunkingConfiguration != nil {
+			actionCacheInfo, err := blobstore_configuration.NewBlobAccessFromConfiguration(
+				dependenciesGroup,
+				chunkingConfiguration.ActionCache,
+				blobstore_configuration.NewACBlobAccessCreator(
+					&info,
+					grpcClientFactory,
+					int(configuration.MaximumMessageSizeBytes)))
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create Action Cache")
+			}
+			chunker, err := cas.NewContentDefinedChunker(
+				int(chunkingConfiguration.MinimumChunkSizeBytes),
+				int(chunkingConfiguration.AverageChunkSizeBytes),
+				int(chunkingConfiguration.MaximumChunkSizeBytes))
+			if err != nil {
+				return util.StatusWrap(err, "Invalid chunking configuration")
+			}
+			chunkedBlobUploader = &cas.ChunkedBlobUploader{
+				ActionCache:             actionCacheInfo.BlobAccess,
+				Chunker:                 chunker,
+				MinimumBlobSizeBytes:    chunkingConfiguration.MinimumBlobSizeBytes,
+				MaximumMessageSizeBytes: int(configuration.MaximumMessageSizeBytes),
+			}
+		}
+
+		rootDigest, err := cas.UploadDirectory(ctx, info.BlobAccess, digestFunction, directory, batchSize, chunkedBlobUploader)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to upload directory %#v", os.Args[2])
+		}
+		fmt.Println(rootDigest.GetByteStreamReadPath(remoteexecution.Compressor_IDENTITY))
+		return nil
+	})
+}
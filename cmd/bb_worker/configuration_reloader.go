@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	re_blobstore "github.com/buildbarn/bb-remote-execution/pkg/blobstore"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/configuration/bb_worker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/program"
+	blobstore_pb "github.com/buildbarn/bb-storage/pkg/proto/configuration/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// reloadableRunner contains the state of a runner that may be altered
+// when the configuration of the worker is reloaded.
+type reloadableRunner struct {
+	advertisedPlatform *builder.AdvertisedPlatform
+	concurrencyLimit   *builder.ConcurrencyLimit
+	maximumConcurrency uint64
+
+	// Platforms announced for the size class partitions of the
+	// runner, in the order in which they are declared in the
+	// configuration file.
+	sizeClassPartitionAdvertisedPlatforms []*builder.AdvertisedPlatform
+}
+
+// configurationReloader reloads the configuration file of bb_worker,
+// and applies the changes that can be made without restarting the
+// worker. This prevents all local caches from being discarded when
+// making small adjustments to the configuration.
+type configurationReloader struct {
+	path                 string
+	currentConfiguration *bb_worker.ApplicationConfiguration
+
+	// Runners in the order in which they are declared in the
+	// configuration file, across all build directories.
+	runners []reloadableRunner
+
+	storageGroup              program.Group
+	newCASAndAC               casAndACFactory
+	contentAddressableStorage *re_blobstore.SwappableBlobAccess
+	actionCache               *re_blobstore.SwappableBlobAccess
+	closeStorageBackends      func()
+}
+
+// casAndACFactory creates storage backends for the Content Addressable
+// Storage and Action Cache. Routines needed by the backends are
+// launched in the provided group.
+type casAndACFactory func(group program.Group, configuration *blobstore_pb.BlobstoreConfiguration) (blobstore.BlobAccess, blobstore.BlobAccess, error)
+
+// newClosableCASAndAC creates storage backends for the Content
+// Addressable Storage and Action Cache, launching any routines needed
+// by them in a group of their own. These routines are terminated by
+// calling the function that is returned, which is done after the
+// backends have been swapped out by reloading the configuration.
+//
+// gRPC client connections are shared with other backends that use the
+// same client configuration. These connections therefore remain open
+// after the backends are closed.
+func newClosableCASAndAC(group program.Group, newCASAndAC casAndACFactory, configuration *blobstore_pb.BlobstoreConfiguration) (blobstore.BlobAccess, blobstore.BlobAccess, func(), error) {
+	type casAndAC struct {
+		contentAddressableStorage blobstore.BlobAccess
+		actionCache               blobstore.BlobAccess
+		err                       error
+	}
+	created := make(chan casAndAC, 1)
+	closed := make(chan struct{})
+	group.Go(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+		contentAddressableStorage, actionCache, err := newCASAndAC(dependenciesGroup, configuration)
+		created <- casAndAC{
+			contentAddressableStorage: contentAddressableStorage,
+			actionCache:               actionCache,
+			err:                       err,
+		}
+		if err == nil {
+			select {
+			case <-closed:
+			case <-ctx.Done():
+			}
+		}
+		return nil
+	})
+	backends := <-created
+	if backends.err != nil {
+		return nil, nil, nil, backends.err
+	}
+	var closeOnce sync.Once
+	return backends.contentAddressableStorage, backends.actionCache, func() {
+		closeOnce.Do(func() { close(closed) })
+	}, nil
+}
+
+// start a routine that reloads the configuration upon receiving SIGHUP.
+// If a file check interval is provided, the configuration is also
+// reloaded when the modification time of the configuration file
+// changes.
+func (cr *configurationReloader) start(group program.Group, fileCheckInterval time.Duration) error {
+	fileInfo, err := os.Stat(cr.path)
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to obtain properties of %#v", cr.path)
+	}
+	lastModificationTime := fileInfo.ModTime()
+
+	group.Go(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGHUP)
+		defer signal.Stop(signals)
+
+		var fileCheckTicks <-chan time.Time
+		if fileCheckInterval > 0 {
+			ticker := time.NewTicker(fileCheckInterval)
+			defer ticker.Stop()
+			fileCheckTicks = ticker.C
+		}
+
+		for {
+			select {
+			case <-signals:
+				log.Print("Received SIGHUP, reloading configuration")
+			case <-fileCheckTicks:
+				fileInfo, err := os.Stat(cr.path)
+				if err != nil {
+					log.Printf("Failed to obtain properties of %#v: %s", cr.path, err)
+					continue
+				}
+				if fileInfo.ModTime().Equal(lastModificationTime) {
+					continue
+				}
+				lastModificationTime = fileInfo.ModTime()
+				log.Print("Configuration file was modified, reloading configuration")
+			case <-ctx.Done():
+				return nil
+			}
+			if err := cr.reload(); err != nil {
+				log.Print("Failed to reload configuration: ", err)
+			}
+		}
+	})
+	return nil
+}
+
+// reload the configuration file. Either all changes that can be made at
+// runtime are applied, or none of them are.
+func (cr *configurationReloader) reload() error {
+	var newConfiguration bb_worker.ApplicationConfiguration
+	if err := util.UnmarshalConfigurationFromFile(cr.path, &newConfiguration); err != nil {
+		return util.StatusWrapf(err, "Failed to read configuration from %s", cr.path)
+	}
+
+	// Validate the new configuration prior to applying any of the
+	// changes.
+	oldBuildDirectories := cr.currentConfiguration.BuildDirectories
+	newBuildDirectories := newConfiguration.BuildDirectories
+	if len(newBuildDirectories) != len(oldBuildDirectories) {
+		return status.Errorf(codes.InvalidArgument, "Number of build directories changed from %d to %d, which requires a restart", len(oldBuildDirectories), len(newBuildDirectories))
+	}
+	var oldRunnerConfigurations, newRunnerConfigurations []*bb_worker.RunnerConfiguration
+	for i, newBuildDirectory := range newBuildDirectories {
+		if oldCount, newCount := len(oldBuildDirectories[i].Runners), len(newBuildDirectory.Runners); newCount != oldCount {
+			return status.Errorf(codes.InvalidArgument, "Number of runners of build directory at index %d changed from %d to %d, which requires a restart", i, oldCount, newCount)
+		}
+		oldRunnerConfigurations = append(oldRunnerConfigurations, oldBuildDirectories[i].Runners...)
+		newRunnerConfigurations = append(newRunnerConfigurations, newBuildDirectory.Runners...)
+	}
+	for i, runnerConfiguration := range newRunnerConfigurations {
+		if concurrency := runnerConfiguration.Concurrency; concurrency < 1 || concurrency > cr.runners[i].maximumConcurrency {
+			return status.Errorf(codes.InvalidArgument, "Concurrency of runner at index %d must be in range [1, %d] without restarting, while %d was provided", i, cr.runners[i].maximumConcurrency, concurrency)
+		}
+	}
+
+	var newContentAddressableStorage, newActionCache blobstore.BlobAccess
+	var closeNewStorageBackends func()
+	blobstoreChanged := !proto.Equal(cr.currentConfiguration.Blobstore, newConfiguration.Blobstore)
+	if blobstoreChanged {
+		var err error
+		newContentAddressableStorage, newActionCache, closeNewStorageBackends, err = newClosableCASAndAC(cr.storageGroup, cr.newCASAndAC, newConfiguration.Blobstore)
+		if err != nil {
+			return util.StatusWrap(err, "Failed to create storage backends")
+		}
+	}
+
+	// Apply the changes.
+	var waitForPreviousContentAddressableStorage, waitForPreviousActionCache func()
+	if blobstoreChanged {
+		waitForPreviousContentAddressableStorage = cr.contentAddressableStorage.Swap(newContentAddressableStorage)
+		waitForPreviousActionCache = cr.actionCache.Swap(newActionCache)
+		log.Print("Switched to new storage backends")
+	}
+	for i, newRunnerConfiguration := range newRunnerConfigurations {
+		// Only announce different platform properties if they
+		// changed, so that changes made through the WorkerAdmin
+		// gRPC service are otherwise retained.
+		oldRunnerConfiguration, runner := oldRunnerConfigurations[i], &cr.runners[i]
+		if newRunnerConfiguration.SizeClass != oldRunnerConfiguration.SizeClass || !proto.Equal(newRunnerConfiguration.Platform, oldRunnerConfiguration.Platform) {
+			runner.advertisedPlatform.Set(newRunnerConfiguration.Platform, newRunnerConfiguration.SizeClass)
+		}
+		if !proto.Equal(newRunnerConfiguration.Platform, oldRunnerConfiguration.Platform) {
+			// Changes to the size class partitions
+			// themselves require a restart.
+			for j, advertisedPlatform := range runner.sizeClassPartitionAdvertisedPlatforms {
+				advertisedPlatform.Set(newRunnerConfiguration.Platform, oldRunnerConfiguration.SizeClassPartitions[j].SizeClass)
+			}
+		}
+		runner.concurrencyLimit.Set(int(newRunnerConfiguration.Concurrency))
+	}
+
+	// Report changes to options that cannot be applied at runtime,
+	// so that operators are aware that a restart is needed.
+	if !proto.Equal(withoutReloadableOptions(cr.currentConfiguration), withoutReloadableOptions(&newConfiguration)) {
+		log.Print("Configuration contains changes that are only applied after restarting the worker")
+	}
+
+	// Retain options that cannot be applied at runtime, so that
+	// they keep on being reported.
+	appliedConfiguration := proto.Clone(cr.currentConfiguration).(*bb_worker.ApplicationConfiguration)
+	appliedConfiguration.Blobstore = newConfiguration.Blobstore
+	i := 0
+	for _, buildDirectory := range appliedConfiguration.BuildDirectories {
+		for _, appliedRunnerConfiguration := range buildDirectory.Runners {
+			newRunnerConfiguration := newRunnerConfigurations[i]
+			appliedRunnerConfiguration.Concurrency = newRunnerConfiguration.Concurrency
+			appliedRunnerConfiguration.Platform = newRunnerConfiguration.Platform
+			appliedRunnerConfiguration.SizeClass = newRunnerConfiguration.SizeClass
+			i++
+		}
+	}
+	cr.currentConfiguration = appliedConfiguration
+
+	// Close the previous storage backends once all calls against
+	// them have completed.
+	if blobstoreChanged {
+		waitForPreviousContentAddressableStorage()
+		waitForPreviousActionCache()
+		cr.closeStorageBackends()
+		cr.closeStorageBackends = closeNewStorageBackends
+		log.Print("Closed previous storage backends")
+	}
+	return nil
+}
+
+// withoutReloadableOptions returns a copy of a configuration from which
+// all options that may be changed at runtime have been removed.
+func withoutReloadableOptions(configuration *bb_worker.ApplicationConfiguration) *bb_worker.ApplicationConfiguration {
+	stripped := proto.Clone(configuration).(*bb_worker.ApplicationConfiguration)
+	stripped.Blobstore = nil
+	for _, buildDirectory := range stripped.BuildDirectories {
+		for _, runnerConfiguration := range buildDirectory.Runners {
+			runnerConfiguration.Concurrency = 0
+			runnerConfiguration.Platform = nil
+			runnerConfiguration.SizeClass = 0
+		}
+	}
+	return stripped
+}
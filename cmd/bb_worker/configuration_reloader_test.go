@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	re_blobstore "github.com/buildbarn/bb-remote-execution/pkg/blobstore"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/configuration/bb_worker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/program"
+	blobstore_pb "github.com/buildbarn/bb-storage/pkg/proto/configuration/blobstore"
+	grpc_pb "github.com/buildbarn/bb-storage/pkg/proto/configuration/grpc"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestBlobstoreConfiguration(address string) *blobstore_pb.BlobstoreConfiguration {
+	return &blobstore_pb.BlobstoreConfiguration{
+		ContentAddressableStorage: &blobstore_pb.BlobAccessConfiguration{
+			Backend: &blobstore_pb.BlobAccessConfiguration_Grpc{
+				Grpc: &grpc_pb.ClientConfiguration{Address: address},
+			},
+		},
+		ActionCache: &blobstore_pb.BlobAccessConfiguration{
+			Backend: &blobstore_pb.BlobAccessConfiguration_Grpc{
+				Grpc: &grpc_pb.ClientConfiguration{Address: address},
+			},
+		},
+	}
+}
+
+func newTestRunnerConfiguration(concurrency uint64, os string, sizeClass uint32) *bb_worker.RunnerConfiguration {
+	return &bb_worker.RunnerConfiguration{
+		Endpoint:           &grpc_pb.ClientConfiguration{Address: "unix:///worker/runner"},
+		Concurrency:        concurrency,
+		MaximumConcurrency: 4,
+		Platform: &remoteexecution.Platform{
+			Properties: []*remoteexecution.Platform_Property{
+				{Name: "os", Value: os},
+			},
+		},
+		SizeClass: sizeClass,
+	}
+}
+
+func TestConfigurationReloaderReload(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	initialConfiguration := &bb_worker.ApplicationConfiguration{
+		Blobstore: newTestBlobstoreConfiguration("storage-a:8980"),
+		BuildDirectories: []*bb_worker.BuildDirectoryConfiguration{{
+			Runners: []*bb_worker.RunnerConfiguration{
+				newTestRunnerConfiguration(2, "linux", 1),
+			},
+		}},
+		MaximumMessageSizeBytes: 1 << 20,
+	}
+
+	for _, tc := range []struct {
+		name             string
+		newConfiguration *bb_worker.ApplicationConfiguration
+		expectedErr      error
+		// State of the runner after reloading.
+		expectedOS          string
+		expectedSizeClass   uint32
+		expectedConcurrency int
+		// Whether new storage backends should have been created.
+		expectedStorageAddress string
+	}{
+		{
+			name:                   "Unchanged",
+			newConfiguration:       initialConfiguration,
+			expectedOS:             "linux",
+			expectedSizeClass:      1,
+			expectedConcurrency:    2,
+			expectedStorageAddress: "storage-a:8980",
+		},
+		{
+			name: "RunnerChanged",
+			newConfiguration: &bb_worker.ApplicationConfiguration{
+				Blobstore: newTestBlobstoreConfiguration("storage-a:8980"),
+				BuildDirectories: []*bb_worker.BuildDirectoryConfiguration{{
+					Runners: []*bb_worker.RunnerConfiguration{
+						newTestRunnerConfiguration(4, "freebsd", 2),
+					},
+				}},
+				MaximumMessageSizeBytes: 1 << 20,
+			},
+			expectedOS:             "freebsd",
+			expectedSizeClass:      2,
+			expectedConcurrency:    4,
+			expectedStorageAddress: "storage-a:8980",
+		},
+		{
+			name: "BlobstoreChanged",
+			newConfiguration: &bb_worker.ApplicationConfiguration{
+				Blobstore: newTestBlobstoreConfiguration("storage-b:8980"),
+				BuildDirectories: []*bb_worker.BuildDirectoryConfiguration{{
+					Runners: []*bb_worker.RunnerConfiguration{
+						newTestRunnerConfiguration(2, "linux", 1),
+					},
+				}},
+				MaximumMessageSizeBytes: 1 << 20,
+			},
+			expectedOS:             "linux",
+			expectedSizeClass:      1,
+			expectedConcurrency:    2,
+			expectedStorageAddress: "storage-b:8980",
+		},
+		{
+			name: "ConcurrencyTooHigh",
+			newConfiguration: &bb_worker.ApplicationConfiguration{
+				Blobstore: newTestBlobstoreConfiguration("storage-b:8980"),
+				BuildDirectories: []*bb_worker.BuildDirectoryConfiguration{{
+					Runners: []*bb_worker.RunnerConfiguration{
+						newTestRunnerConfiguration(5, "freebsd", 2),
+					},
+				}},
+				MaximumMessageSizeBytes: 1 << 20,
+			},
+			expectedErr:            status.Error(codes.InvalidArgument, "Concurrency of runner at index 0 must be in range [1, 4] without restarting, while 5 was provided"),
+			expectedOS:             "linux",
+			expectedSizeClass:      1,
+			expectedConcurrency:    2,
+			expectedStorageAddress: "storage-a:8980",
+		},
+		{
+			name: "BuildDirectoryCountChanged",
+			newConfiguration: &bb_worker.ApplicationConfiguration{
+				Blobstore: newTestBlobstoreConfiguration("storage-b:8980"),
+				BuildDirectories: []*bb_worker.BuildDirectoryConfiguration{
+					{
+						Runners: []*bb_worker.RunnerConfiguration{
+							newTestRunnerConfiguration(4, "freebsd", 2),
+						},
+					},
+					{
+						Runners: []*bb_worker.RunnerConfiguration{
+							newTestRunnerConfiguration(1, "freebsd", 2),
+						},
+					},
+				},
+				MaximumMessageSizeBytes: 1 << 20,
+			},
+			expectedErr:            status.Error(codes.InvalidArgument, "Number of build directories changed from 1 to 2, which requires a restart"),
+			expectedOS:             "linux",
+			expectedSizeClass:      1,
+			expectedConcurrency:    2,
+			expectedStorageAddress: "storage-a:8980",
+		},
+		{
+			name: "RunnerCountChanged",
+			newConfiguration: &bb_worker.ApplicationConfiguration{
+				Blobstore: newTestBlobstoreConfiguration("storage-b:8980"),
+				BuildDirectories: []*bb_worker.BuildDirectoryConfiguration{{
+					Runners: []*bb_worker.RunnerConfiguration{
+						newTestRunnerConfiguration(4, "freebsd", 2),
+						newTestRunnerConfiguration(1, "freebsd", 2),
+					},
+				}},
+				MaximumMessageSizeBytes: 1 << 20,
+			},
+			expectedErr:            status.Error(codes.InvalidArgument, "Number of runners of build directory at index 0 changed from 1 to 2, which requires a restart"),
+			expectedOS:             "linux",
+			expectedSizeClass:      1,
+			expectedConcurrency:    2,
+			expectedStorageAddress: "storage-a:8980",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "bb_worker.json")
+			data, err := protojson.Marshal(tc.newConfiguration)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(path, data, 0o666))
+
+			require.NoError(t, program.RunLocal(ctx, func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+				// Storage backends are mocks that are keyed
+				// by the address of the configuration they
+				// were created from. Every backend launches a
+				// routine, so that we can observe that they
+				// are closed.
+				storageBackends := map[string]*mock.MockBlobAccess{}
+				storageBackendsClosed := map[string]chan struct{}{}
+				newCASAndAC := func(group program.Group, configuration *blobstore_pb.BlobstoreConfiguration) (blobstore.BlobAccess, blobstore.BlobAccess, error) {
+					address := configuration.ContentAddressableStorage.GetGrpc().Address
+					require.NotContains(t, storageBackends, address)
+					storageBackends[address] = mock.NewMockBlobAccess(ctrl)
+					closed := make(chan struct{})
+					storageBackendsClosed[address] = closed
+					group.Go(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+						<-ctx.Done()
+						close(closed)
+						return nil
+					})
+					return storageBackends[address], storageBackends[address], nil
+				}
+				contentAddressableStorage, actionCache, closeStorageBackends, err := newClosableCASAndAC(dependenciesGroup, newCASAndAC, initialConfiguration.Blobstore)
+				require.NoError(t, err)
+
+				advertisedPlatform := builder.NewAdvertisedPlatform(initialConfiguration.BuildDirectories[0].Runners[0].Platform, 1)
+				concurrencyLimit := builder.NewConcurrencyLimit(2)
+				cr := &configurationReloader{
+					path:                 path,
+					currentConfiguration: proto.Clone(initialConfiguration).(*bb_worker.ApplicationConfiguration),
+					runners: []reloadableRunner{{
+						advertisedPlatform: advertisedPlatform,
+						concurrencyLimit:   concurrencyLimit,
+						maximumConcurrency: 4,
+					}},
+					storageGroup:              dependenciesGroup,
+					newCASAndAC:               newCASAndAC,
+					contentAddressableStorage: re_blobstore.NewSwappableBlobAccess(contentAddressableStorage),
+					actionCache:               re_blobstore.NewSwappableBlobAccess(actionCache),
+					closeStorageBackends:      closeStorageBackends,
+				}
+
+				err = cr.reload()
+				if tc.expectedErr == nil {
+					require.NoError(t, err)
+				} else {
+					testutil.RequireEqualStatus(t, tc.expectedErr, err)
+				}
+
+				// Check the state of the runner.
+				platform, sizeClass := advertisedPlatform.Get()
+				testutil.RequireEqualProto(t, &remoteexecution.Platform{
+					Properties: []*remoteexecution.Platform_Property{
+						{Name: "os", Value: tc.expectedOS},
+					},
+				}, platform)
+				require.Equal(t, tc.expectedSizeClass, sizeClass)
+				canceledCtx, cancel := context.WithCancel(ctx)
+				cancel()
+				for threadIndex := 0; threadIndex < 4; threadIndex++ {
+					require.Equal(t, threadIndex < tc.expectedConcurrency, concurrencyLimit.WaitUntilThreadEnabled(canceledCtx, threadIndex))
+				}
+
+				// Calls should be forwarded to the storage
+				// backend created from the expected
+				// configuration. If this is not the initial
+				// backend, the initial backend should have
+				// been closed.
+				exampleDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+				storageBackends[tc.expectedStorageAddress].EXPECT().FindMissing(ctx, exampleDigest.ToSingletonSet()).
+					Return(digest.EmptySet, nil).
+					Times(2)
+				missing, err := cr.contentAddressableStorage.FindMissing(ctx, exampleDigest.ToSingletonSet())
+				require.NoError(t, err)
+				require.Equal(t, digest.EmptySet, missing)
+				missing, err = cr.actionCache.FindMissing(ctx, exampleDigest.ToSingletonSet())
+				require.NoError(t, err)
+				require.Equal(t, digest.EmptySet, missing)
+				if tc.expectedStorageAddress != "storage-a:8980" {
+					<-storageBackendsClosed["storage-a:8980"]
+					require.Len(t, storageBackends, 2)
+				} else {
+					require.Len(t, storageBackends, 1)
+				}
+				return nil
+			}))
+		})
+	}
+}
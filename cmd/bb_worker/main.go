@@ -2,9 +2,13 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"log"
+	"math/rand"
 	"net/url"
 	"os"
 	"regexp"
@@ -13,37 +17,64 @@ import (
 	"sync/atomic"
 	"time"
 
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	re_blobstore "github.com/buildbarn/bb-remote-execution/pkg/blobstore"
 	"github.com/buildbarn/bb-remote-execution/pkg/builder"
 	"github.com/buildbarn/bb-remote-execution/pkg/cas"
 	"github.com/buildbarn/bb-remote-execution/pkg/cleaner"
 	re_clock "github.com/buildbarn/bb-remote-execution/pkg/clock"
+	"github.com/buildbarn/bb-remote-execution/pkg/containerimage"
 	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
 	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
 	virtual_configuration "github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual/configuration"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/cacheadmin"
 	cal_proto "github.com/buildbarn/bb-remote-execution/pkg/proto/completedactionlogger"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/configuration/bb_worker"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/debugger"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
 	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/workeradmin"
+	"github.com/buildbarn/bb-remote-execution/pkg/secrets"
+	re_sync "github.com/buildbarn/bb-remote-execution/pkg/sync"
+	"github.com/buildbarn/bb-storage/pkg/auth"
 	"github.com/buildbarn/bb-storage/pkg/blobstore"
 	blobstore_configuration "github.com/buildbarn/bb-storage/pkg/blobstore/configuration"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/readcaching"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/readfallback"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/replication"
 	"github.com/buildbarn/bb-storage/pkg/clock"
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/eviction"
 	"github.com/buildbarn/bb-storage/pkg/filesystem"
 	"github.com/buildbarn/bb-storage/pkg/global"
+	bb_grpc "github.com/buildbarn/bb-storage/pkg/grpc"
+	bb_http "github.com/buildbarn/bb-storage/pkg/http"
 	"github.com/buildbarn/bb-storage/pkg/program"
+	blobstore_pb "github.com/buildbarn/bb-storage/pkg/proto/configuration/blobstore"
 	"github.com/buildbarn/bb-storage/pkg/random"
 	"github.com/buildbarn/bb-storage/pkg/util"
 	"github.com/google/uuid"
 
 	"golang.org/x/sync/semaphore"
+	"google.golang.org/genproto/googleapis/bytestream"
+	"google.golang.org/genproto/googleapis/devtools/build/v1"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	"go.opentelemetry.io/otel"
 )
 
+// Priorities of operations against the Content Addressable Storage,
+// used when 'content_addressable_storage_concurrency' is set.
+const (
+	casPriorityWrites = iota
+	casPriorityExecution
+	casPriorityVirtualFileSystem
+)
+
 func main() {
 	program.RunMain(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
 		if len(os.Args) != 2 {
@@ -79,20 +110,180 @@ func main() {
 		if err != nil {
 			return util.StatusWrap(err, "Failed to create file pool")
 		}
+		var filePoolBudget *re_filesystem.FilePoolBudget
+		if budgetConfiguration := configuration.FilePoolBudget; budgetConfiguration != nil {
+			var maximumReclaimWait time.Duration
+			if d := budgetConfiguration.MaximumReclaimWait; d != nil {
+				if err := d.CheckValid(); err != nil {
+					return util.StatusWrap(err, "Invalid file pool budget maximum reclaim wait")
+				}
+				maximumReclaimWait = d.AsDuration()
+			}
+			filePoolBudget = re_filesystem.NewFilePoolBudget(
+				budgetConfiguration.MaximumFileCount,
+				budgetConfiguration.MaximumTotalSizeBytes,
+				clock.SystemClock,
+				maximumReclaimWait)
+		}
 
-		// Storage access.
-		globalContentAddressableStorage, actionCache, err := blobstore_configuration.NewCASAndACBlobAccessFromConfiguration(
-			dependenciesGroup,
-			configuration.Blobstore,
-			grpcClientFactory,
-			int(configuration.MaximumMessageSizeBytes))
+		var gracefulShutdownTimeout time.Duration
+		if d := configuration.GracefulShutdownTimeout; d != nil {
+			if err := d.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid graceful shutdown timeout")
+			}
+			gracefulShutdownTimeout = d.AsDuration()
+		}
+
+		var workerResourcesReporter builder.WorkerResourcesReporter
+		if reportingConfiguration := configuration.WorkerResourcesReporting; reportingConfiguration != nil {
+			workerResourcesReporter = builder.NewSystemWorkerResourcesReporter(reportingConfiguration.BuildDirectoryPath)
+		}
+		var terminationTimeReporter builder.TerminationTimeReporter
+		if path := configuration.ExpectedTerminationTimePath; path != "" {
+			terminationTimeReporter = builder.NewFileTerminationTimeReporter(path)
+		}
+
+		var failedActionPauser builder.FailedActionPauser
+		if pauseOnFailureConfiguration := configuration.PauseOnFailure; pauseOnFailureConfiguration != nil {
+			if err := pauseOnFailureConfiguration.MaximumPauseDuration.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid maximum pause duration")
+			}
+			debugAuthorizer, err := auth.DefaultAuthorizerFactory.NewAuthorizerFromConfiguration(pauseOnFailureConfiguration.DebugAuthorizer)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create debug authorizer")
+			}
+			pausedActionRegistry := builder.NewPausedActionRegistry(
+				clock.SystemClock,
+				uuid.NewRandom,
+				pauseOnFailureConfiguration.MaximumPauseDuration.AsDuration(),
+				debugAuthorizer)
+			if err := bb_grpc.NewServersFromConfigurationAndServe(
+				pauseOnFailureConfiguration.GrpcServers,
+				func(s grpc.ServiceRegistrar) {
+					debugger.RegisterDebuggerServer(s, pausedActionRegistry)
+				},
+				siblingsGroup,
+			); err != nil {
+				return util.StatusWrap(err, "gRPC server failure")
+			}
+			failedActionPauser = pausedActionRegistry
+		}
+
+		// Storage access. The backends are wrapped, so that
+		// they can be replaced when the configuration is
+		// reloaded, without discarding any of the caches that
+		// are layered on top of them.
+		newCASAndAC := func(group program.Group, blobstoreConfiguration *blobstore_pb.BlobstoreConfiguration) (blobstore.BlobAccess, blobstore.BlobAccess, error) {
+			return blobstore_configuration.NewCASAndACBlobAccessFromConfiguration(
+				group,
+				blobstoreConfiguration,
+				grpcClientFactory,
+				int(configuration.MaximumMessageSizeBytes))
+		}
+		baseContentAddressableStorage, baseActionCache, closeStorageBackends, err := newClosableCASAndAC(dependenciesGroup, newCASAndAC, configuration.Blobstore)
 		if err != nil {
 			return err
 		}
+		swappableContentAddressableStorage := re_blobstore.NewSwappableBlobAccess(baseContentAddressableStorage)
+		swappableActionCache := re_blobstore.NewSwappableBlobAccess(baseActionCache)
+		var globalContentAddressableStorage, actionCache blobstore.BlobAccess = swappableContentAddressableStorage, swappableActionCache
+		if zstdCompressionConfiguration := configuration.ZstdCompression; zstdCompressionConfiguration != nil {
+			casConnection, err := grpcClientFactory.NewClientFromConfiguration(zstdCompressionConfiguration.Client)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create zstd compression RPC client")
+			}
+			globalContentAddressableStorage = re_blobstore.NewZstdByteStreamBlobAccess(
+				globalContentAddressableStorage,
+				bytestream.NewByteStreamClient(casConnection),
+				remoteexecution.NewCapabilitiesClient(casConnection),
+				uuid.NewRandom,
+				/* writeChunkSize = */ 65536,
+				zstdCompressionConfiguration.MinimumSizeBytes)
+		}
+		if tiersConfiguration := configuration.ContentAddressableStorageTiers; tiersConfiguration != nil {
+			if err := tiersConfiguration.UnavailabilityDuration.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid Content Addressable Storage tier unavailability duration")
+			}
+			tiers := []re_blobstore.TieredBlobAccessTier{{
+				Name:       tiersConfiguration.PrimaryTierName,
+				BlobAccess: globalContentAddressableStorage,
+			}}
+			for i, tierConfiguration := range tiersConfiguration.Tiers {
+				info, err := blobstore_configuration.NewBlobAccessFromConfiguration(
+					dependenciesGroup,
+					tierConfiguration.Backend,
+					blobstore_configuration.NewCASBlobAccessCreator(
+						grpcClientFactory,
+						int(configuration.MaximumMessageSizeBytes)))
+				if err != nil {
+					return util.StatusWrapf(err, "Failed to create Content Addressable Storage tier at index %d", i)
+				}
+				tiers = append(tiers, re_blobstore.TieredBlobAccessTier{
+					Name:       tierConfiguration.Name,
+					BlobAccess: info.BlobAccess,
+				})
+			}
+			globalContentAddressableStorage = re_blobstore.NewTieredBlobAccess(
+				"ContentAddressableStorage",
+				tiers,
+				clock.SystemClock,
+				tiersConfiguration.UnavailabilityDuration.AsDuration())
+		}
+		for i, federatedConfiguration := range configuration.FederatedContentAddressableStorages {
+			info, err := blobstore_configuration.NewBlobAccessFromConfiguration(
+				dependenciesGroup,
+				federatedConfiguration,
+				blobstore_configuration.NewCASBlobAccessCreator(
+					grpcClientFactory,
+					int(configuration.MaximumMessageSizeBytes)))
+			if err != nil {
+				return util.StatusWrapf(err, "Failed to create federated Content Addressable Storage at index %d", i)
+			}
+			globalContentAddressableStorage = readfallback.NewReadFallbackBlobAccess(
+				globalContentAddressableStorage,
+				info.BlobAccess,
+				replication.NewLocalBlobReplicator(info.BlobAccess, globalContentAddressableStorage))
+		}
 		globalContentAddressableStorage = re_blobstore.NewExistencePreconditionBlobAccess(globalContentAddressableStorage)
 
+		// If configured, limit the number of concurrent operations
+		// against the CAS. Reads performed on behalf of the
+		// virtual file system take precedence over other
+		// operations, as build actions stall while these are
+		// pending.
+		virtualFileSystemContentAddressableStorage := globalContentAddressableStorage
+		if concurrencyConfiguration := configuration.ContentAddressableStorageConcurrency; concurrencyConfiguration != nil {
+			if concurrencyConfiguration.MaximumConcurrentOperations <= 0 {
+				return status.Error(codes.InvalidArgument, "Maximum number of concurrent Content Addressable Storage operations must be positive")
+			}
+			contentAddressableStorageSemaphore := re_sync.NewPrioritySemaphore(concurrencyConfiguration.MaximumConcurrentOperations)
+			virtualFileSystemContentAddressableStorage = re_blobstore.NewPriorityLimitingBlobAccess(
+				globalContentAddressableStorage,
+				contentAddressableStorageSemaphore,
+				casPriorityVirtualFileSystem,
+				casPriorityWrites)
+			globalContentAddressableStorage = re_blobstore.NewPriorityLimitingBlobAccess(
+				globalContentAddressableStorage,
+				contentAddressableStorageSemaphore,
+				casPriorityExecution,
+				casPriorityWrites)
+		}
+
+		// If configured, let the virtual file system read large
+		// files in chunked form, so that only the parts that are
+		// accessed by build actions need to be fetched.
+		virtualFileSystemContentAddressableStorage, err = cas.NewChunkedBlobAccessFromConfiguration(
+			configuration.ChunkedBlobAccess,
+			virtualFileSystemContentAddressableStorage,
+			actionCache,
+			int(configuration.MaximumMessageSizeBytes))
+		if err != nil {
+			return util.StatusWrap(err, "Failed to create chunked blob access")
+		}
+
 		var prefetchingDownloadConcurrency *semaphore.Weighted
 		var fileSystemAccessCache blobstore.BlobAccess
+		var accessProfileCache *builder.AccessProfileCache
 		prefetchingConfiguration := configuration.Prefetching
 		if prefetchingConfiguration != nil {
 			info, err := blobstore_configuration.NewBlobAccessFromConfiguration(
@@ -106,26 +297,118 @@ func main() {
 			}
 			fileSystemAccessCache = info.BlobAccess
 			prefetchingDownloadConcurrency = semaphore.NewWeighted(prefetchingConfiguration.DownloadConcurrency)
+
+			if accessProfileCacheConfiguration := prefetchingConfiguration.AccessProfileCache; accessProfileCacheConfiguration != nil {
+				evictionSet, err := eviction.NewSetFromConfiguration[string](accessProfileCacheConfiguration.CacheReplacementPolicy)
+				if err != nil {
+					return util.StatusWrap(err, "Failed to create eviction set for access profile cache")
+				}
+				accessProfileCache = builder.NewAccessProfileCache(
+					int(accessProfileCacheConfiguration.MaximumCacheSize),
+					eviction.NewMetricsSet(evictionSet, "AccessProfileCache"))
+			}
 		}
 
 		// Cached read access for Directory objects stored in the
 		// Content Addressable Storage. All workers make use of the same
-		// cache, to increase the hit rate. This process does not read
-		// Tree objects.
-		directoryFetcher, err := cas.NewCachingDirectoryFetcherFromConfiguration(
-			configuration.DirectoryCache,
-			cas.NewBlobAccessDirectoryFetcher(
-				globalContentAddressableStorage,
-				/* maximumDirectorySizeBytes = */ int(configuration.MaximumMessageSizeBytes),
-				/* maximumTreeSizeBytes = */ 0))
+		// cache, to increase the hit rate, unless build directories
+		// declare their own persistent directory cache.
+		directoryFetcher, err := newDirectoryFetcher(
+			dependenciesGroup,
+			&configuration,
+			configuration.PersistentDirectoryCache,
+			globalContentAddressableStorage,
+			grpcClientFactory)
 		if err != nil {
-			return util.StatusWrap(err, "Failed to create caching directory fetcher")
+			return err
+		}
+
+		// Download toolchains that runners may mount inside the
+		// input roots of actions.
+		if pinnedToolchainsConfiguration := configuration.PinnedToolchains; pinnedToolchainsConfiguration != nil {
+			rootDirectoryDigests := make([]digest.Digest, 0, len(pinnedToolchainsConfiguration.Toolchains))
+			for i, toolchain := range pinnedToolchainsConfiguration.Toolchains {
+				instanceName, err := digest.NewInstanceName(toolchain.InstanceName)
+				if err != nil {
+					return util.StatusWrapf(err, "Invalid instance name for pinned toolchain at index %d", i)
+				}
+				digestFunction, err := instanceName.GetDigestFunction(toolchain.DigestFunction, len(toolchain.RootDirectoryDigest.GetHash()))
+				if err != nil {
+					return util.StatusWrapf(err, "Invalid digest function for pinned toolchain at index %d", i)
+				}
+				rootDirectoryDigest, err := digestFunction.NewDigestFromProto(toolchain.RootDirectoryDigest)
+				if err != nil {
+					return util.StatusWrapf(err, "Invalid root directory digest for pinned toolchain at index %d", i)
+				}
+				rootDirectoryDigests = append(rootDirectoryDigests, rootDirectoryDigest)
+			}
+
+			toolchainsDirectory, err := filesystem.NewLocalDirectory(pinnedToolchainsConfiguration.DirectoryPath)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to open pinned toolchains directory")
+			}
+			downloadConcurrency := pinnedToolchainsConfiguration.DownloadConcurrency
+			if downloadConcurrency <= 0 {
+				downloadConcurrency = 1
+			}
+			err = builder.MaterializePinnedToolchains(
+				ctx,
+				toolchainsDirectory,
+				rootDirectoryDigests,
+				directoryFetcher,
+				cas.NewBlobAccessFileFetcher(globalContentAddressableStorage),
+				semaphore.NewWeighted(downloadConcurrency))
+			toolchainsDirectory.Close()
+			if err != nil {
+				return util.StatusWrap(err, "Failed to materialize pinned toolchains")
+			}
 		}
 
 		if len(configuration.BuildDirectories) == 0 {
 			return status.Error(codes.InvalidArgument, "Cannot start worker without any build directories")
 		}
 
+		// Unmount FUSE and NFS file systems that were left behind
+		// by previous instances of bb_worker, prior to creating
+		// mounts for the current instance.
+		if staleMountCleaningConfiguration := configuration.StaleMountCleaning; staleMountCleaningConfiguration != nil {
+			var activeMountPoints []string
+			for _, buildDirectoryConfiguration := range configuration.BuildDirectories {
+				if backend, ok := buildDirectoryConfiguration.Backend.(*bb_worker.BuildDirectoryConfiguration_Virtual); ok {
+					activeMountPoints = append(activeMountPoints, backend.Virtual.Mount.GetMountPath())
+				}
+			}
+			staleMountCleaner := cleaner.NewStaleMountCleaner(
+				cleaner.SystemMountTable,
+				staleMountCleaningConfiguration.DirectoryPath,
+				activeMountPoints)
+			if err := staleMountCleaner(ctx); err != nil {
+				return util.StatusWrap(err, "Failed to clean stale mounts")
+			}
+			if interval := staleMountCleaningConfiguration.Interval; interval != nil {
+				if err := interval.CheckValid(); err != nil {
+					return util.StatusWrap(err, "Invalid stale mount cleaning interval")
+				}
+				if interval.AsDuration() <= 0 {
+					return status.Error(codes.InvalidArgument, "Stale mount cleaning interval must be positive")
+				}
+				dependenciesGroup.Go(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+					t := time.NewTicker(interval.AsDuration())
+					defer t.Stop()
+					for {
+						select {
+						case <-t.C:
+							if err := staleMountCleaner(ctx); err != nil {
+								log.Print("Failed to clean stale mounts: ", err)
+							}
+						case <-ctx.Done():
+							return nil
+						}
+					}
+				})
+			}
+		}
+
 		// Setup the RemoteCompletedActionLogger for the
 		// ActionLoggingBuildExecutor to ensure we only create
 		// one client per worker rather than one per runner.
@@ -160,23 +443,125 @@ func main() {
 			}()
 		}
 
+		var buildEventClient build.PublishBuildEventClient
+		var buildEventInstanceNamePatcher digest.InstanceNamePatcher
+		var buildEventAcknowledgementTimeout time.Duration
+		buildEventPublishingConfiguration := configuration.BuildEventPublishing
+		if buildEventPublishingConfiguration != nil {
+			buildEventConnection, err := grpcClientFactory.NewClientFromConfiguration(buildEventPublishingConfiguration.Client)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create a new gRPC client for publishing build events")
+			}
+			buildEventClient = build.NewPublishBuildEventClient(buildEventConnection)
+			instanceNamePrefix, err := digest.NewInstanceName(buildEventPublishingConfiguration.AddInstanceNamePrefix)
+			if err != nil {
+				return util.StatusWrapf(err, "Invalid instance name prefix %#v", buildEventPublishingConfiguration.AddInstanceNamePrefix)
+			}
+			buildEventInstanceNamePatcher = digest.NewInstanceNamePatcher(digest.EmptyInstanceName, instanceNamePrefix)
+			if err := buildEventPublishingConfiguration.AcknowledgementTimeout.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid build event acknowledgement timeout")
+			}
+			buildEventAcknowledgementTimeout = buildEventPublishingConfiguration.AcknowledgementTimeout.AsDuration()
+		}
+
+		var instanceNameMapping *builder.InstanceNameMapping
+		if len(configuration.InstanceNameMappings) > 0 {
+			instanceNameMapping = builder.NewInstanceNameMapping()
+			for _, mapping := range configuration.InstanceNameMappings {
+				oldPrefix, err := digest.NewInstanceName(mapping.InstanceNamePrefix)
+				if err != nil {
+					return util.StatusWrapf(err, "Invalid instance name prefix %#v", mapping.InstanceNamePrefix)
+				}
+				newPrefix, err := digest.NewInstanceName(mapping.NewInstanceNamePrefix)
+				if err != nil {
+					return util.StatusWrapf(err, "Invalid new instance name prefix %#v", mapping.NewInstanceNamePrefix)
+				}
+				if err := instanceNameMapping.Add(oldPrefix, newPrefix); err != nil {
+					return util.StatusWrap(err, "Failed to add instance name mapping")
+				}
+			}
+		}
+
 		outputUploadConcurrency := configuration.OutputUploadConcurrency
 		if outputUploadConcurrency <= 0 {
-			return status.Errorf(codes.InvalidArgument, "Nonpositive output upload concurrency: ", outputUploadConcurrency)
+			return status.Errorf(codes.InvalidArgument, "Nonpositive output upload concurrency: %d", outputUploadConcurrency)
 		}
 		outputUploadConcurrencySemaphore := semaphore.NewWeighted(outputUploadConcurrency)
 
+		// Actions running on all runners share the same physical
+		// memory. Decline actions whose memory requirements can't
+		// be satisfied, instead of letting the OOM killer pick
+		// one to terminate.
+		var memoryAdmission *builder.MemoryAdmission
+		if memoryAdmissionConfiguration := configuration.MemoryAdmission; memoryAdmissionConfiguration != nil {
+			capacityBytes := memoryAdmissionConfiguration.CapacityBytes
+			if capacityBytes == 0 {
+				capacityBytes, err = builder.GetPhysicalMemoryBytes()
+				if err != nil {
+					return util.StatusWrap(err, "Failed to obtain amount of physical memory for memory admission")
+				}
+			}
+			memoryAdmission = builder.NewMemoryAdmission(capacityBytes, memoryAdmissionConfiguration.DefaultActionMemoryBytes)
+		}
+
 		testInfrastructureFailureShutdownState := builder.NewTestInfrastructureFailureShutdownState()
-		for _, buildDirectoryConfiguration := range configuration.BuildDirectories {
+		cachingFileFetchers := map[string]cas.CachingFileFetcher{}
+		var advertisedPlatforms []*builder.AdvertisedPlatform
+		executingActionRegistry := builder.NewExecutingActionRegistry(clock.SystemClock)
+		var reloadableRunners []reloadableRunner
+		workerIDs := workerIDSet{}
+		for buildDirectoryIndex, buildDirectoryConfiguration := range configuration.BuildDirectories {
+			// Build directories may be placed on separate
+			// disks, each having its own file pool.
+			buildDirectoryFilePool := filePool
+			if filePoolConfiguration := buildDirectoryConfiguration.FilePool; filePoolConfiguration != nil {
+				buildDirectoryFilePool, err = re_filesystem.NewFilePoolFromConfiguration(filePoolConfiguration)
+				if err != nil {
+					return util.StatusWrapf(err, "Failed to create file pool for build directory at index %d", buildDirectoryIndex)
+				}
+			}
+
+			// Similarly, build directories may store their own
+			// persistent cache of Directory objects.
+			buildDirectoryDirectoryFetcher := directoryFetcher
+			if persistentDirectoryCacheConfiguration := buildDirectoryConfiguration.PersistentDirectoryCache; persistentDirectoryCacheConfiguration != nil {
+				buildDirectoryDirectoryFetcher, err = newDirectoryFetcher(
+					dependenciesGroup,
+					&configuration,
+					persistentDirectoryCacheConfiguration,
+					globalContentAddressableStorage,
+					grpcClientFactory)
+				if err != nil {
+					return util.StatusWrapf(err, "Build directory at index %d", buildDirectoryIndex)
+				}
+			}
+
 			var virtualBuildDirectory virtual.PrepopulatedDirectory
 			var handleAllocator virtual.StatefulHandleAllocator
 			var symlinkFactory virtual.SymlinkFactory
 			var characterDeviceFactory virtual.CharacterDeviceFactory
 			var naiveBuildDirectory filesystem.DirectoryCloser
 			var fileFetcher cas.FileFetcher
+			var fileFetcherSemaphore *semaphore.Weighted
+			var inputRootPopulationOptions *builder.InputRootPopulationOptions
+			var inputRootPrefetcher builder.InputRootPrefetcher
+			var idlePrefetcher builder.IdlePrefetcher
+			var warmInputRootPool *builder.WarmInputRootPool
 			var buildDirectoryCleaner cleaner.Cleaner
+			var buildDirectoryQuotaManager builder.DirectoryQuotaManager
+			var buildDirectoryQuotaConfiguration *bb_worker.BuildDirectoryQuotaConfiguration
+			var buildDirectoryQuotaRootPath string
+			var tmpfsBuildDirectoriesConfiguration *bb_worker.TmpfsBuildDirectoriesConfiguration
+			var tmpfsBuildDirectoryManager builder.TmpfsManager
+			var tmpfsBuildDirectoryCapacity *builder.TmpfsBuildDirectoryCapacity
+			var tmpfsBuildDirectoryRootPath string
 			uploadBatchSize := blobstore.RecommendedFindMissingDigestsCount
 			var maximumExecutionTimeoutCompensation time.Duration
+			var eagerUploadSemaphore *semaphore.Weighted
+			var passthroughCacheDirectoryPath string
+			var onlyUploadModifiedOutputs bool
+			var reportOperationStatistics bool
+			var buildDirectoryNameGenerator random.ThreadSafeGenerator
 			switch backend := buildDirectoryConfiguration.Backend.(type) {
 			case *bb_worker.BuildDirectoryConfiguration_Virtual:
 				var mount virtual_configuration.Mount
@@ -213,16 +598,26 @@ func main() {
 					virtual.NewHandleAllocatingFileAllocator(
 						virtual.NewPoolBackedFileAllocator(
 							re_filesystem.EmptyFilePool,
-							util.DefaultErrorLogger),
+							util.DefaultErrorLogger,
+							nil),
 						handleAllocator),
 					symlinkFactory,
 					util.DefaultErrorLogger,
 					handleAllocator,
 					initialContentsSorter,
 					hiddenFilesPattern,
-					clock.SystemClock)
+					clock.SystemClock,
+					backend.Virtual.CaseInsensitive)
 
-				if err := mount.Expose(dependenciesGroup, virtualBuildDirectory); err != nil {
+				// Let all concurrently running actions share
+				// a single mount, while preventing them from
+				// accessing each other's build directories.
+				var mountRootDirectory virtual.Directory = virtualBuildDirectory
+				if backend.Virtual.IsolateActions {
+					mountRootDirectory = virtual.NewActionIsolatingDirectory(virtualBuildDirectory)
+					buildDirectoryNameGenerator = random.CryptoThreadSafeGenerator
+				}
+				if err := mount.Expose(dependenciesGroup, mountRootDirectory); err != nil {
 					return util.StatusWrap(err, "Failed to expose build directory mount")
 				}
 
@@ -236,6 +631,15 @@ func main() {
 					return util.StatusWrap(err, "Invalid maximum execution timeout compensation")
 				}
 				maximumExecutionTimeoutCompensation = backend.Virtual.MaximumExecutionTimeoutCompensation.AsDuration()
+				if maximumConcurrentEagerUploads := backend.Virtual.MaximumConcurrentEagerUploads; maximumConcurrentEagerUploads > 0 {
+					eagerUploadSemaphore = semaphore.NewWeighted(maximumConcurrentEagerUploads)
+				}
+				passthroughCacheDirectoryPath = backend.Virtual.PassthroughCacheDirectoryPath
+				onlyUploadModifiedOutputs = backend.Virtual.OnlyUploadModifiedOutputs
+				if onlyUploadModifiedOutputs && backend.Virtual.CaseInsensitive {
+					return status.Error(codes.InvalidArgument, "Only uploading modified outputs cannot be combined with case insensitive build directories")
+				}
+				reportOperationStatistics = backend.Virtual.ReportOperationStatistics
 			case *bb_worker.BuildDirectoryConfiguration_Native:
 				// Directory where actual builds take place.
 				nativeConfiguration := backend.Native
@@ -263,12 +667,140 @@ func main() {
 				if err != nil {
 					return util.StatusWrap(err, "Failed to create eviction set for cache directory")
 				}
-				fileFetcher = cas.NewHardlinkingFileFetcher(
-					cas.NewBlobAccessFileFetcher(globalContentAddressableStorage),
+				fileLinker := cas.HardlinkFile
+				if nativeConfiguration.CloneCachedFiles {
+					// Fail at startup if cloning is not
+					// supported, as opposed to failing
+					// every action afterwards.
+					fileLinker = cas.CloneFile
+					if err := cas.CheckFileLinkerSupported(fileLinker, cacheDirectory, naiveBuildDirectory); err != nil {
+						return util.StatusWrap(err, "Cloning files from the cache directory into the build directory is not supported")
+					}
+				}
+				fileFetcherContentAddressableStorage := globalContentAddressableStorage
+				if batchReadBlobsConfiguration := nativeConfiguration.BatchReadBlobs; batchReadBlobsConfiguration != nil {
+					if batchReadBlobsConfiguration.MaximumConcurrentBatches < 1 {
+						return status.Error(codes.InvalidArgument, "Maximum number of concurrent BatchReadBlobs calls must be positive")
+					}
+					casConnection, err := grpcClientFactory.NewClientFromConfiguration(batchReadBlobsConfiguration.Client)
+					if err != nil {
+						return util.StatusWrap(err, "Failed to create BatchReadBlobs RPC client")
+					}
+					fileFetcherContentAddressableStorage = re_blobstore.NewBatchReadingBlobAccess(
+						fileFetcherContentAddressableStorage,
+						remoteexecution.NewContentAddressableStorageClient(casConnection),
+						batchReadBlobsConfiguration.MaximumBlobSizeBytes,
+						batchReadBlobsConfiguration.MaximumBatchSizeBytes,
+						semaphore.NewWeighted(batchReadBlobsConfiguration.MaximumConcurrentBatches))
+				}
+				cachingFileFetcher := cas.NewHardlinkingFileFetcher(
+					cas.NewBlobAccessFileFetcher(fileFetcherContentAddressableStorage),
 					cacheDirectory,
 					int(nativeConfiguration.MaximumCacheFileCount),
 					nativeConfiguration.MaximumCacheSizeBytes,
-					eviction.NewMetricsSet(evictionSet, "HardlinkingFileFetcher"))
+					eviction.NewMetricsSet(evictionSet, "HardlinkingFileFetcher"),
+					fileLinker,
+					nativeConfiguration.VerifyCachedFiles)
+				cachingFileFetchers[nativeConfiguration.CacheDirectoryPath] = cachingFileFetcher
+				fileFetcher = cas.NewPhaseTimingFileFetcher(cachingFileFetcher)
+				inputFileDownloadConcurrency := nativeConfiguration.InputFileDownloadConcurrency
+				if inputFileDownloadConcurrency < 1 {
+					inputFileDownloadConcurrency = 1
+				}
+				fileFetcherSemaphore = semaphore.NewWeighted(inputFileDownloadConcurrency)
+
+				if populationConfiguration := nativeConfiguration.InputRootPopulation; populationConfiguration != nil {
+					inputRootPopulationOptions = &builder.InputRootPopulationOptions{
+						MaximumBytesPerSecondPerAction: populationConfiguration.MaximumBytesPerSecondPerAction,
+						Clock:                          clock.SystemClock,
+					}
+					if maximumConcurrentDirectoryFetches := populationConfiguration.MaximumConcurrentDirectoryFetches; maximumConcurrentDirectoryFetches > 0 {
+						inputRootPopulationOptions.DirectoryFetchSemaphore = semaphore.NewWeighted(maximumConcurrentDirectoryFetches)
+					}
+					if largeFileSizeBytes := populationConfiguration.LargeFileSizeBytes; largeFileSizeBytes > 0 {
+						maximumConcurrentLargeFileFetches := populationConfiguration.MaximumConcurrentLargeFileFetches
+						if maximumConcurrentLargeFileFetches < 1 {
+							maximumConcurrentLargeFileFetches = 1
+						}
+						inputRootPopulationOptions.LargeFileSizeBytes = largeFileSizeBytes
+						inputRootPopulationOptions.LargeFileFetchSemaphore = semaphore.NewWeighted(maximumConcurrentLargeFileFetches)
+					}
+				}
+
+				if prefetchDirectoryPath := nativeConfiguration.TentativeAssignmentPrefetchDirectoryPath; prefetchDirectoryPath != "" {
+					prefetchDirectory, err := filesystem.NewLocalDirectory(prefetchDirectoryPath)
+					if err != nil {
+						return util.StatusWrap(err, "Failed to open tentative assignment prefetch directory")
+					}
+					if err := prefetchDirectory.RemoveAllChildren(); err != nil {
+						return util.StatusWrap(err, "Failed to clear tentative assignment prefetch directory")
+					}
+					inputRootPrefetcher = builder.NewFileFetchingInputRootPrefetcher(buildDirectoryDirectoryFetcher, fileFetcher, prefetchDirectory)
+				}
+
+				if idlePrefetchingConfiguration := nativeConfiguration.IdlePrefetching; idlePrefetchingConfiguration != nil {
+					idlePrefetchDirectory, err := filesystem.NewLocalDirectory(idlePrefetchingConfiguration.ScratchDirectoryPath)
+					if err != nil {
+						return util.StatusWrap(err, "Failed to open idle prefetching scratch directory")
+					}
+					if err := idlePrefetchDirectory.RemoveAllChildren(); err != nil {
+						return util.StatusWrap(err, "Failed to clear idle prefetching scratch directory")
+					}
+					idlePrefetcher, err = builder.NewCacheStatisticsIdlePrefetcher(
+						cachingFileFetcher,
+						idlePrefetchDirectory,
+						int(idlePrefetchingConfiguration.MaximumFiles),
+						idlePrefetchingConfiguration.StatisticsPath)
+					if err != nil {
+						return util.StatusWrap(err, "Failed to create idle prefetcher")
+					}
+				}
+
+				if warmInputRootsConfiguration := nativeConfiguration.WarmInputRoots; warmInputRootsConfiguration != nil {
+					warmInputRootsDirectory, err := filesystem.NewLocalDirectory(warmInputRootsConfiguration.DirectoryPath)
+					if err != nil {
+						return util.StatusWrap(err, "Failed to open warm input roots directory")
+					}
+					if err := warmInputRootsDirectory.RemoveAllChildren(); err != nil {
+						return util.StatusWrap(err, "Failed to clear warm input roots directory")
+					}
+					warmInputRootPool = builder.NewWarmInputRootPool(warmInputRootsDirectory, int(warmInputRootsConfiguration.MaximumInputRoots))
+				}
+
+				if quotaConfiguration := nativeConfiguration.BuildDirectoryQuota; quotaConfiguration != nil {
+					if quotaConfiguration.MaximumSizeBytes <= 0 {
+						return status.Error(codes.InvalidArgument, "Maximum build directory size must be positive")
+					}
+					if err := quotaConfiguration.PollingInterval.CheckValid(); err != nil {
+						return util.StatusWrap(err, "Invalid build directory quota polling interval")
+					}
+					if quotaConfiguration.PollingInterval.AsDuration() <= 0 {
+						return status.Error(codes.InvalidArgument, "Build directory quota polling interval must be positive")
+					}
+					if projectQuotaConfiguration := quotaConfiguration.ProjectQuota; projectQuotaConfiguration != nil {
+						buildDirectoryQuotaManager, err = builder.NewProjectQuotaDirectoryQuotaManager(
+							projectQuotaConfiguration.BlockDevicePath,
+							projectQuotaConfiguration.FirstProjectId,
+							projectQuotaConfiguration.ProjectIdCount)
+						if err != nil {
+							return util.StatusWrap(err, "Failed to create project quota manager")
+						}
+					} else {
+						buildDirectoryQuotaManager = builder.TraversingDirectoryQuotaManager
+					}
+					buildDirectoryQuotaConfiguration = quotaConfiguration
+					buildDirectoryQuotaRootPath = nativeConfiguration.BuildDirectoryPath
+				}
+
+				if tmpfsConfiguration := nativeConfiguration.TmpfsBuildDirectories; tmpfsConfiguration != nil {
+					tmpfsBuildDirectoryManager, err = builder.NewLocalTmpfsManager()
+					if err != nil {
+						return util.StatusWrap(err, "Failed to create tmpfs manager")
+					}
+					tmpfsBuildDirectoriesConfiguration = tmpfsConfiguration
+					tmpfsBuildDirectoryCapacity = builder.NewTmpfsBuildDirectoryCapacity(tmpfsConfiguration.MaximumTotalSizeBytes)
+					tmpfsBuildDirectoryRootPath = nativeConfiguration.BuildDirectoryPath
+				}
 
 				// Using a native file system requires us to
 				// hold on to file descriptors while uploading
@@ -284,11 +816,71 @@ func main() {
 			if len(buildDirectoryConfiguration.Runners) == 0 {
 				return util.StatusWrap(err, "Cannot start worker without any runners")
 			}
-			for _, runnerConfiguration := range buildDirectoryConfiguration.Runners {
+			for buildDirectoryRunnerIndex, runnerConfiguration := range buildDirectoryConfiguration.Runners {
 				if runnerConfiguration.Concurrency < 1 {
 					return util.StatusWrap(err, "Runner concurrency must be positive")
 				}
-				concurrencyLength := len(strconv.FormatUint(runnerConfiguration.Concurrency-1, 10))
+				maximumConcurrency := runnerConfiguration.Concurrency
+				if runnerConfiguration.MaximumConcurrency > maximumConcurrency {
+					maximumConcurrency = runnerConfiguration.MaximumConcurrency
+				}
+				concurrencyLength := len(strconv.FormatUint(maximumConcurrency-1, 10))
+
+				// The platform properties and size class
+				// announced by all threads of the runner
+				// may be changed through the WorkerAdmin
+				// gRPC service, or by reloading the
+				// configuration. The same holds for the
+				// number of threads requesting work.
+				runnerIndex := len(advertisedPlatforms)
+				advertisedPlatform := builder.NewAdvertisedPlatform(runnerConfiguration.Platform, runnerConfiguration.SizeClass)
+				advertisedPlatforms = append(advertisedPlatforms, advertisedPlatform)
+				concurrencyLimit := builder.NewConcurrencyLimit(int(runnerConfiguration.Concurrency))
+
+				// Threads are launched for the size class
+				// of the runner, and for every size class
+				// partition sharing the execution slots of
+				// the runner.
+				runnerPartitions := []runnerPartition{{
+					sizeClass:          runnerConfiguration.SizeClass,
+					advertisedPlatform: advertisedPlatform,
+					concurrencyLimit:   concurrencyLimit,
+					threads:            maximumConcurrency,
+				}}
+				var sizeClassPartitionAdvertisedPlatforms []*builder.AdvertisedPlatform
+				for _, partitionConfiguration := range runnerConfiguration.SizeClassPartitions {
+					slotsPerAction := uint64(partitionConfiguration.SlotsPerAction)
+					if slotsPerAction < 1 {
+						return status.Error(codes.InvalidArgument, "Size class partition slots per action must be positive")
+					}
+					for _, partition := range runnerPartitions {
+						if partition.sizeClass == partitionConfiguration.SizeClass {
+							return status.Errorf(codes.InvalidArgument, "Runner has multiple partitions for size class %d", partitionConfiguration.SizeClass)
+						}
+					}
+					threads := maximumConcurrency / slotsPerAction
+					if threads < 1 {
+						threads = 1
+					}
+					partitionAdvertisedPlatform := builder.NewAdvertisedPlatform(runnerConfiguration.Platform, partitionConfiguration.SizeClass)
+					advertisedPlatforms = append(advertisedPlatforms, partitionAdvertisedPlatform)
+					sizeClassPartitionAdvertisedPlatforms = append(sizeClassPartitionAdvertisedPlatforms, partitionAdvertisedPlatform)
+					runnerPartitions = append(runnerPartitions, runnerPartition{
+						sizeClass:          partitionConfiguration.SizeClass,
+						advertisedPlatform: partitionAdvertisedPlatform,
+						concurrencyLimit:   concurrencyLimit.WithSlotsPerAction(int(slotsPerAction)),
+						threads:            threads,
+						workerID: map[string]string{
+							"size_class": strconv.FormatUint(uint64(partitionConfiguration.SizeClass), 10),
+						},
+					})
+				}
+				reloadableRunners = append(reloadableRunners, reloadableRunner{
+					advertisedPlatform:                    advertisedPlatform,
+					concurrencyLimit:                      concurrencyLimit,
+					maximumConcurrency:                    maximumConcurrency,
+					sizeClassPartitionAdvertisedPlatforms: sizeClassPartitionAdvertisedPlatforms,
+				})
 
 				// Obtain raw device numbers of character
 				// devices that need to be available within the
@@ -309,14 +901,140 @@ func main() {
 				if err != nil {
 					return util.StatusWrap(err, "Failed to create runner RPC client")
 				}
-				runnerClient := runner_pb.NewRunnerClient(runnerConnection)
+				runnerClient := executingActionRegistry.NewRunnerClient(
+					runner_pb.NewRunnerClient(runnerConnection))
 
-				for threadID := uint64(0); threadID < runnerConfiguration.Concurrency; threadID++ {
+				// Execute actions having small input roots
+				// in a separate build directory on local
+				// disk, using a runner of their own.
+				trivialActionsConfiguration := runnerConfiguration.TrivialActions
+				var trivialActionsBuildDirectory filesystem.DirectoryCloser
+				var trivialActionsRunnerClient runner_pb.RunnerClient
+				var trivialActionsFileFetcher cas.FileFetcher
+				var trivialActionsFileFetcherSemaphore *semaphore.Weighted
+				var trivialActionsIdleInvoker *cleaner.IdleInvoker
+				var trivialActionsNextParallelActionID atomic.Uint64
+				if trivialActionsConfiguration != nil {
+					trivialActionsBuildDirectory, err = filesystem.NewLocalDirectory(trivialActionsConfiguration.BuildDirectoryPath)
+					if err != nil {
+						return util.StatusWrapf(err, "Failed to open trivial actions build directory %v", trivialActionsConfiguration.BuildDirectoryPath)
+					}
+					trivialActionsIdleInvoker = cleaner.NewIdleInvoker(
+						cleaner.NewDirectoryCleaner(trivialActionsBuildDirectory, trivialActionsConfiguration.BuildDirectoryPath))
+					trivialActionsRunnerConnection, err := grpcClientFactory.NewClientFromConfiguration(trivialActionsConfiguration.Endpoint)
+					if err != nil {
+						return util.StatusWrap(err, "Failed to create trivial actions runner RPC client")
+					}
+					trivialActionsRunnerClient = executingActionRegistry.NewRunnerClient(
+						runner_pb.NewRunnerClient(trivialActionsRunnerConnection))
+					trivialActionsFileFetcher, trivialActionsFileFetcherSemaphore = fileFetcher, fileFetcherSemaphore
+					if trivialActionsFileFetcher == nil {
+						trivialActionsFileFetcher = cas.NewBlobAccessFileFetcher(globalContentAddressableStorage)
+						trivialActionsFileFetcherSemaphore = semaphore.NewWeighted(1)
+					}
+				}
+
+				outputPruner, err := builder.NewOutputPruner(runnerConfiguration.OutputPruningPatterns)
+				if err != nil {
+					return util.StatusWrap(err, "Invalid output pruning patterns")
+				}
+
+				// Resolver of container images, shared by all
+				// threads of the runner, so that every image is
+				// only converted once.
+				var containerImageResolver containerimage.Resolver
+				containerImagePlatformPropertyName := "container-image"
+				if containerImageConfiguration := runnerConfiguration.ContainerImage; containerImageConfiguration != nil {
+					roundTripper, err := bb_http.NewRoundTripperFromConfiguration(containerImageConfiguration.HttpClient)
+					if err != nil {
+						return util.StatusWrap(err, "Failed to create container registry HTTP client")
+					}
+					uploadBatchSize := blobstore.RecommendedFindMissingDigestsCount
+					if containerImageConfiguration.UploadBatchSize > 0 {
+						uploadBatchSize = int(containerImageConfiguration.UploadBatchSize)
+					}
+					uploadConcurrency := int64(1)
+					if containerImageConfiguration.UploadConcurrency > 0 {
+						uploadConcurrency = containerImageConfiguration.UploadConcurrency
+					}
+					containerImageResolver = containerimage.NewRegistryResolver(
+						containerimage.NewHTTPRegistry(
+							roundTripper,
+							containerImageConfiguration.InsecureRegistries),
+						globalContentAddressableStorage,
+						uploadBatchSize,
+						uploadConcurrency)
+					if name := containerImageConfiguration.PlatformPropertyName; name != "" {
+						containerImagePlatformPropertyName = name
+					}
+				}
+
+				var remoteAssetFetchClient remoteasset.FetchClient
+				if remoteAssetConfiguration := runnerConfiguration.RemoteAsset; remoteAssetConfiguration != nil {
+					remoteAssetConnection, err := grpcClientFactory.NewClientFromConfiguration(remoteAssetConfiguration.Client)
+					if err != nil {
+						return util.StatusWrap(err, "Failed to create Remote Asset gRPC client")
+					}
+					remoteAssetFetchClient = remoteasset.NewFetchClient(remoteAssetConnection)
+				}
+
+				var prefetchPathsDownloadConcurrency *semaphore.Weighted
+				if concurrency := runnerConfiguration.PrefetchPathsDownloadConcurrency; concurrency > 0 {
+					prefetchPathsDownloadConcurrency = semaphore.NewWeighted(concurrency)
+				}
+
+				// Compute the policy hash and load the signing
+				// key that are used to attest execution.
+				var attestationPolicyHash []byte
+				var attestationPrivateKey ed25519.PrivateKey
+				if attestationConfiguration := runnerConfiguration.ExecutionAttestation; attestationConfiguration != nil {
+					policy := proto.Clone(runnerConfiguration).(*bb_worker.RunnerConfiguration)
+					policy.ExecutionAttestation = nil
+					policyData, err := proto.MarshalOptions{Deterministic: true}.Marshal(policy)
+					if err != nil {
+						return util.StatusWrap(err, "Failed to marshal runner configuration")
+					}
+					policyHash := sha256.Sum256(policyData)
+					attestationPolicyHash = policyHash[:]
+
+					if signingPrivateKey := attestationConfiguration.SigningPrivateKey; signingPrivateKey != "" {
+						block, _ := pem.Decode([]byte(signingPrivateKey))
+						if block == nil {
+							return status.Error(codes.InvalidArgument, "Execution attestation signing private key does not contain a PEM block")
+						}
+						privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+						if err != nil {
+							return util.StatusWrap(err, "Failed to parse execution attestation signing private key")
+						}
+						ed25519PrivateKey, ok := privateKey.(ed25519.PrivateKey)
+						if !ok {
+							return status.Error(codes.InvalidArgument, "Execution attestation signing private key is not an Ed25519 key")
+						}
+						attestationPrivateKey = ed25519PrivateKey
+					}
+				}
+
+				var runnerThreads []runnerThread
+				for _, partition := range runnerPartitions {
+					for threadID := uint64(0); threadID < partition.threads; threadID++ {
+						runnerThreads = append(runnerThreads, runnerThread{
+							partition: partition,
+							id:        threadID,
+						})
+					}
+				}
+				for _, runnerThread := range runnerThreads {
+					partition, threadID := runnerThread.partition, runnerThread.id
 					// Per-worker separate writer of the Content
 					// Addressable Storage that batches writes after
-					// completing the build action.
+					// completing the build action. Reads against it
+					// are performed by the virtual file system.
+					contentAddressableStorage := globalContentAddressableStorage
+					if virtualBuildDirectory != nil {
+						contentAddressableStorage = virtualFileSystemContentAddressableStorage
+					}
 					contentAddressableStorageWriter, contentAddressableStorageFlusher := re_blobstore.NewBatchedStoreBlobAccess(
-						globalContentAddressableStorage,
+						re_blobstore.NewPhaseTimingBlobAccess(contentAddressableStorage),
 						digest.KeyWithoutInstance,
 						uploadBatchSize,
 						outputUploadConcurrencySemaphore)
@@ -341,64 +1059,352 @@ func main() {
 						buildDirectory = builder.NewVirtualBuildDirectory(
 							virtualBuildDirectory,
 							cas.NewSuspendingDirectoryFetcher(
-								directoryFetcher,
+								buildDirectoryDirectoryFetcher,
 								suspendableClock),
 							re_blobstore.NewSuspendingBlobAccess(
 								contentAddressableStorageWriter,
 								suspendableClock),
 							symlinkFactory,
 							characterDeviceFactory,
-							handleAllocator)
+							handleAllocator,
+							eagerUploadSemaphore,
+							passthroughCacheDirectoryPath,
+							onlyUploadModifiedOutputs,
+							reportOperationStatistics)
 					} else {
 						executionTimeoutClock = clock.SystemClock
 						buildDirectory = builder.NewNaiveBuildDirectory(
 							naiveBuildDirectory,
-							directoryFetcher,
+							buildDirectoryDirectoryFetcher,
 							fileFetcher,
-							contentAddressableStorageWriter)
+							fileFetcherSemaphore,
+							inputRootPopulationOptions,
+							contentAddressableStorageWriter,
+							warmInputRootPool)
+					}
+
+					// Clean the build directory every time
+					// when going from fully idle to
+					// executing one action.
+					buildDirectoryCreator := builder.NewCleanBuildDirectoryCreator(
+						builder.NewRootBuildDirectoryCreator(buildDirectory),
+						buildDirectoryIdleInvoker)
+
+					// Run actions on a tmpfs whose size
+					// depends on the size class of the
+					// runner, as long as memory permits.
+					if tmpfsBuildDirectoriesConfiguration != nil {
+						sizeBytes, ok := tmpfsBuildDirectoriesConfiguration.SizeBytesPerSizeClass[partition.sizeClass]
+						if !ok {
+							sizeBytes = tmpfsBuildDirectoriesConfiguration.SizeBytes
+						}
+						if sizeBytes > 0 {
+							buildDirectoryCreator = builder.NewTmpfsBuildDirectoryCreator(
+								buildDirectoryCreator,
+								tmpfsBuildDirectoryRootPath,
+								tmpfsBuildDirectoryManager,
+								sizeBytes,
+								tmpfsBuildDirectoryCapacity)
+						}
 					}
 
 					// Create a per-action subdirectory in
 					// the build directory named after the
 					// action digest, so that multiple
 					// actions may be run concurrently.
-					//
-					// Also clean the build directory every
-					// time when going from fully idle to
-					// executing one action.
-					buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(
-						builder.NewCleanBuildDirectoryCreator(
-							builder.NewRootBuildDirectoryCreator(buildDirectory),
-							buildDirectoryIdleInvoker),
-						&sharedBuildDirectoryNextParallelActionID)
+					// Use random names if actions need to
+					// be isolated from each other.
+					buildDirectoryCreator = builder.NewSharedBuildDirectoryCreator(
+						buildDirectoryCreator,
+						&sharedBuildDirectoryNextParallelActionID,
+						buildDirectoryNameGenerator)
 
-					workerID := map[string]string{}
-					if runnerConfiguration.Concurrency > 1 {
-						workerID["thread"] = fmt.Sprintf("%0*d", concurrencyLength, threadID)
+					// Limit the amount of disk space each
+					// action's build directory may consume.
+					if buildDirectoryQuotaManager != nil {
+						buildDirectoryCreator = builder.NewQuotaEnforcingBuildDirectoryCreator(
+							buildDirectoryCreator,
+							buildDirectoryQuotaRootPath,
+							buildDirectoryQuotaManager,
+							buildDirectoryQuotaConfiguration.MaximumSizeBytes,
+							clock.SystemClock,
+							buildDirectoryQuotaConfiguration.PollingInterval.AsDuration())
 					}
-					for k, v := range runnerConfiguration.WorkerId {
-						workerID[k] = v
+
+					// Threads of runners are distinguished
+					// by a thread number and labels
+					// provided by the configuration.
+					// Runners having the same
+					// configuration, but belonging to
+					// different build directories, need
+					// to have 'worker_id' set on the
+					// build directory.
+					threadWorkerID := map[string]string{}
+					if partition.threads > 1 {
+						threadWorkerID["thread"] = fmt.Sprintf("%0*d", concurrencyLength, threadID)
 					}
-					workerName, err := json.Marshal(workerID)
+					workerID, workerName, err := workerIDs.add(
+						buildDirectoryConfiguration.WorkerId,
+						threadWorkerID,
+						partition.workerID,
+						runnerConfiguration.WorkerId)
 					if err != nil {
-						return util.StatusWrap(err, "Failed to marshal worker ID")
+						return util.StatusWrapf(err, "Runner at index %d of build directory at index %d", buildDirectoryRunnerIndex, buildDirectoryIndex)
 					}
 
-					buildExecutor := builder.NewLocalBuildExecutor(
-						contentAddressableStorageWriter,
-						buildDirectoryCreator,
-						runnerClient,
-						executionTimeoutClock,
-						inputRootCharacterDevices,
-						int(configuration.MaximumMessageSizeBytes),
-						runnerConfiguration.EnvironmentVariables,
-						configuration.ForceUploadTreesAndDirectories)
+					// Apply locale and time zone settings on top of
+					// the environment variables that are configured.
+					environmentVariables := map[string]string{}
+					for name, value := range runnerConfiguration.EnvironmentVariables {
+						environmentVariables[name] = value
+					}
+					platformPropertyEnvironmentVariables := map[string]string{}
+					if localeConfiguration := runnerConfiguration.Locale; localeConfiguration != nil {
+						for _, localeVariable := range []struct {
+							name             string
+							value            string
+							platformProperty string
+						}{
+							{"LANG", localeConfiguration.Lang, localeConfiguration.LangPlatformProperty},
+							{"LC_ALL", localeConfiguration.LcAll, localeConfiguration.LcAllPlatformProperty},
+							{"TZ", localeConfiguration.Tz, localeConfiguration.TzPlatformProperty},
+						} {
+							if localeVariable.value != "" {
+								environmentVariables[localeVariable.name] = localeVariable.value
+							}
+							if localeVariable.platformProperty != "" {
+								platformPropertyEnvironmentVariables[localeVariable.platformProperty] = localeVariable.name
+							}
+						}
+					}
+
+					var vcsMetadataEnvironmentVariables *builder.VCSMetadataEnvironmentVariables
+					if vcsMetadataConfiguration := runnerConfiguration.VcsMetadata; vcsMetadataConfiguration != nil {
+						vcsMetadataEnvironmentVariables = &builder.VCSMetadataEnvironmentVariables{
+							CommitSHA: vcsMetadataConfiguration.CommitShaEnvironmentVariable,
+							Dirty:     vcsMetadataConfiguration.DirtyEnvironmentVariable,
+						}
+					}
+
+					var actionKeepalive *builder.ActionKeepalive
+					if actionKeepaliveConfiguration := runnerConfiguration.ActionKeepalive; actionKeepaliveConfiguration != nil {
+						if actionKeepaliveConfiguration.EnvironmentVariable == "" {
+							return status.Error(codes.InvalidArgument, "No action keepalive environment variable provided")
+						}
+						if err := actionKeepaliveConfiguration.MaximumExecutionTimeout.CheckValid(); err != nil {
+							return util.StatusWrap(err, "Invalid maximum execution timeout for action keepalive")
+						}
+						actionKeepalive = &builder.ActionKeepalive{
+							EnvironmentVariable:     actionKeepaliveConfiguration.EnvironmentVariable,
+							MaximumExecutionTimeout: actionKeepaliveConfiguration.MaximumExecutionTimeout.AsDuration(),
+						}
+					}
+
+					var secretEnvironmentVariables *builder.SecretEnvironmentVariables
+					if secretEnvironmentVariablesConfiguration := runnerConfiguration.SecretEnvironmentVariables; secretEnvironmentVariablesConfiguration != nil {
+						secretsProvider, err := secrets.NewProviderFromConfiguration(secretEnvironmentVariablesConfiguration.Provider)
+						if err != nil {
+							return util.StatusWrap(err, "Failed to create secrets provider")
+						}
+						secretEnvironmentVariables = &builder.SecretEnvironmentVariables{
+							Provider: secretsProvider,
+						}
+						for _, variable := range secretEnvironmentVariablesConfiguration.EnvironmentVariables {
+							if variable.Name == "" {
+								return status.Error(codes.InvalidArgument, "No secret environment variable name provided")
+							}
+							instanceNamePrefix, err := digest.NewInstanceName(variable.InstanceNamePrefix)
+							if err != nil {
+								return util.StatusWrapf(err, "Invalid instance name prefix %#v for secret environment variable %#v", variable.InstanceNamePrefix, variable.Name)
+							}
+							secretEnvironmentVariables.Variables = append(secretEnvironmentVariables.Variables, builder.SecretEnvironmentVariable{
+								Name:               variable.Name,
+								InstanceNamePrefix: instanceNamePrefix,
+								SecretName:         variable.SecretName,
+							})
+						}
+					}
+
+					var fakeTime *builder.FakeTime
+					if fakeTimeConfiguration := runnerConfiguration.FakeTime; fakeTimeConfiguration != nil {
+						if fakeTimeConfiguration.PreloadLibraryPath == "" {
+							return status.Error(codes.InvalidArgument, "No fake time preload library path provided")
+						}
+						fakeTime = &builder.FakeTime{
+							PreloadLibraryPath: fakeTimeConfiguration.PreloadLibraryPath,
+						}
+					}
+
+					var commandOutputTruncation *builder.CommandOutputTruncation
+					if commandOutputTruncationConfiguration := runnerConfiguration.CommandOutputTruncation; commandOutputTruncationConfiguration != nil {
+						if commandOutputTruncationConfiguration.MaximumSizeBytes <= 0 {
+							return status.Error(codes.InvalidArgument, "Maximum command output size must be positive")
+						}
+						commandOutputTruncation = &builder.CommandOutputTruncation{
+							MaximumSizeBytes: commandOutputTruncationConfiguration.MaximumSizeBytes,
+						}
+						if largeLogsConfiguration := commandOutputTruncationConfiguration.LargeLogsContentAddressableStorage; largeLogsConfiguration != nil {
+							info, err := blobstore_configuration.NewBlobAccessFromConfiguration(
+								dependenciesGroup,
+								largeLogsConfiguration,
+								blobstore_configuration.NewCASBlobAccessCreator(
+									grpcClientFactory,
+									int(configuration.MaximumMessageSizeBytes)))
+							if err != nil {
+								return util.StatusWrap(err, "Failed to create large logs Content Addressable Storage")
+							}
+							commandOutputTruncation.LargeLogsContentAddressableStorage = info.BlobAccess
+						}
+					}
+
+					var outputLimits *builder.OutputLimits
+					if outputLimitsConfiguration := runnerConfiguration.OutputLimits; outputLimitsConfiguration != nil {
+						outputLimits = &builder.OutputLimits{
+							MaximumSizeBytes: outputLimitsConfiguration.MaximumSizeBytes,
+							MaximumFileCount: outputLimitsConfiguration.MaximumFileCount,
+						}
+					}
+
+					var actionResultVerification *builder.ActionResultVerification
+					if actionResultVerificationConfiguration := runnerConfiguration.ActionResultVerification; actionResultVerificationConfiguration != nil {
+						materializationCheckRatio := actionResultVerificationConfiguration.MaterializationCheckRatio
+						if materializationCheckRatio < 0 || materializationCheckRatio > 1 {
+							return status.Error(codes.InvalidArgument, "Action result materialization check ratio must be in range [0, 1]")
+						}
+						actionResultVerification = &builder.ActionResultVerification{
+							MaximumUploadRetries:      int(actionResultVerificationConfiguration.MaximumUploadRetries),
+							StorageFlusher:            contentAddressableStorageFlusher,
+							MaterializationCheckRatio: materializationCheckRatio,
+							RandomNumberGenerator:     random.FastThreadSafeGenerator,
+						}
+					}
+
+					var nestedExecutionProxy *builder.NestedExecutionProxy
+					if nestedExecutionConfiguration := runnerConfiguration.NestedExecution; nestedExecutionConfiguration != nil {
+						if nestedExecutionConfiguration.EnvironmentVariable == "" {
+							return status.Error(codes.InvalidArgument, "No nested execution environment variable provided")
+						}
+						if nestedExecutionConfiguration.SocketDirectory == "" {
+							return status.Error(codes.InvalidArgument, "No nested execution socket directory provided")
+						}
+						nestedExecutionConnection, err := grpcClientFactory.NewClientFromConfiguration(nestedExecutionConfiguration.Client)
+						if err != nil {
+							return util.StatusWrap(err, "Failed to create a new gRPC client for nested execution")
+						}
+						nestedExecutionProxy = &builder.NestedExecutionProxy{
+							EnvironmentVariable: nestedExecutionConfiguration.EnvironmentVariable,
+							SocketDirectory:     nestedExecutionConfiguration.SocketDirectory,
+							UserID:              uint32(os.Getuid()),
+							GroupID:             uint32(os.Getgid()),
+							ClientConnection:    nestedExecutionConnection,
+						}
+						if runCommandsAs := nestedExecutionConfiguration.RunCommandsAs; runCommandsAs != nil {
+							nestedExecutionProxy.UserID = runCommandsAs.UserId
+							nestedExecutionProxy.GroupID = runCommandsAs.GroupId
+						}
+					}
+
+					var outputPathValidation *builder.OutputPathValidation
+					if outputPathValidationConfiguration := runnerConfiguration.OutputPathValidation; outputPathValidationConfiguration != nil {
+						outputPathValidation = &builder.OutputPathValidation{
+							RejectCaseInsensitiveCollisions: outputPathValidationConfiguration.RejectCaseInsensitiveCollisions,
+							RejectWindowsIncompatibleNames:  outputPathValidationConfiguration.RejectWindowsIncompatibleNames,
+							MaximumPathLength:               int(outputPathValidationConfiguration.MaximumPathLength),
+						}
+					}
+
+					newLocalBuildExecutor := func(buildDirectoryCreator builder.BuildDirectoryCreator, runnerClient runner_pb.RunnerClient, executionTimeoutClock clock.Clock) builder.BuildExecutor {
+						return builder.NewLocalBuildExecutor(
+							contentAddressableStorageWriter,
+							buildDirectoryCreator,
+							runnerClient,
+							executionTimeoutClock,
+							inputRootCharacterDevices,
+							int(configuration.MaximumMessageSizeBytes),
+							environmentVariables,
+							platformPropertyEnvironmentVariables,
+							vcsMetadataEnvironmentVariables,
+							failedActionPauser,
+							configuration.ForceUploadTreesAndDirectories,
+							outputPruner,
+							actionKeepalive,
+							secretEnvironmentVariables,
+							fakeTime,
+							commandOutputTruncation,
+							outputLimits,
+							actionResultVerification,
+							nestedExecutionProxy,
+							outputPathValidation)
+					}
+					buildExecutor := newLocalBuildExecutor(buildDirectoryCreator, runnerClient, executionTimeoutClock)
+
+					if trivialActionsConfiguration != nil {
+						buildExecutor = builder.NewTrivialActionRoutingBuildExecutor(
+							buildExecutor,
+							newLocalBuildExecutor(
+								builder.NewSharedBuildDirectoryCreator(
+									builder.NewCleanBuildDirectoryCreator(
+										builder.NewRootBuildDirectoryCreator(
+											builder.NewNaiveBuildDirectory(
+												trivialActionsBuildDirectory,
+												buildDirectoryDirectoryFetcher,
+												trivialActionsFileFetcher,
+												trivialActionsFileFetcherSemaphore,
+												/* inputRootPopulationOptions = */ nil,
+												contentAddressableStorageWriter,
+												/* warmInputRootPool = */ nil)),
+										trivialActionsIdleInvoker),
+									&trivialActionsNextParallelActionID,
+									buildDirectoryNameGenerator),
+								trivialActionsRunnerClient,
+								clock.SystemClock),
+							buildDirectoryDirectoryFetcher,
+							int(trivialActionsConfiguration.MaximumInputRootNodes),
+							trivialActionsConfiguration.MaximumInputRootSizeBytes)
+					}
+
+					if runnerConfiguration.ReportReadInputRootFiles {
+						buildExecutor = builder.NewReadFilesReportingBuildExecutor(
+							buildExecutor,
+							globalContentAddressableStorage)
+					}
+
+					if inputRootMinimization := runnerConfiguration.InputRootMinimization; inputRootMinimization != nil {
+						buildExecutor = builder.NewInputRootMinimizingBuildExecutor(
+							buildExecutor,
+							globalContentAddressableStorage,
+							buildDirectoryDirectoryFetcher,
+							inputRootMinimization.MaximumExecutions)
+					}
+
+					if prefetchPathsDownloadConcurrency != nil {
+						buildExecutor = builder.NewPrefetchPathsBuildExecutor(
+							buildExecutor,
+							globalContentAddressableStorage,
+							buildDirectoryDirectoryFetcher,
+							prefetchPathsDownloadConcurrency)
+					}
+
+					if accessProfileCache != nil {
+						// This decorator needs to be placed
+						// below PrefetchingBuildExecutor, as
+						// the latter does not forward file
+						// system activity to the monitor it
+						// is provided.
+						buildExecutor = builder.NewAccessProfilePrefetchingBuildExecutor(
+							buildExecutor,
+							globalContentAddressableStorage,
+							buildDirectoryDirectoryFetcher,
+							prefetchingDownloadConcurrency,
+							accessProfileCache,
+							int(prefetchingConfiguration.AccessProfileCache.MaximumPathsPerProfile))
+					}
 
 					if prefetchingConfiguration != nil {
 						buildExecutor = builder.NewPrefetchingBuildExecutor(
 							buildExecutor,
 							globalContentAddressableStorage,
-							directoryFetcher,
+							buildDirectoryDirectoryFetcher,
 							prefetchingDownloadConcurrency,
 							fileSystemAccessCache,
 							int(configuration.MaximumMessageSizeBytes),
@@ -406,14 +1412,122 @@ func main() {
 							int(prefetchingConfiguration.BloomFilterMaximumSizeBytes))
 					}
 
+					if chunkSizeBytes := runnerConfiguration.FilePoolEncryptionChunkSizeBytes; chunkSizeBytes > 0 {
+						keyProvider := re_filesystem.RandomEncryptionKeyProvider
+						if masterKeyConfiguration := runnerConfiguration.FilePoolEncryptionMasterKey; masterKeyConfiguration != nil {
+							if err := masterKeyConfiguration.RefreshInterval.CheckValid(); err != nil {
+								return util.StatusWrap(err, "Invalid file pool encryption master key refresh interval")
+							}
+							keyProvider = re_filesystem.NewMasterKeyDerivingEncryptionKeyProvider(
+								re_filesystem.NewFileMasterKeySource(
+									masterKeyConfiguration.Path,
+									clock.SystemClock,
+									masterKeyConfiguration.RefreshInterval.AsDuration()))
+						}
+						buildExecutor = builder.NewFilePoolEncryptingBuildExecutor(buildExecutor, int(chunkSizeBytes), keyProvider)
+					}
+
+					if executablePolicy := runnerConfiguration.ExecutablePolicy; executablePolicy != nil {
+						buildExecutor, err = builder.NewExecutableValidatingBuildExecutor(
+							buildExecutor,
+							globalContentAddressableStorage,
+							buildDirectoryDirectoryFetcher,
+							int(configuration.MaximumMessageSizeBytes),
+							&builder.ExecutablePolicy{
+								AllowedPaths:   executablePolicy.AllowedPaths,
+								DeniedPaths:    executablePolicy.DeniedPaths,
+								AllowedDigests: executablePolicy.AllowedDigests,
+								DeniedDigests:  executablePolicy.DeniedDigests,
+							})
+						if err != nil {
+							return util.StatusWrap(err, "Invalid executable policy")
+						}
+					}
+
+					if containerImageResolver != nil {
+						buildExecutor = builder.NewContainerImageBuildExecutor(
+							buildExecutor,
+							globalContentAddressableStorage,
+							buildDirectoryDirectoryFetcher,
+							containerImageResolver,
+							containerImagePlatformPropertyName)
+					}
+
+					if remoteAssetFetchClient != nil {
+						buildExecutor = builder.NewRemoteAssetFetchingBuildExecutor(
+							buildExecutor,
+							globalContentAddressableStorage,
+							buildDirectoryDirectoryFetcher,
+							remoteAssetFetchClient)
+					}
+
+					// Apply the symlink policy to the input
+					// root provided by the client, as opposed
+					// to the one merged with a container image.
+					if symlinkPolicyConfiguration := runnerConfiguration.SymlinkPolicy; symlinkPolicyConfiguration != nil {
+						symlinkPolicyActions := map[bb_worker.SymlinkPolicyConfiguration_Action]builder.SymlinkPolicyAction{
+							bb_worker.SymlinkPolicyConfiguration_ALLOW:               builder.SymlinkPolicyActionAllow,
+							bb_worker.SymlinkPolicyConfiguration_REJECT:              builder.SymlinkPolicyActionReject,
+							bb_worker.SymlinkPolicyConfiguration_REWRITE_TO_RELATIVE: builder.SymlinkPolicyActionRewriteToRelative,
+						}
+						inputRootAction, ok := symlinkPolicyActions[symlinkPolicyConfiguration.InputRootAction]
+						if !ok {
+							return status.Error(codes.InvalidArgument, "Invalid symlink policy input root action")
+						}
+						outputAction, ok := symlinkPolicyActions[symlinkPolicyConfiguration.OutputAction]
+						if !ok || outputAction == builder.SymlinkPolicyActionRewriteToRelative {
+							return status.Error(codes.InvalidArgument, "Invalid symlink policy output action")
+						}
+						buildExecutor = builder.NewSymlinkPolicyEnforcingBuildExecutor(
+							buildExecutor,
+							globalContentAddressableStorage,
+							buildDirectoryDirectoryFetcher,
+							int(configuration.MaximumMessageSizeBytes),
+							&builder.SymlinkPolicy{
+								InputRootAction: inputRootAction,
+								OutputAction:    outputAction,
+								AllowedPrefixes: symlinkPolicyConfiguration.AllowedPrefixes,
+							})
+					}
+
+					if filePoolBudget != nil {
+						buildExecutor = builder.NewFilePoolBudgetBuildExecutor(
+							buildExecutor,
+							filePoolBudget,
+							int(runnerConfiguration.FilePoolBudgetPriority))
+					}
+
+					// Reject actions whose Command message
+					// cannot be executed as specified,
+					// before constructing the input root.
+					if commandValidationConfiguration := runnerConfiguration.CommandValidation; commandValidationConfiguration != nil {
+						buildExecutor = builder.NewCommandValidatingBuildExecutor(
+							buildExecutor,
+							globalContentAddressableStorage,
+							int(configuration.MaximumMessageSizeBytes),
+							builder.CommandLimits{
+								MaximumArgumentSizeBytes:       commandValidationConfiguration.MaximumArgumentSizeBytes,
+								MaximumTotalArgumentsSizeBytes: commandValidationConfiguration.MaximumTotalArgumentsSizeBytes,
+							})
+					}
+
+					// Let actions that set the
+					// "resources:cores" platform property
+					// hold multiple execution slots.
+					buildExecutor = builder.NewConcurrencyLimitingBuildExecutor(
+						buildExecutor,
+						partition.concurrencyLimit)
+
 					buildExecutor = builder.NewMetricsBuildExecutor(
 						builder.NewFilePoolStatsBuildExecutor(
 							builder.NewTimestampedBuildExecutor(
-								builder.NewStorageFlushingBuildExecutor(
-									buildExecutor,
-									contentAddressableStorageFlusher),
+								builder.NewPhaseTimingBuildExecutor(
+									builder.NewStorageFlushingBuildExecutor(
+										buildExecutor,
+										contentAddressableStorageFlusher),
+									clock.SystemClock),
 								clock.SystemClock,
-								string(workerName))))
+								workerName)))
 
 					if len(runnerConfiguration.CostsPerSecond) > 0 {
 						buildExecutor = builder.NewCostComputingBuildExecutor(buildExecutor, runnerConfiguration.CostsPerSecond)
@@ -426,11 +1540,98 @@ func main() {
 							maximumConsecutiveFailures)
 					}
 
+					if attestationConfiguration := runnerConfiguration.ExecutionAttestation; attestationConfiguration != nil {
+						buildExecutor = builder.NewAttestingBuildExecutor(
+							buildExecutor,
+							workerID,
+							attestationConfiguration.IsolationLevel,
+							attestationPolicyHash,
+							attestationPrivateKey)
+					}
+
+					if faultInjectionConfiguration := runnerConfiguration.FaultInjection; faultInjectionConfiguration != nil {
+						if err := faultInjectionConfiguration.MaximumDelay.CheckValid(); err != nil {
+							return util.StatusWrap(err, "Invalid fault injection maximum delay")
+						}
+						if err := faultInjectionConfiguration.MaximumKillDelay.CheckValid(); err != nil {
+							return util.StatusWrap(err, "Invalid fault injection maximum kill delay")
+						}
+						var generator random.SingleThreadedGenerator
+						if seed := faultInjectionConfiguration.Seed; seed != 0 {
+							generator = rand.New(rand.NewSource(seed))
+						} else {
+							generator = random.NewFastSingleThreadedGenerator()
+						}
+						buildExecutor = builder.NewFaultInjectingBuildExecutor(
+							buildExecutor,
+							clock.SystemClock,
+							generator,
+							builder.FaultInjectionProbabilities{
+								Delay:            faultInjectionConfiguration.DelayProbability,
+								MaximumDelay:     faultInjectionConfiguration.MaximumDelay.AsDuration(),
+								TransientError:   faultInjectionConfiguration.TransientErrorProbability,
+								Kill:             faultInjectionConfiguration.KillProbability,
+								MaximumKillDelay: faultInjectionConfiguration.MaximumKillDelay.AsDuration(),
+								OutputCorruption: faultInjectionConfiguration.TestOnlyOutputCorruptionProbability,
+							})
+					}
+
+					actionCacheWritePolicy := builder.SuccessfulActionCacheWritePolicy
+					if writePolicyConfiguration := runnerConfiguration.ActionCacheWritePolicy; writePolicyConfiguration != nil {
+						if writePolicyConfiguration.IncludeFailures {
+							actionCacheWritePolicy = builder.CompletedActionCacheWritePolicy
+						}
+						if writePolicyConfiguration.RequireDeterminismVerification {
+							actionCacheWritePolicy = builder.NewDeterminismVerifyingActionCacheWritePolicy(actionCacheWritePolicy)
+						}
+						if len(writePolicyConfiguration.ExcludedActionMnemonics) > 0 || len(writePolicyConfiguration.ExcludedPlatformProperties) > 0 {
+							actionCacheWritePolicy = builder.NewExcludingActionCacheWritePolicy(
+								actionCacheWritePolicy,
+								writePolicyConfiguration.ExcludedActionMnemonics,
+								writePolicyConfiguration.ExcludedPlatformProperties)
+						}
+					}
 					buildExecutor = builder.NewCachingBuildExecutor(
 						buildExecutor,
 						globalContentAddressableStorage,
 						actionCache,
-						browserURL)
+						browserURL,
+						actionCacheWritePolicy)
+
+					// Let storage distinguish final build
+					// outputs from intermediate objects.
+					if retentionHintsConfiguration := runnerConfiguration.RetentionHints; retentionHintsConfiguration != nil {
+						if retentionHintsConfiguration.PlatformPropertyName == "" {
+							return status.Error(codes.InvalidArgument, "No retention hints platform property name provided")
+						}
+						if retentionHintsConfiguration.MetadataHeaderName == "" {
+							return status.Error(codes.InvalidArgument, "No retention hints metadata header name provided")
+						}
+						buildExecutor = builder.NewRetentionHintingBuildExecutor(
+							buildExecutor,
+							&builder.RetentionHintPolicy{
+								PlatformPropertyName: retentionHintsConfiguration.PlatformPropertyName,
+								MetadataHeaderName:   retentionHintsConfiguration.MetadataHeaderName,
+								Hints:                retentionHintsConfiguration.Hints,
+								DefaultHint:          retentionHintsConfiguration.DefaultHint,
+							})
+					}
+
+					if recentResultCacheConfiguration := runnerConfiguration.RecentResultCache; recentResultCacheConfiguration != nil {
+						if err := recentResultCacheConfiguration.MaximumAge.CheckValid(); err != nil {
+							return util.StatusWrap(err, "Invalid recent result cache maximum age")
+						}
+						evictionSet, err := eviction.NewSetFromConfiguration[digest.Digest](recentResultCacheConfiguration.CacheReplacementPolicy)
+						if err != nil {
+							return util.StatusWrap(err, "Failed to create eviction set for recent result cache")
+						}
+						buildExecutor = builder.NewRecentResultCachingBuildExecutor(
+							buildExecutor,
+							clock.SystemClock,
+							recentResultCacheConfiguration.MaximumAge.AsDuration(),
+							int(recentResultCacheConfiguration.MaximumCacheSize),
+							eviction.NewMetricsSet(evictionSet, "RecentResultCachingBuildExecutor"))
+					}
 
 					for _, remoteCompletedActionLogger := range remoteCompletedActionLoggers {
 						buildExecutor = builder.NewCompletedActionLoggingBuildExecutor(
@@ -440,31 +1641,112 @@ func main() {
 							remoteCompletedActionLogger.instanceNamePatcher)
 					}
 
+					if buildEventClient != nil {
+						buildExecutor = builder.NewBuildEventPublishingBuildExecutor(
+							buildExecutor,
+							buildEventClient,
+							clock.SystemClock,
+							util.DefaultErrorLogger,
+							workerID,
+							buildEventInstanceNamePatcher,
+							buildEventPublishingConfiguration.ProjectId,
+							buildEventAcknowledgementTimeout)
+					}
+
 					buildExecutor = builder.NewTracingBuildExecutor(
 						builder.NewLoggingBuildExecutor(
 							buildExecutor,
 							browserURL),
 						tracerProvider)
 
+					// Permit listing the action through the
+					// WorkerAdmin gRPC service.
+					buildExecutor = executingActionRegistry.NewBuildExecutor(buildExecutor, runnerIndex)
+
 					instanceNamePrefix, err := digest.NewInstanceName(runnerConfiguration.InstanceNamePrefix)
 					if err != nil {
 						return util.StatusWrapf(err, "Invalid instance name prefix %#v", runnerConfiguration.InstanceNamePrefix)
 					}
 
+					// When a worker-wide budget is used, per-runner
+					// limits are optional.
+					if filePoolBudget == nil || runnerConfiguration.MaximumFilePoolFileCount > 0 || runnerConfiguration.MaximumFilePoolSizeBytes > 0 {
+						buildExecutor = builder.NewQuotaEnforcingFilePoolBuildExecutor(
+							buildExecutor,
+							runnerConfiguration.MaximumFilePoolFileCount,
+							runnerConfiguration.MaximumFilePoolSizeBytes)
+					}
+
+					// Grant additional resources to actions
+					// that the scheduler retries on a larger
+					// size class.
+					buildExecutor = builder.NewResourceLimitsScalingBuildExecutor(buildExecutor)
+
 					buildClient := builder.NewBuildClient(
 						schedulerClient,
 						buildExecutor,
-						re_filesystem.NewQuotaEnforcingFilePool(
-							filePool,
-							runnerConfiguration.MaximumFilePoolFileCount,
-							runnerConfiguration.MaximumFilePoolSizeBytes),
+						buildDirectoryFilePool,
 						clock.SystemClock,
 						workerID,
 						instanceNamePrefix,
-						runnerConfiguration.Platform,
-						runnerConfiguration.SizeClass)
-					builder.LaunchWorkerThread(siblingsGroup, buildClient, string(workerName))
+						instanceNameMapping,
+						partition.advertisedPlatform,
+						inputRootPrefetcher,
+						idlePrefetcher,
+						gracefulShutdownTimeout,
+						workerResourcesReporter,
+						terminationTimeReporter,
+						partition.concurrencyLimit,
+						memoryAdmission)
+					builder.LaunchWorkerThread(siblingsGroup, buildClient, workerName, partition.concurrencyLimit, int(threadID))
+				}
+			}
+		}
+
+		if cacheAdminConfiguration := configuration.CacheAdmin; cacheAdminConfiguration != nil {
+			if err := bb_grpc.NewServersFromConfigurationAndServe(
+				cacheAdminConfiguration.GrpcServers,
+				func(s grpc.ServiceRegistrar) {
+					cacheadmin.RegisterCacheAdminServer(s, cas.NewCacheAdminServer(cachingFileFetchers))
+				},
+				siblingsGroup,
+			); err != nil {
+				return util.StatusWrap(err, "gRPC server failure")
+			}
+		}
+
+		if workerAdminConfiguration := configuration.WorkerAdmin; workerAdminConfiguration != nil {
+			if err := bb_grpc.NewServersFromConfigurationAndServe(
+				workerAdminConfiguration.GrpcServers,
+				func(s grpc.ServiceRegistrar) {
+					workeradmin.RegisterWorkerAdminServer(s, builder.NewWorkerAdminServer(advertisedPlatforms, executingActionRegistry))
+				},
+				siblingsGroup,
+			); err != nil {
+				return util.StatusWrap(err, "gRPC server failure")
+			}
+		}
+
+		if reloadingConfiguration := configuration.ConfigurationReloading; reloadingConfiguration != nil {
+			var fileCheckInterval time.Duration
+			if d := reloadingConfiguration.FileCheckInterval; d != nil {
+				if err := d.CheckValid(); err != nil {
+					return util.StatusWrap(err, "Invalid configuration file check interval")
 				}
+				fileCheckInterval = d.AsDuration()
+			}
+			reloader := &configurationReloader{
+				path:                      os.Args[1],
+				currentConfiguration:      &configuration,
+				runners:                   reloadableRunners,
+				storageGroup:              dependenciesGroup,
+				newCASAndAC:               newCASAndAC,
+				contentAddressableStorage: swappableContentAddressableStorage,
+				actionCache:               swappableActionCache,
+				closeStorageBackends:      closeStorageBackends,
+			}
+			if err := reloader.start(siblingsGroup, fileCheckInterval); err != nil {
+				return util.StatusWrap(err, "Failed to start configuration reloading")
 			}
 		}
 
@@ -472,3 +1754,58 @@ func main() {
 		return nil
 	})
 }
+
+// runnerPartition contains the state shared by all threads of a runner
+// that announce the same size class to the scheduler.
+type runnerPartition struct {
+	sizeClass          uint32
+	advertisedPlatform *builder.AdvertisedPlatform
+	concurrencyLimit   *builder.ConcurrencyLimit
+	threads            uint64
+
+	// Additional worker ID labels, preventing threads of
+	// different partitions from having the same worker ID.
+	workerID map[string]string
+}
+
+// runnerThread is a single worker thread of a runner partition.
+type runnerThread struct {
+	partition runnerPartition
+	id        uint64
+}
+
+// newDirectoryFetcher creates a DirectoryFetcher that provides cached
+// read access to Directory objects stored in the Content Addressable
+// Storage. If configured, Directory objects are also persistently
+// cached in a backend that is local to the worker, so that they don't
+// need to be reloaded after restarts.
+func newDirectoryFetcher(group program.Group, configuration *bb_worker.ApplicationConfiguration, persistentDirectoryCacheConfiguration *blobstore_pb.BlobAccessConfiguration, contentAddressableStorage blobstore.BlobAccess, grpcClientFactory bb_grpc.ClientFactory) (cas.DirectoryFetcher, error) {
+	directoryContentAddressableStorage := contentAddressableStorage
+	if persistentDirectoryCacheConfiguration != nil {
+		info, err := blobstore_configuration.NewBlobAccessFromConfiguration(
+			group,
+			persistentDirectoryCacheConfiguration,
+			blobstore_configuration.NewCASBlobAccessCreator(
+				grpcClientFactory,
+				int(configuration.MaximumMessageSizeBytes)))
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to create persistent directory cache")
+		}
+		directoryContentAddressableStorage = readcaching.NewReadCachingBlobAccess(
+			contentAddressableStorage,
+			info.BlobAccess,
+			replication.NewLocalBlobReplicator(contentAddressableStorage, info.BlobAccess))
+	}
+
+	// This process does not read Tree objects.
+	directoryFetcher, err := cas.NewCachingDirectoryFetcherFromConfiguration(
+		configuration.DirectoryCache,
+		cas.NewBlobAccessDirectoryFetcher(
+			directoryContentAddressableStorage,
+			/* maximumDirectorySizeBytes = */ int(configuration.MaximumMessageSizeBytes),
+			/* maximumTreeSizeBytes = */ 0))
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to create caching directory fetcher")
+	}
+	return cas.NewPhaseTimingDirectoryFetcher(directoryFetcher), nil
+}
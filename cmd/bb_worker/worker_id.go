@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// workerIDSet keeps track of the worker IDs of all threads launched by
+// this process. The scheduler identifies workers by their worker ID,
+// meaning that threads having the same worker ID would continuously
+// overwrite each other's state. Such configurations are rejected.
+type workerIDSet map[string]struct{}
+
+// add the worker ID of a thread to the set, returning its JSON
+// representation that is used as the name of the worker thread.
+// Labels are merged in order, meaning that labels provided later take
+// precedence over ones provided earlier.
+func (s workerIDSet) add(labels ...map[string]string) (map[string]string, string, error) {
+	workerID := map[string]string{}
+	for _, l := range labels {
+		for k, v := range l {
+			workerID[k] = v
+		}
+	}
+	workerName, err := json.Marshal(workerID)
+	if err != nil {
+		return nil, "", util.StatusWrap(err, "Failed to marshal worker ID")
+	}
+	if _, ok := s[string(workerName)]; ok {
+		return nil, "", status.Errorf(codes.InvalidArgument, "Worker ID %s is used by multiple threads. Set \"worker_id\" on build directories or runners to make their worker IDs unique", workerName)
+	}
+	s[string(workerName)] = struct{}{}
+	return workerID, string(workerName), nil
+}
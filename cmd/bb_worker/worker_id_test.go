@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWorkerIDSet(t *testing.T) {
+	workerIDs := workerIDSet{}
+
+	t.Run("Merged", func(t *testing.T) {
+		// Labels provided later should take precedence over
+		// labels provided earlier.
+		workerID, workerName, err := workerIDs.add(
+			map[string]string{"disk": "nvme0", "hostname": "default"},
+			map[string]string{"thread": "0"},
+			nil,
+			map[string]string{"hostname": "worker123"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"disk":     "nvme0",
+			"hostname": "worker123",
+			"thread":   "0",
+		}, workerID)
+		require.Equal(t, `{"disk":"nvme0","hostname":"worker123","thread":"0"}`, workerName)
+	})
+
+	t.Run("DistinctBuildDirectory", func(t *testing.T) {
+		// A runner having the same configuration, but belonging
+		// to a build directory on another disk, should be
+		// permitted if the build directory sets a worker ID.
+		_, workerName, err := workerIDs.add(
+			map[string]string{"disk": "nvme1"},
+			map[string]string{"thread": "0"},
+			nil,
+			map[string]string{"hostname": "worker123"})
+		require.NoError(t, err)
+		require.Equal(t, `{"disk":"nvme1","hostname":"worker123","thread":"0"}`, workerName)
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		// Threads that end up having the same worker ID would
+		// be indistinguishable by the scheduler.
+		_, _, err := workerIDs.add(
+			nil,
+			map[string]string{"thread": "0"},
+			nil,
+			map[string]string{"disk": "nvme0", "hostname": "worker123"})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Worker ID {\"disk\":\"nvme0\",\"hostname\":\"worker123\",\"thread\":\"0\"} is used by multiple threads. Set \"worker_id\" on build directories or runners to make their worker IDs unique"), err)
+	})
+}
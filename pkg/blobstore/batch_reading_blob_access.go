@@ -0,0 +1,210 @@
+package blobstore
+
+import (
+	"context"
+	"sync"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// pendingBatchRead is a read of a single blob that has been enqueued,
+// but not yet completed. Multiple calls to Get() for the same blob
+// share the same pendingBatchRead.
+type pendingBatchRead struct {
+	ctx    context.Context
+	digest digest.Digest
+	done   chan struct{}
+
+	// Fields that are set prior to closing the 'done' channel.
+	data []byte
+	err  error
+}
+
+// batchReadQueue contains all pending reads for blobs that share the
+// same instance name and digest function, as only those can be
+// combined into a single BatchReadBlobs() call.
+type batchReadQueue struct {
+	pendingReads    map[digest.Digest]*pendingBatchRead
+	pendingOrder    []*pendingBatchRead
+	dispatcherAlive bool
+}
+
+type batchReadingBlobAccess struct {
+	blobstore.BlobAccess
+	contentAddressableStorage remoteexecution.ContentAddressableStorageClient
+	maximumBlobSizeBytes      int64
+	maximumBatchSizeBytes     int64
+	batchSemaphore            *semaphore.Weighted
+
+	lock   sync.Mutex
+	queues map[digest.Function]*batchReadQueue
+}
+
+// NewBatchReadingBlobAccess is an adapter for BlobAccess that causes
+// Get() operations for small blobs to be coalesced into calls to
+// ContentAddressableStorage.BatchReadBlobs(). Larger blobs are still
+// read through the backend, which typically uses the ByteStream API.
+// Blobs that BatchReadBlobs() reports as absent are read through the
+// backend as well, as the backend may provide fallbacks to other
+// storage.
+//
+// Coalescing happens without introducing any artificial delays. Reads
+// are accumulated while all batchSemaphore slots are occupied by
+// BatchReadBlobs() calls that are in flight. This makes this adapter
+// useful when many small files are downloaded concurrently, such as
+// when populating input roots containing large numbers of headers.
+func NewBatchReadingBlobAccess(blobAccess blobstore.BlobAccess, contentAddressableStorage remoteexecution.ContentAddressableStorageClient, maximumBlobSizeBytes, maximumBatchSizeBytes int64, batchSemaphore *semaphore.Weighted) blobstore.BlobAccess {
+	return &batchReadingBlobAccess{
+		BlobAccess:                blobAccess,
+		contentAddressableStorage: contentAddressableStorage,
+		maximumBlobSizeBytes:      maximumBlobSizeBytes,
+		maximumBatchSizeBytes:     maximumBatchSizeBytes,
+		batchSemaphore:            batchSemaphore,
+		queues:                    map[digest.Function]*batchReadQueue{},
+	}
+}
+
+func (ba *batchReadingBlobAccess) Get(ctx context.Context, blobDigest digest.Digest) buffer.Buffer {
+	if blobDigest.GetSizeBytes() > ba.maximumBlobSizeBytes {
+		return ba.BlobAccess.Get(ctx, blobDigest)
+	}
+
+	// Enqueue the read, or attach to an existing read for the same
+	// blob. Launch a dispatcher if none is running.
+	digestFunction := blobDigest.GetDigestFunction()
+	ba.lock.Lock()
+	q, ok := ba.queues[digestFunction]
+	if !ok {
+		q = &batchReadQueue{
+			pendingReads: map[digest.Digest]*pendingBatchRead{},
+		}
+		ba.queues[digestFunction] = q
+	}
+	pr, ok := q.pendingReads[blobDigest]
+	if !ok {
+		pr = &pendingBatchRead{
+			ctx:    ctx,
+			digest: blobDigest,
+			done:   make(chan struct{}),
+		}
+		q.pendingReads[blobDigest] = pr
+		q.pendingOrder = append(q.pendingOrder, pr)
+		if !q.dispatcherAlive {
+			q.dispatcherAlive = true
+			go ba.dispatch(digestFunction, q)
+		}
+	}
+	ba.lock.Unlock()
+
+	select {
+	case <-pr.done:
+		if status.Code(pr.err) == codes.NotFound {
+			// The backend may be capable of obtaining
+			// blobs from places that the server receiving
+			// BatchReadBlobs() calls cannot access.
+			return ba.BlobAccess.Get(ctx, blobDigest)
+		} else if pr.err != nil {
+			return buffer.NewBufferFromError(pr.err)
+		}
+		return buffer.NewCASBufferFromByteSlice(blobDigest, pr.data, buffer.BackendProvided(buffer.Irreparable(blobDigest)))
+	case <-ctx.Done():
+		return buffer.NewBufferFromError(util.StatusFromContext(ctx))
+	}
+}
+
+// dispatch repeatedly takes pending reads from a queue and issues
+// BatchReadBlobs() calls for them, until the queue is empty.
+func (ba *batchReadingBlobAccess) dispatch(digestFunction digest.Function, q *batchReadQueue) {
+	for {
+		// Acquiring the semaphore cannot fail, as the context
+		// is never canceled.
+		ba.batchSemaphore.Acquire(context.Background(), 1)
+
+		ba.lock.Lock()
+		var batch []*pendingBatchRead
+		batchSizeBytes := int64(0)
+		for len(q.pendingOrder) > 0 {
+			pr := q.pendingOrder[0]
+			sizeBytes := pr.digest.GetSizeBytes()
+			if len(batch) > 0 && batchSizeBytes+sizeBytes > ba.maximumBatchSizeBytes {
+				break
+			}
+			batch = append(batch, pr)
+			batchSizeBytes += sizeBytes
+			q.pendingOrder[0] = nil
+			q.pendingOrder = q.pendingOrder[1:]
+			delete(q.pendingReads, pr.digest)
+		}
+		if len(batch) == 0 {
+			q.dispatcherAlive = false
+			delete(ba.queues, digestFunction)
+			ba.lock.Unlock()
+			ba.batchSemaphore.Release(1)
+			return
+		}
+		ba.lock.Unlock()
+
+		go func() {
+			ba.readBatch(digestFunction, batch)
+			ba.batchSemaphore.Release(1)
+		}()
+	}
+}
+
+func (ba *batchReadingBlobAccess) readBatch(digestFunction digest.Function, batch []*pendingBatchRead) {
+	defer func() {
+		for _, pr := range batch {
+			close(pr.done)
+		}
+	}()
+
+	// Use the context of the first read in the batch to issue the
+	// RPC, so that credentials are forwarded. Don't let the batch
+	// fail if only this read gets canceled.
+	ctx := context.WithoutCancel(batch[0].ctx)
+	request := &remoteexecution.BatchReadBlobsRequest{
+		InstanceName:   digestFunction.GetInstanceName().String(),
+		Digests:        make([]*remoteexecution.Digest, 0, len(batch)),
+		DigestFunction: digestFunction.GetEnumValue(),
+	}
+	readsByDigest := make(map[digest.Digest]*pendingBatchRead, len(batch))
+	for _, pr := range batch {
+		request.Digests = append(request.Digests, pr.digest.GetProto())
+		readsByDigest[pr.digest] = pr
+	}
+	response, err := ba.contentAddressableStorage.BatchReadBlobs(ctx, request)
+	if err != nil {
+		for _, pr := range batch {
+			pr.err = util.StatusWrap(err, "Failed to read batch of blobs")
+		}
+		return
+	}
+
+	for _, blobResponse := range response.Responses {
+		blobDigest, err := digestFunction.NewDigestFromProto(blobResponse.Digest)
+		if err != nil {
+			continue
+		}
+		pr, ok := readsByDigest[blobDigest]
+		if !ok {
+			continue
+		}
+		if err := status.ErrorProto(blobResponse.Status); err != nil {
+			pr.err = err
+		} else {
+			pr.data = blobResponse.Data
+		}
+		delete(readsByDigest, blobDigest)
+	}
+	for _, pr := range readsByDigest {
+		pr.err = status.Error(codes.Internal, "Server did not return blob in response to batch read")
+	}
+}
@@ -0,0 +1,142 @@
+package blobstore_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/sync/semaphore"
+	status_pb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBatchReadingBlobAccess(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBlobAccess := mock.NewMockBlobAccess(ctrl)
+	contentAddressableStorage := mock.NewMockContentAddressableStorageClient(ctrl)
+	blobAccess := blobstore.NewBatchReadingBlobAccess(
+		baseBlobAccess,
+		contentAddressableStorage,
+		/* maximumBlobSizeBytes = */ 100,
+		/* maximumBatchSizeBytes = */ 1000,
+		semaphore.NewWeighted(1))
+
+	digestHello := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+
+	t.Run("LargeBlob", func(t *testing.T) {
+		// Blobs exceeding the maximum size should be read
+		// through the backend.
+		largeDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "3e25960a79dbc69b674cd4ec67a72c62", 1000)
+		baseBlobAccess.EXPECT().Get(ctx, largeDigest).Return(buffer.NewBufferFromError(status.Error(codes.Internal, "Server on fire")))
+
+		_, err := blobAccess.Get(ctx, largeDigest).ToByteSlice(1000)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Server on fire"), err)
+	})
+
+	t.Run("RPCFailure", func(t *testing.T) {
+		contentAddressableStorage.EXPECT().BatchReadBlobs(gomock.Any(), testutil.EqProto(t, &remoteexecution.BatchReadBlobsRequest{
+			InstanceName:   "hello",
+			Digests:        []*remoteexecution.Digest{{Hash: "8b1a9953c4611296a827abf8c47804d7", SizeBytes: 5}},
+			DigestFunction: remoteexecution.DigestFunction_MD5,
+		})).Return(nil, status.Error(codes.Unavailable, "Server not reachable"))
+
+		_, err := blobAccess.Get(ctx, digestHello).ToByteSlice(100)
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Failed to read batch of blobs: Server not reachable"), err)
+	})
+
+	t.Run("BlobError", func(t *testing.T) {
+		// Errors for individual blobs should be propagated
+		// with their original code.
+		contentAddressableStorage.EXPECT().BatchReadBlobs(gomock.Any(), gomock.Any()).Return(&remoteexecution.BatchReadBlobsResponse{
+			Responses: []*remoteexecution.BatchReadBlobsResponse_Response{{
+				Digest: &remoteexecution.Digest{Hash: "8b1a9953c4611296a827abf8c47804d7", SizeBytes: 5},
+				Status: &status_pb.Status{Code: int32(codes.PermissionDenied), Message: "Access denied"},
+			}},
+		}, nil)
+
+		_, err := blobAccess.Get(ctx, digestHello).ToByteSlice(100)
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Access denied"), err)
+	})
+
+	t.Run("BlobNotFound", func(t *testing.T) {
+		// Blobs that are absent should be read through the
+		// backend, as it may be capable of falling back to
+		// other storage.
+		contentAddressableStorage.EXPECT().BatchReadBlobs(gomock.Any(), gomock.Any()).Return(&remoteexecution.BatchReadBlobsResponse{
+			Responses: []*remoteexecution.BatchReadBlobsResponse_Response{{
+				Digest: &remoteexecution.Digest{Hash: "8b1a9953c4611296a827abf8c47804d7", SizeBytes: 5},
+				Status: &status_pb.Status{Code: int32(codes.NotFound), Message: "Blob not found"},
+			}},
+		}, nil)
+		baseBlobAccess.EXPECT().Get(ctx, digestHello).Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello")))
+
+		data, err := blobAccess.Get(ctx, digestHello).ToByteSlice(100)
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hello"), data)
+	})
+
+	t.Run("BlobMissingFromResponse", func(t *testing.T) {
+		contentAddressableStorage.EXPECT().BatchReadBlobs(gomock.Any(), gomock.Any()).Return(&remoteexecution.BatchReadBlobsResponse{}, nil)
+
+		_, err := blobAccess.Get(ctx, digestHello).ToByteSlice(100)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Server did not return blob in response to batch read"), err)
+	})
+
+	t.Run("ConcurrentReads", func(t *testing.T) {
+		// Concurrent reads may be coalesced into batches in
+		// arbitrary ways, but each batch should only contain
+		// distinct blobs, and all reads should return the
+		// right data.
+		digestGoodbye := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "6fc422233a40a75a1f028e11c3cd1140", 7)
+		contents := map[string][]byte{
+			"8b1a9953c4611296a827abf8c47804d7": []byte("Hello"),
+			"6fc422233a40a75a1f028e11c3cd1140": []byte("Goodbye"),
+		}
+		contentAddressableStorage.EXPECT().BatchReadBlobs(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, in *remoteexecution.BatchReadBlobsRequest, opts ...grpc.CallOption) (*remoteexecution.BatchReadBlobsResponse, error) {
+				require.Equal(t, "hello", in.InstanceName)
+				require.Equal(t, remoteexecution.DigestFunction_MD5, in.DigestFunction)
+				require.LessOrEqual(t, len(in.Digests), 2)
+				response := &remoteexecution.BatchReadBlobsResponse{}
+				seen := map[string]bool{}
+				for _, blobDigest := range in.Digests {
+					require.False(t, seen[blobDigest.Hash])
+					seen[blobDigest.Hash] = true
+					response.Responses = append(response.Responses, &remoteexecution.BatchReadBlobsResponse_Response{
+						Digest: blobDigest,
+						Data:   contents[blobDigest.Hash],
+					})
+				}
+				return response, nil
+			}).MinTimes(1).MaxTimes(20)
+
+		errors := make(chan error, 20)
+		for i := 0; i < 20; i++ {
+			blobDigest, expectedData := digestHello, []byte("Hello")
+			if i%2 == 1 {
+				blobDigest, expectedData = digestGoodbye, []byte("Goodbye")
+			}
+			go func() {
+				data, err := blobAccess.Get(ctx, blobDigest).ToByteSlice(100)
+				if err == nil && string(data) != string(expectedData) {
+					err = status.Errorf(codes.Internal, "Got data %#v", string(data))
+				}
+				errors <- err
+			}()
+		}
+		for i := 0; i < 20; i++ {
+			require.NoError(t, <-errors)
+		}
+	})
+}
@@ -113,26 +113,9 @@ func (ss *blobAccessMutableProtoStore[T, TProto]) Get(ctx context.Context, reduc
 	}
 
 	// Extract a couple of handles from previous actions that we can
-	// write to storage at this point. It is safe to access
-	// handle.message here, as handle.useCount is guaranteed to be
-	// zero for handles that are queued for writing.
-	for i := 0; i < writesPerRead && len(ss.handlesToWrite) > 0; i++ {
-		newLength := len(ss.handlesToWrite) - 1
-		handle := ss.handlesToWrite[newLength]
-		ss.handlesToWrite[newLength] = nil
-		ss.handlesToWrite = ss.handlesToWrite[:newLength]
-		if handle.handlesToWriteIndex != newLength {
-			panic("Handle has bad write index")
-		}
-		handle.handlesToWriteIndex = -1
-		handlesToWrite = append(handlesToWrite, handleToWrite[T, TProto]{
-			handle:         handle,
-			message:        proto.Clone(TProto(&handle.message)),
-			writingVersion: handle.currentVersion,
-		})
-	}
+	// write to storage at this point.
+	handlesToWrite = ss.dequeueHandlesToWriteLocked(handlesToWrite, writesPerRead)
 	ss.lock.Unlock()
-	blobAccessMutableProtoHandlesDequeued.Add(float64(len(handlesToWrite)))
 
 	// If no handle exists, create a new handle containing the
 	// existing message for the action.
@@ -159,17 +142,7 @@ func (ss *blobAccessMutableProtoStore[T, TProto]) Get(ctx context.Context, reduc
 	for _, handleToWriteIter := range handlesToWrite {
 		handleToWrite := handleToWriteIter
 		group.Go(func() error {
-			if err := ss.initialSizeClassCache.Put(ctxWithCancel, handleToWrite.handle.digest, buffer.NewProtoBufferFromProto(handleToWrite.message, buffer.UserProvided)); err != nil {
-				ss.lock.Lock()
-				handleToWrite.handle.removeOrQueueForWriteLocked()
-				ss.lock.Unlock()
-				return util.StatusWrapf(err, "Failed to write mutable Protobuf message with digest %#v", handleToWrite.handle.digest.String())
-			}
-			ss.lock.Lock()
-			handleToWrite.handle.writtenVersion = handleToWrite.writingVersion
-			handleToWrite.handle.removeOrQueueForWriteLocked()
-			ss.lock.Unlock()
-			return nil
+			return ss.writeHandle(ctxWithCancel, handleToWrite)
 		})
 	}
 
@@ -200,6 +173,66 @@ func (ss *blobAccessMutableProtoStore[T, TProto]) Get(ctx context.Context, reduc
 	return handleToReturn, nil
 }
 
+// dequeueHandlesToWriteLocked removes up to a given number of handles
+// from the write queue, appending them to a list of handles that the
+// caller should write to storage. It is safe to access handle.message
+// here, as handle.useCount is guaranteed to be zero for handles that
+// are queued for writing.
+func (ss *blobAccessMutableProtoStore[T, TProto]) dequeueHandlesToWriteLocked(handlesToWrite []handleToWrite[T, TProto], count int) []handleToWrite[T, TProto] {
+	for i := 0; i < count && len(ss.handlesToWrite) > 0; i++ {
+		newLength := len(ss.handlesToWrite) - 1
+		handle := ss.handlesToWrite[newLength]
+		ss.handlesToWrite[newLength] = nil
+		ss.handlesToWrite = ss.handlesToWrite[:newLength]
+		if handle.handlesToWriteIndex != newLength {
+			panic("Handle has bad write index")
+		}
+		handle.handlesToWriteIndex = -1
+		handlesToWrite = append(handlesToWrite, handleToWrite[T, TProto]{
+			handle:         handle,
+			message:        proto.Clone(TProto(&handle.message)),
+			writingVersion: handle.currentVersion,
+		})
+		blobAccessMutableProtoHandlesDequeued.Inc()
+	}
+	return handlesToWrite
+}
+
+// writeHandle writes a message that was previously dequeued by
+// dequeueHandlesToWriteLocked() to storage. Upon failure, the handle is
+// placed back in the write queue, so that writing it is retried later.
+func (ss *blobAccessMutableProtoStore[T, TProto]) writeHandle(ctx context.Context, handleToWrite handleToWrite[T, TProto]) error {
+	if err := ss.initialSizeClassCache.Put(ctx, handleToWrite.handle.digest, buffer.NewProtoBufferFromProto(handleToWrite.message, buffer.UserProvided)); err != nil {
+		ss.lock.Lock()
+		handleToWrite.handle.removeOrQueueForWriteLocked()
+		ss.lock.Unlock()
+		return util.StatusWrapf(err, "Failed to write mutable Protobuf message with digest %#v", handleToWrite.handle.digest.String())
+	}
+	ss.lock.Lock()
+	handleToWrite.handle.writtenVersion = handleToWrite.writingVersion
+	handleToWrite.handle.removeOrQueueForWriteLocked()
+	ss.lock.Unlock()
+	return nil
+}
+
+func (ss *blobAccessMutableProtoStore[T, TProto]) Flush(ctx context.Context) error {
+	// Extract all handles that are queued for writing. Handles
+	// that are still in use are not written, as they will be
+	// queued for writing once released.
+	ss.lock.Lock()
+	handlesToWrite := ss.dequeueHandlesToWriteLocked(nil, len(ss.handlesToWrite))
+	ss.lock.Unlock()
+
+	group, ctxWithCancel := errgroup.WithContext(ctx)
+	for _, handleToWriteIter := range handlesToWrite {
+		handleToWrite := handleToWriteIter
+		group.Go(func() error {
+			return ss.writeHandle(ctxWithCancel, handleToWrite)
+		})
+	}
+	return group.Wait()
+}
+
 type blobAccessMutableProtoHandle[T any, TProto interface {
 	*T
 	proto.Message
@@ -171,4 +171,49 @@ func TestBlobAccessMutableProtoStore(t *testing.T) {
 
 		handle5.Release(false)
 	})
+
+	t.Run("Flush", func(t *testing.T) {
+		// Flushing a store that has no pending writes should be
+		// a no-op.
+		require.NoError(t, store.Flush(ctx))
+
+		// Create a handle and modify it.
+		blobAccess.EXPECT().Get(gomock.Any(), digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 123)).
+			Return(buffer.NewBufferFromError(status.Error(codes.NotFound, "Blob does not exist")))
+
+		handle, err := store.Get(ctx, digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 123))
+		require.NoError(t, err)
+		handle.GetMutableProto().LastSeenFailure = &timestamppb.Timestamp{Seconds: 1620819007}
+
+		// As long as the handle is in use, it should not be
+		// written by Flush().
+		require.NoError(t, store.Flush(ctx))
+		handle.Release(true)
+
+		// Failures writing the message should be propagated.
+		// The handle should remain queued for writing.
+		blobAccess.EXPECT().Put(gomock.Any(), digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 123), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+				b.Discard()
+				return status.Error(codes.Internal, "Storage failure")
+			})
+
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to write mutable Protobuf message with digest \"3-8b1a9953c4611296a827abf8c47804d7-123-hello\": Storage failure"), store.Flush(ctx))
+
+		// Retrying should cause the message to be written.
+		blobAccess.EXPECT().Put(gomock.Any(), digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 123), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+				m, err := b.ToProto(&iscc.PreviousExecutionStats{}, 10000)
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, &iscc.PreviousExecutionStats{
+					LastSeenFailure: &timestamppb.Timestamp{Seconds: 1620819007},
+				}, m)
+				return nil
+			})
+
+		require.NoError(t, store.Flush(ctx))
+
+		// Once written, subsequent flushes should be no-ops.
+		require.NoError(t, store.Flush(ctx))
+	})
 }
@@ -18,8 +18,13 @@ import (
 // all handles obtained from a single store must be called while holding
 // a global lock. The Protobuf message embedded in the handle gets
 // invalidated after locks are dropped.
+//
+// Implementations may defer writing messages to storage after handles
+// are released. Flush() can be called to force all pending changes to
+// be written, which is needed to prevent data loss upon shutdown.
 type MutableProtoStore[T proto.Message] interface {
 	Get(ctx context.Context, reducedActionDigest digest.Digest) (MutableProtoHandle[T], error)
+	Flush(ctx context.Context) error
 }
 
 // MutableProtoHandle is a handle that is returned by MutableProtoStore.
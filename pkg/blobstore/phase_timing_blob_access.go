@@ -0,0 +1,40 @@
+package blobstore
+
+import (
+	"context"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+type phaseTimingBlobAccess struct {
+	blobstore.BlobAccess
+}
+
+// NewPhaseTimingBlobAccess is a decorator for BlobAccess that simply
+// forwards all methods. The duration of calls to FindMissing() and
+// Put() is accumulated into the clock.PhaseTimer attached to the
+// Context, if any, as time spent determining which outputs are missing
+// and uploading them, respectively.
+//
+// This decorator is used by workers to report how much time is spent
+// in the individual phases of uploading outputs of build actions.
+func NewPhaseTimingBlobAccess(base blobstore.BlobAccess) blobstore.BlobAccess {
+	return &phaseTimingBlobAccess{
+		BlobAccess: base,
+	}
+}
+
+func (ba *phaseTimingBlobAccess) Put(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+	defer clock.StartPhase(ctx, clock.PhaseUpload).Finish()
+
+	return ba.BlobAccess.Put(ctx, digest, b)
+}
+
+func (ba *phaseTimingBlobAccess) FindMissing(ctx context.Context, digests digest.Set) (digest.Set, error) {
+	defer clock.StartPhase(ctx, clock.PhaseFindMissingBlobs).Finish()
+
+	return ba.BlobAccess.FindMissing(ctx, digests)
+}
@@ -0,0 +1,92 @@
+package blobstore
+
+import (
+	"context"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/sync"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/slicing"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+)
+
+type priorityLimitingBlobAccess struct {
+	base          blobstore.BlobAccess
+	semaphore     *sync.PrioritySemaphore
+	readPriority  int
+	writePriority int
+}
+
+// NewPriorityLimitingBlobAccess is a decorator for BlobAccess that
+// limits the number of concurrent operations by acquiring a
+// PrioritySemaphore. Reads hold on to the semaphore until the returned
+// buffer is consumed or discarded. Reads and writes may be assigned
+// different priorities.
+//
+// By letting multiple instances of this decorator share the same
+// semaphore, bb_worker can ensure that reads performed on behalf of
+// the virtual file system are not starved by large numbers of outputs
+// being uploaded.
+func NewPriorityLimitingBlobAccess(base blobstore.BlobAccess, semaphore *sync.PrioritySemaphore, readPriority, writePriority int) blobstore.BlobAccess {
+	return &priorityLimitingBlobAccess{
+		base:          base,
+		semaphore:     semaphore,
+		readPriority:  readPriority,
+		writePriority: writePriority,
+	}
+}
+
+func (ba *priorityLimitingBlobAccess) Get(ctx context.Context, digest digest.Digest) buffer.Buffer {
+	if err := ba.semaphore.Acquire(ctx, ba.readPriority); err != nil {
+		return buffer.NewBufferFromError(util.StatusFromContext(ctx))
+	}
+	return buffer.WithErrorHandler(
+		ba.base.Get(ctx, digest),
+		&releasingErrorHandler{semaphore: ba.semaphore})
+}
+
+func (ba *priorityLimitingBlobAccess) GetFromComposite(ctx context.Context, parentDigest, childDigest digest.Digest, slicer slicing.BlobSlicer) buffer.Buffer {
+	if err := ba.semaphore.Acquire(ctx, ba.readPriority); err != nil {
+		return buffer.NewBufferFromError(util.StatusFromContext(ctx))
+	}
+	return buffer.WithErrorHandler(
+		ba.base.GetFromComposite(ctx, parentDigest, childDigest, slicer),
+		&releasingErrorHandler{semaphore: ba.semaphore})
+}
+
+func (ba *priorityLimitingBlobAccess) Put(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+	if err := ba.semaphore.Acquire(ctx, ba.writePriority); err != nil {
+		b.Discard()
+		return util.StatusFromContext(ctx)
+	}
+	defer ba.semaphore.Release()
+
+	return ba.base.Put(ctx, digest, b)
+}
+
+func (ba *priorityLimitingBlobAccess) FindMissing(ctx context.Context, digests digest.Set) (digest.Set, error) {
+	if err := ba.semaphore.Acquire(ctx, ba.writePriority); err != nil {
+		return digest.EmptySet, util.StatusFromContext(ctx)
+	}
+	defer ba.semaphore.Release()
+
+	return ba.base.FindMissing(ctx, digests)
+}
+
+func (ba *priorityLimitingBlobAccess) GetCapabilities(ctx context.Context, instanceName digest.InstanceName) (*remoteexecution.ServerCapabilities, error) {
+	return ba.base.GetCapabilities(ctx, instanceName)
+}
+
+type releasingErrorHandler struct {
+	semaphore *sync.PrioritySemaphore
+}
+
+func (eh *releasingErrorHandler) OnError(err error) (buffer.Buffer, error) {
+	return nil, err
+}
+
+func (eh *releasingErrorHandler) Done() {
+	eh.semaphore.Release()
+}
@@ -0,0 +1,84 @@
+package blobstore_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/blobstore"
+	"github.com/buildbarn/bb-remote-execution/pkg/sync"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPriorityLimitingBlobAccess(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBlobAccess := mock.NewMockBlobAccess(ctrl)
+	semaphore := sync.NewPrioritySemaphore(1)
+	blobAccess := blobstore.NewPriorityLimitingBlobAccess(baseBlobAccess, semaphore, 1, 0)
+
+	exampleDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	t.Run("GetHoldsSemaphore", func(t *testing.T) {
+		// As long as the buffer returned by Get() has not been
+		// consumed, other operations should not be permitted to
+		// run.
+		r := mock.NewMockReadCloser(ctrl)
+		baseBlobAccess.EXPECT().Get(ctx, exampleDigest).
+			Return(buffer.NewCASBufferFromReader(exampleDigest, r, buffer.UserProvided))
+
+		b := blobAccess.Get(ctx, exampleDigest)
+
+		_, err := blobAccess.FindMissing(canceledCtx, digest.EmptySet)
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "context canceled"), err)
+
+		r.EXPECT().Read(gomock.Any()).DoAndReturn(func(p []byte) (int, error) {
+			return copy(p, "Hello"), io.EOF
+		})
+		r.EXPECT().Close()
+
+		data, err := b.ToByteSlice(1000)
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hello"), data)
+
+		// Consuming the buffer should have released the
+		// semaphore.
+		baseBlobAccess.EXPECT().FindMissing(canceledCtx, digest.EmptySet).Return(digest.EmptySet, nil)
+
+		missing, err := blobAccess.FindMissing(canceledCtx, digest.EmptySet)
+		require.NoError(t, err)
+		require.Equal(t, digest.EmptySet, missing)
+	})
+
+	t.Run("PutReleasesSemaphore", func(t *testing.T) {
+		baseBlobAccess.EXPECT().Put(ctx, exampleDigest, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+				b.Discard()
+				return status.Error(codes.Unavailable, "Server offline")
+			})
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Unavailable, "Server offline"),
+			blobAccess.Put(ctx, exampleDigest, buffer.NewValidatedBufferFromByteSlice([]byte("Hello"))))
+
+		baseBlobAccess.EXPECT().Put(canceledCtx, exampleDigest, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+				b.Discard()
+				return nil
+			})
+
+		require.NoError(t, blobAccess.Put(canceledCtx, exampleDigest, buffer.NewValidatedBufferFromByteSlice([]byte("Hello"))))
+	})
+}
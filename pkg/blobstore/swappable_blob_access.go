@@ -0,0 +1,115 @@
+package blobstore
+
+import (
+	"context"
+	"sync"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/slicing"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+// swappableBackend is a backend of SwappableBlobAccess, together with
+// the number of calls against it that are still in progress.
+type swappableBackend struct {
+	base     blobstore.BlobAccess
+	inFlight sync.WaitGroup
+}
+
+// SwappableBlobAccess is a decorator for BlobAccess that forwards all
+// methods to a backend that may be replaced at runtime. This permits
+// storage backends to be reconfigured without discarding any caches
+// that are layered on top of them.
+type SwappableBlobAccess struct {
+	lock    sync.RWMutex
+	backend *swappableBackend
+}
+
+// NewSwappableBlobAccess creates a SwappableBlobAccess that initially
+// forwards all methods to the provided backend.
+func NewSwappableBlobAccess(base blobstore.BlobAccess) *SwappableBlobAccess {
+	return &SwappableBlobAccess{
+		backend: &swappableBackend{
+			base: base,
+		},
+	}
+}
+
+// Swap the backend to which methods are forwarded. Calls that are in
+// progress continue to use the previous backend. The function that is
+// returned blocks until all of these calls have completed, including
+// the consumption of buffers returned by Get() and
+// GetFromComposite(). After that, the previous backend may be closed
+// safely.
+func (ba *SwappableBlobAccess) Swap(base blobstore.BlobAccess) func() {
+	ba.lock.Lock()
+	previousBackend := ba.backend
+	ba.backend = &swappableBackend{
+		base: base,
+	}
+	ba.lock.Unlock()
+	return previousBackend.inFlight.Wait
+}
+
+// acquireBackend returns the current backend, incrementing the number
+// of calls against it that are in progress. The caller must call
+// inFlight.Done() on the backend when done.
+func (ba *SwappableBlobAccess) acquireBackend() *swappableBackend {
+	ba.lock.RLock()
+	defer ba.lock.RUnlock()
+	backend := ba.backend
+	backend.inFlight.Add(1)
+	return backend
+}
+
+// Get a blob from the current backend.
+func (ba *SwappableBlobAccess) Get(ctx context.Context, digest digest.Digest) buffer.Buffer {
+	backend := ba.acquireBackend()
+	return buffer.WithErrorHandler(backend.base.Get(ctx, digest), swappableErrorHandler{backend: backend})
+}
+
+// GetFromComposite obtains a blob that is part of a composite blob from
+// the current backend.
+func (ba *SwappableBlobAccess) GetFromComposite(ctx context.Context, parentDigest, childDigest digest.Digest, slicer slicing.BlobSlicer) buffer.Buffer {
+	backend := ba.acquireBackend()
+	return buffer.WithErrorHandler(backend.base.GetFromComposite(ctx, parentDigest, childDigest, slicer), swappableErrorHandler{backend: backend})
+}
+
+// Put a blob in the current backend.
+func (ba *SwappableBlobAccess) Put(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+	backend := ba.acquireBackend()
+	defer backend.inFlight.Done()
+	return backend.base.Put(ctx, digest, b)
+}
+
+// FindMissing determines which blobs are absent from the current
+// backend.
+func (ba *SwappableBlobAccess) FindMissing(ctx context.Context, digests digest.Set) (digest.Set, error) {
+	backend := ba.acquireBackend()
+	defer backend.inFlight.Done()
+	return backend.base.FindMissing(ctx, digests)
+}
+
+// GetCapabilities returns the capabilities of the current backend.
+func (ba *SwappableBlobAccess) GetCapabilities(ctx context.Context, instanceName digest.InstanceName) (*remoteexecution.ServerCapabilities, error) {
+	backend := ba.acquireBackend()
+	defer backend.inFlight.Done()
+	return backend.base.GetCapabilities(ctx, instanceName)
+}
+
+// swappableErrorHandler is used by SwappableBlobAccess to determine
+// when buffers returned by a backend have been consumed, so that the
+// backend is no longer considered to be in use.
+type swappableErrorHandler struct {
+	backend *swappableBackend
+}
+
+func (eh swappableErrorHandler) OnError(err error) (buffer.Buffer, error) {
+	return nil, err
+}
+
+func (eh swappableErrorHandler) Done() {
+	eh.backend.inFlight.Done()
+}
@@ -0,0 +1,70 @@
+package blobstore_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwappableBlobAccess(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	oldBlobAccess := mock.NewMockBlobAccess(ctrl)
+	blobAccess := blobstore.NewSwappableBlobAccess(oldBlobAccess)
+
+	exampleDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+
+	// Calls should initially be forwarded to the backend that was
+	// provided upon construction.
+	oldBlobAccess.EXPECT().Get(ctx, exampleDigest).
+		Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello")))
+	data, err := blobAccess.Get(ctx, exampleDigest).ToByteSlice(100)
+	require.NoError(t, err)
+	require.Equal(t, []byte("Hello"), data)
+
+	// Obtain a buffer from the backend, but don't consume it yet.
+	oldBlobAccess.EXPECT().Get(ctx, exampleDigest).
+		Return(buffer.NewCASBufferFromReader(exampleDigest, io.NopCloser(bytes.NewBufferString("Hello")), buffer.UserProvided))
+	b := blobAccess.Get(ctx, exampleDigest)
+
+	// After swapping, calls should be forwarded to the new backend.
+	newBlobAccess := mock.NewMockBlobAccess(ctrl)
+	waitForPreviousBackend := blobAccess.Swap(newBlobAccess)
+	drained := make(chan struct{})
+	go func() {
+		waitForPreviousBackend()
+		close(drained)
+	}()
+
+	newBlobAccess.EXPECT().FindMissing(ctx, exampleDigest.ToSingletonSet()).
+		Return(exampleDigest.ToSingletonSet(), nil)
+	missing, err := blobAccess.FindMissing(ctx, exampleDigest.ToSingletonSet())
+	require.NoError(t, err)
+	require.Equal(t, exampleDigest.ToSingletonSet(), missing)
+
+	// The previous backend should only be considered drained
+	// after the buffer obtained from it has been consumed.
+	select {
+	case <-drained:
+		t.Fatal("Previous backend was drained while a buffer obtained from it was still in use")
+	case <-time.After(10 * time.Millisecond):
+	}
+	data, err = b.ToByteSlice(100)
+	require.NoError(t, err)
+	require.Equal(t, []byte("Hello"), data)
+	<-drained
+
+	// Swapping again should not need to wait, as no calls against
+	// the new backend are in progress.
+	blobAccess.Swap(oldBlobAccess)()
+}
@@ -0,0 +1,264 @@
+package blobstore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/slicing"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	tieredBlobAccessPrometheusMetrics sync.Once
+
+	tieredBlobAccessReadsServed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "blobstore",
+			Name:      "tiered_blob_access_reads_served_total",
+			Help:      "Number of reads that were served by a tier of TieredBlobAccess",
+		},
+		[]string{"name", "tier"})
+	tieredBlobAccessFailovers = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "blobstore",
+			Name:      "tiered_blob_access_failovers_total",
+			Help:      "Number of times TieredBlobAccess failed over to the next tier, due to a tier being unavailable",
+		},
+		[]string{"name", "tier"})
+)
+
+// TieredBlobAccessTier is a single tier of storage that is provided to
+// NewTieredBlobAccess().
+type TieredBlobAccessTier struct {
+	Name       string
+	BlobAccess blobstore.BlobAccess
+}
+
+type tieredBlobAccessTier struct {
+	blobAccess  blobstore.BlobAccess
+	readsServed prometheus.Counter
+	failovers   prometheus.Counter
+
+	lock             sync.Mutex
+	unavailableUntil time.Time
+}
+
+type tieredBlobAccess struct {
+	tiers                  []*tieredBlobAccessTier
+	clock                  clock.Clock
+	unavailabilityDuration time.Duration
+}
+
+// NewTieredBlobAccess creates a BlobAccess that is backed by an ordered
+// list of Content Addressable Storage backends, such as a cluster that
+// is local to the worker, followed by a central cluster.
+//
+// Reads are attempted against the tiers in order, falling through to
+// the next tier if a blob is absent or a tier is unavailable. Blobs
+// that are read from a tier after being reported absent by an earlier
+// tier are copied into the latter, so that subsequent reads are served
+// from there.
+//
+// Writes and calls to FindMissing() are sent to the first tier that is
+// available. If a tier returns UNAVAILABLE, it is skipped by all
+// operations for the provided duration. This permits workers to
+// continue to operate during outages of individual tiers.
+func NewTieredBlobAccess(name string, tiers []TieredBlobAccessTier, clock clock.Clock, unavailabilityDuration time.Duration) blobstore.BlobAccess {
+	tieredBlobAccessPrometheusMetrics.Do(func() {
+		prometheus.MustRegister(tieredBlobAccessReadsServed)
+		prometheus.MustRegister(tieredBlobAccessFailovers)
+	})
+
+	ba := &tieredBlobAccess{
+		clock:                  clock,
+		unavailabilityDuration: unavailabilityDuration,
+	}
+	for _, tier := range tiers {
+		ba.tiers = append(ba.tiers, &tieredBlobAccessTier{
+			blobAccess:  tier.BlobAccess,
+			readsServed: tieredBlobAccessReadsServed.WithLabelValues(name, tier.Name),
+			failovers:   tieredBlobAccessFailovers.WithLabelValues(name, tier.Name),
+		})
+	}
+	return ba
+}
+
+// isAvailable returns whether a tier should be used, or whether it
+// has recently been observed to be unavailable.
+func (ba *tieredBlobAccess) isAvailable(tier *tieredBlobAccessTier) bool {
+	tier.lock.Lock()
+	defer tier.lock.Unlock()
+	return !ba.clock.Now().Before(tier.unavailableUntil)
+}
+
+// observeError inspects an error returned by a tier. If the tier is
+// unavailable, it is skipped by subsequent operations for some time.
+// The return value indicates whether the next tier should be tried.
+func (ba *tieredBlobAccess) observeError(tier *tieredBlobAccessTier, err error) bool {
+	if status.Code(err) != codes.Unavailable {
+		return false
+	}
+	tier.failovers.Inc()
+	tier.lock.Lock()
+	tier.unavailableUntil = ba.clock.Now().Add(ba.unavailabilityDuration)
+	tier.lock.Unlock()
+	return true
+}
+
+// getFirstAvailableTier returns the index of the first tier that has
+// not been observed to be unavailable recently. If all tiers are
+// unavailable, the last tier is used.
+func (ba *tieredBlobAccess) getFirstAvailableTier() int {
+	for i, tier := range ba.tiers[:len(ba.tiers)-1] {
+		if ba.isAvailable(tier) {
+			return i
+		}
+	}
+	return len(ba.tiers) - 1
+}
+
+func (ba *tieredBlobAccess) get(getter func(b blobstore.BlobAccess) buffer.Buffer, replicate func(source, sink blobstore.BlobAccess) buffer.Buffer) buffer.Buffer {
+	eh := &tieredErrorHandler{
+		blobAccess:      ba,
+		getter:          getter,
+		replicate:       replicate,
+		current:         -1,
+		firstNotFoundAt: -1,
+	}
+	eh.nextTier()
+	return buffer.WithErrorHandler(eh.getBuffer(), eh)
+}
+
+func (ba *tieredBlobAccess) Get(ctx context.Context, blobDigest digest.Digest) buffer.Buffer {
+	return ba.get(
+		func(b blobstore.BlobAccess) buffer.Buffer {
+			return b.Get(ctx, blobDigest)
+		},
+		func(source, sink blobstore.BlobAccess) buffer.Buffer {
+			// Copy the blob into the tier that reported it
+			// as being absent. Failures to do so are not
+			// propagated, as the blob was read successfully.
+			b1, b2 := source.Get(ctx, blobDigest).CloneStream()
+			return b1.WithTask(func() error {
+				sink.Put(ctx, blobDigest, b2)
+				return nil
+			})
+		})
+}
+
+func (ba *tieredBlobAccess) GetFromComposite(ctx context.Context, parentDigest, childDigest digest.Digest, slicer slicing.BlobSlicer) buffer.Buffer {
+	return ba.get(
+		func(b blobstore.BlobAccess) buffer.Buffer {
+			return b.GetFromComposite(ctx, parentDigest, childDigest, slicer)
+		},
+		// Blobs obtained through slicing are not copied into
+		// earlier tiers, as they are only a part of the parent.
+		nil)
+}
+
+func (ba *tieredBlobAccess) Put(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+	// The buffer can only be consumed once, meaning that writes
+	// cannot be retried against the next tier. Instead, tiers that
+	// are unavailable are skipped by subsequent writes.
+	tier := ba.tiers[ba.getFirstAvailableTier()]
+	err := tier.blobAccess.Put(ctx, blobDigest, b)
+	if err != nil {
+		ba.observeError(tier, err)
+	}
+	return err
+}
+
+func (ba *tieredBlobAccess) FindMissing(ctx context.Context, digests digest.Set) (digest.Set, error) {
+	// Only consult the tier to which blobs are written, so that
+	// callers upload blobs that are absent from it.
+	for i := ba.getFirstAvailableTier(); ; i++ {
+		tier := ba.tiers[i]
+		missing, err := tier.blobAccess.FindMissing(ctx, digests)
+		if err == nil || i == len(ba.tiers)-1 || !ba.observeError(tier, err) {
+			return missing, err
+		}
+	}
+}
+
+func (ba *tieredBlobAccess) GetCapabilities(ctx context.Context, instanceName digest.InstanceName) (*remoteexecution.ServerCapabilities, error) {
+	for i := ba.getFirstAvailableTier(); ; i++ {
+		tier := ba.tiers[i]
+		serverCapabilities, err := tier.blobAccess.GetCapabilities(ctx, instanceName)
+		if err == nil || i == len(ba.tiers)-1 || !ba.observeError(tier, err) {
+			return serverCapabilities, err
+		}
+	}
+}
+
+// tieredErrorHandler is used by TieredBlobAccess to read blobs from
+// the next tier if the current tier is unable to provide them.
+type tieredErrorHandler struct {
+	blobAccess *tieredBlobAccess
+	getter     func(b blobstore.BlobAccess) buffer.Buffer
+	replicate  func(source, sink blobstore.BlobAccess) buffer.Buffer
+
+	current         int
+	firstNotFoundAt int
+	failed          bool
+}
+
+// nextTier advances to the next tier that should be tried. Tiers that
+// were recently observed to be unavailable are skipped, except for the
+// last one.
+func (eh *tieredErrorHandler) nextTier() bool {
+	ba := eh.blobAccess
+	for eh.current+1 < len(ba.tiers) {
+		eh.current++
+		if eh.current == len(ba.tiers)-1 || ba.isAvailable(ba.tiers[eh.current]) {
+			return true
+		}
+	}
+	return false
+}
+
+// getBuffer returns a buffer for reading from the current tier. If an
+// earlier tier reported the blob to be absent, it is copied into it.
+func (eh *tieredErrorHandler) getBuffer() buffer.Buffer {
+	ba := eh.blobAccess
+	source := ba.tiers[eh.current].blobAccess
+	if eh.firstNotFoundAt >= 0 && eh.replicate != nil {
+		return eh.replicate(source, ba.tiers[eh.firstNotFoundAt].blobAccess)
+	}
+	return eh.getter(source)
+}
+
+func (eh *tieredErrorHandler) OnError(observedErr error) (buffer.Buffer, error) {
+	switch status.Code(observedErr) {
+	case codes.NotFound:
+		if eh.firstNotFoundAt < 0 {
+			eh.firstNotFoundAt = eh.current
+		}
+	case codes.Unavailable:
+		eh.blobAccess.observeError(eh.blobAccess.tiers[eh.current], observedErr)
+	default:
+		eh.failed = true
+		return nil, observedErr
+	}
+	if !eh.nextTier() {
+		eh.failed = true
+		return nil, observedErr
+	}
+	return eh.getBuffer(), nil
+}
+
+func (eh *tieredErrorHandler) Done() {
+	if !eh.failed && eh.current >= 0 {
+		eh.blobAccess.tiers[eh.current].readsServed.Inc()
+	}
+}
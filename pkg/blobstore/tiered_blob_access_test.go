@@ -0,0 +1,158 @@
+package blobstore_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTieredBlobAccess(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	regionalBlobAccess := mock.NewMockBlobAccess(ctrl)
+	centralBlobAccess := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	blobAccess := blobstore.NewTieredBlobAccess(
+		"ContentAddressableStorage",
+		[]blobstore.TieredBlobAccessTier{
+			{Name: "regional", BlobAccess: regionalBlobAccess},
+			{Name: "central", BlobAccess: centralBlobAccess},
+		},
+		clock,
+		time.Minute)
+
+	exampleDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+
+	t.Run("GetFromFirstTier", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		regionalBlobAccess.EXPECT().Get(ctx, exampleDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello")))
+
+		data, err := blobAccess.Get(ctx, exampleDigest).ToByteSlice(100)
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hello"), data)
+	})
+
+	t.Run("GetReadThrough", func(t *testing.T) {
+		// Blobs absent from the first tier should be read from the
+		// second tier, and copied into the first tier.
+		clock.EXPECT().Now().Return(time.Unix(1001, 0))
+		regionalBlobAccess.EXPECT().Get(ctx, exampleDigest).
+			Return(buffer.NewBufferFromError(status.Error(codes.NotFound, "Object not found")))
+		centralBlobAccess.EXPECT().Get(ctx, exampleDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello")))
+		regionalBlobAccess.EXPECT().Put(ctx, exampleDigest, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				data, err := b.ToByteSlice(100)
+				require.NoError(t, err)
+				require.Equal(t, []byte("Hello"), data)
+				return nil
+			})
+
+		data, err := blobAccess.Get(ctx, exampleDigest).ToByteSlice(100)
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hello"), data)
+	})
+
+	t.Run("GetNotFound", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1002, 0))
+		regionalBlobAccess.EXPECT().Get(ctx, exampleDigest).
+			Return(buffer.NewBufferFromError(status.Error(codes.NotFound, "Object not found")))
+		centralBlobAccess.EXPECT().Get(ctx, exampleDigest).
+			Return(buffer.NewBufferFromError(status.Error(codes.NotFound, "Object not found")))
+		regionalBlobAccess.EXPECT().Put(ctx, exampleDigest, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				_, err := b.ToByteSlice(100)
+				return err
+			})
+
+		_, err := blobAccess.Get(ctx, exampleDigest).ToByteSlice(100)
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Object not found"), err)
+	})
+
+	t.Run("GetOtherError", func(t *testing.T) {
+		// Errors other than NOT_FOUND and UNAVAILABLE should not
+		// cause other tiers to be consulted.
+		clock.EXPECT().Now().Return(time.Unix(1003, 0))
+		regionalBlobAccess.EXPECT().Get(ctx, exampleDigest).
+			Return(buffer.NewBufferFromError(status.Error(codes.PermissionDenied, "Access denied")))
+
+		_, err := blobAccess.Get(ctx, exampleDigest).ToByteSlice(100)
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Access denied"), err)
+	})
+
+	t.Run("Failover", func(t *testing.T) {
+		// If the first tier is unavailable, reads should be served
+		// by the second tier without copying the blob.
+		clock.EXPECT().Now().Return(time.Unix(1004, 0)).Times(2)
+		regionalBlobAccess.EXPECT().Get(ctx, exampleDigest).
+			Return(buffer.NewBufferFromError(status.Error(codes.Unavailable, "Server offline")))
+		centralBlobAccess.EXPECT().Get(ctx, exampleDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello")))
+
+		data, err := blobAccess.Get(ctx, exampleDigest).ToByteSlice(100)
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hello"), data)
+
+		// For the duration that follows, the first tier should
+		// not be consulted by any operation.
+		clock.EXPECT().Now().Return(time.Unix(1030, 0))
+		centralBlobAccess.EXPECT().Get(ctx, exampleDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello")))
+
+		data, err = blobAccess.Get(ctx, exampleDigest).ToByteSlice(100)
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hello"), data)
+
+		clock.EXPECT().Now().Return(time.Unix(1040, 0))
+		centralBlobAccess.EXPECT().Put(ctx, exampleDigest, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				b.Discard()
+				return nil
+			})
+
+		require.NoError(t, blobAccess.Put(ctx, exampleDigest, buffer.NewValidatedBufferFromByteSlice([]byte("Hello"))))
+
+		clock.EXPECT().Now().Return(time.Unix(1050, 0))
+		centralBlobAccess.EXPECT().FindMissing(ctx, exampleDigest.ToSingletonSet()).
+			Return(digest.EmptySet, nil)
+
+		missing, err := blobAccess.FindMissing(ctx, exampleDigest.ToSingletonSet())
+		require.NoError(t, err)
+		require.Equal(t, digest.EmptySet, missing)
+
+		// Once the duration has passed, the first tier should be
+		// used again.
+		clock.EXPECT().Now().Return(time.Unix(1064, 0))
+		regionalBlobAccess.EXPECT().FindMissing(ctx, exampleDigest.ToSingletonSet()).
+			Return(exampleDigest.ToSingletonSet(), nil)
+
+		missing, err = blobAccess.FindMissing(ctx, exampleDigest.ToSingletonSet())
+		require.NoError(t, err)
+		require.Equal(t, exampleDigest.ToSingletonSet(), missing)
+	})
+
+	t.Run("FindMissingFailover", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1100, 0)).Times(2)
+		regionalBlobAccess.EXPECT().FindMissing(ctx, exampleDigest.ToSingletonSet()).
+			Return(digest.EmptySet, status.Error(codes.Unavailable, "Server offline"))
+		centralBlobAccess.EXPECT().FindMissing(ctx, exampleDigest.ToSingletonSet()).
+			Return(exampleDigest.ToSingletonSet(), nil)
+
+		missing, err := blobAccess.FindMissing(ctx, exampleDigest.ToSingletonSet())
+		require.NoError(t, err)
+		require.Equal(t, exampleDigest.ToSingletonSet(), missing)
+	})
+}
@@ -0,0 +1,252 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/google/uuid"
+	"github.com/klauspost/compress/zstd"
+
+	"google.golang.org/genproto/googleapis/bytestream"
+	"google.golang.org/grpc/codes"
+)
+
+type zstdByteStreamBlobAccess struct {
+	blobstore.BlobAccess
+	byteStreamClient   bytestream.ByteStreamClient
+	capabilitiesClient remoteexecution.CapabilitiesClient
+	uuidGenerator      util.UUIDGenerator
+	writeChunkSize     int
+	minimumSizeBytes   int64
+
+	lock              sync.Mutex
+	supportsZstdCache map[digest.InstanceName]bool
+}
+
+// NewZstdByteStreamBlobAccess is an adapter for BlobAccess that causes
+// reads and writes of blobs to be performed against the ByteStream
+// service in zstd compressed form, using the "compressed-blobs"
+// resource names described in REv2. This reduces the amount of
+// network bandwidth used to transfer compressible blobs, at the cost
+// of increased CPU usage.
+//
+// Compression is only used for blobs that are at least
+// minimumSizeBytes in size, and only if the server announces support
+// for zstd in its cache capabilities. All other operations are
+// forwarded to the backend, which should refer to the same storage.
+func NewZstdByteStreamBlobAccess(blobAccess blobstore.BlobAccess, byteStreamClient bytestream.ByteStreamClient, capabilitiesClient remoteexecution.CapabilitiesClient, uuidGenerator util.UUIDGenerator, writeChunkSize int, minimumSizeBytes int64) blobstore.BlobAccess {
+	return &zstdByteStreamBlobAccess{
+		BlobAccess:         blobAccess,
+		byteStreamClient:   byteStreamClient,
+		capabilitiesClient: capabilitiesClient,
+		uuidGenerator:      uuidGenerator,
+		writeChunkSize:     writeChunkSize,
+		minimumSizeBytes:   minimumSizeBytes,
+		supportsZstdCache:  map[digest.InstanceName]bool{},
+	}
+}
+
+// shouldCompress returns whether a given blob should be transferred in
+// compressed form. Support for zstd is determined by calling
+// GetCapabilities() once for every instance name.
+func (ba *zstdByteStreamBlobAccess) shouldCompress(ctx context.Context, blobDigest digest.Digest) (bool, error) {
+	if blobDigest.GetSizeBytes() < ba.minimumSizeBytes {
+		return false, nil
+	}
+
+	instanceName := blobDigest.GetInstanceName()
+	ba.lock.Lock()
+	supportsZstd, ok := ba.supportsZstdCache[instanceName]
+	ba.lock.Unlock()
+	if ok {
+		return supportsZstd, nil
+	}
+
+	serverCapabilities, err := ba.capabilitiesClient.GetCapabilities(ctx, &remoteexecution.GetCapabilitiesRequest{
+		InstanceName: instanceName.String(),
+	})
+	if err != nil {
+		return false, util.StatusWrap(err, "Failed to obtain server capabilities")
+	}
+	supportsZstd = false
+	for _, compressor := range serverCapabilities.CacheCapabilities.GetSupportedCompressors() {
+		if compressor == remoteexecution.Compressor_ZSTD {
+			supportsZstd = true
+			break
+		}
+	}
+
+	ba.lock.Lock()
+	ba.supportsZstdCache[instanceName] = supportsZstd
+	ba.lock.Unlock()
+	return supportsZstd, nil
+}
+
+func (ba *zstdByteStreamBlobAccess) Get(ctx context.Context, blobDigest digest.Digest) buffer.Buffer {
+	if compress, err := ba.shouldCompress(ctx, blobDigest); err != nil {
+		return buffer.NewBufferFromError(err)
+	} else if !compress {
+		return ba.BlobAccess.Get(ctx, blobDigest)
+	}
+
+	ctxWithCancel, cancel := context.WithCancel(ctx)
+	client, err := ba.byteStreamClient.Read(ctxWithCancel, &bytestream.ReadRequest{
+		ResourceName: blobDigest.GetByteStreamReadPath(remoteexecution.Compressor_ZSTD),
+	})
+	if err != nil {
+		cancel()
+		return buffer.NewBufferFromError(err)
+	}
+	decoder, err := zstd.NewReader(&byteStreamReader{client: client}, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		cancel()
+		return buffer.NewBufferFromError(util.StatusWrapWithCode(err, codes.Internal, "Failed to create zstd decoder"))
+	}
+	return buffer.NewCASBufferFromReader(blobDigest, &zstdDecodingReadCloser{
+		decoder: decoder,
+		cancel:  cancel,
+	}, buffer.BackendProvided(buffer.Irreparable(blobDigest)))
+}
+
+func (ba *zstdByteStreamBlobAccess) Put(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+	if compress, err := ba.shouldCompress(ctx, blobDigest); err != nil {
+		b.Discard()
+		return err
+	} else if !compress {
+		return ba.BlobAccess.Put(ctx, blobDigest, b)
+	}
+
+	r := b.ToReader()
+	defer r.Close()
+
+	ctxWithCancel, cancel := context.WithCancel(ctx)
+	defer cancel()
+	client, err := ba.byteStreamClient.Write(ctxWithCancel)
+	if err != nil {
+		return err
+	}
+
+	w := &byteStreamWriter{
+		client:       client,
+		resourceName: blobDigest.GetByteStreamWritePath(uuid.Must(ba.uuidGenerator()), remoteexecution.Compressor_ZSTD),
+		chunkSize:    ba.writeChunkSize,
+	}
+	encoder, err := zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		cancel()
+		client.CloseAndRecv()
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to create zstd encoder")
+	}
+	_, err = io.Copy(encoder, r)
+	if closeErr := encoder.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = w.flush()
+	}
+	if err != nil && !w.sendFailed {
+		// Reading or compressing the blob failed. Abort the
+		// write, so that the server discards the data.
+		cancel()
+		client.CloseAndRecv()
+		return err
+	}
+
+	// If sending failed, the server terminated the stream, either
+	// due to an error or because the blob already exists. In both
+	// cases the status can be extracted from the stream.
+	_, err = client.CloseAndRecv()
+	return err
+}
+
+// byteStreamReader is an io.Reader that returns the data contained in
+// the responses of a ByteStream Read() call.
+type byteStreamReader struct {
+	client bytestream.ByteStream_ReadClient
+	data   []byte
+}
+
+func (r *byteStreamReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		response, err := r.client.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.data = response.Data
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// zstdDecodingReadCloser is an io.ReadCloser that returns the
+// decompressed contents of a blob. Closing it releases the decoder
+// and cancels the ByteStream Read() call.
+type zstdDecodingReadCloser struct {
+	decoder *zstd.Decoder
+	cancel  context.CancelFunc
+}
+
+func (r *zstdDecodingReadCloser) Read(p []byte) (int, error) {
+	return r.decoder.Read(p)
+}
+
+func (r *zstdDecodingReadCloser) Close() error {
+	r.decoder.Close()
+	r.cancel()
+	return nil
+}
+
+// byteStreamWriter is an io.Writer that sends compressed data as part
+// of a ByteStream Write() call. As described in REv2, the write offset
+// of the first request refers to the uncompressed blob, while
+// subsequent requests use the total size of the compressed data sent.
+// As uploads always start at offset zero, this is equal to the number
+// of compressed bytes sent.
+type byteStreamWriter struct {
+	client       bytestream.ByteStream_WriteClient
+	resourceName string
+	chunkSize    int
+	buffered     []byte
+	writeOffset  int64
+	sendFailed   bool
+}
+
+func (w *byteStreamWriter) Write(p []byte) (int, error) {
+	w.buffered = append(w.buffered, p...)
+	for len(w.buffered) >= w.chunkSize {
+		if err := w.send(w.buffered[:w.chunkSize], false); err != nil {
+			return 0, err
+		}
+		w.buffered = w.buffered[w.chunkSize:]
+	}
+	return len(p), nil
+}
+
+// flush sends any remaining buffered data, and finishes the write.
+func (w *byteStreamWriter) flush() error {
+	err := w.send(w.buffered, true)
+	w.buffered = nil
+	return err
+}
+
+func (w *byteStreamWriter) send(data []byte, finishWrite bool) error {
+	if err := w.client.Send(&bytestream.WriteRequest{
+		ResourceName: w.resourceName,
+		WriteOffset:  w.writeOffset,
+		Data:         data,
+		FinishWrite:  finishWrite,
+	}); err != nil {
+		w.sendFailed = true
+		return err
+	}
+	w.writeOffset += int64(len(data))
+	w.resourceName = ""
+	return nil
+}
@@ -0,0 +1,193 @@
+package blobstore_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/genproto/googleapis/bytestream"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestZstdByteStreamBlobAccess(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBlobAccess := mock.NewMockBlobAccess(ctrl)
+	byteStreamClient := mock.NewMockByteStreamClient(ctrl)
+	capabilitiesClient := mock.NewMockCapabilitiesClient(ctrl)
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	blobAccess := blobstore.NewZstdByteStreamBlobAccess(
+		baseBlobAccess,
+		byteStreamClient,
+		capabilitiesClient,
+		uuidGenerator.Call,
+		/* writeChunkSize = */ 4,
+		/* minimumSizeBytes = */ 5)
+
+	zstdCapabilities := &remoteexecution.ServerCapabilities{
+		CacheCapabilities: &remoteexecution.CacheCapabilities{
+			SupportedCompressors: []remoteexecution.Compressor_Value{
+				remoteexecution.Compressor_ZSTD,
+			},
+		},
+	}
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	compressedHello := encoder.EncodeAll([]byte("Hello"), nil)
+
+	t.Run("SmallBlob", func(t *testing.T) {
+		// Blobs below the minimum size should be transferred
+		// through the backend, without calling GetCapabilities().
+		blobDigest := digest.MustNewDigest("small", remoteexecution.DigestFunction_MD5, "800618943025315f869e4e1f09471012", 1)
+		baseBlobAccess.EXPECT().Get(ctx, blobDigest).Return(buffer.NewValidatedBufferFromByteSlice([]byte("F")))
+
+		data, err := blobAccess.Get(ctx, blobDigest).ToByteSlice(100)
+		require.NoError(t, err)
+		require.Equal(t, []byte("F"), data)
+	})
+
+	t.Run("CapabilitiesFailure", func(t *testing.T) {
+		// Failures to obtain capabilities should not be cached.
+		blobDigest := digest.MustNewDigest("failure", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+		capabilitiesClient.EXPECT().GetCapabilities(ctx, testutil.EqProto(t, &remoteexecution.GetCapabilitiesRequest{
+			InstanceName: "failure",
+		})).Return(nil, status.Error(codes.Unavailable, "Server not reachable")).Times(2)
+
+		_, err := blobAccess.Get(ctx, blobDigest).ToByteSlice(100)
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Failed to obtain server capabilities: Server not reachable"), err)
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Unavailable, "Failed to obtain server capabilities: Server not reachable"),
+			blobAccess.Put(ctx, blobDigest, buffer.NewValidatedBufferFromByteSlice([]byte("Hello"))))
+	})
+
+	t.Run("NoZstdSupport", func(t *testing.T) {
+		// If the server does not support zstd, all requests
+		// should go through the backend. Capabilities should
+		// only be requested once.
+		blobDigest := digest.MustNewDigest("uncompressed", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+		capabilitiesClient.EXPECT().GetCapabilities(ctx, testutil.EqProto(t, &remoteexecution.GetCapabilitiesRequest{
+			InstanceName: "uncompressed",
+		})).Return(&remoteexecution.ServerCapabilities{
+			CacheCapabilities: &remoteexecution.CacheCapabilities{},
+		}, nil)
+		baseBlobAccess.EXPECT().Get(ctx, blobDigest).Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello")))
+		baseBlobAccess.EXPECT().Put(ctx, blobDigest, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				b.Discard()
+				return nil
+			})
+
+		data, err := blobAccess.Get(ctx, blobDigest).ToByteSlice(100)
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hello"), data)
+
+		require.NoError(t, blobAccess.Put(ctx, blobDigest, buffer.NewValidatedBufferFromByteSlice([]byte("Hello"))))
+	})
+
+	blobDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+	capabilitiesClient.EXPECT().GetCapabilities(gomock.Any(), testutil.EqProto(t, &remoteexecution.GetCapabilitiesRequest{
+		InstanceName: "hello",
+	})).Return(zstdCapabilities, nil)
+
+	t.Run("GetSuccess", func(t *testing.T) {
+		// Compressed data should be decompressed, regardless
+		// of how it is split up across responses.
+		readClient := mock.NewMockByteStream_ReadClient(ctrl)
+		byteStreamClient.EXPECT().Read(gomock.Any(), testutil.EqProto(t, &bytestream.ReadRequest{
+			ResourceName: "hello/compressed-blobs/zstd/8b1a9953c4611296a827abf8c47804d7/5",
+		})).Return(readClient, nil)
+		readClient.EXPECT().Recv().Return(&bytestream.ReadResponse{Data: compressedHello[:3]}, nil)
+		readClient.EXPECT().Recv().Return(&bytestream.ReadResponse{Data: compressedHello[3:]}, nil)
+		readClient.EXPECT().Recv().Return(nil, io.EOF).AnyTimes()
+
+		data, err := blobAccess.Get(ctx, blobDigest).ToByteSlice(100)
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hello"), data)
+	})
+
+	t.Run("GetCorrupted", func(t *testing.T) {
+		// Data that decompresses to the wrong contents should
+		// be rejected by the checksum validation.
+		readClient := mock.NewMockByteStream_ReadClient(ctrl)
+		byteStreamClient.EXPECT().Read(gomock.Any(), gomock.Any()).Return(readClient, nil)
+		readClient.EXPECT().Recv().Return(&bytestream.ReadResponse{Data: encoder.EncodeAll([]byte("Jello"), nil)}, nil)
+		readClient.EXPECT().Recv().Return(nil, io.EOF).AnyTimes()
+
+		_, err := blobAccess.Get(ctx, blobDigest).ToByteSlice(100)
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("PutSuccess", func(t *testing.T) {
+		// Compressed data should be sent in chunks. The write
+		// offsets should correspond to the amount of compressed
+		// data sent.
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse("36ebab65-3c4f-4faf-818b-2eabb4cd1b02"))
+		writeClient := mock.NewMockByteStream_WriteClient(ctrl)
+		byteStreamClient.EXPECT().Write(gomock.Any()).Return(writeClient, nil)
+		var compressedData []byte
+		finished := false
+		writeClient.EXPECT().Send(gomock.Any()).DoAndReturn(func(request *bytestream.WriteRequest) error {
+			require.False(t, finished)
+			if len(compressedData) == 0 {
+				require.Equal(t, "hello/uploads/36ebab65-3c4f-4faf-818b-2eabb4cd1b02/compressed-blobs/zstd/8b1a9953c4611296a827abf8c47804d7/5", request.ResourceName)
+			} else {
+				require.Empty(t, request.ResourceName)
+			}
+			require.Equal(t, int64(len(compressedData)), request.WriteOffset)
+			require.LessOrEqual(t, len(request.Data), 4)
+			compressedData = append(compressedData, request.Data...)
+			finished = request.FinishWrite
+			return nil
+		}).MinTimes(2)
+		writeClient.EXPECT().CloseAndRecv().Return(&bytestream.WriteResponse{CommittedSize: 5}, nil)
+
+		require.NoError(t, blobAccess.Put(ctx, blobDigest, buffer.NewValidatedBufferFromByteSlice([]byte("Hello"))))
+		require.True(t, finished)
+
+		decoder, err := zstd.NewReader(nil)
+		require.NoError(t, err)
+		defer decoder.Close()
+		data, err := decoder.DecodeAll(compressedData, nil)
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hello"), data)
+	})
+
+	t.Run("PutAlreadyExists", func(t *testing.T) {
+		// If the server terminates the stream early because
+		// the blob already exists, the write should succeed.
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse("9dd6e6c4-2e1e-4fd7-9a5f-4e0e7e1a3b6c"))
+		writeClient := mock.NewMockByteStream_WriteClient(ctrl)
+		byteStreamClient.EXPECT().Write(gomock.Any()).Return(writeClient, nil)
+		writeClient.EXPECT().Send(gomock.Any()).Return(io.EOF)
+		writeClient.EXPECT().CloseAndRecv().Return(&bytestream.WriteResponse{CommittedSize: -1}, nil)
+
+		require.NoError(t, blobAccess.Put(ctx, blobDigest, buffer.NewValidatedBufferFromByteSlice([]byte("Hello"))))
+	})
+
+	t.Run("PutFailure", func(t *testing.T) {
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse("c8f6e2a1-6f2b-4b9c-8e59-5d0a3c7f1e24"))
+		writeClient := mock.NewMockByteStream_WriteClient(ctrl)
+		byteStreamClient.EXPECT().Write(gomock.Any()).Return(writeClient, nil)
+		writeClient.EXPECT().Send(gomock.Any()).Return(io.EOF)
+		writeClient.EXPECT().CloseAndRecv().Return(nil, status.Error(codes.PermissionDenied, "Access denied"))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.PermissionDenied, "Access denied"),
+			blobAccess.Put(ctx, blobDigest, buffer.NewValidatedBufferFromByteSlice([]byte("Hello"))))
+	})
+}
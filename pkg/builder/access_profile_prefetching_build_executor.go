@@ -0,0 +1,282 @@
+package builder
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/eviction"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AccessProfileCache holds access profiles of actions that were
+// executed by the worker. An access profile is the list of paths of
+// files in the input root that were read by an action, in the order in
+// which they were first read. Profiles are keyed by the digest of the
+// action's Command message, meaning that they remain usable when the
+// action's input root changes.
+//
+// A single instance of AccessProfileCache may be shared by all
+// runners of a worker.
+type AccessProfileCache struct {
+	lock             sync.Mutex
+	profiles         map[string][]string
+	maximumCacheSize int
+	evictionSet      eviction.Set[string]
+}
+
+// NewAccessProfileCache creates an AccessProfileCache that retains at
+// most a given number of profiles.
+func NewAccessProfileCache(maximumCacheSize int, evictionSet eviction.Set[string]) *AccessProfileCache {
+	return &AccessProfileCache{
+		profiles:         map[string][]string{},
+		maximumCacheSize: maximumCacheSize,
+		evictionSet:      evictionSet,
+	}
+}
+
+func (c *AccessProfileCache) get(key string) ([]string, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	paths, ok := c.profiles[key]
+	if ok {
+		c.evictionSet.Touch(key)
+	}
+	return paths, ok
+}
+
+func (c *AccessProfileCache) put(key string, paths []string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.profiles[key]; ok {
+		c.evictionSet.Touch(key)
+	} else {
+		for len(c.profiles) > 0 && len(c.profiles) >= c.maximumCacheSize {
+			delete(c.profiles, c.evictionSet.Peek())
+			c.evictionSet.Remove()
+		}
+		c.evictionSet.Insert(key)
+	}
+	c.profiles[key] = paths
+}
+
+type accessProfilePrefetchingBuildExecutor struct {
+	BuildExecutor
+	contentAddressableStorage blobstore.BlobAccess
+	directoryFetcher          cas.DirectoryFetcher
+	fileReadSemaphore         *semaphore.Weighted
+	accessProfileCache        *AccessProfileCache
+	maximumPathsPerProfile    int
+}
+
+// NewAccessProfilePrefetchingBuildExecutor creates a decorator for
+// BuildExecutor that records which files in the input root are read by
+// an action through the virtual file system, and in which order. When
+// an action with the same Command message is executed afterwards, the
+// files contained in the profile are prefetched from the Content
+// Addressable Storage (CAS) in that same order, while the action is
+// running. This reduces the number of cold reads that need to be
+// serviced by the virtual file system.
+//
+// As opposed to NewPrefetchingBuildExecutor(), which stores Bloom
+// filters in the File System Access Cache (FSAC), profiles are kept in
+// memory of the worker. Because they contain exact paths and preserve
+// ordering, files that are needed early on are fetched first.
+//
+// Like NewPrefetchingBuildExecutor(), this decorator is only of use on
+// workers that use a virtual build directory.
+func NewAccessProfilePrefetchingBuildExecutor(buildExecutor BuildExecutor, contentAddressableStorage blobstore.BlobAccess, directoryFetcher cas.DirectoryFetcher, fileReadSemaphore *semaphore.Weighted, accessProfileCache *AccessProfileCache, maximumPathsPerProfile int) BuildExecutor {
+	return &accessProfilePrefetchingBuildExecutor{
+		BuildExecutor:             buildExecutor,
+		contentAddressableStorage: contentAddressableStorage,
+		directoryFetcher:          directoryFetcher,
+		fileReadSemaphore:         fileReadSemaphore,
+		accessProfileCache:        accessProfileCache,
+		maximumPathsPerProfile:    maximumPathsPerProfile,
+	}
+}
+
+func (be *accessProfilePrefetchingBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	action := request.Action
+	if action == nil {
+		response := NewDefaultExecuteResponse(request)
+		attachErrorToExecuteResponse(response, status.Error(codes.InvalidArgument, "Request does not contain an action"))
+		return response
+	}
+	commandDigest, err := digestFunction.NewDigestFromProto(action.CommandDigest)
+	if err != nil {
+		response := NewDefaultExecuteResponse(request)
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to extract digest for command"))
+		return response
+	}
+	key := commandDigest.GetKey(digest.KeyWithInstance)
+
+	// If a profile exists, prefetch the files contained in it in
+	// the background, while the action is running.
+	group, groupCtx := errgroup.WithContext(ctx)
+	prefetchCtx, cancelPrefetch := context.WithCancel(groupCtx)
+	defer cancelPrefetch()
+	if paths, ok := be.accessProfileCache.get(key); ok {
+		orderedPrefetcher := orderedPrefetcher{
+			context:                   prefetchCtx,
+			group:                     group,
+			digestFunction:            digestFunction,
+			contentAddressableStorage: be.contentAddressableStorage,
+			directoryFetcher:          be.directoryFetcher,
+			fileReadSemaphore:         be.fileReadSemaphore,
+			directories:               map[string]*remoteexecution.Directory{},
+		}
+		group.Go(func() error {
+			// Prefetching may be interrupted if the action
+			// completes quickly. These cancelation errors
+			// should not propagate to the caller.
+			if err := orderedPrefetcher.prefetch(action.InputRootDigest, paths); status.Code(err) != codes.Canceled {
+				return err
+			}
+			return nil
+		})
+	}
+
+	readFileRecordingMonitor := access.NewReadFileRecordingUnreadDirectoryMonitor(monitor)
+	response := be.BuildExecutor.Execute(ctx, filePool, readFileRecordingMonitor, digestFunction, request, executionStateUpdates)
+	cancelPrefetch()
+	if err := group.Wait(); err != nil {
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to prefetch files using access profile"))
+	}
+
+	if executeResponseIsSuccessful(response) {
+		paths := readFileRecordingMonitor.GetReadFilesInOrder()
+		if len(paths) > be.maximumPathsPerProfile {
+			paths = paths[:be.maximumPathsPerProfile]
+		}
+		be.accessProfileCache.put(key, paths)
+	}
+	return response
+}
+
+// orderedPrefetcher is used by accessProfilePrefetchingBuildExecutor
+// to download files in the input root in the order in which they are
+// listed in an access profile.
+type orderedPrefetcher struct {
+	context                   context.Context
+	group                     *errgroup.Group
+	digestFunction            digest.Function
+	contentAddressableStorage blobstore.BlobAccess
+	directoryFetcher          cas.DirectoryFetcher
+	fileReadSemaphore         *semaphore.Weighted
+
+	// Directories in the input root that have been loaded, keyed by
+	// their path. The input root uses the empty path.
+	directories map[string]*remoteexecution.Directory
+}
+
+// getDirectory returns the contents of a directory in the input root.
+// If the directory does not exist, nil is returned.
+func (op *orderedPrefetcher) getDirectory(directoryPath string, inputRootDigest *remoteexecution.Digest) (*remoteexecution.Directory, error) {
+	if directory, ok := op.directories[directoryPath]; ok {
+		return directory, nil
+	}
+
+	var rawDirectoryDigest *remoteexecution.Digest
+	if directoryPath == "" {
+		rawDirectoryDigest = inputRootDigest
+	} else {
+		parentPath, name := "", directoryPath
+		if slash := strings.LastIndexByte(directoryPath, '/'); slash >= 0 {
+			parentPath, name = directoryPath[:slash], directoryPath[slash+1:]
+		}
+		parent, err := op.getDirectory(parentPath, inputRootDigest)
+		if err != nil || parent == nil {
+			return nil, err
+		}
+		for _, child := range parent.Directories {
+			if child.Name == name {
+				rawDirectoryDigest = child.Digest
+				break
+			}
+		}
+		if rawDirectoryDigest == nil {
+			// Input root no longer contains this directory.
+			op.directories[directoryPath] = nil
+			return nil, nil
+		}
+	}
+
+	directoryDigest, err := op.digestFunction.NewDigestFromProto(rawDirectoryDigest)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to parse digest for directory %#v", directoryPath)
+	}
+	directory, err := op.directoryFetcher.GetDirectory(op.context, directoryDigest)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to prefetch directory %#v", directoryPath)
+	}
+	op.directories[directoryPath] = directory
+	return directory, nil
+}
+
+func (op *orderedPrefetcher) prefetch(inputRootDigest *remoteexecution.Digest, paths []string) error {
+	for _, filePath := range paths {
+		directoryPath, name := "", filePath
+		if slash := strings.LastIndexByte(filePath, '/'); slash >= 0 {
+			directoryPath, name = filePath[:slash], filePath[slash+1:]
+		}
+		directory, err := op.getDirectory(directoryPath, inputRootDigest)
+		if err != nil {
+			return err
+		}
+		if directory == nil {
+			continue
+		}
+
+		// Skip files that are no longer part of the input root.
+		var rawFileDigest *remoteexecution.Digest
+		for _, file := range directory.Files {
+			if file.Name == name {
+				rawFileDigest = file.Digest
+				break
+			}
+		}
+		if rawFileDigest == nil {
+			continue
+		}
+		fileDigest, err := op.digestFunction.NewDigestFromProto(rawFileDigest)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to parse digest for file %#v", filePath)
+		}
+
+		// Download files at a globally bounded concurrency.
+		// Acquiring the semaphore sequentially ensures that
+		// downloads are started in the order of the profile.
+		// Just like PrefetchingBuildExecutor, perform a 1 byte
+		// read to load the file into the local cache.
+		if op.context.Err() != nil || op.fileReadSemaphore.Acquire(op.context, 1) != nil {
+			return util.StatusFromContext(op.context)
+		}
+		prefetchedPath := filePath
+		op.group.Go(func() error {
+			var b [1]byte
+			_, err := op.contentAddressableStorage.Get(op.context, fileDigest).ReadAt(b[:], 0)
+			op.fileReadSemaphore.Release(1)
+			if err != nil && err != io.EOF && status.Code(err) != codes.Canceled {
+				return util.StatusWrapf(err, "Failed to prefetch file %#v", prefetchedPath)
+			}
+			return nil
+		})
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/eviction"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+
+	"golang.org/x/sync/semaphore"
+)
+
+func TestAccessProfilePrefetchingBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	buildExecutor := builder.NewAccessProfilePrefetchingBuildExecutor(
+		baseBuildExecutor,
+		contentAddressableStorage,
+		directoryFetcher,
+		semaphore.NewWeighted(1),
+		builder.NewAccessProfileCache(10, eviction.NewLRUSet[string]()),
+		/* maximumPathsPerProfile = */ 2)
+
+	filePool := mock.NewMockFilePool(ctrl)
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5)
+	executionStateUpdates := make(chan<- *remoteworker.CurrentState_Executing)
+	newRequest := func(inputRootHash string) *remoteworker.DesiredState_Executing {
+		return &remoteworker.DesiredState_Executing{
+			Action: &remoteexecution.Action{
+				CommandDigest: &remoteexecution.Digest{
+					Hash:      "e72499b7c6de12b7ad046541f6de8beb",
+					SizeBytes: 123,
+				},
+				InputRootDigest: &remoteexecution.Digest{
+					Hash:      inputRootHash,
+					SizeBytes: 456,
+				},
+			},
+		}
+	}
+	successfulResponse := &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+		},
+	}
+
+	t.Run("RecordProfile", func(t *testing.T) {
+		// The first time the action runs, no profile exists.
+		// Files that are read should be recorded in order. Only
+		// the first two paths should be retained.
+		request := newRequest("095afbd64b6358665546558583c64d38")
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, gomock.Any(), digestFunction, request, executionStateUpdates).
+			DoAndReturn(func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+				rootDirectory := monitor.ReadDirectory()
+				rootDirectory.ResolvedDirectory(path.MustNewComponent("lib")).ReadDirectory().ReadFile(path.MustNewComponent("header.h"))
+				rootDirectory.ReadFile(path.MustNewComponent("main.c"))
+				rootDirectory.ReadFile(path.MustNewComponent("unused.c"))
+				return successfulResponse
+			})
+
+		testutil.RequireEqualProto(
+			t,
+			successfulResponse,
+			buildExecutor.Execute(ctx, filePool, nil, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("FailedExecution", func(t *testing.T) {
+		// Profiles of actions that fail should not replace
+		// existing ones.
+		request := newRequest("8b1a9953c4611296a827abf8c47804d7")
+		inputRootDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 456)
+		directoryFetcher.EXPECT().GetDirectory(gomock.Any(), inputRootDigest).Return(&remoteexecution.Directory{}, nil).MaxTimes(1)
+		failedResponse := &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+				ExitCode:          1,
+			},
+		}
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, gomock.Any(), digestFunction, request, executionStateUpdates).
+			DoAndReturn(func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+				monitor.ReadDirectory().ReadFile(path.MustNewComponent("other.c"))
+				return failedResponse
+			})
+
+		testutil.RequireEqualProto(
+			t,
+			failedResponse,
+			buildExecutor.Execute(ctx, filePool, nil, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("PrefetchProfile", func(t *testing.T) {
+		// When the action runs again with a different input
+		// root, the files in the profile should be prefetched
+		// in the order in which they were read previously.
+		request := newRequest("0cc175b9c0f1b6a831c399e269772661")
+		inputRootDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "0cc175b9c0f1b6a831c399e269772661", 456)
+		libDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "92eb5ffee6ae2fec3ad71c777531578f", 100)
+		headerDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "4a8a08f09d37b73795649038408b5f33", 200)
+		mainDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8277e0910d750195b448797616e091ad", 300)
+		directoryFetcher.EXPECT().GetDirectory(gomock.Any(), inputRootDigest).Return(&remoteexecution.Directory{
+			Directories: []*remoteexecution.DirectoryNode{{
+				Name:   "lib",
+				Digest: libDigest.GetProto(),
+			}},
+			Files: []*remoteexecution.FileNode{{
+				Name:   "main.c",
+				Digest: mainDigest.GetProto(),
+			}},
+		}, nil)
+		directoryFetcher.EXPECT().GetDirectory(gomock.Any(), libDigest).Return(&remoteexecution.Directory{
+			Files: []*remoteexecution.FileNode{{
+				Name:   "header.h",
+				Digest: headerDigest.GetProto(),
+			}},
+		}, nil)
+		prefetched := make(chan struct{})
+		gomock.InOrder(
+			contentAddressableStorage.EXPECT().Get(gomock.Any(), headerDigest).
+				Return(buffer.NewValidatedBufferFromByteSlice([]byte("#define X 1\n"))),
+			contentAddressableStorage.EXPECT().Get(gomock.Any(), mainDigest).
+				DoAndReturn(func(ctx context.Context, blobDigest digest.Digest) buffer.Buffer {
+					close(prefetched)
+					return buffer.NewValidatedBufferFromByteSlice([]byte("int main() {}\n"))
+				}))
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, gomock.Any(), digestFunction, request, executionStateUpdates).
+			DoAndReturn(func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+				<-prefetched
+				return successfulResponse
+			})
+
+		testutil.RequireEqualProto(
+			t,
+			successfulResponse,
+			buildExecutor.Execute(ctx, filePool, nil, digestFunction, request, executionStateUpdates))
+	})
+}
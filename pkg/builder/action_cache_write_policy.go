@@ -0,0 +1,142 @@
+package builder
+
+import (
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+
+	"google.golang.org/grpc/status"
+)
+
+// ActionCacheWriteDecision is returned by ActionCacheWritePolicy to
+// indicate whether the result of an action is stored in the Action
+// Cache (AC).
+type ActionCacheWriteDecision int
+
+const (
+	// ActionCacheWriteDecisionSkip indicates that the result of the
+	// action should not be stored in the Action Cache. It is stored
+	// in the Content Addressable Storage instead, so that it can
+	// still be inspected through bb_browser.
+	ActionCacheWriteDecisionSkip ActionCacheWriteDecision = iota
+	// ActionCacheWriteDecisionWrite indicates that the result of
+	// the action should be stored in the Action Cache.
+	ActionCacheWriteDecisionWrite
+	// ActionCacheWriteDecisionVerifyDeterminism indicates that the
+	// result of the action should only be stored in the Action
+	// Cache if executing the action once more yields identical
+	// outputs.
+	ActionCacheWriteDecisionVerifyDeterminism
+)
+
+// ActionCacheWritePolicy is used by CachingBuildExecutor to determine
+// whether the result of an action that has been executed is stored in
+// the Action Cache (AC). Actions that have the 'do_not_cache' flag set
+// are never stored in the Action Cache, regardless of the policy.
+type ActionCacheWritePolicy interface {
+	GetActionCacheWriteDecision(request *remoteworker.DesiredState_Executing, response *remoteexecution.ExecuteResponse) ActionCacheWriteDecision
+}
+
+type successfulActionCacheWritePolicy struct{}
+
+func (successfulActionCacheWritePolicy) GetActionCacheWriteDecision(request *remoteworker.DesiredState_Executing, response *remoteexecution.ExecuteResponse) ActionCacheWriteDecision {
+	if executeResponseIsSuccessful(response) {
+		return ActionCacheWriteDecisionWrite
+	}
+	return ActionCacheWriteDecisionSkip
+}
+
+// SuccessfulActionCacheWritePolicy is an ActionCacheWritePolicy that
+// only stores results of actions that completed with exit code zero in
+// the Action Cache. This is the behavior that clients such as Bazel
+// expect.
+var SuccessfulActionCacheWritePolicy ActionCacheWritePolicy = successfulActionCacheWritePolicy{}
+
+type completedActionCacheWritePolicy struct{}
+
+func (completedActionCacheWritePolicy) GetActionCacheWriteDecision(request *remoteworker.DesiredState_Executing, response *remoteexecution.ExecuteResponse) ActionCacheWriteDecision {
+	if status.ErrorProto(response.Status) == nil && response.Result != nil {
+		return ActionCacheWriteDecisionWrite
+	}
+	return ActionCacheWriteDecisionSkip
+}
+
+// CompletedActionCacheWritePolicy is an ActionCacheWritePolicy that
+// stores results of all actions that ran to completion in the Action
+// Cache, including ones that completed with a non-zero exit code. This
+// may be used to collect results of failing actions, for example to
+// analyze flaky tests. Actions that failed due to infrastructure
+// errors or timeouts are not stored.
+var CompletedActionCacheWritePolicy ActionCacheWritePolicy = completedActionCacheWritePolicy{}
+
+type determinismVerifyingActionCacheWritePolicy struct {
+	base ActionCacheWritePolicy
+}
+
+// NewDeterminismVerifyingActionCacheWritePolicy creates a decorator
+// for ActionCacheWritePolicy that only permits results of actions to
+// be stored in the Action Cache if executing the action once more
+// yields identical outputs. This prevents results of nondeterministic
+// actions from being cached, at the cost of executing every cacheable
+// action twice.
+func NewDeterminismVerifyingActionCacheWritePolicy(base ActionCacheWritePolicy) ActionCacheWritePolicy {
+	return &determinismVerifyingActionCacheWritePolicy{
+		base: base,
+	}
+}
+
+func (p *determinismVerifyingActionCacheWritePolicy) GetActionCacheWriteDecision(request *remoteworker.DesiredState_Executing, response *remoteexecution.ExecuteResponse) ActionCacheWriteDecision {
+	if decision := p.base.GetActionCacheWriteDecision(request, response); decision != ActionCacheWriteDecisionWrite {
+		return decision
+	}
+	return ActionCacheWriteDecisionVerifyDeterminism
+}
+
+type excludingActionCacheWritePolicy struct {
+	base                       ActionCacheWritePolicy
+	excludedActionMnemonics    map[string]struct{}
+	excludedPlatformProperties []*remoteexecution.Platform_Property
+}
+
+// NewExcludingActionCacheWritePolicy creates a decorator for
+// ActionCacheWritePolicy that prevents results of actions from being
+// stored in the Action Cache if they have one of the provided action
+// mnemonics, or if they have one of the provided platform properties.
+// Action mnemonics are obtained from the RequestMetadata that the
+// scheduler attaches to the auxiliary metadata of the action.
+func NewExcludingActionCacheWritePolicy(base ActionCacheWritePolicy, excludedActionMnemonics []string, excludedPlatformProperties []*remoteexecution.Platform_Property) ActionCacheWritePolicy {
+	p := &excludingActionCacheWritePolicy{
+		base:                       base,
+		excludedActionMnemonics:    make(map[string]struct{}, len(excludedActionMnemonics)),
+		excludedPlatformProperties: excludedPlatformProperties,
+	}
+	for _, actionMnemonic := range excludedActionMnemonics {
+		p.excludedActionMnemonics[actionMnemonic] = struct{}{}
+	}
+	return p
+}
+
+func (p *excludingActionCacheWritePolicy) isExcluded(request *remoteworker.DesiredState_Executing) bool {
+	for _, auxiliaryMetadata := range request.AuxiliaryMetadata {
+		var requestMetadata remoteexecution.RequestMetadata
+		if auxiliaryMetadata.UnmarshalTo(&requestMetadata) == nil {
+			if _, ok := p.excludedActionMnemonics[requestMetadata.ActionMnemonic]; ok {
+				return true
+			}
+		}
+	}
+	for _, property := range request.Action.GetPlatform().GetProperties() {
+		for _, excludedProperty := range p.excludedPlatformProperties {
+			if property.Name == excludedProperty.Name && property.Value == excludedProperty.Value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *excludingActionCacheWritePolicy) GetActionCacheWriteDecision(request *remoteworker.DesiredState_Executing, response *remoteexecution.ExecuteResponse) ActionCacheWriteDecision {
+	if p.isExcluded(request) {
+		return ActionCacheWriteDecisionSkip
+	}
+	return p.base.GetActionCacheWriteDecision(request, response)
+}
@@ -0,0 +1,87 @@
+package builder_test
+
+import (
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestActionCacheWritePolicy(t *testing.T) {
+	request := &remoteworker.DesiredState_Executing{
+		Action: &remoteexecution.Action{
+			Platform: &remoteexecution.Platform{
+				Properties: []*remoteexecution.Platform_Property{
+					{Name: "os", Value: "linux"},
+				},
+			},
+		},
+	}
+	succeeded := &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{},
+	}
+	failed := &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{ExitCode: 1},
+	}
+	timedOut := &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{},
+		Status: status.New(codes.DeadlineExceeded, "Build took more than ten seconds").Proto(),
+	}
+
+	t.Run("Successful", func(t *testing.T) {
+		policy := builder.SuccessfulActionCacheWritePolicy
+		require.Equal(t, builder.ActionCacheWriteDecisionWrite, policy.GetActionCacheWriteDecision(request, succeeded))
+		require.Equal(t, builder.ActionCacheWriteDecisionSkip, policy.GetActionCacheWriteDecision(request, failed))
+		require.Equal(t, builder.ActionCacheWriteDecisionSkip, policy.GetActionCacheWriteDecision(request, timedOut))
+	})
+
+	t.Run("Completed", func(t *testing.T) {
+		policy := builder.CompletedActionCacheWritePolicy
+		require.Equal(t, builder.ActionCacheWriteDecisionWrite, policy.GetActionCacheWriteDecision(request, succeeded))
+		require.Equal(t, builder.ActionCacheWriteDecisionWrite, policy.GetActionCacheWriteDecision(request, failed))
+		require.Equal(t, builder.ActionCacheWriteDecisionSkip, policy.GetActionCacheWriteDecision(request, timedOut))
+	})
+
+	t.Run("DeterminismVerifying", func(t *testing.T) {
+		policy := builder.NewDeterminismVerifyingActionCacheWritePolicy(builder.SuccessfulActionCacheWritePolicy)
+		require.Equal(t, builder.ActionCacheWriteDecisionVerifyDeterminism, policy.GetActionCacheWriteDecision(request, succeeded))
+		require.Equal(t, builder.ActionCacheWriteDecisionSkip, policy.GetActionCacheWriteDecision(request, failed))
+	})
+
+	t.Run("ExcludedPlatformProperty", func(t *testing.T) {
+		policy := builder.NewExcludingActionCacheWritePolicy(
+			builder.SuccessfulActionCacheWritePolicy,
+			nil,
+			[]*remoteexecution.Platform_Property{{Name: "os", Value: "linux"}})
+		require.Equal(t, builder.ActionCacheWriteDecisionSkip, policy.GetActionCacheWriteDecision(request, succeeded))
+
+		policy = builder.NewExcludingActionCacheWritePolicy(
+			builder.SuccessfulActionCacheWritePolicy,
+			nil,
+			[]*remoteexecution.Platform_Property{{Name: "os", Value: "windows"}})
+		require.Equal(t, builder.ActionCacheWriteDecisionWrite, policy.GetActionCacheWriteDecision(request, succeeded))
+	})
+
+	t.Run("ExcludedActionMnemonic", func(t *testing.T) {
+		policy := builder.NewExcludingActionCacheWritePolicy(
+			builder.SuccessfulActionCacheWritePolicy,
+			[]string{"TestRunner"},
+			nil)
+		require.Equal(t, builder.ActionCacheWriteDecisionWrite, policy.GetActionCacheWriteDecision(request, succeeded))
+
+		requestMetadata, err := anypb.New(&remoteexecution.RequestMetadata{
+			ActionMnemonic: "TestRunner",
+		})
+		require.NoError(t, err)
+		require.Equal(t, builder.ActionCacheWriteDecisionSkip, policy.GetActionCacheWriteDecision(&remoteworker.DesiredState_Executing{
+			Action:            request.Action,
+			AuxiliaryMetadata: []*anypb.Any{requestMetadata},
+		}, succeeded))
+	})
+}
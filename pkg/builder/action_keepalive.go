@@ -0,0 +1,118 @@
+package builder
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ActionKeepalivePlatformPropertyName is the name of the platform
+// property that may be used by actions to request that their execution
+// timeout is extended for as long as they signal that they are alive.
+// Its value must be a duration (e.g., "60s"), denoting the interval at
+// which the action creates the keepalive file.
+const ActionKeepalivePlatformPropertyName = "keepalive-interval"
+
+// keepaliveComponent is the name of the file in the input root that
+// actions need to create periodically to extend their execution
+// timeout.
+var keepaliveComponent = path.MustNewComponent(".bb_keepalive")
+
+// ActionKeepalive contains the options that need to be provided to
+// LocalBuildExecutor to let actions extend their execution timeout.
+type ActionKeepalive struct {
+	// Name of the environment variable that is set to the path of
+	// the keepalive file, relative to the action's working
+	// directory.
+	EnvironmentVariable string
+	// The maximum amount of time an action may run, regardless of
+	// whether it keeps signaling that it's alive.
+	MaximumExecutionTimeout time.Duration
+}
+
+// getKeepaliveInterval returns the interval at which an action has
+// requested to create the keepalive file. Zero is returned if the
+// action did not request its execution timeout to be extended.
+func getKeepaliveInterval(action *remoteexecution.Action) (time.Duration, error) {
+	for _, property := range action.GetPlatform().GetProperties() {
+		if property.Name == ActionKeepalivePlatformPropertyName {
+			interval, err := time.ParseDuration(property.Value)
+			if err != nil {
+				return 0, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid value for platform property %#v", ActionKeepalivePlatformPropertyName)
+			}
+			if interval <= 0 {
+				return 0, status.Errorf(codes.InvalidArgument, "Platform property %#v must be positive", ActionKeepalivePlatformPropertyName)
+			}
+			return interval, nil
+		}
+	}
+	return 0, nil
+}
+
+// getKeepalivePath returns the path of the keepalive file, relative to
+// the working directory of the action.
+func getKeepalivePath(workingDirectory string) string {
+	var b strings.Builder
+	for _, component := range strings.Split(workingDirectory, "/") {
+		if component != "" && component != "." {
+			b.WriteString("../")
+		}
+	}
+	b.WriteString(keepaliveComponent.String())
+	return b.String()
+}
+
+// keepaliveMonitor periodically checks whether an action has created
+// the keepalive file in its input root. Once the action's original
+// execution timeout has passed, the action is terminated as soon as
+// it fails to create the keepalive file within the interval.
+type keepaliveMonitor struct {
+	expired atomic.Bool
+	done    chan struct{}
+}
+
+func newKeepaliveMonitor(ctx context.Context, clock clock.Clock, inputRootDirectory BuildDirectory, executionTimeout, interval time.Duration, cancel context.CancelFunc) *keepaliveMonitor {
+	m := &keepaliveMonitor{
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(m.done)
+		var elapsed time.Duration
+		for {
+			timer, timerChannel := clock.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timerChannel:
+			}
+			elapsed += interval
+
+			// Remove the keepalive file, so that the action
+			// needs to create it again before the next check.
+			alive := inputRootDirectory.Remove(keepaliveComponent) == nil
+			if !alive && elapsed >= executionTimeout {
+				m.expired.Store(true)
+				cancel()
+				return
+			}
+		}
+	}()
+	return m
+}
+
+// wait for the keepalive monitor to terminate, returning whether the
+// action was terminated due to not signaling that it's alive.
+func (m *keepaliveMonitor) wait() bool {
+	<-m.done
+	return m.expired.Load()
+}
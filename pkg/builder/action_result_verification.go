@@ -0,0 +1,378 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/random"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ActionResultVerification contains options for checking that all
+// blobs referenced by the ActionResult of a build action are present
+// in the Content Addressable Storage, prior to reporting the action as
+// completed. This prevents clients from receiving results whose
+// outputs cannot be downloaded, due to uploads failing silently.
+//
+// A nil ActionResultVerification does not perform any checks.
+type ActionResultVerification struct {
+	// The number of times outputs are uploaded once more if blobs
+	// are found to be absent, before the action is reported as
+	// failed.
+	MaximumUploadRetries int
+
+	// If not nil, this callback is invoked prior to checking the
+	// presence of blobs. It should be set if outputs are written
+	// to storage in batches, as pending blobs are reported as
+	// absent otherwise.
+	StorageFlusher StorageFlusher
+
+	// The fraction of actions whose outputs are read back from the
+	// Content Addressable Storage after verifying their presence,
+	// and compared against the contents of the build directory.
+	// This catches corruption introduced while hashing or uploading
+	// outputs. RandomNumberGenerator is used to select the actions
+	// to check.
+	MaterializationCheckRatio float64
+	RandomNumberGenerator     random.ThreadSafeGenerator
+}
+
+// materializationCheckChunkSizeBytes is the size of the chunks in which
+// files are compared against blobs stored in the Content Addressable
+// Storage.
+const materializationCheckChunkSizeBytes = 64 * 1024
+
+// verify that all blobs referenced by an ActionResult are present. If
+// not, reupload() is called to upload the outputs once more.
+func (arv *ActionResultVerification) verify(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, digestFunction digest.Function, actionResult *remoteexecution.ActionResult, maximumMessageSizeBytes int, reupload func() error) error {
+	for retries := 0; ; retries++ {
+		if arv.StorageFlusher != nil {
+			if err := arv.StorageFlusher(ctx); err != nil {
+				if retries >= arv.MaximumUploadRetries {
+					return util.StatusWrap(err, "Failed to flush outputs")
+				}
+				// Blobs that could not be flushed are
+				// discarded. Upload them once more.
+				if err := reupload(); err != nil {
+					return util.StatusWrap(err, "Failed to upload outputs once more")
+				}
+				continue
+			}
+		}
+
+		missing, err := getMissingActionResultBlobs(ctx, contentAddressableStorage, digestFunction, actionResult, maximumMessageSizeBytes)
+		if err != nil {
+			return util.StatusWrap(err, "Failed to verify presence of outputs")
+		}
+		firstMissing, ok := missing.First()
+		if !ok {
+			return nil
+		}
+		if retries >= arv.MaximumUploadRetries {
+			return status.Errorf(codes.Internal, "%d blob(s) referenced by the action result are absent from the Content Addressable Storage after %d upload retries, including %#v", missing.Length(), retries, firstMissing.String())
+		}
+		if err := reupload(); err != nil {
+			return util.StatusWrap(err, "Failed to upload outputs once more")
+		}
+	}
+}
+
+// getMissingActionResultBlobs returns the digests of all blobs
+// referenced by an ActionResult that are absent from the Content
+// Addressable Storage, including files contained in output
+// directories.
+func getMissingActionResultBlobs(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, digestFunction digest.Function, actionResult *remoteexecution.ActionResult, maximumMessageSizeBytes int) (digest.Set, error) {
+	digests := digest.NewSetBuilder()
+	addDigest := func(d *remoteexecution.Digest) error {
+		if d == nil {
+			return nil
+		}
+		blobDigest, err := digestFunction.NewDigestFromProto(d)
+		if err != nil {
+			return err
+		}
+		digests.Add(blobDigest)
+		return nil
+	}
+
+	if err := addDigest(actionResult.StdoutDigest); err != nil {
+		return digest.EmptySet, util.StatusWrap(err, "Invalid standard output digest")
+	}
+	if err := addDigest(actionResult.StderrDigest); err != nil {
+		return digest.EmptySet, util.StatusWrap(err, "Invalid standard error digest")
+	}
+	for _, outputFile := range actionResult.OutputFiles {
+		if err := addDigest(outputFile.Digest); err != nil {
+			return digest.EmptySet, util.StatusWrapf(err, "Invalid digest for output file %#v", outputFile.Path)
+		}
+	}
+	for _, outputDirectory := range actionResult.OutputDirectories {
+		if err := addDigest(outputDirectory.RootDirectoryDigest); err != nil {
+			return digest.EmptySet, util.StatusWrapf(err, "Invalid root directory digest for output directory %#v", outputDirectory.Path)
+		}
+		treeDigest, err := digestFunction.NewDigestFromProto(outputDirectory.TreeDigest)
+		if err != nil {
+			return digest.EmptySet, util.StatusWrapf(err, "Invalid tree digest for output directory %#v", outputDirectory.Path)
+		}
+		digests.Add(treeDigest)
+
+		// Files contained in the output directory can only be
+		// enumerated if the Tree object is present. If it is
+		// absent, it is reported as missing by itself.
+		treeMessage, err := contentAddressableStorage.Get(ctx, treeDigest).ToProto(&remoteexecution.Tree{}, maximumMessageSizeBytes)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				continue
+			}
+			return digest.EmptySet, util.StatusWrapf(err, "Failed to obtain tree for output directory %#v", outputDirectory.Path)
+		}
+		tree := treeMessage.(*remoteexecution.Tree)
+		for _, directory := range append([]*remoteexecution.Directory{tree.Root}, tree.Children...) {
+			for _, file := range directory.GetFiles() {
+				if err := addDigest(file.Digest); err != nil {
+					return digest.EmptySet, util.StatusWrapf(err, "Invalid digest for file %#v in output directory %#v", file.Name, outputDirectory.Path)
+				}
+			}
+		}
+	}
+
+	missing, err := contentAddressableStorage.FindMissing(ctx, digests.Build())
+	if err != nil {
+		return digest.EmptySet, util.StatusWrap(err, "Failed to determine existence of blobs")
+	}
+	return missing, nil
+}
+
+// shouldCheckMaterialization returns whether the outputs of the
+// current action should be compared against the contents of the
+// Content Addressable Storage.
+func (arv *ActionResultVerification) shouldCheckMaterialization() bool {
+	return arv.MaterializationCheckRatio > 0 && arv.RandomNumberGenerator.Float64() < arv.MaterializationCheckRatio
+}
+
+// checkMaterialization compares the outputs referenced by an
+// ActionResult against the contents of the build directory. This is
+// equivalent to downloading the ActionResult into a scratch directory
+// and comparing it against the build directory, except that blobs are
+// streamed from the Content Addressable Storage, so that no additional
+// disk space is needed.
+func (arv *ActionResultVerification) checkMaterialization(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, digestFunction digest.Function, actionResult *remoteexecution.ActionResult, inputRootDirectory BuildDirectory, workingDirectory string, maximumMessageSizeBytes int) error {
+	c := materializationChecker{
+		context:                   ctx,
+		contentAddressableStorage: contentAddressableStorage,
+		digestFunction:            digestFunction,
+		inputRootDirectory:        inputRootDirectory,
+		workingDirectory:          workingDirectory,
+	}
+
+	for _, outputFile := range actionResult.OutputFiles {
+		if err := c.withParentDirectory(outputFile.Path, func(d BuildDirectory, name path.Component) error {
+			return c.compareFile(d, name, outputFile.Digest, outputFile.IsExecutable)
+		}); err != nil {
+			return util.StatusWrapf(err, "Output file %#v", outputFile.Path)
+		}
+	}
+	for _, outputSymlinks := range [][]*remoteexecution.OutputSymlink{
+		actionResult.OutputSymlinks,
+		actionResult.OutputFileSymlinks,
+		actionResult.OutputDirectorySymlinks,
+	} {
+		for _, outputSymlink := range outputSymlinks {
+			if err := c.withParentDirectory(outputSymlink.Path, func(d BuildDirectory, name path.Component) error {
+				return compareSymlink(d, name, outputSymlink.Target)
+			}); err != nil {
+				return util.StatusWrapf(err, "Output symbolic link %#v", outputSymlink.Path)
+			}
+		}
+	}
+	for _, outputDirectory := range actionResult.OutputDirectories {
+		treeDigest, err := digestFunction.NewDigestFromProto(outputDirectory.TreeDigest)
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid tree digest for output directory %#v", outputDirectory.Path)
+		}
+		treeMessage, err := contentAddressableStorage.Get(ctx, treeDigest).ToProto(&remoteexecution.Tree{}, maximumMessageSizeBytes)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to obtain tree for output directory %#v", outputDirectory.Path)
+		}
+		tree := treeMessage.(*remoteexecution.Tree)
+		children := make(map[digest.Digest]*remoteexecution.Directory, len(tree.Children))
+		for _, child := range tree.Children {
+			data, err := proto.MarshalOptions{Deterministic: true}.Marshal(child)
+			if err != nil {
+				return util.StatusWrapfWithCode(err, codes.Internal, "Failed to marshal child directory of output directory %#v", outputDirectory.Path)
+			}
+			digestGenerator := digestFunction.NewGenerator(int64(len(data)))
+			digestGenerator.Write(data)
+			children[digestGenerator.Sum()] = child
+		}
+
+		if err := c.withParentDirectory(outputDirectory.Path, func(d BuildDirectory, name path.Component) error {
+			return c.compareDirectory(d, name, tree.Root, children)
+		}); err != nil {
+			return util.StatusWrapf(err, "Output directory %#v", outputDirectory.Path)
+		}
+	}
+	return nil
+}
+
+// materializationChecker contains the state of a single call to
+// ActionResultVerification.checkMaterialization().
+type materializationChecker struct {
+	context                   context.Context
+	contentAddressableStorage blobstore.BlobAccess
+	digestFunction            digest.Function
+	inputRootDirectory        BuildDirectory
+	workingDirectory          string
+}
+
+// withParentDirectory resolves the path of an output relative to the
+// working directory, and invokes a callback against the directory
+// containing the output.
+func (c *materializationChecker) withParentDirectory(outputPath string, callback func(d BuildDirectory, name path.Component) error) error {
+	var resolvedPath outputNodePath
+	if err := path.Resolve(c.workingDirectory, path.NewRelativeScopeWalker(&resolvedPath)); err != nil {
+		return util.StatusWrap(err, "Invalid working directory")
+	}
+	if err := path.Resolve(outputPath, path.NewRelativeScopeWalker(&resolvedPath)); err != nil {
+		return util.StatusWrap(err, "Invalid path")
+	}
+	if len(resolvedPath.components) == 0 {
+		return status.Error(codes.InvalidArgument, "Path resolves to the input root directory")
+	}
+
+	d := c.inputRootDirectory
+	parentComponents := resolvedPath.components[:len(resolvedPath.components)-1]
+	for _, component := range parentComponents {
+		child, err := d.EnterBuildDirectory(component)
+		if d != c.inputRootDirectory {
+			d.Close()
+		}
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to enter directory %#v", component.String())
+		}
+		d = child
+	}
+	err := callback(d, resolvedPath.components[len(parentComponents)])
+	if d != c.inputRootDirectory {
+		d.Close()
+	}
+	return err
+}
+
+// compareFile compares a regular file in the build directory against a
+// blob stored in the Content Addressable Storage.
+func (c *materializationChecker) compareFile(d BuildDirectory, name path.Component, blobDigest *remoteexecution.Digest, isExecutable bool) error {
+	fileInfo, err := d.Lstat(name)
+	if err != nil {
+		return util.StatusWrap(err, "Failed to obtain file properties")
+	}
+	if fileInfo.Type() != filesystem.FileTypeRegularFile {
+		return status.Error(codes.Internal, "File in build directory is not a regular file")
+	}
+	if fileInfo.IsExecutable() != isExecutable {
+		return status.Errorf(codes.Internal, "File in build directory has executable bit %t, while %t was reported", fileInfo.IsExecutable(), isExecutable)
+	}
+
+	parsedDigest, err := c.digestFunction.NewDigestFromProto(blobDigest)
+	if err != nil {
+		return util.StatusWrap(err, "Invalid digest")
+	}
+	f, err := d.OpenRead(name)
+	if err != nil {
+		return util.StatusWrap(err, "Failed to open file")
+	}
+	defer f.Close()
+	r := c.contentAddressableStorage.Get(c.context, parsedDigest).ToReader()
+	defer r.Close()
+
+	localReader := io.NewSectionReader(f, 0, math.MaxInt64)
+	localChunk := make([]byte, materializationCheckChunkSizeBytes)
+	remoteChunk := make([]byte, materializationCheckChunkSizeBytes)
+	for offset := int64(0); ; offset += materializationCheckChunkSizeBytes {
+		localSizeBytes, localErr := io.ReadFull(localReader, localChunk)
+		if localErr != nil && localErr != io.EOF && localErr != io.ErrUnexpectedEOF {
+			return util.StatusWrapWithCode(localErr, codes.Internal, "Failed to read file")
+		}
+		remoteSizeBytes, remoteErr := io.ReadFull(r, remoteChunk)
+		if remoteErr != nil && remoteErr != io.EOF && remoteErr != io.ErrUnexpectedEOF {
+			return util.StatusWrap(remoteErr, "Failed to read blob")
+		}
+		if localSizeBytes != remoteSizeBytes || !bytes.Equal(localChunk[:localSizeBytes], remoteChunk[:remoteSizeBytes]) {
+			return status.Errorf(codes.Internal, "File in build directory differs from blob %#v at or after offset %d", parsedDigest.String(), offset)
+		}
+		if localErr != nil {
+			return nil
+		}
+	}
+}
+
+// compareSymlink compares the target of a symbolic link in the build
+// directory against the target that was reported.
+func compareSymlink(d BuildDirectory, name path.Component, target string) error {
+	actualTarget, err := d.Readlink(name)
+	if err != nil {
+		return util.StatusWrap(err, "Failed to read symbolic link target")
+	}
+	if actualTarget != target {
+		return status.Errorf(codes.Internal, "Symbolic link in build directory has target %#v, while %#v was reported", actualTarget, target)
+	}
+	return nil
+}
+
+// compareDirectory recursively compares a directory in the build
+// directory against a Directory message contained in a Tree.
+func (c *materializationChecker) compareDirectory(parent BuildDirectory, name path.Component, directory *remoteexecution.Directory, children map[digest.Digest]*remoteexecution.Directory) error {
+	d, err := parent.EnterBuildDirectory(name)
+	if err != nil {
+		return util.StatusWrap(err, "Failed to enter directory")
+	}
+	defer d.Close()
+
+	for _, file := range directory.Files {
+		childName, ok := path.NewComponent(file.Name)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "File %#v has an invalid name", file.Name)
+		}
+		if err := c.compareFile(d, childName, file.Digest, file.IsExecutable); err != nil {
+			return util.StatusWrapf(err, "File %#v", file.Name)
+		}
+	}
+	for _, symlink := range directory.Symlinks {
+		childName, ok := path.NewComponent(symlink.Name)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "Symbolic link %#v has an invalid name", symlink.Name)
+		}
+		if err := compareSymlink(d, childName, symlink.Target); err != nil {
+			return util.StatusWrapf(err, "Symbolic link %#v", symlink.Name)
+		}
+	}
+	for _, subdirectory := range directory.Directories {
+		childName, ok := path.NewComponent(subdirectory.Name)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "Directory %#v has an invalid name", subdirectory.Name)
+		}
+		childDigest, err := c.digestFunction.NewDigestFromProto(subdirectory.Digest)
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid digest for directory %#v", subdirectory.Name)
+		}
+		child, ok := children[childDigest]
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "Tree does not contain directory %#v with digest %#v", subdirectory.Name, childDigest.String())
+		}
+		if err := c.compareDirectory(d, childName, child, children); err != nil {
+			return util.StatusWrapf(err, "Directory %#v", subdirectory.Name)
+		}
+	}
+	return nil
+}
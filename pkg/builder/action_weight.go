@@ -0,0 +1,38 @@
+package builder
+
+import (
+	"strconv"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ActionWeightPlatformPropertyName is the name of the platform
+// property that may be used by actions to indicate that they consume
+// more than one execution slot of a worker (e.g., because they are
+// linker invocations that use many CPU cores). Its value is a positive
+// decimal number.
+const ActionWeightPlatformPropertyName = "resources:cores"
+
+// GetActionWeight returns the number of execution slots that are
+// consumed by an action, based on the value of the "resources:cores"
+// platform property. Actions that don't set this property consume a
+// single execution slot.
+func GetActionWeight(platform *remoteexecution.Platform) (int, error) {
+	for _, property := range platform.GetProperties() {
+		if property.Name == ActionWeightPlatformPropertyName {
+			weight, err := strconv.ParseUint(property.Value, 10, 16)
+			if err != nil {
+				return 0, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid value for platform property %#v", ActionWeightPlatformPropertyName)
+			}
+			if weight == 0 {
+				return 0, status.Errorf(codes.InvalidArgument, "Platform property %#v must be positive", ActionWeightPlatformPropertyName)
+			}
+			return int(weight), nil
+		}
+	}
+	return 1, nil
+}
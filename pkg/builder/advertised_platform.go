@@ -0,0 +1,44 @@
+package builder
+
+import (
+	"sync"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+)
+
+// AdvertisedPlatform holds the platform properties and size class that
+// one or more BuildClients announce to the scheduler. These may be
+// changed at runtime (e.g., through the WorkerAdmin gRPC service),
+// causing BuildClients to be moved to another platform queue the next
+// time they are idle.
+type AdvertisedPlatform struct {
+	lock      sync.Mutex
+	platform  *remoteexecution.Platform
+	sizeClass uint32
+}
+
+// NewAdvertisedPlatform creates an AdvertisedPlatform that initially
+// announces the provided platform properties and size class.
+func NewAdvertisedPlatform(platform *remoteexecution.Platform, sizeClass uint32) *AdvertisedPlatform {
+	return &AdvertisedPlatform{
+		platform:  platform,
+		sizeClass: sizeClass,
+	}
+}
+
+// Get the platform properties and size class that should currently be
+// announced to the scheduler.
+func (ap *AdvertisedPlatform) Get() (*remoteexecution.Platform, uint32) {
+	ap.lock.Lock()
+	defer ap.lock.Unlock()
+	return ap.platform, ap.sizeClass
+}
+
+// Set the platform properties and size class that should be announced
+// to the scheduler.
+func (ap *AdvertisedPlatform) Set(platform *remoteexecution.Platform, sizeClass uint32) {
+	ap.lock.Lock()
+	defer ap.lock.Unlock()
+	ap.platform = platform
+	ap.sizeClass = sizeClass
+}
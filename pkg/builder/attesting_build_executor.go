@@ -0,0 +1,85 @@
+package builder
+
+import (
+	"context"
+	"crypto/ed25519"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/attestation"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+type attestingBuildExecutor struct {
+	BuildExecutor
+	workerID       map[string]string
+	isolationLevel string
+	policyHash     []byte
+	privateKey     ed25519.PrivateKey
+}
+
+// NewAttestingBuildExecutor creates a decorator for BuildExecutor that
+// attaches an ExecutionAttestation message to the auxiliary metadata
+// of the ActionResult. This message describes the provenance of the
+// result, so that readers of the Action Cache can decide whether to
+// trust it.
+//
+// If a private key is provided, the attestation is signed, allowing
+// readers to verify that it was generated by a trusted worker. This
+// decorator should be placed directly below CachingBuildExecutor, so
+// that no other metadata is added to the ActionResult after the
+// attestation has been computed.
+func NewAttestingBuildExecutor(buildExecutor BuildExecutor, workerID map[string]string, isolationLevel string, policyHash []byte, privateKey ed25519.PrivateKey) BuildExecutor {
+	return &attestingBuildExecutor{
+		BuildExecutor:  buildExecutor,
+		workerID:       workerID,
+		isolationLevel: isolationLevel,
+		policyHash:     policyHash,
+		privateKey:     privateKey,
+	}
+}
+
+func (be *attestingBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	response := be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+
+	actionResult, err := proto.MarshalOptions{Deterministic: true}.Marshal(response.Result)
+	if err != nil {
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to marshal action result"))
+		return response
+	}
+	actionResultDigestGenerator := digestFunction.NewGenerator(int64(len(actionResult)))
+	if _, err := actionResultDigestGenerator.Write(actionResult); err != nil {
+		panic(err)
+	}
+
+	statement, err := proto.MarshalOptions{Deterministic: true}.Marshal(&attestation.ExecutionAttestation_Statement{
+		ActionDigest:       request.ActionDigest,
+		ActionResultDigest: actionResultDigestGenerator.Sum().GetProto(),
+		WorkerId:           be.workerID,
+		IsolationLevel:     be.isolationLevel,
+		PolicyHash:         be.policyHash,
+	})
+	if err != nil {
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to marshal execution attestation statement"))
+		return response
+	}
+	executionAttestation := &attestation.ExecutionAttestation{
+		Statement: statement,
+	}
+	if be.privateKey != nil {
+		executionAttestation.Signature = ed25519.Sign(be.privateKey, statement)
+	}
+
+	if executionAttestationAny, err := anypb.New(executionAttestation); err == nil {
+		response.Result.ExecutionMetadata.AuxiliaryMetadata = append(response.Result.ExecutionMetadata.AuxiliaryMetadata, executionAttestationAny)
+	} else {
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to marshal execution attestation"))
+	}
+	return response
+}
@@ -0,0 +1,78 @@
+package builder_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/attestation"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAttestingBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	privateKey := ed25519.NewKeyFromSeed([]byte("0123456789abcdef0123456789abcdef"))
+	buildExecutor := builder.NewAttestingBuildExecutor(
+		baseBuildExecutor,
+		map[string]string{"hostname": "worker1"},
+		"sandboxed",
+		[]byte{0x12, 0x34},
+		privateKey)
+
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5)
+	request := &remoteworker.DesiredState_Executing{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "d41d8cd98f00b204e9800998ecf8427e",
+			SizeBytes: 123,
+		},
+		Action: &remoteexecution.Action{},
+	}
+	executionStateUpdates := make(chan *remoteworker.CurrentState_Executing, 3)
+
+	baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(&remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			ExitCode:          1,
+			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+		},
+	})
+
+	response := buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+	auxiliaryMetadata := response.Result.ExecutionMetadata.AuxiliaryMetadata
+	require.Len(t, auxiliaryMetadata, 1)
+	var executionAttestation attestation.ExecutionAttestation
+	require.NoError(t, auxiliaryMetadata[0].UnmarshalTo(&executionAttestation))
+
+	// The signature should be valid for the statement.
+	require.True(t, ed25519.Verify(privateKey.Public().(ed25519.PublicKey), executionAttestation.Statement, executionAttestation.Signature))
+
+	// The statement should describe the worker and reference the
+	// ActionResult without the attestation attached.
+	var statement attestation.ExecutionAttestation_Statement
+	require.NoError(t, proto.Unmarshal(executionAttestation.Statement, &statement))
+	testutil.RequireEqualProto(t, &attestation.ExecutionAttestation_Statement{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "d41d8cd98f00b204e9800998ecf8427e",
+			SizeBytes: 123,
+		},
+		ActionResultDigest: &remoteexecution.Digest{
+			Hash:      "2d76fe79d814743137653238683d2d9a",
+			SizeBytes: 4,
+		},
+		WorkerId:       map[string]string{"hostname": "worker1"},
+		IsolationLevel: "sandboxed",
+		PolicyHash:     []byte{0x12, 0x34},
+	}, &statement)
+}
@@ -17,6 +17,7 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -26,12 +27,21 @@ import (
 // build actions.
 type BuildClient struct {
 	// Constant fields.
-	scheduler           remoteworker.OperationQueueClient
-	buildExecutor       BuildExecutor
-	filePool            filesystem.FilePool
-	clock               clock.Clock
-	instanceNamePrefix  digest.InstanceName
-	instanceNamePatcher digest.InstanceNamePatcher
+	scheduler               remoteworker.OperationQueueClient
+	buildExecutor           BuildExecutor
+	filePool                filesystem.FilePool
+	clock                   clock.Clock
+	instanceNamePrefix      digest.InstanceName
+	instanceNamePatcher     digest.InstanceNamePatcher
+	instanceNameMapping     *InstanceNameMapping
+	inputRootPrefetcher     InputRootPrefetcher
+	idlePrefetcher          IdlePrefetcher
+	advertisedPlatform      *AdvertisedPlatform
+	gracefulShutdownTimeout time.Duration
+	workerResourcesReporter WorkerResourcesReporter
+	terminationTimeReporter TerminationTimeReporter
+	concurrencyLimit        *ConcurrencyLimit
+	memoryAdmission         *MemoryAdmission
 
 	// Mutable fields that are always set.
 	request                         remoteworker.SynchronizeRequest
@@ -41,24 +51,96 @@ type BuildClient struct {
 	// Mutable fields that are only set when executing an action.
 	executionCancellation func()
 	executionUpdates      <-chan *remoteworker.CurrentState_Executing
+	executionInterrupted  bool
+
+	// Mutable fields that are only set when an action was declined
+	// due to a lack of memory.
+	memoryReleased <-chan struct{}
+
+	// Mutable fields that are only set when termination of the
+	// worker has been initiated while executing an action.
+	gracefulShutdownDeadline *time.Time
+
+	// Mutable fields that are only set when prefetching the input
+	// root of a tentatively assigned action.
+	prefetchActionDigest *remoteexecution.Digest
+	prefetchCancellation func()
+
+	// Mutable fields that are only set when prefetching files into
+	// local caches while idle.
+	idlePrefetchCancellation func()
 }
 
 // NewBuildClient creates a new BuildClient instance that is set to the
 // initial state (i.e., being idle).
-func NewBuildClient(scheduler remoteworker.OperationQueueClient, buildExecutor BuildExecutor, filePool filesystem.FilePool, clock clock.Clock, workerID map[string]string, instanceNamePrefix digest.InstanceName, platform *remoteexecution.Platform, sizeClass uint32) *BuildClient {
+//
+// If an InputRootPrefetcher is provided, the BuildClient requests that
+// the scheduler announces the action that is likely to be executed
+// next. The input root of this action is then prefetched while the
+// current action is still executing.
+//
+// If an IdlePrefetcher is provided, it is invoked every time the
+// worker becomes idle, so that local caches can be warmed up without
+// affecting the execution of actions. Prefetching is interrupted as
+// soon as an action is assigned to the worker.
+//
+// The platform properties and size class that are announced to the
+// scheduler are obtained from an AdvertisedPlatform. Changes to it are
+// applied once the scheduler no longer thinks the worker is executing
+// an action, so that the worker can be moved to another platform queue
+// without interrupting any work.
+//
+// When termination of the worker is initiated, the BuildClient stops
+// requesting work from the scheduler, while letting the action that is
+// currently executing run to completion. If a graceful shutdown
+// timeout is provided, the action is interrupted if it does not
+// complete within that amount of time. Its failure is reported to the
+// scheduler with code UNAVAILABLE, so that clients may retry it.
+//
+// If a WorkerResourcesReporter is provided, the resources available to
+// the worker are reported to the scheduler as part of every
+// synchronization, allowing the scheduler to refrain from assigning
+// tasks to the worker while it is constrained. Similarly, if a
+// TerminationTimeReporter is provided, the time at which the worker is
+// expected to terminate is reported, allowing the scheduler to refrain
+// from assigning tasks that are not expected to complete in time.
+//
+// If a ConcurrencyLimit is provided, the number of execution slots of
+// the runner that are available is reported, allowing the scheduler to
+// only assign tasks whose weight can be accommodated.
+//
+// If a MemoryAdmission is provided, actions are declined if their
+// memory requirements can't be satisfied, causing the scheduler to
+// requeue them. The worker does not request any further work until
+// memory held by other actions is released.
+//
+// If an InstanceNameMapping is provided, the instance names of actions
+// are translated prior to accessing storage.
+func NewBuildClient(scheduler remoteworker.OperationQueueClient, buildExecutor BuildExecutor, filePool filesystem.FilePool, clock clock.Clock, workerID map[string]string, instanceNamePrefix digest.InstanceName, instanceNameMapping *InstanceNameMapping, advertisedPlatform *AdvertisedPlatform, inputRootPrefetcher InputRootPrefetcher, idlePrefetcher IdlePrefetcher, gracefulShutdownTimeout time.Duration, workerResourcesReporter WorkerResourcesReporter, terminationTimeReporter TerminationTimeReporter, concurrencyLimit *ConcurrencyLimit, memoryAdmission *MemoryAdmission) *BuildClient {
+	platform, sizeClass := advertisedPlatform.Get()
 	return &BuildClient{
-		scheduler:           scheduler,
-		buildExecutor:       buildExecutor,
-		filePool:            filePool,
-		clock:               clock,
-		instanceNamePrefix:  instanceNamePrefix,
-		instanceNamePatcher: digest.NewInstanceNamePatcher(digest.EmptyInstanceName, instanceNamePrefix),
+		scheduler:               scheduler,
+		buildExecutor:           buildExecutor,
+		filePool:                filePool,
+		clock:                   clock,
+		instanceNamePrefix:      instanceNamePrefix,
+		instanceNamePatcher:     digest.NewInstanceNamePatcher(digest.EmptyInstanceName, instanceNamePrefix),
+		instanceNameMapping:     instanceNameMapping,
+		inputRootPrefetcher:     inputRootPrefetcher,
+		idlePrefetcher:          idlePrefetcher,
+		advertisedPlatform:      advertisedPlatform,
+		gracefulShutdownTimeout: gracefulShutdownTimeout,
+		workerResourcesReporter: workerResourcesReporter,
+		terminationTimeReporter: terminationTimeReporter,
+		concurrencyLimit:        concurrencyLimit,
+		memoryAdmission:         memoryAdmission,
 
 		request: remoteworker.SynchronizeRequest{
-			WorkerId:           workerID,
-			InstanceNamePrefix: instanceNamePrefix.String(),
-			Platform:           platform,
-			SizeClass:          sizeClass,
+			WorkerId:                   workerID,
+			InstanceNamePrefix:         instanceNamePrefix.String(),
+			Platform:                   platform,
+			SizeClass:                  sizeClass,
+			AcceptTentativeAssignments: inputRootPrefetcher != nil,
 			CurrentState: &remoteworker.CurrentState{
 				WorkerState: &remoteworker.CurrentState_Idle{
 					Idle: &emptypb.Empty{},
@@ -69,18 +151,63 @@ func NewBuildClient(scheduler remoteworker.OperationQueueClient, buildExecutor B
 	}
 }
 
-func (bc *BuildClient) startExecution(executionRequest *remoteworker.DesiredState_Executing) error {
+func (bc *BuildClient) getDigestFunction(executionRequest *remoteworker.DesiredState_Executing) (digest.Function, error) {
 	instanceNameSuffix, err := digest.NewInstanceName(executionRequest.InstanceNameSuffix)
 	if err != nil {
-		return util.StatusWrapf(err, "Invalid instance name suffix %#v", executionRequest.InstanceNameSuffix)
+		return digest.Function{}, util.StatusWrapf(err, "Invalid instance name suffix %#v", executionRequest.InstanceNameSuffix)
 	}
-	digestFunction, err := bc.instanceNamePatcher.PatchInstanceName(instanceNameSuffix).
+	instanceName := bc.instanceNamePatcher.PatchInstanceName(instanceNameSuffix)
+	return bc.instanceNameMapping.MapInstanceName(instanceName).
 		GetDigestFunction(executionRequest.DigestFunction, 0)
+}
+
+func (bc *BuildClient) startExecution(executionRequest *remoteworker.DesiredState_Executing) error {
+	digestFunction, err := bc.getDigestFunction(executionRequest)
 	if err != nil {
 		return err
 	}
 
 	bc.stopExecution()
+	bc.stopIdlePrefetching()
+
+	// Let the build executor know the size class under which the
+	// action was assigned, as this may differ from the size class
+	// that is currently advertised.
+	executionRequest.SizeClass = bc.request.SizeClass
+
+	// Decline the action if running it would cause the memory
+	// requirements of all actions to exceed the amount of memory
+	// that is available.
+	var admittedMemoryBytes uint64
+	if bc.memoryAdmission != nil {
+		memoryBytes, memoryReleased, err := bc.memoryAdmission.admit(executionRequest.Action.GetPlatform())
+		if err != nil {
+			executeResponse := NewDefaultExecuteResponse(executionRequest)
+			attachErrorToExecuteResponse(executeResponse, err)
+			bc.request.CurrentState.WorkerState = &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: executionRequest.ActionDigest,
+					ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+						Completed: executeResponse,
+					},
+				},
+			}
+			return nil
+		}
+		if memoryReleased != nil {
+			bc.memoryReleased = memoryReleased
+			bc.request.CurrentState.WorkerState = &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: executionRequest.ActionDigest,
+					ExecutionState: &remoteworker.CurrentState_Executing_Rejected{
+						Rejected: &emptypb.Empty{},
+					},
+				},
+			}
+			return nil
+		}
+		admittedMemoryBytes = memoryBytes
+	}
 
 	// Spawn the execution of the build action.
 	var ctx context.Context
@@ -98,6 +225,9 @@ func (bc *BuildClient) startExecution(executionRequest *remoteworker.DesiredStat
 			digestFunction,
 			executionRequest,
 			updates)
+		if bc.memoryAdmission != nil {
+			bc.memoryAdmission.release(admittedMemoryBytes)
+		}
 		updates <- &remoteworker.CurrentState_Executing{
 			ActionDigest: executionRequest.ActionDigest,
 			ExecutionState: &remoteworker.CurrentState_Executing_Completed{
@@ -131,6 +261,7 @@ func (bc *BuildClient) stopExecution() {
 		}
 		bc.executionCancellation = nil
 		bc.executionUpdates = nil
+		bc.executionInterrupted = false
 	}
 
 	bc.request.CurrentState.WorkerState = &remoteworker.CurrentState_Idle{
@@ -138,9 +269,84 @@ func (bc *BuildClient) stopExecution() {
 	}
 }
 
+// startPrefetching starts prefetching the input root of an action that
+// the scheduler has tentatively assigned to the worker. Prefetching is
+// performed on a best-effort basis, meaning that errors are only
+// logged.
+func (bc *BuildClient) startPrefetching(executionRequest *remoteworker.DesiredState_Executing) {
+	if proto.Equal(bc.prefetchActionDigest, executionRequest.ActionDigest) {
+		// Already prefetching this action.
+		return
+	}
+	if bc.prefetchCancellation != nil {
+		bc.prefetchCancellation()
+		bc.prefetchCancellation = nil
+	}
+	bc.prefetchActionDigest = executionRequest.ActionDigest
+
+	digestFunction, err := bc.getDigestFunction(executionRequest)
+	if err != nil {
+		log.Print("Failed to prefetch input root of tentatively assigned action: ", err)
+		return
+	}
+	inputRootDigest, err := digestFunction.NewDigestFromProto(executionRequest.Action.GetInputRootDigest())
+	if err != nil {
+		log.Print("Failed to prefetch input root of tentatively assigned action: Invalid input root digest: ", err)
+		return
+	}
+
+	var ctx context.Context
+	ctx, bc.prefetchCancellation = context.WithCancel(
+		otel.NewContextWithW3CTraceContext(
+			context.Background(),
+			executionRequest.W3CTraceContext))
+	go func() {
+		if err := bc.inputRootPrefetcher.PrefetchInputRoot(ctx, inputRootDigest); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to prefetch input root %#v of tentatively assigned action: %s", inputRootDigest.String(), err)
+		}
+	}()
+}
+
+// startIdlePrefetching starts warming up local caches while the worker
+// is idle. Similar to startPrefetching(), errors are only logged.
+func (bc *BuildClient) startIdlePrefetching(ctx context.Context) {
+	if bc.idlePrefetchCancellation != nil {
+		// Already prefetched during this idle period.
+		return
+	}
+
+	ctx, bc.idlePrefetchCancellation = context.WithCancel(ctx)
+	go func() {
+		if err := bc.idlePrefetcher.PrefetchWhileIdle(ctx); err != nil && ctx.Err() == nil {
+			log.Print("Failed to prefetch files while idle: ", err)
+		}
+	}()
+}
+
+func (bc *BuildClient) stopIdlePrefetching() {
+	if bc.idlePrefetchCancellation != nil {
+		bc.idlePrefetchCancellation()
+		bc.idlePrefetchCancellation = nil
+	}
+}
+
 func (bc *BuildClient) applyExecutionUpdate(update *remoteworker.CurrentState_Executing) {
 	if update != nil {
 		// New update received.
+		if completed, ok := update.ExecutionState.(*remoteworker.CurrentState_Executing_Completed); ok && bc.executionInterrupted && completed.Completed.Status != nil {
+			// The action failed, because it got interrupted
+			// due to the worker shutting down. Report this
+			// in such a way that clients retry the action.
+			var responseCopy remoteexecution.ExecuteResponse
+			proto.Merge(&responseCopy, completed.Completed)
+			responseCopy.Status = status.New(codes.Unavailable, "Execution was interrupted, as the worker is shutting down").Proto()
+			update = &remoteworker.CurrentState_Executing{
+				ActionDigest: update.ActionDigest,
+				ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+					Completed: &responseCopy,
+				},
+			}
+		}
 		bc.request.CurrentState.WorkerState = &remoteworker.CurrentState_Executing_{
 			Executing: update,
 		}
@@ -149,6 +355,7 @@ func (bc *BuildClient) applyExecutionUpdate(update *remoteworker.CurrentState_Ex
 		bc.executionCancellation()
 		bc.executionCancellation = nil
 		bc.executionUpdates = nil
+		bc.executionInterrupted = false
 	}
 }
 
@@ -195,14 +402,58 @@ func (bc *BuildClient) Run(ctx context.Context) (bool, error) {
 		}
 	}
 
+	// If an action was declined due to a lack of memory, wait for
+	// other actions to release memory before requesting more work.
+	// Otherwise the scheduler may hand out the same action again.
+	if _, ok := bc.request.CurrentState.WorkerState.(*remoteworker.CurrentState_Idle); ok && bc.memoryReleased != nil {
+		select {
+		case <-bc.memoryReleased:
+		case <-ctx.Done():
+		}
+		bc.memoryReleased = nil
+	}
+
 	// When executing an action, see if there are any updates on the
 	// execution state.
 	if bc.executionCancellation != nil {
-		timer, timerChannel := bc.clock.NewTimer(bc.nextSynchronizationAt.Sub(bc.clock.Now()))
+		waitUntil := bc.nextSynchronizationAt
+		var terminationStarted <-chan struct{}
+		if bc.gracefulShutdownTimeout > 0 {
+			if ctx.Err() == nil {
+				// Wake up as soon as termination is
+				// initiated, so that the graceful
+				// shutdown timeout is applied precisely.
+				terminationStarted = ctx.Done()
+			} else {
+				// Termination has been initiated.
+				// Interrupt the action if it doesn't
+				// complete in time.
+				now := bc.clock.Now()
+				if bc.gracefulShutdownDeadline == nil {
+					deadline := now.Add(bc.gracefulShutdownTimeout)
+					bc.gracefulShutdownDeadline = &deadline
+				}
+				if now.Before(*bc.gracefulShutdownDeadline) {
+					if bc.gracefulShutdownDeadline.Before(waitUntil) {
+						waitUntil = *bc.gracefulShutdownDeadline
+					}
+				} else if !bc.executionInterrupted {
+					bc.executionCancellation()
+					bc.executionInterrupted = true
+				}
+			}
+		}
+
+		timer, timerChannel := bc.clock.NewTimer(waitUntil.Sub(bc.clock.Now()))
 		select {
 		case <-timerChannel:
 			// No meaningful updates. Send the last update
 			// once again.
+		case <-terminationStarted:
+			// Termination has been initiated. Send the last
+			// update once again, so that the next iteration
+			// applies the graceful shutdown timeout.
+			timer.Stop()
 		case update := <-bc.executionUpdates:
 			// One or more execution updates available. Send
 			// a new update with the latest state,
@@ -222,6 +473,8 @@ func (bc *BuildClient) Run(ctx context.Context) (bool, error) {
 	// prefer_being_able set to false (potentially blocking) or true
 	// (non-blocking).
 	currentStateIsExecuting := false
+	platform, sizeClass := bc.advertisedPlatform.Get()
+	platformChanged := sizeClass != bc.request.SizeClass || !proto.Equal(platform, bc.request.Platform)
 	switch workerState := bc.request.CurrentState.WorkerState.(type) {
 	case *remoteworker.CurrentState_Idle:
 		// Even though we are idle, the scheduler may think we
@@ -229,13 +482,32 @@ func (bc *BuildClient) Run(ctx context.Context) (bool, error) {
 		// readiness checks. Forcefully switch to idle, so that
 		// we can still do this before picking up more work.
 		bc.request.PreferBeingIdle = bc.schedulerMayThinkExecutingUntil != nil
+
+		// Only announce different platform properties or a
+		// different size class if the scheduler doesn't think
+		// we're executing an action that was obtained from the
+		// platform queue we're currently part of.
+		if platformChanged && !bc.request.PreferBeingIdle {
+			bc.request.Platform = platform
+			bc.request.SizeClass = sizeClass
+		}
 	case *remoteworker.CurrentState_Executing_:
 		if updateCompleted, ok := workerState.Executing.ExecutionState.(*remoteworker.CurrentState_Executing_Completed); ok {
 			// In case execution failed with a serious
 			// error, request that the worker gets a brief
 			// amount of idle time, so that we can do some
 			// health checks prior to picking up more work.
-			bc.request.PreferBeingIdle = status.ErrorProto(updateCompleted.Completed.Status) != nil
+			//
+			// Also do this if the platform properties or
+			// size class have changed, as we shouldn't pick
+			// up any more work from the current platform
+			// queue.
+			bc.request.PreferBeingIdle = status.ErrorProto(updateCompleted.Completed.Status) != nil || platformChanged
+		} else if _, ok := workerState.Executing.ExecutionState.(*remoteworker.CurrentState_Executing_Rejected); ok {
+			// The action was declined. Request that the
+			// scheduler requeues it, instead of handing out
+			// another action right away.
+			bc.request.PreferBeingIdle = true
 		} else {
 			currentStateIsExecuting = true
 			bc.request.PreferBeingIdle = false
@@ -252,6 +524,31 @@ func (bc *BuildClient) Run(ctx context.Context) (bool, error) {
 	if ctx.Err() != nil {
 		bc.request.PreferBeingIdle = true
 		ctx = context.Background()
+	} else if !currentStateIsExecuting && !bc.request.PreferBeingIdle && bc.idlePrefetcher != nil {
+		// We're about to wait for work to be assigned. Use
+		// this time to warm up local caches.
+		bc.startIdlePrefetching(ctx)
+	}
+
+	// Report the resources that are currently available to the
+	// worker. Failing to obtain these should not prevent the worker
+	// from executing actions, so simply omit them.
+	if bc.workerResourcesReporter != nil {
+		resources, err := bc.workerResourcesReporter.GetWorkerResources()
+		if err != nil {
+			log.Print("Failed to obtain worker resources: ", err)
+		}
+		bc.request.Resources = resources
+	}
+	if bc.terminationTimeReporter != nil {
+		expectedTerminationTime, err := bc.terminationTimeReporter.GetExpectedTerminationTime()
+		if err != nil {
+			log.Print("Failed to obtain expected termination time: ", err)
+		}
+		bc.request.ExpectedTerminationTime = expectedTerminationTime
+	}
+	if bc.concurrencyLimit != nil {
+		bc.request.ExecutionSlots = bc.concurrencyLimit.getExecutionSlots()
 	}
 
 	// Inform scheduler of current worker state, potentially
@@ -298,6 +595,9 @@ func (bc *BuildClient) Run(ctx context.Context) (bool, error) {
 
 	// Scheduler has instructed to continue as is.
 	if currentStateIsExecuting {
+		if tentativeAssignment := response.TentativeAssignment; tentativeAssignment != nil && bc.inputRootPrefetcher != nil {
+			bc.startPrefetching(tentativeAssignment)
+		}
 		bc.touchSchedulerMayThinkExecuting()
 		return false, nil
 	}
@@ -308,12 +608,23 @@ func (bc *BuildClient) Run(ctx context.Context) (bool, error) {
 // LaunchWorkerThread launches a single routine that uses a build client
 // to repeatedly synchronizes against the scheduler, requesting a task
 // to execute.
-func LaunchWorkerThread(group program.Group, buildClient *BuildClient, workerName string) {
+//
+// If a ConcurrencyLimit is provided, the routine stops synchronizing
+// against the scheduler while it is idle and the index of the thread
+// exceeds the limit. Synchronization resumes once the limit is raised.
+func LaunchWorkerThread(group program.Group, buildClient *BuildClient, workerName string, concurrencyLimit *ConcurrencyLimit, threadIndex int) {
 	group.Go(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
 		generator := random.NewFastSingleThreadedGenerator()
+		mayTerminate := true
 		for {
+			if mayTerminate && concurrencyLimit != nil && !concurrencyLimit.WaitUntilThreadEnabled(ctx, threadIndex) {
+				log.Printf("Worker %s: terminating", workerName)
+				return nil
+			}
+
 			terminationStartedBeforeRun := ctx.Err() != nil
-			if mayTerminate, err := buildClient.Run(ctx); mayTerminate && ctx.Err() != nil {
+			var err error
+			if mayTerminate, err = buildClient.Run(ctx); mayTerminate && ctx.Err() != nil {
 				log.Printf("Worker %s: terminating", workerName)
 				return nil
 			} else if err != nil {
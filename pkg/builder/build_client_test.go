@@ -8,12 +8,15 @@ import (
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-remote-execution/internal/mock"
 	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/testutil"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
@@ -35,7 +38,7 @@ func TestBuildClient(t *testing.T) {
 			{Name: "os", Value: "linux"},
 		},
 	}
-	bc := builder.NewBuildClient(operationQueueClient, buildExecutor, filePool, clock, workerID, digest.MustNewInstanceName("prefix"), platform, 4)
+	bc := builder.NewBuildClient(operationQueueClient, buildExecutor, filePool, clock, workerID, digest.MustNewInstanceName("prefix"), nil, builder.NewAdvertisedPlatform(platform, 4), nil, nil, 0, nil, nil, nil, nil)
 
 	// If synchronizing against the scheduler doesn't yield any
 	// action to run, the client should remain in the idle state.
@@ -375,3 +378,633 @@ func TestBuildClient(t *testing.T) {
 	require.Equal(t, true, mayTerminate)
 	require.NoError(t, err)
 }
+
+func TestBuildClientTentativeAssignment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	operationQueueClient := mock.NewMockOperationQueueClient(ctrl)
+	buildExecutor := mock.NewMockBuildExecutor(ctrl)
+	filePool := mock.NewMockFilePool(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	inputRootPrefetcher := mock.NewMockInputRootPrefetcher(ctrl)
+	workerID := map[string]string{"hostname": "example.com"}
+	digestFunction := digest.MustNewFunction("prefix/suffix", remoteexecution.DigestFunction_SHA1)
+	bc := builder.NewBuildClient(operationQueueClient, buildExecutor, filePool, clock, workerID, digest.MustNewInstanceName("prefix"), nil, builder.NewAdvertisedPlatform(nil, 0), inputRootPrefetcher, nil, 0, nil, nil, nil, nil)
+
+	// Let the scheduler return an action to execute. The build
+	// client should announce that it accepts tentative assignments.
+	buildExecutor.EXPECT().CheckReadiness(context.Background())
+	desiredStateExecuting1 := &remoteworker.DesiredState_Executing{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+		Action:             &remoteexecution.Action{},
+		InstanceNameSuffix: "suffix",
+		DigestFunction:     remoteexecution.DigestFunction_SHA1,
+	}
+	operationQueueClient.EXPECT().Synchronize(context.Background(), testutil.EqProto(t, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "prefix",
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+		AcceptTentativeAssignments: true,
+	})).Return(&remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1020},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Executing_{
+				Executing: desiredStateExecuting1,
+			},
+		},
+	}, nil)
+	executionReleased := make(chan struct{})
+	defer close(executionReleased)
+	buildExecutor.EXPECT().Execute(
+		gomock.Any(),
+		filePool,
+		nil,
+		digestFunction,
+		desiredStateExecuting1,
+		gomock.Any(),
+	).DoAndReturn(func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+		executionStateUpdates <- &remoteworker.CurrentState_Executing{
+			ActionDigest: request.ActionDigest,
+			ExecutionState: &remoteworker.CurrentState_Executing_Running{
+				Running: &emptypb.Empty{},
+			},
+		}
+		<-executionReleased
+		return &remoteexecution.ExecuteResponse{}
+	})
+	mayTerminate, err := bc.Run(context.Background())
+	require.Equal(t, false, mayTerminate)
+	require.NoError(t, err)
+
+	// While the action is running, the scheduler may announce the
+	// action that is likely to be executed next. This should cause
+	// its input root to be prefetched.
+	desiredStateExecuting2 := &remoteworker.DesiredState_Executing{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "8c7bdf20235417b8e3bfa695407e1ff0b43e8223",
+			SizeBytes: 123,
+		},
+		Action: &remoteexecution.Action{
+			InputRootDigest: &remoteexecution.Digest{
+				Hash:      "11483c42a98269d01673aa3157836d2882aad5de",
+				SizeBytes: 456,
+			},
+		},
+		InstanceNameSuffix: "suffix",
+		DigestFunction:     remoteexecution.DigestFunction_SHA1,
+	}
+	currentStateRunning := &remoteworker.CurrentState{
+		WorkerState: &remoteworker.CurrentState_Executing_{
+			Executing: &remoteworker.CurrentState_Executing{
+				ActionDigest: desiredStateExecuting1.ActionDigest,
+				ExecutionState: &remoteworker.CurrentState_Executing_Running{
+					Running: &emptypb.Empty{},
+				},
+			},
+		},
+	}
+	clock.EXPECT().Now().Return(time.Unix(1015, 0)).Times(2)
+	timer1 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(5*time.Second).Return(timer1, nil)
+	timer1.EXPECT().Stop().Return(true)
+	operationQueueClient.EXPECT().Synchronize(context.Background(), testutil.EqProto(t, &remoteworker.SynchronizeRequest{
+		WorkerId:                   workerID,
+		InstanceNamePrefix:         "prefix",
+		CurrentState:               currentStateRunning,
+		AcceptTentativeAssignments: true,
+	})).Return(&remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1025},
+		TentativeAssignment:   desiredStateExecuting2,
+	}, nil)
+	prefetched := make(chan struct{})
+	inputRootPrefetcher.EXPECT().PrefetchInputRoot(gomock.Any(), digest.MustNewDigest("prefix/suffix", remoteexecution.DigestFunction_SHA1, "11483c42a98269d01673aa3157836d2882aad5de", 456)).
+		DoAndReturn(func(ctx context.Context, inputRootDigest digest.Digest) error {
+			close(prefetched)
+			return nil
+		})
+	mayTerminate, err = bc.Run(context.Background())
+	require.Equal(t, false, mayTerminate)
+	require.NoError(t, err)
+	<-prefetched
+
+	// Announcing the same action once again should not cause it to
+	// be prefetched twice.
+	clock.EXPECT().Now().Return(time.Unix(1025, 0))
+	timer2 := mock.NewMockTimer(ctrl)
+	timerChannel2 := make(chan time.Time, 1)
+	timerChannel2 <- time.Unix(1025, 0)
+	clock.EXPECT().NewTimer(time.Duration(0)).Return(timer2, timerChannel2)
+	operationQueueClient.EXPECT().Synchronize(context.Background(), testutil.EqProto(t, &remoteworker.SynchronizeRequest{
+		WorkerId:                   workerID,
+		InstanceNamePrefix:         "prefix",
+		CurrentState:               currentStateRunning,
+		AcceptTentativeAssignments: true,
+	})).Return(&remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1035},
+		TentativeAssignment:   desiredStateExecuting2,
+	}, nil)
+	mayTerminate, err = bc.Run(context.Background())
+	require.Equal(t, false, mayTerminate)
+	require.NoError(t, err)
+}
+
+func TestBuildClientPlatformChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	operationQueueClient := mock.NewMockOperationQueueClient(ctrl)
+	buildExecutor := mock.NewMockBuildExecutor(ctrl)
+	filePool := mock.NewMockFilePool(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	workerID := map[string]string{"hostname": "example.com"}
+	digestFunction := digest.MustNewFunction("prefix/suffix", remoteexecution.DigestFunction_SHA1)
+	platform1 := &remoteexecution.Platform{
+		Properties: []*remoteexecution.Platform_Property{
+			{Name: "os", Value: "linux"},
+		},
+	}
+	advertisedPlatform := builder.NewAdvertisedPlatform(platform1, 4)
+	bc := builder.NewBuildClient(operationQueueClient, buildExecutor, filePool, clock, workerID, digest.MustNewInstanceName("prefix"), nil, advertisedPlatform, nil, nil, 0, nil, nil, nil, nil)
+
+	// Let the scheduler return an action to execute. The size class
+	// that was announced should be provided to the BuildExecutor.
+	buildExecutor.EXPECT().CheckReadiness(context.Background())
+	operationQueueClient.EXPECT().Synchronize(context.Background(), testutil.EqProto(t, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "prefix",
+		Platform:           platform1,
+		SizeClass:          4,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	})).Return(&remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1020},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Executing_{
+				Executing: &remoteworker.DesiredState_Executing{
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						SizeBytes: 123,
+					},
+					InstanceNameSuffix: "suffix",
+					DigestFunction:     remoteexecution.DigestFunction_SHA1,
+				},
+			},
+		},
+	}, nil)
+	buildExecutor.EXPECT().Execute(
+		gomock.Any(),
+		filePool,
+		nil,
+		digestFunction,
+		testutil.EqProto(t, &remoteworker.DesiredState_Executing{
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+				SizeBytes: 123,
+			},
+			InstanceNameSuffix: "suffix",
+			DigestFunction:     remoteexecution.DigestFunction_SHA1,
+			SizeClass:          4,
+		}),
+		gomock.Any(),
+	).Return(&remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{},
+	})
+	mayTerminate, err := bc.Run(context.Background())
+	require.Equal(t, false, mayTerminate)
+	require.NoError(t, err)
+
+	// Change the platform properties and size class while the
+	// action is executing. When reporting completion of the
+	// action, the old platform properties and size class should
+	// still be announced. The worker should prefer being idle, so
+	// that it doesn't pick up any more work from the current
+	// platform queue.
+	platform2 := &remoteexecution.Platform{
+		Properties: []*remoteexecution.Platform_Property{
+			{Name: "cpu", Value: "aarch64"},
+			{Name: "os", Value: "linux"},
+		},
+	}
+	advertisedPlatform.Set(platform2, 8)
+
+	clock.EXPECT().Now().Return(time.Unix(1015, 0)).Times(2)
+	timer := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(5*time.Second).Return(timer, nil)
+	timer.EXPECT().Stop().Return(true)
+	operationQueueClient.EXPECT().Synchronize(context.Background(), testutil.EqProto(t, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "prefix",
+		Platform:           platform1,
+		SizeClass:          4,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						SizeBytes: 123,
+					},
+					ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+						Completed: &remoteexecution.ExecuteResponse{
+							Result: &remoteexecution.ActionResult{},
+						},
+					},
+				},
+			},
+		},
+		PreferBeingIdle: true,
+	})).Return(&remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1025},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, nil)
+	mayTerminate, err = bc.Run(context.Background())
+	require.Equal(t, true, mayTerminate)
+	require.NoError(t, err)
+
+	// Now that the scheduler no longer thinks the worker is
+	// executing, the new platform properties and size class should
+	// be announced.
+	buildExecutor.EXPECT().CheckReadiness(context.Background())
+	operationQueueClient.EXPECT().Synchronize(context.Background(), testutil.EqProto(t, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "prefix",
+		Platform:           platform2,
+		SizeClass:          8,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	})).Return(&remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1030},
+	}, nil)
+	mayTerminate, err = bc.Run(context.Background())
+	require.Equal(t, true, mayTerminate)
+	require.NoError(t, err)
+}
+
+func TestBuildClientGracefulShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	operationQueueClient := mock.NewMockOperationQueueClient(ctrl)
+	buildExecutor := mock.NewMockBuildExecutor(ctrl)
+	filePool := mock.NewMockFilePool(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	workerID := map[string]string{"hostname": "example.com"}
+	bc := builder.NewBuildClient(operationQueueClient, buildExecutor, filePool, clock, workerID, digest.MustNewInstanceName("prefix"), nil, builder.NewAdvertisedPlatform(nil, 0), nil, nil, 10*time.Second, nil, nil, nil, nil)
+
+	// Let the scheduler return an action to execute. Let the
+	// action run until it gets interrupted.
+	buildExecutor.EXPECT().CheckReadiness(context.Background())
+	actionDigest := &remoteexecution.Digest{
+		Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+		SizeBytes: 123,
+	}
+	operationQueueClient.EXPECT().Synchronize(context.Background(), testutil.EqProto(t, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "prefix",
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	})).Return(&remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1020},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Executing_{
+				Executing: &remoteworker.DesiredState_Executing{
+					ActionDigest:       actionDigest,
+					InstanceNameSuffix: "suffix",
+					DigestFunction:     remoteexecution.DigestFunction_SHA1,
+				},
+			},
+		},
+	}, nil)
+	buildExecutor.EXPECT().Execute(gomock.Any(), filePool, nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+			<-ctx.Done()
+			return &remoteexecution.ExecuteResponse{
+				Result: &remoteexecution.ActionResult{},
+				Status: status.New(codes.Canceled, "Context canceled").Proto(),
+			}
+		})
+	mayTerminate, err := bc.Run(context.Background())
+	require.Equal(t, false, mayTerminate)
+	require.NoError(t, err)
+
+	// Initiate termination of the worker. This should cause the
+	// graceful shutdown timeout to start. The worker should prefer
+	// being idle, so that it doesn't get any new work.
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	clock.EXPECT().Now().Return(time.Unix(1010, 0)).Times(3)
+	timer1 := mock.NewMockTimer(ctrl)
+	timerChannel1 := make(chan time.Time, 1)
+	timerChannel1 <- time.Unix(1020, 0)
+	clock.EXPECT().NewTimer(10*time.Second).Return(timer1, timerChannel1)
+	operationQueueClient.EXPECT().Synchronize(context.Background(), testutil.EqProto(t, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "prefix",
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: actionDigest,
+					ExecutionState: &remoteworker.CurrentState_Executing_Started{
+						Started: &emptypb.Empty{},
+					},
+				},
+			},
+		},
+		PreferBeingIdle: true,
+	})).Return(&remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1030},
+	}, nil)
+	mayTerminate, err = bc.Run(canceledCtx)
+	require.Equal(t, false, mayTerminate)
+	require.NoError(t, err)
+
+	// Once the graceful shutdown timeout has been reached, the
+	// action should be interrupted. Its failure should be reported
+	// with code UNAVAILABLE, so that clients retry it.
+	clock.EXPECT().Now().Return(time.Unix(1021, 0)).Times(4)
+	timer2 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(9*time.Second).Return(timer2, nil)
+	timer2.EXPECT().Stop().Return(true)
+	operationQueueClient.EXPECT().Synchronize(context.Background(), testutil.EqProto(t, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "prefix",
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: actionDigest,
+					ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+						Completed: &remoteexecution.ExecuteResponse{
+							Result: &remoteexecution.ActionResult{},
+							Status: status.New(codes.Unavailable, "Execution was interrupted, as the worker is shutting down").Proto(),
+						},
+					},
+				},
+			},
+		},
+		PreferBeingIdle: true,
+	})).Return(&remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1021},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, nil)
+	mayTerminate, err = bc.Run(canceledCtx)
+	require.Equal(t, true, mayTerminate)
+	require.NoError(t, err)
+
+	// As the scheduler no longer thinks the worker is executing,
+	// it may terminate.
+	mayTerminate, err = bc.Run(canceledCtx)
+	require.Equal(t, true, mayTerminate)
+	require.NoError(t, err)
+}
+
+func TestBuildClientWorkerResources(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	operationQueueClient := mock.NewMockOperationQueueClient(ctrl)
+	buildExecutor := mock.NewMockBuildExecutor(ctrl)
+	filePool := mock.NewMockFilePool(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	workerID := map[string]string{"hostname": "example.com"}
+	workerResourcesReporter := mock.NewMockWorkerResourcesReporter(ctrl)
+	bc := builder.NewBuildClient(operationQueueClient, buildExecutor, filePool, clock, workerID, digest.MustNewInstanceName("prefix"), nil, builder.NewAdvertisedPlatform(nil, 0), nil, nil, 0, workerResourcesReporter, nil, nil, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		// Resources reported by the WorkerResourcesReporter
+		// should be forwarded to the scheduler.
+		resources := &remoteworker.WorkerResources{
+			BuildDirectoryFreeBytes: 10 * 1024 * 1024 * 1024,
+			AvailableMemoryBytes:    4 * 1024 * 1024 * 1024,
+			LoadAverage:             1.5,
+		}
+		buildExecutor.EXPECT().CheckReadiness(context.Background())
+		workerResourcesReporter.EXPECT().GetWorkerResources().Return(resources, nil)
+		operationQueueClient.EXPECT().Synchronize(context.Background(), testutil.EqProto(t, &remoteworker.SynchronizeRequest{
+			WorkerId:           workerID,
+			InstanceNamePrefix: "prefix",
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Idle{
+					Idle: &emptypb.Empty{},
+				},
+			},
+			Resources: resources,
+		})).Return(&remoteworker.SynchronizeResponse{
+			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1010},
+		}, nil)
+		mayTerminate, err := bc.Run(context.Background())
+		require.Equal(t, true, mayTerminate)
+		require.NoError(t, err)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		// Failing to obtain the resources should not prevent
+		// the worker from synchronizing. The resources that
+		// were reported previously should not be sent, as they
+		// may be stale.
+		buildExecutor.EXPECT().CheckReadiness(context.Background())
+		workerResourcesReporter.EXPECT().GetWorkerResources().
+			Return(nil, status.Error(codes.Internal, "Failed to read load average"))
+		operationQueueClient.EXPECT().Synchronize(context.Background(), testutil.EqProto(t, &remoteworker.SynchronizeRequest{
+			WorkerId:           workerID,
+			InstanceNamePrefix: "prefix",
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Idle{
+					Idle: &emptypb.Empty{},
+				},
+			},
+		})).Return(&remoteworker.SynchronizeResponse{
+			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1020},
+		}, nil)
+		mayTerminate, err := bc.Run(context.Background())
+		require.Equal(t, true, mayTerminate)
+		require.NoError(t, err)
+	})
+}
+
+func TestBuildClientMemoryAdmission(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	operationQueueClient := mock.NewMockOperationQueueClient(ctrl)
+	buildExecutor := mock.NewMockBuildExecutor(ctrl)
+	filePool := mock.NewMockFilePool(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).Times(2)
+	workerID1 := map[string]string{"thread": "1"}
+	workerID2 := map[string]string{"thread": "2"}
+	digestFunction := digest.MustNewFunction("prefix", remoteexecution.DigestFunction_SHA1)
+	memoryAdmission := builder.NewMemoryAdmission(1000, 600)
+	bc1 := builder.NewBuildClient(operationQueueClient, buildExecutor, filePool, clock, workerID1, digest.MustNewInstanceName("prefix"), nil, builder.NewAdvertisedPlatform(nil, 0), nil, nil, 0, nil, nil, nil, memoryAdmission)
+	bc2 := builder.NewBuildClient(operationQueueClient, buildExecutor, filePool, clock, workerID2, digest.MustNewInstanceName("prefix"), nil, builder.NewAdvertisedPlatform(nil, 0), nil, nil, 0, nil, nil, nil, memoryAdmission)
+
+	// Let the first worker thread start executing an action that
+	// requires the default amount of memory.
+	desiredStateExecuting1 := &remoteworker.DesiredState_Executing{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+		Action: &remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+				SizeBytes: 456,
+			},
+		},
+		DigestFunction: remoteexecution.DigestFunction_SHA1,
+	}
+	buildExecutor.EXPECT().CheckReadiness(context.Background())
+	operationQueueClient.EXPECT().Synchronize(context.Background(), testutil.EqProto(t, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID1,
+		InstanceNamePrefix: "prefix",
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	})).Return(&remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1010},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Executing_{
+				Executing: desiredStateExecuting1,
+			},
+		},
+	}, nil)
+	execution1Done := make(chan struct{})
+	buildExecutor.EXPECT().Execute(
+		gomock.Any(),
+		filePool,
+		nil,
+		digestFunction,
+		desiredStateExecuting1,
+		gomock.Any(),
+	).DoAndReturn(func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+		<-execution1Done
+		return &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{},
+		}
+	})
+	mayTerminate, err := bc1.Run(context.Background())
+	require.Equal(t, false, mayTerminate)
+	require.NoError(t, err)
+
+	// The second worker thread should decline an action that
+	// requires more memory than is left.
+	desiredStateExecuting2 := &remoteworker.DesiredState_Executing{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "8c7bdf20235417b8e3bfa695407e1ff0b43e8223",
+			SizeBytes: 123,
+		},
+		Action: &remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "11483c42a98269d01673aa3157836d2882aad5de",
+				SizeBytes: 456,
+			},
+			Platform: &remoteexecution.Platform{
+				Properties: []*remoteexecution.Platform_Property{
+					{Name: "resources:memory", Value: "500"},
+				},
+			},
+		},
+		DigestFunction: remoteexecution.DigestFunction_SHA1,
+	}
+	buildExecutor.EXPECT().CheckReadiness(context.Background())
+	operationQueueClient.EXPECT().Synchronize(context.Background(), testutil.EqProto(t, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID2,
+		InstanceNamePrefix: "prefix",
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	})).Return(&remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1010},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Executing_{
+				Executing: desiredStateExecuting2,
+			},
+		},
+	}, nil)
+	mayTerminate, err = bc2.Run(context.Background())
+	require.Equal(t, false, mayTerminate)
+	require.NoError(t, err)
+
+	// The rejection should be reported to the scheduler, while
+	// requesting not to receive any other work.
+	operationQueueClient.EXPECT().Synchronize(context.Background(), testutil.EqProto(t, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID2,
+		InstanceNamePrefix: "prefix",
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: desiredStateExecuting2.ActionDigest,
+					ExecutionState: &remoteworker.CurrentState_Executing_Rejected{
+						Rejected: &emptypb.Empty{},
+					},
+				},
+			},
+		},
+		PreferBeingIdle: true,
+	})).Return(&remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1010},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, nil)
+	mayTerminate, err = bc2.Run(context.Background())
+	require.Equal(t, true, mayTerminate)
+	require.NoError(t, err)
+
+	// The second worker thread should only request more work once
+	// the first action completes and releases its memory.
+	var released bool
+	buildExecutor.EXPECT().CheckReadiness(context.Background())
+	operationQueueClient.EXPECT().Synchronize(context.Background(), testutil.EqProto(t, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID2,
+		InstanceNamePrefix: "prefix",
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	})).DoAndReturn(func(ctx context.Context, request *remoteworker.SynchronizeRequest, opts ...grpc.CallOption) (*remoteworker.SynchronizeResponse, error) {
+		require.True(t, released)
+		return &remoteworker.SynchronizeResponse{
+			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1020},
+		}, nil
+	})
+	runDone := make(chan struct{})
+	go func() {
+		mayTerminate, err := bc2.Run(context.Background())
+		require.Equal(t, true, mayTerminate)
+		require.NoError(t, err)
+		close(runDone)
+	}()
+	released = true
+	close(execution1Done)
+	<-runDone
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/buildbarn/bb-storage/pkg/filesystem"
 	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
 	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 // BuildDirectory is a directory that may be used for the purpose of
@@ -23,6 +25,7 @@ type BuildDirectory interface {
 
 	// Methods inherited from filesystem.Directory.
 	Mknod(name path.Component, perm os.FileMode, deviceNumber filesystem.DeviceNumber) error
+	OpenRead(name path.Component) (filesystem.FileReader, error)
 	Remove(name path.Component) error
 	RemoveAll(name path.Component) error
 
@@ -36,7 +39,11 @@ type BuildDirectory interface {
 	// errors. Implementations of BuildDirectory are free to let
 	// this be a no-op, with the disadvantage that they cannot apply
 	// resource limits or provide rich I/O error messages.
-	InstallHooks(filePool re_filesystem.FilePool, errorLogger util.ErrorLogger)
+	//
+	// The context and digest function are those of the build
+	// action that is about to be executed. Implementations may use
+	// these to upload output files while the build action runs.
+	InstallHooks(ctx context.Context, digestFunction digest.Function, filePool re_filesystem.FilePool, errorLogger util.ErrorLogger)
 
 	// Recursively merges the contents of a Directory stored in the
 	// Content Addressable Storage into a local directory. If this
@@ -45,3 +52,13 @@ type BuildDirectory interface {
 	// provided ErrorLogger may be used to return an error.
 	MergeDirectoryContents(ctx context.Context, errorLogger util.ErrorLogger, digest digest.Digest, monitor access.UnreadDirectoryMonitor) error
 }
+
+// ResourceUsageReportingBuildDirectory is an optional interface that
+// may be implemented by BuildDirectory to report statistics on the
+// resources consumed by the build action, such as the amount of disk
+// space used. LocalBuildExecutor attaches these statistics to the
+// auxiliary metadata of the action's execution metadata. Implementations
+// may return nil if no statistics have been gathered.
+type ResourceUsageReportingBuildDirectory interface {
+	GetResourceUsage() (*anypb.Any, error)
+}
@@ -0,0 +1,217 @@
+package builder
+
+import (
+	"context"
+	"io"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/actionevent"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/genproto/googleapis/devtools/build/v1"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type buildEventPublishingBuildExecutor struct {
+	BuildExecutor
+	client                 build.PublishBuildEventClient
+	clock                  clock.Clock
+	errorLogger            util.ErrorLogger
+	workerID               map[string]string
+	instanceNamePatcher    digest.InstanceNamePatcher
+	projectID              string
+	acknowledgementTimeout time.Duration
+}
+
+// NewBuildEventPublishingBuildExecutor creates a decorator for
+// BuildExecutor that publishes events to a Build Event Service (BES)
+// when actions start and finish executing. These events contain an
+// ActionEvent message, stating the action digest, the ID of the worker,
+// the exit code and the duration of execution.
+//
+// Events are published with the invocation ID and correlated
+// invocations ID that the client provided as part of RequestMetadata.
+// This causes them to be grouped together with the events published by
+// the client (e.g., Bazel), allowing remote execution telemetry to be
+// shown next to them. Actions for which no invocation ID is known are
+// not published. Every action uses a separate stream of events having
+// the WORKER component type.
+//
+// Failures to publish events are reported through the ErrorLogger, but
+// do not cause the execution of actions to fail.
+func NewBuildEventPublishingBuildExecutor(base BuildExecutor, client build.PublishBuildEventClient, clock clock.Clock, errorLogger util.ErrorLogger, workerID map[string]string, instanceNamePatcher digest.InstanceNamePatcher, projectID string, acknowledgementTimeout time.Duration) BuildExecutor {
+	return &buildEventPublishingBuildExecutor{
+		BuildExecutor:          base,
+		client:                 client,
+		clock:                  clock,
+		errorLogger:            errorLogger,
+		workerID:               workerID,
+		instanceNamePatcher:    instanceNamePatcher,
+		projectID:              projectID,
+		acknowledgementTimeout: acknowledgementTimeout,
+	}
+}
+
+// buildEventStream keeps track of the state of the stream of events
+// that is published for a single action.
+type buildEventStream struct {
+	executor       *buildEventPublishingBuildExecutor
+	stream         build.PublishBuildEvent_PublishBuildToolEventStreamClient
+	streamID       *build.StreamId
+	sequenceNumber int64
+}
+
+func (s *buildEventStream) send(event *build.BuildEvent) error {
+	s.sequenceNumber++
+	return s.stream.Send(&build.PublishBuildToolEventStreamRequest{
+		OrderedBuildEvent: &build.OrderedBuildEvent{
+			StreamId:       s.streamID,
+			SequenceNumber: s.sequenceNumber,
+			Event:          event,
+		},
+		ProjectId: s.executor.projectID,
+	})
+}
+
+func (s *buildEventStream) sendActionEvent(eventTime time.Time, actionEvent *actionevent.ActionEvent) error {
+	details, err := anypb.New(actionEvent)
+	if err != nil {
+		return util.StatusWrap(err, "Failed to marshal action event")
+	}
+	return s.send(&build.BuildEvent{
+		EventTime: timestamppb.New(eventTime),
+		Event: &build.BuildEvent_BuildExecutionEvent{
+			BuildExecutionEvent: details,
+		},
+	})
+}
+
+// finish the stream of events, and wait for the server to acknowledge
+// all events that were sent.
+func (s *buildEventStream) finish(cancel context.CancelFunc) error {
+	if err := s.send(&build.BuildEvent{
+		EventTime: timestamppb.New(s.executor.clock.Now()),
+		Event: &build.BuildEvent_ComponentStreamFinished{
+			ComponentStreamFinished: &build.BuildEvent_BuildComponentStreamFinished{
+				Type: build.BuildEvent_BuildComponentStreamFinished_FINISHED,
+			},
+		},
+	}); err != nil {
+		return util.StatusWrap(err, "Failed to send component stream finished event")
+	}
+	if err := s.stream.CloseSend(); err != nil {
+		return util.StatusWrap(err, "Failed to close stream")
+	}
+
+	// Don't let a server that is slow to respond cause the
+	// worker to hang indefinitely.
+	timer, timerChannel := s.executor.clock.NewTimer(s.executor.acknowledgementTimeout)
+	defer timer.Stop()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-timerChannel:
+			cancel()
+		case <-done:
+		}
+	}()
+
+	for {
+		if _, err := s.stream.Recv(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return util.StatusWrap(err, "Failed to receive acknowledgements")
+		}
+	}
+}
+
+func (be *buildEventPublishingBuildExecutor) Execute(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	// Obtain the invocation ID from the RequestMetadata that the
+	// scheduler attached to the action.
+	var requestMetadata *remoteexecution.RequestMetadata
+	for _, auxiliaryMetadata := range request.AuxiliaryMetadata {
+		var candidate remoteexecution.RequestMetadata
+		if auxiliaryMetadata.UnmarshalTo(&candidate) == nil {
+			requestMetadata = &candidate
+			break
+		}
+	}
+	if requestMetadata.GetToolInvocationId() == "" {
+		return be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+	}
+
+	buildID := requestMetadata.CorrelatedInvocationsId
+	if buildID == "" {
+		buildID = requestMetadata.ToolInvocationId
+	}
+	newActionEvent := func() *actionevent.ActionEvent {
+		return &actionevent.ActionEvent{
+			ActionDigest:    request.ActionDigest,
+			InstanceName:    be.instanceNamePatcher.PatchInstanceName(digestFunction.GetInstanceName()).String(),
+			DigestFunction:  digestFunction.GetEnumValue(),
+			WorkerId:        be.workerID,
+			RequestMetadata: requestMetadata,
+		}
+	}
+
+	// Publish an event indicating that execution has started. The
+	// stream is kept open during execution, so that the event is
+	// delivered without delay.
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var s *buildEventStream
+	startTime := be.clock.Now()
+	if stream, err := be.client.PublishBuildToolEventStream(streamCtx); err != nil {
+		be.errorLogger.Log(util.StatusWrap(err, "Failed to create build event stream"))
+	} else {
+		s = &buildEventStream{
+			executor: be,
+			stream:   stream,
+			streamID: &build.StreamId{
+				BuildId:      buildID,
+				InvocationId: requestMetadata.ToolInvocationId,
+				Component:    build.StreamId_WORKER,
+			},
+		}
+		actionEvent := newActionEvent()
+		actionEvent.Event = &actionevent.ActionEvent_Started{
+			Started: &emptypb.Empty{},
+		}
+		if err := s.sendActionEvent(startTime, actionEvent); err != nil {
+			be.errorLogger.Log(util.StatusWrap(err, "Failed to publish action started event"))
+			s = nil
+		}
+	}
+
+	response := be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+
+	if s != nil {
+		completionTime := be.clock.Now()
+		actionEvent := newActionEvent()
+		actionEvent.Event = &actionevent.ActionEvent_Finished_{
+			Finished: &actionevent.ActionEvent_Finished{
+				ExitCode:          response.Result.GetExitCode(),
+				Status:            response.Status,
+				ExecutionMetadata: response.Result.GetExecutionMetadata(),
+				Duration:          durationpb.New(completionTime.Sub(startTime)),
+			},
+		}
+		if err := s.sendActionEvent(completionTime, actionEvent); err != nil {
+			be.errorLogger.Log(util.StatusWrap(err, "Failed to publish action finished event"))
+		} else if err := s.finish(cancel); err != nil {
+			be.errorLogger.Log(util.StatusWrap(err, "Failed to publish build events"))
+		}
+	}
+	return response
+}
@@ -0,0 +1,202 @@
+package builder_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/actionevent"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/genproto/googleapis/devtools/build/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestBuildEventPublishingBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	conn := mock.NewMockClientConnInterface(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	errorLogger := mock.NewMockErrorLogger(ctrl)
+	buildExecutor := builder.NewBuildEventPublishingBuildExecutor(
+		baseBuildExecutor,
+		build.NewPublishBuildEventClient(conn),
+		clock,
+		errorLogger,
+		map[string]string{"hostname": "worker123"},
+		digest.NewInstanceNamePatcher(digest.EmptyInstanceName, digest.MustNewInstanceName("prefix")),
+		"my-project",
+		time.Minute)
+
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	digestFunction := digest.MustNewFunction("main", remoteexecution.DigestFunction_SHA256)
+	actionDigest := &remoteexecution.Digest{
+		Hash:      "5ee5d5a4c0a1a0b0b2a4e3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2",
+		SizeBytes: 123,
+	}
+	requestMetadata := &remoteexecution.RequestMetadata{
+		ToolInvocationId:        "d7d8ad2b-9e1c-4c4f-8f8c-7d5b3e2a1f00",
+		CorrelatedInvocationsId: "0a4b6b3c-7e2d-4f1a-9b8c-6d5e4f3a2b1c",
+		ActionMnemonic:          "CppCompile",
+	}
+	requestMetadataAny, err := anypb.New(requestMetadata)
+	require.NoError(t, err)
+	response := &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			ExitCode: 1,
+			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+				Worker: "worker123",
+			},
+		},
+	}
+
+	t.Run("NoInvocationID", func(t *testing.T) {
+		// Actions for which no invocation ID is known can't be
+		// correlated, so no events should be published.
+		request := &remoteworker.DesiredState_Executing{
+			ActionDigest: actionDigest,
+		}
+		executionStateUpdates := make(chan *remoteworker.CurrentState_Executing, 1)
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(response)
+
+		testutil.RequireEqualProto(
+			t,
+			response,
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		request := &remoteworker.DesiredState_Executing{
+			ActionDigest:      actionDigest,
+			AuxiliaryMetadata: []*anypb.Any{requestMetadataAny},
+		}
+		executionStateUpdates := make(chan *remoteworker.CurrentState_Executing, 1)
+		streamID := &build.StreamId{
+			BuildId:      "0a4b6b3c-7e2d-4f1a-9b8c-6d5e4f3a2b1c",
+			InvocationId: "d7d8ad2b-9e1c-4c4f-8f8c-7d5b3e2a1f00",
+			Component:    build.StreamId_WORKER,
+		}
+		requireBuildExecutionEvent := func(sequenceNumber int64, eventTime int64, expectedActionEvent *actionevent.ActionEvent) func(interface{}) error {
+			return func(m interface{}) error {
+				request := m.(*build.PublishBuildToolEventStreamRequest)
+				require.Equal(t, "my-project", request.ProjectId)
+				orderedBuildEvent := request.OrderedBuildEvent
+				testutil.RequireEqualProto(t, streamID, orderedBuildEvent.StreamId)
+				require.Equal(t, sequenceNumber, orderedBuildEvent.SequenceNumber)
+				testutil.RequireEqualProto(t, &timestamppb.Timestamp{Seconds: eventTime}, orderedBuildEvent.Event.EventTime)
+				var actionEvent actionevent.ActionEvent
+				require.NoError(t, orderedBuildEvent.Event.GetBuildExecutionEvent().UnmarshalTo(&actionEvent))
+				testutil.RequireEqualProto(t, expectedActionEvent, &actionEvent)
+				return nil
+			}
+		}
+
+		clientStream := mock.NewMockClientStream(ctrl)
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		conn.EXPECT().NewStream(gomock.Any(), gomock.Any(), "/google.devtools.build.v1.PublishBuildEvent/PublishBuildToolEventStream", gomock.Any()).
+			Return(clientStream, nil)
+		clientStream.EXPECT().SendMsg(gomock.Any()).DoAndReturn(requireBuildExecutionEvent(1, 1000, &actionevent.ActionEvent{
+			ActionDigest:    actionDigest,
+			InstanceName:    "prefix/main",
+			DigestFunction:  remoteexecution.DigestFunction_SHA256,
+			WorkerId:        map[string]string{"hostname": "worker123"},
+			RequestMetadata: requestMetadata,
+			Event: &actionevent.ActionEvent_Started{
+				Started: &emptypb.Empty{},
+			},
+		}))
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(response)
+		clock.EXPECT().Now().Return(time.Unix(1005, 0))
+		clientStream.EXPECT().SendMsg(gomock.Any()).DoAndReturn(requireBuildExecutionEvent(2, 1005, &actionevent.ActionEvent{
+			ActionDigest:    actionDigest,
+			InstanceName:    "prefix/main",
+			DigestFunction:  remoteexecution.DigestFunction_SHA256,
+			WorkerId:        map[string]string{"hostname": "worker123"},
+			RequestMetadata: requestMetadata,
+			Event: &actionevent.ActionEvent_Finished_{
+				Finished: &actionevent.ActionEvent_Finished{
+					ExitCode: 1,
+					ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+						Worker: "worker123",
+					},
+					Duration: &durationpb.Duration{Seconds: 5},
+				},
+			},
+		}))
+		clock.EXPECT().Now().Return(time.Unix(1006, 0))
+		clientStream.EXPECT().SendMsg(gomock.Any()).DoAndReturn(func(m interface{}) error {
+			testutil.RequireEqualProto(t, &build.PublishBuildToolEventStreamRequest{
+				OrderedBuildEvent: &build.OrderedBuildEvent{
+					StreamId:       streamID,
+					SequenceNumber: 3,
+					Event: &build.BuildEvent{
+						EventTime: &timestamppb.Timestamp{Seconds: 1006},
+						Event: &build.BuildEvent_ComponentStreamFinished{
+							ComponentStreamFinished: &build.BuildEvent_BuildComponentStreamFinished{
+								Type: build.BuildEvent_BuildComponentStreamFinished_FINISHED,
+							},
+						},
+					},
+				},
+				ProjectId: "my-project",
+			}, m.(proto.Message))
+			return nil
+		})
+		clientStream.EXPECT().CloseSend()
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		for i := int64(1); i <= 3; i++ {
+			sequenceNumber := i
+			clientStream.EXPECT().RecvMsg(gomock.Any()).DoAndReturn(func(m interface{}) error {
+				proto.Merge(m.(proto.Message), &build.PublishBuildToolEventStreamResponse{
+					StreamId:       streamID,
+					SequenceNumber: sequenceNumber,
+				})
+				return nil
+			})
+		}
+		clientStream.EXPECT().RecvMsg(gomock.Any()).Return(io.EOF)
+		timer.EXPECT().Stop()
+
+		testutil.RequireEqualProto(
+			t,
+			response,
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("StreamCreationFailure", func(t *testing.T) {
+		// Failures to publish events should be logged, but
+		// should not cause the action to fail.
+		request := &remoteworker.DesiredState_Executing{
+			ActionDigest:      actionDigest,
+			AuxiliaryMetadata: []*anypb.Any{requestMetadataAny},
+		}
+		executionStateUpdates := make(chan *remoteworker.CurrentState_Executing, 1)
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		conn.EXPECT().NewStream(gomock.Any(), gomock.Any(), "/google.devtools.build.v1.PublishBuildEvent/PublishBuildToolEventStream", gomock.Any()).
+			Return(nil, status.Error(codes.Unavailable, "Server offline"))
+		errorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.Unavailable, "Failed to create build event stream: Server offline")))
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(response)
+
+		testutil.RequireEqualProto(
+			t,
+			response,
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+}
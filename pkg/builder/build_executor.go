@@ -6,6 +6,7 @@ import (
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
 	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/errorclassification"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
 	"github.com/buildbarn/bb-storage/pkg/digest"
 
@@ -39,6 +40,47 @@ func attachErrorToExecuteResponse(response *remoteexecution.ExecuteResponse, err
 	}
 }
 
+// attachClassifiedErrorToExecuteResponse is identical to
+// attachErrorToExecuteResponse(), except that the error is annotated
+// with the subsystem of the worker in which it occurred. If the error
+// is already classified, its original classification is retained.
+func attachClassifiedErrorToExecuteResponse(response *remoteexecution.ExecuteResponse, domain errorclassification.Domain, err error) {
+	attachErrorToExecuteResponse(response, classifyError(domain, err))
+}
+
+// classifyError adds an ErrorClassification message to the details of
+// an error, unless the error already contains one.
+func classifyError(domain errorclassification.Domain, err error) error {
+	s := status.Convert(err)
+	if getErrorDomain(s) != errorclassification.Domain_UNKNOWN {
+		return err
+	}
+	sWithDetails, detailsErr := s.WithDetails(&errorclassification.ErrorClassification{
+		Domain: domain,
+	})
+	if detailsErr != nil {
+		return err
+	}
+	return sWithDetails.Err()
+}
+
+func getErrorDomain(s *status.Status) errorclassification.Domain {
+	for _, detail := range s.Details() {
+		if classification, ok := detail.(*errorclassification.ErrorClassification); ok {
+			return classification.Domain
+		}
+	}
+	return errorclassification.Domain_UNKNOWN
+}
+
+// GetErrorDomainFromExecuteResponse returns the subsystem of the worker
+// in which the error stored in an ExecuteResponse occurred. UNKNOWN is
+// returned if the ExecuteResponse contains no error, or if the error is
+// not classified.
+func GetErrorDomainFromExecuteResponse(response *remoteexecution.ExecuteResponse) errorclassification.Domain {
+	return getErrorDomain(status.FromProto(response.Status))
+}
+
 func executeResponseIsSuccessful(response *remoteexecution.ExecuteResponse) bool {
 	return status.ErrorProto(response.Status) == nil && response.Result.ExitCode == 0
 }
@@ -47,10 +89,17 @@ func executeResponseIsSuccessful(response *remoteexecution.ExecuteResponse) bool
 // to a pair of strings that describe the execution outcome. These
 // strings can be used as part of metrics labels.
 //
+// Actions that were canceled (e.g., because no clients were waiting for
+// them any longer) are reported separately from other failures, as
+// they don't indicate a problem with the action or the infrastructure.
+//
 // TODO: Move this into some other package, so that pkg/scheduler
 // doesn't need to depend on pkg/builder?
 func GetResultAndGRPCCodeFromExecuteResponse(response *remoteexecution.ExecuteResponse) (result, grpcCode string) {
-	if c := status.FromProto(response.Status).Code(); c != codes.OK {
+	if c := status.FromProto(response.Status).Code(); c == codes.Canceled {
+		result = "Canceled"
+		grpcCode = c.String()
+	} else if c != codes.OK {
 		result = "Failure"
 		grpcCode = c.String()
 	} else if actionResult := response.Result; actionResult == nil {
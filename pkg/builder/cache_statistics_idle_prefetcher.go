@@ -0,0 +1,242 @@
+package builder
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/cacheadmin"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+// cacheEntryKey is the key under which statistics of a single file in
+// the cache are tracked. Similar to the cache itself, it does not
+// include the instance name.
+type cacheEntryKey struct {
+	digestFunction remoteexecution.DigestFunction_Value
+	hash           string
+	sizeBytes      int64
+	isExecutable   bool
+}
+
+func newCacheEntryKey(entry *cacheadmin.CacheEntry) cacheEntryKey {
+	return cacheEntryKey{
+		digestFunction: entry.DigestFunction,
+		hash:           entry.Digest.GetHash(),
+		sizeBytes:      entry.Digest.GetSizeBytes(),
+		isExecutable:   entry.IsExecutable,
+	}
+}
+
+type trackedCacheEntry struct {
+	entry *cacheadmin.CacheEntry
+
+	// The number of hits accumulated by previous incarnations of
+	// the file in the cache, and the number of hits of the current
+	// incarnation that was observed most recently.
+	previousHits uint64
+	currentHits  uint64
+
+	// Whether the file was reported to be present in the cache, and
+	// whether it has been prefetched since then.
+	present    bool
+	prefetched bool
+}
+
+func (e *trackedCacheEntry) getHits() uint64 {
+	return e.previousHits + e.currentHits
+}
+
+type cacheStatisticsIdlePrefetcher struct {
+	fileFetcher      cas.CachingFileFetcher
+	scratchDirectory filesystem.Directory
+	maximumFiles     int
+	statisticsPath   string
+
+	lock          sync.Mutex
+	entries       map[cacheEntryKey]*trackedCacheEntry
+	nextFileIndex uint64
+}
+
+// NewCacheStatisticsIdlePrefetcher creates an IdlePrefetcher that
+// downloads the files that were taken from a cache most frequently,
+// as reported by CachingFileFetcher.GetStatistics(). Files are fetched
+// into a scratch directory and removed immediately afterwards, meaning
+// that the scratch directory needs to be placed on the same file
+// system as the cache directory.
+//
+// As the cache is emptied when the worker starts, statistics are
+// optionally persisted in a file. This allows files that were used
+// frequently prior to restarting the worker to be prefetched.
+func NewCacheStatisticsIdlePrefetcher(fileFetcher cas.CachingFileFetcher, scratchDirectory filesystem.Directory, maximumFiles int, statisticsPath string) (IdlePrefetcher, error) {
+	p := &cacheStatisticsIdlePrefetcher{
+		fileFetcher:      fileFetcher,
+		scratchDirectory: scratchDirectory,
+		maximumFiles:     maximumFiles,
+		statisticsPath:   statisticsPath,
+
+		entries: map[cacheEntryKey]*trackedCacheEntry{},
+	}
+	if statisticsPath != "" {
+		data, err := os.ReadFile(statisticsPath)
+		if err == nil {
+			var statistics cacheadmin.CacheStatistics
+			if err := proto.Unmarshal(data, &statistics); err != nil {
+				return nil, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Failed to unmarshal cache statistics file %#v", statisticsPath)
+			}
+			for _, entry := range statistics.MostUsedEntries {
+				p.entries[newCacheEntryKey(entry)] = &trackedCacheEntry{
+					entry:        entry,
+					previousHits: entry.Hits,
+				}
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to read cache statistics file %#v", statisticsPath)
+		}
+	}
+	return p, nil
+}
+
+// updateStatistics merges the current statistics of the cache into
+// the ones that are tracked, returning the entries that were used most
+// frequently.
+func (p *cacheStatisticsIdlePrefetcher) updateStatistics() []*trackedCacheEntry {
+	for _, trackedEntry := range p.entries {
+		trackedEntry.present = false
+	}
+	for _, entry := range p.fileFetcher.GetStatistics(p.maximumFiles).MostUsedEntries {
+		key := newCacheEntryKey(entry)
+		trackedEntry, ok := p.entries[key]
+		if !ok {
+			trackedEntry = &trackedCacheEntry{}
+			p.entries[key] = trackedEntry
+		}
+		if entry.Hits < trackedEntry.currentHits {
+			// The file was evicted from the cache and
+			// downloaded once more since we last looked.
+			trackedEntry.previousHits += trackedEntry.currentHits
+		}
+		trackedEntry.entry = entry
+		trackedEntry.currentHits = entry.Hits
+		trackedEntry.present = true
+		trackedEntry.prefetched = false
+	}
+
+	mostUsedEntries := make([]*trackedCacheEntry, 0, len(p.entries))
+	for _, trackedEntry := range p.entries {
+		mostUsedEntries = append(mostUsedEntries, trackedEntry)
+	}
+	sort.Slice(mostUsedEntries, func(i, j int) bool {
+		ei, ej := mostUsedEntries[i], mostUsedEntries[j]
+		if hi, hj := ei.getHits(), ej.getHits(); hi != hj {
+			return hi > hj
+		}
+		ki, kj := newCacheEntryKey(ei.entry), newCacheEntryKey(ej.entry)
+		if ki.hash != kj.hash {
+			return ki.hash < kj.hash
+		}
+		return !ki.isExecutable && kj.isExecutable
+	})
+
+	// Discard entries that are used infrequently, so that the
+	// amount of memory and disk space used remains bounded.
+	if len(mostUsedEntries) > p.maximumFiles {
+		for _, trackedEntry := range mostUsedEntries[p.maximumFiles:] {
+			delete(p.entries, newCacheEntryKey(trackedEntry.entry))
+		}
+		mostUsedEntries = mostUsedEntries[:p.maximumFiles]
+	}
+	return mostUsedEntries
+}
+
+// writeStatistics persists the statistics of the cache, so that they
+// may be used after the worker restarts.
+func (p *cacheStatisticsIdlePrefetcher) writeStatistics(mostUsedEntries []*trackedCacheEntry) error {
+	statistics := cacheadmin.CacheStatistics{
+		MostUsedEntries: make([]*cacheadmin.CacheEntry, 0, len(mostUsedEntries)),
+	}
+	for _, trackedEntry := range mostUsedEntries {
+		entry := proto.Clone(trackedEntry.entry).(*cacheadmin.CacheEntry)
+		entry.Hits = trackedEntry.getHits()
+		statistics.MostUsedEntries = append(statistics.MostUsedEntries, entry)
+	}
+	data, err := proto.Marshal(&statistics)
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to marshal cache statistics")
+	}
+
+	// Write the statistics into a temporary file first, so that
+	// the statistics file is never left in a partially written
+	// state.
+	temporaryPath := p.statisticsPath + ".tmp"
+	if err := os.WriteFile(temporaryPath, data, 0o666); err != nil {
+		return util.StatusWrapfWithCode(err, codes.Internal, "Failed to write cache statistics file %#v", temporaryPath)
+	}
+	if err := os.Rename(temporaryPath, p.statisticsPath); err != nil {
+		return util.StatusWrapfWithCode(err, codes.Internal, "Failed to rename cache statistics file %#v", temporaryPath)
+	}
+	return nil
+}
+
+func (p *cacheStatisticsIdlePrefetcher) PrefetchWhileIdle(ctx context.Context) error {
+	// Only let a single worker thread perform prefetching.
+	if !p.lock.TryLock() {
+		return nil
+	}
+	defer p.lock.Unlock()
+
+	mostUsedEntries := p.updateStatistics()
+	if p.statisticsPath != "" {
+		if err := p.writeStatistics(mostUsedEntries); err != nil {
+			return err
+		}
+	}
+
+	for _, trackedEntry := range mostUsedEntries {
+		if ctx.Err() != nil {
+			return util.StatusFromContext(ctx)
+		}
+
+		// Files that are reported by the cache are already
+		// present. Files that were prefetched previously are
+		// not reported until they are used by an action.
+		if trackedEntry.present || trackedEntry.prefetched {
+			continue
+		}
+		trackedEntry.prefetched = true
+		entry := trackedEntry.entry
+
+		instanceName, err := digest.NewInstanceName(entry.InstanceName)
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid instance name %#v", entry.InstanceName)
+		}
+		digestFunction, err := instanceName.GetDigestFunction(entry.DigestFunction, 0)
+		if err != nil {
+			return util.StatusWrap(err, "Failed to obtain digest function")
+		}
+		blobDigest, err := digestFunction.NewDigestFromProto(entry.Digest)
+		if err != nil {
+			return util.StatusWrap(err, "Failed to extract digest")
+		}
+
+		p.nextFileIndex++
+		name := path.MustNewComponent(strconv.FormatUint(p.nextFileIndex, 10))
+		if err := p.fileFetcher.GetFile(ctx, blobDigest, p.scratchDirectory, name, entry.IsExecutable); err != nil {
+			return util.StatusWrapf(err, "Failed to fetch file %#v", blobDigest.String())
+		}
+		if err := p.scratchDirectory.Remove(name); err != nil {
+			return util.StatusWrapfWithCode(err, codes.Internal, "Failed to remove scratch file %#v", name.String())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package builder_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/cacheadmin"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCacheStatisticsIdlePrefetcher(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	fileFetcher := mock.NewMockCachingFileFetcher(ctrl)
+	scratchDirectory := mock.NewMockDirectory(ctrl)
+	statisticsPath := filepath.Join(t.TempDir(), "statistics")
+
+	cacheEntry := &cacheadmin.CacheEntry{
+		Digest: &remoteexecution.Digest{
+			Hash:      "8b1a9953c4611296a827abf8c47804d7",
+			SizeBytes: 5,
+		},
+		DigestFunction: remoteexecution.DigestFunction_MD5,
+		IsExecutable:   true,
+		Hits:           3,
+		InstanceName:   "hello",
+	}
+	blobDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+
+	t.Run("InvalidStatisticsFile", func(t *testing.T) {
+		invalidStatisticsPath := filepath.Join(t.TempDir(), "statistics")
+		require.NoError(t, os.WriteFile(invalidStatisticsPath, []byte("Hello"), 0o666))
+
+		_, err := builder.NewCacheStatisticsIdlePrefetcher(fileFetcher, scratchDirectory, 10, invalidStatisticsPath)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("EvictedFiles", func(t *testing.T) {
+		idlePrefetcher, err := builder.NewCacheStatisticsIdlePrefetcher(fileFetcher, scratchDirectory, 10, statisticsPath)
+		require.NoError(t, err)
+
+		// Files that are present in the cache should not be
+		// prefetched, but their statistics should be persisted.
+		fileFetcher.EXPECT().GetStatistics(10).Return(&cacheadmin.CacheStatistics{
+			MostUsedEntries: []*cacheadmin.CacheEntry{cacheEntry},
+		})
+		require.NoError(t, idlePrefetcher.PrefetchWhileIdle(ctx))
+
+		data, err := os.ReadFile(statisticsPath)
+		require.NoError(t, err)
+		var statistics cacheadmin.CacheStatistics
+		require.NoError(t, proto.Unmarshal(data, &statistics))
+		testutil.RequireEqualProto(t, &cacheadmin.CacheStatistics{
+			MostUsedEntries: []*cacheadmin.CacheEntry{cacheEntry},
+		}, &statistics)
+
+		// Once evicted, the file should be fetched once more.
+		// Failures should be propagated.
+		fileFetcher.EXPECT().GetStatistics(10).Return(&cacheadmin.CacheStatistics{})
+		fileFetcher.EXPECT().GetFile(ctx, blobDigest, scratchDirectory, path.MustNewComponent("1"), true).
+			Return(syscall.EIO)
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Unknown, "Failed to fetch file \"3-8b1a9953c4611296a827abf8c47804d7-5-hello\": input/output error"),
+			idlePrefetcher.PrefetchWhileIdle(ctx))
+
+		// Files should only be attempted to be prefetched
+		// once, until they are observed to be used again.
+		fileFetcher.EXPECT().GetStatistics(10).Return(&cacheadmin.CacheStatistics{})
+		require.NoError(t, idlePrefetcher.PrefetchWhileIdle(ctx))
+	})
+
+	t.Run("PersistedStatistics", func(t *testing.T) {
+		// Statistics written by a previous instance should be
+		// used to prefetch files that are no longer present.
+		idlePrefetcher, err := builder.NewCacheStatisticsIdlePrefetcher(fileFetcher, scratchDirectory, 10, statisticsPath)
+		require.NoError(t, err)
+
+		fileFetcher.EXPECT().GetStatistics(10).Return(&cacheadmin.CacheStatistics{})
+		gomock.InOrder(
+			fileFetcher.EXPECT().GetFile(ctx, blobDigest, scratchDirectory, path.MustNewComponent("1"), true),
+			scratchDirectory.EXPECT().Remove(path.MustNewComponent("1")))
+		require.NoError(t, idlePrefetcher.PrefetchWhileIdle(ctx))
+
+		// Hits of the file prior to the restart should be
+		// retained, even if the file is used again.
+		usedCacheEntry := proto.Clone(cacheEntry).(*cacheadmin.CacheEntry)
+		usedCacheEntry.Hits = 2
+		fileFetcher.EXPECT().GetStatistics(10).Return(&cacheadmin.CacheStatistics{
+			MostUsedEntries: []*cacheadmin.CacheEntry{usedCacheEntry},
+		})
+		require.NoError(t, idlePrefetcher.PrefetchWhileIdle(ctx))
+
+		data, err := os.ReadFile(statisticsPath)
+		require.NoError(t, err)
+		var statistics cacheadmin.CacheStatistics
+		require.NoError(t, proto.Unmarshal(data, &statistics))
+		expectedCacheEntry := proto.Clone(cacheEntry).(*cacheadmin.CacheEntry)
+		expectedCacheEntry.Hits = 5
+		testutil.RequireEqualProto(t, &cacheadmin.CacheStatistics{
+			MostUsedEntries: []*cacheadmin.CacheEntry{expectedCacheEntry},
+		}, &statistics)
+	})
+}
@@ -17,6 +17,7 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 type cachingBuildExecutor struct {
@@ -24,6 +25,7 @@ type cachingBuildExecutor struct {
 	contentAddressableStorage blobstore.BlobAccess
 	actionCache               blobstore.BlobAccess
 	browserURL                *url.URL
+	writePolicy               ActionCacheWritePolicy
 }
 
 // NewCachingBuildExecutor creates an adapter for BuildExecutor that
@@ -31,14 +33,18 @@ type cachingBuildExecutor struct {
 // If they may not be cached, they are stored in the Content Addressable
 // Storage (CAS) instead.
 //
+// Whether results may be cached is determined by the provided
+// ActionCacheWritePolicy.
+//
 // In both cases, a link to bb_browser is added to the ExecuteResponse,
 // so that the user may inspect the Action and ActionResult in detail.
-func NewCachingBuildExecutor(base BuildExecutor, contentAddressableStorage, actionCache blobstore.BlobAccess, browserURL *url.URL) BuildExecutor {
+func NewCachingBuildExecutor(base BuildExecutor, contentAddressableStorage, actionCache blobstore.BlobAccess, browserURL *url.URL, writePolicy ActionCacheWritePolicy) BuildExecutor {
 	return &cachingBuildExecutor{
 		BuildExecutor:             base,
 		contentAddressableStorage: contentAddressableStorage,
 		actionCache:               actionCache,
 		browserURL:                browserURL,
+		writePolicy:               writePolicy,
 	}
 }
 
@@ -48,7 +54,7 @@ func (be *cachingBuildExecutor) Execute(ctx context.Context, filePool filesystem
 		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to extract digest for action"))
 	} else if action := request.Action; action == nil {
 		attachErrorToExecuteResponse(response, status.Error(codes.InvalidArgument, "Request does not contain an action"))
-	} else if !action.DoNotCache && executeResponseIsSuccessful(response) {
+	} else if !action.DoNotCache && be.shouldWriteActionResult(ctx, filePool, digestFunction, request, response) {
 		// Store result in the Action Cache.
 		if err := be.actionCache.Put(ctx, actionDigest, buffer.NewProtoBufferFromProto(response.Result, buffer.UserProvided)); err == nil {
 			response.Message = "Action details (cached result): " + re_util.GetBrowserURL(be.browserURL, "action", actionDigest)
@@ -74,3 +80,46 @@ func (be *cachingBuildExecutor) Execute(ctx context.Context, filePool filesystem
 	}
 	return response
 }
+
+// shouldWriteActionResult returns whether the result of an action may
+// be stored in the Action Cache, based on the decision of the
+// ActionCacheWritePolicy. If the policy requires the action to be
+// deterministic, the action is executed once more, and its outputs are
+// compared against those of the original execution.
+func (be *cachingBuildExecutor) shouldWriteActionResult(ctx context.Context, filePool filesystem.FilePool, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, response *remoteexecution.ExecuteResponse) bool {
+	switch be.writePolicy.GetActionCacheWriteDecision(request, response) {
+	case ActionCacheWriteDecisionWrite:
+		return true
+	case ActionCacheWriteDecisionVerifyDeterminism:
+		// Re-executions of the action should not cause updates
+		// to be sent to the scheduler, as the scheduler would
+		// interpret these as the action restarting.
+		discardedExecutionStateUpdates := make(chan *remoteworker.CurrentState_Executing)
+		go func() {
+			for range discardedExecutionStateUpdates {
+			}
+		}()
+		verificationResponse := be.BuildExecutor.Execute(ctx, filePool, nil, digestFunction, request, discardedExecutionStateUpdates)
+		close(discardedExecutionStateUpdates)
+		return status.ErrorProto(verificationResponse.Status) == nil &&
+			verificationResponse.Result != nil &&
+			verificationResponse.Result.ExitCode == response.Result.ExitCode &&
+			proto.Equal(getActionResultOutputs(verificationResponse.Result), getActionResultOutputs(response.Result))
+	default:
+		return false
+	}
+}
+
+// getActionResultOutputs returns a copy of an ActionResult that only
+// contains its outputs. Other fields, such as execution metadata and
+// standard output, tend to differ between executions, even if the
+// action is deterministic.
+func getActionResultOutputs(actionResult *remoteexecution.ActionResult) *remoteexecution.ActionResult {
+	return &remoteexecution.ActionResult{
+		OutputFiles:             actionResult.OutputFiles,
+		OutputFileSymlinks:      actionResult.OutputFileSymlinks,
+		OutputSymlinks:          actionResult.OutputSymlinks,
+		OutputDirectories:       actionResult.OutputDirectories,
+		OutputDirectorySymlinks: actionResult.OutputDirectorySymlinks,
+	}
+}
@@ -62,7 +62,7 @@ func TestCachingBuildExecutorCachedSuccess(t *testing.T) {
 		Scheme: "https",
 		Host:   "example.com",
 		Path:   "/some/sub/directory",
-	})
+	}, builder.SuccessfulActionCacheWritePolicy)
 
 	executeResponse := cachingBuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata)
 	testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
@@ -115,7 +115,7 @@ func TestCachingBuildExecutorCachedSuccessExplicitOK(t *testing.T) {
 		Scheme: "https",
 		Host:   "example.com",
 		Path:   "/some/sub/directory",
-	})
+	}, builder.SuccessfulActionCacheWritePolicy)
 
 	executeResponse := cachingBuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata)
 	testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
@@ -183,7 +183,7 @@ func TestCachingBuildExecutorCachedSuccessNonZeroExitCode(t *testing.T) {
 		Scheme: "https",
 		Host:   "example.com",
 		Path:   "/some/sub/directory",
-	})
+	}, builder.SuccessfulActionCacheWritePolicy)
 
 	executeResponse := cachingBuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata)
 	testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
@@ -236,7 +236,7 @@ func TestCachingBuildExecutorCachedStorageFailure(t *testing.T) {
 		Scheme: "https",
 		Host:   "example.com",
 		Path:   "/",
-	})
+	}, builder.SuccessfulActionCacheWritePolicy)
 
 	executeResponse := cachingBuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata)
 	testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
@@ -296,7 +296,7 @@ func TestCachingBuildExecutorUncachedDoNotCache(t *testing.T) {
 		Scheme: "http",
 		Host:   "example.com",
 		Path:   "/some/sub/directory/",
-	})
+	}, builder.SuccessfulActionCacheWritePolicy)
 
 	executeResponse := cachingBuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata)
 	testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
@@ -358,7 +358,7 @@ func TestCachingBuildExecutorUncachedError(t *testing.T) {
 		Scheme: "http",
 		Host:   "example.com",
 		Path:   "/some/sub/directory/",
-	})
+	}, builder.SuccessfulActionCacheWritePolicy)
 
 	executeResponse := cachingBuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata)
 	testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
@@ -421,7 +421,7 @@ func TestCachingBuildExecutorUncachedStorageFailure(t *testing.T) {
 		Scheme: "http",
 		Host:   "example.com",
 		Path:   "/some/sub/directory/",
-	})
+	}, builder.SuccessfulActionCacheWritePolicy)
 
 	executeResponse := cachingBuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata)
 	testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
@@ -431,3 +431,75 @@ func TestCachingBuildExecutorUncachedStorageFailure(t *testing.T) {
 		Status: status.New(codes.DeadlineExceeded, "Build took more than ten seconds").Proto(),
 	}, executeResponse)
 }
+
+// When the write policy requires determinism to be verified, the action
+// should be executed once more. The result may only be stored in the
+// Action Cache if the outputs of both executions are identical.
+func TestCachingBuildExecutorDeterminismVerification(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	actionDigest := &remoteexecution.Digest{
+		Hash:      "64ec88ca00b268e5ba1a35678a1b5316d212f4f366b2477232534a8aeca37f3c",
+		SizeBytes: 11,
+	}
+	action := &remoteexecution.Action{DoNotCache: false}
+	request := &remoteworker.DesiredState_Executing{
+		ActionDigest: actionDigest,
+		Action:       action,
+	}
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	digestFunction := digest.MustNewFunction("freebsd12", remoteexecution.DigestFunction_SHA256)
+	var metadata chan<- *remoteworker.CurrentState_Executing = make(chan *remoteworker.CurrentState_Executing, 10)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	actionCache := mock.NewMockBlobAccess(ctrl)
+	cachingBuildExecutor := builder.NewCachingBuildExecutor(baseBuildExecutor, contentAddressableStorage, actionCache, &url.URL{
+		Scheme: "https",
+		Host:   "example.com",
+		Path:   "/some/sub/directory",
+	}, builder.NewDeterminismVerifyingActionCacheWritePolicy(builder.SuccessfulActionCacheWritePolicy))
+
+	outputFile := func(hash string) *remoteexecution.ExecuteResponse {
+		return &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				OutputFiles: []*remoteexecution.OutputFile{
+					{
+						Path: "hello.o",
+						Digest: &remoteexecution.Digest{
+							Hash:      hash,
+							SizeBytes: 42,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("Deterministic", func(t *testing.T) {
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, metadata).
+			Return(outputFile("8b1a9953c4611296a827abf8c47804d7e6c49c6b"))
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, nil, digestFunction, request, gomock.Any()).
+			Return(outputFile("8b1a9953c4611296a827abf8c47804d7e6c49c6b"))
+		actionCache.EXPECT().Put(
+			ctx,
+			digest.MustNewDigest("freebsd12", remoteexecution.DigestFunction_SHA256, "64ec88ca00b268e5ba1a35678a1b5316d212f4f366b2477232534a8aeca37f3c", 11),
+			gomock.Any()).
+			Return(nil)
+
+		executeResponse := cachingBuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata)
+		require.Equal(t, "Action details (cached result): https://example.com/some/sub/directory/freebsd12/blobs/sha256/action/64ec88ca00b268e5ba1a35678a1b5316d212f4f366b2477232534a8aeca37f3c-11/", executeResponse.Message)
+	})
+
+	t.Run("Nondeterministic", func(t *testing.T) {
+		// Outputs differing between executions should cause the
+		// result to be stored in the CAS instead.
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, metadata).
+			Return(outputFile("8b1a9953c4611296a827abf8c47804d7e6c49c6b"))
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, nil, digestFunction, request, gomock.Any()).
+			Return(outputFile("c5d9c41e6c7b1ab5a4f5d8a3ee3c7bb52a4b2b7a"))
+		contentAddressableStorage.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).Return(nil)
+
+		executeResponse := cachingBuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata)
+		require.Contains(t, executeResponse.Message, "Action details (uncached result): ")
+	})
+}
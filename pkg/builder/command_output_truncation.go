@@ -0,0 +1,101 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+)
+
+// CommandOutputTruncation contains the options that need to be
+// provided to LocalBuildExecutor to limit the size of the standard
+// output and standard error of actions.
+type CommandOutputTruncation struct {
+	// The maximum size of the standard output and standard error
+	// that is stored in the Content Addressable Storage. Outputs
+	// that exceed this size are reduced to their head and tail,
+	// separated by a marker that states how many bytes were
+	// omitted.
+	MaximumSizeBytes int64
+
+	// If not nil, the full contents of outputs that are truncated
+	// are stored in this Content Addressable Storage.
+	LargeLogsContentAddressableStorage blobstore.BlobAccess
+}
+
+// uploadOutput uploads the standard output or standard error of an
+// action into the Content Addressable Storage. If the output exceeds
+// the maximum size, a truncated copy is uploaded instead, and
+// information on the original output is returned.
+func (cot *CommandOutputTruncation) uploadOutput(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, buildDirectory BuildDirectory, name path.Component, digestFunction digest.Function) (digest.Digest, *resourceusage.TruncatedCommandOutputs_Output, error) {
+	f, err := buildDirectory.OpenRead(name)
+	if err != nil {
+		return digest.BadDigest, nil, err
+	}
+	defer f.Close()
+
+	// Determine the size of the output. Only compute its digest if
+	// it needs to be stored in the large logs storage.
+	var digestGenerator *digest.Generator
+	var sizeWriter io.Writer = io.Discard
+	if cot.LargeLogsContentAddressableStorage != nil {
+		digestGenerator = digestFunction.NewGenerator(cot.MaximumSizeBytes)
+		sizeWriter = digestGenerator
+	}
+	sizeBytes, err := io.Copy(sizeWriter, io.NewSectionReader(f, 0, 1<<62))
+	if err != nil {
+		return digest.BadDigest, nil, util.StatusWrap(err, "Failed to read output")
+	}
+	if sizeBytes <= cot.MaximumSizeBytes {
+		d, err := buildDirectory.UploadFile(ctx, name, digestFunction)
+		return d, nil, err
+	}
+
+	// Construct a copy of the output that only contains its head
+	// and tail.
+	headSizeBytes := cot.MaximumSizeBytes / 2
+	tailSizeBytes := cot.MaximumSizeBytes - headSizeBytes
+	marker := fmt.Sprintf("\n\n[... %d bytes truncated ...]\n\n", sizeBytes-headSizeBytes-tailSizeBytes)
+	data := make([]byte, 0, cot.MaximumSizeBytes+int64(len(marker)))
+	head := make([]byte, headSizeBytes)
+	if _, err := io.ReadFull(io.NewSectionReader(f, 0, headSizeBytes), head); err != nil {
+		return digest.BadDigest, nil, util.StatusWrap(err, "Failed to read head of output")
+	}
+	data = append(append(data, head...), marker...)
+	tail := make([]byte, tailSizeBytes)
+	if _, err := io.ReadFull(io.NewSectionReader(f, sizeBytes-tailSizeBytes, tailSizeBytes), tail); err != nil {
+		return digest.BadDigest, nil, util.StatusWrap(err, "Failed to read tail of output")
+	}
+	data = append(data, tail...)
+
+	truncatedDigestGenerator := digestFunction.NewGenerator(int64(len(data)))
+	if _, err := truncatedDigestGenerator.Write(data); err != nil {
+		panic(err)
+	}
+	truncatedDigest := truncatedDigestGenerator.Sum()
+	if err := contentAddressableStorage.Put(ctx, truncatedDigest, buffer.NewValidatedBufferFromByteSlice(data)); err != nil {
+		return digest.BadDigest, nil, util.StatusWrap(err, "Failed to store truncated output")
+	}
+
+	truncatedOutput := &resourceusage.TruncatedCommandOutputs_Output{
+		OriginalSizeBytes: uint64(sizeBytes),
+	}
+	if digestGenerator != nil {
+		originalDigest := digestGenerator.Sum()
+		if err := cot.LargeLogsContentAddressableStorage.Put(
+			ctx,
+			originalDigest,
+			buffer.NewCASBufferFromReader(originalDigest, io.NopCloser(io.NewSectionReader(f, 0, sizeBytes)), buffer.UserProvided),
+		); err != nil {
+			return digest.BadDigest, nil, util.StatusWrap(err, "Failed to store original output in large logs storage")
+		}
+		truncatedOutput.OriginalDigest = originalDigest.GetProto()
+	}
+	return truncatedDigest, truncatedOutput, nil
+}
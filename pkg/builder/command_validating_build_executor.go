@@ -0,0 +1,235 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CommandLimits describes the limits that the operating system imposes
+// on the arguments and environment variables of processes.
+type CommandLimits struct {
+	// The maximum size of a single argument or environment
+	// variable in bytes. Zero if unlimited.
+	MaximumArgumentSizeBytes int64
+	// The maximum combined size of all arguments and environment
+	// variables in bytes. Zero if unlimited.
+	MaximumTotalArgumentsSizeBytes int64
+}
+
+type commandValidatingBuildExecutor struct {
+	BuildExecutor
+	contentAddressableStorage blobstore.BlobAccess
+	maximumMessageSizeBytes   int
+	limits                    CommandLimits
+}
+
+// NewCommandValidatingBuildExecutor creates a decorator for
+// BuildExecutor that validates the Command message of an action before
+// any work is done to construct its input root. Commands having
+// duplicate environment variables, output paths that conflict with each
+// other, working directories or output paths that are not normalized,
+// or arguments that exceed the limits of the operating system are
+// rejected with INVALID_ARGUMENT.
+//
+// Without this decorator, such actions tend to fail while being
+// executed by the runner, with errors that are hard to relate to the
+// offending field of the Command message.
+func NewCommandValidatingBuildExecutor(buildExecutor BuildExecutor, contentAddressableStorage blobstore.BlobAccess, maximumMessageSizeBytes int, limits CommandLimits) BuildExecutor {
+	return &commandValidatingBuildExecutor{
+		BuildExecutor:             buildExecutor,
+		contentAddressableStorage: contentAddressableStorage,
+		maximumMessageSizeBytes:   maximumMessageSizeBytes,
+		limits:                    limits,
+	}
+}
+
+// validateCommandString checks whether an argument or environment
+// variable can be passed to a process.
+func (be *commandValidatingBuildExecutor) validateCommandString(s, description string) error {
+	if strings.IndexByte(s, 0) >= 0 {
+		return status.Errorf(codes.InvalidArgument, "%s contains a null byte", description)
+	}
+	if limit := be.limits.MaximumArgumentSizeBytes; limit > 0 && int64(len(s))+1 > limit {
+		return status.Errorf(codes.InvalidArgument, "%s has a size of %d bytes, which exceeds the maximum of %d bytes", description, len(s)+1, limit)
+	}
+	return nil
+}
+
+// resolveNormalizedPath resolves a path in the Command message
+// relative to a directory in the input root, requiring that it does
+// not contain any redundant components. Leading ".." components are
+// only permitted if allowParentReferences is set.
+func resolveNormalizedPath(directory []string, p string, allowParentReferences bool) ([]string, error) {
+	if p == "" {
+		return directory, nil
+	}
+	if strings.HasPrefix(p, "/") {
+		return nil, status.Error(codes.InvalidArgument, "Path is absolute, while a relative path was expected")
+	}
+	resolved := append([]string(nil), directory...)
+	leading := true
+	for _, component := range strings.Split(p, "/") {
+		switch component {
+		case "":
+			return nil, status.Error(codes.InvalidArgument, "Path contains an empty component or a trailing slash")
+		case ".":
+			return nil, status.Error(codes.InvalidArgument, "Path contains a \".\" component")
+		case "..":
+			if !allowParentReferences || !leading {
+				return nil, status.Error(codes.InvalidArgument, "Path contains a \"..\" component that is not permitted")
+			}
+			if len(resolved) == 0 {
+				return nil, status.Error(codes.InvalidArgument, "Path resolves to a location outside the input root")
+			}
+			resolved = resolved[:len(resolved)-1]
+		default:
+			leading = false
+			resolved = append(resolved, component)
+		}
+	}
+	return resolved, nil
+}
+
+// validateOutputs checks that output paths are normalized, and that
+// none of them conflict with each other.
+func validateOutputs(workingDirectory []string, command *remoteexecution.Command) error {
+	type output struct {
+		path        string
+		isDirectory bool
+	}
+	var outputs []output
+	if len(command.OutputPaths) > 0 {
+		// REv2.1 and later: the type of the outputs is not
+		// known in advance.
+		for _, outputPath := range command.OutputPaths {
+			outputs = append(outputs, output{path: outputPath, isDirectory: true})
+		}
+	} else {
+		for _, outputFile := range command.OutputFiles {
+			outputs = append(outputs, output{path: outputFile})
+		}
+		for _, outputDirectory := range command.OutputDirectories {
+			outputs = append(outputs, output{path: outputDirectory, isDirectory: true})
+		}
+	}
+
+	seen := map[string]output{}
+	resolvedPaths := make([]string, 0, len(outputs))
+	for _, o := range outputs {
+		if o.path == "" {
+			return status.Error(codes.InvalidArgument, "Output path is empty")
+		}
+		components, err := resolveNormalizedPath(workingDirectory, o.path, true)
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid output path %#v", o.path)
+		}
+		if len(components) == 0 {
+			return status.Errorf(codes.InvalidArgument, "Output path %#v resolves to the input root directory", o.path)
+		}
+		resolvedPath := strings.Join(components, "/")
+		if existing, ok := seen[resolvedPath]; ok {
+			if existing.path == o.path {
+				if existing.isDirectory == o.isDirectory {
+					return status.Errorf(codes.InvalidArgument, "Output path %#v is listed multiple times", o.path)
+				}
+				return status.Errorf(codes.InvalidArgument, "Output path %#v is listed both as an output file and an output directory", o.path)
+			}
+			return status.Errorf(codes.InvalidArgument, "Output paths %#v and %#v refer to the same location", existing.path, o.path)
+		}
+		seen[resolvedPath] = o
+		resolvedPaths = append(resolvedPaths, resolvedPath)
+	}
+
+	// Output files cannot be parents of other outputs, as that
+	// would require them to be directories.
+	for i, resolvedPath := range resolvedPaths {
+		components := strings.Split(resolvedPath, "/")
+		for j := 1; j < len(components); j++ {
+			if parent, ok := seen[strings.Join(components[:j], "/")]; ok && !parent.isDirectory {
+				return status.Errorf(codes.InvalidArgument, "Output file %#v is a parent of output path %#v", parent.path, outputs[i].path)
+			}
+		}
+	}
+	return nil
+}
+
+// validateCommand returns an error if the Command message of an action
+// cannot be executed as specified.
+func (be *commandValidatingBuildExecutor) validateCommand(ctx context.Context, digestFunction digest.Function, action *remoteexecution.Action) error {
+	commandDigest, err := digestFunction.NewDigestFromProto(action.CommandDigest)
+	if err != nil {
+		return util.StatusWrap(err, "Failed to extract digest for command")
+	}
+	commandMessage, err := be.contentAddressableStorage.Get(ctx, commandDigest).ToProto(&remoteexecution.Command{}, be.maximumMessageSizeBytes)
+	if err != nil {
+		return util.StatusWrap(err, "Failed to obtain command")
+	}
+	command := commandMessage.(*remoteexecution.Command)
+
+	if len(command.Arguments) == 0 {
+		return status.Error(codes.InvalidArgument, "Command does not contain any arguments")
+	}
+	totalSizeBytes := int64(0)
+	for i, argument := range command.Arguments {
+		if err := be.validateCommandString(argument, fmt.Sprintf("Argument at index %d", i)); err != nil {
+			return err
+		}
+		totalSizeBytes += int64(len(argument)) + 1
+	}
+
+	environmentVariables := map[string]struct{}{}
+	for _, environmentVariable := range command.EnvironmentVariables {
+		name := environmentVariable.Name
+		if name == "" {
+			return status.Error(codes.InvalidArgument, "Environment variable has an empty name")
+		}
+		if strings.ContainsRune(name, '=') {
+			return status.Errorf(codes.InvalidArgument, "Name of environment variable %#v contains an equals sign", name)
+		}
+		if _, ok := environmentVariables[name]; ok {
+			return status.Errorf(codes.InvalidArgument, "Environment variable %#v is set multiple times", name)
+		}
+		environmentVariables[name] = struct{}{}
+		entry := name + "=" + environmentVariable.Value
+		if err := be.validateCommandString(entry, fmt.Sprintf("Environment variable %#v", name)); err != nil {
+			return err
+		}
+		totalSizeBytes += int64(len(entry)) + 1
+	}
+	if limit := be.limits.MaximumTotalArgumentsSizeBytes; limit > 0 && totalSizeBytes > limit {
+		return status.Errorf(codes.InvalidArgument, "Arguments and environment variables have a combined size of %d bytes, which exceeds the maximum of %d bytes", totalSizeBytes, limit)
+	}
+
+	workingDirectory, err := resolveNormalizedPath(nil, command.WorkingDirectory, false)
+	if err != nil {
+		return util.StatusWrapf(err, "Invalid working directory %#v", command.WorkingDirectory)
+	}
+	return validateOutputs(workingDirectory, command)
+}
+
+func (be *commandValidatingBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	action := request.Action
+	if action == nil {
+		response := NewDefaultExecuteResponse(request)
+		attachErrorToExecuteResponse(response, status.Error(codes.InvalidArgument, "Request does not contain an action"))
+		return response
+	}
+	if err := be.validateCommand(ctx, digestFunction, action); err != nil {
+		response := NewDefaultExecuteResponse(request)
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Invalid command"))
+		return response
+	}
+	return be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+}
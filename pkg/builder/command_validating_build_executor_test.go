@@ -0,0 +1,188 @@
+package builder_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCommandValidatingBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	buildExecutor := builder.NewCommandValidatingBuildExecutor(
+		baseBuildExecutor,
+		contentAddressableStorage,
+		/* maximumMessageSizeBytes = */ 10000,
+		builder.CommandLimits{
+			MaximumArgumentSizeBytes:       100,
+			MaximumTotalArgumentsSizeBytes: 150,
+		})
+
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5)
+	request := &remoteworker.DesiredState_Executing{
+		Action: &remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "e72499b7c6de12b7ad046541f6de8beb",
+				SizeBytes: 123,
+			},
+			InputRootDigest: &remoteexecution.Digest{
+				Hash:      "095afbd64b6358665546558583c64d38",
+				SizeBytes: 456,
+			},
+		},
+	}
+	commandDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "e72499b7c6de12b7ad046541f6de8beb", 123)
+	executionStateUpdates := make(chan *remoteworker.CurrentState_Executing, 3)
+
+	expectCommand := func(command *remoteexecution.Command) {
+		contentAddressableStorage.EXPECT().Get(ctx, commandDigest).Return(buffer.NewProtoBufferFromProto(command, buffer.UserProvided))
+	}
+	requireRejected := func(t *testing.T, message string) {
+		testutil.RequireEqualProto(
+			t,
+			&remoteexecution.ExecuteResponse{
+				Result: &remoteexecution.ActionResult{
+					ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+				},
+				Status: status.New(codes.InvalidArgument, message).Proto(),
+			},
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	}
+
+	t.Run("NoArguments", func(t *testing.T) {
+		expectCommand(&remoteexecution.Command{})
+
+		requireRejected(t, "Invalid command: Command does not contain any arguments")
+	})
+
+	t.Run("NullByteInArgument", func(t *testing.T) {
+		expectCommand(&remoteexecution.Command{
+			Arguments: []string{"cc", "hello\x00.c"},
+		})
+
+		requireRejected(t, "Invalid command: Argument at index 1 contains a null byte")
+	})
+
+	t.Run("ArgumentTooLarge", func(t *testing.T) {
+		expectCommand(&remoteexecution.Command{
+			Arguments: []string{"echo", strings.Repeat("x", 100)},
+		})
+
+		requireRejected(t, "Invalid command: Argument at index 1 has a size of 101 bytes, which exceeds the maximum of 100 bytes")
+	})
+
+	t.Run("ArgumentsTooLarge", func(t *testing.T) {
+		// Environment variables count towards the combined
+		// size of the arguments.
+		expectCommand(&remoteexecution.Command{
+			Arguments: []string{"echo", strings.Repeat("x", 90)},
+			EnvironmentVariables: []*remoteexecution.Command_EnvironmentVariable{
+				{Name: "A", Value: strings.Repeat("a", 90)},
+			},
+		})
+
+		requireRejected(t, "Invalid command: Arguments and environment variables have a combined size of 189 bytes, which exceeds the maximum of 150 bytes")
+	})
+
+	t.Run("DuplicateEnvironmentVariable", func(t *testing.T) {
+		expectCommand(&remoteexecution.Command{
+			Arguments: []string{"cc"},
+			EnvironmentVariables: []*remoteexecution.Command_EnvironmentVariable{
+				{Name: "PATH", Value: "/bin"},
+				{Name: "PATH", Value: "/usr/bin"},
+			},
+		})
+
+		requireRejected(t, "Invalid command: Environment variable \"PATH\" is set multiple times")
+	})
+
+	t.Run("NonNormalizedWorkingDirectory", func(t *testing.T) {
+		expectCommand(&remoteexecution.Command{
+			Arguments:        []string{"cc"},
+			WorkingDirectory: "src/./lib",
+		})
+
+		requireRejected(t, "Invalid command: Invalid working directory \"src/./lib\": Path contains a \".\" component")
+	})
+
+	t.Run("OutputOutsideInputRoot", func(t *testing.T) {
+		expectCommand(&remoteexecution.Command{
+			Arguments:        []string{"cc"},
+			WorkingDirectory: "src",
+			OutputPaths:      []string{"../../hello.o"},
+		})
+
+		requireRejected(t, "Invalid command: Invalid output path \"../../hello.o\": Path resolves to a location outside the input root")
+	})
+
+	t.Run("OutputFileIsParent", func(t *testing.T) {
+		expectCommand(&remoteexecution.Command{
+			Arguments:   []string{"cc"},
+			OutputFiles: []string{"out/hello.o", "out"},
+		})
+
+		requireRejected(t, "Invalid command: Output file \"out\" is a parent of output path \"out/hello.o\"")
+	})
+
+	t.Run("FileAndDirectory", func(t *testing.T) {
+		expectCommand(&remoteexecution.Command{
+			Arguments:         []string{"cc"},
+			OutputFiles:       []string{"out/hello"},
+			OutputDirectories: []string{"out/hello"},
+		})
+
+		requireRejected(t, "Invalid command: Output path \"out/hello\" is listed both as an output file and an output directory")
+	})
+
+	t.Run("SameLocation", func(t *testing.T) {
+		// Output paths may refer to the same location, even if
+		// spelled differently.
+		expectCommand(&remoteexecution.Command{
+			Arguments:         []string{"cc"},
+			WorkingDirectory:  "src",
+			OutputFiles:       []string{"hello.o"},
+			OutputDirectories: []string{"../src/hello.o"},
+		})
+
+		requireRejected(t, "Invalid command: Output paths \"hello.o\" and \"../src/hello.o\" refer to the same location")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		expectCommand(&remoteexecution.Command{
+			Arguments:        []string{"cc", "-c", "hello.c"},
+			WorkingDirectory: "src",
+			EnvironmentVariables: []*remoteexecution.Command_EnvironmentVariable{
+				{Name: "PATH", Value: "/bin"},
+			},
+			OutputFiles:       []string{"hello.o", "../out/hello.o"},
+			OutputDirectories: []string{"../out"},
+		})
+		response := &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		}
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(response)
+
+		testutil.RequireEqualProto(
+			t,
+			response,
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+}
@@ -0,0 +1,201 @@
+package builder
+
+import (
+	"context"
+	"sync"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/util"
+)
+
+// ConcurrencyLimit holds the number of worker threads of a runner that
+// are permitted to request work from the scheduler. It may be changed
+// at runtime (e.g., when the configuration of the worker is reloaded).
+// Worker threads whose index exceeds the limit stop synchronizing
+// against the scheduler once they are idle.
+//
+// The limit also acts as the number of execution slots of the runner.
+// Actions that are executing hold one or more slots, depending on
+// their weight (see GetActionWeight()). Idle worker threads only
+// request work while slots are available.
+//
+// The slots of a runner may be shared by multiple size classes (see
+// WithSlotsPerAction()), so that a single runner can either run many
+// small actions or a few large ones.
+type ConcurrencyLimit struct {
+	*concurrencyLimitState
+	slotsPerAction int
+}
+
+type concurrencyLimitState struct {
+	lock      sync.Mutex
+	limit     int
+	usedSlots int
+	waiters   []*concurrencyLimitWaiter
+	changed   chan struct{}
+}
+
+type concurrencyLimitWaiter struct {
+	slots   int
+	granted chan struct{}
+}
+
+// NewConcurrencyLimit creates a ConcurrencyLimit that initially
+// permits a given number of worker threads to request work.
+func NewConcurrencyLimit(limit int) *ConcurrencyLimit {
+	return &ConcurrencyLimit{
+		concurrencyLimitState: &concurrencyLimitState{
+			limit:   limit,
+			changed: make(chan struct{}),
+		},
+		slotsPerAction: 1,
+	}
+}
+
+// WithSlotsPerAction returns a ConcurrencyLimit that shares its
+// execution slots with the current one, but lets every action hold at
+// least a given number of slots. Worker threads of the returned
+// ConcurrencyLimit are spread out accordingly, meaning that only a
+// single thread is enabled if the number of slots per action is equal
+// to the limit.
+//
+// This can be used to let a runner advertise multiple size classes,
+// where actions of larger size classes claim a larger share of the
+// runner's slots.
+func (cl *ConcurrencyLimit) WithSlotsPerAction(slotsPerAction int) *ConcurrencyLimit {
+	return &ConcurrencyLimit{
+		concurrencyLimitState: cl.concurrencyLimitState,
+		slotsPerAction:        slotsPerAction,
+	}
+}
+
+// getActionSlots returns the number of execution slots that an action
+// having a given weight holds.
+func (cl *ConcurrencyLimit) getActionSlots(weight int) int {
+	if weight < cl.slotsPerAction {
+		return cl.slotsPerAction
+	}
+	return weight
+}
+
+// notifyLocked wakes up all worker threads that are waiting for the
+// state of the ConcurrencyLimit to change.
+func (cl *concurrencyLimitState) notifyLocked() {
+	close(cl.changed)
+	cl.changed = make(chan struct{})
+}
+
+// grantLocked hands out execution slots to waiters in the order in
+// which they arrived, so that actions having a large weight are not
+// starved by lighter ones. Actions whose weight exceeds the limit are
+// permitted to run once no other actions are executing.
+func (cl *concurrencyLimitState) grantLocked() {
+	for len(cl.waiters) > 0 {
+		w := cl.waiters[0]
+		if cl.usedSlots > 0 && cl.usedSlots+w.slots > cl.limit {
+			break
+		}
+		cl.usedSlots += w.slots
+		close(w.granted)
+		cl.waiters = cl.waiters[1:]
+	}
+}
+
+// Set the number of worker threads that are permitted to request work.
+func (cl *ConcurrencyLimit) Set(limit int) {
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+	if cl.limit != limit {
+		cl.limit = limit
+		cl.grantLocked()
+		cl.notifyLocked()
+	}
+}
+
+// WaitUntilThreadEnabled blocks until the worker thread having a given
+// index is permitted to request work. It returns false if the context
+// is canceled before that happens.
+func (cl *ConcurrencyLimit) WaitUntilThreadEnabled(ctx context.Context, threadIndex int) bool {
+	for {
+		cl.lock.Lock()
+		enabled := threadIndex*cl.slotsPerAction < cl.limit && cl.usedSlots < cl.limit && len(cl.waiters) == 0
+		changed := cl.changed
+		cl.lock.Unlock()
+		if enabled {
+			return true
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// AcquireSlots blocks until a given number of execution slots are
+// available, and marks them as being in use.
+func (cl *ConcurrencyLimit) AcquireSlots(ctx context.Context, slots int) error {
+	slots = cl.getActionSlots(slots)
+	w := &concurrencyLimitWaiter{
+		slots:   slots,
+		granted: make(chan struct{}),
+	}
+	cl.lock.Lock()
+	cl.waiters = append(cl.waiters, w)
+	cl.grantLocked()
+	cl.notifyLocked()
+	cl.lock.Unlock()
+
+	select {
+	case <-w.granted:
+		return nil
+	case <-ctx.Done():
+	}
+
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+	select {
+	case <-w.granted:
+		// Slots were granted while the context was canceled.
+		cl.usedSlots -= slots
+	default:
+		for i, wOther := range cl.waiters {
+			if wOther == w {
+				cl.waiters = append(cl.waiters[:i], cl.waiters[i+1:]...)
+				break
+			}
+		}
+	}
+	cl.grantLocked()
+	cl.notifyLocked()
+	return util.StatusFromContext(ctx)
+}
+
+// ReleaseSlots marks execution slots that were previously acquired
+// through AcquireSlots() as no longer being in use.
+func (cl *ConcurrencyLimit) ReleaseSlots(slots int) {
+	slots = cl.getActionSlots(slots)
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+	cl.usedSlots -= slots
+	cl.grantLocked()
+	cl.notifyLocked()
+}
+
+// getExecutionSlots returns the total number of execution slots, and
+// the number of slots that are available for new actions. No slots are
+// reported as available while actions are waiting for slots to be
+// released.
+func (cl *ConcurrencyLimit) getExecutionSlots() *remoteworker.ExecutionSlots {
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+	executionSlots := &remoteworker.ExecutionSlots{Total: uint32(cl.limit)}
+	if cl.slotsPerAction > 1 {
+		executionSlots.SlotsPerAction = uint32(cl.slotsPerAction)
+	}
+	if len(cl.waiters) == 0 && cl.usedSlots < cl.limit {
+		executionSlots.Available = uint32(cl.limit - cl.usedSlots)
+	}
+	return executionSlots
+}
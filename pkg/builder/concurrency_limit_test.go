@@ -0,0 +1,138 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	concurrencyLimit := builder.NewConcurrencyLimit(2)
+
+	t.Run("Enabled", func(t *testing.T) {
+		require.True(t, concurrencyLimit.WaitUntilThreadEnabled(context.Background(), 0))
+		require.True(t, concurrencyLimit.WaitUntilThreadEnabled(context.Background(), 1))
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		// Threads beyond the limit should block until the
+		// context is canceled.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.False(t, concurrencyLimit.WaitUntilThreadEnabled(ctx, 2))
+	})
+
+	t.Run("Raised", func(t *testing.T) {
+		// Raising the limit should wake up threads that are
+		// waiting.
+		enabled := make(chan bool)
+		go func() {
+			enabled <- concurrencyLimit.WaitUntilThreadEnabled(context.Background(), 3)
+		}()
+		concurrencyLimit.Set(3)
+		concurrencyLimit.Set(4)
+		require.True(t, <-enabled)
+	})
+}
+
+func TestConcurrencyLimitSlots(t *testing.T) {
+	concurrencyLimit := builder.NewConcurrencyLimit(4)
+
+	t.Run("AcquireRelease", func(t *testing.T) {
+		// An action consuming three slots should leave one
+		// slot available for other actions.
+		require.NoError(t, concurrencyLimit.AcquireSlots(context.Background(), 3))
+		require.True(t, concurrencyLimit.WaitUntilThreadEnabled(context.Background(), 1))
+		require.NoError(t, concurrencyLimit.AcquireSlots(context.Background(), 1))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.False(t, concurrencyLimit.WaitUntilThreadEnabled(ctx, 2))
+
+		concurrencyLimit.ReleaseSlots(1)
+		concurrencyLimit.ReleaseSlots(3)
+	})
+
+	t.Run("FirstComeFirstServed", func(t *testing.T) {
+		// Actions waiting for slots should be granted them in
+		// the order in which they arrived, so that heavy
+		// actions are not starved by lighter ones.
+		require.NoError(t, concurrencyLimit.AcquireSlots(context.Background(), 2))
+		acquired := make(chan struct{})
+		go func() {
+			require.NoError(t, concurrencyLimit.AcquireSlots(context.Background(), 4))
+			close(acquired)
+		}()
+		for {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if !concurrencyLimit.WaitUntilThreadEnabled(ctx, 0) {
+				break
+			}
+		}
+
+		// Light actions should not overtake the heavy one.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.Error(t, concurrencyLimit.AcquireSlots(ctx, 1))
+
+		concurrencyLimit.ReleaseSlots(2)
+		<-acquired
+		concurrencyLimit.ReleaseSlots(4)
+		require.True(t, concurrencyLimit.WaitUntilThreadEnabled(context.Background(), 3))
+	})
+
+	t.Run("ExceedingLimit", func(t *testing.T) {
+		// Actions whose weight exceeds the limit should be
+		// permitted to run in isolation.
+		require.NoError(t, concurrencyLimit.AcquireSlots(context.Background(), 8))
+		concurrencyLimit.ReleaseSlots(8)
+	})
+}
+
+func TestConcurrencyLimitWithSlotsPerAction(t *testing.T) {
+	smallConcurrencyLimit := builder.NewConcurrencyLimit(8)
+	largeConcurrencyLimit := smallConcurrencyLimit.WithSlotsPerAction(8)
+
+	t.Run("Threads", func(t *testing.T) {
+		// Only a single thread of the large size class should
+		// be enabled, as every action holds all slots.
+		require.True(t, largeConcurrencyLimit.WaitUntilThreadEnabled(context.Background(), 0))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.False(t, largeConcurrencyLimit.WaitUntilThreadEnabled(ctx, 1))
+	})
+
+	t.Run("ClaimAllSlots", func(t *testing.T) {
+		// An action of the large size class should wait for
+		// actions of the small size class to complete. In the
+		// meantime, no further actions of the small size class
+		// should be accepted.
+		require.NoError(t, smallConcurrencyLimit.AcquireSlots(context.Background(), 1))
+		acquired := make(chan struct{})
+		go func() {
+			require.NoError(t, largeConcurrencyLimit.AcquireSlots(context.Background(), 1))
+			close(acquired)
+		}()
+		for {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if !smallConcurrencyLimit.WaitUntilThreadEnabled(ctx, 0) {
+				break
+			}
+		}
+
+		smallConcurrencyLimit.ReleaseSlots(1)
+		<-acquired
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.False(t, smallConcurrencyLimit.WaitUntilThreadEnabled(ctx, 0))
+
+		largeConcurrencyLimit.ReleaseSlots(1)
+		require.True(t, smallConcurrencyLimit.WaitUntilThreadEnabled(context.Background(), 7))
+	})
+}
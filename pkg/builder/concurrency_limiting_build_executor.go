@@ -0,0 +1,47 @@
+package builder
+
+import (
+	"context"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+)
+
+type concurrencyLimitingBuildExecutor struct {
+	BuildExecutor
+	concurrencyLimit *ConcurrencyLimit
+}
+
+// NewConcurrencyLimitingBuildExecutor creates a decorator for
+// BuildExecutor that lets actions hold a number of execution slots of
+// a ConcurrencyLimit while executing, based on their weight. Actions
+// are delayed until enough slots are available, thereby preventing
+// actions that use many CPU cores from running alongside too many
+// other actions.
+func NewConcurrencyLimitingBuildExecutor(buildExecutor BuildExecutor, concurrencyLimit *ConcurrencyLimit) BuildExecutor {
+	return &concurrencyLimitingBuildExecutor{
+		BuildExecutor:    buildExecutor,
+		concurrencyLimit: concurrencyLimit,
+	}
+}
+
+func (be *concurrencyLimitingBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	weight, err := GetActionWeight(request.Action.GetPlatform())
+	if err != nil {
+		response := NewDefaultExecuteResponse(request)
+		attachErrorToExecuteResponse(response, err)
+		return response
+	}
+	if err := be.concurrencyLimit.AcquireSlots(ctx, weight); err != nil {
+		response := NewDefaultExecuteResponse(request)
+		attachErrorToExecuteResponse(response, util.StatusWrapf(err, "Failed to acquire %d execution slots", weight))
+		return response
+	}
+	defer be.concurrencyLimit.ReleaseSlots(weight)
+
+	return be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+}
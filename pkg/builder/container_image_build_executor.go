@@ -0,0 +1,182 @@
+package builder
+
+import (
+	"context"
+	"sort"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-remote-execution/pkg/containerimage"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type containerImageBuildExecutor struct {
+	BuildExecutor
+	contentAddressableStorage blobstore.BlobAccess
+	directoryFetcher          cas.DirectoryFetcher
+	resolver                  containerimage.Resolver
+	platformPropertyName      string
+}
+
+// NewContainerImageBuildExecutor creates a decorator for BuildExecutor
+// that provides container semantics to actions, without running a
+// container runtime on the worker. Actions that set the configured
+// platform property (typically "container-image") to a container image
+// reference get the root file system of the image placed beneath their
+// input root. Files in the input root take precedence over files in
+// the image.
+//
+// The root file system of the image is obtained from a Resolver, which
+// stores it in the Content Addressable Storage. The merged input root
+// is stored in the Content Addressable Storage as well, meaning that
+// it is instantiated like any other input root.
+func NewContainerImageBuildExecutor(buildExecutor BuildExecutor, contentAddressableStorage blobstore.BlobAccess, directoryFetcher cas.DirectoryFetcher, resolver containerimage.Resolver, platformPropertyName string) BuildExecutor {
+	return &containerImageBuildExecutor{
+		BuildExecutor:             buildExecutor,
+		contentAddressableStorage: contentAddressableStorage,
+		directoryFetcher:          directoryFetcher,
+		resolver:                  resolver,
+		platformPropertyName:      platformPropertyName,
+	}
+}
+
+func (be *containerImageBuildExecutor) Execute(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	imageReference := ""
+	for _, property := range request.Action.GetPlatform().GetProperties() {
+		if property.Name == be.platformPropertyName {
+			imageReference = property.Value
+		}
+	}
+	if imageReference == "" {
+		return be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+	}
+
+	response := NewDefaultExecuteResponse(request)
+	inputRootDigest, err := digestFunction.NewDigestFromProto(request.Action.InputRootDigest)
+	if err != nil {
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to extract digest for input root"))
+		return response
+	}
+	imageRootDigest, err := be.resolver.Resolve(ctx, digestFunction, imageReference)
+	if err != nil {
+		attachErrorToExecuteResponse(response, util.StatusWrapf(err, "Failed to resolve container image %#v", imageReference))
+		return response
+	}
+	mergedInputRootDigest, err := be.mergeDirectories(ctx, digestFunction, imageRootDigest, inputRootDigest)
+	if err != nil {
+		attachErrorToExecuteResponse(response, util.StatusWrapf(err, "Failed to merge input root with container image %#v", imageReference))
+		return response
+	}
+
+	newRequest := proto.Clone(request).(*remoteworker.DesiredState_Executing)
+	newRequest.Action.InputRootDigest = mergedInputRootDigest.GetProto()
+	return be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, newRequest, executionStateUpdates)
+}
+
+// mergeDirectories computes the union of two directory trees stored in
+// the Content Addressable Storage. Entries in the upper directory take
+// precedence over entries in the lower directory, except if both
+// entries are directories, in which case they are merged recursively.
+func (be *containerImageBuildExecutor) mergeDirectories(ctx context.Context, digestFunction digest.Function, lowerDigest, upperDigest digest.Digest) (digest.Digest, error) {
+	lower, err := be.directoryFetcher.GetDirectory(ctx, lowerDigest)
+	if err != nil {
+		return digest.BadDigest, util.StatusWrapf(err, "Failed to obtain directory %#v", lowerDigest.String())
+	}
+	upper, err := be.directoryFetcher.GetDirectory(ctx, upperDigest)
+	if err != nil {
+		return digest.BadDigest, util.StatusWrapf(err, "Failed to obtain directory %#v", upperDigest.String())
+	}
+
+	// Entries in the upper directory shadow entries in the lower
+	// directory having the same name.
+	upperNames := map[string]struct{}{}
+	for _, node := range upper.Directories {
+		upperNames[node.Name] = struct{}{}
+	}
+	for _, node := range upper.Files {
+		upperNames[node.Name] = struct{}{}
+	}
+	for _, node := range upper.Symlinks {
+		upperNames[node.Name] = struct{}{}
+	}
+	lowerDirectories := map[string]*remoteexecution.DirectoryNode{}
+	merged := &remoteexecution.Directory{
+		Files:          append([]*remoteexecution.FileNode(nil), upper.Files...),
+		Symlinks:       append([]*remoteexecution.SymlinkNode(nil), upper.Symlinks...),
+		NodeProperties: upper.NodeProperties,
+	}
+	for _, node := range lower.Directories {
+		if _, ok := upperNames[node.Name]; ok {
+			lowerDirectories[node.Name] = node
+		} else {
+			merged.Directories = append(merged.Directories, node)
+		}
+	}
+	for _, node := range lower.Files {
+		if _, ok := upperNames[node.Name]; !ok {
+			merged.Files = append(merged.Files, node)
+		}
+	}
+	for _, node := range lower.Symlinks {
+		if _, ok := upperNames[node.Name]; !ok {
+			merged.Symlinks = append(merged.Symlinks, node)
+		}
+	}
+
+	// Directories that are present in both directories are merged
+	// recursively.
+	for _, node := range upper.Directories {
+		lowerNode, ok := lowerDirectories[node.Name]
+		if !ok {
+			merged.Directories = append(merged.Directories, node)
+			continue
+		}
+		lowerChildDigest, err := digestFunction.NewDigestFromProto(lowerNode.Digest)
+		if err != nil {
+			return digest.BadDigest, util.StatusWrapf(err, "Invalid digest for directory %#v", node.Name)
+		}
+		upperChildDigest, err := digestFunction.NewDigestFromProto(node.Digest)
+		if err != nil {
+			return digest.BadDigest, util.StatusWrapf(err, "Invalid digest for directory %#v", node.Name)
+		}
+		mergedChildDigest, err := be.mergeDirectories(ctx, digestFunction, lowerChildDigest, upperChildDigest)
+		if err != nil {
+			return digest.BadDigest, util.StatusWrapf(err, "Directory %#v", node.Name)
+		}
+		merged.Directories = append(merged.Directories, &remoteexecution.DirectoryNode{
+			Name:   node.Name,
+			Digest: mergedChildDigest.GetProto(),
+		})
+	}
+	sort.Slice(merged.Directories, func(i, j int) bool {
+		return merged.Directories[i].Name < merged.Directories[j].Name
+	})
+	sort.Slice(merged.Files, func(i, j int) bool {
+		return merged.Files[i].Name < merged.Files[j].Name
+	})
+	sort.Slice(merged.Symlinks, func(i, j int) bool {
+		return merged.Symlinks[i].Name < merged.Symlinks[j].Name
+	})
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(merged)
+	if err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to marshal merged directory")
+	}
+	digestGenerator := digestFunction.NewGenerator(int64(len(data)))
+	if _, err := digestGenerator.Write(data); err != nil {
+		panic(err)
+	}
+	mergedDigest := digestGenerator.Sum()
+	if err := be.contentAddressableStorage.Put(ctx, mergedDigest, buffer.NewValidatedBufferFromByteSlice(data)); err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to store merged directory")
+	}
+	return mergedDigest, nil
+}
@@ -0,0 +1,173 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestContainerImageBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	resolver := mock.NewMockResolver(ctrl)
+	buildExecutor := builder.NewContainerImageBuildExecutor(
+		baseBuildExecutor,
+		contentAddressableStorage,
+		directoryFetcher,
+		resolver,
+		"container-image")
+
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5)
+	executionStateUpdates := make(chan *remoteworker.CurrentState_Executing, 3)
+	inputRootDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "095afbd64b6358665546558583c64d38", 456)
+	newRequest := func(imageReference string) *remoteworker.DesiredState_Executing {
+		platform := &remoteexecution.Platform{}
+		if imageReference != "" {
+			platform.Properties = append(platform.Properties, &remoteexecution.Platform_Property{
+				Name:  "container-image",
+				Value: imageReference,
+			})
+		}
+		return &remoteworker.DesiredState_Executing{
+			Action: &remoteexecution.Action{
+				InputRootDigest: inputRootDigest.GetProto(),
+				Platform:        platform,
+			},
+		}
+	}
+	successfulResponse := &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+		},
+	}
+
+	t.Run("NoContainerImage", func(t *testing.T) {
+		// Without the platform property, the request should be
+		// forwarded as is.
+		request := newRequest("")
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(successfulResponse)
+
+		testutil.RequireEqualProto(
+			t,
+			successfulResponse,
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("ResolutionFailure", func(t *testing.T) {
+		resolver.EXPECT().Resolve(ctx, digestFunction, "docker://example.com/image:latest").
+			Return(digest.BadDigest, status.Error(codes.NotFound, "Image not found"))
+
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+			Status: status.New(codes.NotFound, "Failed to resolve container image \"docker://example.com/image:latest\": Image not found").Proto(),
+		}, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, newRequest("docker://example.com/image:latest"), executionStateUpdates))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The input root should be placed on top of the root
+		// file system of the image. Directories that exist in
+		// both should be merged. Files in the input root
+		// should take precedence.
+		imageRootDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "2b5a0e9f8ab5ec2e86c04a4c6a1b5d33", 300)
+		imageEtcDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "7e9c1f3a5b2d4c6e8f0a1b3c5d7e9f1a", 100)
+		inputRootEtcDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "a4c6e8f0b2d4f6a8c0e2b4d6f8a0c2e4", 100)
+		binDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "c1e3a5b7d9f1a3c5e7b9d1f3a5c7e9b1", 100)
+		resolver.EXPECT().Resolve(ctx, digestFunction, "ubuntu:22.04").Return(imageRootDigest, nil)
+		directoryFetcher.EXPECT().GetDirectory(ctx, imageRootDigest).Return(&remoteexecution.Directory{
+			Directories: []*remoteexecution.DirectoryNode{
+				{Name: "bin", Digest: binDigest.GetProto()},
+				{Name: "etc", Digest: imageEtcDigest.GetProto()},
+			},
+			Files: []*remoteexecution.FileNode{
+				{Name: "README", Digest: &remoteexecution.Digest{Hash: "0cc175b9c0f1b6a831c399e269772661", SizeBytes: 1}},
+			},
+			Symlinks: []*remoteexecution.SymlinkNode{
+				{Name: "lib", Target: "usr/lib"},
+			},
+		}, nil)
+		directoryFetcher.EXPECT().GetDirectory(ctx, inputRootDigest).Return(&remoteexecution.Directory{
+			Directories: []*remoteexecution.DirectoryNode{
+				{Name: "etc", Digest: inputRootEtcDigest.GetProto()},
+			},
+			Files: []*remoteexecution.FileNode{
+				{Name: "README", Digest: &remoteexecution.Digest{Hash: "92eb5ffee6ae2fec3ad71c777531578f", SizeBytes: 1}},
+			},
+		}, nil)
+		directoryFetcher.EXPECT().GetDirectory(ctx, imageEtcDigest).Return(&remoteexecution.Directory{
+			Files: []*remoteexecution.FileNode{
+				{Name: "hosts", Digest: &remoteexecution.Digest{Hash: "4a8a08f09d37b73795649038408b5f33", SizeBytes: 1}},
+				{Name: "passwd", Digest: &remoteexecution.Digest{Hash: "8277e0910d750195b448797616e091ad", SizeBytes: 1}},
+			},
+		}, nil)
+		directoryFetcher.EXPECT().GetDirectory(ctx, inputRootEtcDigest).Return(&remoteexecution.Directory{
+			Files: []*remoteexecution.FileNode{
+				{Name: "hosts", Digest: &remoteexecution.Digest{Hash: "e1671797c52e15f763380b45e841ec32", SizeBytes: 1}},
+			},
+		}, nil)
+
+		storedDirectories := map[digest.Digest]buffer.Buffer{}
+		contentAddressableStorage.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				storedDirectories[blobDigest] = b
+				return nil
+			}).Times(2)
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, gomock.Any(), executionStateUpdates).DoAndReturn(
+			func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+				mergedRootDigest, err := digestFunction.NewDigestFromProto(request.Action.InputRootDigest)
+				require.NoError(t, err)
+				mergedRoot, err := storedDirectories[mergedRootDigest].ToProto(&remoteexecution.Directory{}, 10000)
+				require.NoError(t, err)
+				require.Len(t, mergedRoot.(*remoteexecution.Directory).Directories, 2)
+				mergedEtcDigest, err := digestFunction.NewDigestFromProto(mergedRoot.(*remoteexecution.Directory).Directories[1].Digest)
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, &remoteexecution.Directory{
+					Directories: []*remoteexecution.DirectoryNode{
+						{Name: "bin", Digest: binDigest.GetProto()},
+						{Name: "etc", Digest: mergedEtcDigest.GetProto()},
+					},
+					Files: []*remoteexecution.FileNode{
+						{Name: "README", Digest: &remoteexecution.Digest{Hash: "92eb5ffee6ae2fec3ad71c777531578f", SizeBytes: 1}},
+					},
+					Symlinks: []*remoteexecution.SymlinkNode{
+						{Name: "lib", Target: "usr/lib"},
+					},
+				}, mergedRoot)
+
+				mergedEtc, err := storedDirectories[mergedEtcDigest].ToProto(&remoteexecution.Directory{}, 10000)
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, &remoteexecution.Directory{
+					Files: []*remoteexecution.FileNode{
+						{Name: "hosts", Digest: &remoteexecution.Digest{Hash: "e1671797c52e15f763380b45e841ec32", SizeBytes: 1}},
+						{Name: "passwd", Digest: &remoteexecution.Digest{Hash: "8277e0910d750195b448797616e091ad", SizeBytes: 1}},
+					},
+				}, mergedEtc)
+				return successfulResponse
+			})
+
+		testutil.RequireEqualProto(
+			t,
+			successfulResponse,
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, newRequest("ubuntu:22.04"), executionStateUpdates))
+	})
+}
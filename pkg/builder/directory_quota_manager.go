@@ -0,0 +1,22 @@
+package builder
+
+// DirectoryQuotaManager is used by
+// NewQuotaEnforcingBuildDirectoryCreator() to measure the size of
+// build directories and, if supported, let the operating system enforce
+// limits on their size.
+type DirectoryQuotaManager interface {
+	// Start tracking the size of a directory on the local file
+	// system. The directory is expected to be empty.
+	NewDirectoryQuota(directoryPath string, maximumSizeBytes int64) (DirectoryQuota, error)
+}
+
+// DirectoryQuota is a handle to a directory whose size is tracked by a
+// DirectoryQuotaManager.
+type DirectoryQuota interface {
+	// Return the total size of all files stored in the directory.
+	GetSizeBytes() (int64, error)
+
+	// Stop tracking the size of the directory. This should only be
+	// called after the directory has been removed.
+	Release() error
+}
@@ -0,0 +1,292 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ExecutablePolicy describes which executables build actions are
+// permitted to invoke as their top-level command (i.e., argv[0]).
+type ExecutablePolicy struct {
+	// Paths of executables that may be invoked. If both AllowedPaths
+	// and AllowedDigests are empty, any executable that is not denied
+	// may be invoked.
+	AllowedPaths []string
+	// Paths of executables that may not be invoked.
+	DeniedPaths []string
+	// Digests of executables stored in the input root that may be
+	// invoked.
+	AllowedDigests []*remoteexecution.Digest
+	// Digests of executables stored in the input root that may not
+	// be invoked.
+	DeniedDigests []*remoteexecution.Digest
+}
+
+type executableValidatingBuildExecutor struct {
+	BuildExecutor
+	contentAddressableStorage blobstore.BlobAccess
+	directoryFetcher          cas.DirectoryFetcher
+	maximumMessageSizeBytes   int
+
+	allowedPaths   map[string]struct{}
+	deniedPaths    map[string]struct{}
+	allowedDigests map[string]struct{}
+	deniedDigests  map[string]struct{}
+}
+
+// NewExecutableValidatingBuildExecutor creates a decorator for
+// BuildExecutor that rejects actions whose top-level command is not
+// permitted by an ExecutablePolicy. This can be used as a guardrail on
+// workers that should only run a fixed set of tools.
+//
+// Executables can either be matched by path or by the digest of the
+// file in the input root that is referenced by it. Paths are matched
+// as follows:
+//
+//   - Absolute paths are normalized lexically, meaning that
+//     "/usr//bin/../bin/python3" is matched as "/usr/bin/python3".
+//   - Relative paths containing a slash are resolved against the
+//     working directory, and are matched relative to the input root.
+//     For example, "../tools/compiler" with working directory "src" is
+//     matched as "tools/compiler". Only these paths can be matched by
+//     digest.
+//   - Bare names without a slash (e.g., "python3") are subject to
+//     PATH lookups performed by the runner. As the executable that is
+//     ultimately invoked cannot be determined by the worker, these are
+//     only matched literally against bare names in the policy.
+//
+// Paths in the policy are normalized in the same way, where relative
+// paths are interpreted relative to the input root.
+func NewExecutableValidatingBuildExecutor(buildExecutor BuildExecutor, contentAddressableStorage blobstore.BlobAccess, directoryFetcher cas.DirectoryFetcher, maximumMessageSizeBytes int, policy *ExecutablePolicy) (BuildExecutor, error) {
+	be := &executableValidatingBuildExecutor{
+		BuildExecutor:             buildExecutor,
+		contentAddressableStorage: contentAddressableStorage,
+		directoryFetcher:          directoryFetcher,
+		maximumMessageSizeBytes:   maximumMessageSizeBytes,
+
+		allowedPaths:   map[string]struct{}{},
+		deniedPaths:    map[string]struct{}{},
+		allowedDigests: map[string]struct{}{},
+		deniedDigests:  map[string]struct{}{},
+	}
+	for _, p := range policy.AllowedPaths {
+		normalized, err := newExecutablePath("", p)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Invalid allowed path %#v", p)
+		}
+		be.allowedPaths[normalized.String()] = struct{}{}
+	}
+	for _, p := range policy.DeniedPaths {
+		normalized, err := newExecutablePath("", p)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Invalid denied path %#v", p)
+		}
+		be.deniedPaths[normalized.String()] = struct{}{}
+	}
+	for _, d := range policy.AllowedDigests {
+		be.allowedDigests[getExecutableDigestKey(d)] = struct{}{}
+	}
+	for _, d := range policy.DeniedDigests {
+		be.deniedDigests[getExecutableDigestKey(d)] = struct{}{}
+	}
+	return be, nil
+}
+
+func getExecutableDigestKey(d *remoteexecution.Digest) string {
+	return fmt.Sprintf("%s-%d", d.GetHash(), d.GetSizeBytes())
+}
+
+// executablePath is the normalized path of an executable.
+type executablePath struct {
+	bareName   string
+	absolute   bool
+	components []string
+}
+
+// newExecutablePath normalizes the path of an executable, as described
+// in the documentation of NewExecutableValidatingBuildExecutor().
+func newExecutablePath(workingDirectory, argv0 string) (executablePath, error) {
+	if !strings.ContainsRune(argv0, '/') {
+		return executablePath{bareName: argv0}, nil
+	}
+	var p executablePath
+	if !strings.HasPrefix(argv0, "/") {
+		if err := path.Resolve(workingDirectory, path.NewRelativeScopeWalker(&p)); err != nil {
+			return executablePath{}, util.StatusWrap(err, "Failed to resolve working directory")
+		}
+	}
+	if err := path.Resolve(argv0, &p); err != nil {
+		return executablePath{}, util.StatusWrap(err, "Failed to resolve executable")
+	}
+	if len(p.components) == 0 {
+		return executablePath{}, status.Error(codes.InvalidArgument, "Executable does not refer to a file")
+	}
+	return p, nil
+}
+
+func (p *executablePath) String() string {
+	if p.absolute {
+		return "/" + strings.Join(p.components, "/")
+	}
+	if p.components == nil {
+		return p.bareName
+	}
+	return strings.Join(p.components, "/")
+}
+
+// isInInputRoot returns true if the executable is stored in the input
+// root, meaning its digest can be determined.
+func (p *executablePath) isInInputRoot() bool {
+	return !p.absolute && p.components != nil
+}
+
+// executablePath implements path.ScopeWalker and path.ComponentWalker,
+// so that pathname strings can be normalized by calling path.Resolve().
+// As the executable may reside outside the input root, symbolic links
+// cannot be expanded, meaning ".." components are processed lexically.
+
+func (p *executablePath) OnScope(absolute bool) (path.ComponentWalker, error) {
+	if absolute {
+		p.absolute = true
+		p.components = p.components[:0]
+	}
+	return p, nil
+}
+
+func (p *executablePath) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
+	p.components = append(p.components, name.String())
+	return path.GotDirectory{
+		Child:        p,
+		IsReversible: true,
+	}, nil
+}
+
+func (p *executablePath) OnTerminal(name path.Component) (*path.GotSymlink, error) {
+	p.components = append(p.components, name.String())
+	return nil, nil
+}
+
+func (p *executablePath) OnUp() (path.ComponentWalker, error) {
+	if len(p.components) > 0 {
+		p.components = p.components[:len(p.components)-1]
+	} else if !p.absolute {
+		return nil, status.Error(codes.InvalidArgument, "Path resolves to a location outside the input root")
+	}
+	return p, nil
+}
+
+// getExecutableDigest looks up the digest of the file in the input
+// root that corresponds to the executable of the action.
+func (be *executableValidatingBuildExecutor) getExecutableDigest(ctx context.Context, digestFunction digest.Function, action *remoteexecution.Action, executable *executablePath) (*remoteexecution.Digest, error) {
+	directoryDigest, err := digestFunction.NewDigestFromProto(action.InputRootDigest)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to extract digest for input root")
+	}
+	components := executable.components
+	for i, component := range components {
+		directory, err := be.directoryFetcher.GetDirectory(ctx, directoryDigest)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Failed to fetch directory %#v", strings.Join(components[:i], "/"))
+		}
+		if i == len(components)-1 {
+			for _, file := range directory.Files {
+				if file.Name == component {
+					return file.Digest, nil
+				}
+			}
+			return nil, status.Errorf(codes.InvalidArgument, "Executable %#v does not refer to a regular file in the input root", executable.String())
+		}
+		found := false
+		for _, child := range directory.Directories {
+			if child.Name == component {
+				if directoryDigest, err = digestFunction.NewDigestFromProto(child.Digest); err != nil {
+					return nil, util.StatusWrapf(err, "Failed to extract digest for directory %#v", strings.Join(components[:i+1], "/"))
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, status.Errorf(codes.InvalidArgument, "Executable %#v does not refer to a regular file in the input root", executable.String())
+		}
+	}
+	panic("Path should contain at least one component")
+}
+
+// validateCommand returns an error if the command may not be executed
+// according to the policy.
+func (be *executableValidatingBuildExecutor) validateCommand(ctx context.Context, digestFunction digest.Function, action *remoteexecution.Action) error {
+	commandDigest, err := digestFunction.NewDigestFromProto(action.CommandDigest)
+	if err != nil {
+		return util.StatusWrap(err, "Failed to extract digest for command")
+	}
+	commandMessage, err := be.contentAddressableStorage.Get(ctx, commandDigest).ToProto(&remoteexecution.Command{}, be.maximumMessageSizeBytes)
+	if err != nil {
+		return util.StatusWrap(err, "Failed to obtain command")
+	}
+	command := commandMessage.(*remoteexecution.Command)
+	if len(command.Arguments) == 0 {
+		return status.Error(codes.InvalidArgument, "Command does not contain any arguments")
+	}
+	argv0 := command.Arguments[0]
+	executable, err := newExecutablePath(command.WorkingDirectory, argv0)
+	if err != nil {
+		return util.StatusWrapf(err, "Invalid executable %#v", argv0)
+	}
+	executableStr := executable.String()
+	if _, ok := be.deniedPaths[executableStr]; ok {
+		return status.Errorf(codes.PermissionDenied, "Executable %#v is denied on this worker", argv0)
+	}
+	_, allowed := be.allowedPaths[executableStr]
+
+	// Only look up the digest of the executable if the policy
+	// depends on it. Denied digests are checked even if the path
+	// is allowed explicitly.
+	if executable.isInInputRoot() && (len(be.deniedDigests) > 0 || (!allowed && len(be.allowedDigests) > 0)) {
+		executableDigest, err := be.getExecutableDigest(ctx, digestFunction, action, &executable)
+		if err != nil {
+			return err
+		}
+		key := getExecutableDigestKey(executableDigest)
+		if _, ok := be.deniedDigests[key]; ok {
+			return status.Errorf(codes.PermissionDenied, "Executable %#v with digest %s is denied on this worker", argv0, key)
+		}
+		if _, ok := be.allowedDigests[key]; ok {
+			allowed = true
+		}
+	}
+
+	if !allowed && (len(be.allowedPaths) > 0 || len(be.allowedDigests) > 0) {
+		return status.Errorf(codes.PermissionDenied, "Executable %#v is not permitted on this worker", argv0)
+	}
+	return nil
+}
+
+func (be *executableValidatingBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	action := request.Action
+	if action == nil {
+		response := NewDefaultExecuteResponse(request)
+		attachErrorToExecuteResponse(response, status.Error(codes.InvalidArgument, "Request does not contain an action"))
+		return response
+	}
+	if err := be.validateCommand(ctx, digestFunction, action); err != nil {
+		response := NewDefaultExecuteResponse(request)
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Command rejected by executable policy"))
+		return response
+	}
+	return be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+}
@@ -0,0 +1,248 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExecutableValidatingBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	buildExecutor, err := builder.NewExecutableValidatingBuildExecutor(
+		baseBuildExecutor,
+		contentAddressableStorage,
+		directoryFetcher,
+		/* maximumMessageSizeBytes = */ 10000,
+		&builder.ExecutablePolicy{
+			AllowedPaths: []string{"/usr/bin/python3", "bash", "./scripts//run.sh"},
+			DeniedPaths:  []string{"/bin/sh"},
+			AllowedDigests: []*remoteexecution.Digest{{
+				Hash:      "0a6b3b3e2a8d8e4b3c7cc3b0c9df5a7a",
+				SizeBytes: 1200,
+			}},
+		})
+	require.NoError(t, err)
+
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5)
+	request := &remoteworker.DesiredState_Executing{
+		Action: &remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "e72499b7c6de12b7ad046541f6de8beb",
+				SizeBytes: 123,
+			},
+			InputRootDigest: &remoteexecution.Digest{
+				Hash:      "095afbd64b6358665546558583c64d38",
+				SizeBytes: 456,
+			},
+		},
+	}
+	commandDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "e72499b7c6de12b7ad046541f6de8beb", 123)
+	inputRootDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "095afbd64b6358665546558583c64d38", 456)
+	executionStateUpdates := make(chan *remoteworker.CurrentState_Executing, 3)
+
+	expectCommand := func(arguments ...string) {
+		contentAddressableStorage.EXPECT().Get(ctx, commandDigest).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Command{
+			Arguments:        arguments,
+			WorkingDirectory: "src",
+		}, buffer.UserProvided))
+	}
+	rejectedResponse := func(code codes.Code, message string) *remoteexecution.ExecuteResponse {
+		return &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+			Status: status.New(code, message).Proto(),
+		}
+	}
+
+	t.Run("DeniedPath", func(t *testing.T) {
+		expectCommand("/bin/sh", "-c", "echo hello")
+
+		testutil.RequireEqualProto(
+			t,
+			rejectedResponse(codes.PermissionDenied, "Command rejected by executable policy: Executable \"/bin/sh\" is denied on this worker"),
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("DeniedPathUnnormalized", func(t *testing.T) {
+		// Paths should be normalized before being compared
+		// against the policy.
+		expectCommand("//bin/../usr/./../bin/sh", "-c", "echo hello")
+
+		testutil.RequireEqualProto(
+			t,
+			rejectedResponse(codes.PermissionDenied, "Command rejected by executable policy: Executable \"//bin/../usr/./../bin/sh\" is denied on this worker"),
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("UnlistedBareName", func(t *testing.T) {
+		// Bare names are subject to PATH lookups. They can
+		// only be matched against bare names in the policy.
+		expectCommand("sh", "-c", "echo hello")
+
+		testutil.RequireEqualProto(
+			t,
+			rejectedResponse(codes.PermissionDenied, "Command rejected by executable policy: Executable \"sh\" is not permitted on this worker"),
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("AllowedBareName", func(t *testing.T) {
+		expectCommand("bash", "-c", "echo hello")
+		response := &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		}
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(response)
+
+		testutil.RequireEqualProto(
+			t,
+			response,
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("AllowedRelativePath", func(t *testing.T) {
+		// Relative paths are resolved against the working
+		// directory, and compared relative to the input root.
+		expectCommand("../scripts/run.sh")
+		response := &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		}
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(response)
+
+		testutil.RequireEqualProto(
+			t,
+			response,
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("UnlistedPath", func(t *testing.T) {
+		// Absolute paths cannot be matched by digest, meaning
+		// they need to be listed explicitly.
+		expectCommand("/usr/bin/perl", "script.pl")
+
+		testutil.RequireEqualProto(
+			t,
+			rejectedResponse(codes.PermissionDenied, "Command rejected by executable policy: Executable \"/usr/bin/perl\" is not permitted on this worker"),
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("AllowedPath", func(t *testing.T) {
+		expectCommand("/usr/bin/python3", "script.py")
+		response := &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		}
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(response)
+
+		testutil.RequireEqualProto(
+			t,
+			response,
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	// Directory hierarchy that is used by the tests below. The
+	// executable is referenced relative to the working directory.
+	toolsDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "a0f1fa7bb0d8e2ea0b3c5df9fbc8a1e4", 100)
+	expectInputRoot := func() {
+		directoryFetcher.EXPECT().GetDirectory(ctx, inputRootDigest).Return(&remoteexecution.Directory{
+			Directories: []*remoteexecution.DirectoryNode{{
+				Name: "tools",
+				Digest: &remoteexecution.Digest{
+					Hash:      "a0f1fa7bb0d8e2ea0b3c5df9fbc8a1e4",
+					SizeBytes: 100,
+				},
+			}},
+		}, nil)
+		directoryFetcher.EXPECT().GetDirectory(ctx, toolsDigest).Return(&remoteexecution.Directory{
+			Files: []*remoteexecution.FileNode{
+				{
+					Name: "compiler",
+					Digest: &remoteexecution.Digest{
+						Hash:      "0a6b3b3e2a8d8e4b3c7cc3b0c9df5a7a",
+						SizeBytes: 1200,
+					},
+					IsExecutable: true,
+				},
+				{
+					Name: "untrusted",
+					Digest: &remoteexecution.Digest{
+						Hash:      "e1c4b1a3d2f6e6b0b65c9e0b5ab4c2f7",
+						SizeBytes: 800,
+					},
+					IsExecutable: true,
+				},
+			},
+		}, nil)
+	}
+
+	t.Run("UnlistedDigest", func(t *testing.T) {
+		expectCommand("../tools/untrusted")
+		expectInputRoot()
+
+		testutil.RequireEqualProto(
+			t,
+			rejectedResponse(codes.PermissionDenied, "Command rejected by executable policy: Executable \"../tools/untrusted\" is not permitted on this worker"),
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("AllowedDigest", func(t *testing.T) {
+		expectCommand("../tools/compiler", "-c", "hello.c")
+		expectInputRoot()
+		response := &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		}
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(response)
+
+		testutil.RequireEqualProto(
+			t,
+			response,
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("OutsideInputRoot", func(t *testing.T) {
+		expectCommand("../../compiler")
+
+		testutil.RequireEqualProto(
+			t,
+			rejectedResponse(codes.InvalidArgument, "Command rejected by executable policy: Invalid executable \"../../compiler\": Failed to resolve executable: Path resolves to a location outside the input root"),
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+}
+
+func TestExecutableValidatingBuildExecutorInvalidPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	_, err := builder.NewExecutableValidatingBuildExecutor(
+		mock.NewMockBuildExecutor(ctrl),
+		mock.NewMockBlobAccess(ctrl),
+		mock.NewMockDirectoryFetcher(ctrl),
+		/* maximumMessageSizeBytes = */ 10000,
+		&builder.ExecutablePolicy{
+			AllowedPaths: []string{"../compiler"},
+		})
+	testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid allowed path \"../compiler\": Failed to resolve executable: Path resolves to a location outside the input root"), err)
+}
@@ -0,0 +1,259 @@
+package builder
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/workeradmin"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ExecutingActionRegistry keeps track of all actions that are
+// currently being executed by the worker, so that they can be listed
+// through the WorkerAdmin gRPC service. For every action it tracks the
+// stages of execution it went through and the amount of file pool
+// space it uses. While the command of the action is running, it also
+// permits obtaining the processes that are part of it.
+type ExecutingActionRegistry struct {
+	clock clock.Clock
+
+	lock         sync.Mutex
+	nextActionID uint64
+	actions      map[uint64]*executingAction
+}
+
+type executionStage struct {
+	name      string
+	startTime time.Time
+}
+
+type executingAction struct {
+	id             uint64
+	runnerIndex    int
+	digestFunction digest.Function
+	actionDigest   *remoteexecution.Digest
+	filePool       *statsCollectingFilePool
+
+	// Fields protected by the lock of the registry.
+	stages             []executionStage
+	runner             runner_pb.RunnerClient
+	inputRootDirectory string
+}
+
+// executingActionKey is the key under which the executingAction is
+// stored in the Context that is provided to the underlying
+// BuildExecutor. This permits the RunnerClient to associate calls to
+// Run() with the action.
+type executingActionKey struct{}
+
+// NewExecutingActionRegistry creates an ExecutingActionRegistry that
+// does not track any actions.
+func NewExecutingActionRegistry(clock clock.Clock) *ExecutingActionRegistry {
+	return &ExecutingActionRegistry{
+		clock:   clock,
+		actions: map[uint64]*executingAction{},
+	}
+}
+
+// NewBuildExecutor creates a decorator for BuildExecutor that
+// registers all actions that are executed through it.
+func (r *ExecutingActionRegistry) NewBuildExecutor(base BuildExecutor, runnerIndex int) BuildExecutor {
+	return &executingActionRegisteringBuildExecutor{
+		BuildExecutor: base,
+		registry:      r,
+		runnerIndex:   runnerIndex,
+	}
+}
+
+// NewRunnerClient creates a decorator for RunnerClient that keeps
+// track of commands of registered actions that are currently running.
+// It should be provided to the BuildExecutor that is wrapped by
+// NewBuildExecutor().
+func (r *ExecutingActionRegistry) NewRunnerClient(base runner_pb.RunnerClient) runner_pb.RunnerClient {
+	return &executingActionRegisteringRunnerClient{
+		RunnerClient: base,
+		registry:     r,
+	}
+}
+
+// getStageName returns the name of the stage of execution that is
+// entered upon receiving an execution state update.
+func getStageName(update *remoteworker.CurrentState_Executing) string {
+	switch update.ExecutionState.(type) {
+	case *remoteworker.CurrentState_Executing_Started:
+		return "started"
+	case *remoteworker.CurrentState_Executing_FetchingInputs:
+		return "fetching_inputs"
+	case *remoteworker.CurrentState_Executing_Running:
+		return "running"
+	case *remoteworker.CurrentState_Executing_UploadingOutputs:
+		return "uploading_outputs"
+	default:
+		return ""
+	}
+}
+
+// ListExecutingActions returns the state of all actions that are
+// currently being executed, sorted by action ID.
+func (r *ExecutingActionRegistry) ListExecutingActions() []*workeradmin.ExecutingAction {
+	now := r.clock.Now()
+	r.lock.Lock()
+	actions := make([]*workeradmin.ExecutingAction, 0, len(r.actions))
+	filePools := make([]*statsCollectingFilePool, 0, len(r.actions))
+	for _, action := range r.actions {
+		stages := make([]*workeradmin.ExecutionStage, 0, len(action.stages))
+		for i, stage := range action.stages {
+			endTime := now
+			if i+1 < len(action.stages) {
+				endTime = action.stages[i+1].startTime
+			}
+			stages = append(stages, &workeradmin.ExecutionStage{
+				Name:      stage.name,
+				StartTime: timestamppb.New(stage.startTime),
+				Duration:  durationpb.New(endTime.Sub(stage.startTime)),
+			})
+		}
+		actions = append(actions, &workeradmin.ExecutingAction{
+			ActionId:       action.id,
+			RunnerIndex:    uint32(action.runnerIndex),
+			InstanceName:   action.digestFunction.GetInstanceName().String(),
+			DigestFunction: action.digestFunction.GetEnumValue(),
+			ActionDigest:   action.actionDigest,
+			Stages:         stages,
+		})
+		filePools = append(filePools, action.filePool)
+	}
+	r.lock.Unlock()
+
+	// Obtain file pool usage after releasing the lock of the
+	// registry, so that both locks are never held at the same time.
+	for i, action := range actions {
+		fp := filePools[i]
+		fp.lock.Lock()
+		action.FilePoolFilesCount = fp.totalFiles
+		action.FilePoolSizeBytes = fp.totalSize
+		fp.lock.Unlock()
+	}
+
+	sort.Slice(actions, func(i, j int) bool {
+		return actions[i].ActionId < actions[j].ActionId
+	})
+	return actions
+}
+
+// GetProcessTree returns the processes that are part of the command of
+// an action that is currently being executed.
+func (r *ExecutingActionRegistry) GetProcessTree(ctx context.Context, actionID uint64) (*runner_pb.GetProcessTreeResponse, error) {
+	r.lock.Lock()
+	action, ok := r.actions[actionID]
+	if !ok {
+		r.lock.Unlock()
+		return nil, status.Errorf(codes.NotFound, "Action %d is not being executed", actionID)
+	}
+	runner, inputRootDirectory := action.runner, action.inputRootDirectory
+	r.lock.Unlock()
+
+	if runner == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "Action %d is not running a command", actionID)
+	}
+	return runner.GetProcessTree(ctx, &runner_pb.GetProcessTreeRequest{
+		InputRootDirectory: inputRootDirectory,
+	})
+}
+
+type executingActionRegisteringBuildExecutor struct {
+	BuildExecutor
+	registry    *ExecutingActionRegistry
+	runnerIndex int
+}
+
+func (be *executingActionRegisteringBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	r := be.registry
+	action := &executingAction{
+		runnerIndex:    be.runnerIndex,
+		digestFunction: digestFunction,
+		actionDigest:   request.ActionDigest,
+		filePool:       newStatsCollectingFilePool(filePool),
+		stages: []executionStage{{
+			name:      "started",
+			startTime: r.clock.Now(),
+		}},
+	}
+	r.lock.Lock()
+	r.nextActionID++
+	action.id = r.nextActionID
+	r.actions[action.id] = action
+	r.lock.Unlock()
+	defer func() {
+		r.lock.Lock()
+		delete(r.actions, action.id)
+		r.lock.Unlock()
+	}()
+
+	// Call into the underlying build executor, recording the
+	// stages of execution the action goes through.
+	baseUpdates := make(chan *remoteworker.CurrentState_Executing)
+	baseCompletion := make(chan *remoteexecution.ExecuteResponse)
+	go func() {
+		baseCompletion <- be.BuildExecutor.Execute(
+			context.WithValue(ctx, executingActionKey{}, action),
+			action.filePool,
+			monitor,
+			digestFunction,
+			request,
+			baseUpdates)
+	}()
+	for {
+		select {
+		case update := <-baseUpdates:
+			if name := getStageName(update); name != "" {
+				now := r.clock.Now()
+				r.lock.Lock()
+				action.stages = append(action.stages, executionStage{
+					name:      name,
+					startTime: now,
+				})
+				r.lock.Unlock()
+			}
+			executionStateUpdates <- update
+		case response := <-baseCompletion:
+			return response
+		}
+	}
+}
+
+type executingActionRegisteringRunnerClient struct {
+	runner_pb.RunnerClient
+	registry *ExecutingActionRegistry
+}
+
+func (rc *executingActionRegisteringRunnerClient) Run(ctx context.Context, request *runner_pb.RunRequest, opts ...grpc.CallOption) (*runner_pb.RunResponse, error) {
+	if action, ok := ctx.Value(executingActionKey{}).(*executingAction); ok {
+		r := rc.registry
+		r.lock.Lock()
+		action.runner = rc.RunnerClient
+		action.inputRootDirectory = request.InputRootDirectory
+		r.lock.Unlock()
+		defer func() {
+			r.lock.Lock()
+			action.runner = nil
+			action.inputRootDirectory = ""
+			r.lock.Unlock()
+		}()
+	}
+	return rc.RunnerClient.Run(ctx, request, opts...)
+}
@@ -0,0 +1,169 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/workeradmin"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestExecutingActionRegistry(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	clock := mock.NewMockClock(ctrl)
+	registry := builder.NewExecutingActionRegistry(clock)
+	baseRunnerClient := mock.NewMockRunnerClient(ctrl)
+	runnerClient := registry.NewRunnerClient(baseRunnerClient)
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	buildExecutor := registry.NewBuildExecutor(baseBuildExecutor, 3)
+
+	actionDigest := &remoteexecution.Digest{
+		Hash:      "d41d8cd98f00b204e9800998ecf8427e",
+		SizeBytes: 123,
+	}
+	request := &remoteworker.DesiredState_Executing{
+		ActionDigest: actionDigest,
+	}
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5)
+	runRequest := &runner_pb.RunRequest{
+		Arguments:          []string{"sleep", "1000"},
+		InputRootDirectory: "0/root",
+	}
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := registry.GetProcessTree(ctx, 42)
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Action 42 is not being executed"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Obtain the state of the action at various points
+		// during its execution.
+		var actionsWhileFetching, actionsWhileRunning []*workeradmin.ExecutingAction
+		var processTreeErrWhileFetching, processTreeErrWhileRunning error
+		var processTreeWhileRunning *runner_pb.GetProcessTreeResponse
+
+		// Updates are forwarded after being recorded. Wait for
+		// them to be forwarded, so that the listings are
+		// deterministic.
+		forwardedUpdates := make(chan *remoteworker.CurrentState_Executing, 10)
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		baseBuildExecutor.EXPECT().Execute(gomock.Any(), gomock.Any(), nil, digestFunction, request, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+				clock.EXPECT().Now().Return(time.Unix(1001, 0))
+				executionStateUpdates <- &remoteworker.CurrentState_Executing{
+					ActionDigest: actionDigest,
+					ExecutionState: &remoteworker.CurrentState_Executing_FetchingInputs{
+						FetchingInputs: &emptypb.Empty{},
+					},
+				}
+				<-forwardedUpdates
+
+				// Create a file in the file pool, which
+				// should be reflected in the listing.
+				f, err := filePool.NewFile()
+				require.NoError(t, err)
+				defer f.Close()
+				_, err = f.WriteAt([]byte("Hello"), 0)
+				require.NoError(t, err)
+
+				clock.EXPECT().Now().Return(time.Unix(1003, 0))
+				actionsWhileFetching = registry.ListExecutingActions()
+				_, processTreeErrWhileFetching = registry.GetProcessTree(ctx, 1)
+
+				clock.EXPECT().Now().Return(time.Unix(1004, 0))
+				executionStateUpdates <- &remoteworker.CurrentState_Executing{
+					ActionDigest: actionDigest,
+					ExecutionState: &remoteworker.CurrentState_Executing_Running{
+						Running: &emptypb.Empty{},
+					},
+				}
+				<-forwardedUpdates
+				baseRunnerClient.EXPECT().Run(ctx, runRequest).DoAndReturn(
+					func(ctx context.Context, runRequest *runner_pb.RunRequest, opts ...interface{}) (*runner_pb.RunResponse, error) {
+						clock.EXPECT().Now().Return(time.Unix(1010, 0))
+						actionsWhileRunning = registry.ListExecutingActions()
+						baseRunnerClient.EXPECT().GetProcessTree(ctx, &runner_pb.GetProcessTreeRequest{
+							InputRootDirectory: "0/root",
+						}).Return(&runner_pb.GetProcessTreeResponse{
+							Processes: []*runner_pb.Process{
+								{ProcessId: 123, ParentProcessId: 1, Arguments: []string{"sleep", "1000"}, State: "S"},
+							},
+						}, nil)
+						processTreeWhileRunning, processTreeErrWhileRunning = registry.GetProcessTree(ctx, 1)
+						return &runner_pb.RunResponse{}, nil
+					})
+				_, err = runnerClient.Run(ctx, runRequest)
+				require.NoError(t, err)
+
+				return &remoteexecution.ExecuteResponse{
+					Result: &remoteexecution.ActionResult{},
+				}
+			})
+
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{},
+		}, buildExecutor.Execute(ctx, re_filesystem.InMemoryFilePool, nil, digestFunction, request, forwardedUpdates))
+
+		testutil.RequireEqualProto(t, &workeradmin.ListExecutingActionsResponse{
+			Actions: []*workeradmin.ExecutingAction{{
+				ActionId:       1,
+				RunnerIndex:    3,
+				InstanceName:   "hello",
+				DigestFunction: remoteexecution.DigestFunction_MD5,
+				ActionDigest:   actionDigest,
+				Stages: []*workeradmin.ExecutionStage{
+					{Name: "started", StartTime: &timestamppb.Timestamp{Seconds: 1000}, Duration: &durationpb.Duration{Seconds: 1}},
+					{Name: "fetching_inputs", StartTime: &timestamppb.Timestamp{Seconds: 1001}, Duration: &durationpb.Duration{Seconds: 2}},
+				},
+				FilePoolFilesCount: 1,
+				FilePoolSizeBytes:  5,
+			}},
+		}, &workeradmin.ListExecutingActionsResponse{Actions: actionsWhileFetching})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Action 1 is not running a command"), processTreeErrWhileFetching)
+
+		testutil.RequireEqualProto(t, &workeradmin.ListExecutingActionsResponse{
+			Actions: []*workeradmin.ExecutingAction{{
+				ActionId:       1,
+				RunnerIndex:    3,
+				InstanceName:   "hello",
+				DigestFunction: remoteexecution.DigestFunction_MD5,
+				ActionDigest:   actionDigest,
+				Stages: []*workeradmin.ExecutionStage{
+					{Name: "started", StartTime: &timestamppb.Timestamp{Seconds: 1000}, Duration: &durationpb.Duration{Seconds: 1}},
+					{Name: "fetching_inputs", StartTime: &timestamppb.Timestamp{Seconds: 1001}, Duration: &durationpb.Duration{Seconds: 3}},
+					{Name: "running", StartTime: &timestamppb.Timestamp{Seconds: 1004}, Duration: &durationpb.Duration{Seconds: 6}},
+				},
+				FilePoolFilesCount: 1,
+				FilePoolSizeBytes:  5,
+			}},
+		}, &workeradmin.ListExecutingActionsResponse{Actions: actionsWhileRunning})
+		require.NoError(t, processTreeErrWhileRunning)
+		testutil.RequireEqualProto(t, &runner_pb.GetProcessTreeResponse{
+			Processes: []*runner_pb.Process{
+				{ProcessId: 123, ParentProcessId: 1, Arguments: []string{"sleep", "1000"}, State: "S"},
+			},
+		}, processTreeWhileRunning)
+
+		// Once completed, the action should no longer be listed.
+		clock.EXPECT().Now().Return(time.Unix(1020, 0))
+		require.Empty(t, registry.ListExecutingActions())
+	})
+}
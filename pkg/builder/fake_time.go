@@ -0,0 +1,95 @@
+package builder
+
+import (
+	"strconv"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FakeTimePlatformPropertyName is the name of the platform property
+// that may be used by actions to request that they are run with a
+// virtualized clock. Its value determines the time at which the clock
+// starts:
+//
+//   - "source-date-epoch": The value of the SOURCE_DATE_EPOCH
+//     environment variable of the action.
+//   - "salt": The salt of the action, interpreted as a decimal number
+//     of seconds since the Unix epoch.
+//   - A decimal number of seconds since the Unix epoch.
+const FakeTimePlatformPropertyName = "fake-time"
+
+// sourceDateEpochEnvironmentVariable is the name of the environment
+// variable that is used by many tools to obtain the timestamp they
+// embed into their outputs.
+// https://reproducible-builds.org/specs/source-date-epoch/
+const sourceDateEpochEnvironmentVariable = "SOURCE_DATE_EPOCH"
+
+// FakeTime contains the options that need to be provided to
+// LocalBuildExecutor to let actions run with a virtualized clock.
+//
+// The clock is virtualized by preloading libfaketime into the
+// processes of the action. Linux time namespaces are not used, as they
+// only permit adjusting the monotonic and boot time clocks, while
+// tools that embed timestamps into their outputs use the real time
+// clock.
+type FakeTime struct {
+	// Path of the libfaketime shared library, as seen by the
+	// action (e.g., "/usr/lib/x86_64-linux-gnu/faketime/libfaketime.so.1").
+	PreloadLibraryPath string
+}
+
+// getFakeTimeEpoch returns the time at which the virtualized clock of
+// an action should start, in seconds since the Unix epoch. False is
+// returned if the action did not request a virtualized clock.
+func getFakeTimeEpoch(action *remoteexecution.Action, environmentVariables map[string]string) (int64, bool, error) {
+	for _, property := range action.GetPlatform().GetProperties() {
+		if property.Name == FakeTimePlatformPropertyName {
+			var value string
+			switch property.Value {
+			case "source-date-epoch":
+				v, ok := environmentVariables[sourceDateEpochEnvironmentVariable]
+				if !ok {
+					return 0, false, status.Errorf(codes.InvalidArgument, "Platform property %#v requests the use of environment variable %#v, which is not set", FakeTimePlatformPropertyName, sourceDateEpochEnvironmentVariable)
+				}
+				value = v
+			case "salt":
+				value = string(action.Salt)
+			default:
+				value = property.Value
+			}
+			epoch, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return 0, false, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid value for platform property %#v", FakeTimePlatformPropertyName)
+			}
+			if epoch < 0 {
+				return 0, false, status.Errorf(codes.InvalidArgument, "Platform property %#v must not refer to a time before the Unix epoch", FakeTimePlatformPropertyName)
+			}
+			return epoch, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// setEnvironmentVariables adds the environment variables that are
+// needed to let libfaketime start the clock of an action at a given
+// time. The clock continues to advance from there. The monotonic
+// clock is left intact, so that timeouts and sleeps performed by the
+// action behave as usual.
+func (ft *FakeTime) setEnvironmentVariables(environmentVariables map[string]string, epoch int64) {
+	if preload := environmentVariables["LD_PRELOAD"]; preload != "" {
+		environmentVariables["LD_PRELOAD"] = ft.PreloadLibraryPath + ":" + preload
+	} else {
+		environmentVariables["LD_PRELOAD"] = ft.PreloadLibraryPath
+	}
+	formattedEpoch := strconv.FormatInt(epoch, 10)
+	environmentVariables["FAKETIME"] = "@" + formattedEpoch
+	environmentVariables["FAKETIME_FMT"] = "%s"
+	environmentVariables["DONT_FAKE_MONOTONIC"] = "1"
+	if _, ok := environmentVariables[sourceDateEpochEnvironmentVariable]; !ok {
+		environmentVariables[sourceDateEpochEnvironmentVariable] = formattedEpoch
+	}
+}
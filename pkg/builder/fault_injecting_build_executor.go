@@ -0,0 +1,158 @@
+package builder
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/random"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FaultInjectionProbabilities contains the probabilities at which
+// FaultInjectingBuildExecutor injects faults into executions of
+// actions. All probabilities are in range [0.0, 1.0].
+type FaultInjectionProbabilities struct {
+	// Probability at which execution of an action is delayed by a
+	// random amount of time, up to MaximumDelay.
+	Delay        float64
+	MaximumDelay time.Duration
+
+	// Probability at which an action is not executed, and a
+	// transient infrastructure error is returned instead.
+	TransientError float64
+
+	// Probability at which an action is killed after running for a
+	// random amount of time, up to MaximumKillDelay.
+	Kill             float64
+	MaximumKillDelay time.Duration
+
+	// Probability at which the digest of one of the output files of
+	// an action is corrupted, causing the ActionResult to refer to
+	// an object that is not present in the Content Addressable
+	// Storage.
+	OutputCorruption float64
+}
+
+type faultInjectingBuildExecutor struct {
+	BuildExecutor
+	clock         clock.Clock
+	probabilities FaultInjectionProbabilities
+
+	lock      sync.Mutex
+	generator random.SingleThreadedGenerator
+}
+
+// NewFaultInjectingBuildExecutor creates a decorator for BuildExecutor
+// that randomly injects faults into executions of actions. This can be
+// used in staging environments to verify that clients and the
+// scheduler are capable of recovering from infrastructure failures.
+//
+// By providing a random number generator with a fixed seed, the
+// sequence of faults that is injected is deterministic, assuming that
+// actions are executed sequentially.
+//
+// Corruption of outputs causes invalid results to be written into the
+// Action Cache, and should therefore only be enabled in test
+// environments.
+func NewFaultInjectingBuildExecutor(base BuildExecutor, clock clock.Clock, generator random.SingleThreadedGenerator, probabilities FaultInjectionProbabilities) BuildExecutor {
+	return &faultInjectingBuildExecutor{
+		BuildExecutor: base,
+		clock:         clock,
+		probabilities: probabilities,
+		generator:     generator,
+	}
+}
+
+func (be *faultInjectingBuildExecutor) randomDuration(probability float64, maximum time.Duration) time.Duration {
+	if be.generator.Float64() >= probability {
+		return -1
+	}
+	if maximum <= 0 {
+		return 0
+	}
+	return random.Duration(be.generator, maximum)
+}
+
+func (be *faultInjectingBuildExecutor) Execute(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	// Decide which faults to inject up front, so that the sequence
+	// of random numbers consumed does not depend on the outcome of
+	// the execution.
+	be.lock.Lock()
+	delay := be.randomDuration(be.probabilities.Delay, be.probabilities.MaximumDelay)
+	injectTransientError := be.generator.Float64() < be.probabilities.TransientError
+	killDelay := be.randomDuration(be.probabilities.Kill, be.probabilities.MaximumKillDelay)
+	corruptOutput := be.generator.Float64() < be.probabilities.OutputCorruption
+	be.lock.Unlock()
+
+	if delay >= 0 {
+		timer, timerChannel := be.clock.NewTimer(delay)
+		select {
+		case <-timerChannel:
+		case <-ctx.Done():
+			timer.Stop()
+			return &remoteexecution.ExecuteResponse{
+				Status: status.Convert(util.StatusFromContext(ctx)).Proto(),
+			}
+		}
+	}
+
+	if injectTransientError {
+		return &remoteexecution.ExecuteResponse{
+			Status: status.New(codes.Unavailable, "Injected fault: transient infrastructure error").Proto(),
+		}
+	}
+
+	var response *remoteexecution.ExecuteResponse
+	if killDelay >= 0 {
+		// Cancel the context of the action after the delay
+		// has passed, causing the action to be killed.
+		ctxWithCancel, cancel := context.WithCancel(ctx)
+		timer, timerChannel := be.clock.NewTimer(killDelay)
+		var killed atomic.Bool
+		done := make(chan struct{})
+		go func() {
+			select {
+			case <-timerChannel:
+				killed.Store(true)
+				cancel()
+			case <-done:
+				timer.Stop()
+			}
+		}()
+		response = be.BuildExecutor.Execute(ctxWithCancel, filePool, monitor, digestFunction, request, executionStateUpdates)
+		close(done)
+		cancel()
+		if killed.Load() {
+			response.Status = status.New(codes.Unavailable, "Injected fault: action was killed").Proto()
+		}
+	} else {
+		response = be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+	}
+
+	if corruptOutput && response.Result != nil && len(response.Result.OutputFiles) > 0 {
+		be.lock.Lock()
+		outputFile := response.Result.OutputFiles[be.generator.Intn(len(response.Result.OutputFiles))]
+		if outputDigest := outputFile.Digest; outputDigest != nil {
+			hash := make([]byte, len(outputDigest.Hash)/2)
+			be.generator.Read(hash)
+			outputFile.Digest = &remoteexecution.Digest{
+				Hash:      hex.EncodeToString(hash),
+				SizeBytes: outputDigest.SizeBytes,
+			}
+		}
+		be.lock.Unlock()
+	}
+	return response
+}
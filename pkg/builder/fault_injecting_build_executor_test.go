@@ -0,0 +1,164 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFaultInjectingBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	generator := mock.NewMockSingleThreadedGenerator(ctrl)
+
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5)
+	request := &remoteworker.DesiredState_Executing{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "d41d8cd98f00b204e9800998ecf8427e",
+			SizeBytes: 123,
+		},
+		Action: &remoteexecution.Action{},
+	}
+	metadata := make(chan *remoteworker.CurrentState_Executing, 10)
+
+	expectRandomNumbers := func(values ...float64) {
+		for _, value := range values {
+			generator.EXPECT().Float64().Return(value)
+		}
+	}
+
+	t.Run("NoFaults", func(t *testing.T) {
+		buildExecutor := builder.NewFaultInjectingBuildExecutor(baseBuildExecutor, clock, generator, builder.FaultInjectionProbabilities{
+			Delay:            0.1,
+			MaximumDelay:     10 * time.Second,
+			TransientError:   0.1,
+			Kill:             0.1,
+			MaximumKillDelay: 10 * time.Second,
+			OutputCorruption: 0.1,
+		})
+		expectRandomNumbers(0.5, 0.5, 0.5, 0.5)
+		response := &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{ExitCode: 0},
+		}
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, gomock.Any()).Return(response)
+
+		testutil.RequireEqualProto(t, response, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata))
+	})
+
+	t.Run("Delay", func(t *testing.T) {
+		buildExecutor := builder.NewFaultInjectingBuildExecutor(baseBuildExecutor, clock, generator, builder.FaultInjectionProbabilities{
+			Delay:        0.1,
+			MaximumDelay: 10 * time.Second,
+		})
+		expectRandomNumbers(0.05)
+		generator.EXPECT().Int63n(int64(10 * time.Second)).Return(int64(3 * time.Second))
+		expectRandomNumbers(0.5, 0.5, 0.5)
+		timerChannel := make(chan time.Time, 1)
+		timerChannel <- time.Unix(1003, 0)
+		clock.EXPECT().NewTimer(3*time.Second).Return(mock.NewMockTimer(ctrl), timerChannel)
+		response := &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{ExitCode: 0},
+		}
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, gomock.Any()).Return(response)
+
+		testutil.RequireEqualProto(t, response, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata))
+	})
+
+	t.Run("TransientError", func(t *testing.T) {
+		// The action should not be executed at all.
+		buildExecutor := builder.NewFaultInjectingBuildExecutor(baseBuildExecutor, clock, generator, builder.FaultInjectionProbabilities{
+			TransientError: 0.1,
+		})
+		expectRandomNumbers(0.5, 0.05, 0.5, 0.5)
+
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Status: status.New(codes.Unavailable, "Injected fault: transient infrastructure error").Proto(),
+		}, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata))
+	})
+
+	t.Run("Kill", func(t *testing.T) {
+		// The context of the action should be canceled after
+		// the kill delay has passed.
+		buildExecutor := builder.NewFaultInjectingBuildExecutor(baseBuildExecutor, clock, generator, builder.FaultInjectionProbabilities{
+			Kill:             0.1,
+			MaximumKillDelay: 10 * time.Second,
+		})
+		expectRandomNumbers(0.5, 0.5, 0.05)
+		generator.EXPECT().Int63n(int64(10 * time.Second)).Return(int64(2 * time.Second))
+		expectRandomNumbers(0.5)
+		timerChannel := make(chan time.Time, 1)
+		clock.EXPECT().NewTimer(2*time.Second).Return(mock.NewMockTimer(ctrl), timerChannel)
+		baseBuildExecutor.EXPECT().Execute(gomock.Any(), filePool, monitor, digestFunction, request, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+				timerChannel <- time.Unix(1002, 0)
+				<-ctx.Done()
+				return &remoteexecution.ExecuteResponse{
+					Result: &remoteexecution.ActionResult{},
+					Status: status.New(codes.Canceled, "Failed to run command: context canceled").Proto(),
+				}
+			})
+
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{},
+			Status: status.New(codes.Unavailable, "Injected fault: action was killed").Proto(),
+		}, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata))
+	})
+
+	t.Run("OutputCorruption", func(t *testing.T) {
+		// The digest of one of the output files should be
+		// replaced by a random one of the same length.
+		buildExecutor := builder.NewFaultInjectingBuildExecutor(baseBuildExecutor, clock, generator, builder.FaultInjectionProbabilities{
+			OutputCorruption: 0.1,
+		})
+		expectRandomNumbers(0.5, 0.5, 0.5, 0.05)
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, gomock.Any()).Return(&remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				OutputFiles: []*remoteexecution.OutputFile{
+					{
+						Path: "a.o",
+						Digest: &remoteexecution.Digest{
+							Hash:      "0cc175b9c0f1b6a831c399e269772661",
+							SizeBytes: 1,
+						},
+					},
+					{
+						Path: "b.o",
+						Digest: &remoteexecution.Digest{
+							Hash:      "92eb5ffee6ae2fec3ad71c777531578f",
+							SizeBytes: 1,
+						},
+					},
+				},
+			},
+		})
+		generator.EXPECT().Intn(2).Return(1)
+		generator.EXPECT().Read(gomock.Len(16)).DoAndReturn(func(p []byte) (int, error) {
+			for i := range p {
+				p[i] = 0xff
+			}
+			return len(p), nil
+		})
+
+		response := buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata)
+		require.Equal(t, "0cc175b9c0f1b6a831c399e269772661", response.Result.OutputFiles[0].Digest.Hash)
+		require.Equal(t, "ffffffffffffffffffffffffffffffff", response.Result.OutputFiles[1].Digest.Hash)
+	})
+}
@@ -0,0 +1,79 @@
+package builder
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+)
+
+type fileFetchingInputRootPrefetcher struct {
+	directoryFetcher cas.DirectoryFetcher
+	fileFetcher      cas.FileFetcher
+	scratchDirectory filesystem.Directory
+	nextFileIndex    atomic.Uint64
+}
+
+// NewFileFetchingInputRootPrefetcher creates an InputRootPrefetcher
+// that fetches all files contained in an input root through a
+// FileFetcher, storing them in a scratch directory and removing them
+// immediately afterwards. This is only useful if the FileFetcher is
+// backed by a cache, such as the one created by
+// cas.NewHardlinkingFileFetcher(). In that case the scratch directory
+// needs to be placed on the same file system as the cache directory.
+func NewFileFetchingInputRootPrefetcher(directoryFetcher cas.DirectoryFetcher, fileFetcher cas.FileFetcher, scratchDirectory filesystem.Directory) InputRootPrefetcher {
+	return &fileFetchingInputRootPrefetcher{
+		directoryFetcher: directoryFetcher,
+		fileFetcher:      fileFetcher,
+		scratchDirectory: scratchDirectory,
+	}
+}
+
+func (p *fileFetchingInputRootPrefetcher) PrefetchInputRoot(ctx context.Context, inputRootDigest digest.Digest) error {
+	return p.prefetchDirectory(ctx, inputRootDigest, map[digest.Digest]struct{}{})
+}
+
+func (p *fileFetchingInputRootPrefetcher) prefetchDirectory(ctx context.Context, directoryDigest digest.Digest, directoriesSeen map[digest.Digest]struct{}) error {
+	// Input roots may contain the same directory many times. Only
+	// traverse each of them once.
+	if _, ok := directoriesSeen[directoryDigest]; ok {
+		return nil
+	}
+	directoriesSeen[directoryDigest] = struct{}{}
+
+	directory, err := p.directoryFetcher.GetDirectory(ctx, directoryDigest)
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to fetch directory %#v", directoryDigest.String())
+	}
+	digestFunction := directoryDigest.GetDigestFunction()
+	for _, file := range directory.Files {
+		fileDigest, err := digestFunction.NewDigestFromProto(file.Digest)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to extract digest for file %#v", file.Name)
+		}
+		name := path.MustNewComponent(strconv.FormatUint(p.nextFileIndex.Add(1), 10))
+		if err := p.fileFetcher.GetFile(ctx, fileDigest, p.scratchDirectory, name, file.IsExecutable); err != nil {
+			return util.StatusWrapf(err, "Failed to fetch file %#v", file.Name)
+		}
+		if err := p.scratchDirectory.Remove(name); err != nil {
+			return util.StatusWrapfWithCode(err, codes.Internal, "Failed to remove scratch file %#v", name.String())
+		}
+	}
+	for _, child := range directory.Directories {
+		childDigest, err := digestFunction.NewDigestFromProto(child.Digest)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to extract digest for directory %#v", child.Name)
+		}
+		if err := p.prefetchDirectory(ctx, childDigest, directoriesSeen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package builder_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFileFetchingInputRootPrefetcher(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	fileFetcher := mock.NewMockFileFetcher(ctrl)
+	scratchDirectory := mock.NewMockDirectory(ctrl)
+	inputRootPrefetcher := builder.NewFileFetchingInputRootPrefetcher(directoryFetcher, fileFetcher, scratchDirectory)
+
+	rootDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "4df5f448a5e6b3c41e6aae7a8a9832aa", 456)
+
+	t.Run("DirectoryFetchFailure", func(t *testing.T) {
+		directoryFetcher.EXPECT().GetDirectory(ctx, rootDigest).
+			Return(nil, status.Error(codes.Internal, "Server on fire"))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Internal, "Failed to fetch directory \"3-4df5f448a5e6b3c41e6aae7a8a9832aa-456-hello\": Server on fire"),
+			inputRootPrefetcher.PrefetchInputRoot(ctx, rootDigest))
+	})
+
+	t.Run("FileFetchFailure", func(t *testing.T) {
+		directoryFetcher.EXPECT().GetDirectory(ctx, rootDigest).Return(&remoteexecution.Directory{
+			Files: []*remoteexecution.FileNode{
+				{
+					Name: "hello.txt",
+					Digest: &remoteexecution.Digest{
+						Hash:      "8b1a9953c4611296a827abf8c47804d7",
+						SizeBytes: 5,
+					},
+				},
+			},
+		}, nil)
+		fileFetcher.EXPECT().GetFile(ctx, digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5), scratchDirectory, gomock.Any(), false).
+			Return(syscall.EIO)
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Unknown, "Failed to fetch file \"hello.txt\": input/output error"),
+			inputRootPrefetcher.PrefetchInputRoot(ctx, rootDigest))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// All files should be fetched into the scratch
+		// directory and removed afterwards. Directories that
+		// occur multiple times should only be traversed once.
+		childDigest := &remoteexecution.Digest{
+			Hash:      "6fc422233a40a75a1f028e11c3cd1140",
+			SizeBytes: 100,
+		}
+		directoryFetcher.EXPECT().GetDirectory(ctx, rootDigest).Return(&remoteexecution.Directory{
+			Directories: []*remoteexecution.DirectoryNode{
+				{Name: "a", Digest: childDigest},
+				{Name: "b", Digest: childDigest},
+			},
+		}, nil)
+		directoryFetcher.EXPECT().GetDirectory(ctx, digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "6fc422233a40a75a1f028e11c3cd1140", 100)).Return(&remoteexecution.Directory{
+			Files: []*remoteexecution.FileNode{
+				{
+					Name: "tool",
+					Digest: &remoteexecution.Digest{
+						Hash:      "8b1a9953c4611296a827abf8c47804d7",
+						SizeBytes: 5,
+					},
+					IsExecutable: true,
+				},
+			},
+		}, nil)
+		var scratchName path.Component
+		fileFetcher.EXPECT().GetFile(ctx, digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5), scratchDirectory, gomock.Any(), true).
+			DoAndReturn(func(ctx context.Context, blobDigest digest.Digest, directory filesystem.Directory, name path.Component, isExecutable bool) error {
+				scratchName = name
+				return nil
+			})
+		scratchDirectory.EXPECT().Remove(gomock.Any()).DoAndReturn(func(name path.Component) error {
+			require.Equal(t, scratchName, name)
+			return nil
+		})
+
+		require.NoError(t, inputRootPrefetcher.PrefetchInputRoot(ctx, rootDigest))
+	})
+}
@@ -0,0 +1,51 @@
+package builder
+
+import (
+	"context"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type filePoolBudgetBuildExecutor struct {
+	BuildExecutor
+	budget   *re_filesystem.FilePoolBudget
+	priority int
+}
+
+// NewFilePoolBudgetBuildExecutor creates a decorator for BuildExecutor
+// that lets build actions allocate temporary files from a
+// FilePoolBudget that is shared by all worker threads.
+//
+// If the budget is exhausted while an action with a higher priority
+// needs space, the action executed through this decorator is
+// terminated, so that its space may be reclaimed.
+func NewFilePoolBudgetBuildExecutor(buildExecutor BuildExecutor, budget *re_filesystem.FilePoolBudget, priority int) BuildExecutor {
+	return &filePoolBudgetBuildExecutor{
+		BuildExecutor: buildExecutor,
+		budget:        budget,
+		priority:      priority,
+	}
+}
+
+func (be *filePoolBudgetBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	reclaimedErr := status.Error(codes.ResourceExhausted, "File pool space was reclaimed by a higher priority action")
+	ctxWithCancel, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	budgetedFilePool := be.budget.NewFilePool(ctxWithCancel, filePool, be.priority, func() { cancel(reclaimedErr) })
+	defer budgetedFilePool.Close()
+
+	response := be.BuildExecutor.Execute(ctxWithCancel, budgetedFilePool, monitor, digestFunction, request, executionStateUpdates)
+	if context.Cause(ctxWithCancel) == reclaimedErr && ctx.Err() == nil {
+		// Make sure the action is reported as failed, even if the
+		// cancellation did not cause the action to fail.
+		attachErrorToExecuteResponse(response, reclaimedErr)
+	}
+	return response
+}
@@ -0,0 +1,56 @@
+package builder
+
+import (
+	"context"
+	"log"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+)
+
+type filePoolEncryptingBuildExecutor struct {
+	BuildExecutor
+	chunkSizeBytes int
+	keyProvider    re_filesystem.EncryptionKeyProvider
+}
+
+// NewFilePoolEncryptingBuildExecutor creates a decorator for
+// BuildExecutor that causes all temporary files created by the build
+// action to be encrypted before they are written to the FilePool. A
+// new encryption key is obtained for every action. The identifier of
+// this key is logged, so that it is possible to determine afterwards
+// which key was used to encrypt the files of an action.
+func NewFilePoolEncryptingBuildExecutor(buildExecutor BuildExecutor, chunkSizeBytes int, keyProvider re_filesystem.EncryptionKeyProvider) BuildExecutor {
+	return &filePoolEncryptingBuildExecutor{
+		BuildExecutor:  buildExecutor,
+		chunkSizeBytes: chunkSizeBytes,
+		keyProvider:    keyProvider,
+	}
+}
+
+func (be *filePoolEncryptingBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	actionDigest, err := digestFunction.NewDigestFromProto(request.ActionDigest)
+	if err != nil {
+		response := NewDefaultExecuteResponse(request)
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to extract digest for action"))
+		return response
+	}
+	key, err := be.keyProvider.NewEncryptionKey(actionDigest.String())
+	if err != nil {
+		response := NewDefaultExecuteResponse(request)
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to obtain encryption key"))
+		return response
+	}
+	encryptingFilePool, err := re_filesystem.NewEncryptingFilePool(filePool, be.chunkSizeBytes, &key)
+	if err != nil {
+		response := NewDefaultExecuteResponse(request)
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to create encrypting file pool"))
+		return response
+	}
+	log.Printf("Action %s: Encrypting temporary files using key %#v", actionDigest, key.ID)
+	return be.BuildExecutor.Execute(ctx, encryptingFilePool, monitor, digestFunction, request, executionStateUpdates)
+}
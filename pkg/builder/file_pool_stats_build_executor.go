@@ -31,14 +31,22 @@ func NewFilePoolStatsBuildExecutor(buildExecutor BuildExecutor) BuildExecutor {
 }
 
 func (be *filePoolStatsBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
-	fp := statsCollectingFilePool{base: filePool}
-	response := be.BuildExecutor.Execute(ctx, &fp, monitor, digestFunction, request, executionStateUpdates)
+	fp := newStatsCollectingFilePool(filePool)
+	response := be.BuildExecutor.Execute(ctx, fp, monitor, digestFunction, request, executionStateUpdates)
 
 	fp.lock.Lock()
-	stats := fp.stats
+	// The amount of storage consumed by files is only sampled when
+	// they are closed. Also sample files that are still opened, so
+	// that they are accounted for in the peak.
+	for f := range fp.openFiles {
+		if allocatedSize, hasAllocatedSize := f.getAllocatedSize(); hasAllocatedSize {
+			f.updateAllocatedSizeLocked(allocatedSize)
+		}
+	}
+	resourceUsage, err := anypb.New(&fp.stats)
 	fp.lock.Unlock()
 
-	if resourceUsage, err := anypb.New(&stats); err == nil {
+	if err == nil {
 		response.Result.ExecutionMetadata.AuxiliaryMetadata = append(response.Result.ExecutionMetadata.AuxiliaryMetadata, resourceUsage)
 	} else {
 		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to marshal file pool resource usage"))
@@ -51,10 +59,19 @@ func (be *filePoolStatsBuildExecutor) Execute(ctx context.Context, filePool re_f
 type statsCollectingFilePool struct {
 	base re_filesystem.FilePool
 
-	lock       sync.Mutex
-	stats      resourceusage.FilePoolResourceUsage
-	totalSize  uint64
-	totalFiles uint64
+	lock               sync.Mutex
+	stats              resourceusage.FilePoolResourceUsage
+	openFiles          map[*statsCollectingFileReadWriter]struct{}
+	totalSize          uint64
+	totalAllocatedSize uint64
+	totalFiles         uint64
+}
+
+func newStatsCollectingFilePool(base re_filesystem.FilePool) *statsCollectingFilePool {
+	return &statsCollectingFilePool{
+		base:      base,
+		openFiles: map[*statsCollectingFileReadWriter]struct{}{},
+	}
 }
 
 func (fp *statsCollectingFilePool) NewFile() (filesystem.FileReadWriter, error) {
@@ -63,18 +80,21 @@ func (fp *statsCollectingFilePool) NewFile() (filesystem.FileReadWriter, error)
 		return nil, err
 	}
 
+	fr := &statsCollectingFileReadWriter{
+		FileReadWriter: f,
+		pool:           fp,
+	}
+
 	fp.lock.Lock()
 	fp.stats.FilesCreated++
 	fp.totalFiles++
 	if fp.stats.FilesCountPeak < fp.totalFiles {
 		fp.stats.FilesCountPeak = fp.totalFiles
 	}
+	fp.openFiles[fr] = struct{}{}
 	fp.lock.Unlock()
 
-	return &statsCollectingFileReadWriter{
-		FileReadWriter: f,
-		pool:           fp,
-	}, nil
+	return fr, nil
 }
 
 // statsCollectingFileReadWriter is a decorator for
@@ -84,7 +104,8 @@ type statsCollectingFileReadWriter struct {
 	filesystem.FileReadWriter
 	pool *statsCollectingFilePool
 
-	size uint64
+	size          uint64
+	allocatedSize uint64
 }
 
 func (f *statsCollectingFileReadWriter) updateSizeLocked(newSize uint64) {
@@ -97,6 +118,28 @@ func (f *statsCollectingFileReadWriter) updateSizeLocked(newSize uint64) {
 	}
 }
 
+// getAllocatedSize obtains the amount of storage that is consumed by
+// the file. As this may perform I/O, it is not called as part of every
+// write. It is only called when the file is closed, and when statistics
+// are gathered for files that are still opened.
+func (f *statsCollectingFileReadWriter) getAllocatedSize() (uint64, bool) {
+	allocatedSize, err := re_filesystem.GetFileAllocatedSizeBytes(f.FileReadWriter)
+	if err != nil {
+		return 0, false
+	}
+	return uint64(allocatedSize), true
+}
+
+func (f *statsCollectingFileReadWriter) updateAllocatedSizeLocked(newAllocatedSize uint64) {
+	fp := f.pool
+	fp.totalAllocatedSize -= f.allocatedSize
+	f.allocatedSize = newAllocatedSize
+	fp.totalAllocatedSize += f.allocatedSize
+	if fp.stats.FilesAllocatedSizeBytesPeak < fp.totalAllocatedSize {
+		fp.stats.FilesAllocatedSizeBytesPeak = fp.totalAllocatedSize
+	}
+}
+
 func (f *statsCollectingFileReadWriter) ReadAt(p []byte, off int64) (int, error) {
 	n, err := f.FileReadWriter.ReadAt(p, off)
 
@@ -140,16 +183,40 @@ func (f *statsCollectingFileReadWriter) Truncate(length int64) error {
 	return err
 }
 
+func (f *statsCollectingFileReadWriter) Deallocate(off, size int64) error {
+	err := re_filesystem.DeallocateFile(f.FileReadWriter, off, size)
+
+	fp := f.pool
+	fp.lock.Lock()
+	fp.stats.DeallocatesCount++
+	if err == nil {
+		fp.stats.DeallocatesSizeBytes += uint64(size)
+	}
+	fp.lock.Unlock()
+
+	return err
+}
+
 func (f *statsCollectingFileReadWriter) Close() error {
-	err := f.FileReadWriter.Close()
-	f.FileReadWriter = nil
+	// Sample the amount of storage consumed by the file one last
+	// time, so that it is accounted for in the peak. The file is
+	// removed from the set of opened files before it is closed, so
+	// that Execute() does not attempt to sample it afterwards.
+	allocatedSize, hasAllocatedSize := f.getAllocatedSize()
 
 	fp := f.pool
 	fp.lock.Lock()
+	delete(fp.openFiles, f)
+	if hasAllocatedSize {
+		f.updateAllocatedSizeLocked(allocatedSize)
+	}
 	fp.totalFiles--
 	fp.totalSize -= f.size
+	fp.totalAllocatedSize -= f.allocatedSize
 	fp.lock.Unlock()
 	f.pool = nil
 
+	err := f.FileReadWriter.Close()
+	f.FileReadWriter = nil
 	return err
 }
@@ -58,6 +58,7 @@ func TestFilePoolStatsBuildExecutorExample(t *testing.T) {
 		require.Equal(t, 7, n)
 		require.Equal(t, io.EOF, err)
 		require.Equal(t, []byte("\x00\x00Hello\x00\x00\x00"), p[:])
+		require.NoError(t, filesystem.DeallocateFile(f, 102, 3))
 		require.NoError(t, f.Truncate(42))
 		require.NoError(t, f.Close())
 
@@ -83,14 +84,17 @@ func TestFilePoolStatsBuildExecutorExample(t *testing.T) {
 	// Validate the execute response, which should now contain the
 	// file pool resource usage statistics.
 	resourceUsage, err := anypb.New(&resourceusage.FilePoolResourceUsage{
-		FilesCreated:       2,
-		FilesCountPeak:     1,
-		FilesSizeBytesPeak: 105,
-		ReadsCount:         1,
-		ReadsSizeBytes:     7,
-		WritesCount:        1,
-		WritesSizeBytes:    5,
-		TruncatesCount:     2,
+		FilesCreated:                2,
+		FilesCountPeak:              1,
+		FilesSizeBytesPeak:          105,
+		ReadsCount:                  1,
+		ReadsSizeBytes:              7,
+		WritesCount:                 1,
+		WritesSizeBytes:             5,
+		TruncatesCount:              2,
+		DeallocatesCount:            1,
+		DeallocatesSizeBytes:        3,
+		FilesAllocatedSizeBytesPeak: 42,
 	})
 	require.NoError(t, err)
 	testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
@@ -102,3 +106,75 @@ func TestFilePoolStatsBuildExecutorExample(t *testing.T) {
 		},
 	}, executeResponse)
 }
+
+func TestFilePoolStatsBuildExecutorAllocatedSizeSampling(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	request := &remoteworker.DesiredState_Executing{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "d41d8cd98f00b204e9800998ecf8427e",
+			SizeBytes: 123,
+		},
+	}
+
+	// The amount of storage consumed by files should only be
+	// sampled when files are closed, and when the action completes
+	// for files that are still opened.
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	baseBuildExecutor.EXPECT().Execute(
+		ctx,
+		gomock.Any(),
+		monitor,
+		digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5),
+		request,
+		gomock.Any()).DoAndReturn(func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+		f1, err := filePool.NewFile()
+		require.NoError(t, err)
+		n, err := f1.WriteAt(make([]byte, 100), 0)
+		require.Equal(t, 100, n)
+		require.NoError(t, err)
+
+		f2, err := filePool.NewFile()
+		require.NoError(t, err)
+		n, err = f2.WriteAt(make([]byte, 200), 0)
+		require.Equal(t, 200, n)
+		require.NoError(t, err)
+
+		require.NoError(t, f1.Close())
+
+		// Leave the second file opened.
+		return &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		}
+	})
+
+	executionStateUpdates := make(chan *remoteworker.CurrentState_Executing, 3)
+	buildExecutor := builder.NewFilePoolStatsBuildExecutor(baseBuildExecutor)
+	executeResponse := buildExecutor.Execute(
+		ctx,
+		filesystem.InMemoryFilePool,
+		monitor,
+		digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5),
+		request,
+		executionStateUpdates)
+
+	resourceUsage, err := anypb.New(&resourceusage.FilePoolResourceUsage{
+		FilesCreated:                2,
+		FilesCountPeak:              2,
+		FilesSizeBytesPeak:          300,
+		WritesCount:                 2,
+		WritesSizeBytes:             300,
+		FilesAllocatedSizeBytesPeak: 200,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+				AuxiliaryMetadata: []*anypb.Any{resourceUsage},
+			},
+		},
+	}, executeResponse)
+}
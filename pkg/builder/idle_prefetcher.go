@@ -0,0 +1,16 @@
+package builder
+
+import (
+	"context"
+)
+
+// IdlePrefetcher is used by BuildClient to warm up local caches while
+// the worker is idle. This reduces the latency of actions that are
+// executed after the worker has started, or after files have been
+// evicted from the cache.
+//
+// Implementations should return quickly if prefetching is already
+// being performed by another worker thread.
+type IdlePrefetcher interface {
+	PrefetchWhileIdle(ctx context.Context) error
+}
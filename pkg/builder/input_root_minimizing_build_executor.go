@@ -0,0 +1,392 @@
+package builder
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// InputRootMinimizationPlatformPropertyName is the name of the
+// platform property that may be used by actions to request that their
+// input root is minimized.
+const InputRootMinimizationPlatformPropertyName = "minimize-input-root"
+
+type inputRootMinimizingBuildExecutor struct {
+	BuildExecutor
+	contentAddressableStorage blobstore.BlobAccess
+	directoryFetcher          cas.DirectoryFetcher
+	maximumExecutions         uint32
+}
+
+// NewInputRootMinimizingBuildExecutor creates a decorator for
+// BuildExecutor that computes the minimal set of input files that is
+// needed by an action to succeed. It does this by re-executing the
+// action with files removed from its input root. Files that were not
+// read by the original execution are removed first, as these are most
+// likely not needed. The results are attached to the auxiliary
+// metadata of the ActionResult in the form of an
+// InputRootMinimizationReport message.
+//
+// Minimization is only performed for actions that set the
+// "minimize-input-root" platform property to "true", and that succeed
+// when executed with their original input root. Like
+// ReadFilesReportingBuildExecutor, this decorator needs to be placed
+// below PrefetchingBuildExecutor.
+func NewInputRootMinimizingBuildExecutor(buildExecutor BuildExecutor, contentAddressableStorage blobstore.BlobAccess, directoryFetcher cas.DirectoryFetcher, maximumExecutions uint32) BuildExecutor {
+	return &inputRootMinimizingBuildExecutor{
+		BuildExecutor:             buildExecutor,
+		contentAddressableStorage: contentAddressableStorage,
+		directoryFetcher:          directoryFetcher,
+		maximumExecutions:         maximumExecutions,
+	}
+}
+
+func (be *inputRootMinimizingBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	minimizationRequested := false
+	for _, property := range request.Action.GetPlatform().GetProperties() {
+		if property.Name == InputRootMinimizationPlatformPropertyName {
+			minimizationRequested = property.Value == "true"
+		}
+	}
+	if !minimizationRequested {
+		return be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+	}
+
+	readFileRecordingMonitor := access.NewReadFileRecordingUnreadDirectoryMonitor(monitor)
+	response := be.BuildExecutor.Execute(ctx, filePool, readFileRecordingMonitor, digestFunction, request, executionStateUpdates)
+	if !isSuccessfulExecuteResponse(response) {
+		return response
+	}
+
+	// Load the full input root, so that pruned copies of it can be
+	// constructed.
+	inputRootDigest, err := digestFunction.NewDigestFromProto(request.Action.InputRootDigest)
+	if err != nil {
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to extract digest for input root"))
+		return response
+	}
+	m := inputRootMinimizer{
+		context:                   ctx,
+		buildExecutor:             be.BuildExecutor,
+		contentAddressableStorage: be.contentAddressableStorage,
+		filePool:                  filePool,
+		digestFunction:            digestFunction,
+		request:                   request,
+		outputPaths:               getOutputPaths(response.Result),
+		removedPaths:              map[string]struct{}{},
+		executionsRemaining:       be.maximumExecutions,
+		minimalInputRootDigest:    request.Action.InputRootDigest,
+	}
+	m.inputRoot, err = be.loadInputRootDirectory(ctx, inputRootDigest, "", &m.inputRootFiles)
+	if err != nil {
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to load input root"))
+		return response
+	}
+
+	// Partition the files in the input root into ones that have
+	// and have not been read. Attempt to remove the files that
+	// have not been read first, as those are the most likely
+	// candidates for removal.
+	readFiles := map[string]struct{}{}
+	for _, p := range readFileRecordingMonitor.GetReadFiles() {
+		readFiles[p] = struct{}{}
+	}
+	var unreadCandidates, readCandidates []string
+	for _, p := range m.inputRootFiles {
+		if _, ok := readFiles[p]; ok {
+			readCandidates = append(readCandidates, p)
+		} else {
+			unreadCandidates = append(unreadCandidates, p)
+		}
+	}
+
+	// Re-executions of the action should not cause updates to be
+	// sent to the scheduler, as the scheduler would interpret
+	// these as the action restarting.
+	discardedExecutionStateUpdates := make(chan *remoteworker.CurrentState_Executing)
+	go func() {
+		for range discardedExecutionStateUpdates {
+		}
+	}()
+	m.executionStateUpdates = discardedExecutionStateUpdates
+	err = m.removeUnneededPaths(unreadCandidates)
+	if err == nil {
+		err = m.removeUnneededPaths(readCandidates)
+	}
+	close(discardedExecutionStateUpdates)
+	if err != nil {
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to minimize input root"))
+		return response
+	}
+
+	// Store the list of unneeded paths in the CAS.
+	unneededPaths := make([]string, 0, len(m.removedPaths))
+	for p := range m.removedPaths {
+		unneededPaths = append(unneededPaths, p)
+	}
+	sort.Strings(unneededPaths)
+	var pathsData strings.Builder
+	for _, p := range unneededPaths {
+		pathsData.WriteString(p)
+		pathsData.WriteByte('\n')
+	}
+	pathsBlob := []byte(pathsData.String())
+	pathsDigest := computeBlobDigest(digestFunction, pathsBlob)
+	if err := be.contentAddressableStorage.Put(ctx, pathsDigest, buffer.NewValidatedBufferFromByteSlice(pathsBlob)); err != nil {
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to store list of unneeded input root files"))
+		return response
+	}
+
+	if reportMetadata, err := anypb.New(&resourceusage.InputRootMinimizationReport{
+		InputRootFilesCount:    uint64(len(m.inputRootFiles)),
+		ReadFilesCount:         uint64(len(readCandidates)),
+		UnneededPathsDigest:    pathsDigest.GetProto(),
+		UnneededPathsCount:     uint64(len(unneededPaths)),
+		MinimalInputRootDigest: m.minimalInputRootDigest,
+		ExecutionsCount:        m.executionsCount,
+		Complete:               !m.budgetExhausted,
+	}); err == nil {
+		response.Result.ExecutionMetadata.AuxiliaryMetadata = append(response.Result.ExecutionMetadata.AuxiliaryMetadata, reportMetadata)
+	} else {
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to marshal input root minimization report"))
+	}
+	return response
+}
+
+// loadInputRootDirectory recursively loads the Directory messages of
+// an input root, returning them in the form of a tree. The paths of
+// all files contained in the input root are appended to a list.
+func (be *inputRootMinimizingBuildExecutor) loadInputRootDirectory(ctx context.Context, directoryDigest digest.Digest, directoryPath string, files *[]string) (*inputRootDirectory, error) {
+	directory, err := be.directoryFetcher.GetDirectory(ctx, directoryDigest)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to fetch directory %#v", directoryPath)
+	}
+	d := &inputRootDirectory{
+		directory:   directory,
+		digest:      directoryDigest.GetProto(),
+		path:        directoryPath,
+		directories: make([]*inputRootDirectory, 0, len(directory.Directories)),
+	}
+	for _, file := range directory.Files {
+		*files = append(*files, joinInputRootPath(directoryPath, file.Name))
+	}
+	for _, childDirectory := range directory.Directories {
+		childPath := joinInputRootPath(directoryPath, childDirectory.Name)
+		childDigest, err := directoryDigest.GetDigestFunction().NewDigestFromProto(childDirectory.Digest)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Failed to extract digest for directory %#v", childPath)
+		}
+		child, err := be.loadInputRootDirectory(ctx, childDigest, childPath, files)
+		if err != nil {
+			return nil, err
+		}
+		d.directories = append(d.directories, child)
+	}
+	return d, nil
+}
+
+func joinInputRootPath(directoryPath, name string) string {
+	if directoryPath == "" {
+		return name
+	}
+	return directoryPath + "/" + name
+}
+
+// computeBlobDigest computes the digest of a blob that is about to be
+// stored in the Content Addressable Storage.
+func computeBlobDigest(digestFunction digest.Function, data []byte) digest.Digest {
+	digestGenerator := digestFunction.NewGenerator(int64(len(data)))
+	if _, err := digestGenerator.Write(data); err != nil {
+		panic(err)
+	}
+	return digestGenerator.Sum()
+}
+
+// isSuccessfulExecuteResponse returns whether an ExecuteResponse
+// describes an action that ran to completion with exit code zero.
+func isSuccessfulExecuteResponse(response *remoteexecution.ExecuteResponse) bool {
+	return status.ErrorProto(response.Status) == nil && response.Result != nil && response.Result.ExitCode == 0
+}
+
+// getOutputPaths returns the sorted paths of all outputs contained in
+// an ActionResult. These are compared to ensure that executions of the
+// action against a pruned input root yield the same set of outputs.
+func getOutputPaths(actionResult *remoteexecution.ActionResult) []string {
+	var outputPaths []string
+	for _, outputFile := range actionResult.OutputFiles {
+		outputPaths = append(outputPaths, outputFile.Path)
+	}
+	for _, outputDirectory := range actionResult.OutputDirectories {
+		outputPaths = append(outputPaths, outputDirectory.Path)
+	}
+	for _, outputSymlink := range actionResult.OutputSymlinks {
+		outputPaths = append(outputPaths, outputSymlink.Path)
+	}
+	sort.Strings(outputPaths)
+	return outputPaths
+}
+
+// inputRootDirectory is a directory in the input root of an action
+// that is being minimized.
+type inputRootDirectory struct {
+	directory *remoteexecution.Directory
+	digest    *remoteexecution.Digest
+	path      string
+	// Child directories, in the same order as directory.Directories.
+	directories []*inputRootDirectory
+}
+
+// inputRootMinimizer holds the state of the minimization of the input
+// root of a single action.
+type inputRootMinimizer struct {
+	context                   context.Context
+	buildExecutor             BuildExecutor
+	contentAddressableStorage blobstore.BlobAccess
+	filePool                  re_filesystem.FilePool
+	digestFunction            digest.Function
+	request                   *remoteworker.DesiredState_Executing
+	executionStateUpdates     chan<- *remoteworker.CurrentState_Executing
+	inputRoot                 *inputRootDirectory
+	inputRootFiles            []string
+	outputPaths               []string
+
+	removedPaths           map[string]struct{}
+	executionsRemaining    uint32
+	executionsCount        uint32
+	budgetExhausted        bool
+	minimalInputRootDigest *remoteexecution.Digest
+}
+
+// removeUnneededPaths attempts to remove a list of files from the input
+// root. Initially, all files are removed at once. If that causes the
+// action to fail, the list is split up into increasingly smaller
+// chunks, until each of the remaining files has been tested
+// individually.
+func (m *inputRootMinimizer) removeUnneededPaths(candidates []string) error {
+	for chunks := 1; chunks <= len(candidates); {
+		chunkSize := (len(candidates) + chunks - 1) / chunks
+		var remainingCandidates []string
+		for i := 0; i < len(candidates); i += chunkSize {
+			chunk := candidates[i:min(i+chunkSize, len(candidates))]
+			if m.executionsRemaining == 0 {
+				m.budgetExhausted = true
+				return nil
+			}
+			removed, err := m.tryRemovingPaths(chunk)
+			if err != nil {
+				return err
+			}
+			if !removed {
+				remainingCandidates = append(remainingCandidates, chunk...)
+			}
+		}
+		if len(remainingCandidates) == len(candidates) {
+			// None of the chunks could be removed. Retry
+			// with a finer granularity.
+			chunks *= 2
+		}
+		candidates = remainingCandidates
+	}
+	return nil
+}
+
+// tryRemovingPaths re-executes the action with a set of files removed
+// from the input root, on top of the files that were removed
+// previously. If the action still succeeds, the files remain removed.
+func (m *inputRootMinimizer) tryRemovingPaths(paths []string) (bool, error) {
+	if err := util.StatusFromContext(m.context); err != nil {
+		return false, err
+	}
+	removedPaths := make(map[string]struct{}, len(m.removedPaths)+len(paths))
+	for p := range m.removedPaths {
+		removedPaths[p] = struct{}{}
+	}
+	for _, p := range paths {
+		removedPaths[p] = struct{}{}
+	}
+	inputRootDigest, err := m.uploadPrunedDirectory(m.inputRoot, removedPaths)
+	if err != nil {
+		return false, err
+	}
+
+	action := proto.Clone(m.request.Action).(*remoteexecution.Action)
+	action.InputRootDigest = inputRootDigest
+	request := proto.Clone(m.request).(*remoteworker.DesiredState_Executing)
+	request.Action = action
+
+	m.executionsRemaining--
+	m.executionsCount++
+	response := m.buildExecutor.Execute(m.context, m.filePool, nil, m.digestFunction, request, m.executionStateUpdates)
+	if !isSuccessfulExecuteResponse(response) || !slices.Equal(getOutputPaths(response.Result), m.outputPaths) {
+		return false, nil
+	}
+	m.removedPaths = removedPaths
+	m.minimalInputRootDigest = inputRootDigest
+	return true, nil
+}
+
+// uploadPrunedDirectory creates a copy of a directory in the input root
+// that has a set of files removed, and stores it in the Content
+// Addressable Storage. Directories that are not affected by the
+// removal are not uploaded, as their original contents can be used.
+func (m *inputRootMinimizer) uploadPrunedDirectory(d *inputRootDirectory, removedPaths map[string]struct{}) (*remoteexecution.Digest, error) {
+	original := d.directory
+	pruned := &remoteexecution.Directory{
+		Files:          make([]*remoteexecution.FileNode, 0, len(original.Files)),
+		Directories:    make([]*remoteexecution.DirectoryNode, 0, len(original.Directories)),
+		Symlinks:       original.Symlinks,
+		NodeProperties: original.NodeProperties,
+	}
+	modified := false
+	for _, file := range original.Files {
+		if _, ok := removedPaths[joinInputRootPath(d.path, file.Name)]; ok {
+			modified = true
+		} else {
+			pruned.Files = append(pruned.Files, file)
+		}
+	}
+	for i, childDirectory := range original.Directories {
+		childDigest, err := m.uploadPrunedDirectory(d.directories[i], removedPaths)
+		if err != nil {
+			return nil, err
+		}
+		if proto.Equal(childDigest, childDirectory.Digest) {
+			pruned.Directories = append(pruned.Directories, childDirectory)
+		} else {
+			modified = true
+			pruned.Directories = append(pruned.Directories, &remoteexecution.DirectoryNode{
+				Name:   childDirectory.Name,
+				Digest: childDigest,
+			})
+		}
+	}
+	if !modified {
+		return d.digest, nil
+	}
+
+	data, err := proto.Marshal(pruned)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to marshal directory %#v", d.path)
+	}
+	directoryDigest := computeBlobDigest(m.digestFunction, data)
+	if err := m.contentAddressableStorage.Put(m.context, directoryDigest, buffer.NewValidatedBufferFromByteSlice(data)); err != nil {
+		return nil, util.StatusWrapf(err, "Failed to store directory %#v", d.path)
+	}
+	return directoryDigest.GetProto(), nil
+}
@@ -0,0 +1,260 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestInputRootMinimizingBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	buildExecutor := builder.NewInputRootMinimizingBuildExecutor(baseBuildExecutor, contentAddressableStorage, directoryFetcher, 10)
+
+	filePool := mock.NewMockFilePool(ctrl)
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5)
+	executionStateUpdates := make(chan *remoteworker.CurrentState_Executing, 3)
+
+	t.Run("NotRequested", func(t *testing.T) {
+		// Actions that don't set the platform property should
+		// be executed regularly.
+		request := &remoteworker.DesiredState_Executing{
+			Action: &remoteexecution.Action{
+				InputRootDigest: &remoteexecution.Digest{
+					Hash:      "1e7e4a4a8f43c4a4bdf3a6ab0b6e16a8",
+					SizeBytes: 123,
+				},
+			},
+		}
+		monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(&remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExitCode:          1,
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		})
+
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExitCode:          1,
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		}, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Input root containing three files, of which two are
+		// read by the action.
+		rootDirectoryDigest := &remoteexecution.Digest{
+			Hash:      "7f0eb6a0d1bc4fd0a4b1e5e3ea1b4b6c",
+			SizeBytes: 200,
+		}
+		includeDirectoryDigest := &remoteexecution.Digest{
+			Hash:      "a5c4d1a3b7e8e3f1b0e2d97e3d4b6f1a",
+			SizeBytes: 50,
+		}
+		aC := &remoteexecution.FileNode{
+			Name: "a.c",
+			Digest: &remoteexecution.Digest{
+				Hash:      "0cc175b9c0f1b6a831c399e269772661",
+				SizeBytes: 1,
+			},
+		}
+		bH := &remoteexecution.FileNode{
+			Name: "b.h",
+			Digest: &remoteexecution.Digest{
+				Hash:      "92eb5ffee6ae2fec3ad71c777531578f",
+				SizeBytes: 1,
+			},
+		}
+		stdioH := &remoteexecution.FileNode{
+			Name: "stdio.h",
+			Digest: &remoteexecution.Digest{
+				Hash:      "4a8a08f09d37b73795649038408b5f33",
+				SizeBytes: 1,
+			},
+		}
+		request := &remoteworker.DesiredState_Executing{
+			Action: &remoteexecution.Action{
+				InputRootDigest: rootDirectoryDigest,
+				Platform: &remoteexecution.Platform{
+					Properties: []*remoteexecution.Platform_Property{
+						{Name: "minimize-input-root", Value: "true"},
+					},
+				},
+			},
+		}
+		successfulResponse := &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				OutputFiles: []*remoteexecution.OutputFile{
+					{
+						Path: "a.o",
+						Digest: &remoteexecution.Digest{
+							Hash:      "e1faffb3e614e6c2fba74296962386b7",
+							SizeBytes: 3,
+						},
+					},
+				},
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		}
+
+		// Initial execution of the action.
+		monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+		rootReadDirectoryMonitor := mock.NewMockReadDirectoryMonitor(ctrl)
+		monitor.EXPECT().ReadDirectory().Return(rootReadDirectoryMonitor)
+		rootReadDirectoryMonitor.EXPECT().ReadFile(path.MustNewComponent("a.c"))
+		includeUnreadDirectoryMonitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+		rootReadDirectoryMonitor.EXPECT().ResolvedDirectory(path.MustNewComponent("include")).Return(includeUnreadDirectoryMonitor)
+		includeReadDirectoryMonitor := mock.NewMockReadDirectoryMonitor(ctrl)
+		includeUnreadDirectoryMonitor.EXPECT().ReadDirectory().Return(includeReadDirectoryMonitor)
+		includeReadDirectoryMonitor.EXPECT().ReadFile(path.MustNewComponent("stdio.h"))
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, gomock.Any(), digestFunction, request, executionStateUpdates).DoAndReturn(
+			func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+				rootReadDirectoryMonitor := monitor.ReadDirectory()
+				rootReadDirectoryMonitor.ReadFile(path.MustNewComponent("a.c"))
+				rootReadDirectoryMonitor.ResolvedDirectory(path.MustNewComponent("include")).ReadDirectory().ReadFile(path.MustNewComponent("stdio.h"))
+				executionStateUpdates <- &remoteworker.CurrentState_Executing{}
+				return successfulResponse
+			})
+		directoryFetcher.EXPECT().GetDirectory(ctx, digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "7f0eb6a0d1bc4fd0a4b1e5e3ea1b4b6c", 200)).
+			Return(&remoteexecution.Directory{
+				Files: []*remoteexecution.FileNode{aC, bH},
+				Directories: []*remoteexecution.DirectoryNode{
+					{Name: "include", Digest: includeDirectoryDigest},
+				},
+			}, nil)
+		directoryFetcher.EXPECT().GetDirectory(ctx, digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "a5c4d1a3b7e8e3f1b0e2d97e3d4b6f1a", 50)).
+			Return(&remoteexecution.Directory{
+				Files: []*remoteexecution.FileNode{stdioH},
+			}, nil)
+
+		// Helpers for validating that pruned directories are
+		// uploaded, and that the action is re-executed against
+		// the resulting input root.
+		expectPut := func(expectedDirectory *remoteexecution.Directory) *remoteexecution.Digest {
+			var uploadedDigest remoteexecution.Digest
+			contentAddressableStorage.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+					directory, err := b.ToProto(&remoteexecution.Directory{}, 10000)
+					require.NoError(t, err)
+					testutil.RequireEqualProto(t, expectedDirectory, directory)
+					uploadedDigest.Hash = blobDigest.GetHashString()
+					uploadedDigest.SizeBytes = blobDigest.GetSizeBytes()
+					return nil
+				})
+			return &uploadedDigest
+		}
+		expectExecute := func(expectedInputRootDigest *remoteexecution.Digest, response *remoteexecution.ExecuteResponse) {
+			baseBuildExecutor.EXPECT().Execute(ctx, filePool, gomock.Nil(), digestFunction, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+					testutil.RequireEqualProto(t, expectedInputRootDigest, request.Action.InputRootDigest)
+					require.Equal(t, "minimize-input-root", request.Action.Platform.Properties[0].Name)
+
+					// Updates should not be forwarded.
+					executionStateUpdates <- &remoteworker.CurrentState_Executing{}
+					return response
+				})
+		}
+
+		// The unread file b.h should be removed first, which
+		// succeeds.
+		inputRootWithoutBH := expectPut(&remoteexecution.Directory{
+			Files: []*remoteexecution.FileNode{aC},
+			Directories: []*remoteexecution.DirectoryNode{
+				{Name: "include", Digest: includeDirectoryDigest},
+			},
+		})
+		expectExecute(inputRootWithoutBH, successfulResponse)
+
+		// Removing both files that were read causes the action
+		// to fail.
+		emptyIncludeDirectory := expectPut(&remoteexecution.Directory{})
+		inputRootWithoutReadFiles := expectPut(&remoteexecution.Directory{
+			Directories: []*remoteexecution.DirectoryNode{
+				{Name: "include", Digest: emptyIncludeDirectory},
+			},
+		})
+		expectExecute(inputRootWithoutReadFiles, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExitCode:          1,
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		})
+
+		// Removing a.c causes the action to no longer yield its
+		// output file. It should therefore be retained.
+		inputRootWithoutAC := expectPut(&remoteexecution.Directory{
+			Directories: []*remoteexecution.DirectoryNode{
+				{Name: "include", Digest: includeDirectoryDigest},
+			},
+		})
+		expectExecute(inputRootWithoutAC, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		})
+
+		// Removing include/stdio.h succeeds.
+		emptyIncludeDirectory2 := expectPut(&remoteexecution.Directory{})
+		minimalInputRoot := expectPut(&remoteexecution.Directory{
+			Files: []*remoteexecution.FileNode{aC},
+			Directories: []*remoteexecution.DirectoryNode{
+				{Name: "include", Digest: emptyIncludeDirectory2},
+			},
+		})
+		expectExecute(minimalInputRoot, successfulResponse)
+
+		// The list of unneeded files should be written into the
+		// CAS.
+		pathsDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "c969fe62053579c33a0986d09403702b", 20)
+		contentAddressableStorage.EXPECT().Put(ctx, pathsDigest, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+				data, err := b.ToByteSlice(1000)
+				require.NoError(t, err)
+				require.Equal(t, "b.h\ninclude/stdio.h\n", string(data))
+				return nil
+			})
+
+		response := buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+		require.Len(t, executionStateUpdates, 1)
+		<-executionStateUpdates
+
+		report, err := anypb.New(&resourceusage.InputRootMinimizationReport{
+			InputRootFilesCount:    3,
+			ReadFilesCount:         2,
+			UnneededPathsDigest:    pathsDigest.GetProto(),
+			UnneededPathsCount:     2,
+			MinimalInputRootDigest: minimalInputRoot,
+			ExecutionsCount:        4,
+			Complete:               true,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				OutputFiles: successfulResponse.Result.OutputFiles,
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+					AuxiliaryMetadata: []*anypb.Any{report},
+				},
+			},
+		}, response)
+	})
+}
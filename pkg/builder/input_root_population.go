@@ -0,0 +1,211 @@
+package builder
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+// InputRootPopulationOptions contains options that control the order
+// and concurrency with which NaiveBuildDirectory populates input
+// roots. The semaphores contained in this struct may be shared by all
+// build directories of a worker, thereby bounding the total load that
+// the worker places on the Content Addressable Storage.
+type InputRootPopulationOptions struct {
+	// If not nil, the Directory objects of the input root are
+	// prefetched in parallel, bounded by this semaphore. If nil,
+	// Directory objects are fetched sequentially, while traversing
+	// the input root.
+	DirectoryFetchSemaphore *semaphore.Weighted
+
+	// Files whose size is at least LargeFileSizeBytes are fetched
+	// in the background, bounded by LargeFileFetchSemaphore, as
+	// opposed to the semaphore used for regular input files. This
+	// prevents large files from stalling the population of the
+	// remainder of the input root. If LargeFileFetchSemaphore is
+	// nil, no distinction between small and large files is made.
+	LargeFileSizeBytes      int64
+	LargeFileFetchSemaphore *semaphore.Weighted
+
+	// If non-zero, the rate at which input files are fetched on
+	// behalf of a single build action is limited to the provided
+	// number of bytes per second.
+	MaximumBytesPerSecondPerAction int64
+	Clock                          clock.Clock
+}
+
+// inputRootPopulation contains the state of a single call to
+// NaiveBuildDirectory.MergeDirectoryContents().
+type inputRootPopulation struct {
+	group               *errgroup.Group
+	directoryPrefetcher *inputDirectoryPrefetcher
+	bandwidthLimiter    *bandwidthLimiter
+}
+
+func (o *InputRootPopulationOptions) newInputRootPopulation(ctx context.Context, group *errgroup.Group, directoryFetcher cas.DirectoryFetcher) *inputRootPopulation {
+	p := &inputRootPopulation{group: group}
+	if o == nil {
+		return p
+	}
+	if o.DirectoryFetchSemaphore != nil {
+		p.directoryPrefetcher = &inputDirectoryPrefetcher{
+			context:          ctx,
+			group:            group,
+			directoryFetcher: directoryFetcher,
+			semaphore:        o.DirectoryFetchSemaphore,
+			directories:      map[digest.Digest]*prefetchedInputDirectory{},
+		}
+	}
+	if o.MaximumBytesPerSecondPerAction > 0 {
+		p.bandwidthLimiter = &bandwidthLimiter{
+			clock:          o.Clock,
+			bytesPerSecond: o.MaximumBytesPerSecondPerAction,
+		}
+	}
+	return p
+}
+
+// waitForBandwidth blocks until the bandwidth limit of the build action
+// permits fetching a file of a given size.
+func (p *inputRootPopulation) waitForBandwidth(ctx context.Context, sizeBytes int64) error {
+	if p.bandwidthLimiter == nil {
+		return nil
+	}
+	return p.bandwidthLimiter.wait(ctx, sizeBytes)
+}
+
+// isLargeFile returns whether a file is large enough that it should be
+// fetched using LargeFileFetchSemaphore.
+func (o *InputRootPopulationOptions) isLargeFile(sizeBytes int64) bool {
+	return o != nil && o.LargeFileFetchSemaphore != nil && sizeBytes >= o.LargeFileSizeBytes
+}
+
+// sortFilesByFetchPriority returns a copy of a list of files, sorted
+// in the order in which they should be fetched. Executables are
+// fetched first, as these tend to include the program that is invoked
+// by the build action. Other files are fetched in order of increasing
+// size, so that a small number of large files does not delay the
+// creation of the majority of the input root.
+func sortFilesByFetchPriority(files []*remoteexecution.FileNode) []*remoteexecution.FileNode {
+	sorted := append([]*remoteexecution.FileNode(nil), files...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].IsExecutable != sorted[j].IsExecutable {
+			return sorted[i].IsExecutable
+		}
+		return sorted[i].Digest.GetSizeBytes() < sorted[j].Digest.GetSizeBytes()
+	})
+	return sorted
+}
+
+type prefetchedInputDirectory struct {
+	done      chan struct{}
+	directory *remoteexecution.Directory
+	err       error
+}
+
+// inputDirectoryPrefetcher loads Directory objects that are part of
+// an input root in the background, so that they are available by the
+// time the input root is traversed.
+type inputDirectoryPrefetcher struct {
+	context          context.Context
+	group            *errgroup.Group
+	directoryFetcher cas.DirectoryFetcher
+	semaphore        *semaphore.Weighted
+
+	lock        sync.Mutex
+	directories map[digest.Digest]*prefetchedInputDirectory
+}
+
+// start loading a Directory object in the background, if this has not
+// been done already.
+func (dp *inputDirectoryPrefetcher) start(directoryDigest digest.Digest) *prefetchedInputDirectory {
+	dp.lock.Lock()
+	defer dp.lock.Unlock()
+
+	if pd, ok := dp.directories[directoryDigest]; ok {
+		return pd
+	}
+	pd := &prefetchedInputDirectory{done: make(chan struct{})}
+	dp.directories[directoryDigest] = pd
+	dp.group.Go(func() error {
+		// Errors are not returned, as these would cancel the
+		// population of the input root. They are reported by
+		// get(), so that they are annotated with the path of the
+		// directory.
+		if err := dp.semaphore.Acquire(dp.context, 1); err != nil {
+			pd.err = util.StatusFromContext(dp.context)
+		} else {
+			pd.directory, pd.err = dp.directoryFetcher.GetDirectory(dp.context, directoryDigest)
+			dp.semaphore.Release(1)
+		}
+		close(pd.done)
+		return nil
+	})
+	return pd
+}
+
+// startChildren starts loading the child directories of a Directory
+// object. Children with invalid digests are ignored, as these are
+// reported during traversal.
+func (dp *inputDirectoryPrefetcher) startChildren(directory *remoteexecution.Directory, digestFunction digest.Function) {
+	for _, child := range directory.Directories {
+		if childDigest, err := digestFunction.NewDigestFromProto(child.Digest); err == nil {
+			dp.start(childDigest)
+		}
+	}
+}
+
+// get a Directory object, waiting for it to be loaded if needed.
+func (dp *inputDirectoryPrefetcher) get(directoryDigest digest.Digest) (*remoteexecution.Directory, error) {
+	pd := dp.start(directoryDigest)
+	select {
+	case <-pd.done:
+		return pd.directory, pd.err
+	case <-dp.context.Done():
+		return nil, util.StatusFromContext(dp.context)
+	}
+}
+
+// bandwidthLimiter limits the rate at which input files are fetched on
+// behalf of a single build action. Every fetch is assigned a time slot
+// whose duration is proportional to the size of the file. Fetches are
+// delayed until the start of their time slot.
+type bandwidthLimiter struct {
+	clock          clock.Clock
+	bytesPerSecond int64
+
+	lock          sync.Mutex
+	nextAvailable time.Time
+}
+
+func (bl *bandwidthLimiter) wait(ctx context.Context, sizeBytes int64) error {
+	bl.lock.Lock()
+	now := bl.clock.Now()
+	start := bl.nextAvailable
+	if start.Before(now) {
+		start = now
+	}
+	bl.nextAvailable = start.Add(time.Duration(float64(sizeBytes) / float64(bl.bytesPerSecond) * float64(time.Second)))
+	bl.lock.Unlock()
+
+	if delay := start.Sub(now); delay > 0 {
+		timer, timerChannel := bl.clock.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return util.StatusFromContext(ctx)
+		case <-timerChannel:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package builder
+
+import (
+	"context"
+
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+// InputRootPrefetcher is used by BuildClient to warm up local caches
+// for an action that the scheduler has tentatively assigned to the
+// worker, while the worker is still executing another action. This
+// hides the latency of fetching input files for short running actions.
+type InputRootPrefetcher interface {
+	PrefetchInputRoot(ctx context.Context, inputRootDigest digest.Digest) error
+}
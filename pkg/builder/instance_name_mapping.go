@@ -0,0 +1,56 @@
+package builder
+
+import (
+	"github.com/buildbarn/bb-storage/pkg/digest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// InstanceNameMapping translates the instance names of actions provided
+// by the scheduler to the instance names that are used to access the
+// Content Addressable Storage and Action Cache. This permits a single
+// pool of workers to execute actions on behalf of multiple tenants,
+// whose data is stored in logically isolated namespaces. Combined with
+// a demultiplexing storage configuration, every tenant may even use
+// its own storage backends.
+//
+// Instance names are matched against the longest prefix that was
+// registered. Instance names not matching any prefix are left
+// unmodified. A nil InstanceNameMapping does not alter any instance
+// names.
+type InstanceNameMapping struct {
+	trie     *digest.InstanceNameTrie
+	patchers []digest.InstanceNamePatcher
+}
+
+// NewInstanceNameMapping creates an InstanceNameMapping that does not
+// contain any mappings.
+func NewInstanceNameMapping() *InstanceNameMapping {
+	return &InstanceNameMapping{
+		trie: digest.NewInstanceNameTrie(),
+	}
+}
+
+// Add a mapping, causing instance names having a given prefix to have
+// that prefix replaced.
+func (m *InstanceNameMapping) Add(oldPrefix, newPrefix digest.InstanceName) error {
+	if m.trie.ContainsExact(oldPrefix) {
+		return status.Errorf(codes.InvalidArgument, "Multiple mappings for instance name prefix %#v", oldPrefix.String())
+	}
+	m.trie.Set(oldPrefix, len(m.patchers))
+	m.patchers = append(m.patchers, digest.NewInstanceNamePatcher(oldPrefix, newPrefix))
+	return nil
+}
+
+// MapInstanceName translates an instance name provided by the
+// scheduler to the instance name under which storage is accessed.
+func (m *InstanceNameMapping) MapInstanceName(instanceName digest.InstanceName) digest.InstanceName {
+	if m == nil {
+		return instanceName
+	}
+	if idx := m.trie.GetLongestPrefix(instanceName); idx >= 0 {
+		return m.patchers[idx].PatchInstanceName(instanceName)
+	}
+	return instanceName
+}
@@ -0,0 +1,45 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestInstanceNameMapping(t *testing.T) {
+	instanceNameMapping := builder.NewInstanceNameMapping()
+	require.NoError(t, instanceNameMapping.Add(digest.MustNewInstanceName("tenants/a"), digest.MustNewInstanceName("storage/a")))
+	require.NoError(t, instanceNameMapping.Add(digest.MustNewInstanceName("tenants/a/secret"), digest.MustNewInstanceName("secret")))
+
+	t.Run("Duplicate", func(t *testing.T) {
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.InvalidArgument, "Multiple mappings for instance name prefix \"tenants/a\""),
+			instanceNameMapping.Add(digest.MustNewInstanceName("tenants/a"), digest.EmptyInstanceName))
+	})
+
+	t.Run("NoMatch", func(t *testing.T) {
+		require.Equal(t, digest.MustNewInstanceName("tenants/b"), instanceNameMapping.MapInstanceName(digest.MustNewInstanceName("tenants/b")))
+		require.Equal(t, digest.MustNewInstanceName("tenants/ab"), instanceNameMapping.MapInstanceName(digest.MustNewInstanceName("tenants/ab")))
+	})
+
+	t.Run("Match", func(t *testing.T) {
+		require.Equal(t, digest.MustNewInstanceName("storage/a"), instanceNameMapping.MapInstanceName(digest.MustNewInstanceName("tenants/a")))
+		require.Equal(t, digest.MustNewInstanceName("storage/a/x"), instanceNameMapping.MapInstanceName(digest.MustNewInstanceName("tenants/a/x")))
+	})
+
+	t.Run("LongestPrefix", func(t *testing.T) {
+		require.Equal(t, digest.MustNewInstanceName("secret/x"), instanceNameMapping.MapInstanceName(digest.MustNewInstanceName("tenants/a/secret/x")))
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		var nilMapping *builder.InstanceNameMapping
+		require.Equal(t, digest.MustNewInstanceName("tenants/a"), nilMapping.MapInstanceName(digest.MustNewInstanceName("tenants/a")))
+	})
+}
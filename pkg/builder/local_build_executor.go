@@ -3,6 +3,7 @@ package builder
 import (
 	"context"
 	"os"
+	"strconv"
 	"sync"
 	"time"
 
@@ -10,8 +11,14 @@ import (
 	re_clock "github.com/buildbarn/bb-remote-execution/pkg/clock"
 	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
 	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/errorclassification"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/hermeticity"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
 	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/vcsmetadata"
+	"github.com/buildbarn/bb-remote-execution/pkg/secrets"
+	"github.com/buildbarn/bb-storage/pkg/auth"
 	"github.com/buildbarn/bb-storage/pkg/blobstore"
 	"github.com/buildbarn/bb-storage/pkg/clock"
 	"github.com/buildbarn/bb-storage/pkg/digest"
@@ -21,6 +28,8 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -36,6 +45,13 @@ var (
 	checkReadinessComponent      = path.MustNewComponent("check_readiness")
 )
 
+// localeEnvironmentVariableNames contains the names of environment
+// variables that alter the behavior of locale and time zone sensitive
+// tools. Actions that use values for these environment variables that
+// differ from the ones configured on the worker have a
+// HermeticityReport attached to their ActionResult.
+var localeEnvironmentVariableNames = []string{"LANG", "LC_ALL", "TZ"}
+
 // capturingErrorLogger is an error logger that stores up to a single
 // error. When the error is stored, a context cancelation function is
 // invoked. This is used by localBuildExecutor to kill a build action in
@@ -64,29 +80,166 @@ func (el *capturingErrorLogger) GetError() error {
 	return el.error
 }
 
+// VCSMetadataEnvironmentVariables contains the names of environment
+// variables through which version control system metadata provided by
+// the client is exposed to actions. Empty names cause the
+// corresponding value not to be exposed.
+type VCSMetadataEnvironmentVariables struct {
+	CommitSHA string
+	Dirty     string
+}
+
+// SecretEnvironmentVariable is an environment variable whose value is
+// obtained from a secrets provider. It is only injected into actions
+// whose instance name is equal to or is a child of
+// InstanceNamePrefix.
+type SecretEnvironmentVariable struct {
+	Name               string
+	InstanceNamePrefix digest.InstanceName
+	SecretName         string
+}
+
+// SecretEnvironmentVariables contains the environment variables whose
+// values are obtained from a secrets provider, and the provider from
+// which they are obtained.
+type SecretEnvironmentVariables struct {
+	Provider  secrets.Provider
+	Variables []SecretEnvironmentVariable
+}
+
+// instanceNameHasPrefix returns whether an instance name is equal to
+// or is a child of a given prefix.
+func instanceNameHasPrefix(instanceName, prefix digest.InstanceName) bool {
+	components := instanceName.GetComponents()
+	prefixComponents := prefix.GetComponents()
+	if len(prefixComponents) > len(components) {
+		return false
+	}
+	for i, component := range prefixComponents {
+		if components[i] != component {
+			return false
+		}
+	}
+	return true
+}
+
 type localBuildExecutor struct {
-	contentAddressableStorage      blobstore.BlobAccess
-	buildDirectoryCreator          BuildDirectoryCreator
-	runner                         runner_pb.RunnerClient
-	clock                          clock.Clock
-	inputRootCharacterDevices      map[path.Component]filesystem.DeviceNumber
-	maximumMessageSizeBytes        int
-	environmentVariables           map[string]string
-	forceUploadTreesAndDirectories bool
+	contentAddressableStorage            blobstore.BlobAccess
+	buildDirectoryCreator                BuildDirectoryCreator
+	runner                               runner_pb.RunnerClient
+	clock                                clock.Clock
+	inputRootCharacterDevices            map[path.Component]filesystem.DeviceNumber
+	maximumMessageSizeBytes              int
+	environmentVariables                 map[string]string
+	platformPropertyEnvironmentVariables map[string]string
+	vcsMetadataEnvironmentVariables      *VCSMetadataEnvironmentVariables
+	failedActionPauser                   FailedActionPauser
+	forceUploadTreesAndDirectories       bool
+	outputPruner                         *OutputPruner
+	actionKeepalive                      *ActionKeepalive
+	secretEnvironmentVariables           *SecretEnvironmentVariables
+	fakeTime                             *FakeTime
+	commandOutputTruncation              *CommandOutputTruncation
+	outputLimits                         *OutputLimits
+	actionResultVerification             *ActionResultVerification
+	nestedExecutionProxy                 *NestedExecutionProxy
+	outputPathValidation                 *OutputPathValidation
 }
 
 // NewLocalBuildExecutor returns a BuildExecutor that executes build
 // steps on the local system.
-func NewLocalBuildExecutor(contentAddressableStorage blobstore.BlobAccess, buildDirectoryCreator BuildDirectoryCreator, runner runner_pb.RunnerClient, clock clock.Clock, inputRootCharacterDevices map[path.Component]filesystem.DeviceNumber, maximumMessageSizeBytes int, environmentVariables map[string]string, forceUploadTreesAndDirectories bool) BuildExecutor {
+//
+// Environment variables passed to the action are obtained by taking
+// the ones provided in environmentVariables, followed by ones whose
+// values are provided through the action's platform properties, as
+// described by platformPropertyEnvironmentVariables (a map of platform
+// property names to environment variable names). Environment variables
+// specified in the Command message take precedence over both.
+//
+// If vcsMetadataEnvironmentVariables is not nil, VCS metadata that the
+// scheduler attached to the action's auxiliary metadata is exposed
+// through environment variables as well. This allows actions that
+// need to know the commit SHA of the source tree to obtain it without
+// invoking git against a repository that is not present on the
+// worker. These environment variables also take precedence over the
+// ones provided in environmentVariables and through platform
+// properties, but not over the ones in the Command message.
+//
+// If failedActionPauser is not nil, it is called into for every action
+// that fails and has set the "pause-on-failure" platform property,
+// prior to removing its build directory.
+//
+// Entries in output directories that match outputPruner are not
+// uploaded into the Content Addressable Storage.
+//
+// The size class of the worker, as provided in the execution request,
+// is forwarded to the runner, so that it may apply resource limits that
+// differ between size classes.
+//
+// If actionKeepalive is not nil, actions may set the
+// "keepalive-interval" platform property to run beyond their execution
+// timeout, up to a hard maximum. Such actions need to create a
+// keepalive file in their input root at least once every interval. The
+// path of this file is provided through an environment variable.
+//
+// If secretEnvironmentVariables is not nil, environment variables
+// whose values are obtained from a secrets provider are injected into
+// actions whose instance name matches. These take precedence over all
+// other environment variables, including the ones in the Command
+// message, so that actions observe the values that the worker
+// provides. As they are not part of the Command message, they do not
+// affect the action digest, and are thus not part of the key under
+// which results are stored in the Action Cache. They are also not
+// exposed to failedActionPauser.
+//
+// If fakeTime is not nil, actions may set the "fake-time" platform
+// property to run with a virtualized clock that starts at a fixed
+// point in time. This improves the determinism of tools that embed
+// timestamps into their outputs.
+//
+// If commandOutputTruncation is not nil, the standard output and
+// standard error of actions that exceed a maximum size are truncated
+// prior to being stored in the Content Addressable Storage.
+//
+// If outputLimits is not nil, actions whose outputs exceed the limits
+// fail without any of their outputs being uploaded.
+//
+// If actionResultVerification is not nil, the presence of all blobs
+// referenced by the ActionResult is checked prior to returning it.
+// Outputs are uploaded once more if any of them are found to be absent.
+//
+// If nestedExecutionProxy is not nil, actions may set the
+// "nested-execution" platform property to "true" to obtain access to
+// the Remote Execution API for the duration of their execution. The
+// gRPC target of a proxy that forwards calls to the cluster is
+// provided through an environment variable.
+//
+// If outputPathValidation is not nil, actions whose outputs have paths
+// that cannot be materialized on all platforms (e.g., paths that only
+// differ in case, or that use reserved names on Windows) fail without
+// any of their outputs being uploaded.
+func NewLocalBuildExecutor(contentAddressableStorage blobstore.BlobAccess, buildDirectoryCreator BuildDirectoryCreator, runner runner_pb.RunnerClient, clock clock.Clock, inputRootCharacterDevices map[path.Component]filesystem.DeviceNumber, maximumMessageSizeBytes int, environmentVariables, platformPropertyEnvironmentVariables map[string]string, vcsMetadataEnvironmentVariables *VCSMetadataEnvironmentVariables, failedActionPauser FailedActionPauser, forceUploadTreesAndDirectories bool, outputPruner *OutputPruner, actionKeepalive *ActionKeepalive, secretEnvironmentVariables *SecretEnvironmentVariables, fakeTime *FakeTime, commandOutputTruncation *CommandOutputTruncation, outputLimits *OutputLimits, actionResultVerification *ActionResultVerification, nestedExecutionProxy *NestedExecutionProxy, outputPathValidation *OutputPathValidation) BuildExecutor {
 	return &localBuildExecutor{
-		contentAddressableStorage:      contentAddressableStorage,
-		buildDirectoryCreator:          buildDirectoryCreator,
-		runner:                         runner,
-		clock:                          clock,
-		inputRootCharacterDevices:      inputRootCharacterDevices,
-		maximumMessageSizeBytes:        maximumMessageSizeBytes,
-		environmentVariables:           environmentVariables,
-		forceUploadTreesAndDirectories: forceUploadTreesAndDirectories,
+		contentAddressableStorage:            contentAddressableStorage,
+		buildDirectoryCreator:                buildDirectoryCreator,
+		runner:                               runner,
+		clock:                                clock,
+		inputRootCharacterDevices:            inputRootCharacterDevices,
+		maximumMessageSizeBytes:              maximumMessageSizeBytes,
+		environmentVariables:                 environmentVariables,
+		platformPropertyEnvironmentVariables: platformPropertyEnvironmentVariables,
+		vcsMetadataEnvironmentVariables:      vcsMetadataEnvironmentVariables,
+		failedActionPauser:                   failedActionPauser,
+		forceUploadTreesAndDirectories:       forceUploadTreesAndDirectories,
+		outputPruner:                         outputPruner,
+		actionKeepalive:                      actionKeepalive,
+		secretEnvironmentVariables:           secretEnvironmentVariables,
+		fakeTime:                             fakeTime,
+		commandOutputTruncation:              commandOutputTruncation,
+		outputLimits:                         outputLimits,
+		actionResultVerification:             actionResultVerification,
+		nestedExecutionProxy:                 nestedExecutionProxy,
+		outputPathValidation:                 outputPathValidation,
 	}
 }
 
@@ -130,21 +283,48 @@ func (be *localBuildExecutor) Execute(ctx context.Context, filePool re_filesyste
 	response := NewDefaultExecuteResponse(request)
 	action := request.Action
 	if action == nil {
-		attachErrorToExecuteResponse(response, status.Error(codes.InvalidArgument, "Request does not contain an action"))
+		attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_INPUT_FETCH, status.Error(codes.InvalidArgument, "Request does not contain an action"))
 		return response
 	}
 	if err := action.Timeout.CheckValid(); err != nil {
-		attachErrorToExecuteResponse(
+		attachClassifiedErrorToExecuteResponse(
 			response,
+			errorclassification.Domain_INPUT_FETCH,
 			util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid execution timeout"))
 		return response
 	}
 	executionTimeout := action.Timeout.AsDuration()
 
+	// Actions may request that their execution timeout is extended
+	// for as long as they signal that they are alive.
+	var keepaliveInterval time.Duration
+	if be.actionKeepalive != nil {
+		var err error
+		keepaliveInterval, err = getKeepaliveInterval(action)
+		if err != nil {
+			attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_INPUT_FETCH, err)
+			return response
+		}
+	}
+
+	// Actions may request that they are paused upon failure. Validate
+	// this request before executing the action, so that malformed
+	// values are reported as an error of the action, as opposed to
+	// being detected after the action has failed.
+	var pauseOnFailureDuration time.Duration
+	if be.failedActionPauser != nil {
+		var err error
+		pauseOnFailureDuration, err = getPauseOnFailureDuration(action)
+		if err != nil {
+			attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_INPUT_FETCH, err)
+			return response
+		}
+	}
+
 	// Obtain build directory.
 	actionDigest, err := digestFunction.NewDigestFromProto(request.ActionDigest)
 	if err != nil {
-		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to extract digest for action"))
+		attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_INPUT_FETCH, util.StatusWrap(err, "Failed to extract digest for action"))
 		return response
 	}
 	var actionDigestIfNotRunInParallel *digest.Digest
@@ -153,8 +333,9 @@ func (be *localBuildExecutor) Execute(ctx context.Context, filePool re_filesyste
 	}
 	buildDirectory, buildDirectoryPath, err := be.buildDirectoryCreator.GetBuildDirectory(ctx, actionDigestIfNotRunInParallel)
 	if err != nil {
-		attachErrorToExecuteResponse(
+		attachClassifiedErrorToExecuteResponse(
 			response,
+			errorclassification.Domain_SANDBOX_SETUP,
 			util.StatusWrap(err, "Failed to acquire build environment"))
 		return response
 	}
@@ -165,7 +346,7 @@ func (be *localBuildExecutor) Execute(ctx context.Context, filePool re_filesyste
 	ctxWithIOError, cancelIOError := context.WithCancel(ctx)
 	defer cancelIOError()
 	ioErrorCapturer := capturingErrorLogger{cancel: cancelIOError}
-	buildDirectory.InstallHooks(filePool, &ioErrorCapturer)
+	buildDirectory.InstallHooks(ctx, digestFunction, filePool, &ioErrorCapturer)
 
 	executionStateUpdates <- &remoteworker.CurrentState_Executing{
 		ActionDigest: request.ActionDigest,
@@ -176,15 +357,17 @@ func (be *localBuildExecutor) Execute(ctx context.Context, filePool re_filesyste
 
 	// Create input root directory inside of build directory.
 	if err := buildDirectory.Mkdir(inputRootDirectoryComponent, 0o777); err != nil {
-		attachErrorToExecuteResponse(
+		attachClassifiedErrorToExecuteResponse(
 			response,
+			errorclassification.Domain_SANDBOX_SETUP,
 			util.StatusWrap(err, "Failed to create input root directory"))
 		return response
 	}
 	inputRootDirectory, err := buildDirectory.EnterBuildDirectory(inputRootDirectoryComponent)
 	if err != nil {
-		attachErrorToExecuteResponse(
+		attachClassifiedErrorToExecuteResponse(
 			response,
+			errorclassification.Domain_SANDBOX_SETUP,
 			util.StatusWrap(err, "Failed to enter input root directory"))
 		return response
 	}
@@ -192,19 +375,20 @@ func (be *localBuildExecutor) Execute(ctx context.Context, filePool re_filesyste
 
 	inputRootDigest, err := digestFunction.NewDigestFromProto(action.InputRootDigest)
 	if err != nil {
-		attachErrorToExecuteResponse(
+		attachClassifiedErrorToExecuteResponse(
 			response,
+			errorclassification.Domain_INPUT_FETCH,
 			util.StatusWrap(err, "Failed to extract digest for input root"))
 		return response
 	}
 	if err := inputRootDirectory.MergeDirectoryContents(ctx, &ioErrorCapturer, inputRootDigest, monitor); err != nil {
-		attachErrorToExecuteResponse(response, err)
+		attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_INPUT_FETCH, err)
 		return response
 	}
 
 	if len(be.inputRootCharacterDevices) > 0 {
 		if err := be.createCharacterDevices(inputRootDirectory); err != nil {
-			attachErrorToExecuteResponse(response, err)
+			attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_SANDBOX_SETUP, err)
 			return response
 		}
 	}
@@ -213,22 +397,22 @@ func (be *localBuildExecutor) Execute(ctx context.Context, filePool re_filesyste
 	// These are not declared in the input root explicitly.
 	commandDigest, err := digestFunction.NewDigestFromProto(action.CommandDigest)
 	if err != nil {
-		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to extract digest for command"))
+		attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_INPUT_FETCH, util.StatusWrap(err, "Failed to extract digest for command"))
 		return response
 	}
 	commandMessage, err := be.contentAddressableStorage.Get(ctx, commandDigest).ToProto(&remoteexecution.Command{}, be.maximumMessageSizeBytes)
 	if err != nil {
-		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to obtain command"))
+		attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_INPUT_FETCH, util.StatusWrap(err, "Failed to obtain command"))
 		return response
 	}
 	command := commandMessage.(*remoteexecution.Command)
 	outputHierarchy, err := NewOutputHierarchy(command)
 	if err != nil {
-		attachErrorToExecuteResponse(response, err)
+		attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_INPUT_FETCH, err)
 		return response
 	}
 	if err := outputHierarchy.CreateParentDirectories(inputRootDirectory); err != nil {
-		attachErrorToExecuteResponse(response, err)
+		attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_SANDBOX_SETUP, err)
 		return response
 	}
 
@@ -238,15 +422,17 @@ func (be *localBuildExecutor) Execute(ctx context.Context, filePool re_filesyste
 	// action completes. When using FUSE, it also causes quotas to
 	// be applied to them.
 	if err := buildDirectory.Mkdir(temporaryDirectoryComponent, 0o777); err != nil {
-		attachErrorToExecuteResponse(
+		attachClassifiedErrorToExecuteResponse(
 			response,
+			errorclassification.Domain_SANDBOX_SETUP,
 			util.StatusWrap(err, "Failed to create temporary directory inside build directory"))
 		return response
 	}
 
 	if err := buildDirectory.Mkdir(serverLogsDirectoryComponent, 0o777); err != nil {
-		attachErrorToExecuteResponse(
+		attachClassifiedErrorToExecuteResponse(
 			response,
+			errorclassification.Domain_SANDBOX_SETUP,
 			util.StatusWrap(err, "Failed to create server logs directory inside build directory"))
 		return response
 	}
@@ -262,22 +448,155 @@ func (be *localBuildExecutor) Execute(ctx context.Context, filePool re_filesyste
 	for name, value := range be.environmentVariables {
 		environmentVariables[name] = value
 	}
+	for _, property := range request.Action.GetPlatform().GetProperties() {
+		if name, ok := be.platformPropertyEnvironmentVariables[property.Name]; ok {
+			environmentVariables[name] = property.Value
+		}
+	}
+	if names := be.vcsMetadataEnvironmentVariables; names != nil {
+		for _, auxiliaryMetadata := range request.AuxiliaryMetadata {
+			var vcsMetadata vcsmetadata.VcsMetadata
+			if auxiliaryMetadata.MessageIs(&vcsMetadata) {
+				if err := auxiliaryMetadata.UnmarshalTo(&vcsMetadata); err != nil {
+					attachClassifiedErrorToExecuteResponse(
+						response,
+						errorclassification.Domain_INPUT_FETCH,
+						util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to unmarshal VCS metadata"))
+					return response
+				}
+				if names.CommitSHA != "" {
+					environmentVariables[names.CommitSHA] = vcsMetadata.CommitSha
+				}
+				if names.Dirty != "" {
+					environmentVariables[names.Dirty] = strconv.FormatBool(vcsMetadata.Dirty)
+				}
+			}
+		}
+	}
+	if keepaliveInterval > 0 {
+		environmentVariables[be.actionKeepalive.EnvironmentVariable] = getKeepalivePath(command.WorkingDirectory)
+	}
+	if be.nestedExecutionProxy != nil && isNestedExecutionRequested(action) {
+		// Forward calls using the credentials of the client
+		// that requested execution of this action.
+		authenticationMetadata, err := auth.NewAuthenticationMetadataFromProto(request.AuthenticationMetadata)
+		if err != nil {
+			attachClassifiedErrorToExecuteResponse(
+				response,
+				errorclassification.Domain_SANDBOX_SETUP,
+				util.StatusWrap(err, "Failed to obtain authentication metadata for nested execution proxy"))
+			return response
+		}
+		target, stopProxy, err := be.nestedExecutionProxy.start(digestFunction.GetInstanceName(), authenticationMetadata, be.maximumMessageSizeBytes)
+		if err != nil {
+			attachClassifiedErrorToExecuteResponse(
+				response,
+				errorclassification.Domain_SANDBOX_SETUP,
+				util.StatusWrap(err, "Failed to start nested execution proxy"))
+			return response
+		}
+		defer stopProxy()
+		environmentVariables[be.nestedExecutionProxy.EnvironmentVariable] = target
+	}
 	for _, environmentVariable := range command.EnvironmentVariables {
 		environmentVariables[environmentVariable.Name] = environmentVariable.Value
 	}
 
-	// Invoke the command.
-	ctxWithTimeout, cancelTimeout := be.clock.NewContextWithTimeout(ctxWithIOError, executionTimeout)
-	runResponse, runErr := be.runner.Run(ctxWithTimeout, &runner_pb.RunRequest{
+	// Actions may request that they are run with a virtualized
+	// clock, pinned to a fixed starting point.
+	if be.fakeTime != nil {
+		epoch, ok, err := getFakeTimeEpoch(action, environmentVariables)
+		if err != nil {
+			attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_INPUT_FETCH, err)
+			return response
+		}
+		if ok {
+			be.fakeTime.setEnvironmentVariables(environmentVariables, epoch)
+		}
+	}
+
+	// Report any locale related environment variables for which
+	// the action overrides the value configured on the worker.
+	var hermeticityReport hermeticity.HermeticityReport
+	for _, name := range localeEnvironmentVariableNames {
+		if workerValue, ok := be.environmentVariables[name]; ok {
+			if actionValue := environmentVariables[name]; actionValue != workerValue {
+				hermeticityReport.EnvironmentVariableOverrides = append(
+					hermeticityReport.EnvironmentVariableOverrides,
+					&hermeticity.HermeticityReport_EnvironmentVariableOverride{
+						Name:        name,
+						WorkerValue: workerValue,
+						ActionValue: actionValue,
+					})
+			}
+		}
+	}
+	if len(hermeticityReport.EnvironmentVariableOverrides) > 0 {
+		if hermeticityReportAny, err := anypb.New(&hermeticityReport); err == nil {
+			response.Result.ExecutionMetadata.AuxiliaryMetadata = append(response.Result.ExecutionMetadata.AuxiliaryMetadata, hermeticityReportAny)
+		} else {
+			attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_INFRASTRUCTURE, util.StatusWrap(err, "Failed to marshal hermeticity report"))
+		}
+	}
+
+	// Inject environment variables whose values are obtained from
+	// a secrets provider. These are only passed on to the runner,
+	// so that they don't end up being exposed through the debugger
+	// if the action is paused. Errors should not contain the values
+	// of secrets.
+	runEnvironmentVariables := environmentVariables
+	if secretEnvironmentVariables := be.secretEnvironmentVariables; secretEnvironmentVariables != nil {
+		runEnvironmentVariables = make(map[string]string, len(environmentVariables)+len(secretEnvironmentVariables.Variables))
+		for name, value := range environmentVariables {
+			runEnvironmentVariables[name] = value
+		}
+		instanceName := digestFunction.GetInstanceName()
+		for _, secretEnvironmentVariable := range secretEnvironmentVariables.Variables {
+			if !instanceNameHasPrefix(instanceName, secretEnvironmentVariable.InstanceNamePrefix) {
+				continue
+			}
+			value, err := secretEnvironmentVariables.Provider.GetSecret(ctx, secretEnvironmentVariable.SecretName)
+			if err != nil {
+				attachClassifiedErrorToExecuteResponse(
+					response,
+					errorclassification.Domain_SANDBOX_SETUP,
+					util.StatusWrapf(err, "Failed to obtain value of environment variable %#v", secretEnvironmentVariable.Name))
+				return response
+			}
+			runEnvironmentVariables[secretEnvironmentVariable.Name] = value
+		}
+	}
+
+	// Invoke the command. Actions that signal that they are alive
+	// may run up to the hard maximum, for as long as they keep
+	// creating the keepalive file.
+	runTimeout := executionTimeout
+	if keepaliveInterval > 0 && runTimeout < be.actionKeepalive.MaximumExecutionTimeout {
+		runTimeout = be.actionKeepalive.MaximumExecutionTimeout
+	}
+	ctxWithTimeout, cancelTimeout := be.clock.NewContextWithTimeout(ctxWithIOError, runTimeout)
+	ctxWithKeepalive, cancelKeepalive := context.WithCancel(ctxWithTimeout)
+	var keepalive *keepaliveMonitor
+	if keepaliveInterval > 0 && runTimeout > executionTimeout {
+		keepalive = newKeepaliveMonitor(ctxWithKeepalive, be.clock, inputRootDirectory, executionTimeout, keepaliveInterval, cancelKeepalive)
+	}
+	runResponse, runErr := be.runner.Run(ctxWithKeepalive, &runner_pb.RunRequest{
 		Arguments:            command.Arguments,
-		EnvironmentVariables: environmentVariables,
+		EnvironmentVariables: runEnvironmentVariables,
 		WorkingDirectory:     command.WorkingDirectory,
 		StdoutPath:           buildDirectoryPath.Append(stdoutComponent).String(),
 		StderrPath:           buildDirectoryPath.Append(stderrComponent).String(),
 		InputRootDirectory:   buildDirectoryPath.Append(inputRootDirectoryComponent).String(),
 		TemporaryDirectory:   buildDirectoryPath.Append(temporaryDirectoryComponent).String(),
 		ServerLogsDirectory:  buildDirectoryPath.Append(serverLogsDirectoryComponent).String(),
+		SizeClass:            request.SizeClass,
+		Priority:             request.Priority,
+		Platform:             action.Platform,
 	})
+	cancelKeepalive()
+	if keepalive != nil && keepalive.wait() {
+		runErr = status.Errorf(codes.DeadlineExceeded, "Command did not create the keepalive file within %s after exceeding its execution timeout of %s", keepaliveInterval, executionTimeout)
+	}
 	cancelTimeout()
 	<-ctxWithTimeout.Done()
 
@@ -285,15 +604,39 @@ func (be *localBuildExecutor) Execute(ctx context.Context, filePool re_filesyste
 	// related to it to the response first. These errors should be
 	// preferred over the cancelation errors that are a result of it.
 	if err := ioErrorCapturer.GetError(); err != nil {
-		attachErrorToExecuteResponse(response, util.StatusWrap(err, "I/O error while running command"))
+		attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_INFRASTRUCTURE, util.StatusWrap(err, "I/O error while running command"))
 	}
 
 	// Attach the exit code or execution error.
 	if runErr == nil {
 		response.Result.ExitCode = runResponse.ExitCode
 		response.Result.ExecutionMetadata.AuxiliaryMetadata = append(response.Result.ExecutionMetadata.AuxiliaryMetadata, runResponse.ResourceUsage...)
+
+		// If the command failed after attempting to spawn
+		// more processes than permitted, it most likely
+		// contains a fork bomb. Report this explicitly, as the
+		// error messages printed by the command itself tend to
+		// be uninformative.
+		if runResponse.ExitCode != 0 {
+			if count := getPIDsLimitExceededCount(runResponse.ResourceUsage); count > 0 {
+				attachClassifiedErrorToExecuteResponse(
+					response,
+					errorclassification.Domain_RESOURCE_LIMIT,
+					status.Errorf(codes.ResourceExhausted, "Command attempted to exceed the maximum number of processes %d times", count))
+			}
+		}
 	} else {
-		attachErrorToExecuteResponse(response, util.StatusWrap(runErr, "Failed to run command"))
+		attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_EXECUTION, util.StatusWrap(runErr, "Failed to run command"))
+	}
+
+	// Attach resource usage statistics gathered by the build
+	// directory, such as the amount of disk space it consumed.
+	if resourceUsageReporter, ok := buildDirectory.(ResourceUsageReportingBuildDirectory); ok {
+		if resourceUsage, err := resourceUsageReporter.GetResourceUsage(); err != nil {
+			attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_INFRASTRUCTURE, util.StatusWrap(err, "Failed to obtain build directory resource usage"))
+		} else if resourceUsage != nil {
+			response.Result.ExecutionMetadata.AuxiliaryMetadata = append(response.Result.ExecutionMetadata.AuxiliaryMetadata, resourceUsage)
+		}
 	}
 
 	// For FUSE-based workers: Attach the amount of time the action
@@ -301,6 +644,15 @@ func (be *localBuildExecutor) Execute(ctx context.Context, filePool re_filesyste
 	if unsuspendedDuration, ok := ctxWithTimeout.Value(re_clock.UnsuspendedDurationKey{}).(time.Duration); ok {
 		response.Result.ExecutionMetadata.VirtualExecutionDuration = durationpb.New(unsuspendedDuration)
 	}
+	if suspendedDuration, ok := ctxWithTimeout.Value(re_clock.SuspendedDurationKey{}).(time.Duration); ok {
+		if executionTimeoutCompensation, err := anypb.New(&resourceusage.ExecutionTimeoutCompensation{
+			SuspendedDuration: durationpb.New(suspendedDuration),
+		}); err == nil {
+			response.Result.ExecutionMetadata.AuxiliaryMetadata = append(response.Result.ExecutionMetadata.AuxiliaryMetadata, executionTimeoutCompensation)
+		} else {
+			attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_INFRASTRUCTURE, util.StatusWrap(err, "Failed to marshal execution timeout compensation"))
+		}
+	}
 
 	executionStateUpdates <- &remoteworker.CurrentState_Executing{
 		ActionDigest: request.ActionDigest,
@@ -312,18 +664,46 @@ func (be *localBuildExecutor) Execute(ctx context.Context, filePool re_filesyste
 	// Upload command output. In the common case, the stdout and
 	// stderr files are empty. If that's the case, don't bother
 	// setting the digest to keep the ActionResult small.
-	if stdoutDigest, err := buildDirectory.UploadFile(ctx, stdoutComponent, digestFunction); err != nil {
-		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to store stdout"))
-	} else if stdoutDigest.GetSizeBytes() > 0 {
-		response.Result.StdoutDigest = stdoutDigest.GetProto()
+	var truncatedCommandOutputs resourceusage.TruncatedCommandOutputs
+	if stdoutDigest, truncatedStdout, err := be.uploadCommandOutput(ctx, buildDirectory, stdoutComponent, digestFunction); err != nil {
+		attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_OUTPUT_UPLOAD, util.StatusWrap(err, "Failed to store stdout"))
+	} else {
+		if stdoutDigest.GetSizeBytes() > 0 {
+			response.Result.StdoutDigest = stdoutDigest.GetProto()
+		}
+		truncatedCommandOutputs.Stdout = truncatedStdout
 	}
-	if stderrDigest, err := buildDirectory.UploadFile(ctx, stderrComponent, digestFunction); err != nil {
-		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to store stderr"))
-	} else if stderrDigest.GetSizeBytes() > 0 {
-		response.Result.StderrDigest = stderrDigest.GetProto()
+	if stderrDigest, truncatedStderr, err := be.uploadCommandOutput(ctx, buildDirectory, stderrComponent, digestFunction); err != nil {
+		attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_OUTPUT_UPLOAD, util.StatusWrap(err, "Failed to store stderr"))
+	} else {
+		if stderrDigest.GetSizeBytes() > 0 {
+			response.Result.StderrDigest = stderrDigest.GetProto()
+		}
+		truncatedCommandOutputs.Stderr = truncatedStderr
 	}
-	if err := outputHierarchy.UploadOutputs(ctx, inputRootDirectory, be.contentAddressableStorage, digestFunction, response.Result, be.forceUploadTreesAndDirectories); err != nil {
-		attachErrorToExecuteResponse(response, err)
+	if truncatedCommandOutputs.Stdout != nil || truncatedCommandOutputs.Stderr != nil {
+		if truncatedCommandOutputsAny, err := anypb.New(&truncatedCommandOutputs); err == nil {
+			response.Result.ExecutionMetadata.AuxiliaryMetadata = append(response.Result.ExecutionMetadata.AuxiliaryMetadata, truncatedCommandOutputsAny)
+		} else {
+			attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_INFRASTRUCTURE, util.StatusWrap(err, "Failed to marshal truncated command outputs"))
+		}
+	}
+	if err := outputHierarchy.UploadOutputs(ctx, inputRootDirectory, be.contentAddressableStorage, digestFunction, filePool, response.Result, be.forceUploadTreesAndDirectories, be.outputPruner, be.outputLimits, be.outputPathValidation); err != nil {
+		attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_OUTPUT_UPLOAD, err)
+	}
+
+	// Check that all outputs were uploaded successfully, so that
+	// clients don't receive results referencing absent blobs.
+	if be.actionResultVerification != nil && response.Status == nil {
+		if err := be.actionResultVerification.verify(ctx, be.contentAddressableStorage, digestFunction, response.Result, be.maximumMessageSizeBytes, func() error {
+			return be.reuploadOutputs(ctx, buildDirectory, inputRootDirectory, outputHierarchy, digestFunction, filePool)
+		}); err != nil {
+			attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_OUTPUT_UPLOAD, err)
+		} else if be.actionResultVerification.shouldCheckMaterialization() {
+			if err := be.actionResultVerification.checkMaterialization(ctx, be.contentAddressableStorage, digestFunction, response.Result, inputRootDirectory, command.WorkingDirectory, be.maximumMessageSizeBytes); err != nil {
+				attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_OUTPUT_UPLOAD, util.StatusWrap(err, "Outputs stored in the Content Addressable Storage do not match the build directory"))
+			}
+		}
 	}
 
 	// Recursively traverse the server logs directory and attach any
@@ -334,10 +714,98 @@ func (be *localBuildExecutor) Execute(ctx context.Context, filePool re_filesyste
 		digestFunction:  digestFunction,
 	}
 	serverLogsDirectoryUploader.uploadDirectory(buildDirectory, serverLogsDirectoryComponent, nil)
+	attachServerLogDigests(response)
+
+	// Keep the build directory in place if the action failed and
+	// requested to be paused, so that it can be inspected.
+	if pauseOnFailureDuration > 0 && !executeResponseIsSuccessful(response) {
+		if err := be.failedActionPauser.PauseFailedAction(ctx, &PausableAction{
+			ActionDigest:         actionDigest,
+			InstanceName:         digestFunction.GetInstanceName(),
+			PauseDuration:        pauseOnFailureDuration,
+			BuildDirectory:       buildDirectory,
+			BuildDirectoryPath:   buildDirectoryPath,
+			Runner:               be.runner,
+			EnvironmentVariables: environmentVariables,
+			WorkingDirectory:     command.WorkingDirectory,
+		}); err != nil {
+			attachClassifiedErrorToExecuteResponse(response, errorclassification.Domain_INFRASTRUCTURE, util.StatusWrap(err, "Failed to pause failed action"))
+		}
+	}
 
 	return response
 }
 
+// uploadCommandOutput uploads the standard output or standard error of
+// an action into the Content Addressable Storage, truncating it if
+// configured to do so.
+func (be *localBuildExecutor) uploadCommandOutput(ctx context.Context, buildDirectory BuildDirectory, name path.Component, digestFunction digest.Function) (digest.Digest, *resourceusage.TruncatedCommandOutputs_Output, error) {
+	if be.commandOutputTruncation == nil {
+		d, err := buildDirectory.UploadFile(ctx, name, digestFunction)
+		return d, nil, err
+	}
+	return be.commandOutputTruncation.uploadOutput(ctx, be.contentAddressableStorage, buildDirectory, name, digestFunction)
+}
+
+// reuploadOutputs uploads the standard output, standard error and
+// outputs of an action into the Content Addressable Storage once more.
+// As the contents of the build directory are unchanged, the digests of
+// the outputs are identical to the ones stored in the ActionResult.
+func (be *localBuildExecutor) reuploadOutputs(ctx context.Context, buildDirectory, inputRootDirectory BuildDirectory, outputHierarchy *OutputHierarchy, digestFunction digest.Function, filePool re_filesystem.FilePool) error {
+	if _, _, err := be.uploadCommandOutput(ctx, buildDirectory, stdoutComponent, digestFunction); err != nil {
+		return util.StatusWrap(err, "Failed to store stdout")
+	}
+	if _, _, err := be.uploadCommandOutput(ctx, buildDirectory, stderrComponent, digestFunction); err != nil {
+		return util.StatusWrap(err, "Failed to store stderr")
+	}
+	return outputHierarchy.UploadOutputs(ctx, inputRootDirectory, be.contentAddressableStorage, digestFunction, filePool, &remoteexecution.ActionResult{}, be.forceUploadTreesAndDirectories, be.outputPruner, be.outputLimits, be.outputPathValidation)
+}
+
+// getPIDsLimitExceededCount returns the number of times a command
+// attempted to exceed the maximum number of processes, as reported by
+// the runner through PIDsResourceUsage messages.
+func getPIDsLimitExceededCount(resourceUsage []*anypb.Any) int64 {
+	var pidsResourceUsage resourceusage.PIDsResourceUsage
+	for _, message := range resourceUsage {
+		if message.MessageIs(&pidsResourceUsage) && message.UnmarshalTo(&pidsResourceUsage) == nil {
+			return pidsResourceUsage.PidsLimitExceededCount
+		}
+	}
+	return 0
+}
+
+// attachServerLogDigests sets the digests of core dumps and traces
+// that were captured by the runner, as reported through
+// CoreDumpsResourceUsage and TraceResourceUsage messages. These are
+// stored as server logs, meaning their digests are only known after
+// the server logs directory has been uploaded.
+func attachServerLogDigests(response *remoteexecution.ExecuteResponse) {
+	auxiliaryMetadata := response.Result.ExecutionMetadata.AuxiliaryMetadata
+	for i, message := range auxiliaryMetadata {
+		var updated proto.Message
+		var coreDumps resourceusage.CoreDumpsResourceUsage
+		var trace resourceusage.TraceResourceUsage
+		if message.MessageIs(&coreDumps) && message.UnmarshalTo(&coreDumps) == nil {
+			for _, coreDump := range coreDumps.CoreDumps {
+				if logFile, ok := response.ServerLogs[coreDump.ServerLogName]; ok {
+					coreDump.CompressedDigest = logFile.Digest
+				}
+			}
+			updated = &coreDumps
+		} else if message.MessageIs(&trace) && message.UnmarshalTo(&trace) == nil {
+			if logFile, ok := response.ServerLogs[trace.ServerLogName]; ok {
+				trace.Digest = logFile.Digest
+			}
+			updated = &trace
+		} else {
+			continue
+		}
+		if updatedAny, err := anypb.New(updated); err == nil {
+			auxiliaryMetadata[i] = updatedAny
+		}
+	}
+}
+
 type serverLogsDirectoryUploader struct {
 	context         context.Context
 	executeResponse *remoteexecution.ExecuteResponse
@@ -347,14 +815,14 @@ type serverLogsDirectoryUploader struct {
 func (u *serverLogsDirectoryUploader) uploadDirectory(parentDirectory UploadableDirectory, dName path.Component, dPath *path.Trace) {
 	d, err := parentDirectory.EnterUploadableDirectory(dName)
 	if err != nil {
-		attachErrorToExecuteResponse(u.executeResponse, util.StatusWrapf(err, "Failed to enter server logs directory %#v", dPath.String()))
+		attachClassifiedErrorToExecuteResponse(u.executeResponse, errorclassification.Domain_OUTPUT_UPLOAD, util.StatusWrapf(err, "Failed to enter server logs directory %#v", dPath.String()))
 		return
 	}
 	defer d.Close()
 
 	files, err := d.ReadDir()
 	if err != nil {
-		attachErrorToExecuteResponse(u.executeResponse, util.StatusWrapf(err, "Failed to read server logs directory %#v", dPath.String()))
+		attachClassifiedErrorToExecuteResponse(u.executeResponse, errorclassification.Domain_OUTPUT_UPLOAD, util.StatusWrapf(err, "Failed to read server logs directory %#v", dPath.String()))
 		return
 	}
 
@@ -368,7 +836,7 @@ func (u *serverLogsDirectoryUploader) uploadDirectory(parentDirectory Uploadable
 					Digest: childDigest.GetProto(),
 				}
 			} else {
-				attachErrorToExecuteResponse(u.executeResponse, util.StatusWrapf(err, "Failed to store server log %#v", childPath.String()))
+				attachClassifiedErrorToExecuteResponse(u.executeResponse, errorclassification.Domain_OUTPUT_UPLOAD, util.StatusWrapf(err, "Failed to store server log %#v", childPath.String()))
 			}
 		case filesystem.FileTypeDirectory:
 			u.uploadDirectory(d, childName, childPath)
@@ -3,6 +3,8 @@ package builder_test
 import (
 	"context"
 	"os"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -10,9 +12,14 @@ import (
 	"github.com/buildbarn/bb-remote-execution/internal/mock"
 	"github.com/buildbarn/bb-remote-execution/pkg/builder"
 	re_clock "github.com/buildbarn/bb-remote-execution/pkg/clock"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
 	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/errorclassification"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/hermeticity"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
 	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/vcsmetadata"
 	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/filesystem"
@@ -30,6 +37,16 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+// newClassifiedStatus creates a Status that contains an
+// ErrorClassification, as generated by LocalBuildExecutor.
+func newClassifiedStatus(t *testing.T, domain errorclassification.Domain, code codes.Code, message string) *status.Status {
+	s, err := status.New(code, message).WithDetails(&errorclassification.ErrorClassification{
+		Domain: domain,
+	})
+	require.NoError(t, err)
+	return s
+}
+
 func TestLocalBuildExecutorInvalidActionDigest(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
@@ -37,7 +54,7 @@ func TestLocalBuildExecutorInvalidActionDigest(t *testing.T) {
 	buildDirectoryCreator := mock.NewMockBuildDirectoryCreator(ctrl)
 	runner := mock.NewMockRunnerClient(ctrl)
 	clock := mock.NewMockClock(ctrl)
-	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{} /* forceUploadTreesAndDirectories = */, false)
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	filePool := mock.NewMockFilePool(ctrl)
 	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
@@ -65,7 +82,7 @@ func TestLocalBuildExecutorInvalidActionDigest(t *testing.T) {
 		Result: &remoteexecution.ActionResult{
 			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
 		},
-		Status: status.New(codes.InvalidArgument, "Failed to extract digest for action: Hash has length 34, while 64 characters were expected").Proto(),
+		Status: newClassifiedStatus(t, errorclassification.Domain_INPUT_FETCH, codes.InvalidArgument, "Failed to extract digest for action: Hash has length 34, while 64 characters were expected").Proto(),
 	}, executeResponse)
 }
 
@@ -76,7 +93,7 @@ func TestLocalBuildExecutorMissingAction(t *testing.T) {
 	buildDirectoryCreator := mock.NewMockBuildDirectoryCreator(ctrl)
 	runner := mock.NewMockRunnerClient(ctrl)
 	clock := mock.NewMockClock(ctrl)
-	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{} /* forceUploadTreesAndDirectories = */, false)
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	filePool := mock.NewMockFilePool(ctrl)
 	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
@@ -97,7 +114,7 @@ func TestLocalBuildExecutorMissingAction(t *testing.T) {
 		Result: &remoteexecution.ActionResult{
 			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
 		},
-		Status: status.New(codes.InvalidArgument, "Request does not contain an action").Proto(),
+		Status: newClassifiedStatus(t, errorclassification.Domain_INPUT_FETCH, codes.InvalidArgument, "Request does not contain an action").Proto(),
 	}, executeResponse)
 }
 
@@ -111,7 +128,7 @@ func TestLocalBuildExecutorBuildDirectoryCreatorFailedFailed(t *testing.T) {
 		Return(nil, nil, status.Error(codes.InvalidArgument, "Platform requirements not provided"))
 	runner := mock.NewMockRunnerClient(ctrl)
 	clock := mock.NewMockClock(ctrl)
-	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{} /* forceUploadTreesAndDirectories = */, false)
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	filePool := mock.NewMockFilePool(ctrl)
 	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
@@ -139,7 +156,7 @@ func TestLocalBuildExecutorBuildDirectoryCreatorFailedFailed(t *testing.T) {
 		Result: &remoteexecution.ActionResult{
 			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
 		},
-		Status: status.New(codes.InvalidArgument, "Failed to acquire build environment: Platform requirements not provided").Proto(),
+		Status: newClassifiedStatus(t, errorclassification.Domain_SANDBOX_SETUP, codes.InvalidArgument, "Failed to acquire build environment: Platform requirements not provided").Proto(),
 	}, executeResponse)
 }
 
@@ -154,7 +171,7 @@ func TestLocalBuildExecutorInputRootPopulationFailed(t *testing.T) {
 		Return(buildDirectory, nil, nil)
 	filePool := mock.NewMockFilePool(ctrl)
 	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
-	buildDirectory.EXPECT().InstallHooks(filePool, gomock.Any())
+	buildDirectory.EXPECT().InstallHooks(gomock.Any(), gomock.Any(), filePool, gomock.Any())
 	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("root"), os.FileMode(0o777))
 	inputRootDirectory := mock.NewMockBuildDirectory(ctrl)
 	buildDirectory.EXPECT().EnterBuildDirectory(path.MustNewComponent("root")).Return(inputRootDirectory, nil)
@@ -168,7 +185,7 @@ func TestLocalBuildExecutorInputRootPopulationFailed(t *testing.T) {
 	buildDirectory.EXPECT().Close()
 	runner := mock.NewMockRunnerClient(ctrl)
 	clock := mock.NewMockClock(ctrl)
-	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{} /* forceUploadTreesAndDirectories = */, false)
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	metadata := make(chan *remoteworker.CurrentState_Executing, 10)
 	executeResponse := localBuildExecutor.Execute(
@@ -194,7 +211,7 @@ func TestLocalBuildExecutorInputRootPopulationFailed(t *testing.T) {
 		Result: &remoteexecution.ActionResult{
 			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
 		},
-		Status: status.New(codes.FailedPrecondition, "Some input files could not be found").Proto(),
+		Status: newClassifiedStatus(t, errorclassification.Domain_INPUT_FETCH, codes.FailedPrecondition, "Some input files could not be found").Proto(),
 	}, executeResponse)
 }
 
@@ -219,7 +236,7 @@ func TestLocalBuildExecutorOutputDirectoryCreationFailure(t *testing.T) {
 		Return(buildDirectory, nil, nil)
 	filePool := mock.NewMockFilePool(ctrl)
 	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
-	buildDirectory.EXPECT().InstallHooks(filePool, gomock.Any())
+	buildDirectory.EXPECT().InstallHooks(gomock.Any(), gomock.Any(), filePool, gomock.Any())
 	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("root"), os.FileMode(0o777))
 	inputRootDirectory := mock.NewMockBuildDirectory(ctrl)
 	buildDirectory.EXPECT().EnterBuildDirectory(path.MustNewComponent("root")).Return(inputRootDirectory, nil)
@@ -234,7 +251,7 @@ func TestLocalBuildExecutorOutputDirectoryCreationFailure(t *testing.T) {
 	buildDirectory.EXPECT().Close()
 	runner := mock.NewMockRunnerClient(ctrl)
 	clock := mock.NewMockClock(ctrl)
-	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{} /* forceUploadTreesAndDirectories = */, false)
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	metadata := make(chan *remoteworker.CurrentState_Executing, 10)
 	executeResponse := localBuildExecutor.Execute(
@@ -264,7 +281,7 @@ func TestLocalBuildExecutorOutputDirectoryCreationFailure(t *testing.T) {
 		Result: &remoteexecution.ActionResult{
 			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
 		},
-		Status: status.New(codes.Internal, "Failed to create output parent directory \"foo\": Out of disk space").Proto(),
+		Status: newClassifiedStatus(t, errorclassification.Domain_SANDBOX_SETUP, codes.Internal, "Failed to create output parent directory \"foo\": Out of disk space").Proto(),
 	}, executeResponse)
 }
 
@@ -279,7 +296,7 @@ func TestLocalBuildExecutorMissingCommand(t *testing.T) {
 		Return(buildDirectory, nil, nil)
 	filePool := mock.NewMockFilePool(ctrl)
 	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
-	buildDirectory.EXPECT().InstallHooks(filePool, gomock.Any())
+	buildDirectory.EXPECT().InstallHooks(gomock.Any(), gomock.Any(), filePool, gomock.Any())
 	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("root"), os.FileMode(0o777))
 	inputRootDirectory := mock.NewMockBuildDirectory(ctrl)
 	buildDirectory.EXPECT().EnterBuildDirectory(path.MustNewComponent("root")).Return(inputRootDirectory, nil)
@@ -293,7 +310,7 @@ func TestLocalBuildExecutorMissingCommand(t *testing.T) {
 	buildDirectory.EXPECT().Close()
 	runner := mock.NewMockRunnerClient(ctrl)
 	clock := mock.NewMockClock(ctrl)
-	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{} /* forceUploadTreesAndDirectories = */, false)
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	metadata := make(chan *remoteworker.CurrentState_Executing, 10)
 	executeResponse := localBuildExecutor.Execute(
@@ -319,7 +336,7 @@ func TestLocalBuildExecutorMissingCommand(t *testing.T) {
 		Result: &remoteexecution.ActionResult{
 			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
 		},
-		Status: status.New(codes.InvalidArgument, "Failed to extract digest for command: No digest provided").Proto(),
+		Status: newClassifiedStatus(t, errorclassification.Domain_INPUT_FETCH, codes.InvalidArgument, "Failed to extract digest for command: No digest provided").Proto(),
 	}, executeResponse)
 }
 
@@ -362,8 +379,9 @@ func TestLocalBuildExecutorOutputSymlinkReadingFailure(t *testing.T) {
 	buildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest).
 		Return(buildDirectory, nil, nil)
 	filePool := mock.NewMockFilePool(ctrl)
+	filePool.EXPECT().NewFile().DoAndReturn(re_filesystem.InMemoryFilePool.NewFile).Times(2)
 	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
-	buildDirectory.EXPECT().InstallHooks(filePool, gomock.Any())
+	buildDirectory.EXPECT().InstallHooks(gomock.Any(), gomock.Any(), filePool, gomock.Any())
 	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("root"), os.FileMode(0o777))
 	inputRootDirectory := mock.NewMockBuildDirectory(ctrl)
 	buildDirectory.EXPECT().EnterBuildDirectory(path.MustNewComponent("root")).Return(inputRootDirectory, nil)
@@ -406,7 +424,7 @@ func TestLocalBuildExecutorOutputSymlinkReadingFailure(t *testing.T) {
 	clock.EXPECT().NewContextWithTimeout(gomock.Any(), time.Hour).DoAndReturn(func(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
 		return context.WithCancel(parent)
 	})
-	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{} /* forceUploadTreesAndDirectories = */, false)
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	metadata := make(chan *remoteworker.CurrentState_Executing, 10)
 	executeResponse := localBuildExecutor.Execute(
@@ -454,7 +472,123 @@ func TestLocalBuildExecutorOutputSymlinkReadingFailure(t *testing.T) {
 			},
 			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
 		},
-		Status: status.New(codes.Internal, "Failed to read output symlink \"foo/bar\": Cosmic rays caused interference").Proto(),
+		Status: newClassifiedStatus(t, errorclassification.Domain_OUTPUT_UPLOAD, codes.Internal, "Failed to read output symlink \"foo/bar\": Cosmic rays caused interference").Proto(),
+	}, executeResponse)
+}
+
+func TestLocalBuildExecutorProcessLimitExceeded(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	contentAddressableStorage.EXPECT().Get(
+		gomock.Any(),
+		digest.MustNewDigest("freebsd", remoteexecution.DigestFunction_SHA256, "6666666666666666666666666666666666666666666666666666666666666666", 234),
+	).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Command{
+		Arguments: []string{"sh", "-c", ":(){ :|:& };:"},
+		EnvironmentVariables: []*remoteexecution.Command_EnvironmentVariable{
+			{Name: "PATH", Value: "/bin:/usr/bin"},
+		},
+	}, buffer.UserProvided))
+	buildDirectory := mock.NewMockBuildDirectory(ctrl)
+	buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("stdout"), gomock.Any()).Return(
+		digest.MustNewDigest("freebsd", remoteexecution.DigestFunction_SHA256, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", 0),
+		nil)
+	buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("stderr"), gomock.Any()).Return(
+		digest.MustNewDigest("freebsd", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000006", 678),
+		nil)
+
+	buildDirectoryCreator := mock.NewMockBuildDirectoryCreator(ctrl)
+	actionDigest := digest.MustNewDigest("freebsd", remoteexecution.DigestFunction_SHA256, "5555555555555555555555555555555555555555555555555555555555555555", 7)
+	buildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest).
+		Return(buildDirectory, nil, nil)
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	buildDirectory.EXPECT().InstallHooks(gomock.Any(), gomock.Any(), filePool, gomock.Any())
+	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("root"), os.FileMode(0o777))
+	inputRootDirectory := mock.NewMockBuildDirectory(ctrl)
+	buildDirectory.EXPECT().EnterBuildDirectory(path.MustNewComponent("root")).Return(inputRootDirectory, nil)
+	inputRootDirectory.EXPECT().MergeDirectoryContents(
+		ctx,
+		gomock.Any(),
+		digest.MustNewDigest("freebsd", remoteexecution.DigestFunction_SHA256, "7777777777777777777777777777777777777777777777777777777777777777", 42),
+		monitor,
+	).Return(nil)
+	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("tmp"), os.FileMode(0o777))
+	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("server_logs"), os.FileMode(0o777))
+
+	// The size class of the worker should be forwarded to the
+	// runner, so that it can apply the right process limit. As the
+	// command failed after attempting to exceed this limit, the
+	// failure should be reported explicitly.
+	pidsResourceUsage, err := anypb.New(&resourceusage.PIDsResourceUsage{
+		PidsPeak:               1024,
+		PidsLimitExceededCount: 12345,
+	})
+	require.NoError(t, err)
+	runner := mock.NewMockRunnerClient(ctrl)
+	runner.EXPECT().Run(gomock.Any(), &runner_pb.RunRequest{
+		Arguments:            []string{"sh", "-c", ":(){ :|:& };:"},
+		EnvironmentVariables: map[string]string{"PATH": "/bin:/usr/bin"},
+		WorkingDirectory:     "",
+		StdoutPath:           "stdout",
+		StderrPath:           "stderr",
+		InputRootDirectory:   "root",
+		TemporaryDirectory:   "tmp",
+		ServerLogsDirectory:  "server_logs",
+		SizeClass:            8,
+	}).Return(&runner_pb.RunResponse{
+		ExitCode:      1,
+		ResourceUsage: []*anypb.Any{pidsResourceUsage},
+	}, nil)
+	inputRootDirectory.EXPECT().Close()
+	serverLogsDirectory := mock.NewMockUploadableDirectory(ctrl)
+	buildDirectory.EXPECT().EnterUploadableDirectory(path.MustNewComponent("server_logs")).Return(serverLogsDirectory, nil)
+	serverLogsDirectory.EXPECT().ReadDir()
+	serverLogsDirectory.EXPECT().Close()
+	buildDirectory.EXPECT().Close()
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().NewContextWithTimeout(gomock.Any(), time.Hour).DoAndReturn(func(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+		return context.WithCancel(parent)
+	})
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	metadata := make(chan *remoteworker.CurrentState_Executing, 10)
+	executeResponse := localBuildExecutor.Execute(
+		ctx,
+		filePool,
+		monitor,
+		digest.MustNewFunction("freebsd", remoteexecution.DigestFunction_SHA256),
+		&remoteworker.DesiredState_Executing{
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "5555555555555555555555555555555555555555555555555555555555555555",
+				SizeBytes: 7,
+			},
+			Action: &remoteexecution.Action{
+				CommandDigest: &remoteexecution.Digest{
+					Hash:      "6666666666666666666666666666666666666666666666666666666666666666",
+					SizeBytes: 234,
+				},
+				InputRootDigest: &remoteexecution.Digest{
+					Hash:      "7777777777777777777777777777777777777777777777777777777777777777",
+					SizeBytes: 42,
+				},
+				Timeout: &durationpb.Duration{Seconds: 3600},
+			},
+			SizeClass: 8,
+		},
+		metadata)
+	testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			ExitCode: 1,
+			StderrDigest: &remoteexecution.Digest{
+				Hash:      "0000000000000000000000000000000000000000000000000000000000000006",
+				SizeBytes: 678,
+			},
+			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+				AuxiliaryMetadata: []*anypb.Any{pidsResourceUsage},
+			},
+		},
+		Status: newClassifiedStatus(t, errorclassification.Domain_RESOURCE_LIMIT, codes.ResourceExhausted, "Command attempted to exceed the maximum number of processes 12345 times").Proto(),
 	}, executeResponse)
 }
 
@@ -524,6 +658,7 @@ func TestLocalBuildExecutorSuccess(t *testing.T) {
 			{Name: "BAZEL_DO_NOT_DETECT_CPP_TOOLCHAIN", Value: "1"},
 			{Name: "PATH", Value: "/bin:/usr/bin"},
 			{Name: "PWD", Value: "/proc/self/cwd"},
+			{Name: "TZ", Value: "Europe/Amsterdam"},
 		},
 		OutputPaths: []string{
 			"bazel-out/k8-fastbuild/bin/_objs/hello/hello.pic.d",
@@ -561,7 +696,7 @@ func TestLocalBuildExecutorSuccess(t *testing.T) {
 		Return(buildDirectory, ((*path.Trace)(nil)).Append(path.MustNewComponent("0000000000000000")), nil)
 	filePool := mock.NewMockFilePool(ctrl)
 	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
-	buildDirectory.EXPECT().InstallHooks(filePool, gomock.Any())
+	buildDirectory.EXPECT().InstallHooks(gomock.Any(), gomock.Any(), filePool, gomock.Any())
 	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("root"), os.FileMode(0o777))
 	buildDirectory.EXPECT().EnterBuildDirectory(path.MustNewComponent("root")).Return(inputRootDirectory, nil)
 	inputRootDirectory.EXPECT().MergeDirectoryContents(
@@ -583,7 +718,7 @@ func TestLocalBuildExecutorSuccess(t *testing.T) {
 	resourceUsage, err := anypb.New(&emptypb.Empty{})
 	require.NoError(t, err)
 	runner := mock.NewMockRunnerClient(ctrl)
-	runner.EXPECT().Run(gomock.Any(), &runner_pb.RunRequest{
+	runner.EXPECT().Run(gomock.Any(), testutil.EqProto(t, &runner_pb.RunRequest{
 		Arguments: []string{
 			"/usr/local/bin/clang",
 			"-MD",
@@ -596,9 +731,14 @@ func TestLocalBuildExecutorSuccess(t *testing.T) {
 		},
 		EnvironmentVariables: map[string]string{
 			"BAZEL_DO_NOT_DETECT_CPP_TOOLCHAIN": "1",
+			"BUILD_SCM_DIRTY":                   "true",
+			"BUILD_SCM_REVISION":                "3f786850e387550fdab836ed7e6dc881de23001b",
+			"LANG":                              "nl_NL.UTF-8",
+			"LC_ALL":                            "C.UTF-8",
 			"PATH":                              "/bin:/usr/bin",
 			"PWD":                               "/proc/self/cwd",
 			"TEST_VAR":                          "123",
+			"TZ":                                "Europe/Amsterdam",
 		},
 		WorkingDirectory:    "",
 		StdoutPath:          "0000000000000000/stdout",
@@ -606,7 +746,12 @@ func TestLocalBuildExecutorSuccess(t *testing.T) {
 		InputRootDirectory:  "0000000000000000/root",
 		TemporaryDirectory:  "0000000000000000/tmp",
 		ServerLogsDirectory: "0000000000000000/server_logs",
-	}).Return(&runner_pb.RunResponse{
+		Platform: &remoteexecution.Platform{
+			Properties: []*remoteexecution.Platform_Property{
+				{Name: "locale", Value: "nl_NL.UTF-8"},
+			},
+		},
+	})).Return(&runner_pb.RunResponse{
 		ExitCode:      0,
 		ResourceUsage: []*anypb.Any{resourceUsage},
 	}, nil)
@@ -618,21 +763,59 @@ func TestLocalBuildExecutorSuccess(t *testing.T) {
 	buildDirectory.EXPECT().Close()
 	clock := mock.NewMockClock(ctrl)
 	clock.EXPECT().NewContextWithTimeout(gomock.Any(), time.Hour).DoAndReturn(func(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
-		return context.WithCancel(context.WithValue(parent, re_clock.UnsuspendedDurationKey{}, 5*time.Second))
+		return context.WithCancel(
+			context.WithValue(
+				context.WithValue(parent, re_clock.UnsuspendedDurationKey{}, 5*time.Second),
+				re_clock.SuspendedDurationKey{},
+				2*time.Second))
 	})
 	inputRootCharacterDevices := map[path.Component]filesystem.DeviceNumber{
 		path.MustNewComponent("null"): filesystem.NewDeviceNumberFromMajorMinor(1, 3),
 	}
 	environmentVars := map[string]string{
+		"LANG":     "C.UTF-8",
+		"LC_ALL":   "C.UTF-8",
 		"TEST_VAR": "123",
+		"TZ":       "UTC",
 		"PWD":      "dont-overwrite",
 	}
-	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, inputRootCharacterDevices, 10000, environmentVars /* forceUploadTreesAndDirectories = */, false)
+	platformPropertyEnvironmentVars := map[string]string{
+		"locale": "LANG",
+	}
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, inputRootCharacterDevices, 10000, environmentVars, platformPropertyEnvironmentVars, &builder.VCSMetadataEnvironmentVariables{
+		CommitSHA: "BUILD_SCM_REVISION",
+		Dirty:     "BUILD_SCM_DIRTY",
+	}, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	// The action overrides the values of LANG and TZ configured on
+	// the worker. This should be captured in a hermeticity report.
+	hermeticityReport, err := anypb.New(&hermeticity.HermeticityReport{
+		EnvironmentVariableOverrides: []*hermeticity.HermeticityReport_EnvironmentVariableOverride{
+			{Name: "LANG", WorkerValue: "C.UTF-8", ActionValue: "nl_NL.UTF-8"},
+			{Name: "TZ", WorkerValue: "UTC", ActionValue: "Europe/Amsterdam"},
+		},
+	})
+	require.NoError(t, err)
+
+	// The time during which the execution timeout was paused should
+	// be reported.
+	executionTimeoutCompensation, err := anypb.New(&resourceusage.ExecutionTimeoutCompensation{
+		SuspendedDuration: &durationpb.Duration{Seconds: 2},
+	})
+	require.NoError(t, err)
 
 	requestMetadata, err := anypb.New(&remoteexecution.RequestMetadata{
 		ToolInvocationId: "666b72d8-c43e-4998-866c-9312a31fe86d",
 	})
 	require.NoError(t, err)
+
+	// VCS metadata provided by the client should be exposed to the
+	// action through environment variables.
+	vcsMetadata, err := anypb.New(&vcsmetadata.VcsMetadata{
+		CommitSha: "3f786850e387550fdab836ed7e6dc881de23001b",
+		Dirty:     true,
+	})
+	require.NoError(t, err)
 	metadata := make(chan *remoteworker.CurrentState_Executing, 10)
 	executeResponse := localBuildExecutor.Execute(
 		ctx,
@@ -654,8 +837,13 @@ func TestLocalBuildExecutorSuccess(t *testing.T) {
 					SizeBytes: 345,
 				},
 				Timeout: &durationpb.Duration{Seconds: 3600},
+				Platform: &remoteexecution.Platform{
+					Properties: []*remoteexecution.Platform_Property{
+						{Name: "locale", Value: "nl_NL.UTF-8"},
+					},
+				},
 			},
-			AuxiliaryMetadata: []*anypb.Any{requestMetadata},
+			AuxiliaryMetadata: []*anypb.Any{requestMetadata, vcsMetadata},
 		},
 		metadata)
 	testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
@@ -686,7 +874,7 @@ func TestLocalBuildExecutorSuccess(t *testing.T) {
 				SizeBytes: 678,
 			},
 			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
-				AuxiliaryMetadata:        []*anypb.Any{requestMetadata, resourceUsage},
+				AuxiliaryMetadata:        []*anypb.Any{requestMetadata, vcsMetadata, hermeticityReport, resourceUsage, executionTimeoutCompensation},
 				VirtualExecutionDuration: &durationpb.Duration{Seconds: 5},
 			},
 		},
@@ -700,7 +888,7 @@ func TestLocalBuildExecutorCachingInvalidTimeout(t *testing.T) {
 	buildDirectoryCreator := mock.NewMockBuildDirectoryCreator(ctrl)
 	runner := mock.NewMockRunnerClient(ctrl)
 	clock := mock.NewMockClock(ctrl)
-	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{} /* forceUploadTreesAndDirectories = */, false)
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	// Execution should fail, as the number of nanoseconds in the
 	// timeout is not within bounds.
@@ -728,7 +916,50 @@ func TestLocalBuildExecutorCachingInvalidTimeout(t *testing.T) {
 			},
 		},
 		metadata)
-	testutil.RequirePrefixedStatus(t, status.Error(codes.InvalidArgument, "Invalid execution timeout: "), status.ErrorProto(executeResponse.Status))
+	testutil.RequirePrefixedStatus(t, newClassifiedStatus(t, errorclassification.Domain_INPUT_FETCH, codes.InvalidArgument, "Invalid execution timeout: ").Err(), status.ErrorProto(executeResponse.Status))
+}
+
+func TestLocalBuildExecutorInvalidPauseOnFailure(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	buildDirectoryCreator := mock.NewMockBuildDirectoryCreator(ctrl)
+	runner := mock.NewMockRunnerClient(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	failedActionPauser := builder.NewPausedActionRegistry(clock, mock.NewMockUUIDGenerator(ctrl).Call, time.Hour, mock.NewMockAuthorizer(ctrl))
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{}, nil, nil, failedActionPauser /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	// Malformed values of the "pause-on-failure" platform property
+	// should be rejected before the action is executed, and be
+	// reported as an error of the action instead of the worker.
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	metadata := make(chan *remoteworker.CurrentState_Executing, 10)
+	executeResponse := localBuildExecutor.Execute(
+		ctx,
+		filePool,
+		monitor,
+		digest.MustNewFunction("ubuntu1804", remoteexecution.DigestFunction_SHA256),
+		&remoteworker.DesiredState_Executing{
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "0000000000000000000000000000000000000000000000000000000000000001",
+				SizeBytes: 123,
+			},
+			Action: &remoteexecution.Action{
+				InputRootDigest: &remoteexecution.Digest{
+					Hash:      "0000000000000000000000000000000000000000000000000000000000000003",
+					SizeBytes: 345,
+				},
+				Timeout: &durationpb.Duration{Seconds: 3600},
+				Platform: &remoteexecution.Platform{
+					Properties: []*remoteexecution.Platform_Property{
+						{Name: "pause-on-failure", Value: "forever"},
+					},
+				},
+			},
+		},
+		metadata)
+	testutil.RequireEqualStatus(t, newClassifiedStatus(t, errorclassification.Domain_INPUT_FETCH, codes.InvalidArgument, "Invalid value for platform property \"pause-on-failure\": time: invalid duration \"forever\"").Err(), status.ErrorProto(executeResponse.Status))
 }
 
 func TestLocalBuildExecutorInputRootIOFailureDuringExecution(t *testing.T) {
@@ -757,7 +988,7 @@ func TestLocalBuildExecutorInputRootIOFailureDuringExecution(t *testing.T) {
 		Return(buildDirectory, nil, nil)
 	filePool := mock.NewMockFilePool(ctrl)
 	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
-	buildDirectory.EXPECT().InstallHooks(filePool, gomock.Any())
+	buildDirectory.EXPECT().InstallHooks(gomock.Any(), gomock.Any(), filePool, gomock.Any())
 
 	// Input root creation. Preserve the error logger that is
 	// provided, so that an I/O error can be triggered during the
@@ -806,7 +1037,7 @@ func TestLocalBuildExecutorInputRootIOFailureDuringExecution(t *testing.T) {
 	clock.EXPECT().NewContextWithTimeout(gomock.Any(), 15*time.Minute).DoAndReturn(func(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
 		return context.WithCancel(parent)
 	})
-	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{} /* forceUploadTreesAndDirectories = */, false)
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	metadata := make(chan *remoteworker.CurrentState_Executing, 10)
 	executeResponse := localBuildExecutor.Execute(
@@ -846,7 +1077,7 @@ func TestLocalBuildExecutorInputRootIOFailureDuringExecution(t *testing.T) {
 			},
 			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
 		},
-		Status: status.New(codes.FailedPrecondition, "I/O error while running command: Blob not found").Proto(),
+		Status: newClassifiedStatus(t, errorclassification.Domain_INFRASTRUCTURE, codes.FailedPrecondition, "I/O error while running command: Blob not found").Proto(),
 	}, executeResponse)
 }
 
@@ -876,7 +1107,7 @@ func TestLocalBuildExecutorTimeoutDuringExecution(t *testing.T) {
 		Return(buildDirectory, nil, nil)
 	filePool := mock.NewMockFilePool(ctrl)
 	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
-	buildDirectory.EXPECT().InstallHooks(filePool, gomock.Any())
+	buildDirectory.EXPECT().InstallHooks(gomock.Any(), gomock.Any(), filePool, gomock.Any())
 
 	// Input root creation.
 	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("root"), os.FileMode(0o777))
@@ -925,7 +1156,7 @@ func TestLocalBuildExecutorTimeoutDuringExecution(t *testing.T) {
 	clock.EXPECT().NewContextWithTimeout(gomock.Any(), time.Hour).DoAndReturn(func(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
 		return context.WithTimeout(parent, 0)
 	})
-	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{} /* forceUploadTreesAndDirectories = */, false)
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	metadata := make(chan *remoteworker.CurrentState_Executing, 10)
 	executeResponse := localBuildExecutor.Execute(
@@ -971,7 +1202,147 @@ func TestLocalBuildExecutorTimeoutDuringExecution(t *testing.T) {
 				},
 			},
 		},
-		Status: status.New(codes.DeadlineExceeded, "Failed to run command: context deadline exceeded").Proto(),
+		Status: newClassifiedStatus(t, errorclassification.Domain_EXECUTION, codes.DeadlineExceeded, "Failed to run command: context deadline exceeded").Proto(),
+	}, executeResponse)
+}
+
+func TestLocalBuildExecutorKeepaliveExpired(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	// Build directory.
+	buildDirectory := mock.NewMockBuildDirectory(ctrl)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	contentAddressableStorage.EXPECT().Get(
+		gomock.Any(),
+		digest.MustNewDigest("ubuntu1804", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000002", 234),
+	).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Command{
+		Arguments:        []string{"./hil_test"},
+		WorkingDirectory: "tests/hil",
+	}, buffer.UserProvided))
+	buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("stdout"), gomock.Any()).Return(
+		digest.MustNewDigest("ubuntu1804", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000005", 567),
+		nil)
+	buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("stderr"), gomock.Any()).Return(
+		digest.MustNewDigest("ubuntu1804", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000006", 678),
+		nil)
+
+	// Build environment.
+	buildDirectoryCreator := mock.NewMockBuildDirectoryCreator(ctrl)
+	actionDigest := digest.MustNewDigest("ubuntu1804", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000001", 123)
+	buildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest).
+		Return(buildDirectory, nil, nil)
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	buildDirectory.EXPECT().InstallHooks(gomock.Any(), gomock.Any(), filePool, gomock.Any())
+
+	// Input root creation.
+	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("root"), os.FileMode(0o777))
+	inputRootDirectory := mock.NewMockBuildDirectory(ctrl)
+	buildDirectory.EXPECT().EnterBuildDirectory(path.MustNewComponent("root")).Return(inputRootDirectory, nil)
+	inputRootDirectory.EXPECT().MergeDirectoryContents(
+		ctx,
+		gomock.Any(),
+		digest.MustNewDigest("ubuntu1804", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000003", 345),
+		monitor,
+	).Return(nil)
+	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("tmp"), os.FileMode(0o777))
+	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("server_logs"), os.FileMode(0o777))
+
+	// As the action requests its execution timeout to be extended,
+	// it should be run with the maximum execution timeout. The
+	// path of the keepalive file should be provided relative to the
+	// working directory.
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().NewContextWithTimeout(gomock.Any(), 4*time.Hour).DoAndReturn(func(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+		return context.WithCancel(parent)
+	})
+	runner := mock.NewMockRunnerClient(ctrl)
+	runner.EXPECT().Run(gomock.Any(), &runner_pb.RunRequest{
+		Arguments: []string{"./hil_test"},
+		EnvironmentVariables: map[string]string{
+			"BUILDBARN_KEEPALIVE_FILE": "../../.bb_keepalive",
+		},
+		WorkingDirectory:    "tests/hil",
+		StdoutPath:          "stdout",
+		StderrPath:          "stderr",
+		InputRootDirectory:  "root",
+		TemporaryDirectory:  "tmp",
+		ServerLogsDirectory: "server_logs",
+		Platform: &remoteexecution.Platform{
+			Properties: []*remoteexecution.Platform_Property{
+				{Name: "keepalive-interval", Value: "30s"},
+			},
+		},
+	}).DoAndReturn(func(ctx context.Context, request *runner_pb.RunRequest, opts ...grpc.CallOption) (*runner_pb.RunResponse, error) {
+		<-ctx.Done()
+		return nil, util.StatusFromContext(ctx)
+	})
+
+	// The action creates the keepalive file during the first two
+	// intervals, meaning it may run beyond its execution timeout of
+	// one minute. It fails to do so during the third interval,
+	// causing it to be terminated.
+	for i, err := range []error{nil, nil, syscall.ENOENT} {
+		timer := mock.NewMockTimer(ctrl)
+		timerChannel := make(chan time.Time, 1)
+		timerChannel <- time.Unix(1000+30*int64(i+1), 0)
+		clock.EXPECT().NewTimer(30*time.Second).Return(timer, timerChannel)
+		inputRootDirectory.EXPECT().Remove(path.MustNewComponent(".bb_keepalive")).Return(err)
+	}
+	inputRootDirectory.EXPECT().Close()
+
+	serverLogsDirectory := mock.NewMockUploadableDirectory(ctrl)
+	buildDirectory.EXPECT().EnterUploadableDirectory(path.MustNewComponent("server_logs")).Return(serverLogsDirectory, nil)
+	serverLogsDirectory.EXPECT().ReadDir()
+	serverLogsDirectory.EXPECT().Close()
+	buildDirectory.EXPECT().Close()
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, &builder.ActionKeepalive{
+		EnvironmentVariable:     "BUILDBARN_KEEPALIVE_FILE",
+		MaximumExecutionTimeout: 4 * time.Hour,
+	}, nil, nil, nil, nil, nil, nil, nil)
+
+	metadata := make(chan *remoteworker.CurrentState_Executing, 10)
+	executeResponse := localBuildExecutor.Execute(
+		ctx,
+		filePool,
+		monitor,
+		digest.MustNewFunction("ubuntu1804", remoteexecution.DigestFunction_SHA256),
+		&remoteworker.DesiredState_Executing{
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "0000000000000000000000000000000000000000000000000000000000000001",
+				SizeBytes: 123,
+			},
+			Action: &remoteexecution.Action{
+				CommandDigest: &remoteexecution.Digest{
+					Hash:      "0000000000000000000000000000000000000000000000000000000000000002",
+					SizeBytes: 234,
+				},
+				InputRootDigest: &remoteexecution.Digest{
+					Hash:      "0000000000000000000000000000000000000000000000000000000000000003",
+					SizeBytes: 345,
+				},
+				Timeout: &durationpb.Duration{Seconds: 60},
+				Platform: &remoteexecution.Platform{
+					Properties: []*remoteexecution.Platform_Property{
+						{Name: "keepalive-interval", Value: "30s"},
+					},
+				},
+			},
+		},
+		metadata)
+	testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			StdoutDigest: &remoteexecution.Digest{
+				Hash:      "0000000000000000000000000000000000000000000000000000000000000005",
+				SizeBytes: 567,
+			},
+			StderrDigest: &remoteexecution.Digest{
+				Hash:      "0000000000000000000000000000000000000000000000000000000000000006",
+				SizeBytes: 678,
+			},
+			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+		},
+		Status: newClassifiedStatus(t, errorclassification.Domain_EXECUTION, codes.DeadlineExceeded, "Failed to run command: Command did not create the keepalive file within 30s after exceeding its execution timeout of 1m0s").Proto(),
 	}, executeResponse)
 }
 
@@ -989,7 +1360,7 @@ func TestLocalBuildExecutorCharacterDeviceNodeCreationFailed(t *testing.T) {
 		Return(buildDirectory, nil, nil)
 	filePool := mock.NewMockFilePool(ctrl)
 	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
-	buildDirectory.EXPECT().InstallHooks(filePool, gomock.Any())
+	buildDirectory.EXPECT().InstallHooks(gomock.Any(), gomock.Any(), filePool, gomock.Any())
 
 	// Input root creation.
 	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("root"), os.FileMode(0o777))
@@ -1017,7 +1388,7 @@ func TestLocalBuildExecutorCharacterDeviceNodeCreationFailed(t *testing.T) {
 	inputRootCharacterDevices := map[path.Component]filesystem.DeviceNumber{
 		path.MustNewComponent("null"): filesystem.NewDeviceNumberFromMajorMinor(1, 3),
 	}
-	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, inputRootCharacterDevices, 10000, map[string]string{} /* forceUploadTreesAndDirectories = */, false)
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, inputRootCharacterDevices, 10000, map[string]string{}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	metadata := make(chan *remoteworker.CurrentState_Executing, 10)
 	executeResponse := localBuildExecutor.Execute(
@@ -1043,6 +1414,586 @@ func TestLocalBuildExecutorCharacterDeviceNodeCreationFailed(t *testing.T) {
 		Result: &remoteexecution.ActionResult{
 			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
 		},
-		Status: status.New(codes.Internal, "Failed to create character device \"null\": Device node creation failed").Proto(),
+		Status: newClassifiedStatus(t, errorclassification.Domain_SANDBOX_SETUP, codes.Internal, "Failed to create character device \"null\": Device node creation failed").Proto(),
 	}, executeResponse)
 }
+
+func TestLocalBuildExecutorSecretEnvironmentVariables(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	buildDirectoryCreator := mock.NewMockBuildDirectoryCreator(ctrl)
+	runner := mock.NewMockRunnerClient(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	secretsProvider := mock.NewMockProvider(ctrl)
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{
+		"PATH": "/bin",
+	}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, &builder.SecretEnvironmentVariables{
+		Provider: secretsProvider,
+		Variables: []builder.SecretEnvironmentVariable{
+			{
+				Name:               "SIGNING_KEY",
+				InstanceNamePrefix: digest.MustNewInstanceName("release"),
+				SecretName:         "release/signing#private_key",
+			},
+			{
+				Name:               "DEBUG_TOKEN",
+				InstanceNamePrefix: digest.MustNewInstanceName("debug"),
+				SecretName:         "debug/token#value",
+			},
+		},
+	}, nil, nil, nil, nil, nil, nil)
+
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	digestFunction := digest.MustNewFunction("release/arm64", remoteexecution.DigestFunction_SHA256)
+	request := &remoteworker.DesiredState_Executing{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "0000000000000000000000000000000000000000000000000000000000000001",
+			SizeBytes: 123,
+		},
+		Action: &remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "0000000000000000000000000000000000000000000000000000000000000002",
+				SizeBytes: 234,
+			},
+			InputRootDigest: &remoteexecution.Digest{
+				Hash:      "0000000000000000000000000000000000000000000000000000000000000003",
+				SizeBytes: 345,
+			},
+			Timeout: &durationpb.Duration{Seconds: 3600},
+		},
+	}
+
+	// Steps that are performed for every execution, up to the point
+	// where secrets are obtained.
+	prepareBuildDirectory := func() (*mock.MockBuildDirectory, *mock.MockBuildDirectory) {
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("release/arm64", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000002", 234),
+		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Command{
+			Arguments: []string{"sign", "artifact.tar"},
+			EnvironmentVariables: []*remoteexecution.Command_EnvironmentVariable{
+				{Name: "SIGNING_KEY", Value: "placeholder"},
+			},
+		}, buffer.UserProvided))
+		buildDirectory := mock.NewMockBuildDirectory(ctrl)
+		actionDigest := digest.MustNewDigest("release/arm64", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000001", 123)
+		buildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest).Return(buildDirectory, nil, nil)
+		buildDirectory.EXPECT().InstallHooks(gomock.Any(), gomock.Any(), filePool, gomock.Any())
+		buildDirectory.EXPECT().Mkdir(path.MustNewComponent("root"), os.FileMode(0o777))
+		inputRootDirectory := mock.NewMockBuildDirectory(ctrl)
+		buildDirectory.EXPECT().EnterBuildDirectory(path.MustNewComponent("root")).Return(inputRootDirectory, nil)
+		inputRootDirectory.EXPECT().MergeDirectoryContents(
+			ctx,
+			gomock.Any(),
+			digest.MustNewDigest("release/arm64", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000003", 345),
+			monitor,
+		).Return(nil)
+		buildDirectory.EXPECT().Mkdir(path.MustNewComponent("tmp"), os.FileMode(0o777))
+		buildDirectory.EXPECT().Mkdir(path.MustNewComponent("server_logs"), os.FileMode(0o777))
+		inputRootDirectory.EXPECT().Close()
+		buildDirectory.EXPECT().Close()
+		return buildDirectory, inputRootDirectory
+	}
+
+	t.Run("ProviderFailure", func(t *testing.T) {
+		prepareBuildDirectory()
+		secretsProvider.EXPECT().GetSecret(ctx, "release/signing#private_key").
+			Return("", status.Error(codes.PermissionDenied, "Permission denied"))
+
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+			Status: newClassifiedStatus(t, errorclassification.Domain_SANDBOX_SETUP, codes.PermissionDenied, "Failed to obtain value of environment variable \"SIGNING_KEY\": Permission denied").Proto(),
+		}, localBuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, make(chan *remoteworker.CurrentState_Executing, 10)))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Only the secret whose instance name prefix matches
+		// should be injected. It should take precedence over
+		// the value provided in the Command message.
+		buildDirectory, _ := prepareBuildDirectory()
+		secretsProvider.EXPECT().GetSecret(ctx, "release/signing#private_key").Return("hunter2", nil)
+		clock.EXPECT().NewContextWithTimeout(gomock.Any(), time.Hour).DoAndReturn(func(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+			return context.WithCancel(parent)
+		})
+		runner.EXPECT().Run(gomock.Any(), testutil.EqProto(t, &runner_pb.RunRequest{
+			Arguments: []string{"sign", "artifact.tar"},
+			EnvironmentVariables: map[string]string{
+				"PATH":        "/bin",
+				"SIGNING_KEY": "hunter2",
+			},
+			StdoutPath:          "stdout",
+			StderrPath:          "stderr",
+			InputRootDirectory:  "root",
+			TemporaryDirectory:  "tmp",
+			ServerLogsDirectory: "server_logs",
+		})).Return(&runner_pb.RunResponse{}, nil)
+		buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("stdout"), gomock.Any()).Return(digest.MustNewDigest("release/arm64", remoteexecution.DigestFunction_SHA256, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", 0), nil)
+		buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("stderr"), gomock.Any()).Return(digest.MustNewDigest("release/arm64", remoteexecution.DigestFunction_SHA256, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", 0), nil)
+		serverLogsDirectory := mock.NewMockUploadableDirectory(ctrl)
+		buildDirectory.EXPECT().EnterUploadableDirectory(path.MustNewComponent("server_logs")).Return(serverLogsDirectory, nil)
+		serverLogsDirectory.EXPECT().ReadDir()
+		serverLogsDirectory.EXPECT().Close()
+
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		}, localBuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, make(chan *remoteworker.CurrentState_Executing, 10)))
+	})
+}
+
+func TestLocalBuildExecutorFakeTime(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	buildDirectoryCreator := mock.NewMockBuildDirectoryCreator(ctrl)
+	runner := mock.NewMockRunnerClient(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{
+		"PATH": "/bin",
+	}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, &builder.FakeTime{
+		PreloadLibraryPath: "/usr/lib/faketime/libfaketime.so.1",
+	}, nil, nil, nil, nil, nil)
+
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+	newRequest := func(fakeTime string) *remoteworker.DesiredState_Executing {
+		return &remoteworker.DesiredState_Executing{
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "0000000000000000000000000000000000000000000000000000000000000001",
+				SizeBytes: 123,
+			},
+			Action: &remoteexecution.Action{
+				CommandDigest: &remoteexecution.Digest{
+					Hash:      "0000000000000000000000000000000000000000000000000000000000000002",
+					SizeBytes: 234,
+				},
+				InputRootDigest: &remoteexecution.Digest{
+					Hash:      "0000000000000000000000000000000000000000000000000000000000000003",
+					SizeBytes: 345,
+				},
+				Timeout: &durationpb.Duration{Seconds: 3600},
+				Platform: &remoteexecution.Platform{
+					Properties: []*remoteexecution.Platform_Property{
+						{Name: "fake-time", Value: fakeTime},
+					},
+				},
+			},
+		}
+	}
+
+	// Steps that are performed for every execution, up to the point
+	// where environment variables are computed.
+	prepareBuildDirectory := func() *mock.MockBuildDirectory {
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000002", 234),
+		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Command{
+			Arguments: []string{"tar", "-cf", "archive.tar", "src"},
+			EnvironmentVariables: []*remoteexecution.Command_EnvironmentVariable{
+				{Name: "LD_PRELOAD", Value: "libsandbox.so"},
+				{Name: "SOURCE_DATE_EPOCH", Value: "1700000000"},
+			},
+		}, buffer.UserProvided))
+		buildDirectory := mock.NewMockBuildDirectory(ctrl)
+		actionDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000001", 123)
+		buildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest).Return(buildDirectory, nil, nil)
+		buildDirectory.EXPECT().InstallHooks(gomock.Any(), gomock.Any(), filePool, gomock.Any())
+		buildDirectory.EXPECT().Mkdir(path.MustNewComponent("root"), os.FileMode(0o777))
+		inputRootDirectory := mock.NewMockBuildDirectory(ctrl)
+		buildDirectory.EXPECT().EnterBuildDirectory(path.MustNewComponent("root")).Return(inputRootDirectory, nil)
+		inputRootDirectory.EXPECT().MergeDirectoryContents(
+			ctx,
+			gomock.Any(),
+			digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000003", 345),
+			monitor,
+		).Return(nil)
+		buildDirectory.EXPECT().Mkdir(path.MustNewComponent("tmp"), os.FileMode(0o777))
+		buildDirectory.EXPECT().Mkdir(path.MustNewComponent("server_logs"), os.FileMode(0o777))
+		inputRootDirectory.EXPECT().Close()
+		buildDirectory.EXPECT().Close()
+		return buildDirectory
+	}
+
+	t.Run("InvalidValue", func(t *testing.T) {
+		prepareBuildDirectory()
+
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+			Status: newClassifiedStatus(t, errorclassification.Domain_INPUT_FETCH, codes.InvalidArgument, "Invalid value for platform property \"fake-time\": strconv.ParseInt: parsing \"yesterday\": invalid syntax").Proto(),
+		}, localBuildExecutor.Execute(ctx, filePool, monitor, digestFunction, newRequest("yesterday"), make(chan *remoteworker.CurrentState_Executing, 10)))
+	})
+
+	t.Run("SourceDateEpoch", func(t *testing.T) {
+		// The clock should start at the time provided through
+		// SOURCE_DATE_EPOCH. libfaketime should be loaded
+		// before any libraries preloaded by the action.
+		buildDirectory := prepareBuildDirectory()
+		clock.EXPECT().NewContextWithTimeout(gomock.Any(), time.Hour).DoAndReturn(func(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+			return context.WithCancel(parent)
+		})
+		runner.EXPECT().Run(gomock.Any(), testutil.EqProto(t, &runner_pb.RunRequest{
+			Arguments: []string{"tar", "-cf", "archive.tar", "src"},
+			EnvironmentVariables: map[string]string{
+				"DONT_FAKE_MONOTONIC": "1",
+				"FAKETIME":            "@1700000000",
+				"FAKETIME_FMT":        "%s",
+				"LD_PRELOAD":          "/usr/lib/faketime/libfaketime.so.1:libsandbox.so",
+				"PATH":                "/bin",
+				"SOURCE_DATE_EPOCH":   "1700000000",
+			},
+			StdoutPath:          "stdout",
+			StderrPath:          "stderr",
+			InputRootDirectory:  "root",
+			TemporaryDirectory:  "tmp",
+			ServerLogsDirectory: "server_logs",
+			Platform: &remoteexecution.Platform{
+				Properties: []*remoteexecution.Platform_Property{
+					{Name: "fake-time", Value: "source-date-epoch"},
+				},
+			},
+		})).Return(&runner_pb.RunResponse{}, nil)
+		buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("stdout"), gomock.Any()).Return(digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", 0), nil)
+		buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("stderr"), gomock.Any()).Return(digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", 0), nil)
+		serverLogsDirectory := mock.NewMockUploadableDirectory(ctrl)
+		buildDirectory.EXPECT().EnterUploadableDirectory(path.MustNewComponent("server_logs")).Return(serverLogsDirectory, nil)
+		serverLogsDirectory.EXPECT().ReadDir()
+		serverLogsDirectory.EXPECT().Close()
+
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		}, localBuildExecutor.Execute(ctx, filePool, monitor, digestFunction, newRequest("source-date-epoch"), make(chan *remoteworker.CurrentState_Executing, 10)))
+	})
+}
+
+func TestLocalBuildExecutorCommandOutputTruncation(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	buildDirectoryCreator := mock.NewMockBuildDirectoryCreator(ctrl)
+	runner := mock.NewMockRunnerClient(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	largeLogsContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, &builder.CommandOutputTruncation{
+		MaximumSizeBytes:                   10,
+		LargeLogsContentAddressableStorage: largeLogsContentAddressableStorage,
+	}, nil, nil, nil, nil)
+
+	contentAddressableStorage.EXPECT().Get(
+		gomock.Any(),
+		digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000002", 234),
+	).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Command{
+		Arguments: []string{"cat", "log.txt"},
+	}, buffer.UserProvided))
+	buildDirectory := mock.NewMockBuildDirectory(ctrl)
+	actionDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000001", 123)
+	buildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest).Return(buildDirectory, nil, nil)
+	filePool := mock.NewMockFilePool(ctrl)
+	buildDirectory.EXPECT().InstallHooks(gomock.Any(), gomock.Any(), filePool, gomock.Any())
+	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("root"), os.FileMode(0o777))
+	inputRootDirectory := mock.NewMockBuildDirectory(ctrl)
+	buildDirectory.EXPECT().EnterBuildDirectory(path.MustNewComponent("root")).Return(inputRootDirectory, nil)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	inputRootDirectory.EXPECT().MergeDirectoryContents(
+		ctx,
+		gomock.Any(),
+		digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000003", 345),
+		monitor,
+	).Return(nil)
+	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("tmp"), os.FileMode(0o777))
+	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("server_logs"), os.FileMode(0o777))
+	clock.EXPECT().NewContextWithTimeout(gomock.Any(), time.Hour).DoAndReturn(func(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+		return context.WithCancel(parent)
+	})
+	runner.EXPECT().Run(gomock.Any(), gomock.Any()).Return(&runner_pb.RunResponse{}, nil)
+
+	// Standard output is small enough to be uploaded as is.
+	stdout := mock.NewMockFileReader(ctrl)
+	buildDirectory.EXPECT().OpenRead(path.MustNewComponent("stdout")).Return(stdout, nil)
+	stdout.EXPECT().ReadAt(gomock.Any(), gomock.Any()).DoAndReturn(strings.NewReader("Hello").ReadAt).AnyTimes()
+	stdout.EXPECT().Close()
+	buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("stdout"), gomock.Any()).Return(digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969", 5), nil)
+
+	// Standard error exceeds the maximum size. Only its head and
+	// tail should be stored in the Content Addressable Storage,
+	// while the full output is stored in the large logs storage.
+	stderr := mock.NewMockFileReader(ctrl)
+	buildDirectory.EXPECT().OpenRead(path.MustNewComponent("stderr")).Return(stderr, nil)
+	stderr.EXPECT().ReadAt(gomock.Any(), gomock.Any()).DoAndReturn(strings.NewReader("0123456789abcdefghij").ReadAt).AnyTimes()
+	stderr.EXPECT().Close()
+	contentAddressableStorage.EXPECT().Put(
+		ctx,
+		digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "7e4c626da377ce5b706892ced32ecadbda4543ed170c647dff5ce5e1c068108c", 42),
+		gomock.Any(),
+	).DoAndReturn(func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+		data, err := b.ToByteSlice(100)
+		require.NoError(t, err)
+		require.Equal(t, "01234\n\n[... 10 bytes truncated ...]\n\nfghij", string(data))
+		return nil
+	})
+	largeLogsContentAddressableStorage.EXPECT().Put(
+		ctx,
+		digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "6bc14bdc4517a7a682c6910de2e2946eb8e1ecd04090728fef6d092a7ceb62c5", 20),
+		gomock.Any(),
+	).DoAndReturn(func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+		data, err := b.ToByteSlice(100)
+		require.NoError(t, err)
+		require.Equal(t, "0123456789abcdefghij", string(data))
+		return nil
+	})
+
+	serverLogsDirectory := mock.NewMockUploadableDirectory(ctrl)
+	buildDirectory.EXPECT().EnterUploadableDirectory(path.MustNewComponent("server_logs")).Return(serverLogsDirectory, nil)
+	serverLogsDirectory.EXPECT().ReadDir()
+	serverLogsDirectory.EXPECT().Close()
+	inputRootDirectory.EXPECT().Close()
+	buildDirectory.EXPECT().Close()
+
+	truncatedCommandOutputs, err := anypb.New(&resourceusage.TruncatedCommandOutputs{
+		Stderr: &resourceusage.TruncatedCommandOutputs_Output{
+			OriginalSizeBytes: 20,
+			OriginalDigest: &remoteexecution.Digest{
+				Hash:      "6bc14bdc4517a7a682c6910de2e2946eb8e1ecd04090728fef6d092a7ceb62c5",
+				SizeBytes: 20,
+			},
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			StdoutDigest: &remoteexecution.Digest{
+				Hash:      "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+				SizeBytes: 5,
+			},
+			StderrDigest: &remoteexecution.Digest{
+				Hash:      "7e4c626da377ce5b706892ced32ecadbda4543ed170c647dff5ce5e1c068108c",
+				SizeBytes: 42,
+			},
+			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+				AuxiliaryMetadata: []*anypb.Any{truncatedCommandOutputs},
+			},
+		},
+	}, localBuildExecutor.Execute(ctx, filePool, monitor, digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256), &remoteworker.DesiredState_Executing{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "0000000000000000000000000000000000000000000000000000000000000001",
+			SizeBytes: 123,
+		},
+		Action: &remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "0000000000000000000000000000000000000000000000000000000000000002",
+				SizeBytes: 234,
+			},
+			InputRootDigest: &remoteexecution.Digest{
+				Hash:      "0000000000000000000000000000000000000000000000000000000000000003",
+				SizeBytes: 345,
+			},
+			Timeout: &durationpb.Duration{Seconds: 3600},
+		},
+	}, make(chan *remoteworker.CurrentState_Executing, 10)))
+}
+
+func TestLocalBuildExecutorActionResultVerification(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	buildDirectoryCreator := mock.NewMockBuildDirectoryCreator(ctrl)
+	runner := mock.NewMockRunnerClient(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	storageFlusher := mock.NewMockStorageFlusher(ctrl)
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, nil, nil, &builder.ActionResultVerification{
+		MaximumUploadRetries: 1,
+		StorageFlusher:       storageFlusher.Call,
+	}, nil, nil)
+
+	contentAddressableStorage.EXPECT().Get(
+		gomock.Any(),
+		digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000002", 234),
+	).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Command{
+		Arguments: []string{"echo", "Hello"},
+	}, buffer.UserProvided))
+	buildDirectory := mock.NewMockBuildDirectory(ctrl)
+	actionDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000001", 123)
+	buildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest).Return(buildDirectory, nil, nil)
+	filePool := mock.NewMockFilePool(ctrl)
+	buildDirectory.EXPECT().InstallHooks(gomock.Any(), gomock.Any(), filePool, gomock.Any())
+	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("root"), os.FileMode(0o777))
+	inputRootDirectory := mock.NewMockBuildDirectory(ctrl)
+	buildDirectory.EXPECT().EnterBuildDirectory(path.MustNewComponent("root")).Return(inputRootDirectory, nil)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	inputRootDirectory.EXPECT().MergeDirectoryContents(
+		ctx,
+		gomock.Any(),
+		digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000003", 345),
+		monitor,
+	).Return(nil)
+	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("tmp"), os.FileMode(0o777))
+	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("server_logs"), os.FileMode(0o777))
+	clock.EXPECT().NewContextWithTimeout(gomock.Any(), time.Hour).DoAndReturn(func(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+		return context.WithCancel(parent)
+	})
+	runner.EXPECT().Run(gomock.Any(), gomock.Any()).Return(&runner_pb.RunResponse{}, nil)
+
+	stdoutDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "66a045b452102c59d840ec097d59d9467e13a3f34f6494e539ffd32c1bb35f18", 6)
+	emptyDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", 0)
+	buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("stdout"), gomock.Any()).Return(stdoutDigest, nil)
+	buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("stderr"), gomock.Any()).Return(emptyDigest, nil)
+
+	// Standard output is reported as being absent after the first
+	// upload. This should cause all outputs to be uploaded once
+	// more, after which verification succeeds.
+	storageFlusher.EXPECT().Call(ctx).Return(nil)
+	contentAddressableStorage.EXPECT().FindMissing(ctx, stdoutDigest.ToSingletonSet()).
+		Return(stdoutDigest.ToSingletonSet(), nil)
+	buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("stdout"), gomock.Any()).Return(stdoutDigest, nil)
+	buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("stderr"), gomock.Any()).Return(emptyDigest, nil)
+	storageFlusher.EXPECT().Call(ctx).Return(nil)
+	contentAddressableStorage.EXPECT().FindMissing(ctx, stdoutDigest.ToSingletonSet()).
+		Return(digest.EmptySet, nil)
+
+	serverLogsDirectory := mock.NewMockUploadableDirectory(ctrl)
+	buildDirectory.EXPECT().EnterUploadableDirectory(path.MustNewComponent("server_logs")).Return(serverLogsDirectory, nil)
+	serverLogsDirectory.EXPECT().ReadDir()
+	serverLogsDirectory.EXPECT().Close()
+	inputRootDirectory.EXPECT().Close()
+	buildDirectory.EXPECT().Close()
+
+	testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			StdoutDigest:      stdoutDigest.GetProto(),
+			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+		},
+	}, localBuildExecutor.Execute(ctx, filePool, monitor, digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256), &remoteworker.DesiredState_Executing{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "0000000000000000000000000000000000000000000000000000000000000001",
+			SizeBytes: 123,
+		},
+		Action: &remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "0000000000000000000000000000000000000000000000000000000000000002",
+				SizeBytes: 234,
+			},
+			InputRootDigest: &remoteexecution.Digest{
+				Hash:      "0000000000000000000000000000000000000000000000000000000000000003",
+				SizeBytes: 345,
+			},
+			Timeout: &durationpb.Duration{Seconds: 3600},
+		},
+	}, make(chan *remoteworker.CurrentState_Executing, 10)))
+}
+
+func TestLocalBuildExecutorActionResultMaterializationCheck(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	buildDirectoryCreator := mock.NewMockBuildDirectoryCreator(ctrl)
+	runner := mock.NewMockRunnerClient(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	randomNumberGenerator := mock.NewMockThreadSafeGenerator(ctrl)
+	localBuildExecutor := builder.NewLocalBuildExecutor(contentAddressableStorage, buildDirectoryCreator, runner, clock, nil, 10000, map[string]string{}, nil, nil, nil /* forceUploadTreesAndDirectories = */, false, nil, nil, nil, nil, nil, nil, &builder.ActionResultVerification{
+		MaterializationCheckRatio: 0.5,
+		RandomNumberGenerator:     randomNumberGenerator,
+	}, nil, nil)
+
+	contentAddressableStorage.EXPECT().Get(
+		gomock.Any(),
+		digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000002", 234),
+	).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Command{
+		Arguments:   []string{"cc", "-o", "hello.o", "hello.c"},
+		OutputPaths: []string{"hello.o"},
+	}, buffer.UserProvided))
+	buildDirectory := mock.NewMockBuildDirectory(ctrl)
+	actionDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000001", 123)
+	buildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest).Return(buildDirectory, nil, nil)
+	filePool := mock.NewMockFilePool(ctrl)
+	buildDirectory.EXPECT().InstallHooks(gomock.Any(), gomock.Any(), filePool, gomock.Any())
+	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("root"), os.FileMode(0o777))
+	inputRootDirectory := mock.NewMockBuildDirectory(ctrl)
+	buildDirectory.EXPECT().EnterBuildDirectory(path.MustNewComponent("root")).Return(inputRootDirectory, nil)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	inputRootDirectory.EXPECT().MergeDirectoryContents(
+		ctx,
+		gomock.Any(),
+		digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "0000000000000000000000000000000000000000000000000000000000000003", 345),
+		monitor,
+	).Return(nil)
+	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("tmp"), os.FileMode(0o777))
+	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("server_logs"), os.FileMode(0o777))
+	clock.EXPECT().NewContextWithTimeout(gomock.Any(), time.Hour).DoAndReturn(func(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+		return context.WithCancel(parent)
+	})
+	runner.EXPECT().Run(gomock.Any(), gomock.Any()).Return(&runner_pb.RunResponse{}, nil)
+
+	emptyDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", 0)
+	helloDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969", 5)
+	buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("stdout"), gomock.Any()).Return(emptyDigest, nil)
+	buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("stderr"), gomock.Any()).Return(emptyDigest, nil)
+	inputRootDirectory.EXPECT().Lstat(path.MustNewComponent("hello.o")).
+		Return(filesystem.NewFileInfo(path.MustNewComponent("hello.o"), filesystem.FileTypeRegularFile, false), nil).
+		Times(2)
+	inputRootDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("hello.o"), gomock.Any()).Return(helloDigest, nil)
+	contentAddressableStorage.EXPECT().FindMissing(ctx, helloDigest.ToSingletonSet()).Return(digest.EmptySet, nil)
+
+	// The output file is selected for being compared against the
+	// Content Addressable Storage. Its contents in the build
+	// directory differ from the blob that was uploaded, meaning that
+	// the action needs to fail.
+	randomNumberGenerator.EXPECT().Float64().Return(0.3)
+	localDirectory, err := filesystem.NewLocalDirectory(t.TempDir())
+	require.NoError(t, err)
+	defer localDirectory.Close()
+	w, err := localDirectory.OpenAppend(path.MustNewComponent("hello.o"), filesystem.CreateExcl(0o666))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("Hellp"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	f, err := localDirectory.OpenRead(path.MustNewComponent("hello.o"))
+	require.NoError(t, err)
+	inputRootDirectory.EXPECT().OpenRead(path.MustNewComponent("hello.o")).Return(f, nil)
+	contentAddressableStorage.EXPECT().Get(gomock.Any(), helloDigest).
+		Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello")))
+
+	serverLogsDirectory := mock.NewMockUploadableDirectory(ctrl)
+	buildDirectory.EXPECT().EnterUploadableDirectory(path.MustNewComponent("server_logs")).Return(serverLogsDirectory, nil)
+	serverLogsDirectory.EXPECT().ReadDir()
+	serverLogsDirectory.EXPECT().Close()
+	inputRootDirectory.EXPECT().Close()
+	buildDirectory.EXPECT().Close()
+
+	testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			OutputFiles: []*remoteexecution.OutputFile{
+				{
+					Path:   "hello.o",
+					Digest: helloDigest.GetProto(),
+				},
+			},
+			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+		},
+		Status: newClassifiedStatus(t, errorclassification.Domain_OUTPUT_UPLOAD, codes.Internal, "Outputs stored in the Content Addressable Storage do not match the build directory: Output file \"hello.o\": File in build directory differs from blob \"1-185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969-5-\" at or after offset 0").Proto(),
+	}, localBuildExecutor.Execute(ctx, filePool, monitor, digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256), &remoteworker.DesiredState_Executing{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "0000000000000000000000000000000000000000000000000000000000000001",
+			SizeBytes: 123,
+		},
+		Action: &remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "0000000000000000000000000000000000000000000000000000000000000002",
+				SizeBytes: 234,
+			},
+			InputRootDigest: &remoteexecution.Digest{
+				Hash:      "0000000000000000000000000000000000000000000000000000000000000003",
+				SizeBytes: 345,
+			},
+			Timeout: &durationpb.Duration{Seconds: 3600},
+		},
+	}, make(chan *remoteworker.CurrentState_Executing, 10)))
+}
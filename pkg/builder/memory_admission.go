@@ -0,0 +1,93 @@
+package builder
+
+import (
+	"strconv"
+	"sync"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+)
+
+// ActionMemoryPlatformPropertyName is the name of the platform
+// property that may be used by actions to announce the amount of
+// memory they require, in bytes.
+const ActionMemoryPlatformPropertyName = "resources:memory"
+
+// MemoryAdmission keeps track of the estimated memory footprint of all
+// actions running on a worker. Workers use it to decline actions whose
+// memory requirements can't be satisfied, instead of starting them and
+// letting the kernel's OOM killer terminate an arbitrary process.
+//
+// A single instance should be shared by all runners of a worker, as
+// they all share the same physical memory.
+type MemoryAdmission struct {
+	capacityBytes            uint64
+	defaultActionMemoryBytes uint64
+
+	lock      sync.Mutex
+	usedBytes uint64
+	released  chan struct{}
+}
+
+// NewMemoryAdmission creates a MemoryAdmission that permits actions to
+// run, as long as the sum of their memory requirements does not exceed
+// a given capacity. Actions that don't announce their memory
+// requirements through the "resources:memory" platform property are
+// assumed to require a default amount of memory.
+func NewMemoryAdmission(capacityBytes, defaultActionMemoryBytes uint64) *MemoryAdmission {
+	return &MemoryAdmission{
+		capacityBytes:            capacityBytes,
+		defaultActionMemoryBytes: defaultActionMemoryBytes,
+		released:                 make(chan struct{}),
+	}
+}
+
+// getActionMemoryBytes returns the amount of memory that an action is
+// expected to require.
+func (ma *MemoryAdmission) getActionMemoryBytes(platform *remoteexecution.Platform) (uint64, error) {
+	for _, property := range platform.GetProperties() {
+		if property.Name == ActionMemoryPlatformPropertyName {
+			memoryBytes, err := strconv.ParseUint(property.Value, 10, 64)
+			if err != nil {
+				return 0, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid value for platform property %#v", ActionMemoryPlatformPropertyName)
+			}
+			return memoryBytes, nil
+		}
+	}
+	return ma.defaultActionMemoryBytes, nil
+}
+
+// admit an action, provided that its memory requirements can be
+// satisfied. Actions whose requirements exceed the capacity are
+// admitted if no other actions are running, as they would otherwise
+// never be executed.
+//
+// If the action is admitted, the amount of memory that needs to be
+// released upon completion is returned. If the action is not admitted,
+// a channel is returned that is closed the next time memory is
+// released, which may be used to wait before requesting more work.
+func (ma *MemoryAdmission) admit(platform *remoteexecution.Platform) (uint64, <-chan struct{}, error) {
+	memoryBytes, err := ma.getActionMemoryBytes(platform)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	ma.lock.Lock()
+	defer ma.lock.Unlock()
+	if ma.usedBytes > 0 && (memoryBytes > ma.capacityBytes || ma.usedBytes > ma.capacityBytes-memoryBytes) {
+		return 0, ma.released, nil
+	}
+	ma.usedBytes += memoryBytes
+	return memoryBytes, nil, nil
+}
+
+// release memory of an action that was previously admitted.
+func (ma *MemoryAdmission) release(memoryBytes uint64) {
+	ma.lock.Lock()
+	defer ma.lock.Unlock()
+	ma.usedBytes -= memoryBytes
+	close(ma.released)
+	ma.released = make(chan struct{})
+}
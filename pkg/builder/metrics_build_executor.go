@@ -39,6 +39,14 @@ var (
 			Buckets:   util.DecimalExponentialBuckets(-3, 6, 2),
 		},
 		[]string{"result", "grpc_code"})
+	buildExecutorErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "builder",
+			Name:      "build_executor_errors_total",
+			Help:      "Number of build executions that failed, partitioned by the subsystem of the worker in which the error occurred.",
+		},
+		[]string{"grpc_code", "error_domain"})
 
 	// Metrics for FilePoolResourceUsage.
 	buildExecutorFilePoolFilesCreated = prometheus.NewHistogramVec(
@@ -68,6 +76,15 @@ var (
 			Buckets:   prometheus.ExponentialBuckets(1.0, 2.0, 33),
 		},
 		[]string{"result", "grpc_code"})
+	buildExecutorFilePoolFilesAllocatedSizeBytesPeak = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "buildbarn",
+			Subsystem: "builder",
+			Name:      "build_executor_file_pool_files_allocated_size_bytes_peak",
+			Help:      "Peak amount of storage consumed by files created by a build action, in bytes.",
+			Buckets:   prometheus.ExponentialBuckets(1.0, 2.0, 33),
+		},
+		[]string{"result", "grpc_code"})
 	buildExecutorFilePoolFilesOperationsCount = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: "buildbarn",
@@ -234,6 +251,37 @@ var (
 			Buckets:   util.DecimalExponentialBuckets(0, 9, 2),
 		},
 		[]string{"result", "grpc_code"})
+
+	// Metrics for BlockIOResourceUsage.
+	buildExecutorBlockIOOperationsCount = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "buildbarn",
+			Subsystem: "builder",
+			Name:      "build_executor_block_io_operations_count",
+			Help:      "Number of block I/O operations issued by build actions.",
+			Buckets:   util.DecimalExponentialBuckets(0, 9, 2),
+		},
+		[]string{"result", "grpc_code", "operation"})
+	buildExecutorBlockIOOperationsSizeBytes = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "buildbarn",
+			Subsystem: "builder",
+			Name:      "build_executor_block_io_operations_size_bytes",
+			Help:      "Total size of block I/O operations issued by build actions, in bytes.",
+			Buckets:   prometheus.ExponentialBuckets(1.0, 2.0, 41),
+		},
+		[]string{"result", "grpc_code", "operation"})
+
+	// Metrics for EnergyResourceUsage.
+	buildExecutorEstimatedEnergy = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "buildbarn",
+			Subsystem: "builder",
+			Name:      "build_executor_estimated_energy",
+			Help:      "Estimated amount of energy consumed by build actions, in joules.",
+			Buckets:   util.DecimalExponentialBuckets(-1, 7, 2),
+		},
+		[]string{"result", "grpc_code"})
 )
 
 type metricsBuildExecutor struct {
@@ -247,10 +295,12 @@ func NewMetricsBuildExecutor(buildExecutor BuildExecutor) BuildExecutor {
 	buildExecutorPrometheusMetrics.Do(func() {
 		prometheus.MustRegister(buildExecutorDurationSeconds)
 		prometheus.MustRegister(buildExecutorVirtualExecutionDuration)
+		prometheus.MustRegister(buildExecutorErrors)
 
 		prometheus.MustRegister(buildExecutorFilePoolFilesCreated)
 		prometheus.MustRegister(buildExecutorFilePoolFilesCountPeak)
 		prometheus.MustRegister(buildExecutorFilePoolFilesSizeBytesPeak)
+		prometheus.MustRegister(buildExecutorFilePoolFilesAllocatedSizeBytesPeak)
 		prometheus.MustRegister(buildExecutorFilePoolFilesOperationsCount)
 		prometheus.MustRegister(buildExecutorFilePoolFilesOperationsSizeBytes)
 
@@ -271,6 +321,11 @@ func NewMetricsBuildExecutor(buildExecutor BuildExecutor) BuildExecutor {
 		prometheus.MustRegister(buildExecutorPOSIXSignalsReceived)
 		prometheus.MustRegister(buildExecutorPOSIXVoluntaryContextSwitches)
 		prometheus.MustRegister(buildExecutorPOSIXInvoluntaryContextSwitches)
+
+		prometheus.MustRegister(buildExecutorBlockIOOperationsCount)
+		prometheus.MustRegister(buildExecutorBlockIOOperationsSizeBytes)
+
+		prometheus.MustRegister(buildExecutorEstimatedEnergy)
 	})
 
 	return &metricsBuildExecutor{
@@ -295,6 +350,9 @@ func observeTimestampDelta(histogram prometheus.Observer, pbStart, pbCompleted *
 func (be *metricsBuildExecutor) Execute(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
 	response := be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
 	result, grpcCode := GetResultAndGRPCCodeFromExecuteResponse(response)
+	if grpcCode != "" {
+		buildExecutorErrors.WithLabelValues(grpcCode, GetErrorDomainFromExecuteResponse(response).String()).Inc()
+	}
 
 	// Expose metrics for timestamps stored in ExecutedActionMetadata.
 	metadata := response.Result.ExecutionMetadata
@@ -315,16 +373,21 @@ func (be *metricsBuildExecutor) Execute(ctx context.Context, filePool filesystem
 		var filePool resourceusage.FilePoolResourceUsage
 		var inputRoot resourceusage.InputRootResourceUsage
 		var posix resourceusage.POSIXResourceUsage
+		var blockIO resourceusage.BlockIOResourceUsage
+		var energy resourceusage.EnergyResourceUsage
 		if auxiliaryMetadata.UnmarshalTo(&filePool) == nil {
 			// Expose metrics stored in FilePoolResourceUsage.
 			buildExecutorFilePoolFilesCreated.WithLabelValues(result, grpcCode).Observe(float64(filePool.FilesCreated))
 			buildExecutorFilePoolFilesCountPeak.WithLabelValues(result, grpcCode).Observe(float64(filePool.FilesCountPeak))
 			buildExecutorFilePoolFilesSizeBytesPeak.WithLabelValues(result, grpcCode).Observe(float64(filePool.FilesSizeBytesPeak))
+			buildExecutorFilePoolFilesAllocatedSizeBytesPeak.WithLabelValues(result, grpcCode).Observe(float64(filePool.FilesAllocatedSizeBytesPeak))
 			buildExecutorFilePoolFilesOperationsCount.WithLabelValues(result, grpcCode, "Read").Observe(float64(filePool.ReadsCount))
 			buildExecutorFilePoolFilesOperationsSizeBytes.WithLabelValues(result, grpcCode, "Read").Observe(float64(filePool.ReadsSizeBytes))
 			buildExecutorFilePoolFilesOperationsCount.WithLabelValues(result, grpcCode, "Write").Observe(float64(filePool.WritesCount))
 			buildExecutorFilePoolFilesOperationsSizeBytes.WithLabelValues(result, grpcCode, "Write").Observe(float64(filePool.WritesSizeBytes))
 			buildExecutorFilePoolFilesOperationsCount.WithLabelValues(result, grpcCode, "Truncate").Observe(float64(filePool.TruncatesCount))
+			buildExecutorFilePoolFilesOperationsCount.WithLabelValues(result, grpcCode, "Deallocate").Observe(float64(filePool.DeallocatesCount))
+			buildExecutorFilePoolFilesOperationsSizeBytes.WithLabelValues(result, grpcCode, "Deallocate").Observe(float64(filePool.DeallocatesSizeBytes))
 		} else if auxiliaryMetadata.UnmarshalTo(&inputRoot) == nil {
 			buildExecutorInputRootDirectoriesResolved.WithLabelValues(result, grpcCode).Observe(float64(inputRoot.DirectoriesResolved))
 			buildExecutorInputRootDirectoriesRead.WithLabelValues(result, grpcCode).Observe(float64(inputRoot.DirectoriesRead))
@@ -344,6 +407,17 @@ func (be *metricsBuildExecutor) Execute(ctx context.Context, filePool filesystem
 			buildExecutorPOSIXSignalsReceived.WithLabelValues(result, grpcCode).Observe(float64(posix.SignalsReceived))
 			buildExecutorPOSIXVoluntaryContextSwitches.WithLabelValues(result, grpcCode).Observe(float64(posix.VoluntaryContextSwitches))
 			buildExecutorPOSIXInvoluntaryContextSwitches.WithLabelValues(result, grpcCode).Observe(float64(posix.InvoluntaryContextSwitches))
+		} else if auxiliaryMetadata.UnmarshalTo(&blockIO) == nil {
+			// Expose metrics stored in BlockIOResourceUsage.
+			buildExecutorBlockIOOperationsCount.WithLabelValues(result, grpcCode, "Read").Observe(float64(blockIO.ReadOperations))
+			buildExecutorBlockIOOperationsSizeBytes.WithLabelValues(result, grpcCode, "Read").Observe(float64(blockIO.ReadBytes))
+			buildExecutorBlockIOOperationsCount.WithLabelValues(result, grpcCode, "Write").Observe(float64(blockIO.WriteOperations))
+			buildExecutorBlockIOOperationsSizeBytes.WithLabelValues(result, grpcCode, "Write").Observe(float64(blockIO.WriteBytes))
+			buildExecutorBlockIOOperationsCount.WithLabelValues(result, grpcCode, "Discard").Observe(float64(blockIO.DiscardOperations))
+			buildExecutorBlockIOOperationsSizeBytes.WithLabelValues(result, grpcCode, "Discard").Observe(float64(blockIO.DiscardBytes))
+		} else if auxiliaryMetadata.UnmarshalTo(&energy) == nil {
+			// Expose metrics stored in EnergyResourceUsage.
+			buildExecutorEstimatedEnergy.WithLabelValues(result, grpcCode).Observe(energy.EstimatedEnergyJoules)
 		}
 	}
 
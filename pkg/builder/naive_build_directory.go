@@ -4,8 +4,12 @@ import (
 	"context"
 	"io"
 	"math"
+	"os"
+	"sync"
 
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	re_clock "github.com/buildbarn/bb-remote-execution/pkg/clock"
 	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
 	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
 	"github.com/buildbarn/bb-storage/pkg/blobstore"
@@ -15,15 +19,24 @@ import (
 	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
 	"github.com/buildbarn/bb-storage/pkg/util"
 
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type naiveBuildDirectory struct {
 	filesystem.DirectoryCloser
 	directoryFetcher          cas.DirectoryFetcher
 	fileFetcher               cas.FileFetcher
+	fileFetcherSemaphore      *semaphore.Weighted
+	populationOptions         *InputRootPopulationOptions
 	contentAddressableStorage blobstore.BlobAccess
+	warmInputRootPool         *WarmInputRootPool
+
+	inputRootDigest *digest.Digest
 }
 
 // NewNaiveBuildDirectory creates a BuildDirectory that is backed by a
@@ -36,12 +49,32 @@ type naiveBuildDirectory struct {
 // regular local file systems. The downside of such file systems is that
 // we cannot populate them on demand. All of the input files must be
 // present before invoking the build action.
-func NewNaiveBuildDirectory(directory filesystem.DirectoryCloser, directoryFetcher cas.DirectoryFetcher, fileFetcher cas.FileFetcher, contentAddressableStorage blobstore.BlobAccess) BuildDirectory {
+//
+// Input files are fetched concurrently, bounded by
+// fileFetcherSemaphore. This permits FileFetchers backed by storage
+// that coalesces reads of small blobs to do so effectively. Within
+// every directory, executables are fetched first, followed by other
+// files in order of increasing size. If populationOptions is not nil,
+// it may be used to prefetch directories in parallel, to fetch large
+// files using a separate concurrency limit, and to limit the bandwidth
+// used by a single build action.
+//
+// If warmInputRootPool is not nil, directories into which input roots
+// are merged are moved into the pool upon closure. Subsequent calls to
+// MergeDirectoryContents() against empty directories take an input
+// root out of the pool, and only apply the differences between it and
+// the requested input root. Files that are present in both input roots
+// are assumed to be unmodified, meaning that this option should only
+// be used if build actions are unable to alter their input files.
+func NewNaiveBuildDirectory(directory filesystem.DirectoryCloser, directoryFetcher cas.DirectoryFetcher, fileFetcher cas.FileFetcher, fileFetcherSemaphore *semaphore.Weighted, populationOptions *InputRootPopulationOptions, contentAddressableStorage blobstore.BlobAccess, warmInputRootPool *WarmInputRootPool) BuildDirectory {
 	return &naiveBuildDirectory{
 		DirectoryCloser:           directory,
 		directoryFetcher:          directoryFetcher,
 		fileFetcher:               fileFetcher,
+		fileFetcherSemaphore:      fileFetcherSemaphore,
+		populationOptions:         populationOptions,
 		contentAddressableStorage: contentAddressableStorage,
+		warmInputRootPool:         warmInputRootPool,
 	}
 }
 
@@ -54,10 +87,25 @@ func (d *naiveBuildDirectory) EnterBuildDirectory(name path.Component) (BuildDir
 		DirectoryCloser:           child,
 		directoryFetcher:          d.directoryFetcher,
 		fileFetcher:               d.fileFetcher,
+		fileFetcherSemaphore:      d.fileFetcherSemaphore,
+		populationOptions:         d.populationOptions,
 		contentAddressableStorage: d.contentAddressableStorage,
+		warmInputRootPool:         d.warmInputRootPool,
 	}, nil
 }
 
+func (d *naiveBuildDirectory) Close() error {
+	if d.inputRootDigest != nil {
+		// Preserve the input root, so that it may be reused by
+		// a subsequent build action.
+		if err := d.warmInputRootPool.add(d.DirectoryCloser, *d.inputRootDigest); err != nil {
+			d.DirectoryCloser.Close()
+			return util.StatusWrap(err, "Failed to add input root to warm input root pool")
+		}
+	}
+	return d.DirectoryCloser.Close()
+}
+
 func (d *naiveBuildDirectory) EnterParentPopulatableDirectory(name path.Component) (ParentPopulatableDirectory, error) {
 	return d.EnterBuildDirectory(name)
 }
@@ -66,22 +114,81 @@ func (d *naiveBuildDirectory) EnterUploadableDirectory(name path.Component) (Upl
 	return d.EnterBuildDirectory(name)
 }
 
-func (d *naiveBuildDirectory) InstallHooks(filePool re_filesystem.FilePool, errorLogger util.ErrorLogger) {
+func (d *naiveBuildDirectory) InstallHooks(ctx context.Context, digestFunction digest.Function, filePool re_filesystem.FilePool, errorLogger util.ErrorLogger) {
 	// Simply ignore the provided hooks, as POSIX offers no way to
 	// install them. This means no quota enforcement and detection
 	// of I/O errors is performed.
 }
 
-func (d *naiveBuildDirectory) mergeDirectoryContents(ctx context.Context, digest digest.Digest, inputDirectory filesystem.Directory, pathTrace *path.Trace) error {
+// getInputDirectory obtains a Directory object that is part of the
+// input root that is being populated. If prefetching is enabled, it
+// also starts loading the children of the Directory object.
+func (d *naiveBuildDirectory) getInputDirectory(ctx context.Context, p *inputRootPopulation, directoryDigest digest.Digest) (*remoteexecution.Directory, error) {
+	if p.directoryPrefetcher == nil {
+		return d.directoryFetcher.GetDirectory(ctx, directoryDigest)
+	}
+	directory, err := p.directoryPrefetcher.get(directoryDigest)
+	if err != nil {
+		return nil, err
+	}
+	p.directoryPrefetcher.startChildren(directory, directoryDigest.GetDigestFunction())
+	return directory, nil
+}
+
+// fetchInputFile fetches a single input file in the background. Large
+// files are fetched using a separate semaphore, without blocking the
+// caller. The caller is responsible for waiting on filesFetched before
+// closing the directory in which the file is created.
+func (d *naiveBuildDirectory) fetchInputFile(ctx context.Context, p *inputRootPopulation, filesFetched *sync.WaitGroup, blobDigest digest.Digest, directory filesystem.Directory, name path.Component, isExecutable bool, pathTrace *path.Trace) error {
+	sizeBytes := blobDigest.GetSizeBytes()
+	fileFetcherSemaphore := d.fileFetcherSemaphore
+	isLargeFile := d.populationOptions.isLargeFile(sizeBytes)
+	if isLargeFile {
+		fileFetcherSemaphore = d.populationOptions.LargeFileFetchSemaphore
+	} else {
+		if err := p.waitForBandwidth(ctx, sizeBytes); err != nil {
+			return err
+		}
+		if ctx.Err() != nil || fileFetcherSemaphore.Acquire(ctx, 1) != nil {
+			return util.StatusFromContext(ctx)
+		}
+	}
+
+	filesFetched.Add(1)
+	p.group.Go(func() error {
+		defer filesFetched.Done()
+		if isLargeFile {
+			if err := p.waitForBandwidth(ctx, sizeBytes); err != nil {
+				return err
+			}
+			if ctx.Err() != nil || fileFetcherSemaphore.Acquire(ctx, 1) != nil {
+				return util.StatusFromContext(ctx)
+			}
+		}
+		err := d.fileFetcher.GetFile(ctx, blobDigest, directory, name, isExecutable)
+		fileFetcherSemaphore.Release(1)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to obtain input file %#v", pathTrace.String())
+		}
+		return nil
+	})
+	return nil
+}
+
+func (d *naiveBuildDirectory) mergeDirectoryContents(ctx context.Context, p *inputRootPopulation, digest digest.Digest, inputDirectory filesystem.Directory, pathTrace *path.Trace) error {
 	// Obtain directory.
-	directory, err := d.directoryFetcher.GetDirectory(ctx, digest)
+	directory, err := d.getInputDirectory(ctx, p, digest)
 	if err != nil {
 		return util.StatusWrapf(err, "Failed to obtain input directory %#v", pathTrace.String())
 	}
 
-	// Create children.
+	// Create children. Files are fetched in the background, but
+	// must be completed before returning, as the caller closes
+	// inputDirectory afterwards.
+	var filesFetched sync.WaitGroup
+	defer filesFetched.Wait()
 	digestFunction := digest.GetDigestFunction()
-	for _, file := range directory.Files {
+	for _, file := range sortFilesByFetchPriority(directory.Files) {
 		component, ok := path.NewComponent(file.Name)
 		if !ok {
 			return status.Errorf(codes.InvalidArgument, "File %#v has an invalid name", file.Name)
@@ -91,8 +198,8 @@ func (d *naiveBuildDirectory) mergeDirectoryContents(ctx context.Context, digest
 		if err != nil {
 			return util.StatusWrapf(err, "Failed to extract digest for input file %#v", childPathTrace.String())
 		}
-		if err := d.fileFetcher.GetFile(ctx, childDigest, inputDirectory, component, file.IsExecutable); err != nil {
-			return util.StatusWrapf(err, "Failed to obtain input file %#v", childPathTrace.String())
+		if err := d.fetchInputFile(ctx, p, &filesFetched, childDigest, inputDirectory, component, file.IsExecutable, childPathTrace); err != nil {
+			return err
 		}
 	}
 	for _, directory := range directory.Directories {
@@ -112,7 +219,7 @@ func (d *naiveBuildDirectory) mergeDirectoryContents(ctx context.Context, digest
 		if err != nil {
 			return util.StatusWrapf(err, "Failed to enter input directory %#v", childPathTrace.String())
 		}
-		err = d.mergeDirectoryContents(ctx, childDigest, childDirectory, childPathTrace)
+		err = d.mergeDirectoryContents(ctx, p, childDigest, childDirectory, childPathTrace)
 		childDirectory.Close()
 		if err != nil {
 			return err
@@ -131,8 +238,210 @@ func (d *naiveBuildDirectory) mergeDirectoryContents(ctx context.Context, digest
 	return nil
 }
 
-func (d *naiveBuildDirectory) MergeDirectoryContents(ctx context.Context, errorLogger util.ErrorLogger, digest digest.Digest, monitor access.UnreadDirectoryMonitor) error {
-	return d.mergeDirectoryContents(ctx, digest, d.DirectoryCloser, nil)
+// reuseDirectoryContents is similar to mergeDirectoryContents(), except
+// that it operates on a directory that contains the contents of a
+// directory that was used as part of a previous input root. Any files,
+// directories and symbolic links that are not part of the new input
+// root are removed, while the ones that are missing are created.
+//
+// As build actions may leave files behind anywhere in the input root,
+// the contents of all directories are inspected, even if their digests
+// are unchanged. The contents of files are assumed to be unchanged.
+func (d *naiveBuildDirectory) reuseDirectoryContents(ctx context.Context, p *inputRootPopulation, oldDigest *digest.Digest, newDigest digest.Digest, inputDirectory filesystem.Directory, pathTrace *path.Trace) error {
+	// Obtain both the old and the new directory. The old directory
+	// is only known if it was present in the old input root.
+	type oldFile struct {
+		digest       digest.Digest
+		isExecutable bool
+	}
+	oldFiles := map[path.Component]oldFile{}
+	oldDirectories := map[path.Component]digest.Digest{}
+	if oldDigest != nil {
+		oldDirectory, err := d.directoryFetcher.GetDirectory(ctx, *oldDigest)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to obtain previous input directory %#v", pathTrace.String())
+		}
+		oldDigestFunction := oldDigest.GetDigestFunction()
+		for _, file := range oldDirectory.Files {
+			if component, ok := path.NewComponent(file.Name); ok {
+				if fileDigest, err := oldDigestFunction.NewDigestFromProto(file.Digest); err == nil {
+					oldFiles[component] = oldFile{
+						digest:       fileDigest,
+						isExecutable: file.IsExecutable,
+					}
+				}
+			}
+		}
+		for _, directory := range oldDirectory.Directories {
+			if component, ok := path.NewComponent(directory.Name); ok {
+				if directoryDigest, err := oldDigestFunction.NewDigestFromProto(directory.Digest); err == nil {
+					oldDirectories[component] = directoryDigest
+				}
+			}
+		}
+	}
+	newDirectory, err := d.getInputDirectory(ctx, p, newDigest)
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to obtain input directory %#v", pathTrace.String())
+	}
+
+	newFiles := map[path.Component]*remoteexecution.FileNode{}
+	for _, file := range newDirectory.Files {
+		component, ok := path.NewComponent(file.Name)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "File %#v has an invalid name", file.Name)
+		}
+		newFiles[component] = file
+	}
+	newDirectories := map[path.Component]*remoteexecution.DirectoryNode{}
+	for _, directory := range newDirectory.Directories {
+		component, ok := path.NewComponent(directory.Name)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "Directory %#v has an invalid name", directory.Name)
+		}
+		newDirectories[component] = directory
+	}
+	newSymlinks := map[path.Component]string{}
+	for _, symlink := range newDirectory.Symlinks {
+		component, ok := path.NewComponent(symlink.Name)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "Symlink %#v has an invalid name", symlink.Name)
+		}
+		newSymlinks[component] = symlink.Target
+	}
+
+	// Remove all entries in the directory that don't match the new
+	// directory.
+	entries, err := inputDirectory.ReadDir()
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to read contents of input directory %#v", pathTrace.String())
+	}
+	digestFunction := newDigest.GetDigestFunction()
+	presentFiles := map[path.Component]struct{}{}
+	presentDirectories := map[path.Component]struct{}{}
+	presentSymlinks := map[path.Component]struct{}{}
+	for _, entry := range entries {
+		component := entry.Name()
+		keep := false
+		switch entry.Type() {
+		case filesystem.FileTypeRegularFile:
+			if newFile, ok := newFiles[component]; ok {
+				if oldFile, ok := oldFiles[component]; ok && oldFile.isExecutable == newFile.IsExecutable {
+					newFileDigest, err := digestFunction.NewDigestFromProto(newFile.Digest)
+					keep = err == nil && newFileDigest == oldFile.digest
+				}
+			}
+			if keep {
+				presentFiles[component] = struct{}{}
+			}
+		case filesystem.FileTypeDirectory:
+			if _, ok := newDirectories[component]; ok {
+				keep = true
+				presentDirectories[component] = struct{}{}
+			}
+		case filesystem.FileTypeSymlink:
+			if newTarget, ok := newSymlinks[component]; ok {
+				if target, err := inputDirectory.Readlink(component); err == nil && target == newTarget {
+					keep = true
+					presentSymlinks[component] = struct{}{}
+				}
+			}
+		}
+		if !keep {
+			if err := inputDirectory.RemoveAll(component); err != nil {
+				return util.StatusWrapf(err, "Failed to remove %#v", pathTrace.Append(component).String())
+			}
+		}
+	}
+
+	// Create all entries that are missing.
+	var filesFetched sync.WaitGroup
+	defer filesFetched.Wait()
+	for _, file := range sortFilesByFetchPriority(newDirectory.Files) {
+		component := path.MustNewComponent(file.Name)
+		if _, ok := presentFiles[component]; ok {
+			continue
+		}
+		childPathTrace := pathTrace.Append(component)
+		childDigest, err := digestFunction.NewDigestFromProto(file.Digest)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to extract digest for input file %#v", childPathTrace.String())
+		}
+		if err := d.fetchInputFile(ctx, p, &filesFetched, childDigest, inputDirectory, component, file.IsExecutable, childPathTrace); err != nil {
+			return err
+		}
+	}
+	for _, directory := range newDirectory.Directories {
+		component := path.MustNewComponent(directory.Name)
+		childPathTrace := pathTrace.Append(component)
+		childDigest, err := digestFunction.NewDigestFromProto(directory.Digest)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to extract digest for input directory %#v", childPathTrace.String())
+		}
+		var childOldDigest *digest.Digest
+		if _, ok := presentDirectories[component]; ok {
+			if oldDirectoryDigest, ok := oldDirectories[component]; ok {
+				childOldDigest = &oldDirectoryDigest
+			}
+		} else if err := inputDirectory.Mkdir(component, 0o777); err != nil {
+			return util.StatusWrapf(err, "Failed to create input directory %#v", childPathTrace.String())
+		}
+		childDirectory, err := inputDirectory.EnterDirectory(component)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to enter input directory %#v", childPathTrace.String())
+		}
+		err = d.reuseDirectoryContents(ctx, p, childOldDigest, childDigest, childDirectory, childPathTrace)
+		childDirectory.Close()
+		if err != nil {
+			return err
+		}
+	}
+	for _, symlink := range newDirectory.Symlinks {
+		component := path.MustNewComponent(symlink.Name)
+		if _, ok := presentSymlinks[component]; ok {
+			continue
+		}
+		if err := inputDirectory.Symlink(symlink.Target, component); err != nil {
+			return util.StatusWrapf(err, "Failed to create input symlink %#v", pathTrace.Append(component).String())
+		}
+	}
+	return nil
+}
+
+func (d *naiveBuildDirectory) MergeDirectoryContents(ctx context.Context, errorLogger util.ErrorLogger, inputRootDigest digest.Digest, monitor access.UnreadDirectoryMonitor) error {
+	// If enabled, attempt to reuse the input root of a previous
+	// build action. This can only be done if the directory is
+	// still empty.
+	var oldDigest *digest.Digest
+	if d.warmInputRootPool != nil {
+		if entries, err := d.ReadDir(); err != nil {
+			return util.StatusWrap(err, "Failed to read contents of input root")
+		} else if len(entries) == 0 {
+			warmDigest, ok, err := d.warmInputRootPool.take(d.DirectoryCloser)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to take input root from warm input root pool")
+			}
+			if ok {
+				oldDigest = &warmDigest
+			}
+		}
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	p := d.populationOptions.newInputRootPopulation(groupCtx, group, d.directoryFetcher)
+	group.Go(func() error {
+		if oldDigest != nil {
+			return d.reuseDirectoryContents(groupCtx, p, oldDigest, inputRootDigest, d.DirectoryCloser, nil)
+		}
+		return d.mergeDirectoryContents(groupCtx, p, inputRootDigest, d.DirectoryCloser, nil)
+	})
+	if err := group.Wait(); err != nil {
+		return err
+	}
+	if d.warmInputRootPool != nil {
+		d.inputRootDigest = &inputRootDigest
+	}
+	return nil
 }
 
 func (d *naiveBuildDirectory) UploadFile(ctx context.Context, name path.Component, digestFunction digest.Function) (digest.Digest, error) {
@@ -142,8 +451,10 @@ func (d *naiveBuildDirectory) UploadFile(ctx context.Context, name path.Componen
 	}
 
 	// Walk through the file to compute the digest.
+	hashingMeasurement := re_clock.StartPhase(ctx, re_clock.PhaseOutputHashing)
 	digestGenerator := digestFunction.NewGenerator(math.MaxInt64)
 	sizeBytes, err := io.Copy(digestGenerator, io.NewSectionReader(file, 0, math.MaxInt64))
+	hashingMeasurement.Finish()
 	if err != nil {
 		file.Close()
 		return digest.BadDigest, util.StatusWrap(err, "Failed to compute file digest")
@@ -166,6 +477,65 @@ func (d *naiveBuildDirectory) UploadFile(ctx context.Context, name path.Componen
 	return blobDigest, nil
 }
 
+// statableFile is implemented by files returned by local directories,
+// as they are backed by os.File. It is used by
+// naiveBuildDirectory.GetNodeProperties() to obtain attributes that are
+// not part of filesystem.FileInfo.
+type statableFile interface {
+	Stat() (os.FileInfo, error)
+}
+
+func (d *naiveBuildDirectory) GetFileSizeBytes(ctx context.Context, name path.Component) (int64, error) {
+	file, err := d.OpenRead(name)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	f, ok := file.(statableFile)
+	if !ok {
+		return 0, status.Error(codes.Unimplemented, "Build directory does not support obtaining file attributes")
+	}
+	fileInfo, err := f.Stat()
+	if err != nil {
+		return 0, util.StatusWrap(err, "Failed to obtain file attributes")
+	}
+	return fileInfo.Size(), nil
+}
+
+func (d *naiveBuildDirectory) GetNodeProperties(ctx context.Context, name path.Component) (*remoteexecution.NodeProperties, error) {
+	file, err := d.OpenRead(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	f, ok := file.(statableFile)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "Build directory does not support obtaining file attributes")
+	}
+	fileInfo, err := f.Stat()
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to obtain file attributes")
+	}
+
+	mode := fileInfo.Mode()
+	unixMode := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		unixMode |= 0o4000
+	}
+	if mode&os.ModeSetgid != 0 {
+		unixMode |= 0o2000
+	}
+	if mode&os.ModeSticky != 0 {
+		unixMode |= 0o1000
+	}
+	return &remoteexecution.NodeProperties{
+		Mtime:    timestamppb.New(fileInfo.ModTime()),
+		UnixMode: wrapperspb.UInt32(unixMode),
+	}, nil
+}
+
 // newSectionReadCloser returns an io.ReadCloser that reads from r at a
 // given offset, but stops with EOF after n bytes. This function is
 // identical to io.NewSectionReader(), except that it provides an
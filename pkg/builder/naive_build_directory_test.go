@@ -3,22 +3,29 @@ package builder_test
 import (
 	"context"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"syscall"
 	"testing"
+	"time"
 
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-remote-execution/internal/mock"
 	"github.com/buildbarn/bb-remote-execution/pkg/builder"
 	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
 	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
 	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
 	"github.com/buildbarn/bb-storage/pkg/testutil"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
+	"golang.org/x/sync/semaphore"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestNaiveBuildDirectorySuccess(t *testing.T) {
@@ -26,7 +33,7 @@ func TestNaiveBuildDirectorySuccess(t *testing.T) {
 
 	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
 	directoryFetcher.EXPECT().GetDirectory(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "7777777777777777777777777777777777777777777777777777777777777777", 42),
 	).Return(&remoteexecution.Directory{
 		Directories: []*remoteexecution.DirectoryNode{
@@ -63,7 +70,7 @@ func TestNaiveBuildDirectorySuccess(t *testing.T) {
 		},
 	}, nil)
 	directoryFetcher.EXPECT().GetDirectory(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "8888888888888888888888888888888888888888888888888888888888888888", 123),
 	).Return(&remoteexecution.Directory{
 		Symlinks: []*remoteexecution.SymlinkNode{
@@ -82,13 +89,13 @@ func TestNaiveBuildDirectorySuccess(t *testing.T) {
 	nestedDirectory.EXPECT().Close()
 	fileFetcher := mock.NewMockFileFetcher(ctrl)
 	fileFetcher.EXPECT().GetFile(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "9999999999999999999999999999999999999999999999999999999999999999", 512),
 		buildDirectory,
 		path.MustNewComponent("non-executable"),
 		false).Return(nil)
 	fileFetcher.EXPECT().GetFile(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", 512),
 		buildDirectory,
 		path.MustNewComponent("executable"),
@@ -96,7 +103,7 @@ func TestNaiveBuildDirectorySuccess(t *testing.T) {
 	buildDirectory.EXPECT().Symlink("executable",
 		path.MustNewComponent("link-to-executable")).Return(nil)
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	inputRootPopulator := builder.NewNaiveBuildDirectory(buildDirectory, directoryFetcher, fileFetcher, contentAddressableStorage)
+	inputRootPopulator := builder.NewNaiveBuildDirectory(buildDirectory, directoryFetcher, fileFetcher, semaphore.NewWeighted(1), nil, contentAddressableStorage, nil)
 
 	err := inputRootPopulator.MergeDirectoryContents(
 		ctx,
@@ -111,14 +118,14 @@ func TestNaiveBuildDirectoryInputRootNotInStorage(t *testing.T) {
 
 	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
 	directoryFetcher.EXPECT().GetDirectory(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "7777777777777777777777777777777777777777777777777777777777777777", 42),
 	).Return(nil, status.Error(codes.Internal, "Storage is offline"))
 	errorLogger := mock.NewMockErrorLogger(ctrl)
 	buildDirectory := mock.NewMockDirectoryCloser(ctrl)
 	fileFetcher := mock.NewMockFileFetcher(ctrl)
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	inputRootPopulator := builder.NewNaiveBuildDirectory(buildDirectory, directoryFetcher, fileFetcher, contentAddressableStorage)
+	inputRootPopulator := builder.NewNaiveBuildDirectory(buildDirectory, directoryFetcher, fileFetcher, semaphore.NewWeighted(1), nil, contentAddressableStorage, nil)
 
 	err := inputRootPopulator.MergeDirectoryContents(
 		ctx,
@@ -133,7 +140,7 @@ func TestNaiveBuildDirectoryMissingInputDirectoryDigest(t *testing.T) {
 
 	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
 	directoryFetcher.EXPECT().GetDirectory(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "7777777777777777777777777777777777777777777777777777777777777777", 42),
 	).Return(&remoteexecution.Directory{
 		Directories: []*remoteexecution.DirectoryNode{
@@ -147,7 +154,7 @@ func TestNaiveBuildDirectoryMissingInputDirectoryDigest(t *testing.T) {
 		},
 	}, nil)
 	directoryFetcher.EXPECT().GetDirectory(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "8888888888888888888888888888888888888888888888888888888888888888", 123),
 	).Return(&remoteexecution.Directory{
 		Directories: []*remoteexecution.DirectoryNode{
@@ -164,7 +171,7 @@ func TestNaiveBuildDirectoryMissingInputDirectoryDigest(t *testing.T) {
 	helloDirectory.EXPECT().Close()
 	fileFetcher := mock.NewMockFileFetcher(ctrl)
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	inputRootPopulator := builder.NewNaiveBuildDirectory(buildDirectory, directoryFetcher, fileFetcher, contentAddressableStorage)
+	inputRootPopulator := builder.NewNaiveBuildDirectory(buildDirectory, directoryFetcher, fileFetcher, semaphore.NewWeighted(1), nil, contentAddressableStorage, nil)
 
 	err := inputRootPopulator.MergeDirectoryContents(
 		ctx,
@@ -179,7 +186,7 @@ func TestNaiveBuildDirectoryDirectoryCreationFailure(t *testing.T) {
 
 	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
 	directoryFetcher.EXPECT().GetDirectory(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "7777777777777777777777777777777777777777777777777777777777777777", 42),
 	).Return(&remoteexecution.Directory{
 		Directories: []*remoteexecution.DirectoryNode{
@@ -193,7 +200,7 @@ func TestNaiveBuildDirectoryDirectoryCreationFailure(t *testing.T) {
 		},
 	}, nil)
 	directoryFetcher.EXPECT().GetDirectory(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "8888888888888888888888888888888888888888888888888888888888888888", 123),
 	).Return(&remoteexecution.Directory{
 		Directories: []*remoteexecution.DirectoryNode{
@@ -215,7 +222,7 @@ func TestNaiveBuildDirectoryDirectoryCreationFailure(t *testing.T) {
 	helloDirectory.EXPECT().Close()
 	fileFetcher := mock.NewMockFileFetcher(ctrl)
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	inputRootPopulator := builder.NewNaiveBuildDirectory(buildDirectory, directoryFetcher, fileFetcher, contentAddressableStorage)
+	inputRootPopulator := builder.NewNaiveBuildDirectory(buildDirectory, directoryFetcher, fileFetcher, semaphore.NewWeighted(1), nil, contentAddressableStorage, nil)
 
 	err := inputRootPopulator.MergeDirectoryContents(
 		ctx,
@@ -230,7 +237,7 @@ func TestNaiveBuildDirectoryDirectoryEnterDirectoryFailure(t *testing.T) {
 
 	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
 	directoryFetcher.EXPECT().GetDirectory(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "7777777777777777777777777777777777777777777777777777777777777777", 42),
 	).Return(&remoteexecution.Directory{
 		Directories: []*remoteexecution.DirectoryNode{
@@ -244,7 +251,7 @@ func TestNaiveBuildDirectoryDirectoryEnterDirectoryFailure(t *testing.T) {
 		},
 	}, nil)
 	directoryFetcher.EXPECT().GetDirectory(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "8888888888888888888888888888888888888888888888888888888888888888", 123),
 	).Return(&remoteexecution.Directory{
 		Directories: []*remoteexecution.DirectoryNode{
@@ -267,7 +274,7 @@ func TestNaiveBuildDirectoryDirectoryEnterDirectoryFailure(t *testing.T) {
 	helloDirectory.EXPECT().Close()
 	fileFetcher := mock.NewMockFileFetcher(ctrl)
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	inputRootPopulator := builder.NewNaiveBuildDirectory(buildDirectory, directoryFetcher, fileFetcher, contentAddressableStorage)
+	inputRootPopulator := builder.NewNaiveBuildDirectory(buildDirectory, directoryFetcher, fileFetcher, semaphore.NewWeighted(1), nil, contentAddressableStorage, nil)
 
 	err := inputRootPopulator.MergeDirectoryContents(
 		ctx,
@@ -282,7 +289,7 @@ func TestNaiveBuildDirectoryMissingInputFileDigest(t *testing.T) {
 
 	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
 	directoryFetcher.EXPECT().GetDirectory(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "7777777777777777777777777777777777777777777777777777777777777777", 42),
 	).Return(&remoteexecution.Directory{
 		Directories: []*remoteexecution.DirectoryNode{
@@ -296,7 +303,7 @@ func TestNaiveBuildDirectoryMissingInputFileDigest(t *testing.T) {
 		},
 	}, nil)
 	directoryFetcher.EXPECT().GetDirectory(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "8888888888888888888888888888888888888888888888888888888888888888", 123),
 	).Return(&remoteexecution.Directory{
 		Files: []*remoteexecution.FileNode{
@@ -313,7 +320,7 @@ func TestNaiveBuildDirectoryMissingInputFileDigest(t *testing.T) {
 	helloDirectory.EXPECT().Close()
 	fileFetcher := mock.NewMockFileFetcher(ctrl)
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	inputRootPopulator := builder.NewNaiveBuildDirectory(buildDirectory, directoryFetcher, fileFetcher, contentAddressableStorage)
+	inputRootPopulator := builder.NewNaiveBuildDirectory(buildDirectory, directoryFetcher, fileFetcher, semaphore.NewWeighted(1), nil, contentAddressableStorage, nil)
 
 	err := inputRootPopulator.MergeDirectoryContents(
 		ctx,
@@ -328,7 +335,7 @@ func TestNaiveBuildDirectoryFileCreationFailure(t *testing.T) {
 
 	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
 	directoryFetcher.EXPECT().GetDirectory(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "7777777777777777777777777777777777777777777777777777777777777777", 42),
 	).Return(&remoteexecution.Directory{
 		Directories: []*remoteexecution.DirectoryNode{
@@ -342,7 +349,7 @@ func TestNaiveBuildDirectoryFileCreationFailure(t *testing.T) {
 		},
 	}, nil)
 	directoryFetcher.EXPECT().GetDirectory(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "8888888888888888888888888888888888888888888888888888888888888888", 123),
 	).Return(&remoteexecution.Directory{
 		Files: []*remoteexecution.FileNode{
@@ -362,14 +369,14 @@ func TestNaiveBuildDirectoryFileCreationFailure(t *testing.T) {
 	buildDirectory.EXPECT().EnterDirectory(path.MustNewComponent("Hello")).Return(helloDirectory, nil)
 	fileFetcher := mock.NewMockFileFetcher(ctrl)
 	fileFetcher.EXPECT().GetFile(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", 87),
 		helloDirectory,
 		path.MustNewComponent("World"),
 		false).Return(status.Error(codes.DataLoss, "Disk on fire"))
 	helloDirectory.EXPECT().Close()
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	inputRootPopulator := builder.NewNaiveBuildDirectory(buildDirectory, directoryFetcher, fileFetcher, contentAddressableStorage)
+	inputRootPopulator := builder.NewNaiveBuildDirectory(buildDirectory, directoryFetcher, fileFetcher, semaphore.NewWeighted(1), nil, contentAddressableStorage, nil)
 
 	err := inputRootPopulator.MergeDirectoryContents(
 		ctx,
@@ -384,7 +391,7 @@ func TestNaiveBuildDirectorySymlinkCreationFailure(t *testing.T) {
 
 	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
 	directoryFetcher.EXPECT().GetDirectory(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "7777777777777777777777777777777777777777777777777777777777777777", 42),
 	).Return(&remoteexecution.Directory{
 		Directories: []*remoteexecution.DirectoryNode{
@@ -398,7 +405,7 @@ func TestNaiveBuildDirectorySymlinkCreationFailure(t *testing.T) {
 		},
 	}, nil)
 	directoryFetcher.EXPECT().GetDirectory(
-		ctx,
+		gomock.Any(),
 		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "8888888888888888888888888888888888888888888888888888888888888888", 123),
 	).Return(&remoteexecution.Directory{
 		Symlinks: []*remoteexecution.SymlinkNode{
@@ -417,7 +424,7 @@ func TestNaiveBuildDirectorySymlinkCreationFailure(t *testing.T) {
 	helloDirectory.EXPECT().Close()
 	fileFetcher := mock.NewMockFileFetcher(ctrl)
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	inputRootPopulator := builder.NewNaiveBuildDirectory(buildDirectory, directoryFetcher, fileFetcher, contentAddressableStorage)
+	inputRootPopulator := builder.NewNaiveBuildDirectory(buildDirectory, directoryFetcher, fileFetcher, semaphore.NewWeighted(1), nil, contentAddressableStorage, nil)
 
 	err := inputRootPopulator.MergeDirectoryContents(
 		ctx,
@@ -427,6 +434,116 @@ func TestNaiveBuildDirectorySymlinkCreationFailure(t *testing.T) {
 	testutil.RequireEqualStatus(t, status.Error(codes.Unimplemented, "Failed to create input symlink \"Hello/World\": This filesystem does not support symbolic links"), err)
 }
 
+func TestNaiveBuildDirectoryInputRootPopulation(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	// Directory objects should be prefetched, and files should be
+	// fetched in order of priority, respecting the bandwidth limit.
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	directoryFetcher.EXPECT().GetDirectory(
+		gomock.Any(),
+		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "7777777777777777777777777777777777777777777777777777777777777777", 42),
+	).Return(&remoteexecution.Directory{
+		Directories: []*remoteexecution.DirectoryNode{
+			{
+				Name: "directory",
+				Digest: &remoteexecution.Digest{
+					Hash:      "8888888888888888888888888888888888888888888888888888888888888888",
+					SizeBytes: 123,
+				},
+			},
+		},
+		Files: []*remoteexecution.FileNode{
+			{
+				Name: "large",
+				Digest: &remoteexecution.Digest{
+					Hash:      "9999999999999999999999999999999999999999999999999999999999999999",
+					SizeBytes: 1000,
+				},
+			},
+			{
+				Name: "small",
+				Digest: &remoteexecution.Digest{
+					Hash:      "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+					SizeBytes: 10,
+				},
+			},
+			{
+				Name: "executable",
+				Digest: &remoteexecution.Digest{
+					Hash:      "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+					SizeBytes: 500,
+				},
+				IsExecutable: true,
+			},
+		},
+	}, nil)
+	directoryFetcher.EXPECT().GetDirectory(
+		gomock.Any(),
+		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "8888888888888888888888888888888888888888888888888888888888888888", 123),
+	).Return(&remoteexecution.Directory{}, nil)
+	errorLogger := mock.NewMockErrorLogger(ctrl)
+	buildDirectory := mock.NewMockDirectoryCloser(ctrl)
+	buildDirectory.EXPECT().Mkdir(path.MustNewComponent("directory"), os.FileMode(0o777)).Return(nil)
+	nestedDirectory := mock.NewMockDirectoryCloser(ctrl)
+	buildDirectory.EXPECT().EnterDirectory(path.MustNewComponent("directory")).Return(nestedDirectory, nil)
+	nestedDirectory.EXPECT().Close()
+
+	// At 100 bytes per second, the executable may be fetched
+	// immediately. The small file needs to wait for five seconds,
+	// while the large file needs to wait for the small file as well.
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	fileFetcher := mock.NewMockFileFetcher(ctrl)
+	fetchExecutable := fileFetcher.EXPECT().GetFile(
+		gomock.Any(),
+		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", 500),
+		buildDirectory,
+		path.MustNewComponent("executable"),
+		true).Return(nil)
+	timer1 := mock.NewMockTimer(ctrl)
+	timerChannel1 := make(chan time.Time, 1)
+	timerChannel1 <- time.Unix(1005, 0)
+	clock.EXPECT().NewTimer(5*time.Second).Return(timer1, timerChannel1)
+	fileFetcher.EXPECT().GetFile(
+		gomock.Any(),
+		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", 10),
+		buildDirectory,
+		path.MustNewComponent("small"),
+		false).Return(nil).After(fetchExecutable)
+	timer2 := mock.NewMockTimer(ctrl)
+	timerChannel2 := make(chan time.Time, 1)
+	timerChannel2 <- time.Unix(1005, 100000000)
+	clock.EXPECT().NewTimer(5100*time.Millisecond).Return(timer2, timerChannel2)
+	fileFetcher.EXPECT().GetFile(
+		gomock.Any(),
+		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "9999999999999999999999999999999999999999999999999999999999999999", 1000),
+		buildDirectory,
+		path.MustNewComponent("large"),
+		false).Return(nil)
+	inputRootPopulator := builder.NewNaiveBuildDirectory(
+		buildDirectory,
+		directoryFetcher,
+		fileFetcher,
+		semaphore.NewWeighted(1),
+		&builder.InputRootPopulationOptions{
+			DirectoryFetchSemaphore:        semaphore.NewWeighted(1),
+			LargeFileSizeBytes:             600,
+			LargeFileFetchSemaphore:        semaphore.NewWeighted(1),
+			MaximumBytesPerSecondPerAction: 100,
+			Clock:                          clock,
+		},
+		mock.NewMockBlobAccess(ctrl),
+		nil)
+
+	err := inputRootPopulator.MergeDirectoryContents(
+		ctx,
+		errorLogger,
+		digest.MustNewDigest("netbsd", remoteexecution.DigestFunction_SHA256, "7777777777777777777777777777777777777777777777777777777777777777", 42),
+		nil)
+	require.NoError(t, err)
+}
+
 func TestNaiveBuildDirectoryUploadFile(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
@@ -438,7 +555,10 @@ func TestNaiveBuildDirectoryUploadFile(t *testing.T) {
 		buildDirectory,
 		directoryFetcher,
 		fileFetcher,
-		contentAddressableStorage)
+		semaphore.NewWeighted(1),
+		nil,
+		contentAddressableStorage,
+		nil)
 
 	helloWorldDigest := digest.MustNewDigest("default-scheduler", remoteexecution.DigestFunction_MD5, "3e25960a79dbc69b674cd4ec67a72c62", 11)
 	digestFunction := helloWorldDigest.GetDigestFunction()
@@ -529,3 +649,195 @@ func TestNaiveBuildDirectoryUploadFile(t *testing.T) {
 		require.Equal(t, digest, helloWorldDigest)
 	})
 }
+
+func TestNaiveBuildDirectoryGetNodeProperties(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	t.Run("Unsupported", func(t *testing.T) {
+		// Files that are not backed by the local file system
+		// cannot provide their attributes.
+		buildDirectory := mock.NewMockDirectoryCloser(ctrl)
+		inputRootPopulator := builder.NewNaiveBuildDirectory(
+			buildDirectory,
+			mock.NewMockDirectoryFetcher(ctrl),
+			mock.NewMockFileFetcher(ctrl),
+			semaphore.NewWeighted(1),
+			nil,
+			mock.NewMockBlobAccess(ctrl),
+			nil)
+		file := mock.NewMockFileReader(ctrl)
+		buildDirectory.EXPECT().OpenRead(path.MustNewComponent("hello")).Return(file, nil)
+		file.EXPECT().Close()
+
+		_, err := inputRootPopulator.GetNodeProperties(ctx, path.MustNewComponent("hello"))
+		testutil.RequireEqualStatus(t, status.Error(codes.Unimplemented, "Build directory does not support obtaining file attributes"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		buildPath := t.TempDir()
+		filePath := filepath.Join(buildPath, "hello")
+		require.NoError(t, os.WriteFile(filePath, []byte("Hello world"), 0o644))
+		require.NoError(t, os.Chmod(filePath, 0o751))
+		require.NoError(t, os.Chtimes(filePath, time.Unix(1700000000, 0), time.Unix(1600000000, 500)))
+
+		buildDirectory, err := filesystem.NewLocalDirectory(buildPath)
+		require.NoError(t, err)
+		inputRootPopulator := builder.NewNaiveBuildDirectory(
+			buildDirectory,
+			mock.NewMockDirectoryFetcher(ctrl),
+			mock.NewMockFileFetcher(ctrl),
+			semaphore.NewWeighted(1),
+			nil,
+			mock.NewMockBlobAccess(ctrl),
+			nil)
+		defer inputRootPopulator.Close()
+
+		nodeProperties, err := inputRootPopulator.GetNodeProperties(ctx, path.MustNewComponent("hello"))
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteexecution.NodeProperties{
+			Mtime:    &timestamppb.Timestamp{Seconds: 1600000000, Nanos: 500},
+			UnixMode: &wrapperspb.UInt32Value{Value: 0o751},
+		}, nodeProperties)
+	})
+}
+
+func TestNaiveBuildDirectoryWarmInputRootPool(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	buildPath := t.TempDir()
+	warmPath := t.TempDir()
+	warmDirectory, err := filesystem.NewLocalDirectory(warmPath)
+	require.NoError(t, err)
+	defer warmDirectory.Close()
+	warmInputRootPool := builder.NewWarmInputRootPool(warmDirectory, 1)
+
+	fileDigest := func(hash string) *remoteexecution.Digest {
+		return &remoteexecution.Digest{Hash: hash, SizeBytes: 1}
+	}
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	directories := map[string]*remoteexecution.Directory{
+		// Input root of the first action.
+		"00000000000000000000000000000001": {
+			Directories: []*remoteexecution.DirectoryNode{
+				{Name: "sub", Digest: fileDigest("00000000000000000000000000000003")},
+			},
+			Files: []*remoteexecution.FileNode{
+				{Name: "a.txt", Digest: fileDigest("0000000000000000000000000000000a")},
+				{Name: "b.txt", Digest: fileDigest("0000000000000000000000000000000b")},
+			},
+			Symlinks: []*remoteexecution.SymlinkNode{
+				{Name: "link", Target: "a.txt"},
+			},
+		},
+		// Input root of the second action.
+		"00000000000000000000000000000002": {
+			Directories: []*remoteexecution.DirectoryNode{
+				{Name: "sub", Digest: fileDigest("00000000000000000000000000000003")},
+			},
+			Files: []*remoteexecution.FileNode{
+				{Name: "a.txt", Digest: fileDigest("0000000000000000000000000000000a")},
+				{Name: "b.txt", Digest: fileDigest("0000000000000000000000000000000c")},
+				{Name: "new.txt", Digest: fileDigest("0000000000000000000000000000000d")},
+			},
+			Symlinks: []*remoteexecution.SymlinkNode{
+				{Name: "link", Target: "b.txt"},
+			},
+		},
+		"00000000000000000000000000000003": {
+			Files: []*remoteexecution.FileNode{
+				{Name: "c.txt", Digest: fileDigest("0000000000000000000000000000000e")},
+			},
+		},
+	}
+	directoryFetcher.EXPECT().GetDirectory(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, directoryDigest digest.Digest) (*remoteexecution.Directory, error) {
+			return directories[directoryDigest.GetHashString()], nil
+		}).AnyTimes()
+	fileFetcher := mock.NewMockFileFetcher(ctrl)
+	expectGetFile := func(name, hash string) {
+		fileFetcher.EXPECT().GetFile(gomock.Any(), digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, hash, 1), gomock.Any(), path.MustNewComponent(name), false).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, directory filesystem.Directory, name path.Component, isExecutable bool) error {
+				w, err := directory.OpenWrite(name, filesystem.CreateExcl(0o444))
+				if err != nil {
+					return err
+				}
+				_, err = w.WriteAt([]byte(hash[len(hash)-1:]), 0)
+				w.Close()
+				return err
+			})
+	}
+	errorLogger := mock.NewMockErrorLogger(ctrl)
+
+	getInputRoot := func(name string) builder.BuildDirectory {
+		require.NoError(t, os.Mkdir(filepath.Join(buildPath, name), 0o777))
+		directory, err := filesystem.NewLocalDirectory(filepath.Join(buildPath, name))
+		require.NoError(t, err)
+		return builder.NewNaiveBuildDirectory(directory, directoryFetcher, fileFetcher, semaphore.NewWeighted(1), nil, nil, warmInputRootPool)
+	}
+
+	// The first input root needs to be constructed from scratch.
+	expectGetFile("a.txt", "0000000000000000000000000000000a")
+	expectGetFile("b.txt", "0000000000000000000000000000000b")
+	expectGetFile("c.txt", "0000000000000000000000000000000e")
+	inputRoot1 := getInputRoot("root1")
+	require.NoError(t, inputRoot1.MergeDirectoryContents(ctx, errorLogger, digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "00000000000000000000000000000001", 1), nil))
+
+	// Let the build action leave some files behind. Closing the
+	// input root should move it into the pool.
+	require.NoError(t, os.WriteFile(filepath.Join(buildPath, "root1", "output.o"), nil, 0o666))
+	require.NoError(t, os.WriteFile(filepath.Join(buildPath, "root1", "sub", "stray"), nil, 0o666))
+	require.NoError(t, inputRoot1.Close())
+	entries, err := os.ReadDir(filepath.Join(buildPath, "root1"))
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	// The second input root should be derived from the first one.
+	// Only files that are new or changed should be fetched, while
+	// files left behind by the build action should be removed.
+	expectGetFile("b.txt", "0000000000000000000000000000000c")
+	expectGetFile("new.txt", "0000000000000000000000000000000d")
+	inputRoot2 := getInputRoot("root2")
+	require.NoError(t, inputRoot2.MergeDirectoryContents(ctx, errorLogger, digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "00000000000000000000000000000002", 1), nil))
+
+	var contents []string
+	require.NoError(t, filepath.WalkDir(filepath.Join(buildPath, "root2"), func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(buildPath, p)
+		if err != nil {
+			return err
+		}
+		switch {
+		case entry.Type()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			contents = append(contents, relativePath+" -> "+target)
+		case entry.Type().IsRegular():
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			contents = append(contents, relativePath+": "+string(data))
+		default:
+			contents = append(contents, relativePath+"/")
+		}
+		return nil
+	}))
+	require.Equal(t, []string{
+		"root2/",
+		"root2/a.txt: a",
+		"root2/b.txt: c",
+		"root2/link -> b.txt",
+		"root2/new.txt: d",
+		"root2/sub/",
+		"root2/sub/c.txt: e",
+	}, contents)
+
+	entries, err = os.ReadDir(warmPath)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+	require.NoError(t, inputRoot2.Close())
+}
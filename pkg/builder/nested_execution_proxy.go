@@ -0,0 +1,470 @@
+package builder
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/auth"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	bb_grpc "github.com/buildbarn/bb-storage/pkg/grpc"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/google/uuid"
+
+	"google.golang.org/genproto/googleapis/bytestream"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// NestedExecutionPlatformPropertyName is the name of the platform
+// property that may be used by actions to request that they are
+// provided access to the Remote Execution API, so that they can
+// schedule remote work themselves. Its value must be "true".
+const NestedExecutionPlatformPropertyName = "nested-execution"
+
+// NestedExecutionProxy contains the options that need to be provided
+// to LocalBuildExecutor to let actions schedule remote work
+// themselves. This is useful for build tools that run inside of an
+// action, such as nested Bazel invocations or test sharding drivers.
+//
+// For every action that requests it, a gRPC server is launched that
+// listens on a UNIX socket. The socket is placed in a directory of its
+// own that is only accessible by the user running the action, and
+// connections made by other users are rejected. Calls against this
+// server are forwarded to the cluster, using the instance name and the
+// authentication metadata of the client that requested execution of
+// the action. Actions are not permitted to use other instance names,
+// to write entries into the Action Cache, or to wait for operations
+// that they did not create.
+type NestedExecutionProxy struct {
+	// Name of the environment variable that is set to the gRPC
+	// target of the proxy (e.g., "unix:///run/nested/<uuid>/socket").
+	EnvironmentVariable string
+	// Directory in which directories containing UNIX sockets are
+	// created. This directory needs to be accessible by actions
+	// under the same path.
+	SocketDirectory string
+	// User ID and group ID under which actions run. The directory
+	// containing the UNIX socket is owned by this user, and
+	// connections made by other users are rejected.
+	UserID  uint32
+	GroupID uint32
+	// Connection to the cluster, to which calls are forwarded. The
+	// authentication metadata of the client that requested
+	// execution of the action is attached to the context of calls,
+	// so that credentials can be derived from it.
+	ClientConnection grpc.ClientConnInterface
+}
+
+// isNestedExecutionRequested returns whether an action has requested
+// that it is provided access to the Remote Execution API.
+func isNestedExecutionRequested(action *remoteexecution.Action) bool {
+	for _, property := range action.GetPlatform().GetProperties() {
+		if property.Name == NestedExecutionPlatformPropertyName {
+			return property.Value == "true"
+		}
+	}
+	return false
+}
+
+// start a gRPC server on a UNIX socket that forwards calls on behalf
+// of a single action. The gRPC target of the server is returned,
+// together with a function that needs to be called to stop the server
+// once the action has completed.
+func (p *NestedExecutionProxy) start(instanceName digest.InstanceName, authenticationMetadata *auth.AuthenticationMetadata, maximumMessageSizeBytes int) (string, func(), error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return "", nil, util.StatusWrap(err, "Failed to generate socket directory name")
+	}
+
+	// Place the socket in a directory that is only accessible by
+	// the user running the action, so that other processes on the
+	// system cannot connect to it.
+	socketDirectory := filepath.Join(p.SocketDirectory, id.String())
+	if err := os.Mkdir(socketDirectory, 0o700); err != nil {
+		return "", nil, util.StatusWrapf(err, "Failed to create socket directory %#v", socketDirectory)
+	}
+	if int(p.UserID) != os.Geteuid() || int(p.GroupID) != os.Getegid() {
+		if err := os.Chown(socketDirectory, int(p.UserID), int(p.GroupID)); err != nil {
+			os.Remove(socketDirectory)
+			return "", nil, util.StatusWrapf(err, "Failed to change ownership of socket directory %#v", socketDirectory)
+		}
+	}
+	socketPath := filepath.Join(socketDirectory, "socket")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		os.Remove(socketDirectory)
+		return "", nil, util.StatusWrapf(err, "Failed to listen on %#v", socketPath)
+	}
+	// Actions may run as a different user than the worker. Access
+	// is already restricted by the permissions of the directory.
+	if err := os.Chmod(socketPath, 0o666); err != nil {
+		listener.Close()
+		os.Remove(socketDirectory)
+		return "", nil, util.StatusWrapf(err, "Failed to change permissions of %#v", socketPath)
+	}
+
+	server := grpc.NewServer(
+		grpc.Creds(bb_grpc.PeerTransportCredentials),
+		grpc.MaxRecvMsgSize(maximumMessageSizeBytes),
+		grpc.MaxSendMsgSize(maximumMessageSizeBytes))
+	nestedExecutionServer := NewNestedExecutionServer(p.ClientConnection, instanceName, authenticationMetadata, p.UserID)
+	remoteexecution.RegisterActionCacheServer(server, nestedExecutionServer)
+	remoteexecution.RegisterCapabilitiesServer(server, nestedExecutionServer)
+	remoteexecution.RegisterContentAddressableStorageServer(server, nestedExecutionServer)
+	remoteexecution.RegisterExecutionServer(server, nestedExecutionServer)
+	bytestream.RegisterByteStreamServer(server, nestedExecutionServer)
+	go server.Serve(listener)
+
+	// Stopping the server closes the listener, which causes the
+	// socket to be removed.
+	return "unix://" + socketPath, func() {
+		server.Stop()
+		os.Remove(socketDirectory)
+	}, nil
+}
+
+// NestedExecutionServer is the set of gRPC services that is offered to
+// actions that schedule remote work themselves.
+type NestedExecutionServer interface {
+	remoteexecution.ActionCacheServer
+	remoteexecution.CapabilitiesServer
+	remoteexecution.ContentAddressableStorageServer
+	remoteexecution.ExecutionServer
+	bytestream.ByteStreamServer
+}
+
+type nestedExecutionServer struct {
+	actionCacheClient               remoteexecution.ActionCacheClient
+	capabilitiesClient              remoteexecution.CapabilitiesClient
+	contentAddressableStorageClient remoteexecution.ContentAddressableStorageClient
+	executionClient                 remoteexecution.ExecutionClient
+	byteStreamClient                bytestream.ByteStreamClient
+	instanceName                    digest.InstanceName
+	authenticationMetadata          *auth.AuthenticationMetadata
+	userID                          uint32
+
+	lock           sync.Mutex
+	operationNames map[string]struct{}
+}
+
+// NewNestedExecutionServer creates a gRPC server that forwards calls
+// made by an action to the cluster. Requests need to either use the
+// instance name of the action, or the empty instance name. In both
+// cases they are forwarded using the instance name of the action.
+//
+// Calls are only accepted if they are made over a UNIX socket by a
+// process running as the provided user ID. They are forwarded with the
+// provided authentication metadata attached to their context, so that
+// the client connection can derive credentials from it.
+func NewNestedExecutionServer(clientConnection grpc.ClientConnInterface, instanceName digest.InstanceName, authenticationMetadata *auth.AuthenticationMetadata, userID uint32) NestedExecutionServer {
+	return &nestedExecutionServer{
+		actionCacheClient:               remoteexecution.NewActionCacheClient(clientConnection),
+		capabilitiesClient:              remoteexecution.NewCapabilitiesClient(clientConnection),
+		contentAddressableStorageClient: remoteexecution.NewContentAddressableStorageClient(clientConnection),
+		executionClient:                 remoteexecution.NewExecutionClient(clientConnection),
+		byteStreamClient:                bytestream.NewByteStreamClient(clientConnection),
+		instanceName:                    instanceName,
+		authenticationMetadata:          authenticationMetadata,
+		userID:                          userID,
+		operationNames:                  map[string]struct{}{},
+	}
+}
+
+// getOutgoingContext checks that a call was made by a process running
+// as the user of the action. It returns the context that should be
+// used to forward the call, having the authentication metadata of the
+// client that requested execution of the action attached.
+func (s *nestedExecutionServer) getOutgoingContext(ctx context.Context) (context.Context, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Connection was not established using gRPC")
+	}
+	authInfo, ok := p.AuthInfo.(bb_grpc.PeerAuthInfo)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Connection was not established over a UNIX socket")
+	}
+	if authInfo.UID != s.userID {
+		return nil, status.Errorf(codes.PermissionDenied, "Connections are only permitted from user ID %d, while the peer has user ID %d", s.userID, authInfo.UID)
+	}
+	return auth.NewContextWithAuthenticationMetadata(ctx, s.authenticationMetadata), nil
+}
+
+// patchInstanceName validates the instance name provided by the
+// action, and returns the instance name against which the request
+// needs to be forwarded.
+func (s *nestedExecutionServer) patchInstanceName(instanceName string) (string, error) {
+	if allowedInstanceName := s.instanceName.String(); instanceName != "" && instanceName != allowedInstanceName {
+		return "", status.Errorf(codes.PermissionDenied, "Actions may only use instance name %#v", allowedInstanceName)
+	}
+	return s.instanceName.String(), nil
+}
+
+// patchResourceName replaces the instance name contained in a
+// ByteStream resource name with the instance name of the action.
+func (s *nestedExecutionServer) patchResourceName(resourceName string) (string, error) {
+	components := strings.FieldsFunc(resourceName, func(r rune) bool { return r == '/' })
+	for i, component := range components {
+		if component == "blobs" || component == "compressed-blobs" || component == "uploads" {
+			instanceName, err := s.patchInstanceName(strings.Join(components[:i], "/"))
+			if err != nil {
+				return "", err
+			}
+			if instanceName == "" {
+				return strings.Join(components[i:], "/"), nil
+			}
+			return instanceName + "/" + strings.Join(components[i:], "/"), nil
+		}
+	}
+	return "", status.Errorf(codes.InvalidArgument, "Invalid resource name %#v", resourceName)
+}
+
+func (s *nestedExecutionServer) GetActionResult(ctx context.Context, in *remoteexecution.GetActionResultRequest) (*remoteexecution.ActionResult, error) {
+	ctx, err := s.getOutgoingContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instanceName, err := s.patchInstanceName(in.InstanceName)
+	if err != nil {
+		return nil, err
+	}
+	in.InstanceName = instanceName
+	return s.actionCacheClient.GetActionResult(ctx, in)
+}
+
+func (s *nestedExecutionServer) UpdateActionResult(ctx context.Context, in *remoteexecution.UpdateActionResultRequest) (*remoteexecution.ActionResult, error) {
+	// Permitting this would allow actions to tamper with the
+	// results of other actions.
+	return nil, status.Error(codes.PermissionDenied, "Actions may not store results in the Action Cache")
+}
+
+func (s *nestedExecutionServer) GetCapabilities(ctx context.Context, in *remoteexecution.GetCapabilitiesRequest) (*remoteexecution.ServerCapabilities, error) {
+	ctx, err := s.getOutgoingContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instanceName, err := s.patchInstanceName(in.InstanceName)
+	if err != nil {
+		return nil, err
+	}
+	in.InstanceName = instanceName
+	return s.capabilitiesClient.GetCapabilities(ctx, in)
+}
+
+func (s *nestedExecutionServer) FindMissingBlobs(ctx context.Context, in *remoteexecution.FindMissingBlobsRequest) (*remoteexecution.FindMissingBlobsResponse, error) {
+	ctx, err := s.getOutgoingContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instanceName, err := s.patchInstanceName(in.InstanceName)
+	if err != nil {
+		return nil, err
+	}
+	in.InstanceName = instanceName
+	return s.contentAddressableStorageClient.FindMissingBlobs(ctx, in)
+}
+
+func (s *nestedExecutionServer) BatchUpdateBlobs(ctx context.Context, in *remoteexecution.BatchUpdateBlobsRequest) (*remoteexecution.BatchUpdateBlobsResponse, error) {
+	ctx, err := s.getOutgoingContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instanceName, err := s.patchInstanceName(in.InstanceName)
+	if err != nil {
+		return nil, err
+	}
+	in.InstanceName = instanceName
+	return s.contentAddressableStorageClient.BatchUpdateBlobs(ctx, in)
+}
+
+func (s *nestedExecutionServer) BatchReadBlobs(ctx context.Context, in *remoteexecution.BatchReadBlobsRequest) (*remoteexecution.BatchReadBlobsResponse, error) {
+	ctx, err := s.getOutgoingContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instanceName, err := s.patchInstanceName(in.InstanceName)
+	if err != nil {
+		return nil, err
+	}
+	in.InstanceName = instanceName
+	return s.contentAddressableStorageClient.BatchReadBlobs(ctx, in)
+}
+
+func (s *nestedExecutionServer) GetTree(in *remoteexecution.GetTreeRequest, out remoteexecution.ContentAddressableStorage_GetTreeServer) error {
+	ctx, err := s.getOutgoingContext(out.Context())
+	if err != nil {
+		return err
+	}
+	instanceName, err := s.patchInstanceName(in.InstanceName)
+	if err != nil {
+		return err
+	}
+	in.InstanceName = instanceName
+	client, err := s.contentAddressableStorageClient.GetTree(ctx, in)
+	if err != nil {
+		return err
+	}
+	for {
+		response, err := client.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := out.Send(response); err != nil {
+			return err
+		}
+	}
+}
+
+// forwardOperations relays the operations returned by Execute() and
+// WaitExecution() to the action. The names of the operations are
+// recorded, so that the action may wait for them afterwards.
+func (s *nestedExecutionServer) forwardOperations(client interface {
+	Recv() (*longrunningpb.Operation, error)
+}, out interface {
+	Send(*longrunningpb.Operation) error
+},
+) error {
+	for {
+		operation, err := client.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		s.lock.Lock()
+		s.operationNames[operation.Name] = struct{}{}
+		s.lock.Unlock()
+		if err := out.Send(operation); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *nestedExecutionServer) Execute(in *remoteexecution.ExecuteRequest, out remoteexecution.Execution_ExecuteServer) error {
+	ctx, err := s.getOutgoingContext(out.Context())
+	if err != nil {
+		return err
+	}
+	instanceName, err := s.patchInstanceName(in.InstanceName)
+	if err != nil {
+		return err
+	}
+	in.InstanceName = instanceName
+	client, err := s.executionClient.Execute(ctx, in)
+	if err != nil {
+		return err
+	}
+	return s.forwardOperations(client, out)
+}
+
+func (s *nestedExecutionServer) WaitExecution(in *remoteexecution.WaitExecutionRequest, out remoteexecution.Execution_WaitExecutionServer) error {
+	ctx, err := s.getOutgoingContext(out.Context())
+	if err != nil {
+		return err
+	}
+	// Operation names are not scoped to an instance name. Prevent
+	// actions from observing operations of other clients.
+	s.lock.Lock()
+	_, ok := s.operationNames[in.Name]
+	s.lock.Unlock()
+	if !ok {
+		return status.Error(codes.PermissionDenied, "Actions may only wait for operations they created")
+	}
+	client, err := s.executionClient.WaitExecution(ctx, in)
+	if err != nil {
+		return err
+	}
+	return s.forwardOperations(client, out)
+}
+
+func (s *nestedExecutionServer) Read(in *bytestream.ReadRequest, out bytestream.ByteStream_ReadServer) error {
+	ctx, err := s.getOutgoingContext(out.Context())
+	if err != nil {
+		return err
+	}
+	resourceName, err := s.patchResourceName(in.ResourceName)
+	if err != nil {
+		return err
+	}
+	in.ResourceName = resourceName
+	client, err := s.byteStreamClient.Read(ctx, in)
+	if err != nil {
+		return err
+	}
+	for {
+		response, err := client.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := out.Send(response); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *nestedExecutionServer) Write(out bytestream.ByteStream_WriteServer) error {
+	ctx, err := s.getOutgoingContext(out.Context())
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	client, err := s.byteStreamClient.Write(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		request, err := out.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		// Only the first request is required to contain the
+		// resource name, but later requests may repeat it.
+		if request.ResourceName != "" {
+			resourceName, err := s.patchResourceName(request.ResourceName)
+			if err != nil {
+				return err
+			}
+			request.ResourceName = resourceName
+		}
+		if err := client.Send(request); err == io.EOF {
+			// The server terminated the stream. The
+			// actual error is returned by CloseAndRecv().
+			break
+		} else if err != nil {
+			return err
+		}
+	}
+	response, err := client.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	return out.SendAndClose(response)
+}
+
+func (s *nestedExecutionServer) QueryWriteStatus(ctx context.Context, in *bytestream.QueryWriteStatusRequest) (*bytestream.QueryWriteStatusResponse, error) {
+	ctx, err := s.getOutgoingContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resourceName, err := s.patchResourceName(in.ResourceName)
+	if err != nil {
+		return nil, err
+	}
+	in.ResourceName = resourceName
+	return s.byteStreamClient.QueryWriteStatus(ctx, in)
+}
@@ -0,0 +1,235 @@
+package builder_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-storage/pkg/auth"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	bb_grpc "github.com/buildbarn/bb-storage/pkg/grpc"
+	auth_pb "github.com/buildbarn/bb-storage/pkg/proto/auth"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/genproto/googleapis/bytestream"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestNestedExecutionServer(t *testing.T) {
+	ctrl, baseCtx := gomock.WithContext(context.Background(), t)
+
+	// Calls should only be accepted if they originate from the user
+	// as which the action runs.
+	ctx := peer.NewContext(baseCtx, &peer.Peer{
+		AuthInfo: bb_grpc.PeerAuthInfo{UID: 1000},
+	})
+
+	conn := mock.NewMockClientConnInterface(ctrl)
+	authenticationMetadata := auth.MustNewAuthenticationMetadataFromProto(&auth_pb.AuthenticationMetadata{
+		Public: structpb.NewStringValue("alice"),
+	})
+	server := builder.NewNestedExecutionServer(conn, digest.MustNewInstanceName("hello/world"), authenticationMetadata, 1000)
+
+	blobDigest := &remoteexecution.Digest{
+		Hash:      "8b1a9953c4611296a827abf8c47804d7",
+		SizeBytes: 5,
+	}
+
+	t.Run("FindMissingBlobsNoPeer", func(t *testing.T) {
+		_, err := server.FindMissingBlobs(baseCtx, &remoteexecution.FindMissingBlobsRequest{
+			BlobDigests: []*remoteexecution.Digest{blobDigest},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Unauthenticated, "Connection was not established using gRPC"), err)
+	})
+
+	t.Run("FindMissingBlobsOtherUser", func(t *testing.T) {
+		// Other processes on the system should not be able to
+		// make calls on behalf of the action.
+		_, err := server.FindMissingBlobs(peer.NewContext(baseCtx, &peer.Peer{
+			AuthInfo: bb_grpc.PeerAuthInfo{UID: 1001},
+		}), &remoteexecution.FindMissingBlobsRequest{
+			BlobDigests: []*remoteexecution.Digest{blobDigest},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Connections are only permitted from user ID 1000, while the peer has user ID 1001"), err)
+	})
+
+	t.Run("FindMissingBlobsOtherInstanceName", func(t *testing.T) {
+		// Actions should not be able to access data stored
+		// under other instance names.
+		_, err := server.FindMissingBlobs(ctx, &remoteexecution.FindMissingBlobsRequest{
+			InstanceName: "other",
+			BlobDigests:  []*remoteexecution.Digest{blobDigest},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Actions may only use instance name \"hello/world\""), err)
+	})
+
+	t.Run("FindMissingBlobsSuccess", func(t *testing.T) {
+		// Requests using the empty instance name should be
+		// forwarded using the instance name of the action. The
+		// authentication metadata of the client that requested
+		// execution of the action should be attached.
+		conn.EXPECT().Invoke(gomock.Any(), "/build.bazel.remote.execution.v2.ContentAddressableStorage/FindMissingBlobs", gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+				require.Equal(t, authenticationMetadata, auth.AuthenticationMetadataFromContext(ctx))
+				testutil.RequireEqualProto(t, &remoteexecution.FindMissingBlobsRequest{
+					InstanceName: "hello/world",
+					BlobDigests:  []*remoteexecution.Digest{blobDigest},
+				}, args.(proto.Message))
+				proto.Merge(reply.(proto.Message), &remoteexecution.FindMissingBlobsResponse{
+					MissingBlobDigests: []*remoteexecution.Digest{blobDigest},
+				})
+				return nil
+			})
+
+		response, err := server.FindMissingBlobs(ctx, &remoteexecution.FindMissingBlobsRequest{
+			BlobDigests: []*remoteexecution.Digest{blobDigest},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteexecution.FindMissingBlobsResponse{
+			MissingBlobDigests: []*remoteexecution.Digest{blobDigest},
+		}, response)
+	})
+
+	t.Run("UpdateActionResult", func(t *testing.T) {
+		// Actions should not be able to tamper with the results
+		// of other actions.
+		_, err := server.UpdateActionResult(ctx, &remoteexecution.UpdateActionResultRequest{
+			InstanceName: "hello/world",
+			ActionDigest: blobDigest,
+			ActionResult: &remoteexecution.ActionResult{},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Actions may not store results in the Action Cache"), err)
+	})
+
+	t.Run("QueryWriteStatusOtherUser", func(t *testing.T) {
+		_, err := server.QueryWriteStatus(peer.NewContext(baseCtx, &peer.Peer{
+			AuthInfo: bb_grpc.PeerAuthInfo{UID: 1001},
+		}), &bytestream.QueryWriteStatusRequest{
+			ResourceName: "uploads/e3ad3f9d-2f4c-4b2e-9a0b-1c1d3e5f7a9b/blobs/8b1a9953c4611296a827abf8c47804d7/5",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Connections are only permitted from user ID 1000, while the peer has user ID 1001"), err)
+	})
+
+	t.Run("QueryWriteStatusInvalidResourceName", func(t *testing.T) {
+		_, err := server.QueryWriteStatus(ctx, &bytestream.QueryWriteStatusRequest{
+			ResourceName: "hello/world/8b1a9953c4611296a827abf8c47804d7/5",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid resource name \"hello/world/8b1a9953c4611296a827abf8c47804d7/5\""), err)
+	})
+
+	t.Run("QueryWriteStatusSuccess", func(t *testing.T) {
+		// The instance name should be inserted into the
+		// resource name.
+		conn.EXPECT().Invoke(gomock.Any(), "/google.bytestream.ByteStream/QueryWriteStatus", gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+				require.Equal(t, authenticationMetadata, auth.AuthenticationMetadataFromContext(ctx))
+				testutil.RequireEqualProto(t, &bytestream.QueryWriteStatusRequest{
+					ResourceName: "hello/world/uploads/e3ad3f9d-2f4c-4b2e-9a0b-1c1d3e5f7a9b/blobs/8b1a9953c4611296a827abf8c47804d7/5",
+				}, args.(proto.Message))
+				proto.Merge(reply.(proto.Message), &bytestream.QueryWriteStatusResponse{
+					CommittedSize: 5,
+					Complete:      true,
+				})
+				return nil
+			})
+
+		response, err := server.QueryWriteStatus(ctx, &bytestream.QueryWriteStatusRequest{
+			ResourceName: "uploads/e3ad3f9d-2f4c-4b2e-9a0b-1c1d3e5f7a9b/blobs/8b1a9953c4611296a827abf8c47804d7/5",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &bytestream.QueryWriteStatusResponse{
+			CommittedSize: 5,
+			Complete:      true,
+		}, response)
+	})
+
+	t.Run("ExecuteSuccess", func(t *testing.T) {
+		// Operations returned by the cluster should be relayed
+		// to the action.
+		clientStream := mock.NewMockClientStream(ctrl)
+		conn.EXPECT().NewStream(gomock.Any(), gomock.Any(), "/build.bazel.remote.execution.v2.Execution/Execute", gomock.Any()).
+			Return(clientStream, nil)
+		clientStream.EXPECT().SendMsg(gomock.Any()).DoAndReturn(func(m interface{}) error {
+			testutil.RequireEqualProto(t, &remoteexecution.ExecuteRequest{
+				InstanceName: "hello/world",
+				ActionDigest: blobDigest,
+			}, m.(proto.Message))
+			return nil
+		})
+		clientStream.EXPECT().CloseSend()
+		clientStream.EXPECT().RecvMsg(gomock.Any()).DoAndReturn(func(m interface{}) error {
+			proto.Merge(m.(proto.Message), &longrunningpb.Operation{
+				Name: "fd6ee599-dd97-4bd2-a1a9-c2b6b5dbab0e",
+			})
+			return nil
+		})
+		clientStream.EXPECT().RecvMsg(gomock.Any()).Return(io.EOF)
+
+		out := mock.NewMockExecution_ExecuteServer(ctrl)
+		out.EXPECT().Context().Return(ctx).AnyTimes()
+		out.EXPECT().Send(testutil.EqProto(t, &longrunningpb.Operation{
+			Name: "fd6ee599-dd97-4bd2-a1a9-c2b6b5dbab0e",
+		}))
+
+		require.NoError(t, server.Execute(&remoteexecution.ExecuteRequest{
+			InstanceName: "hello/world",
+			ActionDigest: blobDigest,
+		}, out))
+	})
+
+	t.Run("WaitExecutionOtherOperation", func(t *testing.T) {
+		// Actions should not be able to observe operations
+		// created by other clients.
+		out := mock.NewMockExecution_WaitExecutionServer(ctrl)
+		out.EXPECT().Context().Return(ctx).AnyTimes()
+
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Actions may only wait for operations they created"), server.WaitExecution(&remoteexecution.WaitExecutionRequest{
+			Name: "0b2b8d5e-5c7c-4e0b-9d3e-3c1e4f5a6b7c",
+		}, out))
+	})
+
+	t.Run("WaitExecutionSuccess", func(t *testing.T) {
+		// Waiting for the operation that was created by
+		// ExecuteSuccess should be permitted.
+		clientStream := mock.NewMockClientStream(ctrl)
+		conn.EXPECT().NewStream(gomock.Any(), gomock.Any(), "/build.bazel.remote.execution.v2.Execution/WaitExecution", gomock.Any()).
+			Return(clientStream, nil)
+		clientStream.EXPECT().SendMsg(gomock.Any()).DoAndReturn(func(m interface{}) error {
+			testutil.RequireEqualProto(t, &remoteexecution.WaitExecutionRequest{
+				Name: "fd6ee599-dd97-4bd2-a1a9-c2b6b5dbab0e",
+			}, m.(proto.Message))
+			return nil
+		})
+		clientStream.EXPECT().CloseSend()
+		clientStream.EXPECT().RecvMsg(gomock.Any()).DoAndReturn(func(m interface{}) error {
+			proto.Merge(m.(proto.Message), &longrunningpb.Operation{
+				Name: "fd6ee599-dd97-4bd2-a1a9-c2b6b5dbab0e",
+				Done: true,
+			})
+			return nil
+		})
+		clientStream.EXPECT().RecvMsg(gomock.Any()).Return(io.EOF)
+
+		out := mock.NewMockExecution_WaitExecutionServer(ctrl)
+		out.EXPECT().Context().Return(ctx).AnyTimes()
+		out.EXPECT().Send(testutil.EqProto(t, &longrunningpb.Operation{
+			Name: "fd6ee599-dd97-4bd2-a1a9-c2b6b5dbab0e",
+			Done: true,
+		}))
+
+		require.NoError(t, server.WaitExecution(&remoteexecution.WaitExecutionRequest{
+			Name: "fd6ee599-dd97-4bd2-a1a9-c2b6b5dbab0e",
+		}, out))
+	})
+}
@@ -2,10 +2,14 @@ package builder
 
 import (
 	"context"
+	"math"
 	"os"
 	"sort"
+	"strings"
 
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_clock "github.com/buildbarn/bb-remote-execution/pkg/clock"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
 	"github.com/buildbarn/bb-storage/pkg/blobstore"
 	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
 	"github.com/buildbarn/bb-storage/pkg/digest"
@@ -107,75 +111,122 @@ func (on *outputNode) createParentDirectories(d ParentPopulatableDirectory, dPat
 	return nil
 }
 
-// UploadOutputs is recursively invoked by
-// OutputHierarchy.UploadOutputs() to upload output directories and
-// files from the locations where they are expected.
-func (on *outputNode) uploadOutputs(s *uploadOutputsState, d UploadableDirectory, dPath *path.Trace) {
-	// Upload REv2.0 output directories that are expected to be
-	// present in this directory.
-	for _, component := range sortToUpload(on.directoriesToUpload) {
-		childPath := dPath.Append(component)
-		paths := on.directoriesToUpload[component]
-		if fileInfo, err := d.Lstat(component); err == nil {
-			switch fileType := fileInfo.Type(); fileType {
-			case filesystem.FileTypeDirectory:
-				s.uploadOutputDirectory(d, component, childPath, paths)
-			case filesystem.FileTypeSymlink:
-				s.uploadOutputSymlink(d, component, childPath, &s.actionResult.OutputDirectorySymlinks, paths)
-			default:
-				s.saveError(status.Errorf(codes.InvalidArgument, "Output directory %#v is not a directory or symlink", childPath.String()))
-			}
-		} else if !os.IsNotExist(err) {
-			s.saveError(util.StatusWrapf(err, "Failed to read attributes of output directory %#v", childPath.String()))
-		}
+// outputKind describes how outputs declared through one of the fields
+// of the Command message are uploaded. Each kind of output permits a
+// different set of file types to be present at the output's location.
+type outputKind struct {
+	name              string
+	allowDirectories  bool
+	allowRegularFiles bool
+	getOutputSymlinks func(actionResult *remoteexecution.ActionResult) *[]*remoteexecution.OutputSymlink
+	invalidTypeError  string
+}
+
+var (
+	// REv2.0 output directories, declared through Command's
+	// 'output_directories' field.
+	outputKindDirectory = outputKind{
+		name:             "output directory",
+		allowDirectories: true,
+		getOutputSymlinks: func(actionResult *remoteexecution.ActionResult) *[]*remoteexecution.OutputSymlink {
+			return &actionResult.OutputDirectorySymlinks
+		},
+		invalidTypeError: "Output directory %#v is not a directory or symlink",
+	}
+	// REv2.0 output files, declared through Command's
+	// 'output_files' field.
+	outputKindFile = outputKind{
+		name:              "output file",
+		allowRegularFiles: true,
+		getOutputSymlinks: func(actionResult *remoteexecution.ActionResult) *[]*remoteexecution.OutputSymlink {
+			return &actionResult.OutputFileSymlinks
+		},
+		invalidTypeError: "Output file %#v is not a regular file or symlink",
+	}
+	// REv2.1 output paths, declared through Command's
+	// 'output_paths' field. The type of the output is only
+	// determined after execution.
+	outputKindPath = outputKind{
+		name:              "output path",
+		allowDirectories:  true,
+		allowRegularFiles: true,
+		getOutputSymlinks: func(actionResult *remoteexecution.ActionResult) *[]*remoteexecution.OutputSymlink {
+			return &actionResult.OutputSymlinks
+		},
+		invalidTypeError: "Output path %#v is not a directory, regular file or symlink",
 	}
+)
 
-	// Upload REv2.0 output files that are expected to be present in
-	// this directory.
-	for _, component := range sortToUpload(on.filesToUpload) {
+// uploadOutputsOfKind uploads all outputs of a given kind that are
+// expected to be present in a directory. Depending on the type of file
+// that is found at each location, it is uploaded as a directory,
+// regular file or symbolic link.
+func (on *outputNode) uploadOutputsOfKind(s *uploadOutputsState, d UploadableDirectory, dPath *path.Trace, toUpload map[path.Component][]string, kind *outputKind, modifiedChildren map[path.Component]bool) {
+	for _, component := range sortToUpload(toUpload) {
+		if _, ok := modifiedChildren[component]; modifiedChildren != nil && !ok {
+			// Output was not modified by the build action.
+			continue
+		}
 		childPath := dPath.Append(component)
-		paths := on.filesToUpload[component]
-		if fileInfo, err := d.Lstat(component); err == nil {
-			switch fileType := fileInfo.Type(); fileType {
-			case filesystem.FileTypeRegularFile:
-				s.uploadOutputFile(d, component, childPath, fileInfo.IsExecutable(), paths)
-			case filesystem.FileTypeSymlink:
-				s.uploadOutputSymlink(d, component, childPath, &s.actionResult.OutputFileSymlinks, paths)
-			default:
-				s.saveError(status.Errorf(codes.InvalidArgument, "Output file %#v is not a regular file or symlink", childPath.String()))
+		paths := toUpload[component]
+		fileInfo, err := d.Lstat(component)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				s.saveError(util.StatusWrapf(err, "Failed to read attributes of %s %#v", kind.name, childPath.String()))
 			}
-		} else if !os.IsNotExist(err) {
-			s.saveError(util.StatusWrapf(err, "Failed to read attributes of output file %#v", childPath.String()))
+			continue
+		}
+		switch fileType := fileInfo.Type(); {
+		case fileType == filesystem.FileTypeDirectory && kind.allowDirectories:
+			s.uploadOutputDirectory(d, component, childPath, paths)
+		case fileType == filesystem.FileTypeRegularFile && kind.allowRegularFiles:
+			s.uploadOutputFile(d, component, childPath, fileInfo.IsExecutable(), paths)
+		case fileType == filesystem.FileTypeSymlink:
+			s.uploadOutputSymlink(d, component, childPath, kind.getOutputSymlinks(s.actionResult), paths)
+		default:
+			s.saveError(status.Errorf(codes.InvalidArgument, kind.invalidTypeError, childPath.String()))
 		}
 	}
+}
 
-	// Upload REv2.1 output paths that are expected to be present in
-	// this directory.
-	for _, component := range sortToUpload(on.pathsToUpload) {
-		childPath := dPath.Append(component)
-		paths := on.pathsToUpload[component]
-		if fileInfo, err := d.Lstat(component); err == nil {
-			switch fileType := fileInfo.Type(); fileType {
-			case filesystem.FileTypeDirectory:
-				s.uploadOutputDirectory(d, component, childPath, paths)
-			case filesystem.FileTypeRegularFile:
-				s.uploadOutputFile(d, component, childPath, fileInfo.IsExecutable(), paths)
-			case filesystem.FileTypeSymlink:
-				s.uploadOutputSymlink(d, component, childPath, &s.actionResult.OutputSymlinks, paths)
-			default:
-				s.saveError(status.Errorf(codes.InvalidArgument, "Output path %#v is not a directory, regular file or symlink", childPath.String()))
-			}
-		} else if !os.IsNotExist(err) {
-			s.saveError(util.StatusWrapf(err, "Failed to read attributes of output path %#v", childPath.String()))
+// UploadOutputs is recursively invoked by
+// OutputHierarchy.UploadOutputs() to upload output directories and
+// files from the locations where they are expected.
+//
+// If the directory keeps track of which children were modified by the
+// build action, outputs that were left untouched are skipped. The
+// allModified flag is set when the directory itself was created by the
+// build action, meaning that all of its children need to be inspected.
+func (on *outputNode) uploadOutputs(s *uploadOutputsState, d UploadableDirectory, dPath *path.Trace, allModified bool) {
+	var modifiedChildren map[path.Component]bool
+	if dTracking, ok := d.(ModificationTrackingDirectory); ok && !allModified {
+		var err error
+		modifiedChildren, err = dTracking.LookupModifiedChildren()
+		if err != nil {
+			s.saveError(util.StatusWrapf(err, "Failed to look up modified children of output parent directory %#v", dPath.String()))
+			return
 		}
 	}
 
+	on.uploadOutputsOfKind(s, d, dPath, on.directoriesToUpload, &outputKindDirectory, modifiedChildren)
+	on.uploadOutputsOfKind(s, d, dPath, on.filesToUpload, &outputKindFile, modifiedChildren)
+	on.uploadOutputsOfKind(s, d, dPath, on.pathsToUpload, &outputKindPath, modifiedChildren)
+
 	// Traverse into subdirectories.
 	for _, component := range on.getSubdirectoryNames() {
+		childAllModified := allModified
+		if modifiedChildren != nil {
+			created, ok := modifiedChildren[component]
+			if !ok {
+				// Directory does not contain any outputs.
+				continue
+			}
+			childAllModified = created
+		}
 		childPath := dPath.Append(component)
 		childNode := on.subdirectories[component]
 		if childDirectory, err := d.EnterUploadableDirectory(component); err == nil {
-			childNode.uploadOutputs(s, childDirectory, childPath)
+			childNode.uploadOutputs(s, childDirectory, childPath, childAllModified)
 			childDirectory.Close()
 		} else if !os.IsNotExist(err) {
 			s.saveError(util.StatusWrapf(err, "Failed to enter output parent directory %#v", childPath.String()))
@@ -189,15 +240,51 @@ type uploadOutputsState struct {
 	context                   context.Context
 	contentAddressableStorage blobstore.BlobAccess
 	digestFunction            digest.Function
+	filePool                  re_filesystem.FilePool
 	actionResult              *remoteexecution.ActionResult
 	uploadTreesAndDirectories bool
+	outputNodeProperties      outputNodeProperties
+	outputPruner              *OutputPruner
 
 	firstError error
 }
 
+// getNodeProperties returns the properties of a file that the client
+// requested through the Command's output_node_properties field. If no
+// properties were requested, this function returns nil without
+// accessing the file.
+func (s *uploadOutputsState) getNodeProperties(d UploadableDirectory, name path.Component) (*remoteexecution.NodeProperties, error) {
+	if !s.outputNodeProperties.mtime && !s.outputNodeProperties.unixMode && !s.outputNodeProperties.xattrs {
+		return nil, nil
+	}
+	nodeProperties, err := d.GetNodeProperties(s.context, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only return the properties that were requested.
+	filteredNodeProperties := &remoteexecution.NodeProperties{}
+	if s.outputNodeProperties.mtime {
+		filteredNodeProperties.Mtime = nodeProperties.Mtime
+	}
+	if s.outputNodeProperties.unixMode {
+		filteredNodeProperties.UnixMode = nodeProperties.UnixMode
+	}
+	if s.outputNodeProperties.xattrs {
+		for _, property := range nodeProperties.Properties {
+			if strings.HasPrefix(property.Name, xattrNodePropertyPrefix) {
+				filteredNodeProperties.Properties = append(filteredNodeProperties.Properties, property)
+			}
+		}
+	}
+	return filteredNodeProperties, nil
+}
+
 // computeDigest computes the digest of a byte slice, using the digest
 // function that's also used by the client.
 func (s *uploadOutputsState) computeDigest(data []byte) digest.Digest {
+	defer re_clock.StartPhase(s.context, re_clock.PhaseOutputHashing).Finish()
+
 	digestGenerator := s.digestFunction.NewGenerator(int64(len(data)))
 	if _, err := digestGenerator.Write(data); err != nil {
 		panic(err)
@@ -217,72 +304,61 @@ func (s *uploadOutputsState) saveError(err error) {
 // UploadOutputDirectoryEntered is called to upload a single output
 // directory as a remoteexecution.Tree. The root directory is assumed to
 // already be opened.
+//
+// Output directories may contain a large number of files. To prevent
+// the size of the resulting Tree object from causing excessive memory
+// usage, Directory messages are spilled to a temporary file as they
+// are generated. Once all Directory messages have been generated, the
+// Tree object is assembled in a second temporary file, from which it
+// is uploaded.
 func (s *uploadOutputsState) uploadOutputDirectoryEntered(d UploadableDirectory, dPath *path.Trace, paths []string) {
+	directoriesFile, err := s.filePool.NewFile()
+	if err != nil {
+		s.saveError(util.StatusWrapf(err, "Failed to create temporary file for output directory %#v", dPath.String()))
+		return
+	}
 	dState := uploadOutputDirectoryState{
 		uploadOutputsState: s,
-		directoriesSeen:    map[digest.Digest][]byte{},
-	}
-	if rootDirectoryDigest, err := dState.uploadDirectory(d, dPath); err == nil {
-		// Approximate the size of the resulting Tree object, so
-		// that we may allocate all space at once.
-		directories := dState.directories
-		maximumTreeSizeBytes := 0
-		for _, directory := range directories {
-			maximumTreeSizeBytes += len(directory)
-		}
-		maximumTreeSizeBytes += len(directories) * (1 + protowire.SizeVarint(uint64(maximumTreeSizeBytes)))
-
-		// Construct the Tree object. We don't want to use
-		// proto.Marshal() for this, as it would require us to
-		// marshal all of the directories a second time.
-		treeData := make([]byte, 0, maximumTreeSizeBytes)
-		tag := byte(blobstore.TreeRootFieldNumber<<3) | byte(protowire.BytesType)
-		for i := len(directories); i > 0; i-- {
-			directory := directories[i-1]
-			treeData = append(treeData, tag)
-			treeData = protowire.AppendVarint(treeData, uint64(len(directory)))
-			treeData = append(treeData, directory...)
-			tag = byte(blobstore.TreeChildrenFieldNumber<<3) | byte(protowire.BytesType)
-		}
-
-		// Always upload the directory in Tree form, even if the
-		// client did not request it. CompletenessCheckingBlobAccess
-		// depends on it to work efficiently.
-		successfullyUploaded := true
-		treeDigest := s.computeDigest(treeData)
-		if err := s.contentAddressableStorage.Put(s.context, treeDigest, buffer.NewValidatedBufferFromByteSlice(treeData)); err != nil {
-			s.saveError(util.StatusWrapf(err, "Failed to store output directory %#v", dPath.String()))
-			successfullyUploaded = false
-		}
+		directoriesFile:    directoriesFile,
+		directoriesSeen:    map[digest.Digest]struct{}{},
+	}
+	rootDirectoryDigest, err := dState.uploadDirectory(d, dPath)
+	if err != nil {
+		directoriesFile.Close()
+		s.saveError(err)
+		return
+	}
+	treeDigest, treeFile, treeSizeBytes, err := dState.buildTree()
+	directoriesFile.Close()
+	if err != nil {
+		s.saveError(util.StatusWrapf(err, "Failed to build tree for output directory %#v", dPath.String()))
+		return
+	}
 
-		// Upload Directory messages if requested by the client.
-		// Only in this case may we set OutputDirectory's
-		// root_directory_digest.
-		var rootDirectoryDigestProto *remoteexecution.Digest
-		if s.uploadTreesAndDirectories {
-			rootDirectoryDigestProto = rootDirectoryDigest.GetProto()
-			for directoryDigest, directory := range dState.directoriesSeen {
-				if err := s.contentAddressableStorage.Put(s.context, directoryDigest, buffer.NewValidatedBufferFromByteSlice(directory)); err != nil {
-					s.saveError(util.StatusWrapf(err, "Failed to store output directory %#v", dPath.String()))
-					successfullyUploaded = false
-				}
-			}
-		}
+	// Always upload the directory in Tree form, even if the client
+	// did not request it. CompletenessCheckingBlobAccess depends on
+	// it to work efficiently.
+	if err := s.contentAddressableStorage.Put(s.context, treeDigest, buffer.NewValidatedBufferFromReaderAt(treeFile, treeSizeBytes)); err != nil {
+		s.saveError(util.StatusWrapf(err, "Failed to store output directory %#v", dPath.String()))
+		return
+	}
 
-		if successfullyUploaded {
-			for _, path := range paths {
-				s.actionResult.OutputDirectories = append(
-					s.actionResult.OutputDirectories,
-					&remoteexecution.OutputDirectory{
-						Path:                  path,
-						TreeDigest:            treeDigest.GetProto(),
-						IsTopologicallySorted: true,
-						RootDirectoryDigest:   rootDirectoryDigestProto,
-					})
-			}
-		}
-	} else {
-		s.saveError(err)
+	// Directory messages have only been uploaded if requested by
+	// the client. Only in this case may we set OutputDirectory's
+	// root_directory_digest.
+	var rootDirectoryDigestProto *remoteexecution.Digest
+	if s.uploadTreesAndDirectories {
+		rootDirectoryDigestProto = rootDirectoryDigest.GetProto()
+	}
+	for _, path := range paths {
+		s.actionResult.OutputDirectories = append(
+			s.actionResult.OutputDirectories,
+			&remoteexecution.OutputDirectory{
+				Path:                  path,
+				TreeDigest:            treeDigest.GetProto(),
+				IsTopologicallySorted: true,
+				RootDirectoryDigest:   rootDirectoryDigestProto,
+			})
 	}
 }
 
@@ -300,18 +376,25 @@ func (s *uploadOutputsState) uploadOutputDirectory(d UploadableDirectory, name p
 
 // UploadOutputDirectory is called to upload a single output file.
 func (s *uploadOutputsState) uploadOutputFile(d UploadableDirectory, name path.Component, childPath *path.Trace, isExecutable bool, paths []string) {
-	if digest, err := d.UploadFile(s.context, name, s.digestFunction); err == nil {
-		for _, path := range paths {
-			s.actionResult.OutputFiles = append(
-				s.actionResult.OutputFiles,
-				&remoteexecution.OutputFile{
-					Path:         path,
-					Digest:       digest.GetProto(),
-					IsExecutable: isExecutable,
-				})
-		}
-	} else {
+	digest, err := d.UploadFile(s.context, name, s.digestFunction)
+	if err != nil {
 		s.saveError(util.StatusWrapf(err, "Failed to store output file %#v", childPath.String()))
+		return
+	}
+	nodeProperties, err := s.getNodeProperties(d, name)
+	if err != nil {
+		s.saveError(util.StatusWrapf(err, "Failed to obtain properties of output file %#v", childPath.String()))
+		return
+	}
+	for _, path := range paths {
+		s.actionResult.OutputFiles = append(
+			s.actionResult.OutputFiles,
+			&remoteexecution.OutputFile{
+				Path:           path,
+				Digest:         digest.GetProto(),
+				IsExecutable:   isExecutable,
+				NodeProperties: nodeProperties,
+			})
 	}
 }
 
@@ -337,8 +420,17 @@ func (s *uploadOutputsState) uploadOutputSymlink(d UploadableDirectory, name pat
 type uploadOutputDirectoryState struct {
 	*uploadOutputsState
 
-	directories     [][]byte
-	directoriesSeen map[digest.Digest][]byte
+	directoriesFile          filesystem.FileReadWriter
+	directoriesFileSizeBytes int64
+	directories              []directoryExtent
+	directoriesSeen          map[digest.Digest]struct{}
+}
+
+// directoryExtent is the location of a marshaled Directory message in
+// the temporary file used by uploadOutputDirectoryState.
+type directoryExtent struct {
+	offsetBytes int64
+	sizeBytes   int
 }
 
 // UploadDirectory is called to upload a single directory. Elements in
@@ -353,17 +445,23 @@ func (s *uploadOutputDirectoryState) uploadDirectory(d UploadableDirectory, dPat
 	var directory remoteexecution.Directory
 	for _, file := range files {
 		name := file.Name()
+		if s.outputPruner.ShouldPrune(name) {
+			continue
+		}
 		childPath := dPath.Append(name)
 		switch fileType := file.Type(); fileType {
 		case filesystem.FileTypeRegularFile:
-			if childDigest, err := d.UploadFile(s.context, name, s.digestFunction); err == nil {
+			if childDigest, err := d.UploadFile(s.context, name, s.digestFunction); err != nil {
+				s.saveError(util.StatusWrapf(err, "Failed to store output file %#v", childPath.String()))
+			} else if nodeProperties, err := s.getNodeProperties(d, name); err != nil {
+				s.saveError(util.StatusWrapf(err, "Failed to obtain properties of output file %#v", childPath.String()))
+			} else {
 				directory.Files = append(directory.Files, &remoteexecution.FileNode{
-					Name:         name.String(),
-					Digest:       childDigest.GetProto(),
-					IsExecutable: file.IsExecutable(),
+					Name:           name.String(),
+					Digest:         childDigest.GetProto(),
+					IsExecutable:   file.IsExecutable(),
+					NodeProperties: nodeProperties,
 				})
-			} else {
-				s.saveError(util.StatusWrapf(err, "Failed to store output file %#v", childPath.String()))
 			}
 		case filesystem.FileTypeDirectory:
 			if childDirectory, err := d.EnterUploadableDirectory(name); err == nil {
@@ -399,12 +497,70 @@ func (s *uploadOutputDirectoryState) uploadDirectory(d UploadableDirectory, dPat
 
 	// There is no need to make the directory part of the Tree if we
 	// have seen an identical directory previously.
-	digest := s.computeDigest(data)
-	if _, ok := s.directoriesSeen[digest]; !ok {
-		s.directories = append(s.directories, data)
-		s.directoriesSeen[digest] = data
+	directoryDigest := s.computeDigest(data)
+	if _, ok := s.directoriesSeen[directoryDigest]; !ok {
+		s.directoriesSeen[directoryDigest] = struct{}{}
+
+		// Upload Directory messages if requested by the client.
+		if s.uploadTreesAndDirectories {
+			if err := s.contentAddressableStorage.Put(s.context, directoryDigest, buffer.NewValidatedBufferFromByteSlice(data)); err != nil {
+				return digest.BadDigest, util.StatusWrapf(err, "Failed to store output directory %#v", dPath.String())
+			}
+		}
+
+		if _, err := s.directoriesFile.WriteAt(data, s.directoriesFileSizeBytes); err != nil {
+			return digest.BadDigest, util.StatusWrapf(err, "Failed to write output directory %#v to temporary file", dPath.String())
+		}
+		s.directories = append(s.directories, directoryExtent{
+			offsetBytes: s.directoriesFileSizeBytes,
+			sizeBytes:   len(data),
+		})
+		s.directoriesFileSizeBytes += int64(len(data))
+	}
+	return directoryDigest, nil
+}
+
+// buildTree constructs a remoteexecution.Tree object from the Directory
+// messages that were spilled to the temporary file. Directories were
+// generated in post-order, meaning that they need to be emitted in
+// reverse order to obtain a topologically sorted Tree, having the root
+// directory stored first.
+//
+// We don't want to use proto.Marshal() for this, as it would require
+// us to hold all directories in memory and marshal them a second time.
+func (s *uploadOutputDirectoryState) buildTree() (digest.Digest, filesystem.FileReadWriter, int64, error) {
+	treeFile, err := s.filePool.NewFile()
+	if err != nil {
+		return digest.BadDigest, nil, 0, util.StatusWrap(err, "Failed to create temporary file")
 	}
-	return digest, nil
+
+	digestGenerator := s.digestFunction.NewGenerator(math.MaxInt64)
+	treeSizeBytes := int64(0)
+	var entry []byte
+	tag := byte(blobstore.TreeRootFieldNumber<<3) | byte(protowire.BytesType)
+	for i := len(s.directories); i > 0; i-- {
+		directory := s.directories[i-1]
+		entry = protowire.AppendVarint(append(entry[:0], tag), uint64(directory.sizeBytes))
+		headerSizeBytes := len(entry)
+		if cap(entry) < headerSizeBytes+directory.sizeBytes {
+			entry = append(make([]byte, 0, headerSizeBytes+directory.sizeBytes), entry...)
+		}
+		entry = entry[:headerSizeBytes+directory.sizeBytes]
+		if n, err := s.directoriesFile.ReadAt(entry[headerSizeBytes:], directory.offsetBytes); n != directory.sizeBytes {
+			treeFile.Close()
+			return digest.BadDigest, nil, 0, util.StatusWrap(err, "Failed to read directory from temporary file")
+		}
+		if _, err := treeFile.WriteAt(entry, treeSizeBytes); err != nil {
+			treeFile.Close()
+			return digest.BadDigest, nil, 0, util.StatusWrap(err, "Failed to write directory to temporary file")
+		}
+		if _, err := digestGenerator.Write(entry); err != nil {
+			panic(err)
+		}
+		treeSizeBytes += int64(len(entry))
+		tag = byte(blobstore.TreeChildrenFieldNumber<<3) | byte(protowire.BytesType)
+	}
+	return digestGenerator.Sum(), treeFile, treeSizeBytes, nil
 }
 
 // outputNodePath is an implementation of path.ComponentWalker that is
@@ -451,8 +607,24 @@ type OutputHierarchy struct {
 	root                      outputNode
 	rootsToUpload             []string
 	uploadTreesAndDirectories bool
+	outputNodeProperties      outputNodeProperties
 }
 
+// outputNodeProperties contains the set of properties that the client
+// requested to be attached to output files, through the Command's
+// output_node_properties field.
+type outputNodeProperties struct {
+	mtime    bool
+	unixMode bool
+	xattrs   bool
+}
+
+// xattrNodePropertyPrefix is the prefix of the names of node
+// properties that contain extended attributes of output files. As
+// values of extended attributes may contain arbitrary binary data, they
+// are stored in base64 encoded form.
+const xattrNodePropertyPrefix = "xattr."
+
 // NewOutputHierarchy creates a new OutputHierarchy that uses the
 // working directory and the output paths specified in an REv2 Command
 // message.
@@ -468,6 +640,20 @@ func NewOutputHierarchy(command *remoteexecution.Command) (*OutputHierarchy, err
 			command.OutputDirectoryFormat == remoteexecution.Command_TREE_AND_DIRECTORY,
 	}
 
+	// REv2 requires that unrecognized node properties are rejected.
+	for _, nodeProperty := range command.OutputNodeProperties {
+		switch nodeProperty {
+		case "mtime":
+			oh.outputNodeProperties.mtime = true
+		case "unix_mode":
+			oh.outputNodeProperties.unixMode = true
+		case "xattrs":
+			oh.outputNodeProperties.xattrs = true
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "Unsupported output node property %#v", nodeProperty)
+		}
+	}
+
 	if len(command.OutputPaths) == 0 {
 		// Register REv2.0 output directories.
 		for _, outputDirectory := range command.OutputDirectories {
@@ -491,8 +677,20 @@ func NewOutputHierarchy(command *remoteexecution.Command) (*OutputHierarchy, err
 			}
 		}
 	} else {
-		// Register REv2.1 output paths.
+		// Register REv2.1 output paths. REv2 requires that
+		// clients deduplicate output paths. Be lenient and
+		// ignore duplicates, as opposed to reporting the same
+		// output multiple times.
+		outputPathsSeen := make(map[string]struct{}, len(command.OutputPaths))
 		for _, outputPath := range command.OutputPaths {
+			if _, ok := outputPathsSeen[outputPath]; ok {
+				continue
+			}
+			outputPathsSeen[outputPath] = struct{}{}
+
+			if strings.HasSuffix(outputPath, "/") {
+				return nil, status.Errorf(codes.InvalidArgument, "Output path %#v has a trailing slash", outputPath)
+			}
 			if on, name, err := oh.lookup(workingDirectory, outputPath); err != nil {
 				return nil, util.StatusWrapf(err, "Invalid output path %#v", outputPath)
 			} else if on == nil {
@@ -550,19 +748,38 @@ func (oh *OutputHierarchy) CreateParentDirectories(d ParentPopulatableDirectory)
 }
 
 // UploadOutputs uploads outputs of the build action into the CAS. This
-// function is called after executing the build action.
-func (oh *OutputHierarchy) UploadOutputs(ctx context.Context, d UploadableDirectory, contentAddressableStorage blobstore.BlobAccess, digestFunction digest.Function, actionResult *remoteexecution.ActionResult, forceUploadTreesAndDirectories bool) error {
+// function is called after executing the build action. The FilePool
+// is used to allocate temporary files in which Tree objects of output
+// directories are constructed. Entries inside output directories that
+// match the OutputPruner are omitted. If the outputs exceed the
+// OutputLimits or don't conform to the OutputPathValidation, none of
+// them are uploaded.
+func (oh *OutputHierarchy) UploadOutputs(ctx context.Context, d UploadableDirectory, contentAddressableStorage blobstore.BlobAccess, digestFunction digest.Function, filePool re_filesystem.FilePool, actionResult *remoteexecution.ActionResult, forceUploadTreesAndDirectories bool, outputPruner *OutputPruner, outputLimits *OutputLimits, outputPathValidation *OutputPathValidation) error {
+	if outputLimits != nil {
+		if err := outputLimits.check(oh.measureOutputs(ctx, d, outputPruner)); err != nil {
+			return err
+		}
+	}
+	if outputPathValidation != nil {
+		if err := oh.validateOutputPaths(d, outputPruner, outputPathValidation); err != nil {
+			return err
+		}
+	}
+
 	s := uploadOutputsState{
 		context:                   ctx,
 		contentAddressableStorage: contentAddressableStorage,
 		digestFunction:            digestFunction,
+		filePool:                  filePool,
 		actionResult:              actionResult,
 		uploadTreesAndDirectories: oh.uploadTreesAndDirectories || forceUploadTreesAndDirectories,
+		outputNodeProperties:      oh.outputNodeProperties,
+		outputPruner:              outputPruner,
 	}
 
 	if len(oh.rootsToUpload) > 0 {
 		s.uploadOutputDirectoryEntered(d, nil, oh.rootsToUpload)
 	}
-	oh.root.uploadOutputs(&s, d, nil)
+	oh.root.uploadOutputs(&s, d, nil, false)
 	return s.firstError
 }
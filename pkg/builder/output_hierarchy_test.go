@@ -9,6 +9,7 @@ import (
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-remote-execution/internal/mock"
 	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
 	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/filesystem"
@@ -19,6 +20,8 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestOutputHierarchyCreation(t *testing.T) {
@@ -59,6 +62,24 @@ func TestOutputHierarchyCreation(t *testing.T) {
 		})
 		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output file \"..\" resolves to the input root directory"), err)
 	})
+
+	t.Run("UnsupportedOutputNodeProperty", func(t *testing.T) {
+		_, err := builder.NewOutputHierarchy(&remoteexecution.Command{
+			WorkingDirectory:     "hello",
+			OutputNodeProperties: []string{"mtime", "owner"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Unsupported output node property \"owner\""), err)
+	})
+
+	t.Run("OutputPathTrailingSlash", func(t *testing.T) {
+		// REv2.1 explicitly forbids output paths from having a
+		// trailing slash.
+		_, err := builder.NewOutputHierarchy(&remoteexecution.Command{
+			WorkingDirectory: "hello",
+			OutputPaths:      []string{"world/"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output path \"world/\" has a trailing slash"), err)
+	})
 }
 
 func TestOutputHierarchyCreateParentDirectories(t *testing.T) {
@@ -257,8 +278,12 @@ func TestOutputHierarchyUploadOutputs(t *testing.T) {
 				root,
 				contentAddressableStorage,
 				digestFunction,
+				re_filesystem.InMemoryFilePool,
 				&actionResult,
-				/* forceUploadTreesAndDirectories = */ false))
+				/* forceUploadTreesAndDirectories = */ false,
+				/* outputPruner = */ nil,
+				/* outputLimits = */ nil,
+				/* outputPathValidation = */ nil))
 		require.Equal(t, remoteexecution.ActionResult{}, actionResult)
 	})
 
@@ -398,8 +423,12 @@ func TestOutputHierarchyUploadOutputs(t *testing.T) {
 				root,
 				contentAddressableStorage,
 				digestFunction,
+				re_filesystem.InMemoryFilePool,
 				&actionResult,
-				/* forceUploadTreesAndDirectories = */ false))
+				/* forceUploadTreesAndDirectories = */ false,
+				/* outputPruner = */ nil,
+				/* outputLimits = */ nil,
+				/* outputPathValidation = */ nil))
 		require.Equal(t, expectedResult, actionResult)
 	}
 
@@ -752,8 +781,12 @@ func TestOutputHierarchyUploadOutputs(t *testing.T) {
 				root,
 				contentAddressableStorage,
 				digestFunction,
+				re_filesystem.InMemoryFilePool,
 				&actionResult,
-				/* forceUploadTreesAndDirectories = */ false))
+				/* forceUploadTreesAndDirectories = */ false,
+				/* outputPruner = */ nil,
+				/* outputLimits = */ nil,
+				/* outputPathValidation = */ nil))
 		require.Equal(t, remoteexecution.ActionResult{
 			OutputDirectories: []*remoteexecution.OutputDirectory{
 				{
@@ -768,6 +801,58 @@ func TestOutputHierarchyUploadOutputs(t *testing.T) {
 		}, actionResult)
 	})
 
+	t.Run("DigestFunctions", func(t *testing.T) {
+		// Trees of output directories should be hashed using
+		// the digest function of the action, which may also be
+		// a tree hashing digest function.
+		for _, treeDigest := range []digest.Digest{
+			digest.MustNewDigest("example", remoteexecution.DigestFunction_SHA256TREE, "102b51b9765a56a3e899f7cf0ee38e5251f9c503b357b330a49183eb7b155604", 2),
+			digest.MustNewDigest("example", remoteexecution.DigestFunction_BLAKE3, "1f689a456b251eaf4c2f1e236ad982e0bee3a54470f6a00cf2812b809535b140", 2),
+		} {
+			t.Run(treeDigest.GetDigestFunction().GetEnumValue().String(), func(t *testing.T) {
+				root.EXPECT().ReadDir().Return(nil, nil)
+				contentAddressableStorage.EXPECT().Put(ctx, treeDigest, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+						m, err := b.ToProto(&remoteexecution.Tree{}, 10000)
+						require.NoError(t, err)
+						testutil.RequireEqualProto(t, &remoteexecution.Tree{
+							Root: &remoteexecution.Directory{},
+						}, m)
+						return nil
+					})
+
+				oh, err := builder.NewOutputHierarchy(&remoteexecution.Command{
+					WorkingDirectory:  "foo",
+					OutputDirectories: []string{".."},
+				})
+				require.NoError(t, err)
+				var actionResult remoteexecution.ActionResult
+				require.NoError(
+					t,
+					oh.UploadOutputs(
+						ctx,
+						root,
+						contentAddressableStorage,
+						treeDigest.GetDigestFunction(),
+						re_filesystem.InMemoryFilePool,
+						&actionResult,
+						/* forceUploadTreesAndDirectories = */ false,
+						/* outputPruner = */ nil,
+						/* outputLimits = */ nil,
+						/* outputPathValidation = */ nil))
+				require.Equal(t, remoteexecution.ActionResult{
+					OutputDirectories: []*remoteexecution.OutputDirectory{
+						{
+							Path:                  "..",
+							TreeDigest:            treeDigest.GetProto(),
+							IsTopologicallySorted: true,
+						},
+					},
+				}, actionResult)
+			})
+		}
+	})
+
 	t.Run("RootPath", func(t *testing.T) {
 		// Similar to the previous test, it is also permitted to
 		// add the root directory as an REv2.1 output path.
@@ -798,8 +883,12 @@ func TestOutputHierarchyUploadOutputs(t *testing.T) {
 				root,
 				contentAddressableStorage,
 				digestFunction,
+				re_filesystem.InMemoryFilePool,
 				&actionResult,
-				/* forceUploadTreesAndDirectories = */ false))
+				/* forceUploadTreesAndDirectories = */ false,
+				/* outputPruner = */ nil,
+				/* outputLimits = */ nil,
+				/* outputPathValidation = */ nil))
 		require.Equal(t, remoteexecution.ActionResult{
 			OutputDirectories: []*remoteexecution.OutputDirectory{
 				{
@@ -833,8 +922,12 @@ func TestOutputHierarchyUploadOutputs(t *testing.T) {
 				root,
 				contentAddressableStorage,
 				digestFunction,
+				re_filesystem.InMemoryFilePool,
 				&actionResult,
-				/* forceUploadTreesAndDirectories = */ false))
+				/* forceUploadTreesAndDirectories = */ false,
+				/* outputPruner = */ nil,
+				/* outputLimits = */ nil,
+				/* outputPathValidation = */ nil))
 		require.Equal(t, remoteexecution.ActionResult{}, actionResult)
 	})
 
@@ -857,8 +950,12 @@ func TestOutputHierarchyUploadOutputs(t *testing.T) {
 				root,
 				contentAddressableStorage,
 				digestFunction,
+				re_filesystem.InMemoryFilePool,
 				&actionResult,
-				/* forceUploadTreesAndDirectories = */ false))
+				/* forceUploadTreesAndDirectories = */ false,
+				/* outputPruner = */ nil,
+				/* outputLimits = */ nil,
+				/* outputPathValidation = */ nil))
 		require.Equal(t, remoteexecution.ActionResult{}, actionResult)
 	})
 
@@ -881,11 +978,203 @@ func TestOutputHierarchyUploadOutputs(t *testing.T) {
 				root,
 				contentAddressableStorage,
 				digestFunction,
+				re_filesystem.InMemoryFilePool,
 				&actionResult,
-				/* forceUploadTreesAndDirectories = */ false))
+				/* forceUploadTreesAndDirectories = */ false,
+				/* outputPruner = */ nil,
+				/* outputLimits = */ nil,
+				/* outputPathValidation = */ nil))
 		require.Equal(t, remoteexecution.ActionResult{}, actionResult)
 	})
 
+	t.Run("DuplicatePaths", func(t *testing.T) {
+		// Output paths that are listed multiple times should
+		// only be reported once.
+		root.EXPECT().Lstat(path.MustNewComponent("foo")).Return(filesystem.NewFileInfo(path.MustNewComponent("foo"), filesystem.FileTypeRegularFile, false), nil)
+		root.EXPECT().UploadFile(ctx, path.MustNewComponent("foo"), gomock.Any()).Return(
+			digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "a58c2f2281011ca2e631b39baa1ab657", 12),
+			nil)
+
+		oh, err := builder.NewOutputHierarchy(&remoteexecution.Command{
+			WorkingDirectory: "",
+			OutputPaths:      []string{"foo", "foo"},
+		})
+		require.NoError(t, err)
+		var actionResult remoteexecution.ActionResult
+		require.NoError(
+			t,
+			oh.UploadOutputs(
+				ctx,
+				root,
+				contentAddressableStorage,
+				digestFunction,
+				re_filesystem.InMemoryFilePool,
+				&actionResult,
+				/* forceUploadTreesAndDirectories = */ false,
+				/* outputPruner = */ nil,
+				/* outputLimits = */ nil,
+				/* outputPathValidation = */ nil))
+		require.Equal(t, remoteexecution.ActionResult{
+			OutputFiles: []*remoteexecution.OutputFile{
+				{
+					Path: "foo",
+					Digest: &remoteexecution.Digest{
+						Hash:      "a58c2f2281011ca2e631b39baa1ab657",
+						SizeBytes: 12,
+					},
+				},
+			},
+		}, actionResult)
+	})
+
+	t.Run("OutputNodeProperties", func(t *testing.T) {
+		// Properties requested through output_node_properties
+		// should be attached to output files, both at the top
+		// level and inside output directories. Properties that
+		// were not requested should be omitted.
+		root.EXPECT().Lstat(path.MustNewComponent("bar")).Return(filesystem.NewFileInfo(path.MustNewComponent("bar"), filesystem.FileTypeDirectory, false), nil)
+		bar := mock.NewMockUploadableDirectory(ctrl)
+		root.EXPECT().EnterUploadableDirectory(path.MustNewComponent("bar")).Return(bar, nil)
+		bar.EXPECT().ReadDir().Return([]filesystem.FileInfo{
+			filesystem.NewFileInfo(path.MustNewComponent("baz"), filesystem.FileTypeRegularFile, false),
+		}, nil)
+		bar.EXPECT().UploadFile(ctx, path.MustNewComponent("baz"), gomock.Any()).Return(
+			digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "a58c2f2281011ca2e631b39baa1ab657", 12),
+			nil)
+		bar.EXPECT().GetNodeProperties(ctx, path.MustNewComponent("baz")).Return(&remoteexecution.NodeProperties{
+			Mtime:    &timestamppb.Timestamp{Seconds: 1600000000},
+			UnixMode: &wrapperspb.UInt32Value{Value: 0o644},
+		}, nil)
+		bar.EXPECT().Close()
+		var treeDigest digest.Digest
+		contentAddressableStorage.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+				treeDigest = digest
+				m, err := b.ToProto(&remoteexecution.Tree{}, 10000)
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, &remoteexecution.Tree{
+					Root: &remoteexecution.Directory{
+						Files: []*remoteexecution.FileNode{
+							{
+								Name: "baz",
+								Digest: &remoteexecution.Digest{
+									Hash:      "a58c2f2281011ca2e631b39baa1ab657",
+									SizeBytes: 12,
+								},
+								NodeProperties: &remoteexecution.NodeProperties{
+									UnixMode: &wrapperspb.UInt32Value{Value: 0o644},
+								},
+							},
+						},
+					},
+				}, m)
+				return nil
+			})
+		root.EXPECT().Lstat(path.MustNewComponent("foo")).Return(filesystem.NewFileInfo(path.MustNewComponent("foo"), filesystem.FileTypeRegularFile, true), nil)
+		root.EXPECT().UploadFile(ctx, path.MustNewComponent("foo"), gomock.Any()).Return(
+			digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "2b1d5a1e23fbcae1a7fd2a2e3e8e5ca7", 34),
+			nil)
+		root.EXPECT().GetNodeProperties(ctx, path.MustNewComponent("foo")).Return(&remoteexecution.NodeProperties{
+			Mtime:    &timestamppb.Timestamp{Seconds: 1700000000},
+			UnixMode: &wrapperspb.UInt32Value{Value: 0o755},
+		}, nil)
+
+		oh, err := builder.NewOutputHierarchy(&remoteexecution.Command{
+			OutputPaths:          []string{"bar", "foo"},
+			OutputNodeProperties: []string{"unix_mode"},
+		})
+		require.NoError(t, err)
+		var actionResult remoteexecution.ActionResult
+		require.NoError(
+			t,
+			oh.UploadOutputs(
+				ctx,
+				root,
+				contentAddressableStorage,
+				digestFunction,
+				re_filesystem.InMemoryFilePool,
+				&actionResult,
+				/* forceUploadTreesAndDirectories = */ false,
+				/* outputPruner = */ nil,
+				/* outputLimits = */ nil,
+				/* outputPathValidation = */ nil))
+		testutil.RequireEqualProto(t, &remoteexecution.ActionResult{
+			OutputDirectories: []*remoteexecution.OutputDirectory{
+				{
+					Path:                  "bar",
+					TreeDigest:            treeDigest.GetProto(),
+					IsTopologicallySorted: true,
+				},
+			},
+			OutputFiles: []*remoteexecution.OutputFile{
+				{
+					Path: "foo",
+					Digest: &remoteexecution.Digest{
+						Hash:      "2b1d5a1e23fbcae1a7fd2a2e3e8e5ca7",
+						SizeBytes: 34,
+					},
+					IsExecutable: true,
+					NodeProperties: &remoteexecution.NodeProperties{
+						UnixMode: &wrapperspb.UInt32Value{Value: 0o755},
+					},
+				},
+			},
+		}, &actionResult)
+	})
+
+	t.Run("OutputNodePropertiesXAttrs", func(t *testing.T) {
+		// Extended attributes should only be attached to output
+		// files if requested explicitly.
+		root.EXPECT().Lstat(path.MustNewComponent("foo")).Return(filesystem.NewFileInfo(path.MustNewComponent("foo"), filesystem.FileTypeRegularFile, true), nil)
+		root.EXPECT().UploadFile(ctx, path.MustNewComponent("foo"), gomock.Any()).Return(
+			digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "2b1d5a1e23fbcae1a7fd2a2e3e8e5ca7", 34),
+			nil)
+		root.EXPECT().GetNodeProperties(ctx, path.MustNewComponent("foo")).Return(&remoteexecution.NodeProperties{
+			Properties: []*remoteexecution.NodeProperty{
+				{Name: "xattr.com.apple.cs.CodeDirectory", Value: "+t4="},
+			},
+			Mtime:    &timestamppb.Timestamp{Seconds: 1700000000},
+			UnixMode: &wrapperspb.UInt32Value{Value: 0o755},
+		}, nil)
+
+		oh, err := builder.NewOutputHierarchy(&remoteexecution.Command{
+			OutputPaths:          []string{"foo"},
+			OutputNodeProperties: []string{"xattrs"},
+		})
+		require.NoError(t, err)
+		var actionResult remoteexecution.ActionResult
+		require.NoError(
+			t,
+			oh.UploadOutputs(
+				ctx,
+				root,
+				contentAddressableStorage,
+				digestFunction,
+				re_filesystem.InMemoryFilePool,
+				&actionResult,
+				/* forceUploadTreesAndDirectories = */ false,
+				/* outputPruner = */ nil,
+				/* outputLimits = */ nil,
+				/* outputPathValidation = */ nil))
+		testutil.RequireEqualProto(t, &remoteexecution.ActionResult{
+			OutputFiles: []*remoteexecution.OutputFile{
+				{
+					Path: "foo",
+					Digest: &remoteexecution.Digest{
+						Hash:      "2b1d5a1e23fbcae1a7fd2a2e3e8e5ca7",
+						SizeBytes: 34,
+					},
+					IsExecutable: true,
+					NodeProperties: &remoteexecution.NodeProperties{
+						Properties: []*remoteexecution.NodeProperty{
+							{Name: "xattr.com.apple.cs.CodeDirectory", Value: "+t4="},
+						},
+					},
+				},
+			},
+		}, &actionResult)
+	})
+
 	t.Run("OutputDirectoryFormatTreeAndDirectory", func(t *testing.T) {
 		// If the client sets Command's output_directory_format
 		// to TREE_AND_DIRECTORY, we must store both Tree and
@@ -993,8 +1282,12 @@ func TestOutputHierarchyUploadOutputs(t *testing.T) {
 				root,
 				contentAddressableStorage,
 				digestFunction,
+				re_filesystem.InMemoryFilePool,
 				&actionResult,
-				/* forceUploadTreesAndDirectories = */ false))
+				/* forceUploadTreesAndDirectories = */ false,
+				/* outputPruner = */ nil,
+				/* outputLimits = */ nil,
+				/* outputPathValidation = */ nil))
 		testutil.RequireEqualProto(t, &remoteexecution.ActionResult{
 			OutputDirectories: []*remoteexecution.OutputDirectory{{
 				Path: ".",
@@ -1011,5 +1304,383 @@ func TestOutputHierarchyUploadOutputs(t *testing.T) {
 		}, &actionResult)
 	})
 
+	t.Run("OutputPruning", func(t *testing.T) {
+		// Entries inside output directories that match the
+		// OutputPruner should not be uploaded. Outputs that are
+		// requested explicitly should be uploaded regardless.
+		outputPruner, err := builder.NewOutputPruner([]string{"*.pyc", "__pycache__"})
+		require.NoError(t, err)
+
+		root.EXPECT().Lstat(path.MustNewComponent("bar")).Return(filesystem.NewFileInfo(path.MustNewComponent("bar"), filesystem.FileTypeDirectory, false), nil)
+		bar := mock.NewMockUploadableDirectory(ctrl)
+		root.EXPECT().EnterUploadableDirectory(path.MustNewComponent("bar")).Return(bar, nil)
+		bar.EXPECT().ReadDir().Return([]filesystem.FileInfo{
+			filesystem.NewFileInfo(path.MustNewComponent("__pycache__"), filesystem.FileTypeDirectory, false),
+			filesystem.NewFileInfo(path.MustNewComponent("baz"), filesystem.FileTypeRegularFile, false),
+			filesystem.NewFileInfo(path.MustNewComponent("baz.pyc"), filesystem.FileTypeRegularFile, false),
+		}, nil)
+		bar.EXPECT().UploadFile(ctx, path.MustNewComponent("baz"), gomock.Any()).Return(
+			digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "a58c2f2281011ca2e631b39baa1ab657", 12),
+			nil)
+		bar.EXPECT().Close()
+		var treeDigest digest.Digest
+		contentAddressableStorage.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+				treeDigest = digest
+				m, err := b.ToProto(&remoteexecution.Tree{}, 10000)
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, &remoteexecution.Tree{
+					Root: &remoteexecution.Directory{
+						Files: []*remoteexecution.FileNode{
+							{
+								Name: "baz",
+								Digest: &remoteexecution.Digest{
+									Hash:      "a58c2f2281011ca2e631b39baa1ab657",
+									SizeBytes: 12,
+								},
+							},
+						},
+					},
+				}, m)
+				return nil
+			})
+		root.EXPECT().Lstat(path.MustNewComponent("foo.pyc")).Return(filesystem.NewFileInfo(path.MustNewComponent("foo.pyc"), filesystem.FileTypeRegularFile, false), nil)
+		root.EXPECT().UploadFile(ctx, path.MustNewComponent("foo.pyc"), gomock.Any()).Return(
+			digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "2b1d5a1e23fbcae1a7fd2a2e3e8e5ca7", 34),
+			nil)
+
+		oh, err := builder.NewOutputHierarchy(&remoteexecution.Command{
+			OutputPaths: []string{"bar", "foo.pyc"},
+		})
+		require.NoError(t, err)
+		var actionResult remoteexecution.ActionResult
+		require.NoError(
+			t,
+			oh.UploadOutputs(
+				ctx,
+				root,
+				contentAddressableStorage,
+				digestFunction,
+				re_filesystem.InMemoryFilePool,
+				&actionResult,
+				/* forceUploadTreesAndDirectories = */ false,
+				outputPruner,
+				/* outputLimits = */ nil,
+				/* outputPathValidation = */ nil))
+		testutil.RequireEqualProto(t, &remoteexecution.ActionResult{
+			OutputDirectories: []*remoteexecution.OutputDirectory{
+				{
+					Path:                  "bar",
+					TreeDigest:            treeDigest.GetProto(),
+					IsTopologicallySorted: true,
+				},
+			},
+			OutputFiles: []*remoteexecution.OutputFile{
+				{
+					Path: "foo.pyc",
+					Digest: &remoteexecution.Digest{
+						Hash:      "2b1d5a1e23fbcae1a7fd2a2e3e8e5ca7",
+						SizeBytes: 34,
+					},
+				},
+			},
+		}, &actionResult)
+	})
+
+	t.Run("ModificationTracking", func(t *testing.T) {
+		// If the directory keeps track of which children were
+		// modified by the build action, outputs that were left
+		// untouched should not be inspected.
+		type modificationTrackingUploadableDirectory struct {
+			*mock.MockUploadableDirectory
+			*mock.MockModificationTrackingDirectory
+		}
+		newModificationTrackingDirectory := func() modificationTrackingUploadableDirectory {
+			return modificationTrackingUploadableDirectory{
+				MockUploadableDirectory:           mock.NewMockUploadableDirectory(ctrl),
+				MockModificationTrackingDirectory: mock.NewMockModificationTrackingDirectory(ctrl),
+			}
+		}
+
+		trackingRoot := newModificationTrackingDirectory()
+		trackingRoot.MockModificationTrackingDirectory.EXPECT().LookupModifiedChildren().Return(map[path.Component]bool{
+			path.MustNewComponent("created"): true,
+			path.MustNewComponent("loaded"):  false,
+			path.MustNewComponent("renamed"): true,
+		}, nil)
+		trackingRoot.MockUploadableDirectory.EXPECT().Lstat(path.MustNewComponent("created")).Return(filesystem.NewFileInfo(path.MustNewComponent("created"), filesystem.FileTypeRegularFile, false), nil)
+		trackingRoot.MockUploadableDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("created"), gomock.Any()).Return(
+			digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "a58c2f2281011ca2e631b39baa1ab657", 12),
+			nil)
+
+		// Directories whose contents have been loaded may
+		// contain modified children, so they need to be
+		// entered to look them up.
+		loaded := newModificationTrackingDirectory()
+		trackingRoot.MockUploadableDirectory.EXPECT().EnterUploadableDirectory(path.MustNewComponent("loaded")).Return(loaded, nil)
+		loaded.MockModificationTrackingDirectory.EXPECT().LookupModifiedChildren().Return(map[path.Component]bool{}, nil)
+		loaded.MockUploadableDirectory.EXPECT().Close()
+
+		// Directories that were created or renamed by the build
+		// action may contain files that are part of the input
+		// root. All of their children need to be inspected.
+		renamed := newModificationTrackingDirectory()
+		trackingRoot.MockUploadableDirectory.EXPECT().EnterUploadableDirectory(path.MustNewComponent("renamed")).Return(renamed, nil)
+		renamed.MockUploadableDirectory.EXPECT().Lstat(path.MustNewComponent("file")).Return(filesystem.NewFileInfo(path.MustNewComponent("file"), filesystem.FileTypeRegularFile, true), nil)
+		renamed.MockUploadableDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("file"), gomock.Any()).Return(
+			digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "2b1d5a1e23fbcae1a7fd2a2e3e8e5ca7", 34),
+			nil)
+		renamed.MockUploadableDirectory.EXPECT().Close()
+
+		oh, err := builder.NewOutputHierarchy(&remoteexecution.Command{
+			OutputPaths: []string{
+				"created",
+				"loaded/file",
+				"renamed/file",
+				"unmodified",
+				"untouched/file",
+			},
+		})
+		require.NoError(t, err)
+		var actionResult remoteexecution.ActionResult
+		require.NoError(
+			t,
+			oh.UploadOutputs(
+				ctx,
+				trackingRoot,
+				contentAddressableStorage,
+				digestFunction,
+				re_filesystem.InMemoryFilePool,
+				&actionResult,
+				/* forceUploadTreesAndDirectories = */ false,
+				/* outputPruner = */ nil,
+				/* outputLimits = */ nil,
+				/* outputPathValidation = */ nil))
+		testutil.RequireEqualProto(t, &remoteexecution.ActionResult{
+			OutputFiles: []*remoteexecution.OutputFile{
+				{
+					Path: "created",
+					Digest: &remoteexecution.Digest{
+						Hash:      "a58c2f2281011ca2e631b39baa1ab657",
+						SizeBytes: 12,
+					},
+				},
+				{
+					Path: "renamed/file",
+					Digest: &remoteexecution.Digest{
+						Hash:      "2b1d5a1e23fbcae1a7fd2a2e3e8e5ca7",
+						SizeBytes: 34,
+					},
+					IsExecutable: true,
+				},
+			},
+		}, &actionResult)
+	})
+
+	t.Run("OutputLimits", func(t *testing.T) {
+		// Outputs exceeding the limits should cause the action
+		// to fail, without any of the outputs being uploaded.
+		// The error should list the largest outputs.
+		oh, err := builder.NewOutputHierarchy(&remoteexecution.Command{
+			OutputPaths: []string{"bar", "foo"},
+		})
+		require.NoError(t, err)
+
+		expectMeasurement := func() {
+			root.EXPECT().Lstat(path.MustNewComponent("bar")).Return(filesystem.NewFileInfo(path.MustNewComponent("bar"), filesystem.FileTypeDirectory, false), nil)
+			bar := mock.NewMockUploadableDirectory(ctrl)
+			root.EXPECT().EnterUploadableDirectory(path.MustNewComponent("bar")).Return(bar, nil)
+			bar.EXPECT().ReadDir().Return([]filesystem.FileInfo{
+				filesystem.NewFileInfo(path.MustNewComponent("baz"), filesystem.FileTypeRegularFile, false),
+				filesystem.NewFileInfo(path.MustNewComponent("qux"), filesystem.FileTypeRegularFile, false),
+				filesystem.NewFileInfo(path.MustNewComponent("symlink"), filesystem.FileTypeSymlink, false),
+			}, nil)
+			bar.EXPECT().GetFileSizeBytes(ctx, path.MustNewComponent("baz")).Return(int64(100), nil)
+			bar.EXPECT().GetFileSizeBytes(ctx, path.MustNewComponent("qux")).Return(int64(200), nil)
+			bar.EXPECT().Close()
+			root.EXPECT().Lstat(path.MustNewComponent("foo")).Return(filesystem.NewFileInfo(path.MustNewComponent("foo"), filesystem.FileTypeRegularFile, false), nil)
+			root.EXPECT().GetFileSizeBytes(ctx, path.MustNewComponent("foo")).Return(int64(50), nil)
+		}
+
+		t.Run("SizeBytes", func(t *testing.T) {
+			expectMeasurement()
+
+			var actionResult remoteexecution.ActionResult
+			testutil.RequireEqualStatus(
+				t,
+				status.Error(codes.InvalidArgument, "Outputs have a total size of 350 bytes, which exceeds the maximum of 300 bytes. Largest outputs: \"bar\" (300 bytes), \"foo\" (50 bytes)"),
+				oh.UploadOutputs(
+					ctx,
+					root,
+					contentAddressableStorage,
+					digestFunction,
+					re_filesystem.InMemoryFilePool,
+					&actionResult,
+					/* forceUploadTreesAndDirectories = */ false,
+					/* outputPruner = */ nil,
+					&builder.OutputLimits{MaximumSizeBytes: 300},
+					/* outputPathValidation = */ nil))
+			testutil.RequireEqualProto(t, &remoteexecution.ActionResult{}, &actionResult)
+		})
+
+		t.Run("FileCount", func(t *testing.T) {
+			expectMeasurement()
+
+			var actionResult remoteexecution.ActionResult
+			testutil.RequireEqualStatus(
+				t,
+				status.Error(codes.InvalidArgument, "Outputs contain a total of 3 files, which exceeds the maximum of 2 files. Largest outputs: \"bar\" (2 files), \"foo\" (1 files)"),
+				oh.UploadOutputs(
+					ctx,
+					root,
+					contentAddressableStorage,
+					digestFunction,
+					re_filesystem.InMemoryFilePool,
+					&actionResult,
+					/* forceUploadTreesAndDirectories = */ false,
+					/* outputPruner = */ nil,
+					&builder.OutputLimits{MaximumSizeBytes: 1000, MaximumFileCount: 2},
+					/* outputPathValidation = */ nil))
+			testutil.RequireEqualProto(t, &remoteexecution.ActionResult{}, &actionResult)
+		})
+	})
+
+	t.Run("OutputPathValidation", func(t *testing.T) {
+		// Outputs that cannot be materialized on Windows or
+		// macOS should cause the action to fail, without any
+		// of the outputs being uploaded.
+		outputPathValidation := &builder.OutputPathValidation{
+			RejectCaseInsensitiveCollisions: true,
+			RejectWindowsIncompatibleNames:  true,
+			MaximumPathLength:               20,
+		}
+
+		t.Run("CaseInsensitiveCollision", func(t *testing.T) {
+			oh, err := builder.NewOutputHierarchy(&remoteexecution.Command{
+				OutputPaths: []string{"bar"},
+			})
+			require.NoError(t, err)
+
+			root.EXPECT().Lstat(path.MustNewComponent("bar")).Return(filesystem.NewFileInfo(path.MustNewComponent("bar"), filesystem.FileTypeDirectory, false), nil)
+			bar := mock.NewMockUploadableDirectory(ctrl)
+			root.EXPECT().EnterUploadableDirectory(path.MustNewComponent("bar")).Return(bar, nil)
+			bar.EXPECT().ReadDir().Return([]filesystem.FileInfo{
+				filesystem.NewFileInfo(path.MustNewComponent("Baz.h"), filesystem.FileTypeRegularFile, false),
+				filesystem.NewFileInfo(path.MustNewComponent("baz.h"), filesystem.FileTypeRegularFile, false),
+			}, nil)
+			bar.EXPECT().Close()
+
+			var actionResult remoteexecution.ActionResult
+			testutil.RequireEqualStatus(
+				t,
+				status.Error(codes.InvalidArgument, "Output paths \"bar/Baz.h\" and \"bar/baz.h\" only differ in case, meaning they cannot be materialized on case-insensitive file systems"),
+				oh.UploadOutputs(
+					ctx,
+					root,
+					contentAddressableStorage,
+					digestFunction,
+					re_filesystem.InMemoryFilePool,
+					&actionResult,
+					/* forceUploadTreesAndDirectories = */ false,
+					/* outputPruner = */ nil,
+					/* outputLimits = */ nil,
+					outputPathValidation))
+			testutil.RequireEqualProto(t, &remoteexecution.ActionResult{}, &actionResult)
+		})
+
+		t.Run("ReservedName", func(t *testing.T) {
+			oh, err := builder.NewOutputHierarchy(&remoteexecution.Command{
+				OutputPaths: []string{"out/nul.txt"},
+			})
+			require.NoError(t, err)
+
+			out := mock.NewMockUploadableDirectory(ctrl)
+			root.EXPECT().EnterUploadableDirectory(path.MustNewComponent("out")).Return(out, nil)
+			out.EXPECT().Lstat(path.MustNewComponent("nul.txt")).Return(filesystem.NewFileInfo(path.MustNewComponent("nul.txt"), filesystem.FileTypeRegularFile, false), nil)
+			out.EXPECT().Close()
+
+			var actionResult remoteexecution.ActionResult
+			testutil.RequireEqualStatus(
+				t,
+				status.Error(codes.InvalidArgument, "Output path \"out/nul.txt\" has component \"nul.txt\", which is a reserved device name on Windows"),
+				oh.UploadOutputs(
+					ctx,
+					root,
+					contentAddressableStorage,
+					digestFunction,
+					re_filesystem.InMemoryFilePool,
+					&actionResult,
+					/* forceUploadTreesAndDirectories = */ false,
+					/* outputPruner = */ nil,
+					/* outputLimits = */ nil,
+					outputPathValidation))
+			testutil.RequireEqualProto(t, &remoteexecution.ActionResult{}, &actionResult)
+		})
+
+		t.Run("TrailingDot", func(t *testing.T) {
+			oh, err := builder.NewOutputHierarchy(&remoteexecution.Command{
+				OutputPaths: []string{"bar"},
+			})
+			require.NoError(t, err)
+
+			root.EXPECT().Lstat(path.MustNewComponent("bar")).Return(filesystem.NewFileInfo(path.MustNewComponent("bar"), filesystem.FileTypeDirectory, false), nil)
+			bar := mock.NewMockUploadableDirectory(ctrl)
+			root.EXPECT().EnterUploadableDirectory(path.MustNewComponent("bar")).Return(bar, nil)
+			bar.EXPECT().ReadDir().Return([]filesystem.FileInfo{
+				filesystem.NewFileInfo(path.MustNewComponent("file."), filesystem.FileTypeRegularFile, false),
+			}, nil)
+			bar.EXPECT().Close()
+
+			var actionResult remoteexecution.ActionResult
+			testutil.RequireEqualStatus(
+				t,
+				status.Error(codes.InvalidArgument, "Output path \"bar/file.\" has a component ending with a dot or space, which is not permitted on Windows"),
+				oh.UploadOutputs(
+					ctx,
+					root,
+					contentAddressableStorage,
+					digestFunction,
+					re_filesystem.InMemoryFilePool,
+					&actionResult,
+					/* forceUploadTreesAndDirectories = */ false,
+					/* outputPruner = */ nil,
+					/* outputLimits = */ nil,
+					outputPathValidation))
+			testutil.RequireEqualProto(t, &remoteexecution.ActionResult{}, &actionResult)
+		})
+
+		t.Run("PathLength", func(t *testing.T) {
+			oh, err := builder.NewOutputHierarchy(&remoteexecution.Command{
+				OutputPaths: []string{"bar"},
+			})
+			require.NoError(t, err)
+
+			root.EXPECT().Lstat(path.MustNewComponent("bar")).Return(filesystem.NewFileInfo(path.MustNewComponent("bar"), filesystem.FileTypeDirectory, false), nil)
+			bar := mock.NewMockUploadableDirectory(ctrl)
+			root.EXPECT().EnterUploadableDirectory(path.MustNewComponent("bar")).Return(bar, nil)
+			bar.EXPECT().ReadDir().Return([]filesystem.FileInfo{
+				filesystem.NewFileInfo(path.MustNewComponent("a_very_long_name.h"), filesystem.FileTypeRegularFile, false),
+			}, nil)
+			bar.EXPECT().Close()
+
+			var actionResult remoteexecution.ActionResult
+			testutil.RequireEqualStatus(
+				t,
+				status.Error(codes.InvalidArgument, "Output path \"bar/a_very_long_name.h\" has a length of 22 bytes, which exceeds the maximum of 20 bytes"),
+				oh.UploadOutputs(
+					ctx,
+					root,
+					contentAddressableStorage,
+					digestFunction,
+					re_filesystem.InMemoryFilePool,
+					&actionResult,
+					/* forceUploadTreesAndDirectories = */ false,
+					/* outputPruner = */ nil,
+					/* outputLimits = */ nil,
+					outputPathValidation))
+			testutil.RequireEqualProto(t, &remoteexecution.ActionResult{}, &actionResult)
+		})
+	})
+
 	// TODO: Are there other cases we'd like to unit test?
 }
@@ -0,0 +1,226 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// outputLimitsMaximumOffenders is the maximum number of outputs that
+// are listed in errors returned by OutputLimits.
+const outputLimitsMaximumOffenders = 5
+
+// OutputLimits contains limits on the outputs of a build action that
+// are enforced by OutputHierarchy.UploadOutputs() prior to uploading
+// any of them. This prevents actions that accidentally generate an
+// excessive amount of data from filling up the Content Addressable
+// Storage.
+//
+// A nil OutputLimits does not enforce any limits.
+type OutputLimits struct {
+	// The maximum combined size of all output files, including
+	// files contained in output directories. Zero means that the
+	// size is not limited.
+	MaximumSizeBytes int64
+
+	// The maximum number of output files, including files
+	// contained in output directories. Zero means that the number
+	// of files is not limited.
+	MaximumFileCount int64
+}
+
+// outputUsage contains the amount of data and the number of files
+// stored in a single output of a build action.
+type outputUsage struct {
+	path      string
+	sizeBytes int64
+	fileCount int64
+}
+
+// check whether the outputs of a build action exceed any of the
+// limits. If so, the error that is returned lists the outputs that
+// contribute most to exceeding it.
+func (ol *OutputLimits) check(outputs []outputUsage) error {
+	totalSizeBytes, totalFileCount := int64(0), int64(0)
+	for _, output := range outputs {
+		totalSizeBytes += output.sizeBytes
+		totalFileCount += output.fileCount
+	}
+
+	if ol.MaximumSizeBytes > 0 && totalSizeBytes > ol.MaximumSizeBytes {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"Outputs have a total size of %d bytes, which exceeds the maximum of %d bytes. Largest outputs: %s",
+			totalSizeBytes,
+			ol.MaximumSizeBytes,
+			getLargestOutputs(outputs, func(output *outputUsage) int64 { return output.sizeBytes }, "bytes"))
+	}
+	if ol.MaximumFileCount > 0 && totalFileCount > ol.MaximumFileCount {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"Outputs contain a total of %d files, which exceeds the maximum of %d files. Largest outputs: %s",
+			totalFileCount,
+			ol.MaximumFileCount,
+			getLargestOutputs(outputs, func(output *outputUsage) int64 { return output.fileCount }, "files"))
+	}
+	return nil
+}
+
+// getLargestOutputs returns a human readable summary of the outputs
+// that have the highest value for a given metric.
+func getLargestOutputs(outputs []outputUsage, getValue func(output *outputUsage) int64, unit string) string {
+	sorted := append([]outputUsage(nil), outputs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return getValue(&sorted[i]) > getValue(&sorted[j])
+	})
+	if len(sorted) > outputLimitsMaximumOffenders {
+		sorted = sorted[:outputLimitsMaximumOffenders]
+	}
+	summaries := make([]string, 0, len(sorted))
+	for i := range sorted {
+		summaries = append(summaries, fmt.Sprintf("%#v (%d %s)", sorted[i].path, getValue(&sorted[i]), unit))
+	}
+	return strings.Join(summaries, ", ")
+}
+
+// measureOutputsState is used by OutputHierarchy.measureOutputs() to
+// track common parameters during recursion.
+//
+// Errors that occur while measuring outputs are not reported. Outputs
+// that cannot be measured are also likely to fail to upload, in which
+// case the error is reported by OutputHierarchy.UploadOutputs().
+type measureOutputsState struct {
+	context      context.Context
+	outputPruner *OutputPruner
+
+	outputs []outputUsage
+}
+
+// measureOutputs computes the amount of data and the number of files
+// stored in each of the outputs of a build action, without uploading
+// them.
+func (oh *OutputHierarchy) measureOutputs(ctx context.Context, d UploadableDirectory, outputPruner *OutputPruner) []outputUsage {
+	s := measureOutputsState{
+		context:      ctx,
+		outputPruner: outputPruner,
+	}
+	if len(oh.rootsToUpload) > 0 {
+		sizeBytes, fileCount := s.measureDirectory(d)
+		s.outputs = append(s.outputs, outputUsage{
+			path:      oh.rootsToUpload[0],
+			sizeBytes: sizeBytes,
+			fileCount: fileCount,
+		})
+	}
+	oh.root.measureOutputs(&s, d, false)
+	return s.outputs
+}
+
+// measureOutputs is recursively invoked by
+// OutputHierarchy.measureOutputs() to measure output directories and
+// files at the locations where they are expected. Like
+// outputNode.uploadOutputs(), it skips outputs that were not modified
+// by the build action.
+func (on *outputNode) measureOutputs(s *measureOutputsState, d UploadableDirectory, allModified bool) {
+	var modifiedChildren map[path.Component]bool
+	if dTracking, ok := d.(ModificationTrackingDirectory); ok && !allModified {
+		var err error
+		modifiedChildren, err = dTracking.LookupModifiedChildren()
+		if err != nil {
+			return
+		}
+	}
+
+	on.measureOutputsOfKind(s, d, on.directoriesToUpload, &outputKindDirectory, modifiedChildren)
+	on.measureOutputsOfKind(s, d, on.filesToUpload, &outputKindFile, modifiedChildren)
+	on.measureOutputsOfKind(s, d, on.pathsToUpload, &outputKindPath, modifiedChildren)
+
+	// Traverse into subdirectories.
+	for _, component := range on.getSubdirectoryNames() {
+		childAllModified := allModified
+		if modifiedChildren != nil {
+			created, ok := modifiedChildren[component]
+			if !ok {
+				continue
+			}
+			childAllModified = created
+		}
+		if childDirectory, err := d.EnterUploadableDirectory(component); err == nil {
+			on.subdirectories[component].measureOutputs(s, childDirectory, childAllModified)
+			childDirectory.Close()
+		}
+	}
+}
+
+// measureOutputsOfKind measures all outputs of a given kind that are
+// expected to be present in a directory. Symbolic links are not
+// followed, and thus do not contribute to the limits.
+func (on *outputNode) measureOutputsOfKind(s *measureOutputsState, d UploadableDirectory, toMeasure map[path.Component][]string, kind *outputKind, modifiedChildren map[path.Component]bool) {
+	for _, component := range sortToUpload(toMeasure) {
+		if _, ok := modifiedChildren[component]; modifiedChildren != nil && !ok {
+			continue
+		}
+		fileInfo, err := d.Lstat(component)
+		if err != nil {
+			continue
+		}
+		output := outputUsage{path: toMeasure[component][0]}
+		switch fileType := fileInfo.Type(); {
+		case fileType == filesystem.FileTypeDirectory && kind.allowDirectories:
+			childDirectory, err := d.EnterUploadableDirectory(component)
+			if err != nil {
+				continue
+			}
+			output.sizeBytes, output.fileCount = s.measureDirectory(childDirectory)
+			childDirectory.Close()
+		case fileType == filesystem.FileTypeRegularFile && kind.allowRegularFiles:
+			sizeBytes, err := d.GetFileSizeBytes(s.context, component)
+			if err != nil {
+				continue
+			}
+			output.sizeBytes, output.fileCount = sizeBytes, 1
+		default:
+			continue
+		}
+		s.outputs = append(s.outputs, output)
+	}
+}
+
+// measureDirectory computes the combined size and number of files
+// contained in an output directory, omitting entries that match the
+// OutputPruner.
+func (s *measureOutputsState) measureDirectory(d UploadableDirectory) (int64, int64) {
+	files, err := d.ReadDir()
+	if err != nil {
+		return 0, 0
+	}
+	totalSizeBytes, totalFileCount := int64(0), int64(0)
+	for _, file := range files {
+		name := file.Name()
+		if s.outputPruner.ShouldPrune(name) {
+			continue
+		}
+		switch file.Type() {
+		case filesystem.FileTypeRegularFile:
+			if sizeBytes, err := d.GetFileSizeBytes(s.context, name); err == nil {
+				totalSizeBytes += sizeBytes
+				totalFileCount++
+			}
+		case filesystem.FileTypeDirectory:
+			if childDirectory, err := d.EnterUploadableDirectory(name); err == nil {
+				sizeBytes, fileCount := s.measureDirectory(childDirectory)
+				childDirectory.Close()
+				totalSizeBytes += sizeBytes
+				totalFileCount += fileCount
+			}
+		}
+	}
+	return totalSizeBytes, totalFileCount
+}
@@ -0,0 +1,242 @@
+package builder
+
+import (
+	"strings"
+
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// windowsReservedNames contains the names of devices that cannot be
+// used as file names on Windows, regardless of their case and whether
+// they are followed by an extension.
+var windowsReservedNames = map[string]struct{}{
+	"AUX": {}, "CON": {}, "NUL": {}, "PRN": {},
+	"COM1": {}, "COM2": {}, "COM3": {}, "COM4": {}, "COM5": {}, "COM6": {}, "COM7": {}, "COM8": {}, "COM9": {},
+	"LPT1": {}, "LPT2": {}, "LPT3": {}, "LPT4": {}, "LPT5": {}, "LPT6": {}, "LPT7": {}, "LPT8": {}, "LPT9": {},
+}
+
+// OutputPathValidation contains rules for the paths of outputs of a
+// build action that are enforced by OutputHierarchy.UploadOutputs()
+// prior to uploading any of them. This prevents actions from
+// producing results that cannot be materialized by clients running on
+// Windows or macOS.
+//
+// A nil OutputPathValidation does not enforce any rules.
+type OutputPathValidation struct {
+	// Reject outputs containing multiple entries in a single
+	// directory whose names only differ in case. These cannot be
+	// materialized on case-insensitive file systems, such as the
+	// ones used by default on Windows and macOS.
+	RejectCaseInsensitiveCollisions bool
+
+	// Reject outputs containing names that are not permitted on
+	// Windows. These are reserved device names (e.g., "CON" and
+	// "NUL.txt"), names ending with a dot or space, and names
+	// containing characters like '<', ':' and '\'.
+	RejectWindowsIncompatibleNames bool
+
+	// The maximum length of the paths of outputs, including files
+	// contained in output directories, relative to the working
+	// directory of the action. Zero means that the length is not
+	// limited.
+	MaximumPathLength int
+}
+
+// checkName returns an error if the name of a file contained in the
+// outputs of a build action is not permitted.
+func (opv *OutputPathValidation) checkName(outputPath, name string) error {
+	if !opv.RejectWindowsIncompatibleNames {
+		return nil
+	}
+	if i := strings.IndexAny(name, "<>:\"\\|?*"); i >= 0 {
+		return status.Errorf(codes.InvalidArgument, "Output path %#v contains character %#v, which is not permitted on Windows", outputPath, name[i:i+1])
+	}
+	for _, r := range name {
+		if r < 0x20 {
+			return status.Errorf(codes.InvalidArgument, "Output path %#v contains a control character, which is not permitted on Windows", outputPath)
+		}
+	}
+	if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+		return status.Errorf(codes.InvalidArgument, "Output path %#v has a component ending with a dot or space, which is not permitted on Windows", outputPath)
+	}
+	baseName, _, _ := strings.Cut(name, ".")
+	if _, ok := windowsReservedNames[strings.ToUpper(strings.TrimRight(baseName, " "))]; ok {
+		return status.Errorf(codes.InvalidArgument, "Output path %#v has component %#v, which is a reserved device name on Windows", outputPath, name)
+	}
+	return nil
+}
+
+// checkLength returns an error if the path of an output is too long.
+func (opv *OutputPathValidation) checkLength(outputPath string) error {
+	if opv.MaximumPathLength > 0 && len(outputPath) > opv.MaximumPathLength {
+		return status.Errorf(codes.InvalidArgument, "Output path %#v has a length of %d bytes, which exceeds the maximum of %d bytes", outputPath, len(outputPath), opv.MaximumPathLength)
+	}
+	return nil
+}
+
+// checkPath returns an error if any of the components of the path of
+// an output are not permitted, or if the path is too long.
+func (opv *OutputPathValidation) checkPath(outputPath string) error {
+	if err := opv.checkLength(outputPath); err != nil {
+		return err
+	}
+	for _, component := range strings.Split(outputPath, "/") {
+		if component != "" && component != "." && component != ".." {
+			if err := opv.checkName(outputPath, component); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkCollisions returns an error if any of the names of files
+// contained in a single directory only differ in case.
+func (opv *OutputPathValidation) checkCollisions(directoryPath string, names []string) error {
+	if !opv.RejectCaseInsensitiveCollisions {
+		return nil
+	}
+	seen := make(map[string]string, len(names))
+	for _, name := range names {
+		lowerName := strings.ToLower(name)
+		if otherName, ok := seen[lowerName]; ok {
+			return status.Errorf(
+				codes.InvalidArgument,
+				"Output paths %#v and %#v only differ in case, meaning they cannot be materialized on case-insensitive file systems",
+				joinInputRootPath(directoryPath, otherName),
+				joinInputRootPath(directoryPath, name))
+		}
+		seen[lowerName] = name
+	}
+	return nil
+}
+
+// validateOutputPathsState is used by
+// OutputHierarchy.validateOutputPaths() to track common parameters
+// during recursion.
+//
+// Like measureOutputsState, errors that occur while accessing outputs
+// are not reported, as they are reported by
+// OutputHierarchy.UploadOutputs().
+type validateOutputPathsState struct {
+	validation   *OutputPathValidation
+	outputPruner *OutputPruner
+}
+
+// validateOutputPaths checks whether the paths of all outputs of a
+// build action, including files contained in output directories,
+// conform to the OutputPathValidation.
+func (oh *OutputHierarchy) validateOutputPaths(d UploadableDirectory, outputPruner *OutputPruner, validation *OutputPathValidation) error {
+	s := validateOutputPathsState{
+		validation:   validation,
+		outputPruner: outputPruner,
+	}
+	if len(oh.rootsToUpload) > 0 {
+		if err := s.validateDirectory(d, ""); err != nil {
+			return err
+		}
+	}
+	return oh.root.validateOutputPaths(&s, d, false)
+}
+
+// validateOutputPaths is recursively invoked by
+// OutputHierarchy.validateOutputPaths() to check outputs at the
+// locations where they are expected. Like outputNode.uploadOutputs(),
+// it skips outputs that were not modified by the build action.
+func (on *outputNode) validateOutputPaths(s *validateOutputPathsState, d UploadableDirectory, allModified bool) error {
+	var modifiedChildren map[path.Component]bool
+	if dTracking, ok := d.(ModificationTrackingDirectory); ok && !allModified {
+		var err error
+		modifiedChildren, err = dTracking.LookupModifiedChildren()
+		if err != nil {
+			return nil
+		}
+	}
+
+	for _, toValidate := range []map[path.Component][]string{on.directoriesToUpload, on.filesToUpload, on.pathsToUpload} {
+		for _, component := range sortToUpload(toValidate) {
+			if _, ok := modifiedChildren[component]; modifiedChildren != nil && !ok {
+				continue
+			}
+			fileInfo, err := d.Lstat(component)
+			if err != nil {
+				continue
+			}
+			for _, outputPath := range toValidate[component] {
+				if err := s.validation.checkPath(outputPath); err != nil {
+					return err
+				}
+			}
+			if fileInfo.Type() == filesystem.FileTypeDirectory {
+				childDirectory, err := d.EnterUploadableDirectory(component)
+				if err != nil {
+					continue
+				}
+				err = s.validateDirectory(childDirectory, toValidate[component][0])
+				childDirectory.Close()
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Traverse into subdirectories.
+	for _, component := range on.getSubdirectoryNames() {
+		childAllModified := allModified
+		if modifiedChildren != nil {
+			created, ok := modifiedChildren[component]
+			if !ok {
+				continue
+			}
+			childAllModified = created
+		}
+		if childDirectory, err := d.EnterUploadableDirectory(component); err == nil {
+			err := on.subdirectories[component].validateOutputPaths(s, childDirectory, childAllModified)
+			childDirectory.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateDirectory checks whether the paths of all files contained in
+// an output directory conform to the OutputPathValidation, omitting
+// entries that match the OutputPruner.
+func (s *validateOutputPathsState) validateDirectory(d UploadableDirectory, directoryPath string) error {
+	files, err := d.ReadDir()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		name := file.Name()
+		if s.outputPruner.ShouldPrune(name) {
+			continue
+		}
+		names = append(names, name.String())
+		childPath := joinInputRootPath(directoryPath, name.String())
+		if err := s.validation.checkLength(childPath); err != nil {
+			return err
+		}
+		if err := s.validation.checkName(childPath, name.String()); err != nil {
+			return err
+		}
+		if file.Type() == filesystem.FileTypeDirectory {
+			if childDirectory, err := d.EnterUploadableDirectory(name); err == nil {
+				err := s.validateDirectory(childDirectory, childPath)
+				childDirectory.Close()
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return s.validation.checkCollisions(directoryPath, names)
+}
@@ -0,0 +1,53 @@
+package builder
+
+import (
+	stdpath "path"
+
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// OutputPruner decides which entries in output directories should be
+// omitted when uploading them into the Content Addressable Storage.
+// This can be used to discard files that build rules generate
+// accidentally (e.g., "*.pyc" files and "__pycache__" directories),
+// thereby reducing the number of objects written into the CAS.
+//
+// A nil OutputPruner does not prune any entries.
+type OutputPruner struct {
+	patterns []string
+}
+
+// NewOutputPruner creates an OutputPruner that prunes entries whose
+// names match any of the provided patterns. Patterns use the syntax
+// of path.Match().
+func NewOutputPruner(patterns []string) (*OutputPruner, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	for _, pattern := range patterns {
+		if _, err := stdpath.Match(pattern, ""); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid pattern %#v: %s", pattern, err)
+		}
+	}
+	return &OutputPruner{
+		patterns: patterns,
+	}, nil
+}
+
+// ShouldPrune returns true if an entry in an output directory having
+// the provided name should not be uploaded.
+func (op *OutputPruner) ShouldPrune(name path.Component) bool {
+	if op == nil {
+		return false
+	}
+	nameStr := name.String()
+	for _, pattern := range op.patterns {
+		if matched, _ := stdpath.Match(pattern, nameStr); matched {
+			return true
+		}
+	}
+	return false
+}
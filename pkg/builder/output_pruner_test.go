@@ -0,0 +1,40 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOutputPruner(t *testing.T) {
+	t.Run("NoPatterns", func(t *testing.T) {
+		// Not providing any patterns should yield a nil
+		// OutputPruner, which doesn't prune anything.
+		outputPruner, err := builder.NewOutputPruner(nil)
+		require.NoError(t, err)
+		require.Nil(t, outputPruner)
+		require.False(t, outputPruner.ShouldPrune(path.MustNewComponent("foo.pyc")))
+	})
+
+	t.Run("InvalidPattern", func(t *testing.T) {
+		_, err := builder.NewOutputPruner([]string{"*.pyc", "[abc"})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid pattern \"[abc\": syntax error in pattern"), err)
+	})
+
+	t.Run("Matching", func(t *testing.T) {
+		outputPruner, err := builder.NewOutputPruner([]string{"*.pyc", "__pycache__", ".DS_Store"})
+		require.NoError(t, err)
+
+		require.True(t, outputPruner.ShouldPrune(path.MustNewComponent("foo.pyc")))
+		require.True(t, outputPruner.ShouldPrune(path.MustNewComponent("__pycache__")))
+		require.True(t, outputPruner.ShouldPrune(path.MustNewComponent(".DS_Store")))
+		require.False(t, outputPruner.ShouldPrune(path.MustNewComponent("foo.py")))
+		require.False(t, outputPruner.ShouldPrune(path.MustNewComponent("foo.pyc.txt")))
+	})
+}
@@ -0,0 +1,298 @@
+package builder
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/debugger"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-storage/pkg/auth"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// PauseOnFailurePlatformPropertyName is the name of the platform
+// property that build actions may set to request that their build
+// directory is kept in place upon failure. The value of the property
+// is the amount of time the action should remain paused (e.g., "10m").
+const PauseOnFailurePlatformPropertyName = "pause-on-failure"
+
+var (
+	debugStdoutComponent = path.MustNewComponent("debug_stdout")
+	debugStderrComponent = path.MustNewComponent("debug_stderr")
+)
+
+// getPauseOnFailureDuration returns the amount of time an action has
+// requested to remain paused upon failure. Zero is returned if the
+// action did not request to be paused.
+func getPauseOnFailureDuration(action *remoteexecution.Action) (time.Duration, error) {
+	for _, property := range action.GetPlatform().GetProperties() {
+		if property.Name == PauseOnFailurePlatformPropertyName {
+			duration, err := time.ParseDuration(property.Value)
+			if err != nil {
+				return 0, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid value for platform property %#v", PauseOnFailurePlatformPropertyName)
+			}
+			if duration <= 0 {
+				return 0, status.Errorf(codes.InvalidArgument, "Platform property %#v must be positive", PauseOnFailurePlatformPropertyName)
+			}
+			return duration, nil
+		}
+	}
+	return 0, nil
+}
+
+// PausableAction contains the state of a failed build action that is
+// needed to execute commands inside its build directory.
+type PausableAction struct {
+	ActionDigest digest.Digest
+	// The instance name against which the action was executed.
+	// Access to the paused action is authorized against it.
+	InstanceName digest.InstanceName
+	// The amount of time the action requested to remain paused,
+	// through the "pause-on-failure" platform property.
+	PauseDuration        time.Duration
+	BuildDirectory       BuildDirectory
+	BuildDirectoryPath   *path.Trace
+	Runner               runner_pb.RunnerClient
+	EnvironmentVariables map[string]string
+	WorkingDirectory     string
+}
+
+// FailedActionPauser is called into by LocalBuildExecutor when a build
+// action fails, prior to removing its build directory. It may block to
+// keep the build directory in place.
+type FailedActionPauser interface {
+	PauseFailedAction(ctx context.Context, action *PausableAction) error
+}
+
+// PausedActionRegistry is a FailedActionPauser that keeps track of
+// all build actions that are paused, and permits inspecting them
+// through the Debugger gRPC service.
+type PausedActionRegistry interface {
+	FailedActionPauser
+	debugger.DebuggerServer
+}
+
+type pausedAction struct {
+	action         *PausableAction
+	expirationTime time.Time
+	resume         chan struct{}
+
+	// Fields protected by the lock of the registry.
+	resumed bool
+
+	// Exec() calls are serialized, so that stdout and stderr files
+	// can be reused. It also ensures that PauseFailedAction() does
+	// not return while commands are running.
+	execLock sync.Mutex
+	closed   bool
+}
+
+type pausedActionRegistry struct {
+	clock                clock.Clock
+	uuidGenerator        util.UUIDGenerator
+	maximumPauseDuration time.Duration
+	authorizer           auth.Authorizer
+
+	lock          sync.Mutex
+	pausedActions map[string]*pausedAction
+}
+
+// NewPausedActionRegistry creates a PausedActionRegistry. Build actions
+// are paused for at most the provided duration, regardless of the
+// value of the "pause-on-failure" platform property.
+//
+// Access to paused actions through the Debugger service is checked
+// against the instance name of the action using the provided
+// Authorizer. Paused actions for which the caller is not authorized
+// are omitted from the results of ListPausedActions().
+func NewPausedActionRegistry(clock clock.Clock, uuidGenerator util.UUIDGenerator, maximumPauseDuration time.Duration, authorizer auth.Authorizer) PausedActionRegistry {
+	return &pausedActionRegistry{
+		clock:                clock,
+		uuidGenerator:        uuidGenerator,
+		maximumPauseDuration: maximumPauseDuration,
+		authorizer:           authorizer,
+		pausedActions:        map[string]*pausedAction{},
+	}
+}
+
+func (r *pausedActionRegistry) PauseFailedAction(ctx context.Context, action *PausableAction) error {
+	pauseDuration := action.PauseDuration
+	if pauseDuration <= 0 {
+		return nil
+	}
+	if pauseDuration > r.maximumPauseDuration {
+		pauseDuration = r.maximumPauseDuration
+	}
+
+	id, err := r.uuidGenerator()
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to generate paused action ID")
+	}
+	pa := &pausedAction{
+		action:         action,
+		expirationTime: r.clock.Now().Add(pauseDuration),
+		resume:         make(chan struct{}),
+	}
+	r.lock.Lock()
+	r.pausedActions[id.String()] = pa
+	r.lock.Unlock()
+	log.Printf("Action %s failed, and is paused for %s with ID %s", action.ActionDigest, pauseDuration, id)
+
+	timer, timerChannel := r.clock.NewTimer(pauseDuration)
+	select {
+	case <-timerChannel:
+	case <-pa.resume:
+		timer.Stop()
+	case <-ctx.Done():
+		timer.Stop()
+	}
+
+	// Prevent further commands from being executed, and wait for
+	// any running commands to complete.
+	r.lock.Lock()
+	delete(r.pausedActions, id.String())
+	r.lock.Unlock()
+	pa.execLock.Lock()
+	pa.closed = true
+	pa.execLock.Unlock()
+	return nil
+}
+
+func (r *pausedActionRegistry) ListPausedActions(ctx context.Context, request *emptypb.Empty) (*debugger.ListPausedActionsResponse, error) {
+	r.lock.Lock()
+	candidates := make([]*debugger.PausedAction, 0, len(r.pausedActions))
+	instanceNames := make([]digest.InstanceName, 0, len(r.pausedActions))
+	for id, pa := range r.pausedActions {
+		candidates = append(candidates, &debugger.PausedAction{
+			Id:             id,
+			InstanceName:   pa.action.InstanceName.String(),
+			ActionDigest:   pa.action.ActionDigest.GetProto(),
+			ExpirationTime: timestamppb.New(pa.expirationTime),
+		})
+		instanceNames = append(instanceNames, pa.action.InstanceName)
+	}
+	r.lock.Unlock()
+
+	// Perform authorization checks without holding any locks. Only
+	// return paused actions the caller is permitted to access.
+	pausedActions := make([]*debugger.PausedAction, 0, len(candidates))
+	for i, err := range r.authorizer.Authorize(ctx, instanceNames) {
+		if err == nil {
+			pausedActions = append(pausedActions, candidates[i])
+		}
+	}
+
+	sort.Slice(pausedActions, func(i, j int) bool {
+		return pausedActions[i].Id < pausedActions[j].Id
+	})
+	return &debugger.ListPausedActionsResponse{
+		PausedActions: pausedActions,
+	}, nil
+}
+
+// getPausedAction looks up a paused action by ID, and checks whether
+// the caller is authorized to access it.
+func (r *pausedActionRegistry) getPausedAction(ctx context.Context, id string) (*pausedAction, error) {
+	r.lock.Lock()
+	pa, ok := r.pausedActions[id]
+	r.lock.Unlock()
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "No paused action with ID %#v exists", id)
+	}
+	if err := auth.AuthorizeSingleInstanceName(ctx, r.authorizer, pa.action.InstanceName); err != nil {
+		return nil, util.StatusWrap(err, "Authorization")
+	}
+	return pa, nil
+}
+
+func (r *pausedActionRegistry) Exec(ctx context.Context, request *debugger.ExecRequest) (*debugger.ExecResponse, error) {
+	if len(request.Arguments) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "No arguments provided")
+	}
+	if request.Timeout != nil {
+		if err := request.Timeout.CheckValid(); err != nil {
+			return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid timeout")
+		}
+	}
+	pa, err := r.getPausedAction(ctx, request.PausedActionId)
+	if err != nil {
+		return nil, err
+	}
+
+	pa.execLock.Lock()
+	defer pa.execLock.Unlock()
+	if pa.closed {
+		return nil, status.Errorf(codes.NotFound, "No paused action with ID %#v exists", request.PausedActionId)
+	}
+
+	action := pa.action
+	environmentVariables := request.EnvironmentVariables
+	if len(environmentVariables) == 0 {
+		environmentVariables = action.EnvironmentVariables
+	}
+	workingDirectory := request.WorkingDirectory
+	if workingDirectory == "" {
+		workingDirectory = action.WorkingDirectory
+	}
+	ctxWithTimeout := ctx
+	if request.Timeout != nil {
+		var cancelTimeout context.CancelFunc
+		ctxWithTimeout, cancelTimeout = r.clock.NewContextWithTimeout(ctx, request.Timeout.AsDuration())
+		defer cancelTimeout()
+	}
+	runResponse, err := action.Runner.Run(ctxWithTimeout, &runner_pb.RunRequest{
+		Arguments:            request.Arguments,
+		EnvironmentVariables: environmentVariables,
+		WorkingDirectory:     workingDirectory,
+		StdoutPath:           action.BuildDirectoryPath.Append(debugStdoutComponent).String(),
+		StderrPath:           action.BuildDirectoryPath.Append(debugStderrComponent).String(),
+		InputRootDirectory:   action.BuildDirectoryPath.Append(inputRootDirectoryComponent).String(),
+		TemporaryDirectory:   action.BuildDirectoryPath.Append(temporaryDirectoryComponent).String(),
+		ServerLogsDirectory:  action.BuildDirectoryPath.Append(serverLogsDirectoryComponent).String(),
+	})
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to run command")
+	}
+
+	digestFunction := action.ActionDigest.GetDigestFunction()
+	stdoutDigest, err := action.BuildDirectory.UploadFile(ctx, debugStdoutComponent, digestFunction)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to store stdout")
+	}
+	stderrDigest, err := action.BuildDirectory.UploadFile(ctx, debugStderrComponent, digestFunction)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to store stderr")
+	}
+	return &debugger.ExecResponse{
+		ExitCode:     runResponse.ExitCode,
+		StdoutDigest: stdoutDigest.GetProto(),
+		StderrDigest: stderrDigest.GetProto(),
+	}, nil
+}
+
+func (r *pausedActionRegistry) Resume(ctx context.Context, request *debugger.ResumeRequest) (*emptypb.Empty, error) {
+	pa, err := r.getPausedAction(ctx, request.PausedActionId)
+	if err != nil {
+		return nil, err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !pa.resumed {
+		pa.resumed = true
+		close(pa.resume)
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,182 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/debugger"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestPausedActionRegistry(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	clock := mock.NewMockClock(ctrl)
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	authorizer := mock.NewMockAuthorizer(ctrl)
+	registry := builder.NewPausedActionRegistry(clock, uuidGenerator.Call, time.Hour, authorizer)
+
+	buildDirectory := mock.NewMockBuildDirectory(ctrl)
+	runner := mock.NewMockRunnerClient(ctrl)
+	instanceName := digest.MustNewInstanceName("hello")
+	actionDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "d41d8cd98f00b204e9800998ecf8427e", 123)
+	newPausableAction := func(pauseDuration time.Duration) *builder.PausableAction {
+		return &builder.PausableAction{
+			ActionDigest:         actionDigest,
+			InstanceName:         instanceName,
+			PauseDuration:        pauseDuration,
+			BuildDirectory:       buildDirectory,
+			BuildDirectoryPath:   ((*path.Trace)(nil)).Append(path.MustNewComponent("0")),
+			Runner:               runner,
+			EnvironmentVariables: map[string]string{"PATH": "/bin"},
+			WorkingDirectory:     "src",
+		}
+	}
+
+	// pauseAction pauses an action in the background, and waits
+	// for it to become visible to the Debugger service.
+	pauseAction := func(ctx context.Context, id string) (<-chan struct{}, <-chan error) {
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(id))
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		timer := mock.NewMockTimer(ctrl)
+		registered := make(chan struct{})
+		// The duration should be capped to the configured
+		// maximum.
+		clock.EXPECT().NewTimer(time.Hour).DoAndReturn(func(d time.Duration) (*mock.MockTimer, <-chan time.Time) {
+			close(registered)
+			return timer, nil
+		})
+		paused := make(chan struct{})
+		timer.EXPECT().Stop().DoAndReturn(func() bool {
+			close(paused)
+			return true
+		})
+		pauseErrors := make(chan error, 1)
+		go func() {
+			pauseErrors <- registry.PauseFailedAction(ctx, newPausableAction(24*time.Hour))
+		}()
+		<-registered
+		return paused, pauseErrors
+	}
+
+	t.Run("NotRequested", func(t *testing.T) {
+		// Actions that don't set the platform property should
+		// not be paused.
+		require.NoError(t, registry.PauseFailedAction(ctx, newPausableAction(0)))
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		// Callers that are not authorized to access the instance
+		// name of the action should not be able to see it, run
+		// commands inside of it, or resume it.
+		pauseCtx, cancelPause := context.WithCancel(ctx)
+		paused, pauseErrors := pauseAction(pauseCtx, "a1b0d4f9-6a53-4c0f-8e0c-5f3c33f4e0f1")
+		authorizer.EXPECT().Authorize(ctx, []digest.InstanceName{instanceName}).
+			Return([]error{status.Error(codes.PermissionDenied, "Permission denied")}).
+			Times(3)
+
+		response, err := registry.ListPausedActions(ctx, &emptypb.Empty{})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &debugger.ListPausedActionsResponse{}, response)
+
+		_, err = registry.Exec(ctx, &debugger.ExecRequest{
+			PausedActionId: "a1b0d4f9-6a53-4c0f-8e0c-5f3c33f4e0f1",
+			Arguments:      []string{"cat", "/etc/shadow"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Authorization: Permission denied"), err)
+
+		_, err = registry.Resume(ctx, &debugger.ResumeRequest{
+			PausedActionId: "a1b0d4f9-6a53-4c0f-8e0c-5f3c33f4e0f1",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Authorization: Permission denied"), err)
+
+		// The action should remain paused until the execution
+		// of the action is cancelled.
+		cancelPause()
+		<-paused
+		require.NoError(t, <-pauseErrors)
+	})
+
+	t.Run("ExecAndResume", func(t *testing.T) {
+		paused, pauseErrors := pauseAction(ctx, "36ebab65-3c4f-4faf-818b-2eabb4cd1b02")
+		authorizer.EXPECT().Authorize(ctx, []digest.InstanceName{instanceName}).Return([]error{nil}).Times(3)
+
+		response, err := registry.ListPausedActions(ctx, &emptypb.Empty{})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &debugger.ListPausedActionsResponse{
+			PausedActions: []*debugger.PausedAction{{
+				Id:           "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+				InstanceName: "hello",
+				ActionDigest: &remoteexecution.Digest{
+					Hash:      "d41d8cd98f00b204e9800998ecf8427e",
+					SizeBytes: 123,
+				},
+				ExpirationTime: &timestamppb.Timestamp{Seconds: 4600},
+			}},
+		}, response)
+
+		// Run a command inside the build directory. The
+		// environment of the failed action should be used.
+		runner.EXPECT().Run(gomock.Any(), testutil.EqProto(t, &runner_pb.RunRequest{
+			Arguments:            []string{"ls", "-l"},
+			EnvironmentVariables: map[string]string{"PATH": "/bin"},
+			WorkingDirectory:     "src",
+			StdoutPath:           "0/debug_stdout",
+			StderrPath:           "0/debug_stderr",
+			InputRootDirectory:   "0/root",
+			TemporaryDirectory:   "0/tmp",
+			ServerLogsDirectory:  "0/server_logs",
+		})).Return(&runner_pb.RunResponse{ExitCode: 2}, nil)
+		stdoutDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "7fa0a8e9d3e4b3c2a1f0e9d8c7b6a5f4", 42)
+		buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("debug_stdout"), gomock.Any()).Return(stdoutDigest, nil)
+		stderrDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "d41d8cd98f00b204e9800998ecf8427e", 0)
+		buildDirectory.EXPECT().UploadFile(ctx, path.MustNewComponent("debug_stderr"), gomock.Any()).Return(stderrDigest, nil)
+
+		execResponse, err := registry.Exec(ctx, &debugger.ExecRequest{
+			PausedActionId: "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+			Arguments:      []string{"ls", "-l"},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &debugger.ExecResponse{
+			ExitCode:     2,
+			StdoutDigest: stdoutDigest.GetProto(),
+			StderrDigest: stderrDigest.GetProto(),
+		}, execResponse)
+
+		// Resuming the action should cause PauseFailedAction()
+		// to return.
+		_, err = registry.Resume(ctx, &debugger.ResumeRequest{
+			PausedActionId: "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+		})
+		require.NoError(t, err)
+		<-paused
+		require.NoError(t, <-pauseErrors)
+
+		// The action should no longer be accessible.
+		authorizer.EXPECT().Authorize(ctx, []digest.InstanceName{}).Return([]error{})
+		response, err = registry.ListPausedActions(ctx, &emptypb.Empty{})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &debugger.ListPausedActionsResponse{}, response)
+
+		_, err = registry.Exec(ctx, &debugger.ExecRequest{
+			PausedActionId: "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+			Arguments:      []string{"ls", "-l"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "No paused action with ID \"36ebab65-3c4f-4faf-818b-2eabb4cd1b02\" exists"), err)
+	})
+}
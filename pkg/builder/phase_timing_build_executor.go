@@ -0,0 +1,59 @@
+package builder
+
+import (
+	"context"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_clock "github.com/buildbarn/bb-remote-execution/pkg/clock"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type phaseTimingBuildExecutor struct {
+	BuildExecutor
+	clock clock.Clock
+}
+
+// NewPhaseTimingBuildExecutor creates a decorator for BuildExecutor
+// that annotates ExecuteResponses to contain the amount of time spent
+// in the individual phases of fetching inputs and uploading outputs,
+// such as fetching directories and calling FindMissingBlobs().
+//
+// The time spent is measured by decorators of DirectoryFetcher,
+// FileFetcher and BlobAccess, which accumulate it into a
+// clock.PhaseTimer that this decorator attaches to the Context. To
+// ensure uploads of outputs are fully accounted for, this decorator
+// should be placed above any decorator that flushes pending writes to
+// storage.
+func NewPhaseTimingBuildExecutor(buildExecutor BuildExecutor, clock clock.Clock) BuildExecutor {
+	return &phaseTimingBuildExecutor{
+		BuildExecutor: buildExecutor,
+		clock:         clock,
+	}
+}
+
+func (be *phaseTimingBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	phaseTimer := re_clock.NewPhaseTimer(be.clock)
+	response := be.BuildExecutor.Execute(re_clock.NewContextWithPhaseTimer(ctx, phaseTimer), filePool, monitor, digestFunction, request, executionStateUpdates)
+
+	if phaseDurations, err := anypb.New(&resourceusage.ExecutionPhaseDurations{
+		DirectoryFetchDuration:   durationpb.New(phaseTimer.GetDuration(re_clock.PhaseDirectoryFetch)),
+		FileFetchDuration:        durationpb.New(phaseTimer.GetDuration(re_clock.PhaseFileFetch)),
+		OutputHashingDuration:    durationpb.New(phaseTimer.GetDuration(re_clock.PhaseOutputHashing)),
+		FindMissingBlobsDuration: durationpb.New(phaseTimer.GetDuration(re_clock.PhaseFindMissingBlobs)),
+		UploadDuration:           durationpb.New(phaseTimer.GetDuration(re_clock.PhaseUpload)),
+	}); err == nil {
+		response.Result.ExecutionMetadata.AuxiliaryMetadata = append(response.Result.ExecutionMetadata.AuxiliaryMetadata, phaseDurations)
+	} else {
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to marshal execution phase durations"))
+	}
+	return response
+}
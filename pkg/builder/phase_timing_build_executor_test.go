@@ -0,0 +1,98 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	re_clock "github.com/buildbarn/bb-remote-execution/pkg/clock"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestPhaseTimingBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	request := &remoteworker.DesiredState_Executing{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "d41d8cd98f00b204e9800998ecf8427e",
+			SizeBytes: 123,
+		},
+	}
+
+	// Let the base BuildExecutor perform some operations that
+	// belong to phases that are tracked.
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	baseBuildExecutor.EXPECT().Execute(
+		gomock.Any(),
+		filesystem.InMemoryFilePool,
+		monitor,
+		digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5),
+		request,
+		gomock.Any()).DoAndReturn(func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		m := re_clock.StartPhase(ctx, re_clock.PhaseDirectoryFetch)
+		clock.EXPECT().Now().Return(time.Unix(1002, 0))
+		m.Finish()
+
+		clock.EXPECT().Now().Return(time.Unix(1010, 0))
+		m = re_clock.StartPhase(ctx, re_clock.PhaseFindMissingBlobs)
+		clock.EXPECT().Now().Return(time.Unix(1010, 500000000))
+		m.Finish()
+
+		clock.EXPECT().Now().Return(time.Unix(1011, 0))
+		m = re_clock.StartPhase(ctx, re_clock.PhaseUpload)
+		clock.EXPECT().Now().Return(time.Unix(1014, 0))
+		m.Finish()
+
+		return &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExitCode:          1,
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		}
+	})
+
+	executionStateUpdates := make(chan *remoteworker.CurrentState_Executing, 3)
+	buildExecutor := builder.NewPhaseTimingBuildExecutor(baseBuildExecutor, clock)
+	executeResponse := buildExecutor.Execute(
+		ctx,
+		filesystem.InMemoryFilePool,
+		monitor,
+		digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5),
+		request,
+		executionStateUpdates)
+
+	// The execute response should contain the durations of all
+	// phases, including the ones that were not entered.
+	phaseDurations, err := anypb.New(&resourceusage.ExecutionPhaseDurations{
+		DirectoryFetchDuration:   durationpb.New(2 * time.Second),
+		FileFetchDuration:        &durationpb.Duration{},
+		OutputHashingDuration:    &durationpb.Duration{},
+		FindMissingBlobsDuration: durationpb.New(500 * time.Millisecond),
+		UploadDuration:           durationpb.New(3 * time.Second),
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			ExitCode: 1,
+			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+				AuxiliaryMetadata: []*anypb.Any{phaseDurations},
+			},
+		},
+	}, executeResponse)
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+// +build !linux
+
+package builder
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetPhysicalMemoryBytes returns the total amount of physical memory
+// of the system. On this operating system this functionality is not
+// available.
+func GetPhysicalMemoryBytes() (uint64, error) {
+	return 0, status.Error(codes.Unimplemented, "Obtaining the amount of physical memory is not supported on this platform")
+}
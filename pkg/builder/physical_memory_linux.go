@@ -0,0 +1,21 @@
+//go:build linux
+// +build linux
+
+package builder
+
+import (
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+)
+
+// GetPhysicalMemoryBytes returns the total amount of physical memory
+// of the system.
+func GetPhysicalMemoryBytes() (uint64, error) {
+	var sysinfo unix.Sysinfo_t
+	if err := unix.Sysinfo(&sysinfo); err != nil {
+		return 0, util.StatusWrapWithCode(err, codes.Internal, "Failed to obtain system information")
+	}
+	return uint64(sysinfo.Totalram) * uint64(sysinfo.Unit), nil
+}
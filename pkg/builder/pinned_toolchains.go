@@ -0,0 +1,104 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc/codes"
+)
+
+// GetPinnedToolchainName returns the name of the directory in which
+// MaterializePinnedToolchains() stores a toolchain, having the form
+// "${hash}-${size_bytes}". Actions refer to pinned toolchains using
+// the same notation.
+func GetPinnedToolchainName(rootDirectoryDigest digest.Digest) path.Component {
+	return path.MustNewComponent(fmt.Sprintf("%s-%d", rootDirectoryDigest.GetHashString(), rootDirectoryDigest.GetSizeBytes()))
+}
+
+// MaterializePinnedToolchains ensures that a directory on local disk
+// contains a set of toolchains, each stored in a subdirectory that is
+// named after the digest of its root Directory message. Toolchains
+// that are already present are left untouched, while all other
+// contents of the directory are removed.
+//
+// Toolchains are first extracted under a temporary name, and renamed
+// once complete. This ensures that a toolchain whose extraction got
+// interrupted is never used.
+func MaterializePinnedToolchains(ctx context.Context, toolchainsDirectory filesystem.Directory, rootDirectoryDigests []digest.Digest, directoryFetcher cas.DirectoryFetcher, fileFetcher cas.FileFetcher, fileFetcherSemaphore *semaphore.Weighted) error {
+	missingToolchains := make(map[path.Component]digest.Digest, len(rootDirectoryDigests))
+	for _, rootDirectoryDigest := range rootDirectoryDigests {
+		missingToolchains[GetPinnedToolchainName(rootDirectoryDigest)] = rootDirectoryDigest
+	}
+
+	// Remove toolchains that are no longer pinned, and any
+	// leftovers of extractions that got interrupted.
+	entries, err := toolchainsDirectory.ReadDir()
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to read contents of toolchains directory")
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if _, ok := missingToolchains[name]; ok && entry.Type() == filesystem.FileTypeDirectory {
+			delete(missingToolchains, name)
+		} else if err := toolchainsDirectory.RemoveAll(name); err != nil {
+			return util.StatusWrapfWithCode(err, codes.Internal, "Failed to remove %#v", name.String())
+		}
+	}
+
+	names := make([]path.Component, 0, len(missingToolchains))
+	for name := range missingToolchains {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i].String() < names[j].String()
+	})
+	for _, name := range names {
+		if err := materializePinnedToolchain(ctx, toolchainsDirectory, name, missingToolchains[name], directoryFetcher, fileFetcher, fileFetcherSemaphore); err != nil {
+			return util.StatusWrapf(err, "Failed to materialize toolchain %#v", name.String())
+		}
+	}
+	return nil
+}
+
+func materializePinnedToolchain(ctx context.Context, toolchainsDirectory filesystem.Directory, name path.Component, rootDirectoryDigest digest.Digest, directoryFetcher cas.DirectoryFetcher, fileFetcher cas.FileFetcher, fileFetcherSemaphore *semaphore.Weighted) error {
+	temporaryName := path.MustNewComponent(".tmp-" + name.String())
+	if err := toolchainsDirectory.Mkdir(temporaryName, 0o755); err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to create temporary directory")
+	}
+	temporaryDirectory, err := toolchainsDirectory.EnterDirectory(temporaryName)
+	if err != nil {
+		toolchainsDirectory.RemoveAll(temporaryName)
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to enter temporary directory")
+	}
+
+	buildDirectory := NewNaiveBuildDirectory(
+		temporaryDirectory,
+		directoryFetcher,
+		fileFetcher,
+		fileFetcherSemaphore,
+		/* populationOptions = */ nil,
+		/* contentAddressableStorage = */ nil,
+		/* warmInputRootPool = */ nil)
+	err = buildDirectory.MergeDirectoryContents(ctx, util.DefaultErrorLogger, rootDirectoryDigest, nil)
+	if closeErr := buildDirectory.Close(); err == nil && closeErr != nil {
+		err = util.StatusWrapWithCode(closeErr, codes.Internal, "Failed to close temporary directory")
+	}
+	if err != nil {
+		toolchainsDirectory.RemoveAll(temporaryName)
+		return err
+	}
+
+	if err := toolchainsDirectory.Rename(temporaryName, toolchainsDirectory, name); err != nil {
+		toolchainsDirectory.RemoveAll(temporaryName)
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to rename temporary directory")
+	}
+	return nil
+}
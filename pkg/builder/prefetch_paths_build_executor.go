@@ -0,0 +1,225 @@
+package builder
+
+import (
+	"context"
+	"io"
+	stdpath "path"
+	"strings"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PrefetchPathsPlatformPropertyName is the name of the platform
+// property that may be used by actions to specify which files in the
+// input root should be downloaded before execution.
+const PrefetchPathsPlatformPropertyName = "prefetch-paths"
+
+type prefetchPathsBuildExecutor struct {
+	BuildExecutor
+	contentAddressableStorage blobstore.BlobAccess
+	directoryFetcher          cas.DirectoryFetcher
+	fileReadSemaphore         *semaphore.Weighted
+}
+
+// NewPrefetchPathsBuildExecutor creates a decorator for BuildExecutor
+// that downloads files in the input root of the action prior to
+// execution, if they match any of the glob patterns provided in the
+// "prefetch-paths" platform property. Files that don't match are
+// still loaded lazily.
+//
+// The platform property contains a comma separated list of patterns,
+// which are relative to the input root. Each pathname component of a
+// pattern is matched using the syntax of Go's path.Match(). In
+// addition to that, "**" matches any number of pathname components.
+//
+// Like PrefetchingBuildExecutor, this decorator is only of use on
+// workers that use a virtual build directory.
+func NewPrefetchPathsBuildExecutor(buildExecutor BuildExecutor, contentAddressableStorage blobstore.BlobAccess, directoryFetcher cas.DirectoryFetcher, fileReadSemaphore *semaphore.Weighted) BuildExecutor {
+	return &prefetchPathsBuildExecutor{
+		BuildExecutor:             buildExecutor,
+		contentAddressableStorage: contentAddressableStorage,
+		directoryFetcher:          directoryFetcher,
+		fileReadSemaphore:         fileReadSemaphore,
+	}
+}
+
+func (be *prefetchPathsBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	action := request.Action
+	if action == nil {
+		response := NewDefaultExecuteResponse(request)
+		attachErrorToExecuteResponse(response, status.Error(codes.InvalidArgument, "Request does not contain an action"))
+		return response
+	}
+
+	// Extract patterns from the platform properties.
+	var patterns [][]string
+	for _, property := range action.Platform.GetProperties() {
+		if property.Name == PrefetchPathsPlatformPropertyName {
+			for _, pattern := range strings.Split(property.Value, ",") {
+				if pattern = strings.TrimSpace(pattern); pattern != "" {
+					components := strings.Split(pattern, "/")
+					for _, component := range components {
+						if _, err := stdpath.Match(component, ""); err != nil {
+							response := NewDefaultExecuteResponse(request)
+							attachErrorToExecuteResponse(response, status.Errorf(codes.InvalidArgument, "Invalid prefetch path pattern %#v", pattern))
+							return response
+						}
+					}
+					patterns = append(patterns, components)
+				}
+			}
+		}
+	}
+
+	if len(patterns) > 0 {
+		group, groupCtx := errgroup.WithContext(ctx)
+		pp := pathPrefetcher{
+			context:                   groupCtx,
+			group:                     group,
+			patterns:                  patterns,
+			digestFunction:            digestFunction,
+			contentAddressableStorage: be.contentAddressableStorage,
+			directoryFetcher:          be.directoryFetcher,
+			fileReadSemaphore:         be.fileReadSemaphore,
+		}
+		initialState := make([]globPosition, 0, len(patterns))
+		for i := range patterns {
+			initialState = append(initialState, globPosition{pattern: i})
+		}
+		group.Go(func() error {
+			return pp.prefetchRecursively(nil, pp.closure(initialState), action.InputRootDigest)
+		})
+		if err := group.Wait(); err != nil {
+			response := NewDefaultExecuteResponse(request)
+			attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to prefetch input root"))
+			return response
+		}
+	}
+	return be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+}
+
+// globPosition is the position within one of the patterns that is
+// being matched.
+type globPosition struct {
+	pattern   int
+	component int
+}
+
+// pathPrefetcher is used by prefetchPathsBuildExecutor to recursively
+// traverse the input root, downloading all files that match any of
+// the patterns.
+type pathPrefetcher struct {
+	context                   context.Context
+	group                     *errgroup.Group
+	patterns                  [][]string
+	digestFunction            digest.Function
+	contentAddressableStorage blobstore.BlobAccess
+	directoryFetcher          cas.DirectoryFetcher
+	fileReadSemaphore         *semaphore.Weighted
+}
+
+// closure extends a set of positions with the ones that can be
+// reached by letting "**" match zero pathname components.
+func (pp *pathPrefetcher) closure(positions []globPosition) []globPosition {
+	for i := 0; i < len(positions); i++ {
+		p := positions[i]
+		if pattern := pp.patterns[p.pattern]; p.component < len(pattern) && pattern[p.component] == "**" {
+			positions = append(positions, globPosition{pattern: p.pattern, component: p.component + 1})
+		}
+	}
+	return positions
+}
+
+// step computes the set of positions after matching a single pathname
+// component.
+func (pp *pathPrefetcher) step(positions []globPosition, name string) []globPosition {
+	var newPositions []globPosition
+	for _, p := range positions {
+		pattern := pp.patterns[p.pattern]
+		if p.component < len(pattern) {
+			if pattern[p.component] == "**" {
+				newPositions = append(newPositions, p)
+			} else if matched, _ := stdpath.Match(pattern[p.component], name); matched {
+				newPositions = append(newPositions, globPosition{pattern: p.pattern, component: p.component + 1})
+			}
+		}
+	}
+	return pp.closure(newPositions)
+}
+
+// isMatch returns whether any of the patterns has been matched fully.
+func (pp *pathPrefetcher) isMatch(positions []globPosition) bool {
+	for _, p := range positions {
+		if p.component == len(pp.patterns[p.pattern]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (pp *pathPrefetcher) prefetchRecursively(pathTrace *path.Trace, positions []globPosition, rawDirectoryDigest *remoteexecution.Digest) error {
+	directoryDigest, err := pp.digestFunction.NewDigestFromProto(rawDirectoryDigest)
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to parse digest for directory %#v", pathTrace.String())
+	}
+	directory, err := pp.directoryFetcher.GetDirectory(pp.context, directoryDigest)
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to prefetch directory %#v", pathTrace.String())
+	}
+
+	for _, file := range directory.Files {
+		component, ok := path.NewComponent(file.Name)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "File %#v in directory %#v has an invalid name", file.Name, pathTrace.String())
+		}
+		if pp.isMatch(pp.step(positions, file.Name)) {
+			childPathTrace := pathTrace.Append(component)
+			fileDigest, err := pp.digestFunction.NewDigestFromProto(file.Digest)
+			if err != nil {
+				return util.StatusWrapf(err, "Failed to parse digest for file %#v", childPathTrace.String())
+			}
+
+			// Download files at a globally bounded
+			// concurrency. Just like PrefetchingBuildExecutor,
+			// perform a 1 byte read to load the file into
+			// the local cache.
+			if pp.context.Err() != nil || pp.fileReadSemaphore.Acquire(pp.context, 1) != nil {
+				return util.StatusFromContext(pp.context)
+			}
+			pp.group.Go(func() error {
+				var b [1]byte
+				_, err := pp.contentAddressableStorage.Get(pp.context, fileDigest).ReadAt(b[:], 0)
+				pp.fileReadSemaphore.Release(1)
+				if err != nil && err != io.EOF {
+					return util.StatusWrapf(err, "Failed to prefetch file %#v", childPathTrace.String())
+				}
+				return nil
+			})
+		}
+	}
+	for _, directory := range directory.Directories {
+		component, ok := path.NewComponent(directory.Name)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "Directory %#v in directory %#v has an invalid name", directory.Name, pathTrace.String())
+		}
+		if childPositions := pp.step(positions, directory.Name); len(childPositions) > 0 {
+			if err := pp.prefetchRecursively(pathTrace.Append(component), childPositions, directory.Digest); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPrefetchPathsBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	buildExecutor := builder.NewPrefetchPathsBuildExecutor(
+		baseBuildExecutor,
+		contentAddressableStorage,
+		directoryFetcher,
+		semaphore.NewWeighted(2))
+
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5)
+	executionStateUpdates := make(chan *remoteworker.CurrentState_Executing, 3)
+	newRequest := func(prefetchPaths ...string) *remoteworker.DesiredState_Executing {
+		platform := &remoteexecution.Platform{}
+		for _, value := range prefetchPaths {
+			platform.Properties = append(platform.Properties, &remoteexecution.Platform_Property{
+				Name:  "prefetch-paths",
+				Value: value,
+			})
+		}
+		return &remoteworker.DesiredState_Executing{
+			Action: &remoteexecution.Action{
+				InputRootDigest: &remoteexecution.Digest{
+					Hash:      "095afbd64b6358665546558583c64d38",
+					SizeBytes: 456,
+				},
+				Platform: platform,
+			},
+		}
+	}
+	successfulResponse := &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+		},
+	}
+
+	t.Run("NoPatterns", func(t *testing.T) {
+		// Without the platform property, the input root should
+		// not be traversed.
+		request := newRequest()
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(successfulResponse)
+
+		testutil.RequireEqualProto(
+			t,
+			successfulResponse,
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("InvalidPattern", func(t *testing.T) {
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+			Status: status.New(codes.InvalidArgument, "Invalid prefetch path pattern \"include/[\"").Proto(),
+		}, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, newRequest("include/["), executionStateUpdates))
+	})
+
+	// Input root that is used by the tests below.
+	expectInputRoot := func() {
+		directoryFetcher.EXPECT().GetDirectory(gomock.Any(), digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "095afbd64b6358665546558583c64d38", 456)).Return(&remoteexecution.Directory{
+			Directories: []*remoteexecution.DirectoryNode{
+				{
+					Name: "include",
+					Digest: &remoteexecution.Digest{
+						Hash:      "a1b8c9f1bcf2e7f0bb34a9b9d2e4a5c6",
+						SizeBytes: 100,
+					},
+				},
+				{
+					Name: "src",
+					Digest: &remoteexecution.Digest{
+						Hash:      "e9a5b7d1c3f2e4a6b8c0d2e4f6a8b0c2",
+						SizeBytes: 200,
+					},
+				},
+			},
+			Files: []*remoteexecution.FileNode{
+				{
+					Name: "config.h",
+					Digest: &remoteexecution.Digest{
+						Hash:      "3b1e6a9b2d9c8f0e1a2b3c4d5e6f7a8b",
+						SizeBytes: 10,
+					},
+				},
+				{
+					Name: "main.c",
+					Digest: &remoteexecution.Digest{
+						Hash:      "4c2f7bac3eadf90f2b3c4d5e6f7a8b9c",
+						SizeBytes: 20,
+					},
+				},
+			},
+		}, nil)
+	}
+	expectIncludeDirectory := func() {
+		directoryFetcher.EXPECT().GetDirectory(gomock.Any(), digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "a1b8c9f1bcf2e7f0bb34a9b9d2e4a5c6", 100)).Return(&remoteexecution.Directory{
+			Files: []*remoteexecution.FileNode{
+				{
+					Name: "stdio.h",
+					Digest: &remoteexecution.Digest{
+						Hash:      "5d3f8cbd4fbef01f3c4d5e6f7a8b9c0d",
+						SizeBytes: 30,
+					},
+				},
+			},
+		}, nil)
+	}
+
+	t.Run("FetchFailure", func(t *testing.T) {
+		expectInputRoot()
+		contentAddressableStorage.EXPECT().Get(gomock.Any(), digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "3b1e6a9b2d9c8f0e1a2b3c4d5e6f7a8b", 10)).
+			Return(buffer.NewBufferFromError(status.Error(codes.Internal, "Server on fire")))
+
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+			Status: status.New(codes.Internal, "Failed to prefetch input root: Failed to prefetch file \"config.h\": Server on fire").Proto(),
+		}, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, newRequest("*.h"), executionStateUpdates))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Only header files in the root directory and all
+		// files underneath "include" should be downloaded. The
+		// "src" directory should not be traversed.
+		expectInputRoot()
+		expectIncludeDirectory()
+		contentAddressableStorage.EXPECT().Get(gomock.Any(), digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "3b1e6a9b2d9c8f0e1a2b3c4d5e6f7a8b", 10)).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("#define X\n")))
+		contentAddressableStorage.EXPECT().Get(gomock.Any(), digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "5d3f8cbd4fbef01f3c4d5e6f7a8b9c0d", 30)).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("int printf(const char *, ...);")))
+		request := newRequest("*.h, include/**")
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(successfulResponse)
+
+		testutil.RequireEqualProto(
+			t,
+			successfulResponse,
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+}
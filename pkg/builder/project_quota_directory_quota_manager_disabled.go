@@ -0,0 +1,17 @@
+//go:build !linux
+// +build !linux
+
+package builder
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewProjectQuotaDirectoryQuotaManager creates a DirectoryQuotaManager
+// that uses project quotas, as supported by XFS and ext4, to let the
+// kernel enforce limits on the size of directories. On this operating
+// system this functionality is not available.
+func NewProjectQuotaDirectoryQuotaManager(blockDevicePath string, firstProjectID, projectIDCount uint32) (DirectoryQuotaManager, error) {
+	return nil, status.Error(codes.Unimplemented, "Project quotas are not supported on this platform")
+}
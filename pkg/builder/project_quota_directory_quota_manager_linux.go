@@ -0,0 +1,186 @@
+//go:build linux
+// +build linux
+
+package builder
+
+import (
+	"sync"
+	"unsafe"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// FS_IOC_FSGETXATTR and FS_IOC_FSSETXATTR, used to obtain and
+	// adjust the project ID of a directory.
+	fsIocFsgetxattr = 0x801c581f
+	fsIocFssetxattr = 0x401c5820
+
+	// FS_XFLAG_PROJINHERIT, causing files and directories created
+	// inside a directory to inherit its project ID.
+	fsXflagProjinherit = 0x200
+
+	// Q_GETQUOTA and Q_SETQUOTA, applied to PRJQUOTA.
+	qGetquota = 0x800007
+	qSetquota = 0x800008
+	prjquota  = 2
+
+	// QIF_BLIMITS, indicating that the block limits of a dqblk are
+	// to be set.
+	qifBlimits = 1
+
+	// The size of a block, as used by the limits of a dqblk.
+	qifDqblksize = 1024
+)
+
+// fsxattr corresponds to struct fsxattr, as used by FS_IOC_FSGETXATTR.
+type fsxattr struct {
+	Xflags     uint32
+	Extsize    uint32
+	Nextents   uint32
+	Projid     uint32
+	Cowextsize uint32
+	Pad        [8]byte
+}
+
+// ifDqblk corresponds to struct if_dqblk, as used by quotactl(2).
+type ifDqblk struct {
+	Bhardlimit uint64
+	Bsoftlimit uint64
+	Curspace   uint64
+	Ihardlimit uint64
+	Isoftlimit uint64
+	Curinodes  uint64
+	Btime      uint64
+	Itime      uint64
+	Valid      uint32
+}
+
+type projectQuotaDirectoryQuotaManager struct {
+	blockDevicePath *byte
+
+	lock           sync.Mutex
+	freeProjectIDs []uint32
+}
+
+// NewProjectQuotaDirectoryQuotaManager creates a DirectoryQuotaManager
+// that uses project quotas, as supported by XFS and ext4, to let the
+// kernel enforce limits on the size of directories. Every directory is
+// assigned a distinct project ID, taken from a fixed range.
+func NewProjectQuotaDirectoryQuotaManager(blockDevicePath string, firstProjectID, projectIDCount uint32) (DirectoryQuotaManager, error) {
+	blockDevicePathPtr, err := unix.BytePtrFromString(blockDevicePath)
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid block device path")
+	}
+	if firstProjectID == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Project ID 0 is reserved, and cannot be used")
+	}
+	freeProjectIDs := make([]uint32, 0, projectIDCount)
+	for i := uint32(0); i < projectIDCount; i++ {
+		freeProjectIDs = append(freeProjectIDs, firstProjectID+projectIDCount-1-i)
+	}
+	return &projectQuotaDirectoryQuotaManager{
+		blockDevicePath: blockDevicePathPtr,
+		freeProjectIDs:  freeProjectIDs,
+	}, nil
+}
+
+func (dqm *projectQuotaDirectoryQuotaManager) quotactl(cmd int, projectID uint32, dqblk *ifDqblk) error {
+	if _, _, errno := unix.Syscall6(
+		unix.SYS_QUOTACTL,
+		uintptr(cmd<<8|prjquota),
+		uintptr(unsafe.Pointer(dqm.blockDevicePath)),
+		uintptr(projectID),
+		uintptr(unsafe.Pointer(dqblk)),
+		0,
+		0,
+	); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func (dqm *projectQuotaDirectoryQuotaManager) setLimit(projectID uint32, maximumSizeBytes int64) error {
+	return dqm.quotactl(qSetquota, projectID, &ifDqblk{
+		Bhardlimit: uint64((maximumSizeBytes + qifDqblksize - 1) / qifDqblksize),
+		Valid:      qifBlimits,
+	})
+}
+
+func (dqm *projectQuotaDirectoryQuotaManager) NewDirectoryQuota(directoryPath string, maximumSizeBytes int64) (DirectoryQuota, error) {
+	dqm.lock.Lock()
+	if len(dqm.freeProjectIDs) == 0 {
+		dqm.lock.Unlock()
+		return nil, status.Error(codes.ResourceExhausted, "No project IDs available")
+	}
+	projectID := dqm.freeProjectIDs[len(dqm.freeProjectIDs)-1]
+	dqm.freeProjectIDs = dqm.freeProjectIDs[:len(dqm.freeProjectIDs)-1]
+	dqm.lock.Unlock()
+
+	if err := dqm.assignProjectID(directoryPath, projectID, maximumSizeBytes); err != nil {
+		dqm.releaseProjectID(projectID)
+		return nil, err
+	}
+	return &projectQuotaDirectoryQuota{
+		manager:   dqm,
+		projectID: projectID,
+	}, nil
+}
+
+func (dqm *projectQuotaDirectoryQuotaManager) assignProjectID(directoryPath string, projectID uint32, maximumSizeBytes int64) error {
+	// Set the limit prior to assigning the project ID to the
+	// directory, so that no writes go unaccounted.
+	if err := dqm.setLimit(projectID, maximumSizeBytes); err != nil {
+		return util.StatusWrapfWithCode(err, codes.Internal, "Failed to set limit of project ID %d", projectID)
+	}
+
+	fd, err := unix.Open(directoryPath, unix.O_DIRECTORY|unix.O_RDONLY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return util.StatusWrapfWithCode(err, codes.Internal, "Failed to open directory %#v", directoryPath)
+	}
+	defer unix.Close(fd)
+
+	var attr fsxattr
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), fsIocFsgetxattr, uintptr(unsafe.Pointer(&attr))); errno != 0 {
+		return util.StatusWrapfWithCode(errno, codes.Internal, "Failed to obtain attributes of directory %#v", directoryPath)
+	}
+	attr.Projid = projectID
+	attr.Xflags |= fsXflagProjinherit
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), fsIocFssetxattr, uintptr(unsafe.Pointer(&attr))); errno != 0 {
+		return util.StatusWrapfWithCode(errno, codes.Internal, "Failed to set project ID of directory %#v", directoryPath)
+	}
+	return nil
+}
+
+func (dqm *projectQuotaDirectoryQuotaManager) releaseProjectID(projectID uint32) {
+	dqm.lock.Lock()
+	dqm.freeProjectIDs = append(dqm.freeProjectIDs, projectID)
+	dqm.lock.Unlock()
+}
+
+type projectQuotaDirectoryQuota struct {
+	manager   *projectQuotaDirectoryQuotaManager
+	projectID uint32
+}
+
+func (dq *projectQuotaDirectoryQuota) GetSizeBytes() (int64, error) {
+	var dqblk ifDqblk
+	if err := dq.manager.quotactl(qGetquota, dq.projectID, &dqblk); err != nil {
+		return 0, util.StatusWrapfWithCode(err, codes.Internal, "Failed to obtain usage of project ID %d", dq.projectID)
+	}
+	return int64(dqblk.Curspace), nil
+}
+
+func (dq *projectQuotaDirectoryQuota) Release() error {
+	// Lift the limit, so that the project ID can be reused with a
+	// different limit.
+	if err := dq.manager.setLimit(dq.projectID, 0); err != nil {
+		return util.StatusWrapfWithCode(err, codes.Internal, "Failed to clear limit of project ID %d", dq.projectID)
+	}
+	dq.manager.releaseProjectID(dq.projectID)
+	return nil
+}
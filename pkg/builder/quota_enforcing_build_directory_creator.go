@@ -0,0 +1,166 @@
+package builder
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"time"
+
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/errorclassification"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+type quotaEnforcingBuildDirectoryCreator struct {
+	base               BuildDirectoryCreator
+	buildDirectoryPath string
+	quotaManager       DirectoryQuotaManager
+	maximumSizeBytes   int64
+	clock              clock.Clock
+	pollingInterval    time.Duration
+}
+
+// NewQuotaEnforcingBuildDirectoryCreator is an adapter for
+// BuildDirectoryCreator that limits the amount of disk space that the
+// build directory of a single action may consume. It is intended to be
+// used in combination with native build directories, for which file
+// pool quotas have no effect.
+//
+// The size of the build directory is measured periodically while the
+// action runs. Once the limit is exceeded, the action is terminated
+// with RESOURCE_EXHAUSTED. The largest size observed is reported by
+// means of a BuildDirectoryResourceUsage message. The limit is scaled
+// for actions run through NewResourceLimitsScalingBuildExecutor().
+func NewQuotaEnforcingBuildDirectoryCreator(base BuildDirectoryCreator, buildDirectoryPath string, quotaManager DirectoryQuotaManager, maximumSizeBytes int64, clock clock.Clock, pollingInterval time.Duration) BuildDirectoryCreator {
+	return &quotaEnforcingBuildDirectoryCreator{
+		base:               base,
+		buildDirectoryPath: buildDirectoryPath,
+		quotaManager:       quotaManager,
+		maximumSizeBytes:   maximumSizeBytes,
+		clock:              clock,
+		pollingInterval:    pollingInterval,
+	}
+}
+
+func (dc *quotaEnforcingBuildDirectoryCreator) GetBuildDirectory(ctx context.Context, actionDigestIfNotRunInParallel *digest.Digest) (BuildDirectory, *path.Trace, error) {
+	buildDirectory, buildDirectoryPath, err := dc.base.GetBuildDirectory(ctx, actionDigestIfNotRunInParallel)
+	if err != nil {
+		return nil, nil, err
+	}
+	maximumSizeBytes := scaleResourceLimit(ctx, dc.maximumSizeBytes)
+	quota, err := dc.quotaManager.NewDirectoryQuota(filepath.Join(dc.buildDirectoryPath, buildDirectoryPath.String()), maximumSizeBytes)
+	if err != nil {
+		buildDirectory.Close()
+		return nil, nil, util.StatusWrapf(err, "Failed to apply quota to build directory %#v", buildDirectoryPath.String())
+	}
+	return &quotaEnforcingBuildDirectory{
+		BuildDirectory:     buildDirectory,
+		creator:            dc,
+		buildDirectoryPath: buildDirectoryPath.String(),
+		maximumSizeBytes:   maximumSizeBytes,
+		quota:              quota,
+	}, buildDirectoryPath, nil
+}
+
+type quotaEnforcingBuildDirectory struct {
+	BuildDirectory
+	creator            *quotaEnforcingBuildDirectoryCreator
+	buildDirectoryPath string
+	maximumSizeBytes   int64
+	quota              DirectoryQuota
+
+	lock          sync.Mutex
+	sizeBytesPeak int64
+	stopPolling   chan struct{}
+	pollingDone   chan struct{}
+}
+
+// measureSize obtains the current size of the build directory, and
+// updates the largest size observed. It returns an error if the size
+// exceeds the limit.
+func (d *quotaEnforcingBuildDirectory) measureSize() error {
+	sizeBytes, err := d.quota.GetSizeBytes()
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to obtain size of build directory %#v", d.buildDirectoryPath)
+	}
+
+	d.lock.Lock()
+	if d.sizeBytesPeak < sizeBytes {
+		d.sizeBytesPeak = sizeBytes
+	}
+	d.lock.Unlock()
+
+	if maximumSizeBytes := d.maximumSizeBytes; sizeBytes > maximumSizeBytes {
+		return classifyError(
+			errorclassification.Domain_RESOURCE_LIMIT,
+			status.Errorf(codes.ResourceExhausted, "Build directory has a size of %d bytes, which exceeds the maximum of %d bytes", sizeBytes, maximumSizeBytes))
+	}
+	return nil
+}
+
+func (d *quotaEnforcingBuildDirectory) InstallHooks(ctx context.Context, digestFunction digest.Function, filePool re_filesystem.FilePool, errorLogger util.ErrorLogger) {
+	d.BuildDirectory.InstallHooks(ctx, digestFunction, filePool, errorLogger)
+
+	// Periodically measure the size of the build directory,
+	// terminating the action if it has become too large.
+	d.stopPolling = make(chan struct{})
+	d.pollingDone = make(chan struct{})
+	go func() {
+		defer close(d.pollingDone)
+		for {
+			timer, timerChannel := d.creator.clock.NewTimer(d.creator.pollingInterval)
+			select {
+			case <-d.stopPolling:
+				timer.Stop()
+				return
+			case <-timerChannel:
+			}
+
+			if err := d.measureSize(); err != nil {
+				errorLogger.Log(err)
+				return
+			}
+		}
+	}()
+}
+
+// GetResourceUsage returns the largest size of the build directory
+// that was observed, including a final measurement taken at the time
+// of calling.
+func (d *quotaEnforcingBuildDirectory) GetResourceUsage() (*anypb.Any, error) {
+	if err := d.measureSize(); status.Code(err) != codes.OK && status.Code(err) != codes.ResourceExhausted {
+		return nil, err
+	}
+
+	d.lock.Lock()
+	sizeBytesPeak := d.sizeBytesPeak
+	d.lock.Unlock()
+	return anypb.New(&resourceusage.BuildDirectoryResourceUsage{
+		SizeBytesPeak:    sizeBytesPeak,
+		MaximumSizeBytes: d.maximumSizeBytes,
+	})
+}
+
+func (d *quotaEnforcingBuildDirectory) Close() error {
+	if d.stopPolling != nil {
+		close(d.stopPolling)
+		<-d.pollingDone
+	}
+	err1 := d.BuildDirectory.Close()
+	err2 := d.quota.Release()
+	if err1 != nil {
+		return err1
+	}
+	if err2 != nil {
+		return util.StatusWrapf(err2, "Failed to release quota of build directory %#v", d.buildDirectoryPath)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestQuotaEnforcingBuildDirectoryCreator(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildDirectoryCreator := mock.NewMockBuildDirectoryCreator(ctrl)
+	quotaManager := mock.NewMockDirectoryQuotaManager(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	buildDirectoryCreator := builder.NewQuotaEnforcingBuildDirectoryCreator(
+		baseBuildDirectoryCreator,
+		"/worker/build",
+		quotaManager,
+		1000,
+		clock,
+		time.Second)
+	actionDigest := digest.MustNewDigest("debian8", remoteexecution.DigestFunction_SHA256, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", 0)
+	buildDirectoryPath := ((*path.Trace)(nil)).Append(path.MustNewComponent("e3b0c44298fc1c14"))
+
+	t.Run("GetBuildDirectoryFailure", func(t *testing.T) {
+		baseBuildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest).
+			Return(nil, nil, status.Error(codes.Internal, "No space left on device"))
+
+		_, _, err := buildDirectoryCreator.GetBuildDirectory(ctx, &actionDigest)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "No space left on device"), err)
+	})
+
+	t.Run("NewDirectoryQuotaFailure", func(t *testing.T) {
+		// Failure to apply a quota should cause the build
+		// directory to be released.
+		baseBuildDirectory := mock.NewMockBuildDirectory(ctrl)
+		baseBuildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest).
+			Return(baseBuildDirectory, buildDirectoryPath, nil)
+		quotaManager.EXPECT().NewDirectoryQuota("/worker/build/e3b0c44298fc1c14", int64(1000)).
+			Return(nil, status.Error(codes.ResourceExhausted, "No project IDs available"))
+		baseBuildDirectory.EXPECT().Close()
+
+		_, _, err := buildDirectoryCreator.GetBuildDirectory(ctx, &actionDigest)
+		testutil.RequireEqualStatus(t, status.Error(codes.ResourceExhausted, "Failed to apply quota to build directory \"e3b0c44298fc1c14\": No project IDs available"), err)
+	})
+
+	t.Run("QuotaExceeded", func(t *testing.T) {
+		baseBuildDirectory := mock.NewMockBuildDirectory(ctrl)
+		baseBuildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest).
+			Return(baseBuildDirectory, buildDirectoryPath, nil)
+		quota := mock.NewMockDirectoryQuota(ctrl)
+		quotaManager.EXPECT().NewDirectoryQuota("/worker/build/e3b0c44298fc1c14", int64(1000)).Return(quota, nil)
+
+		buildDirectory, actualBuildDirectoryPath, err := buildDirectoryCreator.GetBuildDirectory(ctx, &actionDigest)
+		require.NoError(t, err)
+		require.Equal(t, buildDirectoryPath, actualBuildDirectoryPath)
+
+		// Install hooks. This should cause the size of the
+		// build directory to be measured periodically.
+		filePool := mock.NewMockFilePool(ctrl)
+		errorLogger := mock.NewMockErrorLogger(ctrl)
+		digestFunction := actionDigest.GetDigestFunction()
+		baseBuildDirectory.EXPECT().InstallHooks(ctx, digestFunction, filePool, errorLogger)
+		timer1 := mock.NewMockTimer(ctrl)
+		timerChannel1 := make(chan time.Time, 1)
+		clock.EXPECT().NewTimer(time.Second).Return(timer1, timerChannel1)
+		timer2 := mock.NewMockTimer(ctrl)
+		timerChannel2 := make(chan time.Time, 1)
+		wait := make(chan struct{})
+		clock.EXPECT().NewTimer(time.Second).DoAndReturn(func(d time.Duration) (*mock.MockTimer, <-chan time.Time) {
+			close(wait)
+			return timer2, timerChannel2
+		})
+		buildDirectory.InstallHooks(ctx, digestFunction, filePool, errorLogger)
+
+		// Let the first measurement succeed.
+		quota.EXPECT().GetSizeBytes().Return(int64(400), nil)
+		timerChannel1 <- time.Unix(1000, 0)
+		<-wait
+
+		// The second measurement exceeds the limit, meaning
+		// the action should be terminated.
+		wait = make(chan struct{})
+		quota.EXPECT().GetSizeBytes().Return(int64(1200), nil)
+		var loggedErr error
+		errorLogger.EXPECT().Log(gomock.Any()).Do(func(err error) {
+			loggedErr = err
+			close(wait)
+		})
+		timerChannel2 <- time.Unix(1001, 0)
+		<-wait
+		s := status.Convert(loggedErr)
+		require.Equal(t, codes.ResourceExhausted, s.Code())
+		require.Equal(t, "Build directory has a size of 1200 bytes, which exceeds the maximum of 1000 bytes", s.Message())
+
+		// The peak size should be reported, even if the build
+		// directory shrunk in the meantime.
+		quota.EXPECT().GetSizeBytes().Return(int64(300), nil)
+		resourceUsage, err := buildDirectory.(builder.ResourceUsageReportingBuildDirectory).GetResourceUsage()
+		require.NoError(t, err)
+		var buildDirectoryResourceUsage resourceusage.BuildDirectoryResourceUsage
+		require.NoError(t, resourceUsage.UnmarshalTo(&buildDirectoryResourceUsage))
+		testutil.RequireEqualProto(t, &resourceusage.BuildDirectoryResourceUsage{
+			SizeBytesPeak:    1200,
+			MaximumSizeBytes: 1000,
+		}, &buildDirectoryResourceUsage)
+
+		// Closing the build directory should release the
+		// quota after the build directory is removed.
+		gomock.InOrder(
+			baseBuildDirectory.EXPECT().Close(),
+			quota.EXPECT().Release())
+		require.NoError(t, buildDirectory.Close())
+	})
+
+	t.Run("ReportedWithoutPolling", func(t *testing.T) {
+		// Even if the action completes before the size of the
+		// build directory is measured periodically, its size
+		// should be reported.
+		baseBuildDirectory := mock.NewMockBuildDirectory(ctrl)
+		baseBuildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest).
+			Return(baseBuildDirectory, buildDirectoryPath, nil)
+		quota := mock.NewMockDirectoryQuota(ctrl)
+		quotaManager.EXPECT().NewDirectoryQuota("/worker/build/e3b0c44298fc1c14", int64(1000)).Return(quota, nil)
+
+		buildDirectory, _, err := buildDirectoryCreator.GetBuildDirectory(ctx, &actionDigest)
+		require.NoError(t, err)
+
+		filePool := mock.NewMockFilePool(ctrl)
+		errorLogger := mock.NewMockErrorLogger(ctrl)
+		digestFunction := actionDigest.GetDigestFunction()
+		baseBuildDirectory.EXPECT().InstallHooks(ctx, digestFunction, filePool, errorLogger)
+		timer := mock.NewMockTimer(ctrl)
+		wait := make(chan struct{})
+		clock.EXPECT().NewTimer(time.Second).DoAndReturn(func(d time.Duration) (*mock.MockTimer, <-chan time.Time) {
+			close(wait)
+			return timer, nil
+		})
+		buildDirectory.InstallHooks(ctx, digestFunction, filePool, errorLogger)
+		<-wait
+
+		quota.EXPECT().GetSizeBytes().Return(int64(600), nil)
+		resourceUsage, err := buildDirectory.(builder.ResourceUsageReportingBuildDirectory).GetResourceUsage()
+		require.NoError(t, err)
+		expectedResourceUsage, err := anypb.New(&resourceusage.BuildDirectoryResourceUsage{
+			SizeBytesPeak:    600,
+			MaximumSizeBytes: 1000,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, expectedResourceUsage, resourceUsage)
+
+		timer.EXPECT().Stop()
+		baseBuildDirectory.EXPECT().Close()
+		quota.EXPECT().Release()
+		require.NoError(t, buildDirectory.Close())
+	})
+}
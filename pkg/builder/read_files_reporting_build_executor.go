@@ -0,0 +1,74 @@
+package builder
+
+import (
+	"context"
+	"strings"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+type readFilesReportingBuildExecutor struct {
+	BuildExecutor
+	contentAddressableStorage blobstore.BlobAccess
+}
+
+// NewReadFilesReportingBuildExecutor creates a decorator for
+// BuildExecutor that records which files in the input root are read by
+// the build action. The list of paths is stored in the Content
+// Addressable Storage, and a reference to it is attached to the
+// auxiliary metadata of the ActionResult in the form of an
+// InputRootReadFiles message.
+//
+// File accesses are only reported if the build directory is backed by
+// a virtual file system. This decorator needs to be placed below
+// PrefetchingBuildExecutor, as it does not forward the
+// UnreadDirectoryMonitor that is provided to it.
+func NewReadFilesReportingBuildExecutor(buildExecutor BuildExecutor, contentAddressableStorage blobstore.BlobAccess) BuildExecutor {
+	return &readFilesReportingBuildExecutor{
+		BuildExecutor:             buildExecutor,
+		contentAddressableStorage: contentAddressableStorage,
+	}
+}
+
+func (be *readFilesReportingBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	readFileRecordingMonitor := access.NewReadFileRecordingUnreadDirectoryMonitor(monitor)
+	response := be.BuildExecutor.Execute(ctx, filePool, readFileRecordingMonitor, digestFunction, request, executionStateUpdates)
+
+	// Store the list of paths in the CAS.
+	readFiles := readFileRecordingMonitor.GetReadFiles()
+	var pathsData strings.Builder
+	for _, p := range readFiles {
+		pathsData.WriteString(p)
+		pathsData.WriteByte('\n')
+	}
+	pathsBlob := []byte(pathsData.String())
+	digestGenerator := digestFunction.NewGenerator(int64(len(pathsBlob)))
+	if _, err := digestGenerator.Write(pathsBlob); err != nil {
+		panic(err)
+	}
+	pathsDigest := digestGenerator.Sum()
+	if err := be.contentAddressableStorage.Put(ctx, pathsDigest, buffer.NewValidatedBufferFromByteSlice(pathsBlob)); err != nil {
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to store list of read input root files"))
+		return response
+	}
+
+	if readFilesMetadata, err := anypb.New(&resourceusage.InputRootReadFiles{
+		PathsDigest: pathsDigest.GetProto(),
+		PathsCount:  uint64(len(readFiles)),
+	}); err == nil {
+		response.Result.ExecutionMetadata.AuxiliaryMetadata = append(response.Result.ExecutionMetadata.AuxiliaryMetadata, readFilesMetadata)
+	} else {
+		attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to marshal list of read input root files"))
+	}
+	return response
+}
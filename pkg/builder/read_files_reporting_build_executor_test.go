@@ -0,0 +1,112 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestReadFilesReportingBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	buildExecutor := builder.NewReadFilesReportingBuildExecutor(baseBuildExecutor, contentAddressableStorage)
+
+	filePool := mock.NewMockFilePool(ctrl)
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5)
+	request := &remoteworker.DesiredState_Executing{
+		Action: &remoteexecution.Action{
+			InputRootDigest: &remoteexecution.Digest{
+				Hash:      "095afbd64b6358665546558583c64d38",
+				SizeBytes: 456,
+			},
+		},
+	}
+	executionStateUpdates := make(chan *remoteworker.CurrentState_Executing, 3)
+
+	// Let the build action read a couple of files. Activity should
+	// be forwarded to the monitor provided by the caller.
+	expectExecute := func() access.UnreadDirectoryMonitor {
+		rootUnreadDirectoryMonitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+		rootReadDirectoryMonitor := mock.NewMockReadDirectoryMonitor(ctrl)
+		rootUnreadDirectoryMonitor.EXPECT().ReadDirectory().Return(rootReadDirectoryMonitor)
+		rootReadDirectoryMonitor.EXPECT().ReadFile(path.MustNewComponent("hello.c"))
+		childUnreadDirectoryMonitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+		rootReadDirectoryMonitor.EXPECT().ResolvedDirectory(path.MustNewComponent("include")).Return(childUnreadDirectoryMonitor)
+		childReadDirectoryMonitor := mock.NewMockReadDirectoryMonitor(ctrl)
+		childUnreadDirectoryMonitor.EXPECT().ReadDirectory().Return(childReadDirectoryMonitor)
+		childReadDirectoryMonitor.EXPECT().ReadFile(path.MustNewComponent("stdio.h"))
+
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, gomock.Any(), digestFunction, request, executionStateUpdates).DoAndReturn(
+			func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+				rootReadDirectoryMonitor := monitor.ReadDirectory()
+				rootReadDirectoryMonitor.ReadFile(path.MustNewComponent("hello.c"))
+				rootReadDirectoryMonitor.ResolvedDirectory(path.MustNewComponent("include")).ReadDirectory().ReadFile(path.MustNewComponent("stdio.h"))
+				return &remoteexecution.ExecuteResponse{
+					Result: &remoteexecution.ActionResult{
+						ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+					},
+				}
+			})
+		return rootUnreadDirectoryMonitor
+	}
+	pathsDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "bce848128010dbc13f04ac7d3fb5dbf5", 24)
+
+	t.Run("StorageFailure", func(t *testing.T) {
+		monitor := expectExecute()
+		contentAddressableStorage.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+				b.Discard()
+				return status.Error(codes.Internal, "Server on fire")
+			})
+
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+			Status: status.New(codes.Internal, "Failed to store list of read input root files: Server on fire").Proto(),
+		}, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		monitor := expectExecute()
+		contentAddressableStorage.EXPECT().Put(ctx, pathsDigest, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+				data, err := b.ToByteSlice(1000)
+				require.NoError(t, err)
+				require.Equal(t, "hello.c\ninclude/stdio.h\n", string(data))
+				return nil
+			})
+
+		readFiles, err := anypb.New(&resourceusage.InputRootReadFiles{
+			PathsDigest: pathsDigest.GetProto(),
+			PathsCount:  2,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+					AuxiliaryMetadata: []*anypb.Any{readFiles},
+				},
+			},
+		}, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+}
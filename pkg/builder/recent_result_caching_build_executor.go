@@ -0,0 +1,121 @@
+package builder
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/eviction"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	recentResultCachingBuildExecutorPrometheusMetrics sync.Once
+
+	recentResultCachingBuildExecutorLookups = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "builder",
+			Name:      "recent_result_caching_build_executor_lookups_total",
+			Help:      "Number of actions for which RecentResultCachingBuildExecutor looked up a recently completed response, by outcome.",
+		},
+		[]string{"result"})
+	recentResultCachingBuildExecutorLookupsHit         = recentResultCachingBuildExecutorLookups.WithLabelValues("Hit")
+	recentResultCachingBuildExecutorLookupsMiss        = recentResultCachingBuildExecutorLookups.WithLabelValues("Miss")
+	recentResultCachingBuildExecutorLookupsExpired     = recentResultCachingBuildExecutorLookups.WithLabelValues("Expired")
+	recentResultCachingBuildExecutorLookupsUncacheable = recentResultCachingBuildExecutorLookups.WithLabelValues("Uncacheable")
+)
+
+type recentResult struct {
+	response    *remoteexecution.ExecuteResponse
+	completedAt time.Time
+}
+
+type recentResultCachingBuildExecutor struct {
+	BuildExecutor
+	clock      clock.Clock
+	maximumAge time.Duration
+
+	lock             sync.Mutex
+	results          map[digest.Digest]recentResult
+	maximumCacheSize int
+	evictionSet      eviction.Set[digest.Digest]
+}
+
+// NewRecentResultCachingBuildExecutor creates a decorator for
+// BuildExecutor that keeps the responses of recently completed actions
+// in memory, keyed by action digest. When the scheduler assigns an
+// identical action to the worker shortly after completion (e.g., due
+// to a client retrying after a lost WaitExecution() call), the
+// previous response is returned without executing the action again.
+//
+// Only responses of actions that succeeded and that do not have the
+// 'do_not_cache' flag set are retained. Responses are returned for at
+// most the provided maximum age.
+func NewRecentResultCachingBuildExecutor(base BuildExecutor, clock clock.Clock, maximumAge time.Duration, maximumCacheSize int, evictionSet eviction.Set[digest.Digest]) BuildExecutor {
+	recentResultCachingBuildExecutorPrometheusMetrics.Do(func() {
+		prometheus.MustRegister(recentResultCachingBuildExecutorLookups)
+	})
+
+	return &recentResultCachingBuildExecutor{
+		BuildExecutor: base,
+		clock:         clock,
+		maximumAge:    maximumAge,
+
+		results:          map[digest.Digest]recentResult{},
+		maximumCacheSize: maximumCacheSize,
+		evictionSet:      evictionSet,
+	}
+}
+
+func (be *recentResultCachingBuildExecutor) Execute(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	actionDigest, err := digestFunction.NewDigestFromProto(request.ActionDigest)
+	if err != nil || request.Action.GetDoNotCache() {
+		recentResultCachingBuildExecutorLookupsUncacheable.Inc()
+		return be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+	}
+
+	be.lock.Lock()
+	if result, ok := be.results[actionDigest]; !ok {
+		recentResultCachingBuildExecutorLookupsMiss.Inc()
+	} else if be.clock.Now().Sub(result.completedAt) > be.maximumAge {
+		recentResultCachingBuildExecutorLookupsExpired.Inc()
+	} else {
+		be.evictionSet.Touch(actionDigest)
+		be.lock.Unlock()
+		recentResultCachingBuildExecutorLookupsHit.Inc()
+		return proto.Clone(result.response).(*remoteexecution.ExecuteResponse)
+	}
+	be.lock.Unlock()
+
+	response := be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+	if executeResponseIsSuccessful(response) {
+		result := recentResult{
+			response:    proto.Clone(response).(*remoteexecution.ExecuteResponse),
+			completedAt: be.clock.Now(),
+		}
+
+		be.lock.Lock()
+		if _, ok := be.results[actionDigest]; ok {
+			// Replace a response that has expired.
+			be.evictionSet.Touch(actionDigest)
+		} else {
+			for len(be.results) > 0 && len(be.results) >= be.maximumCacheSize {
+				delete(be.results, be.evictionSet.Peek())
+				be.evictionSet.Remove()
+			}
+			be.evictionSet.Insert(actionDigest)
+		}
+		be.results[actionDigest] = result
+		be.lock.Unlock()
+	}
+	return response
+}
@@ -0,0 +1,113 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/eviction"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+)
+
+func TestRecentResultCachingBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	buildExecutor := builder.NewRecentResultCachingBuildExecutor(
+		baseBuildExecutor,
+		clock,
+		time.Minute,
+		1,
+		eviction.NewLRUSet[digest.Digest]())
+
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_SHA256)
+	metadata := make(chan *remoteworker.CurrentState_Executing, 10)
+	request1 := &remoteworker.DesiredState_Executing{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "f4d8f9d5bd1a0d9f5b2e3b3e5b2ae79e7e1a8b6f8c9b3e1ce9f59c8d8c1e4b6a",
+			SizeBytes: 123,
+		},
+		Action: &remoteexecution.Action{},
+	}
+	request2 := &remoteworker.DesiredState_Executing{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "1a4e56b9a8b8f3ce2f9d3f6f0a7f2b1c5e7d9d0b3e4b1a9c8d7e6f5a4b3c2d1e",
+			SizeBytes: 456,
+		},
+		Action: &remoteexecution.Action{},
+	}
+	response1 := &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			StdoutRaw: []byte("Hello"),
+		},
+	}
+	response2 := &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			StdoutRaw: []byte("World"),
+		},
+	}
+
+	t.Run("InitialExecution", func(t *testing.T) {
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request1, gomock.Any()).Return(response1)
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+
+		testutil.RequireEqualProto(t, response1, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request1, metadata))
+	})
+
+	t.Run("CacheHit", func(t *testing.T) {
+		// Executing the same action shortly after should cause
+		// the previous response to be returned.
+		clock.EXPECT().Now().Return(time.Unix(1030, 0))
+
+		testutil.RequireEqualProto(t, response1, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request1, metadata))
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		// Once the response has expired, the action should be
+		// executed once more.
+		clock.EXPECT().Now().Return(time.Unix(1061, 0))
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request1, gomock.Any()).Return(response1)
+		clock.EXPECT().Now().Return(time.Unix(1070, 0))
+
+		testutil.RequireEqualProto(t, response1, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request1, metadata))
+	})
+
+	t.Run("Eviction", func(t *testing.T) {
+		// As the cache has a size of one, executing another
+		// action should cause the previous response to be
+		// evicted.
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request2, gomock.Any()).Return(response2)
+		clock.EXPECT().Now().Return(time.Unix(1080, 0))
+
+		testutil.RequireEqualProto(t, response2, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request2, metadata))
+
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request1, gomock.Any()).Return(response1)
+		clock.EXPECT().Now().Return(time.Unix(1090, 0))
+
+		testutil.RequireEqualProto(t, response1, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request1, metadata))
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		// Responses of failed actions should not be retained.
+		failedRequest := &remoteworker.DesiredState_Executing{
+			ActionDigest: request2.ActionDigest,
+			Action:       &remoteexecution.Action{},
+		}
+		failedResponse := &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{ExitCode: 1},
+		}
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, failedRequest, gomock.Any()).Return(failedResponse).Times(2)
+
+		testutil.RequireEqualProto(t, failedResponse, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, failedRequest, metadata))
+		testutil.RequireEqualProto(t, failedResponse, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, failedRequest, metadata))
+	})
+}
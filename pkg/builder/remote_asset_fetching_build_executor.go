@@ -0,0 +1,284 @@
+package builder
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// RemoteAssetBlobPlatformPropertyName is the name of the
+	// platform property that may be used by actions to request that
+	// a file is fetched through the Remote Asset API, and placed in
+	// the input root.
+	RemoteAssetBlobPlatformPropertyName = "remote-asset-blob"
+	// RemoteAssetDirectoryPlatformPropertyName is the name of the
+	// platform property that may be used by actions to request that
+	// a directory is fetched through the Remote Asset API, and placed
+	// in the input root.
+	RemoteAssetDirectoryPlatformPropertyName = "remote-asset-directory"
+
+	// remoteAssetChecksumQualifierName is the name of the qualifier
+	// that pins the contents of an asset by its checksum.
+	remoteAssetChecksumQualifierName = "checksum.sri"
+)
+
+type remoteAssetFetchingBuildExecutor struct {
+	BuildExecutor
+	contentAddressableStorage blobstore.BlobAccess
+	directoryFetcher          cas.DirectoryFetcher
+	fetchClient               remoteasset.FetchClient
+}
+
+// NewRemoteAssetFetchingBuildExecutor creates a decorator for
+// BuildExecutor that fetches external assets through the Remote Asset
+// API, and places them in the input root of the action prior to
+// execution. This prevents clients from needing to upload large
+// third-party archives to the Content Addressable Storage themselves.
+//
+// Assets are requested by setting the "remote-asset-blob" or
+// "remote-asset-directory" platform properties. These properties may
+// be provided multiple times, and have values of the following form:
+//
+//	<path in input root> <URI> [<qualifier name>=<qualifier value> ...]
+//
+// To ensure actions remain deterministic, assets need to be pinned by
+// providing the "checksum.sri" qualifier. The resulting input root is
+// stored in the Content Addressable Storage, meaning that it is
+// instantiated like any other input root.
+func NewRemoteAssetFetchingBuildExecutor(buildExecutor BuildExecutor, contentAddressableStorage blobstore.BlobAccess, directoryFetcher cas.DirectoryFetcher, fetchClient remoteasset.FetchClient) BuildExecutor {
+	return &remoteAssetFetchingBuildExecutor{
+		BuildExecutor:             buildExecutor,
+		contentAddressableStorage: contentAddressableStorage,
+		directoryFetcher:          directoryFetcher,
+		fetchClient:               fetchClient,
+	}
+}
+
+// remoteAsset contains the parsed value of a "remote-asset-blob" or
+// "remote-asset-directory" platform property.
+type remoteAsset struct {
+	isDirectory bool
+	path        []string
+	uri         string
+	qualifiers  []*remoteasset.Qualifier
+}
+
+func parseRemoteAsset(value string, isDirectory bool) (*remoteAsset, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 2 {
+		return nil, status.Error(codes.InvalidArgument, "Value must contain a path and a URI")
+	}
+
+	path := strings.Split(fields[0], "/")
+	for _, component := range path {
+		if component == "" || component == "." || component == ".." {
+			return nil, status.Errorf(codes.InvalidArgument, "Path %#v is not a normalized relative path", fields[0])
+		}
+	}
+
+	asset := &remoteAsset{
+		isDirectory: isDirectory,
+		path:        path,
+		uri:         fields[1],
+	}
+	hasChecksum := false
+	for _, field := range fields[2:] {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok || name == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "Qualifier %#v is not of the form name=value", field)
+		}
+		if name == remoteAssetChecksumQualifierName {
+			hasChecksum = true
+		}
+		asset.qualifiers = append(asset.qualifiers, &remoteasset.Qualifier{
+			Name:  name,
+			Value: value,
+		})
+	}
+	if !hasChecksum {
+		return nil, status.Errorf(codes.InvalidArgument, "Asset %#v is not pinned using the %#v qualifier", asset.uri, remoteAssetChecksumQualifierName)
+	}
+	return asset, nil
+}
+
+// fetch the asset through the Remote Asset API, returning the digest
+// of the resulting blob or directory.
+func (be *remoteAssetFetchingBuildExecutor) fetch(ctx context.Context, digestFunction digest.Function, asset *remoteAsset) (*remoteexecution.Digest, error) {
+	instanceName := digestFunction.GetInstanceName().String()
+	var fetchStatus error
+	var assetDigest *remoteexecution.Digest
+	if asset.isDirectory {
+		response, err := be.fetchClient.FetchDirectory(ctx, &remoteasset.FetchDirectoryRequest{
+			InstanceName: instanceName,
+			Uris:         []string{asset.uri},
+			Qualifiers:   asset.qualifiers,
+		})
+		if err != nil {
+			return nil, err
+		}
+		fetchStatus = status.ErrorProto(response.Status)
+		assetDigest = response.RootDirectoryDigest
+	} else {
+		response, err := be.fetchClient.FetchBlob(ctx, &remoteasset.FetchBlobRequest{
+			InstanceName: instanceName,
+			Uris:         []string{asset.uri},
+			Qualifiers:   asset.qualifiers,
+		})
+		if err != nil {
+			return nil, err
+		}
+		fetchStatus = status.ErrorProto(response.Status)
+		assetDigest = response.BlobDigest
+	}
+	if fetchStatus != nil {
+		return nil, fetchStatus
+	}
+	if _, err := digestFunction.NewDigestFromProto(assetDigest); err != nil {
+		return nil, util.StatusWrap(err, "Remote Asset server returned an invalid digest")
+	}
+	return assetDigest, nil
+}
+
+// insertAsset places a fetched asset in a directory of the input root,
+// creating any parent directories as needed. A copy of the directory is
+// stored in the Content Addressable Storage, and its digest is
+// returned. If directoryDigest is nil, the directory is created.
+func (be *remoteAssetFetchingBuildExecutor) insertAsset(ctx context.Context, digestFunction digest.Function, directoryDigest *remoteexecution.Digest, asset *remoteAsset, assetDigest *remoteexecution.Digest, depth int) (*remoteexecution.Digest, error) {
+	directory := &remoteexecution.Directory{}
+	if directoryDigest != nil {
+		d, err := digestFunction.NewDigestFromProto(directoryDigest)
+		if err != nil {
+			return nil, err
+		}
+		original, err := be.directoryFetcher.GetDirectory(ctx, d)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Failed to fetch directory %#v", d.String())
+		}
+		directory = proto.Clone(original).(*remoteexecution.Directory)
+	}
+
+	name := asset.path[depth]
+	for _, file := range directory.Files {
+		if file.Name == name {
+			return nil, status.Errorf(codes.InvalidArgument, "Path %#v already exists in the input root", strings.Join(asset.path[:depth+1], "/"))
+		}
+	}
+	for _, symlink := range directory.Symlinks {
+		if symlink.Name == name {
+			return nil, status.Errorf(codes.InvalidArgument, "Path %#v already exists in the input root", strings.Join(asset.path[:depth+1], "/"))
+		}
+	}
+
+	if depth == len(asset.path)-1 {
+		// Place the asset inside this directory.
+		for _, childDirectory := range directory.Directories {
+			if childDirectory.Name == name {
+				return nil, status.Errorf(codes.InvalidArgument, "Path %#v already exists in the input root", strings.Join(asset.path, "/"))
+			}
+		}
+		if asset.isDirectory {
+			directory.Directories = append(directory.Directories, &remoteexecution.DirectoryNode{
+				Name:   name,
+				Digest: assetDigest,
+			})
+		} else {
+			directory.Files = append(directory.Files, &remoteexecution.FileNode{
+				Name:   name,
+				Digest: assetDigest,
+			})
+			sort.Slice(directory.Files, func(i, j int) bool {
+				return directory.Files[i].Name < directory.Files[j].Name
+			})
+		}
+	} else {
+		// Descend into a child directory, creating it if it
+		// doesn't exist.
+		var childDirectory *remoteexecution.DirectoryNode
+		for _, candidate := range directory.Directories {
+			if candidate.Name == name {
+				childDirectory = candidate
+			}
+		}
+		if childDirectory == nil {
+			childDirectory = &remoteexecution.DirectoryNode{Name: name}
+			directory.Directories = append(directory.Directories, childDirectory)
+		}
+		newChildDigest, err := be.insertAsset(ctx, digestFunction, childDirectory.Digest, asset, assetDigest, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		childDirectory.Digest = newChildDigest
+	}
+	sort.Slice(directory.Directories, func(i, j int) bool {
+		return directory.Directories[i].Name < directory.Directories[j].Name
+	})
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(directory)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to marshal directory")
+	}
+	newDirectoryDigest := computeBlobDigest(digestFunction, data)
+	if err := be.contentAddressableStorage.Put(ctx, newDirectoryDigest, buffer.NewValidatedBufferFromByteSlice(data)); err != nil {
+		return nil, util.StatusWrap(err, "Failed to store directory")
+	}
+	return newDirectoryDigest.GetProto(), nil
+}
+
+func (be *remoteAssetFetchingBuildExecutor) Execute(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	var assets []*remoteAsset
+	response := NewDefaultExecuteResponse(request)
+	for _, property := range request.Action.GetPlatform().GetProperties() {
+		var isDirectory bool
+		switch property.Name {
+		case RemoteAssetBlobPlatformPropertyName:
+			isDirectory = false
+		case RemoteAssetDirectoryPlatformPropertyName:
+			isDirectory = true
+		default:
+			continue
+		}
+		asset, err := parseRemoteAsset(property.Value, isDirectory)
+		if err != nil {
+			attachErrorToExecuteResponse(response, util.StatusWrapf(err, "Invalid value for platform property %#v", property.Name))
+			return response
+		}
+		assets = append(assets, asset)
+	}
+	if len(assets) == 0 {
+		return be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+	}
+
+	inputRootDigest := request.Action.InputRootDigest
+	for _, asset := range assets {
+		assetDigest, err := be.fetch(ctx, digestFunction, asset)
+		if err != nil {
+			attachErrorToExecuteResponse(response, util.StatusWrapf(err, "Failed to fetch asset %#v", asset.uri))
+			return response
+		}
+		inputRootDigest, err = be.insertAsset(ctx, digestFunction, inputRootDigest, asset, assetDigest, 0)
+		if err != nil {
+			attachErrorToExecuteResponse(response, util.StatusWrapf(err, "Failed to place asset %#v in the input root", asset.uri))
+			return response
+		}
+	}
+
+	newRequest := proto.Clone(request).(*remoteworker.DesiredState_Executing)
+	newRequest.Action.InputRootDigest = inputRootDigest
+	return be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, newRequest, executionStateUpdates)
+}
@@ -0,0 +1,207 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRemoteAssetFetchingBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	conn := mock.NewMockClientConnInterface(ctrl)
+	buildExecutor := builder.NewRemoteAssetFetchingBuildExecutor(
+		baseBuildExecutor,
+		contentAddressableStorage,
+		directoryFetcher,
+		remoteasset.NewFetchClient(conn))
+
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5)
+	executionStateUpdates := make(chan *remoteworker.CurrentState_Executing, 3)
+	inputRootDigest := &remoteexecution.Digest{
+		Hash:      "095afbd64b6358665546558583c64d38",
+		SizeBytes: 456,
+	}
+
+	t.Run("NoAssets", func(t *testing.T) {
+		request := &remoteworker.DesiredState_Executing{
+			Action: &remoteexecution.Action{
+				InputRootDigest: inputRootDigest,
+			},
+		}
+		response := &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		}
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(response)
+
+		testutil.RequireEqualProto(
+			t,
+			response,
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("NotPinned", func(t *testing.T) {
+		// Assets need to be pinned by checksum, as actions
+		// would otherwise not be deterministic.
+		request := &remoteworker.DesiredState_Executing{
+			Action: &remoteexecution.Action{
+				InputRootDigest: inputRootDigest,
+				Platform: &remoteexecution.Platform{
+					Properties: []*remoteexecution.Platform_Property{
+						{Name: "remote-asset-blob", Value: "foo.tar.gz https://example.com/foo.tar.gz"},
+					},
+				},
+			},
+		}
+
+		response := buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid value for platform property \"remote-asset-blob\": Asset \"https://example.com/foo.tar.gz\" is not pinned using the \"checksum.sri\" qualifier"), status.ErrorProto(response.Status))
+	})
+
+	t.Run("FetchFailure", func(t *testing.T) {
+		request := &remoteworker.DesiredState_Executing{
+			Action: &remoteexecution.Action{
+				InputRootDigest: inputRootDigest,
+				Platform: &remoteexecution.Platform{
+					Properties: []*remoteexecution.Platform_Property{
+						{Name: "remote-asset-directory", Value: "external/foo https://example.com/foo.tar.gz checksum.sri=sha256-Ay0j/Q=="},
+					},
+				},
+			},
+		}
+		conn.EXPECT().Invoke(gomock.Any(), "/build.bazel.remote.asset.v1.Fetch/FetchDirectory", gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+				proto.Merge(reply.(proto.Message), &remoteasset.FetchDirectoryResponse{
+					Status: status.New(codes.NotFound, "Asset not found").Proto(),
+				})
+				return nil
+			})
+
+		response := buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Failed to fetch asset \"https://example.com/foo.tar.gz\": Asset not found"), status.ErrorProto(response.Status))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		request := &remoteworker.DesiredState_Executing{
+			Action: &remoteexecution.Action{
+				InputRootDigest: inputRootDigest,
+				Platform: &remoteexecution.Platform{
+					Properties: []*remoteexecution.Platform_Property{
+						{Name: "remote-asset-blob", Value: "third_party/foo.tar.gz https://example.com/foo.tar.gz checksum.sri=sha256-Ay0j/Q=="},
+					},
+				},
+			},
+		}
+		assetDigest := &remoteexecution.Digest{
+			Hash:      "8b1a9953c4611296a827abf8c47804d7",
+			SizeBytes: 1000000,
+		}
+		conn.EXPECT().Invoke(gomock.Any(), "/build.bazel.remote.asset.v1.Fetch/FetchBlob", gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+				testutil.RequireEqualProto(t, &remoteasset.FetchBlobRequest{
+					InstanceName: "hello",
+					Uris:         []string{"https://example.com/foo.tar.gz"},
+					Qualifiers: []*remoteasset.Qualifier{{
+						Name:  "checksum.sri",
+						Value: "sha256-Ay0j/Q==",
+					}},
+				}, args.(proto.Message))
+				proto.Merge(reply.(proto.Message), &remoteasset.FetchBlobResponse{
+					Uri:        "https://example.com/foo.tar.gz",
+					BlobDigest: assetDigest,
+				})
+				return nil
+			})
+		directoryFetcher.EXPECT().GetDirectory(ctx, digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "095afbd64b6358665546558583c64d38", 456)).
+			Return(&remoteexecution.Directory{
+				Directories: []*remoteexecution.DirectoryNode{{
+					Name: "src",
+					Digest: &remoteexecution.Digest{
+						Hash:      "a0f1fa7bb0d8e2ea0b3c5df9fbc8a1e4",
+						SizeBytes: 100,
+					},
+				}},
+			}, nil)
+
+		// The parent directory of the asset doesn't exist, so
+		// it should be created.
+		var thirdPartyDigest, newInputRootDigest *remoteexecution.Digest
+		contentAddressableStorage.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				directory, err := b.ToProto(&remoteexecution.Directory{}, 10000)
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, &remoteexecution.Directory{
+					Files: []*remoteexecution.FileNode{{
+						Name:   "foo.tar.gz",
+						Digest: assetDigest,
+					}},
+				}, directory)
+				thirdPartyDigest = blobDigest.GetProto()
+				return nil
+			})
+		contentAddressableStorage.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				directory, err := b.ToProto(&remoteexecution.Directory{}, 10000)
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, &remoteexecution.Directory{
+					Directories: []*remoteexecution.DirectoryNode{
+						{
+							Name: "src",
+							Digest: &remoteexecution.Digest{
+								Hash:      "a0f1fa7bb0d8e2ea0b3c5df9fbc8a1e4",
+								SizeBytes: 100,
+							},
+						},
+						{
+							Name:   "third_party",
+							Digest: thirdPartyDigest,
+						},
+					},
+				}, directory)
+				newInputRootDigest = blobDigest.GetProto()
+				return nil
+			})
+		response := &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		}
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, gomock.Any(), executionStateUpdates).DoAndReturn(
+			func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+				testutil.RequireEqualProto(t, newInputRootDigest, request.Action.InputRootDigest)
+				return response
+			})
+
+		testutil.RequireEqualProto(
+			t,
+			response,
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+
+		// The original request should not be modified.
+		testutil.RequireEqualProto(t, inputRootDigest, request.Action.InputRootDigest)
+	})
+}
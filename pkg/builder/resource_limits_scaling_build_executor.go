@@ -0,0 +1,93 @@
+package builder
+
+import (
+	"context"
+	"math"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+// resourceLimitsMultiplierKey is the key under which the resource
+// limits multiplier of the action is stored in the Context that is
+// provided to the underlying BuildExecutor.
+type resourceLimitsMultiplierKey struct{}
+
+// scaleResourceLimit multiplies a resource limit by the multiplier
+// that is attached to a Context by NewResourceLimitsScalingBuildExecutor().
+func scaleResourceLimit(ctx context.Context, limit int64) int64 {
+	multiplier, ok := ctx.Value(resourceLimitsMultiplierKey{}).(float64)
+	if !ok {
+		return limit
+	}
+	scaled := float64(limit) * multiplier
+	if scaled >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(scaled)
+}
+
+type resourceLimitsScalingBuildExecutor struct {
+	BuildExecutor
+}
+
+// NewResourceLimitsScalingBuildExecutor creates a decorator for
+// BuildExecutor that raises the resource limits of actions that the
+// scheduler retries on a larger size class, after they failed on a
+// smaller one. The multiplier is obtained from the SizeClassEscalation
+// message that the scheduler attaches to the auxiliary metadata of the
+// action.
+//
+// The multiplier is attached to the Context, causing it to be applied
+// by NewQuotaEnforcingFilePoolBuildExecutor() and
+// NewQuotaEnforcingBuildDirectoryCreator().
+func NewResourceLimitsScalingBuildExecutor(buildExecutor BuildExecutor) BuildExecutor {
+	return &resourceLimitsScalingBuildExecutor{
+		BuildExecutor: buildExecutor,
+	}
+}
+
+func (be *resourceLimitsScalingBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	for _, auxiliaryMetadata := range request.AuxiliaryMetadata {
+		var escalation resourceusage.SizeClassEscalation
+		if auxiliaryMetadata.UnmarshalTo(&escalation) == nil && escalation.ResourceLimitsMultiplier > 0 {
+			ctx = context.WithValue(ctx, resourceLimitsMultiplierKey{}, escalation.ResourceLimitsMultiplier)
+		}
+	}
+	return be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+}
+
+type quotaEnforcingFilePoolBuildExecutor struct {
+	BuildExecutor
+	maximumFileCount int64
+	maximumSizeBytes int64
+}
+
+// NewQuotaEnforcingFilePoolBuildExecutor creates a decorator for
+// BuildExecutor that limits the number of files and the amount of space
+// that a single action may allocate from the FilePool. The limits are
+// scaled if the action is subject to a resource limits multiplier.
+func NewQuotaEnforcingFilePoolBuildExecutor(buildExecutor BuildExecutor, maximumFileCount, maximumSizeBytes int64) BuildExecutor {
+	return &quotaEnforcingFilePoolBuildExecutor{
+		BuildExecutor:    buildExecutor,
+		maximumFileCount: maximumFileCount,
+		maximumSizeBytes: maximumSizeBytes,
+	}
+}
+
+func (be *quotaEnforcingFilePoolBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	return be.BuildExecutor.Execute(
+		ctx,
+		re_filesystem.NewQuotaEnforcingFilePool(
+			filePool,
+			scaleResourceLimit(ctx, be.maximumFileCount),
+			scaleResourceLimit(ctx, be.maximumSizeBytes)),
+		monitor,
+		digestFunction,
+		request,
+		executionStateUpdates)
+}
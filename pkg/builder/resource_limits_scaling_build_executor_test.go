@@ -0,0 +1,83 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestResourceLimitsScalingBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	buildExecutor := builder.NewResourceLimitsScalingBuildExecutor(
+		builder.NewQuotaEnforcingFilePoolBuildExecutor(baseBuildExecutor, 1, 1000))
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_SHA256)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+
+	// createFiles is invoked by the underlying BuildExecutor to
+	// determine how many files may be allocated from the FilePool.
+	baseFilePool := mock.NewMockFilePool(ctrl)
+	createFiles := func(expectedFileCount int) func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+		return func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+			for i := 0; i < expectedFileCount; i++ {
+				baseFilePool.EXPECT().NewFile().Return(mock.NewMockFileReadWriter(ctrl), nil)
+				_, err := filePool.NewFile()
+				require.NoError(t, err)
+			}
+			_, err := filePool.NewFile()
+			testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "File count quota reached"), err)
+			return &remoteexecution.ExecuteResponse{}
+		}
+	}
+
+	t.Run("NotEscalated", func(t *testing.T) {
+		// Actions that were not retried should be subject to the
+		// limits that are configured.
+		request := &remoteworker.DesiredState_Executing{}
+		baseBuildExecutor.EXPECT().Execute(gomock.Any(), gomock.Any(), monitor, digestFunction, request, gomock.Any()).
+			DoAndReturn(createFiles(1))
+
+		testutil.RequireEqualProto(
+			t,
+			&remoteexecution.ExecuteResponse{},
+			buildExecutor.Execute(ctx, baseFilePool, monitor, digestFunction, request, make(chan *remoteworker.CurrentState_Executing, 10)))
+	})
+
+	t.Run("Escalated", func(t *testing.T) {
+		// Actions that are retried on a larger size class should
+		// be permitted to use more resources.
+		escalation, err := anypb.New(&resourceusage.SizeClassEscalation{
+			FailedAttempts: []*resourceusage.SizeClassEscalation_FailedAttempt{
+				{SizeClass: 1, ExitCode: 137},
+			},
+			ResourceLimitsMultiplier: 3,
+		})
+		require.NoError(t, err)
+		request := &remoteworker.DesiredState_Executing{
+			AuxiliaryMetadata: []*anypb.Any{escalation},
+		}
+		baseBuildExecutor.EXPECT().Execute(gomock.Any(), gomock.Any(), monitor, digestFunction, request, gomock.Any()).
+			DoAndReturn(createFiles(3))
+
+		testutil.RequireEqualProto(
+			t,
+			&remoteexecution.ExecuteResponse{},
+			buildExecutor.Execute(ctx, baseFilePool, monitor, digestFunction, request, make(chan *remoteworker.CurrentState_Executing, 10)))
+	})
+}
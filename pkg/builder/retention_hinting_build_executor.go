@@ -0,0 +1,76 @@
+package builder
+
+import (
+	"context"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RetentionHintPolicy describes how actions may request that their
+// outputs are retained by storage for a longer or shorter amount of
+// time.
+type RetentionHintPolicy struct {
+	// Name of the platform property through which actions specify
+	// a retention hint (e.g., "retention").
+	PlatformPropertyName string
+	// Name of the gRPC metadata header through which the hint is
+	// provided to storage (e.g., "x-buildbarn-retention").
+	MetadataHeaderName string
+	// Values of the platform property that are accepted, mapped to
+	// the value of the gRPC metadata header.
+	Hints map[string]string
+	// Value of the gRPC metadata header for actions that don't set
+	// the platform property. No header is attached if empty.
+	DefaultHint string
+}
+
+type retentionHintingBuildExecutor struct {
+	BuildExecutor
+	policy *RetentionHintPolicy
+}
+
+// NewRetentionHintingBuildExecutor creates a decorator for
+// BuildExecutor that attaches a retention hint to all requests that
+// are sent to storage while executing an action, including uploads of
+// its outputs and the resulting Action Cache entry. This permits
+// operators to let storage distinguish final build outputs (e.g.,
+// release artifacts) from intermediate objects, so that the former
+// can be retained longer.
+//
+// The hint is provided in the form of gRPC metadata, meaning that it
+// only applies to storage that is accessed over gRPC. The hint is
+// driven by a platform property of the action. As platform properties
+// are used to route actions to workers, workers need to announce the
+// platform property for every hint they accept.
+func NewRetentionHintingBuildExecutor(buildExecutor BuildExecutor, policy *RetentionHintPolicy) BuildExecutor {
+	return &retentionHintingBuildExecutor{
+		BuildExecutor: buildExecutor,
+		policy:        policy,
+	}
+}
+
+func (be *retentionHintingBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	hint := be.policy.DefaultHint
+	for _, property := range request.Action.GetPlatform().GetProperties() {
+		if property.Name == be.policy.PlatformPropertyName {
+			var ok bool
+			if hint, ok = be.policy.Hints[property.Value]; !ok {
+				response := NewDefaultExecuteResponse(request)
+				attachErrorToExecuteResponse(response, status.Errorf(codes.InvalidArgument, "Unsupported retention hint %#v", property.Value))
+				return response
+			}
+		}
+	}
+	if hint != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, be.policy.MetadataHeaderName, hint)
+	}
+	return be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+}
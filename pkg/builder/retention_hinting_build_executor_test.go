@@ -0,0 +1,92 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestRetentionHintingBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	buildExecutor := builder.NewRetentionHintingBuildExecutor(
+		baseBuildExecutor,
+		&builder.RetentionHintPolicy{
+			PlatformPropertyName: "retention",
+			MetadataHeaderName:   "x-buildbarn-retention",
+			Hints: map[string]string{
+				"intermediate": "short",
+				"release":      "long",
+			},
+			DefaultHint: "short",
+		})
+
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_SHA256)
+	newRequest := func(properties ...*remoteexecution.Platform_Property) *remoteworker.DesiredState_Executing {
+		return &remoteworker.DesiredState_Executing{
+			Action: &remoteexecution.Action{
+				Platform: &remoteexecution.Platform{
+					Properties: properties,
+				},
+				Timeout: &durationpb.Duration{Seconds: 60},
+			},
+		}
+	}
+	expectHint := func(request *remoteworker.DesiredState_Executing, expectedHint string) {
+		baseBuildExecutor.EXPECT().Execute(gomock.Any(), filePool, monitor, digestFunction, request, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				require.Equal(t, []string{expectedHint}, md.Get("x-buildbarn-retention"))
+				return &remoteexecution.ExecuteResponse{}
+			})
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		// Actions not setting the platform property should use
+		// the default hint.
+		request := newRequest()
+		expectHint(request, "short")
+
+		testutil.RequireEqualProto(
+			t,
+			&remoteexecution.ExecuteResponse{},
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, make(chan *remoteworker.CurrentState_Executing, 10)))
+	})
+
+	t.Run("Release", func(t *testing.T) {
+		request := newRequest(&remoteexecution.Platform_Property{Name: "retention", Value: "release"})
+		expectHint(request, "long")
+
+		testutil.RequireEqualProto(
+			t,
+			&remoteexecution.ExecuteResponse{},
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, make(chan *remoteworker.CurrentState_Executing, 10)))
+	})
+
+	t.Run("UnsupportedHint", func(t *testing.T) {
+		// Actions should not be executed if they request a hint
+		// that is not supported.
+		request := newRequest(&remoteexecution.Platform_Property{Name: "retention", Value: "forever"})
+
+		response := buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, make(chan *remoteworker.CurrentState_Executing, 10))
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Unsupported retention hint \"forever\""), status.ErrorProto(response.Status))
+	})
+}
@@ -2,20 +2,24 @@ package builder
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"strconv"
 	"sync/atomic"
 
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/random"
 	"github.com/buildbarn/bb-storage/pkg/util"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 type sharedBuildDirectoryCreator struct {
 	base                 BuildDirectoryCreator
 	nextParallelActionID *atomic.Uint64
+	randomNameGenerator  random.ThreadSafeGenerator
 }
 
 // NewSharedBuildDirectoryCreator is an adapter for
@@ -28,10 +32,17 @@ type sharedBuildDirectoryCreator struct {
 // This adapter can be used to add concurrency to a single worker. When
 // executing build actions in parallel, every build action needs its own
 // build directory.
-func NewSharedBuildDirectoryCreator(base BuildDirectoryCreator, nextParallelActionID *atomic.Uint64) BuildDirectoryCreator {
+//
+// If a random number generator is provided, subdirectories are given
+// random names instead. This prevents actions from guessing the names
+// of each other's build directories, which is needed to isolate
+// actions that share a mount created using
+// virtual.NewActionIsolatingDirectory().
+func NewSharedBuildDirectoryCreator(base BuildDirectoryCreator, nextParallelActionID *atomic.Uint64, randomNameGenerator random.ThreadSafeGenerator) BuildDirectoryCreator {
 	return &sharedBuildDirectoryCreator{
 		base:                 base,
 		nextParallelActionID: nextParallelActionID,
+		randomNameGenerator:  randomNameGenerator,
 	}
 }
 
@@ -43,7 +54,9 @@ func (dc *sharedBuildDirectoryCreator) GetBuildDirectory(ctx context.Context, ac
 
 	// Determine the name of the subdirectory.
 	var name string
-	if actionDigestIfNotRunInParallel == nil {
+	if dc.randomNameGenerator != nil {
+		name = fmt.Sprintf("%016x", dc.randomNameGenerator.Uint64())
+	} else if actionDigestIfNotRunInParallel == nil {
 		// Multiple instances of this action may run in
 		// parallel, as the scheduler is not permitted to
 		// deduplicate them. This is likely caused by the
@@ -101,6 +114,16 @@ type sharedBuildDirectory struct {
 	childDirectoryPath string
 }
 
+// GetResourceUsage forwards calls to the underlying build directory.
+// This is needed, as embedding BuildDirectory does not cause optional
+// interfaces implemented by it to be exposed.
+func (d *sharedBuildDirectory) GetResourceUsage() (*anypb.Any, error) {
+	if resourceUsageReporter, ok := d.BuildDirectory.(ResourceUsageReportingBuildDirectory); ok {
+		return resourceUsageReporter.GetResourceUsage()
+	}
+	return nil, nil
+}
+
 func (d *sharedBuildDirectory) Close() error {
 	err1 := d.BuildDirectory.Close()
 	err2 := d.parentDirectory.RemoveAll(d.childDirectoryName)
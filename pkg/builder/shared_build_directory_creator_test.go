@@ -29,7 +29,7 @@ func TestSharedBuildDirectoryCreatorGetBuildDirectoryFailure(t *testing.T) {
 		Return(nil, nil, status.Error(codes.Internal, "No space left on device"))
 
 	var nextParallelActionID atomic.Uint64
-	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID)
+	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID, nil)
 	_, _, err := buildDirectoryCreator.GetBuildDirectory(ctx, &actionDigest)
 	testutil.RequireEqualStatus(t, status.Error(codes.Internal, "No space left on device"), err)
 }
@@ -48,7 +48,7 @@ func TestSharedBuildDirectoryCreatorMkdirFailure(t *testing.T) {
 	baseBuildDirectory.EXPECT().Close()
 
 	var nextParallelActionID atomic.Uint64
-	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID)
+	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID, nil)
 	_, _, err := buildDirectoryCreator.GetBuildDirectory(ctx, &actionDigest)
 	testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to create build directory \"base-directory/e3b0c44298fc1c14\": Directory already exists"), err)
 }
@@ -68,7 +68,7 @@ func TestSharedBuildDirectoryCreatorEnterBuildDirectoryFailure(t *testing.T) {
 	baseBuildDirectory.EXPECT().Close()
 
 	var nextParallelActionID atomic.Uint64
-	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID)
+	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID, nil)
 	_, _, err := buildDirectoryCreator.GetBuildDirectory(ctx, &actionDigest)
 	testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to enter build directory \"base-directory/e3b0c44298fc1c14\": Out of file descriptors"), err)
 }
@@ -91,7 +91,7 @@ func TestSharedBuildDirectoryCreatorCloseChildFailure(t *testing.T) {
 	baseBuildDirectory.EXPECT().Close()
 
 	var nextParallelActionID atomic.Uint64
-	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID)
+	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID, nil)
 	buildDirectory, buildDirectoryPath, err := buildDirectoryCreator.GetBuildDirectory(ctx, &actionDigest)
 	require.NoError(t, err)
 	require.Equal(t, baseBuildDirectoryPath.Append(path.MustNewComponent("e3b0c44298fc1c14")), buildDirectoryPath)
@@ -118,7 +118,7 @@ func TestSharedBuildDirectoryCreatorRemoveAllFailure(t *testing.T) {
 	baseBuildDirectory.EXPECT().Close()
 
 	var nextParallelActionID atomic.Uint64
-	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID)
+	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID, nil)
 	buildDirectory, buildDirectoryPath, err := buildDirectoryCreator.GetBuildDirectory(ctx, &actionDigest)
 	require.NoError(t, err)
 	require.Equal(t, baseBuildDirectoryPath.Append(path.MustNewComponent("e3b0c44298fc1c14")), buildDirectoryPath)
@@ -146,7 +146,7 @@ func TestSharedBuildDirectoryCreatorCloseParentFailure(t *testing.T) {
 	baseBuildDirectory.EXPECT().Close().Return(status.Error(codes.Internal, "Bad file descriptor"))
 
 	var nextParallelActionID atomic.Uint64
-	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID)
+	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID, nil)
 	buildDirectory, buildDirectoryPath, err := buildDirectoryCreator.GetBuildDirectory(ctx, &actionDigest)
 	require.NoError(t, err)
 	require.Equal(t, baseBuildDirectoryPath.Append(path.MustNewComponent("e3b0c44298fc1c14")), buildDirectoryPath)
@@ -173,7 +173,7 @@ func TestSharedBuildDirectoryCreatorSuccessNotParallel(t *testing.T) {
 	baseBuildDirectory.EXPECT().Close()
 
 	var nextParallelActionID atomic.Uint64
-	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID)
+	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID, nil)
 	buildDirectory, buildDirectoryPath, err := buildDirectoryCreator.GetBuildDirectory(ctx, &actionDigest)
 	require.NoError(t, err)
 	require.Equal(t, baseBuildDirectoryPath.Append(path.MustNewComponent("e3b0c44298fc1c14")), buildDirectoryPath)
@@ -187,7 +187,7 @@ func TestSharedBuildDirectoryCreatorMkdirSuccessParallel(t *testing.T) {
 	baseBuildDirectory := mock.NewMockBuildDirectory(ctrl)
 	baseBuildDirectoryPath := ((*path.Trace)(nil)).Append(path.MustNewComponent("base-directory"))
 	var nextParallelActionID atomic.Uint64
-	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID)
+	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID, nil)
 
 	// Build directories for actions that run in parallel are simply
 	// named incrementally to prevent collisions.
@@ -207,3 +207,33 @@ func TestSharedBuildDirectoryCreatorMkdirSuccessParallel(t *testing.T) {
 	_, _, err = buildDirectoryCreator.GetBuildDirectory(ctx, nil)
 	testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to create build directory \"base-directory/2\": Foo"), err)
 }
+
+func TestSharedBuildDirectoryCreatorRandomName(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	// When a random number generator is provided, build
+	// directories should be given random names, even if the
+	// action is not run in parallel.
+	baseBuildDirectoryCreator := mock.NewMockBuildDirectoryCreator(ctrl)
+	baseBuildDirectory := mock.NewMockBuildDirectory(ctrl)
+	baseBuildDirectoryPath := ((*path.Trace)(nil)).Append(path.MustNewComponent("base-directory"))
+	actionDigest := digest.MustNewDigest("debian8", remoteexecution.DigestFunction_SHA256, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", 0)
+	baseBuildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest).
+		Return(baseBuildDirectory, baseBuildDirectoryPath, nil)
+	randomNameGenerator := mock.NewMockThreadSafeGenerator(ctrl)
+	randomNameGenerator.EXPECT().Uint64().Return(uint64(0x3f9a6c21d8e4b507))
+	baseBuildDirectory.EXPECT().Mkdir(path.MustNewComponent("3f9a6c21d8e4b507"), os.FileMode(0o777))
+	subDirectory := mock.NewMockBuildDirectory(ctrl)
+	baseBuildDirectory.EXPECT().EnterBuildDirectory(path.MustNewComponent("3f9a6c21d8e4b507")).Return(subDirectory, nil)
+
+	var nextParallelActionID atomic.Uint64
+	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(baseBuildDirectoryCreator, &nextParallelActionID, randomNameGenerator)
+	buildDirectory, buildDirectoryPath, err := buildDirectoryCreator.GetBuildDirectory(ctx, &actionDigest)
+	require.NoError(t, err)
+	require.Equal(t, baseBuildDirectoryPath.Append(path.MustNewComponent("3f9a6c21d8e4b507")), buildDirectoryPath)
+
+	subDirectory.EXPECT().Close()
+	baseBuildDirectory.EXPECT().RemoveAll(path.MustNewComponent("3f9a6c21d8e4b507"))
+	baseBuildDirectory.EXPECT().Close()
+	require.NoError(t, buildDirectory.Close())
+}
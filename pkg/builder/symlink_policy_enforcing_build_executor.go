@@ -0,0 +1,238 @@
+package builder
+
+import (
+	"context"
+	"strings"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// SymlinkPolicyAction describes how symbolic links having an absolute
+// target are handled by SymlinkPolicyEnforcingBuildExecutor.
+type SymlinkPolicyAction int
+
+const (
+	// SymlinkPolicyActionAllow leaves symbolic links having an
+	// absolute target untouched.
+	SymlinkPolicyActionAllow SymlinkPolicyAction = iota
+	// SymlinkPolicyActionReject causes the action to fail.
+	SymlinkPolicyActionReject
+	// SymlinkPolicyActionRewriteToRelative rewrites the target of
+	// symbolic links in the input root, so that they are relative
+	// to the input root, as opposed to the root of the file system.
+	SymlinkPolicyActionRewriteToRelative
+)
+
+// SymlinkPolicy describes how symbolic links having an absolute target
+// are handled, both in the input root of an action and in its outputs.
+type SymlinkPolicy struct {
+	// The action to take for symbolic links in the input root.
+	InputRootAction SymlinkPolicyAction
+	// The action to take for symbolic links in the outputs. As
+	// outputs are only inspected after execution, they cannot be
+	// rewritten.
+	OutputAction SymlinkPolicyAction
+	// Absolute paths that symbolic links may always point to,
+	// including any paths underneath them (e.g., "/usr/lib").
+	AllowedPrefixes []string
+}
+
+type symlinkPolicyEnforcingBuildExecutor struct {
+	BuildExecutor
+	contentAddressableStorage blobstore.BlobAccess
+	directoryFetcher          cas.DirectoryFetcher
+	maximumMessageSizeBytes   int
+	policy                    SymlinkPolicy
+}
+
+// NewSymlinkPolicyEnforcingBuildExecutor creates a decorator for
+// BuildExecutor that applies a SymlinkPolicy to symbolic links having
+// an absolute target. Such symbolic links make the results of an
+// action depend on the contents of the worker's file system, meaning
+// they are non-hermetic.
+//
+// Symbolic links in the input root are checked prior to execution,
+// which requires loading all directories of the input root. Rewriting
+// them to be relative causes a new input root to be stored in the
+// Content Addressable Storage. Symbolic links in outputs are checked
+// after execution. Violations are reported through the status of the
+// ExecuteResponse, stating the path of the offending symbolic link.
+func NewSymlinkPolicyEnforcingBuildExecutor(buildExecutor BuildExecutor, contentAddressableStorage blobstore.BlobAccess, directoryFetcher cas.DirectoryFetcher, maximumMessageSizeBytes int, policy *SymlinkPolicy) BuildExecutor {
+	return &symlinkPolicyEnforcingBuildExecutor{
+		BuildExecutor:             buildExecutor,
+		contentAddressableStorage: contentAddressableStorage,
+		directoryFetcher:          directoryFetcher,
+		maximumMessageSizeBytes:   maximumMessageSizeBytes,
+		policy:                    *policy,
+	}
+}
+
+// isPermittedTarget returns whether the target of a symbolic link is
+// permitted without applying the policy's action, either because it is
+// relative or because it is covered by one of the allowed prefixes.
+func (be *symlinkPolicyEnforcingBuildExecutor) isPermittedTarget(target string) bool {
+	if !strings.HasPrefix(target, "/") {
+		return true
+	}
+	for _, prefix := range be.policy.AllowedPrefixes {
+		trimmedPrefix := strings.TrimSuffix(prefix, "/")
+		if target == trimmedPrefix || strings.HasPrefix(target, trimmedPrefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteTargetToRelative converts an absolute symbolic link target
+// to one that is relative to the input root, given the number of
+// directories between the input root and the symbolic link.
+func rewriteTargetToRelative(target string, depth int) string {
+	relativeTarget := strings.Repeat("../", depth) + strings.TrimLeft(target, "/")
+	if relativeTarget == "" {
+		return "."
+	}
+	return strings.TrimSuffix(relativeTarget, "/")
+}
+
+// applyToInputRootDirectory applies the policy to all symbolic links
+// contained in a directory of the input root and its children. If any
+// of the symbolic links are rewritten, a new copy of the directory is
+// stored in the Content Addressable Storage, and its digest is
+// returned.
+func (be *symlinkPolicyEnforcingBuildExecutor) applyToInputRootDirectory(ctx context.Context, directoryDigest digest.Digest, directoryPath string, depth int) (*remoteexecution.Digest, error) {
+	original, err := be.directoryFetcher.GetDirectory(ctx, directoryDigest)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to fetch directory %#v", directoryPath)
+	}
+	var rewritten *remoteexecution.Directory
+	getRewritten := func() *remoteexecution.Directory {
+		if rewritten == nil {
+			rewritten = proto.Clone(original).(*remoteexecution.Directory)
+		}
+		return rewritten
+	}
+
+	for i, childDirectory := range original.Directories {
+		childPath := joinInputRootPath(directoryPath, childDirectory.Name)
+		childDigest, err := directoryDigest.GetDigestFunction().NewDigestFromProto(childDirectory.Digest)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Failed to extract digest for directory %#v", childPath)
+		}
+		newChildDigest, err := be.applyToInputRootDirectory(ctx, childDigest, childPath, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if !proto.Equal(newChildDigest, childDirectory.Digest) {
+			getRewritten().Directories[i].Digest = newChildDigest
+		}
+	}
+	for i, symlink := range original.Symlinks {
+		if be.isPermittedTarget(symlink.Target) {
+			continue
+		}
+		symlinkPath := joinInputRootPath(directoryPath, symlink.Name)
+		switch be.policy.InputRootAction {
+		case SymlinkPolicyActionReject:
+			return nil, status.Errorf(codes.FailedPrecondition, "Symbolic link %#v in the input root has absolute target %#v, which is not permitted by the symlink policy of this worker", symlinkPath, symlink.Target)
+		case SymlinkPolicyActionRewriteToRelative:
+			getRewritten().Symlinks[i].Target = rewriteTargetToRelative(symlink.Target, depth)
+		}
+	}
+	if rewritten == nil {
+		return directoryDigest.GetProto(), nil
+	}
+
+	data, err := proto.Marshal(rewritten)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to marshal directory %#v", directoryPath)
+	}
+	rewrittenDigest := computeBlobDigest(directoryDigest.GetDigestFunction(), data)
+	if err := be.contentAddressableStorage.Put(ctx, rewrittenDigest, buffer.NewValidatedBufferFromByteSlice(data)); err != nil {
+		return nil, util.StatusWrapf(err, "Failed to store directory %#v", directoryPath)
+	}
+	return rewrittenDigest.GetProto(), nil
+}
+
+// checkOutputs returns an error if any of the symbolic links contained
+// in the outputs of an action are not permitted.
+func (be *symlinkPolicyEnforcingBuildExecutor) checkOutputs(ctx context.Context, digestFunction digest.Function, actionResult *remoteexecution.ActionResult) error {
+	for _, outputSymlinks := range [][]*remoteexecution.OutputSymlink{
+		actionResult.OutputSymlinks,
+		actionResult.OutputFileSymlinks,
+		actionResult.OutputDirectorySymlinks,
+	} {
+		for _, outputSymlink := range outputSymlinks {
+			if !be.isPermittedTarget(outputSymlink.Target) {
+				return status.Errorf(codes.FailedPrecondition, "Output symbolic link %#v has absolute target %#v, which is not permitted by the symlink policy of this worker", outputSymlink.Path, outputSymlink.Target)
+			}
+		}
+	}
+
+	for _, outputDirectory := range actionResult.OutputDirectories {
+		treeDigest, err := digestFunction.NewDigestFromProto(outputDirectory.TreeDigest)
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid tree digest for output directory %#v", outputDirectory.Path)
+		}
+		treeMessage, err := be.contentAddressableStorage.Get(ctx, treeDigest).ToProto(&remoteexecution.Tree{}, be.maximumMessageSizeBytes)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to obtain tree for output directory %#v", outputDirectory.Path)
+		}
+		tree := treeMessage.(*remoteexecution.Tree)
+		for _, directory := range append([]*remoteexecution.Directory{tree.Root}, tree.Children...) {
+			for _, symlink := range directory.GetSymlinks() {
+				if !be.isPermittedTarget(symlink.Target) {
+					return status.Errorf(codes.FailedPrecondition, "Output directory %#v contains symbolic link %#v with absolute target %#v, which is not permitted by the symlink policy of this worker", outputDirectory.Path, symlink.Name, symlink.Target)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (be *symlinkPolicyEnforcingBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	if be.policy.InputRootAction != SymlinkPolicyActionAllow {
+		if request.Action == nil {
+			response := NewDefaultExecuteResponse(request)
+			attachErrorToExecuteResponse(response, status.Error(codes.InvalidArgument, "Request does not contain an action"))
+			return response
+		}
+		inputRootDigest, err := digestFunction.NewDigestFromProto(request.Action.InputRootDigest)
+		if err != nil {
+			response := NewDefaultExecuteResponse(request)
+			attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to extract digest for input root"))
+			return response
+		}
+		newInputRootDigest, err := be.applyToInputRootDirectory(ctx, inputRootDigest, "", 0)
+		if err != nil {
+			response := NewDefaultExecuteResponse(request)
+			attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to apply symlink policy to input root"))
+			return response
+		}
+		if !proto.Equal(newInputRootDigest, request.Action.InputRootDigest) {
+			action := proto.Clone(request.Action).(*remoteexecution.Action)
+			action.InputRootDigest = newInputRootDigest
+			request = proto.Clone(request).(*remoteworker.DesiredState_Executing)
+			request.Action = action
+		}
+	}
+
+	response := be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+	if be.policy.OutputAction == SymlinkPolicyActionReject && response.Result != nil {
+		if err := be.checkOutputs(ctx, digestFunction, response.Result); err != nil {
+			attachErrorToExecuteResponse(response, util.StatusWrap(err, "Failed to apply symlink policy to outputs"))
+		}
+	}
+	return response
+}
@@ -0,0 +1,248 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSymlinkPolicyEnforcingBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5)
+	request := &remoteworker.DesiredState_Executing{
+		Action: &remoteexecution.Action{
+			InputRootDigest: &remoteexecution.Digest{
+				Hash:      "095afbd64b6358665546558583c64d38",
+				SizeBytes: 456,
+			},
+		},
+	}
+	inputRootDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "095afbd64b6358665546558583c64d38", 456)
+	sysrootDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "a0f1fa7bb0d8e2ea0b3c5df9fbc8a1e4", 100)
+	executionStateUpdates := make(chan *remoteworker.CurrentState_Executing, 3)
+
+	// Input root containing a symbolic link with an absolute target
+	// that is covered by the allowed prefixes, and one that isn't.
+	expectInputRoot := func() {
+		directoryFetcher.EXPECT().GetDirectory(ctx, inputRootDigest).Return(&remoteexecution.Directory{
+			Directories: []*remoteexecution.DirectoryNode{{
+				Name: "sysroot",
+				Digest: &remoteexecution.Digest{
+					Hash:      "a0f1fa7bb0d8e2ea0b3c5df9fbc8a1e4",
+					SizeBytes: 100,
+				},
+			}},
+			Symlinks: []*remoteexecution.SymlinkNode{{
+				Name:   "python",
+				Target: "/usr/bin/python3",
+			}},
+		}, nil)
+		directoryFetcher.EXPECT().GetDirectory(ctx, sysrootDigest).Return(&remoteexecution.Directory{
+			Symlinks: []*remoteexecution.SymlinkNode{{
+				Name:   "lib",
+				Target: "/usr/lib",
+			}},
+		}, nil)
+	}
+	successfulResponse := &remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+		},
+	}
+
+	t.Run("InputRootRejected", func(t *testing.T) {
+		buildExecutor := builder.NewSymlinkPolicyEnforcingBuildExecutor(
+			baseBuildExecutor,
+			contentAddressableStorage,
+			directoryFetcher,
+			/* maximumMessageSizeBytes = */ 10000,
+			&builder.SymlinkPolicy{
+				InputRootAction: builder.SymlinkPolicyActionReject,
+				AllowedPrefixes: []string{"/usr/bin/"},
+			})
+		expectInputRoot()
+
+		testutil.RequireEqualProto(
+			t,
+			&remoteexecution.ExecuteResponse{
+				Result: &remoteexecution.ActionResult{
+					ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+				},
+				Status: status.New(codes.FailedPrecondition, "Failed to apply symlink policy to input root: Symbolic link \"sysroot/lib\" in the input root has absolute target \"/usr/lib\", which is not permitted by the symlink policy of this worker").Proto(),
+			},
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("InputRootRewritten", func(t *testing.T) {
+		buildExecutor := builder.NewSymlinkPolicyEnforcingBuildExecutor(
+			baseBuildExecutor,
+			contentAddressableStorage,
+			directoryFetcher,
+			/* maximumMessageSizeBytes = */ 10000,
+			&builder.SymlinkPolicy{
+				InputRootAction: builder.SymlinkPolicyActionRewriteToRelative,
+				AllowedPrefixes: []string{"/usr/bin"},
+			})
+		expectInputRoot()
+
+		// Both the directory containing the symbolic link and
+		// its parent need to be stored once more.
+		expectPut := func(expectedDirectory *remoteexecution.Directory) *remoteexecution.Digest {
+			var uploadedDigest remoteexecution.Digest
+			contentAddressableStorage.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+					directory, err := b.ToProto(&remoteexecution.Directory{}, 10000)
+					require.NoError(t, err)
+					testutil.RequireEqualProto(t, expectedDirectory, directory)
+					uploadedDigest.Hash = blobDigest.GetHashString()
+					uploadedDigest.SizeBytes = blobDigest.GetSizeBytes()
+					return nil
+				})
+			return &uploadedDigest
+		}
+		rewrittenSysrootDigest := expectPut(&remoteexecution.Directory{
+			Symlinks: []*remoteexecution.SymlinkNode{{
+				Name:   "lib",
+				Target: "../usr/lib",
+			}},
+		})
+		var rewrittenInputRootDigest *remoteexecution.Digest
+		contentAddressableStorage.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				directory, err := b.ToProto(&remoteexecution.Directory{}, 10000)
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, &remoteexecution.Directory{
+					Directories: []*remoteexecution.DirectoryNode{{
+						Name:   "sysroot",
+						Digest: rewrittenSysrootDigest,
+					}},
+					Symlinks: []*remoteexecution.SymlinkNode{{
+						Name:   "python",
+						Target: "/usr/bin/python3",
+					}},
+				}, directory)
+				rewrittenInputRootDigest = blobDigest.GetProto()
+				return nil
+			})
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, gomock.Any(), executionStateUpdates).DoAndReturn(
+			func(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+				testutil.RequireEqualProto(t, rewrittenInputRootDigest, request.Action.InputRootDigest)
+				return successfulResponse
+			})
+
+		testutil.RequireEqualProto(
+			t,
+			successfulResponse,
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+
+		// The original request should not be modified.
+		require.Equal(t, "095afbd64b6358665546558583c64d38", request.Action.InputRootDigest.Hash)
+	})
+
+	t.Run("OutputSymlinkRejected", func(t *testing.T) {
+		buildExecutor := builder.NewSymlinkPolicyEnforcingBuildExecutor(
+			baseBuildExecutor,
+			contentAddressableStorage,
+			directoryFetcher,
+			/* maximumMessageSizeBytes = */ 10000,
+			&builder.SymlinkPolicy{
+				OutputAction: builder.SymlinkPolicyActionReject,
+			})
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(&remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				OutputSymlinks: []*remoteexecution.OutputSymlink{
+					{Path: "relative", Target: "../foo"},
+					{Path: "absolute", Target: "/home/user/foo"},
+				},
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		})
+
+		testutil.RequireEqualProto(
+			t,
+			&remoteexecution.ExecuteResponse{
+				Result: &remoteexecution.ActionResult{
+					OutputSymlinks: []*remoteexecution.OutputSymlink{
+						{Path: "relative", Target: "../foo"},
+						{Path: "absolute", Target: "/home/user/foo"},
+					},
+					ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+				},
+				Status: status.New(codes.FailedPrecondition, "Failed to apply symlink policy to outputs: Output symbolic link \"absolute\" has absolute target \"/home/user/foo\", which is not permitted by the symlink policy of this worker").Proto(),
+			},
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+
+	t.Run("OutputDirectoryRejected", func(t *testing.T) {
+		buildExecutor := builder.NewSymlinkPolicyEnforcingBuildExecutor(
+			baseBuildExecutor,
+			contentAddressableStorage,
+			directoryFetcher,
+			/* maximumMessageSizeBytes = */ 10000,
+			&builder.SymlinkPolicy{
+				OutputAction: builder.SymlinkPolicyActionReject,
+			})
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(&remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				OutputDirectories: []*remoteexecution.OutputDirectory{{
+					Path: "out",
+					TreeDigest: &remoteexecution.Digest{
+						Hash:      "4ec4b5a7a9d6d5ac2b6d5d8d6ec8e9d4",
+						SizeBytes: 200,
+					},
+				}},
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+		})
+		contentAddressableStorage.EXPECT().Get(ctx, digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "4ec4b5a7a9d6d5ac2b6d5d8d6ec8e9d4", 200)).
+			Return(buffer.NewProtoBufferFromProto(&remoteexecution.Tree{
+				Root: &remoteexecution.Directory{},
+				Children: []*remoteexecution.Directory{{
+					Symlinks: []*remoteexecution.SymlinkNode{{
+						Name:   "config",
+						Target: "/etc/config",
+					}},
+				}},
+			}, buffer.UserProvided))
+
+		response := buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Failed to apply symlink policy to outputs: Output directory \"out\" contains symbolic link \"config\" with absolute target \"/etc/config\", which is not permitted by the symlink policy of this worker"), status.ErrorProto(response.Status))
+	})
+
+	t.Run("Allowed", func(t *testing.T) {
+		// Without any restrictions, the input root and outputs
+		// should not be inspected.
+		buildExecutor := builder.NewSymlinkPolicyEnforcingBuildExecutor(
+			baseBuildExecutor,
+			contentAddressableStorage,
+			directoryFetcher,
+			/* maximumMessageSizeBytes = */ 10000,
+			&builder.SymlinkPolicy{})
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates).Return(successfulResponse)
+
+		testutil.RequireEqualProto(
+			t,
+			successfulResponse,
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates))
+	})
+}
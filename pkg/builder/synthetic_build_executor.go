@@ -0,0 +1,117 @@
+package builder
+
+import (
+	"context"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SyntheticExecutionDurationEnvironmentVariableName is the name of
+// the environment variable that actions may set to override the
+// amount of time it takes for SyntheticBuildExecutor to execute them.
+const SyntheticExecutionDurationEnvironmentVariableName = "SYNTHETIC_WORKER_EXECUTION_DURATION"
+
+type syntheticBuildExecutor struct {
+	clock                     clock.Clock
+	durationSampler           func() time.Duration
+	actionResult              *remoteexecution.ActionResult
+	contentAddressableStorage blobstore.BlobAccess
+	maximumMessageSizeBytes   int
+}
+
+// NewSyntheticBuildExecutor creates a BuildExecutor that does not
+// execute actions. Instead, it waits for an amount of time that is
+// obtained from a sampler, and returns a canned ActionResult. This
+// permits load testing the scheduler with large numbers of workers,
+// without requiring any storage infrastructure.
+//
+// If a Content Addressable Storage is provided, the Command message of
+// each action is loaded, so that the action may override the amount of
+// time it takes to execute it by setting an environment variable.
+func NewSyntheticBuildExecutor(clock clock.Clock, durationSampler func() time.Duration, actionResult *remoteexecution.ActionResult, contentAddressableStorage blobstore.BlobAccess, maximumMessageSizeBytes int) BuildExecutor {
+	return &syntheticBuildExecutor{
+		clock:                     clock,
+		durationSampler:           durationSampler,
+		actionResult:              actionResult,
+		contentAddressableStorage: contentAddressableStorage,
+		maximumMessageSizeBytes:   maximumMessageSizeBytes,
+	}
+}
+
+func (be *syntheticBuildExecutor) CheckReadiness(ctx context.Context) error {
+	return nil
+}
+
+func (be *syntheticBuildExecutor) getExecutionDuration(ctx context.Context, digestFunction digest.Function, action *remoteexecution.Action) (time.Duration, error) {
+	if be.contentAddressableStorage != nil {
+		commandDigest, err := digestFunction.NewDigestFromProto(action.CommandDigest)
+		if err != nil {
+			return 0, util.StatusWrap(err, "Failed to extract digest for command")
+		}
+		commandMessage, err := be.contentAddressableStorage.Get(ctx, commandDigest).ToProto(&remoteexecution.Command{}, be.maximumMessageSizeBytes)
+		if err != nil {
+			return 0, util.StatusWrap(err, "Failed to obtain command")
+		}
+		for _, environmentVariable := range commandMessage.(*remoteexecution.Command).EnvironmentVariables {
+			if environmentVariable.Name == SyntheticExecutionDurationEnvironmentVariableName {
+				d, err := time.ParseDuration(environmentVariable.Value)
+				if err != nil {
+					return 0, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid execution duration")
+				}
+				return d, nil
+			}
+		}
+	}
+	return be.durationSampler(), nil
+}
+
+func (be *syntheticBuildExecutor) Execute(ctx context.Context, filePool filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	response := NewDefaultExecuteResponse(request)
+	action := request.Action
+	if action == nil {
+		attachErrorToExecuteResponse(response, status.Error(codes.InvalidArgument, "Request does not contain an action"))
+		return response
+	}
+	executionDuration, err := be.getExecutionDuration(ctx, digestFunction, action)
+	if err != nil {
+		attachErrorToExecuteResponse(response, err)
+		return response
+	}
+
+	executionStateUpdates <- &remoteworker.CurrentState_Executing{
+		ActionDigest: request.ActionDigest,
+		ExecutionState: &remoteworker.CurrentState_Executing_Running{
+			Running: &emptypb.Empty{},
+		},
+	}
+	startTime := be.clock.Now()
+	timer, timerChannel := be.clock.NewTimer(executionDuration)
+	select {
+	case <-timerChannel:
+	case <-ctx.Done():
+		timer.Stop()
+		attachErrorToExecuteResponse(response, util.StatusFromContext(ctx))
+		return response
+	}
+
+	executionMetadata := response.Result.ExecutionMetadata
+	response.Result = proto.Clone(be.actionResult).(*remoteexecution.ActionResult)
+	executionMetadata.ExecutionStartTimestamp = timestamppb.New(startTime)
+	executionMetadata.ExecutionCompletedTimestamp = timestamppb.New(be.clock.Now())
+	response.Result.ExecutionMetadata = executionMetadata
+	return response
+}
@@ -0,0 +1,124 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestSyntheticBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	clock := mock.NewMockClock(ctrl)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	buildExecutor := builder.NewSyntheticBuildExecutor(
+		clock,
+		func() time.Duration { return 5 * time.Second },
+		&remoteexecution.ActionResult{
+			StdoutRaw: []byte("Hello"),
+		},
+		contentAddressableStorage,
+		10000)
+
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5)
+	request := &remoteworker.DesiredState_Executing{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "d41d8cd98f00b204e9800998ecf8427e",
+			SizeBytes: 123,
+		},
+		Action: &remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "0cc175b9c0f1b6a831c399e269772661",
+				SizeBytes: 456,
+			},
+		},
+	}
+	commandDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "0cc175b9c0f1b6a831c399e269772661", 456)
+
+	t.Run("SampledDuration", func(t *testing.T) {
+		contentAddressableStorage.EXPECT().Get(ctx, commandDigest).
+			Return(buffer.NewProtoBufferFromProto(&remoteexecution.Command{}, buffer.UserProvided))
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		timerChannel := make(chan time.Time, 1)
+		timerChannel <- time.Unix(1005, 0)
+		clock.EXPECT().NewTimer(5*time.Second).Return(mock.NewMockTimer(ctrl), timerChannel)
+		clock.EXPECT().Now().Return(time.Unix(1005, 0))
+
+		metadata := make(chan *remoteworker.CurrentState_Executing, 10)
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				StdoutRaw: []byte("Hello"),
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+					ExecutionStartTimestamp:     &timestamppb.Timestamp{Seconds: 1000},
+					ExecutionCompletedTimestamp: &timestamppb.Timestamp{Seconds: 1005},
+				},
+			},
+		}, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata))
+		testutil.RequireEqualProto(t, &remoteworker.CurrentState_Executing{
+			ActionDigest: request.ActionDigest,
+			ExecutionState: &remoteworker.CurrentState_Executing_Running{
+				Running: &emptypb.Empty{},
+			},
+		}, <-metadata)
+	})
+
+	t.Run("EnvironmentVariableOverride", func(t *testing.T) {
+		// Actions may override the execution duration by
+		// setting an environment variable.
+		contentAddressableStorage.EXPECT().Get(ctx, commandDigest).
+			Return(buffer.NewProtoBufferFromProto(&remoteexecution.Command{
+				EnvironmentVariables: []*remoteexecution.Command_EnvironmentVariable{
+					{Name: "SYNTHETIC_WORKER_EXECUTION_DURATION", Value: "1.5s"},
+				},
+			}, buffer.UserProvided))
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		timerChannel := make(chan time.Time, 1)
+		timerChannel <- time.Unix(1001, 500000000)
+		clock.EXPECT().NewTimer(1500*time.Millisecond).Return(mock.NewMockTimer(ctrl), timerChannel)
+		clock.EXPECT().Now().Return(time.Unix(1001, 500000000))
+
+		metadata := make(chan *remoteworker.CurrentState_Executing, 10)
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				StdoutRaw: []byte("Hello"),
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+					ExecutionStartTimestamp:     &timestamppb.Timestamp{Seconds: 1000},
+					ExecutionCompletedTimestamp: &timestamppb.Timestamp{Seconds: 1001, Nanos: 500000000},
+				},
+			},
+		}, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata))
+	})
+
+	t.Run("InvalidEnvironmentVariable", func(t *testing.T) {
+		contentAddressableStorage.EXPECT().Get(ctx, commandDigest).
+			Return(buffer.NewProtoBufferFromProto(&remoteexecution.Command{
+				EnvironmentVariables: []*remoteexecution.Command_EnvironmentVariable{
+					{Name: "SYNTHETIC_WORKER_EXECUTION_DURATION", Value: "forever"},
+				},
+			}, buffer.UserProvided))
+
+		metadata := make(chan *remoteworker.CurrentState_Executing, 10)
+		testutil.RequireEqualProto(t, &remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{
+				ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{},
+			},
+			Status: status.New(codes.InvalidArgument, "Invalid execution duration: time: invalid duration \"forever\"").Proto(),
+		}, buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, metadata))
+	})
+}
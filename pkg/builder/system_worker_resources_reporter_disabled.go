@@ -0,0 +1,24 @@
+//go:build !linux
+// +build !linux
+
+package builder
+
+import (
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type systemWorkerResourcesReporter struct{}
+
+// NewSystemWorkerResourcesReporter creates a WorkerResourcesReporter
+// that reports the resources of the locally running operating system.
+// On this operating system this functionality is not available.
+func NewSystemWorkerResourcesReporter(buildDirectoryPath string) WorkerResourcesReporter {
+	return systemWorkerResourcesReporter{}
+}
+
+func (wrr systemWorkerResourcesReporter) GetWorkerResources() (*remoteworker.WorkerResources, error) {
+	return nil, status.Error(codes.Unimplemented, "Reporting worker resources is not supported on this platform")
+}
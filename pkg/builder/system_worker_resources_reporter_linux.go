@@ -0,0 +1,98 @@
+//go:build linux
+// +build linux
+
+package builder
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type systemWorkerResourcesReporter struct {
+	buildDirectoryPath string
+}
+
+// NewSystemWorkerResourcesReporter creates a WorkerResourcesReporter
+// that reports the resources of the locally running operating system.
+// The amount of free space is obtained from the file system containing
+// the build directory, while the amount of available memory and the
+// load average are obtained from procfs.
+func NewSystemWorkerResourcesReporter(buildDirectoryPath string) WorkerResourcesReporter {
+	return &systemWorkerResourcesReporter{
+		buildDirectoryPath: buildDirectoryPath,
+	}
+}
+
+func (wrr *systemWorkerResourcesReporter) GetWorkerResources() (*remoteworker.WorkerResources, error) {
+	var statfs unix.Statfs_t
+	if err := unix.Statfs(wrr.buildDirectoryPath, &statfs); err != nil {
+		return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to obtain file system statistics for %#v", wrr.buildDirectoryPath)
+	}
+	availableMemoryBytes, err := getAvailableMemoryBytes()
+	if err != nil {
+		return nil, err
+	}
+	loadAverage, err := getLoadAverage()
+	if err != nil {
+		return nil, err
+	}
+	return &remoteworker.WorkerResources{
+		BuildDirectoryFreeBytes: statfs.Bavail * uint64(statfs.Bsize),
+		AvailableMemoryBytes:    availableMemoryBytes,
+		LoadAverage:             loadAverage,
+	}, nil
+}
+
+// getAvailableMemoryBytes returns the amount of memory that is
+// available for starting new applications without swapping, as
+// reported by the "MemAvailable" field in /proc/meminfo.
+func getAvailableMemoryBytes() (uint64, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, util.StatusWrapWithCode(err, codes.Internal, "Failed to open memory statistics")
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 3 && fields[0] == "MemAvailable:" && fields[2] == "kB" {
+			kilobytes, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, util.StatusWrapfWithCode(err, codes.Internal, "Invalid amount of available memory %#v", fields[1])
+			}
+			return kilobytes * 1024, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, util.StatusWrapWithCode(err, codes.Internal, "Failed to read memory statistics")
+	}
+	return 0, status.Error(codes.Internal, "Memory statistics do not contain the amount of available memory")
+}
+
+// getLoadAverage returns the system's one minute load average, as
+// reported by /proc/loadavg.
+func getLoadAverage() (float64, error) {
+	data, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return 0, util.StatusWrapWithCode(err, codes.Internal, "Failed to read load average")
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, status.Error(codes.Internal, "Load average is empty")
+	}
+	loadAverage, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, util.StatusWrapfWithCode(err, codes.Internal, "Invalid load average %#v", fields[0])
+	}
+	return loadAverage, nil
+}
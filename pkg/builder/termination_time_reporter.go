@@ -0,0 +1,50 @@
+package builder
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TerminationTimeReporter is called into by BuildClient to obtain the
+// time at which the worker is expected to terminate. This is reported
+// to the scheduler, so that it can refrain from assigning tasks to the
+// worker that are not expected to complete in time.
+type TerminationTimeReporter interface {
+	GetExpectedTerminationTime() (*timestamppb.Timestamp, error)
+}
+
+type fileTerminationTimeReporter struct {
+	path string
+}
+
+// NewFileTerminationTimeReporter creates a TerminationTimeReporter
+// that reads an RFC 3339 timestamp from a file. This file may be
+// written by lifecycle hooks of autoscalers, or by handlers of spot
+// instance interruption notices. If the file does not exist, the
+// worker is not expected to terminate.
+func NewFileTerminationTimeReporter(path string) TerminationTimeReporter {
+	return &fileTerminationTimeReporter{
+		path: path,
+	}
+}
+
+func (ttr *fileTerminationTimeReporter) GetExpectedTerminationTime() (*timestamppb.Timestamp, error) {
+	data, err := os.ReadFile(ttr.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, util.StatusWrapf(err, "Failed to read expected termination time from %#v", ttr.path)
+	}
+	terminationTime, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid expected termination time in %#v", ttr.path)
+	}
+	return timestamppb.New(terminationTime), nil
+}
@@ -0,0 +1,210 @@
+package builder
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	tmpfsBuildDirectoryCreatorPrometheusMetrics sync.Once
+
+	tmpfsBuildDirectoryCreatorBuildDirectories = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "builder",
+			Name:      "tmpfs_build_directory_creator_build_directories_total",
+			Help:      "Number of build directories handed out by TmpfsBuildDirectoryCreator, by whether they were backed by tmpfs or fell back to disk.",
+		},
+		[]string{"storage"})
+	tmpfsBuildDirectoryCreatorBuildDirectoriesTmpfs = tmpfsBuildDirectoryCreatorBuildDirectories.WithLabelValues("Tmpfs")
+	tmpfsBuildDirectoryCreatorBuildDirectoriesDisk  = tmpfsBuildDirectoryCreatorBuildDirectories.WithLabelValues("Disk")
+
+	tmpfsBuildDirectoryCreatorAllocatedSizeBytes = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "buildbarn",
+			Subsystem: "builder",
+			Name:      "tmpfs_build_directory_creator_allocated_size_bytes",
+			Help:      "Total size of all instances of tmpfs that are currently mounted by TmpfsBuildDirectoryCreator.",
+		})
+	tmpfsBuildDirectoryCreatorUsedSizeBytes = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "buildbarn",
+			Subsystem: "builder",
+			Name:      "tmpfs_build_directory_creator_used_size_bytes",
+			Help:      "Amount of space in use by build directories backed by tmpfs, measured right before they are removed.",
+			Buckets:   prometheus.ExponentialBuckets(1.0, 2.0, 33),
+		})
+)
+
+// TmpfsBuildDirectoryCapacity keeps track of the total size of all
+// instances of tmpfs that are mounted by TmpfsBuildDirectoryCreators.
+// A single instance should be shared by all runners of a worker, as
+// they all share the same physical memory.
+type TmpfsBuildDirectoryCapacity struct {
+	maximumSizeBytes uint64
+
+	lock      sync.Mutex
+	sizeBytes uint64
+}
+
+// NewTmpfsBuildDirectoryCapacity creates a TmpfsBuildDirectoryCapacity
+// that permits instances of tmpfs to be mounted, as long as their total
+// size does not exceed a given limit.
+func NewTmpfsBuildDirectoryCapacity(maximumSizeBytes uint64) *TmpfsBuildDirectoryCapacity {
+	return &TmpfsBuildDirectoryCapacity{
+		maximumSizeBytes: maximumSizeBytes,
+	}
+}
+
+// reserve space for an instance of tmpfs, if available.
+func (c *TmpfsBuildDirectoryCapacity) reserve(sizeBytes uint64) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if sizeBytes > c.maximumSizeBytes || c.sizeBytes > c.maximumSizeBytes-sizeBytes {
+		return false
+	}
+	c.sizeBytes += sizeBytes
+	tmpfsBuildDirectoryCreatorAllocatedSizeBytes.Add(float64(sizeBytes))
+	return true
+}
+
+// release space of an instance of tmpfs that was previously reserved.
+func (c *TmpfsBuildDirectoryCapacity) release(sizeBytes uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.sizeBytes -= sizeBytes
+	tmpfsBuildDirectoryCreatorAllocatedSizeBytes.Sub(float64(sizeBytes))
+}
+
+type tmpfsBuildDirectoryCreator struct {
+	base               BuildDirectoryCreator
+	buildDirectoryPath string
+	tmpfsManager       TmpfsManager
+	sizeBytes          uint64
+	capacity           *TmpfsBuildDirectoryCapacity
+}
+
+// NewTmpfsBuildDirectoryCreator is an adapter for BuildDirectoryCreator
+// that causes build actions to be executed on an instance of tmpfs,
+// which is mounted for every action individually. This permits actions
+// that perform many small I/O operations to run entirely from memory.
+//
+// This adapter needs to be placed underneath
+// NewSharedBuildDirectoryCreator(), as it mounts tmpfs on top of the
+// per-action subdirectory when it is entered. The size of the tmpfs
+// is fixed, and is typically derived from the size class of the
+// runner. If mounting it would cause the total size of all instances
+// of tmpfs to exceed the capacity, the build directory remains backed
+// by disk.
+func NewTmpfsBuildDirectoryCreator(base BuildDirectoryCreator, buildDirectoryPath string, tmpfsManager TmpfsManager, sizeBytes uint64, capacity *TmpfsBuildDirectoryCapacity) BuildDirectoryCreator {
+	tmpfsBuildDirectoryCreatorPrometheusMetrics.Do(func() {
+		prometheus.MustRegister(tmpfsBuildDirectoryCreatorBuildDirectories)
+		prometheus.MustRegister(tmpfsBuildDirectoryCreatorAllocatedSizeBytes)
+		prometheus.MustRegister(tmpfsBuildDirectoryCreatorUsedSizeBytes)
+	})
+
+	return &tmpfsBuildDirectoryCreator{
+		base:               base,
+		buildDirectoryPath: buildDirectoryPath,
+		tmpfsManager:       tmpfsManager,
+		sizeBytes:          sizeBytes,
+		capacity:           capacity,
+	}
+}
+
+func (dc *tmpfsBuildDirectoryCreator) GetBuildDirectory(ctx context.Context, actionDigestIfNotRunInParallel *digest.Digest) (BuildDirectory, *path.Trace, error) {
+	parentDirectory, parentDirectoryPath, err := dc.base.GetBuildDirectory(ctx, actionDigestIfNotRunInParallel)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !dc.capacity.reserve(dc.sizeBytes) {
+		tmpfsBuildDirectoryCreatorBuildDirectoriesDisk.Inc()
+		return parentDirectory, parentDirectoryPath, nil
+	}
+	tmpfsBuildDirectoryCreatorBuildDirectoriesTmpfs.Inc()
+	return &tmpfsParentBuildDirectory{
+		BuildDirectory:      parentDirectory,
+		creator:             dc,
+		parentDirectoryPath: filepath.Join(dc.buildDirectoryPath, parentDirectoryPath.String()),
+	}, parentDirectoryPath, nil
+}
+
+// tmpfsParentBuildDirectory is the parent directory of a per-action
+// build directory, on which tmpfs gets mounted when entered.
+type tmpfsParentBuildDirectory struct {
+	BuildDirectory
+	creator             *tmpfsBuildDirectoryCreator
+	parentDirectoryPath string
+	mountpoint          *path.Component
+}
+
+func (d *tmpfsParentBuildDirectory) getMountpointPath(name path.Component) string {
+	return filepath.Join(d.parentDirectoryPath, name.String())
+}
+
+// unmount the tmpfs, if mounted on a given child directory.
+func (d *tmpfsParentBuildDirectory) unmount(name path.Component) error {
+	if d.mountpoint == nil || *d.mountpoint != name {
+		return nil
+	}
+	d.mountpoint = nil
+	usedSizeBytes, err := d.creator.tmpfsManager.UnmountTmpfs(d.getMountpointPath(name))
+	if err != nil {
+		return err
+	}
+	tmpfsBuildDirectoryCreatorUsedSizeBytes.Observe(float64(usedSizeBytes))
+	return nil
+}
+
+func (d *tmpfsParentBuildDirectory) EnterBuildDirectory(name path.Component) (BuildDirectory, error) {
+	if d.mountpoint != nil {
+		return d.BuildDirectory.EnterBuildDirectory(name)
+	}
+
+	// Mount tmpfs prior to entering the directory, as the
+	// directory handle would otherwise refer to the underlying
+	// directory on disk.
+	if err := d.creator.tmpfsManager.MountTmpfs(d.getMountpointPath(name), d.creator.sizeBytes); err != nil {
+		return nil, err
+	}
+	d.mountpoint = &name
+	child, err := d.BuildDirectory.EnterBuildDirectory(name)
+	if err != nil {
+		d.unmount(name)
+		return nil, err
+	}
+	return child, nil
+}
+
+func (d *tmpfsParentBuildDirectory) Remove(name path.Component) error {
+	if err := d.unmount(name); err != nil {
+		return err
+	}
+	return d.BuildDirectory.Remove(name)
+}
+
+func (d *tmpfsParentBuildDirectory) RemoveAll(name path.Component) error {
+	if err := d.unmount(name); err != nil {
+		return err
+	}
+	return d.BuildDirectory.RemoveAll(name)
+}
+
+func (d *tmpfsParentBuildDirectory) Close() error {
+	var err1 error
+	if d.mountpoint != nil {
+		err1 = d.unmount(*d.mountpoint)
+	}
+	d.creator.capacity.release(d.creator.sizeBytes)
+	err2 := d.BuildDirectory.Close()
+	if err1 != nil {
+		return util.StatusWrap(err1, "Failed to unmount build directory")
+	}
+	return err2
+}
@@ -0,0 +1,112 @@
+package builder_test
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTmpfsBuildDirectoryCreator(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildDirectoryCreator := mock.NewMockBuildDirectoryCreator(ctrl)
+	tmpfsManager := mock.NewMockTmpfsManager(ctrl)
+	capacity := builder.NewTmpfsBuildDirectoryCapacity(1536)
+	var nextParallelActionID atomic.Uint64
+	buildDirectoryCreator := builder.NewSharedBuildDirectoryCreator(
+		builder.NewTmpfsBuildDirectoryCreator(
+			baseBuildDirectoryCreator,
+			"/worker/build",
+			tmpfsManager,
+			1024,
+			capacity),
+		&nextParallelActionID,
+		/* randomNameGenerator = */ nil)
+
+	actionDigest1 := digest.MustNewDigest("debian8", remoteexecution.DigestFunction_SHA256, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", 0)
+	actionDigest2 := digest.MustNewDigest("debian8", remoteexecution.DigestFunction_SHA256, "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb", 1)
+
+	t.Run("MountFailure", func(t *testing.T) {
+		baseBuildDirectory := mock.NewMockBuildDirectory(ctrl)
+		baseBuildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest1).Return(baseBuildDirectory, nil, nil)
+		baseBuildDirectory.EXPECT().Mkdir(path.MustNewComponent("e3b0c44298fc1c14"), os.FileMode(0o777))
+		tmpfsManager.EXPECT().MountTmpfs("/worker/build/e3b0c44298fc1c14", uint64(1024)).
+			Return(status.Error(codes.PermissionDenied, "Failed to mount tmpfs at \"/worker/build/e3b0c44298fc1c14\": operation not permitted"))
+		baseBuildDirectory.EXPECT().Remove(path.MustNewComponent("e3b0c44298fc1c14"))
+		baseBuildDirectory.EXPECT().Close()
+
+		_, _, err := buildDirectoryCreator.GetBuildDirectory(ctx, &actionDigest1)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to enter build directory \"e3b0c44298fc1c14\": Failed to mount tmpfs at \"/worker/build/e3b0c44298fc1c14\": operation not permitted"), err)
+	})
+
+	t.Run("FallbackToDisk", func(t *testing.T) {
+		// The first action should be run on tmpfs. As the
+		// capacity does not permit a second instance of tmpfs
+		// to be mounted, the second action should be run on
+		// disk.
+		baseBuildDirectory1 := mock.NewMockBuildDirectory(ctrl)
+		baseBuildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest1).Return(baseBuildDirectory1, nil, nil)
+		baseBuildDirectory1.EXPECT().Mkdir(path.MustNewComponent("e3b0c44298fc1c14"), os.FileMode(0o777))
+		tmpfsManager.EXPECT().MountTmpfs("/worker/build/e3b0c44298fc1c14", uint64(1024))
+		childBuildDirectory1 := mock.NewMockBuildDirectory(ctrl)
+		baseBuildDirectory1.EXPECT().EnterBuildDirectory(path.MustNewComponent("e3b0c44298fc1c14")).Return(childBuildDirectory1, nil)
+
+		buildDirectory1, buildDirectoryPath1, err := buildDirectoryCreator.GetBuildDirectory(ctx, &actionDigest1)
+		require.NoError(t, err)
+		require.Equal(t, "e3b0c44298fc1c14", buildDirectoryPath1.String())
+
+		baseBuildDirectory2 := mock.NewMockBuildDirectory(ctrl)
+		baseBuildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest2).Return(baseBuildDirectory2, nil, nil)
+		baseBuildDirectory2.EXPECT().Mkdir(path.MustNewComponent("ca978112ca1bbdca"), os.FileMode(0o777))
+		childBuildDirectory2 := mock.NewMockBuildDirectory(ctrl)
+		baseBuildDirectory2.EXPECT().EnterBuildDirectory(path.MustNewComponent("ca978112ca1bbdca")).Return(childBuildDirectory2, nil)
+
+		buildDirectory2, buildDirectoryPath2, err := buildDirectoryCreator.GetBuildDirectory(ctx, &actionDigest2)
+		require.NoError(t, err)
+		require.Equal(t, "ca978112ca1bbdca", buildDirectoryPath2.String())
+
+		// Closing the build directories should cause the tmpfs
+		// to be unmounted prior to removing the directory.
+		childBuildDirectory1.EXPECT().Close()
+		tmpfsManager.EXPECT().UnmountTmpfs("/worker/build/e3b0c44298fc1c14").Return(uint64(300), nil)
+		baseBuildDirectory1.EXPECT().RemoveAll(path.MustNewComponent("e3b0c44298fc1c14"))
+		baseBuildDirectory1.EXPECT().Close()
+		require.NoError(t, buildDirectory1.Close())
+
+		childBuildDirectory2.EXPECT().Close()
+		baseBuildDirectory2.EXPECT().RemoveAll(path.MustNewComponent("ca978112ca1bbdca"))
+		baseBuildDirectory2.EXPECT().Close()
+		require.NoError(t, buildDirectory2.Close())
+
+		// Now that capacity has been released, the next action
+		// should be run on tmpfs once more.
+		baseBuildDirectory3 := mock.NewMockBuildDirectory(ctrl)
+		baseBuildDirectoryCreator.EXPECT().GetBuildDirectory(ctx, &actionDigest2).Return(baseBuildDirectory3, nil, nil)
+		baseBuildDirectory3.EXPECT().Mkdir(path.MustNewComponent("ca978112ca1bbdca"), os.FileMode(0o777))
+		tmpfsManager.EXPECT().MountTmpfs("/worker/build/ca978112ca1bbdca", uint64(1024))
+		childBuildDirectory3 := mock.NewMockBuildDirectory(ctrl)
+		baseBuildDirectory3.EXPECT().EnterBuildDirectory(path.MustNewComponent("ca978112ca1bbdca")).Return(childBuildDirectory3, nil)
+
+		buildDirectory3, _, err := buildDirectoryCreator.GetBuildDirectory(ctx, &actionDigest2)
+		require.NoError(t, err)
+
+		childBuildDirectory3.EXPECT().Close()
+		tmpfsManager.EXPECT().UnmountTmpfs("/worker/build/ca978112ca1bbdca").Return(uint64(0), nil)
+		baseBuildDirectory3.EXPECT().RemoveAll(path.MustNewComponent("ca978112ca1bbdca"))
+		baseBuildDirectory3.EXPECT().Close()
+		require.NoError(t, buildDirectory3.Close())
+	})
+}
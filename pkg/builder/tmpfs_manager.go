@@ -0,0 +1,14 @@
+package builder
+
+// TmpfsManager is used by NewTmpfsBuildDirectoryCreator() to mount
+// instances of tmpfs on which build actions are executed.
+type TmpfsManager interface {
+	// MountTmpfs mounts a tmpfs at target, whose size is limited to
+	// a given number of bytes. The target path must be absolute.
+	MountTmpfs(target string, sizeBytes uint64) error
+
+	// UnmountTmpfs unmounts a tmpfs that was previously mounted
+	// through MountTmpfs(). The amount of space that was in use
+	// right before unmounting is returned.
+	UnmountTmpfs(target string) (uint64, error)
+}
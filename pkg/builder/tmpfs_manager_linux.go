@@ -0,0 +1,44 @@
+//go:build linux
+// +build linux
+
+package builder
+
+import (
+	"strconv"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+)
+
+type localTmpfsManager struct{}
+
+// NewLocalTmpfsManager creates a TmpfsManager that mounts instances of
+// tmpfs using the mount() system call.
+func NewLocalTmpfsManager() (TmpfsManager, error) {
+	return localTmpfsManager{}, nil
+}
+
+func (localTmpfsManager) MountTmpfs(target string, sizeBytes uint64) error {
+	if err := unix.Mount("tmpfs", target, "tmpfs", unix.MS_NOSUID|unix.MS_NODEV, "size="+strconv.FormatUint(sizeBytes, 10)); err != nil {
+		return util.StatusWrapfWithCode(err, codes.Internal, "Failed to mount tmpfs at %#v", target)
+	}
+	return nil
+}
+
+func (localTmpfsManager) UnmountTmpfs(target string) (uint64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(target, &stat); err != nil {
+		return 0, util.StatusWrapfWithCode(err, codes.Internal, "Failed to obtain file system statistics of tmpfs at %#v", target)
+	}
+
+	// Perform a lazy unmount, so that processes that are left
+	// behind by the build action don't prevent removal of the
+	// build directory. Memory is released once these processes
+	// terminate.
+	if err := unix.Unmount(target, unix.MNT_DETACH); err != nil {
+		return 0, util.StatusWrapfWithCode(err, codes.Internal, "Failed to unmount tmpfs at %#v", target)
+	}
+	return (stat.Blocks - stat.Bfree) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,57 @@
+package builder
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+type traversingDirectoryQuotaManager struct{}
+
+// TraversingDirectoryQuotaManager is an implementation of
+// DirectoryQuotaManager that computes the size of a directory by
+// recursively traversing it and summing the sizes of all files
+// contained within. It does not rely on any features of the underlying
+// file system, meaning limits can only be enforced by polling.
+//
+// Traversing large directories is expensive. It is therefore
+// recommended to use project quotas if the file system supports them.
+var TraversingDirectoryQuotaManager DirectoryQuotaManager = traversingDirectoryQuotaManager{}
+
+func (traversingDirectoryQuotaManager) NewDirectoryQuota(directoryPath string, maximumSizeBytes int64) (DirectoryQuota, error) {
+	return traversingDirectoryQuota{directoryPath: directoryPath}, nil
+}
+
+type traversingDirectoryQuota struct {
+	directoryPath string
+}
+
+func (dq traversingDirectoryQuota) GetSizeBytes() (int64, error) {
+	sizeBytes := int64(0)
+	err := filepath.WalkDir(dq.directoryPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Build actions may remove files while the
+			// directory is being traversed.
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			sizeBytes += info.Size()
+		}
+		return nil
+	})
+	return sizeBytes, err
+}
+
+func (traversingDirectoryQuota) Release() error {
+	return nil
+}
@@ -0,0 +1,120 @@
+package builder
+
+import (
+	"context"
+	"sync"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	trivialActionRoutingBuildExecutorPrometheusMetrics sync.Once
+
+	trivialActionRoutingBuildExecutorActions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "builder",
+			Name:      "trivial_action_routing_build_executor_actions_total",
+			Help:      "Number of actions processed by TrivialActionRoutingBuildExecutor, by whether they were executed through the fast path.",
+		},
+		[]string{"path"})
+	trivialActionRoutingBuildExecutorActionsTrivial = trivialActionRoutingBuildExecutorActions.WithLabelValues("Trivial")
+	trivialActionRoutingBuildExecutorActionsRegular = trivialActionRoutingBuildExecutorActions.WithLabelValues("Regular")
+)
+
+type trivialActionRoutingBuildExecutor struct {
+	BuildExecutor
+	trivialBuildExecutor      BuildExecutor
+	directoryFetcher          cas.DirectoryFetcher
+	maximumInputRootNodes     int
+	maximumInputRootSizeBytes int64
+}
+
+// NewTrivialActionRoutingBuildExecutor creates a decorator for
+// BuildExecutor that forwards actions having a small input root (e.g.,
+// ones that write a single file or create a symbolic link) to a
+// separate BuildExecutor. For such actions, the fixed cost of setting
+// up a virtual build directory and allocating space from the FilePool
+// tends to dominate the execution time.
+//
+// The separate BuildExecutor is expected to execute actions in a build
+// directory on local disk, preferably backed by tmpfs. Files created by
+// these actions that need to be stored in the FilePool are kept in
+// memory.
+//
+// This decorator needs to be placed directly on top of the
+// BuildExecutors created by NewLocalBuildExecutor(), so that the
+// policies applied by other decorators apply to both paths.
+func NewTrivialActionRoutingBuildExecutor(base, trivialBuildExecutor BuildExecutor, directoryFetcher cas.DirectoryFetcher, maximumInputRootNodes int, maximumInputRootSizeBytes int64) BuildExecutor {
+	trivialActionRoutingBuildExecutorPrometheusMetrics.Do(func() {
+		prometheus.MustRegister(trivialActionRoutingBuildExecutorActions)
+	})
+
+	return &trivialActionRoutingBuildExecutor{
+		BuildExecutor:             base,
+		trivialBuildExecutor:      trivialBuildExecutor,
+		directoryFetcher:          directoryFetcher,
+		maximumInputRootNodes:     maximumInputRootNodes,
+		maximumInputRootSizeBytes: maximumInputRootSizeBytes,
+	}
+}
+
+// isTrivial returns whether the input root of an action is small
+// enough to be executed through the fast path. Loading of the input
+// root is aborted as soon as the limits are exceeded.
+func (be *trivialActionRoutingBuildExecutor) isTrivial(ctx context.Context, digestFunction digest.Function, action *remoteexecution.Action) bool {
+	if action == nil {
+		return false
+	}
+	rootDirectoryDigest, err := digestFunction.NewDigestFromProto(action.InputRootDigest)
+	if err != nil {
+		// Let the regular path report the error.
+		return false
+	}
+
+	nodes := 0
+	sizeBytes := int64(0)
+	pendingDirectoryDigests := []digest.Digest{rootDirectoryDigest}
+	for len(pendingDirectoryDigests) > 0 {
+		directoryDigest := pendingDirectoryDigests[len(pendingDirectoryDigests)-1]
+		pendingDirectoryDigests = pendingDirectoryDigests[:len(pendingDirectoryDigests)-1]
+		directory, err := be.directoryFetcher.GetDirectory(ctx, directoryDigest)
+		if err != nil {
+			return false
+		}
+
+		nodes += len(directory.Directories) + len(directory.Files) + len(directory.Symlinks)
+		if nodes > be.maximumInputRootNodes {
+			return false
+		}
+		for _, file := range directory.Files {
+			sizeBytes += file.Digest.GetSizeBytes()
+			if sizeBytes > be.maximumInputRootSizeBytes {
+				return false
+			}
+		}
+		for _, childDirectory := range directory.Directories {
+			childDirectoryDigest, err := digestFunction.NewDigestFromProto(childDirectory.Digest)
+			if err != nil {
+				return false
+			}
+			pendingDirectoryDigests = append(pendingDirectoryDigests, childDirectoryDigest)
+		}
+	}
+	return true
+}
+
+func (be *trivialActionRoutingBuildExecutor) Execute(ctx context.Context, filePool re_filesystem.FilePool, monitor access.UnreadDirectoryMonitor, digestFunction digest.Function, request *remoteworker.DesiredState_Executing, executionStateUpdates chan<- *remoteworker.CurrentState_Executing) *remoteexecution.ExecuteResponse {
+	if !be.isTrivial(ctx, digestFunction, request.Action) {
+		trivialActionRoutingBuildExecutorActionsRegular.Inc()
+		return be.BuildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, executionStateUpdates)
+	}
+	trivialActionRoutingBuildExecutorActionsTrivial.Inc()
+	return be.trivialBuildExecutor.Execute(ctx, re_filesystem.InMemoryFilePool, monitor, digestFunction, request, executionStateUpdates)
+}
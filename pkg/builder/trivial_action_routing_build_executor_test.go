@@ -0,0 +1,160 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+)
+
+func TestTrivialActionRoutingBuildExecutor(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	trivialBuildExecutor := mock.NewMockBuildExecutor(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	buildExecutor := builder.NewTrivialActionRoutingBuildExecutor(
+		baseBuildExecutor,
+		trivialBuildExecutor,
+		directoryFetcher,
+		/* maximumInputRootNodes = */ 3,
+		/* maximumInputRootSizeBytes = */ 100)
+
+	filePool := mock.NewMockFilePool(ctrl)
+	monitor := mock.NewMockUnreadDirectoryMonitor(ctrl)
+	digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5)
+	newRequest := func(inputRootDigest *remoteexecution.Digest) *remoteworker.DesiredState_Executing {
+		return &remoteworker.DesiredState_Executing{
+			Action: &remoteexecution.Action{
+				CommandDigest: &remoteexecution.Digest{
+					Hash:      "6b7b5d3a5de6e8a1cba2d5bfb2c0c3d4",
+					SizeBytes: 123,
+				},
+				InputRootDigest: inputRootDigest,
+			},
+		}
+	}
+
+	t.Run("EmptyInputRoot", func(t *testing.T) {
+		// Actions with an empty input root should always be
+		// executed through the fast path, using a FilePool
+		// that is backed by memory.
+		request := newRequest(&remoteexecution.Digest{
+			Hash:      "d41d8cd98f00b204e9800998ecf8427e",
+			SizeBytes: 0,
+		})
+		directoryFetcher.EXPECT().GetDirectory(ctx, digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "d41d8cd98f00b204e9800998ecf8427e", 0)).
+			Return(&remoteexecution.Directory{}, nil)
+		trivialBuildExecutor.EXPECT().Execute(ctx, re_filesystem.InMemoryFilePool, monitor, digestFunction, request, gomock.Any()).
+			Return(&remoteexecution.ExecuteResponse{Result: &remoteexecution.ActionResult{ExitCode: 0}})
+
+		testutil.RequireEqualProto(
+			t,
+			&remoteexecution.ExecuteResponse{Result: &remoteexecution.ActionResult{ExitCode: 0}},
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, make(chan *remoteworker.CurrentState_Executing, 10)))
+	})
+
+	t.Run("SmallInputRoot", func(t *testing.T) {
+		// Input roots that are within the limits should also be
+		// executed through the fast path.
+		request := newRequest(&remoteexecution.Digest{
+			Hash:      "4d5f6d1c4e4a3b2a1f0e9d8c7b6a5f4e",
+			SizeBytes: 80,
+		})
+		directoryFetcher.EXPECT().GetDirectory(ctx, digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "4d5f6d1c4e4a3b2a1f0e9d8c7b6a5f4e", 80)).
+			Return(&remoteexecution.Directory{
+				Directories: []*remoteexecution.DirectoryNode{
+					{
+						Name: "bin",
+						Digest: &remoteexecution.Digest{
+							Hash:      "a1b2c3d4e5f60718293a4b5c6d7e8f90",
+							SizeBytes: 40,
+						},
+					},
+				},
+			}, nil)
+		directoryFetcher.EXPECT().GetDirectory(ctx, digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "a1b2c3d4e5f60718293a4b5c6d7e8f90", 40)).
+			Return(&remoteexecution.Directory{
+				Files: []*remoteexecution.FileNode{
+					{
+						Name: "input.txt",
+						Digest: &remoteexecution.Digest{
+							Hash:      "0f1e2d3c4b5a69788796a5b4c3d2e1f0",
+							SizeBytes: 100,
+						},
+					},
+				},
+				Symlinks: []*remoteexecution.SymlinkNode{
+					{Name: "link", Target: "input.txt"},
+				},
+			}, nil)
+		trivialBuildExecutor.EXPECT().Execute(ctx, re_filesystem.InMemoryFilePool, monitor, digestFunction, request, gomock.Any()).
+			Return(&remoteexecution.ExecuteResponse{Result: &remoteexecution.ActionResult{ExitCode: 0}})
+
+		testutil.RequireEqualProto(
+			t,
+			&remoteexecution.ExecuteResponse{Result: &remoteexecution.ActionResult{ExitCode: 0}},
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, make(chan *remoteworker.CurrentState_Executing, 10)))
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		// Input roots whose files exceed the maximum size
+		// should be executed through the regular path.
+		request := newRequest(&remoteexecution.Digest{
+			Hash:      "4d5f6d1c4e4a3b2a1f0e9d8c7b6a5f4e",
+			SizeBytes: 80,
+		})
+		directoryFetcher.EXPECT().GetDirectory(ctx, digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "4d5f6d1c4e4a3b2a1f0e9d8c7b6a5f4e", 80)).
+			Return(&remoteexecution.Directory{
+				Files: []*remoteexecution.FileNode{
+					{
+						Name: "large.bin",
+						Digest: &remoteexecution.Digest{
+							Hash:      "0f1e2d3c4b5a69788796a5b4c3d2e1f0",
+							SizeBytes: 101,
+						},
+					},
+				},
+			}, nil)
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, gomock.Any()).
+			Return(&remoteexecution.ExecuteResponse{Result: &remoteexecution.ActionResult{ExitCode: 1}})
+
+		testutil.RequireEqualProto(
+			t,
+			&remoteexecution.ExecuteResponse{Result: &remoteexecution.ActionResult{ExitCode: 1}},
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, make(chan *remoteworker.CurrentState_Executing, 10)))
+	})
+
+	t.Run("TooManyNodes", func(t *testing.T) {
+		// Input roots containing too many nodes should be
+		// executed through the regular path. There is no need
+		// to load child directories.
+		request := newRequest(&remoteexecution.Digest{
+			Hash:      "4d5f6d1c4e4a3b2a1f0e9d8c7b6a5f4e",
+			SizeBytes: 80,
+		})
+		directoryFetcher.EXPECT().GetDirectory(ctx, digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "4d5f6d1c4e4a3b2a1f0e9d8c7b6a5f4e", 80)).
+			Return(&remoteexecution.Directory{
+				Symlinks: []*remoteexecution.SymlinkNode{
+					{Name: "a", Target: "x"},
+					{Name: "b", Target: "x"},
+					{Name: "c", Target: "x"},
+					{Name: "d", Target: "x"},
+				},
+			}, nil)
+		baseBuildExecutor.EXPECT().Execute(ctx, filePool, monitor, digestFunction, request, gomock.Any()).
+			Return(&remoteexecution.ExecuteResponse{Result: &remoteexecution.ActionResult{ExitCode: 1}})
+
+		testutil.RequireEqualProto(
+			t,
+			&remoteexecution.ExecuteResponse{Result: &remoteexecution.ActionResult{ExitCode: 1}},
+			buildExecutor.Execute(ctx, filePool, monitor, digestFunction, request, make(chan *remoteworker.CurrentState_Executing, 10)))
+	})
+}
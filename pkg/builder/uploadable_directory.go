@@ -3,6 +3,7 @@ package builder
 import (
 	"context"
 
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/filesystem"
 	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
@@ -20,6 +21,31 @@ type UploadableDirectory interface {
 	ReadDir() ([]filesystem.FileInfo, error)
 	Readlink(name path.Component) (string, error)
 
+	// Obtain the size of a regular file. This is used to enforce
+	// limits on the size of outputs prior to uploading them.
+	GetFileSizeBytes(ctx context.Context, name path.Component) (int64, error)
+
 	// Upload a file into the Content Addressable Storage.
 	UploadFile(ctx context.Context, name path.Component, digestFunction digest.Function) (digest.Digest, error)
+
+	// Obtain properties of a file, such as its modification time
+	// and mode. These are attached to output files if requested
+	// through the Command's output_node_properties field. Fields
+	// that cannot be obtained are left unset.
+	GetNodeProperties(ctx context.Context, name path.Component) (*remoteexecution.NodeProperties, error)
+}
+
+// ModificationTrackingDirectory is an optional interface that may be
+// implemented by UploadableDirectory. It permits
+// OutputHierarchy.UploadOutputs() to skip outputs that were not
+// modified by the build action, instead of calling Lstat() on every
+// location where an output is expected.
+type ModificationTrackingDirectory interface {
+	// LookupModifiedChildren() returns the names of children of the
+	// directory that may have been modified by the build action.
+	// Children that were created by the build action are mapped to
+	// true. Directories that may contain modified children are
+	// mapped to false. If modifications are not tracked, a nil map
+	// is returned.
+	LookupModifiedChildren() (map[path.Component]bool, error)
 }
@@ -2,21 +2,29 @@ package builder
 
 import (
 	"context"
+	"encoding/base64"
+	"io"
 	"os"
 	"syscall"
 
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-remote-execution/pkg/cas"
 	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
 	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
 	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
 	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/clock"
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/filesystem"
 	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
 	"github.com/buildbarn/bb-storage/pkg/util"
 
+	"golang.org/x/sync/semaphore"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type virtualBuildDirectoryOptions struct {
@@ -25,11 +33,16 @@ type virtualBuildDirectoryOptions struct {
 	symlinkFactory            virtual.SymlinkFactory
 	characterDeviceFactory    virtual.CharacterDeviceFactory
 	handleAllocator           virtual.StatefulHandleAllocator
+	eagerUploadSemaphore      *semaphore.Weighted
+	passthroughCacheDirectory string
+	onlyUploadModifiedOutputs bool
+	reportOperationStatistics bool
 }
 
 type virtualBuildDirectory struct {
 	virtual.PrepopulatedDirectory
-	options *virtualBuildDirectoryOptions
+	options             *virtualBuildDirectoryOptions
+	operationStatistics *virtual.OperationStatistics
 }
 
 // NewVirtualBuildDirectory creates a BuildDirectory that is backed by a
@@ -37,7 +50,28 @@ type virtualBuildDirectory struct {
 // input root explicitly, it calls PrepopulatedDirectory.CreateChildren
 // to add special file and directory nodes whose contents are read on
 // demand.
-func NewVirtualBuildDirectory(directory virtual.PrepopulatedDirectory, directoryFetcher cas.DirectoryFetcher, contentAddressableStorage blobstore.BlobAccess, symlinkFactory virtual.SymlinkFactory, characterDeviceFactory virtual.CharacterDeviceFactory, handleAllocator virtual.StatefulHandleAllocator) BuildDirectory {
+//
+// If eagerUploadSemaphore is set, output files are uploaded into the
+// Content Addressable Storage as soon as the build action closes them,
+// as opposed to uploading them after the build action completes. The
+// semaphore limits the number of files that are uploaded concurrently.
+//
+// If passthroughCacheDirectory is set, files in the input root that are
+// also present in this local cache directory are offered to the virtual
+// file system as backing files, permitting the kernel to read them
+// directly.
+//
+// If onlyUploadModifiedOutputs is set, OutputHierarchy.UploadOutputs()
+// only inspects outputs that were created by the build action through
+// the virtual file system, as opposed to calling Lstat() on every
+// location where an output is expected. Outputs that correspond to
+// unmodified files in the input root are not reported.
+//
+// If reportOperationStatistics is set, the number of LOOKUP, GETATTR,
+// READ and READDIR operations performed by the build action against
+// the virtual file system and their latencies are recorded, and
+// reported through GetResourceUsage().
+func NewVirtualBuildDirectory(directory virtual.PrepopulatedDirectory, directoryFetcher cas.DirectoryFetcher, contentAddressableStorage blobstore.BlobAccess, symlinkFactory virtual.SymlinkFactory, characterDeviceFactory virtual.CharacterDeviceFactory, handleAllocator virtual.StatefulHandleAllocator, eagerUploadSemaphore *semaphore.Weighted, passthroughCacheDirectory string, onlyUploadModifiedOutputs, reportOperationStatistics bool) BuildDirectory {
 	return &virtualBuildDirectory{
 		PrepopulatedDirectory: directory,
 		options: &virtualBuildDirectoryOptions{
@@ -46,6 +80,10 @@ func NewVirtualBuildDirectory(directory virtual.PrepopulatedDirectory, directory
 			symlinkFactory:            symlinkFactory,
 			characterDeviceFactory:    characterDeviceFactory,
 			handleAllocator:           handleAllocator,
+			eagerUploadSemaphore:      eagerUploadSemaphore,
+			passthroughCacheDirectory: passthroughCacheDirectory,
+			onlyUploadModifiedOutputs: onlyUploadModifiedOutputs,
+			reportOperationStatistics: reportOperationStatistics,
 		},
 	}
 }
@@ -62,6 +100,7 @@ func (d *virtualBuildDirectory) EnterBuildDirectory(name path.Component) (BuildD
 	return &virtualBuildDirectory{
 		PrepopulatedDirectory: directory,
 		options:               d.options,
+		operationStatistics:   d.operationStatistics,
 	}, nil
 }
 
@@ -78,23 +117,48 @@ func (d *virtualBuildDirectory) EnterUploadableDirectory(name path.Component) (U
 	return d.EnterBuildDirectory(name)
 }
 
-func (d *virtualBuildDirectory) InstallHooks(filePool re_filesystem.FilePool, errorLogger util.ErrorLogger) {
+func (d *virtualBuildDirectory) InstallHooks(ctx context.Context, digestFunction digest.Function, filePool re_filesystem.FilePool, errorLogger util.ErrorLogger) {
+	var eagerFileUploader *virtual.EagerFileUploader
+	if d.options.eagerUploadSemaphore != nil {
+		eagerFileUploader = virtual.NewEagerFileUploader(
+			ctx,
+			d.options.contentAddressableStorage,
+			digestFunction,
+			d.options.eagerUploadSemaphore)
+	}
+	var fileAllocator virtual.FileAllocator = virtual.NewPoolBackedFileAllocator(filePool, errorLogger, eagerFileUploader)
+	if d.options.reportOperationStatistics {
+		// Gather statistics for the build action that is about
+		// to run in this directory.
+		d.operationStatistics = virtual.NewOperationStatistics(clock.SystemClock)
+		fileAllocator = virtual.NewOperationStatisticsFileAllocator(fileAllocator, d.operationStatistics)
+	}
 	d.PrepopulatedDirectory.InstallHooks(
-		virtual.NewHandleAllocatingFileAllocator(
-			virtual.NewPoolBackedFileAllocator(filePool, errorLogger),
-			d.options.handleAllocator),
-		errorLogger)
+		virtual.NewHandleAllocatingFileAllocator(fileAllocator, d.options.handleAllocator),
+		errorLogger,
+		d.operationStatistics)
 }
 
 func (d *virtualBuildDirectory) MergeDirectoryContents(ctx context.Context, errorLogger util.ErrorLogger, digest digest.Digest, monitor access.UnreadDirectoryMonitor) error {
+	casFileFactory := virtual.NewBlobAccessCASFileFactory(
+		ctx,
+		d.options.contentAddressableStorage,
+		errorLogger)
+	if d.options.passthroughCacheDirectory != "" {
+		casFileFactory = virtual.NewLocalCachePassthroughCASFileFactory(
+			casFileFactory,
+			d.options.passthroughCacheDirectory)
+	}
+	if d.operationStatistics != nil {
+		casFileFactory = virtual.NewOperationStatisticsCASFileFactory(
+			casFileFactory,
+			d.operationStatistics)
+	}
 	initialContentsFetcher := virtual.NewCASInitialContentsFetcher(
 		ctx,
 		cas.NewDecomposedDirectoryWalker(d.options.directoryFetcher, digest),
 		virtual.NewStatelessHandleAllocatingCASFileFactory(
-			virtual.NewBlobAccessCASFileFactory(
-				ctx,
-				d.options.contentAddressableStorage,
-				errorLogger),
+			casFileFactory,
 			d.options.handleAllocator.New()),
 		d.options.symlinkFactory,
 		digest.GetDigestFunction())
@@ -119,6 +183,84 @@ func (d *virtualBuildDirectory) UploadFile(ctx context.Context, name path.Compon
 	return digest.BadDigest, syscall.EISDIR
 }
 
+func (d *virtualBuildDirectory) OpenRead(name path.Component) (filesystem.FileReader, error) {
+	child, err := d.LookupChild(name)
+	if err != nil {
+		return nil, err
+	}
+	_, leaf := child.GetPair()
+	if leaf == nil {
+		return nil, syscall.EISDIR
+	}
+	return virtualLeafReader{leaf: leaf}, nil
+}
+
+func (d *virtualBuildDirectory) GetFileSizeBytes(ctx context.Context, name path.Component) (int64, error) {
+	child, err := d.LookupChild(name)
+	if err != nil {
+		return 0, err
+	}
+	_, leaf := child.GetPair()
+	if leaf == nil {
+		return 0, syscall.EISDIR
+	}
+
+	var attributes virtual.Attributes
+	leaf.VirtualGetAttributes(ctx, virtual.AttributesMaskSizeBytes, &attributes)
+	sizeBytes, ok := attributes.GetSizeBytes()
+	if !ok {
+		return 0, status.Error(codes.Internal, "File did not report its size")
+	}
+	return int64(sizeBytes), nil
+}
+
+func (d *virtualBuildDirectory) GetNodeProperties(ctx context.Context, name path.Component) (*remoteexecution.NodeProperties, error) {
+	child, err := d.LookupChild(name)
+	if err != nil {
+		return nil, err
+	}
+	_, leaf := child.GetPair()
+	if leaf == nil {
+		return nil, syscall.EISDIR
+	}
+
+	var attributes virtual.Attributes
+	leaf.VirtualGetAttributes(ctx, virtual.AttributesMaskLastDataModificationTime|virtual.AttributesMaskPermissions, &attributes)
+	var nodeProperties remoteexecution.NodeProperties
+	if lastDataModificationTime, ok := attributes.GetLastDataModificationTime(); ok {
+		nodeProperties.Mtime = timestamppb.New(lastDataModificationTime)
+	}
+	if permissions, ok := attributes.GetPermissions(); ok {
+		nodeProperties.UnixMode = wrapperspb.UInt32(permissions.ToMode())
+	}
+	for _, xattrName := range leaf.VirtualListXAttrs() {
+		// Extended attributes may be removed concurrently.
+		if value, s := leaf.VirtualGetXAttr(xattrName); s == virtual.StatusOK {
+			nodeProperties.Properties = append(nodeProperties.Properties, &remoteexecution.NodeProperty{
+				Name:  xattrNodePropertyPrefix + xattrName,
+				Value: base64.StdEncoding.EncodeToString(value),
+			})
+		}
+	}
+	return &nodeProperties, nil
+}
+
+// GetResourceUsage returns statistics on the operations performed
+// against the virtual file system since InstallHooks() was called.
+func (d *virtualBuildDirectory) GetResourceUsage() (*anypb.Any, error) {
+	if d.operationStatistics == nil {
+		return nil, nil
+	}
+	return anypb.New(d.operationStatistics.GetResourceUsage())
+}
+
+func (d *virtualBuildDirectory) LookupModifiedChildren() (map[path.Component]bool, error) {
+	if !d.options.onlyUploadModifiedOutputs {
+		return nil, nil
+	}
+	return d.PrepopulatedDirectory.LookupModifiedChildren()
+}
+
 func (d *virtualBuildDirectory) Lstat(name path.Component) (filesystem.FileInfo, error) {
 	child, err := d.LookupChild(name)
 	if err != nil {
@@ -160,3 +302,40 @@ func (d *virtualBuildDirectory) Readlink(name path.Component) (string, error) {
 	}
 	return "", syscall.EISDIR
 }
+
+// virtualLeafReader is an adapter for virtual.Leaf that permits reading
+// its contents through the filesystem.FileReader interface.
+type virtualLeafReader struct {
+	leaf virtual.Leaf
+}
+
+func (r virtualLeafReader) ReadAt(p []byte, off int64) (int, error) {
+	nTotal := 0
+	for len(p) > 0 {
+		n, eof, s := r.leaf.VirtualRead(p, uint64(off))
+		if s != virtual.StatusOK {
+			return nTotal, syscall.EIO
+		}
+		nTotal += n
+		if eof && n < len(p) {
+			return nTotal, io.EOF
+		}
+		p = p[n:]
+		off += int64(n)
+	}
+	return nTotal, nil
+}
+
+func (r virtualLeafReader) GetNextRegionOffset(offset int64, regionType filesystem.RegionType) (int64, error) {
+	nextOffset, s := r.leaf.VirtualSeek(uint64(offset), regionType)
+	if s == virtual.StatusErrNXIO || (s == virtual.StatusOK && nextOffset == nil) {
+		return 0, io.EOF
+	} else if s != virtual.StatusOK {
+		return 0, syscall.EIO
+	}
+	return int64(*nextOffset), nil
+}
+
+func (r virtualLeafReader) Close() error {
+	return nil
+}
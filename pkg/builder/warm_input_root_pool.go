@@ -0,0 +1,138 @@
+package builder
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+)
+
+type warmInputRoot struct {
+	name   path.Component
+	digest digest.Digest
+}
+
+// WarmInputRootPool holds on to input roots of build actions that
+// completed previously, so that they may be reused by subsequent
+// actions. Consecutive actions tend to have largely identical input
+// roots (e.g., when building targets in the same package), meaning
+// that it is often cheaper to apply the differences between the two
+// input roots than it is to construct the new input root from
+// scratch.
+//
+// Input roots are stored in subdirectories of a directory that must
+// reside on the same file system as the build directory, so that they
+// can be moved in and out of the build directory through renaming.
+type WarmInputRootPool struct {
+	directory         filesystem.Directory
+	maximumInputRoots int
+
+	lock       sync.Mutex
+	nextID     uint64
+	inputRoots []warmInputRoot
+}
+
+// NewWarmInputRootPool creates a WarmInputRootPool that stores at most
+// maximumInputRoots input roots in a given directory. The directory is
+// expected to be empty.
+func NewWarmInputRootPool(directory filesystem.Directory, maximumInputRoots int) *WarmInputRootPool {
+	return &WarmInputRootPool{
+		directory:         directory,
+		maximumInputRoots: maximumInputRoots,
+	}
+}
+
+// take the most recently added input root out of the pool, moving its
+// contents into an empty target directory. The digest of the input
+// root is returned, so that the caller can apply the differences
+// between it and the desired input root.
+func (p *WarmInputRootPool) take(target filesystem.Directory) (digest.Digest, bool, error) {
+	p.lock.Lock()
+	if len(p.inputRoots) == 0 {
+		p.lock.Unlock()
+		return digest.BadDigest, false, nil
+	}
+	inputRoot := p.inputRoots[len(p.inputRoots)-1]
+	p.inputRoots = p.inputRoots[:len(p.inputRoots)-1]
+	p.lock.Unlock()
+
+	err := p.moveInputRoot(inputRoot.name, func(inputRootDirectory filesystem.Directory) error {
+		return moveAllChildren(inputRootDirectory, target)
+	})
+	if err2 := p.directory.RemoveAll(inputRoot.name); err == nil && err2 != nil {
+		err = util.StatusWrapf(err2, "Failed to remove input root directory %#v", inputRoot.name.String())
+	}
+	if err != nil {
+		return digest.BadDigest, false, err
+	}
+	return inputRoot.digest, true, nil
+}
+
+// moveInputRoot enters the directory of an input root stored in the
+// pool, and calls into a function to move contents in or out of it.
+func (p *WarmInputRootPool) moveInputRoot(name path.Component, move func(inputRootDirectory filesystem.Directory) error) error {
+	inputRootDirectory, err := p.directory.EnterDirectory(name)
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to enter input root directory %#v", name.String())
+	}
+	err = move(inputRootDirectory)
+	inputRootDirectory.Close()
+	return err
+}
+
+// moveAllChildren moves all files, directories and symbolic links
+// contained in one directory into another.
+func moveAllChildren(source, target filesystem.Directory) error {
+	entries, err := source.ReadDir()
+	if err != nil {
+		return util.StatusWrap(err, "Failed to read directory contents")
+	}
+	for _, entry := range entries {
+		if err := source.Rename(entry.Name(), target, entry.Name()); err != nil {
+			return util.StatusWrapf(err, "Failed to move %#v", entry.Name().String())
+		}
+	}
+	return nil
+}
+
+// add the contents of a directory corresponding to a given input root
+// digest to the pool. If the pool is full, the least recently added
+// input root is removed.
+func (p *WarmInputRootPool) add(source filesystem.Directory, inputRootDigest digest.Digest) error {
+	p.lock.Lock()
+	name := path.MustNewComponent(strconv.FormatUint(p.nextID, 10))
+	p.nextID++
+	p.lock.Unlock()
+
+	if err := p.directory.Mkdir(name, 0o777); err != nil {
+		return util.StatusWrapf(err, "Failed to create input root directory %#v", name.String())
+	}
+	if err := p.moveInputRoot(name, func(inputRootDirectory filesystem.Directory) error {
+		return moveAllChildren(source, inputRootDirectory)
+	}); err != nil {
+		p.directory.RemoveAll(name)
+		return err
+	}
+
+	p.lock.Lock()
+	p.inputRoots = append(p.inputRoots, warmInputRoot{
+		name:   name,
+		digest: inputRootDigest,
+	})
+	var evicted []warmInputRoot
+	if excess := len(p.inputRoots) - p.maximumInputRoots; excess > 0 {
+		evicted = append(evicted, p.inputRoots[:excess]...)
+		p.inputRoots = p.inputRoots[excess:]
+	}
+	p.lock.Unlock()
+
+	for _, inputRoot := range evicted {
+		if err := p.directory.RemoveAll(inputRoot.name); err != nil {
+			return util.StatusWrapf(err, "Failed to remove input root directory %#v", inputRoot.name.String())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package builder
+
+import (
+	"context"
+
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/workeradmin"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type workerAdminServer struct {
+	runners                 []*AdvertisedPlatform
+	executingActionRegistry *ExecutingActionRegistry
+}
+
+// NewWorkerAdminServer creates a gRPC server for the WorkerAdmin
+// service, which permits changing the platform properties and size
+// class that runners of a worker announce to the scheduler. It also
+// permits inspecting the actions that are currently being executed,
+// as tracked by an ExecutingActionRegistry.
+func NewWorkerAdminServer(runners []*AdvertisedPlatform, executingActionRegistry *ExecutingActionRegistry) workeradmin.WorkerAdminServer {
+	return &workerAdminServer{
+		runners:                 runners,
+		executingActionRegistry: executingActionRegistry,
+	}
+}
+
+func (s *workerAdminServer) ListRunners(ctx context.Context, request *emptypb.Empty) (*workeradmin.ListRunnersResponse, error) {
+	runners := make([]*workeradmin.RunnerState, 0, len(s.runners))
+	for runnerIndex, runner := range s.runners {
+		platform, sizeClass := runner.Get()
+		runners = append(runners, &workeradmin.RunnerState{
+			RunnerIndex: uint32(runnerIndex),
+			Platform:    platform,
+			SizeClass:   sizeClass,
+		})
+	}
+	return &workeradmin.ListRunnersResponse{
+		Runners: runners,
+	}, nil
+}
+
+func (s *workerAdminServer) SetRunnerPlatform(ctx context.Context, request *workeradmin.SetRunnerPlatformRequest) (*emptypb.Empty, error) {
+	if request.RunnerIndex >= uint32(len(s.runners)) {
+		return nil, status.Errorf(codes.NotFound, "Runner index %d is out of range, as the worker only has %d runners", request.RunnerIndex, len(s.runners))
+	}
+
+	// The scheduler rejects platform properties that are not in
+	// normal form. Reject them here, so that the worker doesn't
+	// end up being unable to synchronize.
+	platform := request.Platform
+	if platform == nil {
+		return nil, status.Error(codes.InvalidArgument, "No platform provided")
+	}
+	properties := platform.Properties
+	for i := 1; i < len(properties); i++ {
+		if properties[i-1].Name > properties[i].Name ||
+			(properties[i-1].Name == properties[i].Name &&
+				properties[i-1].Value >= properties[i].Value) {
+			marshaler := protojson.MarshalOptions{}
+			return nil, status.Errorf(codes.InvalidArgument, "Platform properties are not lexicographically sorted, as property %s should have been placed before property %s", marshaler.Format(properties[i]), marshaler.Format(properties[i-1]))
+		}
+	}
+
+	s.runners[request.RunnerIndex].Set(platform, request.SizeClass)
+	return &emptypb.Empty{}, nil
+}
+
+func (s *workerAdminServer) ListExecutingActions(ctx context.Context, request *emptypb.Empty) (*workeradmin.ListExecutingActionsResponse, error) {
+	return &workeradmin.ListExecutingActionsResponse{
+		Actions: s.executingActionRegistry.ListExecutingActions(),
+	}, nil
+}
+
+func (s *workerAdminServer) GetActionProcessTree(ctx context.Context, request *workeradmin.GetActionProcessTreeRequest) (*runner_pb.GetProcessTreeResponse, error) {
+	return s.executingActionRegistry.GetProcessTree(ctx, request.ActionId)
+}
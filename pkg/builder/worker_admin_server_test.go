@@ -0,0 +1,85 @@
+package builder_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/builder"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/workeradmin"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestWorkerAdminServer(t *testing.T) {
+	ctx := context.Background()
+
+	linuxPlatform := &remoteexecution.Platform{
+		Properties: []*remoteexecution.Platform_Property{
+			{Name: "os", Value: "linux"},
+		},
+	}
+	runner0 := builder.NewAdvertisedPlatform(linuxPlatform, 1)
+	runner1 := builder.NewAdvertisedPlatform(linuxPlatform, 2)
+	server := builder.NewWorkerAdminServer([]*builder.AdvertisedPlatform{runner0, runner1}, builder.NewExecutingActionRegistry(clock.SystemClock))
+
+	t.Run("ListRunners", func(t *testing.T) {
+		response, err := server.ListRunners(ctx, &emptypb.Empty{})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &workeradmin.ListRunnersResponse{
+			Runners: []*workeradmin.RunnerState{
+				{RunnerIndex: 0, Platform: linuxPlatform, SizeClass: 1},
+				{RunnerIndex: 1, Platform: linuxPlatform, SizeClass: 2},
+			},
+		}, response)
+	})
+
+	t.Run("RunnerIndexOutOfRange", func(t *testing.T) {
+		_, err := server.SetRunnerPlatform(ctx, &workeradmin.SetRunnerPlatformRequest{
+			RunnerIndex: 2,
+			Platform:    linuxPlatform,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Runner index 2 is out of range, as the worker only has 2 runners"), err)
+	})
+
+	t.Run("UnsortedPlatformProperties", func(t *testing.T) {
+		_, err := server.SetRunnerPlatform(ctx, &workeradmin.SetRunnerPlatformRequest{
+			RunnerIndex: 1,
+			Platform: &remoteexecution.Platform{
+				Properties: []*remoteexecution.Platform_Property{
+					{Name: "os", Value: "linux"},
+					{Name: "cpu", Value: "aarch64"},
+				},
+			},
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		platform, sizeClass := runner1.Get()
+		testutil.RequireEqualProto(t, linuxPlatform, platform)
+		require.Equal(t, uint32(2), sizeClass)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		aarch64Platform := &remoteexecution.Platform{
+			Properties: []*remoteexecution.Platform_Property{
+				{Name: "cpu", Value: "aarch64"},
+				{Name: "os", Value: "linux"},
+			},
+		}
+		_, err := server.SetRunnerPlatform(ctx, &workeradmin.SetRunnerPlatformRequest{
+			RunnerIndex: 1,
+			Platform:    aarch64Platform,
+			SizeClass:   4,
+		})
+		require.NoError(t, err)
+
+		platform, sizeClass := runner1.Get()
+		testutil.RequireEqualProto(t, aarch64Platform, platform)
+		require.Equal(t, uint32(4), sizeClass)
+	})
+}
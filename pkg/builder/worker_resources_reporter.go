@@ -0,0 +1,14 @@
+package builder
+
+import (
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+)
+
+// WorkerResourcesReporter is called into by BuildClient to obtain the
+// amount of resources that are available to the worker. These are
+// reported to the scheduler, so that it can refrain from assigning
+// tasks to workers that are constrained (e.g., low on disk space due to
+// other workloads running on the same system).
+type WorkerResourcesReporter interface {
+	GetWorkerResources() (*remoteworker.WorkerResources, error)
+}
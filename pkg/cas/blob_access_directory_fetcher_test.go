@@ -18,6 +18,7 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestBlobAccessDirectoryFetcherGetDirectory(t *testing.T) {
@@ -72,6 +73,66 @@ func TestBlobAccessDirectoryFetcherGetDirectory(t *testing.T) {
 	})
 }
 
+func TestBlobAccessDirectoryFetcherGetDirectoryDigestFunctions(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	blobAccess := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := cas.NewBlobAccessDirectoryFetcher(blobAccess, 1000, 10000)
+
+	// Directory objects that are part of the input root should be
+	// validated using the digest function of the action.
+	for _, tc := range []struct {
+		fileDigest          digest.Digest
+		corruptedHashString string
+	}{
+		{
+			fileDigest:          digest.MustNewDigest("example", remoteexecution.DigestFunction_SHA256TREE, "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969", 5),
+			corruptedHashString: "bb6d3e71bb9087f0b47f1890d0df2e51d67355f0b8c9339be21d6aaaf054e1c7",
+		},
+		{
+			fileDigest:          digest.MustNewDigest("example", remoteexecution.DigestFunction_BLAKE3, "fbc2b0516ee8744d293b980779178a3508850fdcfe965985782c39601b65794f", 5),
+			corruptedHashString: "49ff19d1be8f111ca9e28874ec63779d85a50ad9a661c7c929043d424fbd21ee",
+		},
+	} {
+		fileDigest := tc.fileDigest
+		digestFunction := fileDigest.GetDigestFunction()
+		t.Run(digestFunction.GetEnumValue().String(), func(t *testing.T) {
+			exampleDirectory := &remoteexecution.Directory{
+				Files: []*remoteexecution.FileNode{
+					{
+						Name:   "hello.txt",
+						Digest: fileDigest.GetProto(),
+					},
+				},
+			}
+			data, err := proto.Marshal(exampleDirectory)
+			require.NoError(t, err)
+			generator := digestFunction.NewGenerator(int64(len(data)))
+			generator.Write(data)
+			directoryDigest := generator.Sum()
+
+			t.Run("Success", func(t *testing.T) {
+				blobAccess.EXPECT().Get(ctx, directoryDigest).Return(
+					buffer.NewCASBufferFromReader(directoryDigest, io.NopCloser(bytes.NewBuffer(data)), buffer.UserProvided))
+
+				directory, err := directoryFetcher.GetDirectory(ctx, directoryDigest)
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, exampleDirectory, directory)
+			})
+
+			t.Run("ChecksumMismatch", func(t *testing.T) {
+				corruptedData := append([]byte(nil), data...)
+				corruptedData[len(corruptedData)-1] ^= 1
+				blobAccess.EXPECT().Get(ctx, directoryDigest).Return(
+					buffer.NewCASBufferFromReader(directoryDigest, io.NopCloser(bytes.NewBuffer(corruptedData)), buffer.UserProvided))
+
+				_, err := directoryFetcher.GetDirectory(ctx, directoryDigest)
+				testutil.RequireEqualStatus(t, status.Errorf(codes.InvalidArgument, "Buffer has checksum %s, while %s was expected", tc.corruptedHashString, directoryDigest.GetHashString()), err)
+			})
+		})
+	}
+}
+
 func TestBlobAccessDirectoryFetcherGetTreeRootDirectory(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
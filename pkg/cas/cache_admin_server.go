@@ -0,0 +1,83 @@
+package cas
+
+import (
+	"context"
+	"sort"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/cacheadmin"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type cacheAdminServer struct {
+	caches     map[string]CachingFileFetcher
+	cacheNames []string
+}
+
+// NewCacheAdminServer creates a gRPC server for the CacheAdmin service,
+// which permits inspecting and flushing the contents of a set of
+// caches of input files, identified by name.
+func NewCacheAdminServer(caches map[string]CachingFileFetcher) cacheadmin.CacheAdminServer {
+	cacheNames := make([]string, 0, len(caches))
+	for cacheName := range caches {
+		cacheNames = append(cacheNames, cacheName)
+	}
+	sort.Strings(cacheNames)
+	return &cacheAdminServer{
+		caches:     caches,
+		cacheNames: cacheNames,
+	}
+}
+
+func (s *cacheAdminServer) GetCacheStatistics(ctx context.Context, request *cacheadmin.GetCacheStatisticsRequest) (*cacheadmin.GetCacheStatisticsResponse, error) {
+	caches := make([]*cacheadmin.CacheStatistics, 0, len(s.cacheNames))
+	for _, cacheName := range s.cacheNames {
+		statistics := s.caches[cacheName].GetStatistics(int(request.MaximumMostUsedEntries))
+		statistics.Name = cacheName
+		caches = append(caches, statistics)
+	}
+	return &cacheadmin.GetCacheStatisticsResponse{
+		Caches: caches,
+	}, nil
+}
+
+func (s *cacheAdminServer) FlushCache(ctx context.Context, request *cacheadmin.FlushCacheRequest) (*emptypb.Empty, error) {
+	cacheNames := s.cacheNames
+	if request.CacheName != "" {
+		if _, ok := s.caches[request.CacheName]; !ok {
+			return nil, status.Errorf(codes.NotFound, "Cache %#v does not exist", request.CacheName)
+		}
+		cacheNames = []string{request.CacheName}
+	}
+
+	blobDigests := make([]digest.Digest, 0, len(request.Digests))
+	for i, blobDigest := range request.Digests {
+		digestFunction, err := digest.EmptyInstanceName.GetDigestFunction(request.DigestFunction, len(blobDigest.GetHash()))
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Invalid digest function for digest at index %d", i)
+		}
+		parsedDigest, err := digestFunction.NewDigestFromProto(blobDigest)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Invalid digest at index %d", i)
+		}
+		blobDigests = append(blobDigests, parsedDigest)
+	}
+
+	for _, cacheName := range cacheNames {
+		cache := s.caches[cacheName]
+		var err error
+		if len(blobDigests) == 0 {
+			err = cache.FlushAll()
+		} else {
+			err = cache.FlushFiles(blobDigests)
+		}
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Failed to flush cache %#v", cacheName)
+		}
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,130 @@
+package cas_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/cacheadmin"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCacheAdminServer(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	cache1 := mock.NewMockCachingFileFetcher(ctrl)
+	cache2 := mock.NewMockCachingFileFetcher(ctrl)
+	server := cas.NewCacheAdminServer(map[string]cas.CachingFileFetcher{
+		"/worker/cache2": cache2,
+		"/worker/cache1": cache1,
+	})
+
+	t.Run("GetCacheStatistics", func(t *testing.T) {
+		// Statistics should be returned in sorted order, with
+		// the names of the caches filled in.
+		cache1.EXPECT().GetStatistics(10).Return(&cacheadmin.CacheStatistics{
+			FileCount: 1,
+			SizeBytes: 5,
+			Hits:      3,
+			MostUsedEntries: []*cacheadmin.CacheEntry{
+				{
+					Digest: &remoteexecution.Digest{
+						Hash:      "8b1a9953c4611296a827abf8c47804d7",
+						SizeBytes: 5,
+					},
+					DigestFunction: remoteexecution.DigestFunction_MD5,
+					Hits:           3,
+				},
+			},
+		})
+		cache2.EXPECT().GetStatistics(10).Return(&cacheadmin.CacheStatistics{
+			Misses: 7,
+		})
+
+		response, err := server.GetCacheStatistics(ctx, &cacheadmin.GetCacheStatisticsRequest{
+			MaximumMostUsedEntries: 10,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &cacheadmin.GetCacheStatisticsResponse{
+			Caches: []*cacheadmin.CacheStatistics{
+				{
+					Name:      "/worker/cache1",
+					FileCount: 1,
+					SizeBytes: 5,
+					Hits:      3,
+					MostUsedEntries: []*cacheadmin.CacheEntry{
+						{
+							Digest: &remoteexecution.Digest{
+								Hash:      "8b1a9953c4611296a827abf8c47804d7",
+								SizeBytes: 5,
+							},
+							DigestFunction: remoteexecution.DigestFunction_MD5,
+							Hits:           3,
+						},
+					},
+				},
+				{
+					Name:   "/worker/cache2",
+					Misses: 7,
+				},
+			},
+		}, response)
+	})
+
+	t.Run("FlushNonexistentCache", func(t *testing.T) {
+		_, err := server.FlushCache(ctx, &cacheadmin.FlushCacheRequest{
+			CacheName: "/worker/cache3",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Cache \"/worker/cache3\" does not exist"), err)
+	})
+
+	t.Run("FlushInvalidDigest", func(t *testing.T) {
+		_, err := server.FlushCache(ctx, &cacheadmin.FlushCacheRequest{
+			DigestFunction: remoteexecution.DigestFunction_MD5,
+			Digests: []*remoteexecution.Digest{
+				{
+					Hash:      "8b1a9953c4611296a827abf8c47804d7",
+					SizeBytes: -1,
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid digest at index 0: Invalid digest size: -1 bytes"), err)
+	})
+
+	t.Run("FlushFiles", func(t *testing.T) {
+		cache2.EXPECT().FlushFiles([]digest.Digest{
+			digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5),
+		})
+
+		_, err := server.FlushCache(ctx, &cacheadmin.FlushCacheRequest{
+			CacheName:      "/worker/cache2",
+			DigestFunction: remoteexecution.DigestFunction_MD5,
+			Digests: []*remoteexecution.Digest{
+				{
+					Hash:      "8b1a9953c4611296a827abf8c47804d7",
+					SizeBytes: 5,
+				},
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("FlushAll", func(t *testing.T) {
+		// If no cache name and digests are provided, all caches
+		// should be flushed entirely.
+		cache1.EXPECT().FlushAll()
+		cache2.EXPECT().FlushAll().Return(status.Error(codes.Internal, "Failed to remove cached file \"3-8b1a9953c4611296a827abf8c47804d7-5-x\": "+syscall.EIO.Error()))
+
+		_, err := server.FlushCache(ctx, &cacheadmin.FlushCacheRequest{})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to flush cache \"/worker/cache2\": Failed to remove cached file \"3-8b1a9953c4611296a827abf8c47804d7-5-x\": input/output error"), err)
+	})
+}
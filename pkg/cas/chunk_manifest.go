@@ -0,0 +1,216 @@
+package cas
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// chunkManifestSalt is stored in the Action messages that are used to
+// key chunk manifests in the Action Cache. This prevents these entries
+// from colliding with the results of actual build actions.
+var chunkManifestSalt = []byte("bb-remote-execution chunk manifest")
+
+// chunkManifest describes how a large blob stored in the Content
+// Addressable Storage can be reconstructed by concatenating a sequence
+// of smaller blobs.
+//
+// Chunk manifests are stored in the Action Cache in the form of an
+// ActionResult, having an output file for every chunk. The path of
+// each output file is the offset of the chunk within the blob. As
+// ActionResults reference the chunks, implementations of the Action
+// Cache that check for completeness discard manifests whose chunks
+// are no longer present.
+type chunkManifest struct {
+	offsetsBytes []int64
+	digests      []digest.Digest
+}
+
+// getChunkManifestActionDigest returns the digest of the Action
+// message under which the chunk manifest of a blob is stored in the
+// Action Cache.
+func getChunkManifestActionDigest(blobDigest digest.Digest) (digest.Digest, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(&remoteexecution.Action{
+		CommandDigest: blobDigest.GetProto(),
+		Salt:          chunkManifestSalt,
+	})
+	if err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to marshal chunk manifest action")
+	}
+	digestGenerator := blobDigest.GetDigestFunction().NewGenerator(int64(len(data)))
+	if _, err := digestGenerator.Write(data); err != nil {
+		panic(err)
+	}
+	return digestGenerator.Sum(), nil
+}
+
+// newChunkManifestFromActionResult converts an ActionResult obtained
+// from the Action Cache to a chunk manifest, validating that the chunks
+// are contiguous and add up to the size of the blob.
+func newChunkManifestFromActionResult(actionResult *remoteexecution.ActionResult, blobDigest digest.Digest) (*chunkManifest, error) {
+	digestFunction := blobDigest.GetDigestFunction()
+	manifest := &chunkManifest{
+		offsetsBytes: make([]int64, 0, len(actionResult.OutputFiles)),
+		digests:      make([]digest.Digest, 0, len(actionResult.OutputFiles)),
+	}
+	expectedOffsetBytes := int64(0)
+	for i, outputFile := range actionResult.OutputFiles {
+		offsetBytes, err := strconv.ParseInt(outputFile.Path, 10, 64)
+		if err != nil {
+			return nil, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid offset for chunk at index %d", i)
+		}
+		if offsetBytes != expectedOffsetBytes {
+			return nil, status.Errorf(codes.InvalidArgument, "Chunk at index %d has offset %d, while %d was expected", i, offsetBytes, expectedOffsetBytes)
+		}
+		chunkDigest, err := digestFunction.NewDigestFromProto(outputFile.Digest)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Invalid digest for chunk at index %d", i)
+		}
+		if chunkDigest.GetSizeBytes() == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "Chunk at index %d is empty", i)
+		}
+		manifest.offsetsBytes = append(manifest.offsetsBytes, offsetBytes)
+		manifest.digests = append(manifest.digests, chunkDigest)
+		expectedOffsetBytes += chunkDigest.GetSizeBytes()
+	}
+	if expectedOffsetBytes != blobDigest.GetSizeBytes() {
+		return nil, status.Errorf(codes.InvalidArgument, "Chunks have a total size of %d bytes, while the blob is %d bytes in size", expectedOffsetBytes, blobDigest.GetSizeBytes())
+	}
+	return manifest, nil
+}
+
+// getChunkIndex returns the index of the chunk that contains the byte
+// at a given offset.
+func (m *chunkManifest) getChunkIndex(offsetBytes int64) int {
+	return sort.Search(len(m.offsetsBytes), func(i int) bool {
+		return m.offsetsBytes[i] > offsetBytes
+	}) - 1
+}
+
+// ChunkedBlobUploader contains the options that need to be provided
+// to UploadDirectory() to let it store large files in chunked form.
+// Chunked files can be read efficiently by workers that use
+// ChunkedBlobAccess, as these only need to download the chunks they
+// don't already have, as opposed to the full file.
+type ChunkedBlobUploader struct {
+	// The Action Cache in which chunk manifests are stored.
+	ActionCache blobstore.BlobAccess
+	// The chunker that is used to determine chunk boundaries. To
+	// maximize the amount of deduplication between versions of a
+	// file, it is advised to keep its parameters constant.
+	Chunker *ContentDefinedChunker
+	// Files smaller than this size are not chunked.
+	MinimumBlobSizeBytes int64
+	// The maximum size of ActionResult messages to read from the
+	// Action Cache.
+	MaximumMessageSizeBytes int
+}
+
+// chunkUploadBatchSizeBytes is the amount of chunk data that
+// ChunkedBlobUploader keeps in memory, before checking for their
+// existence in the Content Addressable Storage and uploading them.
+const chunkUploadBatchSizeBytes = 64 * 1024 * 1024
+
+// chunkUploadBatch contains the chunks for which ChunkedBlobUploader
+// has not yet determined whether they need to be uploaded.
+type chunkUploadBatch struct {
+	context                   context.Context
+	contentAddressableStorage blobstore.BlobAccess
+	chunks                    map[digest.Digest][]byte
+	sizeBytes                 int
+}
+
+func (b *chunkUploadBatch) add(chunkDigest digest.Digest, data []byte) error {
+	if _, ok := b.chunks[chunkDigest]; !ok {
+		b.chunks[chunkDigest] = append([]byte(nil), data...)
+		b.sizeBytes += len(data)
+		if b.sizeBytes >= chunkUploadBatchSizeBytes {
+			return b.flush()
+		}
+	}
+	return nil
+}
+
+func (b *chunkUploadBatch) flush() error {
+	if len(b.chunks) == 0 {
+		return nil
+	}
+	digests := digest.NewSetBuilder()
+	for chunkDigest := range b.chunks {
+		digests.Add(chunkDigest)
+	}
+	missing, err := b.contentAddressableStorage.FindMissing(b.context, digests.Build())
+	if err != nil {
+		return util.StatusWrap(err, "Failed to determine existence of chunks")
+	}
+	for _, chunkDigest := range missing.Items() {
+		if err := b.contentAddressableStorage.Put(b.context, chunkDigest, buffer.NewValidatedBufferFromByteSlice(b.chunks[chunkDigest])); err != nil {
+			return util.StatusWrapf(err, "Failed to upload chunk %#v", chunkDigest.String())
+		}
+	}
+	b.chunks = map[digest.Digest][]byte{}
+	b.sizeBytes = 0
+	return nil
+}
+
+// uploadIfMissing stores a blob in chunked form, if no chunk manifest
+// exists for it yet. The function that is provided is called to obtain
+// the contents of the blob.
+func (u *ChunkedBlobUploader) uploadIfMissing(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, blobDigest digest.Digest, split func(f func(chunk []byte) error) error) error {
+	actionDigest, err := getChunkManifestActionDigest(blobDigest)
+	if err != nil {
+		return err
+	}
+	if _, err := u.ActionCache.Get(ctx, actionDigest).ToProto(&remoteexecution.ActionResult{}, u.MaximumMessageSizeBytes); err == nil {
+		return nil
+	} else if status.Code(err) != codes.NotFound {
+		return util.StatusWrap(err, "Failed to obtain existing chunk manifest")
+	}
+
+	digestFunction := blobDigest.GetDigestFunction()
+	batch := chunkUploadBatch{
+		context:                   ctx,
+		contentAddressableStorage: contentAddressableStorage,
+		chunks:                    map[digest.Digest][]byte{},
+	}
+	var actionResult remoteexecution.ActionResult
+	offsetBytes := int64(0)
+	if err := split(func(chunk []byte) error {
+		digestGenerator := digestFunction.NewGenerator(int64(len(chunk)))
+		if _, err := digestGenerator.Write(chunk); err != nil {
+			panic(err)
+		}
+		chunkDigest := digestGenerator.Sum()
+		actionResult.OutputFiles = append(actionResult.OutputFiles, &remoteexecution.OutputFile{
+			Path:   strconv.FormatInt(offsetBytes, 10),
+			Digest: chunkDigest.GetProto(),
+		})
+		offsetBytes += int64(len(chunk))
+		return batch.add(chunkDigest, chunk)
+	}); err != nil {
+		return err
+	}
+	if offsetBytes != blobDigest.GetSizeBytes() {
+		return status.Errorf(codes.InvalidArgument, "Blob is %d bytes in size, while %d bytes were expected", offsetBytes, blobDigest.GetSizeBytes())
+	}
+	if err := batch.flush(); err != nil {
+		return err
+	}
+
+	// Only store the manifest after all chunks have been uploaded,
+	// so that readers never observe incomplete manifests.
+	if err := u.ActionCache.Put(ctx, actionDigest, buffer.NewProtoBufferFromProto(&actionResult, buffer.UserProvided)); err != nil {
+		return util.StatusWrap(err, "Failed to store chunk manifest")
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package cas
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/eviction"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ChunkedBlobAccessEvictionSet is the eviction set type that is
+// accepted by NewChunkedBlobAccess().
+type ChunkedBlobAccessEvictionSet = eviction.Set[string]
+
+type chunkedBlobAccess struct {
+	blobstore.BlobAccess
+	actionCache             blobstore.BlobAccess
+	digestKeyFormat         digest.KeyFormat
+	minimumBlobSizeBytes    int64
+	maximumMessageSizeBytes int
+	maximumCachedManifests  int
+
+	lock        sync.Mutex
+	manifests   map[string]*chunkManifest
+	evictionSet ChunkedBlobAccessEvictionSet
+}
+
+// NewChunkedBlobAccess creates a decorator for a Content Addressable
+// Storage that reads large blobs by fetching the chunks listed in
+// their chunk manifest, as opposed to fetching the blob as a whole.
+// Chunk manifests are created by UploadDirectory() when provided a
+// ChunkedBlobUploader.
+//
+// When combined with a caching backend, this allows workers to reuse
+// chunks that are shared between different versions of large files
+// (e.g., SDKs, container images), and to only fetch the parts of a
+// file that are actually read.
+//
+// Chunk manifests are cached in memory, including the absence of
+// manifests. Blobs for which no manifest exists are read from the
+// backend as usual. The contents of individual chunks are validated,
+// but the blob as a whole is not. The Action Cache is thus trusted to
+// only contain valid manifests.
+func NewChunkedBlobAccess(base, actionCache blobstore.BlobAccess, digestKeyFormat digest.KeyFormat, minimumBlobSizeBytes int64, maximumMessageSizeBytes, maximumCachedManifests int, evictionSet ChunkedBlobAccessEvictionSet) blobstore.BlobAccess {
+	return &chunkedBlobAccess{
+		BlobAccess:              base,
+		actionCache:             actionCache,
+		digestKeyFormat:         digestKeyFormat,
+		minimumBlobSizeBytes:    minimumBlobSizeBytes,
+		maximumMessageSizeBytes: maximumMessageSizeBytes,
+		maximumCachedManifests:  maximumCachedManifests,
+
+		manifests:   map[string]*chunkManifest{},
+		evictionSet: evictionSet,
+	}
+}
+
+func (ba *chunkedBlobAccess) lookup(key string) (*chunkManifest, bool) {
+	ba.lock.Lock()
+	defer ba.lock.Unlock()
+
+	if manifest, ok := ba.manifests[key]; ok {
+		ba.evictionSet.Touch(key)
+		return manifest, true
+	}
+	return nil, false
+}
+
+func (ba *chunkedBlobAccess) insert(key string, manifest *chunkManifest) {
+	ba.lock.Lock()
+	defer ba.lock.Unlock()
+
+	if _, ok := ba.manifests[key]; !ok {
+		// Make space if needed.
+		for len(ba.manifests) > 0 && len(ba.manifests) >= ba.maximumCachedManifests {
+			key := ba.evictionSet.Peek()
+			ba.evictionSet.Remove()
+			delete(ba.manifests, key)
+		}
+
+		ba.evictionSet.Insert(key)
+		ba.manifests[key] = manifest
+	}
+}
+
+// getManifest returns the chunk manifest of a blob. A nil manifest is
+// returned if the blob is not stored in chunked form.
+func (ba *chunkedBlobAccess) getManifest(ctx context.Context, blobDigest digest.Digest) (*chunkManifest, error) {
+	key := blobDigest.GetKey(ba.digestKeyFormat)
+	if manifest, ok := ba.lookup(key); ok {
+		return manifest, nil
+	}
+
+	actionDigest, err := getChunkManifestActionDigest(blobDigest)
+	if err != nil {
+		return nil, err
+	}
+	actionResult, err := ba.actionCache.Get(ctx, actionDigest).ToProto(&remoteexecution.ActionResult{}, ba.maximumMessageSizeBytes)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			ba.insert(key, nil)
+			return nil, nil
+		}
+		return nil, util.StatusWrap(err, "Failed to obtain chunk manifest")
+	}
+	manifest, err := newChunkManifestFromActionResult(actionResult.(*remoteexecution.ActionResult), blobDigest)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Invalid chunk manifest")
+	}
+	ba.insert(key, manifest)
+	return manifest, nil
+}
+
+func (ba *chunkedBlobAccess) Get(ctx context.Context, blobDigest digest.Digest) buffer.Buffer {
+	sizeBytes := blobDigest.GetSizeBytes()
+	if sizeBytes < ba.minimumBlobSizeBytes {
+		return ba.BlobAccess.Get(ctx, blobDigest)
+	}
+
+	// Fall back to reading the blob as a whole if no manifest
+	// exists, or if it cannot be loaded. It is better to read the
+	// blob slowly than to fail.
+	manifest, err := ba.getManifest(ctx, blobDigest)
+	if err != nil || manifest == nil {
+		return ba.BlobAccess.Get(ctx, blobDigest)
+	}
+	return buffer.NewValidatedBufferFromReaderAt(
+		&chunkedBlobReader{
+			context:    ctx,
+			blobAccess: ba.BlobAccess,
+			manifest:   manifest,
+			sizeBytes:  sizeBytes,
+		},
+		sizeBytes)
+}
+
+// chunkedBlobReader is a ReadAtCloser that reads data from a blob by
+// only fetching the chunks that overlap with the requested region.
+type chunkedBlobReader struct {
+	context    context.Context
+	blobAccess blobstore.BlobAccess
+	manifest   *chunkManifest
+	sizeBytes  int64
+}
+
+func (r *chunkedBlobReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "Negative read offset: %d", off)
+	}
+	nTotal := 0
+	for i := r.manifest.getChunkIndex(off); len(p) > 0 && off < r.sizeBytes; i++ {
+		chunkOffsetBytes := r.manifest.offsetsBytes[i]
+		chunkDigest := r.manifest.digests[i]
+		n, err := r.blobAccess.Get(r.context, chunkDigest).ReadAt(p, off-chunkOffsetBytes)
+		nTotal += n
+		p = p[n:]
+		off += int64(n)
+		if err != nil && err != io.EOF {
+			return nTotal, util.StatusWrapf(err, "Failed to read chunk %#v", chunkDigest.String())
+		}
+	}
+	if len(p) > 0 {
+		return nTotal, io.EOF
+	}
+	return nTotal, nil
+}
+
+func (r *chunkedBlobReader) Close() error {
+	return nil
+}
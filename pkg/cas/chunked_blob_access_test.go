@@ -0,0 +1,134 @@
+package cas_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/eviction"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestChunkedBlobAccess(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	actionCache := mock.NewMockBlobAccess(ctrl)
+	blobAccess := cas.NewChunkedBlobAccess(
+		baseContentAddressableStorage,
+		actionCache,
+		digest.KeyWithInstance,
+		/* minimumBlobSizeBytes = */ 10,
+		/* maximumMessageSizeBytes = */ 10000,
+		/* maximumCachedManifests = */ 10,
+		eviction.NewLRUSet[string]())
+
+	t.Run("SmallBlob", func(t *testing.T) {
+		// Blobs below the minimum size should be read directly,
+		// without consulting the Action Cache.
+		blobDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+		baseContentAddressableStorage.EXPECT().Get(ctx, blobDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello")))
+
+		data, err := blobAccess.Get(ctx, blobDigest).ToByteSlice(100)
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hello"), data)
+	})
+
+	t.Run("NoManifest", func(t *testing.T) {
+		// Large blobs without a manifest should be read as a
+		// whole. The absence of the manifest should be cached.
+		blobDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "6cd3556deb0da54bca060b4c39479839", 13)
+		actionCache.EXPECT().Get(ctx, gomock.Any()).
+			Return(buffer.NewBufferFromError(status.Error(codes.NotFound, "Object not found")))
+		baseContentAddressableStorage.EXPECT().Get(ctx, blobDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello, world!"))).
+			Times(2)
+
+		for i := 0; i < 2; i++ {
+			data, err := blobAccess.Get(ctx, blobDigest).ToByteSlice(100)
+			require.NoError(t, err)
+			require.Equal(t, []byte("Hello, world!"), data)
+		}
+	})
+
+	t.Run("ManifestFailure", func(t *testing.T) {
+		// Failures reading the manifest should cause the blob
+		// to be read as a whole.
+		blobDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "ed076287532e86365e841e92bfc50d8c", 12)
+		actionCache.EXPECT().Get(ctx, gomock.Any()).
+			Return(buffer.NewBufferFromError(status.Error(codes.Unavailable, "Server offline")))
+		baseContentAddressableStorage.EXPECT().Get(ctx, blobDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello World!")))
+
+		data, err := blobAccess.Get(ctx, blobDigest).ToByteSlice(100)
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hello World!"), data)
+	})
+
+	t.Run("Chunked", func(t *testing.T) {
+		blobDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "26723c0d6e52bf7a2e1e3fa1ab27004b", 15)
+		chunk1Digest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "6f5902ac237024bdd0c176cb93063dc4", 6)
+		chunk2Digest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "cc2b8ab4a67c2b13a6b1a5e4d5a1a1a1", 4)
+		chunk3Digest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "0e8bd5b8d1d0e58c6a7f36b4e26e4cd1", 5)
+		actionCache.EXPECT().Get(ctx, gomock.Any()).
+			Return(buffer.NewProtoBufferFromProto(&remoteexecution.ActionResult{
+				OutputFiles: []*remoteexecution.OutputFile{
+					{Path: "0", Digest: chunk1Digest.GetProto()},
+					{Path: "6", Digest: chunk2Digest.GetProto()},
+					{Path: "10", Digest: chunk3Digest.GetProto()},
+				},
+			}, buffer.UserProvided))
+
+		// Reads should only fetch the chunks that overlap with
+		// the requested region.
+		baseContentAddressableStorage.EXPECT().Get(ctx, chunk1Digest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello ")))
+		baseContentAddressableStorage.EXPECT().Get(ctx, chunk2Digest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("chun")))
+
+		var p [6]byte
+		n, err := blobAccess.Get(ctx, blobDigest).ReadAt(p[:], 3)
+		require.NoError(t, err)
+		require.Equal(t, 6, n)
+		require.Equal(t, []byte("lo chu"), p[:])
+
+		// Reading past the end of the blob should yield EOF.
+		// The manifest should be cached at this point.
+		baseContentAddressableStorage.EXPECT().Get(ctx, chunk3Digest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("ked!\n")))
+
+		n, err = blobAccess.Get(ctx, blobDigest).ReadAt(p[:], 12)
+		require.Equal(t, 3, n)
+		require.Equal(t, []byte("d!\n"), p[:3])
+		require.Equal(t, io.EOF, err)
+	})
+
+	t.Run("InvalidManifest", func(t *testing.T) {
+		// Manifests whose chunks don't add up to the size of
+		// the blob should be ignored.
+		blobDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "9e107d9d372bb6826bd81d3542a419d6", 43)
+		chunkDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "6f5902ac237024bdd0c176cb93063dc4", 6)
+		actionCache.EXPECT().Get(ctx, gomock.Any()).
+			Return(buffer.NewProtoBufferFromProto(&remoteexecution.ActionResult{
+				OutputFiles: []*remoteexecution.OutputFile{
+					{Path: "0", Digest: chunkDigest.GetProto()},
+				},
+			}, buffer.UserProvided))
+		baseContentAddressableStorage.EXPECT().Get(ctx, blobDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("The quick brown fox jumps over the lazy dog")))
+
+		data, err := blobAccess.Get(ctx, blobDigest).ToByteSlice(100)
+		require.NoError(t, err)
+		require.Equal(t, []byte("The quick brown fox jumps over the lazy dog"), data)
+	})
+}
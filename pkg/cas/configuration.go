@@ -2,6 +2,7 @@ package cas
 
 import (
 	pb "github.com/buildbarn/bb-remote-execution/pkg/proto/configuration/cas"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/eviction"
 )
@@ -27,3 +28,26 @@ func NewCachingDirectoryFetcherFromConfiguration(configuration *pb.CachingDirect
 		eviction.NewMetricsSet(evictionSet, "CachingDirectoryFetcher"),
 	), nil
 }
+
+// NewChunkedBlobAccessFromConfiguration creates a new ChunkedBlobAccess
+// based on parameters provided in a configuration file.
+func NewChunkedBlobAccessFromConfiguration(configuration *pb.ChunkedBlobAccessConfiguration, base, actionCache blobstore.BlobAccess, maximumMessageSizeBytes int) (blobstore.BlobAccess, error) {
+	if configuration == nil {
+		// No configuration provided. Read blobs as a whole.
+		return base, nil
+	}
+
+	evictionSet, err := eviction.NewSetFromConfiguration[string](configuration.CacheReplacementPolicy)
+	if err != nil {
+		return nil, err
+	}
+	return NewChunkedBlobAccess(
+		base,
+		actionCache,
+		digest.KeyWithInstance,
+		configuration.MinimumBlobSizeBytes,
+		maximumMessageSizeBytes,
+		int(configuration.MaximumCachedManifests),
+		eviction.NewMetricsSet(evictionSet, "ChunkedBlobAccess"),
+	), nil
+}
@@ -0,0 +1,108 @@
+package cas
+
+import (
+	"io"
+	"math/bits"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// gearTable contains the random values that are used by
+// ContentDefinedChunker to compute a rolling hash. The values are
+// generated using SplitMix64 with a fixed seed, so that chunk
+// boundaries are stable across releases and between the tools that
+// produce and consume chunked blobs.
+var gearTable = func() (table [256]uint64) {
+	state := uint64(0)
+	for i := range table {
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		table[i] = z ^ (z >> 31)
+	}
+	return
+}()
+
+// ContentDefinedChunker splits blobs into chunks whose boundaries are
+// determined by the contents of the blob, as opposed to their offset.
+// This means that inserting or removing data in the middle of a blob
+// only causes the chunks surrounding the modification to change.
+//
+// Boundaries are determined using a Gear hash, similar to FastCDC. A
+// boundary is placed at every position where the most significant bits
+// of the hash are zero, while respecting the minimum and maximum chunk
+// size.
+type ContentDefinedChunker struct {
+	minimumSizeBytes int
+	maximumSizeBytes int
+	mask             uint64
+}
+
+// NewContentDefinedChunker creates a ContentDefinedChunker that yields
+// chunks of a given minimum, average and maximum size. The average size
+// needs to be a power of two.
+func NewContentDefinedChunker(minimumSizeBytes, averageSizeBytes, maximumSizeBytes int) (*ContentDefinedChunker, error) {
+	if minimumSizeBytes <= 0 || minimumSizeBytes > averageSizeBytes || averageSizeBytes > maximumSizeBytes {
+		return nil, status.Error(codes.InvalidArgument, "Chunk sizes must be positive, and satisfy minimum <= average <= maximum")
+	}
+	if bits.OnesCount(uint(averageSizeBytes)) != 1 {
+		return nil, status.Error(codes.InvalidArgument, "Average chunk size must be a power of two")
+	}
+	maskBits := bits.TrailingZeros(uint(averageSizeBytes))
+	return &ContentDefinedChunker{
+		minimumSizeBytes: minimumSizeBytes,
+		maximumSizeBytes: maximumSizeBytes,
+		mask:             ^uint64(0) << (64 - maskBits),
+	}, nil
+}
+
+// findBoundary returns the size of the first chunk contained in a
+// buffer. The buffer must either contain at least the maximum chunk
+// size worth of data, or the remainder of the blob.
+func (c *ContentDefinedChunker) findBoundary(data []byte) int {
+	if len(data) <= c.minimumSizeBytes {
+		return len(data)
+	}
+	limit := len(data)
+	if limit > c.maximumSizeBytes {
+		limit = c.maximumSizeBytes
+	}
+	h := uint64(0)
+	for i := c.minimumSizeBytes; i < limit; i++ {
+		h = (h << 1) + gearTable[data[i]]
+		if h&c.mask == 0 {
+			return i + 1
+		}
+	}
+	return limit
+}
+
+// Split the data returned by a reader into chunks. The callback is
+// invoked for every chunk in order. The slice passed to the callback is
+// only valid for the duration of the call.
+func (c *ContentDefinedChunker) Split(r io.Reader, f func(chunk []byte) error) error {
+	buf := make([]byte, c.maximumSizeBytes)
+	n := 0
+	eof := false
+	for {
+		if !eof {
+			m, err := io.ReadFull(r, buf[n:])
+			n += m
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				eof = true
+			} else if err != nil {
+				return err
+			}
+		}
+		if n == 0 {
+			return nil
+		}
+		boundary := c.findBoundary(buf[:n])
+		if err := f(buf[:boundary]); err != nil {
+			return err
+		}
+		n = copy(buf, buf[boundary:n])
+	}
+}
@@ -0,0 +1,71 @@
+package cas_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestContentDefinedChunker(t *testing.T) {
+	t.Run("InvalidSizes", func(t *testing.T) {
+		_, err := cas.NewContentDefinedChunker(2048, 1024, 4096)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Chunk sizes must be positive, and satisfy minimum <= average <= maximum"), err)
+
+		_, err = cas.NewContentDefinedChunker(1024, 3000, 4096)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Average chunk size must be a power of two"), err)
+	})
+
+	chunker, err := cas.NewContentDefinedChunker(256, 1024, 4096)
+	require.NoError(t, err)
+	split := func(data []byte) [][]byte {
+		var chunks [][]byte
+		require.NoError(t, chunker.Split(bytes.NewReader(data), func(chunk []byte) error {
+			chunks = append(chunks, append([]byte(nil), chunk...))
+			return nil
+		}))
+		return chunks
+	}
+
+	data := make([]byte, 256*1024)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	t.Run("Empty", func(t *testing.T) {
+		require.Empty(t, split(nil))
+	})
+
+	t.Run("Sizes", func(t *testing.T) {
+		// Concatenating all chunks should yield the original
+		// data. All chunks except the last one should respect
+		// the minimum and maximum size.
+		chunks := split(data)
+		require.Greater(t, len(chunks), 1)
+		require.Equal(t, data, bytes.Join(chunks, nil))
+		for _, chunk := range chunks[:len(chunks)-1] {
+			require.GreaterOrEqual(t, len(chunk), 256)
+			require.LessOrEqual(t, len(chunk), 4096)
+		}
+	})
+
+	t.Run("Insertion", func(t *testing.T) {
+		// Inserting data at the start should only affect the
+		// first few chunks. Boundaries should realign, causing
+		// the remaining chunks to be identical.
+		originalChunks := split(data)
+		modifiedChunks := split(append([]byte("Hello, world!"), data...))
+		shared := 0
+		for i := 1; i <= len(originalChunks) && i <= len(modifiedChunks); i++ {
+			if !bytes.Equal(originalChunks[len(originalChunks)-i], modifiedChunks[len(modifiedChunks)-i]) {
+				break
+			}
+			shared++
+		}
+		require.Greater(t, shared, len(originalChunks)-3)
+	})
+}
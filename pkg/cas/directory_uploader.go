@@ -0,0 +1,255 @@
+package cas
+
+import (
+	"context"
+	"io"
+	"math"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// uploadableBlob contains the information that is needed to upload a
+// blob after it has been determined to be absent from the Content
+// Addressable Storage. Directory messages are retained in memory,
+// while files are reopened by path.
+type uploadableBlob struct {
+	directoryMessage []byte
+	filePath         []path.Component
+}
+
+type directoryUploader struct {
+	context                   context.Context
+	contentAddressableStorage blobstore.BlobAccess
+	digestFunction            digest.Function
+	rootDirectory             filesystem.Directory
+	batchSize                 int
+	chunkedBlobUploader       *ChunkedBlobUploader
+
+	pendingBlobs map[digest.Digest]uploadableBlob
+}
+
+// UploadDirectory walks a local directory and converts it to a REv2
+// Merkle tree consisting of Directory messages. All files and
+// Directory messages that are absent from the Content Addressable
+// Storage are uploaded. The existence of blobs is checked by calling
+// FindMissing() in batches of the provided size.
+//
+// If a ChunkedBlobUploader is provided, large files are additionally
+// stored in chunked form, permitting workers to fetch them partially.
+//
+// Upon success, the digest of the root Directory message is returned,
+// which may be used as the input root of an action.
+func UploadDirectory(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, digestFunction digest.Function, directory filesystem.Directory, batchSize int, chunkedBlobUploader *ChunkedBlobUploader) (digest.Digest, error) {
+	u := directoryUploader{
+		context:                   ctx,
+		contentAddressableStorage: contentAddressableStorage,
+		digestFunction:            digestFunction,
+		rootDirectory:             directory,
+		batchSize:                 batchSize,
+		chunkedBlobUploader:       chunkedBlobUploader,
+		pendingBlobs:              map[digest.Digest]uploadableBlob{},
+	}
+	rootDigest, err := u.uploadDirectory(directory, nil)
+	if err != nil {
+		return digest.BadDigest, err
+	}
+	if err := u.flush(); err != nil {
+		return digest.BadDigest, err
+	}
+	return rootDigest, nil
+}
+
+// enqueue a blob for uploading. When the number of pending blobs
+// reaches the batch size, the batch is flushed.
+func (u *directoryUploader) enqueue(blobDigest digest.Digest, blob uploadableBlob) error {
+	u.pendingBlobs[blobDigest] = blob
+	if len(u.pendingBlobs) >= u.batchSize {
+		return u.flush()
+	}
+	return nil
+}
+
+// flush calls FindMissing() against the Content Addressable Storage
+// for all pending blobs, and uploads the ones that are missing.
+func (u *directoryUploader) flush() error {
+	if len(u.pendingBlobs) == 0 {
+		return nil
+	}
+	digests := digest.NewSetBuilder()
+	for blobDigest := range u.pendingBlobs {
+		digests.Add(blobDigest)
+	}
+	missing, err := u.contentAddressableStorage.FindMissing(u.context, digests.Build())
+	if err != nil {
+		return util.StatusWrap(err, "Failed to determine existence of blobs")
+	}
+	for _, blobDigest := range missing.Items() {
+		blob := u.pendingBlobs[blobDigest]
+		if blob.filePath == nil {
+			if err := u.contentAddressableStorage.Put(
+				u.context,
+				blobDigest,
+				buffer.NewValidatedBufferFromByteSlice(blob.directoryMessage)); err != nil {
+				return util.StatusWrapf(err, "Failed to upload directory %#v", blobDigest.String())
+			}
+		} else if err := u.uploadFile(blobDigest, blob.filePath); err != nil {
+			return err
+		}
+	}
+	u.pendingBlobs = map[digest.Digest]uploadableBlob{}
+	return nil
+}
+
+func (u *directoryUploader) uploadFile(blobDigest digest.Digest, filePath []path.Component) error {
+	d := u.rootDirectory
+	for _, component := range filePath[:len(filePath)-1] {
+		child, err := d.EnterDirectory(component)
+		if d != u.rootDirectory {
+			d.(filesystem.DirectoryCloser).Close()
+		}
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to enter directory %#v", component.String())
+		}
+		d = child
+	}
+	if d != u.rootDirectory {
+		defer d.(filesystem.DirectoryCloser).Close()
+	}
+
+	name := filePath[len(filePath)-1]
+	file, err := d.OpenRead(name)
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to open file %#v", name.String())
+	}
+	// The buffer validates the contents of the file against the
+	// digest, causing the upload to fail if the file was modified
+	// after it was walked.
+	if err := u.contentAddressableStorage.Put(
+		u.context,
+		blobDigest,
+		buffer.NewCASBufferFromReader(
+			blobDigest,
+			struct {
+				io.Reader
+				io.Closer
+			}{
+				Reader: io.NewSectionReader(file, 0, blobDigest.GetSizeBytes()),
+				Closer: file,
+			},
+			buffer.UserProvided)); err != nil {
+		return util.StatusWrapf(err, "Failed to upload file %#v", name.String())
+	}
+	return nil
+}
+
+// uploadChunkedFile stores a file in chunked form, if it is large
+// enough and no chunk manifest for it exists yet.
+func (u *directoryUploader) uploadChunkedFile(d filesystem.Directory, name path.Component, blobDigest digest.Digest) error {
+	cbu := u.chunkedBlobUploader
+	if cbu == nil || blobDigest.GetSizeBytes() < cbu.MinimumBlobSizeBytes {
+		return nil
+	}
+	return cbu.uploadIfMissing(u.context, u.contentAddressableStorage, blobDigest, func(f func(chunk []byte) error) error {
+		file, err := d.OpenRead(name)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return cbu.Chunker.Split(io.NewSectionReader(file, 0, blobDigest.GetSizeBytes()+1), f)
+	})
+}
+
+// computeFileDigest reads the contents of a file to compute its digest.
+func (u *directoryUploader) computeFileDigest(d filesystem.Directory, name path.Component) (digest.Digest, error) {
+	file, err := d.OpenRead(name)
+	if err != nil {
+		return digest.BadDigest, err
+	}
+	defer file.Close()
+
+	digestGenerator := u.digestFunction.NewGenerator(math.MaxInt64)
+	if _, err := io.Copy(digestGenerator, io.NewSectionReader(file, 0, math.MaxInt64)); err != nil {
+		return digest.BadDigest, err
+	}
+	return digestGenerator.Sum(), nil
+}
+
+func (u *directoryUploader) uploadDirectory(d filesystem.Directory, dPath []path.Component) (digest.Digest, error) {
+	entries, err := d.ReadDir()
+	if err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to read directory contents")
+	}
+
+	var directory remoteexecution.Directory
+	for _, entry := range entries {
+		name := entry.Name()
+		childPath := append(dPath[:len(dPath):len(dPath)], name)
+		switch fileType := entry.Type(); fileType {
+		case filesystem.FileTypeRegularFile:
+			childDigest, err := u.computeFileDigest(d, name)
+			if err != nil {
+				return digest.BadDigest, util.StatusWrapf(err, "Failed to compute digest of file %#v", name.String())
+			}
+			directory.Files = append(directory.Files, &remoteexecution.FileNode{
+				Name:         name.String(),
+				Digest:       childDigest.GetProto(),
+				IsExecutable: entry.IsExecutable(),
+			})
+			if err := u.enqueue(childDigest, uploadableBlob{filePath: childPath}); err != nil {
+				return digest.BadDigest, err
+			}
+			if err := u.uploadChunkedFile(d, name, childDigest); err != nil {
+				return digest.BadDigest, util.StatusWrapf(err, "Failed to upload file %#v in chunked form", name.String())
+			}
+		case filesystem.FileTypeDirectory:
+			child, err := d.EnterDirectory(name)
+			if err != nil {
+				return digest.BadDigest, util.StatusWrapf(err, "Failed to enter directory %#v", name.String())
+			}
+			childDigest, err := u.uploadDirectory(child, childPath)
+			child.Close()
+			if err != nil {
+				return digest.BadDigest, util.StatusWrapf(err, "Directory %#v", name.String())
+			}
+			directory.Directories = append(directory.Directories, &remoteexecution.DirectoryNode{
+				Name:   name.String(),
+				Digest: childDigest.GetProto(),
+			})
+		case filesystem.FileTypeSymlink:
+			target, err := d.Readlink(name)
+			if err != nil {
+				return digest.BadDigest, util.StatusWrapf(err, "Failed to read symbolic link %#v", name.String())
+			}
+			directory.Symlinks = append(directory.Symlinks, &remoteexecution.SymlinkNode{
+				Name:   name.String(),
+				Target: target,
+			})
+		default:
+			return digest.BadDigest, status.Errorf(codes.InvalidArgument, "File %#v has an unsupported file type", name.String())
+		}
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(&directory)
+	if err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to marshal directory")
+	}
+	digestGenerator := u.digestFunction.NewGenerator(int64(len(data)))
+	if _, err := digestGenerator.Write(data); err != nil {
+		panic(err)
+	}
+	directoryDigest := digestGenerator.Sum()
+	if err := u.enqueue(directoryDigest, uploadableBlob{directoryMessage: data}); err != nil {
+		return digest.BadDigest, err
+	}
+	return directoryDigest, nil
+}
@@ -0,0 +1,167 @@
+package cas_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUploadDirectory(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	directoryPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(directoryPath, "hello.txt"), []byte("Hello"), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(directoryPath, "bin"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(directoryPath, "bin", "run.sh"), []byte("#!/bin/sh\n"), 0o755))
+	require.NoError(t, os.Symlink("hello.txt", filepath.Join(directoryPath, "link")))
+	directory, err := filesystem.NewLocalDirectory(directoryPath)
+	require.NoError(t, err)
+	defer directory.Close()
+
+	digestFunction := digest.MustNewFunction("example", remoteexecution.DigestFunction_MD5)
+	helloDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+	runDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "3e2b31c72181b87149ff995e7202c0e3", 10)
+	binDirectory := &remoteexecution.Directory{
+		Files: []*remoteexecution.FileNode{
+			{
+				Name:         "run.sh",
+				Digest:       runDigest.GetProto(),
+				IsExecutable: true,
+			},
+		},
+	}
+	rootDirectory := &remoteexecution.Directory{
+		Directories: []*remoteexecution.DirectoryNode{
+			{
+				Name:   "bin",
+				Digest: computeDirectoryDigest(t, digestFunction, binDirectory).GetProto(),
+			},
+		},
+		Files: []*remoteexecution.FileNode{
+			{
+				Name:   "hello.txt",
+				Digest: helloDigest.GetProto(),
+			},
+		},
+		Symlinks: []*remoteexecution.SymlinkNode{
+			{
+				Name:   "link",
+				Target: "hello.txt",
+			},
+		},
+	}
+	rootDigest := computeDirectoryDigest(t, digestFunction, rootDirectory)
+
+	t.Run("FindMissingFailure", func(t *testing.T) {
+		contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+		contentAddressableStorage.EXPECT().FindMissing(ctx, gomock.Any()).
+			Return(digest.EmptySet, status.Error(codes.Unavailable, "Server offline"))
+
+		_, err := cas.UploadDirectory(ctx, contentAddressableStorage, digestFunction, directory, 100, nil)
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Failed to determine existence of blobs: Server offline"), err)
+	})
+
+	t.Run("SingleBatch", func(t *testing.T) {
+		// All four blobs should be checked for existence using a
+		// single call. Only the missing ones should be uploaded.
+		contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+		contentAddressableStorage.EXPECT().FindMissing(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, digests digest.Set) (digest.Set, error) {
+				require.Equal(t, 4, digests.Length())
+				return digest.NewSetBuilder().Add(helloDigest).Add(rootDigest).Build(), nil
+			})
+		contentAddressableStorage.EXPECT().Put(ctx, helloDigest, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				data, err := b.ToByteSlice(100)
+				require.NoError(t, err)
+				require.Equal(t, []byte("Hello"), data)
+				return nil
+			})
+		contentAddressableStorage.EXPECT().Put(ctx, rootDigest, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				m, err := b.ToProto(&remoteexecution.Directory{}, 1000)
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, rootDirectory, m)
+				return nil
+			})
+
+		uploadedDigest, err := cas.UploadDirectory(ctx, contentAddressableStorage, digestFunction, directory, 100, nil)
+		require.NoError(t, err)
+		require.Equal(t, rootDigest, uploadedDigest)
+	})
+
+	t.Run("MultipleBatches", func(t *testing.T) {
+		// With a batch size of one, every blob should be checked
+		// for existence separately.
+		contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+		contentAddressableStorage.EXPECT().FindMissing(ctx, gomock.Any()).
+			Return(digest.EmptySet, nil).
+			Times(4)
+
+		uploadedDigest, err := cas.UploadDirectory(ctx, contentAddressableStorage, digestFunction, directory, 1, nil)
+		require.NoError(t, err)
+		require.Equal(t, rootDigest, uploadedDigest)
+	})
+
+	t.Run("Chunked", func(t *testing.T) {
+		// Files that exceed the minimum size should additionally
+		// be stored in chunked form, having a manifest in the
+		// Action Cache.
+		contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+		actionCache := mock.NewMockBlobAccess(ctrl)
+		chunker, err := cas.NewContentDefinedChunker(2, 4, 8)
+		require.NoError(t, err)
+
+		actionCache.EXPECT().Get(ctx, gomock.Any()).
+			Return(buffer.NewBufferFromError(status.Error(codes.NotFound, "Object not found")))
+		contentAddressableStorage.EXPECT().FindMissing(ctx, gomock.Any()).
+			Return(digest.EmptySet, nil).
+			Times(2)
+		actionCache.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, actionDigest digest.Digest, b buffer.Buffer) error {
+				m, err := b.ToProto(&remoteexecution.ActionResult{}, 1000)
+				require.NoError(t, err)
+				offsetBytes := int64(0)
+				for _, outputFile := range m.(*remoteexecution.ActionResult).OutputFiles {
+					require.Equal(t, strconv.FormatInt(offsetBytes, 10), outputFile.Path)
+					offsetBytes += outputFile.Digest.SizeBytes
+				}
+				require.Equal(t, int64(10), offsetBytes)
+				return nil
+			})
+
+		uploadedDigest, err := cas.UploadDirectory(ctx, contentAddressableStorage, digestFunction, directory, 100, &cas.ChunkedBlobUploader{
+			ActionCache:             actionCache,
+			Chunker:                 chunker,
+			MinimumBlobSizeBytes:    10,
+			MaximumMessageSizeBytes: 1000,
+		})
+		require.NoError(t, err)
+		require.Equal(t, rootDigest, uploadedDigest)
+	})
+}
+
+func computeDirectoryDigest(t *testing.T, digestFunction digest.Function, directory *remoteexecution.Directory) digest.Digest {
+	b := buffer.NewProtoBufferFromProto(directory, buffer.UserProvided)
+	data, err := b.ToByteSlice(1000)
+	require.NoError(t, err)
+	digestGenerator := digestFunction.NewGenerator(int64(len(data)))
+	_, err = digestGenerator.Write(data)
+	require.NoError(t, err)
+	return digestGenerator.Sum()
+}
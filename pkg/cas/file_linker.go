@@ -0,0 +1,62 @@
+package cas
+
+import (
+	"os"
+
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+)
+
+// FileLinker is used by NewHardlinkingFileFetcher() to make files in
+// the cache directory appear in build directories, and vice versa.
+type FileLinker func(oldDirectory filesystem.Directory, oldName path.Component, newDirectory filesystem.Directory, newName path.Component) error
+
+// HardlinkFile is a FileLinker that creates hardlinks.
+func HardlinkFile(oldDirectory filesystem.Directory, oldName path.Component, newDirectory filesystem.Directory, newName path.Component) error {
+	return oldDirectory.Link(oldName, newDirectory, newName)
+}
+
+// CloneFile is a FileLinker that creates copy-on-write clones of
+// files. This is implemented using clonefile() on Darwin, and using
+// ioctl(FICLONE) on Linux. The latter is supported by file systems
+// such as Btrfs, XFS and bcachefs.
+var CloneFile FileLinker = cloneFile
+
+// CheckFileLinkerSupported validates that a FileLinker is capable of
+// linking files between two directories, by attempting to link a file
+// from one directory to the other. This can be used to validate at
+// startup that the file systems on which the cache directory and build
+// directories reside support cloning.
+func CheckFileLinkerSupported(fileLinker FileLinker, oldDirectory, newDirectory filesystem.Directory) error {
+	probeName := path.MustNewComponent(".file_linker_probe")
+	if err := oldDirectory.Remove(probeName); err != nil && !os.IsNotExist(err) {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to remove stale probe file from source directory")
+	}
+	if err := newDirectory.Remove(probeName); err != nil && !os.IsNotExist(err) {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to remove stale probe file from target directory")
+	}
+
+	w, err := oldDirectory.OpenAppend(probeName, filesystem.CreateExcl(0o444))
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to create probe file in source directory")
+	}
+	defer oldDirectory.Remove(probeName)
+	if _, err := w.Write([]byte("Hello")); err != nil {
+		w.Close()
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to write probe file in source directory")
+	}
+	if err := w.Close(); err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to close probe file in source directory")
+	}
+
+	if err := fileLinker(oldDirectory, probeName, newDirectory, probeName); err != nil {
+		return util.StatusWrapWithCode(err, codes.FailedPrecondition, "Failed to link probe file into target directory")
+	}
+	if err := newDirectory.Remove(probeName); err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to remove probe file from target directory")
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build darwin || freebsd || windows
+// +build darwin freebsd windows
+
+package cas
+
+import (
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+)
+
+func cloneFile(oldDirectory filesystem.Directory, oldName path.Component, newDirectory filesystem.Directory, newName path.Component) error {
+	return oldDirectory.Clonefile(oldName, newDirectory, newName)
+}
@@ -0,0 +1,64 @@
+//go:build linux
+// +build linux
+
+package cas
+
+import (
+	"os"
+	"runtime"
+
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fileDescriptorProvider is implemented by file handles returned by
+// filesystem.Directory that are backed by a file descriptor, such as
+// the ones returned by filesystem.NewLocalDirectory().
+type fileDescriptorProvider interface {
+	Fd() uintptr
+}
+
+func cloneFile(oldDirectory filesystem.Directory, oldName path.Component, newDirectory filesystem.Directory, newName path.Component) error {
+	r, err := oldDirectory.OpenRead(oldName)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	rFD, ok := r.(fileDescriptorProvider)
+	if !ok {
+		return status.Error(codes.Unimplemented, "Source file is not backed by a file descriptor")
+	}
+
+	// Create the target file with the same permissions as the
+	// source file.
+	var mode os.FileMode = 0o444
+	if fileInfo, err := oldDirectory.Lstat(oldName); err != nil {
+		return err
+	} else if fileInfo.IsExecutable() {
+		mode = 0o555
+	}
+	w, err := newDirectory.OpenWrite(newName, filesystem.CreateExcl(mode))
+	if err != nil {
+		return err
+	}
+	wFD, ok := w.(fileDescriptorProvider)
+	if !ok {
+		w.Close()
+		newDirectory.Remove(newName)
+		return status.Error(codes.Unimplemented, "Target file is not backed by a file descriptor")
+	}
+
+	err = unix.IoctlFileClone(int(wFD.Fd()), int(rFD.Fd()))
+	runtime.KeepAlive(r)
+	runtime.KeepAlive(w)
+	if err != nil {
+		w.Close()
+		newDirectory.Remove(newName)
+		return err
+	}
+	return w.Close()
+}
@@ -0,0 +1,68 @@
+package cas_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCheckFileLinkerSupported(t *testing.T) {
+	oldPath, newPath := t.TempDir(), t.TempDir()
+	oldDirectory, err := filesystem.NewLocalDirectory(oldPath)
+	require.NoError(t, err)
+	defer oldDirectory.Close()
+	newDirectory, err := filesystem.NewLocalDirectory(newPath)
+	require.NoError(t, err)
+	defer newDirectory.Close()
+
+	t.Run("Hardlink", func(t *testing.T) {
+		// Hardlinking between directories on the same file
+		// system should always be supported. The probe files
+		// should be removed afterwards.
+		require.NoError(t, cas.CheckFileLinkerSupported(cas.HardlinkFile, oldDirectory, newDirectory))
+
+		for _, p := range []string{oldPath, newPath} {
+			entries, err := os.ReadDir(p)
+			require.NoError(t, err)
+			require.Empty(t, entries)
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		failingFileLinker := func(oldDirectory filesystem.Directory, oldName path.Component, newDirectory filesystem.Directory, newName path.Component) error {
+			return status.Error(codes.Unimplemented, "Not supported")
+		}
+		err := cas.CheckFileLinkerSupported(failingFileLinker, oldDirectory, newDirectory)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		// Whether cloning is supported depends on the file
+		// system used by the test. If it is supported, the
+		// resulting file should have the same contents and
+		// permissions.
+		require.NoError(t, os.WriteFile(filepath.Join(oldPath, "source"), []byte("Hello"), 0o555))
+		if err := cas.CloneFile(oldDirectory, path.MustNewComponent("source"), newDirectory, path.MustNewComponent("target")); err != nil {
+			require.Error(t, cas.CheckFileLinkerSupported(cas.CloneFile, oldDirectory, newDirectory))
+			_, statErr := os.Lstat(filepath.Join(newPath, "target"))
+			require.True(t, os.IsNotExist(statErr))
+			t.Skipf("Cloning files is not supported by the file system: %s", err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(newPath, "target"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hello"), data)
+		fileInfo, err := os.Lstat(filepath.Join(newPath, "target"))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o555), fileInfo.Mode().Perm())
+		require.NoError(t, cas.CheckFileLinkerSupported(cas.CloneFile, oldDirectory, newDirectory))
+	})
+}
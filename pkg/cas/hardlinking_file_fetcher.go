@@ -2,9 +2,16 @@ package cas
 
 import (
 	"context"
+	"errors"
+	"io"
+	"math"
 	"os"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"syscall"
 
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/cacheadmin"
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/eviction"
 	"github.com/buildbarn/bb-storage/pkg/filesystem"
@@ -14,15 +21,44 @@ import (
 	"google.golang.org/grpc/codes"
 )
 
+// CachingFileFetcher is a FileFetcher that stores files in a local
+// cache. The contents of the cache may be inspected and flushed, which
+// permits exposing it through the CacheAdmin gRPC service.
+type CachingFileFetcher interface {
+	FileFetcher
+
+	// GetStatistics returns the size of the cache, the number of
+	// cache hits and misses, and the entries in the cache that were
+	// used most frequently.
+	GetStatistics(maximumMostUsedEntries int) *cacheadmin.CacheStatistics
+
+	// FlushFiles removes the executable and non-executable variants
+	// of files with the provided digests from the cache.
+	FlushFiles(blobDigests []digest.Digest) error
+
+	// FlushAll removes all files from the cache.
+	FlushAll() error
+}
+
+type cachedFile struct {
+	digest       digest.Digest
+	isExecutable bool
+	hits         atomic.Uint64
+}
+
 type hardlinkingFileFetcher struct {
 	base           FileFetcher
 	cacheDirectory filesystem.Directory
 	maxFiles       int
 	maxSize        int64
+	fileLinker     FileLinker
+	verifyFiles    bool
 
 	filesLock      sync.RWMutex
-	filesSize      map[string]int64
+	files          map[string]*cachedFile
 	filesTotalSize int64
+	hits           atomic.Uint64
+	misses         atomic.Uint64
 
 	evictionLock sync.Mutex
 	evictionSet  eviction.Set[string]
@@ -33,75 +69,195 @@ type hardlinkingFileFetcher struct {
 // at the target location, they are hardlinked into the cache. Future
 // calls for the same file will hardlink them from the cache to the
 // target location. This reduces the amount of network traffic needed.
-func NewHardlinkingFileFetcher(base FileFetcher, cacheDirectory filesystem.Directory, maxFiles int, maxSize int64, evictionSet eviction.Set[string]) FileFetcher {
+//
+// Files are linked using the provided FileLinker. If CloneFile is
+// used, files are cloned instead of hardlinked. On file systems that
+// support it, this provides copy-on-write semantics, preventing build
+// actions from modifying the contents of the cache. If verifyFiles is
+// set, the contents of cached files are checksummed every time they
+// are reused. Files that were modified are removed from the cache and
+// downloaded once more.
+func NewHardlinkingFileFetcher(base FileFetcher, cacheDirectory filesystem.Directory, maxFiles int, maxSize int64, evictionSet eviction.Set[string], fileLinker FileLinker, verifyFiles bool) CachingFileFetcher {
 	return &hardlinkingFileFetcher{
 		base:           base,
 		cacheDirectory: cacheDirectory,
 		maxFiles:       maxFiles,
 		maxSize:        maxSize,
+		fileLinker:     fileLinker,
+		verifyFiles:    verifyFiles,
 
-		filesSize: map[string]int64{},
+		files: map[string]*cachedFile{},
 
 		evictionSet: evictionSet,
 	}
 }
 
 func (ff *hardlinkingFileFetcher) makeSpace(size int64) error {
-	for len(ff.filesSize) > 0 && (len(ff.filesSize) >= ff.maxFiles || ff.filesTotalSize+size > ff.maxSize) {
-		// Remove a file from disk.
-		key := ff.evictionSet.Peek()
-		if err := ff.cacheDirectory.Remove(path.MustNewComponent(key)); err != nil && !os.IsNotExist(err) {
-			return util.StatusWrapfWithCode(err, codes.Internal, "Failed to remove cached file %#v", key)
+	for len(ff.files) > 0 && (len(ff.files) >= ff.maxFiles || ff.filesTotalSize+size > ff.maxSize) {
+		if err := ff.removeOldestFile(); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		// Remove file from bookkeeping.
-		ff.evictionSet.Remove()
-		ff.filesTotalSize -= ff.filesSize[key]
-		delete(ff.filesSize, key)
+// removeOldestFile removes the file from the cache that is first in
+// line to be evicted.
+func (ff *hardlinkingFileFetcher) removeOldestFile() error {
+	// Remove a file from disk.
+	key := ff.evictionSet.Peek()
+	if err := ff.cacheDirectory.Remove(path.MustNewComponent(key)); err != nil && !os.IsNotExist(err) {
+		return util.StatusWrapfWithCode(err, codes.Internal, "Failed to remove cached file %#v", key)
 	}
+
+	// Remove file from bookkeeping.
+	ff.evictionSet.Remove()
+	ff.filesTotalSize -= ff.files[key].digest.GetSizeBytes()
+	delete(ff.files, key)
 	return nil
 }
 
-func (ff *hardlinkingFileFetcher) GetFile(ctx context.Context, blobDigest digest.Digest, directory filesystem.Directory, name path.Component, isExecutable bool) error {
+// link a file from one directory into another, either by creating a
+// hardlink or by cloning it. If the directories reside on different
+// file systems (e.g., because build directories are backed by tmpfs),
+// the file is copied instead.
+func (ff *hardlinkingFileFetcher) link(oldDirectory filesystem.Directory, oldName path.Component, newDirectory filesystem.Directory, newName path.Component, isExecutable bool) error {
+	err := ff.fileLinker(oldDirectory, oldName, newDirectory, newName)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	return copyFile(oldDirectory, oldName, newDirectory, newName, isExecutable)
+}
+
+// copyFile copies the contents of a file from one directory into
+// another.
+func copyFile(oldDirectory filesystem.Directory, oldName path.Component, newDirectory filesystem.Directory, newName path.Component, isExecutable bool) error {
+	r, err := oldDirectory.OpenRead(oldName)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	var mode os.FileMode = 0o444
+	if isExecutable {
+		mode = 0o555
+	}
+	w, err := newDirectory.OpenAppend(newName, filesystem.CreateExcl(mode))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, io.NewSectionReader(r, 0, math.MaxInt64)); err != nil {
+		w.Close()
+		newDirectory.Remove(newName)
+		return err
+	}
+	return w.Close()
+}
+
+// isCachedFileValid returns whether the contents of a file in the
+// cache directory still match the digest of the blob it was created
+// from. Files may have been modified by build actions that changed
+// the contents of their inputs through a hardlink.
+func (ff *hardlinkingFileFetcher) isCachedFileValid(blobDigest digest.Digest, name path.Component) (bool, error) {
+	f, err := ff.cacheDirectory.OpenRead(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, util.StatusWrapfWithCode(err, codes.Internal, "Failed to open cached file %#v", name.String())
+	}
+	defer f.Close()
+
+	sizeBytes := blobDigest.GetSizeBytes()
+	generator := blobDigest.GetDigestFunction().NewGenerator(sizeBytes)
+	if _, err := io.Copy(generator, io.NewSectionReader(f, 0, sizeBytes+1)); err != nil {
+		return false, util.StatusWrapfWithCode(err, codes.Internal, "Failed to read cached file %#v", name.String())
+	}
+	return generator.Sum() == blobDigest, nil
+}
+
+// getCacheKey returns the name of the file in the cache directory
+// that is used to store a given file.
+func getCacheKey(blobDigest digest.Digest, isExecutable bool) string {
 	key := blobDigest.GetKey(digest.KeyWithoutInstance)
 	if isExecutable {
-		key += "+x"
-	} else {
-		key += "-x"
+		return key + "+x"
 	}
+	return key + "-x"
+}
+
+func (ff *hardlinkingFileFetcher) GetFile(ctx context.Context, blobDigest digest.Digest, directory filesystem.Directory, name path.Component, isExecutable bool) error {
+	key := getCacheKey(blobDigest, isExecutable)
 
 	// If the file is present in the cache, hardlink it to the destination.
-	wasMissing := false
+	wasMissing, isCorrupted := false, false
 	ff.filesLock.RLock()
-	if _, ok := ff.filesSize[key]; ok {
+	if file, ok := ff.files[key]; ok {
 		ff.evictionLock.Lock()
 		ff.evictionSet.Touch(key)
 		ff.evictionLock.Unlock()
 
-		if err := ff.cacheDirectory.Link(path.MustNewComponent(key), directory, name); err == nil {
-			// Successfully hardlinked the file to its destination.
-			ff.filesLock.RUnlock()
-			return nil
-		} else if !os.IsNotExist(err) {
-			ff.filesLock.RUnlock()
-			return util.StatusWrapfWithCode(err, codes.Internal, "Failed to create hardlink to cached file %#v", key)
+		if ff.verifyFiles {
+			valid, err := ff.isCachedFileValid(blobDigest, path.MustNewComponent(key))
+			if err != nil {
+				ff.filesLock.RUnlock()
+				return err
+			}
+			isCorrupted = !valid
+		}
+
+		if !isCorrupted {
+			if err := ff.link(ff.cacheDirectory, path.MustNewComponent(key), directory, name, isExecutable); err == nil {
+				// Successfully linked the file to its destination.
+				file.hits.Add(1)
+				ff.hits.Add(1)
+				ff.filesLock.RUnlock()
+				return nil
+			} else if !os.IsNotExist(err) {
+				ff.filesLock.RUnlock()
+				return util.StatusWrapfWithCode(err, codes.Internal, "Failed to create hardlink to cached file %#v", key)
+			}
 		}
 
 		// The file was part of the cache, even though it did not
-		// exist on disk. Some other process may have tampered
-		// with the cache directory's contents.
+		// exist on disk or was corrupted. Some other process may
+		// have tampered with the cache directory's contents, or
+		// the file was removed due to being corrupted or flushed.
 		wasMissing = true
 	}
 	ff.filesLock.RUnlock()
 
+	if isCorrupted {
+		// The file was modified. Remove it, so that it gets
+		// replaced by a fresh copy below. Other callers may be
+		// linking the file while the read lock is held, so only
+		// remove it while holding the write lock. The file may
+		// have been replaced in the meantime, so verify it
+		// once more.
+		ff.filesLock.Lock()
+		valid, err := ff.isCachedFileValid(blobDigest, path.MustNewComponent(key))
+		if err == nil && !valid {
+			if err = ff.cacheDirectory.Remove(path.MustNewComponent(key)); err != nil && !os.IsNotExist(err) {
+				err = util.StatusWrapfWithCode(err, codes.Internal, "Failed to remove corrupted cached file %#v", key)
+			} else {
+				err = nil
+			}
+		}
+		ff.filesLock.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
 	// Download the file at the intended location.
+	ff.misses.Add(1)
 	if err := ff.base.GetFile(ctx, blobDigest, directory, name, isExecutable); err != nil {
 		return err
 	}
 
 	ff.filesLock.Lock()
 	defer ff.filesLock.Unlock()
-	if _, ok := ff.filesSize[key]; !ok {
+	if _, ok := ff.files[key]; !ok {
 		ff.evictionLock.Lock()
 		defer ff.evictionLock.Unlock()
 
@@ -111,19 +267,103 @@ func (ff *hardlinkingFileFetcher) GetFile(ctx context.Context, blobDigest digest
 			return err
 		}
 
-		// Hardlink the file into the cache.
-		if err := directory.Link(name, ff.cacheDirectory, path.MustNewComponent(key)); err != nil && !os.IsExist(err) {
+		// Link the file into the cache.
+		if err := ff.link(directory, name, ff.cacheDirectory, path.MustNewComponent(key), isExecutable); err != nil && !os.IsExist(err) {
 			return util.StatusWrapfWithCode(err, codes.Internal, "Failed to add cached file %#v", key)
 		}
 		ff.evictionSet.Insert(key)
-		ff.filesSize[key] = sizeBytes
+		ff.files[key] = &cachedFile{
+			digest:       blobDigest,
+			isExecutable: isExecutable,
+		}
 		ff.filesTotalSize += sizeBytes
 	} else if wasMissing {
 		// Even though the file is part of our bookkeeping, we
 		// observed it didn't exist. Repair this inconsistency.
-		if err := directory.Link(name, ff.cacheDirectory, path.MustNewComponent(key)); err != nil && !os.IsExist(err) {
+		if err := ff.link(directory, name, ff.cacheDirectory, path.MustNewComponent(key), isExecutable); err != nil && !os.IsExist(err) {
 			return util.StatusWrapfWithCode(err, codes.Internal, "Failed to repair cached file %#v", key)
 		}
 	}
 	return nil
 }
+
+func (ff *hardlinkingFileFetcher) GetStatistics(maximumMostUsedEntries int) *cacheadmin.CacheStatistics {
+	ff.filesLock.RLock()
+	defer ff.filesLock.RUnlock()
+
+	type usedFile struct {
+		file *cachedFile
+		hits uint64
+	}
+	var usedFiles []usedFile
+	for _, file := range ff.files {
+		if hits := file.hits.Load(); hits > 0 {
+			usedFiles = append(usedFiles, usedFile{file: file, hits: hits})
+		}
+	}
+	sort.Slice(usedFiles, func(i, j int) bool {
+		fi, fj := usedFiles[i], usedFiles[j]
+		if fi.hits != fj.hits {
+			return fi.hits > fj.hits
+		}
+		return getCacheKey(fi.file.digest, fi.file.isExecutable) < getCacheKey(fj.file.digest, fj.file.isExecutable)
+	})
+	if len(usedFiles) > maximumMostUsedEntries {
+		usedFiles = usedFiles[:maximumMostUsedEntries]
+	}
+
+	mostUsedEntries := make([]*cacheadmin.CacheEntry, 0, len(usedFiles))
+	for _, usedFile := range usedFiles {
+		mostUsedEntries = append(mostUsedEntries, &cacheadmin.CacheEntry{
+			Digest:         usedFile.file.digest.GetProto(),
+			DigestFunction: usedFile.file.digest.GetDigestFunction().GetEnumValue(),
+			IsExecutable:   usedFile.file.isExecutable,
+			Hits:           usedFile.hits,
+			InstanceName:   usedFile.file.digest.GetInstanceName().String(),
+		})
+	}
+	return &cacheadmin.CacheStatistics{
+		FileCount:        uint64(len(ff.files)),
+		SizeBytes:        ff.filesTotalSize,
+		MaximumFileCount: uint64(ff.maxFiles),
+		MaximumSizeBytes: ff.maxSize,
+		Hits:             ff.hits.Load(),
+		Misses:           ff.misses.Load(),
+		MostUsedEntries:  mostUsedEntries,
+	}
+}
+
+func (ff *hardlinkingFileFetcher) FlushFiles(blobDigests []digest.Digest) error {
+	ff.filesLock.Lock()
+	defer ff.filesLock.Unlock()
+
+	// Only remove the files from disk, as eviction sets don't
+	// permit removing arbitrary keys. The files remain part of the
+	// bookkeeping, causing GetFile() to download and reinsert them
+	// when requested.
+	for _, blobDigest := range blobDigests {
+		for _, isExecutable := range []bool{false, true} {
+			key := getCacheKey(blobDigest, isExecutable)
+			if _, ok := ff.files[key]; ok {
+				if err := ff.cacheDirectory.Remove(path.MustNewComponent(key)); err != nil && !os.IsNotExist(err) {
+					return util.StatusWrapfWithCode(err, codes.Internal, "Failed to remove cached file %#v", key)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (ff *hardlinkingFileFetcher) FlushAll() error {
+	ff.filesLock.Lock()
+	defer ff.filesLock.Unlock()
+	ff.evictionLock.Lock()
+	defer ff.evictionLock.Unlock()
+
+	for len(ff.files) > 0 {
+		if err := ff.removeOldestFile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
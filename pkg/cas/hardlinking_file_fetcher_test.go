@@ -2,15 +2,19 @@ package cas_test
 
 import (
 	"context"
+	"io"
 	"os"
+	"path/filepath"
 	"syscall"
 	"testing"
 
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-remote-execution/internal/mock"
 	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/cacheadmin"
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/eviction"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
 	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
 	"github.com/buildbarn/bb-storage/pkg/testutil"
 	"github.com/golang/mock/gomock"
@@ -25,7 +29,7 @@ func TestHardlinkingFileFetcher(t *testing.T) {
 
 	baseFileFetcher := mock.NewMockFileFetcher(ctrl)
 	cacheDirectory := mock.NewMockDirectory(ctrl)
-	fileFetcher := cas.NewHardlinkingFileFetcher(baseFileFetcher, cacheDirectory, 1, 1024, eviction.NewLRUSet[string]())
+	fileFetcher := cas.NewHardlinkingFileFetcher(baseFileFetcher, cacheDirectory, 1, 1024, eviction.NewLRUSet[string](), cas.HardlinkFile, false)
 
 	blobDigest1 := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
 	buildDirectory := mock.NewMockDirectory(ctrl)
@@ -126,3 +130,171 @@ func TestHardlinkingFileFetcher(t *testing.T) {
 		t,
 		fileFetcher.GetFile(ctx, blobDigest2, buildDirectory, path.MustNewComponent("goodbye.txt"), false))
 }
+
+func TestHardlinkingFileFetcherClonefileAndVerification(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseFileFetcher := mock.NewMockFileFetcher(ctrl)
+	cacheDirectory := mock.NewMockDirectory(ctrl)
+	cloneFile := func(oldDirectory filesystem.Directory, oldName path.Component, newDirectory filesystem.Directory, newName path.Component) error {
+		return oldDirectory.Clonefile(oldName, newDirectory, newName)
+	}
+	fileFetcher := cas.NewHardlinkingFileFetcher(baseFileFetcher, cacheDirectory, 10, 1024, eviction.NewLRUSet[string](), cloneFile, true)
+
+	blobDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+	buildDirectory := mock.NewMockDirectory(ctrl)
+
+	// Files should be cloned into the cache.
+	baseFileFetcher.EXPECT().GetFile(ctx, blobDigest, buildDirectory, path.MustNewComponent("hello.txt"), false)
+	buildDirectory.EXPECT().Clonefile(path.MustNewComponent("hello.txt"), cacheDirectory, path.MustNewComponent("3-8b1a9953c4611296a827abf8c47804d7-5-x"))
+	require.NoError(
+		t,
+		fileFetcher.GetFile(ctx, blobDigest, buildDirectory, path.MustNewComponent("hello.txt"), false))
+
+	mockCachedFileContents := func(contents string) {
+		file := mock.NewMockFileReader(ctrl)
+		cacheDirectory.EXPECT().OpenRead(path.MustNewComponent("3-8b1a9953c4611296a827abf8c47804d7-5-x")).Return(file, nil)
+		file.EXPECT().ReadAt(gomock.Any(), int64(0)).DoAndReturn(func(p []byte, off int64) (int, error) {
+			return copy(p, contents), io.EOF
+		})
+		file.EXPECT().Close()
+	}
+
+	// Files with intact contents should be cloned from the cache.
+	mockCachedFileContents("Hello")
+	cacheDirectory.EXPECT().Clonefile(path.MustNewComponent("3-8b1a9953c4611296a827abf8c47804d7-5-x"), buildDirectory, path.MustNewComponent("hello.txt"))
+	require.NoError(
+		t,
+		fileFetcher.GetFile(ctx, blobDigest, buildDirectory, path.MustNewComponent("hello.txt"), false))
+
+	// Failures to remove corrupted files should be propagated. The
+	// file should be verified once more after acquiring the write
+	// lock, as it may have been replaced in the meantime.
+	mockCachedFileContents("Jello")
+	mockCachedFileContents("Jello")
+	cacheDirectory.EXPECT().Remove(path.MustNewComponent("3-8b1a9953c4611296a827abf8c47804d7-5-x")).Return(syscall.EIO)
+	testutil.RequireEqualStatus(
+		t,
+		status.Error(codes.Internal, "Failed to remove corrupted cached file \"3-8b1a9953c4611296a827abf8c47804d7-5-x\": input/output error"),
+		fileFetcher.GetFile(ctx, blobDigest, buildDirectory, path.MustNewComponent("hello.txt"), false))
+
+	// Corrupted files should be removed from the cache and be
+	// replaced by a freshly downloaded copy.
+	mockCachedFileContents("Hello, world")
+	mockCachedFileContents("Hello, world")
+	cacheDirectory.EXPECT().Remove(path.MustNewComponent("3-8b1a9953c4611296a827abf8c47804d7-5-x"))
+	baseFileFetcher.EXPECT().GetFile(ctx, blobDigest, buildDirectory, path.MustNewComponent("hello.txt"), false)
+	buildDirectory.EXPECT().Clonefile(path.MustNewComponent("hello.txt"), cacheDirectory, path.MustNewComponent("3-8b1a9953c4611296a827abf8c47804d7-5-x"))
+	require.NoError(
+		t,
+		fileFetcher.GetFile(ctx, blobDigest, buildDirectory, path.MustNewComponent("hello.txt"), false))
+}
+
+func TestHardlinkingFileFetcherStatisticsAndFlushing(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseFileFetcher := mock.NewMockFileFetcher(ctrl)
+	cacheDirectory := mock.NewMockDirectory(ctrl)
+	fileFetcher := cas.NewHardlinkingFileFetcher(baseFileFetcher, cacheDirectory, 10, 1024, eviction.NewLRUSet[string](), cas.HardlinkFile, false)
+
+	blobDigest1 := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+	blobDigest2 := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "6f5902ac237024bdd0c176cb93063dc4", 12)
+	buildDirectory := mock.NewMockDirectory(ctrl)
+
+	// Insert two files into the cache, and let the first one be
+	// reused twice and the second one once.
+	for _, file := range []struct {
+		blobDigest digest.Digest
+		key        string
+		hits       int
+	}{
+		{blobDigest1, "3-8b1a9953c4611296a827abf8c47804d7-5-x", 2},
+		{blobDigest2, "3-6f5902ac237024bdd0c176cb93063dc4-12-x", 1},
+	} {
+		baseFileFetcher.EXPECT().GetFile(ctx, file.blobDigest, buildDirectory, path.MustNewComponent("file"), false)
+		buildDirectory.EXPECT().Link(path.MustNewComponent("file"), cacheDirectory, path.MustNewComponent(file.key))
+		require.NoError(t, fileFetcher.GetFile(ctx, file.blobDigest, buildDirectory, path.MustNewComponent("file"), false))
+
+		cacheDirectory.EXPECT().Link(path.MustNewComponent(file.key), buildDirectory, path.MustNewComponent("file")).Times(file.hits)
+		for i := 0; i < file.hits; i++ {
+			require.NoError(t, fileFetcher.GetFile(ctx, file.blobDigest, buildDirectory, path.MustNewComponent("file"), false))
+		}
+	}
+
+	testutil.RequireEqualProto(t, &cacheadmin.CacheStatistics{
+		FileCount:        2,
+		SizeBytes:        17,
+		MaximumFileCount: 10,
+		MaximumSizeBytes: 1024,
+		Hits:             3,
+		Misses:           2,
+		MostUsedEntries: []*cacheadmin.CacheEntry{
+			{
+				Digest: &remoteexecution.Digest{
+					Hash:      "8b1a9953c4611296a827abf8c47804d7",
+					SizeBytes: 5,
+				},
+				DigestFunction: remoteexecution.DigestFunction_MD5,
+				Hits:           2,
+				InstanceName:   "example",
+			},
+		},
+	}, fileFetcher.GetStatistics(1))
+
+	// Flushing individual files should only remove them from disk.
+	// They are downloaded and reinserted when requested.
+	cacheDirectory.EXPECT().Remove(path.MustNewComponent("3-8b1a9953c4611296a827abf8c47804d7-5-x"))
+	require.NoError(t, fileFetcher.FlushFiles([]digest.Digest{blobDigest1}))
+
+	cacheDirectory.EXPECT().Link(path.MustNewComponent("3-8b1a9953c4611296a827abf8c47804d7-5-x"), buildDirectory, path.MustNewComponent("file")).
+		Return(syscall.ENOENT)
+	baseFileFetcher.EXPECT().GetFile(ctx, blobDigest1, buildDirectory, path.MustNewComponent("file"), false)
+	buildDirectory.EXPECT().Link(path.MustNewComponent("file"), cacheDirectory, path.MustNewComponent("3-8b1a9953c4611296a827abf8c47804d7-5-x"))
+	require.NoError(t, fileFetcher.GetFile(ctx, blobDigest1, buildDirectory, path.MustNewComponent("file"), false))
+
+	// Flushing the entire cache should remove all files, both from
+	// disk and the bookkeeping.
+	cacheDirectory.EXPECT().Remove(path.MustNewComponent("3-8b1a9953c4611296a827abf8c47804d7-5-x"))
+	cacheDirectory.EXPECT().Remove(path.MustNewComponent("3-6f5902ac237024bdd0c176cb93063dc4-12-x"))
+	require.NoError(t, fileFetcher.FlushAll())
+
+	statistics := fileFetcher.GetStatistics(10)
+	require.Equal(t, uint64(0), statistics.FileCount)
+	require.Equal(t, int64(0), statistics.SizeBytes)
+	require.Empty(t, statistics.MostUsedEntries)
+}
+
+func TestHardlinkingFileFetcherCrossDevice(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseFileFetcher := mock.NewMockFileFetcher(ctrl)
+	cacheDirectoryPath := t.TempDir()
+	cacheDirectory, err := filesystem.NewLocalDirectory(cacheDirectoryPath)
+	require.NoError(t, err)
+	defer cacheDirectory.Close()
+	fileFetcher := cas.NewHardlinkingFileFetcher(baseFileFetcher, cacheDirectory, 10, 1024, eviction.NewLRUSet[string](), cas.HardlinkFile, false)
+
+	blobDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+	buildDirectory := mock.NewMockDirectory(ctrl)
+
+	// If the build directory resides on another file system than
+	// the cache directory (e.g., tmpfs), hardlinking fails with
+	// EXDEV. The file should be copied instead.
+	baseFileFetcher.EXPECT().GetFile(ctx, blobDigest, buildDirectory, path.MustNewComponent("hello.sh"), true)
+	buildDirectory.EXPECT().Link(path.MustNewComponent("hello.sh"), cacheDirectory, path.MustNewComponent("3-8b1a9953c4611296a827abf8c47804d7-5+x")).
+		Return(syscall.EXDEV)
+	fileReader := mock.NewMockFileReader(ctrl)
+	buildDirectory.EXPECT().OpenRead(path.MustNewComponent("hello.sh")).Return(fileReader, nil)
+	fileReader.EXPECT().ReadAt(gomock.Any(), int64(0)).DoAndReturn(func(p []byte, off int64) (int, error) {
+		return copy(p, "Hello"), io.EOF
+	})
+	fileReader.EXPECT().Close()
+	require.NoError(t, fileFetcher.GetFile(ctx, blobDigest, buildDirectory, path.MustNewComponent("hello.sh"), true))
+
+	data, err := os.ReadFile(filepath.Join(cacheDirectoryPath, "3-8b1a9953c4611296a827abf8c47804d7-5+x"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("Hello"), data)
+	fileInfo, err := os.Stat(filepath.Join(cacheDirectoryPath, "3-8b1a9953c4611296a827abf8c47804d7-5+x"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o555), fileInfo.Mode().Perm())
+}
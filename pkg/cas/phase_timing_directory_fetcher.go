@@ -0,0 +1,41 @@
+package cas
+
+import (
+	"context"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+type phaseTimingDirectoryFetcher struct {
+	base DirectoryFetcher
+}
+
+// NewPhaseTimingDirectoryFetcher is a decorator for DirectoryFetcher
+// that simply forwards all methods. The duration of each call is
+// accumulated into the clock.PhaseTimer attached to the Context, if
+// any, as time spent fetching directories.
+func NewPhaseTimingDirectoryFetcher(base DirectoryFetcher) DirectoryFetcher {
+	return &phaseTimingDirectoryFetcher{
+		base: base,
+	}
+}
+
+func (df *phaseTimingDirectoryFetcher) GetDirectory(ctx context.Context, directoryDigest digest.Digest) (*remoteexecution.Directory, error) {
+	defer clock.StartPhase(ctx, clock.PhaseDirectoryFetch).Finish()
+
+	return df.base.GetDirectory(ctx, directoryDigest)
+}
+
+func (df *phaseTimingDirectoryFetcher) GetTreeRootDirectory(ctx context.Context, treeDigest digest.Digest) (*remoteexecution.Directory, error) {
+	defer clock.StartPhase(ctx, clock.PhaseDirectoryFetch).Finish()
+
+	return df.base.GetTreeRootDirectory(ctx, treeDigest)
+}
+
+func (df *phaseTimingDirectoryFetcher) GetTreeChildDirectory(ctx context.Context, treeDigest, childDigest digest.Digest) (*remoteexecution.Directory, error) {
+	defer clock.StartPhase(ctx, clock.PhaseDirectoryFetch).Finish()
+
+	return df.base.GetTreeChildDirectory(ctx, treeDigest, childDigest)
+}
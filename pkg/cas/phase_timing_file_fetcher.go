@@ -0,0 +1,30 @@
+package cas
+
+import (
+	"context"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+)
+
+type phaseTimingFileFetcher struct {
+	base FileFetcher
+}
+
+// NewPhaseTimingFileFetcher is a decorator for FileFetcher that simply
+// forwards all calls. The duration of each call is accumulated into
+// the clock.PhaseTimer attached to the Context, if any, as time spent
+// fetching files.
+func NewPhaseTimingFileFetcher(base FileFetcher) FileFetcher {
+	return &phaseTimingFileFetcher{
+		base: base,
+	}
+}
+
+func (ff *phaseTimingFileFetcher) GetFile(ctx context.Context, blobDigest digest.Digest, directory filesystem.Directory, name path.Component, isExecutable bool) error {
+	defer clock.StartPhase(ctx, clock.PhaseFileFetch).Finish()
+
+	return ff.base.GetFile(ctx, blobDigest, directory, name, isExecutable)
+}
@@ -0,0 +1,14 @@
+package cleaner
+
+// Mount of a file system on the operating system.
+type Mount struct {
+	MountPoint     string
+	FileSystemType string
+}
+
+// MountTable is an interface for extracting a list of file systems
+// that are mounted on the operating system, and for unmounting them.
+type MountTable interface {
+	GetMounts() ([]Mount, error)
+	Unmount(mountPoint string) error
+}
@@ -10,6 +10,12 @@ type Process struct {
 	ProcessID    int
 	UserID       int
 	CreationTime time.Time
+
+	// The command line arguments of the process. This may be
+	// empty if it cannot be obtained (e.g., because the process is
+	// a zombie). On some operating systems only the name of the
+	// executable is provided.
+	CommandLine []string
 }
 
 // ProcessTable is an interface for extracting a list of processes
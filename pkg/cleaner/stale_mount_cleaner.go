@@ -0,0 +1,78 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+)
+
+// isVirtualFileSystemType returns true if a file system type
+// corresponds to one of the virtual file systems that bb_worker is
+// capable of exposing build directories through (i.e., FUSE or NFS).
+func isVirtualFileSystemType(fileSystemType string) bool {
+	return strings.Contains(fileSystemType, "fuse") || strings.HasPrefix(fileSystemType, "nfs")
+}
+
+// NewStaleMountCleaner creates a Cleaner that unmounts FUSE and NFS
+// file systems underneath a given directory that were left behind by
+// previous instances of bb_worker (e.g., due to it crashing). Such
+// mounts prevent bb_worker from starting, as accessing them fails
+// with ENOTCONN or causes the caller to hang.
+//
+// Mounts whose mount point is listed in activeMountPoints belong to
+// the current instance, and are left alone. Once unmounted, mount
+// points of stale mounts are removed if they are empty, so that
+// orphaned build directories don't accumulate.
+func NewStaleMountCleaner(mountTable MountTable, directoryPath string, activeMountPoints []string) Cleaner {
+	directoryPath = filepath.Clean(directoryPath)
+	active := make(map[string]struct{}, len(activeMountPoints))
+	for _, mountPoint := range activeMountPoints {
+		active[filepath.Clean(mountPoint)] = struct{}{}
+	}
+
+	return func(ctx context.Context) error {
+		mounts, err := mountTable.GetMounts()
+		if err != nil {
+			return util.StatusWrap(err, "Failed to get mounts from mount table")
+		}
+
+		// Process mounts in the reverse order in which they
+		// were created, so that nested and stacked mounts are
+		// unmounted before their parents.
+		var unmounted []string
+		for i := len(mounts) - 1; i >= 0; i-- {
+			mount := mounts[i]
+			mountPoint := filepath.Clean(mount.MountPoint)
+			if _, ok := active[mountPoint]; ok ||
+				!isVirtualFileSystemType(mount.FileSystemType) ||
+				(mountPoint != directoryPath && !strings.HasPrefix(mountPoint, directoryPath+string(filepath.Separator))) {
+				continue
+			}
+			if err := mountTable.Unmount(mountPoint); err != nil {
+				return util.StatusWrapfWithCode(err, codes.Internal, "Failed to unmount stale mount %#v", mountPoint)
+			}
+			unmounted = append(unmounted, mountPoint)
+		}
+
+		for _, mountPoint := range unmounted {
+			if mountPoint == directoryPath {
+				continue
+			}
+			if err := os.Remove(mountPoint); err != nil &&
+				!os.IsNotExist(err) &&
+				!errors.Is(err, syscall.ENOTEMPTY) &&
+				!errors.Is(err, syscall.EEXIST) &&
+				!errors.Is(err, syscall.EBUSY) {
+				return util.StatusWrapfWithCode(err, codes.Internal, "Failed to remove mount point %#v", mountPoint)
+			}
+		}
+		return nil
+	}
+}
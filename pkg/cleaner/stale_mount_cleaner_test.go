@@ -0,0 +1,87 @@
+package cleaner_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/cleaner"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStaleMountCleaner(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	t.Run("GetMountsFailure", func(t *testing.T) {
+		mountTable := mock.NewMockMountTable(ctrl)
+		mountTable.EXPECT().GetMounts().Return(nil, status.Error(codes.Internal, "Failed to open /proc/self/mountinfo"))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Internal, "Failed to get mounts from mount table: Failed to open /proc/self/mountinfo"),
+			cleaner.NewStaleMountCleaner(mountTable, "/worker", nil)(ctx))
+	})
+
+	t.Run("UnmountFailure", func(t *testing.T) {
+		mountTable := mock.NewMockMountTable(ctrl)
+		mountTable.EXPECT().GetMounts().Return([]cleaner.Mount{
+			{MountPoint: "/worker/build", FileSystemType: "fuse"},
+		}, nil)
+		mountTable.EXPECT().Unmount("/worker/build").Return(syscall.EPERM)
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Internal, "Failed to unmount stale mount \"/worker/build\": operation not permitted"),
+			cleaner.NewStaleMountCleaner(mountTable, "/worker", nil)(ctx))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Only FUSE and NFS mounts underneath the directory
+		// that don't belong to the current instance should be
+		// unmounted, in reverse order.
+		directoryPath := t.TempDir()
+		for _, name := range []string{"active", "orphaned", "nonempty"} {
+			require.NoError(t, os.Mkdir(filepath.Join(directoryPath, name), 0o755))
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(directoryPath, "nonempty", "file"), nil, 0o644))
+
+		mountTable := mock.NewMockMountTable(ctrl)
+		mountTable.EXPECT().GetMounts().Return([]cleaner.Mount{
+			{MountPoint: "/", FileSystemType: "ext4"},
+			{MountPoint: "/mnt/nfs", FileSystemType: "nfs4"},
+			{MountPoint: directoryPath + "-sibling", FileSystemType: "fuse"},
+			{MountPoint: filepath.Join(directoryPath, "active"), FileSystemType: "fuse"},
+			{MountPoint: filepath.Join(directoryPath, "orphaned"), FileSystemType: "fuse"},
+			{MountPoint: filepath.Join(directoryPath, "orphaned", "nested"), FileSystemType: "nfs"},
+			{MountPoint: filepath.Join(directoryPath, "nonempty"), FileSystemType: "tmpfs"},
+			{MountPoint: filepath.Join(directoryPath, "nonempty"), FileSystemType: "fuse.bb_worker"},
+		}, nil)
+		gomock.InOrder(
+			mountTable.EXPECT().Unmount(filepath.Join(directoryPath, "nonempty")),
+			mountTable.EXPECT().Unmount(filepath.Join(directoryPath, "orphaned", "nested")),
+			mountTable.EXPECT().Unmount(filepath.Join(directoryPath, "orphaned")))
+
+		require.NoError(t, cleaner.NewStaleMountCleaner(
+			mountTable,
+			directoryPath,
+			[]string{filepath.Join(directoryPath, "active")})(ctx))
+
+		// Empty mount points of stale mounts should be removed.
+		// Active mount points and ones containing files should
+		// be left alone.
+		_, err := os.Stat(filepath.Join(directoryPath, "active"))
+		require.NoError(t, err)
+		_, err = os.Stat(filepath.Join(directoryPath, "orphaned"))
+		require.True(t, os.IsNotExist(err))
+		_, err = os.Stat(filepath.Join(directoryPath, "nonempty", "file"))
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,47 @@
+//go:build darwin
+// +build darwin
+
+package cleaner
+
+import (
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+)
+
+type systemMountTable struct{}
+
+func (mt systemMountTable) GetMounts() ([]Mount, error) {
+	n, err := unix.Getfsstat(nil, unix.MNT_NOWAIT)
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to obtain number of mounted file systems")
+	}
+	// Add some slack, in case file systems get mounted in the
+	// meantime.
+	stats := make([]unix.Statfs_t, n+16)
+	n, err = unix.Getfsstat(stats, unix.MNT_NOWAIT)
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to obtain list of mounted file systems")
+	}
+
+	mounts := make([]Mount, 0, n)
+	for _, stat := range stats[:n] {
+		mounts = append(mounts, Mount{
+			MountPoint:     unix.ByteSliceToString(stat.Mntonname[:]),
+			FileSystemType: unix.ByteSliceToString(stat.Fstypename[:]),
+		})
+	}
+	return mounts, nil
+}
+
+func (mt systemMountTable) Unmount(mountPoint string) error {
+	// macOS does not support lazy unmounting. Force the unmount
+	// instead, as the server backing a stale mount is gone.
+	return unix.Unmount(mountPoint, unix.MNT_FORCE)
+}
+
+// SystemMountTable corresponds with the mount table of the locally
+// running operating system. On this operating system the information
+// is obtained by calling getfsstat().
+var SystemMountTable MountTable = systemMountTable{}
@@ -0,0 +1,24 @@
+//go:build freebsd || windows
+// +build freebsd windows
+
+package cleaner
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type systemMountTable struct{}
+
+func (mt systemMountTable) GetMounts() ([]Mount, error) {
+	return nil, status.Error(codes.Unimplemented, "Scanning the mount table is not supported on this platform")
+}
+
+func (mt systemMountTable) Unmount(mountPoint string) error {
+	return status.Error(codes.Unimplemented, "Unmounting file systems is not supported on this platform")
+}
+
+// SystemMountTable corresponds with the mount table of the locally
+// running operating system. On this operating system this
+// functionality is not available.
+var SystemMountTable MountTable = systemMountTable{}
@@ -0,0 +1,91 @@
+//go:build linux
+// +build linux
+
+package cleaner
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type systemMountTable struct{}
+
+// unescapeMountInfoField converts octal escape sequences that are
+// used in /proc/self/mountinfo to represent spaces, tabs, newlines and
+// backslashes back to their original form.
+func unescapeMountInfoField(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func (mt systemMountTable) GetMounts() ([]Mount, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to open /proc/self/mountinfo")
+	}
+	defer f.Close()
+
+	// Every line has the form:
+	//
+	//     36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw
+	//
+	// The fifth field contains the mount point. The file system
+	// type follows the separator, as the number of optional fields
+	// preceding it is variable.
+	var mounts []Mount
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		separator := -1
+		for i := 6; i < len(fields); i++ {
+			if fields[i] == "-" {
+				separator = i
+				break
+			}
+		}
+		if len(fields) < 5 || separator < 0 || separator+1 >= len(fields) {
+			return nil, status.Errorf(codes.Internal, "Malformed line in /proc/self/mountinfo: %#v", scanner.Text())
+		}
+		mounts = append(mounts, Mount{
+			MountPoint:     unescapeMountInfoField(fields[4]),
+			FileSystemType: fields[separator+1],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to read /proc/self/mountinfo")
+	}
+	return mounts, nil
+}
+
+func (mt systemMountTable) Unmount(mountPoint string) error {
+	// Perform a lazy unmount, as stale FUSE and NFS mounts may
+	// still be referenced by processes that are stuck accessing
+	// them.
+	return unix.Unmount(mountPoint, unix.MNT_DETACH)
+}
+
+// SystemMountTable corresponds with the mount table of the locally
+// running operating system. On this operating system the information
+// is extracted from procfs.
+var SystemMountTable MountTable = systemMountTable{}
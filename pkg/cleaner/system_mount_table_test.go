@@ -0,0 +1,28 @@
+package cleaner_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/cleaner"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemMountTable(t *testing.T) {
+	if runtime.GOOS == "freebsd" || runtime.GOOS == "windows" {
+		return
+	}
+
+	mounts, err := cleaner.SystemMountTable.GetMounts()
+	require.NoError(t, err)
+
+	// The root file system should always be mounted.
+	found := false
+	for _, mount := range mounts {
+		if mount.MountPoint == "/" {
+			require.NotEmpty(t, mount.FileSystemType)
+			found = true
+		}
+	}
+	require.True(t, found)
+}
@@ -23,10 +23,18 @@ func (pt systemProcessTable) GetProcesses() ([]Process, error) {
 	processes := make([]Process, 0, len(kinfoProcs))
 	for _, kinfoProc := range kinfoProcs {
 		startTime := kinfoProc.Proc.P_starttime
+		var commandLine []string
+		if comm := unix.ByteSliceToString(kinfoProc.Proc.P_comm[:]); comm != "" {
+			// Obtaining the full command line would require
+			// calling the "kern.procargs2" sysctl for every
+			// process. Only report the executable name.
+			commandLine = []string{comm}
+		}
 		processes = append(processes, Process{
 			ProcessID:    int(kinfoProc.Proc.P_pid),
 			UserID:       int(kinfoProc.Eproc.Ucred.Uid),
 			CreationTime: time.Unix(startTime.Sec, int64(startTime.Usec)*1000),
+			CommandLine:  commandLine,
 		})
 	}
 	return processes, nil
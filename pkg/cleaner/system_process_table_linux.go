@@ -6,6 +6,7 @@ package cleaner
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/buildbarn/bb-storage/pkg/util"
@@ -47,10 +48,18 @@ func (pt systemProcessTable) GetProcesses() ([]Process, error) {
 		} else if err != nil {
 			return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to stat process %d", pid)
 		}
+
+		// The process may terminate at any point in time.
+		// Permit its command line to be absent.
+		var commandLine []string
+		if cmdline, err := os.ReadFile("/proc/" + name + "/cmdline"); err == nil && len(cmdline) > 0 {
+			commandLine = strings.Split(strings.TrimSuffix(string(cmdline), "\x00"), "\x00")
+		}
 		processes = append(processes, Process{
 			ProcessID:    int(pid),
 			UserID:       int(stat.Uid),
 			CreationTime: time.Unix(int64(stat.Ctim.Sec), int64(stat.Ctim.Nsec)),
+			CommandLine:  commandLine,
 		})
 	}
 	return processes, nil
@@ -32,6 +32,9 @@ func TestSystemProcessTable(t *testing.T) {
 			require.Equal(t, os.Getuid(), process.UserID)
 			require.True(t, process.CreationTime.After(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
 			require.False(t, process.CreationTime.After(time.Now()))
+			if runtime.GOOS == "linux" {
+				require.Equal(t, os.Args, process.CommandLine)
+			}
 			break
 		}
 	}
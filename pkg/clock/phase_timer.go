@@ -0,0 +1,107 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/clock"
+)
+
+// Phase of the execution of a build action for which PhaseTimer keeps
+// track of the amount of time spent.
+type Phase int
+
+const (
+	// PhaseDirectoryFetch corresponds to loading Directory messages
+	// from the Content Addressable Storage.
+	PhaseDirectoryFetch Phase = iota
+	// PhaseFileFetch corresponds to loading the contents of input
+	// files from the Content Addressable Storage.
+	PhaseFileFetch
+	// PhaseOutputHashing corresponds to computing the digests of
+	// output files and directories.
+	PhaseOutputHashing
+	// PhaseFindMissingBlobs corresponds to determining which
+	// outputs are absent from the Content Addressable Storage.
+	PhaseFindMissingBlobs
+	// PhaseUpload corresponds to writing outputs into the Content
+	// Addressable Storage.
+	PhaseUpload
+
+	phaseCount
+)
+
+// PhaseTimer accumulates the amount of time spent in the phases of the
+// execution of a single build action. As operations belonging to a
+// phase may run concurrently, the accumulated time of a phase may
+// exceed the wall time of the build action.
+//
+// Instances of PhaseTimer are attached to a Context, so that code that
+// performs the work of individual phases does not need to be aware of
+// the build action that is being executed.
+type PhaseTimer struct {
+	clock clock.Clock
+
+	lock      sync.Mutex
+	durations [phaseCount]time.Duration
+}
+
+// NewPhaseTimer creates a PhaseTimer for which no time has been
+// accumulated yet.
+func NewPhaseTimer(clock clock.Clock) *PhaseTimer {
+	return &PhaseTimer{
+		clock: clock,
+	}
+}
+
+// GetDuration returns the amount of time accumulated for a phase.
+func (pt *PhaseTimer) GetDuration(phase Phase) time.Duration {
+	pt.lock.Lock()
+	defer pt.lock.Unlock()
+	return pt.durations[phase]
+}
+
+type phaseTimerKey struct{}
+
+// NewContextWithPhaseTimer returns a Context to which a PhaseTimer is
+// attached. Time spent in operations called with this context is
+// accumulated into the PhaseTimer.
+func NewContextWithPhaseTimer(ctx context.Context, pt *PhaseTimer) context.Context {
+	return context.WithValue(ctx, phaseTimerKey{}, pt)
+}
+
+// StartPhase starts measuring the duration of an operation belonging
+// to a phase. Finish() needs to be called on the returned measurement
+// when the operation completes. If no PhaseTimer is attached to the
+// Context, no time is accumulated.
+func StartPhase(ctx context.Context, phase Phase) PhaseMeasurement {
+	pt, ok := ctx.Value(phaseTimerKey{}).(*PhaseTimer)
+	if !ok {
+		return PhaseMeasurement{}
+	}
+	return PhaseMeasurement{
+		timer: pt,
+		phase: phase,
+		start: pt.clock.Now(),
+	}
+}
+
+// PhaseMeasurement is returned by StartPhase() to measure the duration
+// of a single operation.
+type PhaseMeasurement struct {
+	timer *PhaseTimer
+	phase Phase
+	start time.Time
+}
+
+// Finish measuring the duration of an operation, and add it to the
+// PhaseTimer.
+func (pm PhaseMeasurement) Finish() {
+	if pt := pm.timer; pt != nil {
+		d := pt.clock.Now().Sub(pm.start)
+		pt.lock.Lock()
+		pt.durations[pm.phase] += d
+		pt.lock.Unlock()
+	}
+}
@@ -0,0 +1,48 @@
+package clock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/clock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhaseTimer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	baseClock := mock.NewMockClock(ctrl)
+	phaseTimer := clock.NewPhaseTimer(baseClock)
+	ctx := clock.NewContextWithPhaseTimer(context.Background(), phaseTimer)
+
+	t.Run("NoPhaseTimer", func(t *testing.T) {
+		// Measurements against a context that has no PhaseTimer
+		// attached to it should be ignored.
+		clock.StartPhase(context.Background(), clock.PhaseUpload).Finish()
+	})
+
+	t.Run("Accumulation", func(t *testing.T) {
+		// Durations of overlapping operations belonging to the
+		// same phase should be summed.
+		baseClock.EXPECT().Now().Return(time.Unix(1000, 0))
+		m1 := clock.StartPhase(ctx, clock.PhaseFileFetch)
+		baseClock.EXPECT().Now().Return(time.Unix(1001, 0))
+		m2 := clock.StartPhase(ctx, clock.PhaseFileFetch)
+		baseClock.EXPECT().Now().Return(time.Unix(1003, 0))
+		m1.Finish()
+		baseClock.EXPECT().Now().Return(time.Unix(1004, 0))
+		m2.Finish()
+
+		baseClock.EXPECT().Now().Return(time.Unix(1010, 0))
+		m3 := clock.StartPhase(ctx, clock.PhaseUpload)
+		baseClock.EXPECT().Now().Return(time.Unix(1015, 0))
+		m3.Finish()
+
+		require.Equal(t, 6*time.Second, phaseTimer.GetDuration(clock.PhaseFileFetch))
+		require.Equal(t, 5*time.Second, phaseTimer.GetDuration(clock.PhaseUpload))
+		require.Equal(t, time.Duration(0), phaseTimer.GetDuration(clock.PhaseDirectoryFetch))
+	})
+}
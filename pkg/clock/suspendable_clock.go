@@ -22,6 +22,13 @@ type Suspendable interface {
 // Context.
 type UnsuspendedDurationKey struct{}
 
+// SuspendedDurationKey instances can be provided to Context.Value() to
+// obtain the total amount of time a SuspendableClock associated with
+// the Context object was suspended, since the creation of the Context.
+// This corresponds to the amount of time by which the timeout of the
+// Context was extended.
+type SuspendedDurationKey struct{}
+
 // SuspendableClock is a decorator for Clock that allows Timers and
 // Contexts with timeouts to be suspended temporarily. This decorator
 // can, for example, be used to let FUSE-based workers compensate the
@@ -142,7 +149,8 @@ func (c *SuspendableClock) NewContextWithTimeout(parent context.Context, d time.
 	// completion.
 	c.lock.Lock()
 	go func() {
-		initialTotalUnsuspended := c.getTotalUnsuspendedNow()
+		startTime := c.base.Now()
+		initialTotalUnsuspended := c.getTotalUnsuspendedWithTime(startTime)
 		finalTotalUnsuspended := initialTotalUnsuspended + d
 		for {
 			c.lock.Unlock()
@@ -158,7 +166,7 @@ func (c *SuspendableClock) NewContextWithTimeout(parent context.Context, d time.
 					// the meantime is not worth
 					// creating another timer for.
 					ctx.err = context.DeadlineExceeded
-					ctx.unsuspendedDuration = currentTotalUnsuspended - initialTotalUnsuspended
+					ctx.setDurations(startTime, now, currentTotalUnsuspended-initialTotalUnsuspended)
 					c.lock.Unlock()
 					close(doneChannel)
 					return
@@ -167,7 +175,8 @@ func (c *SuspendableClock) NewContextWithTimeout(parent context.Context, d time.
 				// Base context got canceled.
 				c.lock.Lock()
 				ctx.err = baseContext.Err()
-				ctx.unsuspendedDuration = c.getTotalUnsuspendedNow() - initialTotalUnsuspended
+				now := c.base.Now()
+				ctx.setDurations(startTime, now, c.getTotalUnsuspendedWithTime(now)-initialTotalUnsuspended)
 				c.lock.Unlock()
 				baseTimer.Stop()
 				close(doneChannel)
@@ -244,6 +253,17 @@ type suspendableContext struct {
 	lock                *sync.Mutex
 	err                 error
 	unsuspendedDuration time.Duration
+	suspendedDuration   time.Duration
+}
+
+// setDurations stores the amount of time the Context was active,
+// split up in the time during which the SuspendableClock was and was
+// not suspended.
+func (ctx *suspendableContext) setDurations(startTime, endTime time.Time, unsuspendedDuration time.Duration) {
+	ctx.unsuspendedDuration = unsuspendedDuration
+	if suspendedDuration := endTime.Sub(startTime) - unsuspendedDuration; suspendedDuration > 0 {
+		ctx.suspendedDuration = suspendedDuration
+	}
 }
 
 func (ctx *suspendableContext) Done() <-chan struct{} {
@@ -258,14 +278,18 @@ func (ctx *suspendableContext) Err() error {
 }
 
 func (ctx *suspendableContext) Value(key interface{}) interface{} {
-	if key != (UnsuspendedDurationKey{}) {
+	switch key {
+	case UnsuspendedDurationKey{}:
+		ctx.lock.Lock()
+		defer ctx.lock.Unlock()
+		return ctx.unsuspendedDuration
+	case SuspendedDurationKey{}:
+		ctx.lock.Lock()
+		defer ctx.lock.Unlock()
+		return ctx.suspendedDuration
+	default:
 		return ctx.Context.Value(key)
 	}
-
-	ctx.lock.Lock()
-	defer ctx.lock.Unlock()
-
-	return ctx.unsuspendedDuration
 }
 
 // suspendableTimer is the implementation of Timer that is returned by
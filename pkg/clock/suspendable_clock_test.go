@@ -59,6 +59,7 @@ func TestSuspendableClockNewContextWithTimeout(t *testing.T) {
 		suspendableCancel()
 
 		require.Equal(t, 5*time.Second, suspendableContext.Value(clock.UnsuspendedDurationKey{}))
+		require.Equal(t, time.Duration(0), suspendableContext.Value(clock.SuspendedDurationKey{}))
 	})
 
 	t.Run("Canceled", func(t *testing.T) {
@@ -93,6 +94,7 @@ func TestSuspendableClockNewContextWithTimeout(t *testing.T) {
 		require.Equal(t, context.Canceled, suspendableContext.Err())
 
 		require.Equal(t, 3*time.Second, suspendableContext.Value(clock.UnsuspendedDurationKey{}))
+		require.Equal(t, time.Duration(0), suspendableContext.Value(clock.SuspendedDurationKey{}))
 	})
 
 	t.Run("Suspension", func(t *testing.T) {
@@ -144,6 +146,10 @@ func TestSuspendableClockNewContextWithTimeout(t *testing.T) {
 		suspendableCancel()
 
 		require.Equal(t, 5*time.Second, suspendableContext.Value(clock.UnsuspendedDurationKey{}))
+
+		// The timeout was extended by the one second during
+		// which the clock was suspended.
+		require.Equal(t, time.Second, suspendableContext.Value(clock.SuspendedDurationKey{}))
 	})
 }
 
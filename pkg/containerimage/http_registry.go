@@ -0,0 +1,341 @@
+package containerimage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	mediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+	// Manifests are small, so there is no need to accept
+	// arbitrarily large responses.
+	maximumManifestSizeBytes = 4 * 1024 * 1024
+)
+
+var manifestMediaTypes = strings.Join([]string{
+	mediaTypeOCIManifest,
+	mediaTypeOCIIndex,
+	mediaTypeDockerManifest,
+	mediaTypeDockerManifestList,
+}, ", ")
+
+// manifest contains the fields of image manifests and image indices
+// that are needed to obtain the layers of an image.
+type manifest struct {
+	Layers    []Descriptor `json:"layers"`
+	Manifests []struct {
+		Descriptor
+		Platform struct {
+			Architecture string `json:"architecture"`
+			OS           string `json:"os"`
+		} `json:"platform"`
+	} `json:"manifests"`
+}
+
+type httpRegistry struct {
+	client             http.Client
+	insecureRegistries map[string]struct{}
+
+	lock   sync.Mutex
+	tokens map[string]string
+}
+
+// NewHTTPRegistry creates a Registry that downloads container images
+// using the OCI Distribution API. Anonymous bearer token
+// authentication, as used by Docker Hub, is performed automatically.
+// Credentials for private registries may be provided by using a
+// RoundTripper that adds an Authorization header.
+//
+// Registries are contacted over HTTPS, except for the ones that are
+// listed as insecure.
+func NewHTTPRegistry(roundTripper http.RoundTripper, insecureRegistries []string) Registry {
+	r := &httpRegistry{
+		client: http.Client{
+			Transport: roundTripper,
+		},
+		insecureRegistries: map[string]struct{}{},
+		tokens:             map[string]string{},
+	}
+	for _, registry := range insecureRegistries {
+		r.insecureRegistries[registry] = struct{}{}
+	}
+	return r
+}
+
+func (r *httpRegistry) getURL(reference ImageReference, suffix string) string {
+	scheme := "https"
+	if _, ok := r.insecureRegistries[reference.Registry]; ok {
+		scheme = "http"
+	}
+	return scheme + "://" + reference.Registry + "/v2/" + reference.Repository + "/" + suffix
+}
+
+// do sends a request to the registry. If the registry requests that
+// the client authenticates using a bearer token, a token is obtained
+// and the request is retried.
+func (r *httpRegistry) do(ctx context.Context, reference ImageReference, method, suffix, accept string) (*http.Response, error) {
+	tokenKey := reference.Registry + "/" + reference.Repository
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, r.getURL(reference, suffix), nil)
+		if err != nil {
+			return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to create HTTP request")
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		r.lock.Lock()
+		token, ok := r.tokens[tokenKey]
+		r.lock.Unlock()
+		if ok {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return nil, util.StatusWrapWithCode(err, codes.Unavailable, "HTTP request failed")
+		}
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			resp.Body.Close()
+			token, err := r.getToken(ctx, reference, challenge)
+			if err != nil {
+				return nil, util.StatusWrap(err, "Failed to obtain bearer token")
+			}
+			r.lock.Lock()
+			r.tokens[tokenKey] = token
+			r.lock.Unlock()
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, status.Errorf(convertHTTPStatusCode(resp.StatusCode), "Registry returned HTTP status %#v", resp.Status)
+		}
+		return resp, nil
+	}
+}
+
+// getToken obtains a bearer token from the authorization service that
+// is referenced by a WWW-Authenticate header.
+func (r *httpRegistry) getToken(ctx context.Context, reference ImageReference, challenge string) (string, error) {
+	scheme, parameters := parseChallenge(challenge)
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", status.Errorf(codes.PermissionDenied, "Registry requested unsupported authentication scheme %#v", scheme)
+	}
+	realm, ok := parameters["realm"]
+	if !ok {
+		return "", status.Error(codes.PermissionDenied, "Registry did not provide an authentication realm")
+	}
+	realmURL, err := url.Parse(realm)
+	if err != nil {
+		return "", util.StatusWrapWithCode(err, codes.PermissionDenied, "Invalid authentication realm")
+	}
+	query := realmURL.Query()
+	if service, ok := parameters["service"]; ok {
+		query.Set("service", service)
+	}
+	scope, ok := parameters["scope"]
+	if !ok {
+		scope = "repository:" + reference.Repository + ":pull"
+	}
+	query.Set("scope", scope)
+	realmURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realmURL.String(), nil)
+	if err != nil {
+		return "", util.StatusWrapWithCode(err, codes.Internal, "Failed to create HTTP request")
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", util.StatusWrapWithCode(err, codes.Unavailable, "HTTP request failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", status.Errorf(convertHTTPStatusCode(resp.StatusCode), "Authorization service returned HTTP status %#v", resp.Status)
+	}
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maximumManifestSizeBytes)).Decode(&tokenResponse); err != nil {
+		return "", util.StatusWrapWithCode(err, codes.PermissionDenied, "Failed to parse token response")
+	}
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+	if tokenResponse.AccessToken != "" {
+		return tokenResponse.AccessToken, nil
+	}
+	return "", status.Error(codes.PermissionDenied, "Token response does not contain a token")
+}
+
+func (r *httpRegistry) GetManifestDigest(ctx context.Context, reference ImageReference) (string, error) {
+	if reference.IsDigest() {
+		return reference.Reference, nil
+	}
+	resp, err := r.do(ctx, reference, http.MethodHead, "manifests/"+reference.Reference, manifestMediaTypes)
+	if err != nil {
+		return "", util.StatusWrapf(err, "Failed to obtain manifest of image %#v", reference.String())
+	}
+	resp.Body.Close()
+	manifestDigest := resp.Header.Get("Docker-Content-Digest")
+	if manifestDigest == "" {
+		return "", status.Errorf(codes.Unimplemented, "Registry did not return the digest of the manifest of image %#v", reference.String())
+	}
+	return manifestDigest, nil
+}
+
+func (r *httpRegistry) GetLayers(ctx context.Context, reference ImageReference) ([]Descriptor, error) {
+	m, err := r.getManifest(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+	if len(m.Manifests) == 0 {
+		return m.Layers, nil
+	}
+
+	// The reference refers to an image index. Pick the image that
+	// corresponds to the current platform.
+	for _, platformManifest := range m.Manifests {
+		if platformManifest.Platform.OS == runtime.GOOS && platformManifest.Platform.Architecture == runtime.GOARCH {
+			m, err := r.getManifest(ctx, reference.WithDigest(platformManifest.Digest))
+			if err != nil {
+				return nil, err
+			}
+			if len(m.Manifests) > 0 {
+				return nil, status.Errorf(codes.InvalidArgument, "Image index of image %#v refers to another image index", reference.String())
+			}
+			return m.Layers, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "Image %#v is not available for platform %s/%s", reference.String(), runtime.GOOS, runtime.GOARCH)
+}
+
+func (r *httpRegistry) getManifest(ctx context.Context, reference ImageReference) (*manifest, error) {
+	resp, err := r.do(ctx, reference, http.MethodGet, "manifests/"+reference.Reference, manifestMediaTypes)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to obtain manifest of image %#v", reference.String())
+	}
+	defer resp.Body.Close()
+
+	var body io.Reader = io.LimitReader(resp.Body, maximumManifestSizeBytes)
+	if reference.IsDigest() {
+		verifyingReader, err := newDigestVerifyingReader(io.NopCloser(body), reference.Reference)
+		if err != nil {
+			return nil, err
+		}
+		body = verifyingReader
+	}
+	var m manifest
+	if err := json.NewDecoder(body).Decode(&m); err != nil {
+		return nil, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Failed to parse manifest of image %#v", reference.String())
+	}
+	// Read any trailing data, so that the digest is validated.
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return nil, util.StatusWrapf(err, "Failed to read manifest of image %#v", reference.String())
+	}
+	return &m, nil
+}
+
+func (r *httpRegistry) GetBlob(ctx context.Context, reference ImageReference, descriptor Descriptor) (io.ReadCloser, error) {
+	resp, err := r.do(ctx, reference, http.MethodGet, "blobs/"+descriptor.Digest, "")
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to obtain blob %#v", descriptor.Digest)
+	}
+	verifyingReader, err := newDigestVerifyingReader(resp.Body, descriptor.Digest)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return verifyingReader, nil
+}
+
+// digestVerifyingReader validates that the data read from a registry
+// matches the expected digest. An error is returned upon reaching the
+// end of the stream if the data is corrupted.
+type digestVerifyingReader struct {
+	io.ReadCloser
+	hasher       hash.Hash
+	expectedHash string
+}
+
+func newDigestVerifyingReader(r io.ReadCloser, expectedDigest string) (io.ReadCloser, error) {
+	algorithm, expectedHash, _ := strings.Cut(expectedDigest, ":")
+	if algorithm != "sha256" {
+		return nil, status.Errorf(codes.Unimplemented, "Unsupported digest algorithm in digest %#v", expectedDigest)
+	}
+	return &digestVerifyingReader{
+		ReadCloser:   r,
+		hasher:       sha256.New(),
+		expectedHash: expectedHash,
+	}, nil
+}
+
+func (r *digestVerifyingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hasher.Write(p[:n])
+	if err == io.EOF {
+		if actualHash := hex.EncodeToString(r.hasher.Sum(nil)); actualHash != r.expectedHash {
+			return n, status.Errorf(codes.DataLoss, "Data has hash %#v, while %#v was expected", actualHash, r.expectedHash)
+		}
+	}
+	return n, err
+}
+
+// parseChallenge parses the value of a WWW-Authenticate header into
+// the authentication scheme and its parameters.
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	parameters := map[string]string{}
+	for rest != "" {
+		var key string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		var value string
+		if strings.HasPrefix(rest, "\"") {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key = strings.TrimSpace(key); key != "" {
+			parameters[strings.ToLower(key)] = value
+		}
+	}
+	return scheme, parameters
+}
+
+func convertHTTPStatusCode(code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return codes.Unavailable
+	default:
+		return codes.Unknown
+	}
+}
@@ -0,0 +1,94 @@
+package containerimage
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultRegistry  = "registry-1.docker.io"
+	defaultTag       = "latest"
+	dockerURLPrefix  = "docker://"
+	officialImageOrg = "library/"
+)
+
+// ImageReference identifies a container image stored in a registry.
+type ImageReference struct {
+	// Host name and optional port number of the registry (e.g.,
+	// "gcr.io" or "localhost:5000").
+	Registry string
+	// Name of the repository within the registry (e.g.,
+	// "library/ubuntu").
+	Repository string
+	// Tag (e.g., "22.04") or digest (e.g., "sha256:0123...cdef") of
+	// the image manifest.
+	Reference string
+}
+
+// NewImageReference parses a container image reference, using the
+// syntax that is also accepted by "docker pull". References may
+// optionally be prefixed with "docker://", which is the convention
+// used by the "container-image" platform property of Bazel's remote
+// execution setups.
+func NewImageReference(s string) (ImageReference, error) {
+	name := strings.TrimPrefix(s, dockerURLPrefix)
+	if name == "" {
+		return ImageReference{}, status.Error(codes.InvalidArgument, "Image reference is empty")
+	}
+
+	// Split off the digest or tag.
+	var reference string
+	if i := strings.IndexByte(name, '@'); i >= 0 {
+		name, reference = name[:i], name[i+1:]
+		if !strings.Contains(reference, ":") {
+			return ImageReference{}, status.Errorf(codes.InvalidArgument, "Image reference %#v contains an invalid digest", s)
+		}
+	} else if i := strings.LastIndexByte(name, ':'); i > strings.LastIndexByte(name, '/') {
+		name, reference = name[:i], name[i+1:]
+	} else {
+		reference = defaultTag
+	}
+
+	// The first component of the name is only a registry if it
+	// looks like a host name. Otherwise the image is stored on
+	// Docker Hub.
+	registry := defaultRegistry
+	if i := strings.IndexByte(name, '/'); i >= 0 {
+		if first := name[:i]; strings.ContainsAny(first, ".:") || first == "localhost" {
+			registry, name = first, name[i+1:]
+		}
+	}
+	if registry == defaultRegistry && !strings.Contains(name, "/") {
+		name = officialImageOrg + name
+	}
+	if name == "" || reference == "" {
+		return ImageReference{}, status.Errorf(codes.InvalidArgument, "Image reference %#v is incomplete", s)
+	}
+	return ImageReference{
+		Registry:   registry,
+		Repository: name,
+		Reference:  reference,
+	}, nil
+}
+
+// IsDigest returns true if the image reference refers to a manifest by
+// digest, as opposed to by tag.
+func (r ImageReference) IsDigest() bool {
+	return strings.Contains(r.Reference, ":")
+}
+
+// WithDigest returns a copy of the image reference that refers to a
+// manifest by digest.
+func (r ImageReference) WithDigest(manifestDigest string) ImageReference {
+	r.Reference = manifestDigest
+	return r
+}
+
+func (r ImageReference) String() string {
+	if r.IsDigest() {
+		return r.Registry + "/" + r.Repository + "@" + r.Reference
+	}
+	return r.Registry + "/" + r.Repository + ":" + r.Reference
+}
@@ -0,0 +1,70 @@
+package containerimage_test
+
+import (
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/containerimage"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewImageReference(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		_, err := containerimage.NewImageReference("docker://")
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Image reference is empty"), err)
+	})
+
+	t.Run("OfficialImage", func(t *testing.T) {
+		// Images without a registry and organization are
+		// official images stored on Docker Hub.
+		reference, err := containerimage.NewImageReference("ubuntu")
+		require.NoError(t, err)
+		require.Equal(t, containerimage.ImageReference{
+			Registry:   "registry-1.docker.io",
+			Repository: "library/ubuntu",
+			Reference:  "latest",
+		}, reference)
+		require.False(t, reference.IsDigest())
+	})
+
+	t.Run("DockerHubWithTag", func(t *testing.T) {
+		reference, err := containerimage.NewImageReference("buildbarn/bb-runner-installer:20231222")
+		require.NoError(t, err)
+		require.Equal(t, containerimage.ImageReference{
+			Registry:   "registry-1.docker.io",
+			Repository: "buildbarn/bb-runner-installer",
+			Reference:  "20231222",
+		}, reference)
+	})
+
+	t.Run("RegistryWithPort", func(t *testing.T) {
+		// Port numbers should not be confused with tags.
+		reference, err := containerimage.NewImageReference("localhost:5000/toolchains/clang")
+		require.NoError(t, err)
+		require.Equal(t, containerimage.ImageReference{
+			Registry:   "localhost:5000",
+			Repository: "toolchains/clang",
+			Reference:  "latest",
+		}, reference)
+	})
+
+	t.Run("DockerURLWithDigest", func(t *testing.T) {
+		reference, err := containerimage.NewImageReference("docker://gcr.io/project/image@sha256:76a7a6a8fdc7ac0b2d9d5a6b2fe2a0f6b8c9c59a6a6e4db0b79f7d3d8b27f9a1")
+		require.NoError(t, err)
+		require.Equal(t, containerimage.ImageReference{
+			Registry:   "gcr.io",
+			Repository: "project/image",
+			Reference:  "sha256:76a7a6a8fdc7ac0b2d9d5a6b2fe2a0f6b8c9c59a6a6e4db0b79f7d3d8b27f9a1",
+		}, reference)
+		require.True(t, reference.IsDigest())
+		require.Equal(t, "gcr.io/project/image@sha256:76a7a6a8fdc7ac0b2d9d5a6b2fe2a0f6b8c9c59a6a6e4db0b79f7d3d8b27f9a1", reference.String())
+	})
+
+	t.Run("InvalidDigest", func(t *testing.T) {
+		_, err := containerimage.NewImageReference("gcr.io/project/image@latest")
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Image reference \"gcr.io/project/image@latest\" contains an invalid digest"), err)
+	})
+}
@@ -0,0 +1,273 @@
+package containerimage
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/klauspost/compress/zstd"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	whiteoutPrefix       = ".wh."
+	opaqueWhiteoutMarker = ".wh..wh..opq"
+)
+
+// flattenedDirectory is an in-memory representation of a directory
+// in the root file system of a container image. Files are stored in
+// the form of FileNodes, as their contents are uploaded into the
+// Content Addressable Storage while layers are being read.
+//
+// While a single layer is being read, opaque whiteouts and whiteouts
+// are recorded, so that they can be applied when the layer is placed
+// on top of the layers below it.
+type flattenedDirectory struct {
+	directories map[string]*flattenedDirectory
+	files       map[string]*remoteexecution.FileNode
+	symlinks    map[string]string
+
+	opaque    bool
+	whiteouts map[string]struct{}
+}
+
+func newFlattenedDirectory() *flattenedDirectory {
+	return &flattenedDirectory{
+		directories: map[string]*flattenedDirectory{},
+		files:       map[string]*remoteexecution.FileNode{},
+		symlinks:    map[string]string{},
+		whiteouts:   map[string]struct{}{},
+	}
+}
+
+func (d *flattenedDirectory) remove(name string) {
+	delete(d.directories, name)
+	delete(d.files, name)
+	delete(d.symlinks, name)
+}
+
+// getOrCreateDirectory returns the directory at a given path,
+// creating it and any of its parents if they do not exist. Files and
+// symbolic links in the way are replaced.
+func (d *flattenedDirectory) getOrCreateDirectory(components []string) *flattenedDirectory {
+	for _, component := range components {
+		child, ok := d.directories[component]
+		if !ok {
+			d.remove(component)
+			child = newFlattenedDirectory()
+			d.directories[component] = child
+		}
+		d = child
+	}
+	return d
+}
+
+// lookupFile returns the FileNode of a regular file at a given path.
+func (d *flattenedDirectory) lookupFile(components []string) (*remoteexecution.FileNode, bool) {
+	for _, component := range components[:len(components)-1] {
+		child, ok := d.directories[component]
+		if !ok {
+			return nil, false
+		}
+		d = child
+	}
+	fileNode, ok := d.files[components[len(components)-1]]
+	return fileNode, ok
+}
+
+// applyLayer places the contents of a layer on top of this
+// directory, applying any whiteouts contained in the layer.
+func (d *flattenedDirectory) applyLayer(layer *flattenedDirectory) {
+	if layer.opaque {
+		d.directories = map[string]*flattenedDirectory{}
+		d.files = map[string]*remoteexecution.FileNode{}
+		d.symlinks = map[string]string{}
+	}
+	for name := range layer.whiteouts {
+		d.remove(name)
+	}
+	for name, fileNode := range layer.files {
+		d.remove(name)
+		d.files[name] = fileNode
+	}
+	for name, target := range layer.symlinks {
+		d.remove(name)
+		d.symlinks[name] = target
+	}
+	for name, layerChild := range layer.directories {
+		if child, ok := d.directories[name]; ok {
+			child.applyLayer(layerChild)
+		} else {
+			d.remove(name)
+			child := newFlattenedDirectory()
+			child.applyLayer(layerChild)
+			d.directories[name] = child
+		}
+	}
+}
+
+// upload the Directory messages of this directory and all of its
+// children into the Content Addressable Storage, returning the digest
+// of this directory.
+func (d *flattenedDirectory) upload(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, digestFunction digest.Function) (digest.Digest, error) {
+	var directory remoteexecution.Directory
+	for name, child := range d.directories {
+		childDigest, err := child.upload(ctx, contentAddressableStorage, digestFunction)
+		if err != nil {
+			return digest.BadDigest, err
+		}
+		directory.Directories = append(directory.Directories, &remoteexecution.DirectoryNode{
+			Name:   name,
+			Digest: childDigest.GetProto(),
+		})
+	}
+	for name, fileNode := range d.files {
+		fileNode := proto.Clone(fileNode).(*remoteexecution.FileNode)
+		fileNode.Name = name
+		directory.Files = append(directory.Files, fileNode)
+	}
+	for name, target := range d.symlinks {
+		directory.Symlinks = append(directory.Symlinks, &remoteexecution.SymlinkNode{
+			Name:   name,
+			Target: target,
+		})
+	}
+	sort.Slice(directory.Directories, func(i, j int) bool {
+		return directory.Directories[i].Name < directory.Directories[j].Name
+	})
+	sort.Slice(directory.Files, func(i, j int) bool {
+		return directory.Files[i].Name < directory.Files[j].Name
+	})
+	sort.Slice(directory.Symlinks, func(i, j int) bool {
+		return directory.Symlinks[i].Name < directory.Symlinks[j].Name
+	})
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(&directory)
+	if err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to marshal directory")
+	}
+	directoryDigest := computeDigest(digestFunction, data)
+	if err := contentAddressableStorage.Put(ctx, directoryDigest, buffer.NewValidatedBufferFromByteSlice(data)); err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to store directory")
+	}
+	return directoryDigest, nil
+}
+
+func computeDigest(digestFunction digest.Function, data []byte) digest.Digest {
+	digestGenerator := digestFunction.NewGenerator(int64(len(data)))
+	if _, err := digestGenerator.Write(data); err != nil {
+		panic(err)
+	}
+	return digestGenerator.Sum()
+}
+
+// newLayerReader decompresses a layer based on its media type.
+func newLayerReader(r io.Reader, mediaType string) (io.Reader, func(), error) {
+	switch {
+	case strings.HasSuffix(mediaType, "+gzip") || strings.HasSuffix(mediaType, ".gzip"):
+		gzipReader, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to create gzip reader")
+		}
+		return gzipReader, func() { gzipReader.Close() }, nil
+	case strings.HasSuffix(mediaType, "+zstd") || strings.HasSuffix(mediaType, ".zstd"):
+		zstdReader, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to create zstd reader")
+		}
+		return zstdReader, zstdReader.Close, nil
+	case strings.HasSuffix(mediaType, ".tar") || strings.HasSuffix(mediaType, ".tar.v1") || strings.HasSuffix(mediaType, ".tar.v2"):
+		return r, func() {}, nil
+	default:
+		return nil, nil, status.Errorf(codes.InvalidArgument, "Unsupported layer media type %#v", mediaType)
+	}
+}
+
+// readLayer reads the contents of a single layer in tar format,
+// uploading the contents of all regular files into the Content
+// Addressable Storage. The lower layers are needed to resolve hard
+// links to files that are not part of the layer itself.
+func readLayer(ctx context.Context, r io.Reader, lower *flattenedDirectory, contentAddressableStorage blobstore.BlobAccess, digestFunction digest.Function) (*flattenedDirectory, error) {
+	layer := newFlattenedDirectory()
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return layer, nil
+		} else if err != nil {
+			return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to read tar header")
+		}
+
+		// Normalize the path, so that it does not escape the
+		// root directory.
+		cleanPath := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+		if cleanPath == "" {
+			continue
+		}
+		components := strings.Split(cleanPath, "/")
+		parent := layer.getOrCreateDirectory(components[:len(components)-1])
+		name := components[len(components)-1]
+
+		if name == opaqueWhiteoutMarker {
+			parent.opaque = true
+			continue
+		}
+		if strings.HasPrefix(name, whiteoutPrefix) {
+			whitedOutName := strings.TrimPrefix(name, whiteoutPrefix)
+			parent.remove(whitedOutName)
+			parent.whiteouts[whitedOutName] = struct{}{}
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeReg:
+			data, err := io.ReadAll(tarReader)
+			if err != nil {
+				return nil, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Failed to read file %#v", cleanPath)
+			}
+			fileDigest := computeDigest(digestFunction, data)
+			if err := contentAddressableStorage.Put(ctx, fileDigest, buffer.NewValidatedBufferFromByteSlice(data)); err != nil {
+				return nil, util.StatusWrapf(err, "Failed to store file %#v", cleanPath)
+			}
+			parent.remove(name)
+			parent.files[name] = &remoteexecution.FileNode{
+				Digest:       fileDigest.GetProto(),
+				IsExecutable: header.Mode&0o111 != 0,
+			}
+		case tar.TypeDir:
+			layer.getOrCreateDirectory(components)
+		case tar.TypeSymlink:
+			parent.remove(name)
+			parent.symlinks[name] = header.Linkname
+		case tar.TypeLink:
+			targetPath := strings.TrimPrefix(path.Clean("/"+header.Linkname), "/")
+			if targetPath == "" {
+				return nil, status.Errorf(codes.InvalidArgument, "Hard link %#v has an invalid target", cleanPath)
+			}
+			targetComponents := strings.Split(targetPath, "/")
+			fileNode, ok := layer.lookupFile(targetComponents)
+			if !ok {
+				if fileNode, ok = lower.lookupFile(targetComponents); !ok {
+					return nil, status.Errorf(codes.InvalidArgument, "Hard link %#v refers to non-existent file %#v", cleanPath, targetPath)
+				}
+			}
+			parent.remove(name)
+			parent.files[name] = fileNode
+		default:
+			// Device nodes and FIFOs cannot be represented
+			// in an REv2 Merkle tree.
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package containerimage
+
+import (
+	"context"
+	"io"
+)
+
+// Descriptor of a blob stored in a container registry, such as an
+// image manifest or a layer.
+type Descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// Registry of container images, from which manifests and layers can be
+// downloaded.
+type Registry interface {
+	// GetManifestDigest resolves an image reference to the digest of
+	// its manifest. For references that already contain a digest,
+	// this function may return the digest without contacting the
+	// registry.
+	GetManifestDigest(ctx context.Context, reference ImageReference) (string, error)
+
+	// GetLayers returns the descriptors of the layers of an image,
+	// ordered from the bottom layer to the top layer. If the
+	// reference refers to an image index, the image that matches the
+	// platform of the current process is selected.
+	GetLayers(ctx context.Context, reference ImageReference) ([]Descriptor, error)
+
+	// GetBlob downloads the contents of a blob, such as a layer.
+	// The contents of the blob are validated against the digest in
+	// the descriptor while being read.
+	GetBlob(ctx context.Context, reference ImageReference, descriptor Descriptor) (io.ReadCloser, error)
+}
@@ -0,0 +1,181 @@
+package containerimage
+
+import (
+	"context"
+	"sync"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_blobstore "github.com/buildbarn/bb-remote-execution/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// Resolver of container images to REv2 directory trees that contain
+// the root file system of the image.
+type Resolver interface {
+	Resolve(ctx context.Context, digestFunction digest.Function, imageReference string) (digest.Digest, error)
+}
+
+type conversionKey struct {
+	instanceName   string
+	digestFunction remoteexecution.DigestFunction_Value
+	manifestDigest string
+}
+
+// conversion of a single container image. Conversions are shared
+// between concurrent callers, so that every image is only converted
+// once.
+type conversion struct {
+	done       chan struct{}
+	rootDigest digest.Digest
+	err        error
+}
+
+type registryResolver struct {
+	registry                  Registry
+	contentAddressableStorage blobstore.BlobAccess
+	uploadBatchSize           int
+	uploadConcurrency         int64
+
+	lock        sync.Mutex
+	conversions map[conversionKey]*conversion
+}
+
+// NewRegistryResolver creates a Resolver that downloads container
+// images from a registry, flattens their layers and stores the
+// resulting directory tree in the Content Addressable Storage.
+//
+// Results are cached, keyed by the digest of the image manifest. This
+// means that images referenced by tag are converted again when the tag
+// is updated. Cached results are discarded when the root directory is
+// no longer present in the Content Addressable Storage.
+func NewRegistryResolver(registry Registry, contentAddressableStorage blobstore.BlobAccess, uploadBatchSize int, uploadConcurrency int64) Resolver {
+	return &registryResolver{
+		registry:                  registry,
+		contentAddressableStorage: contentAddressableStorage,
+		uploadBatchSize:           uploadBatchSize,
+		uploadConcurrency:         uploadConcurrency,
+		conversions:               map[conversionKey]*conversion{},
+	}
+}
+
+func (r *registryResolver) Resolve(ctx context.Context, digestFunction digest.Function, imageReference string) (digest.Digest, error) {
+	reference, err := NewImageReference(imageReference)
+	if err != nil {
+		return digest.BadDigest, err
+	}
+	manifestDigest, err := r.registry.GetManifestDigest(ctx, reference)
+	if err != nil {
+		return digest.BadDigest, err
+	}
+	reference = reference.WithDigest(manifestDigest)
+	key := conversionKey{
+		instanceName:   digestFunction.GetInstanceName().String(),
+		digestFunction: digestFunction.GetEnumValue(),
+		manifestDigest: manifestDigest,
+	}
+
+	for {
+		r.lock.Lock()
+		c, ok := r.conversions[key]
+		if !ok {
+			c = &conversion{done: make(chan struct{})}
+			r.conversions[key] = c
+			r.lock.Unlock()
+
+			c.rootDigest, c.err = r.convert(ctx, digestFunction, reference)
+			close(c.done)
+			if c.err != nil {
+				r.removeConversion(key, c)
+			}
+			return c.rootDigest, c.err
+		}
+		r.lock.Unlock()
+
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return digest.BadDigest, util.StatusFromContext(ctx)
+		}
+		if c.err != nil {
+			// The conversion performed by another caller
+			// failed. Retry it.
+			r.removeConversion(key, c)
+			continue
+		}
+
+		// Only return a cached result if it is still present
+		// in the Content Addressable Storage.
+		missing, err := r.contentAddressableStorage.FindMissing(ctx, c.rootDigest.ToSingletonSet())
+		if err != nil {
+			return digest.BadDigest, util.StatusWrap(err, "Failed to check existence of root directory of container image")
+		}
+		if missing.Empty() {
+			return c.rootDigest, nil
+		}
+		r.removeConversion(key, c)
+	}
+}
+
+func (r *registryResolver) removeConversion(key conversionKey, c *conversion) {
+	r.lock.Lock()
+	if r.conversions[key] == c {
+		delete(r.conversions, key)
+	}
+	r.lock.Unlock()
+}
+
+// convert the layers of a container image to a directory tree in the
+// Content Addressable Storage.
+func (r *registryResolver) convert(ctx context.Context, digestFunction digest.Function, reference ImageReference) (digest.Digest, error) {
+	layers, err := r.registry.GetLayers(ctx, reference)
+	if err != nil {
+		return digest.BadDigest, err
+	}
+
+	contentAddressableStorage, flush := re_blobstore.NewBatchedStoreBlobAccess(
+		r.contentAddressableStorage,
+		digest.KeyWithoutInstance,
+		r.uploadBatchSize,
+		semaphore.NewWeighted(r.uploadConcurrency))
+	root := newFlattenedDirectory()
+	for _, layer := range layers {
+		if err := r.applyLayer(ctx, reference, layer, root, contentAddressableStorage, digestFunction); err != nil {
+			flush(ctx)
+			return digest.BadDigest, util.StatusWrapf(err, "Failed to apply layer %#v of image %#v", layer.Digest, reference.String())
+		}
+	}
+	rootDigest, err := root.upload(ctx, contentAddressableStorage, digestFunction)
+	if err != nil {
+		flush(ctx)
+		return digest.BadDigest, err
+	}
+	if err := flush(ctx); err != nil {
+		return digest.BadDigest, util.StatusWrapf(err, "Failed to store contents of image %#v", reference.String())
+	}
+	return rootDigest, nil
+}
+
+func (r *registryResolver) applyLayer(ctx context.Context, reference ImageReference, layer Descriptor, root *flattenedDirectory, contentAddressableStorage blobstore.BlobAccess, digestFunction digest.Function) error {
+	blob, err := r.registry.GetBlob(ctx, reference, layer)
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+
+	layerReader, closeLayerReader, err := newLayerReader(blob, layer.MediaType)
+	if err != nil {
+		return err
+	}
+	defer closeLayerReader()
+
+	layerRoot, err := readLayer(ctx, layerReader, root, contentAddressableStorage, digestFunction)
+	if err != nil {
+		return err
+	}
+	root.applyLayer(layerRoot)
+	return nil
+}
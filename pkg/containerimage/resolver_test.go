@@ -0,0 +1,201 @@
+package containerimage_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/containerimage"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type tarEntry struct {
+	header tar.Header
+	data   string
+}
+
+func createTar(t *testing.T, entries []tarEntry) []byte {
+	var b bytes.Buffer
+	w := tar.NewWriter(&b)
+	for _, entry := range entries {
+		header := entry.header
+		header.Size = int64(len(entry.data))
+		require.NoError(t, w.WriteHeader(&header))
+		_, err := w.Write([]byte(entry.data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return b.Bytes()
+}
+
+func sha256Digest(data []byte) string {
+	hash := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
+
+func TestRegistryResolver(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	// The bottom layer is compressed, while the top layer is not.
+	// The top layer removes and replaces some of the files of the
+	// bottom layer.
+	var bottomLayer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&bottomLayer)
+	_, err := gzipWriter.Write(createTar(t, []tarEntry{
+		{header: tar.Header{Name: "bin/", Typeflag: tar.TypeDir, Mode: 0o755}},
+		{header: tar.Header{Name: "bin/sh", Typeflag: tar.TypeReg, Mode: 0o755}, data: "sh"},
+		{header: tar.Header{Name: "bin/bash", Typeflag: tar.TypeLink, Linkname: "bin/sh"}},
+		{header: tar.Header{Name: "./etc/passwd", Typeflag: tar.TypeReg, Mode: 0o644}, data: "root"},
+		{header: tar.Header{Name: "etc/shadow", Typeflag: tar.TypeReg, Mode: 0o600}, data: "x"},
+		{header: tar.Header{Name: "lib", Typeflag: tar.TypeSymlink, Linkname: "usr/lib"}},
+		{header: tar.Header{Name: "dev/null", Typeflag: tar.TypeChar}},
+	}))
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+	topLayer := createTar(t, []tarEntry{
+		{header: tar.Header{Name: "bin/ls", Typeflag: tar.TypeReg, Mode: 0o755}, data: "ls"},
+		{header: tar.Header{Name: "bin/.wh..wh..opq", Typeflag: tar.TypeReg}},
+		{header: tar.Header{Name: "etc/.wh.shadow", Typeflag: tar.TypeReg}},
+		{header: tar.Header{Name: "etc/hosts", Typeflag: tar.TypeReg, Mode: 0o644}, data: "localhost"},
+	})
+	manifest, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"layers": []containerimage.Descriptor{
+			{
+				MediaType: "application/vnd.oci.image.layer.v1.tar+gzip",
+				Digest:    sha256Digest(bottomLayer.Bytes()),
+				Size:      int64(bottomLayer.Len()),
+			},
+			{
+				MediaType: "application/vnd.oci.image.layer.v1.tar",
+				Digest:    sha256Digest(topLayer),
+				Size:      int64(len(topLayer)),
+			},
+		},
+	})
+	require.NoError(t, err)
+	manifestDigest := sha256Digest(manifest)
+
+	// Registry that requires clients to obtain a bearer token.
+	manifestRequests := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			require.Equal(t, "repository:tools/base:pull", r.URL.Query().Get("scope"))
+			require.Equal(t, "test-registry", r.URL.Query().Get("service"))
+			w.Write([]byte(`{"token": "secret"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="test-registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/tools/base/manifests/v1":
+			require.Equal(t, http.MethodHead, r.Method)
+			w.Header().Set("Docker-Content-Digest", manifestDigest)
+		case "/v2/tools/base/manifests/" + manifestDigest:
+			manifestRequests++
+			w.Write(manifest)
+		case "/v2/tools/base/blobs/" + sha256Digest(bottomLayer.Bytes()):
+			w.Write(bottomLayer.Bytes())
+		case "/v2/tools/base/blobs/" + sha256Digest(topLayer):
+			w.Write(topLayer)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	registryHost := strings.TrimPrefix(server.URL, "http://")
+
+	// Content Addressable Storage backed by a map.
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	blobs := map[digest.Digest][]byte{}
+	contentAddressableStorage.EXPECT().FindMissing(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, digests digest.Set) (digest.Set, error) {
+			missing := digest.NewSetBuilder()
+			for _, blobDigest := range digests.Items() {
+				if _, ok := blobs[blobDigest]; !ok {
+					missing.Add(blobDigest)
+				}
+			}
+			return missing.Build(), nil
+		}).AnyTimes()
+	contentAddressableStorage.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+			data, err := b.ToByteSlice(10000)
+			require.NoError(t, err)
+			blobs[blobDigest] = data
+			return nil
+		}).AnyTimes()
+	getDirectory := func(directoryDigest *remoteexecution.Digest) *remoteexecution.Directory {
+		for blobDigest, data := range blobs {
+			if proto.Equal(blobDigest.GetProto(), directoryDigest) {
+				var directory remoteexecution.Directory
+				require.NoError(t, proto.Unmarshal(data, &directory))
+				return &directory
+			}
+		}
+		t.Fatalf("Directory %s not found", directoryDigest)
+		return nil
+	}
+
+	resolver := containerimage.NewRegistryResolver(
+		containerimage.NewHTTPRegistry(http.DefaultTransport, []string{registryHost}),
+		contentAddressableStorage,
+		/* uploadBatchSize = */ 10,
+		/* uploadConcurrency = */ 2)
+	digestFunction := digest.MustNewFunction("example", remoteexecution.DigestFunction_SHA256)
+
+	rootDigest, err := resolver.Resolve(ctx, digestFunction, "docker://"+registryHost+"/tools/base:v1")
+	require.NoError(t, err)
+	root := getDirectory(rootDigest.GetProto())
+	require.Len(t, root.Directories, 3)
+	testutil.RequireEqualProto(t, &remoteexecution.SymlinkNode{Name: "lib", Target: "usr/lib"}, root.Symlinks[0])
+
+	// The opaque whiteout should have hidden the files in "bin" of
+	// the bottom layer.
+	require.Equal(t, "bin", root.Directories[0].Name)
+	bin := getDirectory(root.Directories[0].Digest)
+	require.Len(t, bin.Files, 1)
+	require.Equal(t, "ls", bin.Files[0].Name)
+	require.True(t, bin.Files[0].IsExecutable)
+
+	// Device nodes cannot be represented, but their parent
+	// directories are retained.
+	require.Equal(t, "dev", root.Directories[1].Name)
+	require.Empty(t, getDirectory(root.Directories[1].Digest).Files)
+
+	// The whiteout should have removed "etc/shadow".
+	require.Equal(t, "etc", root.Directories[2].Name)
+	etc := getDirectory(root.Directories[2].Digest)
+	require.Len(t, etc.Files, 2)
+	require.Equal(t, "hosts", etc.Files[0].Name)
+	require.Equal(t, "passwd", etc.Files[1].Name)
+	require.False(t, etc.Files[1].IsExecutable)
+
+	// Resolving the same image again should not cause it to be
+	// downloaded once more.
+	cachedRootDigest, err := resolver.Resolve(ctx, digestFunction, "docker://"+registryHost+"/tools/base:v1")
+	require.NoError(t, err)
+	require.Equal(t, rootDigest, cachedRootDigest)
+	require.Equal(t, 1, manifestRequests)
+}
@@ -0,0 +1,116 @@
+package access
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+)
+
+// readFileRecordingState is the shared state that's referenced by all
+// instances of ReadFileRecordingUnreadDirectoryMonitor and
+// readFileRecordingReadDirectoryMonitor.
+type readFileRecordingState struct {
+	lock           sync.Mutex
+	readFiles      map[string]struct{}
+	readFilesOrder []string
+}
+
+// ReadFileRecordingUnreadDirectoryMonitor is a decorator for
+// UnreadDirectoryMonitor that records the paths of all files that have
+// been read.
+type ReadFileRecordingUnreadDirectoryMonitor struct {
+	base  UnreadDirectoryMonitor
+	state *readFileRecordingState
+	path  string
+}
+
+// NewReadFileRecordingUnreadDirectoryMonitor creates an
+// UnreadDirectoryMonitor that records the paths of all files that have
+// been read, forwarding all activity to another UnreadDirectoryMonitor.
+// The base UnreadDirectoryMonitor may be nil, in which case activity is
+// only recorded. The instance that is returned corresponds to the empty
+// path (root directory).
+func NewReadFileRecordingUnreadDirectoryMonitor(base UnreadDirectoryMonitor) *ReadFileRecordingUnreadDirectoryMonitor {
+	return &ReadFileRecordingUnreadDirectoryMonitor{
+		base: base,
+		state: &readFileRecordingState{
+			readFiles: map[string]struct{}{},
+		},
+	}
+}
+
+var _ UnreadDirectoryMonitor = (*ReadFileRecordingUnreadDirectoryMonitor)(nil)
+
+// ReadDirectory can be called to indicate that the contents of a
+// directory have been read.
+func (udm *ReadFileRecordingUnreadDirectoryMonitor) ReadDirectory() ReadDirectoryMonitor {
+	var base ReadDirectoryMonitor
+	if udm.base != nil {
+		base = udm.base.ReadDirectory()
+	}
+	return &readFileRecordingReadDirectoryMonitor{
+		base:  base,
+		state: udm.state,
+		path:  udm.path,
+	}
+}
+
+// GetReadFiles returns the paths of all files that have been read,
+// relative to the root directory. Paths are returned in sorted order.
+func (udm *ReadFileRecordingUnreadDirectoryMonitor) GetReadFiles() []string {
+	readFiles := udm.GetReadFilesInOrder()
+	sort.Strings(readFiles)
+	return readFiles
+}
+
+// GetReadFilesInOrder returns the paths of all files that have been
+// read, relative to the root directory. Paths are returned in the order
+// in which the files were read for the first time.
+func (udm *ReadFileRecordingUnreadDirectoryMonitor) GetReadFilesInOrder() []string {
+	s := udm.state
+	s.lock.Lock()
+	readFiles := append([]string(nil), s.readFilesOrder...)
+	s.lock.Unlock()
+	return readFiles
+}
+
+type readFileRecordingReadDirectoryMonitor struct {
+	base  ReadDirectoryMonitor
+	state *readFileRecordingState
+	path  string
+}
+
+func (rdm *readFileRecordingReadDirectoryMonitor) getChildPath(name path.Component) string {
+	if rdm.path == "" {
+		return name.String()
+	}
+	return rdm.path + "/" + name.String()
+}
+
+func (rdm *readFileRecordingReadDirectoryMonitor) ResolvedDirectory(name path.Component) UnreadDirectoryMonitor {
+	var base UnreadDirectoryMonitor
+	if rdm.base != nil {
+		base = rdm.base.ResolvedDirectory(name)
+	}
+	return &ReadFileRecordingUnreadDirectoryMonitor{
+		base:  base,
+		state: rdm.state,
+		path:  rdm.getChildPath(name),
+	}
+}
+
+func (rdm *readFileRecordingReadDirectoryMonitor) ReadFile(name path.Component) {
+	if rdm.base != nil {
+		rdm.base.ReadFile(name)
+	}
+
+	s := rdm.state
+	childPath := rdm.getChildPath(name)
+	s.lock.Lock()
+	if _, ok := s.readFiles[childPath]; !ok {
+		s.readFiles[childPath] = struct{}{}
+		s.readFilesOrder = append(s.readFilesOrder, childPath)
+	}
+	s.lock.Unlock()
+}
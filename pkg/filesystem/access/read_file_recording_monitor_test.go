@@ -0,0 +1,56 @@
+package access_test
+
+import (
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/access"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFileRecordingUnreadDirectoryMonitor(t *testing.T) {
+	t.Run("WithBase", func(t *testing.T) {
+		baseMonitor := access.NewBloomFilterComputingUnreadDirectoryMonitor()
+		rootUnreadDirectoryMonitor := access.NewReadFileRecordingUnreadDirectoryMonitor(baseMonitor)
+		require.Empty(t, rootUnreadDirectoryMonitor.GetReadFiles())
+
+		// Read files in the root directory and in a subdirectory.
+		rootReadDirectoryMonitor := rootUnreadDirectoryMonitor.ReadDirectory()
+		rootReadDirectoryMonitor.ReadFile(path.MustNewComponent("b"))
+		childReadDirectoryMonitor := rootReadDirectoryMonitor.ResolvedDirectory(path.MustNewComponent("a")).ReadDirectory()
+		childReadDirectoryMonitor.ReadFile(path.MustNewComponent("c"))
+		childReadDirectoryMonitor.ReadFile(path.MustNewComponent("c"))
+		rootReadDirectoryMonitor.ResolvedDirectory(path.MustNewComponent("unread"))
+
+		// Paths should be returned in sorted order, without
+		// duplicates. Directories that are only resolved or read
+		// should not be reported.
+		require.Equal(t, []string{"a/c", "b"}, rootUnreadDirectoryMonitor.GetReadFiles())
+
+		// It should also be possible to obtain the paths in the
+		// order in which they were first read.
+		require.Equal(t, []string{"b", "a/c"}, rootUnreadDirectoryMonitor.GetReadFilesInOrder())
+
+		// All activity should have been forwarded to the
+		// underlying monitor.
+		testutil.RequireEqualProto(t, &resourceusage.InputRootResourceUsage{
+			DirectoriesResolved: 3,
+			DirectoriesRead:     2,
+			FilesRead:           3,
+		}, baseMonitor.GetInputRootResourceUsage())
+	})
+
+	t.Run("WithoutBase", func(t *testing.T) {
+		// BuildClient provides a nil UnreadDirectoryMonitor if
+		// prefetching is disabled. Reads should still be recorded.
+		rootUnreadDirectoryMonitor := access.NewReadFileRecordingUnreadDirectoryMonitor(nil)
+
+		rootReadDirectoryMonitor := rootUnreadDirectoryMonitor.ReadDirectory()
+		rootReadDirectoryMonitor.ReadFile(path.MustNewComponent("b"))
+		rootReadDirectoryMonitor.ResolvedDirectory(path.MustNewComponent("a")).ReadDirectory().ReadFile(path.MustNewComponent("c"))
+
+		require.Equal(t, []string{"a/c", "b"}, rootUnreadDirectoryMonitor.GetReadFiles())
+	})
+}
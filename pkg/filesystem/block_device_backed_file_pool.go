@@ -39,9 +39,10 @@ func (fp *blockDeviceBackedFilePool) NewFile() (filesystem.FileReadWriter, error
 }
 
 type blockDeviceBackedFile struct {
-	fp        *blockDeviceBackedFilePool
-	sizeBytes uint64
-	sectors   []uint32
+	fp               *blockDeviceBackedFilePool
+	sizeBytes        uint64
+	sectors          []uint32
+	allocatedSectors int
 }
 
 func (f *blockDeviceBackedFile) Close() error {
@@ -50,9 +51,21 @@ func (f *blockDeviceBackedFile) Close() error {
 	}
 	f.fp = nil
 	f.sectors = nil
+	f.allocatedSectors = 0
 	return nil
 }
 
+// freeSectors releases a list of sectors of the file, which may
+// contain holes, to the sector allocator.
+func (f *blockDeviceBackedFile) freeSectors(sectors []uint32) {
+	f.fp.sectorAllocator.FreeList(sectors)
+	for _, sector := range sectors {
+		if sector != 0 {
+			f.allocatedSectors--
+		}
+	}
+}
+
 // toDeviceOffset converts a sector number and offset within a sector to
 // a byte offset on the block device.
 func (f *blockDeviceBackedFile) toDeviceOffset(sector uint32, offsetWithinSector int) int64 {
@@ -114,6 +127,78 @@ func (f *blockDeviceBackedFile) limitBufferToSectorBoundary(p []byte, sectorCoun
 	return p
 }
 
+// zeroSectorRange overwrites a range of the file with zero bytes. This
+// function is called by Deallocate() to clear parts of sectors that
+// cannot be released, as they also contain data outside the range.
+func (f *blockDeviceBackedFile) zeroSectorRange(start, end uint64) error {
+	sectorSizeBytes := uint64(f.fp.sectorSizeBytes)
+	for start < end {
+		sectorIndex := int(start / sectorSizeBytes)
+		offsetWithinSector := int(start % sectorSizeBytes)
+		n := sectorSizeBytes - uint64(offsetWithinSector)
+		if n > end-start {
+			n = end - start
+		}
+		if sector := f.sectors[sectorIndex]; sector != 0 {
+			if _, err := f.fp.blockDevice.WriteAt(f.fp.zeroSector[:n], f.toDeviceOffset(sector, offsetWithinSector)); err != nil {
+				return err
+			}
+		}
+		start += n
+	}
+	return nil
+}
+
+func (f *blockDeviceBackedFile) Deallocate(off, size int64) error {
+	if off < 0 {
+		return status.Errorf(codes.InvalidArgument, "Negative deallocation offset: %d", off)
+	}
+	if size < 0 {
+		return status.Errorf(codes.InvalidArgument, "Negative deallocation size: %d", size)
+	}
+
+	// Only the part of the range that overlaps with allocated
+	// sectors needs to be processed.
+	sectorSizeBytes := uint64(f.fp.sectorSizeBytes)
+	start, end := uint64(off), uint64(off)+uint64(size)
+	if allSectors := uint64(len(f.sectors)) * sectorSizeBytes; end > allSectors {
+		end = allSectors
+	}
+	if start >= end {
+		return nil
+	}
+
+	// Sectors that are only partially covered by the range are
+	// zeroed. Sectors that are fully covered are released.
+	firstFullSectorIndex := (start + sectorSizeBytes - 1) / sectorSizeBytes
+	endFullSectorIndex := end / sectorSizeBytes
+	if firstFullSectorIndex >= endFullSectorIndex {
+		return f.zeroSectorRange(start, end)
+	}
+	if err := f.zeroSectorRange(start, firstFullSectorIndex*sectorSizeBytes); err != nil {
+		return err
+	}
+	if err := f.zeroSectorRange(endFullSectorIndex*sectorSizeBytes, end); err != nil {
+		return err
+	}
+	sectors := f.sectors[firstFullSectorIndex:endFullSectorIndex]
+	f.freeSectors(sectors)
+	for i := range sectors {
+		sectors[i] = 0
+	}
+
+	// Ensure that no hole remains at the end, as that would lead
+	// to unnecessary fragmentation when growing the file again.
+	for len(f.sectors) > 0 && f.sectors[len(f.sectors)-1] == 0 {
+		f.sectors = f.sectors[:len(f.sectors)-1]
+	}
+	return nil
+}
+
+func (f *blockDeviceBackedFile) GetAllocatedSizeBytes() (int64, error) {
+	return int64(f.allocatedSectors) * int64(f.fp.sectorSizeBytes), nil
+}
+
 func (f *blockDeviceBackedFile) GetNextRegionOffset(off int64, regionType filesystem.RegionType) (int64, error) {
 	// Short circuit calls that are out of bounds.
 	if off < 0 {
@@ -244,7 +329,7 @@ func (f *blockDeviceBackedFile) ReadAt(p []byte, off int64) (int, error) {
 // truncateSectors truncates a file to a given number of sectors.
 func (f *blockDeviceBackedFile) truncateSectors(sectorCount int) {
 	if len(f.sectors) > sectorCount {
-		f.fp.sectorAllocator.FreeList(f.sectors[sectorCount:])
+		f.freeSectors(f.sectors[sectorCount:])
 		f.sectors = f.sectors[:sectorCount]
 
 		// Ensure that no hole remains at the end, as that would
@@ -362,6 +447,7 @@ func (f *blockDeviceBackedFile) insertSectorsContiguous(firstSectorIndex int, fi
 		}
 		f.sectors[sectorIndex] = firstSector + uint32(i)
 	}
+	f.allocatedSectors += count
 }
 
 // writeToSectors performs a single write against the block device. It
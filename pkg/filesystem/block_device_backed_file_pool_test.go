@@ -318,4 +318,71 @@ func TestBlockDeviceBackedFilePool(t *testing.T) {
 		_, err = f.WriteAt([]byte{0}, -1)
 		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Negative write offset: -1"), err)
 	})
+
+	t.Run("Deallocate", func(t *testing.T) {
+		f, err := pool.NewFile()
+		require.NoError(t, err)
+
+		fd := f.(re_filesystem.FileDeallocator)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Negative deallocation offset: -1"), fd.Deallocate(-1, 10))
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Negative deallocation size: -1"), fd.Deallocate(0, -1))
+
+		// Deallocating storage of an empty file should not
+		// cause any I/O.
+		require.NoError(t, fd.Deallocate(0, 1000))
+
+		// Fill the file with four sectors of data.
+		sectorAllocator.EXPECT().AllocateContiguous(4).Return(uint32(7), 4, nil)
+		blockDevice.EXPECT().WriteAt(gomock.Len(64), int64(96)).Return(64, nil)
+		n, err := f.WriteAt(make([]byte, 64), 0)
+		require.Equal(t, 64, n)
+		require.NoError(t, err)
+
+		allocatedSizeBytes, err := re_filesystem.GetFileAllocatedSizeBytes(f)
+		require.NoError(t, err)
+		require.Equal(t, int64(64), allocatedSizeBytes)
+
+		// Deallocating a range that does not cover sectors
+		// entirely should cause the partially covered parts to
+		// be zeroed. Fully covered sectors should be released.
+		blockDevice.EXPECT().WriteAt(make([]byte, 6), int64(106)).Return(6, nil)
+		blockDevice.EXPECT().WriteAt(make([]byte, 2), int64(144)).Return(2, nil)
+		sectorAllocator.EXPECT().FreeList([]uint32{8, 9})
+		require.NoError(t, fd.Deallocate(10, 40))
+
+		allocatedSizeBytes, err = re_filesystem.GetFileAllocatedSizeBytes(f)
+		require.NoError(t, err)
+		require.Equal(t, int64(32), allocatedSizeBytes)
+
+		nextOffset, err := f.GetNextRegionOffset(10, filesystem.Hole)
+		require.NoError(t, err)
+		require.Equal(t, int64(16), nextOffset)
+		nextOffset, err = f.GetNextRegionOffset(16, filesystem.Data)
+		require.NoError(t, err)
+		require.Equal(t, int64(48), nextOffset)
+
+		// Deallocating the final sector should cause the hole
+		// at the end of the file to be trimmed. The size of the
+		// file should remain unaltered.
+		sectorAllocator.EXPECT().FreeList([]uint32{10})
+		require.NoError(t, fd.Deallocate(48, 100))
+
+		allocatedSizeBytes, err = re_filesystem.GetFileAllocatedSizeBytes(f)
+		require.NoError(t, err)
+		require.Equal(t, int64(16), allocatedSizeBytes)
+
+		_, err = f.GetNextRegionOffset(16, filesystem.Data)
+		require.Equal(t, io.EOF, err)
+		nextOffset, err = f.GetNextRegionOffset(16, filesystem.Hole)
+		require.NoError(t, err)
+		require.Equal(t, int64(16), nextOffset)
+
+		var buf [8]byte
+		n, err = f.ReadAt(buf[:], 60)
+		require.Equal(t, 4, n)
+		require.Equal(t, io.EOF, err)
+
+		sectorAllocator.EXPECT().FreeList([]uint32{7})
+		require.NoError(t, f.Close())
+	})
 }
@@ -22,20 +22,36 @@ func NewFilePoolFromConfiguration(configuration *pb.FilePoolConfiguration) (File
 		return EmptyFilePool, nil
 	}
 
+	filePool, err := newFilePoolFromConfiguration(configuration)
+	if err != nil {
+		return nil, err
+	}
+	return NewMetricsFilePool(filePool), nil
+}
+
+// newFilePoolFromConfiguration constructs a FilePool based on
+// parameters provided in a configuration file, without wrapping it in
+// a MetricsFilePool. This function may be called recursively for file
+// pools that are backed by other file pools. File pools that have a
+// name are wrapped in a NamedMetricsFilePool.
+func newFilePoolFromConfiguration(configuration *pb.FilePoolConfiguration) (FilePool, error) {
 	var filePool FilePool
+	var capacityBytes int64
 	switch backend := configuration.Backend.(type) {
 	case *pb.FilePoolConfiguration_InMemory:
 		filePool = InMemoryFilePool
 	case *pb.FilePoolConfiguration_DirectoryPath:
-		directory, err := filesystem.NewLocalDirectory(backend.DirectoryPath)
+		var err error
+		filePool, err = newDirectoryBackedFilePoolFromPath(backend.DirectoryPath, 0)
 		if err != nil {
-			return nil, util.StatusWrapf(err, "Failed to open directory %#v", backend.DirectoryPath)
+			return nil, err
 		}
-		if err := directory.RemoveAllChildren(); err != nil {
-			directory.Close()
-			return nil, util.StatusWrapf(err, "Failed to empty out directory %#v", backend.DirectoryPath)
+	case *pb.FilePoolConfiguration_Directory:
+		var err error
+		filePool, err = newDirectoryBackedFilePoolFromPath(backend.Directory.Path, int(backend.Directory.MaximumDeletionBacklog))
+		if err != nil {
+			return nil, err
 		}
-		filePool = NewDirectoryBackedFilePool(directory)
 	case *pb.FilePoolConfiguration_BlockDevice:
 		blockDevice, sectorSizeBytes, sectorCount, err := blockdevice.NewBlockDeviceFromConfiguration(backend.BlockDevice, true)
 		if err != nil {
@@ -48,8 +64,64 @@ func NewFilePoolFromConfiguration(configuration *pb.FilePoolConfiguration) (File
 			blockDevice,
 			NewBitmapSectorAllocator(uint32(sectorCount)),
 			sectorSizeBytes)
+		capacityBytes = sectorCount * int64(sectorSizeBytes)
+	case *pb.FilePoolConfiguration_DirectIoBlockDevicePath:
+		blockDevice, sectorSizeBytes, sectorCount, err := NewDirectIOBlockDeviceFromDevice(backend.DirectIoBlockDevicePath)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to create direct I/O block device")
+		}
+		if sectorCount > math.MaxUint32 {
+			return nil, status.Errorf(codes.InvalidArgument, "Block device has %d sectors, while only %d may be addressed", sectorCount, uint32(math.MaxUint32))
+		}
+		filePool = NewBlockDeviceBackedFilePool(
+			blockDevice,
+			NewExtentSectorAllocator(uint32(sectorCount)),
+			sectorSizeBytes)
+		capacityBytes = sectorCount * int64(sectorSizeBytes)
+	case *pb.FilePoolConfiguration_Spilling:
+		if backend.Spilling.Backend == nil {
+			return nil, status.Error(codes.InvalidArgument, "Spilling file pool does not have a backend")
+		}
+		base, err := newFilePoolFromConfiguration(backend.Spilling.Backend)
+		if err != nil {
+			return nil, err
+		}
+		filePool = NewSpillingFilePool(
+			base,
+			backend.Spilling.MaximumInMemoryFileSizeBytes,
+			backend.Spilling.MaximumInMemoryTotalSizeBytes)
+	case *pb.FilePoolConfiguration_WriteBack:
+		if backend.WriteBack.Backend == nil {
+			return nil, status.Error(codes.InvalidArgument, "Write-back file pool does not have a backend")
+		}
+		if backend.WriteBack.MaximumBufferSizeBytes == 0 {
+			return nil, status.Error(codes.InvalidArgument, "Write-back file pool must have a positive maximum buffer size")
+		}
+		base, err := newFilePoolFromConfiguration(backend.WriteBack.Backend)
+		if err != nil {
+			return nil, err
+		}
+		filePool = NewWriteBackFilePool(base, int(backend.WriteBack.MaximumBufferSizeBytes))
 	default:
 		return nil, status.Error(codes.InvalidArgument, "Configuration did not contain a supported file pool backend")
 	}
-	return NewMetricsFilePool(filePool), nil
+	if name := configuration.Name; name != "" {
+		filePool = NewNamedMetricsFilePool(filePool, name, capacityBytes)
+	}
+	return filePool, nil
+}
+
+// newDirectoryBackedFilePoolFromPath creates a directory backed file
+// pool for a given path. Any files left behind in the directory by a
+// previous invocation are removed.
+func newDirectoryBackedFilePoolFromPath(directoryPath string, maximumDeletionBacklog int) (FilePool, error) {
+	directory, err := filesystem.NewLocalDirectory(directoryPath)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to open directory %#v", directoryPath)
+	}
+	if err := directory.RemoveAllChildren(); err != nil {
+		directory.Close()
+		return nil, util.StatusWrapf(err, "Failed to empty out directory %#v", directoryPath)
+	}
+	return NewDirectoryBackedFilePool(directory, maximumDeletionBacklog), nil
 }
@@ -0,0 +1,18 @@
+//go:build darwin || freebsd || windows
+// +build darwin freebsd windows
+
+package filesystem
+
+import (
+	"github.com/buildbarn/bb-storage/pkg/blockdevice"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewDirectIOBlockDeviceFromDevice opens a raw block device using
+// O_DIRECT. On this operating system this functionality is not
+// available.
+func NewDirectIOBlockDeviceFromDevice(path string) (blockdevice.BlockDevice, int, int64, error) {
+	return nil, 0, 0, status.Error(codes.Unimplemented, "Direct I/O block devices are not supported on this platform")
+}
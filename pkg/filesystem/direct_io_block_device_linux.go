@@ -0,0 +1,178 @@
+//go:build linux
+// +build linux
+
+package filesystem
+
+import (
+	"io"
+	"unsafe"
+
+	"github.com/buildbarn/bb-storage/pkg/blockdevice"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type directIOBlockDevice struct {
+	fd              int
+	sectorSizeBytes int
+}
+
+// NewDirectIOBlockDeviceFromDevice opens a raw block device using
+// O_DIRECT, causing all I/O to bypass the kernel's page cache. This
+// prevents large temporary files from evicting pages of other data
+// that is cached by the system (e.g., the worker's local CAS).
+//
+// O_DIRECT requires that the offsets, sizes and memory addresses of
+// I/O operations are aligned to the sector size of the device.
+// Operations that are not aligned are performed through a bounce
+// buffer, requiring partially written sectors to be read first.
+//
+// The sector size of the block device and the total number of sectors
+// are also returned.
+func NewDirectIOBlockDeviceFromDevice(path string) (blockdevice.BlockDevice, int, int64, error) {
+	fd, err := unix.Open(path, unix.O_RDWR|unix.O_DIRECT, 0)
+	if err != nil {
+		return nil, 0, 0, util.StatusWrapf(err, "Failed to open device node %#v", path)
+	}
+
+	// Obtain the size of the device and its individual sectors.
+	// O_DIRECT requires I/O to be aligned to the logical sector
+	// size (BLKSSZGET), which may be smaller than the block size
+	// used by the kernel for buffered I/O (BLKBSZGET).
+	sectorSizeBytes, err := unix.IoctlGetInt(fd, unix.BLKSSZGET)
+	if err != nil {
+		unix.Close(fd)
+		return nil, 0, 0, util.StatusWrapf(err, "Failed to obtain sector size of device node %#v", path)
+	}
+	if sectorSizeBytes <= 0 {
+		unix.Close(fd)
+		return nil, 0, 0, status.Errorf(codes.InvalidArgument, "Device node %#v has invalid sector size %d", path, sectorSizeBytes)
+	}
+	var deviceSizeBytes int64
+	if _, _, err := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), unix.BLKGETSIZE64, uintptr(unsafe.Pointer(&deviceSizeBytes))); err != 0 {
+		unix.Close(fd)
+		return nil, 0, 0, util.StatusWrapf(err, "Failed to obtain size of device node %#v", path)
+	}
+
+	return &directIOBlockDevice{
+		fd:              fd,
+		sectorSizeBytes: sectorSizeBytes,
+	}, sectorSizeBytes, deviceSizeBytes / int64(sectorSizeBytes), nil
+}
+
+// isAligned returns whether an I/O operation can be submitted directly,
+// without making use of a bounce buffer.
+func (bd *directIOBlockDevice) isAligned(p []byte, off int64) bool {
+	sectorSizeBytes := int64(bd.sectorSizeBytes)
+	return len(p) > 0 &&
+		off%sectorSizeBytes == 0 &&
+		int64(len(p))%sectorSizeBytes == 0 &&
+		uintptr(unsafe.Pointer(&p[0]))%uintptr(sectorSizeBytes) == 0
+}
+
+// newBounceBuffer allocates a buffer whose memory address is aligned
+// to the sector size, that can hold the sectors that overlap with a
+// given byte range. It also returns the offset of the first sector.
+func (bd *directIOBlockDevice) newBounceBuffer(off int64, size int) ([]byte, int64) {
+	sectorSizeBytes := int64(bd.sectorSizeBytes)
+	start := off - off%sectorSizeBytes
+	end := (off + int64(size) + sectorSizeBytes - 1) / sectorSizeBytes * sectorSizeBytes
+	buf := make([]byte, end-start+sectorSizeBytes)
+	skip := int(sectorSizeBytes - int64(uintptr(unsafe.Pointer(&buf[0]))%uintptr(sectorSizeBytes)))
+	return buf[skip%bd.sectorSizeBytes:][:end-start], start
+}
+
+func (bd *directIOBlockDevice) preadFull(p []byte, off int64) error {
+	for len(p) > 0 {
+		n, err := unix.Pread(bd.fd, p, off)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return io.ErrUnexpectedEOF
+		}
+		p = p[n:]
+		off += int64(n)
+	}
+	return nil
+}
+
+func (bd *directIOBlockDevice) pwriteFull(p []byte, off int64) error {
+	for len(p) > 0 {
+		n, err := unix.Pwrite(bd.fd, p, off)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return io.ErrShortWrite
+		}
+		p = p[n:]
+		off += int64(n)
+	}
+	return nil
+}
+
+func (bd *directIOBlockDevice) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "Negative read offset: %d", off)
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if bd.isAligned(p, off) {
+		if err := bd.preadFull(p, off); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	buf, start := bd.newBounceBuffer(off, len(p))
+	if err := bd.preadFull(buf, start); err != nil {
+		return 0, err
+	}
+	return copy(p, buf[off-start:]), nil
+}
+
+func (bd *directIOBlockDevice) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "Negative write offset: %d", off)
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if bd.isAligned(p, off) {
+		if err := bd.pwriteFull(p, off); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	// Preserve the contents of the first and last sector, if they
+	// are only written partially.
+	buf, start := bd.newBounceBuffer(off, len(p))
+	sectorSizeBytes := bd.sectorSizeBytes
+	firstSectorPartial := off != start
+	if firstSectorPartial {
+		if err := bd.preadFull(buf[:sectorSizeBytes], start); err != nil {
+			return 0, err
+		}
+	}
+	lastSector := len(buf) - sectorSizeBytes
+	if (off+int64(len(p)))%int64(sectorSizeBytes) != 0 && !(firstSectorPartial && lastSector == 0) {
+		if err := bd.preadFull(buf[lastSector:], start+int64(lastSector)); err != nil {
+			return 0, err
+		}
+	}
+	copy(buf[off-start:], p)
+	if err := bd.pwriteFull(buf, start); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (bd *directIOBlockDevice) Sync() error {
+	return unix.Fdatasync(bd.fd)
+}
@@ -0,0 +1,99 @@
+//go:build linux
+// +build linux
+
+package filesystem_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/sys/unix"
+)
+
+// createLoopDevice creates a loop device that is backed by a file of a
+// given size, having a given logical sector size.
+func createLoopDevice(t *testing.T, sizeBytes int64, sectorSizeBytes int) (string, string) {
+	if os.Geteuid() != 0 {
+		t.Skip("Creating loop devices requires root privileges")
+	}
+	loopControl, err := unix.Open("/dev/loop-control", unix.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("Cannot open loop device control: %s", err)
+	}
+	defer unix.Close(loopControl)
+	loopIndex, err := unix.IoctlRetInt(loopControl, unix.LOOP_CTL_GET_FREE)
+	require.NoError(t, err)
+
+	backingPath := filepath.Join(t.TempDir(), "backing")
+	backingFile, err := os.OpenFile(backingPath, os.O_CREATE|os.O_RDWR, 0o600)
+	require.NoError(t, err)
+	defer backingFile.Close()
+	require.NoError(t, backingFile.Truncate(sizeBytes))
+
+	loopPath := fmt.Sprintf("/dev/loop%d", loopIndex)
+	loopDevice, err := unix.Open(loopPath, unix.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("Cannot open loop device: %s", err)
+	}
+	defer unix.Close(loopDevice)
+	require.NoError(t, unix.IoctlSetInt(loopDevice, unix.LOOP_SET_FD, int(backingFile.Fd())))
+	t.Cleanup(func() {
+		if fd, err := unix.Open(loopPath, unix.O_RDWR, 0); err == nil {
+			unix.IoctlSetInt(fd, unix.LOOP_CLR_FD, 0)
+			unix.Close(fd)
+		}
+	})
+	require.NoError(t, unix.IoctlSetInt(loopDevice, unix.LOOP_SET_BLOCK_SIZE, sectorSizeBytes))
+	return loopPath, backingPath
+}
+
+func TestDirectIOBlockDevice(t *testing.T) {
+	loopPath, backingPath := createLoopDevice(t, 1<<20, 4096)
+
+	blockDevice, sectorSizeBytes, sectorCount, err := re_filesystem.NewDirectIOBlockDeviceFromDevice(loopPath)
+	require.NoError(t, err)
+	require.Equal(t, 4096, sectorSizeBytes)
+	require.Equal(t, int64(256), sectorCount)
+
+	// Unaligned writes should go through a bounce buffer, leaving
+	// data in the surrounding parts of the sectors intact.
+	aligned := bytes.Repeat([]byte{0xaa}, 3*4096)
+	n, err := blockDevice.WriteAt(aligned, 4096)
+	require.NoError(t, err)
+	require.Equal(t, len(aligned), n)
+
+	n, err = blockDevice.WriteAt([]byte("Hello"), 4096+4094)
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+
+	n, err = blockDevice.WriteAt([]byte("world"), 3*4096+10)
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+
+	// Unaligned reads should return the data that was written.
+	var p [9]byte
+	n, err = blockDevice.ReadAt(p[:], 4096+4092)
+	require.NoError(t, err)
+	require.Equal(t, 9, n)
+	require.Equal(t, []byte("\xaa\xaaHello\xaa\xaa"), p[:])
+
+	n, err = blockDevice.ReadAt(p[:], 3*4096+8)
+	require.NoError(t, err)
+	require.Equal(t, 9, n)
+	require.Equal(t, []byte("\xaa\xaaworld\xaa\xaa"), p[:])
+
+	// Data should end up in the backing file at the same offsets.
+	require.NoError(t, blockDevice.Sync())
+	backingData, err := os.ReadFile(backingPath)
+	require.NoError(t, err)
+	require.Equal(t, make([]byte, 4096), backingData[:4096])
+	require.Equal(t, []byte("\xaa\xaaHello\xaa\xaa"), backingData[4096+4092:][:9])
+	require.Equal(t, []byte("\xaa\xaaworld\xaa\xaa"), backingData[3*4096+8:][:9])
+	require.Equal(t, make([]byte, 4096), backingData[4*4096:][:4096])
+}
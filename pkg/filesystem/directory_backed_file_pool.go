@@ -2,16 +2,60 @@ package filesystem
 
 import (
 	"io"
+	"log"
 	"os"
 	"strconv"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/buildbarn/bb-storage/pkg/filesystem"
 	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	directoryBackedFilePoolPrometheusMetrics sync.Once
+
+	directoryBackedFilePoolDeletionBacklogFiles = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "buildbarn",
+			Subsystem: "filesystem",
+			Name:      "directory_backed_file_pool_deletion_backlog_files",
+			Help:      "Number of files backed by a directory backed file pool that have been closed, but are still awaiting deletion.",
+		})
+	directoryBackedFilePoolFilesDeleted = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "filesystem",
+			Name:      "directory_backed_file_pool_files_deleted_total",
+			Help:      "Number of files backed by a directory backed file pool that were deleted asynchronously.",
+		},
+		[]string{"result"})
+	directoryBackedFilePoolFilesDeletedSuccess = directoryBackedFilePoolFilesDeleted.WithLabelValues("Success")
+	directoryBackedFilePoolFilesDeletedFailure = directoryBackedFilePoolFilesDeleted.WithLabelValues("Failure")
+
+	directoryBackedFilePoolDeletionBatchDurationSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "buildbarn",
+			Subsystem: "filesystem",
+			Name:      "directory_backed_file_pool_deletion_batch_duration_seconds",
+			Help:      "Amount of time spent deleting a batch of files backed by a directory backed file pool, in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2.0, 17),
+		})
 )
 
+// directoryBackedFilePoolMaximumDeletionBatchSize is the maximum
+// number of files that are deleted by the background goroutine before
+// metrics are updated.
+const directoryBackedFilePoolMaximumDeletionBatchSize = 100
+
 type directoryBackedFilePool struct {
 	directory filesystem.Directory
+	deletions chan path.Component
 
 	nextID atomic.Uint64
 }
@@ -24,37 +68,126 @@ type directoryBackedFilePool struct {
 // does not keep any backing files open. This would exhaust the worker's
 // file descriptor table. Files are opened on demand.
 //
+// If maximumDeletionBacklog is zero, files are unlinked as part of
+// Close(). Because freeing the space of files that are tens of
+// gigabytes in size may take seconds, a positive value causes files to
+// be unlinked by a background goroutine instead. This prevents the
+// completion of build actions from being delayed. Close() only blocks
+// if the number of files awaiting deletion reaches the maximum, so
+// that the backlog cannot grow without bounds.
+//
 // TODO: Maybe use an eviction.Set to keep a small number of files open?
-func NewDirectoryBackedFilePool(directory filesystem.Directory) FilePool {
-	return &directoryBackedFilePool{
+func NewDirectoryBackedFilePool(directory filesystem.Directory, maximumDeletionBacklog int) FilePool {
+	fp := &directoryBackedFilePool{
 		directory: directory,
 	}
+	if maximumDeletionBacklog > 0 {
+		directoryBackedFilePoolPrometheusMetrics.Do(func() {
+			prometheus.MustRegister(directoryBackedFilePoolDeletionBacklogFiles)
+			prometheus.MustRegister(directoryBackedFilePoolFilesDeleted)
+			prometheus.MustRegister(directoryBackedFilePoolDeletionBatchDurationSeconds)
+		})
+
+		fp.deletions = make(chan path.Component, maximumDeletionBacklog)
+		go fp.processDeletions()
+	}
+	return fp
+}
+
+// processDeletions is run by a background goroutine to unlink files
+// that have been closed. Files are processed in batches, so that the
+// cost of updating metrics is amortized when the backlog is large.
+func (fp *directoryBackedFilePool) processDeletions() {
+	batch := make([]path.Component, 0, directoryBackedFilePoolMaximumDeletionBatchSize)
+	for name := range fp.deletions {
+		batch = append(batch[:0], name)
+	GatherBatch:
+		for len(batch) < cap(batch) {
+			select {
+			case name := <-fp.deletions:
+				batch = append(batch, name)
+			default:
+				break GatherBatch
+			}
+		}
+
+		timeStart := time.Now()
+		failures := 0
+		for _, name := range batch {
+			if err := fp.directory.Remove(name); err != nil && !os.IsNotExist(err) {
+				log.Printf("Failed to delete file %#v from file pool: %s", name.String(), err)
+				failures++
+			}
+		}
+		directoryBackedFilePoolDeletionBatchDurationSeconds.Observe(time.Since(timeStart).Seconds())
+		directoryBackedFilePoolDeletionBacklogFiles.Sub(float64(len(batch)))
+		directoryBackedFilePoolFilesDeletedSuccess.Add(float64(len(batch) - failures))
+		directoryBackedFilePoolFilesDeletedFailure.Add(float64(failures))
+	}
 }
 
 func (fp *directoryBackedFilePool) NewFile() (filesystem.FileReadWriter, error) {
 	return &lazyOpeningSelfDeletingFile{
-		directory: fp.directory,
-		name:      path.MustNewComponent(strconv.FormatUint(fp.nextID.Add(1), 10)),
+		pool: fp,
+		name: path.MustNewComponent(strconv.FormatUint(fp.nextID.Add(1), 10)),
 	}, nil
 }
 
 // lazyOpeningSelfDeletingFile is a file descriptor that forwards
 // operations to a file that is opened on demand. Upon closure, the
-// underlying file is unlinked.
+// underlying file is unlinked, either directly or by the file pool's
+// background goroutine.
 type lazyOpeningSelfDeletingFile struct {
-	directory filesystem.Directory
-	name      path.Component
+	pool *directoryBackedFilePool
+	name path.Component
 }
 
 func (f *lazyOpeningSelfDeletingFile) Close() error {
-	if err := f.directory.Remove(f.name); err != nil && !os.IsNotExist(err) {
+	if f.pool.deletions != nil {
+		directoryBackedFilePoolDeletionBacklogFiles.Inc()
+		f.pool.deletions <- f.name
+		return nil
+	}
+	if err := f.pool.directory.Remove(f.name); err != nil && !os.IsNotExist(err) {
 		return err
 	}
 	return nil
 }
 
+func (f *lazyOpeningSelfDeletingFile) Deallocate(off, size int64) error {
+	if off < 0 {
+		return status.Errorf(codes.InvalidArgument, "Negative deallocation offset: %d", off)
+	}
+	if size < 0 {
+		return status.Errorf(codes.InvalidArgument, "Negative deallocation size: %d", size)
+	}
+	fh, err := f.pool.directory.OpenWrite(f.name, filesystem.DontCreate)
+	if os.IsNotExist(err) {
+		// Empty file that doesn't explicitly exist in the
+		// backing store yet. There is nothing to deallocate.
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer fh.Close()
+	return punchHole(fh, off, size)
+}
+
+func (f *lazyOpeningSelfDeletingFile) GetAllocatedSizeBytes() (int64, error) {
+	fh, err := f.pool.directory.OpenRead(f.name)
+	if os.IsNotExist(err) {
+		// Empty file that doesn't explicitly exist in the
+		// backing store yet.
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	defer fh.Close()
+	return getAllocatedSizeBytes(fh)
+}
+
 func (f *lazyOpeningSelfDeletingFile) GetNextRegionOffset(off int64, regionType filesystem.RegionType) (int64, error) {
-	fh, err := f.directory.OpenRead(f.name)
+	fh, err := f.pool.directory.OpenRead(f.name)
 	if os.IsNotExist(err) {
 		// Empty file that doesn't explicitly exist in the
 		// backing store yet. Treat it as if it's a zero-length
@@ -68,7 +201,7 @@ func (f *lazyOpeningSelfDeletingFile) GetNextRegionOffset(off int64, regionType
 }
 
 func (f *lazyOpeningSelfDeletingFile) ReadAt(p []byte, off int64) (int, error) {
-	fh, err := f.directory.OpenRead(f.name)
+	fh, err := f.pool.directory.OpenRead(f.name)
 	if os.IsNotExist(err) {
 		// Empty file that doesn't explicitly exist in the
 		// backing store yet. Treat it as if it's a zero-length
@@ -88,7 +221,7 @@ func (f *lazyOpeningSelfDeletingFile) Sync() error {
 }
 
 func (f *lazyOpeningSelfDeletingFile) Truncate(size int64) error {
-	fh, err := f.directory.OpenWrite(f.name, filesystem.CreateReuse(0o600))
+	fh, err := f.pool.directory.OpenWrite(f.name, filesystem.CreateReuse(0o600))
 	if err != nil {
 		return err
 	}
@@ -97,7 +230,7 @@ func (f *lazyOpeningSelfDeletingFile) Truncate(size int64) error {
 }
 
 func (f *lazyOpeningSelfDeletingFile) WriteAt(p []byte, off int64) (int, error) {
-	fh, err := f.directory.OpenWrite(f.name, filesystem.CreateReuse(0o600))
+	fh, err := f.pool.directory.OpenWrite(f.name, filesystem.CreateReuse(0o600))
 	if err != nil {
 		return 0, err
 	}
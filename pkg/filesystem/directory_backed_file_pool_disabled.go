@@ -0,0 +1,25 @@
+//go:build darwin || freebsd || windows
+// +build darwin freebsd windows
+
+package filesystem
+
+import (
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// punchHole releases the storage backing a range of a file stored on a
+// local file system. On this operating system this functionality is
+// not available.
+func punchHole(f filesystem.FileWriter, off, size int64) error {
+	return status.Error(codes.Unimplemented, "Punching holes into files is not supported on this platform")
+}
+
+// getAllocatedSizeBytes returns the amount of storage consumed by a
+// file stored on a local file system. On this operating system this
+// functionality is not available.
+func getAllocatedSizeBytes(f filesystem.FileReader) (int64, error) {
+	return 0, status.Error(codes.Unimplemented, "Obtaining the amount of storage allocated by files is not supported on this platform")
+}
@@ -0,0 +1,49 @@
+//go:build linux
+// +build linux
+
+package filesystem
+
+import (
+	"runtime"
+
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fileDescriptorProvider is implemented by file handles returned by
+// filesystem.Directory that are backed by a file descriptor, such as
+// the ones returned by filesystem.NewLocalDirectory().
+type fileDescriptorProvider interface {
+	Fd() uintptr
+}
+
+// punchHole releases the storage backing a range of a file stored on a
+// local file system, using fallocate(FALLOC_FL_PUNCH_HOLE).
+func punchHole(f filesystem.FileWriter, off, size int64) error {
+	fdp, ok := f.(fileDescriptorProvider)
+	if !ok {
+		return status.Error(codes.Unimplemented, "File is not backed by a file descriptor")
+	}
+	defer runtime.KeepAlive(f)
+	return unix.Fallocate(int(fdp.Fd()), unix.FALLOC_FL_PUNCH_HOLE|unix.FALLOC_FL_KEEP_SIZE, off, size)
+}
+
+// getAllocatedSizeBytes returns the amount of storage consumed by a
+// file stored on a local file system, based on the number of blocks
+// reported by fstat().
+func getAllocatedSizeBytes(f filesystem.FileReader) (int64, error) {
+	fdp, ok := f.(fileDescriptorProvider)
+	if !ok {
+		return 0, status.Error(codes.Unimplemented, "File is not backed by a file descriptor")
+	}
+	defer runtime.KeepAlive(f)
+	var stat unix.Stat_t
+	if err := unix.Fstat(int(fdp.Fd()), &stat); err != nil {
+		return 0, err
+	}
+	// st_blocks is always expressed in 512 byte units.
+	return stat.Blocks * 512, nil
+}
@@ -1,7 +1,9 @@
 package filesystem_test
 
 import (
+	"bytes"
 	"io"
+	"runtime"
 	"syscall"
 	"testing"
 
@@ -17,7 +19,7 @@ func TestDirectoryBackedFilePool(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
 	directory := mock.NewMockDirectory(ctrl)
-	fp := re_filesystem.NewDirectoryBackedFilePool(directory)
+	fp := re_filesystem.NewDirectoryBackedFilePool(directory, 0)
 
 	t.Run("EmptyFile", func(t *testing.T) {
 		f, err := fp.NewFile()
@@ -40,6 +42,16 @@ func TestDirectoryBackedFilePool(t *testing.T) {
 		_, err = f.GetNextRegionOffset(0, filesystem.Hole)
 		require.Equal(t, io.EOF, err)
 
+		// There is no storage to deallocate, and no storage is
+		// in use.
+		directory.EXPECT().OpenWrite(path.MustNewComponent("1"), filesystem.DontCreate).Return(nil, syscall.ENOENT)
+		require.NoError(t, re_filesystem.DeallocateFile(f, 0, 100))
+
+		directory.EXPECT().OpenRead(path.MustNewComponent("1")).Return(nil, syscall.ENOENT)
+		allocatedSizeBytes, err := re_filesystem.GetFileAllocatedSizeBytes(f)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), allocatedSizeBytes)
+
 		directory.EXPECT().Remove(path.MustNewComponent("1")).Return(syscall.ENOENT)
 		require.NoError(t, f.Close())
 	})
@@ -93,3 +105,81 @@ func TestDirectoryBackedFilePool(t *testing.T) {
 		require.NoError(t, f.Close())
 	})
 }
+
+func TestDirectoryBackedFilePoolAsynchronousDeletion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	directory := mock.NewMockDirectory(ctrl)
+	fp := re_filesystem.NewDirectoryBackedFilePool(directory, 10)
+
+	// Closing files should not block on them being removed. Failures
+	// to remove files can no longer be reported to the caller.
+	f1, err := fp.NewFile()
+	require.NoError(t, err)
+	f2, err := fp.NewFile()
+	require.NoError(t, err)
+
+	removed := make(chan struct{})
+	unblock := make(chan struct{})
+	directory.EXPECT().Remove(path.MustNewComponent("1")).DoAndReturn(
+		func(name path.Component) error {
+			<-unblock
+			return syscall.ENOENT
+		})
+	directory.EXPECT().Remove(path.MustNewComponent("2")).DoAndReturn(
+		func(name path.Component) error {
+			close(removed)
+			return syscall.EIO
+		})
+
+	require.NoError(t, f1.Close())
+	require.NoError(t, f2.Close())
+	close(unblock)
+	<-removed
+}
+
+func TestDirectoryBackedFilePoolLocalDirectory(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Punching holes into files is only supported on Linux")
+	}
+
+	directory, err := filesystem.NewLocalDirectory(t.TempDir())
+	require.NoError(t, err)
+	defer directory.Close()
+	fp := re_filesystem.NewDirectoryBackedFilePool(directory, 0)
+
+	f, err := fp.NewFile()
+	require.NoError(t, err)
+	defer f.Close()
+
+	// Write 1 MiB of data into the file, which should cause
+	// storage to be allocated.
+	data := bytes.Repeat([]byte{0xff}, 1<<20)
+	n, err := f.WriteAt(data, 0)
+	require.NoError(t, err)
+	require.Equal(t, len(data), n)
+	allocatedBefore, err := re_filesystem.GetFileAllocatedSizeBytes(f)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, allocatedBefore, int64(len(data)))
+
+	// Punching a hole into the middle of the file should release
+	// storage, while leaving the size of the file intact.
+	if err := re_filesystem.DeallocateFile(f, 1<<18, 1<<19); err == syscall.EOPNOTSUPP {
+		t.Skip("File system backing the temporary directory does not support punching holes")
+	} else {
+		require.NoError(t, err)
+	}
+	allocatedAfter, err := re_filesystem.GetFileAllocatedSizeBytes(f)
+	require.NoError(t, err)
+	require.Less(t, allocatedAfter, allocatedBefore)
+
+	var p [4]byte
+	n, err = f.ReadAt(p[:], 1<<18)
+	require.NoError(t, err)
+	require.Equal(t, 4, n)
+	require.Equal(t, [4]byte{}, p)
+	n, err = f.ReadAt(p[:], (1<<20)-4)
+	require.NoError(t, err)
+	require.Equal(t, 4, n)
+	require.Equal(t, [4]byte{0xff, 0xff, 0xff, 0xff}, p)
+}
@@ -0,0 +1,335 @@
+package filesystem
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"io"
+	"sync/atomic"
+
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type encryptingFilePool struct {
+	base           FilePool
+	aead           cipher.AEAD
+	chunkSizeBytes int64
+
+	nextFileID atomic.Uint64
+	nextNonce  atomic.Uint64
+}
+
+// NewEncryptingFilePool creates a decorator for FilePool that encrypts
+// the contents of files before they are written to the underlying
+// FilePool. This prevents temporary files created by build actions
+// from being stored on disk in plaintext.
+//
+// Files are split into chunks of a fixed size, each of which is
+// encrypted separately using AES-GCM. This permits random access, at
+// the cost of having to perform read-modify-write cycles for writes
+// that are not chunk aligned.
+//
+// The encryption key is obtained from an EncryptionKeyProvider. Creating
+// a separate instance of this FilePool for every action, each using a
+// different key, ensures that data of one action cannot be decrypted
+// using the key of another.
+func NewEncryptingFilePool(base FilePool, chunkSizeBytes int, key *EncryptionKey) (FilePool, error) {
+	if chunkSizeBytes <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Chunk size must be positive, while %d was provided", chunkSizeBytes)
+	}
+
+	block, err := aes.NewCipher(key.Key[:])
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to create AES block cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to create AES-GCM cipher")
+	}
+	return &encryptingFilePool{
+		base:           base,
+		aead:           aead,
+		chunkSizeBytes: int64(chunkSizeBytes),
+	}, nil
+}
+
+func (fp *encryptingFilePool) NewFile() (filesystem.FileReadWriter, error) {
+	f, err := fp.base.NewFile()
+	if err != nil {
+		return nil, err
+	}
+	return &encryptingFile{
+		pool:   fp,
+		base:   f,
+		fileID: fp.nextFileID.Add(1),
+	}, nil
+}
+
+// encryptingFile is the file type returned by encryptingFilePool.
+// Every chunk of plaintext is stored in the underlying file as a
+// nonce, followed by the ciphertext and authentication tag. Nonces are
+// obtained from a counter that starts at one, meaning that chunks
+// having an all-zero nonce have never been written and should be
+// treated as if they only contain zero bytes.
+type encryptingFile struct {
+	pool   *encryptingFilePool
+	base   filesystem.FileReadWriter
+	fileID uint64
+	size   int64
+}
+
+// getStoredChunkSize returns the size of a single chunk, as stored in
+// the underlying file.
+func (f *encryptingFile) getStoredChunkSize() int64 {
+	aead := f.pool.aead
+	return int64(aead.NonceSize()+aead.Overhead()) + f.pool.chunkSizeBytes
+}
+
+// getChunkCount returns the number of chunks needed to store a file
+// of a given size.
+func (f *encryptingFile) getChunkCount(size int64) int64 {
+	return (size + f.pool.chunkSizeBytes - 1) / f.pool.chunkSizeBytes
+}
+
+// getAdditionalData returns the additional data that is authenticated
+// as part of every chunk. It binds the chunk to the file and offset at
+// which it is stored, so that chunks can't be swapped.
+func (f *encryptingFile) getAdditionalData(chunk int64) []byte {
+	var additionalData [16]byte
+	binary.LittleEndian.PutUint64(additionalData[:8], f.fileID)
+	binary.LittleEndian.PutUint64(additionalData[8:], uint64(chunk))
+	return additionalData[:]
+}
+
+// readChunk reads a single chunk from the underlying file and
+// decrypts it into a buffer that is of the chunk size.
+func (f *encryptingFile) readChunk(chunk int64, plaintext []byte) error {
+	aead := f.pool.aead
+	stored := make([]byte, f.getStoredChunkSize())
+	n, err := f.base.ReadAt(stored, chunk*int64(len(stored)))
+	if err != nil && err != io.EOF {
+		return err
+	}
+	clear(stored[n:])
+
+	nonce := stored[:aead.NonceSize()]
+	if isZero(nonce) {
+		// Chunk has never been written.
+		clear(plaintext)
+		return nil
+	}
+	if _, err := aead.Open(plaintext[:0], nonce, stored[aead.NonceSize():], f.getAdditionalData(chunk)); err != nil {
+		return util.StatusWrapfWithCode(err, codes.DataLoss, "Failed to decrypt chunk %d", chunk)
+	}
+	return nil
+}
+
+// writeChunk encrypts a chunk of plaintext and writes it into the
+// underlying file.
+func (f *encryptingFile) writeChunk(chunk int64, plaintext []byte) error {
+	aead := f.pool.aead
+	stored := make([]byte, aead.NonceSize(), f.getStoredChunkSize())
+	binary.LittleEndian.PutUint64(stored, f.pool.nextNonce.Add(1))
+	stored = aead.Seal(stored, stored, plaintext, f.getAdditionalData(chunk))
+	_, err := f.base.WriteAt(stored, chunk*int64(len(stored)))
+	return err
+}
+
+// zeroChunkRange zeroes a range of bytes within a single chunk by
+// performing a read-modify-write cycle.
+func (f *encryptingFile) zeroChunkRange(chunk, start, end int64) error {
+	plaintext := make([]byte, f.pool.chunkSizeBytes)
+	if err := f.readChunk(chunk, plaintext); err != nil {
+		return err
+	}
+	if isZero(plaintext[start:end]) {
+		return nil
+	}
+	clear(plaintext[start:end])
+	return f.writeChunk(chunk, plaintext)
+}
+
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *encryptingFile) Close() error {
+	f.pool = nil
+	return f.base.Close()
+}
+
+func (f *encryptingFile) Deallocate(off, size int64) error {
+	if off < 0 {
+		return status.Errorf(codes.InvalidArgument, "Negative deallocation offset: %d", off)
+	}
+	if size < 0 {
+		return status.Errorf(codes.InvalidArgument, "Negative deallocation size: %d", size)
+	}
+
+	end := off + size
+	if end > f.size {
+		end = f.size
+	}
+	if off >= end {
+		return nil
+	}
+
+	// Zero the leading and trailing parts of the range that only
+	// partially cover a chunk.
+	chunkSize := f.pool.chunkSizeBytes
+	firstFullChunk := (off + chunkSize - 1) / chunkSize
+	lastFullChunk := end / chunkSize
+	if firstFullChunk > lastFullChunk {
+		// Range lies within a single chunk.
+		return f.zeroChunkRange(off/chunkSize, off%chunkSize, end%chunkSize)
+	}
+	if off%chunkSize != 0 {
+		if err := f.zeroChunkRange(off/chunkSize, off%chunkSize, chunkSize); err != nil {
+			return err
+		}
+	}
+	if end%chunkSize != 0 {
+		if err := f.zeroChunkRange(end/chunkSize, 0, end%chunkSize); err != nil {
+			return err
+		}
+	}
+
+	// Release the chunks that are fully covered by the range.
+	// The nonces of these chunks become zero, causing them to be
+	// read back as zero bytes.
+	if firstFullChunk < lastFullChunk {
+		storedChunkSize := f.getStoredChunkSize()
+		return DeallocateFile(
+			f.base,
+			firstFullChunk*storedChunkSize,
+			(lastFullChunk-firstFullChunk)*storedChunkSize)
+	}
+	return nil
+}
+
+func (f *encryptingFile) GetAllocatedSizeBytes() (int64, error) {
+	// Report the amount of storage consumed by the ciphertext,
+	// including the overhead of storing nonces and tags.
+	return GetFileAllocatedSizeBytes(f.base)
+}
+
+func (f *encryptingFile) GetNextRegionOffset(off int64, regionType filesystem.RegionType) (int64, error) {
+	// Holes in the underlying file don't necessarily align with
+	// chunks. Report the file as being fully allocated.
+	if off >= f.size {
+		return 0, io.EOF
+	}
+	switch regionType {
+	case filesystem.Data:
+		return off, nil
+	case filesystem.Hole:
+		return f.size, nil
+	default:
+		panic("Unknown region type")
+	}
+}
+
+func (f *encryptingFile) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "Negative read offset: %d", off)
+	}
+	if off >= f.size {
+		return 0, io.EOF
+	}
+	var readErr error
+	if remaining := f.size - off; int64(len(p)) > remaining {
+		p = p[:remaining]
+		readErr = io.EOF
+	}
+
+	chunkSize := f.pool.chunkSizeBytes
+	plaintext := make([]byte, chunkSize)
+	nRead := 0
+	for nRead < len(p) {
+		chunk, chunkOff := off/chunkSize, off%chunkSize
+		if err := f.readChunk(chunk, plaintext); err != nil {
+			return nRead, err
+		}
+		n := copy(p[nRead:], plaintext[chunkOff:])
+		nRead += n
+		off += int64(n)
+	}
+	return nRead, readErr
+}
+
+func (f *encryptingFile) Sync() error {
+	return f.base.Sync()
+}
+
+func (f *encryptingFile) Truncate(size int64) error {
+	if size < 0 {
+		return status.Errorf(codes.InvalidArgument, "Negative truncation size: %d", size)
+	}
+	if size < f.size {
+		// Shrinking the file. Zero the trailing part of the last
+		// chunk, so that it reads back as zeros if the file is
+		// grown once again.
+		chunkSize := f.pool.chunkSizeBytes
+		if size%chunkSize != 0 {
+			if err := f.zeroChunkRange(size/chunkSize, size%chunkSize, chunkSize); err != nil {
+				return err
+			}
+		}
+		if err := f.base.Truncate(f.getChunkCount(size) * f.getStoredChunkSize()); err != nil {
+			return err
+		}
+	}
+	f.size = size
+	return nil
+}
+
+func (f *encryptingFile) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "Negative write offset: %d", off)
+	}
+
+	chunkSize := f.pool.chunkSizeBytes
+	plaintext := make([]byte, chunkSize)
+	nWritten := 0
+	for nWritten < len(p) {
+		chunk, chunkOff := off/chunkSize, off%chunkSize
+		n := len(p) - nWritten
+		if remaining := int(chunkSize - chunkOff); n > remaining {
+			n = remaining
+		}
+		if int64(n) == chunkSize {
+			// Write covers the full chunk.
+			copy(plaintext, p[nWritten:])
+		} else {
+			// Partial write. Merge the data with the
+			// existing contents of the chunk.
+			if chunk < f.getChunkCount(f.size) {
+				if err := f.readChunk(chunk, plaintext); err != nil {
+					return nWritten, err
+				}
+			} else {
+				clear(plaintext)
+			}
+			copy(plaintext[chunkOff:], p[nWritten:nWritten+n])
+		}
+		if err := f.writeChunk(chunk, plaintext); err != nil {
+			return nWritten, err
+		}
+
+		nWritten += n
+		off += int64(n)
+		if f.size < off {
+			f.size = off
+		}
+	}
+	return nWritten, nil
+}
@@ -0,0 +1,150 @@
+package filesystem_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEncryptingFilePool(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	key, err := re_filesystem.RandomEncryptionKeyProvider.NewEncryptionKey("3-8b1a9953c4611296a827abf8c47804d7-123-hello")
+	require.NoError(t, err)
+	require.Equal(t, "random", key.ID)
+
+	t.Run("InvalidChunkSize", func(t *testing.T) {
+		_, err := re_filesystem.NewEncryptingFilePool(mock.NewMockFilePool(ctrl), 0, &key)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Chunk size must be positive, while 0 was provided"), err)
+	})
+
+	// Let the encrypting file pool be backed by in-memory files, so
+	// that we can inspect the data that is stored.
+	basePool := mock.NewMockFilePool(ctrl)
+	pool, err := re_filesystem.NewEncryptingFilePool(basePool, 8, &key)
+	require.NoError(t, err)
+
+	newFile := func(t *testing.T) (filesystem.FileReadWriter, filesystem.FileReadWriter) {
+		baseFile, err := re_filesystem.InMemoryFilePool.NewFile()
+		require.NoError(t, err)
+		basePool.EXPECT().NewFile().Return(baseFile, nil)
+		f, err := pool.NewFile()
+		require.NoError(t, err)
+		return f, baseFile
+	}
+
+	t.Run("EmptyFile", func(t *testing.T) {
+		f, _ := newFile(t)
+
+		var p [10]byte
+		n, err := f.ReadAt(p[:], 0)
+		require.Equal(t, 0, n)
+		require.Equal(t, io.EOF, err)
+
+		require.NoError(t, f.Close())
+	})
+
+	t.Run("NoPlaintextInBaseFile", func(t *testing.T) {
+		f, baseFile := newFile(t)
+
+		// Perform a write that spans multiple chunks, followed
+		// by one that partially overwrites an existing chunk.
+		n, err := f.WriteAt([]byte("Hello, world"), 5)
+		require.Equal(t, 12, n)
+		require.NoError(t, err)
+		n, err = f.WriteAt([]byte("W"), 12)
+		require.Equal(t, 1, n)
+		require.NoError(t, err)
+
+		var p [20]byte
+		n, err = f.ReadAt(p[:], 0)
+		require.Equal(t, 17, n)
+		require.Equal(t, io.EOF, err)
+		require.Equal(t, []byte("\x00\x00\x00\x00\x00Hello, World"), p[:17])
+
+		// The underlying file should not contain any of the
+		// plaintext.
+		var stored [1000]byte
+		n, err = baseFile.ReadAt(stored[:], 0)
+		require.Equal(t, io.EOF, err)
+		require.False(t, bytes.Contains(stored[:n], []byte("Hello")))
+		require.False(t, bytes.Contains(stored[:n], []byte("world")))
+
+		require.NoError(t, f.Close())
+	})
+
+	t.Run("Tampering", func(t *testing.T) {
+		f, baseFile := newFile(t)
+
+		n, err := f.WriteAt([]byte("Hello"), 0)
+		require.Equal(t, 5, n)
+		require.NoError(t, err)
+
+		// Modifying the underlying file should cause
+		// authentication of the chunk to fail.
+		var b [1]byte
+		_, err = baseFile.ReadAt(b[:], 20)
+		require.NoError(t, err)
+		b[0] ^= 1
+		_, err = baseFile.WriteAt(b[:], 20)
+		require.NoError(t, err)
+
+		var p [5]byte
+		_, err = f.ReadAt(p[:], 0)
+		testutil.RequireEqualStatus(t, status.Error(codes.DataLoss, "Failed to decrypt chunk 0: cipher: message authentication failed"), err)
+
+		require.NoError(t, f.Close())
+	})
+
+	t.Run("Truncate", func(t *testing.T) {
+		f, _ := newFile(t)
+
+		n, err := f.WriteAt([]byte("Hello, world"), 0)
+		require.Equal(t, 12, n)
+		require.NoError(t, err)
+
+		// Shrinking and growing the file should cause the
+		// truncated part to be read back as zeros.
+		require.NoError(t, f.Truncate(3))
+		require.NoError(t, f.Truncate(20))
+
+		var p [20]byte
+		n, err = f.ReadAt(p[:], 0)
+		require.Equal(t, 20, n)
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hel\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"), p[:])
+
+		require.NoError(t, f.Close())
+	})
+
+	t.Run("Deallocate", func(t *testing.T) {
+		f, _ := newFile(t)
+
+		n, err := f.WriteAt([]byte("abcdefghijklmnopqrstuvwxyz"), 0)
+		require.Equal(t, 26, n)
+		require.NoError(t, err)
+
+		// Deallocating a range should cause it to be zeroed,
+		// without affecting the size of the file. Chunks that
+		// are fully covered are released.
+		require.NoError(t, f.(re_filesystem.FileDeallocator).Deallocate(3, 18))
+
+		var p [30]byte
+		n, err = f.ReadAt(p[:], 0)
+		require.Equal(t, 26, n)
+		require.Equal(t, io.EOF, err)
+		require.Equal(t, []byte("abc\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00vwxyz"), p[:26])
+
+		require.NoError(t, f.Close())
+	})
+}
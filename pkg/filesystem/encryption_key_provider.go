@@ -0,0 +1,109 @@
+package filesystem
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+)
+
+// EncryptionKey is a key that may be provided to
+// NewEncryptingFilePool(). The identifier is not secret, and may be
+// logged for auditing purposes.
+type EncryptionKey struct {
+	ID  string
+	Key [32]byte
+}
+
+// EncryptionKeyProvider is called into by
+// FilePoolEncryptingBuildExecutor to obtain the key that is used to
+// encrypt the temporary files of a single action.
+type EncryptionKeyProvider interface {
+	NewEncryptionKey(actionDigest string) (EncryptionKey, error)
+}
+
+type randomEncryptionKeyProvider struct{}
+
+func (randomEncryptionKeyProvider) NewEncryptionKey(actionDigest string) (EncryptionKey, error) {
+	key := EncryptionKey{ID: "random"}
+	if _, err := io.ReadFull(rand.Reader, key.Key[:]); err != nil {
+		return EncryptionKey{}, util.StatusWrapWithCode(err, codes.Internal, "Failed to generate encryption key")
+	}
+	return key, nil
+}
+
+// RandomEncryptionKeyProvider is an EncryptionKeyProvider that
+// generates keys randomly. As these keys are only kept in memory,
+// temporary files cannot be decrypted after the action completes.
+var RandomEncryptionKeyProvider EncryptionKeyProvider = randomEncryptionKeyProvider{}
+
+// MasterKey is a key that is obtained from a MasterKeySource, from
+// which the keys of individual actions are derived.
+type MasterKey struct {
+	ID  string
+	Key []byte
+}
+
+// MasterKeySource is a source of master keys. Implementations may
+// return different master keys over time, so that master keys can be
+// rotated.
+type MasterKeySource interface {
+	GetMasterKey() (MasterKey, error)
+}
+
+type masterKeyDerivingEncryptionKeyProvider struct {
+	source MasterKeySource
+}
+
+// NewMasterKeyDerivingEncryptionKeyProvider creates an
+// EncryptionKeyProvider that derives keys from a master key using
+// HKDF-SHA256. Every action uses a randomly generated salt, meaning
+// that even multiple executions of the same action use different keys.
+//
+// The identifier of the resulting key consists of the identifier of
+// the master key and the salt. This allows anyone with access to the
+// master key to recompute the key of an action when performing an
+// audit, while the key itself is never logged.
+func NewMasterKeyDerivingEncryptionKeyProvider(source MasterKeySource) EncryptionKeyProvider {
+	return &masterKeyDerivingEncryptionKeyProvider{
+		source: source,
+	}
+}
+
+func (kp *masterKeyDerivingEncryptionKeyProvider) NewEncryptionKey(actionDigest string) (EncryptionKey, error) {
+	masterKey, err := kp.source.GetMasterKey()
+	if err != nil {
+		return EncryptionKey{}, util.StatusWrap(err, "Failed to obtain master key")
+	}
+	var salt [16]byte
+	if _, err := io.ReadFull(rand.Reader, salt[:]); err != nil {
+		return EncryptionKey{}, util.StatusWrapWithCode(err, codes.Internal, "Failed to generate salt")
+	}
+	return DeriveEncryptionKey(masterKey, salt[:], actionDigest), nil
+}
+
+// DeriveEncryptionKey derives the key of a single action from a master
+// key and salt using HKDF-SHA256, using the digest of the action as
+// context information. This function is exposed, so that keys can be
+// recomputed when performing an audit.
+func DeriveEncryptionKey(masterKey MasterKey, salt []byte, actionDigest string) EncryptionKey {
+	// HKDF-Extract.
+	extract := hmac.New(sha256.New, salt)
+	extract.Write(masterKey.Key)
+	pseudoRandomKey := extract.Sum(nil)
+
+	// HKDF-Expand. As SHA-256 yields 32 bytes of output, only a
+	// single iteration is needed to obtain an AES-256 key.
+	expand := hmac.New(sha256.New, pseudoRandomKey)
+	expand.Write([]byte(actionDigest))
+	expand.Write([]byte{1})
+
+	key := EncryptionKey{ID: masterKey.ID + "/" + hex.EncodeToString(salt)}
+	copy(key.Key[:], expand.Sum(nil))
+	return key
+}
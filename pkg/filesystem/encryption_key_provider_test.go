@@ -0,0 +1,61 @@
+package filesystem_test
+
+import (
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMasterKeyDerivingEncryptionKeyProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	masterKeySource := mock.NewMockMasterKeySource(ctrl)
+	keyProvider := re_filesystem.NewMasterKeyDerivingEncryptionKeyProvider(masterKeySource)
+	masterKey := re_filesystem.MasterKey{
+		ID:  "master-key-1",
+		Key: []byte("0123456789abcdef0123456789abcdef"),
+	}
+
+	t.Run("MasterKeyFailure", func(t *testing.T) {
+		masterKeySource.EXPECT().GetMasterKey().Return(re_filesystem.MasterKey{}, status.Error(codes.Unavailable, "KMS agent offline"))
+
+		_, err := keyProvider.NewEncryptionKey("3-8b1a9953c4611296a827abf8c47804d7-123-hello")
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Failed to obtain master key: KMS agent offline"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Every call should yield a different key, even for the
+		// same action, as a random salt is used.
+		masterKeySource.EXPECT().GetMasterKey().Return(masterKey, nil).Times(2)
+
+		key1, err := keyProvider.NewEncryptionKey("3-8b1a9953c4611296a827abf8c47804d7-123-hello")
+		require.NoError(t, err)
+		key2, err := keyProvider.NewEncryptionKey("3-8b1a9953c4611296a827abf8c47804d7-123-hello")
+		require.NoError(t, err)
+		require.NotEqual(t, key1.ID, key2.ID)
+		require.NotEqual(t, key1.Key, key2.Key)
+		require.Regexp(t, "^master-key-1/[0-9a-f]{32}$", key1.ID)
+	})
+
+	t.Run("Derivation", func(t *testing.T) {
+		// Derivation must be deterministic, so that keys can be
+		// recomputed during audits. Keys should depend on the
+		// salt and the action digest.
+		salt := []byte("saltsaltsaltsalt")
+		key1 := re_filesystem.DeriveEncryptionKey(masterKey, salt, "3-8b1a9953c4611296a827abf8c47804d7-123-hello")
+		require.Equal(t, "master-key-1/73616c7473616c7473616c7473616c74", key1.ID)
+		require.Equal(t, key1, re_filesystem.DeriveEncryptionKey(masterKey, salt, "3-8b1a9953c4611296a827abf8c47804d7-123-hello"))
+
+		key2 := re_filesystem.DeriveEncryptionKey(masterKey, salt, "3-d7ac2672607ba20a44d01d03a6685b24-400-hello")
+		require.NotEqual(t, key1.Key, key2.Key)
+		key3 := re_filesystem.DeriveEncryptionKey(masterKey, []byte("pepperpepperpepp"), "3-8b1a9953c4611296a827abf8c47804d7-123-hello")
+		require.NotEqual(t, key1.Key, key3.Key)
+	})
+}
@@ -0,0 +1,208 @@
+package filesystem
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	extentSectorAllocatorPrometheusMetrics sync.Once
+
+	extentSectorAllocatorFreeSectors = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "buildbarn",
+			Subsystem: "filesystem",
+			Name:      "extent_sector_allocator_free_sectors",
+			Help:      "Number of sectors that are not allocated.",
+		})
+	extentSectorAllocatorFreeExtents = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "buildbarn",
+			Subsystem: "filesystem",
+			Name:      "extent_sector_allocator_free_extents",
+			Help:      "Number of contiguous ranges of sectors that are not allocated.",
+		})
+	extentSectorAllocatorLargestFreeExtentSectors = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "buildbarn",
+			Subsystem: "filesystem",
+			Name:      "extent_sector_allocator_largest_free_extent_sectors",
+			Help:      "Size of the largest contiguous range of sectors that is not allocated.",
+		})
+	extentSectorAllocatorFragmentedAllocations = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "filesystem",
+			Name:      "extent_sector_allocator_fragmented_allocations_total",
+			Help:      "Number of times fewer sectors were allocated contiguously than requested.",
+		})
+)
+
+// extent is a contiguous range of sectors.
+type extent struct {
+	first uint32
+	count uint32
+}
+
+type extentSectorAllocator struct {
+	lock sync.Mutex
+	// Free extents, sorted by sector number. Adjacent extents are
+	// always merged.
+	freeExtents []extent
+	freeSectors uint32
+}
+
+// NewExtentSectorAllocator creates a SectorAllocator that tracks free
+// space as a sorted list of extents (contiguous ranges of sectors).
+// Requests are satisfied using a best fit strategy, meaning that the
+// smallest extent that is large enough is used. If no such extent
+// exists, the largest extent is used instead. Extents are merged with
+// their neighbours when freed.
+//
+// Compared to BitmapSectorAllocator, this allocator tends to keep
+// large files contiguous, at the cost of allocation requiring a scan
+// over all free extents. The level of fragmentation is exposed through
+// Prometheus metrics.
+func NewExtentSectorAllocator(sectorCount uint32) SectorAllocator {
+	extentSectorAllocatorPrometheusMetrics.Do(func() {
+		prometheus.MustRegister(extentSectorAllocatorFreeSectors)
+		prometheus.MustRegister(extentSectorAllocatorFreeExtents)
+		prometheus.MustRegister(extentSectorAllocatorLargestFreeExtentSectors)
+		prometheus.MustRegister(extentSectorAllocatorFragmentedAllocations)
+	})
+
+	sa := &extentSectorAllocator{
+		freeSectors: sectorCount,
+	}
+	if sectorCount > 0 {
+		// Sector numbers handed out start at one.
+		sa.freeExtents = []extent{{first: 1, count: sectorCount}}
+	}
+	sa.updateMetricsLocked()
+	return sa
+}
+
+func (sa *extentSectorAllocator) updateMetricsLocked() {
+	largest := uint32(0)
+	for _, e := range sa.freeExtents {
+		if largest < e.count {
+			largest = e.count
+		}
+	}
+	extentSectorAllocatorFreeSectors.Set(float64(sa.freeSectors))
+	extentSectorAllocatorFreeExtents.Set(float64(len(sa.freeExtents)))
+	extentSectorAllocatorLargestFreeExtentSectors.Set(float64(largest))
+}
+
+func (sa *extentSectorAllocator) AllocateContiguous(maximum int) (uint32, int, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	if len(sa.freeExtents) == 0 {
+		return 0, 0, status.Error(codes.ResourceExhausted, "No free sectors available")
+	}
+
+	// Find the smallest extent that can hold all sectors. Fall back
+	// to the largest extent if no such extent exists.
+	bestFit, largest := -1, 0
+	for i, e := range sa.freeExtents {
+		if int64(e.count) >= int64(maximum) && (bestFit < 0 || e.count < sa.freeExtents[bestFit].count) {
+			bestFit = i
+		}
+		if e.count > sa.freeExtents[largest].count {
+			largest = i
+		}
+	}
+	index := bestFit
+	if index < 0 {
+		index = largest
+		extentSectorAllocatorFragmentedAllocations.Inc()
+	}
+
+	// Allocate sectors from the start of the extent.
+	e := &sa.freeExtents[index]
+	first := e.first
+	allocated := e.count
+	if int64(allocated) > int64(maximum) {
+		allocated = uint32(maximum)
+	}
+	if allocated == e.count {
+		sa.freeExtents = append(sa.freeExtents[:index], sa.freeExtents[index+1:]...)
+	} else {
+		e.first += allocated
+		e.count -= allocated
+	}
+	sa.freeSectors -= allocated
+	sa.updateMetricsLocked()
+	return first, int(allocated), nil
+}
+
+// freeLocked returns a contiguous range of sectors to the list of free
+// extents, merging it with its neighbours.
+func (sa *extentSectorAllocator) freeLocked(first, count uint32) {
+	// Find the first extent that is placed after the range.
+	index := sort.Search(len(sa.freeExtents), func(i int) bool {
+		return sa.freeExtents[i].first > first
+	})
+	if index > 0 {
+		if previous := sa.freeExtents[index-1]; previous.first+previous.count > first {
+			panic(fmt.Sprintf("Attempted to free sector %d, even though it's not allocated", first))
+		}
+	}
+	if index < len(sa.freeExtents) && first+count > sa.freeExtents[index].first {
+		panic(fmt.Sprintf("Attempted to free sector %d, even though it's not allocated", sa.freeExtents[index].first))
+	}
+	sa.freeSectors += count
+
+	mergePrevious := index > 0 && sa.freeExtents[index-1].first+sa.freeExtents[index-1].count == first
+	mergeNext := index < len(sa.freeExtents) && first+count == sa.freeExtents[index].first
+	switch {
+	case mergePrevious && mergeNext:
+		sa.freeExtents[index-1].count += count + sa.freeExtents[index].count
+		sa.freeExtents = append(sa.freeExtents[:index], sa.freeExtents[index+1:]...)
+	case mergePrevious:
+		sa.freeExtents[index-1].count += count
+	case mergeNext:
+		sa.freeExtents[index].first = first
+		sa.freeExtents[index].count += count
+	default:
+		sa.freeExtents = append(sa.freeExtents, extent{})
+		copy(sa.freeExtents[index+1:], sa.freeExtents[index:])
+		sa.freeExtents[index] = extent{first: first, count: count}
+	}
+}
+
+func (sa *extentSectorAllocator) FreeContiguous(first uint32, count int) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	sa.freeLocked(first, uint32(count))
+	sa.updateMetricsLocked()
+}
+
+func (sa *extentSectorAllocator) FreeList(sectors []uint32) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	// Free runs of consecutive sector numbers at once, so that the
+	// number of list operations remains proportional to the number
+	// of extents a file consists of.
+	for i := 0; i < len(sectors); {
+		first := sectors[i]
+		count := uint32(1)
+		for i+int(count) < len(sectors) && sectors[i+int(count)] == first+count && first != 0 {
+			count++
+		}
+		if first != 0 {
+			sa.freeLocked(first, count)
+		}
+		i += int(count)
+	}
+	sa.updateMetricsLocked()
+}
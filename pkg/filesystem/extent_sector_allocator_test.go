@@ -0,0 +1,73 @@
+package filesystem_test
+
+import (
+	"testing"
+
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExtentSectorAllocatorExample(t *testing.T) {
+	sectorAllocator := re_filesystem.NewExtentSectorAllocator(1000)
+
+	// Allocate five regions of sectors that span all of storage.
+	for i := 0; i < 5; i++ {
+		firstSector, sectorCount, err := sectorAllocator.AllocateContiguous(200)
+		require.NoError(t, err)
+		require.Equal(t, uint32(200*i+1), firstSector)
+		require.Equal(t, 200, sectorCount)
+	}
+
+	// Allocating successive sectors should fail.
+	_, _, err := sectorAllocator.AllocateContiguous(123)
+	require.Equal(t, status.Error(codes.ResourceExhausted, "No free sectors available"), err)
+
+	// Free the five regions out of order. Adjacent extents should
+	// be merged, bringing us back to the initial state.
+	for _, i := range []int{3, 0, 4, 1, 2} {
+		sectorAllocator.FreeContiguous(uint32(200*i+1), 200)
+	}
+
+	// Allocating a too large number of sectors should now allocate
+	// the entire space in one go.
+	firstSector, sectorCount, err := sectorAllocator.AllocateContiguous(123456)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), firstSector)
+	require.Equal(t, 1000, sectorCount)
+
+	// Free up some holes of different sizes.
+	sectorAllocator.FreeContiguous(83, 12)
+	sectorAllocator.FreeContiguous(241, 91)
+	sectorAllocator.FreeList([]uint32{503, 1000, 504, 0, 505, 1})
+
+	// Allocations should be served from the smallest hole that is
+	// large enough, so that large holes remain available.
+	for _, a := range []struct {
+		maximum     int
+		firstSector uint32
+		sectorCount int
+	}{
+		{10, 83, 10},
+		{1, 1, 1},
+		{3, 503, 3},
+		{50, 241, 50},
+		// No hole is large enough. The largest hole should
+		// be used.
+		{100, 291, 41},
+		{100, 93, 2},
+		{100, 1000, 1},
+	} {
+		firstSector, sectorCount, err := sectorAllocator.AllocateContiguous(a.maximum)
+		require.NoError(t, err)
+		require.Equal(t, a.firstSector, firstSector)
+		require.Equal(t, a.sectorCount, sectorCount)
+	}
+
+	// With all of the holes filled up, successive allocations are
+	// no longer possible.
+	_, _, err = sectorAllocator.AllocateContiguous(123)
+	require.Equal(t, status.Error(codes.ResourceExhausted, "No free sectors available"), err)
+}
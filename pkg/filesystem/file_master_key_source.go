@@ -0,0 +1,92 @@
+package filesystem
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fileMasterKeySource struct {
+	path            string
+	clock           clock.Clock
+	refreshInterval time.Duration
+
+	lock        sync.Mutex
+	masterKey   MasterKey
+	loaded      bool
+	nextRefresh time.Time
+}
+
+// NewFileMasterKeySource creates a MasterKeySource that reads the
+// master key from a JSON file on disk. Such files are typically
+// written by an agent that obtains the master key from a Key
+// Management Service (KMS).
+//
+// The file is reloaded periodically, so that the master key can be
+// rotated without restarting the worker. If reloading fails after the
+// master key has been loaded successfully at least once, the previous
+// master key continues to be used.
+func NewFileMasterKeySource(path string, clock clock.Clock, refreshInterval time.Duration) MasterKeySource {
+	return &fileMasterKeySource{
+		path:            path,
+		clock:           clock,
+		refreshInterval: refreshInterval,
+	}
+}
+
+type masterKeyFile struct {
+	KeyID string `json:"keyId"`
+	Key   []byte `json:"key"`
+}
+
+func (ks *fileMasterKeySource) loadMasterKey() (MasterKey, error) {
+	data, err := os.ReadFile(ks.path)
+	if err != nil {
+		return MasterKey{}, util.StatusWrapf(err, "Failed to read master key file %#v", ks.path)
+	}
+	var f masterKeyFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return MasterKey{}, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Failed to parse master key file %#v", ks.path)
+	}
+	if f.KeyID == "" {
+		return MasterKey{}, status.Errorf(codes.InvalidArgument, "Master key file %#v does not contain a key identifier", ks.path)
+	}
+	if len(f.Key) != 32 {
+		return MasterKey{}, status.Errorf(codes.InvalidArgument, "Master key in file %#v is %d bytes in size, while 32 bytes were expected", ks.path, len(f.Key))
+	}
+	return MasterKey{
+		ID:  f.KeyID,
+		Key: f.Key,
+	}, nil
+}
+
+func (ks *fileMasterKeySource) GetMasterKey() (MasterKey, error) {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	if now := ks.clock.Now(); !ks.loaded || !now.Before(ks.nextRefresh) {
+		masterKey, err := ks.loadMasterKey()
+		if err != nil {
+			if !ks.loaded {
+				return MasterKey{}, err
+			}
+			log.Printf("Continuing to use master key %#v: %s", ks.masterKey.ID, err)
+		} else {
+			if ks.loaded && masterKey.ID != ks.masterKey.ID {
+				log.Printf("Rotated master key from %#v to %#v", ks.masterKey.ID, masterKey.ID)
+			}
+			ks.masterKey = masterKey
+			ks.loaded = true
+		}
+		ks.nextRefresh = now.Add(ks.refreshInterval)
+	}
+	return ks.masterKey, nil
+}
@@ -0,0 +1,73 @@
+package filesystem_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFileMasterKeySource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	keyPath := filepath.Join(t.TempDir(), "master_key.json")
+	clock := mock.NewMockClock(ctrl)
+	masterKeySource := re_filesystem.NewFileMasterKeySource(keyPath, clock, time.Minute)
+
+	t.Run("InvalidKeyLength", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(keyPath, []byte(`{"keyId": "key-1", "key": "aGVsbG8="}`), 0o600))
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+
+		_, err := masterKeySource.GetMasterKey()
+		testutil.RequireEqualStatus(t, status.Errorf(codes.InvalidArgument, "Master key in file %#v is 5 bytes in size, while 32 bytes were expected", keyPath), err)
+	})
+
+	t.Run("Rotation", func(t *testing.T) {
+		// Initial load of the master key.
+		require.NoError(t, os.WriteFile(keyPath, []byte(`{"keyId": "key-1", "key": "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="}`), 0o600))
+		clock.EXPECT().Now().Return(time.Unix(1001, 0))
+
+		masterKey, err := masterKeySource.GetMasterKey()
+		require.NoError(t, err)
+		require.Equal(t, re_filesystem.MasterKey{
+			ID:  "key-1",
+			Key: []byte("0123456789abcdef0123456789abcdef"),
+		}, masterKey)
+
+		// Replacing the file should have no effect until the
+		// refresh interval has passed.
+		require.NoError(t, os.WriteFile(keyPath, []byte(`{"keyId": "key-2", "key": "ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA="}`), 0o600))
+		clock.EXPECT().Now().Return(time.Unix(1060, 0))
+
+		masterKey, err = masterKeySource.GetMasterKey()
+		require.NoError(t, err)
+		require.Equal(t, "key-1", masterKey.ID)
+
+		clock.EXPECT().Now().Return(time.Unix(1061, 0))
+
+		masterKey, err = masterKeySource.GetMasterKey()
+		require.NoError(t, err)
+		require.Equal(t, re_filesystem.MasterKey{
+			ID:  "key-2",
+			Key: []byte("fedcba9876543210fedcba9876543210"),
+		}, masterKey)
+
+		// If the file becomes unreadable, the previously
+		// loaded master key should continue to be used.
+		require.NoError(t, os.Remove(keyPath))
+		clock.EXPECT().Now().Return(time.Unix(1200, 0))
+
+		masterKey, err = masterKeySource.GetMasterKey()
+		require.NoError(t, err)
+		require.Equal(t, "key-2", masterKey.ID)
+	})
+}
@@ -2,6 +2,9 @@ package filesystem
 
 import (
 	"github.com/buildbarn/bb-storage/pkg/filesystem"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // FilePool is an allocator for temporary files. Files are created by
@@ -13,3 +16,43 @@ import (
 type FilePool interface {
 	NewFile() (filesystem.FileReadWriter, error)
 }
+
+// FileDeallocator may optionally be implemented by file handles
+// returned by FilePool.NewFile(). Deallocate() releases the storage
+// backing a range of the file, turning it into a hole. Subsequent
+// reads of the range return zero bytes. The size of the file remains
+// unaltered.
+//
+// This permits build actions to create large sparse files (e.g., disk
+// images) without consuming a proportional amount of storage.
+type FileDeallocator interface {
+	Deallocate(off, size int64) error
+}
+
+// DeallocateFile releases the storage backing a range of a file that
+// was created through a FilePool. An error is returned if the file
+// does not support deallocation.
+func DeallocateFile(f filesystem.FileReadWriter, off, size int64) error {
+	if fd, ok := f.(FileDeallocator); ok {
+		return fd.Deallocate(off, size)
+	}
+	return status.Error(codes.Unimplemented, "File does not support deallocating storage")
+}
+
+// FileAllocationReporter may optionally be implemented by file handles
+// returned by FilePool.NewFile(). GetAllocatedSizeBytes() returns the
+// amount of storage that is actually consumed by the file. For sparse
+// files, this may be less than the size of the file.
+type FileAllocationReporter interface {
+	GetAllocatedSizeBytes() (int64, error)
+}
+
+// GetFileAllocatedSizeBytes returns the amount of storage consumed by a
+// file that was created through a FilePool. An error is returned if
+// the file does not support reporting this.
+func GetFileAllocatedSizeBytes(f filesystem.FileReader) (int64, error) {
+	if far, ok := f.(FileAllocationReporter); ok {
+		return far.GetAllocatedSizeBytes()
+	}
+	return 0, status.Error(codes.Unimplemented, "File does not support reporting the amount of storage allocated")
+}
@@ -0,0 +1,308 @@
+package filesystem
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FilePoolBudget is a limit on the number of files and the total size
+// of files that may be allocated from FilePools, shared by all actions
+// running on a worker. Instead of partitioning space statically, each
+// action draws from the budget dynamically.
+//
+// Each consumer of the budget has a priority. If the budget is
+// exhausted, space is reclaimed from consumers with a lower priority
+// by invoking their reclamation callback. These callbacks are expected
+// to terminate the action, causing its files to be closed. Allocations
+// made by the higher priority consumer wait until this has happened.
+// As allocations are performed from within file system operations
+// (e.g., write() calls against the virtual file system), this wait is
+// bounded. It is also interrupted if the context of the consumer is
+// canceled.
+type FilePoolBudget struct {
+	clock              clock.Clock
+	maximumReclaimWait time.Duration
+
+	lock           sync.Mutex
+	filesRemaining int64
+	bytesRemaining int64
+	consumers      map[*budgetedFilePool]struct{}
+
+	// Channel that is closed when space is released, or when
+	// reclamation is started. It is replaced afterwards, so that
+	// allocations may wait for the next change.
+	changed chan struct{}
+}
+
+// NewFilePoolBudget creates a FilePoolBudget that permits a given
+// number of files and bytes of space to be allocated. Allocations wait
+// for at most maximumReclaimWait for space to be reclaimed from lower
+// priority consumers.
+func NewFilePoolBudget(maximumFileCount, maximumTotalSize int64, clock clock.Clock, maximumReclaimWait time.Duration) *FilePoolBudget {
+	return &FilePoolBudget{
+		clock:              clock,
+		maximumReclaimWait: maximumReclaimWait,
+		filesRemaining:     maximumFileCount,
+		bytesRemaining:     maximumTotalSize,
+		consumers:          map[*budgetedFilePool]struct{}{},
+		changed:            make(chan struct{}),
+	}
+}
+
+// BudgetedFilePool is a FilePool that draws space from a
+// FilePoolBudget. Close() must be called when the consumer no longer
+// creates any new files, so that it is no longer considered for
+// reclamation. Files that are still open continue to count towards
+// the budget until closed.
+type BudgetedFilePool interface {
+	FilePool
+
+	Close()
+}
+
+// NewFilePool creates a FilePool that draws space from the budget
+// with a given priority. Callback reclaim is invoked at most once, if
+// a consumer with a higher priority requires the space that is in use
+// by this FilePool. As it is invoked while the budget is locked, it
+// must not block on any files belonging to the budget being closed.
+//
+// Allocations that wait for space to be reclaimed are interrupted when
+// the provided context is canceled. This context should be canceled
+// when the action using the FilePool terminates.
+func (b *FilePoolBudget) NewFilePool(ctx context.Context, base FilePool, priority int, reclaim func()) BudgetedFilePool {
+	fp := &budgetedFilePool{
+		context:  ctx,
+		base:     base,
+		budget:   b,
+		priority: priority,
+		reclaim:  reclaim,
+	}
+	b.lock.Lock()
+	b.consumers[fp] = struct{}{}
+	b.lock.Unlock()
+	return fp
+}
+
+// getVictimLocked returns the consumer from which space should be
+// reclaimed to let a consumer with a given priority make progress.
+// The consumer with the lowest priority is picked, preferring the one
+// using the most space.
+func (b *FilePoolBudget) getVictimLocked(priority int) *budgetedFilePool {
+	var victim *budgetedFilePool
+	for fp := range b.consumers {
+		if fp.priority < priority && !fp.reclaimed && (fp.filesUsed > 0 || fp.bytesUsed > 0) &&
+			(victim == nil ||
+				fp.priority < victim.priority ||
+				(fp.priority == victim.priority && fp.bytesUsed > victim.bytesUsed)) {
+			victim = fp
+		}
+	}
+	return victim
+}
+
+// hasPendingReclamationsLocked returns whether there are consumers
+// from which space has been reclaimed, but which still have files
+// opened.
+func (b *FilePoolBudget) hasPendingReclamationsLocked() bool {
+	for fp := range b.consumers {
+		if fp.reclaimed && (fp.filesUsed > 0 || fp.bytesUsed > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// notifyChangedLocked wakes up all allocations that are waiting for
+// space to be reclaimed.
+func (b *FilePoolBudget) notifyChangedLocked() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+func (b *FilePoolBudget) allocate(fp *budgetedFilePool, files, bytes int64) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var timeoutChannel <-chan time.Time
+	for {
+		if fp.reclaimed {
+			return status.Error(codes.ResourceExhausted, "File pool space was reclaimed by a higher priority action")
+		}
+		if b.filesRemaining >= files && b.bytesRemaining >= bytes {
+			b.filesRemaining -= files
+			b.bytesRemaining -= bytes
+			fp.filesUsed += files
+			fp.bytesUsed += bytes
+			return nil
+		}
+
+		if victim := b.getVictimLocked(fp.priority); victim != nil {
+			// Terminate a lower priority consumer.
+			victim.reclaimed = true
+			victim.reclaim()
+			b.notifyChangedLocked()
+		} else if !b.hasPendingReclamationsLocked() {
+			if b.filesRemaining < files {
+				return status.Error(codes.InvalidArgument, "File count quota reached")
+			}
+			return status.Error(codes.InvalidArgument, "File size quota reached")
+		}
+
+		// Wait for consumers from which space is reclaimed to
+		// close their files. Don't hold the lock while waiting.
+		if timeoutChannel == nil {
+			timer, t := b.clock.NewTimer(b.maximumReclaimWait)
+			defer timer.Stop()
+			timeoutChannel = t
+		}
+		changed := b.changed
+		b.lock.Unlock()
+		select {
+		case <-changed:
+			b.lock.Lock()
+		case <-timeoutChannel:
+			b.lock.Lock()
+			return status.Error(codes.ResourceExhausted, "Timed out waiting for file pool space to be reclaimed from lower priority actions")
+		case <-fp.context.Done():
+			b.lock.Lock()
+			return util.StatusWrap(util.StatusFromContext(fp.context), "Interrupted while waiting for file pool space to be reclaimed from lower priority actions")
+		}
+	}
+}
+
+func (b *FilePoolBudget) release(fp *budgetedFilePool, files, bytes int64) {
+	b.lock.Lock()
+	b.filesRemaining += files
+	b.bytesRemaining += bytes
+	fp.filesUsed -= files
+	fp.bytesUsed -= bytes
+	if fp.closed && fp.filesUsed == 0 {
+		delete(b.consumers, fp)
+	}
+	b.notifyChangedLocked()
+	b.lock.Unlock()
+}
+
+type budgetedFilePool struct {
+	context  context.Context
+	base     FilePool
+	budget   *FilePoolBudget
+	priority int
+	reclaim  func()
+
+	// Fields protected by the lock of FilePoolBudget.
+	filesUsed int64
+	bytesUsed int64
+	reclaimed bool
+	closed    bool
+}
+
+func (fp *budgetedFilePool) NewFile() (filesystem.FileReadWriter, error) {
+	if err := fp.budget.allocate(fp, 1, 0); err != nil {
+		return nil, err
+	}
+	f, err := fp.base.NewFile()
+	if err != nil {
+		fp.budget.release(fp, 1, 0)
+		return nil, err
+	}
+	return &budgetedFile{
+		FileReadWriter: f,
+		pool:           fp,
+	}, nil
+}
+
+func (fp *budgetedFilePool) Close() {
+	b := fp.budget
+	b.lock.Lock()
+	fp.closed = true
+	if fp.filesUsed == 0 {
+		delete(b.consumers, fp)
+	}
+	b.lock.Unlock()
+}
+
+type budgetedFile struct {
+	filesystem.FileReadWriter
+
+	pool *budgetedFilePool
+	size int64
+}
+
+func (f *budgetedFile) Close() error {
+	// Close underlying file.
+	err := f.FileReadWriter.Close()
+	f.FileReadWriter = nil
+
+	// Release associated resources.
+	f.pool.budget.release(f.pool, 1, f.size)
+	f.pool = nil
+	return err
+}
+
+func (f *budgetedFile) Deallocate(off, size int64) error {
+	// Budgets are enforced on the size of files, which is not
+	// affected by deallocating storage.
+	return DeallocateFile(f.FileReadWriter, off, size)
+}
+
+func (f *budgetedFile) GetAllocatedSizeBytes() (int64, error) {
+	return GetFileAllocatedSizeBytes(f.FileReadWriter)
+}
+
+func (f *budgetedFile) Truncate(size int64) error {
+	if size < f.size {
+		// File is shrinking.
+		if err := f.FileReadWriter.Truncate(size); err != nil {
+			return err
+		}
+		f.pool.budget.release(f.pool, 0, f.size-size)
+	} else if size > f.size {
+		// File is growing.
+		additionalSpace := size - f.size
+		if err := f.pool.budget.allocate(f.pool, 0, additionalSpace); err != nil {
+			return err
+		}
+		if err := f.FileReadWriter.Truncate(size); err != nil {
+			f.pool.budget.release(f.pool, 0, additionalSpace)
+			return err
+		}
+	}
+	f.size = size
+	return nil
+}
+
+func (f *budgetedFile) WriteAt(p []byte, off int64) (int, error) {
+	// No need to allocate space if the file is not growing.
+	desiredSize := off + int64(len(p))
+	if desiredSize <= f.size {
+		return f.FileReadWriter.WriteAt(p, off)
+	}
+
+	// File is growing. Allocate space prior to writing. Release it,
+	// potentially partially, upon failure.
+	if err := f.pool.budget.allocate(f.pool, 0, desiredSize-f.size); err != nil {
+		return 0, err
+	}
+	n, err := f.FileReadWriter.WriteAt(p, off)
+	actualSize := int64(0)
+	if n > 0 {
+		actualSize = off + int64(n)
+	}
+	if actualSize < f.size {
+		actualSize = f.size
+	}
+	if actualSize < desiredSize {
+		f.pool.budget.release(f.pool, 0, desiredSize-actualSize)
+	}
+	f.size = actualSize
+	return n, err
+}
@@ -0,0 +1,179 @@
+package filesystem_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFilePoolBudget(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	t.Run("Exhausted", func(t *testing.T) {
+		// Consumers with the same priority may not reclaim space
+		// from each other.
+		budget := re_filesystem.NewFilePoolBudget(1, 100, mock.NewMockClock(ctrl), time.Minute)
+		basePool := mock.NewMockFilePool(ctrl)
+		reclaim := func() { t.Fatal("Reclamation should not occur") }
+		pool1 := budget.NewFilePool(ctx, basePool, 0, reclaim)
+		pool2 := budget.NewFilePool(ctx, basePool, 0, reclaim)
+
+		baseFile := mock.NewMockFileReadWriter(ctrl)
+		basePool.EXPECT().NewFile().Return(baseFile, nil)
+		f, err := pool1.NewFile()
+		require.NoError(t, err)
+
+		_, err = pool2.NewFile()
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "File count quota reached"), err)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "File size quota reached"), f.Truncate(101))
+
+		// Closing the file should make space available to the
+		// other consumer.
+		baseFile.EXPECT().Close()
+		require.NoError(t, f.Close())
+		pool1.Close()
+
+		baseFile.EXPECT().Truncate(int64(100))
+		basePool.EXPECT().NewFile().Return(baseFile, nil)
+		f, err = pool2.NewFile()
+		require.NoError(t, err)
+		require.NoError(t, f.Truncate(100))
+		baseFile.EXPECT().Close()
+		require.NoError(t, f.Close())
+		pool2.Close()
+	})
+
+	t.Run("Reclamation", func(t *testing.T) {
+		clock := mock.NewMockClock(ctrl)
+		budget := re_filesystem.NewFilePoolBudget(10, 100, clock, time.Minute)
+		basePool := mock.NewMockFilePool(ctrl)
+
+		// Let a low priority consumer use most of the space.
+		lowPriorityBaseFile := mock.NewMockFileReadWriter(ctrl)
+		basePool.EXPECT().NewFile().Return(lowPriorityBaseFile, nil)
+		lowPriorityBaseFile.EXPECT().Truncate(int64(80))
+		reclaimed := make(chan struct{})
+		lowPriorityPool := budget.NewFilePool(ctx, basePool, 0, func() { close(reclaimed) })
+		lowPriorityFile, err := lowPriorityPool.NewFile()
+		require.NoError(t, err)
+		require.NoError(t, lowPriorityFile.Truncate(80))
+
+		// Once a higher priority consumer requires space, the low
+		// priority consumer should be asked to terminate. The
+		// allocation should block until its files are closed.
+		highPriorityBaseFile := mock.NewMockFileReadWriter(ctrl)
+		basePool.EXPECT().NewFile().Return(highPriorityBaseFile, nil)
+		highPriorityPool := budget.NewFilePool(ctx, basePool, 1, func() { t.Fatal("Reclamation should not occur") })
+		highPriorityFile, err := highPriorityPool.NewFile()
+		require.NoError(t, err)
+
+		go func() {
+			<-reclaimed
+
+			// The low priority consumer should no longer be
+			// able to allocate space.
+			_, err := lowPriorityPool.NewFile()
+			testutil.RequireEqualStatus(t, status.Error(codes.ResourceExhausted, "File pool space was reclaimed by a higher priority action"), err)
+
+			lowPriorityBaseFile.EXPECT().Close()
+			require.NoError(t, lowPriorityFile.Close())
+			lowPriorityPool.Close()
+		}()
+
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		timer.EXPECT().Stop()
+		highPriorityBaseFile.EXPECT().Truncate(int64(50))
+		require.NoError(t, highPriorityFile.Truncate(50))
+
+		highPriorityBaseFile.EXPECT().Close()
+		require.NoError(t, highPriorityFile.Close())
+		highPriorityPool.Close()
+	})
+
+	t.Run("ReclamationTimeout", func(t *testing.T) {
+		clock := mock.NewMockClock(ctrl)
+		budget := re_filesystem.NewFilePoolBudget(10, 100, clock, time.Minute)
+		basePool := mock.NewMockFilePool(ctrl)
+
+		lowPriorityBaseFile := mock.NewMockFileReadWriter(ctrl)
+		basePool.EXPECT().NewFile().Return(lowPriorityBaseFile, nil)
+		lowPriorityBaseFile.EXPECT().Truncate(int64(80))
+		reclaimed := false
+		lowPriorityPool := budget.NewFilePool(ctx, basePool, 0, func() { reclaimed = true })
+		lowPriorityFile, err := lowPriorityPool.NewFile()
+		require.NoError(t, err)
+		require.NoError(t, lowPriorityFile.Truncate(80))
+
+		highPriorityBaseFile := mock.NewMockFileReadWriter(ctrl)
+		basePool.EXPECT().NewFile().Return(highPriorityBaseFile, nil)
+		highPriorityPool := budget.NewFilePool(ctx, basePool, 1, func() { t.Fatal("Reclamation should not occur") })
+		highPriorityFile, err := highPriorityPool.NewFile()
+		require.NoError(t, err)
+
+		// If the low priority consumer does not close its
+		// files in time, the allocation should fail instead of
+		// blocking indefinitely.
+		timer := mock.NewMockTimer(ctrl)
+		timerChannel := make(chan time.Time, 1)
+		timerChannel <- time.Unix(1000, 0)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, timerChannel)
+		timer.EXPECT().Stop()
+		testutil.RequireEqualStatus(t, status.Error(codes.ResourceExhausted, "Timed out waiting for file pool space to be reclaimed from lower priority actions"), highPriorityFile.Truncate(50))
+		require.True(t, reclaimed)
+
+		lowPriorityBaseFile.EXPECT().Close()
+		require.NoError(t, lowPriorityFile.Close())
+		lowPriorityPool.Close()
+		highPriorityBaseFile.EXPECT().Close()
+		require.NoError(t, highPriorityFile.Close())
+		highPriorityPool.Close()
+	})
+
+	t.Run("ReclamationCanceled", func(t *testing.T) {
+		clock := mock.NewMockClock(ctrl)
+		budget := re_filesystem.NewFilePoolBudget(10, 100, clock, time.Minute)
+		basePool := mock.NewMockFilePool(ctrl)
+
+		lowPriorityBaseFile := mock.NewMockFileReadWriter(ctrl)
+		basePool.EXPECT().NewFile().Return(lowPriorityBaseFile, nil)
+		lowPriorityBaseFile.EXPECT().Truncate(int64(80))
+		lowPriorityPool := budget.NewFilePool(ctx, basePool, 0, func() {})
+		lowPriorityFile, err := lowPriorityPool.NewFile()
+		require.NoError(t, err)
+		require.NoError(t, lowPriorityFile.Truncate(80))
+
+		// Allocations should be interrupted if the action of
+		// the consumer terminates.
+		highPriorityCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		highPriorityBaseFile := mock.NewMockFileReadWriter(ctrl)
+		basePool.EXPECT().NewFile().Return(highPriorityBaseFile, nil)
+		highPriorityPool := budget.NewFilePool(highPriorityCtx, basePool, 1, func() { t.Fatal("Reclamation should not occur") })
+		highPriorityFile, err := highPriorityPool.NewFile()
+		require.NoError(t, err)
+
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		timer.EXPECT().Stop()
+		n, err := highPriorityFile.WriteAt([]byte("Hello"), 98)
+		require.Equal(t, 0, n)
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "Interrupted while waiting for file pool space to be reclaimed from lower priority actions: context canceled"), err)
+
+		lowPriorityBaseFile.EXPECT().Close()
+		require.NoError(t, lowPriorityFile.Close())
+		lowPriorityPool.Close()
+		highPriorityBaseFile.EXPECT().Close()
+		require.NoError(t, highPriorityFile.Close())
+		highPriorityPool.Close()
+	})
+}
@@ -4,6 +4,9 @@ import (
 	"io"
 
 	"github.com/buildbarn/bb-storage/pkg/filesystem"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type inMemoryFilePool struct{}
@@ -21,6 +24,31 @@ func (f *inMemoryFile) Close() error {
 	return nil
 }
 
+func (f *inMemoryFile) Deallocate(off, size int64) error {
+	if off < 0 {
+		return status.Errorf(codes.InvalidArgument, "Negative deallocation offset: %d", off)
+	}
+	if size < 0 {
+		return status.Errorf(codes.InvalidArgument, "Negative deallocation size: %d", size)
+	}
+
+	// Files are stored contiguously, meaning that storage cannot
+	// be released. Simply zero the range.
+	if off < int64(len(f.data)) {
+		end := off + size
+		if end > int64(len(f.data)) {
+			end = int64(len(f.data))
+		}
+		clear(f.data[off:end])
+	}
+	return nil
+}
+
+func (f *inMemoryFile) GetAllocatedSizeBytes() (int64, error) {
+	// Files are stored contiguously, so there is no sparseness.
+	return int64(len(f.data)), nil
+}
+
 func (f *inMemoryFile) GetNextRegionOffset(off int64, regionType filesystem.RegionType) (int64, error) {
 	// Files are stored in a byte slice contiguously, so there is no
 	// sparseness.
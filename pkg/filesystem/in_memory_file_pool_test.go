@@ -62,4 +62,25 @@ func TestInMemoryFilePool(t *testing.T) {
 
 		require.NoError(t, f.Close())
 	})
+
+	t.Run("Deallocate", func(t *testing.T) {
+		f, err := fp.NewFile()
+		require.NoError(t, err)
+
+		n, err := f.WriteAt([]byte("Hello, world"), 0)
+		require.Equal(t, 12, n)
+		require.NoError(t, err)
+
+		// Deallocating a range should cause it to be zeroed,
+		// without affecting the size of the file.
+		require.NoError(t, filesystem.DeallocateFile(f, 5, 100))
+
+		var p [16]byte
+		n, err = f.ReadAt(p[:], 0)
+		require.Equal(t, 12, n)
+		require.Equal(t, io.EOF, err)
+		require.Equal(t, []byte("Hello\x00\x00\x00\x00\x00\x00\x00"), p[:n])
+
+		require.NoError(t, f.Close())
+	})
 }
@@ -24,6 +24,41 @@ var (
 			Name:      "file_pool_files_closed_total",
 			Help:      "Number of times a file was closed that is backed by a file pool.",
 		})
+
+	namedFilePoolPrometheusMetrics sync.Once
+
+	namedFilePoolFilesOpen = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "buildbarn",
+			Subsystem: "filesystem",
+			Name:      "named_file_pool_files_open",
+			Help:      "Number of files backed by a named file pool that are currently opened.",
+		},
+		[]string{"name"})
+	namedFilePoolSizeBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "buildbarn",
+			Subsystem: "filesystem",
+			Name:      "named_file_pool_size_bytes",
+			Help:      "Total size of all files backed by a named file pool that are currently opened.",
+		},
+		[]string{"name"})
+	namedFilePoolCapacityBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "buildbarn",
+			Subsystem: "filesystem",
+			Name:      "named_file_pool_capacity_bytes",
+			Help:      "Amount of storage that is available to a named file pool, if known.",
+		},
+		[]string{"name"})
+	namedFilePoolAllocationFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "filesystem",
+			Name:      "named_file_pool_allocation_failures_total",
+			Help:      "Number of times creating or growing a file backed by a named file pool failed.",
+		},
+		[]string{"name"})
 )
 
 type metricsFilePool struct {
@@ -64,3 +99,115 @@ func (f *metricsFile) Close() error {
 	filePoolFilesClosed.Inc()
 	return err
 }
+
+func (f *metricsFile) Deallocate(off, size int64) error {
+	return DeallocateFile(f.FileReadWriter, off, size)
+}
+
+func (f *metricsFile) GetAllocatedSizeBytes() (int64, error) {
+	return GetFileAllocatedSizeBytes(f.FileReadWriter)
+}
+
+type namedMetricsFilePool struct {
+	base FilePool
+
+	filesOpen          prometheus.Gauge
+	sizeBytes          prometheus.Gauge
+	allocationFailures prometheus.Counter
+}
+
+// NewNamedMetricsFilePool creates a decorator for FilePool that exposes
+// Prometheus metrics on the utilization of a file pool, labeled with a
+// name provided through configuration. This makes it possible to
+// monitor individual file pools when they are nested (e.g., the
+// backend of a spilling file pool), or when multiple workers running
+// on the same host are each configured to use a file pool of their
+// own.
+//
+// If the capacity of the file pool is known, it is exposed as well, so
+// that alerts may be raised when file pools are about to run out of
+// space. A capacity of zero indicates that it is unknown.
+func NewNamedMetricsFilePool(base FilePool, name string, capacityBytes int64) FilePool {
+	namedFilePoolPrometheusMetrics.Do(func() {
+		prometheus.MustRegister(namedFilePoolFilesOpen)
+		prometheus.MustRegister(namedFilePoolSizeBytes)
+		prometheus.MustRegister(namedFilePoolCapacityBytes)
+		prometheus.MustRegister(namedFilePoolAllocationFailures)
+	})
+
+	if capacityBytes > 0 {
+		namedFilePoolCapacityBytes.WithLabelValues(name).Set(float64(capacityBytes))
+	}
+	return &namedMetricsFilePool{
+		base: base,
+
+		filesOpen:          namedFilePoolFilesOpen.WithLabelValues(name),
+		sizeBytes:          namedFilePoolSizeBytes.WithLabelValues(name),
+		allocationFailures: namedFilePoolAllocationFailures.WithLabelValues(name),
+	}
+}
+
+func (fp *namedMetricsFilePool) NewFile() (filesystem.FileReadWriter, error) {
+	f, err := fp.base.NewFile()
+	if err != nil {
+		fp.allocationFailures.Inc()
+		return nil, err
+	}
+	fp.filesOpen.Inc()
+	return &namedMetricsFile{
+		FileReadWriter: f,
+		pool:           fp,
+	}, nil
+}
+
+type namedMetricsFile struct {
+	filesystem.FileReadWriter
+	pool *namedMetricsFilePool
+	size int64
+}
+
+// setSize updates the size of the file, adjusting the total size of
+// the file pool accordingly.
+func (f *namedMetricsFile) setSize(size int64) {
+	f.pool.sizeBytes.Add(float64(size - f.size))
+	f.size = size
+}
+
+func (f *namedMetricsFile) Close() error {
+	err := f.FileReadWriter.Close()
+	f.FileReadWriter = nil
+	f.setSize(0)
+	f.pool.filesOpen.Dec()
+	f.pool = nil
+	return err
+}
+
+func (f *namedMetricsFile) Deallocate(off, size int64) error {
+	return DeallocateFile(f.FileReadWriter, off, size)
+}
+
+func (f *namedMetricsFile) GetAllocatedSizeBytes() (int64, error) {
+	return GetFileAllocatedSizeBytes(f.FileReadWriter)
+}
+
+func (f *namedMetricsFile) Truncate(size int64) error {
+	if err := f.FileReadWriter.Truncate(size); err != nil {
+		if size > f.size {
+			f.pool.allocationFailures.Inc()
+		}
+		return err
+	}
+	f.setSize(size)
+	return nil
+}
+
+func (f *namedMetricsFile) WriteAt(p []byte, off int64) (int, error) {
+	n, err := f.FileReadWriter.WriteAt(p, off)
+	if end := off + int64(n); end > f.size {
+		f.setSize(end)
+	}
+	if err != nil && off+int64(len(p)) > f.size {
+		f.pool.allocationFailures.Inc()
+	}
+	return n, err
+}
@@ -0,0 +1,88 @@
+package filesystem_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	prom_testutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNamedMetricsFilePool(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	basePool := mock.NewMockFilePool(ctrl)
+	fp := re_filesystem.NewNamedMetricsFilePool(basePool, "TestNamedMetricsFilePool", 1000)
+
+	requireMetrics := func(t *testing.T, filesOpen, sizeBytes, allocationFailures int) {
+		require.NoError(t, prom_testutil.GatherAndCompare(
+			prometheus.DefaultGatherer,
+			strings.NewReader(`
+# HELP buildbarn_filesystem_named_file_pool_allocation_failures_total Number of times creating or growing a file backed by a named file pool failed.
+# TYPE buildbarn_filesystem_named_file_pool_allocation_failures_total counter
+buildbarn_filesystem_named_file_pool_allocation_failures_total{name="TestNamedMetricsFilePool"} `+strconv.Itoa(allocationFailures)+`
+# HELP buildbarn_filesystem_named_file_pool_capacity_bytes Amount of storage that is available to a named file pool, if known.
+# TYPE buildbarn_filesystem_named_file_pool_capacity_bytes gauge
+buildbarn_filesystem_named_file_pool_capacity_bytes{name="TestNamedMetricsFilePool"} 1000
+# HELP buildbarn_filesystem_named_file_pool_files_open Number of files backed by a named file pool that are currently opened.
+# TYPE buildbarn_filesystem_named_file_pool_files_open gauge
+buildbarn_filesystem_named_file_pool_files_open{name="TestNamedMetricsFilePool"} `+strconv.Itoa(filesOpen)+`
+# HELP buildbarn_filesystem_named_file_pool_size_bytes Total size of all files backed by a named file pool that are currently opened.
+# TYPE buildbarn_filesystem_named_file_pool_size_bytes gauge
+buildbarn_filesystem_named_file_pool_size_bytes{name="TestNamedMetricsFilePool"} `+strconv.Itoa(sizeBytes)+`
+`),
+			"buildbarn_filesystem_named_file_pool_allocation_failures_total",
+			"buildbarn_filesystem_named_file_pool_capacity_bytes",
+			"buildbarn_filesystem_named_file_pool_files_open",
+			"buildbarn_filesystem_named_file_pool_size_bytes"))
+	}
+
+	t.Run("CreationFailure", func(t *testing.T) {
+		basePool.EXPECT().NewFile().Return(nil, status.Error(codes.ResourceExhausted, "Out of file descriptors"))
+
+		_, err := fp.NewFile()
+		testutil.RequireEqualStatus(t, status.Error(codes.ResourceExhausted, "Out of file descriptors"), err)
+		requireMetrics(t, 0, 0, 1)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		baseFile := mock.NewMockFileReadWriter(ctrl)
+		basePool.EXPECT().NewFile().Return(baseFile, nil)
+
+		f, err := fp.NewFile()
+		require.NoError(t, err)
+		requireMetrics(t, 1, 0, 1)
+
+		// Writes and truncations should cause the size of the
+		// file pool to be adjusted.
+		baseFile.EXPECT().WriteAt([]byte("Hello"), int64(100)).Return(5, nil)
+		n, err := f.WriteAt([]byte("Hello"), 100)
+		require.NoError(t, err)
+		require.Equal(t, 5, n)
+		requireMetrics(t, 1, 105, 1)
+
+		baseFile.EXPECT().Truncate(int64(10))
+		require.NoError(t, f.Truncate(10))
+		requireMetrics(t, 1, 10, 1)
+
+		// Failing to grow the file should be counted as an
+		// allocation failure.
+		baseFile.EXPECT().Truncate(int64(2000)).Return(status.Error(codes.ResourceExhausted, "Out of space"))
+		testutil.RequireEqualStatus(t, status.Error(codes.ResourceExhausted, "Out of space"), f.Truncate(2000))
+		requireMetrics(t, 1, 10, 2)
+
+		// Closing the file should release all of its space.
+		baseFile.EXPECT().Close()
+		require.NoError(t, f.Close())
+		requireMetrics(t, 0, 0, 2)
+	})
+}
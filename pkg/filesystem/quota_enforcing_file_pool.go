@@ -87,6 +87,16 @@ func (f *quotaEnforcingFile) Close() error {
 	return err
 }
 
+func (f *quotaEnforcingFile) Deallocate(off, size int64) error {
+	// Quotas are enforced on the size of files, which is not
+	// affected by deallocating storage.
+	return DeallocateFile(f.FileReadWriter, off, size)
+}
+
+func (f *quotaEnforcingFile) GetAllocatedSizeBytes() (int64, error) {
+	return GetFileAllocatedSizeBytes(f.FileReadWriter)
+}
+
 func (f *quotaEnforcingFile) Truncate(size int64) error {
 	if size < f.size {
 		// File is shrinking.
@@ -0,0 +1,169 @@
+package filesystem
+
+import (
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type spillingFilePool struct {
+	base                 FilePool
+	maximumFileSizeBytes int64
+	memoryBytesRemaining quotaMetric
+}
+
+// NewSpillingFilePool creates a FilePool that initially stores the
+// contents of files in memory. Once a file grows beyond a given size,
+// or once the total amount of memory used by all files in this pool
+// exceeds a given budget, the contents of the file are transparently
+// moved to another FilePool (e.g., one backed by a directory or a
+// block device).
+//
+// As most files created by build actions tend to be small, this
+// reduces the number of I/O operations that need to be performed
+// against disks. This is especially beneficial when disks are network
+// attached.
+func NewSpillingFilePool(base FilePool, maximumFileSizeBytes, maximumTotalSizeBytes int64) FilePool {
+	fp := &spillingFilePool{
+		base:                 base,
+		maximumFileSizeBytes: maximumFileSizeBytes,
+	}
+	fp.memoryBytesRemaining.remaining.Store(maximumTotalSizeBytes)
+	return fp
+}
+
+func (fp *spillingFilePool) NewFile() (filesystem.FileReadWriter, error) {
+	return &spillingFile{
+		pool: fp,
+	}, nil
+}
+
+// spillingFile is a file that is either stored in memory, or has been
+// spilled to the underlying FilePool. In the former case, base is nil.
+type spillingFile struct {
+	pool     *spillingFilePool
+	inMemory inMemoryFile
+	base     filesystem.FileReadWriter
+}
+
+// growInMemory attempts to grow the in-memory copy of the file to a
+// given size. When this is not possible, because the file would become
+// too large, or because the memory budget has been exhausted, the file
+// is spilled to the underlying FilePool.
+func (f *spillingFile) growInMemory(size int64) error {
+	currentSize := int64(len(f.inMemory.data))
+	if size <= currentSize {
+		return nil
+	}
+	if size <= f.pool.maximumFileSizeBytes && f.pool.memoryBytesRemaining.allocate(size-currentSize) {
+		return nil
+	}
+	return f.spill()
+}
+
+// spill the contents of the file to the underlying FilePool.
+// Subsequent operations are forwarded to the newly created file.
+func (f *spillingFile) spill() error {
+	base, err := f.pool.base.NewFile()
+	if err != nil {
+		return util.StatusWrap(err, "Failed to create file in underlying file pool")
+	}
+	if data := f.inMemory.data; len(data) > 0 {
+		if _, err := base.WriteAt(data, 0); err != nil {
+			base.Close()
+			return util.StatusWrap(err, "Failed to copy file contents to underlying file pool")
+		}
+	}
+	f.releaseInMemory()
+	f.base = base
+	return nil
+}
+
+// releaseInMemory discards the in-memory copy of the file, returning
+// the memory it used to the budget of the pool.
+func (f *spillingFile) releaseInMemory() {
+	f.pool.memoryBytesRemaining.release(int64(len(f.inMemory.data)))
+	f.inMemory.Close()
+}
+
+func (f *spillingFile) Close() error {
+	var err error
+	if f.base == nil {
+		f.releaseInMemory()
+	} else {
+		err = f.base.Close()
+		f.base = nil
+	}
+	f.pool = nil
+	return err
+}
+
+func (f *spillingFile) Deallocate(off, size int64) error {
+	if f.base == nil {
+		return f.inMemory.Deallocate(off, size)
+	}
+	return DeallocateFile(f.base, off, size)
+}
+
+func (f *spillingFile) GetAllocatedSizeBytes() (int64, error) {
+	if f.base == nil {
+		return f.inMemory.GetAllocatedSizeBytes()
+	}
+	return GetFileAllocatedSizeBytes(f.base)
+}
+
+func (f *spillingFile) GetNextRegionOffset(off int64, regionType filesystem.RegionType) (int64, error) {
+	if f.base == nil {
+		return f.inMemory.GetNextRegionOffset(off, regionType)
+	}
+	return f.base.GetNextRegionOffset(off, regionType)
+}
+
+func (f *spillingFile) ReadAt(p []byte, off int64) (int, error) {
+	if f.base == nil {
+		return f.inMemory.ReadAt(p, off)
+	}
+	return f.base.ReadAt(p, off)
+}
+
+func (f *spillingFile) Sync() error {
+	if f.base == nil {
+		return f.inMemory.Sync()
+	}
+	return f.base.Sync()
+}
+
+func (f *spillingFile) Truncate(size int64) error {
+	if size < 0 {
+		return status.Errorf(codes.InvalidArgument, "Negative truncation size: %d", size)
+	}
+	if f.base == nil {
+		if err := f.growInMemory(size); err != nil {
+			return err
+		}
+		if f.base == nil {
+			if shrinkage := int64(len(f.inMemory.data)) - size; shrinkage > 0 {
+				f.pool.memoryBytesRemaining.release(shrinkage)
+			}
+			return f.inMemory.Truncate(size)
+		}
+	}
+	return f.base.Truncate(size)
+}
+
+func (f *spillingFile) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "Negative write offset: %d", off)
+	}
+	if f.base == nil && len(p) > 0 {
+		if err := f.growInMemory(off + int64(len(p))); err != nil {
+			return 0, err
+		}
+	}
+	if f.base == nil {
+		return f.inMemory.WriteAt(p, off)
+	}
+	return f.base.WriteAt(p, off)
+}
@@ -0,0 +1,113 @@
+package filesystem_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSpillingFilePool(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	basePool := mock.NewMockFilePool(ctrl)
+	pool := re_filesystem.NewSpillingFilePool(basePool, 10, 15)
+
+	t.Run("InMemory", func(t *testing.T) {
+		// Small files should not touch the underlying file pool.
+		f, err := pool.NewFile()
+		require.NoError(t, err)
+
+		n, err := f.WriteAt([]byte("Hello"), 3)
+		require.Equal(t, 5, n)
+		require.NoError(t, err)
+
+		var p [10]byte
+		n, err = f.ReadAt(p[:], 0)
+		require.Equal(t, 8, n)
+		require.Equal(t, io.EOF, err)
+		require.Equal(t, []byte("\x00\x00\x00Hello"), p[:8])
+
+		require.NoError(t, f.Truncate(10))
+		require.NoError(t, f.Close())
+	})
+
+	t.Run("FileTooLarge", func(t *testing.T) {
+		// Growing a file beyond the maximum file size should
+		// cause its contents to be copied into the underlying
+		// file pool.
+		f, err := pool.NewFile()
+		require.NoError(t, err)
+
+		n, err := f.WriteAt([]byte("Hello"), 0)
+		require.Equal(t, 5, n)
+		require.NoError(t, err)
+
+		baseFile := mock.NewMockFileReadWriter(ctrl)
+		basePool.EXPECT().NewFile().Return(baseFile, nil)
+		baseFile.EXPECT().WriteAt([]byte("Hello"), int64(0)).Return(5, nil)
+		baseFile.EXPECT().WriteAt([]byte("world"), int64(8)).Return(5, nil)
+
+		n, err = f.WriteAt([]byte("world"), 8)
+		require.Equal(t, 5, n)
+		require.NoError(t, err)
+
+		// Subsequent operations should go to the underlying file.
+		baseFile.EXPECT().Truncate(int64(3)).Return(nil)
+		require.NoError(t, f.Truncate(3))
+
+		baseFile.EXPECT().Close().Return(nil)
+		require.NoError(t, f.Close())
+	})
+
+	t.Run("BudgetExhausted", func(t *testing.T) {
+		// Even if files are small, they should be spilled once
+		// the total memory budget has been exhausted.
+		f1, err := pool.NewFile()
+		require.NoError(t, err)
+		require.NoError(t, f1.Truncate(10))
+
+		f2, err := pool.NewFile()
+		require.NoError(t, err)
+		require.NoError(t, f2.Truncate(5))
+
+		baseFile := mock.NewMockFileReadWriter(ctrl)
+		basePool.EXPECT().NewFile().Return(baseFile, nil)
+		baseFile.EXPECT().WriteAt(make([]byte, 5), int64(0)).Return(5, nil)
+		baseFile.EXPECT().Truncate(int64(6)).Return(nil)
+		require.NoError(t, f2.Truncate(6))
+
+		// Closing the first file should release its memory,
+		// allowing new files to be stored in memory again.
+		require.NoError(t, f1.Close())
+
+		f3, err := pool.NewFile()
+		require.NoError(t, err)
+		require.NoError(t, f3.Truncate(10))
+		require.NoError(t, f3.Close())
+
+		baseFile.EXPECT().Close().Return(nil)
+		require.NoError(t, f2.Close())
+	})
+
+	t.Run("SpillFailure", func(t *testing.T) {
+		// Failures to create files in the underlying file pool
+		// should be propagated.
+		f, err := pool.NewFile()
+		require.NoError(t, err)
+
+		basePool.EXPECT().NewFile().Return(nil, status.Error(codes.ResourceExhausted, "Out of space"))
+
+		_, err = f.WriteAt(make([]byte, 11), 0)
+		testutil.RequireEqualStatus(t, status.Error(codes.ResourceExhausted, "Failed to create file in underlying file pool: Out of space"), err)
+
+		require.NoError(t, f.Close())
+	})
+}
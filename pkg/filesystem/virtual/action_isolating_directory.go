@@ -0,0 +1,75 @@
+package virtual
+
+import (
+	"context"
+
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+)
+
+type actionIsolatingDirectory struct {
+	Directory
+}
+
+// NewActionIsolatingDirectory creates a decorator for the root
+// directory of a mount that is shared by multiple concurrently running
+// actions, each having its own subdirectory. Children of the root
+// directory can only be accessed by name, similar to a UNIX directory
+// that only has execute permissions set. Listing the directory, and
+// creating, removing or renaming children through the mount are
+// denied.
+//
+// When combined with subdirectory names that cannot be guessed, this
+// prevents actions from accessing each other's subdirectories, without
+// requiring a separate mount per action.
+func NewActionIsolatingDirectory(base Directory) Directory {
+	return &actionIsolatingDirectory{
+		Directory: base,
+	}
+}
+
+func (d *actionIsolatingDirectory) VirtualGetAttributes(ctx context.Context, requested AttributesMask, attributes *Attributes) {
+	d.Directory.VirtualGetAttributes(ctx, requested, attributes)
+	if requested&AttributesMaskPermissions != 0 {
+		attributes.SetPermissions(PermissionsExecute)
+	}
+}
+
+func (d *actionIsolatingDirectory) VirtualSetAttributes(ctx context.Context, in *Attributes, requested AttributesMask, out *Attributes) Status {
+	return StatusErrPerm
+}
+
+func (d *actionIsolatingDirectory) VirtualOpenChild(ctx context.Context, name path.Component, shareAccess ShareMask, createAttributes *Attributes, existingOptions *OpenExistingOptions, requested AttributesMask, openedFileAttributes *Attributes) (Leaf, AttributesMask, ChangeInfo, Status) {
+	if createAttributes != nil {
+		return nil, 0, ChangeInfo{}, StatusErrAccess
+	}
+	return d.Directory.VirtualOpenChild(ctx, name, shareAccess, nil, existingOptions, requested, openedFileAttributes)
+}
+
+func (d *actionIsolatingDirectory) VirtualLink(ctx context.Context, name path.Component, leaf Leaf, requested AttributesMask, out *Attributes) (ChangeInfo, Status) {
+	return ChangeInfo{}, StatusErrAccess
+}
+
+func (d *actionIsolatingDirectory) VirtualMkdir(name path.Component, requested AttributesMask, out *Attributes) (Directory, ChangeInfo, Status) {
+	return nil, ChangeInfo{}, StatusErrAccess
+}
+
+func (d *actionIsolatingDirectory) VirtualMknod(ctx context.Context, name path.Component, fileType filesystem.FileType, requested AttributesMask, out *Attributes) (Leaf, ChangeInfo, Status) {
+	return nil, ChangeInfo{}, StatusErrAccess
+}
+
+func (d *actionIsolatingDirectory) VirtualReadDir(ctx context.Context, firstCookie uint64, requested AttributesMask, reporter DirectoryEntryReporter) Status {
+	return StatusErrAccess
+}
+
+func (d *actionIsolatingDirectory) VirtualRename(oldName path.Component, newDirectory Directory, newName path.Component) (ChangeInfo, ChangeInfo, Status) {
+	return ChangeInfo{}, ChangeInfo{}, StatusErrAccess
+}
+
+func (d *actionIsolatingDirectory) VirtualRemove(name path.Component, removeDirectory, removeLeaf bool) (ChangeInfo, Status) {
+	return ChangeInfo{}, StatusErrAccess
+}
+
+func (d *actionIsolatingDirectory) VirtualSymlink(ctx context.Context, pointedTo []byte, linkName path.Component, requested AttributesMask, out *Attributes) (Leaf, ChangeInfo, Status) {
+	return nil, ChangeInfo{}, StatusErrAccess
+}
@@ -0,0 +1,68 @@
+package virtual_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionIsolatingDirectory(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseDirectory := mock.NewMockVirtualDirectory(ctrl)
+	directory := virtual.NewActionIsolatingDirectory(baseDirectory)
+
+	t.Run("VirtualGetAttributes", func(t *testing.T) {
+		// The directory should only be searchable.
+		baseDirectory.EXPECT().VirtualGetAttributes(ctx, virtual.AttributesMaskFileType|virtual.AttributesMaskPermissions, gomock.Any()).
+			Do(func(ctx context.Context, requested virtual.AttributesMask, attributes *virtual.Attributes) {
+				attributes.SetFileType(filesystem.FileTypeDirectory)
+				attributes.SetPermissions(virtual.PermissionsRead | virtual.PermissionsWrite | virtual.PermissionsExecute)
+			})
+
+		var attributes virtual.Attributes
+		directory.VirtualGetAttributes(ctx, virtual.AttributesMaskFileType|virtual.AttributesMaskPermissions, &attributes)
+		require.Equal(
+			t,
+			(&virtual.Attributes{}).
+				SetFileType(filesystem.FileTypeDirectory).
+				SetPermissions(virtual.PermissionsExecute),
+			&attributes)
+	})
+
+	t.Run("VirtualLookup", func(t *testing.T) {
+		// Children should remain accessible by name.
+		childDirectory := mock.NewMockVirtualDirectory(ctrl)
+		baseDirectory.EXPECT().VirtualLookup(ctx, path.MustNewComponent("3f9a6c21d8e4b507"), virtual.AttributesMask(0), gomock.Any()).
+			Return(virtual.DirectoryChild{}.FromDirectory(childDirectory), virtual.StatusOK)
+
+		var attributes virtual.Attributes
+		child, s := directory.VirtualLookup(ctx, path.MustNewComponent("3f9a6c21d8e4b507"), 0, &attributes)
+		require.Equal(t, virtual.StatusOK, s)
+		require.Equal(t, virtual.DirectoryChild{}.FromDirectory(childDirectory), child)
+	})
+
+	t.Run("VirtualReadDir", func(t *testing.T) {
+		// Listing the directory would reveal the names of
+		// build directories of other actions.
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		require.Equal(t, virtual.StatusErrAccess, directory.VirtualReadDir(ctx, 0, 0, reporter))
+	})
+
+	t.Run("VirtualMkdir", func(t *testing.T) {
+		var attributes virtual.Attributes
+		_, _, s := directory.VirtualMkdir(path.MustNewComponent("foo"), 0, &attributes)
+		require.Equal(t, virtual.StatusErrAccess, s)
+	})
+
+	t.Run("VirtualRemove", func(t *testing.T) {
+		_, s := directory.VirtualRemove(path.MustNewComponent("3f9a6c21d8e4b507"), true, false)
+		require.Equal(t, virtual.StatusErrAccess, s)
+	})
+}
@@ -2,9 +2,12 @@ package virtual
 
 import (
 	"context"
+	"os"
 	"syscall"
+	"time"
 
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_clock "github.com/buildbarn/bb-remote-execution/pkg/clock"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/outputpathpersistency"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
 	"github.com/buildbarn/bb-storage/pkg/blobstore"
@@ -32,7 +35,7 @@ func NewBlobAccessCASFileFactory(ctx context.Context, contentAddressableStorage
 	}
 }
 
-func (cff *blobAccessCASFileFactory) LookupFile(blobDigest digest.Digest, isExecutable bool, readMonitor FileReadMonitor) NativeLeaf {
+func (cff *blobAccessCASFileFactory) LookupFile(blobDigest digest.Digest, isExecutable bool, lastDataModificationTime time.Time, readMonitor FileReadMonitor) NativeLeaf {
 	if readMonitor != nil {
 		panic("The read monitor should have been set up by StatelessHandleAllocatingCASFileFactory")
 	}
@@ -40,10 +43,21 @@ func (cff *blobAccessCASFileFactory) LookupFile(blobDigest digest.Digest, isExec
 		factory: cff,
 		digest:  blobDigest,
 	}
+	var leaf NativeLeaf
 	if isExecutable {
-		return &executableBlobAccessCASFile{blobAccessCASFile: baseFile}
+		leaf = &executableBlobAccessCASFile{blobAccessCASFile: baseFile}
+	} else {
+		leaf = &regularBlobAccessCASFile{blobAccessCASFile: baseFile}
 	}
-	return &regularBlobAccessCASFile{blobAccessCASFile: baseFile}
+	if !lastDataModificationTime.IsZero() {
+		// Only wrap files that have an explicit modification
+		// time, so that the common case remains small.
+		leaf = &timestampedNativeLeaf{
+			NativeLeaf:               leaf,
+			lastDataModificationTime: lastDataModificationTime,
+		}
+	}
+	return leaf
 }
 
 // blobAccessCASFile is the base type for all BlobAccess backed CAS
@@ -102,6 +116,10 @@ func (f *blobAccessCASFile) VirtualAllocate(off, size uint64) Status {
 	return StatusErrWrongType
 }
 
+func (f *blobAccessCASFile) VirtualDeallocate(off, size uint64) Status {
+	return StatusErrWrongType
+}
+
 func (f *blobAccessCASFile) virtualGetAttributesCommon(attributes *Attributes) {
 	attributes.SetChangeID(0)
 	attributes.SetFileType(filesystem.FileTypeRegularFile)
@@ -130,7 +148,10 @@ func (f *blobAccessCASFile) VirtualRead(buf []byte, off uint64) (int, bool, Stat
 	size := uint64(f.digest.GetSizeBytes())
 	buf, eof := BoundReadToFileSize(buf, off, size)
 	if len(buf) > 0 {
-		if n, err := f.factory.contentAddressableStorage.Get(f.factory.context, f.digest).ReadAt(buf, int64(off)); n != len(buf) {
+		fetchMeasurement := re_clock.StartPhase(f.factory.context, re_clock.PhaseFileFetch)
+		n, err := f.factory.contentAddressableStorage.Get(f.factory.context, f.digest).ReadAt(buf, int64(off))
+		fetchMeasurement.Finish()
+		if n != len(buf) {
 			f.factory.errorLogger.Log(util.StatusWrapf(err, "Failed to read from %s at offset %d", f.digest, off))
 			return 0, false, StatusErrIO
 		}
@@ -144,6 +165,32 @@ func (f *blobAccessCASFile) VirtualReadlink(ctx context.Context) ([]byte, Status
 
 func (f *blobAccessCASFile) VirtualClose(shareAccess ShareMask) {}
 
+func (f *blobAccessCASFile) VirtualSync() Status {
+	return StatusOK
+}
+
+func (f *blobAccessCASFile) VirtualOpenPassthroughFile() *os.File {
+	return nil
+}
+
+func (f *blobAccessCASFile) VirtualGetXAttr(name string) ([]byte, Status) {
+	return nil, StatusErrNoXAttr
+}
+
+func (f *blobAccessCASFile) VirtualListXAttrs() []string {
+	return nil
+}
+
+func (f *blobAccessCASFile) VirtualSetXAttr(name string, value []byte, flags XAttrSetFlags) Status {
+	// Files backed by the Content Addressable Storage are
+	// immutable, and have no space to store extended attributes.
+	return StatusErrAccess
+}
+
+func (f *blobAccessCASFile) VirtualRemoveXAttr(name string) Status {
+	return StatusErrNoXAttr
+}
+
 func (f *blobAccessCASFile) virtualSetAttributesCommon(in *Attributes) Status {
 	// TODO: chmod() calls against CAS backed files should not be
 	// permitted. Unfortunately, we allowed it in the past. When
@@ -233,3 +280,32 @@ func (f *executableBlobAccessCASFile) VirtualSetAttributes(ctx context.Context,
 	f.VirtualGetAttributes(ctx, requested, out)
 	return StatusOK
 }
+
+// timestampedNativeLeaf is a decorator for NativeLeaf that reports a
+// fixed modification time. It is used to expose modification times
+// that are provided through NodeProperties of input files.
+type timestampedNativeLeaf struct {
+	NativeLeaf
+	lastDataModificationTime time.Time
+}
+
+func (l *timestampedNativeLeaf) VirtualGetAttributes(ctx context.Context, requested AttributesMask, attributes *Attributes) {
+	l.NativeLeaf.VirtualGetAttributes(ctx, requested, attributes)
+	attributes.SetLastDataModificationTime(l.lastDataModificationTime)
+}
+
+func (l *timestampedNativeLeaf) VirtualOpenSelf(ctx context.Context, shareAccess ShareMask, options *OpenExistingOptions, requested AttributesMask, attributes *Attributes) Status {
+	if s := l.NativeLeaf.VirtualOpenSelf(ctx, shareAccess, options, requested, attributes); s != StatusOK {
+		return s
+	}
+	attributes.SetLastDataModificationTime(l.lastDataModificationTime)
+	return StatusOK
+}
+
+func (l *timestampedNativeLeaf) VirtualSetAttributes(ctx context.Context, in *Attributes, requested AttributesMask, out *Attributes) Status {
+	if s := l.NativeLeaf.VirtualSetAttributes(ctx, in, requested, out); s != StatusOK {
+		return s
+	}
+	out.SetLastDataModificationTime(l.lastDataModificationTime)
+	return StatusOK
+}
@@ -3,6 +3,7 @@ package virtual_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-remote-execution/internal/mock"
@@ -33,7 +34,7 @@ func TestBlobAccessCASFileFactoryVirtualSeek(t *testing.T) {
 		errorLogger)
 
 	digest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 123)
-	f := casFileFactory.LookupFile(digest, false, nil)
+	f := casFileFactory.LookupFile(digest, false, time.Time{}, nil)
 	var out virtual.Attributes
 	f.VirtualGetAttributes(ctx, blobAccessCASFileFactoryAttributesMask, &out)
 	require.Equal(
@@ -83,7 +84,7 @@ func TestBlobAccessCASFileFactoryGetContainingDigests(t *testing.T) {
 		errorLogger)
 
 	digest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "d7ac2672607ba20a44d01d03a6685b24", 400)
-	f := casFileFactory.LookupFile(digest, true, nil)
+	f := casFileFactory.LookupFile(digest, true, time.Time{}, nil)
 	var out virtual.Attributes
 	f.VirtualGetAttributes(ctx, blobAccessCASFileFactoryAttributesMask, &out)
 	require.Equal(
@@ -98,6 +99,33 @@ func TestBlobAccessCASFileFactoryGetContainingDigests(t *testing.T) {
 	require.Equal(t, digest.ToSingletonSet(), f.GetContainingDigests())
 }
 
+func TestBlobAccessCASFileFactoryLastDataModificationTime(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	errorLogger := mock.NewMockErrorLogger(ctrl)
+	casFileFactory := virtual.NewBlobAccessCASFileFactory(
+		ctx,
+		contentAddressableStorage,
+		errorLogger)
+
+	// Files that have an explicit modification time should report
+	// it as part of their attributes.
+	digest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "d7ac2672607ba20a44d01d03a6685b24", 400)
+	f := casFileFactory.LookupFile(digest, false, time.Unix(1600000000, 0), nil)
+	var out virtual.Attributes
+	f.VirtualGetAttributes(ctx, blobAccessCASFileFactoryAttributesMask|virtual.AttributesMaskLastDataModificationTime, &out)
+	require.Equal(
+		t,
+		(&virtual.Attributes{}).
+			SetChangeID(0).
+			SetFileType(filesystem.FileTypeRegularFile).
+			SetLastDataModificationTime(time.Unix(1600000000, 0)).
+			SetPermissions(virtual.PermissionsRead).
+			SetSizeBytes(400),
+		&out)
+}
+
 func TestBlobAccessCASFileFactoryGetOutputServiceFileStatus(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
@@ -109,7 +137,7 @@ func TestBlobAccessCASFileFactoryGetOutputServiceFileStatus(t *testing.T) {
 		errorLogger)
 
 	digest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 123)
-	f := casFileFactory.LookupFile(digest, false, nil)
+	f := casFileFactory.LookupFile(digest, false, time.Time{}, nil)
 	var out virtual.Attributes
 	f.VirtualGetAttributes(ctx, blobAccessCASFileFactoryAttributesMask, &out)
 	require.Equal(
@@ -160,7 +188,7 @@ func TestBlobAccessCASFileFactoryAppendOutputPathPersistencyDirectoryNode(t *tes
 		errorLogger)
 
 	digest1 := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 123)
-	f1 := casFileFactory.LookupFile(digest1, false, nil)
+	f1 := casFileFactory.LookupFile(digest1, false, time.Time{}, nil)
 	var out1 virtual.Attributes
 	f1.VirtualGetAttributes(ctx, blobAccessCASFileFactoryAttributesMask, &out1)
 	require.Equal(
@@ -173,7 +201,7 @@ func TestBlobAccessCASFileFactoryAppendOutputPathPersistencyDirectoryNode(t *tes
 		&out1)
 
 	digest2 := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "0282d25bf4aefdb9cb50ccc78d974f0a", 456)
-	f2 := casFileFactory.LookupFile(digest2, true, nil)
+	f2 := casFileFactory.LookupFile(digest2, true, time.Time{}, nil)
 	var out2 virtual.Attributes
 	f2.VirtualGetAttributes(ctx, blobAccessCASFileFactoryAttributesMask, &out2)
 	require.Equal(
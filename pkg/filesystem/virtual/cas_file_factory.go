@@ -1,11 +1,17 @@
 package virtual
 
 import (
+	"time"
+
 	"github.com/buildbarn/bb-storage/pkg/digest"
 )
 
 // CASFileFactory is a factory type for files whose contents correspond
 // with an object stored in the Content Addressable Storage (CAS).
+//
+// If lastDataModificationTime is not the zero value, files report it
+// as their modification time. Otherwise, the file system reports a
+// deterministic timestamp.
 type CASFileFactory interface {
-	LookupFile(digest digest.Digest, isExecutable bool, readMonitor FileReadMonitor) NativeLeaf
+	LookupFile(digest digest.Digest, isExecutable bool, lastDataModificationTime time.Time, readMonitor FileReadMonitor) NativeLeaf
 }
@@ -2,6 +2,7 @@ package virtual
 
 import (
 	"context"
+	"time"
 
 	"github.com/buildbarn/bb-remote-execution/pkg/cas"
 	"github.com/buildbarn/bb-storage/pkg/digest"
@@ -95,7 +96,25 @@ func (icf *casInitialContentsFetcher) fetchContentsUnwrapped(fileReadMonitorFact
 		if err != nil {
 			return nil, util.StatusWrapf(err, "Failed to obtain digest for file %#v", entry.Name)
 		}
-		leaf := icf.options.casFileFactory.LookupFile(childDigest, entry.IsExecutable, fileReadMonitorFactory(component))
+
+		// Apply the modification time and permissions that are
+		// provided through node properties, so that build tools
+		// that compare timestamps behave as expected.
+		isExecutable := entry.IsExecutable
+		var lastDataModificationTime time.Time
+		if nodeProperties := entry.NodeProperties; nodeProperties != nil {
+			if mtime := nodeProperties.Mtime; mtime != nil {
+				if err := mtime.CheckValid(); err != nil {
+					return nil, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid modification time for file %#v", entry.Name)
+				}
+				lastDataModificationTime = mtime.AsTime()
+			}
+			if unixMode := nodeProperties.UnixMode; unixMode != nil {
+				isExecutable = unixMode.Value&0o111 != 0
+			}
+		}
+
+		leaf := icf.options.casFileFactory.LookupFile(childDigest, isExecutable, lastDataModificationTime, fileReadMonitorFactory(component))
 		children[component] = InitialNode{}.FromLeaf(leaf)
 		leavesToUnlink = append(leavesToUnlink, leaf)
 	}
@@ -3,6 +3,7 @@ package virtual_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-remote-execution/internal/mock"
@@ -15,6 +16,8 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestCASInitialContentsFetcherFetchContents(t *testing.T) {
@@ -110,6 +113,7 @@ func TestCASInitialContentsFetcherFetchContents(t *testing.T) {
 		casFileFactory.EXPECT().LookupFile(
 			digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "ded43ceff96666255cbb89a40cb9d1bd", 1200),
 			/* isExecutable = */ false,
+			/* lastDataModificationTime = */ time.Time{},
 			gomock.Any(),
 		).Return(file1)
 		file1.EXPECT().Unlink()
@@ -144,6 +148,7 @@ func TestCASInitialContentsFetcherFetchContents(t *testing.T) {
 		casFileFactory.EXPECT().LookupFile(
 			digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "0970ca3d192dde1268a19b44bbecadcf", 3000),
 			/* isExecutable = */ false,
+			/* lastDataModificationTime = */ time.Time{},
 			gomock.Any(),
 		).Return(file1)
 		file1.EXPECT().Unlink()
@@ -153,6 +158,44 @@ func TestCASInitialContentsFetcherFetchContents(t *testing.T) {
 		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Root directory: Directory contains multiple children named \"hello\""), err)
 	})
 
+	t.Run("NodeProperties", func(t *testing.T) {
+		// Modification times and UNIX modes provided through
+		// node properties should be applied to the files that
+		// are created.
+		fileReadMonitorFactory := mock.NewMockFileReadMonitorFactory(ctrl)
+		directoryWalker.EXPECT().GetDirectory(ctx).Return(&remoteexecution.Directory{
+			Files: []*remoteexecution.FileNode{
+				{
+					Name: "file",
+					Digest: &remoteexecution.Digest{
+						Hash:      "c0607941dd5b3ca8e175a1bfbfd1c0ea",
+						SizeBytes: 789,
+					},
+					IsExecutable: true,
+					NodeProperties: &remoteexecution.NodeProperties{
+						Mtime:    &timestamppb.Timestamp{Seconds: 1600000000, Nanos: 123},
+						UnixMode: &wrapperspb.UInt32Value{Value: 0o644},
+					},
+				},
+			},
+		}, nil)
+		fileLeaf := mock.NewMockNativeLeaf(ctrl)
+		fileReadMonitor := mock.NewMockFileReadMonitor(ctrl)
+		fileReadMonitorFactory.EXPECT().Call(path.MustNewComponent("file")).Return(fileReadMonitor.Call)
+		casFileFactory.EXPECT().LookupFile(
+			digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "c0607941dd5b3ca8e175a1bfbfd1c0ea", 789),
+			/* isExecutable = */ false,
+			/* lastDataModificationTime = */ time.Unix(1600000000, 123).UTC(),
+			gomock.Any(),
+		).Return(fileLeaf)
+
+		children, err := initialContentsFetcher.FetchContents(fileReadMonitorFactory.Call)
+		require.NoError(t, err)
+		require.Equal(t, map[path.Component]virtual.InitialNode{
+			path.MustNewComponent("file"): virtual.InitialNode{}.FromLeaf(fileLeaf),
+		}, children)
+	})
+
 	t.Run("Success", func(t *testing.T) {
 		// Let the InitialContentsFetcher successfully parse a
 		// Directory object.
@@ -200,6 +243,7 @@ func TestCASInitialContentsFetcherFetchContents(t *testing.T) {
 		casFileFactory.EXPECT().LookupFile(
 			digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "946fbe7108add776d3e3094f512c3483", 456),
 			/* isExecutable = */ true,
+			/* lastDataModificationTime = */ time.Time{},
 			gomock.Any(),
 		).Return(executableLeaf)
 		fileLeaf := mock.NewMockNativeLeaf(ctrl)
@@ -208,6 +252,7 @@ func TestCASInitialContentsFetcherFetchContents(t *testing.T) {
 		casFileFactory.EXPECT().LookupFile(
 			digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "c0607941dd5b3ca8e175a1bfbfd1c0ea", 789),
 			/* isExecutable = */ false,
+			/* lastDataModificationTime = */ time.Time{},
 			gomock.Any(),
 		).Return(fileLeaf)
 		symlinkLeaf := mock.NewMockNativeLeaf(ctrl)
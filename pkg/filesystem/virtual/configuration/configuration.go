@@ -3,14 +3,18 @@ package configuration
 import (
 	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
 	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual/nfsv4"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual/smb3"
 	pb "github.com/buildbarn/bb-remote-execution/pkg/proto/configuration/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/auth"
 	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/eviction"
 	"github.com/buildbarn/bb-storage/pkg/program"
 	"github.com/buildbarn/bb-storage/pkg/random"
 	"github.com/buildbarn/bb-storage/pkg/util"
 	nfsv4_xdr "github.com/buildbarn/go-xdr/pkg/protocols/nfsv4"
 	"github.com/buildbarn/go-xdr/pkg/rpcserver"
+	"github.com/jcmturner/gokrb5/v8/keytab"
 	"github.com/jmespath/go-jmespath"
 
 	"google.golang.org/grpc/codes"
@@ -72,12 +76,43 @@ func (m *nfsv4Mount) Expose(terminationGroup program.Group, rootDirectory virtua
 					stateIDOtherPrefix,
 					clock.SystemClock,
 					enforcedLeaseTime.AsDuration(),
-					announcedLeaseTime.AsDuration()))),
+					announcedLeaseTime.AsDuration(),
+					m.configuration.EnableReadDelegations))),
 	}, m.authenticator)
 
 	return m.mount(terminationGroup, rpcServer)
 }
 
+type smb3Mount struct {
+	mountPath     string
+	configuration *pb.SMB3MountConfiguration
+	fsName        string
+}
+
+func (m *smb3Mount) Expose(terminationGroup program.Group, rootDirectory virtual.Directory) error {
+	server := smb3.NewServer(
+		rootDirectory,
+		m.fsName,
+		m.configuration.ShareName,
+		m.configuration.Username,
+		m.configuration.Password,
+		random.CryptoThreadSafeGenerator,
+		clock.SystemClock)
+	return m.mount(terminationGroup, server)
+}
+
+type projfsMount struct {
+	mountPath       string
+	configuration   *pb.ProjFSMountConfiguration
+	handleAllocator *virtual.FUSEStatefulHandleAllocator
+}
+
+type virtiofsMount struct {
+	mountPath       string
+	configuration   *pb.VirtioFSMountConfiguration
+	handleAllocator *virtual.FUSEStatefulHandleAllocator
+}
+
 // NewMountFromConfiguration creates a new FUSE mount based on options
 // specified in a configuration message and starts processing of
 // incoming requests.
@@ -109,6 +144,44 @@ func NewMountFromConfiguration(configuration *pb.MountConfiguration, fsName stri
 				int(systemAuthentication.MaximumCacheSize),
 				eviction.NewMetricsSet(evictionSet, "SystemAuthenticator"))
 		}
+		authorizer := auth.NewStaticAuthorizer(func(digest.InstanceName) bool { return true })
+		if authorizerConfiguration := backend.Nfsv4.Authorizer; authorizerConfiguration != nil {
+			var err error
+			authorizer, err = auth.DefaultAuthorizerFactory.NewAuthorizerFromConfiguration(authorizerConfiguration)
+			if err != nil {
+				return nil, nil, util.StatusWrap(err, "Failed to create authorizer")
+			}
+			authenticator = nfsv4.NewAuthorizingAuthenticator(authenticator, authorizer)
+		}
+		if kerberosAuthentication := backend.Nfsv4.KerberosAuthentication; kerberosAuthentication != nil {
+			serviceKeytab, err := keytab.Load(kerberosAuthentication.KeytabPath)
+			if err != nil {
+				return nil, nil, util.StatusWrapf(err, "Failed to load keytab %#v", kerberosAuthentication.KeytabPath)
+			}
+			compiledExpression, err := jmespath.Compile(kerberosAuthentication.MetadataJmespathExpression)
+			if err != nil {
+				return nil, nil, util.StatusWrap(err, "Failed to compile Kerberos authentication metadata JMESPath expression")
+			}
+			evictionSet, err := eviction.NewSetFromConfiguration[nfsv4.RPCSECGSSContextHandle](kerberosAuthentication.ContextReplacementPolicy)
+			if err != nil {
+				return nil, nil, util.StatusWrap(err, "Failed to create Kerberos authentication eviction set")
+			}
+			// Only permit other credential flavors if system
+			// authentication is enabled explicitly.
+			var baseAuthenticator rpcserver.Authenticator
+			if backend.Nfsv4.SystemAuthentication != nil {
+				baseAuthenticator = authenticator
+			}
+			authenticator = nfsv4.NewRPCSECGSSAuthenticator(
+				baseAuthenticator,
+				nfsv4.NewKerberosGSSAcceptor(serviceKeytab, random.CryptoThreadSafeGenerator),
+				compiledExpression,
+				authorizer,
+				clock.SystemClock,
+				random.CryptoThreadSafeGenerator,
+				int(kerberosAuthentication.MaximumContexts),
+				eviction.NewMetricsSet(evictionSet, "RPCSECGSSAuthenticator"))
+		}
 
 		return &nfsv4Mount{
 			mountPath:                        configuration.MountPath,
@@ -120,6 +193,36 @@ func NewMountFromConfiguration(configuration *pb.MountConfiguration, fsName stri
 			childDirectoriesAttributeCaching: childDirectoriesAttributeCaching,
 			leavesAttributeCaching:           leavesAttributeCaching,
 		}, handleAllocator, nil
+	case *pb.MountConfiguration_Smb3:
+		// SMB2 clients refer to files by path. The handle
+		// allocator is only used to assign inode numbers, which
+		// the NFSv4 handle allocator provides without requiring
+		// any interaction with the kernel.
+		handleAllocator := virtual.NewNFSHandleAllocator(random.NewFastSingleThreadedGenerator())
+		return &smb3Mount{
+			mountPath:     configuration.MountPath,
+			configuration: backend.Smb3,
+			fsName:        fsName,
+		}, handleAllocator, nil
+	case *pb.MountConfiguration_Projfs:
+		// Removal notifications provided by the FUSE handle
+		// allocator are used to let ProjFS discard placeholders
+		// of files removed from the virtual file system.
+		handleAllocator := virtual.NewFUSEHandleAllocator(random.FastThreadSafeGenerator)
+		return &projfsMount{
+			mountPath:       configuration.MountPath,
+			configuration:   backend.Projfs,
+			handleAllocator: handleAllocator,
+		}, handleAllocator, nil
+	case *pb.MountConfiguration_Virtiofs:
+		// virtio-fs uses the FUSE protocol, meaning that handles
+		// are allocated in the same way.
+		handleAllocator := virtual.NewFUSEHandleAllocator(random.FastThreadSafeGenerator)
+		return &virtiofsMount{
+			mountPath:       configuration.MountPath,
+			configuration:   backend.Virtiofs,
+			handleAllocator: handleAllocator,
+		}, handleAllocator, nil
 	default:
 		return nil, nil, status.Error(codes.InvalidArgument, "No virtual file system backend configuration provided")
 	}
@@ -4,6 +4,7 @@
 package configuration
 
 import (
+	"math"
 	"time"
 
 	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
@@ -14,6 +15,9 @@ import (
 	"github.com/buildbarn/bb-storage/pkg/util"
 	go_fuse "github.com/hanwen/go-fuse/v2/fuse"
 	"github.com/jmespath/go-jmespath"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func (m *fuseMount) Expose(terminationGroup program.Group, rootDirectory virtual.Directory) error {
@@ -42,6 +46,18 @@ func (m *fuseMount) Expose(terminationGroup program.Group, rootDirectory virtual
 		authenticator = fuse.NewInHeaderAuthenticator(compiledExpression)
 	}
 
+	var ioUringRings, ioUringQueueDepth int
+	if ioUring := m.configuration.IoUring; ioUring != nil {
+		if ioUring.Rings == 0 {
+			return status.Error(codes.InvalidArgument, "The number of io_uring rings must be positive")
+		}
+		ioUringRings = int(ioUring.Rings)
+		ioUringQueueDepth = int(ioUring.QueueDepth)
+	}
+	if m.configuration.MaximumBackgroundRequests > math.MaxUint16 || m.configuration.CongestionThreshold > math.MaxUint16 {
+		return status.Errorf(codes.InvalidArgument, "The maximum number of background requests and the congestion threshold may not exceed %d", math.MaxUint16)
+	}
+
 	// Launch the FUSE server.
 	removeStaleMounts(m.mountPath)
 	deterministicTimestamp := uint64(filesystem.DeterministicFileModificationTimestamp.Unix())
@@ -51,7 +67,8 @@ func (m *fuseMount) Expose(terminationGroup program.Group, rootDirectory virtual
 				fuse.NewSimpleRawFileSystem(
 					rootDirectory,
 					m.handleAllocator.RegisterRemovalNotifier,
-					authenticator),
+					authenticator,
+					m.configuration.EnableExtendedAttributes),
 				directoryEntryValidity,
 				inodeAttributeValidity,
 				&go_fuse.Attr{
@@ -77,7 +94,21 @@ func (m *fuseMount) Expose(terminationGroup program.Group, rootDirectory virtual
 			// writes. This means data is only guaranteed to
 			// make it into the virtual file system after
 			// calling close()/fsync()/munmap()/msync().
-			EnableWritebackCache: true,
+			//
+			// The kernel does not permit combining the
+			// writeback cache with passthrough.
+			EnableWritebackCache: !m.configuration.EnablePassthrough && !m.configuration.DisableWritebackCache,
+			EnablePassthrough:    m.configuration.EnablePassthrough,
+			IoUringRings:         ioUringRings,
+			IoUringQueueDepth:    ioUringQueueDepth,
+			MaxBackground:        int(m.configuration.MaximumBackgroundRequests),
+			CongestionThreshold:  int(m.configuration.CongestionThreshold),
+			MaxWrite:             int(m.configuration.MaximumRequestSizeBytes),
+			MaxReadAhead:         int(m.configuration.MaximumReadAheadBytes),
+			// Requests for security labels are issued by the
+			// kernel whenever files are written. Answer these
+			// without calling into the virtual file system.
+			IgnoreSecurityLabels: m.configuration.EnableExtendedAttributes,
 		})
 	if err != nil {
 		return util.StatusWrap(err, "Failed to create FUSE server")
@@ -21,6 +21,7 @@ import (
 	"github.com/buildbarn/bb-storage/pkg/program"
 	"github.com/buildbarn/bb-storage/pkg/util"
 	nfs_sys_prot "github.com/buildbarn/go-xdr/pkg/protocols/darwin_nfs_sys_prot"
+	"github.com/buildbarn/go-xdr/pkg/protocols/rpcv2"
 	"github.com/buildbarn/go-xdr/pkg/rpcserver"
 
 	"golang.org/x/sys/unix"
@@ -28,6 +29,14 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// Pseudo-flavors for RPCSEC_GSS with Kerberos V5, as described in RFC
+// 2623, section 2.2.1.
+const (
+	rpcAuthKerberos5          = 390003
+	rpcAuthKerberos5Integrity = 390004
+	rpcAuthKerberos5Privacy   = 390005
+)
+
 var (
 	initializeNFSOnce sync.Once
 
@@ -184,6 +193,16 @@ func (m *nfsv4Mount) mount(terminationGroup program.Group, rpcServer *rpcserver.
 		writeAttributeCachingDuration(&directoriesAttributeCaching, &attrVals)
 	}
 
+	// Let the client use Kerberos if the server permits it.
+	if m.configuration.KerberosAuthentication != nil {
+		securityFlavors := []uint32{rpcAuthKerberos5Privacy, rpcAuthKerberos5Integrity, rpcAuthKerberos5}
+		if m.configuration.SystemAuthentication != nil {
+			securityFlavors = append(securityFlavors, uint32(rpcv2.AUTH_SYS))
+		}
+		attrMask[0] |= 1 << nfs_sys_prot.NFS_MATTR_SECURITY
+		nfs_sys_prot.WriteNfsMattrSecurity(&attrVals, securityFlavors)
+	}
+
 	// "ticotsord" is the X/Open Transport Interface (XTI)
 	// equivalent of AF_LOCAL with SOCK_STREAM.
 	attrMask[0] |= 1 << nfs_sys_prot.NFS_MATTR_SOCKET_TYPE
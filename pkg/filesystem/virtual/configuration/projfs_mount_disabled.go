@@ -0,0 +1,16 @@
+//go:build !windows
+// +build !windows
+
+package configuration
+
+import (
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/program"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (m *projfsMount) Expose(terminationGroup program.Group, rootDirectory virtual.Directory) error {
+	return status.Error(codes.Unimplemented, "ProjFS is not supported on this platform")
+}
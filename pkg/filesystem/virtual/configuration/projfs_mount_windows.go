@@ -0,0 +1,71 @@
+//go:build windows
+// +build windows
+
+package configuration
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual/projfs"
+	"github.com/buildbarn/bb-storage/pkg/program"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sys/windows"
+)
+
+func (m *projfsMount) Expose(terminationGroup program.Group, rootDirectory virtual.Directory) error {
+	if err := os.MkdirAll(m.mountPath, 0o777); err != nil {
+		return util.StatusWrapf(err, "Failed to create mount path %#v", m.mountPath)
+	}
+
+	// ProjFS requires that a virtualization root is always served
+	// using the same instance ID. Derive it from the mount path, so
+	// that it remains stable across restarts.
+	absoluteMountPath, err := filepath.Abs(m.mountPath)
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to obtain absolute path of %#v", m.mountPath)
+	}
+	instanceIDHash := sha256.Sum256([]byte(absoluteMountPath))
+	var instanceID windows.GUID
+	instanceID.Data1 = uint32(instanceIDHash[0])<<24 | uint32(instanceIDHash[1])<<16 | uint32(instanceIDHash[2])<<8 | uint32(instanceIDHash[3])
+	instanceID.Data2 = uint16(instanceIDHash[4])<<8 | uint16(instanceIDHash[5])
+	instanceID.Data3 = uint16(instanceIDHash[6])<<8 | uint16(instanceIDHash[7])
+	copy(instanceID.Data4[:], instanceIDHash[8:16])
+
+	virtualizationInstance, err := projfs.StartVirtualizing(
+		absoluteMountPath,
+		instanceID,
+		projfs.NewProvider(rootDirectory, projfs.FileNameMatch),
+		m.configuration.PoolThreadCount,
+		m.configuration.ConcurrentThreadCount)
+	if err != nil {
+		return err
+	}
+	m.handleAllocator.RegisterRemovalNotifier(virtualizationInstance.NotifyRemoval)
+
+	// Files that were placed in the virtualization root by a
+	// previous invocation are still stored on disk. Remove them, so
+	// that only the contents of the virtual file system are visible.
+	entries, err := os.ReadDir(absoluteMountPath)
+	if err != nil {
+		virtualizationInstance.StopVirtualizing()
+		return util.StatusWrapf(err, "Failed to read contents of %#v", absoluteMountPath)
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(absoluteMountPath, entry.Name())); err != nil {
+			virtualizationInstance.StopVirtualizing()
+			return util.StatusWrapf(err, "Failed to remove stale file %#v", entry.Name())
+		}
+	}
+
+	terminationGroup.Go(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+		<-ctx.Done()
+		virtualizationInstance.StopVirtualizing()
+		return nil
+	})
+	return nil
+}
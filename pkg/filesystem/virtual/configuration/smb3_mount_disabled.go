@@ -0,0 +1,16 @@
+//go:build !windows
+// +build !windows
+
+package configuration
+
+import (
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual/smb3"
+	"github.com/buildbarn/bb-storage/pkg/program"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (m *smb3Mount) mount(terminationGroup program.Group, server *smb3.Server) error {
+	return status.Error(codes.Unimplemented, "SMB3 is not supported on this platform")
+}
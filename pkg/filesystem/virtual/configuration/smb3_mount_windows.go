@@ -0,0 +1,124 @@
+//go:build windows
+// +build windows
+
+package configuration
+
+import (
+	"context"
+	"log"
+	"net"
+	"unsafe"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual/smb3"
+	"github.com/buildbarn/bb-storage/pkg/program"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	mpr                        = windows.NewLazySystemDLL("mpr.dll")
+	procWNetAddConnection2W    = mpr.NewProc("WNetAddConnection2W")
+	procWNetCancelConnection2W = mpr.NewProc("WNetCancelConnection2W")
+)
+
+// netResource corresponds to the NETRESOURCEW structure that is
+// accepted by WNetAddConnection2W().
+type netResource struct {
+	scope        uint32
+	resourceType uint32
+	displayType  uint32
+	usage        uint32
+	localName    *uint16
+	remoteName   *uint16
+	comment      *uint16
+	provider     *uint16
+}
+
+const (
+	resourceTypeDisk = 0x00000001
+	connectTemporary = 0x00000004
+)
+
+func cancelConnection(localName *uint16) error {
+	if r, _, _ := procWNetCancelConnection2W.Call(uintptr(unsafe.Pointer(localName)), 0, 1); r != 0 {
+		return windows.Errno(r)
+	}
+	return nil
+}
+
+func (m *smb3Mount) mount(terminationGroup program.Group, server *smb3.Server) error {
+	listenAddress := m.configuration.ListenAddress
+	host, _, err := net.SplitHostPort(listenAddress)
+	if err != nil {
+		return util.StatusWrapf(err, "Invalid listen address %#v", listenAddress)
+	}
+	remotePath := `\\` + host + `\` + m.configuration.ShareName
+	localName, err := windows.UTF16PtrFromString(m.mountPath)
+	if err != nil {
+		return util.StatusWrapf(err, "Invalid mount path %#v", m.mountPath)
+	}
+	remoteName, err := windows.UTF16PtrFromString(remotePath)
+	if err != nil {
+		return util.StatusWrapf(err, "Invalid remote path %#v", remotePath)
+	}
+	userName, err := windows.UTF16PtrFromString(m.configuration.Username)
+	if err != nil {
+		return util.StatusWrap(err, "Invalid username")
+	}
+	password, err := windows.UTF16PtrFromString(m.configuration.Password)
+	if err != nil {
+		return util.StatusWrap(err, "Invalid password")
+	}
+
+	// Expose the SMB3 server on a TCP socket.
+	sock, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return util.StatusWrap(err, "Failed to create listening socket for SMB3 server")
+	}
+	// TODO: Run this as part of the program.Group, so that it gets
+	// cleaned up upon shutdown.
+	go func() {
+		for {
+			c, err := sock.Accept()
+			if err != nil {
+				log.Print("Got accept error: ", err)
+				continue
+			}
+			go func() {
+				err := server.HandleConnection(c, c)
+				c.Close()
+				if err != nil {
+					log.Print("Failure handling SMB3 connection: ", err)
+				}
+			}()
+		}
+	}()
+
+	// Map the share to the mount path, removing any network drive
+	// that was left behind by a previous invocation.
+	cancelConnection(localName)
+	resource := netResource{
+		resourceType: resourceTypeDisk,
+		localName:    localName,
+		remoteName:   remoteName,
+	}
+	if r, _, _ := procWNetAddConnection2W.Call(
+		uintptr(unsafe.Pointer(&resource)),
+		uintptr(unsafe.Pointer(password)),
+		uintptr(unsafe.Pointer(userName)),
+		connectTemporary,
+	); r != 0 {
+		return util.StatusWrapf(windows.Errno(r), "Failed to map %#v to %#v", remotePath, m.mountPath)
+	}
+
+	// Automatically unmap the network drive upon termination.
+	terminationGroup.Go(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+		<-ctx.Done()
+		if err := cancelConnection(localName); err != nil {
+			return util.StatusWrapf(err, "Failed to unmap %#v", m.mountPath)
+		}
+		return nil
+	})
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+// +build !linux
+
+package configuration
+
+import (
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/program"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (m *virtiofsMount) Expose(terminationGroup program.Group, rootDirectory virtual.Directory) error {
+	return status.Error(codes.Unimplemented, "virtio-fs is not supported on this platform")
+}
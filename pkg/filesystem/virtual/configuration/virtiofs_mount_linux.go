@@ -0,0 +1,91 @@
+//go:build linux
+// +build linux
+
+package configuration
+
+import (
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual/fuse"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual/virtiofs"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/program"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	go_fuse "github.com/hanwen/go-fuse/v2/fuse"
+)
+
+func (m *virtiofsMount) Expose(terminationGroup program.Group, rootDirectory virtual.Directory) error {
+	// Parse configuration options.
+	var directoryEntryValidity time.Duration
+	if d := m.configuration.DirectoryEntryValidity; d != nil {
+		if err := d.CheckValid(); err != nil {
+			return util.StatusWrap(err, "Failed to parse directory entry validity")
+		}
+		directoryEntryValidity = d.AsDuration()
+	}
+	var inodeAttributeValidity time.Duration
+	if d := m.configuration.InodeAttributeValidity; d != nil {
+		if err := d.CheckValid(); err != nil {
+			return util.StatusWrap(err, "Failed to parse inode attribute validity")
+		}
+		inodeAttributeValidity = d.AsDuration()
+	}
+	maximumRequestQueues := m.configuration.MaximumRequestQueues
+	if maximumRequestQueues == 0 {
+		maximumRequestQueues = 1
+	}
+
+	deterministicTimestamp := uint64(filesystem.DeterministicFileModificationTimestamp.Unix())
+	server := virtiofs.NewServer(
+		fuse.NewMetricsRawFileSystem(
+			fuse.NewDefaultAttributesInjectingRawFileSystem(
+				fuse.NewSimpleRawFileSystem(
+					rootDirectory,
+					m.handleAllocator.RegisterRemovalNotifier,
+					fuse.AllowAuthenticator,
+					/* enableXAttrs = */ false),
+				directoryEntryValidity,
+				inodeAttributeValidity,
+				&go_fuse.Attr{
+					Atime: deterministicTimestamp,
+					Ctime: deterministicTimestamp,
+					Mtime: deterministicTimestamp,
+				}),
+			clock.SystemClock),
+		virtiofs.NewVersionTable(int(m.configuration.MaximumVersionTableSize)),
+		maximumRequestQueues)
+
+	// Expose the virtio-fs server on a UNIX socket, removing any
+	// socket left behind by a previous invocation.
+	if err := os.Remove(m.mountPath); err != nil && !os.IsNotExist(err) {
+		return util.StatusWrapf(err, "Failed to remove stale socket %#v", m.mountPath)
+	}
+	sock, err := net.ListenUnix("unix", &net.UnixAddr{Name: m.mountPath, Net: "unix"})
+	if err != nil {
+		return util.StatusWrap(err, "Failed to create listening socket for virtio-fs server")
+	}
+	// TODO: Run this as part of the program.Group, so that it gets
+	// cleaned up upon shutdown.
+	go func() {
+		for {
+			c, err := sock.AcceptUnix()
+			if err != nil {
+				log.Print("Got accept error: ", err)
+				continue
+			}
+			go func() {
+				err := server.HandleConnection(c)
+				c.Close()
+				if err != nil {
+					log.Print("Failure handling virtio-fs connection: ", err)
+				}
+			}()
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,41 @@
+package virtual
+
+import (
+	"context"
+
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// EagerFileUploader can be provided to NewPoolBackedFileAllocator() to
+// let files be uploaded into the Content Addressable Storage (CAS) as
+// soon as the last file descriptor through which they were written is
+// closed. This allows output files of build actions to be uploaded
+// while the build action is still running, reducing the amount of time
+// spent uploading outputs after the build action completes.
+//
+// Uploading eagerly is merely an optimization. If the file is modified
+// after it has been uploaded, or if uploading fails, UploadFile() will
+// upload the file's contents once more.
+type EagerFileUploader struct {
+	ctx                       context.Context
+	contentAddressableStorage blobstore.BlobAccess
+	digestFunction            digest.Function
+	semaphore                 *semaphore.Weighted
+}
+
+// NewEagerFileUploader creates an EagerFileUploader that writes files
+// into a given Content Addressable Storage, using a given digest
+// function. The semaphore can be used to limit the number of files
+// that are uploaded concurrently. Uploads are aborted when the provided
+// context is cancelled.
+func NewEagerFileUploader(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, digestFunction digest.Function, semaphore *semaphore.Weighted) *EagerFileUploader {
+	return &EagerFileUploader{
+		ctx:                       ctx,
+		contentAddressableStorage: contentAddressableStorage,
+		digestFunction:            digestFunction,
+		semaphore:                 semaphore,
+	}
+}
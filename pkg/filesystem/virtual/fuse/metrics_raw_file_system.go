@@ -147,6 +147,8 @@ var (
 	callbackCounterInodeNotify           = newCallbackCounterWithStatus("InodeNotify")
 	callbackCounterInodeRetrieveCache    = newCallbackCounterWithStatus("InodeRetrieveCache")
 	callbackCounterInodeNotifyStoreCache = newCallbackCounterWithStatus("InodeNotifyStoreCache")
+	callbackCounterRegisterBackingFd     = newCallbackCounterWithStatus("RegisterBackingFd")
+	callbackCounterUnregisterBackingFd   = newCallbackCounterWithStatus("UnregisterBackingFd")
 )
 
 type metricsRawFileSystem struct {
@@ -464,3 +466,15 @@ func (sc *metricsServerCallbacks) InodeNotifyStoreCache(node uint64, offset int6
 	callbackCounterInodeNotifyStoreCache.inc(s)
 	return s
 }
+
+func (sc *metricsServerCallbacks) RegisterBackingFd(backingMap *fuse.BackingMap) (int32, syscall.Errno) {
+	backingID, errno := sc.base.RegisterBackingFd(backingMap)
+	callbackCounterRegisterBackingFd.inc(fuse.Status(errno))
+	return backingID, errno
+}
+
+func (sc *metricsServerCallbacks) UnregisterBackingFd(backingID int32) syscall.Errno {
+	errno := sc.base.UnregisterBackingFd(backingID)
+	callbackCounterUnregisterBackingFd.inc(fuse.Status(errno))
+	return errno
+}
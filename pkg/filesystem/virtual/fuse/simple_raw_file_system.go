@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -53,6 +54,8 @@ func toFUSEStatus(s virtual.Status) fuse.Status {
 		return fuse.EISDIR
 	case virtual.StatusErrNoEnt:
 		return fuse.ENOENT
+	case virtual.StatusErrNoXAttr:
+		return fuse.ENOATTR
 	case virtual.StatusErrNotDir:
 		return fuse.ENOTDIR
 	case virtual.StatusErrNotEmpty:
@@ -89,6 +92,12 @@ type leafEntry struct {
 type simpleRawFileSystem struct {
 	removalNotifierRegistrar virtual.FUSERemovalNotifierRegistrar
 	authenticator            Authenticator
+	enableXAttrs             bool
+
+	// Used to register backing files for FUSE passthrough. Once
+	// registration fails, passthrough is no longer attempted.
+	server              fuse.ServerCallbacks
+	passthroughDisabled atomic.Bool
 
 	// Maps to resolve node IDs to directories and leaves.
 	nodeLock    sync.RWMutex
@@ -113,10 +122,16 @@ type simpleRawFileSystem struct {
 // Separation between these two interfaces was added to make it easier
 // to understand which operations actually get called against a given
 // object type.
-func NewSimpleRawFileSystem(rootDirectory virtual.Directory, removalNotifierRegistrar virtual.FUSERemovalNotifierRegistrar, authenticator Authenticator) fuse.RawFileSystem {
+//
+// Support for extended attributes is optional. When disabled, the
+// kernel is instructed to no longer forward any calls related to
+// extended attributes, which reduces the number of FUSE requests
+// processed while writing files.
+func NewSimpleRawFileSystem(rootDirectory virtual.Directory, removalNotifierRegistrar virtual.FUSERemovalNotifierRegistrar, authenticator Authenticator, enableXAttrs bool) fuse.RawFileSystem {
 	return &simpleRawFileSystem{
 		removalNotifierRegistrar: removalNotifierRegistrar,
 		authenticator:            authenticator,
+		enableXAttrs:             enableXAttrs,
 
 		directories: map[uint64]directoryEntry{
 			fuse.FUSE_ROOT_ID: {
@@ -541,28 +556,115 @@ func (rfs *simpleRawFileSystem) Access(cancel <-chan struct{}, input *fuse.Acces
 	return fuse.OK
 }
 
+// getXAttrLeaf returns the leaf against which operations on extended
+// attributes should be performed. Extended attributes are only
+// supported on leaves. For directories, nil is returned.
+func (rfs *simpleRawFileSystem) getXAttrLeaf(nodeID uint64) virtual.Leaf {
+	rfs.nodeLock.RLock()
+	defer rfs.nodeLock.RUnlock()
+
+	if entry, ok := rfs.leaves[nodeID]; ok {
+		return entry.leaf
+	}
+	rfs.getDirectoryLocked(nodeID)
+	return nil
+}
+
 func (rfs *simpleRawFileSystem) GetXAttr(cancel <-chan struct{}, header *fuse.InHeader, attr string, dest []byte) (uint32, fuse.Status) {
-	// By returning ENOSYS here, the Linux FUSE driver will set
-	// fuse_conn::no_getxattr. This will completely eliminate
-	// getxattr() calls going forward. More details:
-	//
-	// https://github.com/torvalds/linux/blob/371e8fd02969383204b1f6023451125dbc20dfbd/fs/fuse/xattr.c#L60-L61
-	// https://github.com/torvalds/linux/blob/371e8fd02969383204b1f6023451125dbc20dfbd/fs/fuse/xattr.c#L85-L88
-	//
-	// Similar logic is used for some of the other operations.
-	return 0, fuse.ENOSYS
+	if !rfs.enableXAttrs {
+		// By returning ENOSYS here, the Linux FUSE driver will
+		// set fuse_conn::no_getxattr. This will completely
+		// eliminate getxattr() calls going forward. More
+		// details:
+		//
+		// https://github.com/torvalds/linux/blob/371e8fd02969383204b1f6023451125dbc20dfbd/fs/fuse/xattr.c#L60-L61
+		// https://github.com/torvalds/linux/blob/371e8fd02969383204b1f6023451125dbc20dfbd/fs/fuse/xattr.c#L85-L88
+		//
+		// Similar logic is used for some of the other
+		// operations.
+		return 0, fuse.ENOSYS
+	}
+	if _, s := rfs.createContext(cancel, &header.Caller); s != fuse.OK {
+		return 0, s
+	}
+
+	i := rfs.getXAttrLeaf(header.NodeId)
+	if i == nil {
+		return 0, fuse.ENOATTR
+	}
+	value, vs := i.VirtualGetXAttr(attr)
+	if vs != virtual.StatusOK {
+		return 0, toFUSEStatus(vs)
+	}
+	if len(value) > len(dest) {
+		// go-fuse converts ERANGE to success if the caller
+		// only requested the size of the value.
+		return uint32(len(value)), fuse.ERANGE
+	}
+	return uint32(copy(dest, value)), fuse.OK
 }
 
 func (rfs *simpleRawFileSystem) ListXAttr(cancel <-chan struct{}, header *fuse.InHeader, dest []byte) (uint32, fuse.Status) {
-	return 0, fuse.ENOSYS
+	if !rfs.enableXAttrs {
+		return 0, fuse.ENOSYS
+	}
+	if _, s := rfs.createContext(cancel, &header.Caller); s != fuse.OK {
+		return 0, s
+	}
+
+	i := rfs.getXAttrLeaf(header.NodeId)
+	if i == nil {
+		return 0, fuse.OK
+	}
+
+	// Return the names of all extended attributes as a sequence
+	// of null terminated strings.
+	var list []byte
+	for _, name := range i.VirtualListXAttrs() {
+		list = append(list, name...)
+		list = append(list, 0)
+	}
+	if len(list) > len(dest) {
+		return uint32(len(list)), fuse.ERANGE
+	}
+	return uint32(copy(dest, list)), fuse.OK
 }
 
 func (rfs *simpleRawFileSystem) SetXAttr(cancel <-chan struct{}, input *fuse.SetXAttrIn, attr string, data []byte) fuse.Status {
-	return fuse.ENOSYS
+	if !rfs.enableXAttrs {
+		return fuse.ENOSYS
+	}
+	if _, s := rfs.createContext(cancel, &input.Caller); s != fuse.OK {
+		return s
+	}
+
+	i := rfs.getXAttrLeaf(input.NodeId)
+	if i == nil {
+		return fuse.ENOTSUP
+	}
+	var flags virtual.XAttrSetFlags
+	if input.Flags&unix.XATTR_CREATE != 0 {
+		flags |= virtual.XAttrSetFlagsCreate
+	}
+	if input.Flags&unix.XATTR_REPLACE != 0 {
+		flags |= virtual.XAttrSetFlagsReplace
+	}
+	return toFUSEStatus(i.VirtualSetXAttr(attr, data, flags))
 }
 
 func (rfs *simpleRawFileSystem) RemoveXAttr(cancel <-chan struct{}, header *fuse.InHeader, attr string) fuse.Status {
-	return fuse.ENOSYS
+	if !rfs.enableXAttrs {
+		return fuse.ENOSYS
+	}
+	if _, s := rfs.createContext(cancel, &header.Caller); s != fuse.OK {
+		return s
+	}
+
+	i := rfs.getXAttrLeaf(header.NodeId)
+	if i == nil {
+		return fuse.ENOATTR
+	}
+	return toFUSEStatus(i.VirtualRemoveXAttr(attr))
 }
 
 // oflagsToShareMask converts access modes stored in open() flags to a
@@ -644,7 +746,40 @@ func (rfs *simpleRawFileSystem) Open(cancel <-chan struct{}, input *fuse.OpenIn,
 	var options virtual.OpenExistingOptions
 	oflagsToOpenExistingOptions(input.Flags, &options)
 
-	return toFUSEStatus(i.VirtualOpenSelf(ctx, shareAccess, &options, 0, &virtual.Attributes{}))
+	if vs := i.VirtualOpenSelf(ctx, shareAccess, &options, 0, &virtual.Attributes{}); vs != virtual.StatusOK {
+		return toFUSEStatus(vs)
+	}
+	if shareAccess == virtual.ShareMaskRead && !rfs.passthroughDisabled.Load() {
+		rfs.openPassthrough(i, out)
+	}
+	return fuse.OK
+}
+
+// openPassthrough attempts to let the kernel process reads against an
+// opened file directly, using a backing file provided by the leaf.
+// Failures are not fatal, as reads can always be processed by the
+// virtual file system.
+func (rfs *simpleRawFileSystem) openPassthrough(i virtual.Leaf, out *fuse.OpenOut) {
+	f := i.VirtualOpenPassthroughFile()
+	if f == nil {
+		return
+	}
+	// The kernel holds its own reference to the backing file, so
+	// it can be closed once registered.
+	backingID, errno := rfs.server.RegisterBackingFd(&fuse.BackingMap{Fd: int32(f.Fd())})
+	f.Close()
+	if errno != 0 {
+		if !rfs.passthroughDisabled.Swap(true) {
+			log.Printf("Failed to register backing file, disabling FUSE passthrough: %s", errno)
+		}
+		return
+	}
+	// Backing IDs allocated by the kernel are always positive,
+	// meaning that a file handle of zero can be used to indicate
+	// that passthrough is not used.
+	out.OpenFlags |= fuse.FOPEN_PASSTHROUGH
+	out.BackingId = backingID
+	out.Fh = uint64(backingID)
 }
 
 func (rfs *simpleRawFileSystem) Read(cancel <-chan struct{}, input *fuse.ReadIn, buf []byte) (fuse.ReadResult, fuse.Status) {
@@ -707,6 +842,11 @@ func (rfs *simpleRawFileSystem) Release(cancel <-chan struct{}, input *fuse.Rele
 		panic("Input flags cannot be converted to share mask")
 	}
 
+	if input.Fh != 0 {
+		if errno := rfs.server.UnregisterBackingFd(int32(input.Fh)); errno != 0 {
+			log.Printf("Failed to unregister backing file %d: %s", input.Fh, errno)
+		}
+	}
 	i.VirtualClose(shareAccess)
 }
 
@@ -728,15 +868,39 @@ func (rfs *simpleRawFileSystem) Flush(cancel <-chan struct{}, input *fuse.FlushI
 }
 
 func (rfs *simpleRawFileSystem) Fsync(cancel <-chan struct{}, input *fuse.FsyncIn) fuse.Status {
-	return fuse.OK
+	rfs.nodeLock.RLock()
+	i := rfs.getLeafLocked(input.NodeId)
+	rfs.nodeLock.RUnlock()
+
+	return toFUSEStatus(i.VirtualSync())
 }
 
+// Flags that may be provided to fallocate(), which are forwarded to
+// the FUSE server as part of FallocateIn.Mode. These are declared
+// explicitly, as golang.org/x/sys/unix only provides them on Linux.
+const (
+	fallocateFlagKeepSize  = 0x1
+	fallocateFlagPunchHole = 0x2
+)
+
 func (rfs *simpleRawFileSystem) Fallocate(cancel <-chan struct{}, input *fuse.FallocateIn) fuse.Status {
 	rfs.nodeLock.RLock()
 	i := rfs.getLeafLocked(input.NodeId)
 	rfs.nodeLock.RUnlock()
 
-	return toFUSEStatus(i.VirtualAllocate(input.Offset, input.Length))
+	switch input.Mode {
+	case 0:
+		return toFUSEStatus(i.VirtualAllocate(input.Offset, input.Length))
+	case fallocateFlagKeepSize:
+		// Preallocating storage without growing the file. As
+		// file pools allocate storage on demand, this can be
+		// ignored.
+		return fuse.OK
+	case fallocateFlagKeepSize | fallocateFlagPunchHole:
+		return toFUSEStatus(i.VirtualDeallocate(input.Offset, input.Length))
+	default:
+		return fuse.ENOTSUP
+	}
 }
 
 func (rfs *simpleRawFileSystem) OpenDir(cancel <-chan struct{}, input *fuse.OpenIn, out *fuse.OpenOut) fuse.Status {
@@ -872,6 +1036,8 @@ func (rfs *simpleRawFileSystem) StatFs(cancel <-chan struct{}, input *fuse.InHea
 }
 
 func (rfs *simpleRawFileSystem) Init(server fuse.ServerCallbacks) {
+	rfs.server = server
+
 	// Obtain the inode number of the root directory.
 	rfs.nodeLock.RLock()
 	rootDirectory := rfs.directories[fuse.FUSE_ROOT_ID].directory
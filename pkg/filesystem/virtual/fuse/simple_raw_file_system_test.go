@@ -5,6 +5,7 @@ package fuse_test
 
 import (
 	"context"
+	"os"
 	"syscall"
 	"testing"
 	"time"
@@ -17,6 +18,8 @@ import (
 	"github.com/golang/mock/gomock"
 	go_fuse "github.com/hanwen/go-fuse/v2/fuse"
 	"github.com/stretchr/testify/require"
+
+	"golang.org/x/sys/unix"
 )
 
 func TestSimpleRawFileSystemAccess(t *testing.T) {
@@ -24,7 +27,7 @@ func TestSimpleRawFileSystemAccess(t *testing.T) {
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	t.Run("Failure", func(t *testing.T) {
 		rootDirectory.EXPECT().VirtualGetAttributes(gomock.Any(), virtual.AttributesMaskPermissions, gomock.Any()).DoAndReturn(
@@ -60,7 +63,7 @@ func TestSimpleRawFileSystemLookup(t *testing.T) {
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	t.Run("NotFound", func(t *testing.T) {
 		// Lookup failure errors should be propagated.
@@ -150,7 +153,7 @@ func TestSimpleRawFileSystemForget(t *testing.T) {
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	for i := 0; i < 10; i++ {
 		// Perform ten lookups of the same directory.
@@ -282,7 +285,7 @@ func TestSimpleRawFileSystemGetAttr(t *testing.T) {
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	t.Run("Success", func(t *testing.T) {
 		rootDirectory.EXPECT().VirtualGetAttributes(gomock.Any(), fuse.AttributesMaskForFUSEAttr, gomock.Any()).DoAndReturn(
@@ -316,7 +319,7 @@ func TestSimpleRawFileSystemSetAttr(t *testing.T) {
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	t.Run("Chown", func(t *testing.T) {
 		// chown() operations are not supported.
@@ -398,7 +401,7 @@ func TestSimpleRawFileSystemMknod(t *testing.T) {
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	t.Run("BlockDevice", func(t *testing.T) {
 		// An mknod() call for a block device should be
@@ -468,7 +471,7 @@ func TestSimpleRawFileSystemMkdir(t *testing.T) {
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	t.Run("Failure", func(t *testing.T) {
 		// An mkdir() call that fails due to an I/O error.
@@ -524,7 +527,7 @@ func TestSimpleRawFileSystemUnlink(t *testing.T) {
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	t.Run("Failure", func(t *testing.T) {
 		// An unlink() call that fails due to an I/O error.
@@ -555,7 +558,7 @@ func TestSimpleRawFileSystemRmdir(t *testing.T) {
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	t.Run("Failure", func(t *testing.T) {
 		// An rmdir() call that fails due to an I/O error.
@@ -586,7 +589,7 @@ func TestSimpleRawFileSystemSymlink(t *testing.T) {
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	t.Run("Failure", func(t *testing.T) {
 		rootDirectory.EXPECT().VirtualSymlink(
@@ -653,7 +656,7 @@ func TestSimpleRawFileSystemCreate(t *testing.T) {
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	t.Run("ReadWriteCreateExcl", func(t *testing.T) {
 		rootDirectory.EXPECT().VirtualOpenChild(
@@ -698,12 +701,155 @@ func TestSimpleRawFileSystemCreate(t *testing.T) {
 	})
 }
 
+func TestSimpleRawFileSystemOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
+	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
+
+	rootDirectory.EXPECT().VirtualGetAttributes(gomock.Any(), virtual.AttributesMaskInodeNumber, gomock.Any()).
+		Do(func(ctx context.Context, requested virtual.AttributesMask, attributes *virtual.Attributes) {
+			attributes.SetInodeNumber(123)
+		})
+	removalNotifierRegistrar.EXPECT().Call(gomock.Any())
+	mockServerCallbacks := mock.NewMockServerCallbacks(ctrl)
+	rfs.Init(mockServerCallbacks)
+
+	// Make a file known to the file system, so that it can be
+	// opened by node ID.
+	childFile := mock.NewMockVirtualLeaf(ctrl)
+	rootDirectory.EXPECT().VirtualLookup(gomock.Any(), path.MustNewComponent("file"), fuse.AttributesMaskForFUSEAttr, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, name path.Component, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.DirectoryChild, virtual.Status) {
+			out.SetFileType(filesystem.FileTypeRegularFile)
+			out.SetInodeNumber(456)
+			out.SetLinkCount(1)
+			out.SetPermissions(virtual.PermissionsRead)
+			out.SetSizeBytes(1300)
+			return virtual.DirectoryChild{}.FromLeaf(childFile), virtual.StatusOK
+		})
+	var entryOut go_fuse.EntryOut
+	require.Equal(t, go_fuse.OK, rfs.Lookup(nil, &go_fuse.InHeader{
+		NodeId: go_fuse.FUSE_ROOT_ID,
+	}, "file", &entryOut))
+
+	t.Run("Failure", func(t *testing.T) {
+		childFile.EXPECT().VirtualOpenSelf(gomock.Any(), virtual.ShareMaskWrite, &virtual.OpenExistingOptions{}, virtual.AttributesMask(0), gomock.Any()).
+			Return(virtual.StatusErrAccess)
+
+		var openOut go_fuse.OpenOut
+		require.Equal(t, go_fuse.EACCES, rfs.Open(nil, &go_fuse.OpenIn{
+			InHeader: go_fuse.InHeader{
+				NodeId: 456,
+			},
+			Flags: syscall.O_WRONLY,
+		}, &openOut))
+	})
+
+	t.Run("ReadWrite", func(t *testing.T) {
+		// Passthrough should only be attempted for files that
+		// are opened for reading.
+		childFile.EXPECT().VirtualOpenSelf(gomock.Any(), virtual.ShareMaskRead|virtual.ShareMaskWrite, &virtual.OpenExistingOptions{}, virtual.AttributesMask(0), gomock.Any())
+
+		var openOut go_fuse.OpenOut
+		require.Equal(t, go_fuse.OK, rfs.Open(nil, &go_fuse.OpenIn{
+			InHeader: go_fuse.InHeader{
+				NodeId: 456,
+			},
+			Flags: syscall.O_RDWR,
+		}, &openOut))
+		require.Equal(t, go_fuse.OpenOut{}, openOut)
+	})
+
+	t.Run("NoPassthroughFile", func(t *testing.T) {
+		childFile.EXPECT().VirtualOpenSelf(gomock.Any(), virtual.ShareMaskRead, &virtual.OpenExistingOptions{}, virtual.AttributesMask(0), gomock.Any())
+		childFile.EXPECT().VirtualOpenPassthroughFile()
+
+		var openOut go_fuse.OpenOut
+		require.Equal(t, go_fuse.OK, rfs.Open(nil, &go_fuse.OpenIn{
+			InHeader: go_fuse.InHeader{
+				NodeId: 456,
+			},
+			Flags: syscall.O_RDONLY,
+		}, &openOut))
+		require.Equal(t, go_fuse.OpenOut{}, openOut)
+	})
+
+	t.Run("Passthrough", func(t *testing.T) {
+		// If the file provides a backing file, it should be
+		// registered with the kernel. The backing ID should be
+		// stored in the file handle, so that it can be
+		// unregistered when the file is released.
+		backingFile, err := os.CreateTemp(t.TempDir(), "")
+		require.NoError(t, err)
+		childFile.EXPECT().VirtualOpenSelf(gomock.Any(), virtual.ShareMaskRead, &virtual.OpenExistingOptions{}, virtual.AttributesMask(0), gomock.Any())
+		childFile.EXPECT().VirtualOpenPassthroughFile().Return(backingFile)
+		mockServerCallbacks.EXPECT().RegisterBackingFd(&go_fuse.BackingMap{Fd: int32(backingFile.Fd())}).Return(int32(7), syscall.Errno(0))
+
+		var openOut go_fuse.OpenOut
+		require.Equal(t, go_fuse.OK, rfs.Open(nil, &go_fuse.OpenIn{
+			InHeader: go_fuse.InHeader{
+				NodeId: 456,
+			},
+			Flags: syscall.O_RDONLY,
+		}, &openOut))
+		require.Equal(t, go_fuse.OpenOut{
+			Fh:        7,
+			OpenFlags: go_fuse.FOPEN_PASSTHROUGH,
+			BackingId: 7,
+		}, openOut)
+
+		mockServerCallbacks.EXPECT().UnregisterBackingFd(int32(7))
+		childFile.EXPECT().VirtualClose(virtual.ShareMaskRead)
+
+		rfs.Release(nil, &go_fuse.ReleaseIn{
+			InHeader: go_fuse.InHeader{
+				NodeId: 456,
+			},
+			Fh:    7,
+			Flags: syscall.O_RDONLY,
+		})
+	})
+
+	t.Run("RegistrationFailure", func(t *testing.T) {
+		// If registration of the backing file fails (e.g.,
+		// because the kernel does not support passthrough), the
+		// file should be opened without passthrough.
+		backingFile, err := os.CreateTemp(t.TempDir(), "")
+		require.NoError(t, err)
+		childFile.EXPECT().VirtualOpenSelf(gomock.Any(), virtual.ShareMaskRead, &virtual.OpenExistingOptions{}, virtual.AttributesMask(0), gomock.Any())
+		childFile.EXPECT().VirtualOpenPassthroughFile().Return(backingFile)
+		mockServerCallbacks.EXPECT().RegisterBackingFd(gomock.Any()).Return(int32(0), syscall.EPERM)
+
+		var openOut go_fuse.OpenOut
+		require.Equal(t, go_fuse.OK, rfs.Open(nil, &go_fuse.OpenIn{
+			InHeader: go_fuse.InHeader{
+				NodeId: 456,
+			},
+			Flags: syscall.O_RDONLY,
+		}, &openOut))
+		require.Equal(t, go_fuse.OpenOut{}, openOut)
+
+		// Future attempts to open files should no longer
+		// attempt to use passthrough.
+		childFile.EXPECT().VirtualOpenSelf(gomock.Any(), virtual.ShareMaskRead, &virtual.OpenExistingOptions{}, virtual.AttributesMask(0), gomock.Any())
+
+		require.Equal(t, go_fuse.OK, rfs.Open(nil, &go_fuse.OpenIn{
+			InHeader: go_fuse.InHeader{
+				NodeId: 456,
+			},
+			Flags: syscall.O_RDONLY,
+		}, &openOut))
+		require.Equal(t, go_fuse.OpenOut{}, openOut)
+	})
+}
+
 func TestSimpleRawFileSystemOpenDir(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	t.Run("PermissionDenied", func(t *testing.T) {
 		// FUSE on Linux doesn't check permissions on the
@@ -730,7 +876,7 @@ func TestSimpleRawFileSystemReadDir(t *testing.T) {
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	// Open the root directory.
 	rootDirectory.EXPECT().VirtualGetAttributes(gomock.Any(), virtual.AttributesMaskPermissions, gomock.Any()).DoAndReturn(
@@ -961,7 +1107,7 @@ func TestSimpleRawFileSystemReadDirPlus(t *testing.T) {
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	// Open the root directory.
 	rootDirectory.EXPECT().VirtualGetAttributes(gomock.Any(), virtual.AttributesMaskPermissions, gomock.Any()).DoAndReturn(
@@ -1162,7 +1308,7 @@ func TestSimpleRawFileSystemReadlink(t *testing.T) {
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	symlink := mock.NewMockVirtualLeaf(ctrl)
 	rootDirectory.EXPECT().VirtualLookup(gomock.Any(), path.MustNewComponent("symlink"), fuse.AttributesMaskForFUSEAttr, gomock.Any()).DoAndReturn(
@@ -1217,7 +1363,7 @@ func TestSimpleRawFileSystemStatFs(t *testing.T) {
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	t.Run("Success", func(t *testing.T) {
 		// OSXFUSE lets the statvfs() system call succeed, even
@@ -1242,7 +1388,7 @@ func TestSimpleRawFileSystemInit(t *testing.T) {
 
 	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
 	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
-	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
 
 	// An Init() operation should cause SimpleRawFileSystem to
 	// register a removal notifier that forwards calls to
@@ -1315,3 +1461,191 @@ func TestSimpleRawFileSystemInit(t *testing.T) {
 }
 
 // TODO: Add testing coverage for other calls as well.
+
+func TestSimpleRawFileSystemXAttrs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("Disabled", func(t *testing.T) {
+		// When support for extended attributes is disabled, all
+		// operations should fail with ENOSYS. This causes the
+		// kernel to stop issuing these requests.
+		rootDirectory := mock.NewMockVirtualDirectory(ctrl)
+		removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
+		rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
+
+		header := go_fuse.InHeader{NodeId: go_fuse.FUSE_ROOT_ID}
+		_, s := rfs.GetXAttr(nil, &header, "user.foo", nil)
+		require.Equal(t, go_fuse.ENOSYS, s)
+		_, s = rfs.ListXAttr(nil, &header, nil)
+		require.Equal(t, go_fuse.ENOSYS, s)
+		require.Equal(t, go_fuse.ENOSYS, rfs.SetXAttr(nil, &go_fuse.SetXAttrIn{InHeader: header}, "user.foo", []byte("Hello")))
+		require.Equal(t, go_fuse.ENOSYS, rfs.RemoveXAttr(nil, &header, "user.foo"))
+	})
+
+	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
+	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, true)
+
+	rootDirectory.EXPECT().VirtualGetAttributes(gomock.Any(), virtual.AttributesMaskInodeNumber, gomock.Any()).
+		Do(func(ctx context.Context, requested virtual.AttributesMask, attributes *virtual.Attributes) {
+			attributes.SetInodeNumber(123)
+		})
+	removalNotifierRegistrar.EXPECT().Call(gomock.Any())
+	mockServerCallbacks := mock.NewMockServerCallbacks(ctrl)
+	rfs.Init(mockServerCallbacks)
+
+	// Make a file known to the file system, so that its extended
+	// attributes can be accessed by node ID.
+	childFile := mock.NewMockVirtualLeaf(ctrl)
+	rootDirectory.EXPECT().VirtualLookup(gomock.Any(), path.MustNewComponent("file"), fuse.AttributesMaskForFUSEAttr, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, name path.Component, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.DirectoryChild, virtual.Status) {
+			out.SetFileType(filesystem.FileTypeRegularFile)
+			out.SetInodeNumber(456)
+			out.SetLinkCount(1)
+			out.SetPermissions(virtual.PermissionsRead)
+			out.SetSizeBytes(1300)
+			return virtual.DirectoryChild{}.FromLeaf(childFile), virtual.StatusOK
+		})
+	var entryOut go_fuse.EntryOut
+	require.Equal(t, go_fuse.OK, rfs.Lookup(nil, &go_fuse.InHeader{
+		NodeId: go_fuse.FUSE_ROOT_ID,
+	}, "file", &entryOut))
+	fileHeader := go_fuse.InHeader{NodeId: 456}
+
+	t.Run("Directory", func(t *testing.T) {
+		// Directories have no extended attributes, and don't
+		// permit setting them.
+		header := go_fuse.InHeader{NodeId: go_fuse.FUSE_ROOT_ID}
+		_, s := rfs.GetXAttr(nil, &header, "user.foo", nil)
+		require.Equal(t, go_fuse.ENOATTR, s)
+		n, s := rfs.ListXAttr(nil, &header, nil)
+		require.Equal(t, go_fuse.OK, s)
+		require.Equal(t, uint32(0), n)
+		require.Equal(t, go_fuse.ENOTSUP, rfs.SetXAttr(nil, &go_fuse.SetXAttrIn{InHeader: header}, "user.foo", []byte("Hello")))
+	})
+
+	t.Run("GetXAttrNotFound", func(t *testing.T) {
+		childFile.EXPECT().VirtualGetXAttr("user.foo").Return(nil, virtual.StatusErrNoXAttr)
+
+		_, s := rfs.GetXAttr(nil, &fileHeader, "user.foo", make([]byte, 10))
+		require.Equal(t, go_fuse.ENOATTR, s)
+	})
+
+	t.Run("GetXAttrTooSmall", func(t *testing.T) {
+		// If the buffer is too small, the size of the value
+		// should be returned, together with ERANGE.
+		childFile.EXPECT().VirtualGetXAttr("user.foo").Return([]byte("Hello"), virtual.StatusOK)
+
+		n, s := rfs.GetXAttr(nil, &fileHeader, "user.foo", make([]byte, 3))
+		require.Equal(t, go_fuse.ERANGE, s)
+		require.Equal(t, uint32(5), n)
+	})
+
+	t.Run("GetXAttrSuccess", func(t *testing.T) {
+		childFile.EXPECT().VirtualGetXAttr("user.foo").Return([]byte("Hello"), virtual.StatusOK)
+
+		dest := make([]byte, 10)
+		n, s := rfs.GetXAttr(nil, &fileHeader, "user.foo", dest)
+		require.Equal(t, go_fuse.OK, s)
+		require.Equal(t, []byte("Hello"), dest[:n])
+	})
+
+	t.Run("ListXAttr", func(t *testing.T) {
+		// Names should be returned as null terminated strings.
+		childFile.EXPECT().VirtualListXAttrs().Return([]string{"user.bar", "user.foo"}).Times(2)
+
+		n, s := rfs.ListXAttr(nil, &fileHeader, nil)
+		require.Equal(t, go_fuse.ERANGE, s)
+		require.Equal(t, uint32(18), n)
+
+		dest := make([]byte, 100)
+		n, s = rfs.ListXAttr(nil, &fileHeader, dest)
+		require.Equal(t, go_fuse.OK, s)
+		require.Equal(t, []byte("user.bar\x00user.foo\x00"), dest[:n])
+	})
+
+	t.Run("SetXAttr", func(t *testing.T) {
+		// Flags should be translated to their virtual file
+		// system equivalents.
+		childFile.EXPECT().VirtualSetXAttr("user.foo", []byte("Hello"), virtual.XAttrSetFlagsCreate).
+			Return(virtual.StatusErrExist)
+
+		require.Equal(t, go_fuse.Status(syscall.EEXIST), rfs.SetXAttr(nil, &go_fuse.SetXAttrIn{
+			InHeader: fileHeader,
+			Flags:    unix.XATTR_CREATE,
+		}, "user.foo", []byte("Hello")))
+	})
+
+	t.Run("RemoveXAttr", func(t *testing.T) {
+		childFile.EXPECT().VirtualRemoveXAttr("user.foo").Return(virtual.StatusOK)
+
+		require.Equal(t, go_fuse.OK, rfs.RemoveXAttr(nil, &fileHeader, "user.foo"))
+	})
+}
+
+func TestSimpleRawFileSystemFallocate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
+	removalNotifierRegistrar := mock.NewMockFUSERemovalNotifierRegistrar(ctrl)
+	rfs := fuse.NewSimpleRawFileSystem(rootDirectory, removalNotifierRegistrar.Call, fuse.AllowAuthenticator, false)
+
+	// Look up a file, so that fallocate() can be called against it.
+	childFile := mock.NewMockVirtualLeaf(ctrl)
+	rootDirectory.EXPECT().VirtualLookup(gomock.Any(), path.MustNewComponent("file"), fuse.AttributesMaskForFUSEAttr, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, name path.Component, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.DirectoryChild, virtual.Status) {
+			out.SetFileType(filesystem.FileTypeRegularFile)
+			out.SetInodeNumber(2)
+			out.SetLinkCount(1)
+			out.SetPermissions(virtual.PermissionsRead | virtual.PermissionsWrite)
+			out.SetSizeBytes(1000)
+			return virtual.DirectoryChild{}.FromLeaf(childFile), virtual.StatusOK
+		})
+	var entryOut go_fuse.EntryOut
+	require.Equal(t, go_fuse.OK, rfs.Lookup(nil, &go_fuse.InHeader{
+		NodeId: go_fuse.FUSE_ROOT_ID,
+	}, "file", &entryOut))
+
+	t.Run("Allocate", func(t *testing.T) {
+		childFile.EXPECT().VirtualAllocate(uint64(100), uint64(200)).Return(virtual.StatusOK)
+
+		require.Equal(t, go_fuse.OK, rfs.Fallocate(nil, &go_fuse.FallocateIn{
+			InHeader: go_fuse.InHeader{NodeId: 2},
+			Offset:   100,
+			Length:   200,
+		}))
+	})
+
+	t.Run("KeepSize", func(t *testing.T) {
+		// Preallocating storage without changing the size of
+		// the file is a no-op.
+		require.Equal(t, go_fuse.OK, rfs.Fallocate(nil, &go_fuse.FallocateIn{
+			InHeader: go_fuse.InHeader{NodeId: 2},
+			Offset:   100,
+			Length:   200,
+			Mode:     0x1, // FALLOC_FL_KEEP_SIZE.
+		}))
+	})
+
+	t.Run("PunchHole", func(t *testing.T) {
+		childFile.EXPECT().VirtualDeallocate(uint64(100), uint64(200)).Return(virtual.StatusErrIO)
+
+		require.Equal(t, go_fuse.EIO, rfs.Fallocate(nil, &go_fuse.FallocateIn{
+			InHeader: go_fuse.InHeader{NodeId: 2},
+			Offset:   100,
+			Length:   200,
+			Mode:     0x1 | 0x2, // FALLOC_FL_KEEP_SIZE | FALLOC_FL_PUNCH_HOLE.
+		}))
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		// Other modes, such as FALLOC_FL_COLLAPSE_RANGE, are
+		// not supported.
+		require.Equal(t, go_fuse.ENOTSUP, rfs.Fallocate(nil, &go_fuse.FallocateIn{
+			InHeader: go_fuse.InHeader{NodeId: 2},
+			Offset:   100,
+			Length:   200,
+			Mode:     0x8, // FALLOC_FL_COLLAPSE_RANGE.
+		}))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -13,6 +14,9 @@ import (
 	"github.com/buildbarn/bb-storage/pkg/filesystem"
 	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
 	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // StringMatcher is a function type that has the same signature as
@@ -29,6 +33,7 @@ type inMemoryFilesystem struct {
 	initialContentsSorter   Sorter
 	hiddenFilesMatcher      StringMatcher
 	clock                   clock.Clock
+	caseInsensitive         bool
 }
 
 // inMemorySubtree contains state that is shared across all
@@ -38,11 +43,13 @@ type inMemoryFilesystem struct {
 // Every subtree in the filesystem may have its own file allocator. This
 // permits us to apply per-action disk quotas. It may also have its own
 // error logger, which allows us to notify LocalBuildExecutor of disk
-// I/O errors.
+// I/O errors, and its own operation statistics, which allows us to
+// report file system chatter on a per-action basis.
 type inMemorySubtree struct {
-	filesystem    *inMemoryFilesystem
-	fileAllocator FileAllocator
-	errorLogger   util.ErrorLogger
+	filesystem          *inMemoryFilesystem
+	fileAllocator       FileAllocator
+	errorLogger         util.ErrorLogger
+	operationStatistics *OperationStatistics
 }
 
 func (s *inMemorySubtree) createNewDirectory(initialContentsFetcher InitialContentsFetcher) *inMemoryPrepopulatedDirectory {
@@ -50,6 +57,7 @@ func (s *inMemorySubtree) createNewDirectory(initialContentsFetcher InitialConte
 		subtree:                s,
 		initialContentsFetcher: initialContentsFetcher,
 		contents: inMemoryDirectoryContents{
+			caseInsensitive:          s.filesystem.caseInsensitive,
 			lastDataModificationTime: s.filesystem.clock.Now(),
 		},
 	}
@@ -66,6 +74,11 @@ type inMemoryDirectoryChild = Child[*inMemoryPrepopulatedDirectory, NativeLeaf,
 type inMemoryDirectoryEntry struct {
 	child inMemoryDirectoryChild
 
+	// Whether the entry was created through the virtual file
+	// system, as opposed to being part of the directory's initial
+	// contents. Used by LookupModifiedChildren().
+	written bool
+
 	// For VirtualReadDir().
 	cookie   uint64
 	name     path.Component
@@ -78,14 +91,45 @@ type inMemoryDirectoryEntry struct {
 // and a list. The latter is needed for readdir() to behave
 // deterministically. The isDeleted flag may be set when empty and no
 // new children may be added.
+//
+// If the directory is case insensitive, the keys of the map are
+// lowercased. The original names of children are retained in the
+// entries.
 type inMemoryDirectoryContents struct {
 	entriesMap               map[path.Component]*inMemoryDirectoryEntry
 	entriesList              inMemoryDirectoryEntry
+	caseInsensitive          bool
 	isDeleted                bool
 	changeID                 uint64
 	lastDataModificationTime time.Time
 }
 
+// getKey returns the key under which a child with a given name is
+// stored in the map of entries.
+func (c *inMemoryDirectoryContents) getKey(name path.Component) path.Component {
+	if c.caseInsensitive {
+		return path.MustNewComponent(strings.ToLower(name.String()))
+	}
+	return name
+}
+
+// checkChildrenNames checks whether the names of children that are
+// about to be created don't collide with each other. This can only
+// happen if the directory is case insensitive.
+func (c *inMemoryDirectoryContents) checkChildrenNames(children map[path.Component]InitialNode) error {
+	if c.caseInsensitive {
+		names := make(map[path.Component]path.Component, len(children))
+		for name := range children {
+			key := c.getKey(name)
+			if otherName, ok := names[key]; ok {
+				return status.Errorf(codes.InvalidArgument, "Children %#v and %#v only differ in case", otherName.String(), name.String())
+			}
+			names[key] = name
+		}
+	}
+	return nil
+}
+
 // initialize a directory by making it empty.
 func (c *inMemoryDirectoryContents) initialize() {
 	c.entriesMap = map[path.Component]*inMemoryDirectoryEntry{}
@@ -94,19 +138,20 @@ func (c *inMemoryDirectoryContents) initialize() {
 }
 
 // attach an existing directory or leaf to the directory contents.
-func (c *inMemoryDirectoryContents) attach(subtree *inMemorySubtree, name path.Component, child inMemoryDirectoryChild) {
+func (c *inMemoryDirectoryContents) attach(subtree *inMemorySubtree, name path.Component, child inMemoryDirectoryChild, written bool) {
 	if err := c.mayAttach(name); err != 0 {
 		panic(fmt.Sprintf("Directory %#v may not be attached: %s", name, err))
 	}
 	entry := &inMemoryDirectoryEntry{
-		child: child,
+		child:   child,
+		written: written,
 
 		name:     name,
 		cookie:   c.changeID,
 		previous: c.entriesList.previous,
 		next:     &c.entriesList,
 	}
-	c.entriesMap[name] = entry
+	c.entriesMap[c.getKey(name)] = entry
 	entry.previous.next = entry
 	entry.next.previous = entry
 	c.touch(subtree)
@@ -115,9 +160,9 @@ func (c *inMemoryDirectoryContents) attach(subtree *inMemorySubtree, name path.C
 // attachDirectory adds a new directory to the directory contents. The
 // initial contents of this new directory may be specified in the form
 // of an InitialContentsFetcher, which gets evaluated lazily.
-func (c *inMemoryDirectoryContents) attachNewDirectory(subtree *inMemorySubtree, name path.Component, initialContentsFetcher InitialContentsFetcher) *inMemoryPrepopulatedDirectory {
+func (c *inMemoryDirectoryContents) attachNewDirectory(subtree *inMemorySubtree, name path.Component, initialContentsFetcher InitialContentsFetcher, written bool) *inMemoryPrepopulatedDirectory {
 	newDirectory := subtree.createNewDirectory(initialContentsFetcher)
-	c.attach(subtree, name, inMemoryDirectoryChild{}.FromDirectory(newDirectory))
+	c.attach(subtree, name, inMemoryDirectoryChild{}.FromDirectory(newDirectory), written)
 	return newDirectory
 }
 
@@ -125,7 +170,7 @@ func (c *inMemoryDirectoryContents) attachNewDirectory(subtree *inMemorySubtree,
 // foot-shooting. This allows VirtualReadDir() to detect that iteration
 // was interrupted.
 func (c *inMemoryDirectoryContents) detach(subtree *inMemorySubtree, entry *inMemoryDirectoryEntry) {
-	delete(c.entriesMap, entry.name)
+	delete(c.entriesMap, c.getKey(entry.name))
 	entry.previous.next = entry.next
 	entry.next.previous = entry.previous
 	entry.previous = nil
@@ -137,7 +182,7 @@ func (c *inMemoryDirectoryContents) mayAttach(name path.Component) syscall.Errno
 	if c.isDeleted {
 		return syscall.ENOENT
 	}
-	if _, ok := c.entriesMap[name]; ok {
+	if _, ok := c.entriesMap[c.getKey(name)]; ok {
 		return syscall.EEXIST
 	}
 	return 0
@@ -147,7 +192,7 @@ func (c *inMemoryDirectoryContents) virtualMayAttach(name path.Component) Status
 	if c.isDeleted {
 		return StatusErrNoEnt
 	}
-	if _, ok := c.entriesMap[name]; ok {
+	if _, ok := c.entriesMap[c.getKey(name)]; ok {
 		return StatusErrExist
 	}
 	return StatusOK
@@ -167,7 +212,7 @@ func (c *inMemoryDirectoryContents) isDeletable(hiddenFilesMatcher StringMatcher
 	return true
 }
 
-func (c *inMemoryDirectoryContents) createChildren(subtree *inMemorySubtree, children map[path.Component]InitialNode) {
+func (c *inMemoryDirectoryContents) createChildren(subtree *inMemorySubtree, children map[path.Component]InitialNode, written bool) {
 	// Either sort or shuffle the children before inserting them
 	// into the directory. This either makes VirtualReadDir() behave
 	// deterministically, or not, based on preference.
@@ -179,9 +224,9 @@ func (c *inMemoryDirectoryContents) createChildren(subtree *inMemorySubtree, chi
 
 	for _, name := range namesList {
 		if directory, leaf := children[name].GetPair(); directory != nil {
-			c.attachNewDirectory(subtree, name, directory)
+			c.attachNewDirectory(subtree, name, directory, written)
 		} else {
-			c.attach(subtree, name, inMemoryDirectoryChild{}.FromLeaf(leaf))
+			c.attach(subtree, name, inMemoryDirectoryChild{}.FromLeaf(leaf), written)
 		}
 	}
 }
@@ -203,7 +248,7 @@ func (c *inMemoryDirectoryContents) getEntryAtCookie(firstCookie uint64) *inMemo
 // the lock of the child directory.
 func (c *inMemoryDirectoryContents) getAndLockIfDirectory(name path.Component, lockPile *re_sync.LockPile) (*inMemoryDirectoryEntry, bool) {
 	for {
-		entry, ok := c.entriesMap[name]
+		entry, ok := c.entriesMap[c.getKey(name)]
 		if !ok {
 			// No child node present.
 			return nil, false
@@ -219,7 +264,7 @@ func (c *inMemoryDirectoryContents) getAndLockIfDirectory(name path.Component, l
 			// dropping any of the existing locks.
 			return entry, true
 		}
-		if c.entriesMap[name] == entry {
+		if c.entriesMap[c.getKey(name)] == entry {
 			// Even though we dropped locks, no race occurred.
 			return entry, true
 		}
@@ -263,7 +308,11 @@ type inMemoryPrepopulatedDirectory struct {
 // that keeps all directory metadata stored in memory. As the filesystem
 // API does not allow traversing the hierarchy upwards, this directory
 // can be considered the root directory of the hierarchy.
-func NewInMemoryPrepopulatedDirectory(fileAllocator FileAllocator, symlinkFactory SymlinkFactory, errorLogger util.ErrorLogger, handleAllocator StatefulHandleAllocator, initialContentsSorter Sorter, hiddenFilesMatcher StringMatcher, clock clock.Clock) PrepopulatedDirectory {
+//
+// If caseInsensitive is set, lookups of children ignore case, while
+// the case of names provided at creation time is preserved. This
+// mimics the default behavior of file systems on macOS and Windows.
+func NewInMemoryPrepopulatedDirectory(fileAllocator FileAllocator, symlinkFactory SymlinkFactory, errorLogger util.ErrorLogger, handleAllocator StatefulHandleAllocator, initialContentsSorter Sorter, hiddenFilesMatcher StringMatcher, clock clock.Clock, caseInsensitive bool) PrepopulatedDirectory {
 	subtree := &inMemorySubtree{
 		filesystem: &inMemoryFilesystem{
 			symlinkFactory:          symlinkFactory,
@@ -271,6 +320,7 @@ func NewInMemoryPrepopulatedDirectory(fileAllocator FileAllocator, symlinkFactor
 			initialContentsSorter:   initialContentsSorter,
 			hiddenFilesMatcher:      hiddenFilesMatcher,
 			clock:                   clock,
+			caseInsensitive:         caseInsensitive,
 		},
 		fileAllocator: fileAllocator,
 		errorLogger:   errorLogger,
@@ -287,9 +337,12 @@ func (i *inMemoryPrepopulatedDirectory) getContents() (*inMemoryDirectoryContent
 		if err != nil {
 			return nil, err
 		}
+		if err := i.contents.checkChildrenNames(children); err != nil {
+			return nil, err
+		}
 		i.initialContentsFetcher = nil
 		i.contents.initialize()
-		i.contents.createChildren(i.subtree, children)
+		i.contents.createChildren(i.subtree, children, false)
 	}
 	return &i.contents, nil
 }
@@ -328,7 +381,7 @@ func (i *inMemoryPrepopulatedDirectory) LookupChild(name path.Component) (Prepop
 		return PrepopulatedDirectoryChild{}, err
 	}
 
-	if entry, ok := contents.entriesMap[name]; ok {
+	if entry, ok := contents.entriesMap[contents.getKey(name)]; ok {
 		child := &entry.child
 		directory, leaf := child.GetPair()
 		if directory != nil {
@@ -370,6 +423,43 @@ func (i *inMemoryPrepopulatedDirectory) LookupAllChildren() ([]DirectoryPrepopul
 	return directories, leaves, nil
 }
 
+func (i *inMemoryPrepopulatedDirectory) LookupModifiedChildren() (map[path.Component]bool, error) {
+	i.lock.Lock()
+	if i.initialContentsFetcher != nil {
+		// Contents have not been loaded, meaning that none of
+		// the children can have been modified.
+		i.lock.Unlock()
+		return map[path.Component]bool{}, nil
+	}
+
+	modifiedChildren := map[path.Component]bool{}
+	var directoryNames []path.Component
+	var directories []*inMemoryPrepopulatedDirectory
+	for entry := i.contents.entriesList.next; entry != &i.contents.entriesList; entry = entry.next {
+		if entry.written {
+			modifiedChildren[entry.name] = true
+		} else if directory, _ := entry.child.GetPair(); directory != nil {
+			directoryNames = append(directoryNames, entry.name)
+			directories = append(directories, directory)
+		}
+	}
+	i.lock.Unlock()
+
+	// Only report directories that were left untouched if their
+	// contents have been loaded. Acquire the locks of these
+	// directories after releasing the lock of the parent, so that
+	// no locking order needs to be respected.
+	for index, directory := range directories {
+		directory.lock.Lock()
+		loaded := directory.initialContentsFetcher == nil
+		directory.lock.Unlock()
+		if loaded {
+			modifiedChildren[directoryNames[index]] = false
+		}
+	}
+	return modifiedChildren, nil
+}
+
 func (i *inMemoryPrepopulatedDirectory) ReadDir() ([]filesystem.FileInfo, error) {
 	i.lock.Lock()
 	defer i.lock.Unlock()
@@ -437,7 +527,7 @@ func (i *inMemoryPrepopulatedDirectory) RemoveAll(name path.Component) error {
 		return err
 	}
 
-	if entry, ok := contents.entriesMap[name]; ok {
+	if entry, ok := contents.entriesMap[contents.getKey(name)]; ok {
 		contents.detach(i.subtree, entry)
 		i.lock.Unlock()
 		i.handle.NotifyRemoval(name)
@@ -508,14 +598,15 @@ func (i *inMemoryPrepopulatedDirectory) postRemoveChildren(entries *inMemoryDire
 	}
 }
 
-func (i *inMemoryPrepopulatedDirectory) InstallHooks(fileAllocator FileAllocator, errorLogger util.ErrorLogger) {
+func (i *inMemoryPrepopulatedDirectory) InstallHooks(fileAllocator FileAllocator, errorLogger util.ErrorLogger, operationStatistics *OperationStatistics) {
 	i.lock.Lock()
 	defer i.lock.Unlock()
 
 	i.subtree = &inMemorySubtree{
-		filesystem:    i.subtree.filesystem,
-		fileAllocator: fileAllocator,
-		errorLogger:   errorLogger,
+		filesystem:          i.subtree.filesystem,
+		fileAllocator:       fileAllocator,
+		errorLogger:         errorLogger,
+		operationStatistics: operationStatistics,
 	}
 }
 
@@ -531,12 +622,16 @@ func (i *inMemoryPrepopulatedDirectory) CreateChildren(children map[path.Compone
 		i.lock.Unlock()
 		return syscall.ENOENT
 	}
+	if err := contents.checkChildrenNames(children); err != nil {
+		i.lock.Unlock()
+		return err
+	}
 
 	// Remove entries that are about to be overwritten.
 	var overwrittenEntries *inMemoryDirectoryEntry
 	if overwrite {
 		for name := range children {
-			if entry, ok := contents.entriesMap[name]; ok {
+			if entry, ok := contents.entriesMap[contents.getKey(name)]; ok {
 				contents.detach(i.subtree, entry)
 				entry.previous = overwrittenEntries
 				overwrittenEntries = entry
@@ -544,14 +639,14 @@ func (i *inMemoryPrepopulatedDirectory) CreateChildren(children map[path.Compone
 		}
 	} else {
 		for name := range children {
-			if _, ok := contents.entriesMap[name]; ok {
+			if _, ok := contents.entriesMap[contents.getKey(name)]; ok {
 				i.lock.Unlock()
 				return syscall.EEXIST
 			}
 		}
 	}
 
-	contents.createChildren(i.subtree, children)
+	contents.createChildren(i.subtree, children, false)
 	i.lock.Unlock()
 
 	i.postRemoveChildren(overwrittenEntries)
@@ -567,7 +662,7 @@ func (i *inMemoryPrepopulatedDirectory) CreateAndEnterPrepopulatedDirectory(name
 		return nil, err
 	}
 
-	if entry, ok := contents.entriesMap[name]; ok {
+	if entry, ok := contents.entriesMap[contents.getKey(name)]; ok {
 		directory, leaf := entry.child.GetPair()
 		if directory != nil {
 			// Already a directory.
@@ -577,7 +672,7 @@ func (i *inMemoryPrepopulatedDirectory) CreateAndEnterPrepopulatedDirectory(name
 		// Not a directory. Replace it.
 		contents.detach(i.subtree, entry)
 		leaf.Unlink()
-		newChild := contents.attachNewDirectory(i.subtree, name, EmptyInitialContentsFetcher)
+		newChild := contents.attachNewDirectory(i.subtree, name, EmptyInitialContentsFetcher, false)
 		i.lock.Unlock()
 		i.handle.NotifyRemoval(name)
 		return newChild, nil
@@ -586,7 +681,7 @@ func (i *inMemoryPrepopulatedDirectory) CreateAndEnterPrepopulatedDirectory(name
 	if contents.isDeleted {
 		return nil, syscall.ENOENT
 	}
-	child := contents.attachNewDirectory(i.subtree, name, EmptyInitialContentsFetcher)
+	child := contents.attachNewDirectory(i.subtree, name, EmptyInitialContentsFetcher, false)
 	i.lock.Unlock()
 	return child, nil
 }
@@ -663,7 +758,7 @@ func (i *inMemoryPrepopulatedDirectory) VirtualOpenChild(ctx context.Context, na
 		return nil, 0, ChangeInfo{}, s
 	}
 
-	if entry, ok := contents.entriesMap[name]; ok {
+	if entry, ok := contents.entriesMap[contents.getKey(name)]; ok {
 		// File already exists.
 		if existingOptions == nil {
 			return nil, 0, ChangeInfo{}, StatusErrExist
@@ -703,7 +798,7 @@ func (i *inMemoryPrepopulatedDirectory) VirtualOpenChild(ctx context.Context, na
 
 	// Attach file to the directory.
 	changeIDBefore := contents.changeID
-	contents.attach(i.subtree, name, inMemoryDirectoryChild{}.FromLeaf(leaf))
+	contents.attach(i.subtree, name, inMemoryDirectoryChild{}.FromLeaf(leaf), true)
 	leaf.VirtualGetAttributes(ctx, requested, openedFileAttributes)
 	return leaf, respected, ChangeInfo{
 		Before: changeIDBefore,
@@ -714,6 +809,11 @@ func (i *inMemoryPrepopulatedDirectory) VirtualOpenChild(ctx context.Context, na
 const inMemoryPrepopulatedDirectoryLockedAttributesMask = AttributesMaskChangeID | AttributesMaskLastDataModificationTime
 
 func (i *inMemoryPrepopulatedDirectory) VirtualGetAttributes(ctx context.Context, requested AttributesMask, attributes *Attributes) {
+	i.lock.Lock()
+	timer := i.subtree.operationStatistics.Start(OperationGetAttributes)
+	i.lock.Unlock()
+	defer timer.Finish()
+
 	i.virtualGetAttributesUnlocked(requested, attributes)
 	if requested&inMemoryPrepopulatedDirectoryLockedAttributesMask != 0 {
 		i.lock.Lock()
@@ -761,7 +861,7 @@ func (i *inMemoryPrepopulatedDirectory) VirtualLink(ctx context.Context, name pa
 		return ChangeInfo{}, s
 	}
 	changeIDBefore := contents.changeID
-	contents.attach(i.subtree, name, inMemoryDirectoryChild{}.FromLeaf(child))
+	contents.attach(i.subtree, name, inMemoryDirectoryChild{}.FromLeaf(child), true)
 
 	child.VirtualGetAttributes(ctx, requested, out)
 	return ChangeInfo{
@@ -774,6 +874,8 @@ func (i *inMemoryPrepopulatedDirectory) VirtualLookup(ctx context.Context, name
 	lockPile := re_sync.LockPile{}
 	defer lockPile.UnlockAll()
 	lockPile.Lock(&i.lock)
+	timer := i.subtree.operationStatistics.Start(OperationLookup)
+	defer timer.Finish()
 
 	contents, s := i.virtualGetContents()
 	if s != StatusOK {
@@ -796,7 +898,7 @@ func (i *inMemoryPrepopulatedDirectory) VirtualLookup(ctx context.Context, name
 			return DirectoryChild{}.FromLeaf(leaf), StatusOK
 		}
 	} else {
-		if entry, ok := contents.entriesMap[name]; ok {
+		if entry, ok := contents.entriesMap[contents.getKey(name)]; ok {
 			directory, leaf := entry.child.GetPair()
 			if directory != nil {
 				directory.virtualGetAttributesUnlocked(requested, out)
@@ -822,7 +924,7 @@ func (i *inMemoryPrepopulatedDirectory) VirtualMkdir(name path.Component, reques
 		return nil, ChangeInfo{}, s
 	}
 	changeIDBefore := contents.changeID
-	child := contents.attachNewDirectory(i.subtree, name, EmptyInitialContentsFetcher)
+	child := contents.attachNewDirectory(i.subtree, name, EmptyInitialContentsFetcher, true)
 
 	// Even though the child directory is not locked explicitly, the
 	// following is safe, as the directory has not been returned yet.
@@ -853,7 +955,7 @@ func (i *inMemoryPrepopulatedDirectory) VirtualMknod(ctx context.Context, name p
 		New().
 		AsNativeLeaf(NewSpecialFile(fileType, nil))
 	changeIDBefore := contents.changeID
-	contents.attach(i.subtree, name, inMemoryDirectoryChild{}.FromLeaf(child))
+	contents.attach(i.subtree, name, inMemoryDirectoryChild{}.FromLeaf(child), true)
 
 	child.VirtualGetAttributes(ctx, requested, out)
 	return child, ChangeInfo{
@@ -866,6 +968,8 @@ func (i *inMemoryPrepopulatedDirectory) VirtualReadDir(ctx context.Context, firs
 	lockPile := re_sync.LockPile{}
 	defer lockPile.UnlockAll()
 	lockPile.Lock(&i.lock)
+	timer := i.subtree.operationStatistics.Start(OperationReadDir)
+	defer timer.Finish()
 
 	contents, s := i.virtualGetContents()
 	if s != StatusOK {
@@ -934,14 +1038,23 @@ func (i *inMemoryPrepopulatedDirectory) VirtualRename(oldName path.Component, ne
 	oldChangeIDBefore := oldContents.changeID
 	newChangeIDBefore := newContents.changeID
 	if newEntry, ok := newContents.getAndLockIfDirectory(newName, &lockPile); ok {
-		oldEntry, ok := oldContents.entriesMap[oldName]
+		oldEntry, ok := oldContents.entriesMap[oldContents.getKey(oldName)]
 		if !ok {
 			return ChangeInfo{}, ChangeInfo{}, StatusErrNoEnt
 		}
 		oldChild := oldEntry.child
 		oldDirectory, oldLeaf := oldChild.GetPair()
 		newChild := newEntry.child
-		if newDirectory, newLeaf := newChild.GetPair(); newDirectory != nil {
+		if newEntry == oldEntry {
+			// Renaming an entry onto itself. In case
+			// insensitive directories the names may differ
+			// in case, in which case the new name should be
+			// retained.
+			if oldName != newName {
+				oldContents.detach(i.subtree, oldEntry)
+				newContents.attach(i.subtree, newName, oldChild, true)
+			}
+		} else if newDirectory, newLeaf := newChild.GetPair(); newDirectory != nil {
 			// Renaming to a location at which a directory
 			// already exists.
 			if oldDirectory == nil {
@@ -966,7 +1079,7 @@ func (i *inMemoryPrepopulatedDirectory) VirtualRename(oldName path.Component, ne
 				// structures.
 				newContents.detach(i.subtree, newEntry)
 				newDirectory.markDeleted()
-				newContents.attach(i.subtree, newName, oldChild)
+				newContents.attach(i.subtree, newName, oldChild, true)
 			}
 		} else {
 			// Renaming to a location at which a leaf
@@ -982,7 +1095,7 @@ func (i *inMemoryPrepopulatedDirectory) VirtualRename(oldName path.Component, ne
 				oldContents.detach(i.subtree, oldEntry)
 				newContents.detach(i.subtree, newEntry)
 				newLeaf.Unlink()
-				newContents.attach(i.subtree, newName, oldChild)
+				newContents.attach(i.subtree, newName, oldChild, true)
 			}
 		}
 	} else {
@@ -990,7 +1103,7 @@ func (i *inMemoryPrepopulatedDirectory) VirtualRename(oldName path.Component, ne
 		if newContents.isDeleted {
 			return ChangeInfo{}, ChangeInfo{}, StatusErrNoEnt
 		}
-		oldEntry, ok := oldContents.entriesMap[oldName]
+		oldEntry, ok := oldContents.entriesMap[oldContents.getKey(oldName)]
 		if !ok {
 			return ChangeInfo{}, ChangeInfo{}, StatusErrNoEnt
 		}
@@ -1001,7 +1114,7 @@ func (i *inMemoryPrepopulatedDirectory) VirtualRename(oldName path.Component, ne
 			}
 		}
 		oldContents.detach(i.subtree, oldEntry)
-		newContents.attach(i.subtree, newName, oldChild)
+		newContents.attach(i.subtree, newName, oldChild, true)
 	}
 	return ChangeInfo{
 			Before: oldChangeIDBefore,
@@ -1074,7 +1187,7 @@ func (i *inMemoryPrepopulatedDirectory) VirtualSymlink(ctx context.Context, poin
 	}
 	child := i.subtree.filesystem.symlinkFactory.LookupSymlink(pointedTo)
 	changeIDBefore := contents.changeID
-	contents.attach(i.subtree, linkName, inMemoryDirectoryChild{}.FromLeaf(child))
+	contents.attach(i.subtree, linkName, inMemoryDirectoryChild{}.FromLeaf(child), true)
 
 	child.VirtualGetAttributes(ctx, requested, out)
 	return child, ChangeInfo{
@@ -3,6 +3,7 @@ package virtual
 import (
 	"context"
 	"math/bits"
+	"os"
 
 	"github.com/buildbarn/bb-storage/pkg/filesystem"
 )
@@ -40,6 +41,20 @@ func (o *OpenExistingOptions) ToAttributesMask() (m AttributesMask) {
 	return
 }
 
+// XAttrSetFlags specifies constraints that need to be satisfied when
+// calling Leaf.VirtualSetXAttr(). These correspond to setxattr()'s
+// XATTR_CREATE and XATTR_REPLACE flags.
+type XAttrSetFlags uint32
+
+const (
+	// XAttrSetFlagsCreate causes VirtualSetXAttr() to fail with
+	// StatusErrExist if the extended attribute already exists.
+	XAttrSetFlagsCreate XAttrSetFlags = 1 << iota
+	// XAttrSetFlagsReplace causes VirtualSetXAttr() to fail with
+	// StatusErrNoXAttr if the extended attribute does not exist.
+	XAttrSetFlagsReplace
+)
+
 // Leaf node that is exposed through FUSE using SimpleRawFileSystem, or
 // through NFSv4. Examples of leaf nodes are regular files, sockets,
 // FIFOs, symbolic links and devices.
@@ -49,12 +64,41 @@ type Leaf interface {
 	Node
 
 	VirtualAllocate(off, size uint64) Status
+
+	// VirtualDeallocate releases the storage backing a range of
+	// the leaf, corresponding to fallocate()'s
+	// FALLOC_FL_PUNCH_HOLE. Subsequent reads of the range return
+	// zero bytes. The size of the leaf remains unaltered.
+	VirtualDeallocate(off, size uint64) Status
 	VirtualSeek(offset uint64, regionType filesystem.RegionType) (*uint64, Status)
 	VirtualOpenSelf(ctx context.Context, shareAccess ShareMask, options *OpenExistingOptions, requested AttributesMask, attributes *Attributes) Status
 	VirtualRead(buf []byte, offset uint64) (n int, eof bool, s Status)
 	VirtualReadlink(ctx context.Context) ([]byte, Status)
 	VirtualClose(shareAccess ShareMask)
 	VirtualWrite(buf []byte, offset uint64) (int, Status)
+
+	// VirtualSync is called when fsync() is invoked against the
+	// leaf. Leaves that buffer writes should flush them, and report
+	// any errors that occurred while doing so.
+	VirtualSync() Status
+
+	// Operations for accessing extended attributes, corresponding
+	// to getxattr(), listxattr(), setxattr() and removexattr().
+	// Leaves that are incapable of storing extended attributes
+	// report that none are present, and fail modifications.
+	VirtualGetXAttr(name string) ([]byte, Status)
+	VirtualListXAttrs() []string
+	VirtualSetXAttr(name string, value []byte, flags XAttrSetFlags) Status
+	VirtualRemoveXAttr(name string) Status
+
+	// VirtualOpenPassthroughFile may be called after the leaf has
+	// been opened for reading to obtain a local file that has the
+	// same contents. The FUSE server may hand this file to the
+	// kernel, so that read operations no longer need to be
+	// processed by the virtual file system. Ownership of the file
+	// is transferred to the caller. If no such file is available,
+	// nil is returned.
+	VirtualOpenPassthroughFile() *os.File
 }
 
 // StatelessLeafLinkCount is the value that should be assigned to
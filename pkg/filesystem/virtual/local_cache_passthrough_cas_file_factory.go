@@ -0,0 +1,67 @@
+package virtual
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+type localCachePassthroughCASFileFactory struct {
+	base               CASFileFactory
+	cacheDirectoryPath string
+}
+
+// NewLocalCachePassthroughCASFileFactory creates a decorator for
+// CASFileFactory that causes VirtualOpenPassthroughFile() to return
+// files that are present in a local cache directory, using the same
+// naming scheme as the one used by HardlinkingFileFetcher. This
+// permits FUSE servers to let the kernel read these files directly,
+// as opposed to forwarding reads to the Content Addressable Storage.
+//
+// Files in the cache directory are only validated by size. The cache
+// directory must therefore not be writable by build actions.
+func NewLocalCachePassthroughCASFileFactory(base CASFileFactory, cacheDirectoryPath string) CASFileFactory {
+	return &localCachePassthroughCASFileFactory{
+		base:               base,
+		cacheDirectoryPath: cacheDirectoryPath,
+	}
+}
+
+func (cff *localCachePassthroughCASFileFactory) LookupFile(blobDigest digest.Digest, isExecutable bool, lastDataModificationTime time.Time, readMonitor FileReadMonitor) NativeLeaf {
+	return &localCachePassthroughCASFile{
+		NativeLeaf:   cff.base.LookupFile(blobDigest, isExecutable, lastDataModificationTime, readMonitor),
+		factory:      cff,
+		digest:       blobDigest,
+		isExecutable: isExecutable,
+	}
+}
+
+type localCachePassthroughCASFile struct {
+	NativeLeaf
+	factory      *localCachePassthroughCASFileFactory
+	digest       digest.Digest
+	isExecutable bool
+}
+
+func (f *localCachePassthroughCASFile) VirtualOpenPassthroughFile() *os.File {
+	// The contents of the executable and non-executable variants
+	// of a file are identical. Prefer the variant that matches.
+	key := f.digest.GetKey(digest.KeyWithoutInstance)
+	suffixes := [...]string{"-x", "+x"}
+	if f.isExecutable {
+		suffixes[0], suffixes[1] = suffixes[1], suffixes[0]
+	}
+	for _, suffix := range suffixes {
+		file, err := os.Open(filepath.Join(f.factory.cacheDirectoryPath, key+suffix))
+		if err != nil {
+			continue
+		}
+		if fileInfo, err := file.Stat(); err == nil && fileInfo.Mode().IsRegular() && fileInfo.Size() == f.digest.GetSizeBytes() {
+			return file
+		}
+		file.Close()
+	}
+	return f.NativeLeaf.VirtualOpenPassthroughFile()
+}
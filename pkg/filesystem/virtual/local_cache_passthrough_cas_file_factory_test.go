@@ -0,0 +1,65 @@
+package virtual_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalCachePassthroughCASFileFactory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	cacheDirectoryPath := t.TempDir()
+	baseCASFileFactory := mock.NewMockCASFileFactory(ctrl)
+	casFileFactory := virtual.NewLocalCachePassthroughCASFileFactory(baseCASFileFactory, cacheDirectoryPath)
+
+	blobDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+
+	t.Run("NotPresent", func(t *testing.T) {
+		// If the file is not present in the cache directory,
+		// the request should be forwarded to the underlying leaf.
+		underlyingLeaf := mock.NewMockNativeLeaf(ctrl)
+		baseCASFileFactory.EXPECT().LookupFile(blobDigest, false, time.Time{}, nil).Return(underlyingLeaf)
+		underlyingLeaf.EXPECT().VirtualOpenPassthroughFile()
+
+		leaf := casFileFactory.LookupFile(blobDigest, false, time.Time{}, nil)
+		require.Nil(t, leaf.VirtualOpenPassthroughFile())
+	})
+
+	t.Run("SizeMismatch", func(t *testing.T) {
+		// Files in the cache directory whose size does not match
+		// the digest should be ignored.
+		require.NoError(t, os.WriteFile(filepath.Join(cacheDirectoryPath, "3-8b1a9953c4611296a827abf8c47804d7-5-x"), []byte("Hello world"), 0o444))
+		underlyingLeaf := mock.NewMockNativeLeaf(ctrl)
+		baseCASFileFactory.EXPECT().LookupFile(blobDigest, false, time.Time{}, nil).Return(underlyingLeaf)
+		underlyingLeaf.EXPECT().VirtualOpenPassthroughFile()
+
+		leaf := casFileFactory.LookupFile(blobDigest, false, time.Time{}, nil)
+		require.Nil(t, leaf.VirtualOpenPassthroughFile())
+	})
+
+	t.Run("OtherVariant", func(t *testing.T) {
+		// The contents of executable and non-executable files
+		// are identical. It should be possible to use the
+		// executable variant for non-executable files.
+		require.NoError(t, os.WriteFile(filepath.Join(cacheDirectoryPath, "3-8b1a9953c4611296a827abf8c47804d7-5+x"), []byte("Hello"), 0o555))
+		underlyingLeaf := mock.NewMockNativeLeaf(ctrl)
+		baseCASFileFactory.EXPECT().LookupFile(blobDigest, false, time.Time{}, nil).Return(underlyingLeaf)
+
+		leaf := casFileFactory.LookupFile(blobDigest, false, time.Time{}, nil)
+		f := leaf.VirtualOpenPassthroughFile()
+		require.NotNil(t, f)
+		var buf [10]byte
+		n, _ := f.ReadAt(buf[:], 0)
+		require.Equal(t, "Hello", string(buf[:n]))
+		require.NoError(t, f.Close())
+	})
+}
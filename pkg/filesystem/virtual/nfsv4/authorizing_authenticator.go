@@ -0,0 +1,48 @@
+package nfsv4
+
+import (
+	"context"
+
+	"github.com/buildbarn/bb-storage/pkg/auth"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/go-xdr/pkg/protocols/rpcv2"
+	"github.com/buildbarn/go-xdr/pkg/rpcserver"
+)
+
+type authorizingAuthenticator struct {
+	base       rpcserver.Authenticator
+	authorizer auth.Authorizer
+}
+
+// NewAuthorizingAuthenticator creates a decorator for an RPCv2
+// Authenticator that only permits requests to be processed if the
+// authentication metadata obtained by the base Authenticator is
+// permitted by an Authorizer. This can be used to restrict access to
+// an NFSv4 server to a set of users or hosts. As NFSv4 has no notion
+// of instance names, the Authorizer is invoked against the empty
+// instance name.
+//
+// This decorator does not provide any cryptographic guarantees. When
+// used in combination with NewSystemAuthenticator(), authorization
+// decisions are based on AUTH_SYS credentials as provided by the
+// client. These are only as trustworthy as the client that is
+// connected to the NFSv4 server, which is only the case if the kernel
+// is the sole party that is able to connect to it. Use
+// NewRPCSECGSSAuthenticator() if credentials need to be verified.
+func NewAuthorizingAuthenticator(base rpcserver.Authenticator, authorizer auth.Authorizer) rpcserver.Authenticator {
+	return &authorizingAuthenticator{
+		base:       base,
+		authorizer: authorizer,
+	}
+}
+
+func (a *authorizingAuthenticator) Authenticate(ctx context.Context, credentials, verifier *rpcv2.OpaqueAuth) (context.Context, rpcv2.OpaqueAuth, rpcv2.AuthStat) {
+	ctx, responseVerifier, s := a.base.Authenticate(ctx, credentials, verifier)
+	if s != rpcv2.AUTH_OK {
+		return nil, rpcv2.OpaqueAuth{}, s
+	}
+	if err := auth.AuthorizeSingleInstanceName(ctx, a.authorizer, digest.EmptyInstanceName); err != nil {
+		return nil, rpcv2.OpaqueAuth{}, rpcv2.AUTH_TOOWEAK
+	}
+	return ctx, responseVerifier, rpcv2.AUTH_OK
+}
@@ -0,0 +1,92 @@
+package nfsv4_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual/nfsv4"
+	"github.com/buildbarn/bb-storage/pkg/auth"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/eviction"
+	"github.com/buildbarn/go-xdr/pkg/protocols/rpcv2"
+	"github.com/golang/mock/gomock"
+	"github.com/jmespath/go-jmespath"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorizingAuthenticator(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseAuthenticator := nfsv4.NewSystemAuthenticator(
+		jmespath.MustCompile("{\"private\": uid}"),
+		10,
+		eviction.NewLRUSet[nfsv4.SystemAuthenticatorCacheKey]())
+	authorizer := mock.NewMockAuthorizer(ctrl)
+	authenticator := nfsv4.NewAuthorizingAuthenticator(baseAuthenticator, authorizer)
+
+	credentials := rpcv2.OpaqueAuth{
+		Flavor: rpcv2.AUTH_SYS,
+		Body: []byte{
+			// stamp.
+			0x7b, 0xfe, 0x88, 0xfc,
+			// machinename.
+			0x00, 0x00, 0x00, 0x09,
+			0x6c, 0x6f, 0x63, 0x61,
+			0x6c, 0x68, 0x6f, 0x73,
+			0x74, 0x00, 0x00, 0x00,
+			// uid.
+			0x00, 0x00, 0x03, 0xe8,
+			// gid.
+			0x00, 0x00, 0x00, 0x64,
+			// gids.
+			0x00, 0x00, 0x00, 0x02,
+			0x00, 0x00, 0x00, 0x0c,
+			0x00, 0x00, 0x00, 0x14,
+		},
+	}
+
+	t.Run("AuthenticationFailure", func(t *testing.T) {
+		// Requests that fail authentication should not be
+		// forwarded to the authorizer.
+		_, _, s := authenticator.Authenticate(
+			ctx,
+			&rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_NONE},
+			&rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_NONE})
+		require.Equal(t, rpcv2.AUTH_BADCRED, s)
+	})
+
+	t.Run("AuthorizationFailure", func(t *testing.T) {
+		authorizer.EXPECT().Authorize(gomock.Any(), []digest.InstanceName{digest.EmptyInstanceName}).
+			DoAndReturn(func(ctx context.Context, instanceNames []digest.InstanceName) []error {
+				require.Equal(t, map[string]any{
+					"private": 1000.0,
+				}, auth.AuthenticationMetadataFromContext(ctx).GetRaw())
+				return []error{status.Error(codes.PermissionDenied, "User is not permitted to access this file system")}
+			})
+
+		_, _, s := authenticator.Authenticate(
+			ctx,
+			&credentials,
+			&rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_NONE})
+		require.Equal(t, rpcv2.AUTH_TOOWEAK, s)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		authorizer.EXPECT().Authorize(gomock.Any(), []digest.InstanceName{digest.EmptyInstanceName}).
+			Return([]error{nil})
+
+		newCtx, verifier, s := authenticator.Authenticate(
+			ctx,
+			&credentials,
+			&rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_NONE})
+		require.Equal(t, rpcv2.AUTH_OK, s)
+		require.Equal(t, rpcv2.AUTH_SHORT, verifier.Flavor)
+		require.Equal(t, map[string]any{
+			"private": 1000.0,
+		}, auth.AuthenticationMetadataFromContext(newCtx).GetRaw())
+	})
+}
@@ -57,16 +57,32 @@ var (
 			Name:      "base_program_open_owner_files_removed_total",
 			Help:      "Number of open-owner files removed, either through NFSv4 CLOSE operations or due to inactivity on the open-owner.",
 		})
+
+	baseProgramDelegationsCreated = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "nfsv4",
+			Name:      "base_program_delegations_created_total",
+			Help:      "Number of read delegations handed out through NFSv4 OPEN operations.",
+		})
+	baseProgramDelegationsRemoved = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "nfsv4",
+			Name:      "base_program_delegations_removed_total",
+			Help:      "Number of read delegations removed, either through NFSv4 DELEGRETURN operations, conflicting opens or due to inactivity on the client.",
+		})
 )
 
 type baseProgram struct {
-	rootFileHandle     fileHandle
-	handleResolver     virtual.HandleResolver
-	rebootVerifier     nfsv4.Verifier4
-	stateIDOtherPrefix [stateIDOtherPrefixLength]byte
-	clock              clock.Clock
-	enforcedLeaseTime  time.Duration
-	announcedLeaseTime nfsv4.NfsLease4
+	rootFileHandle        fileHandle
+	handleResolver        virtual.HandleResolver
+	rebootVerifier        nfsv4.Verifier4
+	stateIDOtherPrefix    [stateIDOtherPrefixLength]byte
+	clock                 clock.Clock
+	enforcedLeaseTime     time.Duration
+	announcedLeaseTime    nfsv4.NfsLease4
+	enableReadDelegations bool
 
 	lock                         sync.Mutex
 	now                          time.Time
@@ -77,6 +93,7 @@ type baseProgram struct {
 	openOwnerFilesByOther        map[regularStateIDOther]*openOwnerFileState
 	openedFilesByHandle          map[string]*openedFileState
 	lockOwnerFilesByOther        map[regularStateIDOther]*lockOwnerFileState
+	delegationsByOther           map[regularStateIDOther]*delegationState
 	idleClientConfirmations      clientConfirmationState
 	unusedOpenOwners             openOwnerState
 }
@@ -84,13 +101,22 @@ type baseProgram struct {
 // NewBaseProgram creates an nfsv4.Nfs4Program that forwards all
 // operations to a virtual file system. It implements most of the
 // features of NFSv4.0.
-func NewBaseProgram(rootDirectory virtual.Directory, handleResolver virtual.HandleResolver, randomNumberGenerator random.SingleThreadedGenerator, rebootVerifier nfsv4.Verifier4, stateIDOtherPrefix [stateIDOtherPrefixLength]byte, clock clock.Clock, enforcedLeaseTime, announcedLeaseTime time.Duration) nfsv4.Nfs4Program {
+//
+// If enableReadDelegations is set, clients are handed out read
+// delegations for files that are opened for reading. As this
+// implementation does not support callbacks, delegations cannot be
+// recalled. This option should therefore only be enabled if the file
+// system is accessed by a single client.
+func NewBaseProgram(rootDirectory virtual.Directory, handleResolver virtual.HandleResolver, randomNumberGenerator random.SingleThreadedGenerator, rebootVerifier nfsv4.Verifier4, stateIDOtherPrefix [stateIDOtherPrefixLength]byte, clock clock.Clock, enforcedLeaseTime, announcedLeaseTime time.Duration, enableReadDelegations bool) nfsv4.Nfs4Program {
 	baseProgramPrometheusMetrics.Do(func() {
 		prometheus.MustRegister(baseProgramOpenOwnersCreated)
 		prometheus.MustRegister(baseProgramOpenOwnersRemoved)
 
 		prometheus.MustRegister(baseProgramOpenOwnerFilesCreated)
 		prometheus.MustRegister(baseProgramOpenOwnerFilesRemoved)
+
+		prometheus.MustRegister(baseProgramDelegationsCreated)
+		prometheus.MustRegister(baseProgramDelegationsRemoved)
 	})
 
 	var attributes virtual.Attributes
@@ -100,12 +126,13 @@ func NewBaseProgram(rootDirectory virtual.Directory, handleResolver virtual.Hand
 			handle: attributes.GetFileHandle(),
 			node:   virtual.DirectoryChild{}.FromDirectory(rootDirectory),
 		},
-		handleResolver:     handleResolver,
-		rebootVerifier:     rebootVerifier,
-		stateIDOtherPrefix: stateIDOtherPrefix,
-		clock:              clock,
-		enforcedLeaseTime:  enforcedLeaseTime,
-		announcedLeaseTime: nfsv4.NfsLease4(announcedLeaseTime.Seconds()),
+		handleResolver:        handleResolver,
+		rebootVerifier:        rebootVerifier,
+		stateIDOtherPrefix:    stateIDOtherPrefix,
+		clock:                 clock,
+		enforcedLeaseTime:     enforcedLeaseTime,
+		announcedLeaseTime:    nfsv4.NfsLease4(announcedLeaseTime.Seconds()),
+		enableReadDelegations: enableReadDelegations,
 
 		randomNumberGenerator:        randomNumberGenerator,
 		clientsByLongID:              map[string]*clientState{},
@@ -114,6 +141,7 @@ func NewBaseProgram(rootDirectory virtual.Directory, handleResolver virtual.Hand
 		openOwnerFilesByOther:        map[regularStateIDOther]*openOwnerFileState{},
 		openedFilesByHandle:          map[string]*openedFileState{},
 		lockOwnerFilesByOther:        map[regularStateIDOther]*lockOwnerFileState{},
+		delegationsByOther:           map[regularStateIDOther]*delegationState{},
 	}
 	p.idleClientConfirmations.previousIdle = &p.idleClientConfirmations
 	p.idleClientConfirmations.nextIdle = &p.idleClientConfirmations
@@ -765,6 +793,28 @@ func (s *compoundState) getLockOwnerFileByStateID(stateID regularStateID) (*lock
 	return lofs, nfsv4.NFS4_OK
 }
 
+// getDelegationByStateID obtains a delegation by delegation state ID.
+// It also checks whether the delegation state ID corresponds to the
+// current file handle, and that the client provided sequence ID
+// matches the server's value.
+func (s *compoundState) getDelegationByStateID(stateID regularStateID) (*delegationState, nfsv4.Nfsstat4) {
+	p := s.program
+	ds, ok := p.delegationsByOther[stateID.other]
+	if !ok {
+		return nil, nfsv4.NFS4ERR_BAD_STATEID
+	}
+	if !s.currentFileHandle.node.IsSet() {
+		return nil, nfsv4.NFS4ERR_NOFILEHANDLE
+	}
+	if !bytes.Equal(s.currentFileHandle.handle, ds.openedFile.handle) {
+		return nil, nfsv4.NFS4ERR_BAD_STATEID
+	}
+	if st := compareStateSeqID(stateID.seqID, ds.stateID.seqID); st != nfsv4.NFS4_OK {
+		return nil, st
+	}
+	return ds, nfsv4.NFS4_OK
+}
+
 // getOpenedLeaf is used by READ and WRITE operations to obtain an
 // opened leaf corresponding to a file handle and open-owner state ID.
 //
@@ -813,6 +863,30 @@ func (s *compoundState) getOpenedLeaf(ctx context.Context, stateID *nfsv4.Statei
 			return nil, nil, nfsv4.NFS4ERR_OPENMODE
 		}
 	case nfsv4.NFS4ERR_BAD_STATEID:
+		// Client may have provided a delegation state ID.
+		if ds, st := s.getDelegationByStateID(*internalStateID); st != nfsv4.NFS4ERR_BAD_STATEID {
+			if st != nfsv4.NFS4_OK {
+				return nil, nil, st
+			}
+			if shareAccess&^ds.shareAccess != 0 {
+				// Attempted to write to a file using a
+				// read delegation.
+				return nil, nil, nfsv4.NFS4ERR_OPENMODE
+			}
+
+			clientConfirmation := ds.confirmedClient.confirmation
+			clientConfirmation.hold(p)
+			clonedShareAccess := ds.shareCount.clone(shareAccess)
+			return ds.openedFile.leaf, func() {
+				var ll leavesToClose
+				p.enter()
+				ds.downgradeShareAccess(&clonedShareAccess, 0, &ll)
+				clientConfirmation.release(p)
+				p.leave()
+				ll.closeAll()
+			}, nfsv4.NFS4_OK
+		}
+
 		// Client may have provided a lock state ID.
 		lofs, st := s.getLockOwnerFileByStateID(*internalStateID)
 		if st != nfsv4.NFS4_OK {
@@ -987,9 +1061,14 @@ func (s *compoundState) txClose(openStateID regularStateID, ll *leavesToClose) (
 func (s *compoundState) opCommit(args *nfsv4.Commit4args) nfsv4.Commit4res {
 	// As this implementation is purely built for the purpose of
 	// doing builds, there is no need to actually commit to storage.
-	if _, st := s.currentFileHandle.getLeaf(); st != nfsv4.NFS4_OK {
+	// Leaves are only requested to flush writes they buffer.
+	currentLeaf, st := s.currentFileHandle.getLeaf()
+	if st != nfsv4.NFS4_OK {
 		return &nfsv4.Commit4res_default{Status: st}
 	}
+	if vs := currentLeaf.VirtualSync(); vs != virtual.StatusOK {
+		return &nfsv4.Commit4res_default{Status: toNFSv4Status(vs)}
+	}
 	return &nfsv4.Commit4res_NFS4_OK{
 		Resok4: nfsv4.Commit4resok{
 			Writeverf: s.program.rebootVerifier,
@@ -1057,10 +1136,23 @@ func (s *compoundState) opDelegpurge(args *nfsv4.Delegpurge4args) nfsv4.Delegpur
 }
 
 func (s *compoundState) opDelegreturn(args *nfsv4.Delegreturn4args) nfsv4.Delegreturn4res {
-	// This implementation never hands out any delegations to the
-	// client, meaning that any state ID provided to this operation
-	// is invalid.
-	return nfsv4.Delegreturn4res{Status: nfsv4.NFS4ERR_BAD_STATEID}
+	var ll leavesToClose
+	defer ll.closeAll()
+
+	p := s.program
+	p.enter()
+	defer p.leave()
+
+	delegationStateID, st := p.internalizeRegularStateID(&args.DelegStateid)
+	if st != nfsv4.NFS4_OK {
+		return nfsv4.Delegreturn4res{Status: st}
+	}
+	ds, st := s.getDelegationByStateID(delegationStateID)
+	if st != nfsv4.NFS4_OK {
+		return nfsv4.Delegreturn4res{Status: st}
+	}
+	ds.remove(p, &ll)
+	return nfsv4.Delegreturn4res{Status: nfsv4.NFS4_OK}
 }
 
 func (s *compoundState) opGetattr(ctx context.Context, args *nfsv4.Getattr4args) nfsv4.Getattr4res {
@@ -1570,108 +1662,12 @@ func (s *compoundState) txOpen(ctx context.Context, args *nfsv4.Open4args, oos *
 		existingOptions = &virtual.OpenExistingOptions{}
 	}
 
-	// Convert claim. As we don't support delegations, we can only
-	// meaningfully support CLAIM_NULL and CLAIM_PREVIOUS.
+	// Convert claim. As we don't support reclaiming delegations
+	// after a restart, we can only meaningfully support CLAIM_NULL,
+	// CLAIM_PREVIOUS and CLAIM_DELEGATE_CUR.
 	switch claim := args.Claim.(type) {
 	case *nfsv4.OpenClaim4_CLAIM_NULL:
-		p.leave()
-		isLocked = false
-
-		currentDirectory, st := s.currentFileHandle.getDirectory()
-		if st != nfsv4.NFS4_OK {
-			return &nfsv4.Open4res_default{Status: st}
-		}
-
-		name, st := nfsv4NewComponent(claim.File)
-		if st != nfsv4.NFS4_OK {
-			return &nfsv4.Open4res_default{Status: st}
-		}
-
-		// Open the file.
-		var attributes virtual.Attributes
-		leaf, respected, changeInfo, vs := currentDirectory.VirtualOpenChild(
-			ctx,
-			name,
-			shareAccess,
-			createAttributes,
-			existingOptions,
-			virtual.AttributesMaskFileHandle,
-			&attributes)
-		if vs != virtual.StatusOK {
-			return &nfsv4.Open4res_default{Status: toNFSv4Status(vs)}
-		}
-
-		handle := attributes.GetFileHandle()
-		handleKey := string(handle)
-
-		s.currentFileHandle = fileHandle{
-			handle: handle,
-			node:   virtual.DirectoryChild{}.FromLeaf(leaf),
-		}
-
-		response := &nfsv4.Open4res_NFS4_OK{
-			Resok4: nfsv4.Open4resok{
-				Cinfo:      toNFSv4ChangeInfo(&changeInfo),
-				Rflags:     nfsv4.OPEN4_RESULT_LOCKTYPE_POSIX,
-				Attrset:    attributesMaskToBitmap4(respected),
-				Delegation: &nfsv4.OpenDelegation4_OPEN_DELEGATE_NONE{},
-			},
-		}
-
-		p.enter()
-		isLocked = true
-
-		oofs, ok := oos.filesByHandle[handleKey]
-		if ok {
-			// This file has already been opened by this open-owner,
-			// meaning we should upgrade the existing opened file.
-			// The newly opened file can be closed again.
-			//
-			// More details: RFC 7530, section 9.11.
-			oofs.upgrade(shareAccess, leaf, ll)
-		} else {
-			openedFile, ok := p.openedFilesByHandle[handleKey]
-			if ok {
-				openedFile.openOwnersCount.increase()
-			} else {
-				// This file has not been opened by any
-				// open-owner. Keep track of it, so that we
-				// don't need to call into HandleResolver. This
-				// ensures that the file remains accessible
-				// while opened, even when unlinked.
-				openedFile = &openedFileState{
-					handle:          handle,
-					handleKey:       handleKey,
-					leaf:            leaf,
-					openOwnersCount: 1,
-				}
-				openedFile.locks.Initialize()
-				p.openedFilesByHandle[handleKey] = openedFile
-			}
-
-			// This file has not been opened by this open-owner.
-			// Create a new state ID.
-			oofs = &openOwnerFileState{
-				openOwner:      oos,
-				openedFile:     openedFile,
-				stateID:        p.newRegularStateID(1),
-				lockOwnerFiles: map[*lockOwnerState]*lockOwnerFileState{},
-			}
-			if oofs.shareCount.upgrade(&oofs.shareAccess, shareAccess) != 0 {
-				panic("Share access reservations can't overlap for newly created files")
-			}
-			oos.filesByHandle[handleKey] = oofs
-			p.openOwnerFilesByOther[oofs.stateID.other] = oofs
-			baseProgramOpenOwnerFilesCreated.Inc()
-		}
-
-		response.Resok4.Stateid = p.externalizeStateID(oofs.stateID)
-		if !oos.confirmed {
-			// The first time that this open-owner is used. Request
-			// that the caller issues an OPEN_CONFIRM operation.
-			response.Resok4.Rflags |= nfsv4.OPEN4_RESULT_CONFIRM
-		}
-		return response
+		return s.txOpenByName(ctx, claim.File, nil, shareAccess, createAttributes, existingOptions, oos, ll)
 	case *nfsv4.OpenClaim4_CLAIM_PREVIOUS:
 		// Check whether the current open-owner has opened the
 		// file before, using the same delegation type.
@@ -1720,6 +1716,9 @@ func (s *compoundState) txOpen(ctx context.Context, args *nfsv4.Open4args, oos *
 		isLocked = true
 
 		oofs.upgrade(shareAccess, currentLeaf, ll)
+		if shareAccess&virtual.ShareMaskWrite != 0 {
+			oofs.openedFile.revokeDelegations(p, oos.confirmedClient, ll)
+		}
 		return &nfsv4.Open4res_NFS4_OK{
 			Resok4: nfsv4.Open4resok{
 				Stateid:    p.externalizeStateID(oofs.stateID),
@@ -1729,7 +1728,24 @@ func (s *compoundState) txOpen(ctx context.Context, args *nfsv4.Open4args, oos *
 			},
 		}
 	case *nfsv4.OpenClaim4_CLAIM_DELEGATE_CUR:
-		return &nfsv4.Open4res_default{Status: nfsv4.NFS4ERR_RECLAIM_BAD}
+		// The client is converting an open that it performed
+		// locally while holding a delegation to one that is
+		// tracked by the server, typically prior to returning
+		// the delegation.
+		//
+		// More details: RFC 7530, section 10.4.4.
+		delegationStateID, st := p.internalizeRegularStateID(&claim.DelegateCurInfo.DelegateStateid)
+		if st != nfsv4.NFS4_OK {
+			return &nfsv4.Open4res_default{Status: st}
+		}
+		ds, ok := p.delegationsByOther[delegationStateID.other]
+		if !ok || ds.confirmedClient != oos.confirmedClient {
+			return &nfsv4.Open4res_default{Status: nfsv4.NFS4ERR_RECLAIM_BAD}
+		}
+		if st := compareStateSeqID(delegationStateID.seqID, ds.stateID.seqID); st != nfsv4.NFS4_OK {
+			return &nfsv4.Open4res_default{Status: st}
+		}
+		return s.txOpenByName(ctx, claim.DelegateCurInfo.File, ds, shareAccess, createAttributes, existingOptions, oos, ll)
 	case *nfsv4.OpenClaim4_CLAIM_DELEGATE_PREV:
 		return &nfsv4.Open4res_default{Status: nfsv4.NFS4ERR_NOTSUPP}
 	default:
@@ -1737,8 +1753,186 @@ func (s *compoundState) txOpen(ctx context.Context, args *nfsv4.Open4args, oos *
 	}
 }
 
+// txOpenByName is the common implementation of OPEN with CLAIM_NULL
+// and CLAIM_DELEGATE_CUR, where the file to open is identified by name
+// relative to the current directory.
+func (s *compoundState) txOpenByName(ctx context.Context, file string, claimedDelegation *delegationState, shareAccess virtual.ShareMask, createAttributes *virtual.Attributes, existingOptions *virtual.OpenExistingOptions, oos *openOwnerState, ll *leavesToClose) nfsv4.Open4res {
+	// Only hand out read delegations for files that are opened for
+	// reading. Don't hand out delegations to open-owners that
+	// still need to be confirmed, as the open may not be retained.
+	p := s.program
+	tryDelegation := p.enableReadDelegations &&
+		claimedDelegation == nil &&
+		shareAccess == virtual.ShareMaskRead &&
+		createAttributes == nil &&
+		oos.confirmed
+
+	// This method needs to drop the lock, as VirtualOpenChild may
+	// block. This is safe to do within open-owner transactions.
+	p.leave()
+	isLocked := false
+	defer func() {
+		if !isLocked {
+			p.enter()
+		}
+	}()
+
+	currentDirectory, st := s.currentFileHandle.getDirectory()
+	if st != nfsv4.NFS4_OK {
+		return &nfsv4.Open4res_default{Status: st}
+	}
+
+	name, st := nfsv4NewComponent(file)
+	if st != nfsv4.NFS4_OK {
+		return &nfsv4.Open4res_default{Status: st}
+	}
+
+	// Open the file.
+	var attributes virtual.Attributes
+	leaf, respected, changeInfo, vs := currentDirectory.VirtualOpenChild(
+		ctx,
+		name,
+		shareAccess,
+		createAttributes,
+		existingOptions,
+		virtual.AttributesMaskFileHandle,
+		&attributes)
+	if vs != virtual.StatusOK {
+		return &nfsv4.Open4res_default{Status: toNFSv4Status(vs)}
+	}
+
+	handle := attributes.GetFileHandle()
+	handleKey := string(handle)
+
+	// Acquire an additional share reservation on behalf of the
+	// delegation, so that the file remains readable for as long
+	// as the delegation is held, even if the client closes the
+	// file and it gets unlinked.
+	var delegationShareAccess virtual.ShareMask
+	if tryDelegation && leaf.VirtualOpenSelf(
+		ctx,
+		virtual.ShareMaskRead,
+		&virtual.OpenExistingOptions{},
+		0,
+		&virtual.Attributes{},
+	) == virtual.StatusOK {
+		delegationShareAccess = virtual.ShareMaskRead
+	}
+
+	response := &nfsv4.Open4res_NFS4_OK{
+		Resok4: nfsv4.Open4resok{
+			Cinfo:      toNFSv4ChangeInfo(&changeInfo),
+			Rflags:     nfsv4.OPEN4_RESULT_LOCKTYPE_POSIX,
+			Attrset:    attributesMaskToBitmap4(respected),
+			Delegation: &nfsv4.OpenDelegation4_OPEN_DELEGATE_NONE{},
+		},
+	}
+
+	p.enter()
+	isLocked = true
+
+	if claimedDelegation != nil && (p.delegationsByOther[claimedDelegation.stateID.other] != claimedDelegation || claimedDelegation.openedFile.handleKey != handleKey) {
+		// The delegation got returned while the lock was
+		// dropped, or the name no longer refers to the file
+		// for which the delegation was handed out.
+		ll.leaves = append(ll.leaves, leafToClose{
+			leaf:        leaf,
+			shareAccess: shareAccess,
+		})
+		return &nfsv4.Open4res_default{Status: nfsv4.NFS4ERR_BAD_STATEID}
+	}
+
+	s.currentFileHandle = fileHandle{
+		handle: handle,
+		node:   virtual.DirectoryChild{}.FromLeaf(leaf),
+	}
+
+	oofs, ok := oos.filesByHandle[handleKey]
+	if ok {
+		// This file has already been opened by this open-owner,
+		// meaning we should upgrade the existing opened file.
+		// The newly opened file can be closed again.
+		//
+		// More details: RFC 7530, section 9.11.
+		oofs.upgrade(shareAccess, leaf, ll)
+	} else {
+		openedFile, ok := p.openedFilesByHandle[handleKey]
+		if ok {
+			openedFile.holdersCount.increase()
+		} else {
+			// This file has not been opened by any
+			// open-owner. Keep track of it, so that we
+			// don't need to call into HandleResolver. This
+			// ensures that the file remains accessible
+			// while opened, even when unlinked.
+			openedFile = &openedFileState{
+				handle:       handle,
+				handleKey:    handleKey,
+				leaf:         leaf,
+				holdersCount: 1,
+				delegations:  map[*confirmedClientState]*delegationState{},
+			}
+			openedFile.locks.Initialize()
+			p.openedFilesByHandle[handleKey] = openedFile
+		}
+
+		// This file has not been opened by this open-owner.
+		// Create a new state ID.
+		oofs = &openOwnerFileState{
+			openOwner:      oos,
+			openedFile:     openedFile,
+			stateID:        p.newRegularStateID(1),
+			lockOwnerFiles: map[*lockOwnerState]*lockOwnerFileState{},
+		}
+		if oofs.shareCount.upgrade(&oofs.shareAccess, shareAccess) != 0 {
+			panic("Share access reservations can't overlap for newly created files")
+		}
+		oos.filesByHandle[handleKey] = oofs
+		p.openOwnerFilesByOther[oofs.stateID.other] = oofs
+		baseProgramOpenOwnerFilesCreated.Inc()
+	}
+
+	openedFile := oofs.openedFile
+	if shareAccess&virtual.ShareMaskWrite != 0 {
+		openedFile.revokeDelegations(p, oos.confirmedClient, ll)
+	}
+	if delegationShareAccess != 0 {
+		if oofs.shareAccess == virtual.ShareMaskRead && openedFile.holdersCount == 1 {
+			// The file is only opened for reading by the
+			// current open-owner. Hand out a delegation.
+			ds := p.newDelegation(oos.confirmedClient, openedFile, delegationShareAccess)
+			response.Resok4.Delegation = &nfsv4.OpenDelegation4_OPEN_DELEGATE_READ{
+				Read: nfsv4.OpenReadDelegation4{
+					Stateid: p.externalizeStateID(ds.stateID),
+				},
+			}
+		} else {
+			// The file is also opened by others, or opened
+			// for writing by the current open-owner. Don't
+			// hand out a delegation.
+			ll.leaves = append(ll.leaves, leafToClose{
+				leaf:        leaf,
+				shareAccess: delegationShareAccess,
+			})
+		}
+	}
+
+	response.Resok4.Stateid = p.externalizeStateID(oofs.stateID)
+	if !oos.confirmed {
+		// The first time that this open-owner is used. Request
+		// that the caller issues an OPEN_CONFIRM operation.
+		response.Resok4.Rflags |= nfsv4.OPEN4_RESULT_CONFIRM
+	}
+	return response
+}
+
 func (s *compoundState) opOpenattr(args *nfsv4.Openattr4args) nfsv4.Openattr4res {
-	// This implementation does not support named attributes.
+	// This implementation does not support named attributes. This
+	// means that extended attributes stored in leaves can only be
+	// accessed through FUSE. NFSv4.0 has no dedicated operations
+	// for accessing extended attributes, as those were only added
+	// to NFSv4.2 (RFC 8276). Exposing them as named attribute
+	// directories would require allocating file handles for them.
 	if _, _, st := s.currentFileHandle.getNode(); st != nfsv4.NFS4_OK {
 		return nfsv4.Openattr4res{Status: st}
 	}
@@ -2259,6 +2453,7 @@ func (s *compoundState) opSetclientidConfirm(args *nfsv4.SetclientidConfirm4args
 			confirmation: confirmation,
 			openOwners:   map[string]*openOwnerState{},
 			lockOwners:   map[string]*lockOwnerState{},
+			delegations:  map[*openedFileState]*delegationState{},
 		}
 	}
 
@@ -2466,7 +2661,7 @@ func toNFSv4Status(s virtual.Status) nfsv4.Nfsstat4 {
 		return nfsv4.NFS4ERR_IO
 	case virtual.StatusErrIsDir:
 		return nfsv4.NFS4ERR_ISDIR
-	case virtual.StatusErrNoEnt:
+	case virtual.StatusErrNoEnt, virtual.StatusErrNoXAttr:
 		return nfsv4.NFS4ERR_NOENT
 	case virtual.StatusErrNotDir:
 		return nfsv4.NFS4ERR_NOTDIR
@@ -2582,7 +2777,10 @@ func (ccs *clientConfirmationState) remove(p *baseProgram, ll *leavesToClose) {
 	if confirmedClient != nil && confirmedClient.confirmation == ccs {
 		// This client confirmation record was confirmed,
 		// meaning that removing it should also close all opened
-		// files and release all locks.
+		// files, release all locks and remove all delegations.
+		for _, ds := range confirmedClient.delegations {
+			ds.remove(p, ll)
+		}
 		for _, oos := range confirmedClient.openOwners {
 			oos.remove(p, ll)
 		}
@@ -2610,6 +2808,7 @@ type confirmedClientState struct {
 	confirmation *clientConfirmationState
 	openOwners   map[string]*openOwnerState
 	lockOwners   map[string]*lockOwnerState
+	delegations  map[*openedFileState]*delegationState
 }
 
 // clientConfirmationKey contains the information that a client must
@@ -2915,7 +3114,7 @@ func (oofs *openOwnerFileState) removeFinalize(p *baseProgram) {
 	// Disconnect the openedFileState. Do leave it attached to the
 	// openOwnerFileState, so that in-flight READ and WRITE
 	// operations can still safely call close().
-	if oofs.openedFile.openOwnersCount.decrease() {
+	if oofs.openedFile.holdersCount.decrease() {
 		delete(p.openedFilesByHandle, handleKey)
 	}
 }
@@ -2946,8 +3145,89 @@ type openedFileState struct {
 	leaf      virtual.Leaf
 
 	// Variable fields.
-	openOwnersCount referenceCount
-	locks           virtual.ByteRangeLockSet[*lockOwnerState]
+	holdersCount referenceCount
+	locks        virtual.ByteRangeLockSet[*lockOwnerState]
+	delegations  map[*confirmedClientState]*delegationState
+}
+
+// revokeDelegations removes all delegations of a file that are held
+// by clients other than the one provided. This needs to be performed
+// when the file is opened for writing.
+//
+// This implementation does not support callbacks, meaning that
+// delegations cannot be recalled. Clients will only notice that the
+// delegation has been revoked the next time they use it. This is why
+// delegations should only be enabled if the file system is accessed by
+// a single client.
+func (of *openedFileState) revokeDelegations(p *baseProgram, except *confirmedClientState, ll *leavesToClose) {
+	for confirmedClient, ds := range of.delegations {
+		if confirmedClient != except {
+			ds.remove(p, ll)
+		}
+	}
+}
+
+// delegationState stores information on a read delegation that was
+// handed out to a client as part of OPEN. While held, the client may
+// cache the contents and attributes of the file, and open the file
+// locally.
+type delegationState struct {
+	// Constant fields.
+	confirmedClient *confirmedClientState
+	openedFile      *openedFileState
+	stateID         regularStateID
+
+	// Variable fields.
+	shareAccess virtual.ShareMask
+	shareCount  shareCount
+}
+
+// newDelegation hands out a delegation for an opened file to a client.
+// The caller must have acquired share reservations on the file on
+// behalf of the delegation, which are released when the delegation is
+// removed.
+func (p *baseProgram) newDelegation(confirmedClient *confirmedClientState, openedFile *openedFileState, shareAccess virtual.ShareMask) *delegationState {
+	ds := &delegationState{
+		confirmedClient: confirmedClient,
+		openedFile:      openedFile,
+		stateID:         p.newRegularStateID(1),
+	}
+	ds.shareCount.upgrade(&ds.shareAccess, shareAccess)
+	openedFile.holdersCount.increase()
+	openedFile.delegations[confirmedClient] = ds
+	confirmedClient.delegations[openedFile] = ds
+	p.delegationsByOther[ds.stateID.other] = ds
+	baseProgramDelegationsCreated.Inc()
+	return ds
+}
+
+// downgradeShareAccess downgrades the share reservations of a
+// delegation. If this causes a given share reservation to become
+// unused, it schedules (partial) closure of the underlying
+// virtual.Leaf object.
+func (ds *delegationState) downgradeShareAccess(shareAccess *virtual.ShareMask, newShareAccess virtual.ShareMask, ll *leavesToClose) {
+	if shareAccessToClose := ds.shareCount.downgrade(shareAccess, newShareAccess); shareAccessToClose != 0 {
+		ll.leaves = append(ll.leaves, leafToClose{
+			leaf:        ds.openedFile.leaf,
+			shareAccess: shareAccessToClose,
+		})
+	}
+}
+
+// remove the delegation, either because the client returned it
+// through DELEGRETURN, because it got revoked, or because the client's
+// lease expired. READ operations that are still in progress may
+// continue to use the file until they complete.
+func (ds *delegationState) remove(p *baseProgram, ll *leavesToClose) {
+	ds.downgradeShareAccess(&ds.shareAccess, 0, ll)
+	delete(p.delegationsByOther, ds.stateID.other)
+	delete(ds.confirmedClient.delegations, ds.openedFile)
+	openedFile := ds.openedFile
+	delete(openedFile.delegations, ds.confirmedClient)
+	if openedFile.holdersCount.decrease() {
+		delete(p.openedFilesByHandle, openedFile.handleKey)
+	}
+	baseProgramDelegationsRemoved.Inc()
 }
 
 // lockOwnerState represents byte-range locking state associated with a
@@ -239,7 +239,7 @@ func TestBaseProgramCompound_OP_ACCESS(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x96, 0x63, 0x54, 0xf1, 0xa2, 0x6b, 0x8c, 0x61}
 	stateIDOtherPrefix := [...]byte{0x68, 0x78, 0x20, 0xb7}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NoFileHandle", func(t *testing.T) {
 		// Calling ACCESS without a file handle should fail.
@@ -368,7 +368,7 @@ func TestBaseProgramCompound_OP_CLOSE(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x9f, 0xa8, 0x23, 0x40, 0x68, 0x9f, 0x3e, 0xac}
 	stateIDOtherPrefix := [...]byte{0xf5, 0x47, 0xa8, 0x88}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("AnonymousStateID", func(t *testing.T) {
 		// Calling CLOSE against the anonymous state ID is of
@@ -910,7 +910,7 @@ func TestBaseProgramCompound_OP_COMMIT(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x1a, 0xa6, 0x7e, 0x3b, 0xf7, 0x29, 0xa4, 0x7b}
 	stateIDOtherPrefix := [...]byte{0x24, 0xa7, 0x48, 0xbc}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NoFileHandle", func(t *testing.T) {
 		// Calling COMMIT without a file handle should fail.
@@ -971,10 +971,54 @@ func TestBaseProgramCompound_OP_COMMIT(t *testing.T) {
 		}, res)
 	})
 
+	t.Run("SyncFailure", func(t *testing.T) {
+		// Errors that occur while flushing buffered writes
+		// should be propagated.
+		leaf := mock.NewMockVirtualLeaf(ctrl)
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		handleResolverExpectCall(t, handleResolver, []byte{1, 2, 3}, virtual.DirectoryChild{}.FromLeaf(leaf), virtual.StatusOK)
+		leaf.EXPECT().VirtualSync().Return(virtual.StatusErrIO)
+
+		res, err := program.NfsV4Nfsproc4Compound(ctx, &nfsv4_xdr.Compound4args{
+			Tag: "fsync",
+			Argarray: []nfsv4_xdr.NfsArgop4{
+				&nfsv4_xdr.NfsArgop4_OP_PUTFH{
+					Opputfh: nfsv4_xdr.Putfh4args{
+						Object: nfsv4_xdr.NfsFh4{1, 2, 3},
+					},
+				},
+				&nfsv4_xdr.NfsArgop4_OP_COMMIT{
+					Opcommit: nfsv4_xdr.Commit4args{
+						Offset: 10,
+						Count:  20,
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &nfsv4_xdr.Compound4res{
+			Tag: "fsync",
+			Resarray: []nfsv4_xdr.NfsResop4{
+				&nfsv4_xdr.NfsResop4_OP_PUTFH{
+					Opputfh: nfsv4_xdr.Putfh4res{
+						Status: nfsv4_xdr.NFS4_OK,
+					},
+				},
+				&nfsv4_xdr.NfsResop4_OP_COMMIT{
+					Opcommit: &nfsv4_xdr.Commit4res_default{
+						Status: nfsv4_xdr.NFS4ERR_IO,
+					},
+				},
+			},
+			Status: nfsv4_xdr.NFS4ERR_IO,
+		}, res)
+	})
+
 	t.Run("Success", func(t *testing.T) {
 		leaf := mock.NewMockVirtualLeaf(ctrl)
 		clock.EXPECT().Now().Return(time.Unix(1000, 0))
 		handleResolverExpectCall(t, handleResolver, []byte{1, 2, 3}, virtual.DirectoryChild{}.FromLeaf(leaf), virtual.StatusOK)
+		leaf.EXPECT().VirtualSync().Return(virtual.StatusOK)
 
 		res, err := program.NfsV4Nfsproc4Compound(ctx, &nfsv4_xdr.Compound4args{
 			Tag: "fsync",
@@ -1027,7 +1071,7 @@ func TestBaseProgramCompound_OP_CREATE(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x8d, 0x3d, 0xe8, 0x2e, 0xee, 0x3b, 0xca, 0x60}
 	stateIDOtherPrefix := [...]byte{0x60, 0xf5, 0x56, 0x97}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NoFileHandle", func(t *testing.T) {
 		// Calling CREATE without a file handle should fail.
@@ -1579,7 +1623,7 @@ func TestBaseProgramCompound_OP_DELEGPURGE(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x0b, 0xb3, 0x0d, 0xa3, 0x50, 0x11, 0x6b, 0x38}
 	stateIDOtherPrefix := [...]byte{0x17, 0x18, 0x71, 0xc6}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NotSupported", func(t *testing.T) {
 		// As we don't support CLAIM_DELEGATE_PREV, this method
@@ -1609,7 +1653,346 @@ func TestBaseProgramCompound_OP_DELEGPURGE(t *testing.T) {
 	})
 }
 
-// TODO: DELEGRETURN
+func TestBaseProgramCompound_OP_DELEGRETURN(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
+	rootDirectory.EXPECT().VirtualGetAttributes(gomock.Any(), virtual.AttributesMaskFileHandle, gomock.Any()).
+		Do(func(ctx context.Context, requested virtual.AttributesMask, attributes *virtual.Attributes) {
+			attributes.SetFileHandle([]byte{0x8e, 0x35, 0x1d, 0x6a, 0x2f, 0xb1, 0x07, 0xc4})
+		})
+	handleResolver := mock.NewMockHandleResolver(ctrl)
+	randomNumberGenerator := mock.NewMockSingleThreadedGenerator(ctrl)
+	rebootVerifier := nfsv4_xdr.Verifier4{0x4a, 0x6e, 0x1b, 0x93, 0xd2, 0x7f, 0x50, 0x08}
+	stateIDOtherPrefix := [...]byte{0x2d, 0x9c, 0x41, 0xe5}
+	clock := mock.NewMockClock(ctrl)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, true)
+
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	clock.EXPECT().Now().Return(time.Unix(1001, 0))
+	setClientIDForTesting(ctx, t, randomNumberGenerator, program, 0x7b2a5e9fd1c3068e)
+
+	t.Run("BadStateID", func(t *testing.T) {
+		// Returning a delegation that was never handed out
+		// should fail.
+		clock.EXPECT().Now().Return(time.Unix(1002, 0))
+
+		res, err := program.NfsV4Nfsproc4Compound(ctx, &nfsv4_xdr.Compound4args{
+			Tag: "delegreturn",
+			Argarray: []nfsv4_xdr.NfsArgop4{
+				&nfsv4_xdr.NfsArgop4_OP_PUTROOTFH{},
+				&nfsv4_xdr.NfsArgop4_OP_DELEGRETURN{
+					Opdelegreturn: nfsv4_xdr.Delegreturn4args{
+						DelegStateid: nfsv4_xdr.Stateid4{
+							Seqid: 1,
+							Other: [...]byte{
+								0x2d, 0x9c, 0x41, 0xe5,
+								0x61, 0x0a, 0x9d, 0x3c,
+								0xbe, 0x48, 0x27, 0xf0,
+							},
+						},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &nfsv4_xdr.Compound4res{
+			Tag: "delegreturn",
+			Resarray: []nfsv4_xdr.NfsResop4{
+				&nfsv4_xdr.NfsResop4_OP_PUTROOTFH{
+					Opputrootfh: nfsv4_xdr.Putrootfh4res{
+						Status: nfsv4_xdr.NFS4_OK,
+					},
+				},
+				&nfsv4_xdr.NfsResop4_OP_DELEGRETURN{
+					Opdelegreturn: nfsv4_xdr.Delegreturn4res{
+						Status: nfsv4_xdr.NFS4ERR_BAD_STATEID,
+					},
+				},
+			},
+			Status: nfsv4_xdr.NFS4ERR_BAD_STATEID,
+		}, res)
+	})
+
+	// Open a file for reading and confirm the open-owner. As
+	// OPEN_CONFIRM still needs to be called, no delegation is
+	// handed out yet.
+	leaf := mock.NewMockVirtualLeaf(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1003, 0))
+	clock.EXPECT().Now().Return(time.Unix(1004, 0))
+	openUnconfirmedFileForTesting(
+		ctx,
+		t,
+		randomNumberGenerator,
+		program,
+		rootDirectory,
+		leaf,
+		nfsv4_xdr.NfsFh4{0x5d, 0x0e, 0x81, 0x2c, 0xf4, 0x67, 0x39, 0xab},
+		/* shortClientID = */ 0x7b2a5e9fd1c3068e,
+		/* seqID = */ 4081,
+		/* stateIDOther = */ [...]byte{
+			0x2d, 0x9c, 0x41, 0xe5,
+			0x0c, 0x73, 0xd4, 0x58,
+			0x96, 0xe1, 0x2b, 0x0f,
+		})
+	clock.EXPECT().Now().Return(time.Unix(1005, 0))
+	clock.EXPECT().Now().Return(time.Unix(1006, 0))
+	openConfirmForTesting(
+		ctx,
+		t,
+		randomNumberGenerator,
+		program,
+		nfsv4_xdr.NfsFh4{0x5d, 0x0e, 0x81, 0x2c, 0xf4, 0x67, 0x39, 0xab},
+		/* seqID = */ 4082,
+		/* stateIDOther = */ [...]byte{
+			0x2d, 0x9c, 0x41, 0xe5,
+			0x0c, 0x73, 0xd4, 0x58,
+			0x96, 0xe1, 0x2b, 0x0f,
+		})
+
+	// Opening the file once more through the confirmed open-owner
+	// should cause a read delegation to be handed out.
+	clock.EXPECT().Now().Return(time.Unix(1007, 0))
+	rootDirectory.EXPECT().VirtualOpenChild(
+		ctx,
+		path.MustNewComponent("Hello"),
+		virtual.ShareMaskRead,
+		nil,
+		&virtual.OpenExistingOptions{},
+		virtual.AttributesMaskFileHandle,
+		gomock.Any(),
+	).DoAndReturn(func(ctx context.Context, name path.Component, shareAccess virtual.ShareMask, createAttributes *virtual.Attributes, existingOptions *virtual.OpenExistingOptions, requested virtual.AttributesMask, openedFileAttributes *virtual.Attributes) (virtual.Leaf, virtual.AttributesMask, virtual.ChangeInfo, virtual.Status) {
+		openedFileAttributes.SetFileHandle([]byte{0x5d, 0x0e, 0x81, 0x2c, 0xf4, 0x67, 0x39, 0xab})
+		return leaf, 0, virtual.ChangeInfo{
+			Before: 0x8b5c2e1f6a3d9047,
+			After:  0x8b5c2e1f6a3d9047,
+		}, virtual.StatusOK
+	})
+	leaf.EXPECT().VirtualOpenSelf(ctx, virtual.ShareMaskRead, &virtual.OpenExistingOptions{}, virtual.AttributesMask(0), gomock.Any())
+	clock.EXPECT().Now().Return(time.Unix(1008, 0))
+	leaf.EXPECT().VirtualClose(virtual.ShareMaskRead)
+	randomNumberGeneratorExpectRead(randomNumberGenerator, []byte{0xa7, 0x13, 0x5f, 0xc2, 0x38, 0x9e, 0x64, 0xd0})
+
+	res, err := program.NfsV4Nfsproc4Compound(ctx, &nfsv4_xdr.Compound4args{
+		Tag: "open",
+		Argarray: []nfsv4_xdr.NfsArgop4{
+			&nfsv4_xdr.NfsArgop4_OP_PUTROOTFH{},
+			&nfsv4_xdr.NfsArgop4_OP_OPEN{
+				Opopen: nfsv4_xdr.Open4args{
+					Seqid:       4083,
+					ShareAccess: nfsv4_xdr.OPEN4_SHARE_ACCESS_READ,
+					ShareDeny:   nfsv4_xdr.OPEN4_SHARE_DENY_NONE,
+					Owner: nfsv4_xdr.OpenOwner4{
+						Clientid: 0x7b2a5e9fd1c3068e,
+						Owner:    []byte{0xc4, 0x85, 0x50, 0x6b, 0xa5, 0xec, 0x8e, 0x2c},
+					},
+					Openhow: &nfsv4_xdr.Openflag4_default{},
+					Claim: &nfsv4_xdr.OpenClaim4_CLAIM_NULL{
+						File: "Hello",
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &nfsv4_xdr.Compound4res{
+		Tag: "open",
+		Resarray: []nfsv4_xdr.NfsResop4{
+			&nfsv4_xdr.NfsResop4_OP_PUTROOTFH{
+				Opputrootfh: nfsv4_xdr.Putrootfh4res{
+					Status: nfsv4_xdr.NFS4_OK,
+				},
+			},
+			&nfsv4_xdr.NfsResop4_OP_OPEN{
+				Opopen: &nfsv4_xdr.Open4res_NFS4_OK{
+					Resok4: nfsv4_xdr.Open4resok{
+						Stateid: nfsv4_xdr.Stateid4{
+							Seqid: 3,
+							Other: [...]byte{
+								0x2d, 0x9c, 0x41, 0xe5,
+								0x0c, 0x73, 0xd4, 0x58,
+								0x96, 0xe1, 0x2b, 0x0f,
+							},
+						},
+						Cinfo: nfsv4_xdr.ChangeInfo4{
+							Atomic: true,
+							Before: 0x8b5c2e1f6a3d9047,
+							After:  0x8b5c2e1f6a3d9047,
+						},
+						Rflags:  nfsv4_xdr.OPEN4_RESULT_LOCKTYPE_POSIX,
+						Attrset: nfsv4_xdr.Bitmap4{},
+						Delegation: &nfsv4_xdr.OpenDelegation4_OPEN_DELEGATE_READ{
+							Read: nfsv4_xdr.OpenReadDelegation4{
+								Stateid: nfsv4_xdr.Stateid4{
+									Seqid: 1,
+									Other: [...]byte{
+										0x2d, 0x9c, 0x41, 0xe5,
+										0xa7, 0x13, 0x5f, 0xc2,
+										0x38, 0x9e, 0x64, 0xd0,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Status: nfsv4_xdr.NFS4_OK,
+	}, res)
+
+	t.Run("ReadSuccess", func(t *testing.T) {
+		// The delegation state ID may be used to read the file.
+		clock.EXPECT().Now().Return(time.Unix(1009, 0))
+		clock.EXPECT().Now().Return(time.Unix(1010, 0))
+		clock.EXPECT().Now().Return(time.Unix(1011, 0))
+		leaf.EXPECT().VirtualRead(gomock.Len(100), uint64(0)).
+			DoAndReturn(func(buf []byte, offset uint64) (int, bool, virtual.Status) {
+				return copy(buf, "Hello"), true, virtual.StatusOK
+			})
+
+		res, err := program.NfsV4Nfsproc4Compound(ctx, &nfsv4_xdr.Compound4args{
+			Tag: "read",
+			Argarray: []nfsv4_xdr.NfsArgop4{
+				&nfsv4_xdr.NfsArgop4_OP_PUTFH{
+					Opputfh: nfsv4_xdr.Putfh4args{
+						Object: nfsv4_xdr.NfsFh4{0x5d, 0x0e, 0x81, 0x2c, 0xf4, 0x67, 0x39, 0xab},
+					},
+				},
+				&nfsv4_xdr.NfsArgop4_OP_READ{
+					Opread: nfsv4_xdr.Read4args{
+						Stateid: nfsv4_xdr.Stateid4{
+							Seqid: 1,
+							Other: [...]byte{
+								0x2d, 0x9c, 0x41, 0xe5,
+								0xa7, 0x13, 0x5f, 0xc2,
+								0x38, 0x9e, 0x64, 0xd0,
+							},
+						},
+						Offset: 0,
+						Count:  100,
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &nfsv4_xdr.Compound4res{
+			Tag: "read",
+			Resarray: []nfsv4_xdr.NfsResop4{
+				&nfsv4_xdr.NfsResop4_OP_PUTFH{
+					Opputfh: nfsv4_xdr.Putfh4res{
+						Status: nfsv4_xdr.NFS4_OK,
+					},
+				},
+				&nfsv4_xdr.NfsResop4_OP_READ{
+					Opread: &nfsv4_xdr.Read4res_NFS4_OK{
+						Resok4: nfsv4_xdr.Read4resok{
+							Eof:  true,
+							Data: []byte("Hello"),
+						},
+					},
+				},
+			},
+			Status: nfsv4_xdr.NFS4_OK,
+		}, res)
+	})
+
+	t.Run("WriteWithReadDelegation", func(t *testing.T) {
+		// Read delegations cannot be used to write to a file.
+		clock.EXPECT().Now().Return(time.Unix(1012, 0))
+		clock.EXPECT().Now().Return(time.Unix(1013, 0))
+
+		res, err := program.NfsV4Nfsproc4Compound(ctx, &nfsv4_xdr.Compound4args{
+			Tag: "write",
+			Argarray: []nfsv4_xdr.NfsArgop4{
+				&nfsv4_xdr.NfsArgop4_OP_PUTFH{
+					Opputfh: nfsv4_xdr.Putfh4args{
+						Object: nfsv4_xdr.NfsFh4{0x5d, 0x0e, 0x81, 0x2c, 0xf4, 0x67, 0x39, 0xab},
+					},
+				},
+				&nfsv4_xdr.NfsArgop4_OP_WRITE{
+					Opwrite: nfsv4_xdr.Write4args{
+						Stateid: nfsv4_xdr.Stateid4{
+							Seqid: 1,
+							Other: [...]byte{
+								0x2d, 0x9c, 0x41, 0xe5,
+								0xa7, 0x13, 0x5f, 0xc2,
+								0x38, 0x9e, 0x64, 0xd0,
+							},
+						},
+						Offset: 0,
+						Stable: nfsv4_xdr.UNSTABLE4,
+						Data:   []byte("Hello"),
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &nfsv4_xdr.Compound4res{
+			Tag: "write",
+			Resarray: []nfsv4_xdr.NfsResop4{
+				&nfsv4_xdr.NfsResop4_OP_PUTFH{
+					Opputfh: nfsv4_xdr.Putfh4res{
+						Status: nfsv4_xdr.NFS4_OK,
+					},
+				},
+				&nfsv4_xdr.NfsResop4_OP_WRITE{
+					Opwrite: &nfsv4_xdr.Write4res_default{
+						Status: nfsv4_xdr.NFS4ERR_OPENMODE,
+					},
+				},
+			},
+			Status: nfsv4_xdr.NFS4ERR_OPENMODE,
+		}, res)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Returning the delegation should cause the share
+		// reservation held by the delegation to be released.
+		clock.EXPECT().Now().Return(time.Unix(1014, 0))
+		clock.EXPECT().Now().Return(time.Unix(1015, 0))
+		leaf.EXPECT().VirtualClose(virtual.ShareMaskRead)
+
+		res, err := program.NfsV4Nfsproc4Compound(ctx, &nfsv4_xdr.Compound4args{
+			Tag: "delegreturn",
+			Argarray: []nfsv4_xdr.NfsArgop4{
+				&nfsv4_xdr.NfsArgop4_OP_PUTFH{
+					Opputfh: nfsv4_xdr.Putfh4args{
+						Object: nfsv4_xdr.NfsFh4{0x5d, 0x0e, 0x81, 0x2c, 0xf4, 0x67, 0x39, 0xab},
+					},
+				},
+				&nfsv4_xdr.NfsArgop4_OP_DELEGRETURN{
+					Opdelegreturn: nfsv4_xdr.Delegreturn4args{
+						DelegStateid: nfsv4_xdr.Stateid4{
+							Seqid: 1,
+							Other: [...]byte{
+								0x2d, 0x9c, 0x41, 0xe5,
+								0xa7, 0x13, 0x5f, 0xc2,
+								0x38, 0x9e, 0x64, 0xd0,
+							},
+						},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &nfsv4_xdr.Compound4res{
+			Tag: "delegreturn",
+			Resarray: []nfsv4_xdr.NfsResop4{
+				&nfsv4_xdr.NfsResop4_OP_PUTFH{
+					Opputfh: nfsv4_xdr.Putfh4res{
+						Status: nfsv4_xdr.NFS4_OK,
+					},
+				},
+				&nfsv4_xdr.NfsResop4_OP_DELEGRETURN{
+					Opdelegreturn: nfsv4_xdr.Delegreturn4res{
+						Status: nfsv4_xdr.NFS4_OK,
+					},
+				},
+			},
+			Status: nfsv4_xdr.NFS4_OK,
+		}, res)
+	})
+}
 
 func TestBaseProgramCompound_OP_GETATTR(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
@@ -1624,7 +2007,7 @@ func TestBaseProgramCompound_OP_GETATTR(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x5e, 0x5f, 0xfe, 0x34, 0x05, 0x98, 0x9d, 0xf1}
 	stateIDOtherPrefix := [...]byte{0x3d, 0xc0, 0x5d, 0xd2}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NoFileHandle", func(t *testing.T) {
 		// Calling GETATTR without a file handle should fail.
@@ -1837,7 +2220,7 @@ func TestBaseProgramCompound_OP_GETFH(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x3c, 0x79, 0xba, 0xfe, 0xd6, 0x87, 0x1e, 0x32}
 	stateIDOtherPrefix := [...]byte{0x95, 0xce, 0xb4, 0x96}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NoFileHandle", func(t *testing.T) {
 		// Calling GETFH without a file handle should fail.
@@ -1906,7 +2289,7 @@ func TestBaseProgramCompound_OP_ILLEGAL(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x42, 0x51, 0x65, 0x8b, 0xd2, 0x27, 0xc4, 0x13}
 	stateIDOtherPrefix := [...]byte{0x01, 0x22, 0xe2, 0xaa}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("Failure", func(t *testing.T) {
 		res, err := program.NfsV4Nfsproc4Compound(ctx, &nfsv4_xdr.Compound4args{
@@ -1943,7 +2326,7 @@ func TestBaseProgramCompound_OP_LINK(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x8d, 0x94, 0x96, 0x9c, 0xe9, 0x4b, 0xcf, 0xf5}
 	stateIDOtherPrefix := [...]byte{0xdf, 0xdb, 0x0d, 0x38}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NoFileHandle1", func(t *testing.T) {
 		// Calling LINK without any file handles should fail.
@@ -2307,7 +2690,7 @@ func TestBaseProgramCompound_OP_LOOKUP(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0xf5, 0x66, 0xea, 0xae, 0x76, 0x70, 0xd1, 0x5b}
 	stateIDOtherPrefix := [...]byte{0x2d, 0x48, 0xd3, 0x9b}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NoFileHandle", func(t *testing.T) {
 		// Calling LOOKUP without a file handle should fail.
@@ -2590,7 +2973,7 @@ func TestBaseProgramCompound_OP_NVERIFY(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0xab, 0x23, 0xe8, 0x04, 0x79, 0x23, 0x0a, 0x27}
 	stateIDOtherPrefix := [...]byte{0x41, 0x40, 0x91, 0x69}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	// Only basic testing coverage for NVERIFY is provided, as it is
 	// assumed most of the logic is shared with VERIFY.
@@ -2701,7 +3084,7 @@ func TestBaseProgramCompound_OP_OPEN(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x18, 0xe4, 0x47, 0xf1, 0x31, 0x1c, 0xe2, 0x94}
 	stateIDOtherPrefix := [...]byte{0x5c, 0x71, 0xa6, 0x0d}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	clock.EXPECT().Now().Return(time.Unix(1000, 0))
 	clock.EXPECT().Now().Return(time.Unix(1001, 0))
@@ -3129,7 +3512,7 @@ func TestBaseProgramCompound_OP_OPENATTR(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0xe6, 0x7e, 0xb7, 0xdb, 0x52, 0x9c, 0x7c, 0x86}
 	stateIDOtherPrefix := [...]byte{0x06, 0x00, 0x7c, 0x9d}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NoFileHandle", func(t *testing.T) {
 		// Calling OPENATTR without a file handle should fail.
@@ -3203,7 +3586,7 @@ func TestBaseProgramCompound_OP_OPEN_CONFIRM(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x42, 0xa8, 0x3f, 0xd1, 0xde, 0x65, 0x74, 0x2a}
 	stateIDOtherPrefix := [...]byte{0xfa, 0xc3, 0xf7, 0x18}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	clock.EXPECT().Now().Return(time.Unix(1000, 0))
 	clock.EXPECT().Now().Return(time.Unix(1001, 0))
@@ -3319,7 +3702,7 @@ func TestBaseProgramCompound_OP_OPEN_DOWNGRADE(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x4d, 0x0d, 0xc1, 0xca, 0xd9, 0xeb, 0x73, 0xc9}
 	stateIDOtherPrefix := [...]byte{0x2c, 0xa4, 0xce, 0xdc}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("AnonymousStateID", func(t *testing.T) {
 		// Calling OPEN_DOWNGRADE against the anonymous state ID
@@ -4035,7 +4418,7 @@ func TestBaseProgramCompound_OP_READ(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x58, 0x61, 0xb4, 0xff, 0x82, 0x40, 0x8f, 0x1a}
 	stateIDOtherPrefix := [...]byte{0x55, 0xc7, 0xc6, 0xa0}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("StaleStateID", func(t *testing.T) {
 		// Providing a state ID that uses an unknown prefix
@@ -4894,7 +5277,7 @@ func TestBaseProgramCompound_OP_READDIR(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x80, 0x29, 0x6e, 0xe3, 0x1a, 0xf1, 0xec, 0x41}
 	stateIDOtherPrefix := [...]byte{0xce, 0x11, 0x76, 0xe8}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NoFileHandle", func(t *testing.T) {
 		// Calling READDIR without a file handle should fail.
@@ -5218,7 +5601,7 @@ func TestBaseProgramCompound_OP_READLINK(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0xa8, 0x90, 0x8c, 0x43, 0xb7, 0xd6, 0x0f, 0x74}
 	stateIDOtherPrefix := [...]byte{0x46, 0x64, 0x44, 0x31}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NoFileHandle", func(t *testing.T) {
 		// Calling READLINK without a file handle should fail.
@@ -5360,7 +5743,7 @@ func TestBaseProgramCompound_OP_RELEASE_LOCKOWNER(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x27, 0xe1, 0xcd, 0x6a, 0x3f, 0xf8, 0xb7, 0xb2}
 	stateIDOtherPrefix := [...]byte{0xab, 0x4f, 0xf6, 0x1c}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("StaleClientID", func(t *testing.T) {
 		// Calling RELEASE_LOCKOWNER against a non-existent
@@ -5723,7 +6106,7 @@ func TestBaseProgramCompound_OP_REMOVE(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0xe7, 0x77, 0x33, 0xf4, 0x21, 0xad, 0x7a, 0x1b}
 	stateIDOtherPrefix := [...]byte{0x4b, 0x46, 0x62, 0x3c}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NoFileHandle", func(t *testing.T) {
 		// Calling REMOVE without a file handle should fail.
@@ -5948,7 +6331,7 @@ func TestBaseProgramCompound_OP_RESTOREFH(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x5f, 0x98, 0x5c, 0xdf, 0x8a, 0xac, 0x4d, 0x97}
 	stateIDOtherPrefix := [...]byte{0xd4, 0x7c, 0xd1, 0x8f}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NoSavedFileHandle", func(t *testing.T) {
 		// Calling RESTOREFH without a saved file handle should fail.
@@ -6052,7 +6435,7 @@ func TestBaseProgramCompound_OP_SAVEFH(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0xe9, 0xf5, 0x40, 0xa0, 0x20, 0xd9, 0x2c, 0x52}
 	stateIDOtherPrefix := [...]byte{0xf1, 0xd0, 0x0e, 0xa0}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NoFileHandle", func(t *testing.T) {
 		// Calling SAVEFH without a file handle should fail.
@@ -6092,7 +6475,7 @@ func TestBaseProgramCompound_OP_SECINFO(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x70, 0x34, 0xc6, 0x7a, 0x25, 0x6e, 0x08, 0xc0}
 	stateIDOtherPrefix := [...]byte{0xf9, 0x44, 0xa6, 0x25}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NoFileHandle", func(t *testing.T) {
 		// Calling SECINFO without a file handle should fail.
@@ -6318,7 +6701,7 @@ func TestBaseProgramCompound_OP_SETCLIENTID_CONFIRM(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x73, 0xaf, 0xeb, 0xd6, 0x5b, 0x96, 0x74, 0xde}
 	stateIDOtherPrefix := [...]byte{0xdb, 0xd3, 0xb5, 0x41}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NoKnownClientID", func(t *testing.T) {
 		// Calling SETCLIENTID_CONFIRM without calling
@@ -6633,7 +7016,7 @@ func TestBaseProgramCompound_OP_VERIFY(t *testing.T) {
 	rebootVerifier := nfsv4_xdr.Verifier4{0x71, 0x69, 0x6c, 0x7c, 0x90, 0x79, 0x3b, 0x13}
 	stateIDOtherPrefix := [...]byte{0x19, 0xed, 0x93, 0x5f}
 	clock := mock.NewMockClock(ctrl)
-	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute)
+	program := nfsv4.NewBaseProgram(rootDirectory, handleResolver.Call, randomNumberGenerator, rebootVerifier, stateIDOtherPrefix, clock, 2*time.Minute, time.Minute, false)
 
 	t.Run("NoFileHandle", func(t *testing.T) {
 		// Calling VERIFY without a file handle should fail.
@@ -0,0 +1,42 @@
+package nfsv4
+
+import (
+	"time"
+)
+
+// GSSAcceptor is the acceptor side of a GSS-API mechanism, as
+// described in RFC 2743. It is used by the RPCSEC_GSS authenticator to
+// establish security contexts with clients.
+//
+// Only mechanisms that establish a security context in a single round
+// trip are supported, as is the case for Kerberos V5.
+type GSSAcceptor interface {
+	// AcceptSecContext processes the initial context token sent by
+	// a client. Upon success, it returns the established security
+	// context and an output token that needs to be returned to the
+	// client. The output token may be empty.
+	AcceptSecContext(inputToken []byte) (GSSContext, []byte, error)
+}
+
+// GSSContext is a security context that has been established by
+// GSSAcceptor. It can be used to verify and protect messages exchanged
+// with the client.
+type GSSContext interface {
+	// GetSourceName returns the name of the client principal.
+	GetSourceName() string
+	// GetExpirationTime returns the time at which the security
+	// context expires.
+	GetExpirationTime() time.Time
+
+	// GetMIC computes a message integrity code over a message sent
+	// to the client.
+	GetMIC(message []byte) ([]byte, error)
+	// VerifyMIC verifies a message integrity code computed by the
+	// client.
+	VerifyMIC(message, token []byte) error
+	// Wrap encrypts a message sent to the client.
+	Wrap(message []byte) ([]byte, error)
+	// Unwrap decrypts a message sent by the client, and verifies
+	// its integrity.
+	Unwrap(token []byte) ([]byte, error)
+}
@@ -0,0 +1,268 @@
+package nfsv4
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/random"
+	"github.com/jcmturner/gofork/encoding/asn1"
+	"github.com/jcmturner/gokrb5/v8/asn1tools"
+	"github.com/jcmturner/gokrb5/v8/crypto"
+	"github.com/jcmturner/gokrb5/v8/crypto/etype"
+	"github.com/jcmturner/gokrb5/v8/gssapi"
+	"github.com/jcmturner/gokrb5/v8/iana"
+	"github.com/jcmturner/gokrb5/v8/iana/asnAppTag"
+	"github.com/jcmturner/gokrb5/v8/iana/chksumtype"
+	"github.com/jcmturner/gokrb5/v8/iana/etypeID"
+	"github.com/jcmturner/gokrb5/v8/iana/keyusage"
+	"github.com/jcmturner/gokrb5/v8/iana/msgtype"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/messages"
+	"github.com/jcmturner/gokrb5/v8/service"
+	"github.com/jcmturner/gokrb5/v8/types"
+)
+
+// Token identifiers of the Kerberos V5 GSS-API mechanism, as described
+// in RFC 4121, section 4.1 and 4.2.6.
+var (
+	kerberosTokenIDAPReq = [...]byte{0x01, 0x00}
+	kerberosTokenIDAPRep = [...]byte{0x02, 0x00}
+	kerberosTokenIDWrap  = [...]byte{0x05, 0x04}
+)
+
+const kerberosTokenHeaderSizeBytes = 16
+
+type kerberosGSSAcceptor struct {
+	settings              *service.Settings
+	randomNumberGenerator random.ThreadSafeGenerator
+}
+
+// NewKerberosGSSAcceptor creates a GSSAcceptor for the Kerberos V5
+// GSS-API mechanism, as described in RFC 4121. Clients are
+// authenticated by validating the service ticket they provide against
+// the keys contained in a keytab.
+//
+// Only encryption types that use the per-message tokens described in
+// RFC 4121 are supported (i.e., AES). Older encryption types that use
+// the tokens described in RFC 1964 (i.e., DES, 3DES and RC4) are
+// rejected.
+func NewKerberosGSSAcceptor(keytab *keytab.Keytab, randomNumberGenerator random.ThreadSafeGenerator) GSSAcceptor {
+	return &kerberosGSSAcceptor{
+		settings:              service.NewSettings(keytab, service.DecodePAC(false)),
+		randomNumberGenerator: randomNumberGenerator,
+	}
+}
+
+func (a *kerberosGSSAcceptor) AcceptSecContext(inputToken []byte) (GSSContext, []byte, error) {
+	// Extract the AP-REQ from the initial context token.
+	var oid asn1.ObjectIdentifier
+	innerToken, err := asn1.UnmarshalWithParams(inputToken, &oid, "application,explicit,tag:0")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid initial context token: %w", err)
+	}
+	if !oid.Equal(gssapi.OIDKRB5.OID()) {
+		return nil, nil, fmt.Errorf("initial context token uses mechanism %s, while %s was expected", oid, gssapi.OIDKRB5.OID())
+	}
+	if len(innerToken) < 2 || !bytes.Equal(innerToken[:2], kerberosTokenIDAPReq[:]) {
+		return nil, nil, errors.New("initial context token does not contain an AP-REQ")
+	}
+	var apReq messages.APReq
+	if err := apReq.Unmarshal(innerToken[2:]); err != nil {
+		return nil, nil, fmt.Errorf("invalid AP-REQ: %w", err)
+	}
+
+	// Validate the ticket and the authenticator.
+	if ok, _, err := service.VerifyAPREQ(&apReq, a.settings); err != nil {
+		return nil, nil, fmt.Errorf("invalid AP-REQ: %w", err)
+	} else if !ok {
+		return nil, nil, errors.New("invalid AP-REQ")
+	}
+	authenticator := &apReq.Authenticator
+	if authenticator.Cksum.CksumType != chksumtype.GSSAPI || len(authenticator.Cksum.Checksum) < 24 {
+		return nil, nil, errors.New("authenticator does not contain a GSS-API checksum")
+	}
+	flags := binary.LittleEndian.Uint32(authenticator.Cksum.Checksum[20:])
+
+	// Per-message tokens are protected using the initiator's subkey
+	// if provided, or the session key otherwise.
+	sessionKey := apReq.Ticket.DecryptedEncPart.Key
+	key := sessionKey
+	if len(authenticator.SubKey.KeyValue) > 0 {
+		key = authenticator.SubKey
+	}
+	switch key.KeyType {
+	case etypeID.AES128_CTS_HMAC_SHA1_96, etypeID.AES256_CTS_HMAC_SHA1_96, etypeID.AES128_CTS_HMAC_SHA256_128, etypeID.AES256_CTS_HMAC_SHA384_192:
+	default:
+		return nil, nil, fmt.Errorf("encryption type %d is not supported", key.KeyType)
+	}
+	encryptionType, err := crypto.GetEtype(key.KeyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := &kerberosGSSContext{
+		sourceName:     fmt.Sprintf("%s@%s", apReq.Ticket.DecryptedEncPart.CName.PrincipalNameString(), apReq.Ticket.DecryptedEncPart.CRealm),
+		expirationTime: apReq.Ticket.DecryptedEncPart.EndTime,
+		key:            key,
+		encryptionType: encryptionType,
+	}
+	if flags&gssapi.ContextFlagMutual == 0 {
+		// Without mutual authentication, the acceptor uses the
+		// same initial sequence number as the initiator.
+		c.sendSequenceNumber = uint64(uint32(authenticator.SeqNumber))
+		return c, nil, nil
+	}
+
+	// Mutual authentication is requested. Return an AP-REP
+	// containing our initial sequence number.
+	c.sendSequenceNumber = a.randomNumberGenerator.Uint64() & 0x3fffffff
+	encAPRepPart, err := asn1.Marshal(messages.EncAPRepPart{
+		CTime:          authenticator.CTime,
+		Cusec:          authenticator.Cusec,
+		SequenceNumber: int64(c.sendSequenceNumber),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	encryptedEncAPRepPart, err := crypto.GetEncryptedData(
+		asn1tools.AddASNAppTag(encAPRepPart, asnAppTag.EncAPRepPart),
+		sessionKey,
+		keyusage.AP_REP_ENCPART,
+		0)
+	if err != nil {
+		return nil, nil, err
+	}
+	apRep, err := asn1.Marshal(messages.APRep{
+		PVNO:    iana.PVNO,
+		MsgType: msgtype.KRB_AP_REP,
+		EncPart: encryptedEncAPRepPart,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	outputToken, err := asn1.Marshal(gssapi.OIDKRB5.OID())
+	if err != nil {
+		return nil, nil, err
+	}
+	outputToken = append(outputToken, kerberosTokenIDAPRep[:]...)
+	outputToken = append(outputToken, asn1tools.AddASNAppTag(apRep, asnAppTag.APREP)...)
+	return c, asn1tools.AddASNAppTag(outputToken, 0), nil
+}
+
+// kerberosGSSContext is a security context of the Kerberos V5 GSS-API
+// mechanism. It generates and processes the per-message tokens
+// described in RFC 4121, section 4.2.
+type kerberosGSSContext struct {
+	sourceName     string
+	expirationTime time.Time
+	key            types.EncryptionKey
+	encryptionType etype.EType
+
+	sendSequenceNumber uint64
+}
+
+func (c *kerberosGSSContext) GetSourceName() string {
+	return c.sourceName
+}
+
+func (c *kerberosGSSContext) GetExpirationTime() time.Time {
+	return c.expirationTime
+}
+
+func (c *kerberosGSSContext) getNextSequenceNumber() uint64 {
+	return atomic.AddUint64(&c.sendSequenceNumber, 1) - 1
+}
+
+func (c *kerberosGSSContext) GetMIC(message []byte) ([]byte, error) {
+	token := gssapi.MICToken{
+		Flags:     gssapi.MICTokenFlagSentByAcceptor,
+		SndSeqNum: c.getNextSequenceNumber(),
+		Payload:   message,
+	}
+	if err := token.SetChecksum(c.key, keyusage.GSSAPI_ACCEPTOR_SIGN); err != nil {
+		return nil, err
+	}
+	return token.Marshal()
+}
+
+func (c *kerberosGSSContext) VerifyMIC(message, token []byte) error {
+	var micToken gssapi.MICToken
+	if err := micToken.Unmarshal(token, false); err != nil {
+		return err
+	}
+	if micToken.Flags&gssapi.MICTokenFlagAcceptorSubkey != 0 {
+		return errors.New("token is protected using an acceptor subkey, even though none has been asserted")
+	}
+	micToken.Payload = message
+	if ok, err := micToken.Verify(c.key, keyusage.GSSAPI_INITIATOR_SIGN); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("checksum mismatch")
+	}
+	return nil
+}
+
+// Wrap a message, providing confidentiality. The resulting token
+// consists of a header, followed by the encryption of the message and
+// a copy of the header. No filler or rotation is applied.
+func (c *kerberosGSSContext) Wrap(message []byte) ([]byte, error) {
+	var header [kerberosTokenHeaderSizeBytes]byte
+	copy(header[:], kerberosTokenIDWrap[:])
+	header[2] = gssapi.MICTokenFlagSentByAcceptor | gssapi.MICTokenFlagSealed
+	header[3] = 0xff
+	binary.BigEndian.PutUint64(header[8:], c.getNextSequenceNumber())
+
+	plaintext := make([]byte, 0, len(message)+len(header))
+	plaintext = append(plaintext, message...)
+	plaintext = append(plaintext, header[:]...)
+	_, ciphertext, err := c.encryptionType.EncryptMessage(c.key.KeyValue, plaintext, keyusage.GSSAPI_ACCEPTOR_SEAL)
+	if err != nil {
+		return nil, err
+	}
+	return append(header[:], ciphertext...), nil
+}
+
+func (c *kerberosGSSContext) Unwrap(token []byte) ([]byte, error) {
+	if len(token) < kerberosTokenHeaderSizeBytes || !bytes.Equal(token[:2], kerberosTokenIDWrap[:]) || token[3] != 0xff {
+		return nil, errors.New("invalid wrap token header")
+	}
+	header := token[:kerberosTokenHeaderSizeBytes]
+	if flags := header[2]; flags&gssapi.MICTokenFlagSentByAcceptor != 0 {
+		return nil, errors.New("token was sent by the acceptor")
+	} else if flags&gssapi.MICTokenFlagSealed == 0 {
+		return nil, errors.New("token does not provide confidentiality")
+	} else if flags&gssapi.MICTokenFlagAcceptorSubkey != 0 {
+		return nil, errors.New("token is protected using an acceptor subkey, even though none has been asserted")
+	}
+	extraCount := int(binary.BigEndian.Uint16(header[4:]))
+	rightRotationCount := int(binary.BigEndian.Uint16(header[6:]))
+
+	// Undo the rotation that was applied by the sender.
+	ciphertext := token[kerberosTokenHeaderSizeBytes:]
+	if len(ciphertext) < c.encryptionType.GetConfounderByteSize()+c.encryptionType.GetHMACBitLength()/8 {
+		return nil, errors.New("wrap token too short")
+	}
+	rightRotationCount %= len(ciphertext)
+	ciphertext = append(append([]byte(nil), ciphertext[rightRotationCount:]...), ciphertext[:rightRotationCount]...)
+
+	plaintext, err := c.encryptionType.DecryptMessage(c.key.KeyValue, ciphertext, keyusage.GSSAPI_INITIATOR_SEAL)
+	if err != nil {
+		return nil, err
+	}
+
+	// The plaintext contains the message, filler, and a copy of the
+	// header with the rotation count set to zero.
+	messageSizeBytes := len(plaintext) - extraCount - kerberosTokenHeaderSizeBytes
+	if messageSizeBytes < 0 {
+		return nil, errors.New("wrap token too short")
+	}
+	encryptedHeader := plaintext[messageSizeBytes+extraCount:]
+	if !bytes.Equal(encryptedHeader[:6], header[:6]) || !bytes.Equal(encryptedHeader[8:], header[8:]) {
+		return nil, errors.New("encrypted header does not match")
+	}
+	return plaintext[:messageSizeBytes], nil
+}
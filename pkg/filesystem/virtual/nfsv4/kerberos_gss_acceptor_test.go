@@ -0,0 +1,262 @@
+package nfsv4_test
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual/nfsv4"
+	"github.com/golang/mock/gomock"
+	"github.com/jcmturner/gofork/encoding/asn1"
+	"github.com/jcmturner/gokrb5/v8/asn1tools"
+	"github.com/jcmturner/gokrb5/v8/crypto"
+	"github.com/jcmturner/gokrb5/v8/gssapi"
+	"github.com/jcmturner/gokrb5/v8/iana/chksumtype"
+	"github.com/jcmturner/gokrb5/v8/iana/etypeID"
+	"github.com/jcmturner/gokrb5/v8/iana/keyusage"
+	"github.com/jcmturner/gokrb5/v8/iana/nametype"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/messages"
+	"github.com/jcmturner/gokrb5/v8/types"
+	"github.com/stretchr/testify/require"
+)
+
+// newKerberosInitialContextToken creates the initial context token
+// that a client would send to establish a security context, using a
+// service ticket for nfs/server@EXAMPLE.COM that is issued on the fly.
+func newKerberosInitialContextToken(t *testing.T, serviceKeytab *keytab.Keytab, encryptionType int32, flags uint32, endTime time.Time) ([]byte, types.EncryptionKey, types.Authenticator) {
+	now := time.Now()
+	clientName := types.NewPrincipalName(nametype.KRB_NT_PRINCIPAL, "alice")
+	ticket, sessionKey, err := messages.NewTicket(
+		clientName,
+		"EXAMPLE.COM",
+		types.NewPrincipalName(nametype.KRB_NT_SRV_HST, "nfs/server"),
+		"EXAMPLE.COM",
+		types.NewKrbFlags(),
+		serviceKeytab,
+		encryptionType,
+		1,
+		now,
+		now,
+		endTime,
+		endTime)
+	require.NoError(t, err)
+
+	authenticator, err := types.NewAuthenticator("EXAMPLE.COM", clientName)
+	require.NoError(t, err)
+	checksum := make([]byte, 24)
+	binary.LittleEndian.PutUint32(checksum, 16)
+	binary.LittleEndian.PutUint32(checksum[20:], flags)
+	authenticator.Cksum = types.Checksum{
+		CksumType: chksumtype.GSSAPI,
+		Checksum:  checksum,
+	}
+	apReq, err := messages.NewAPReq(ticket, sessionKey, authenticator)
+	require.NoError(t, err)
+	marshaledAPReq, err := apReq.Marshal()
+	require.NoError(t, err)
+
+	token, err := asn1.Marshal(gssapi.OIDKRB5.OID())
+	require.NoError(t, err)
+	token = append(token, 0x01, 0x00)
+	token = append(token, marshaledAPReq...)
+	return asn1tools.AddASNAppTag(token, 0), sessionKey, authenticator
+}
+
+func TestKerberosGSSAcceptor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	serviceKeytab := keytab.New()
+	require.NoError(t, serviceKeytab.AddEntry("nfs/server", "EXAMPLE.COM", "password", time.Unix(1600000000, 0), 1, etypeID.AES256_CTS_HMAC_SHA1_96))
+	require.NoError(t, serviceKeytab.AddEntry("nfs/server", "EXAMPLE.COM", "password", time.Unix(1600000000, 0), 1, etypeID.RC4_HMAC))
+	randomNumberGenerator := mock.NewMockThreadSafeGenerator(ctrl)
+	acceptor := nfsv4.NewKerberosGSSAcceptor(serviceKeytab, randomNumberGenerator)
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		_, _, err := acceptor.AcceptSecContext([]byte("Hello"))
+		require.Error(t, err)
+	})
+
+	t.Run("InvalidTicket", func(t *testing.T) {
+		// Tickets that are encrypted with a key that is not
+		// part of the keytab should be rejected.
+		otherKeytab := keytab.New()
+		require.NoError(t, otherKeytab.AddEntry("nfs/server", "EXAMPLE.COM", "other", time.Unix(1600000000, 0), 1, etypeID.AES256_CTS_HMAC_SHA1_96))
+		token, _, _ := newKerberosInitialContextToken(t, otherKeytab, etypeID.AES256_CTS_HMAC_SHA1_96, 0, time.Now().Add(time.Hour))
+
+		_, _, err := acceptor.AcceptSecContext(token)
+		require.Error(t, err)
+	})
+
+	t.Run("UnsupportedEncryptionType", func(t *testing.T) {
+		// RC4 uses the per-message tokens described in RFC
+		// 1964, which are not supported.
+		token, _, _ := newKerberosInitialContextToken(t, serviceKeytab, etypeID.RC4_HMAC, 0, time.Now().Add(time.Hour))
+
+		_, _, err := acceptor.AcceptSecContext(token)
+		require.EqualError(t, err, "encryption type 23 is not supported")
+	})
+
+	t.Run("WithoutMutualAuthentication", func(t *testing.T) {
+		// Without mutual authentication, no output token is
+		// returned. The acceptor continues to use the sequence
+		// number provided by the initiator.
+		endTime := time.Now().Add(time.Hour)
+		token, sessionKey, authenticator := newKerberosInitialContextToken(t, serviceKeytab, etypeID.AES256_CTS_HMAC_SHA1_96, 0, endTime)
+
+		gssContext, outputToken, err := acceptor.AcceptSecContext(token)
+		require.NoError(t, err)
+		require.Nil(t, outputToken)
+		require.Equal(t, "alice@EXAMPLE.COM", gssContext.GetSourceName())
+		require.Equal(t, endTime.Unix(), gssContext.GetExpirationTime().Unix())
+
+		mic, err := gssContext.GetMIC([]byte("Hello"))
+		require.NoError(t, err)
+		var micToken gssapi.MICToken
+		require.NoError(t, micToken.Unmarshal(mic, true))
+		require.Equal(t, uint64(authenticator.SeqNumber), micToken.SndSeqNum)
+		micToken.Payload = []byte("Hello")
+		ok, err := micToken.Verify(sessionKey, keyusage.GSSAPI_ACCEPTOR_SIGN)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("WithMutualAuthentication", func(t *testing.T) {
+		token, sessionKey, authenticator := newKerberosInitialContextToken(t, serviceKeytab, etypeID.AES256_CTS_HMAC_SHA1_96, gssapi.ContextFlagMutual, time.Now().Add(time.Hour))
+		randomNumberGenerator.EXPECT().Uint64().Return(uint64(0xb3a4c87f12345678))
+
+		gssContext, outputToken, err := acceptor.AcceptSecContext(token)
+		require.NoError(t, err)
+
+		// The output token should contain an AP-REP that
+		// contains the acceptor's initial sequence number.
+		var oid asn1.ObjectIdentifier
+		innerToken, err := asn1.UnmarshalWithParams(outputToken, &oid, "application,explicit,tag:0")
+		require.NoError(t, err)
+		require.True(t, oid.Equal(gssapi.OIDKRB5.OID()))
+		require.Equal(t, []byte{0x02, 0x00}, innerToken[:2])
+		var apRep messages.APRep
+		require.NoError(t, apRep.Unmarshal(innerToken[2:]))
+		encAPRepPart, err := crypto.DecryptEncPart(apRep.EncPart, sessionKey, keyusage.AP_REP_ENCPART)
+		require.NoError(t, err)
+		var decryptedEncAPRepPart messages.EncAPRepPart
+		require.NoError(t, decryptedEncAPRepPart.Unmarshal(encAPRepPart))
+		require.Equal(t, authenticator.CTime.Unix(), decryptedEncAPRepPart.CTime.Unix())
+		require.Equal(t, authenticator.Cusec, decryptedEncAPRepPart.Cusec)
+		require.Equal(t, int64(0x12345678), decryptedEncAPRepPart.SequenceNumber)
+
+		t.Run("GetMIC", func(t *testing.T) {
+			mic, err := gssContext.GetMIC([]byte("Hello"))
+			require.NoError(t, err)
+
+			var micToken gssapi.MICToken
+			require.NoError(t, micToken.Unmarshal(mic, true))
+			require.Equal(t, uint64(0x12345678), micToken.SndSeqNum)
+			micToken.Payload = []byte("Hello")
+			ok, err := micToken.Verify(sessionKey, keyusage.GSSAPI_ACCEPTOR_SIGN)
+			require.NoError(t, err)
+			require.True(t, ok)
+		})
+
+		t.Run("VerifyMIC", func(t *testing.T) {
+			micToken, err := gssapi.NewInitiatorMICToken([]byte("Hello"), sessionKey)
+			require.NoError(t, err)
+			mic, err := micToken.Marshal()
+			require.NoError(t, err)
+
+			require.NoError(t, gssContext.VerifyMIC([]byte("Hello"), mic))
+			require.Error(t, gssContext.VerifyMIC([]byte("Goodbye"), mic))
+		})
+
+		t.Run("Wrap", func(t *testing.T) {
+			wrapToken, err := gssContext.Wrap([]byte("Hello"))
+			require.NoError(t, err)
+
+			header := []byte{
+				// TOK_ID.
+				0x05, 0x04,
+				// Flags: SentByAcceptor and Sealed.
+				0x03,
+				// Filler.
+				0xff,
+				// EC.
+				0x00, 0x00,
+				// RRC.
+				0x00, 0x00,
+				// SND_SEQ.
+				0x00, 0x00, 0x00, 0x00, 0x12, 0x34, 0x56, 0x79,
+			}
+			require.Equal(t, header, wrapToken[:16])
+			encryptionType, err := crypto.GetEtype(sessionKey.KeyType)
+			require.NoError(t, err)
+			plaintext, err := encryptionType.DecryptMessage(sessionKey.KeyValue, wrapToken[16:], keyusage.GSSAPI_ACCEPTOR_SEAL)
+			require.NoError(t, err)
+			require.Equal(t, append([]byte("Hello"), header...), plaintext)
+		})
+
+		// Construct a token like an initiator would, using
+		// three bytes of filler and a right rotation count of
+		// five bytes.
+		newInitiatorWrapToken := func(t *testing.T, message []byte) []byte {
+			header := []byte{
+				// TOK_ID.
+				0x05, 0x04,
+				// Flags: Sealed.
+				0x02,
+				// Filler.
+				0xff,
+				// EC.
+				0x00, 0x03,
+				// RRC.
+				0x00, 0x00,
+				// SND_SEQ.
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2a,
+			}
+			plaintext := append(append(append([]byte(nil), message...), 0x00, 0x00, 0x00), header...)
+			encryptionType, err := crypto.GetEtype(sessionKey.KeyType)
+			require.NoError(t, err)
+			_, ciphertext, err := encryptionType.EncryptMessage(sessionKey.KeyValue, plaintext, keyusage.GSSAPI_INITIATOR_SEAL)
+			require.NoError(t, err)
+
+			header[7] = 0x05
+			rotatedCiphertext := append(append([]byte(nil), ciphertext[len(ciphertext)-5:]...), ciphertext[:len(ciphertext)-5]...)
+			return append(header, rotatedCiphertext...)
+		}
+
+		t.Run("UnwrapSuccess", func(t *testing.T) {
+			message, err := gssContext.Unwrap(newInitiatorWrapToken(t, []byte("Hello")))
+			require.NoError(t, err)
+			require.Equal(t, []byte("Hello"), message)
+		})
+
+		t.Run("UnwrapCorrupted", func(t *testing.T) {
+			wrapToken := newInitiatorWrapToken(t, []byte("Hello"))
+			wrapToken[len(wrapToken)-1] ^= 0x01
+
+			_, err := gssContext.Unwrap(wrapToken)
+			require.Error(t, err)
+		})
+
+		t.Run("UnwrapHeaderMismatch", func(t *testing.T) {
+			// The sequence number in the header is not
+			// covered by the checksum. It must be compared
+			// against the encrypted copy.
+			wrapToken := newInitiatorWrapToken(t, []byte("Hello"))
+			wrapToken[15] = 0x2b
+
+			_, err := gssContext.Unwrap(wrapToken)
+			require.EqualError(t, err, "encrypted header does not match")
+		})
+
+		t.Run("UnwrapSentByAcceptor", func(t *testing.T) {
+			// Tokens generated by ourselves should not be
+			// accepted, as that would allow reflection.
+			wrapToken, err := gssContext.Wrap([]byte("Hello"))
+			require.NoError(t, err)
+
+			_, err = gssContext.Unwrap(wrapToken)
+			require.EqualError(t, err, "token was sent by the acceptor")
+		})
+	})
+}
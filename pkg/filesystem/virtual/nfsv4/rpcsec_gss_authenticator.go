@@ -0,0 +1,474 @@
+package nfsv4
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"log"
+	"math"
+	"sync"
+
+	"github.com/buildbarn/bb-storage/pkg/auth"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/eviction"
+	"github.com/buildbarn/bb-storage/pkg/random"
+	"github.com/buildbarn/go-xdr/pkg/protocols/rpcv2"
+	"github.com/buildbarn/go-xdr/pkg/rpcserver"
+	"github.com/buildbarn/go-xdr/pkg/runtime"
+	"github.com/jmespath/go-jmespath"
+)
+
+// Constants of the RPCSEC_GSS protocol, as described in RFC 2203,
+// section 5.
+const (
+	rpcsecGSSVersion1 = 1
+
+	rpcsecGSSProcData         = 0
+	rpcsecGSSProcInit         = 1
+	rpcsecGSSProcContinueInit = 2
+	rpcsecGSSProcDestroy      = 3
+
+	rpcsecGSSServiceNone      = 1
+	rpcsecGSSServiceIntegrity = 2
+	rpcsecGSSServicePrivacy   = 3
+
+	rpcsecGSSMaximumSequenceNumber = 0x80000000
+
+	// The number of sequence numbers preceding the highest
+	// observed sequence number that are still accepted. This is
+	// announced to clients when establishing a security context.
+	rpcsecGSSSequenceWindow = 64
+
+	// GSS-API major status codes, as described in RFC 2744, section
+	// 3.9.1.
+	gssStatusComplete = 0
+	gssStatusFailure  = 13 << 16
+)
+
+// RPCSECGSSContextHandle is the key type that's used by the RPCSEC_GSS
+// authenticator's eviction set. It is also the handle that is provided
+// to clients to refer to an established security context.
+type RPCSECGSSContextHandle [16]byte
+
+type rpcsecGSSContext struct {
+	gssContext             GSSContext
+	authenticationMetadata *auth.AuthenticationMetadata
+	destroyed              bool
+
+	highestSequenceNumber uint32
+	sequenceNumbersSeen   uint64
+}
+
+// maybeAcceptSequenceNumber returns whether a sequence number provided
+// by a client falls within the sequence window and has not been used
+// before. This prevents requests from being replayed.
+func (c *rpcsecGSSContext) maybeAcceptSequenceNumber(sequenceNumber uint32) bool {
+	if c.sequenceNumbersSeen == 0 || sequenceNumber > c.highestSequenceNumber {
+		if shift := sequenceNumber - c.highestSequenceNumber; c.sequenceNumbersSeen != 0 && shift < rpcsecGSSSequenceWindow {
+			c.sequenceNumbersSeen = c.sequenceNumbersSeen<<shift | 1
+		} else {
+			c.sequenceNumbersSeen = 1
+		}
+		c.highestSequenceNumber = sequenceNumber
+		return true
+	}
+	age := c.highestSequenceNumber - sequenceNumber
+	if age >= rpcsecGSSSequenceWindow || c.sequenceNumbersSeen&(1<<age) != 0 {
+		return false
+	}
+	c.sequenceNumbersSeen |= 1 << age
+	return true
+}
+
+type rpcsecGSSCredentials struct {
+	proc           uint32
+	sequenceNumber uint32
+	service        uint32
+	handle         []byte
+}
+
+func parseRPCSECGSSCredentials(body []byte) (rpcsecGSSCredentials, bool) {
+	var credentials rpcsecGSSCredentials
+	b := bytes.NewBuffer(body)
+	version, _, err := runtime.ReadUnsignedInt(b)
+	if err != nil || version != rpcsecGSSVersion1 {
+		return credentials, false
+	}
+	if credentials.proc, _, err = runtime.ReadUnsignedInt(b); err != nil {
+		return credentials, false
+	}
+	if credentials.sequenceNumber, _, err = runtime.ReadUnsignedInt(b); err != nil {
+		return credentials, false
+	}
+	if credentials.service, _, err = runtime.ReadUnsignedInt(b); err != nil {
+		return credentials, false
+	}
+	if credentials.handle, _, err = runtime.ReadVariableLengthOpaque(b, math.MaxUint32); err != nil || b.Len() != 0 {
+		return credentials, false
+	}
+	return credentials, true
+}
+
+type rpcsecGSSAuthenticator struct {
+	base                  rpcserver.Authenticator
+	acceptor              GSSAcceptor
+	metadataExtractor     *jmespath.JMESPath
+	authorizer            auth.Authorizer
+	clock                 clock.Clock
+	randomNumberGenerator random.ThreadSafeGenerator
+
+	lock               sync.Mutex
+	contexts           map[RPCSECGSSContextHandle]*rpcsecGSSContext
+	maximumContexts    int
+	contextEvictionSet eviction.Set[RPCSECGSSContextHandle]
+}
+
+// NewRPCSECGSSAuthenticator creates an RPCv2 Authenticator that
+// requires that requests provide credentials of flavor RPCSEC_GSS, as
+// described in RFC 2203. Security contexts are established using a
+// GSSAcceptor, such as the one returned by NewKerberosGSSAcceptor().
+//
+// Upon establishment of a security context, the name of the client
+// principal is converted to an AuthenticationMetadata object using a
+// JMESPath expression, which is subsequently checked against an
+// Authorizer. As NFSv4 has no notion of instance names, the Authorizer
+// is invoked against the empty instance name. The resulting metadata
+// is attached to the Context of all requests that use the security
+// context. Requests may use any of the protection services (none,
+// integrity or privacy).
+//
+// Requests using other credential flavors are forwarded to a base
+// Authenticator. If no base Authenticator is provided, such requests
+// are rejected, with the exception of calls to the NULL procedure
+// using AUTH_NONE.
+//
+// RFC 2203 requires that requests with sequence numbers that fall
+// outside the sequence window or that have been observed before are
+// silently discarded. As the RPC server always sends a reply, such
+// requests are rejected with RPCSEC_GSS_CREDPROBLEM instead.
+func NewRPCSECGSSAuthenticator(base rpcserver.Authenticator, acceptor GSSAcceptor, metadataExtractor *jmespath.JMESPath, authorizer auth.Authorizer, clock clock.Clock, randomNumberGenerator random.ThreadSafeGenerator, maximumContexts int, contextEvictionSet eviction.Set[RPCSECGSSContextHandle]) rpcserver.CallAuthenticator {
+	return &rpcsecGSSAuthenticator{
+		base:                  base,
+		acceptor:              acceptor,
+		metadataExtractor:     metadataExtractor,
+		authorizer:            authorizer,
+		clock:                 clock,
+		randomNumberGenerator: randomNumberGenerator,
+
+		contexts:           map[RPCSECGSSContextHandle]*rpcsecGSSContext{},
+		maximumContexts:    maximumContexts,
+		contextEvictionSet: contextEvictionSet,
+	}
+}
+
+func (a *rpcsecGSSAuthenticator) Authenticate(ctx context.Context, credentials, verifier *rpcv2.OpaqueAuth) (context.Context, rpcv2.OpaqueAuth, rpcv2.AuthStat) {
+	// RPCSEC_GSS requires access to the full call header, which is
+	// only provided through AuthenticateCall().
+	if credentials.Flavor == rpcv2.RPCSEC_GSS || a.base == nil {
+		return nil, rpcv2.OpaqueAuth{}, rpcv2.AUTH_TOOWEAK
+	}
+	return a.base.Authenticate(ctx, credentials, verifier)
+}
+
+func (a *rpcsecGSSAuthenticator) AuthenticateCall(ctx context.Context, xid uint32, callBody *rpcv2.CallBody) (context.Context, rpcserver.CallHandler, rpcv2.AuthStat) {
+	if callBody.Cred.Flavor != rpcv2.RPCSEC_GSS {
+		if a.base == nil {
+			if callBody.Cred.Flavor == rpcv2.AUTH_NONE && callBody.Proc == 0 {
+				return ctx, rpcserver.NewStaticVerifierCallHandler(rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_NONE}), rpcv2.AUTH_OK
+			}
+			return nil, nil, rpcv2.AUTH_TOOWEAK
+		}
+		ctx, replyVerifier, s := a.base.Authenticate(ctx, &callBody.Cred, &callBody.Verf)
+		if s != rpcv2.AUTH_OK {
+			return nil, nil, s
+		}
+		return ctx, rpcserver.NewStaticVerifierCallHandler(replyVerifier), rpcv2.AUTH_OK
+	}
+
+	credentials, ok := parseRPCSECGSSCredentials(callBody.Cred.Body)
+	if !ok {
+		return nil, nil, rpcv2.AUTH_BADCRED
+	}
+	switch credentials.proc {
+	case rpcsecGSSProcInit:
+		if callBody.Proc != 0 || len(credentials.handle) != 0 {
+			return nil, nil, rpcv2.AUTH_BADCRED
+		}
+		return ctx, a.handleInit, rpcv2.AUTH_OK
+	case rpcsecGSSProcContinueInit:
+		// Security contexts are always established in a
+		// single round trip. There are thus no incomplete
+		// contexts that can be continued.
+		return nil, nil, rpcv2.RPCSEC_GSS_CREDPROBLEM
+	case rpcsecGSSProcData, rpcsecGSSProcDestroy:
+		if credentials.service < rpcsecGSSServiceNone || credentials.service > rpcsecGSSServicePrivacy {
+			return nil, nil, rpcv2.AUTH_BADCRED
+		}
+		if credentials.proc == rpcsecGSSProcDestroy && callBody.Proc != 0 {
+			return nil, nil, rpcv2.AUTH_BADCRED
+		}
+		if credentials.sequenceNumber >= rpcsecGSSMaximumSequenceNumber {
+			return nil, nil, rpcv2.RPCSEC_GSS_CTXPROBLEM
+		}
+		var handle RPCSECGSSContextHandle
+		if len(credentials.handle) != len(handle) {
+			return nil, nil, rpcv2.RPCSEC_GSS_CREDPROBLEM
+		}
+		copy(handle[:], credentials.handle)
+
+		a.lock.Lock()
+		c, ok := a.contexts[handle]
+		if !ok || c.destroyed {
+			a.lock.Unlock()
+			return nil, nil, rpcv2.RPCSEC_GSS_CREDPROBLEM
+		}
+		a.contextEvictionSet.Touch(handle)
+		a.lock.Unlock()
+
+		if !a.clock.Now().Before(c.gssContext.GetExpirationTime()) {
+			return nil, nil, rpcv2.RPCSEC_GSS_CTXPROBLEM
+		}
+
+		// Validate the verifier, which contains a checksum of
+		// the call header up to and including the credentials.
+		if callBody.Verf.Flavor != rpcv2.RPCSEC_GSS {
+			return nil, nil, rpcv2.RPCSEC_GSS_CREDPROBLEM
+		}
+		header := bytes.NewBuffer(make([]byte, 0, 24+callBody.Cred.GetEncodedSizeBytes()))
+		runtime.WriteUnsignedInt(header, xid)
+		runtime.WriteInt(header, int32(rpcv2.CALL))
+		runtime.WriteUnsignedInt(header, callBody.Rpcvers)
+		runtime.WriteUnsignedInt(header, callBody.Prog)
+		runtime.WriteUnsignedInt(header, callBody.Vers)
+		runtime.WriteUnsignedInt(header, callBody.Proc)
+		callBody.Cred.WriteTo(header)
+		if err := c.gssContext.VerifyMIC(header.Bytes(), callBody.Verf.Body); err != nil {
+			return nil, nil, rpcv2.RPCSEC_GSS_CREDPROBLEM
+		}
+
+		a.lock.Lock()
+		if c.destroyed || !c.maybeAcceptSequenceNumber(credentials.sequenceNumber) {
+			a.lock.Unlock()
+			return nil, nil, rpcv2.RPCSEC_GSS_CREDPROBLEM
+		}
+		if credentials.proc == rpcsecGSSProcDestroy {
+			// Entries can't be removed from the eviction
+			// set directly. Leave the entry in place until
+			// it gets evicted.
+			c.destroyed = true
+			a.lock.Unlock()
+			return ctx, newRPCSECGSSDestroyCallHandler(c.gssContext, credentials.sequenceNumber), rpcv2.AUTH_OK
+		}
+		a.lock.Unlock()
+		return auth.NewContextWithAuthenticationMetadata(ctx, c.authenticationMetadata),
+			newRPCSECGSSDataCallHandler(c.gssContext, credentials.sequenceNumber, credentials.service),
+			rpcv2.AUTH_OK
+	default:
+		return nil, nil, rpcv2.AUTH_BADCRED
+	}
+}
+
+// writeRPCSECGSSInitResult writes an rpc_gss_init_res structure, which
+// is returned by RPCSEC_GSS_INIT calls.
+func writeRPCSECGSSInitResult(w io.Writer, handle []byte, gssMajor, sequenceWindow uint32, gssToken []byte) error {
+	if _, err := runtime.WriteVariableLengthOpaque(w, math.MaxUint32, handle); err != nil {
+		return err
+	}
+	if _, err := runtime.WriteUnsignedInt(w, gssMajor); err != nil {
+		return err
+	}
+	// gss_minor.
+	if _, err := runtime.WriteUnsignedInt(w, 0); err != nil {
+		return err
+	}
+	if _, err := runtime.WriteUnsignedInt(w, sequenceWindow); err != nil {
+		return err
+	}
+	_, err := runtime.WriteVariableLengthOpaque(w, math.MaxUint32, gssToken)
+	return err
+}
+
+func (a *rpcsecGSSAuthenticator) handleInit(ctx context.Context, service rpcserver.Service, vers, proc uint32, parameters io.ReadCloser, returnValue io.Writer) (rpcv2.AcceptedReplyData, rpcv2.OpaqueAuth, error) {
+	inputToken, _, err := runtime.ReadVariableLengthOpaque(parameters, math.MaxUint32)
+	parameters.Close()
+	if err != nil {
+		return &rpcv2.AcceptedReplyData_default{Stat: rpcv2.GARBAGE_ARGS}, rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_NONE}, nil
+	}
+
+	c, outputToken, err := a.establishContext(ctx, inputToken)
+	if err != nil {
+		log.Print("Failed to establish RPCSEC_GSS security context: ", err)
+		return &rpcv2.AcceptedReplyData_SUCCESS{},
+			rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_NONE},
+			writeRPCSECGSSInitResult(returnValue, nil, gssStatusFailure, 0, nil)
+	}
+
+	// Upon success, the verifier contains a checksum of the
+	// sequence window.
+	var sequenceWindow [4]byte
+	binary.BigEndian.PutUint32(sequenceWindow[:], rpcsecGSSSequenceWindow)
+	mic, err := c.gssContext.GetMIC(sequenceWindow[:])
+	if err != nil {
+		return nil, rpcv2.OpaqueAuth{}, err
+	}
+
+	var handle RPCSECGSSContextHandle
+	if _, err := a.randomNumberGenerator.Read(handle[:]); err != nil {
+		return nil, rpcv2.OpaqueAuth{}, err
+	}
+	a.lock.Lock()
+	for len(a.contexts) > 0 && len(a.contexts) > a.maximumContexts {
+		delete(a.contexts, a.contextEvictionSet.Peek())
+		a.contextEvictionSet.Remove()
+	}
+	a.contexts[handle] = c
+	a.contextEvictionSet.Insert(handle)
+	a.lock.Unlock()
+
+	return &rpcv2.AcceptedReplyData_SUCCESS{},
+		rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: mic},
+		writeRPCSECGSSInitResult(returnValue, handle[:], gssStatusComplete, rpcsecGSSSequenceWindow, outputToken)
+}
+
+func (a *rpcsecGSSAuthenticator) establishContext(ctx context.Context, inputToken []byte) (*rpcsecGSSContext, []byte, error) {
+	gssContext, outputToken, err := a.acceptor.AcceptSecContext(inputToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Convert to authentication metadata.
+	raw, err := a.metadataExtractor.Search(map[string]any{
+		"principal": gssContext.GetSourceName(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	authenticationMetadata, err := auth.NewAuthenticationMetadataFromRaw(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := auth.AuthorizeSingleInstanceName(
+		auth.NewContextWithAuthenticationMetadata(ctx, authenticationMetadata),
+		a.authorizer,
+		digest.EmptyInstanceName,
+	); err != nil {
+		return nil, nil, err
+	}
+	return &rpcsecGSSContext{
+		gssContext:             gssContext,
+		authenticationMetadata: authenticationMetadata,
+	}, outputToken, nil
+}
+
+// getRPCSECGSSReplyVerifier computes the verifier of a reply to an
+// RPCSEC_GSS_DATA or RPCSEC_GSS_DESTROY call, which contains a
+// checksum of the sequence number of the call.
+func getRPCSECGSSReplyVerifier(gssContext GSSContext, sequenceNumber uint32) (rpcv2.OpaqueAuth, error) {
+	var message [4]byte
+	binary.BigEndian.PutUint32(message[:], sequenceNumber)
+	mic, err := gssContext.GetMIC(message[:])
+	if err != nil {
+		return rpcv2.OpaqueAuth{}, err
+	}
+	return rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: mic}, nil
+}
+
+func newRPCSECGSSDestroyCallHandler(gssContext GSSContext, sequenceNumber uint32) rpcserver.CallHandler {
+	return func(ctx context.Context, service rpcserver.Service, vers, proc uint32, parameters io.ReadCloser, returnValue io.Writer) (rpcv2.AcceptedReplyData, rpcv2.OpaqueAuth, error) {
+		parameters.Close()
+		replyVerifier, err := getRPCSECGSSReplyVerifier(gssContext, sequenceNumber)
+		if err != nil {
+			return nil, rpcv2.OpaqueAuth{}, err
+		}
+		return &rpcv2.AcceptedReplyData_SUCCESS{}, replyVerifier, nil
+	}
+}
+
+// stripRPCSECGSSSequenceNumber removes the sequence number that is
+// prepended to the parameters of calls using the integrity and privacy
+// services. The sequence number must match the one in the credentials.
+func stripRPCSECGSSSequenceNumber(body []byte, sequenceNumber uint32) ([]byte, bool) {
+	if len(body) < 4 || binary.BigEndian.Uint32(body) != sequenceNumber {
+		return nil, false
+	}
+	return body[4:], true
+}
+
+func newRPCSECGSSDataCallHandler(gssContext GSSContext, sequenceNumber, service uint32) rpcserver.CallHandler {
+	return func(ctx context.Context, s rpcserver.Service, vers, proc uint32, parameters io.ReadCloser, returnValue io.Writer) (rpcv2.AcceptedReplyData, rpcv2.OpaqueAuth, error) {
+		replyVerifier, err := getRPCSECGSSReplyVerifier(gssContext, sequenceNumber)
+		if err != nil {
+			parameters.Close()
+			return nil, rpcv2.OpaqueAuth{}, err
+		}
+
+		if service == rpcsecGSSServiceNone {
+			replyData, err := s(ctx, vers, proc, parameters, returnValue)
+			return replyData, replyVerifier, err
+		}
+
+		// Extract the parameters from the protected body.
+		var arguments []byte
+		if service == rpcsecGSSServiceIntegrity {
+			body, _, err := runtime.ReadVariableLengthOpaque(parameters, math.MaxUint32)
+			if err != nil {
+				parameters.Close()
+				return &rpcv2.AcceptedReplyData_default{Stat: rpcv2.GARBAGE_ARGS}, replyVerifier, nil
+			}
+			checksum, _, err := runtime.ReadVariableLengthOpaque(parameters, math.MaxUint32)
+			parameters.Close()
+			if err != nil || gssContext.VerifyMIC(body, checksum) != nil {
+				return &rpcv2.AcceptedReplyData_default{Stat: rpcv2.GARBAGE_ARGS}, replyVerifier, nil
+			}
+			arguments = body
+		} else {
+			token, _, err := runtime.ReadVariableLengthOpaque(parameters, math.MaxUint32)
+			parameters.Close()
+			if err != nil {
+				return &rpcv2.AcceptedReplyData_default{Stat: rpcv2.GARBAGE_ARGS}, replyVerifier, nil
+			}
+			if arguments, err = gssContext.Unwrap(token); err != nil {
+				return &rpcv2.AcceptedReplyData_default{Stat: rpcv2.GARBAGE_ARGS}, replyVerifier, nil
+			}
+		}
+		arguments, ok := stripRPCSECGSSSequenceNumber(arguments, sequenceNumber)
+		if !ok {
+			return &rpcv2.AcceptedReplyData_default{Stat: rpcv2.GARBAGE_ARGS}, replyVerifier, nil
+		}
+
+		// Call into the service, and protect the results in the
+		// same way as the parameters.
+		results := bytes.NewBuffer(nil)
+		runtime.WriteUnsignedInt(results, sequenceNumber)
+		replyData, err := s(ctx, vers, proc, io.NopCloser(bytes.NewBuffer(arguments)), results)
+		if err != nil {
+			return nil, rpcv2.OpaqueAuth{}, err
+		}
+		if _, ok := replyData.(*rpcv2.AcceptedReplyData_SUCCESS); !ok {
+			return replyData, replyVerifier, nil
+		}
+		if service == rpcsecGSSServiceIntegrity {
+			checksum, err := gssContext.GetMIC(results.Bytes())
+			if err != nil {
+				return nil, rpcv2.OpaqueAuth{}, err
+			}
+			if _, err := runtime.WriteVariableLengthOpaque(returnValue, math.MaxUint32, results.Bytes()); err != nil {
+				return nil, rpcv2.OpaqueAuth{}, err
+			}
+			if _, err := runtime.WriteVariableLengthOpaque(returnValue, math.MaxUint32, checksum); err != nil {
+				return nil, rpcv2.OpaqueAuth{}, err
+			}
+		} else {
+			token, err := gssContext.Wrap(results.Bytes())
+			if err != nil {
+				return nil, rpcv2.OpaqueAuth{}, err
+			}
+			if _, err := runtime.WriteVariableLengthOpaque(returnValue, math.MaxUint32, token); err != nil {
+				return nil, rpcv2.OpaqueAuth{}, err
+			}
+		}
+		return replyData, replyVerifier, nil
+	}
+}
@@ -0,0 +1,597 @@
+package nfsv4_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual/nfsv4"
+	"github.com/buildbarn/bb-storage/pkg/auth"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/eviction"
+	"github.com/buildbarn/go-xdr/pkg/protocols/rpcv2"
+	"github.com/golang/mock/gomock"
+	"github.com/jmespath/go-jmespath"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newRPCSECGSSCredentials creates credentials of flavor RPCSEC_GSS,
+// containing an rpc_gss_cred_vers_1_t structure.
+func newRPCSECGSSCredentials(proc, sequenceNumber, service byte, handle []byte) rpcv2.OpaqueAuth {
+	body := []byte{
+		// version.
+		0x00, 0x00, 0x00, 0x01,
+		// gss_proc.
+		0x00, 0x00, 0x00, proc,
+		// seq_num.
+		0x00, 0x00, 0x00, sequenceNumber,
+		// service.
+		0x00, 0x00, 0x00, service,
+		// handle.
+		0x00, 0x00, 0x00, byte(len(handle)),
+	}
+	body = append(body, handle...)
+	for len(body)%4 != 0 {
+		body = append(body, 0)
+	}
+	return rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: body}
+}
+
+func TestRPCSECGSSAuthenticator(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	acceptor := mock.NewMockGSSAcceptor(ctrl)
+	authorizer := mock.NewMockAuthorizer(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	randomNumberGenerator := mock.NewMockThreadSafeGenerator(ctrl)
+	authenticator := nfsv4.NewRPCSECGSSAuthenticator(
+		/* base = */ nil,
+		acceptor,
+		jmespath.MustCompile("{\"public\": @}"),
+		authorizer,
+		clock,
+		randomNumberGenerator,
+		10,
+		eviction.NewLRUSet[nfsv4.RPCSECGSSContextHandle]())
+
+	handle := []byte{
+		0x3c, 0x8f, 0x1a, 0x52, 0x07, 0xe4, 0x9b, 0x6d,
+		0x21, 0xc5, 0x70, 0xfe, 0x44, 0x98, 0x0b, 0xd3,
+	}
+	gssContext := mock.NewMockGSSContext(ctrl)
+	gssContext.EXPECT().GetExpirationTime().Return(time.Unix(1700000000, 0)).AnyTimes()
+
+	// Service that expects to be called with the authentication
+	// metadata of the security context.
+	echoService := func(ctx context.Context, vers, proc uint32, parameters io.ReadCloser, returnValue io.Writer) (rpcv2.AcceptedReplyData, error) {
+		require.Equal(t, map[string]any{
+			"public": map[string]any{
+				"principal": "alice@EXAMPLE.COM",
+			},
+		}, auth.AuthenticationMetadataFromContext(ctx).GetRaw())
+		require.Equal(t, uint32(4), vers)
+		require.Equal(t, uint32(1), proc)
+		arguments, err := io.ReadAll(parameters)
+		require.NoError(t, err)
+		require.NoError(t, parameters.Close())
+		returnValue.Write(append([]byte("Results: "), arguments...))
+		return &rpcv2.AcceptedReplyData_SUCCESS{}, nil
+	}
+
+	t.Run("OtherFlavor", func(t *testing.T) {
+		// If no base authenticator is provided, other flavors
+		// should be rejected.
+		_, _, s := authenticator.AuthenticateCall(ctx, 123, &rpcv2.CallBody{
+			Rpcvers: 2,
+			Prog:    100003,
+			Vers:    4,
+			Proc:    1,
+			Cred:    rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_SYS},
+			Verf:    rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_NONE},
+		})
+		require.Equal(t, rpcv2.AUTH_TOOWEAK, s)
+	})
+
+	t.Run("NullProcedure", func(t *testing.T) {
+		// The NULL procedure may be called without any
+		// authentication.
+		_, callHandler, s := authenticator.AuthenticateCall(ctx, 123, &rpcv2.CallBody{
+			Rpcvers: 2,
+			Prog:    100003,
+			Vers:    4,
+			Proc:    0,
+			Cred:    rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_NONE},
+			Verf:    rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_NONE},
+		})
+		require.Equal(t, rpcv2.AUTH_OK, s)
+
+		replyData, replyVerifier, err := callHandler(
+			ctx,
+			func(ctx context.Context, vers, proc uint32, parameters io.ReadCloser, returnValue io.Writer) (rpcv2.AcceptedReplyData, error) {
+				require.NoError(t, parameters.Close())
+				return &rpcv2.AcceptedReplyData_SUCCESS{}, nil
+			},
+			4,
+			0,
+			io.NopCloser(bytes.NewBuffer(nil)),
+			bytes.NewBuffer(nil))
+		require.NoError(t, err)
+		require.Equal(t, &rpcv2.AcceptedReplyData_SUCCESS{}, replyData)
+		require.Equal(t, rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_NONE}, replyVerifier)
+	})
+
+	t.Run("MalformedCredentials", func(t *testing.T) {
+		_, _, s := authenticator.AuthenticateCall(ctx, 123, &rpcv2.CallBody{
+			Rpcvers: 2,
+			Prog:    100003,
+			Vers:    4,
+			Proc:    1,
+			Cred: rpcv2.OpaqueAuth{
+				Flavor: rpcv2.RPCSEC_GSS,
+				Body:   []byte{0x00, 0x00, 0x00, 0x02},
+			},
+			Verf: rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_NONE},
+		})
+		require.Equal(t, rpcv2.AUTH_BADCRED, s)
+	})
+
+	t.Run("InitNotNullProcedure", func(t *testing.T) {
+		// Security contexts can only be established by calling
+		// the NULL procedure.
+		_, _, s := authenticator.AuthenticateCall(ctx, 123, &rpcv2.CallBody{
+			Rpcvers: 2,
+			Prog:    100003,
+			Vers:    4,
+			Proc:    1,
+			Cred:    newRPCSECGSSCredentials(1, 0, 1, nil),
+			Verf:    rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_NONE},
+		})
+		require.Equal(t, rpcv2.AUTH_BADCRED, s)
+	})
+
+	initCallBody := &rpcv2.CallBody{
+		Rpcvers: 2,
+		Prog:    100003,
+		Vers:    4,
+		Proc:    0,
+		Cred:    newRPCSECGSSCredentials(1, 0, 1, nil),
+		Verf:    rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_NONE},
+	}
+	initParameters := []byte{
+		// gss_token.
+		0x00, 0x00, 0x00, 0x05,
+		0x48, 0x65, 0x6c, 0x6c,
+		0x6f, 0x00, 0x00, 0x00,
+	}
+
+	t.Run("InitAcceptorFailure", func(t *testing.T) {
+		// Errors returned by the GSS-API mechanism should be
+		// reported through the rpc_gss_init_res structure.
+		acceptor.EXPECT().AcceptSecContext([]byte("Hello")).
+			Return(nil, nil, status.Error(codes.Unauthenticated, "Ticket expired"))
+
+		_, callHandler, s := authenticator.AuthenticateCall(ctx, 123, initCallBody)
+		require.Equal(t, rpcv2.AUTH_OK, s)
+
+		returnValue := bytes.NewBuffer(nil)
+		replyData, replyVerifier, err := callHandler(ctx, nil, 4, 0, io.NopCloser(bytes.NewBuffer(initParameters)), returnValue)
+		require.NoError(t, err)
+		require.Equal(t, &rpcv2.AcceptedReplyData_SUCCESS{}, replyData)
+		require.Equal(t, rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_NONE}, replyVerifier)
+		require.Equal(t, []byte{
+			// handle.
+			0x00, 0x00, 0x00, 0x00,
+			// gss_major == GSS_S_FAILURE.
+			0x00, 0x0d, 0x00, 0x00,
+			// gss_minor.
+			0x00, 0x00, 0x00, 0x00,
+			// seq_window.
+			0x00, 0x00, 0x00, 0x00,
+			// gss_token.
+			0x00, 0x00, 0x00, 0x00,
+		}, returnValue.Bytes())
+	})
+
+	t.Run("InitAuthorizationFailure", func(t *testing.T) {
+		// Security contexts should only be established if the
+		// client principal is authorized.
+		acceptor.EXPECT().AcceptSecContext([]byte("Hello")).Return(gssContext, []byte("Token"), nil)
+		gssContext.EXPECT().GetSourceName().Return("alice@EXAMPLE.COM")
+		authorizer.EXPECT().Authorize(gomock.Any(), []digest.InstanceName{digest.EmptyInstanceName}).
+			DoAndReturn(func(ctx context.Context, instanceNames []digest.InstanceName) []error {
+				require.Equal(t, map[string]any{
+					"public": map[string]any{
+						"principal": "alice@EXAMPLE.COM",
+					},
+				}, auth.AuthenticationMetadataFromContext(ctx).GetRaw())
+				return []error{status.Error(codes.PermissionDenied, "User is not permitted to access this file system")}
+			})
+
+		_, callHandler, s := authenticator.AuthenticateCall(ctx, 123, initCallBody)
+		require.Equal(t, rpcv2.AUTH_OK, s)
+
+		returnValue := bytes.NewBuffer(nil)
+		replyData, replyVerifier, err := callHandler(ctx, nil, 4, 0, io.NopCloser(bytes.NewBuffer(initParameters)), returnValue)
+		require.NoError(t, err)
+		require.Equal(t, &rpcv2.AcceptedReplyData_SUCCESS{}, replyData)
+		require.Equal(t, rpcv2.OpaqueAuth{Flavor: rpcv2.AUTH_NONE}, replyVerifier)
+		require.Equal(t, []byte{
+			// handle.
+			0x00, 0x00, 0x00, 0x00,
+			// gss_major == GSS_S_FAILURE.
+			0x00, 0x0d, 0x00, 0x00,
+			// gss_minor.
+			0x00, 0x00, 0x00, 0x00,
+			// seq_window.
+			0x00, 0x00, 0x00, 0x00,
+			// gss_token.
+			0x00, 0x00, 0x00, 0x00,
+		}, returnValue.Bytes())
+	})
+
+	t.Run("DataUnknownHandle", func(t *testing.T) {
+		_, _, s := authenticator.AuthenticateCall(ctx, 123, &rpcv2.CallBody{
+			Rpcvers: 2,
+			Prog:    100003,
+			Vers:    4,
+			Proc:    1,
+			Cred:    newRPCSECGSSCredentials(0, 1, 1, handle),
+			Verf:    rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("Checksum")},
+		})
+		require.Equal(t, rpcv2.RPCSEC_GSS_CREDPROBLEM, s)
+	})
+
+	t.Run("InitSuccess", func(t *testing.T) {
+		acceptor.EXPECT().AcceptSecContext([]byte("Hello")).Return(gssContext, []byte("Token"), nil)
+		gssContext.EXPECT().GetSourceName().Return("alice@EXAMPLE.COM")
+		authorizer.EXPECT().Authorize(gomock.Any(), []digest.InstanceName{digest.EmptyInstanceName}).
+			Return([]error{nil})
+		gssContext.EXPECT().GetMIC([]byte{0x00, 0x00, 0x00, 0x40}).Return([]byte("WindowChecksum"), nil)
+		randomNumberGenerator.EXPECT().Read(gomock.Len(16)).
+			DoAndReturn(func(p []byte) (int, error) {
+				return copy(p, handle), nil
+			})
+
+		_, callHandler, s := authenticator.AuthenticateCall(ctx, 123, initCallBody)
+		require.Equal(t, rpcv2.AUTH_OK, s)
+
+		returnValue := bytes.NewBuffer(nil)
+		replyData, replyVerifier, err := callHandler(ctx, nil, 4, 0, io.NopCloser(bytes.NewBuffer(initParameters)), returnValue)
+		require.NoError(t, err)
+		require.Equal(t, &rpcv2.AcceptedReplyData_SUCCESS{}, replyData)
+		require.Equal(t, rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("WindowChecksum")}, replyVerifier)
+		require.Equal(t, append(append([]byte{
+			// handle.
+			0x00, 0x00, 0x00, 0x10,
+		}, handle...), []byte{
+			// gss_major == GSS_S_COMPLETE.
+			0x00, 0x00, 0x00, 0x00,
+			// gss_minor.
+			0x00, 0x00, 0x00, 0x00,
+			// seq_window.
+			0x00, 0x00, 0x00, 0x40,
+			// gss_token.
+			0x00, 0x00, 0x00, 0x05,
+			0x54, 0x6f, 0x6b, 0x65,
+			0x6e, 0x00, 0x00, 0x00,
+		}...), returnValue.Bytes())
+	})
+
+	t.Run("DataInvalidVerifier", func(t *testing.T) {
+		// The verifier must contain a checksum of the header of
+		// the call, up to and including the credentials.
+		clock.EXPECT().Now().Return(time.Unix(1600000000, 0))
+		gssContext.EXPECT().VerifyMIC(append([]byte{
+			// xid.
+			0x00, 0x00, 0x00, 0x7b,
+			// mtype == CALL.
+			0x00, 0x00, 0x00, 0x00,
+			// rpcvers.
+			0x00, 0x00, 0x00, 0x02,
+			// prog.
+			0x00, 0x01, 0x86, 0xa3,
+			// vers.
+			0x00, 0x00, 0x00, 0x04,
+			// proc.
+			0x00, 0x00, 0x00, 0x01,
+			// cred.flavor == RPCSEC_GSS.
+			0x00, 0x00, 0x00, 0x06,
+			// cred.body.
+			0x00, 0x00, 0x00, 0x24,
+			// version.
+			0x00, 0x00, 0x00, 0x01,
+			// gss_proc == RPCSEC_GSS_DATA.
+			0x00, 0x00, 0x00, 0x00,
+			// seq_num.
+			0x00, 0x00, 0x00, 0x01,
+			// service == rpc_gss_svc_none.
+			0x00, 0x00, 0x00, 0x01,
+			// handle.
+			0x00, 0x00, 0x00, 0x10,
+		}, handle...), []byte("Checksum")).Return(status.Error(codes.Unauthenticated, "Checksum mismatch"))
+
+		_, _, s := authenticator.AuthenticateCall(ctx, 123, &rpcv2.CallBody{
+			Rpcvers: 2,
+			Prog:    100003,
+			Vers:    4,
+			Proc:    1,
+			Cred:    newRPCSECGSSCredentials(0, 1, 1, handle),
+			Verf:    rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("Checksum")},
+		})
+		require.Equal(t, rpcv2.RPCSEC_GSS_CREDPROBLEM, s)
+	})
+
+	t.Run("DataServiceNone", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1600000000, 0))
+		gssContext.EXPECT().VerifyMIC(gomock.Any(), []byte("Checksum"))
+
+		newCtx, callHandler, s := authenticator.AuthenticateCall(ctx, 123, &rpcv2.CallBody{
+			Rpcvers: 2,
+			Prog:    100003,
+			Vers:    4,
+			Proc:    1,
+			Cred:    newRPCSECGSSCredentials(0, 1, 1, handle),
+			Verf:    rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("Checksum")},
+		})
+		require.Equal(t, rpcv2.AUTH_OK, s)
+
+		// The reply verifier should contain a checksum of the
+		// sequence number.
+		gssContext.EXPECT().GetMIC([]byte{0x00, 0x00, 0x00, 0x01}).Return([]byte("SequenceNumberChecksum"), nil)
+
+		returnValue := bytes.NewBuffer(nil)
+		replyData, replyVerifier, err := callHandler(newCtx, echoService, 4, 1, io.NopCloser(bytes.NewBufferString("Arguments")), returnValue)
+		require.NoError(t, err)
+		require.Equal(t, &rpcv2.AcceptedReplyData_SUCCESS{}, replyData)
+		require.Equal(t, rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("SequenceNumberChecksum")}, replyVerifier)
+		require.Equal(t, []byte("Results: Arguments"), returnValue.Bytes())
+	})
+
+	t.Run("DataReplay", func(t *testing.T) {
+		// Sequence numbers may not be reused.
+		clock.EXPECT().Now().Return(time.Unix(1600000000, 0))
+		gssContext.EXPECT().VerifyMIC(gomock.Any(), []byte("Checksum"))
+
+		_, _, s := authenticator.AuthenticateCall(ctx, 124, &rpcv2.CallBody{
+			Rpcvers: 2,
+			Prog:    100003,
+			Vers:    4,
+			Proc:    1,
+			Cred:    newRPCSECGSSCredentials(0, 1, 1, handle),
+			Verf:    rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("Checksum")},
+		})
+		require.Equal(t, rpcv2.RPCSEC_GSS_CREDPROBLEM, s)
+	})
+
+	t.Run("DataServiceIntegrity", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1600000000, 0))
+		gssContext.EXPECT().VerifyMIC(gomock.Any(), []byte("Checksum"))
+
+		newCtx, callHandler, s := authenticator.AuthenticateCall(ctx, 125, &rpcv2.CallBody{
+			Rpcvers: 2,
+			Prog:    100003,
+			Vers:    4,
+			Proc:    1,
+			Cred:    newRPCSECGSSCredentials(0, 3, 2, handle),
+			Verf:    rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("Checksum")},
+		})
+		require.Equal(t, rpcv2.AUTH_OK, s)
+
+		// Both the parameters and the results should be
+		// prefixed with the sequence number and be followed by
+		// a checksum.
+		gssContext.EXPECT().GetMIC([]byte{0x00, 0x00, 0x00, 0x03}).Return([]byte("SequenceNumberChecksum"), nil)
+		gssContext.EXPECT().VerifyMIC([]byte("\x00\x00\x00\x03Arguments"), []byte("ArgumentsChecksum"))
+		gssContext.EXPECT().GetMIC([]byte("\x00\x00\x00\x03Results: Arguments")).Return([]byte("ResultsChecksum"), nil)
+
+		returnValue := bytes.NewBuffer(nil)
+		replyData, replyVerifier, err := callHandler(newCtx, echoService, 4, 1, io.NopCloser(bytes.NewBuffer([]byte{
+			// databody_integ.
+			0x00, 0x00, 0x00, 0x0d,
+			0x00, 0x00, 0x00, 0x03,
+			0x41, 0x72, 0x67, 0x75,
+			0x6d, 0x65, 0x6e, 0x74,
+			0x73, 0x00, 0x00, 0x00,
+			// checksum.
+			0x00, 0x00, 0x00, 0x11,
+			0x41, 0x72, 0x67, 0x75,
+			0x6d, 0x65, 0x6e, 0x74,
+			0x73, 0x43, 0x68, 0x65,
+			0x63, 0x6b, 0x73, 0x75,
+			0x6d, 0x00, 0x00, 0x00,
+		})), returnValue)
+		require.NoError(t, err)
+		require.Equal(t, &rpcv2.AcceptedReplyData_SUCCESS{}, replyData)
+		require.Equal(t, rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("SequenceNumberChecksum")}, replyVerifier)
+		require.Equal(t, []byte{
+			// databody_integ.
+			0x00, 0x00, 0x00, 0x16,
+			0x00, 0x00, 0x00, 0x03,
+			0x52, 0x65, 0x73, 0x75,
+			0x6c, 0x74, 0x73, 0x3a,
+			0x20, 0x41, 0x72, 0x67,
+			0x75, 0x6d, 0x65, 0x6e,
+			0x74, 0x73, 0x00, 0x00,
+			// checksum.
+			0x00, 0x00, 0x00, 0x0f,
+			0x52, 0x65, 0x73, 0x75,
+			0x6c, 0x74, 0x73, 0x43,
+			0x68, 0x65, 0x63, 0x6b,
+			0x73, 0x75, 0x6d, 0x00,
+		}, returnValue.Bytes())
+	})
+
+	t.Run("DataServiceIntegrityInvalidChecksum", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1600000000, 0))
+		gssContext.EXPECT().VerifyMIC(gomock.Any(), []byte("Checksum"))
+
+		newCtx, callHandler, s := authenticator.AuthenticateCall(ctx, 126, &rpcv2.CallBody{
+			Rpcvers: 2,
+			Prog:    100003,
+			Vers:    4,
+			Proc:    1,
+			Cred:    newRPCSECGSSCredentials(0, 4, 2, handle),
+			Verf:    rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("Checksum")},
+		})
+		require.Equal(t, rpcv2.AUTH_OK, s)
+
+		gssContext.EXPECT().GetMIC([]byte{0x00, 0x00, 0x00, 0x04}).Return([]byte("SequenceNumberChecksum"), nil)
+		gssContext.EXPECT().VerifyMIC([]byte("\x00\x00\x00\x04Arguments"), []byte("ArgumentsChecksum")).
+			Return(status.Error(codes.Unauthenticated, "Checksum mismatch"))
+
+		returnValue := bytes.NewBuffer(nil)
+		replyData, replyVerifier, err := callHandler(newCtx, echoService, 4, 1, io.NopCloser(bytes.NewBuffer([]byte{
+			// databody_integ.
+			0x00, 0x00, 0x00, 0x0d,
+			0x00, 0x00, 0x00, 0x04,
+			0x41, 0x72, 0x67, 0x75,
+			0x6d, 0x65, 0x6e, 0x74,
+			0x73, 0x00, 0x00, 0x00,
+			// checksum.
+			0x00, 0x00, 0x00, 0x11,
+			0x41, 0x72, 0x67, 0x75,
+			0x6d, 0x65, 0x6e, 0x74,
+			0x73, 0x43, 0x68, 0x65,
+			0x63, 0x6b, 0x73, 0x75,
+			0x6d, 0x00, 0x00, 0x00,
+		})), returnValue)
+		require.NoError(t, err)
+		require.Equal(t, &rpcv2.AcceptedReplyData_default{Stat: rpcv2.GARBAGE_ARGS}, replyData)
+		require.Equal(t, rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("SequenceNumberChecksum")}, replyVerifier)
+		require.Empty(t, returnValue.Bytes())
+	})
+
+	t.Run("DataServicePrivacy", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1600000000, 0))
+		gssContext.EXPECT().VerifyMIC(gomock.Any(), []byte("Checksum"))
+
+		newCtx, callHandler, s := authenticator.AuthenticateCall(ctx, 127, &rpcv2.CallBody{
+			Rpcvers: 2,
+			Prog:    100003,
+			Vers:    4,
+			Proc:    1,
+			Cred:    newRPCSECGSSCredentials(0, 2, 3, handle),
+			Verf:    rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("Checksum")},
+		})
+		require.Equal(t, rpcv2.AUTH_OK, s)
+
+		// Both the parameters and the results should be
+		// prefixed with the sequence number and be encrypted.
+		gssContext.EXPECT().GetMIC([]byte{0x00, 0x00, 0x00, 0x02}).Return([]byte("SequenceNumberChecksum"), nil)
+		gssContext.EXPECT().Unwrap([]byte("EncryptedArguments")).Return([]byte("\x00\x00\x00\x02Arguments"), nil)
+		gssContext.EXPECT().Wrap([]byte("\x00\x00\x00\x02Results: Arguments")).Return([]byte("EncryptedResults"), nil)
+
+		returnValue := bytes.NewBuffer(nil)
+		replyData, replyVerifier, err := callHandler(newCtx, echoService, 4, 1, io.NopCloser(bytes.NewBuffer([]byte{
+			// databody_priv.
+			0x00, 0x00, 0x00, 0x12,
+			0x45, 0x6e, 0x63, 0x72,
+			0x79, 0x70, 0x74, 0x65,
+			0x64, 0x41, 0x72, 0x67,
+			0x75, 0x6d, 0x65, 0x6e,
+			0x74, 0x73, 0x00, 0x00,
+		})), returnValue)
+		require.NoError(t, err)
+		require.Equal(t, &rpcv2.AcceptedReplyData_SUCCESS{}, replyData)
+		require.Equal(t, rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("SequenceNumberChecksum")}, replyVerifier)
+		require.Equal(t, []byte{
+			// databody_priv.
+			0x00, 0x00, 0x00, 0x10,
+			0x45, 0x6e, 0x63, 0x72,
+			0x79, 0x70, 0x74, 0x65,
+			0x64, 0x52, 0x65, 0x73,
+			0x75, 0x6c, 0x74, 0x73,
+		}, returnValue.Bytes())
+	})
+
+	t.Run("DataSequenceNumberMismatch", func(t *testing.T) {
+		// The sequence number in the protected body must match
+		// the one in the credentials.
+		clock.EXPECT().Now().Return(time.Unix(1600000000, 0))
+		gssContext.EXPECT().VerifyMIC(gomock.Any(), []byte("Checksum"))
+
+		newCtx, callHandler, s := authenticator.AuthenticateCall(ctx, 128, &rpcv2.CallBody{
+			Rpcvers: 2,
+			Prog:    100003,
+			Vers:    4,
+			Proc:    1,
+			Cred:    newRPCSECGSSCredentials(0, 5, 3, handle),
+			Verf:    rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("Checksum")},
+		})
+		require.Equal(t, rpcv2.AUTH_OK, s)
+
+		gssContext.EXPECT().GetMIC([]byte{0x00, 0x00, 0x00, 0x05}).Return([]byte("SequenceNumberChecksum"), nil)
+		gssContext.EXPECT().Unwrap([]byte("EncryptedArguments")).Return([]byte("\x00\x00\x00\x02Arguments"), nil)
+
+		returnValue := bytes.NewBuffer(nil)
+		replyData, _, err := callHandler(newCtx, echoService, 4, 1, io.NopCloser(bytes.NewBuffer([]byte{
+			// databody_priv.
+			0x00, 0x00, 0x00, 0x12,
+			0x45, 0x6e, 0x63, 0x72,
+			0x79, 0x70, 0x74, 0x65,
+			0x64, 0x41, 0x72, 0x67,
+			0x75, 0x6d, 0x65, 0x6e,
+			0x74, 0x73, 0x00, 0x00,
+		})), returnValue)
+		require.NoError(t, err)
+		require.Equal(t, &rpcv2.AcceptedReplyData_default{Stat: rpcv2.GARBAGE_ARGS}, replyData)
+		require.Empty(t, returnValue.Bytes())
+	})
+
+	t.Run("DataContextExpired", func(t *testing.T) {
+		// Clients should be instructed to establish a new
+		// security context once the current one expires.
+		clock.EXPECT().Now().Return(time.Unix(1700000000, 0))
+
+		_, _, s := authenticator.AuthenticateCall(ctx, 129, &rpcv2.CallBody{
+			Rpcvers: 2,
+			Prog:    100003,
+			Vers:    4,
+			Proc:    1,
+			Cred:    newRPCSECGSSCredentials(0, 6, 1, handle),
+			Verf:    rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("Checksum")},
+		})
+		require.Equal(t, rpcv2.RPCSEC_GSS_CTXPROBLEM, s)
+	})
+
+	t.Run("Destroy", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1600000000, 0))
+		gssContext.EXPECT().VerifyMIC(gomock.Any(), []byte("Checksum"))
+
+		_, callHandler, s := authenticator.AuthenticateCall(ctx, 130, &rpcv2.CallBody{
+			Rpcvers: 2,
+			Prog:    100003,
+			Vers:    4,
+			Proc:    0,
+			Cred:    newRPCSECGSSCredentials(3, 7, 1, handle),
+			Verf:    rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("Checksum")},
+		})
+		require.Equal(t, rpcv2.AUTH_OK, s)
+
+		gssContext.EXPECT().GetMIC([]byte{0x00, 0x00, 0x00, 0x07}).Return([]byte("SequenceNumberChecksum"), nil)
+
+		returnValue := bytes.NewBuffer(nil)
+		replyData, replyVerifier, err := callHandler(ctx, nil, 4, 0, io.NopCloser(bytes.NewBuffer(nil)), returnValue)
+		require.NoError(t, err)
+		require.Equal(t, &rpcv2.AcceptedReplyData_SUCCESS{}, replyData)
+		require.Equal(t, rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("SequenceNumberChecksum")}, replyVerifier)
+		require.Empty(t, returnValue.Bytes())
+
+		// The security context should no longer be usable.
+		_, _, s = authenticator.AuthenticateCall(ctx, 131, &rpcv2.CallBody{
+			Rpcvers: 2,
+			Prog:    100003,
+			Vers:    4,
+			Proc:    1,
+			Cred:    newRPCSECGSSCredentials(0, 8, 1, handle),
+			Verf:    rpcv2.OpaqueAuth{Flavor: rpcv2.RPCSEC_GSS, Body: []byte("Checksum")},
+		})
+		require.Equal(t, rpcv2.RPCSEC_GSS_CREDPROBLEM, s)
+	})
+}
@@ -0,0 +1,173 @@
+package virtual
+
+import (
+	"context"
+	"math/bits"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Operation against the virtual file system for which statistics are
+// gathered by OperationStatistics.
+type Operation int
+
+const (
+	// OperationLookup corresponds to Directory.VirtualLookup().
+	OperationLookup Operation = iota
+	// OperationGetAttributes corresponds to Node.VirtualGetAttributes().
+	OperationGetAttributes
+	// OperationRead corresponds to Leaf.VirtualRead().
+	OperationRead
+	// OperationReadDir corresponds to Directory.VirtualReadDir().
+	OperationReadDir
+
+	operationCount
+)
+
+// operationHistogram keeps track of the number of times an operation
+// was performed, and how long it took. Latencies are stored in buckets
+// whose index corresponds to the number of bits needed to represent
+// the latency in nanoseconds.
+type operationHistogram struct {
+	count           uint64
+	totalDuration   time.Duration
+	maximumDuration time.Duration
+	buckets         [64]uint64
+}
+
+func (h *operationHistogram) record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	h.count++
+	h.totalDuration += d
+	if h.maximumDuration < d {
+		h.maximumDuration = d
+	}
+	h.buckets[bits.Len64(uint64(d))]++
+}
+
+// getPercentile returns an upper bound of the latency of an operation
+// at a given percentile.
+func (h *operationHistogram) getPercentile(percentile uint64) time.Duration {
+	rank := (h.count*percentile + 99) / 100
+	seen := uint64(0)
+	for bucket, count := range h.buckets {
+		seen += count
+		if seen >= rank {
+			if upperBound := time.Duration(uint64(1)<<bucket - 1); upperBound < h.maximumDuration {
+				return upperBound
+			}
+			break
+		}
+	}
+	return h.maximumDuration
+}
+
+func (h *operationHistogram) getResourceUsage() *resourceusage.VirtualFileSystemResourceUsage_Operation {
+	if h.count == 0 {
+		return nil
+	}
+	return &resourceusage.VirtualFileSystemResourceUsage_Operation{
+		Count:           h.count,
+		TotalDuration:   durationpb.New(h.totalDuration),
+		P50Duration:     durationpb.New(h.getPercentile(50)),
+		P90Duration:     durationpb.New(h.getPercentile(90)),
+		P99Duration:     durationpb.New(h.getPercentile(99)),
+		MaximumDuration: durationpb.New(h.maximumDuration),
+	}
+}
+
+// OperationStatistics gathers the number of times operations are
+// performed against the virtual file system, and how long they take.
+// An instance is typically created for every build action, so that the
+// file system chatter of individual actions can be reported.
+//
+// Methods may be called on a nil instance, in which case no statistics
+// are gathered.
+type OperationStatistics struct {
+	clock clock.Clock
+
+	lock       sync.Mutex
+	operations [operationCount]operationHistogram
+}
+
+// NewOperationStatistics creates an OperationStatistics object that
+// has not recorded any operations yet.
+func NewOperationStatistics(clock clock.Clock) *OperationStatistics {
+	return &OperationStatistics{
+		clock: clock,
+	}
+}
+
+// Start measuring the duration of an operation. Finish() needs to be
+// called on the returned timer when the operation completes.
+func (stats *OperationStatistics) Start(operation Operation) OperationTimer {
+	if stats == nil {
+		return OperationTimer{}
+	}
+	return OperationTimer{
+		statistics: stats,
+		operation:  operation,
+		start:      stats.clock.Now(),
+	}
+}
+
+// GetResourceUsage returns the statistics of all operations recorded
+// so far, so that they may be attached to the execution metadata of a
+// build action.
+func (stats *OperationStatistics) GetResourceUsage() *resourceusage.VirtualFileSystemResourceUsage {
+	stats.lock.Lock()
+	defer stats.lock.Unlock()
+
+	return &resourceusage.VirtualFileSystemResourceUsage{
+		Lookup:        stats.operations[OperationLookup].getResourceUsage(),
+		GetAttributes: stats.operations[OperationGetAttributes].getResourceUsage(),
+		Read:          stats.operations[OperationRead].getResourceUsage(),
+		ReadDir:       stats.operations[OperationReadDir].getResourceUsage(),
+	}
+}
+
+// OperationTimer is returned by OperationStatistics.Start() to measure
+// the duration of a single operation.
+type OperationTimer struct {
+	statistics *OperationStatistics
+	operation  Operation
+	start      time.Time
+}
+
+// Finish measuring the duration of an operation, and record it.
+func (t OperationTimer) Finish() {
+	if stats := t.statistics; stats != nil {
+		d := stats.clock.Now().Sub(t.start)
+		stats.lock.Lock()
+		stats.operations[t.operation].record(d)
+		stats.lock.Unlock()
+	}
+}
+
+// operationStatisticsNativeLeaf is a decorator for NativeLeaf that
+// records the duration of operations against files in an instance of
+// OperationStatistics.
+type operationStatisticsNativeLeaf struct {
+	NativeLeaf
+	statistics *OperationStatistics
+}
+
+func (l *operationStatisticsNativeLeaf) VirtualGetAttributes(ctx context.Context, requested AttributesMask, attributes *Attributes) {
+	timer := l.statistics.Start(OperationGetAttributes)
+	l.NativeLeaf.VirtualGetAttributes(ctx, requested, attributes)
+	timer.Finish()
+}
+
+func (l *operationStatisticsNativeLeaf) VirtualRead(buf []byte, off uint64) (int, bool, Status) {
+	timer := l.statistics.Start(OperationRead)
+	n, eof, s := l.NativeLeaf.VirtualRead(buf, off)
+	timer.Finish()
+	return n, eof, s
+}
@@ -0,0 +1,29 @@
+package virtual
+
+import (
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+type operationStatisticsCASFileFactory struct {
+	base       CASFileFactory
+	statistics *OperationStatistics
+}
+
+// NewOperationStatisticsCASFileFactory creates a decorator for
+// CASFileFactory that records the duration of operations against the
+// files it creates in an instance of OperationStatistics.
+func NewOperationStatisticsCASFileFactory(base CASFileFactory, statistics *OperationStatistics) CASFileFactory {
+	return &operationStatisticsCASFileFactory{
+		base:       base,
+		statistics: statistics,
+	}
+}
+
+func (cff *operationStatisticsCASFileFactory) LookupFile(blobDigest digest.Digest, isExecutable bool, lastDataModificationTime time.Time, readMonitor FileReadMonitor) NativeLeaf {
+	return &operationStatisticsNativeLeaf{
+		NativeLeaf: cff.base.LookupFile(blobDigest, isExecutable, lastDataModificationTime, readMonitor),
+		statistics: cff.statistics,
+	}
+}
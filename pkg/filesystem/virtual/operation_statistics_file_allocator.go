@@ -0,0 +1,27 @@
+package virtual
+
+type operationStatisticsFileAllocator struct {
+	base       FileAllocator
+	statistics *OperationStatistics
+}
+
+// NewOperationStatisticsFileAllocator creates a decorator for
+// FileAllocator that records the duration of operations against the
+// files it creates in an instance of OperationStatistics.
+func NewOperationStatisticsFileAllocator(base FileAllocator, statistics *OperationStatistics) FileAllocator {
+	return &operationStatisticsFileAllocator{
+		base:       base,
+		statistics: statistics,
+	}
+}
+
+func (fa *operationStatisticsFileAllocator) NewFile(isExecutable bool, size uint64, shareAccess ShareMask) (NativeLeaf, Status) {
+	leaf, s := fa.base.NewFile(isExecutable, size, shareAccess)
+	if s != StatusOK {
+		return nil, s
+	}
+	return &operationStatisticsNativeLeaf{
+		NativeLeaf: leaf,
+		statistics: fa.statistics,
+	}, StatusOK
+}
@@ -0,0 +1,127 @@
+package virtual_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestOperationStatistics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	clock := mock.NewMockClock(ctrl)
+	statistics := virtual.NewOperationStatistics(clock)
+
+	t.Run("Empty", func(t *testing.T) {
+		// Operations that were never performed should not be
+		// reported.
+		testutil.RequireEqualProto(t, &resourceusage.VirtualFileSystemResourceUsage{}, statistics.GetResourceUsage())
+	})
+
+	t.Run("Percentiles", func(t *testing.T) {
+		// Perform 100 lookups, of which 98 take 1µs, one takes
+		// 1ms and one takes 3ms.
+		for i := 0; i < 100; i++ {
+			d := time.Microsecond
+			if i == 50 {
+				d = time.Millisecond
+			} else if i == 51 {
+				d = 3 * time.Millisecond
+			}
+			clock.EXPECT().Now().Return(time.Unix(1000, 0))
+			timer := statistics.Start(virtual.OperationLookup)
+			clock.EXPECT().Now().Return(time.Unix(1000, 0).Add(d))
+			timer.Finish()
+		}
+
+		// Percentiles are rounded up to the upper bound of the
+		// bucket containing them, but never exceed the maximum.
+		testutil.RequireEqualProto(t, &resourceusage.VirtualFileSystemResourceUsage{
+			Lookup: &resourceusage.VirtualFileSystemResourceUsage_Operation{
+				Count:           100,
+				TotalDuration:   durationpb.New(98*time.Microsecond + 4*time.Millisecond),
+				P50Duration:     durationpb.New(1023 * time.Nanosecond),
+				P90Duration:     durationpb.New(1023 * time.Nanosecond),
+				P99Duration:     durationpb.New(1048575 * time.Nanosecond),
+				MaximumDuration: durationpb.New(3 * time.Millisecond),
+			},
+		}, statistics.GetResourceUsage())
+	})
+
+	t.Run("NilInstance", func(t *testing.T) {
+		// Calling into a nil instance should have no effect.
+		var nilStatistics *virtual.OperationStatistics
+		nilStatistics.Start(virtual.OperationRead).Finish()
+	})
+}
+
+func TestOperationStatisticsFileAllocator(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseFileAllocator := mock.NewMockFileAllocator(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	statistics := virtual.NewOperationStatistics(clock)
+	fileAllocator := virtual.NewOperationStatisticsFileAllocator(baseFileAllocator, statistics)
+
+	t.Run("Failure", func(t *testing.T) {
+		baseFileAllocator.EXPECT().NewFile(false, uint64(0), virtual.ShareMaskWrite).
+			Return(nil, virtual.StatusErrIO)
+
+		_, s := fileAllocator.NewFile(false, 0, virtual.ShareMaskWrite)
+		require.Equal(t, virtual.StatusErrIO, s)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Reads and requests for attributes against the file
+		// should be recorded.
+		baseLeaf := mock.NewMockNativeLeaf(ctrl)
+		baseFileAllocator.EXPECT().NewFile(true, uint64(5), virtual.ShareMaskWrite).
+			Return(baseLeaf, virtual.StatusOK)
+
+		leaf, s := fileAllocator.NewFile(true, 5, virtual.ShareMaskWrite)
+		require.Equal(t, virtual.StatusOK, s)
+
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		baseLeaf.EXPECT().VirtualRead(gomock.Len(5), uint64(0)).Return(5, true, virtual.StatusOK)
+		clock.EXPECT().Now().Return(time.Unix(1000, 2000))
+		var buf [5]byte
+		n, eof, s := leaf.VirtualRead(buf[:], 0)
+		require.Equal(t, virtual.StatusOK, s)
+		require.Equal(t, 5, n)
+		require.True(t, eof)
+
+		clock.EXPECT().Now().Return(time.Unix(1001, 0))
+		baseLeaf.EXPECT().VirtualGetAttributes(ctx, virtual.AttributesMaskSizeBytes, gomock.Any())
+		clock.EXPECT().Now().Return(time.Unix(1001, 100))
+		var attributes virtual.Attributes
+		leaf.VirtualGetAttributes(ctx, virtual.AttributesMaskSizeBytes, &attributes)
+
+		testutil.RequireEqualProto(t, &resourceusage.VirtualFileSystemResourceUsage{
+			GetAttributes: &resourceusage.VirtualFileSystemResourceUsage_Operation{
+				Count:           1,
+				TotalDuration:   durationpb.New(100 * time.Nanosecond),
+				P50Duration:     durationpb.New(100 * time.Nanosecond),
+				P90Duration:     durationpb.New(100 * time.Nanosecond),
+				P99Duration:     durationpb.New(100 * time.Nanosecond),
+				MaximumDuration: durationpb.New(100 * time.Nanosecond),
+			},
+			Read: &resourceusage.VirtualFileSystemResourceUsage_Operation{
+				Count:           1,
+				TotalDuration:   durationpb.New(2 * time.Microsecond),
+				P50Duration:     durationpb.New(2 * time.Microsecond),
+				P90Duration:     durationpb.New(2 * time.Microsecond),
+				P99Duration:     durationpb.New(2 * time.Microsecond),
+				MaximumDuration: durationpb.New(2 * time.Microsecond),
+			},
+		}, statistics.GetResourceUsage())
+	})
+}
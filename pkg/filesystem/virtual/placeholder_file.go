@@ -2,6 +2,7 @@ package virtual
 
 import (
 	"context"
+	"os"
 
 	"github.com/buildbarn/bb-storage/pkg/blobstore"
 	"github.com/buildbarn/bb-storage/pkg/digest"
@@ -36,8 +37,20 @@ func (placeholderFile) VirtualAllocate(off, size uint64) Status {
 	return StatusErrWrongType
 }
 
+func (placeholderFile) VirtualDeallocate(off, size uint64) Status {
+	return StatusErrWrongType
+}
+
 func (placeholderFile) VirtualClose(shareAccess ShareMask) {}
 
+func (placeholderFile) VirtualSync() Status {
+	return StatusOK
+}
+
+func (placeholderFile) VirtualOpenPassthroughFile() *os.File {
+	return nil
+}
+
 func (placeholderFile) VirtualOpenSelf(ctx context.Context, shareAccess ShareMask, options *OpenExistingOptions, requested AttributesMask, attributes *Attributes) Status {
 	// Even though this file may not necessarily be a symbolic link,
 	// the NFSv4 specification requires that NFS4ERR_SYMLINK is
@@ -56,3 +69,21 @@ func (placeholderFile) VirtualSeek(offset uint64, regionType filesystem.RegionTy
 func (placeholderFile) VirtualWrite(buf []byte, off uint64) (int, Status) {
 	panic("Request to write to symbolic link should have been intercepted")
 }
+
+func (placeholderFile) VirtualGetXAttr(name string) ([]byte, Status) {
+	return nil, StatusErrNoXAttr
+}
+
+func (placeholderFile) VirtualListXAttrs() []string {
+	return nil
+}
+
+func (placeholderFile) VirtualSetXAttr(name string, value []byte, flags XAttrSetFlags) Status {
+	// Linux only permits setting extended attributes in the "user."
+	// namespace on regular files and directories.
+	return StatusErrPerm
+}
+
+func (placeholderFile) VirtualRemoveXAttr(name string) Status {
+	return StatusErrNoXAttr
+}
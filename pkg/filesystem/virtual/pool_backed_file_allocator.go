@@ -4,10 +4,13 @@ import (
 	"context"
 	"io"
 	"math"
+	"os"
+	"sort"
 	"sync"
 	"syscall"
 
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_clock "github.com/buildbarn/bb-remote-execution/pkg/clock"
 	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/outputpathpersistency"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
@@ -33,11 +36,23 @@ var (
 			Name:      "pool_backed_file_allocator_uploads_with_writable_descriptors_total",
 			Help:      "Total number times the contents of a pool-backed file were uploaded into the Content Addressable Storage while one or more writable file descriptors were present.",
 		})
+	poolBackedFileAllocatorEagerUploads = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "virtual",
+			Name:      "pool_backed_file_allocator_eager_uploads_total",
+			Help:      "Total number of times the contents of a pool-backed file were uploaded into the Content Addressable Storage after the last writable file descriptor was closed.",
+		},
+		[]string{"result"})
+	poolBackedFileAllocatorEagerUploadsSucceeded = poolBackedFileAllocatorEagerUploads.WithLabelValues("Succeeded")
+	poolBackedFileAllocatorEagerUploadsFailed    = poolBackedFileAllocatorEagerUploads.WithLabelValues("Failed")
+	poolBackedFileAllocatorEagerUploadsReused    = poolBackedFileAllocatorEagerUploads.WithLabelValues("Reused")
 )
 
 type poolBackedFileAllocator struct {
-	pool        re_filesystem.FilePool
-	errorLogger util.ErrorLogger
+	pool              re_filesystem.FilePool
+	errorLogger       util.ErrorLogger
+	eagerFileUploader *EagerFileUploader
 }
 
 // NewPoolBackedFileAllocator creates an allocator for a leaf node that
@@ -49,14 +64,20 @@ type poolBackedFileAllocator struct {
 // file descriptor count reach zero), Close() is called on the
 // underlying backing file descriptor. This may be used to request
 // deletion from underlying storage.
-func NewPoolBackedFileAllocator(pool re_filesystem.FilePool, errorLogger util.ErrorLogger) FileAllocator {
+//
+// If an EagerFileUploader is provided, files are uploaded into the
+// Content Addressable Storage in the background as soon as the last
+// writable file descriptor is closed.
+func NewPoolBackedFileAllocator(pool re_filesystem.FilePool, errorLogger util.ErrorLogger, eagerFileUploader *EagerFileUploader) FileAllocator {
 	poolBackedFileAllocatorPrometheusMetrics.Do(func() {
 		prometheus.MustRegister(poolBackedFileAllocatorUploadsWithWritableDescriptors)
+		prometheus.MustRegister(poolBackedFileAllocatorEagerUploads)
 	})
 
 	return &poolBackedFileAllocator{
-		pool:        pool,
-		errorLogger: errorLogger,
+		pool:              pool,
+		errorLogger:       errorLogger,
+		eagerFileUploader: eagerFileUploader,
 	}
 }
 
@@ -74,7 +95,8 @@ func (fa *poolBackedFileAllocator) NewFile(isExecutable bool, size uint64, share
 		}
 	}
 	f := &fileBackedFile{
-		errorLogger: fa.errorLogger,
+		errorLogger:       fa.errorLogger,
+		eagerFileUploader: fa.eagerFileUploader,
 
 		file:           file,
 		isExecutable:   isExecutable,
@@ -82,13 +104,15 @@ func (fa *poolBackedFileAllocator) NewFile(isExecutable bool, size uint64, share
 		referenceCount: 1,
 		unfreezeWakeup: make(chan struct{}),
 		cachedDigest:   digest.BadDigest,
+		uploadedDigest: digest.BadDigest,
 	}
 	f.acquireShareAccessLocked(shareAccess)
 	return f, StatusOK
 }
 
 type fileBackedFile struct {
-	errorLogger util.ErrorLogger
+	errorLogger       util.ErrorLogger
+	eagerFileUploader *EagerFileUploader
 
 	lock                     sync.RWMutex
 	file                     filesystem.FileReadWriter
@@ -100,6 +124,13 @@ type fileBackedFile struct {
 	unfreezeWakeup           chan struct{}
 	cachedDigest             digest.Digest
 	changeID                 uint64
+	xattrs                   map[string][]byte
+
+	// The digest of the file at the time it was last uploaded
+	// eagerly, and a channel that is closed when an eager upload
+	// that is in progress completes.
+	uploadedDigest  digest.Digest
+	eagerUploadDone chan struct{}
 }
 
 // lockMutatingData picks up the exclusive lock of the file and waits
@@ -213,7 +244,70 @@ func (f *fileBackedFile) updateCachedDigest(digestFunction digest.Function) (dig
 	return newDigest, nil
 }
 
+// uploadEagerly uploads the contents of the file into the Content
+// Addressable Storage in the background. It is called after the last
+// writable file descriptor of the file is closed.
+func (f *fileBackedFile) uploadEagerly(done chan<- struct{}) {
+	uploadedDigest := f.tryUploadEagerly()
+
+	f.lock.Lock()
+	if uploadedDigest != digest.BadDigest {
+		f.uploadedDigest = uploadedDigest
+	}
+	f.eagerUploadDone = nil
+	f.lock.Unlock()
+	close(done)
+}
+
+func (f *fileBackedFile) tryUploadEagerly() digest.Digest {
+	u := f.eagerFileUploader
+	if err := u.semaphore.Acquire(u.ctx, 1); err != nil {
+		return digest.BadDigest
+	}
+	defer u.semaphore.Release(1)
+
+	hasWritableDescriptors, success := f.acquireFrozenDescriptor()
+	if !success {
+		return digest.BadDigest
+	}
+	if hasWritableDescriptors {
+		// The file got reopened for writing while waiting for
+		// the semaphore. Leave uploading to UploadFile().
+		f.Close()
+		return digest.BadDigest
+	}
+
+	blobDigest, err := f.updateCachedDigest(u.digestFunction)
+	if err != nil {
+		f.Close()
+		poolBackedFileAllocatorEagerUploadsFailed.Inc()
+		return digest.BadDigest
+	}
+	if err := u.contentAddressableStorage.Put(
+		u.ctx,
+		blobDigest,
+		buffer.NewValidatedBufferFromReaderAt(f, blobDigest.GetSizeBytes())); err != nil {
+		poolBackedFileAllocatorEagerUploadsFailed.Inc()
+		return digest.BadDigest
+	}
+	poolBackedFileAllocatorEagerUploadsSucceeded.Inc()
+	return blobDigest
+}
+
 func (f *fileBackedFile) UploadFile(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, digestFunction digest.Function) (digest.Digest, error) {
+	// If the file is being uploaded eagerly, wait for that to
+	// complete, so that its results may be reused.
+	f.lock.RLock()
+	eagerUploadDone := f.eagerUploadDone
+	f.lock.RUnlock()
+	if eagerUploadDone != nil {
+		select {
+		case <-eagerUploadDone:
+		case <-ctx.Done():
+			return digest.BadDigest, util.StatusFromContext(ctx)
+		}
+	}
+
 	// Create a file handle that temporarily freezes the contents of
 	// this file. This ensures that the file's contents don't change
 	// between the digest computation and upload phase. This allows
@@ -234,12 +328,25 @@ func (f *fileBackedFile) UploadFile(ctx context.Context, contentAddressableStora
 		poolBackedFileAllocatorUploadsWithWritableDescriptors.Inc()
 	}
 
+	hashingMeasurement := re_clock.StartPhase(ctx, re_clock.PhaseOutputHashing)
 	blobDigest, err := f.updateCachedDigest(digestFunction)
+	hashingMeasurement.Finish()
 	if err != nil {
 		f.Close()
 		return digest.BadDigest, err
 	}
 
+	// Skip the upload if the file's current contents were already
+	// uploaded eagerly.
+	f.lock.RLock()
+	uploadedDigest := f.uploadedDigest
+	f.lock.RUnlock()
+	if blobDigest == uploadedDigest {
+		f.Close()
+		poolBackedFileAllocatorEagerUploadsReused.Inc()
+		return blobDigest, nil
+	}
+
 	if err := contentAddressableStorage.Put(
 		ctx,
 		blobDigest,
@@ -335,6 +442,53 @@ func (f *fileBackedFile) VirtualAllocate(off, size uint64) Status {
 	return StatusOK
 }
 
+func (f *fileBackedFile) VirtualDeallocate(off, size uint64) Status {
+	f.lockMutatingData()
+	defer f.lock.Unlock()
+
+	// Only the part of the range that lies within the file needs
+	// to be released, as the size of the file remains unaltered.
+	end := off + size
+	if end < off || end > f.size {
+		end = f.size
+	}
+	if off >= end {
+		return StatusOK
+	}
+
+	err := re_filesystem.DeallocateFile(f.file, int64(off), int64(end-off))
+	if status.Code(err) == codes.Unimplemented {
+		// The file pool does not support releasing storage.
+		// Overwrite the range with zero bytes instead, so that
+		// the outcome observed by the caller is identical.
+		err = f.zeroRange(off, end)
+	}
+	if err != nil {
+		f.errorLogger.Log(util.StatusWrapf(err, "Failed to deallocate %d bytes at offset %d", end-off, off))
+		return StatusErrIO
+	}
+	f.cachedDigest = digest.BadDigest
+	f.changeID++
+	return StatusOK
+}
+
+// zeroRange overwrites a range of the file with zero bytes.
+func (f *fileBackedFile) zeroRange(off, end uint64) error {
+	var zeroes [64 * 1024]byte
+	for off < end {
+		chunk := zeroes[:]
+		if remaining := end - off; remaining < uint64(len(chunk)) {
+			chunk = chunk[:remaining]
+		}
+		n, err := f.file.WriteAt(chunk, int64(off))
+		if err != nil {
+			return err
+		}
+		off += uint64(n)
+	}
+	return nil
+}
+
 // virtualGetAttributesUnlocked gets file attributes that can be
 // obtained without picking up any locks.
 func (f *fileBackedFile) virtualGetAttributesUnlocked(attributes *Attributes) {
@@ -429,6 +583,13 @@ func (f *fileBackedFile) VirtualReadlink(ctx context.Context) ([]byte, Status) {
 	return nil, StatusErrInval
 }
 
+func (f *fileBackedFile) VirtualOpenPassthroughFile() *os.File {
+	// Files stored in the file pool are mutable. Letting the kernel
+	// access them directly would bypass the bookkeeping performed
+	// by this type.
+	return nil
+}
+
 func (f *fileBackedFile) VirtualClose(shareAccess ShareMask) {
 	f.lock.Lock()
 	defer f.lock.Unlock()
@@ -438,10 +599,37 @@ func (f *fileBackedFile) VirtualClose(shareAccess ShareMask) {
 			panic("Invalid writable descriptor count")
 		}
 		f.writableDescriptorsCount--
+
+		// Start uploading the file eagerly if this was the last
+		// writable file descriptor, and the file remains
+		// reachable afterwards.
+		if f.writableDescriptorsCount == 0 &&
+			f.eagerFileUploader != nil &&
+			f.eagerUploadDone == nil &&
+			f.referenceCount > shareAccess.Count() &&
+			(f.cachedDigest == digest.BadDigest || f.cachedDigest != f.uploadedDigest) {
+			eagerUploadDone := make(chan struct{})
+			f.eagerUploadDone = eagerUploadDone
+			go f.uploadEagerly(eagerUploadDone)
+		}
 	}
 	f.releaseReferencesLocked(shareAccess.Count())
 }
 
+func (f *fileBackedFile) VirtualSync() Status {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.referenceCount == 0 {
+		return StatusErrStale
+	}
+	if err := f.file.Sync(); err != nil {
+		f.errorLogger.Log(util.StatusWrap(err, "Failed to synchronize file"))
+		return StatusErrIO
+	}
+	return StatusOK
+}
+
 func (f *fileBackedFile) virtualTruncate(size uint64) Status {
 	if err := f.file.Truncate(int64(size)); err != nil {
 		f.errorLogger.Log(util.StatusWrapf(err, "Failed to truncate file to length %d", size))
@@ -477,6 +665,64 @@ func (f *fileBackedFile) VirtualSetAttributes(ctx context.Context, in *Attribute
 	return StatusOK
 }
 
+func (f *fileBackedFile) VirtualGetXAttr(name string) ([]byte, Status) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	if value, ok := f.xattrs[name]; ok {
+		return value, StatusOK
+	}
+	return nil, StatusErrNoXAttr
+}
+
+func (f *fileBackedFile) VirtualListXAttrs() []string {
+	f.lock.RLock()
+	names := make([]string, 0, len(f.xattrs))
+	for name := range f.xattrs {
+		names = append(names, name)
+	}
+	f.lock.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
+
+func (f *fileBackedFile) VirtualSetXAttr(name string, value []byte, flags XAttrSetFlags) Status {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if _, ok := f.xattrs[name]; ok {
+		if flags&XAttrSetFlagsCreate != 0 {
+			return StatusErrExist
+		}
+	} else {
+		if flags&XAttrSetFlagsReplace != 0 {
+			return StatusErrNoXAttr
+		}
+		if f.xattrs == nil {
+			f.xattrs = map[string][]byte{}
+		}
+	}
+
+	// The value may be backed by a buffer that is reused by the
+	// caller. Store a copy.
+	f.xattrs[name] = append([]byte{}, value...)
+	f.changeID++
+	return StatusOK
+}
+
+func (f *fileBackedFile) VirtualRemoveXAttr(name string) Status {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if _, ok := f.xattrs[name]; !ok {
+		return StatusErrNoXAttr
+	}
+	delete(f.xattrs, name)
+	f.changeID++
+	return StatusOK
+}
+
 func (f *fileBackedFile) VirtualWrite(buf []byte, offset uint64) (int, Status) {
 	f.lockMutatingData()
 	defer f.lock.Unlock()
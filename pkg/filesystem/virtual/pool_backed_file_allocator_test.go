@@ -8,6 +8,7 @@ import (
 
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
 	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/outputpathpersistency"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
@@ -19,6 +20,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
+	"golang.org/x/sync/semaphore"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -32,7 +34,7 @@ func TestPoolBackedFileAllocatorGetOutputServiceFileStatus(t *testing.T) {
 	pool.EXPECT().NewFile().Return(underlyingFile, nil)
 	errorLogger := mock.NewMockErrorLogger(ctrl)
 
-	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger).
+	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger, nil).
 		NewFile(false, 0, virtual.ShareMaskRead|virtual.ShareMaskWrite)
 	require.Equal(t, virtual.StatusOK, s)
 
@@ -184,7 +186,7 @@ func TestPoolBackedFileAllocatorVirtualSeek(t *testing.T) {
 	pool.EXPECT().NewFile().Return(underlyingFile, nil)
 	errorLogger := mock.NewMockErrorLogger(ctrl)
 
-	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger).
+	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger, nil).
 		NewFile(false, 0, virtual.ShareMaskRead|virtual.ShareMaskWrite)
 	require.Equal(t, virtual.StatusOK, s)
 
@@ -252,7 +254,7 @@ func TestPoolBackedFileAllocatorVirtualOpenSelfStaleAfterUnlink(t *testing.T) {
 	underlyingFile.EXPECT().Close()
 	errorLogger := mock.NewMockErrorLogger(ctrl)
 
-	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger).
+	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger, nil).
 		NewFile(false, 0, virtual.ShareMaskWrite)
 	require.Equal(t, virtual.StatusOK, s)
 
@@ -277,7 +279,7 @@ func TestPoolBackedFileAllocatorVirtualOpenSelfStaleAfterClose(t *testing.T) {
 	underlyingFile.EXPECT().Close()
 	errorLogger := mock.NewMockErrorLogger(ctrl)
 
-	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger).
+	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger, nil).
 		NewFile(false, 0, virtual.ShareMaskWrite)
 	require.Equal(t, virtual.StatusOK, s)
 
@@ -298,7 +300,7 @@ func TestPoolBackedFileAllocatorVirtualRead(t *testing.T) {
 	pool.EXPECT().NewFile().Return(underlyingFile, nil)
 	errorLogger := mock.NewMockErrorLogger(ctrl)
 
-	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger).
+	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger, nil).
 		NewFile(false, 0, virtual.ShareMaskRead|virtual.ShareMaskWrite)
 	require.Equal(t, virtual.StatusOK, s)
 
@@ -377,7 +379,7 @@ func TestPoolBackedFileAllocatorFUSETruncateFailure(t *testing.T) {
 	errorLogger := mock.NewMockErrorLogger(ctrl)
 	errorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.Unavailable, "Failed to truncate file to length 42: Storage backends offline")))
 
-	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger).
+	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger, nil).
 		NewFile(false, 0, virtual.ShareMaskWrite)
 	require.Equal(t, virtual.StatusOK, s)
 
@@ -405,7 +407,7 @@ func TestPoolBackedFileAllocatorVirtualWriteFailure(t *testing.T) {
 	errorLogger := mock.NewMockErrorLogger(ctrl)
 	errorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.Unavailable, "Failed to write to file at offset 42: Storage backends offline")))
 
-	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger).
+	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger, nil).
 		NewFile(false, 0, virtual.ShareMaskWrite)
 	require.Equal(t, virtual.StatusOK, s)
 	_, s = f.VirtualWrite(p[:], 42)
@@ -414,6 +416,80 @@ func TestPoolBackedFileAllocatorVirtualWriteFailure(t *testing.T) {
 	f.Unlink()
 }
 
+func TestPoolBackedFileAllocatorVirtualSyncFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	// Errors that occur while flushing writes that are buffered by
+	// the file pool should be reported to the caller of fsync().
+	pool := mock.NewMockFilePool(ctrl)
+	underlyingFile := mock.NewMockFileReadWriter(ctrl)
+	pool.EXPECT().NewFile().Return(underlyingFile, nil)
+	underlyingFile.EXPECT().Sync().Return(status.Error(codes.ResourceExhausted, "Out of space"))
+	underlyingFile.EXPECT().Close()
+
+	errorLogger := mock.NewMockErrorLogger(ctrl)
+	errorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.ResourceExhausted, "Failed to synchronize file: Out of space")))
+
+	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger, nil).
+		NewFile(false, 0, virtual.ShareMaskWrite)
+	require.Equal(t, virtual.StatusOK, s)
+	require.Equal(t, virtual.StatusErrIO, f.VirtualSync())
+	f.VirtualClose(virtual.ShareMaskWrite)
+	f.Unlink()
+}
+
+func TestPoolBackedFileAllocatorVirtualDeallocate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("Supported", func(t *testing.T) {
+		// Deallocation requests should be forwarded to the file
+		// pool, clamped to the size of the file.
+		errorLogger := mock.NewMockErrorLogger(ctrl)
+		f, s := virtual.NewPoolBackedFileAllocator(re_filesystem.InMemoryFilePool, errorLogger, nil).
+			NewFile(false, 0, virtual.ShareMaskRead|virtual.ShareMaskWrite)
+		require.Equal(t, virtual.StatusOK, s)
+
+		n, s := f.VirtualWrite([]byte("Hello, world"), 0)
+		require.Equal(t, virtual.StatusOK, s)
+		require.Equal(t, 12, n)
+		require.Equal(t, virtual.StatusOK, f.VirtualDeallocate(5, 1000))
+
+		var p [16]byte
+		n, eof, s := f.VirtualRead(p[:], 0)
+		require.Equal(t, virtual.StatusOK, s)
+		require.True(t, eof)
+		require.Equal(t, []byte("Hello\x00\x00\x00\x00\x00\x00\x00"), p[:n])
+
+		f.VirtualClose(virtual.ShareMaskRead | virtual.ShareMaskWrite)
+		f.Unlink()
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		// If the file pool does not support deallocating
+		// storage, the range should be overwritten with zero
+		// bytes instead.
+		pool := mock.NewMockFilePool(ctrl)
+		underlyingFile := mock.NewMockFileReadWriter(ctrl)
+		pool.EXPECT().NewFile().Return(underlyingFile, nil)
+		underlyingFile.EXPECT().WriteAt([]byte("Hello, world"), int64(0)).Return(12, nil)
+		underlyingFile.EXPECT().WriteAt(make([]byte, 7), int64(5)).Return(7, nil)
+		underlyingFile.EXPECT().Close()
+		errorLogger := mock.NewMockErrorLogger(ctrl)
+
+		f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger, nil).
+			NewFile(false, 0, virtual.ShareMaskWrite)
+		require.Equal(t, virtual.StatusOK, s)
+
+		n, s := f.VirtualWrite([]byte("Hello, world"), 0)
+		require.Equal(t, virtual.StatusOK, s)
+		require.Equal(t, 12, n)
+		require.Equal(t, virtual.StatusOK, f.VirtualDeallocate(5, 1000))
+
+		f.VirtualClose(virtual.ShareMaskWrite)
+		f.Unlink()
+	})
+}
+
 func TestPoolBackedFileAllocatorUploadFile(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
@@ -423,7 +499,7 @@ func TestPoolBackedFileAllocatorUploadFile(t *testing.T) {
 	pool.EXPECT().NewFile().Return(underlyingFile, nil)
 	errorLogger := mock.NewMockErrorLogger(ctrl)
 
-	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger).
+	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger, nil).
 		NewFile(false, 0, virtual.ShareMaskWrite)
 	require.Equal(t, virtual.StatusOK, s)
 
@@ -558,6 +634,145 @@ func TestPoolBackedFileAllocatorUploadFile(t *testing.T) {
 	})
 }
 
+func TestPoolBackedFileAllocatorUploadFileDigestFunctions(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	// Files should be hashed using the digest function of the
+	// action. Use a file that is larger than a single SHA256TREE
+	// chunk, so that tree hashing is actually exercised.
+	data := make([]byte, 3000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	for _, fileDigest := range []digest.Digest{
+		digest.MustNewDigest("example", remoteexecution.DigestFunction_SHA256TREE, "365813b888dd0e31182df9b649f19e4ac5f3781a55e2664c86749b99ad53d10a", 3000),
+		digest.MustNewDigest("example", remoteexecution.DigestFunction_BLAKE3, "6c943946a70794f2e14c785d5ee88d300d5f9b91d1b4ef88302974ac4b069052", 3000),
+	} {
+		digestFunction := fileDigest.GetDigestFunction()
+		t.Run(digestFunction.GetEnumValue().String(), func(t *testing.T) {
+			errorLogger := mock.NewMockErrorLogger(ctrl)
+			f, s := virtual.NewPoolBackedFileAllocator(re_filesystem.InMemoryFilePool, errorLogger, nil).
+				NewFile(false, 0, virtual.ShareMaskWrite)
+			require.Equal(t, virtual.StatusOK, s)
+			n, s := f.VirtualWrite(data, 0)
+			require.Equal(t, virtual.StatusOK, s)
+			require.Equal(t, len(data), n)
+
+			contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+			contentAddressableStorage.EXPECT().Put(ctx, fileDigest, gomock.Any()).
+				DoAndReturn(func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+					uploadedData, err := b.ToByteSlice(10000)
+					require.NoError(t, err)
+					require.Equal(t, data, uploadedData)
+					return nil
+				})
+
+			uploadedDigest, err := f.UploadFile(ctx, contentAddressableStorage, digestFunction)
+			require.NoError(t, err)
+			require.Equal(t, fileDigest, uploadedDigest)
+
+			f.VirtualClose(virtual.ShareMaskWrite)
+			f.Unlink()
+		})
+	}
+}
+
+func TestPoolBackedFileAllocatorEagerUpload(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	// Create a file backed by a FilePool, using an allocator that
+	// uploads files eagerly.
+	pool := mock.NewMockFilePool(ctrl)
+	underlyingFile := mock.NewMockFileReadWriter(ctrl)
+	pool.EXPECT().NewFile().Return(underlyingFile, nil)
+	errorLogger := mock.NewMockErrorLogger(ctrl)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	digestFunction := digest.MustNewFunction("example", remoteexecution.DigestFunction_MD5)
+
+	f, s := virtual.NewPoolBackedFileAllocator(
+		pool,
+		errorLogger,
+		virtual.NewEagerFileUploader(
+			ctx,
+			contentAddressableStorage,
+			digestFunction,
+			semaphore.NewWeighted(1)),
+	).NewFile(false, 0, virtual.ShareMaskWrite)
+	require.Equal(t, virtual.StatusOK, s)
+
+	var contents string
+	underlyingFile.EXPECT().ReadAt(gomock.Any(), gomock.Any()).DoAndReturn(func(p []byte, off int64) (int, error) {
+		return copy(p, contents[off:]), io.EOF
+	}).AnyTimes()
+
+	t.Run("Reused", func(t *testing.T) {
+		// Closing the file after writing to it should cause
+		// its contents to be uploaded in the background.
+		contents = "Hello"
+		underlyingFile.EXPECT().WriteAt([]byte("Hello"), int64(0)).Return(5, nil)
+		n, s := f.VirtualWrite([]byte("Hello"), 0)
+		require.Equal(t, virtual.StatusOK, s)
+		require.Equal(t, 5, n)
+
+		fileDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+		contentAddressableStorage.EXPECT().Put(ctx, fileDigest, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+				data, err := b.ToByteSlice(10)
+				require.NoError(t, err)
+				require.Equal(t, []byte("Hello"), data)
+				return nil
+			})
+		f.VirtualClose(virtual.ShareMaskWrite)
+
+		// UploadFile() should wait for the eager upload to
+		// complete. As the file did not change in the
+		// meantime, it should not upload it once more.
+		uploadedDigest, err := f.UploadFile(ctx, contentAddressableStorage, digestFunction)
+		require.NoError(t, err)
+		require.Equal(t, fileDigest, uploadedDigest)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		// Reopen the file and overwrite its contents. Let the
+		// eager upload fail. UploadFile() should then upload
+		// the file itself.
+		require.Equal(t, virtual.StatusOK, f.VirtualOpenSelf(
+			ctx,
+			virtual.ShareMaskWrite,
+			&virtual.OpenExistingOptions{},
+			0,
+			&virtual.Attributes{}))
+		contents = "World"
+		underlyingFile.EXPECT().WriteAt([]byte("World"), int64(0)).Return(5, nil)
+		n, s := f.VirtualWrite([]byte("World"), 0)
+		require.Equal(t, virtual.StatusOK, s)
+		require.Equal(t, 5, n)
+
+		fileDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "f5a7924e621e84c9280a9a27e1bcb7f6", 5)
+		contentAddressableStorage.EXPECT().Put(ctx, fileDigest, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+				b.Discard()
+				return status.Error(codes.Internal, "Server on fire")
+			})
+		f.VirtualClose(virtual.ShareMaskWrite)
+
+		contentAddressableStorage.EXPECT().Put(ctx, fileDigest, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+				data, err := b.ToByteSlice(10)
+				require.NoError(t, err)
+				require.Equal(t, []byte("World"), data)
+				return nil
+			})
+		uploadedDigest, err := f.UploadFile(ctx, contentAddressableStorage, digestFunction)
+		require.NoError(t, err)
+		require.Equal(t, fileDigest, uploadedDigest)
+	})
+
+	underlyingFile.EXPECT().Close()
+	f.Unlink()
+}
+
 func TestPoolBackedFileAllocatorVirtualClose(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
@@ -567,7 +782,7 @@ func TestPoolBackedFileAllocatorVirtualClose(t *testing.T) {
 	pool.EXPECT().NewFile().Return(underlyingFile, nil)
 	errorLogger := mock.NewMockErrorLogger(ctrl)
 
-	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger).
+	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger, nil).
 		NewFile(false, 0, virtual.ShareMaskWrite)
 	require.Equal(t, virtual.StatusOK, s)
 
@@ -614,3 +829,58 @@ func TestPoolBackedFileAllocatorVirtualClose(t *testing.T) {
 	underlyingFile.EXPECT().Close()
 	f.VirtualClose(virtual.ShareMaskWrite)
 }
+
+func TestPoolBackedFileAllocatorXAttrs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	pool := mock.NewMockFilePool(ctrl)
+	underlyingFile := mock.NewMockFileReadWriter(ctrl)
+	pool.EXPECT().NewFile().Return(underlyingFile, nil)
+	errorLogger := mock.NewMockErrorLogger(ctrl)
+
+	f, s := virtual.NewPoolBackedFileAllocator(pool, errorLogger, nil).
+		NewFile(false, 0, 0)
+	require.Equal(t, virtual.StatusOK, s)
+
+	t.Run("Initial", func(t *testing.T) {
+		// Newly created files have no extended attributes.
+		require.Empty(t, f.VirtualListXAttrs())
+		_, s := f.VirtualGetXAttr("user.foo")
+		require.Equal(t, virtual.StatusErrNoXAttr, s)
+		require.Equal(t, virtual.StatusErrNoXAttr, f.VirtualRemoveXAttr("user.foo"))
+		require.Equal(t, virtual.StatusErrNoXAttr, f.VirtualSetXAttr("user.foo", []byte("Hello"), virtual.XAttrSetFlagsReplace))
+	})
+
+	t.Run("SetAndGet", func(t *testing.T) {
+		// Values should be copied, as callers may reuse the
+		// buffer afterwards.
+		value := []byte("Hello")
+		require.Equal(t, virtual.StatusOK, f.VirtualSetXAttr("user.foo", value, virtual.XAttrSetFlagsCreate))
+		copy(value, "World")
+		require.Equal(t, virtual.StatusOK, f.VirtualSetXAttr("com.apple.cs.CodeDirectory", []byte{0xfa, 0xde}, 0))
+
+		require.Equal(t, []string{"com.apple.cs.CodeDirectory", "user.foo"}, f.VirtualListXAttrs())
+		storedValue, s := f.VirtualGetXAttr("user.foo")
+		require.Equal(t, virtual.StatusOK, s)
+		require.Equal(t, []byte("Hello"), storedValue)
+	})
+
+	t.Run("SetFlags", func(t *testing.T) {
+		require.Equal(t, virtual.StatusErrExist, f.VirtualSetXAttr("user.foo", []byte("Bye"), virtual.XAttrSetFlagsCreate))
+		require.Equal(t, virtual.StatusOK, f.VirtualSetXAttr("user.foo", []byte("Bye"), virtual.XAttrSetFlagsReplace))
+
+		storedValue, s := f.VirtualGetXAttr("user.foo")
+		require.Equal(t, virtual.StatusOK, s)
+		require.Equal(t, []byte("Bye"), storedValue)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		require.Equal(t, virtual.StatusOK, f.VirtualRemoveXAttr("user.foo"))
+		require.Equal(t, []string{"com.apple.cs.CodeDirectory"}, f.VirtualListXAttrs())
+		_, s := f.VirtualGetXAttr("user.foo")
+		require.Equal(t, virtual.StatusErrNoXAttr, s)
+	})
+
+	underlyingFile.EXPECT().Close()
+	f.Unlink()
+}
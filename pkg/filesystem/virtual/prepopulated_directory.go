@@ -68,6 +68,18 @@ type PrepopulatedDirectory interface {
 	// managed by PrepopulatedDirectory. Entries are returned in
 	// alphabetical order.
 	LookupAllChildren() ([]DirectoryPrepopulatedDirEntry, []LeafPrepopulatedDirEntry, error)
+	// LookupModifiedChildren() returns the names of children of a
+	// PrepopulatedDirectory that may have been modified through the
+	// virtual file system. Children that were created, linked or
+	// renamed into the directory through the virtual file system
+	// are mapped to true. Other directories whose contents have
+	// been loaded are mapped to false, as files contained in them
+	// may have been modified.
+	//
+	// This method may be used to determine which outputs of a build
+	// action need to be inspected, without traversing the full
+	// directory hierarchy.
+	LookupModifiedChildren() (map[path.Component]bool, error)
 	// CreateChildren() creates one or more files or directories in
 	// the current directory.
 	//
@@ -96,8 +108,10 @@ type PrepopulatedDirectory interface {
 	//
 	// This function is identical to BuildDirectory.InstallHooks(),
 	// except that it uses the FUSE specific FileAllocator instead
-	// of FilePool.
-	InstallHooks(fileAllocator FileAllocator, errorLogger util.ErrorLogger)
+	// of FilePool. If operationStatistics is not nil, the duration
+	// of operations performed against directories in the subtree is
+	// recorded in it.
+	InstallHooks(fileAllocator FileAllocator, errorLogger util.ErrorLogger, operationStatistics *OperationStatistics)
 	// FilterChildren() can be used to traverse over all of the
 	// InitialContentsFetcher and NativeLeaf objects stored in this
 	// directory hierarchy. For each of the objects, a callback is
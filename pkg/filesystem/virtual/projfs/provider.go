@@ -0,0 +1,607 @@
+package projfs
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+)
+
+// File attributes, as described in [MS-FSCC] section 2.6.
+const (
+	fileAttributeReadonly     = 0x00000001
+	fileAttributeDirectory    = 0x00000010
+	fileAttributeNormal       = 0x00000080
+	fileAttributeReparsePoint = 0x00000400
+)
+
+// fileBasicInfoAttributesMask is the set of attributes that need to be
+// requested from the virtual file system to construct a FileBasicInfo.
+const fileBasicInfoAttributesMask = virtual.AttributesMaskFileType |
+	virtual.AttributesMaskInodeNumber |
+	virtual.AttributesMaskLastDataModificationTime |
+	virtual.AttributesMaskPermissions |
+	virtual.AttributesMaskSizeBytes
+
+// fileDataChunkSizeBytes is the maximum amount of data that is read
+// from a file in the virtual file system at once, when ProjFS requests
+// a file to be hydrated.
+const fileDataChunkSizeBytes = 1 << 20
+
+// FileBasicInfo contains the properties of a file that ProjFS stores
+// in placeholders and reports in directory listings. It corresponds to
+// the PRJ_FILE_BASIC_INFO structure, with the exception that it may
+// also contain the target of a symbolic link.
+type FileBasicInfo struct {
+	IsDirectory    bool
+	FileSize       int64
+	Time           time.Time
+	FileAttributes uint32
+
+	// If not empty, the file is a symbolic link that points to the
+	// provided target. Pathname components are separated using
+	// backslashes.
+	SymlinkTarget string
+}
+
+// EnumerationID is an identifier that ProjFS assigns to a directory
+// enumeration session.
+type EnumerationID [16]byte
+
+// NameMatcher is called by Provider to determine whether a file name
+// matches a search expression provided by ProjFS. On Windows, this
+// should call into PrjFileNameMatch().
+type NameMatcher func(fileName, pattern string) bool
+
+// DirectoryEntryFiller is called by Provider to add an entry to the
+// buffer of a directory enumeration. It returns false if the buffer
+// has insufficient space to hold the entry.
+type DirectoryEntryFiller func(fileName string, info *FileBasicInfo) bool
+
+// FileDataWriter is called by Provider to provide the contents of a
+// file that ProjFS requested to be hydrated.
+type FileDataWriter func(data []byte, offset uint64) virtual.Status
+
+// Provider of a Windows Projected File System (ProjFS) virtualization
+// instance. It translates the callbacks invoked by ProjFS to
+// operations against a virtual.Directory.
+//
+// ProjFS stores files on disk, meaning that it only calls into the
+// provider to obtain the contents of directories and files that have
+// not been hydrated yet. Modifications made by build actions are only
+// reported through notifications. Provider applies these to the virtual
+// file system, so that outputs of build actions can be obtained from it
+// in the same way as when using FUSE or NFSv4.
+type Provider struct {
+	rootDirectory virtual.Directory
+	nameMatcher   NameMatcher
+
+	lock         sync.Mutex
+	enumerations map[EnumerationID]*directoryEnumeration
+	// Paths of directories for which placeholders have been
+	// created, keyed by inode number. These are used to translate
+	// removals of files performed through the virtual file system
+	// to paths.
+	directoryPaths map[uint64]string
+}
+
+// NewProvider creates a ProjFS provider that exposes the contents of a
+// virtual file system.
+func NewProvider(rootDirectory virtual.Directory, nameMatcher NameMatcher) *Provider {
+	var rootAttributes virtual.Attributes
+	rootDirectory.VirtualGetAttributes(context.Background(), virtual.AttributesMaskInodeNumber, &rootAttributes)
+	return &Provider{
+		rootDirectory: rootDirectory,
+		nameMatcher:   nameMatcher,
+		enumerations:  map[EnumerationID]*directoryEnumeration{},
+		directoryPaths: map[uint64]string{
+			rootAttributes.GetInodeNumber(): "",
+		},
+	}
+}
+
+// splitPath splits a path provided by ProjFS into its parent directory
+// and its final pathname component.
+func splitPath(filePath string) (string, string) {
+	if i := strings.LastIndexByte(filePath, '\\'); i >= 0 {
+		return filePath[:i], filePath[i+1:]
+	}
+	return "", filePath
+}
+
+func joinPath(directoryPath, name string) string {
+	if directoryPath == "" {
+		return name
+	}
+	return directoryPath + `\` + name
+}
+
+// caseInsensitiveLookupReporter is used by lookupChild() to find
+// directory entries whose names only differ from the requested name
+// by case.
+type caseInsensitiveLookupReporter struct {
+	name      string
+	foundName path.Component
+	found     bool
+}
+
+func (r *caseInsensitiveLookupReporter) ReportEntry(nextCookie uint64, name path.Component, child virtual.DirectoryChild, attributes *virtual.Attributes) bool {
+	if strings.EqualFold(name.String(), r.name) {
+		r.foundName = name
+		r.found = true
+		return false
+	}
+	return true
+}
+
+// lookupChild looks up a child of a directory. As ProjFS expects file
+// names to be case insensitive, it falls back to scanning the directory
+// if no child with the exact name exists.
+func lookupChild(ctx context.Context, directory virtual.Directory, name string, requested virtual.AttributesMask, attributes *virtual.Attributes) (virtual.DirectoryChild, path.Component, virtual.Status) {
+	component, ok := path.NewComponent(name)
+	if !ok {
+		return virtual.DirectoryChild{}, path.Component{}, virtual.StatusErrNoEnt
+	}
+	child, s := directory.VirtualLookup(ctx, component, requested, attributes)
+	if s != virtual.StatusErrNoEnt {
+		return child, component, s
+	}
+
+	reporter := caseInsensitiveLookupReporter{name: name}
+	if s := directory.VirtualReadDir(ctx, 0, 0, &reporter); s != virtual.StatusOK {
+		return virtual.DirectoryChild{}, path.Component{}, s
+	}
+	if !reporter.found {
+		return virtual.DirectoryChild{}, path.Component{}, virtual.StatusErrNoEnt
+	}
+	child, s = directory.VirtualLookup(ctx, reporter.foundName, requested, attributes)
+	return child, reporter.foundName, s
+}
+
+// lookupPath resolves a path provided by ProjFS, returning the file or
+// directory and its path with the casing used by the virtual file
+// system.
+func (p *Provider) lookupPath(ctx context.Context, filePath string, requested virtual.AttributesMask, attributes *virtual.Attributes) (virtual.DirectoryChild, string, virtual.Status) {
+	if filePath == "" {
+		p.rootDirectory.VirtualGetAttributes(ctx, requested, attributes)
+		return virtual.DirectoryChild{}.FromDirectory(p.rootDirectory), "", virtual.StatusOK
+	}
+	directory := p.rootDirectory
+	var canonicalPath string
+	components := strings.Split(filePath, `\`)
+	for i, name := range components {
+		var childAttributes virtual.Attributes
+		childRequested := virtual.AttributesMask(0)
+		if i == len(components)-1 {
+			childRequested = requested
+		}
+		child, component, s := lookupChild(ctx, directory, name, childRequested, &childAttributes)
+		if s != virtual.StatusOK {
+			return virtual.DirectoryChild{}, "", s
+		}
+		canonicalPath = joinPath(canonicalPath, component.String())
+		if i == len(components)-1 {
+			*attributes = childAttributes
+			return child, canonicalPath, virtual.StatusOK
+		}
+		childDirectory, _ := child.GetPair()
+		if childDirectory == nil {
+			return virtual.DirectoryChild{}, "", virtual.StatusErrNoEnt
+		}
+		directory = childDirectory
+	}
+	panic("Path should have at least one component")
+}
+
+// lookupDirectory resolves a path provided by ProjFS that should refer
+// to a directory.
+func (p *Provider) lookupDirectory(ctx context.Context, filePath string) (virtual.Directory, virtual.Status) {
+	var attributes virtual.Attributes
+	child, _, s := p.lookupPath(ctx, filePath, 0, &attributes)
+	if s != virtual.StatusOK {
+		return nil, s
+	}
+	directory, _ := child.GetPair()
+	if directory == nil {
+		return nil, virtual.StatusErrNotDir
+	}
+	return directory, virtual.StatusOK
+}
+
+// lookupParent resolves the parent directory of a path provided by
+// ProjFS, returning the final pathname component.
+func (p *Provider) lookupParent(ctx context.Context, filePath string) (virtual.Directory, path.Component, virtual.Status) {
+	parentPath, name := splitPath(filePath)
+	component, ok := path.NewComponent(name)
+	if !ok {
+		return nil, path.Component{}, virtual.StatusErrInval
+	}
+	directory, s := p.lookupDirectory(ctx, parentPath)
+	if s != virtual.StatusOK {
+		return nil, path.Component{}, s
+	}
+	return directory, component, virtual.StatusOK
+}
+
+// getFileBasicInfo converts the attributes of a file in the virtual
+// file system to the representation used by ProjFS.
+func getFileBasicInfo(ctx context.Context, child virtual.DirectoryChild, attributes *virtual.Attributes) (FileBasicInfo, virtual.Status) {
+	info := FileBasicInfo{
+		Time: filesystem.DeterministicFileModificationTimestamp,
+	}
+	if t, ok := attributes.GetLastDataModificationTime(); ok {
+		info.Time = t
+	}
+	switch attributes.GetFileType() {
+	case filesystem.FileTypeDirectory:
+		info.IsDirectory = true
+		info.FileAttributes = fileAttributeDirectory
+	case filesystem.FileTypeSymlink:
+		_, leaf := child.GetPair()
+		target, s := leaf.VirtualReadlink(ctx)
+		if s != virtual.StatusOK {
+			return FileBasicInfo{}, s
+		}
+		info.SymlinkTarget = strings.ReplaceAll(string(target), "/", `\`)
+		info.FileAttributes = fileAttributeReparsePoint
+	default:
+		if sizeBytes, ok := attributes.GetSizeBytes(); ok {
+			info.FileSize = int64(sizeBytes)
+		}
+		if permissions, ok := attributes.GetPermissions(); ok && permissions&virtual.PermissionsWrite == 0 {
+			info.FileAttributes = fileAttributeReadonly
+		} else {
+			info.FileAttributes = fileAttributeNormal
+		}
+	}
+	return info, virtual.StatusOK
+}
+
+// registerDirectoryPath records the path of a directory for which
+// ProjFS has created a placeholder.
+func (p *Provider) registerDirectoryPath(attributes *virtual.Attributes, directoryPath string) {
+	p.lock.Lock()
+	p.directoryPaths[attributes.GetInodeNumber()] = directoryPath
+	p.lock.Unlock()
+}
+
+// GetPlaceholderInfo is called by ProjFS to obtain the properties of a
+// file or directory for which it wants to create a placeholder. In
+// addition to the properties of the file, it returns the path of the
+// file with the casing used by the virtual file system.
+func (p *Provider) GetPlaceholderInfo(ctx context.Context, filePath string) (string, FileBasicInfo, virtual.Status) {
+	var attributes virtual.Attributes
+	child, canonicalPath, s := p.lookupPath(ctx, filePath, fileBasicInfoAttributesMask, &attributes)
+	if s != virtual.StatusOK {
+		return "", FileBasicInfo{}, s
+	}
+	info, s := getFileBasicInfo(ctx, child, &attributes)
+	if s != virtual.StatusOK {
+		return "", FileBasicInfo{}, s
+	}
+	if info.IsDirectory {
+		p.registerDirectoryPath(&attributes, canonicalPath)
+	}
+	return canonicalPath, info, virtual.StatusOK
+}
+
+// GetFileData is called by ProjFS to obtain the contents of a file
+// that needs to be hydrated.
+func (p *Provider) GetFileData(ctx context.Context, filePath string, offset uint64, length uint32, writer FileDataWriter) virtual.Status {
+	var attributes virtual.Attributes
+	child, _, s := p.lookupPath(ctx, filePath, 0, &attributes)
+	if s != virtual.StatusOK {
+		return s
+	}
+	directory, leaf := child.GetPair()
+	if directory != nil {
+		return virtual.StatusErrIsDir
+	}
+
+	chunk := make([]byte, min(length, fileDataChunkSizeBytes))
+	for end := offset + uint64(length); offset < end; {
+		n, _, s := leaf.VirtualRead(chunk[:min(uint64(len(chunk)), end-offset)], offset)
+		if s != virtual.StatusOK {
+			return s
+		}
+		if n == 0 {
+			// ProjFS requested data beyond the end of the
+			// file, which may happen if the file shrunk.
+			return virtual.StatusErrIO
+		}
+		if s := writer(chunk[:n], offset); s != virtual.StatusOK {
+			return s
+		}
+		offset += uint64(n)
+	}
+	return virtual.StatusOK
+}
+
+// directoryEnumeration holds the state of a directory enumeration
+// session that ProjFS started by calling StartDirectoryEnumeration().
+type directoryEnumeration struct {
+	directory virtual.Directory
+
+	lock             sync.Mutex
+	entries          []directoryEnumerationEntry
+	searchExpression string
+	nextIndex        int
+}
+
+type directoryEnumerationEntry struct {
+	name string
+	info FileBasicInfo
+}
+
+// directoryEnumerationReporter is used by GetDirectoryEnumeration() to
+// collect the entries of a directory.
+type directoryEnumerationReporter struct {
+	children []virtual.DirectoryChild
+	names    []string
+	attrs    []virtual.Attributes
+}
+
+func (r *directoryEnumerationReporter) ReportEntry(nextCookie uint64, name path.Component, child virtual.DirectoryChild, attributes *virtual.Attributes) bool {
+	r.children = append(r.children, child)
+	r.names = append(r.names, name.String())
+	r.attrs = append(r.attrs, *attributes)
+	return true
+}
+
+// StartDirectoryEnumeration is called by ProjFS to start enumerating
+// the contents of a directory.
+func (p *Provider) StartDirectoryEnumeration(ctx context.Context, enumerationID EnumerationID, directoryPath string) virtual.Status {
+	var attributes virtual.Attributes
+	child, canonicalPath, s := p.lookupPath(ctx, directoryPath, virtual.AttributesMaskInodeNumber, &attributes)
+	if s != virtual.StatusOK {
+		return s
+	}
+	directory, _ := child.GetPair()
+	if directory == nil {
+		return virtual.StatusErrNotDir
+	}
+
+	p.lock.Lock()
+	p.directoryPaths[attributes.GetInodeNumber()] = canonicalPath
+	p.enumerations[enumerationID] = &directoryEnumeration{
+		directory: directory,
+	}
+	p.lock.Unlock()
+	return virtual.StatusOK
+}
+
+// EndDirectoryEnumeration is called by ProjFS to release the state
+// associated with a directory enumeration session.
+func (p *Provider) EndDirectoryEnumeration(enumerationID EnumerationID) virtual.Status {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if _, ok := p.enumerations[enumerationID]; !ok {
+		return virtual.StatusErrInval
+	}
+	delete(p.enumerations, enumerationID)
+	return virtual.StatusOK
+}
+
+// GetDirectoryEnumeration is called by ProjFS to obtain the next batch
+// of entries of a directory enumeration session. The contents of the
+// directory are captured upon the first call, or when the scan is
+// restarted. Entries are returned in the order expected by ProjFS,
+// namely sorted case insensitively.
+//
+// If the buffer provided by ProjFS is not large enough to hold a
+// single entry, this function returns true, so that the caller can
+// report ERROR_INSUFFICIENT_BUFFER.
+func (p *Provider) GetDirectoryEnumeration(ctx context.Context, enumerationID EnumerationID, searchExpression string, restartScan bool, filler DirectoryEntryFiller) (bool, virtual.Status) {
+	p.lock.Lock()
+	e, ok := p.enumerations[enumerationID]
+	p.lock.Unlock()
+	if !ok {
+		return false, virtual.StatusErrInval
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.entries == nil || restartScan {
+		var reporter directoryEnumerationReporter
+		if s := e.directory.VirtualReadDir(ctx, 0, fileBasicInfoAttributesMask, &reporter); s != virtual.StatusOK {
+			return false, s
+		}
+		entries := make([]directoryEnumerationEntry, 0, len(reporter.names))
+		for i, name := range reporter.names {
+			info, s := getFileBasicInfo(ctx, reporter.children[i], &reporter.attrs[i])
+			if s != virtual.StatusOK {
+				return false, s
+			}
+			entries = append(entries, directoryEnumerationEntry{
+				name: name,
+				info: info,
+			})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return strings.ToUpper(entries[i].name) < strings.ToUpper(entries[j].name)
+		})
+		e.entries = entries
+		e.searchExpression = searchExpression
+		e.nextIndex = 0
+	}
+
+	filled := false
+	for ; e.nextIndex < len(e.entries); e.nextIndex++ {
+		entry := &e.entries[e.nextIndex]
+		if e.searchExpression != "" && !p.nameMatcher(entry.name, e.searchExpression) {
+			continue
+		}
+		if !filler(entry.name, &entry.info) {
+			return !filled, virtual.StatusOK
+		}
+		filled = true
+	}
+	return false, virtual.StatusOK
+}
+
+// GetRemovedPath is called when a file is removed from a directory in
+// the virtual file system without ProjFS being involved. It returns
+// the path of the file, if ProjFS may have created a placeholder for
+// it. The caller should then request ProjFS to delete the placeholder,
+// so that the removal becomes visible.
+func (p *Provider) GetRemovedPath(parentInodeNumber uint64, name path.Component) (string, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	parentPath, ok := p.directoryPaths[parentInodeNumber]
+	if !ok {
+		return "", false
+	}
+	removedPath := joinPath(parentPath, name.String())
+	removedPathPrefix := removedPath + `\`
+	for inodeNumber, directoryPath := range p.directoryPaths {
+		if directoryPath == removedPath || strings.HasPrefix(directoryPath, removedPathPrefix) {
+			delete(p.directoryPaths, inodeNumber)
+		}
+	}
+	return removedPath, true
+}
+
+// NewFileCreated is called by ProjFS after a file or directory has
+// been created, causing it to be created in the virtual file system as
+// well.
+func (p *Provider) NewFileCreated(ctx context.Context, filePath string, isDirectory bool) virtual.Status {
+	directory, name, s := p.lookupParent(ctx, filePath)
+	if s != virtual.StatusOK {
+		return s
+	}
+	if isDirectory {
+		_, _, s := directory.VirtualMkdir(name, 0, &virtual.Attributes{})
+		return s
+	}
+	var attributes virtual.Attributes
+	leaf, _, _, s := directory.VirtualOpenChild(
+		ctx,
+		name,
+		virtual.ShareMaskWrite,
+		(&virtual.Attributes{}).SetPermissions(virtual.PermissionsRead|virtual.PermissionsWrite),
+		nil,
+		0,
+		&attributes)
+	if s != virtual.StatusOK {
+		return s
+	}
+	leaf.VirtualClose(virtual.ShareMaskWrite)
+	return virtual.StatusOK
+}
+
+// FileModified is called by ProjFS after a handle to a file that was
+// modified is closed. The contents of the file in the virtual file
+// system are replaced with the contents of the file on disk.
+func (p *Provider) FileModified(ctx context.Context, filePath string, contents io.Reader) virtual.Status {
+	var attributes virtual.Attributes
+	child, _, s := p.lookupPath(ctx, filePath, 0, &attributes)
+	if s == virtual.StatusErrNoEnt {
+		// The file was created before notifications were
+		// enabled.
+		if s := p.NewFileCreated(ctx, filePath, false); s != virtual.StatusOK {
+			return s
+		}
+		child, _, s = p.lookupPath(ctx, filePath, 0, &attributes)
+	}
+	if s != virtual.StatusOK {
+		return s
+	}
+	directory, leaf := child.GetPair()
+	if directory != nil {
+		return virtual.StatusErrIsDir
+	}
+
+	if s := leaf.VirtualOpenSelf(ctx, virtual.ShareMaskWrite, &virtual.OpenExistingOptions{Truncate: true}, 0, &attributes); s != virtual.StatusOK {
+		return s
+	}
+	defer leaf.VirtualClose(virtual.ShareMaskWrite)
+
+	chunk := make([]byte, fileDataChunkSizeBytes)
+	var offset uint64
+	for {
+		n, err := contents.Read(chunk)
+		if n > 0 {
+			if _, s := leaf.VirtualWrite(chunk[:n], offset); s != virtual.StatusOK {
+				return s
+			}
+			offset += uint64(n)
+		}
+		if err == io.EOF {
+			return virtual.StatusOK
+		} else if err != nil {
+			return virtual.StatusErrIO
+		}
+	}
+}
+
+// removeRecursively removes a file or directory from the virtual file
+// system. Directories are emptied prior to removal.
+func removeRecursively(ctx context.Context, directory virtual.Directory, name path.Component) virtual.Status {
+	var attributes virtual.Attributes
+	child, s := directory.VirtualLookup(ctx, name, 0, &attributes)
+	if s != virtual.StatusOK {
+		return s
+	}
+	if childDirectory, _ := child.GetPair(); childDirectory != nil {
+		var reporter directoryEnumerationReporter
+		if s := childDirectory.VirtualReadDir(ctx, 0, 0, &reporter); s != virtual.StatusOK {
+			return s
+		}
+		for _, grandchildName := range reporter.names {
+			if s := removeRecursively(ctx, childDirectory, path.MustNewComponent(grandchildName)); s != virtual.StatusOK {
+				return s
+			}
+		}
+	}
+	_, s = directory.VirtualRemove(name, true, true)
+	return s
+}
+
+// FileDeleted is called by ProjFS after a file or directory has been
+// deleted, or moved outside of the virtualization root. The file is
+// removed from the virtual file system as well. No error is returned
+// if the file no longer exists in the virtual file system, as it may
+// have been removed without ProjFS being involved.
+func (p *Provider) FileDeleted(ctx context.Context, filePath string) virtual.Status {
+	directory, name, s := p.lookupParent(ctx, filePath)
+	if s == virtual.StatusOK {
+		var attributes virtual.Attributes
+		if _, component, s := lookupChild(ctx, directory, name.String(), 0, &attributes); s == virtual.StatusOK {
+			name = component
+		}
+		s = removeRecursively(ctx, directory, name)
+	}
+	if s == virtual.StatusErrNoEnt {
+		return virtual.StatusOK
+	}
+	return s
+}
+
+// FileRenamed is called by ProjFS after a file or directory has been
+// renamed within the virtualization root. The file is renamed in the
+// virtual file system as well.
+func (p *Provider) FileRenamed(ctx context.Context, oldPath, newPath string) virtual.Status {
+	oldDirectory, oldName, s := p.lookupParent(ctx, oldPath)
+	if s != virtual.StatusOK {
+		return s
+	}
+	var attributes virtual.Attributes
+	if _, component, s := lookupChild(ctx, oldDirectory, oldName.String(), 0, &attributes); s == virtual.StatusOK {
+		oldName = component
+	}
+	newDirectory, newName, s := p.lookupParent(ctx, newPath)
+	if s != virtual.StatusOK {
+		return s
+	}
+	_, _, s = oldDirectory.VirtualRename(oldName, newDirectory, newName)
+	return s
+}
@@ -0,0 +1,223 @@
+package projfs_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual/projfs"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
+	rootDirectory.EXPECT().VirtualGetAttributes(gomock.Any(), virtual.AttributesMaskInodeNumber, gomock.Any()).
+		Do(func(ctx context.Context, requested virtual.AttributesMask, attributes *virtual.Attributes) {
+			attributes.SetInodeNumber(1)
+		})
+	provider := projfs.NewProvider(rootDirectory, func(fileName, pattern string) bool {
+		return pattern == "*" || strings.EqualFold(fileName, pattern)
+	})
+
+	t.Run("GetPlaceholderInfoNotFound", func(t *testing.T) {
+		rootDirectory.EXPECT().VirtualLookup(ctx, path.MustNewComponent("nonexistent"), gomock.Any(), gomock.Any()).
+			Return(virtual.DirectoryChild{}, virtual.StatusErrNoEnt)
+		rootDirectory.EXPECT().VirtualReadDir(ctx, uint64(0), virtual.AttributesMask(0), gomock.Any())
+
+		_, _, s := provider.GetPlaceholderInfo(ctx, "nonexistent")
+		require.Equal(t, virtual.StatusErrNoEnt, s)
+	})
+
+	t.Run("GetPlaceholderInfoCaseInsensitive", func(t *testing.T) {
+		// ProjFS uses case insensitive file names. If no file
+		// with the exact name exists, a file whose name only
+		// differs by case should be returned.
+		childDirectory := mock.NewMockVirtualDirectory(ctrl)
+		rootDirectory.EXPECT().VirtualLookup(ctx, path.MustNewComponent("SRC"), gomock.Any(), gomock.Any()).
+			Return(virtual.DirectoryChild{}, virtual.StatusErrNoEnt)
+		rootDirectory.EXPECT().VirtualReadDir(ctx, uint64(0), virtual.AttributesMask(0), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, firstCookie uint64, requested virtual.AttributesMask, reporter virtual.DirectoryEntryReporter) virtual.Status {
+				require.True(t, reporter.ReportEntry(1, path.MustNewComponent("include"), virtual.DirectoryChild{}.FromDirectory(mock.NewMockVirtualDirectory(ctrl)), &virtual.Attributes{}))
+				require.False(t, reporter.ReportEntry(2, path.MustNewComponent("src"), virtual.DirectoryChild{}.FromDirectory(childDirectory), &virtual.Attributes{}))
+				return virtual.StatusOK
+			})
+		rootDirectory.EXPECT().VirtualLookup(ctx, path.MustNewComponent("src"), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, name path.Component, requested virtual.AttributesMask, attributes *virtual.Attributes) (virtual.DirectoryChild, virtual.Status) {
+				attributes.SetFileType(filesystem.FileTypeDirectory)
+				attributes.SetInodeNumber(2)
+				attributes.SetLastDataModificationTime(time.Unix(1700000000, 0))
+				return virtual.DirectoryChild{}.FromDirectory(childDirectory), virtual.StatusOK
+			})
+
+		canonicalPath, info, s := provider.GetPlaceholderInfo(ctx, "SRC")
+		require.Equal(t, virtual.StatusOK, s)
+		require.Equal(t, "src", canonicalPath)
+		require.Equal(t, projfs.FileBasicInfo{
+			IsDirectory:    true,
+			Time:           time.Unix(1700000000, 0),
+			FileAttributes: 0x10,
+		}, info)
+
+		// As a placeholder for the directory was created,
+		// removals of files inside it should be reported.
+		removedPath, ok := provider.GetRemovedPath(2, path.MustNewComponent("main.c"))
+		require.True(t, ok)
+		require.Equal(t, `src\main.c`, removedPath)
+
+		// Removing the directory itself should cause it to be
+		// forgotten.
+		removedPath, ok = provider.GetRemovedPath(1, path.MustNewComponent("src"))
+		require.True(t, ok)
+		require.Equal(t, "src", removedPath)
+		_, ok = provider.GetRemovedPath(2, path.MustNewComponent("main.c"))
+		require.False(t, ok)
+	})
+
+	t.Run("GetFileData", func(t *testing.T) {
+		leaf := mock.NewMockVirtualLeaf(ctrl)
+		rootDirectory.EXPECT().VirtualLookup(ctx, path.MustNewComponent("hello.txt"), gomock.Any(), gomock.Any()).
+			Return(virtual.DirectoryChild{}.FromLeaf(leaf), virtual.StatusOK)
+		leaf.EXPECT().VirtualRead(gomock.Len(5), uint64(7)).
+			DoAndReturn(func(buf []byte, offset uint64) (int, bool, virtual.Status) {
+				return copy(buf, "world"), true, virtual.StatusOK
+			})
+
+		var data []byte
+		require.Equal(t, virtual.StatusOK, provider.GetFileData(ctx, "hello.txt", 7, 5, func(chunk []byte, offset uint64) virtual.Status {
+			require.Equal(t, uint64(7), offset)
+			data = append(data, chunk...)
+			return virtual.StatusOK
+		}))
+		require.Equal(t, []byte("world"), data)
+	})
+
+	t.Run("DirectoryEnumeration", func(t *testing.T) {
+		enumerationID := projfs.EnumerationID{1, 2, 3}
+		rootDirectory.EXPECT().VirtualGetAttributes(ctx, virtual.AttributesMaskInodeNumber, gomock.Any()).
+			Do(func(ctx context.Context, requested virtual.AttributesMask, attributes *virtual.Attributes) {
+				attributes.SetInodeNumber(1)
+			})
+		require.Equal(t, virtual.StatusOK, provider.StartDirectoryEnumeration(ctx, enumerationID, ""))
+
+		// Entries should be returned in case insensitive
+		// order. Symbolic links should have their targets
+		// converted to use backslashes.
+		symlink := mock.NewMockVirtualLeaf(ctrl)
+		rootDirectory.EXPECT().VirtualReadDir(ctx, uint64(0), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, firstCookie uint64, requested virtual.AttributesMask, reporter virtual.DirectoryEntryReporter) virtual.Status {
+				require.True(t, reporter.ReportEntry(1, path.MustNewComponent("b.txt"), virtual.DirectoryChild{}.FromLeaf(mock.NewMockVirtualLeaf(ctrl)), (&virtual.Attributes{}).
+					SetFileType(filesystem.FileTypeRegularFile).
+					SetPermissions(virtual.PermissionsRead).
+					SetSizeBytes(42)))
+				require.True(t, reporter.ReportEntry(2, path.MustNewComponent("A"), virtual.DirectoryChild{}.FromDirectory(mock.NewMockVirtualDirectory(ctrl)), (&virtual.Attributes{}).
+					SetFileType(filesystem.FileTypeDirectory)))
+				require.True(t, reporter.ReportEntry(3, path.MustNewComponent("c"), virtual.DirectoryChild{}.FromLeaf(symlink), (&virtual.Attributes{}).
+					SetFileType(filesystem.FileTypeSymlink)))
+				return virtual.StatusOK
+			})
+		symlink.EXPECT().VirtualReadlink(ctx).Return([]byte("../d/e"), virtual.StatusOK)
+
+		// If not even a single entry fits, the caller should
+		// be informed that the buffer is too small.
+		insufficientBuffer, s := provider.GetDirectoryEnumeration(ctx, enumerationID, "*", false, func(fileName string, info *projfs.FileBasicInfo) bool {
+			return false
+		})
+		require.Equal(t, virtual.StatusOK, s)
+		require.True(t, insufficientBuffer)
+
+		var names []string
+		var infos []projfs.FileBasicInfo
+		insufficientBuffer, s = provider.GetDirectoryEnumeration(ctx, enumerationID, "", false, func(fileName string, info *projfs.FileBasicInfo) bool {
+			if len(names) == 2 {
+				return false
+			}
+			names = append(names, fileName)
+			infos = append(infos, *info)
+			return true
+		})
+		require.Equal(t, virtual.StatusOK, s)
+		require.False(t, insufficientBuffer)
+		require.Equal(t, []string{"A", "b.txt"}, names)
+		require.Equal(t, []projfs.FileBasicInfo{
+			{
+				IsDirectory:    true,
+				Time:           filesystem.DeterministicFileModificationTimestamp,
+				FileAttributes: 0x10,
+			},
+			{
+				FileSize:       42,
+				Time:           filesystem.DeterministicFileModificationTimestamp,
+				FileAttributes: 0x1,
+			},
+		}, infos)
+
+		names = nil
+		infos = nil
+		insufficientBuffer, s = provider.GetDirectoryEnumeration(ctx, enumerationID, "", false, func(fileName string, info *projfs.FileBasicInfo) bool {
+			names = append(names, fileName)
+			infos = append(infos, *info)
+			return true
+		})
+		require.Equal(t, virtual.StatusOK, s)
+		require.False(t, insufficientBuffer)
+		require.Equal(t, []string{"c"}, names)
+		require.Equal(t, []projfs.FileBasicInfo{
+			{
+				Time:           filesystem.DeterministicFileModificationTimestamp,
+				FileAttributes: 0x400,
+				SymlinkTarget:  `..\d\e`,
+			},
+		}, infos)
+
+		require.Equal(t, virtual.StatusOK, provider.EndDirectoryEnumeration(enumerationID))
+		require.Equal(t, virtual.StatusErrInval, provider.EndDirectoryEnumeration(enumerationID))
+	})
+
+	t.Run("NewFileCreatedAndModified", func(t *testing.T) {
+		// Files created through ProjFS should be created in
+		// the virtual file system. Once closed, their contents
+		// should be copied.
+		leaf := mock.NewMockVirtualLeaf(ctrl)
+		rootDirectory.EXPECT().VirtualGetAttributes(ctx, virtual.AttributesMask(0), gomock.Any())
+		rootDirectory.EXPECT().VirtualOpenChild(
+			ctx,
+			path.MustNewComponent("output.o"),
+			virtual.ShareMaskWrite,
+			(&virtual.Attributes{}).SetPermissions(virtual.PermissionsRead|virtual.PermissionsWrite),
+			nil,
+			virtual.AttributesMask(0),
+			gomock.Any(),
+		).Return(leaf, virtual.AttributesMask(0), virtual.ChangeInfo{}, virtual.StatusOK)
+		leaf.EXPECT().VirtualClose(virtual.ShareMaskWrite)
+
+		require.Equal(t, virtual.StatusOK, provider.NewFileCreated(ctx, "output.o", false))
+
+		rootDirectory.EXPECT().VirtualLookup(ctx, path.MustNewComponent("output.o"), gomock.Any(), gomock.Any()).
+			Return(virtual.DirectoryChild{}.FromLeaf(leaf), virtual.StatusOK)
+		leaf.EXPECT().VirtualOpenSelf(ctx, virtual.ShareMaskWrite, &virtual.OpenExistingOptions{Truncate: true}, virtual.AttributesMask(0), gomock.Any())
+		leaf.EXPECT().VirtualWrite([]byte("Hello"), uint64(0)).Return(5, virtual.StatusOK)
+		leaf.EXPECT().VirtualClose(virtual.ShareMaskWrite)
+
+		require.Equal(t, virtual.StatusOK, provider.FileModified(ctx, "output.o", strings.NewReader("Hello")))
+	})
+
+	t.Run("FileDeleted", func(t *testing.T) {
+		// Deleting files that no longer exist in the virtual
+		// file system should not cause any errors.
+		rootDirectory.EXPECT().VirtualGetAttributes(ctx, virtual.AttributesMask(0), gomock.Any())
+		rootDirectory.EXPECT().VirtualLookup(ctx, path.MustNewComponent("output.o"), gomock.Any(), gomock.Any()).
+			Return(virtual.DirectoryChild{}, virtual.StatusErrNoEnt).
+			Times(2)
+		rootDirectory.EXPECT().VirtualReadDir(ctx, uint64(0), virtual.AttributesMask(0), gomock.Any())
+
+		require.Equal(t, virtual.StatusOK, provider.FileDeleted(ctx, "output.o"))
+	})
+}
@@ -0,0 +1,512 @@
+//go:build windows
+// +build windows
+
+package projfs
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"unsafe"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	projectedFSLib                    = windows.NewLazySystemDLL("ProjectedFSLib.dll")
+	procPrjAllocateAlignedBuffer      = projectedFSLib.NewProc("PrjAllocateAlignedBuffer")
+	procPrjDeleteFile                 = projectedFSLib.NewProc("PrjDeleteFile")
+	procPrjFileNameMatch              = projectedFSLib.NewProc("PrjFileNameMatch")
+	procPrjFillDirEntryBuffer2        = projectedFSLib.NewProc("PrjFillDirEntryBuffer2")
+	procPrjFreeAlignedBuffer          = projectedFSLib.NewProc("PrjFreeAlignedBuffer")
+	procPrjMarkDirectoryAsPlaceholder = projectedFSLib.NewProc("PrjMarkDirectoryAsPlaceholder")
+	procPrjStartVirtualizing          = projectedFSLib.NewProc("PrjStartVirtualizing")
+	procPrjStopVirtualizing           = projectedFSLib.NewProc("PrjStopVirtualizing")
+	procPrjWriteFileData              = projectedFSLib.NewProc("PrjWriteFileData")
+	procPrjWritePlaceholderInfo2      = projectedFSLib.NewProc("PrjWritePlaceholderInfo2")
+)
+
+// HRESULT values returned by callbacks.
+const (
+	hresultOK                 = 0
+	hresultInsufficientBuffer = 0x8007007a
+)
+
+// Values of PRJ_NOTIFICATION and PRJ_NOTIFY_TYPES.
+const (
+	notificationNewFileCreated               = 0x00000004
+	notificationPreSetHardlink               = 0x00000040
+	notificationFileRenamed                  = 0x00000080
+	notificationFileHandleClosedFileModified = 0x00000400
+	notificationFileHandleClosedFileDeleted  = 0x00000800
+)
+
+const (
+	callbackDataFlagRestartScan = 0x00000001
+	extInfoTypeSymlink          = 1
+	updateAllowDirtyMetadata    = 0x00000001
+	updateAllowDirtyData        = 0x00000002
+	updateAllowTombstone        = 0x00000004
+	updateAllowReadOnly         = 0x00000020
+)
+
+// callbackData corresponds to PRJ_CALLBACK_DATA.
+type callbackData struct {
+	size                           uint32
+	flags                          uint32
+	namespaceVirtualizationContext uintptr
+	commandID                      int32
+	fileID                         windows.GUID
+	dataStreamID                   windows.GUID
+	filePathName                   *uint16
+	versionInfo                    uintptr
+	triggeringProcessID            uint32
+	triggeringProcessImageFileName *uint16
+	instanceContext                uintptr
+}
+
+// callbacks corresponds to PRJ_CALLBACKS.
+type callbacks struct {
+	startDirectoryEnumeration uintptr
+	endDirectoryEnumeration   uintptr
+	getDirectoryEnumeration   uintptr
+	getPlaceholderInfo        uintptr
+	getFileData               uintptr
+	queryFileName             uintptr
+	notification              uintptr
+	cancelCommand             uintptr
+}
+
+// notificationMapping corresponds to PRJ_NOTIFICATION_MAPPING.
+type notificationMapping struct {
+	notificationBitMask uint32
+	notificationRoot    *uint16
+}
+
+// startVirtualizingOptions corresponds to
+// PRJ_STARTVIRTUALIZING_OPTIONS.
+type startVirtualizingOptions struct {
+	flags                     uint32
+	poolThreadCount           uint32
+	concurrentThreadCount     uint32
+	notificationMappings      *notificationMapping
+	notificationMappingsCount uint32
+}
+
+// fileBasicInfo corresponds to PRJ_FILE_BASIC_INFO.
+type fileBasicInfo struct {
+	isDirectory    uint8
+	fileSize       int64
+	creationTime   int64
+	lastAccessTime int64
+	lastWriteTime  int64
+	changeTime     int64
+	fileAttributes uint32
+}
+
+// placeholderVersionInfo corresponds to PRJ_PLACEHOLDER_VERSION_INFO.
+type placeholderVersionInfo struct {
+	providerID [128]byte
+	contentID  [128]byte
+}
+
+// placeholderInfo corresponds to PRJ_PLACEHOLDER_INFO, without the
+// variable length data at the end.
+type placeholderInfo struct {
+	fileBasicInfo       fileBasicInfo
+	eaInformation       [2]uint32
+	securityInformation [2]uint32
+	streamsInformation  [2]uint32
+	versionInfo         placeholderVersionInfo
+	variableData        [1]byte
+}
+
+// extendedInfo corresponds to PRJ_EXTENDED_INFO.
+type extendedInfo struct {
+	infoType       uint32
+	nextInfoOffset uint32
+	symlinkTarget  *uint16
+}
+
+func newFileBasicInfo(info *FileBasicInfo) fileBasicInfo {
+	t := windows.NsecToFiletime(info.Time.UnixNano())
+	ft := int64(t.HighDateTime)<<32 | int64(t.LowDateTime)
+	fbi := fileBasicInfo{
+		fileSize:       info.FileSize,
+		creationTime:   ft,
+		lastAccessTime: ft,
+		lastWriteTime:  ft,
+		changeTime:     ft,
+		fileAttributes: info.FileAttributes,
+	}
+	if info.IsDirectory {
+		fbi.isDirectory = 1
+	}
+	return fbi
+}
+
+// newExtendedInfo creates the extended information that needs to be
+// provided to ProjFS for symbolic links. For other types of files, it
+// returns nil.
+func newExtendedInfo(info *FileBasicInfo) (*extendedInfo, error) {
+	if info.SymlinkTarget == "" {
+		return nil, nil
+	}
+	target, err := windows.UTF16PtrFromString(info.SymlinkTarget)
+	if err != nil {
+		return nil, err
+	}
+	return &extendedInfo{
+		infoType:      extInfoTypeSymlink,
+		symlinkTarget: target,
+	}, nil
+}
+
+// statusToHRESULT converts a status code of the virtual file system to
+// an HRESULT that can be returned to ProjFS.
+func statusToHRESULT(s virtual.Status) uintptr {
+	var errno windows.Errno
+	switch s {
+	case virtual.StatusOK:
+		return hresultOK
+	case virtual.StatusErrAccess, virtual.StatusErrPerm, virtual.StatusErrROFS:
+		errno = windows.ERROR_ACCESS_DENIED
+	case virtual.StatusErrExist:
+		errno = windows.ERROR_FILE_EXISTS
+	case virtual.StatusErrInval:
+		errno = windows.ERROR_INVALID_PARAMETER
+	case virtual.StatusErrIsDir, virtual.StatusErrNotDir, virtual.StatusErrWrongType:
+		errno = windows.ERROR_DIRECTORY
+	case virtual.StatusErrNoEnt:
+		errno = windows.ERROR_FILE_NOT_FOUND
+	case virtual.StatusErrNotEmpty:
+		errno = windows.ERROR_DIR_NOT_EMPTY
+	case virtual.StatusErrXDev:
+		errno = windows.ERROR_NOT_SAME_DEVICE
+	default:
+		errno = windows.ERROR_IO_DEVICE
+	}
+	// HRESULT_FROM_WIN32().
+	return uintptr(0x80070000 | uint32(errno)&0xffff)
+}
+
+// FileNameMatch determines whether a file name matches a search
+// expression, using the semantics of ProjFS. It may be provided to
+// NewProvider() as a NameMatcher.
+func FileNameMatch(fileName, pattern string) bool {
+	fileNameUTF16, err := windows.UTF16PtrFromString(fileName)
+	if err != nil {
+		return false
+	}
+	patternUTF16, err := windows.UTF16PtrFromString(pattern)
+	if err != nil {
+		return false
+	}
+	r, _, _ := procPrjFileNameMatch.Call(uintptr(unsafe.Pointer(fileNameUTF16)), uintptr(unsafe.Pointer(patternUTF16)))
+	return uint8(r) != 0
+}
+
+// VirtualizationInstance is a running ProjFS virtualization instance,
+// which forwards callbacks to a Provider.
+type VirtualizationInstance struct {
+	rootPath string
+	provider *Provider
+	context  uintptr
+}
+
+var (
+	// Callbacks invoked by ProjFS are translated to the
+	// VirtualizationInstance to which they belong by using the
+	// instance context as an index into this list.
+	virtualizationInstancesLock sync.RWMutex
+	virtualizationInstances     []*VirtualizationInstance
+
+	nativeCallbacks = callbacks{
+		startDirectoryEnumeration: windows.NewCallback(startDirectoryEnumerationCallback),
+		endDirectoryEnumeration:   windows.NewCallback(endDirectoryEnumerationCallback),
+		getDirectoryEnumeration:   windows.NewCallback(getDirectoryEnumerationCallback),
+		getPlaceholderInfo:        windows.NewCallback(getPlaceholderInfoCallback),
+		getFileData:               windows.NewCallback(getFileDataCallback),
+		notification:              windows.NewCallback(notificationCallback),
+	}
+)
+
+func getVirtualizationInstance(data *callbackData) *VirtualizationInstance {
+	virtualizationInstancesLock.RLock()
+	defer virtualizationInstancesLock.RUnlock()
+	return virtualizationInstances[data.instanceContext]
+}
+
+func getEnumerationID(enumerationID *windows.GUID) EnumerationID {
+	return *(*EnumerationID)(unsafe.Pointer(enumerationID))
+}
+
+func startDirectoryEnumerationCallback(data *callbackData, enumerationID *windows.GUID) uintptr {
+	vi := getVirtualizationInstance(data)
+	return statusToHRESULT(vi.provider.StartDirectoryEnumeration(
+		context.Background(),
+		getEnumerationID(enumerationID),
+		windows.UTF16PtrToString(data.filePathName)))
+}
+
+func endDirectoryEnumerationCallback(data *callbackData, enumerationID *windows.GUID) uintptr {
+	vi := getVirtualizationInstance(data)
+	return statusToHRESULT(vi.provider.EndDirectoryEnumeration(getEnumerationID(enumerationID)))
+}
+
+func getDirectoryEnumerationCallback(data *callbackData, enumerationID *windows.GUID, searchExpression *uint16, dirEntryBufferHandle uintptr) uintptr {
+	vi := getVirtualizationInstance(data)
+	var searchExpressionString string
+	if searchExpression != nil {
+		searchExpressionString = windows.UTF16PtrToString(searchExpression)
+	}
+	insufficientBuffer, s := vi.provider.GetDirectoryEnumeration(
+		context.Background(),
+		getEnumerationID(enumerationID),
+		searchExpressionString,
+		data.flags&callbackDataFlagRestartScan != 0,
+		func(fileName string, info *FileBasicInfo) bool {
+			fileNameUTF16, err := windows.UTF16PtrFromString(fileName)
+			if err != nil {
+				return true
+			}
+			fbi := newFileBasicInfo(info)
+			ei, err := newExtendedInfo(info)
+			if err != nil {
+				return true
+			}
+			r, _, _ := procPrjFillDirEntryBuffer2.Call(
+				dirEntryBufferHandle,
+				uintptr(unsafe.Pointer(fileNameUTF16)),
+				uintptr(unsafe.Pointer(&fbi)),
+				uintptr(unsafe.Pointer(ei)))
+			return r == hresultOK
+		})
+	if s == virtual.StatusOK && insufficientBuffer {
+		return hresultInsufficientBuffer
+	}
+	return statusToHRESULT(s)
+}
+
+func getPlaceholderInfoCallback(data *callbackData) uintptr {
+	vi := getVirtualizationInstance(data)
+	canonicalPath, info, s := vi.provider.GetPlaceholderInfo(context.Background(), windows.UTF16PtrToString(data.filePathName))
+	if s != virtual.StatusOK {
+		return statusToHRESULT(s)
+	}
+	destinationFileName, err := windows.UTF16PtrFromString(canonicalPath)
+	if err != nil {
+		return statusToHRESULT(virtual.StatusErrInval)
+	}
+	ei, err := newExtendedInfo(&info)
+	if err != nil {
+		return statusToHRESULT(virtual.StatusErrInval)
+	}
+	pi := placeholderInfo{
+		fileBasicInfo: newFileBasicInfo(&info),
+	}
+	r, _, _ := procPrjWritePlaceholderInfo2.Call(
+		data.namespaceVirtualizationContext,
+		uintptr(unsafe.Pointer(destinationFileName)),
+		uintptr(unsafe.Pointer(&pi)),
+		unsafe.Sizeof(pi),
+		uintptr(unsafe.Pointer(ei)))
+	return r
+}
+
+func getFileDataCallback(data *callbackData, byteOffset, length uintptr) uintptr {
+	vi := getVirtualizationInstance(data)
+	return statusToHRESULT(vi.provider.GetFileData(
+		context.Background(),
+		windows.UTF16PtrToString(data.filePathName),
+		uint64(byteOffset),
+		uint32(length),
+		func(chunk []byte, offset uint64) virtual.Status {
+			// ProjFS requires that data is written using
+			// buffers that it allocated.
+			buffer, _, _ := procPrjAllocateAlignedBuffer.Call(data.namespaceVirtualizationContext, uintptr(len(chunk)))
+			if buffer == 0 {
+				return virtual.StatusErrIO
+			}
+			defer procPrjFreeAlignedBuffer.Call(buffer)
+			copy(unsafe.Slice((*byte)(unsafe.Pointer(buffer)), len(chunk)), chunk)
+			if r, _, _ := procPrjWriteFileData.Call(
+				data.namespaceVirtualizationContext,
+				uintptr(unsafe.Pointer(&data.dataStreamID)),
+				buffer,
+				uintptr(offset),
+				uintptr(len(chunk)),
+			); r != hresultOK {
+				return virtual.StatusErrIO
+			}
+			return virtual.StatusOK
+		}))
+}
+
+func notificationCallback(data *callbackData, isDirectory, notification uintptr, destinationFileName *uint16, operationParameters uintptr) uintptr {
+	vi := getVirtualizationInstance(data)
+	ctx := context.Background()
+	filePath := windows.UTF16PtrToString(data.filePathName)
+	var s virtual.Status
+	switch notification {
+	case notificationNewFileCreated:
+		s = vi.provider.NewFileCreated(ctx, filePath, uint8(isDirectory) != 0)
+	case notificationFileHandleClosedFileModified:
+		f, err := os.Open(filepath.Join(vi.rootPath, filePath))
+		if err != nil {
+			log.Printf("Failed to open modified file %#v: %s", filePath, err)
+			return hresultOK
+		}
+		s = vi.provider.FileModified(ctx, filePath, f)
+		f.Close()
+	case notificationFileHandleClosedFileDeleted:
+		s = vi.provider.FileDeleted(ctx, filePath)
+	case notificationFileRenamed:
+		var newPath string
+		if destinationFileName != nil {
+			newPath = windows.UTF16PtrToString(destinationFileName)
+		}
+		switch {
+		case filePath == "":
+			// File was moved into the virtualization root.
+			s = vi.importRecursively(ctx, newPath, uint8(isDirectory) != 0)
+		case newPath == "":
+			// File was moved out of the virtualization root.
+			s = vi.provider.FileDeleted(ctx, filePath)
+		default:
+			s = vi.provider.FileRenamed(ctx, filePath, newPath)
+		}
+	case notificationPreSetHardlink:
+		// Hard links cannot be represented in the virtual
+		// file system, as ProjFS does not report the file to
+		// which the link refers.
+		return statusToHRESULT(virtual.StatusErrXDev)
+	}
+	if s != virtual.StatusOK {
+		log.Printf("Failed to apply ProjFS notification %#x for %#v to the virtual file system: %d", notification, filePath, s)
+	}
+	// Notifications that are sent after the fact cannot be
+	// rejected.
+	return hresultOK
+}
+
+// importRecursively copies a file or directory that was moved into the
+// virtualization root from elsewhere into the virtual file system.
+func (vi *VirtualizationInstance) importRecursively(ctx context.Context, filePath string, isDirectory bool) virtual.Status {
+	if s := vi.provider.NewFileCreated(ctx, filePath, isDirectory); s != virtual.StatusOK {
+		return s
+	}
+	diskPath := filepath.Join(vi.rootPath, filePath)
+	if !isDirectory {
+		f, err := os.Open(diskPath)
+		if err != nil {
+			return virtual.StatusErrIO
+		}
+		defer f.Close()
+		return vi.provider.FileModified(ctx, filePath, f)
+	}
+	entries, err := os.ReadDir(diskPath)
+	if err != nil {
+		return virtual.StatusErrIO
+	}
+	for _, entry := range entries {
+		if s := vi.importRecursively(ctx, filePath+`\`+entry.Name(), entry.IsDir()); s != virtual.StatusOK {
+			return s
+		}
+	}
+	return virtual.StatusOK
+}
+
+// StartVirtualizing starts a ProjFS virtualization instance, exposing
+// the contents of a Provider at a given path. The directory is marked
+// as a virtualization root, using the provided instance ID. The thread
+// counts may be left zero to let ProjFS pick suitable defaults.
+func StartVirtualizing(rootPath string, instanceID windows.GUID, provider *Provider, poolThreadCount, concurrentThreadCount uint32) (*VirtualizationInstance, error) {
+	rootPathUTF16, err := windows.UTF16PtrFromString(rootPath)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Invalid virtualization root path %#v", rootPath)
+	}
+	if err := procPrjMarkDirectoryAsPlaceholder.Find(); err != nil {
+		return nil, util.StatusWrap(err, "ProjFS is not available. Please enable the Client-ProjFS optional feature")
+	}
+	// ERROR_REPARSE_POINT_ENCOUNTERED is returned if the directory
+	// is already a virtualization root with the same instance ID.
+	if r, _, _ := procPrjMarkDirectoryAsPlaceholder.Call(
+		uintptr(unsafe.Pointer(rootPathUTF16)),
+		0,
+		0,
+		uintptr(unsafe.Pointer(&instanceID)),
+	); r != hresultOK && r != uintptr(0x80070000|uint32(windows.ERROR_REPARSE_POINT_ENCOUNTERED)) {
+		return nil, util.StatusWrapf(windows.Errno(r&0xffff), "Failed to mark %#v as a virtualization root", rootPath)
+	}
+
+	vi := &VirtualizationInstance{
+		rootPath: rootPath,
+		provider: provider,
+	}
+	virtualizationInstancesLock.Lock()
+	instanceContext := uintptr(len(virtualizationInstances))
+	virtualizationInstances = append(virtualizationInstances, vi)
+	virtualizationInstancesLock.Unlock()
+
+	notificationRoot, err := windows.UTF16PtrFromString("")
+	if err != nil {
+		panic(err)
+	}
+	mapping := notificationMapping{
+		notificationBitMask: notificationNewFileCreated |
+			notificationPreSetHardlink |
+			notificationFileRenamed |
+			notificationFileHandleClosedFileModified |
+			notificationFileHandleClosedFileDeleted,
+		notificationRoot: notificationRoot,
+	}
+	options := startVirtualizingOptions{
+		poolThreadCount:           poolThreadCount,
+		concurrentThreadCount:     concurrentThreadCount,
+		notificationMappings:      &mapping,
+		notificationMappingsCount: 1,
+	}
+	if r, _, _ := procPrjStartVirtualizing.Call(
+		uintptr(unsafe.Pointer(rootPathUTF16)),
+		uintptr(unsafe.Pointer(&nativeCallbacks)),
+		instanceContext,
+		uintptr(unsafe.Pointer(&options)),
+		uintptr(unsafe.Pointer(&vi.context)),
+	); r != hresultOK {
+		return nil, util.StatusWrapf(windows.Errno(r&0xffff), "Failed to start virtualizing %#v", rootPath)
+	}
+	return vi, nil
+}
+
+// NotifyRemoval can be registered as a virtual.FUSERemovalNotifier. It
+// causes ProjFS to discard placeholders of files that have been removed
+// from the virtual file system without ProjFS being involved, such as
+// build directories of actions that have completed.
+func (vi *VirtualizationInstance) NotifyRemoval(parent uint64, name path.Component) {
+	removedPath, ok := vi.provider.GetRemovedPath(parent, name)
+	if !ok {
+		return
+	}
+	removedPathUTF16, err := windows.UTF16PtrFromString(removedPath)
+	if err != nil {
+		return
+	}
+	var failureReason uint32
+	procPrjDeleteFile.Call(
+		vi.context,
+		uintptr(unsafe.Pointer(removedPathUTF16)),
+		updateAllowDirtyMetadata|updateAllowDirtyData|updateAllowTombstone|updateAllowReadOnly,
+		uintptr(unsafe.Pointer(&failureReason)))
+}
+
+// StopVirtualizing stops the ProjFS virtualization instance.
+func (vi *VirtualizationInstance) StopVirtualizing() {
+	procPrjStopVirtualizing.Call(vi.context)
+}
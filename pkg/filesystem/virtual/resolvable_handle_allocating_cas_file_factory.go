@@ -2,7 +2,9 @@ package virtual
 
 import (
 	"bytes"
+	"encoding/binary"
 	"io"
+	"time"
 
 	"github.com/buildbarn/bb-storage/pkg/digest"
 )
@@ -20,8 +22,8 @@ type resolvableHandleAllocatingCASFileFactory struct {
 // This decorator is intended to be used in places where CASFileFactory
 // is used to hand out files with an indefinite lifetime, such as
 // bb_clientd's "cas" directory. File handles will be larger, as the
-// hash, size and executable bit of of the file will be stored in the
-// file handle.
+// hash, size, executable bit and modification time (if any) of the
+// file will be stored in the file handle.
 func NewResolvableHandleAllocatingCASFileFactory(base CASFileFactory, allocation StatelessHandleAllocation) CASFileFactory {
 	cff := &resolvableHandleAllocatingCASFileFactory{
 		base: base,
@@ -30,35 +32,49 @@ func NewResolvableHandleAllocatingCASFileFactory(base CASFileFactory, allocation
 	return cff
 }
 
-func (cff *resolvableHandleAllocatingCASFileFactory) LookupFile(blobDigest digest.Digest, isExecutable bool, fileReadMonitor FileReadMonitor) NativeLeaf {
+const (
+	resolvableCASFileFlagIsExecutable = 1 << iota
+	resolvableCASFileFlagHasLastDataModificationTime
+)
+
+func (cff *resolvableHandleAllocatingCASFileFactory) LookupFile(blobDigest digest.Digest, isExecutable bool, lastDataModificationTime time.Time, fileReadMonitor FileReadMonitor) NativeLeaf {
 	if fileReadMonitor != nil {
 		panic("Cannot monitor reads against CAS files with a resolvable handle, as the monitor would get lost across lookups")
 	}
 
-	var isExecutableField [1]byte
+	// Store the executable bit and the optional modification time
+	// in the file handle, so that the file can be reconstructed
+	// when resolved.
+	fields := make([]byte, 1, 1+binary.MaxVarintLen64)
 	if isExecutable {
-		isExecutableField[0] = 1
+		fields[0] |= resolvableCASFileFlagIsExecutable
+	}
+	if !lastDataModificationTime.IsZero() {
+		fields[0] |= resolvableCASFileFlagHasLastDataModificationTime
+		fields = binary.AppendVarint(fields, lastDataModificationTime.UnixNano())
 	}
 	return cff.allocator.
 		New(blobDigest).
 		AsResolvableAllocator(func(r io.ByteReader) (DirectoryChild, Status) {
 			return cff.resolve(blobDigest, r)
 		}).
-		New(bytes.NewBuffer(isExecutableField[:])).
-		AsNativeLeaf(cff.base.LookupFile(blobDigest, isExecutable, nil))
+		New(bytes.NewBuffer(fields)).
+		AsNativeLeaf(cff.base.LookupFile(blobDigest, isExecutable, lastDataModificationTime, nil))
 }
 
 func (cff *resolvableHandleAllocatingCASFileFactory) resolve(blobDigest digest.Digest, remainder io.ByteReader) (DirectoryChild, Status) {
-	isExecutable, err := remainder.ReadByte()
-	if err != nil {
+	flags, err := remainder.ReadByte()
+	if err != nil || flags&^(resolvableCASFileFlagIsExecutable|resolvableCASFileFlagHasLastDataModificationTime) != 0 {
 		return DirectoryChild{}, StatusErrBadHandle
 	}
-	switch isExecutable {
-	case 0:
-		return DirectoryChild{}.FromLeaf(cff.LookupFile(blobDigest, false, nil)), StatusOK
-	case 1:
-		return DirectoryChild{}.FromLeaf(cff.LookupFile(blobDigest, true, nil)), StatusOK
-	default:
-		return DirectoryChild{}, StatusErrBadHandle
+	var lastDataModificationTime time.Time
+	if flags&resolvableCASFileFlagHasLastDataModificationTime != 0 {
+		nanos, err := binary.ReadVarint(remainder)
+		if err != nil {
+			return DirectoryChild{}, StatusErrBadHandle
+		}
+		lastDataModificationTime = time.Unix(0, nanos)
 	}
+	isExecutable := flags&resolvableCASFileFlagIsExecutable != 0
+	return DirectoryChild{}.FromLeaf(cff.LookupFile(blobDigest, isExecutable, lastDataModificationTime, nil)), StatusOK
 }
@@ -0,0 +1,270 @@
+package smb3
+
+import (
+	"context"
+	"encoding/binary"
+	"strings"
+)
+
+// compoundState keeps track of the state that is carried over between
+// related requests that are part of the same compound request, as
+// described in [MS-SMB2] section 3.3.5.2.7.2.
+type compoundState struct {
+	sessionID uint64
+	treeID    uint32
+	fileID    [16]byte
+	hasFileID bool
+	status    ntStatus
+}
+
+// requestContext contains the state that is needed by handlers of
+// individual requests.
+type requestContext struct {
+	connection *connection
+	session    *session
+	treeID     uint32
+	message    []byte
+	compound   *compoundState
+}
+
+// body returns the body of the request, which follows the SMB2 header.
+func (rc *requestContext) body() []byte {
+	return rc.message[headerSize:]
+}
+
+// processCompound processes a message that consists of one or more
+// requests, and writes the responses back to the client.
+func (c *connection) processCompound(ctx context.Context, message []byte) {
+	var responses []byte
+	var compound compoundState
+	for {
+		h, ok := parseHeader(message)
+		if !ok {
+			return
+		}
+		request := message
+		if h.nextCommand != 0 {
+			if h.nextCommand%8 != 0 || h.nextCommand < headerSize || int(h.nextCommand) > len(message) {
+				return
+			}
+			request = message[:h.nextCommand]
+		}
+
+		response, signer := c.processRequest(ctx, &h, request, &compound)
+		if response != nil {
+			responseOffset := len(responses)
+			responses = append(responses, response...)
+			if h.nextCommand != 0 {
+				responses = appendPadding(responses, 8)
+				binary.LittleEndian.PutUint32(responses[responseOffset+20:], uint32(len(responses)-responseOffset))
+			}
+			if signer != nil {
+				signer.sign(responses[responseOffset:])
+			}
+		}
+
+		if h.nextCommand == 0 {
+			break
+		}
+		message = message[h.nextCommand:]
+	}
+	if len(responses) > 0 {
+		c.writeMessage(responses)
+	}
+}
+
+// processRequest processes a single request that is part of a
+// compound request. It returns the response that needs to be sent to
+// the client, and the signer that should be used to sign it.
+func (c *connection) processRequest(ctx context.Context, h *header, request []byte, compound *compoundState) ([]byte, *messageSigner) {
+	if h.flags&flagsRelatedOperations != 0 {
+		h.sessionID = compound.sessionID
+		h.treeID = compound.treeID
+	} else {
+		compound.hasFileID = false
+		compound.status = statusSuccess
+	}
+
+	var signer *messageSigner
+	status, body := func() (ntStatus, []byte) {
+		switch h.command {
+		case commandCancel:
+			// Requests are not processed asynchronously,
+			// meaning there is nothing to cancel.
+			return statusSuccess, nil
+		case commandEcho:
+			return statusSuccess, []byte{4, 0, 0, 0}
+		case commandNegotiate, commandSessionSetup:
+			return statusInvalidParameter, nil
+		}
+
+		c.lock.Lock()
+		s, ok := c.sessions[h.sessionID]
+		if !ok || s.signer == nil {
+			c.lock.Unlock()
+			return statusUserSessionDeleted, nil
+		}
+		_, hasTree := s.trees[h.treeID]
+		c.lock.Unlock()
+
+		// Validate the signature of the request. Responses
+		// are signed if the request is signed, or if signing
+		// is required.
+		if h.flags&flagsSigned != 0 {
+			if !s.signer.verify(request) {
+				return statusAccessDenied, nil
+			}
+			signer = s.signer
+		} else if s.signingRequired {
+			return statusAccessDenied, nil
+		}
+
+		rc := requestContext{
+			connection: c,
+			session:    s,
+			treeID:     h.treeID,
+			message:    request,
+			compound:   compound,
+		}
+		switch h.command {
+		case commandLogoff:
+			return rc.processLogoff()
+		case commandTreeConnect:
+			status, body := rc.processTreeConnect()
+			h.treeID = rc.treeID
+			return status, body
+		}
+
+		if !hasTree {
+			return statusNetworkNameDeleted, nil
+		}
+		switch h.command {
+		case commandTreeDisconnect:
+			return rc.processTreeDisconnect()
+		case commandCreate:
+			return rc.processCreate(ctx)
+		case commandClose:
+			return rc.processClose(ctx)
+		case commandFlush:
+			return rc.processFlush()
+		case commandRead:
+			return rc.processRead()
+		case commandWrite:
+			return rc.processWrite()
+		case commandLock:
+			return rc.processLock()
+		case commandIoctl:
+			return rc.processIoctl(ctx)
+		case commandQueryDirectory:
+			return rc.processQueryDirectory(ctx)
+		case commandQueryInfo:
+			return rc.processQueryInfo(ctx)
+		case commandSetInfo:
+			return rc.processSetInfo(ctx)
+		default:
+			// CHANGE_NOTIFY and OPLOCK_BREAK are not
+			// supported, as the server does not track
+			// changes, nor does it grant oplocks.
+			return statusNotSupported, nil
+		}
+	}()
+
+	compound.sessionID = h.sessionID
+	compound.treeID = h.treeID
+	if h.command == commandCreate && status != statusSuccess {
+		compound.hasFileID = false
+		compound.status = status
+	}
+	if h.command == commandCancel {
+		// CANCEL requests don't receive a response.
+		return nil, nil
+	}
+
+	if body == nil {
+		body = newErrorResponse(nil)
+	}
+	responseHeader := newResponseHeader(h, status)
+	return append(appendHeader(nil, &responseHeader), body...), signer
+}
+
+// processLogoff processes an SMB2 LOGOFF request, terminating the
+// session and closing all files opened through it.
+func (rc *requestContext) processLogoff() (ntStatus, []byte) {
+	c := rc.connection
+	c.lock.Lock()
+	delete(c.sessions, rc.session.id)
+	opens := c.removeOpensLocked(func(o *open) bool { return o.session == rc.session })
+	c.lock.Unlock()
+	for _, o := range opens {
+		o.close()
+	}
+	return statusSuccess, []byte{4, 0, 0, 0}
+}
+
+// processTreeConnect processes an SMB2 TREE_CONNECT request. The
+// server only provides a single share.
+func (rc *requestContext) processTreeConnect() (ntStatus, []byte) {
+	body := rc.body()
+	if len(body) < 8 {
+		return statusInvalidParameter, nil
+	}
+	pathBytes, ok := getBuffer(rc.message, uint32(binary.LittleEndian.Uint16(body[4:])), uint32(binary.LittleEndian.Uint16(body[6:])))
+	if !ok {
+		return statusInvalidParameter, nil
+	}
+	path, ok := decodeUTF16(pathBytes)
+	if !ok {
+		return statusInvalidParameter, nil
+	}
+
+	// Paths have the form \\server\share. The server name is
+	// ignored, as the client may use any address or host name to
+	// connect to the server.
+	if !strings.HasPrefix(path, `\\`) {
+		return statusBadNetworkName, nil
+	}
+	components := strings.Split(path[2:], `\`)
+	if len(components) != 2 || !strings.EqualFold(components[1], rc.connection.server.shareName) {
+		return statusBadNetworkName, nil
+	}
+
+	c := rc.connection
+	c.lock.Lock()
+	c.nextTreeID++
+	rc.treeID = c.nextTreeID
+	rc.session.trees[rc.treeID] = struct{}{}
+	c.lock.Unlock()
+
+	response := []byte{16, 0, shareTypeDisk, 0}
+	response = binary.LittleEndian.AppendUint32(response, shareFlagNoCaching)
+	response = binary.LittleEndian.AppendUint32(response, 0)
+	response = binary.LittleEndian.AppendUint32(response, fileAllAccess)
+	return statusSuccess, response
+}
+
+// processTreeDisconnect processes an SMB2 TREE_DISCONNECT request,
+// closing all files opened through the tree connection.
+func (rc *requestContext) processTreeDisconnect() (ntStatus, []byte) {
+	c := rc.connection
+	c.lock.Lock()
+	delete(rc.session.trees, rc.treeID)
+	opens := c.removeOpensLocked(func(o *open) bool { return o.session == rc.session && o.treeID == rc.treeID })
+	c.lock.Unlock()
+	for _, o := range opens {
+		o.close()
+	}
+	return statusSuccess, []byte{4, 0, 0, 0}
+}
+
+// removeOpensLocked removes all opened files matching a predicate
+// from the connection, returning them so that they can be closed.
+func (c *connection) removeOpensLocked(matches func(o *open) bool) []*open {
+	var opens []*open
+	for id, o := range c.opens {
+		if matches(o) {
+			opens = append(opens, o)
+			delete(c.opens, id)
+		}
+	}
+	return opens
+}
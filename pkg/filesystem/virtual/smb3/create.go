@@ -0,0 +1,485 @@
+package smb3
+
+import (
+	"context"
+	"encoding/binary"
+	"strings"
+	"sync"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+)
+
+// open holds the state of a file or directory that has been opened
+// by the client through a CREATE request.
+type open struct {
+	session *session
+	treeID  uint32
+	id      uint64
+
+	// The file that is opened. Exactly one of these fields is set.
+	directory virtual.Directory
+	leaf      virtual.Leaf
+	isSymlink bool
+
+	// The operations that were permitted when opening the leaf
+	// through VirtualOpenSelf() or VirtualOpenChild(). These need to
+	// be passed to VirtualClose() when the file is closed.
+	shareAccess virtual.ShareMask
+
+	lock sync.Mutex
+
+	// The directory containing the file, and the name under which
+	// it is stored. These are used to implement renaming and
+	// deletion. The parent is nil for the root directory.
+	parent        virtual.Directory
+	name          path.Component
+	deletePending bool
+
+	// State of the directory enumeration performed through
+	// QUERY_DIRECTORY.
+	enumerationPattern      []rune
+	enumerationCookie       uint64
+	enumerationDotsReturned int
+	enumerationDone         bool
+	enumerationAnyReturned  bool
+}
+
+// node returns the file or directory that is opened.
+func (o *open) node() virtual.Node {
+	if o.directory != nil {
+		return o.directory
+	}
+	return o.leaf
+}
+
+// fileID returns the SMB2_FILEID that the client uses to refer to the
+// opened file. As durable handles are not supported, the persistent
+// and volatile identifiers are identical.
+func (o *open) fileID() [16]byte {
+	var fileID [16]byte
+	binary.LittleEndian.PutUint64(fileID[:], o.id)
+	binary.LittleEndian.PutUint64(fileID[8:], o.id)
+	return fileID
+}
+
+// close the file, releasing the share access that was acquired when
+// opening it. If the file was marked for deletion, it is removed from
+// its parent directory.
+//
+// Unlike Windows, which only deletes files after the last handle has
+// been closed, files are deleted as soon as the handle through which
+// deletion was requested is closed.
+func (o *open) close() {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.shareAccess != 0 {
+		o.leaf.VirtualClose(o.shareAccess)
+	}
+	if o.deletePending {
+		o.parent.VirtualRemove(o.name, o.directory != nil, o.directory == nil)
+	}
+}
+
+// getOpen looks up a file that was opened by the client. If the
+// FileId is the wildcard value, the file that was used by the previous
+// request in the compound request is returned.
+func (rc *requestContext) getOpen(fileIDBytes []byte) (*open, ntStatus) {
+	var fileID [16]byte
+	copy(fileID[:], fileIDBytes)
+	if fileID == fileIDWildcard {
+		if !rc.compound.hasFileID {
+			if rc.compound.status != statusSuccess {
+				return nil, rc.compound.status
+			}
+			return nil, statusFileClosed
+		}
+		fileID = rc.compound.fileID
+	}
+
+	c := rc.connection
+	c.lock.Lock()
+	o, ok := c.opens[binary.LittleEndian.Uint64(fileID[:])]
+	c.lock.Unlock()
+	if !ok || o.session != rc.session || o.treeID != rc.treeID || o.fileID() != fileID {
+		return nil, statusFileClosed
+	}
+	rc.compound.fileID = fileID
+	rc.compound.hasFileID = true
+	return o, statusSuccess
+}
+
+// parsePath splits a path name provided by the client into
+// components. Path names are relative to the root of the share, and
+// use backslashes as separators.
+func parsePath(p string) ([]path.Component, ntStatus) {
+	if p == "" {
+		return nil, statusSuccess
+	}
+	var components []path.Component
+	for _, name := range strings.Split(p, `\`) {
+		component, ok := path.NewComponent(name)
+		if !ok {
+			return nil, statusObjectNameInvalid
+		}
+		components = append(components, component)
+	}
+	return components, statusSuccess
+}
+
+// parseCreateContexts returns the names of the create contexts that
+// are provided as part of a CREATE request, as described in [MS-SMB2]
+// section 2.2.13.2.
+func parseCreateContexts(message []byte, offset, length uint32) (map[string]struct{}, bool) {
+	names := map[string]struct{}{}
+	b, ok := getBuffer(message, offset, length)
+	if !ok {
+		return nil, false
+	}
+	for len(b) > 0 {
+		if len(b) < 16 {
+			return nil, false
+		}
+		name, ok := getBuffer(b, uint32(binary.LittleEndian.Uint16(b[4:])), uint32(binary.LittleEndian.Uint16(b[6:])))
+		if !ok {
+			return nil, false
+		}
+		names[string(name)] = struct{}{}
+
+		next := binary.LittleEndian.Uint32(b)
+		if next == 0 {
+			break
+		}
+		if next%8 != 0 || next < 16 || int(next) > len(b) {
+			return nil, false
+		}
+		b = b[next:]
+	}
+	return names, true
+}
+
+// newCreateContext creates a create context that is returned as part
+// of a CREATE response.
+func newCreateContext(name string, data []byte) []byte {
+	b := binary.LittleEndian.AppendUint32(nil, 0)
+	b = binary.LittleEndian.AppendUint16(b, 16)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(name)))
+	b = binary.LittleEndian.AppendUint16(b, 0)
+	b = binary.LittleEndian.AppendUint16(b, 24)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(data)))
+	b = append(b, name...)
+	b = appendPadding(b, 8)
+	return append(b, data...)
+}
+
+// newStoppedOnSymlinkResponse creates a response that is returned when
+// a path passed to CREATE resolves to a symbolic link. The client is
+// expected to resolve the symbolic link itself.
+func newStoppedOnSymlinkResponse(ctx context.Context, leaf virtual.Leaf, remaining []path.Component) (ntStatus, []byte) {
+	target, s := leaf.VirtualReadlink(ctx)
+	if s != virtual.StatusOK {
+		return toNTStatus(s), nil
+	}
+	convertedTarget, ok := convertSymlinkTarget(target)
+	if !ok {
+		return statusObjectPathNotFound, nil
+	}
+	unparsedPathLength := 0
+	for _, component := range remaining {
+		unparsedPathLength += len(encodeUTF16(`\` + component.String()))
+	}
+	return statusStoppedOnSymlink, newErrorResponse(newSymlinkErrorResponse(convertedTarget, unparsedPathLength))
+}
+
+// getShareAccess determines which operations need to be permitted on
+// a leaf, based on the access mask provided by the client. Write
+// access is only granted to clients requesting MAXIMUM_ALLOWED if the
+// file is writable.
+func getShareAccess(desiredAccess uint32, permissions virtual.Permissions) virtual.ShareMask {
+	var shareAccess virtual.ShareMask
+	if desiredAccess&accessReadDataMask != 0 {
+		shareAccess |= virtual.ShareMaskRead
+	}
+	if desiredAccess&accessWriteDataExplicit != 0 || (desiredAccess&accessMaximumAllowed != 0 && permissions&virtual.PermissionsWrite != 0) {
+		shareAccess |= virtual.ShareMaskWrite
+	}
+	return shareAccess
+}
+
+// processCreate processes an SMB2 CREATE request, as described in
+// [MS-SMB2] section 3.3.5.9. Opportunistic locks, leases and durable
+// handles are not supported.
+func (rc *requestContext) processCreate(ctx context.Context) (ntStatus, []byte) {
+	body := rc.body()
+	if len(body) < 56 {
+		return statusInvalidParameter, nil
+	}
+	desiredAccess := binary.LittleEndian.Uint32(body[24:])
+	createDisposition := binary.LittleEndian.Uint32(body[36:])
+	createOptions := binary.LittleEndian.Uint32(body[40:])
+	nameBytes, ok := getBuffer(rc.message, uint32(binary.LittleEndian.Uint16(body[44:])), uint32(binary.LittleEndian.Uint16(body[46:])))
+	if !ok {
+		return statusInvalidParameter, nil
+	}
+	name, ok := decodeUTF16(nameBytes)
+	if !ok {
+		return statusObjectNameInvalid, nil
+	}
+	createContexts, ok := parseCreateContexts(rc.message, binary.LittleEndian.Uint32(body[48:]), binary.LittleEndian.Uint32(body[52:]))
+	if !ok {
+		return statusInvalidParameter, nil
+	}
+	if createDisposition > createDispositionOverwriteIf || createOptions&(createOptionDirectoryFile|createOptionNonDirectoryFile) == createOptionDirectoryFile|createOptionNonDirectoryFile {
+		return statusInvalidParameter, nil
+	}
+	for _, reconnect := range []string{"DHnC", "DH2C"} {
+		if _, ok := createContexts[reconnect]; ok {
+			// Durable handles are never granted, meaning
+			// they can also not be reconnected.
+			return statusObjectNameNotFound, nil
+		}
+	}
+
+	// Alternate data streams are not supported. Only permit
+	// access to the default data stream.
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		if !strings.EqualFold(name[i:], "::$DATA") {
+			return statusObjectNameNotFound, nil
+		}
+		name = name[:i]
+	}
+	components, status := parsePath(name)
+	if status != statusSuccess {
+		return status, nil
+	}
+
+	// Resolve the directory containing the file.
+	directory := rc.connection.server.rootDirectory
+	var attributes virtual.Attributes
+	for i := 0; i+1 < len(components); i++ {
+		child, s := directory.VirtualLookup(ctx, components[i], virtual.AttributesMaskFileType, &attributes)
+		if s != virtual.StatusOK {
+			if s == virtual.StatusErrNoEnt {
+				return statusObjectPathNotFound, nil
+			}
+			return toNTStatus(s), nil
+		}
+		childDirectory, childLeaf := child.GetPair()
+		if childDirectory == nil {
+			if attributes.GetFileType() == filesystem.FileTypeSymlink {
+				return newStoppedOnSymlinkResponse(ctx, childLeaf, components[i+1:])
+			}
+			return statusObjectPathNotFound, nil
+		}
+		directory = childDirectory
+	}
+
+	o := &open{
+		session: rc.session,
+		treeID:  rc.treeID,
+	}
+	deleteOnClose := createOptions&createOptionDeleteOnClose != 0
+	createAction := uint32(createActionOpened)
+	if len(components) == 0 {
+		// Opening the root directory.
+		if deleteOnClose {
+			return statusCannotDelete, nil
+		}
+		if status := checkOpenExistingDirectory(createDisposition, createOptions); status != statusSuccess {
+			return status, nil
+		}
+		directory.VirtualGetAttributes(ctx, fileInformationAttributesMask, &attributes)
+		o.directory = directory
+	} else {
+		o.parent = directory
+		o.name = components[len(components)-1]
+		child, s := directory.VirtualLookup(ctx, o.name, fileInformationAttributesMask, &attributes)
+		switch s {
+		case virtual.StatusOK:
+			childDirectory, childLeaf := child.GetPair()
+			if childDirectory != nil {
+				if status := checkOpenExistingDirectory(createDisposition, createOptions); status != statusSuccess {
+					return status, nil
+				}
+				o.directory = childDirectory
+				break
+			}
+
+			if createDisposition == createDispositionCreate {
+				return statusObjectNameCollision, nil
+			}
+			if createOptions&createOptionDirectoryFile != 0 {
+				return statusNotADirectory, nil
+			}
+			truncate := createDisposition == createDispositionSupersede || createDisposition == createDispositionOverwrite || createDisposition == createDispositionOverwriteIf
+			if attributes.GetFileType() == filesystem.FileTypeSymlink {
+				if createOptions&createOptionOpenReparsePoint == 0 {
+					return newStoppedOnSymlinkResponse(ctx, childLeaf, nil)
+				}
+				if truncate {
+					return statusInvalidParameter, nil
+				}
+				o.isSymlink = true
+			} else {
+				permissions, _ := attributes.GetPermissions()
+				if (desiredAccess&accessWriteDataExplicit != 0 || truncate) && permissions&virtual.PermissionsWrite == 0 {
+					return statusAccessDenied, nil
+				}
+				shareAccess := getShareAccess(desiredAccess, permissions)
+				if truncate {
+					shareAccess |= virtual.ShareMaskWrite
+					if createDisposition == createDispositionSupersede {
+						createAction = createActionSuperseded
+					} else {
+						createAction = createActionOverwritten
+					}
+				}
+				if shareAccess != 0 {
+					if s := childLeaf.VirtualOpenSelf(ctx, shareAccess, &virtual.OpenExistingOptions{Truncate: truncate}, fileInformationAttributesMask, &attributes); s != virtual.StatusOK {
+						return toNTStatus(s), nil
+					}
+				}
+				o.shareAccess = shareAccess
+			}
+			o.leaf = childLeaf
+		case virtual.StatusErrNoEnt:
+			if createDisposition == createDispositionOpen || createDisposition == createDispositionOverwrite {
+				return statusObjectNameNotFound, nil
+			}
+			if createOptions&createOptionDirectoryFile != 0 {
+				childDirectory, _, s := directory.VirtualMkdir(o.name, fileInformationAttributesMask, &attributes)
+				if s != virtual.StatusOK {
+					return toNTStatus(s), nil
+				}
+				o.directory = childDirectory
+			} else {
+				// Newly created files are always opened
+				// for reading, so that they can be
+				// closed consistently.
+				shareAccess := getShareAccess(desiredAccess, virtual.PermissionsWrite) | virtual.ShareMaskRead
+				leaf, _, _, s := directory.VirtualOpenChild(
+					ctx,
+					o.name,
+					shareAccess,
+					(&virtual.Attributes{}).SetPermissions(virtual.PermissionsRead|virtual.PermissionsWrite),
+					nil,
+					fileInformationAttributesMask,
+					&attributes)
+				if s != virtual.StatusOK {
+					return toNTStatus(s), nil
+				}
+				o.leaf = leaf
+				o.shareAccess = shareAccess
+			}
+			createAction = createActionCreated
+		default:
+			return toNTStatus(s), nil
+		}
+		o.deletePending = deleteOnClose
+	}
+
+	c := rc.connection
+	c.lock.Lock()
+	c.nextOpenID++
+	o.id = c.nextOpenID
+	c.opens[o.id] = o
+	c.lock.Unlock()
+	fileID := o.fileID()
+	rc.compound.fileID = fileID
+	rc.compound.hasFileID = true
+
+	fi := newFileInformation(&attributes)
+	b := []byte{89, 0, 0, 0}
+	b = binary.LittleEndian.AppendUint32(b, createAction)
+	b = fi.appendNetworkOpenInformation(b)
+	b = binary.LittleEndian.AppendUint32(b, 0)
+	b = append(b, fileID[:]...)
+	createContextsFields := len(b)
+	b = binary.LittleEndian.AppendUint64(b, 0)
+
+	// Respond to create contexts that query information about
+	// the file.
+	var responseCreateContexts [][]byte
+	if _, ok := createContexts["MxAc"]; ok {
+		data := binary.LittleEndian.AppendUint32(nil, uint32(statusSuccess))
+		data = binary.LittleEndian.AppendUint32(data, fileAllAccess)
+		responseCreateContexts = append(responseCreateContexts, newCreateContext("MxAc", data))
+	}
+	if _, ok := createContexts["QFid"]; ok {
+		data := binary.LittleEndian.AppendUint64(nil, fi.inodeNumber)
+		data = append(data, make([]byte, 24)...)
+		responseCreateContexts = append(responseCreateContexts, newCreateContext("QFid", data))
+	}
+	if len(responseCreateContexts) > 0 {
+		createContextsOffset := len(b)
+		previousCreateContext := -1
+		for _, createContext := range responseCreateContexts {
+			b = appendPadding(b, 8)
+			if previousCreateContext >= 0 {
+				binary.LittleEndian.PutUint32(b[previousCreateContext:], uint32(len(b)-previousCreateContext))
+			}
+			previousCreateContext = len(b)
+			b = append(b, createContext...)
+		}
+		binary.LittleEndian.PutUint32(b[createContextsFields:], uint32(headerSize+createContextsOffset))
+		binary.LittleEndian.PutUint32(b[createContextsFields+4:], uint32(len(b)-createContextsOffset))
+	}
+	return statusSuccess, b
+}
+
+// checkOpenExistingDirectory checks whether the options provided to
+// CREATE permit opening an existing directory.
+func checkOpenExistingDirectory(createDisposition, createOptions uint32) ntStatus {
+	if createOptions&createOptionNonDirectoryFile != 0 {
+		return statusFileIsADirectory
+	}
+	switch createDisposition {
+	case createDispositionCreate:
+		return statusObjectNameCollision
+	case createDispositionOpen, createDispositionOpenIf:
+		return statusSuccess
+	default:
+		// Directories cannot be overwritten.
+		return statusInvalidParameter
+	}
+}
+
+// processClose processes an SMB2 CLOSE request, as described in
+// [MS-SMB2] section 3.3.5.10.
+func (rc *requestContext) processClose(ctx context.Context) (ntStatus, []byte) {
+	body := rc.body()
+	if len(body) < 24 {
+		return statusInvalidParameter, nil
+	}
+	flags := binary.LittleEndian.Uint16(body[2:])
+	o, status := rc.getOpen(body[8:24])
+	if status != statusSuccess {
+		return status, nil
+	}
+
+	c := rc.connection
+	c.lock.Lock()
+	_, ok := c.opens[o.id]
+	delete(c.opens, o.id)
+	c.lock.Unlock()
+	if !ok {
+		// File was closed concurrently.
+		return statusFileClosed, nil
+	}
+
+	b := []byte{60, 0}
+	if flags&closeFlagPostqueryAttrib != 0 {
+		var attributes virtual.Attributes
+		o.node().VirtualGetAttributes(ctx, fileInformationAttributesMask, &attributes)
+		fi := newFileInformation(&attributes)
+		b = binary.LittleEndian.AppendUint16(b, closeFlagPostqueryAttrib)
+		b = binary.LittleEndian.AppendUint32(b, 0)
+		b = fi.appendNetworkOpenInformation(b)
+	} else {
+		b = append(b, make([]byte, 58)...)
+	}
+	o.close()
+	return statusSuccess, b
+}
@@ -0,0 +1,323 @@
+package smb3
+
+import (
+	"encoding/binary"
+	"strings"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+)
+
+// fileInformationAttributesMask is the set of attributes that need to
+// be requested from the virtual file system to construct a
+// fileInformation.
+const fileInformationAttributesMask = virtual.AttributesMaskFileType |
+	virtual.AttributesMaskInodeNumber |
+	virtual.AttributesMaskLastDataModificationTime |
+	virtual.AttributesMaskLinkCount |
+	virtual.AttributesMaskPermissions |
+	virtual.AttributesMaskSizeBytes
+
+// fileInformation contains the properties of a file, converted to the
+// representation used by SMB2.
+type fileInformation struct {
+	fileType    filesystem.FileType
+	inodeNumber uint64
+	time        uint64
+	linkCount   uint32
+	sizeBytes   uint64
+	readOnly    bool
+}
+
+func newFileInformation(attributes *virtual.Attributes) fileInformation {
+	fi := fileInformation{
+		fileType:    attributes.GetFileType(),
+		inodeNumber: attributes.GetInodeNumber(),
+		time:        timeToFiletime(filesystem.DeterministicFileModificationTimestamp),
+		linkCount:   attributes.GetLinkCount(),
+	}
+	// Windows does not have a notion of the last status change
+	// time and creation time that is compatible with POSIX. Report
+	// the last data modification time for all of them.
+	if t, ok := attributes.GetLastDataModificationTime(); ok {
+		fi.time = timeToFiletime(t)
+	}
+	if fi.fileType != filesystem.FileTypeDirectory {
+		if sizeBytes, ok := attributes.GetSizeBytes(); ok {
+			fi.sizeBytes = sizeBytes
+		}
+		if permissions, ok := attributes.GetPermissions(); ok {
+			fi.readOnly = permissions&virtual.PermissionsWrite == 0
+		}
+	}
+	return fi
+}
+
+// fileAttributes returns the attributes of the file, as described in
+// [MS-FSCC] section 2.6.
+func (fi *fileInformation) fileAttributes() uint32 {
+	var attributes uint32
+	switch fi.fileType {
+	case filesystem.FileTypeDirectory:
+		attributes |= fileAttributeDirectory
+	case filesystem.FileTypeSymlink:
+		attributes |= fileAttributeReparsePoint
+	}
+	if fi.readOnly {
+		attributes |= fileAttributeReadonly
+	}
+	if attributes == 0 {
+		attributes = fileAttributeNormal
+	}
+	return attributes
+}
+
+// reparseTag returns the reparse tag of the file, or zero if the file
+// is not a reparse point.
+func (fi *fileInformation) reparseTag() uint32 {
+	if fi.fileType == filesystem.FileTypeSymlink {
+		return reparseTagSymlink
+	}
+	return 0
+}
+
+// allocationSize returns the amount of space that is allocated for
+// the file, which is its size rounded up to the block size.
+func (fi *fileInformation) allocationSize() uint64 {
+	return (fi.sizeBytes + fileFsBytesPerSector - 1) / fileFsBytesPerSector * fileFsBytesPerSector
+}
+
+// appendTimes appends the creation, last access, last write and
+// change times of a file to a buffer.
+func (fi *fileInformation) appendTimes(b []byte) []byte {
+	for i := 0; i < 4; i++ {
+		b = binary.LittleEndian.AppendUint64(b, fi.time)
+	}
+	return b
+}
+
+// appendNetworkOpenInformation appends the fields that are shared by
+// CREATE and CLOSE responses and FILE_NETWORK_OPEN_INFORMATION to a
+// buffer.
+func (fi *fileInformation) appendNetworkOpenInformation(b []byte) []byte {
+	b = fi.appendTimes(b)
+	b = binary.LittleEndian.AppendUint64(b, fi.allocationSize())
+	b = binary.LittleEndian.AppendUint64(b, fi.sizeBytes)
+	return binary.LittleEndian.AppendUint32(b, fi.fileAttributes())
+}
+
+// appendBasicInformation appends FILE_BASIC_INFORMATION to a buffer.
+func (fi *fileInformation) appendBasicInformation(b []byte) []byte {
+	b = fi.appendTimes(b)
+	b = binary.LittleEndian.AppendUint32(b, fi.fileAttributes())
+	return binary.LittleEndian.AppendUint32(b, 0)
+}
+
+// appendStandardInformation appends FILE_STANDARD_INFORMATION to a
+// buffer.
+func (fi *fileInformation) appendStandardInformation(b []byte, deletePending bool) []byte {
+	b = binary.LittleEndian.AppendUint64(b, fi.allocationSize())
+	b = binary.LittleEndian.AppendUint64(b, fi.sizeBytes)
+	linkCount := fi.linkCount
+	if linkCount == 0 {
+		linkCount = 1
+	}
+	b = binary.LittleEndian.AppendUint32(b, linkCount)
+	b = appendBool(b, deletePending)
+	b = appendBool(b, fi.fileType == filesystem.FileTypeDirectory)
+	return binary.LittleEndian.AppendUint16(b, 0)
+}
+
+func appendBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 1)
+	}
+	return append(b, 0)
+}
+
+// appendDirectoryEntry appends an entry of a directory listing to a
+// buffer, using one of the formats that may be requested through
+// QUERY_DIRECTORY. The NextEntryOffset field is left zero.
+func (fi *fileInformation) appendDirectoryEntry(b []byte, fileInformationClass uint8, name string) []byte {
+	encodedName := encodeUTF16(name)
+	b = binary.LittleEndian.AppendUint32(b, 0)
+	b = binary.LittleEndian.AppendUint32(b, 0)
+	if fileInformationClass == fileNamesInformation {
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(encodedName)))
+		return append(b, encodedName...)
+	}
+
+	b = fi.appendTimes(b)
+	b = binary.LittleEndian.AppendUint64(b, fi.sizeBytes)
+	b = binary.LittleEndian.AppendUint64(b, fi.allocationSize())
+	b = binary.LittleEndian.AppendUint32(b, fi.fileAttributes())
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(encodedName)))
+	if fileInformationClass != fileDirectoryInformation {
+		// For reparse points, the EaSize field contains the
+		// reparse tag.
+		b = binary.LittleEndian.AppendUint32(b, fi.reparseTag())
+	}
+	switch fileInformationClass {
+	case fileBothDirectoryInformation:
+		// No short names are provided.
+		b = append(b, make([]byte, 26)...)
+	case fileIDBothDirectoryInformation:
+		b = append(b, make([]byte, 28)...)
+		b = binary.LittleEndian.AppendUint64(b, fi.inodeNumber)
+	case fileIDFullDirectoryInformation:
+		b = binary.LittleEndian.AppendUint32(b, 0)
+		b = binary.LittleEndian.AppendUint64(b, fi.inodeNumber)
+	}
+	return append(b, encodedName...)
+}
+
+// isDirectoryInformationClassSupported returns whether a file
+// information class can be used for QUERY_DIRECTORY.
+func isDirectoryInformationClassSupported(fileInformationClass uint8) bool {
+	switch fileInformationClass {
+	case fileDirectoryInformation, fileFullDirectoryInformation, fileBothDirectoryInformation, fileNamesInformation, fileIDBothDirectoryInformation, fileIDFullDirectoryInformation:
+		return true
+	default:
+		return false
+	}
+}
+
+// matchPattern returns whether a file name matches a search pattern
+// provided to QUERY_DIRECTORY, as described in [MS-FSA] section
+// 2.1.4.4. Matching is performed case insensitively.
+func matchPattern(pattern, name []rune) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*', '<':
+			for i := len(name); i >= 0; i-- {
+				if matchPattern(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?', '>':
+			if len(name) == 0 {
+				// The DOS_QM wildcard matches the end of
+				// the name.
+				if pattern[0] == '>' {
+					pattern = pattern[1:]
+					continue
+				}
+				return false
+			}
+		case '"':
+			if len(name) > 0 && name[0] != '.' {
+				return false
+			}
+			if len(name) == 0 {
+				pattern = pattern[1:]
+				continue
+			}
+		default:
+			if len(name) == 0 || !strings.EqualFold(string(pattern[0]), string(name[0])) {
+				return false
+			}
+		}
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+	return len(name) == 0
+}
+
+// hasWildcards returns whether a search pattern provided to
+// QUERY_DIRECTORY contains any wildcards.
+func hasWildcards(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?<>"`)
+}
+
+// newSecurityDescriptor creates a self-relative security descriptor
+// that grants full access to everyone, as described in [MS-DTYP]
+// section 2.4.6. The virtual file system has no notion of access
+// control lists, meaning access checks are left to the client.
+func newSecurityDescriptor(additionalInformation uint32, isDirectory bool) []byte {
+	const (
+		ownerSecurityInformation = 0x00000001
+		groupSecurityInformation = 0x00000002
+		daclSecurityInformation  = 0x00000004
+	)
+	// S-1-1-0, also known as "Everyone".
+	everyoneSID := []byte{1, 1, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0}
+
+	b := []byte{1, 0}
+	b = binary.LittleEndian.AppendUint16(b, securityDescriptorSelfRelative|securityDescriptorDACLPresent)
+	offsets := len(b)
+	b = append(b, make([]byte, 16)...)
+	if additionalInformation&ownerSecurityInformation != 0 {
+		binary.LittleEndian.PutUint32(b[offsets:], uint32(len(b)))
+		b = append(b, everyoneSID...)
+	}
+	if additionalInformation&groupSecurityInformation != 0 {
+		binary.LittleEndian.PutUint32(b[offsets+4:], uint32(len(b)))
+		b = append(b, everyoneSID...)
+	}
+	if additionalInformation&daclSecurityInformation != 0 {
+		binary.LittleEndian.PutUint32(b[offsets+12:], uint32(len(b)))
+		aceSize := 8 + len(everyoneSID)
+		b = append(b, 2, 0)
+		b = binary.LittleEndian.AppendUint16(b, uint16(8+aceSize))
+		b = binary.LittleEndian.AppendUint16(b, 1)
+		b = binary.LittleEndian.AppendUint16(b, 0)
+
+		var aceFlags byte
+		if isDirectory {
+			aceFlags = aceFlagsObjectAndContainerInherit
+		}
+		b = append(b, aceTypeAccessAllowed, aceFlags)
+		b = binary.LittleEndian.AppendUint16(b, uint16(aceSize))
+		b = binary.LittleEndian.AppendUint32(b, fileAllAccess)
+		b = append(b, everyoneSID...)
+	}
+	return b
+}
+
+// convertSymlinkTarget converts the target of a symbolic link to the
+// format used by Windows. Only relative targets can be converted, as
+// absolute paths refer to locations outside the share.
+func convertSymlinkTarget(target []byte) ([]byte, bool) {
+	if len(target) == 0 || target[0] == '/' {
+		return nil, false
+	}
+	return encodeUTF16(strings.ReplaceAll(string(target), "/", `\`)), true
+}
+
+// appendSymlinkReparseData appends the fields of a symbolic link
+// reparse data buffer that follow the ReparseDataLength field, as
+// described in [MS-FSCC] section 2.1.2.4.
+func appendSymlinkReparseData(b []byte, target []byte) []byte {
+	b = binary.LittleEndian.AppendUint16(b, 0)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(target)))
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(target)))
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(target)))
+	b = binary.LittleEndian.AppendUint32(b, symlinkFlagRelative)
+	b = append(b, target...)
+	return append(b, target...)
+}
+
+// newSymlinkReparseDataBuffer creates a reparse data buffer for a
+// symbolic link, as returned by FSCTL_GET_REPARSE_POINT.
+func newSymlinkReparseDataBuffer(target []byte) []byte {
+	b := binary.LittleEndian.AppendUint32(nil, reparseTagSymlink)
+	b = binary.LittleEndian.AppendUint16(b, uint16(12+2*len(target)))
+	b = binary.LittleEndian.AppendUint16(b, 0)
+	return appendSymlinkReparseData(b, target)
+}
+
+// newSymlinkErrorResponse creates the error data that is returned
+// when a path resolves to a symbolic link, as described in [MS-SMB2]
+// section 2.2.2.2.1. The client is expected to resolve the symbolic
+// link, and to reissue the request against the resulting path.
+func newSymlinkErrorResponse(target []byte, unparsedPathLength int) []byte {
+	b := binary.LittleEndian.AppendUint32(nil, 0)
+	b = binary.LittleEndian.AppendUint32(b, symlinkErrorTag)
+	b = binary.LittleEndian.AppendUint32(b, reparseTagSymlink)
+	b = binary.LittleEndian.AppendUint16(b, uint16(12+2*len(target)))
+	b = binary.LittleEndian.AppendUint16(b, uint16(unparsedPathLength))
+	b = appendSymlinkReparseData(b, target)
+	binary.LittleEndian.PutUint32(b, uint32(len(b)-4))
+	return b
+}
@@ -0,0 +1,159 @@
+package smb3
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+)
+
+// processRead processes an SMB2 READ request, as described in
+// [MS-SMB2] section 3.3.5.12.
+func (rc *requestContext) processRead() (ntStatus, []byte) {
+	body := rc.body()
+	if len(body) < 48 {
+		return statusInvalidParameter, nil
+	}
+	length := binary.LittleEndian.Uint32(body[4:])
+	offset := binary.LittleEndian.Uint64(body[8:])
+	minimumCount := binary.LittleEndian.Uint32(body[32:])
+	o, status := rc.getOpen(body[16:32])
+	if status != statusSuccess {
+		return status, nil
+	}
+	if o.directory != nil {
+		return statusInvalidDeviceRequest, nil
+	}
+	if o.shareAccess&virtual.ShareMaskRead == 0 {
+		return statusAccessDenied, nil
+	}
+	if length > maximumIOSize {
+		return statusInvalidParameter, nil
+	}
+
+	b := []byte{17, 0, headerSize + 16, 0}
+	b = binary.LittleEndian.AppendUint32(b, 0)
+	b = binary.LittleEndian.AppendUint64(b, 0)
+	b = append(b, make([]byte, length)...)
+	n, _, s := o.leaf.VirtualRead(b[16:], offset)
+	if s != virtual.StatusOK {
+		return toNTStatus(s), nil
+	}
+	if uint32(n) < minimumCount || (n == 0 && length > 0) {
+		return statusEndOfFile, nil
+	}
+	binary.LittleEndian.PutUint32(b[4:], uint32(n))
+	return statusSuccess, b[:16+n]
+}
+
+// processWrite processes an SMB2 WRITE request, as described in
+// [MS-SMB2] section 3.3.5.13.
+func (rc *requestContext) processWrite() (ntStatus, []byte) {
+	body := rc.body()
+	if len(body) < 48 {
+		return statusInvalidParameter, nil
+	}
+	data, ok := getBuffer(rc.message, uint32(binary.LittleEndian.Uint16(body[2:])), binary.LittleEndian.Uint32(body[4:]))
+	if !ok {
+		return statusInvalidParameter, nil
+	}
+	offset := binary.LittleEndian.Uint64(body[8:])
+	o, status := rc.getOpen(body[16:32])
+	if status != statusSuccess {
+		return status, nil
+	}
+	if o.directory != nil {
+		return statusInvalidDeviceRequest, nil
+	}
+	if o.shareAccess&virtual.ShareMaskWrite == 0 {
+		return statusAccessDenied, nil
+	}
+
+	n, s := o.leaf.VirtualWrite(data, offset)
+	if s != virtual.StatusOK {
+		return toNTStatus(s), nil
+	}
+	b := []byte{17, 0, 0, 0}
+	b = binary.LittleEndian.AppendUint32(b, uint32(n))
+	return statusSuccess, append(b, make([]byte, 9)...)
+}
+
+// processFlush processes an SMB2 FLUSH request. As the virtual file
+// system does not provide any persistence, this is a no-op.
+func (rc *requestContext) processFlush() (ntStatus, []byte) {
+	body := rc.body()
+	if len(body) < 24 {
+		return statusInvalidParameter, nil
+	}
+	if _, status := rc.getOpen(body[8:24]); status != statusSuccess {
+		return status, nil
+	}
+	return statusSuccess, []byte{4, 0, 0, 0}
+}
+
+// processLock processes an SMB2 LOCK request. Byte range locks are
+// not enforced by the server, meaning that all lock requests succeed.
+func (rc *requestContext) processLock() (ntStatus, []byte) {
+	body := rc.body()
+	if len(body) < 48 {
+		return statusInvalidParameter, nil
+	}
+	if _, status := rc.getOpen(body[8:24]); status != statusSuccess {
+		return status, nil
+	}
+	return statusSuccess, []byte{4, 0, 0, 0}
+}
+
+// processIoctl processes an SMB2 IOCTL request, as described in
+// [MS-SMB2] section 3.3.5.15. The only file system control code that
+// is supported is FSCTL_GET_REPARSE_POINT, which clients use to read
+// the targets of symbolic links.
+func (rc *requestContext) processIoctl(ctx context.Context) (ntStatus, []byte) {
+	body := rc.body()
+	if len(body) < 56 {
+		return statusInvalidParameter, nil
+	}
+	ctlCode := binary.LittleEndian.Uint32(body[4:])
+	maximumOutputResponse := binary.LittleEndian.Uint32(body[44:])
+	if binary.LittleEndian.Uint32(body[48:])&ioctlFlagIsFSCTL == 0 {
+		return statusNotSupported, nil
+	}
+
+	var output []byte
+	switch ctlCode {
+	case fsctlDFSGetReferrals, fsctlDFSGetReferralsEx:
+		return statusFSDriverRequired, nil
+	case fsctlGetReparsePoint:
+		o, status := rc.getOpen(body[8:24])
+		if status != statusSuccess {
+			return status, nil
+		}
+		if !o.isSymlink {
+			return statusNotAReparsePoint, nil
+		}
+		target, s := o.leaf.VirtualReadlink(ctx)
+		if s != virtual.StatusOK {
+			return toNTStatus(s), nil
+		}
+		convertedTarget, ok := convertSymlinkTarget(target)
+		if !ok {
+			return statusNotSupported, nil
+		}
+		output = newSymlinkReparseDataBuffer(convertedTarget)
+		if uint32(len(output)) > maximumOutputResponse {
+			return statusBufferTooSmall, nil
+		}
+	default:
+		return statusInvalidDeviceRequest, nil
+	}
+
+	b := []byte{49, 0, 0, 0}
+	b = binary.LittleEndian.AppendUint32(b, ctlCode)
+	b = append(b, body[8:24]...)
+	b = binary.LittleEndian.AppendUint32(b, headerSize+48)
+	b = binary.LittleEndian.AppendUint32(b, 0)
+	b = binary.LittleEndian.AppendUint32(b, headerSize+48)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(output)))
+	b = binary.LittleEndian.AppendUint64(b, 0)
+	return statusSuccess, append(b, output...)
+}
@@ -0,0 +1,357 @@
+package smb3
+
+import (
+	"encoding/binary"
+	"time"
+	"unicode/utf16"
+)
+
+// Constants of the SMB2 protocol family, as described in [MS-SMB2].
+// Only the subset that is needed to implement the SMB 3.1.1 dialect
+// is declared.
+
+const (
+	// Size of the SMB2 packet header.
+	headerSize = 64
+
+	dialectSMB311   = 0x0311
+	dialectWildcard = 0x02ff
+
+	// Maximum read, write and transaction sizes announced to the
+	// client. As multi-credit requests are supported, these may
+	// exceed 64 KiB.
+	maximumIOSize = 1 << 20
+)
+
+// Values of the Command field of the SMB2 packet header.
+const (
+	commandNegotiate      = 0x0000
+	commandSessionSetup   = 0x0001
+	commandLogoff         = 0x0002
+	commandTreeConnect    = 0x0003
+	commandTreeDisconnect = 0x0004
+	commandCreate         = 0x0005
+	commandClose          = 0x0006
+	commandFlush          = 0x0007
+	commandRead           = 0x0008
+	commandWrite          = 0x0009
+	commandLock           = 0x000a
+	commandIoctl          = 0x000b
+	commandCancel         = 0x000c
+	commandEcho           = 0x000d
+	commandQueryDirectory = 0x000e
+	commandChangeNotify   = 0x000f
+	commandQueryInfo      = 0x0010
+	commandSetInfo        = 0x0011
+	commandOplockBreak    = 0x0012
+)
+
+// Values of the Flags field of the SMB2 packet header.
+const (
+	flagsServerToRedir     = 0x00000001
+	flagsAsyncCommand      = 0x00000002
+	flagsRelatedOperations = 0x00000004
+	flagsSigned            = 0x00000008
+)
+
+// Values of the SecurityMode field of NEGOTIATE and SESSION_SETUP.
+const (
+	securityModeSigningEnabled  = 0x0001
+	securityModeSigningRequired = 0x0002
+)
+
+// Values of the Capabilities field of NEGOTIATE.
+const (
+	capabilityLargeMTU = 0x00000004
+)
+
+// Negotiate context types.
+const (
+	negotiateContextPreauthIntegrityCapabilities = 0x0001
+	negotiateContextSigningCapabilities          = 0x0008
+
+	hashAlgorithmSHA512   = 0x0001
+	signingAlgorithmCMAC  = 0x0001
+	preauthIntegritySalts = 32
+)
+
+// Values of the SessionFlags field of SESSION_SETUP responses.
+const (
+	sessionSetupFlagBinding = 0x01
+)
+
+// Values of the ShareType and ShareFlags fields of TREE_CONNECT
+// responses.
+const (
+	shareTypeDisk      = 0x01
+	shareFlagNoCaching = 0x00000030
+	fileAllAccess      = 0x001f01ff
+)
+
+// Access mask bits, as used by the DesiredAccess field of CREATE.
+const (
+	accessFileReadData      = 0x00000001
+	accessFileWriteData     = 0x00000002
+	accessFileAppendData    = 0x00000004
+	accessFileExecute       = 0x00000020
+	accessMaximumAllowed    = 0x02000000
+	accessGenericAll        = 0x10000000
+	accessGenericExecute    = 0x20000000
+	accessGenericWrite      = 0x40000000
+	accessGenericRead       = 0x80000000
+	accessReadDataMask      = accessFileReadData | accessFileExecute | accessGenericRead | accessGenericExecute | accessGenericAll | accessMaximumAllowed
+	accessWriteDataExplicit = accessFileWriteData | accessFileAppendData | accessGenericWrite | accessGenericAll
+)
+
+// Values of the CreateDisposition field of CREATE.
+const (
+	createDispositionSupersede   = 0x00000000
+	createDispositionOpen        = 0x00000001
+	createDispositionCreate      = 0x00000002
+	createDispositionOpenIf      = 0x00000003
+	createDispositionOverwrite   = 0x00000004
+	createDispositionOverwriteIf = 0x00000005
+)
+
+// Values of the CreateOptions field of CREATE.
+const (
+	createOptionDirectoryFile    = 0x00000001
+	createOptionNonDirectoryFile = 0x00000040
+	createOptionDeleteOnClose    = 0x00001000
+	createOptionOpenReparsePoint = 0x00200000
+)
+
+// Values of the CreateAction field of CREATE responses.
+const (
+	createActionSuperseded  = 0x00000000
+	createActionOpened      = 0x00000001
+	createActionCreated     = 0x00000002
+	createActionOverwritten = 0x00000003
+)
+
+// File attributes, as described in [MS-FSCC] section 2.6.
+const (
+	fileAttributeReadonly     = 0x00000001
+	fileAttributeDirectory    = 0x00000010
+	fileAttributeNormal       = 0x00000080
+	fileAttributeReparsePoint = 0x00000400
+)
+
+const (
+	reparseTagSymlink   = 0xa000000c
+	symlinkFlagRelative = 0x00000001
+	symlinkErrorTag     = 0x4c4d5953
+)
+
+// Values of the InfoType field of QUERY_INFO and SET_INFO.
+const (
+	infoTypeFile       = 0x01
+	infoTypeFilesystem = 0x02
+	infoTypeSecurity   = 0x03
+)
+
+// File information classes, as described in [MS-FSCC] section 2.4.
+const (
+	fileDirectoryInformation       = 1
+	fileFullDirectoryInformation   = 2
+	fileBothDirectoryInformation   = 3
+	fileBasicInformation           = 4
+	fileStandardInformation        = 5
+	fileInternalInformation        = 6
+	fileEaInformation              = 7
+	fileAccessInformation          = 8
+	fileRenameInformation          = 10
+	fileNamesInformation           = 12
+	fileDispositionInformation     = 13
+	filePositionInformation        = 14
+	fileModeInformation            = 16
+	fileAlignmentInformation       = 17
+	fileAllInformation             = 18
+	fileAllocationInformation      = 19
+	fileEndOfFileInformation       = 20
+	fileAlternateNameInformation   = 21
+	fileStreamInformation          = 22
+	fileNetworkOpenInformation     = 34
+	fileAttributeTagInformation    = 35
+	fileIDBothDirectoryInformation = 37
+	fileIDFullDirectoryInformation = 38
+	fileValidDataLengthInformation = 39
+	fileIDInformation              = 59
+	fileDispositionInformationEx   = 64
+)
+
+// File system information classes, as described in [MS-FSCC] section
+// 2.5.
+const (
+	fileFsVolumeInformation     = 1
+	fileFsSizeInformation       = 3
+	fileFsDeviceInformation     = 4
+	fileFsAttributeInformation  = 5
+	fileFsFullSizeInformation   = 7
+	fileFsSectorSizeInformation = 11
+)
+
+// Values of the Flags field of QUERY_DIRECTORY.
+const (
+	queryDirectoryRestartScans      = 0x01
+	queryDirectoryReturnSingleEntry = 0x02
+	queryDirectoryIndexSpecified    = 0x04
+	queryDirectoryReopen            = 0x10
+)
+
+// Values of the Flags field of CLOSE.
+const (
+	closeFlagPostqueryAttrib = 0x0001
+)
+
+// Values of the Flags field of FILE_DISPOSITION_INFORMATION_EX.
+const (
+	fileDispositionExFlagDelete = 0x00000001
+)
+
+// Control codes of IOCTL requests that are handled explicitly.
+const (
+	ioctlFlagIsFSCTL       = 0x00000001
+	fsctlGetReparsePoint   = 0x000900a8
+	fsctlDFSGetReferrals   = 0x00060194
+	fsctlDFSGetReferralsEx = 0x000601b0
+)
+
+// Properties of the file system, as reported through
+// FileFsAttributeInformation and FileFsDeviceInformation.
+const (
+	fileFsAttributeCaseSensitive = 0x00000001
+	fileFsAttributeCasePreserved = 0x00000002
+	fileFsAttributeUnicodeOnDisk = 0x00000004
+	fileDeviceDisk               = 0x00000007
+	fileRemoteDevice             = 0x00000010
+	fileFsBytesPerSector         = 4096
+	maximumComponentNameLength   = 255
+)
+
+// Fields of security descriptors, as described in [MS-DTYP] section
+// 2.4.6.
+const (
+	securityDescriptorSelfRelative    = 0x8000
+	securityDescriptorDACLPresent     = 0x0004
+	aceTypeAccessAllowed              = 0x00
+	aceFlagsObjectAndContainerInherit = 0x03
+)
+
+// fileIDWildcard is the value of FileId that refers to the file that
+// was opened or used by the previous request in a compound request.
+var fileIDWildcard = [16]byte{
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+}
+
+// header of an SMB2 packet. Only the synchronous form of the header is
+// supported, as the server never sends interim responses.
+type header struct {
+	creditCharge uint16
+	status       ntStatus
+	command      uint16
+	credits      uint16
+	flags        uint32
+	nextCommand  uint32
+	messageID    uint64
+	treeID       uint32
+	sessionID    uint64
+	signature    [16]byte
+}
+
+// parseHeader parses an SMB2 packet header at the start of a message.
+func parseHeader(b []byte) (header, bool) {
+	if len(b) < headerSize || b[0] != 0xfe || b[1] != 'S' || b[2] != 'M' || b[3] != 'B' || binary.LittleEndian.Uint16(b[4:]) != headerSize {
+		return header{}, false
+	}
+	h := header{
+		creditCharge: binary.LittleEndian.Uint16(b[6:]),
+		status:       ntStatus(binary.LittleEndian.Uint32(b[8:])),
+		command:      binary.LittleEndian.Uint16(b[12:]),
+		credits:      binary.LittleEndian.Uint16(b[14:]),
+		flags:        binary.LittleEndian.Uint32(b[16:]),
+		nextCommand:  binary.LittleEndian.Uint32(b[20:]),
+		messageID:    binary.LittleEndian.Uint64(b[24:]),
+		treeID:       binary.LittleEndian.Uint32(b[36:]),
+		sessionID:    binary.LittleEndian.Uint64(b[40:]),
+	}
+	copy(h.signature[:], b[48:])
+	return h, true
+}
+
+// appendHeader appends an SMB2 packet header to a buffer.
+func appendHeader(b []byte, h *header) []byte {
+	b = append(b, 0xfe, 'S', 'M', 'B')
+	b = binary.LittleEndian.AppendUint16(b, headerSize)
+	b = binary.LittleEndian.AppendUint16(b, h.creditCharge)
+	b = binary.LittleEndian.AppendUint32(b, uint32(h.status))
+	b = binary.LittleEndian.AppendUint16(b, h.command)
+	b = binary.LittleEndian.AppendUint16(b, h.credits)
+	b = binary.LittleEndian.AppendUint32(b, h.flags)
+	b = binary.LittleEndian.AppendUint32(b, h.nextCommand)
+	b = binary.LittleEndian.AppendUint64(b, h.messageID)
+	b = binary.LittleEndian.AppendUint32(b, 0)
+	b = binary.LittleEndian.AppendUint32(b, h.treeID)
+	b = binary.LittleEndian.AppendUint64(b, h.sessionID)
+	return append(b, h.signature[:]...)
+}
+
+// getBuffer returns a variable length buffer that is part of a
+// message, given an offset relative to the start of the SMB2 header.
+func getBuffer(message []byte, offset, length uint32) ([]byte, bool) {
+	if length == 0 {
+		return nil, true
+	}
+	if uint64(offset)+uint64(length) > uint64(len(message)) {
+		return nil, false
+	}
+	return message[offset : offset+length], true
+}
+
+// appendPadding appends zero bytes to a buffer until its length is a
+// multiple of the provided alignment.
+func appendPadding(b []byte, alignment int) []byte {
+	for len(b)%alignment != 0 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+// encodeUTF16 converts a string to UTF-16LE, which is the character
+// encoding used for all strings in SMB2.
+func encodeUTF16(s string) []byte {
+	u := utf16.Encode([]rune(s))
+	b := make([]byte, 0, 2*len(u))
+	for _, c := range u {
+		b = binary.LittleEndian.AppendUint16(b, c)
+	}
+	return b
+}
+
+// decodeUTF16 converts a UTF-16LE string to a Go string.
+func decodeUTF16(b []byte) (string, bool) {
+	if len(b)%2 != 0 {
+		return "", false
+	}
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i < len(b); i += 2 {
+		u = append(u, binary.LittleEndian.Uint16(b[i:]))
+	}
+	return string(utf16.Decode(u)), true
+}
+
+// Difference between the Windows epoch (1601-01-01) and the UNIX epoch
+// (1970-01-01), in units of 100 nanoseconds.
+const filetimeUNIXEpoch = 116444736000000000
+
+// timeToFiletime converts a timestamp to a FILETIME value, which is
+// the number of 100 nanosecond intervals since 1601-01-01.
+func timeToFiletime(t time.Time) uint64 {
+	return uint64(t.UnixNano()/100 + filetimeUNIXEpoch)
+}
+
+// filetimeToTime converts a FILETIME value to a timestamp.
+func filetimeToTime(ft uint64) time.Time {
+	return time.Unix(0, (int64(ft)-filetimeUNIXEpoch)*100)
+}
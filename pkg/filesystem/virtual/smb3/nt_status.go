@@ -0,0 +1,87 @@
+package smb3
+
+import (
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+)
+
+// ntStatus is a status code that is returned as part of every SMB2
+// response, as described in [MS-ERREF] section 2.3.
+type ntStatus uint32
+
+const (
+	statusSuccess                ntStatus = 0x00000000
+	statusBufferOverflow         ntStatus = 0x80000005
+	statusNoMoreFiles            ntStatus = 0x80000006
+	statusInvalidInfoClass       ntStatus = 0xc0000003
+	statusInfoLengthMismatch     ntStatus = 0xc0000004
+	statusInvalidHandle          ntStatus = 0xc0000008
+	statusInvalidParameter       ntStatus = 0xc000000d
+	statusNoSuchFile             ntStatus = 0xc000000f
+	statusInvalidDeviceRequest   ntStatus = 0xc0000010
+	statusEndOfFile              ntStatus = 0xc0000011
+	statusMoreProcessingRequired ntStatus = 0xc0000016
+	statusAccessDenied           ntStatus = 0xc0000022
+	statusBufferTooSmall         ntStatus = 0xc0000023
+	statusObjectTypeMismatch     ntStatus = 0xc0000024
+	statusObjectNameInvalid      ntStatus = 0xc0000033
+	statusObjectNameNotFound     ntStatus = 0xc0000034
+	statusObjectNameCollision    ntStatus = 0xc0000035
+	statusObjectPathNotFound     ntStatus = 0xc000003a
+	statusLogonFailure           ntStatus = 0xc000006d
+	statusMediaWriteProtected    ntStatus = 0xc00000a2
+	statusFileIsADirectory       ntStatus = 0xc00000ba
+	statusNotSupported           ntStatus = 0xc00000bb
+	statusNetworkNameDeleted     ntStatus = 0xc00000c9
+	statusBadNetworkName         ntStatus = 0xc00000cc
+	statusNotSameDevice          ntStatus = 0xc00000d4
+	statusUnexpectedIOError      ntStatus = 0xc00000e9
+	statusDirectoryNotEmpty      ntStatus = 0xc0000101
+	statusNotADirectory          ntStatus = 0xc0000103
+	statusFileDeleted            ntStatus = 0xc0000123
+	statusCannotDelete           ntStatus = 0xc0000121
+	statusFileClosed             ntStatus = 0xc0000128
+	statusUserSessionDeleted     ntStatus = 0xc0000203
+	statusNotAReparsePoint       ntStatus = 0xc0000275
+	statusStoppedOnSymlink       ntStatus = 0x8000002d
+	statusFSDriverRequired       ntStatus = 0xc000019c
+	statusRequestNotAccepted     ntStatus = 0xc00000d0
+)
+
+// toNTStatus converts a status code returned by the virtual file
+// system to its SMB2 equivalent.
+func toNTStatus(s virtual.Status) ntStatus {
+	switch s {
+	case virtual.StatusOK:
+		return statusSuccess
+	case virtual.StatusErrAccess, virtual.StatusErrPerm:
+		return statusAccessDenied
+	case virtual.StatusErrBadHandle:
+		return statusInvalidHandle
+	case virtual.StatusErrExist:
+		return statusObjectNameCollision
+	case virtual.StatusErrInval:
+		return statusInvalidParameter
+	case virtual.StatusErrIO:
+		return statusUnexpectedIOError
+	case virtual.StatusErrIsDir:
+		return statusFileIsADirectory
+	case virtual.StatusErrNoEnt, virtual.StatusErrNoXAttr:
+		return statusObjectNameNotFound
+	case virtual.StatusErrNotDir:
+		return statusNotADirectory
+	case virtual.StatusErrNotEmpty:
+		return statusDirectoryNotEmpty
+	case virtual.StatusErrNXIO:
+		return statusInvalidDeviceRequest
+	case virtual.StatusErrROFS:
+		return statusMediaWriteProtected
+	case virtual.StatusErrStale:
+		return statusFileDeleted
+	case virtual.StatusErrSymlink, virtual.StatusErrWrongType:
+		return statusObjectTypeMismatch
+	case virtual.StatusErrXDev:
+		return statusNotSameDevice
+	default:
+		panic("Unknown status")
+	}
+}
@@ -0,0 +1,244 @@
+package smb3
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rc4"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/md4"
+)
+
+// Implementation of the server side of the NT LAN Manager (NTLM)
+// authentication protocol, as described in [MS-NLMP]. Only NTLMv2 with
+// extended session security is supported.
+
+var ntlmSignature = []byte("NTLMSSP\x00")
+
+const (
+	ntlmMessageTypeNegotiate    = 1
+	ntlmMessageTypeChallenge    = 2
+	ntlmMessageTypeAuthenticate = 3
+)
+
+// Flags that may be set in NegotiateFlags.
+const (
+	ntlmNegotiateUnicode                 = 0x00000001
+	ntlmRequestTarget                    = 0x00000004
+	ntlmNegotiateSign                    = 0x00000010
+	ntlmNegotiateSeal                    = 0x00000020
+	ntlmNegotiateNTLM                    = 0x00000200
+	ntlmNegotiateAlwaysSign              = 0x00008000
+	ntlmTargetTypeServer                 = 0x00020000
+	ntlmNegotiateExtendedSessionSecurity = 0x00080000
+	ntlmNegotiateTargetInfo              = 0x00800000
+	ntlmNegotiateVersion                 = 0x02000000
+	ntlmNegotiate128                     = 0x20000000
+	ntlmNegotiateKeyExchange             = 0x40000000
+	ntlmNegotiate56                      = 0x80000000
+	ntlmNegotiateFlagsAlwaysSet          = ntlmNegotiateUnicode | ntlmRequestTarget | ntlmNegotiateNTLM | ntlmNegotiateAlwaysSign | ntlmTargetTypeServer | ntlmNegotiateExtendedSessionSecurity | ntlmNegotiateTargetInfo | ntlmNegotiateVersion | ntlmNegotiate128 | ntlmNegotiate56
+	ntlmNegotiateFlagsSetIfRequested     = ntlmNegotiateSign | ntlmNegotiateSeal | ntlmNegotiateKeyExchange
+)
+
+// Attribute IDs of AV_PAIR structures stored in the TargetInfo field.
+const (
+	ntlmAvIDEOL             = 0
+	ntlmAvIDNbComputerName  = 1
+	ntlmAvIDNbDomainName    = 2
+	ntlmAvIDDNSComputerName = 3
+	ntlmAvIDDNSDomainName   = 4
+	ntlmAvIDTimestamp       = 7
+)
+
+// ntlmVersion is the value of the VERSION structure that the server
+// reports, corresponding to Windows 10 with NTLM revision 15.
+var ntlmVersion = []byte{10, 0, 0x63, 0x45, 0, 0, 0, 0x0f}
+
+// computeNTOWFv1 computes the NT one-way function of a password,
+// which is the MD4 hash of the password in UTF-16LE.
+func computeNTOWFv1(password string) []byte {
+	h := md4.New()
+	h.Write(encodeUTF16(password))
+	return h.Sum(nil)
+}
+
+// computeNTOWFv2 computes the NTLMv2 response key of a user.
+func computeNTOWFv2(ntowfv1 []byte, userName, domainName string) []byte {
+	h := hmac.New(md5.New, ntowfv1)
+	h.Write(encodeUTF16(strings.ToUpper(userName) + domainName))
+	return h.Sum(nil)
+}
+
+func computeHMACMD5(key []byte, data ...[]byte) []byte {
+	h := hmac.New(md5.New, key)
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// appendNTLMField appends the length, maximum length and offset of a
+// variable length field of an NTLM message to a buffer.
+func appendNTLMField(b []byte, length, offset int) []byte {
+	b = binary.LittleEndian.AppendUint16(b, uint16(length))
+	b = binary.LittleEndian.AppendUint16(b, uint16(length))
+	return binary.LittleEndian.AppendUint32(b, uint32(offset))
+}
+
+// getNTLMField extracts the contents of a variable length field of an
+// NTLM message, given the offset of its length/offset pair.
+func getNTLMField(message []byte, fieldOffset int) ([]byte, bool) {
+	if len(message) < fieldOffset+8 {
+		return nil, false
+	}
+	length := binary.LittleEndian.Uint16(message[fieldOffset:])
+	offset := binary.LittleEndian.Uint32(message[fieldOffset+4:])
+	return getBuffer(message, offset, uint32(length))
+}
+
+func appendAvPair(b []byte, id uint16, value []byte) []byte {
+	b = binary.LittleEndian.AppendUint16(b, id)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(value)))
+	return append(b, value...)
+}
+
+// ntlmCredentials contains the user name and hashed password that
+// clients need to provide to authenticate.
+type ntlmCredentials struct {
+	userName string
+	ntowfv1  []byte
+}
+
+// ntlmServerContext holds the state of an NTLM authentication exchange
+// between the server and a single client.
+type ntlmServerContext struct {
+	credentials     *ntlmCredentials
+	serverName      string
+	negotiateFlags  uint32
+	serverChallenge [8]byte
+}
+
+// processNegotiate processes an NTLM NEGOTIATE_MESSAGE, and returns a
+// CHALLENGE_MESSAGE that needs to be returned to the client.
+func (sc *ntlmServerContext) processNegotiate(negotiateMessage []byte, now time.Time) ([]byte, bool) {
+	if len(negotiateMessage) < 16 || !bytes.Equal(negotiateMessage[:8], ntlmSignature) || binary.LittleEndian.Uint32(negotiateMessage[8:]) != ntlmMessageTypeNegotiate {
+		return nil, false
+	}
+	clientFlags := binary.LittleEndian.Uint32(negotiateMessage[12:])
+	if clientFlags&ntlmNegotiateUnicode == 0 {
+		return nil, false
+	}
+	sc.negotiateFlags = ntlmNegotiateFlagsAlwaysSet | clientFlags&ntlmNegotiateFlagsSetIfRequested
+
+	targetName := encodeUTF16(sc.serverName)
+	var targetInfo []byte
+	targetInfo = appendAvPair(targetInfo, ntlmAvIDNbDomainName, targetName)
+	targetInfo = appendAvPair(targetInfo, ntlmAvIDNbComputerName, targetName)
+	targetInfo = appendAvPair(targetInfo, ntlmAvIDDNSDomainName, targetName)
+	targetInfo = appendAvPair(targetInfo, ntlmAvIDDNSComputerName, targetName)
+	targetInfo = appendAvPair(targetInfo, ntlmAvIDTimestamp, binary.LittleEndian.AppendUint64(nil, timeToFiletime(now)))
+	targetInfo = appendAvPair(targetInfo, ntlmAvIDEOL, nil)
+
+	const challengeHeaderSize = 56
+	challenge := append([]byte(nil), ntlmSignature...)
+	challenge = binary.LittleEndian.AppendUint32(challenge, ntlmMessageTypeChallenge)
+	challenge = appendNTLMField(challenge, len(targetName), challengeHeaderSize)
+	challenge = binary.LittleEndian.AppendUint32(challenge, sc.negotiateFlags)
+	challenge = append(challenge, sc.serverChallenge[:]...)
+	challenge = append(challenge, make([]byte, 8)...)
+	challenge = appendNTLMField(challenge, len(targetInfo), challengeHeaderSize+len(targetName))
+	challenge = append(challenge, ntlmVersion...)
+	challenge = append(challenge, targetName...)
+	return append(challenge, targetInfo...), true
+}
+
+// ntlmSecurityContext contains the keys that are established by
+// successfully completing NTLM authentication.
+type ntlmSecurityContext struct {
+	exportedSessionKey []byte
+	negotiateFlags     uint32
+}
+
+// processAuthenticate processes an NTLM AUTHENTICATE_MESSAGE. It
+// validates the NTLMv2 response provided by the client against the
+// credentials of the user. Upon success, the exported session key is
+// returned.
+func (sc *ntlmServerContext) processAuthenticate(authenticateMessage []byte) (*ntlmSecurityContext, bool) {
+	if len(authenticateMessage) < 64 || !bytes.Equal(authenticateMessage[:8], ntlmSignature) || binary.LittleEndian.Uint32(authenticateMessage[8:]) != ntlmMessageTypeAuthenticate {
+		return nil, false
+	}
+	ntChallengeResponse, ok1 := getNTLMField(authenticateMessage, 20)
+	domainNameBytes, ok2 := getNTLMField(authenticateMessage, 28)
+	userNameBytes, ok3 := getNTLMField(authenticateMessage, 36)
+	encryptedRandomSessionKey, ok4 := getNTLMField(authenticateMessage, 52)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return nil, false
+	}
+	domainName, ok1 := decodeUTF16(domainNameBytes)
+	userName, ok2 := decodeUTF16(userNameBytes)
+	if !ok1 || !ok2 {
+		return nil, false
+	}
+
+	// Anonymous authentication and NTLMv1 are not supported. An
+	// NTLMv2 response consists of a 16 byte proof, followed by a
+	// blob of at least 28 bytes.
+	if !strings.EqualFold(userName, sc.credentials.userName) || len(ntChallengeResponse) < 16+28 {
+		return nil, false
+	}
+	responseKeyNT := computeNTOWFv2(sc.credentials.ntowfv1, userName, domainName)
+	ntProofStr := ntChallengeResponse[:16]
+	if !hmac.Equal(ntProofStr, computeHMACMD5(responseKeyNT, sc.serverChallenge[:], ntChallengeResponse[16:])) {
+		return nil, false
+	}
+
+	// Derive the exported session key. With NTLMv2, the key
+	// exchange key is equal to the session base key.
+	keyExchangeKey := computeHMACMD5(responseKeyNT, ntProofStr)
+	exportedSessionKey := keyExchangeKey
+	if sc.negotiateFlags&ntlmNegotiateKeyExchange != 0 {
+		if len(encryptedRandomSessionKey) != 16 {
+			return nil, false
+		}
+		cipher, err := rc4.NewCipher(keyExchangeKey)
+		if err != nil {
+			return nil, false
+		}
+		exportedSessionKey = make([]byte, 16)
+		cipher.XORKeyStream(exportedSessionKey, encryptedRandomSessionKey)
+	}
+	return &ntlmSecurityContext{
+		exportedSessionKey: exportedSessionKey,
+		negotiateFlags:     sc.negotiateFlags,
+	}, true
+}
+
+// computeServerMIC computes a message integrity code over a message
+// sent by the server. This is used by SPNEGO to protect the list of
+// mechanisms that was negotiated.
+func (sc *ntlmSecurityContext) computeServerMIC(message []byte, sequenceNumber uint32) []byte {
+	md := md5.New()
+	md.Write(sc.exportedSessionKey)
+	md.Write([]byte("session key to server-to-client signing key magic constant\x00"))
+	signingKey := md.Sum(nil)
+
+	seqNum := binary.LittleEndian.AppendUint32(nil, sequenceNumber)
+	checksum := computeHMACMD5(signingKey, seqNum, message)[:8]
+	if sc.negotiateFlags&ntlmNegotiateKeyExchange != 0 {
+		md := md5.New()
+		md.Write(sc.exportedSessionKey)
+		md.Write([]byte("session key to server-to-client sealing key magic constant\x00"))
+		cipher, err := rc4.NewCipher(md.Sum(nil))
+		if err != nil {
+			panic(err)
+		}
+		cipher.XORKeyStream(checksum, checksum)
+	}
+
+	mic := binary.LittleEndian.AppendUint32(nil, 1)
+	mic = append(mic, checksum...)
+	return append(mic, seqNum...)
+}
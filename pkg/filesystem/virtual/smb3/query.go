@@ -0,0 +1,530 @@
+package smb3
+
+import (
+	"context"
+	"encoding/binary"
+	"strings"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+)
+
+// The size of the file system, as reported to clients. The virtual
+// file system does not have a fixed capacity, so report a size that is
+// large enough to not cause clients to refuse writing files.
+const filesystemSizeBytes = 1 << 40
+
+// directoryEntryAppender appends entries to the output of a
+// QUERY_DIRECTORY request, until the output buffer is full.
+type directoryEntryAppender struct {
+	fileInformationClass uint8
+	outputBufferLength   int
+	output               []byte
+	lastEntryOffset      int
+	isFull               bool
+}
+
+func (a *directoryEntryAppender) appendEntry(name string, attributes *virtual.Attributes) bool {
+	fi := newFileInformation(attributes)
+	entry := fi.appendDirectoryEntry(nil, a.fileInformationClass, name)
+	entryOffset := (len(a.output) + 7) &^ 7
+	if entryOffset+len(entry) > a.outputBufferLength {
+		a.isFull = true
+		return false
+	}
+	a.output = appendPadding(a.output, 8)
+	if entryOffset > 0 {
+		binary.LittleEndian.PutUint32(a.output[a.lastEntryOffset:], uint32(entryOffset-a.lastEntryOffset))
+	}
+	a.lastEntryOffset = entryOffset
+	a.output = append(a.output, entry...)
+	return true
+}
+
+// queryDirectoryReporter is an implementation of
+// DirectoryEntryReporter that is used by QUERY_DIRECTORY to append
+// entries matching a search pattern to the output.
+type queryDirectoryReporter struct {
+	open        *open
+	appender    *directoryEntryAppender
+	singleEntry bool
+	stopped     bool
+}
+
+func (r *queryDirectoryReporter) ReportEntry(nextCookie uint64, name path.Component, child virtual.DirectoryChild, attributes *virtual.Attributes) bool {
+	if matchPattern(r.open.enumerationPattern, []rune(name.String())) {
+		if !r.appender.appendEntry(name.String(), attributes) {
+			r.stopped = true
+			return false
+		}
+		if r.singleEntry {
+			r.open.enumerationCookie = nextCookie
+			r.stopped = true
+			return false
+		}
+	}
+	r.open.enumerationCookie = nextCookie
+	return true
+}
+
+// processQueryDirectory processes an SMB2 QUERY_DIRECTORY request, as
+// described in [MS-SMB2] section 3.3.5.18.
+func (rc *requestContext) processQueryDirectory(ctx context.Context) (ntStatus, []byte) {
+	body := rc.body()
+	if len(body) < 32 {
+		return statusInvalidParameter, nil
+	}
+	fileInformationClass := body[2]
+	flags := body[3]
+	patternBytes, ok := getBuffer(rc.message, uint32(binary.LittleEndian.Uint16(body[24:])), uint32(binary.LittleEndian.Uint16(body[26:])))
+	if !ok {
+		return statusInvalidParameter, nil
+	}
+	pattern, ok := decodeUTF16(patternBytes)
+	if !ok {
+		return statusInvalidParameter, nil
+	}
+	outputBufferLength := binary.LittleEndian.Uint32(body[28:])
+	if outputBufferLength > maximumIOSize {
+		outputBufferLength = maximumIOSize
+	}
+	o, status := rc.getOpen(body[8:24])
+	if status != statusSuccess {
+		return status, nil
+	}
+	if o.directory == nil {
+		return statusInvalidParameter, nil
+	}
+	if !isDirectoryInformationClassSupported(fileInformationClass) {
+		return statusInvalidInfoClass, nil
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	// The search pattern is only respected by the first request
+	// of an enumeration.
+	if o.enumerationPattern == nil || flags&(queryDirectoryRestartScans|queryDirectoryReopen) != 0 {
+		if pattern == "" {
+			pattern = "*"
+		}
+		o.enumerationPattern = []rune(pattern)
+		o.enumerationCookie = 0
+		o.enumerationDotsReturned = 0
+		o.enumerationDone = false
+		o.enumerationAnyReturned = false
+	}
+
+	appender := directoryEntryAppender{
+		fileInformationClass: fileInformationClass,
+		outputBufferLength:   int(outputBufferLength),
+	}
+	singleEntry := flags&queryDirectoryReturnSingleEntry != 0
+	if !o.enumerationDone {
+		if pattern := string(o.enumerationPattern); !hasWildcards(pattern) {
+			// The pattern refers to a single file. Look it
+			// up directly, instead of reading the full
+			// directory.
+			var attributes virtual.Attributes
+			if name, ok := path.NewComponent(pattern); ok {
+				if _, s := o.directory.VirtualLookup(ctx, name, fileInformationAttributesMask, &attributes); s == virtual.StatusOK {
+					appender.appendEntry(pattern, &attributes)
+				}
+			}
+			o.enumerationDone = !appender.isFull
+		} else {
+			// Return "." and ".." before any other
+			// entries. As the parent directory may not be
+			// known, report the attributes of the directory
+			// itself for both of them.
+			for o.enumerationDotsReturned < 2 && !appender.isFull && !(singleEntry && len(appender.output) > 0) {
+				name := ".."[:o.enumerationDotsReturned+1]
+				if matchPattern(o.enumerationPattern, []rune(name)) {
+					var attributes virtual.Attributes
+					o.directory.VirtualGetAttributes(ctx, fileInformationAttributesMask, &attributes)
+					if !appender.appendEntry(name, &attributes) {
+						break
+					}
+				}
+				o.enumerationDotsReturned++
+			}
+
+			if !appender.isFull && !(singleEntry && len(appender.output) > 0) {
+				reporter := queryDirectoryReporter{
+					open:        o,
+					appender:    &appender,
+					singleEntry: singleEntry,
+				}
+				if s := o.directory.VirtualReadDir(ctx, o.enumerationCookie, fileInformationAttributesMask, &reporter); s != virtual.StatusOK {
+					return toNTStatus(s), nil
+				}
+				o.enumerationDone = !reporter.stopped
+			}
+		}
+	}
+
+	if len(appender.output) == 0 {
+		if appender.isFull {
+			return statusInfoLengthMismatch, nil
+		}
+		if o.enumerationAnyReturned {
+			return statusNoMoreFiles, nil
+		}
+		o.enumerationAnyReturned = true
+		return statusNoSuchFile, nil
+	}
+	o.enumerationAnyReturned = true
+
+	b := []byte{9, 0}
+	b = binary.LittleEndian.AppendUint16(b, headerSize+8)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(appender.output)))
+	return statusSuccess, append(b, appender.output...)
+}
+
+// processQueryInfo processes an SMB2 QUERY_INFO request, as described
+// in [MS-SMB2] section 3.3.5.20.
+func (rc *requestContext) processQueryInfo(ctx context.Context) (ntStatus, []byte) {
+	body := rc.body()
+	if len(body) < 40 {
+		return statusInvalidParameter, nil
+	}
+	infoType := body[2]
+	fileInformationClass := body[3]
+	outputBufferLength := binary.LittleEndian.Uint32(body[4:])
+	additionalInformation := binary.LittleEndian.Uint32(body[16:])
+	o, status := rc.getOpen(body[24:40])
+	if status != statusSuccess {
+		return status, nil
+	}
+
+	var output []byte
+	switch infoType {
+	case infoTypeFile:
+		output, status = o.queryFileInformation(ctx, fileInformationClass)
+	case infoTypeFilesystem:
+		output, status = rc.queryFilesystemInformation(fileInformationClass)
+	case infoTypeSecurity:
+		output = newSecurityDescriptor(additionalInformation, o.directory != nil)
+		if uint32(len(output)) > outputBufferLength {
+			// Inform the client of the buffer size that
+			// is required.
+			return statusBufferTooSmall, newErrorResponse(binary.LittleEndian.AppendUint32(nil, uint32(len(output))))
+		}
+	default:
+		return statusInvalidParameter, nil
+	}
+	if status != statusSuccess {
+		return status, nil
+	}
+	if uint32(len(output)) > outputBufferLength {
+		output = output[:outputBufferLength]
+		status = statusBufferOverflow
+	}
+
+	b := []byte{9, 0}
+	b = binary.LittleEndian.AppendUint16(b, headerSize+8)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(output)))
+	return status, append(b, output...)
+}
+
+// queryFileInformation returns information about an opened file, as
+// requested through QUERY_INFO with InfoType SMB2_0_INFO_FILE.
+func (o *open) queryFileInformation(ctx context.Context, fileInformationClass uint8) ([]byte, ntStatus) {
+	var attributes virtual.Attributes
+	o.node().VirtualGetAttributes(ctx, fileInformationAttributesMask, &attributes)
+	fi := newFileInformation(&attributes)
+	o.lock.Lock()
+	deletePending := o.deletePending
+	o.lock.Unlock()
+
+	switch fileInformationClass {
+	case fileBasicInformation:
+		return fi.appendBasicInformation(nil), statusSuccess
+	case fileStandardInformation:
+		return fi.appendStandardInformation(nil, deletePending), statusSuccess
+	case fileInternalInformation:
+		return binary.LittleEndian.AppendUint64(nil, fi.inodeNumber), statusSuccess
+	case fileEaInformation, fileModeInformation, fileAlignmentInformation:
+		return binary.LittleEndian.AppendUint32(nil, 0), statusSuccess
+	case fileAccessInformation:
+		return binary.LittleEndian.AppendUint32(nil, fileAllAccess), statusSuccess
+	case filePositionInformation:
+		return binary.LittleEndian.AppendUint64(nil, 0), statusSuccess
+	case fileAllInformation:
+		b := fi.appendBasicInformation(nil)
+		b = fi.appendStandardInformation(b, deletePending)
+		b = binary.LittleEndian.AppendUint64(b, fi.inodeNumber)
+		b = binary.LittleEndian.AppendUint32(b, 0)
+		b = binary.LittleEndian.AppendUint32(b, fileAllAccess)
+		b = binary.LittleEndian.AppendUint64(b, 0)
+		b = binary.LittleEndian.AppendUint32(b, 0)
+		b = binary.LittleEndian.AppendUint32(b, 0)
+		// The file name is not returned, as Windows
+		// clients don't depend on it.
+		return binary.LittleEndian.AppendUint32(b, 0), statusSuccess
+	case fileAlternateNameInformation:
+		// Short names are not supported.
+		return nil, statusObjectNameNotFound
+	case fileStreamInformation:
+		if o.directory != nil || o.isSymlink {
+			return nil, statusSuccess
+		}
+		name := encodeUTF16("::$DATA")
+		b := binary.LittleEndian.AppendUint32(nil, 0)
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(name)))
+		b = binary.LittleEndian.AppendUint64(b, fi.sizeBytes)
+		b = binary.LittleEndian.AppendUint64(b, fi.allocationSize())
+		return append(b, name...), statusSuccess
+	case fileNetworkOpenInformation:
+		b := fi.appendNetworkOpenInformation(nil)
+		return binary.LittleEndian.AppendUint32(b, 0), statusSuccess
+	case fileAttributeTagInformation:
+		b := binary.LittleEndian.AppendUint32(nil, fi.fileAttributes())
+		return binary.LittleEndian.AppendUint32(b, fi.reparseTag()), statusSuccess
+	case fileIDInformation:
+		b := binary.LittleEndian.AppendUint64(nil, 0)
+		b = binary.LittleEndian.AppendUint64(b, fi.inodeNumber)
+		return binary.LittleEndian.AppendUint64(b, 0), statusSuccess
+	default:
+		return nil, statusInvalidInfoClass
+	}
+}
+
+// queryFilesystemInformation returns information about the file
+// system, as requested through QUERY_INFO with InfoType
+// SMB2_0_INFO_FILESYSTEM.
+func (rc *requestContext) queryFilesystemInformation(fileInformationClass uint8) ([]byte, ntStatus) {
+	const allocationUnits = filesystemSizeBytes / fileFsBytesPerSector
+	switch fileInformationClass {
+	case fileFsVolumeInformation:
+		label := encodeUTF16(rc.connection.server.shareName)
+		b := binary.LittleEndian.AppendUint64(nil, timeToFiletime(filesystem.DeterministicFileModificationTimestamp))
+		b = binary.LittleEndian.AppendUint32(b, 0)
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(label)))
+		b = append(b, 0, 0)
+		return append(b, label...), statusSuccess
+	case fileFsSizeInformation:
+		b := binary.LittleEndian.AppendUint64(nil, allocationUnits)
+		b = binary.LittleEndian.AppendUint64(b, allocationUnits)
+		b = binary.LittleEndian.AppendUint32(b, 1)
+		return binary.LittleEndian.AppendUint32(b, fileFsBytesPerSector), statusSuccess
+	case fileFsDeviceInformation:
+		b := binary.LittleEndian.AppendUint32(nil, fileDeviceDisk)
+		return binary.LittleEndian.AppendUint32(b, fileRemoteDevice), statusSuccess
+	case fileFsAttributeInformation:
+		name := encodeUTF16("NTFS")
+		b := binary.LittleEndian.AppendUint32(nil, fileFsAttributeCaseSensitive|fileFsAttributeCasePreserved|fileFsAttributeUnicodeOnDisk)
+		b = binary.LittleEndian.AppendUint32(b, maximumComponentNameLength)
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(name)))
+		return append(b, name...), statusSuccess
+	case fileFsFullSizeInformation:
+		b := binary.LittleEndian.AppendUint64(nil, allocationUnits)
+		b = binary.LittleEndian.AppendUint64(b, allocationUnits)
+		b = binary.LittleEndian.AppendUint64(b, allocationUnits)
+		b = binary.LittleEndian.AppendUint32(b, 1)
+		return binary.LittleEndian.AppendUint32(b, fileFsBytesPerSector), statusSuccess
+	case fileFsSectorSizeInformation:
+		var b []byte
+		for i := 0; i < 4; i++ {
+			b = binary.LittleEndian.AppendUint32(b, fileFsBytesPerSector)
+		}
+		return append(b, make([]byte, 12)...), statusSuccess
+	default:
+		return nil, statusInvalidInfoClass
+	}
+}
+
+// processSetInfo processes an SMB2 SET_INFO request, as described in
+// [MS-SMB2] section 3.3.5.21.
+func (rc *requestContext) processSetInfo(ctx context.Context) (ntStatus, []byte) {
+	body := rc.body()
+	if len(body) < 32 {
+		return statusInvalidParameter, nil
+	}
+	infoType := body[2]
+	fileInformationClass := body[3]
+	buffer, ok := getBuffer(rc.message, uint32(binary.LittleEndian.Uint16(body[8:])), binary.LittleEndian.Uint32(body[4:]))
+	if !ok {
+		return statusInvalidParameter, nil
+	}
+	o, status := rc.getOpen(body[16:32])
+	if status != statusSuccess {
+		return status, nil
+	}
+
+	switch infoType {
+	case infoTypeFile:
+		status = rc.setFileInformation(ctx, o, fileInformationClass, buffer)
+	case infoTypeSecurity:
+		// Security descriptors are not stored. Permit
+		// clients to change them, as some applications
+		// fail otherwise.
+	default:
+		status = statusNotSupported
+	}
+	if status != statusSuccess {
+		return status, nil
+	}
+	return statusSuccess, []byte{2, 0}
+}
+
+// setFileInformation modifies the properties of an opened file, as
+// requested through SET_INFO with InfoType SMB2_0_INFO_FILE.
+func (rc *requestContext) setFileInformation(ctx context.Context, o *open, fileInformationClass uint8, buffer []byte) ntStatus {
+	switch fileInformationClass {
+	case fileBasicInformation:
+		if len(buffer) < 36 {
+			return statusInfoLengthMismatch
+		}
+		var in virtual.Attributes
+		changed := false
+		if lastWriteTime := binary.LittleEndian.Uint64(buffer[16:]); lastWriteTime != 0 && lastWriteTime != ^uint64(0) {
+			in.SetLastDataModificationTime(filetimeToTime(lastWriteTime))
+			changed = true
+		}
+		if fileAttributes := binary.LittleEndian.Uint32(buffer[32:]); fileAttributes != 0 && o.leaf != nil && !o.isSymlink {
+			// Map FILE_ATTRIBUTE_READONLY onto the write
+			// permission of the file.
+			var attributes virtual.Attributes
+			o.leaf.VirtualGetAttributes(ctx, virtual.AttributesMaskPermissions, &attributes)
+			permissions, _ := attributes.GetPermissions()
+			if fileAttributes&fileAttributeReadonly != 0 {
+				permissions &^= virtual.PermissionsWrite
+			} else {
+				permissions |= virtual.PermissionsWrite
+			}
+			in.SetPermissions(permissions)
+			changed = true
+		}
+		if changed {
+			var out virtual.Attributes
+			if s := o.node().VirtualSetAttributes(ctx, &in, 0, &out); s != virtual.StatusOK {
+				return toNTStatus(s)
+			}
+		}
+		return statusSuccess
+	case fileEndOfFileInformation:
+		if len(buffer) < 8 {
+			return statusInfoLengthMismatch
+		}
+		if o.directory != nil || o.isSymlink {
+			return statusInvalidParameter
+		}
+		var in, out virtual.Attributes
+		in.SetSizeBytes(binary.LittleEndian.Uint64(buffer))
+		return toNTStatus(o.leaf.VirtualSetAttributes(ctx, &in, 0, &out))
+	case fileAllocationInformation, filePositionInformation, fileModeInformation, fileValidDataLengthInformation:
+		// These properties have no equivalent in the virtual
+		// file system.
+		return statusSuccess
+	case fileDispositionInformation:
+		if len(buffer) < 1 {
+			return statusInfoLengthMismatch
+		}
+		return o.setDeletePending(ctx, buffer[0] != 0)
+	case fileDispositionInformationEx:
+		if len(buffer) < 4 {
+			return statusInfoLengthMismatch
+		}
+		return o.setDeletePending(ctx, binary.LittleEndian.Uint32(buffer)&fileDispositionExFlagDelete != 0)
+	case fileRenameInformation:
+		return rc.rename(ctx, o, buffer)
+	default:
+		return statusInvalidInfoClass
+	}
+}
+
+// directoryEmptinessChecker is an implementation of
+// DirectoryEntryReporter that determines whether a directory contains
+// any entries.
+type directoryEmptinessChecker struct {
+	isEmpty bool
+}
+
+func (r *directoryEmptinessChecker) ReportEntry(nextCookie uint64, name path.Component, child virtual.DirectoryChild, attributes *virtual.Attributes) bool {
+	r.isEmpty = false
+	return false
+}
+
+// setDeletePending marks a file for deletion, or cancels its pending
+// deletion. Directories may only be marked for deletion if they are
+// empty.
+func (o *open) setDeletePending(ctx context.Context, deletePending bool) ntStatus {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.parent == nil {
+		return statusCannotDelete
+	}
+	if deletePending && o.directory != nil {
+		checker := directoryEmptinessChecker{isEmpty: true}
+		if s := o.directory.VirtualReadDir(ctx, 0, 0, &checker); s != virtual.StatusOK {
+			return toNTStatus(s)
+		}
+		if !checker.isEmpty {
+			return statusDirectoryNotEmpty
+		}
+	}
+	o.deletePending = deletePending
+	return statusSuccess
+}
+
+// rename an opened file, as requested through SET_INFO with
+// FileRenameInformation.
+func (rc *requestContext) rename(ctx context.Context, o *open, buffer []byte) ntStatus {
+	if len(buffer) < 20 {
+		return statusInfoLengthMismatch
+	}
+	replaceIfExists := buffer[0] != 0
+	nameBytes, ok := getBuffer(buffer, 20, binary.LittleEndian.Uint32(buffer[16:]))
+	if !ok {
+		return statusInvalidParameter
+	}
+	name, ok := decodeUTF16(nameBytes)
+	if !ok {
+		return statusObjectNameInvalid
+	}
+	components, status := parsePath(strings.TrimPrefix(name, `\`))
+	if status != statusSuccess {
+		return status
+	}
+	if len(components) == 0 {
+		return statusObjectNameInvalid
+	}
+
+	// Resolve the directory in which the file should be placed.
+	// Symbolic links are not followed.
+	directory := rc.connection.server.rootDirectory
+	var attributes virtual.Attributes
+	for _, component := range components[:len(components)-1] {
+		child, s := directory.VirtualLookup(ctx, component, 0, &attributes)
+		if s != virtual.StatusOK {
+			return statusObjectPathNotFound
+		}
+		childDirectory, _ := child.GetPair()
+		if childDirectory == nil {
+			return statusObjectPathNotFound
+		}
+		directory = childDirectory
+	}
+	newName := components[len(components)-1]
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.parent == nil {
+		return statusAccessDenied
+	}
+	if !replaceIfExists {
+		if _, s := directory.VirtualLookup(ctx, newName, 0, &attributes); s == virtual.StatusOK {
+			return statusObjectNameCollision
+		}
+	}
+	if _, _, s := o.parent.VirtualRename(o.name, directory, newName); s != virtual.StatusOK {
+		return toNTStatus(s)
+	}
+	o.parent = directory
+	o.name = newName
+	return statusSuccess
+}
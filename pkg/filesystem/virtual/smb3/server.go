@@ -0,0 +1,524 @@
+package smb3
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"encoding/binary"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/random"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The maximum size of messages accepted by the server. This permits
+// WRITE requests of maximumIOSize, including their headers.
+const maximumMessageSize = maximumIOSize + 64*1024
+
+// Server of the SMB2 protocol, exposing a virtual file system as a
+// single share. Only the SMB 3.1.1 dialect is supported. Clients
+// authenticate using NTLMv2, using a single user name and password.
+//
+// This implementation is intended to be used by Windows systems to
+// access build directories, similar to how the FUSE and NFSv4 servers
+// are used on other operating systems. It therefore lacks features
+// such as opportunistic locks, leases, durable handles, multichannel
+// and encryption.
+type Server struct {
+	rootDirectory         virtual.Directory
+	serverName            string
+	shareName             string
+	credentials           ntlmCredentials
+	randomNumberGenerator random.ThreadSafeGenerator
+	clock                 clock.Clock
+	serverGUID            [16]byte
+}
+
+// NewServer creates a new SMB2 server that exposes a virtual file
+// system as a share having a given name. The server name is reported
+// to clients as part of NTLM authentication.
+func NewServer(rootDirectory virtual.Directory, serverName, shareName, userName, password string, randomNumberGenerator random.ThreadSafeGenerator, clock clock.Clock) *Server {
+	s := &Server{
+		rootDirectory: rootDirectory,
+		serverName:    strings.ToUpper(serverName),
+		shareName:     shareName,
+		credentials: ntlmCredentials{
+			userName: userName,
+			ntowfv1:  computeNTOWFv1(password),
+		},
+		randomNumberGenerator: randomNumberGenerator,
+		clock:                 clock,
+	}
+	randomNumberGenerator.Read(s.serverGUID[:])
+	return s
+}
+
+// HandleConnection processes all SMB2 requests received over a
+// single connection, using Direct TCP transport framing as described
+// in [MS-SMB2] section 2.1. This function returns when the connection
+// is closed by the client, or when a protocol violation is detected.
+func (s *Server) HandleConnection(r io.Reader, w io.Writer) error {
+	c := &connection{
+		server:   s,
+		w:        w,
+		sessions: map[uint64]*session{},
+		opens:    map[uint64]*open{},
+	}
+	defer c.close()
+
+	ctx := context.Background()
+	for {
+		var transportHeader [4]byte
+		if _, err := io.ReadFull(r, transportHeader[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return util.StatusWrap(err, "Failed to read message length")
+		}
+		length := binary.BigEndian.Uint32(transportHeader[:])
+		if length > maximumMessageSize {
+			return status.Errorf(codes.InvalidArgument, "Message has length %d, which exceeds the maximum of %d bytes", length, maximumMessageSize)
+		}
+		message := make([]byte, length)
+		if _, err := io.ReadFull(r, message); err != nil {
+			return util.StatusWrap(err, "Failed to read message")
+		}
+
+		if len(message) >= 5 && message[0] == 0xff && message[1] == 'S' && message[2] == 'M' && message[3] == 'B' && message[4] == 0x72 {
+			// Legacy SMB1 NEGOTIATE, sent by clients that
+			// also support SMB1. Ask the client to retry
+			// using SMB2.
+			if err := c.processSMB1Negotiate(message); err != nil {
+				return err
+			}
+			continue
+		}
+		h, ok := parseHeader(message)
+		if !ok {
+			return status.Error(codes.InvalidArgument, "Message does not start with a valid SMB2 header")
+		}
+		switch h.command {
+		case commandNegotiate:
+			if err := c.processNegotiate(&h, message); err != nil {
+				return err
+			}
+		case commandSessionSetup:
+			// Session setup requests need to be processed
+			// sequentially, as they affect the
+			// preauthentication integrity hash value.
+			if h.nextCommand != 0 {
+				return status.Error(codes.InvalidArgument, "SESSION_SETUP requests cannot be compounded")
+			}
+			c.processSessionSetup(&h, message)
+		default:
+			if !c.dialectNegotiated {
+				return status.Error(codes.InvalidArgument, "Client did not negotiate a dialect")
+			}
+			c.requestsInFlight.Add(1)
+			go func() {
+				c.processCompound(ctx, message)
+				c.requestsInFlight.Done()
+			}()
+		}
+	}
+}
+
+// connection holds the state of a single connection between the
+// client and server.
+type connection struct {
+	server           *Server
+	requestsInFlight sync.WaitGroup
+
+	writeLock sync.Mutex
+	w         io.Writer
+	writeErr  error
+
+	// Fields that are only accessed while processing NEGOTIATE and
+	// SESSION_SETUP requests, which are processed sequentially.
+	dialectNegotiated         bool
+	preauthIntegrityHashValue [64]byte
+
+	lock       sync.Mutex
+	sessions   map[uint64]*session
+	opens      map[uint64]*open
+	nextOpenID uint64
+	nextTreeID uint32
+}
+
+// session holds the state of an authenticated user on a connection.
+type session struct {
+	id uint64
+
+	// Fields that are used during authentication.
+	preauthIntegrityHashValue [64]byte
+	ntlmServerContext         *ntlmServerContext
+	ntlmChallengeSent         bool
+	spnegoMechTypes           []byte
+	usesSPNEGO                bool
+
+	// Fields that are set when authentication completes. They may
+	// only be accessed while holding connection.lock.
+	signer          *messageSigner
+	signingRequired bool
+	trees           map[uint32]struct{}
+}
+
+// writeMessage writes a message to the client, prefixed with the
+// Direct TCP transport header.
+func (c *connection) writeMessage(message []byte) {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	if c.writeErr == nil {
+		var transportHeader [4]byte
+		binary.BigEndian.PutUint32(transportHeader[:], uint32(len(message)))
+		if _, err := c.w.Write(append(transportHeader[:], message...)); err != nil {
+			c.writeErr = err
+		}
+	}
+}
+
+// close all files that are still opened after the connection is
+// terminated.
+func (c *connection) close() {
+	c.requestsInFlight.Wait()
+	c.lock.Lock()
+	opens := c.opens
+	c.opens = nil
+	c.lock.Unlock()
+	for _, o := range opens {
+		o.close()
+	}
+}
+
+// newResponseHeader creates the header of a response to a request.
+func newResponseHeader(h *header, s ntStatus) header {
+	credits := h.credits
+	if credits == 0 {
+		credits = 1
+	}
+	return header{
+		creditCharge: h.creditCharge,
+		status:       s,
+		command:      h.command,
+		credits:      credits,
+		flags:        flagsServerToRedir | h.flags&flagsRelatedOperations,
+		messageID:    h.messageID,
+		treeID:       h.treeID,
+		sessionID:    h.sessionID,
+	}
+}
+
+// newErrorResponse creates the body of an SMB2 ERROR response. For
+// SMB 3.1.1, any additional data is stored in an error context.
+func newErrorResponse(errorData []byte) []byte {
+	if errorData == nil {
+		return []byte{9, 0, 0, 0, 0, 0, 0, 0, 0}
+	}
+	b := []byte{9, 0, 1, 0}
+	b = binary.LittleEndian.AppendUint32(b, uint32(8+len(errorData)+(8-len(errorData)%8)%8))
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(errorData)))
+	b = binary.LittleEndian.AppendUint32(b, 0)
+	b = append(b, errorData...)
+	return appendPadding(b, 8)
+}
+
+// processSMB1Negotiate responds to an SMB1 NEGOTIATE request that
+// offers the SMB2 wildcard dialect, as described in [MS-SMB2]
+// section 3.3.5.3.1.
+func (c *connection) processSMB1Negotiate(message []byte) error {
+	if c.dialectNegotiated || !bytes.Contains(message, []byte("\x02SMB 2.???\x00")) {
+		return status.Error(codes.InvalidArgument, "Client does not support SMB2")
+	}
+	h := header{command: commandNegotiate}
+	c.writeMessage(c.newNegotiateResponse(&h, dialectWildcard, nil))
+	return nil
+}
+
+// newNegotiateResponse creates a NEGOTIATE response, selecting a given
+// dialect and including a list of negotiate contexts.
+func (c *connection) newNegotiateResponse(h *header, dialect uint16, negotiateContexts [][]byte) []byte {
+	responseHeader := newResponseHeader(h, statusSuccess)
+	securityBuffer := newSPNEGONegTokenInit()
+
+	b := appendHeader(nil, &responseHeader)
+	b = binary.LittleEndian.AppendUint16(b, 65)
+	b = binary.LittleEndian.AppendUint16(b, securityModeSigningEnabled)
+	b = binary.LittleEndian.AppendUint16(b, dialect)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(negotiateContexts)))
+	b = append(b, c.server.serverGUID[:]...)
+	b = binary.LittleEndian.AppendUint32(b, capabilityLargeMTU)
+	b = binary.LittleEndian.AppendUint32(b, maximumIOSize)
+	b = binary.LittleEndian.AppendUint32(b, maximumIOSize)
+	b = binary.LittleEndian.AppendUint32(b, maximumIOSize)
+	b = binary.LittleEndian.AppendUint64(b, timeToFiletime(c.server.clock.Now()))
+	b = binary.LittleEndian.AppendUint64(b, 0)
+	b = binary.LittleEndian.AppendUint16(b, headerSize+64)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(securityBuffer)))
+	negotiateContextOffsetField := len(b)
+	b = binary.LittleEndian.AppendUint32(b, 0)
+	b = append(b, securityBuffer...)
+	if len(negotiateContexts) > 0 {
+		b = appendPadding(b, 8)
+		binary.LittleEndian.PutUint32(b[negotiateContextOffsetField:], uint32(len(b)))
+		for i, negotiateContext := range negotiateContexts {
+			if i > 0 {
+				b = appendPadding(b, 8)
+			}
+			b = append(b, negotiateContext...)
+		}
+	}
+	return b
+}
+
+// appendNegotiateContext appends an SMB2 NEGOTIATE_CONTEXT to a
+// buffer.
+func newNegotiateContext(contextType uint16, data []byte) []byte {
+	b := binary.LittleEndian.AppendUint16(nil, contextType)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(data)))
+	b = binary.LittleEndian.AppendUint32(b, 0)
+	return append(b, data...)
+}
+
+// processNegotiate processes an SMB2 NEGOTIATE request, as described
+// in [MS-SMB2] section 3.3.5.4.
+func (c *connection) processNegotiate(h *header, message []byte) error {
+	if c.dialectNegotiated {
+		return status.Error(codes.InvalidArgument, "Client attempted to negotiate a dialect multiple times")
+	}
+	body := message[headerSize:]
+	if len(body) < 36 {
+		return status.Error(codes.InvalidArgument, "NEGOTIATE request is too small")
+	}
+	dialectCount := int(binary.LittleEndian.Uint16(body[2:]))
+	if len(body) < 36+2*dialectCount {
+		return status.Error(codes.InvalidArgument, "NEGOTIATE request is too small to contain all dialects")
+	}
+	supportsSMB311 := false
+	for i := 0; i < dialectCount; i++ {
+		if binary.LittleEndian.Uint16(body[36+2*i:]) == dialectSMB311 {
+			supportsSMB311 = true
+		}
+	}
+	if !supportsSMB311 {
+		responseHeader := newResponseHeader(h, statusNotSupported)
+		c.writeMessage(append(appendHeader(nil, &responseHeader), newErrorResponse(nil)...))
+		return status.Error(codes.Unimplemented, "Client does not support SMB 3.1.1")
+	}
+
+	// Process negotiate contexts. Preauthentication integrity
+	// using SHA-512 is mandatory for SMB 3.1.1.
+	supportsSHA512 := false
+	supportsCMAC := false
+	negotiateContextOffset := binary.LittleEndian.Uint32(body[28:])
+	negotiateContextCount := int(binary.LittleEndian.Uint16(body[32:]))
+	for i := 0; i < negotiateContextCount; i++ {
+		negotiateContextOffset = (negotiateContextOffset + 7) &^ 7
+		negotiateContextHeader, ok := getBuffer(message, negotiateContextOffset, 8)
+		if !ok {
+			return status.Error(codes.InvalidArgument, "Negotiate context header exceeds message boundaries")
+		}
+		dataLength := uint32(binary.LittleEndian.Uint16(negotiateContextHeader[2:]))
+		data, ok := getBuffer(message, negotiateContextOffset+8, dataLength)
+		if !ok {
+			return status.Error(codes.InvalidArgument, "Negotiate context data exceeds message boundaries")
+		}
+		switch binary.LittleEndian.Uint16(negotiateContextHeader) {
+		case negotiateContextPreauthIntegrityCapabilities:
+			if len(data) >= 4 {
+				algorithmCount := int(binary.LittleEndian.Uint16(data))
+				for j := 0; j < algorithmCount && len(data) >= 6+2*j; j++ {
+					if binary.LittleEndian.Uint16(data[4+2*j:]) == hashAlgorithmSHA512 {
+						supportsSHA512 = true
+					}
+				}
+			}
+		case negotiateContextSigningCapabilities:
+			if len(data) >= 2 {
+				algorithmCount := int(binary.LittleEndian.Uint16(data))
+				for j := 0; j < algorithmCount && len(data) >= 4+2*j; j++ {
+					if binary.LittleEndian.Uint16(data[2+2*j:]) == signingAlgorithmCMAC {
+						supportsCMAC = true
+					}
+				}
+			}
+		}
+		negotiateContextOffset += 8 + dataLength
+	}
+	if !supportsSHA512 {
+		return status.Error(codes.InvalidArgument, "Client does not support SHA-512 preauthentication integrity")
+	}
+
+	preauthIntegrityCapabilities := []byte{1, 0, preauthIntegritySalts, 0}
+	preauthIntegrityCapabilities = binary.LittleEndian.AppendUint16(preauthIntegrityCapabilities, hashAlgorithmSHA512)
+	salt := make([]byte, preauthIntegritySalts)
+	c.server.randomNumberGenerator.Read(salt)
+	negotiateContexts := [][]byte{
+		newNegotiateContext(negotiateContextPreauthIntegrityCapabilities, append(preauthIntegrityCapabilities, salt...)),
+	}
+	if supportsCMAC {
+		negotiateContexts = append(negotiateContexts, newNegotiateContext(negotiateContextSigningCapabilities, []byte{1, 0, signingAlgorithmCMAC, 0}))
+	}
+	response := c.newNegotiateResponse(h, dialectSMB311, negotiateContexts)
+
+	c.updatePreauthIntegrityHashValue(&c.preauthIntegrityHashValue, message)
+	c.updatePreauthIntegrityHashValue(&c.preauthIntegrityHashValue, response)
+	c.dialectNegotiated = true
+	c.writeMessage(response)
+	return nil
+}
+
+// updatePreauthIntegrityHashValue updates a preauthentication
+// integrity hash value, as described in [MS-SMB2] section 3.3.5.4.
+func (c *connection) updatePreauthIntegrityHashValue(hashValue *[64]byte, message []byte) {
+	h := sha512.New()
+	h.Write(hashValue[:])
+	h.Write(message)
+	h.Sum(hashValue[:0])
+}
+
+// processSessionSetup processes an SMB2 SESSION_SETUP request, as
+// described in [MS-SMB2] section 3.3.5.5.
+func (c *connection) processSessionSetup(h *header, message []byte) {
+	var s *session
+	status, body := func() (ntStatus, []byte) {
+		requestBody := message[headerSize:]
+		if !c.dialectNegotiated || len(requestBody) < 24 {
+			return statusInvalidParameter, nil
+		}
+		if requestBody[2]&sessionSetupFlagBinding != 0 {
+			// Multichannel is not supported.
+			return statusRequestNotAccepted, nil
+		}
+		securityMode := requestBody[3]
+		securityBuffer, ok := getBuffer(message, uint32(binary.LittleEndian.Uint16(requestBody[12:])), uint32(binary.LittleEndian.Uint16(requestBody[14:])))
+		if !ok {
+			return statusInvalidParameter, nil
+		}
+
+		c.lock.Lock()
+		if h.sessionID == 0 {
+			// Start a new session.
+			s = &session{
+				preauthIntegrityHashValue: c.preauthIntegrityHashValue,
+				ntlmServerContext: &ntlmServerContext{
+					credentials: &c.server.credentials,
+					serverName:  c.server.serverName,
+				},
+				trees: map[uint32]struct{}{},
+			}
+			for {
+				s.id = c.server.randomNumberGenerator.Uint64()
+				if _, ok := c.sessions[s.id]; s.id != 0 && !ok {
+					break
+				}
+			}
+			c.sessions[s.id] = s
+			h.sessionID = s.id
+		} else if s, ok = c.sessions[h.sessionID]; !ok || s.ntlmServerContext == nil {
+			// Reauthentication of established sessions
+			// is not supported.
+			c.lock.Unlock()
+			s = nil
+			return statusUserSessionDeleted, nil
+		}
+		c.lock.Unlock()
+		c.updatePreauthIntegrityHashValue(&s.preauthIntegrityHashValue, message)
+
+		// Extract the NTLM message from the SPNEGO token.
+		var ntlmMessage []byte
+		mechListMICIsSet := false
+		if bytes.HasPrefix(securityBuffer, ntlmSignature) {
+			ntlmMessage = securityBuffer
+		} else {
+			token, ok := parseSPNEGOToken(securityBuffer)
+			if !ok || !token.ntlmIsSupported {
+				return statusLogonFailure, nil
+			}
+			s.usesSPNEGO = true
+			if token.mechTypes != nil {
+				s.spnegoMechTypes = token.mechTypes
+			}
+			if !token.ntlmIsPreferred || token.mechToken == nil {
+				// The client's optimistic token is for
+				// another mechanism. Request that it
+				// switches to NTLM.
+				return statusMoreProcessingRequired, newSessionSetupResponse(newSPNEGONegTokenResp(spnegoAcceptIncomplete, true, nil, nil))
+			}
+			ntlmMessage = token.mechToken
+			mechListMICIsSet = token.mechListMICIsSet
+		}
+
+		if !s.ntlmChallengeSent {
+			c.server.randomNumberGenerator.Read(s.ntlmServerContext.serverChallenge[:])
+			challenge, ok := s.ntlmServerContext.processNegotiate(ntlmMessage, c.server.clock.Now())
+			if !ok {
+				return statusLogonFailure, nil
+			}
+			s.ntlmChallengeSent = true
+			if s.usesSPNEGO {
+				challenge = newSPNEGONegTokenResp(spnegoAcceptIncomplete, true, challenge, nil)
+			}
+			return statusMoreProcessingRequired, newSessionSetupResponse(challenge)
+		}
+
+		securityContext, ok := s.ntlmServerContext.processAuthenticate(ntlmMessage)
+		if !ok {
+			return statusLogonFailure, nil
+		}
+		var responseToken []byte
+		if s.usesSPNEGO {
+			// If the client protected the list of
+			// mechanisms, do the same.
+			var mechListMIC []byte
+			if mechListMICIsSet && s.spnegoMechTypes != nil {
+				mechListMIC = securityContext.computeServerMIC(s.spnegoMechTypes, 0)
+			}
+			responseToken = newSPNEGONegTokenResp(spnegoAcceptCompleted, false, nil, mechListMIC)
+		}
+
+		c.lock.Lock()
+		s.ntlmServerContext = nil
+		s.signer = newMessageSigner(securityContext.exportedSessionKey, s.preauthIntegrityHashValue[:])
+		s.signingRequired = securityMode&securityModeSigningRequired != 0
+		c.lock.Unlock()
+		return statusSuccess, newSessionSetupResponse(responseToken)
+	}()
+
+	responseHeader := newResponseHeader(h, status)
+	if body == nil {
+		body = newErrorResponse(nil)
+	}
+	response := append(appendHeader(nil, &responseHeader), body...)
+	switch status {
+	case statusSuccess:
+		// The final SESSION_SETUP response is always signed.
+		s.signer.sign(response)
+	case statusMoreProcessingRequired:
+		c.updatePreauthIntegrityHashValue(&s.preauthIntegrityHashValue, response)
+	default:
+		// Authentication failures cause the session to be
+		// discarded.
+		if s != nil {
+			c.lock.Lock()
+			delete(c.sessions, s.id)
+			c.lock.Unlock()
+		}
+	}
+	c.writeMessage(response)
+}
+
+// newSessionSetupResponse creates the body of a SESSION_SETUP
+// response.
+func newSessionSetupResponse(securityBuffer []byte) []byte {
+	b := []byte{9, 0, 0, 0}
+	b = binary.LittleEndian.AppendUint16(b, headerSize+8)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(securityBuffer)))
+	return append(b, securityBuffer...)
+}
@@ -0,0 +1,263 @@
+package smb3_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual/smb3"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/crypto/md4"
+)
+
+func encodeUTF16(s string) []byte {
+	var b []byte
+	for _, c := range utf16.Encode([]rune(s)) {
+		b = binary.LittleEndian.AppendUint16(b, c)
+	}
+	return b
+}
+
+func computeHMACMD5(key []byte, data ...[]byte) []byte {
+	h := hmac.New(md5.New, key)
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+func randomNumberGeneratorExpectRead(randomNumberGenerator *mock.MockThreadSafeGenerator, data []byte) {
+	randomNumberGenerator.EXPECT().Read(gomock.Len(len(data))).
+		DoAndReturn(func(p []byte) (int, error) {
+			return copy(p, data), nil
+		})
+}
+
+// testClient is a minimal SMB2 client that sends requests to the
+// server, and returns the responses.
+type testClient struct {
+	t         *testing.T
+	r         io.Reader
+	w         io.Writer
+	messageID uint64
+	sessionID uint64
+	treeID    uint32
+}
+
+func (c *testClient) roundTrip(command uint16, body []byte) (uint32, []byte) {
+	var message []byte
+	message = append(message, 0xfe, 'S', 'M', 'B', 64, 0, 0, 0)
+	message = binary.LittleEndian.AppendUint32(message, 0)
+	message = binary.LittleEndian.AppendUint16(message, command)
+	message = binary.LittleEndian.AppendUint16(message, 1)
+	message = binary.LittleEndian.AppendUint32(message, 0)
+	message = binary.LittleEndian.AppendUint32(message, 0)
+	message = binary.LittleEndian.AppendUint64(message, c.messageID)
+	message = binary.LittleEndian.AppendUint32(message, 0)
+	message = binary.LittleEndian.AppendUint32(message, c.treeID)
+	message = binary.LittleEndian.AppendUint64(message, c.sessionID)
+	message = append(message, make([]byte, 16)...)
+	message = append(message, body...)
+	c.messageID++
+
+	transportHeader := binary.BigEndian.AppendUint32(nil, uint32(len(message)))
+	_, err := c.w.Write(append(transportHeader, message...))
+	require.NoError(c.t, err)
+
+	_, err = io.ReadFull(c.r, transportHeader)
+	require.NoError(c.t, err)
+	response := make([]byte, binary.BigEndian.Uint32(transportHeader))
+	_, err = io.ReadFull(c.r, response)
+	require.NoError(c.t, err)
+	require.GreaterOrEqual(c.t, len(response), 64)
+	require.Equal(c.t, []byte{0xfe, 'S', 'M', 'B'}, response[:4])
+	require.Equal(c.t, command, binary.LittleEndian.Uint16(response[12:]))
+	c.sessionID = binary.LittleEndian.Uint64(response[40:])
+	return binary.LittleEndian.Uint32(response[8:]), response
+}
+
+func TestServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	rootDirectory := mock.NewMockVirtualDirectory(ctrl)
+	randomNumberGenerator := mock.NewMockThreadSafeGenerator(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1700000000, 0)).AnyTimes()
+
+	randomNumberGeneratorExpectRead(randomNumberGenerator, []byte("0123456789abcdef"))
+	server := smb3.NewServer(rootDirectory, "localhost", "buildbarn", "user", "password", randomNumberGenerator, clock)
+
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.HandleConnection(serverReader, serverWriter)
+	}()
+	client := testClient{t: t, r: clientReader, w: clientWriter}
+
+	// Negotiate SMB 3.1.1, using SHA-512 preauthentication
+	// integrity.
+	randomNumberGeneratorExpectRead(randomNumberGenerator, make([]byte, 32))
+	negotiateRequest := []byte{36, 0, 1, 0, 1, 0, 0, 0}
+	negotiateRequest = binary.LittleEndian.AppendUint32(negotiateRequest, 0)
+	negotiateRequest = append(negotiateRequest, make([]byte, 16)...)
+	negotiateRequest = binary.LittleEndian.AppendUint32(negotiateRequest, 104)
+	negotiateRequest = append(negotiateRequest, 1, 0, 0, 0)
+	negotiateRequest = binary.LittleEndian.AppendUint16(negotiateRequest, 0x0311)
+	negotiateRequest = append(negotiateRequest, 0, 0)
+	negotiateRequest = append(negotiateRequest, 1, 0, 6, 0, 0, 0, 0, 0, 1, 0, 0, 0, 1, 0)
+	status, response := client.roundTrip(0x0000, negotiateRequest)
+	require.Equal(t, uint32(0), status)
+	require.Equal(t, uint16(0x0311), binary.LittleEndian.Uint16(response[68:]))
+	require.Equal(t, []byte("0123456789abcdef"), response[72:88])
+
+	// Perform NTLMv2 authentication. First send a
+	// NEGOTIATE_MESSAGE, which should cause the server to return
+	// a CHALLENGE_MESSAGE.
+	randomNumberGenerator.EXPECT().Uint64().Return(uint64(0x1122334455667788))
+	randomNumberGeneratorExpectRead(randomNumberGenerator, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+	ntlmNegotiate := []byte("NTLMSSP\x00\x01\x00\x00\x00")
+	ntlmNegotiate = binary.LittleEndian.AppendUint32(ntlmNegotiate, 0xa2088297)
+	ntlmNegotiate = append(ntlmNegotiate, make([]byte, 16)...)
+	newSessionSetupRequest := func(securityBuffer []byte) []byte {
+		b := []byte{25, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 88, 0}
+		b = binary.LittleEndian.AppendUint16(b, uint16(len(securityBuffer)))
+		b = append(b, make([]byte, 8)...)
+		return append(b, securityBuffer...)
+	}
+	status, response = client.roundTrip(0x0001, newSessionSetupRequest(ntlmNegotiate))
+	require.Equal(t, uint32(0xc0000016), status)
+	require.Equal(t, uint64(0x1122334455667788), client.sessionID)
+	challenge := response[binary.LittleEndian.Uint16(response[68:]):][:binary.LittleEndian.Uint16(response[70:])]
+	require.Equal(t, []byte("NTLMSSP\x00\x02\x00\x00\x00"), challenge[:12])
+	require.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, challenge[24:32])
+	targetInfo := challenge[binary.LittleEndian.Uint32(challenge[44:]):][:binary.LittleEndian.Uint16(challenge[40:])]
+
+	// Respond with an AUTHENTICATE_MESSAGE containing an NTLMv2
+	// response that is computed using the correct password.
+	passwordHash := md4.New()
+	passwordHash.Write(encodeUTF16("password"))
+	responseKeyNT := computeHMACMD5(passwordHash.Sum(nil), encodeUTF16(strings.ToUpper("user")+"DOMAIN"))
+	temp := []byte{1, 1, 0, 0, 0, 0, 0, 0}
+	temp = append(temp, make([]byte, 8)...)
+	temp = append(temp, "clientch"...)
+	temp = append(temp, 0, 0, 0, 0)
+	temp = append(temp, targetInfo...)
+	temp = append(temp, 0, 0, 0, 0)
+	ntChallengeResponse := append(computeHMACMD5(responseKeyNT, challenge[24:32], temp), temp...)
+	domainName := encodeUTF16("DOMAIN")
+	userName := encodeUTF16("user")
+	ntlmAuthenticate := []byte("NTLMSSP\x00\x03\x00\x00\x00")
+	appendField := func(b []byte, length, offset int) []byte {
+		b = binary.LittleEndian.AppendUint16(b, uint16(length))
+		b = binary.LittleEndian.AppendUint16(b, uint16(length))
+		return binary.LittleEndian.AppendUint32(b, uint32(offset))
+	}
+	ntlmAuthenticate = appendField(ntlmAuthenticate, 0, 64)
+	ntlmAuthenticate = appendField(ntlmAuthenticate, len(ntChallengeResponse), 64)
+	ntlmAuthenticate = appendField(ntlmAuthenticate, len(domainName), 64+len(ntChallengeResponse))
+	ntlmAuthenticate = appendField(ntlmAuthenticate, len(userName), 64+len(ntChallengeResponse)+len(domainName))
+	ntlmAuthenticate = appendField(ntlmAuthenticate, 0, 64)
+	ntlmAuthenticate = appendField(ntlmAuthenticate, 0, 64)
+	ntlmAuthenticate = binary.LittleEndian.AppendUint32(ntlmAuthenticate, 0xa2088215)
+	ntlmAuthenticate = append(ntlmAuthenticate, ntChallengeResponse...)
+	ntlmAuthenticate = append(ntlmAuthenticate, domainName...)
+	ntlmAuthenticate = append(ntlmAuthenticate, userName...)
+	status, response = client.roundTrip(0x0001, newSessionSetupRequest(ntlmAuthenticate))
+	require.Equal(t, uint32(0), status)
+	require.Equal(t, uint32(0x00000009), binary.LittleEndian.Uint32(response[16:]))
+
+	// Connect to the share.
+	sharePath := encodeUTF16(`\\localhost\buildbarn`)
+	treeConnectRequest := []byte{9, 0, 0, 0, 72, 0}
+	treeConnectRequest = binary.LittleEndian.AppendUint16(treeConnectRequest, uint16(len(sharePath)))
+	treeConnectRequest = append(treeConnectRequest, sharePath...)
+	status, response = client.roundTrip(0x0003, treeConnectRequest)
+	require.Equal(t, uint32(0), status)
+	require.Equal(t, byte(1), response[66])
+	client.treeID = binary.LittleEndian.Uint32(response[36:])
+
+	// Open a file for reading.
+	leaf := mock.NewMockVirtualLeaf(ctrl)
+	rootDirectory.EXPECT().VirtualLookup(gomock.Any(), path.MustNewComponent("hello.txt"), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, name path.Component, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.DirectoryChild, virtual.Status) {
+			out.SetFileType(filesystem.FileTypeRegularFile)
+			out.SetInodeNumber(123)
+			out.SetLinkCount(1)
+			out.SetPermissions(virtual.PermissionsRead)
+			out.SetSizeBytes(13)
+			return virtual.DirectoryChild{}.FromLeaf(leaf), virtual.StatusOK
+		})
+	leaf.EXPECT().VirtualOpenSelf(gomock.Any(), virtual.ShareMaskRead, &virtual.OpenExistingOptions{}, gomock.Any(), gomock.Any()).Return(virtual.StatusOK)
+	fileName := encodeUTF16("hello.txt")
+	createRequest := []byte{57, 0, 0, 0}
+	createRequest = binary.LittleEndian.AppendUint32(createRequest, 2)
+	createRequest = append(createRequest, make([]byte, 16)...)
+	createRequest = binary.LittleEndian.AppendUint32(createRequest, 0x00120089)
+	createRequest = binary.LittleEndian.AppendUint32(createRequest, 0)
+	createRequest = binary.LittleEndian.AppendUint32(createRequest, 7)
+	createRequest = binary.LittleEndian.AppendUint32(createRequest, 1)
+	createRequest = binary.LittleEndian.AppendUint32(createRequest, 0x40)
+	createRequest = binary.LittleEndian.AppendUint16(createRequest, 120)
+	createRequest = binary.LittleEndian.AppendUint16(createRequest, uint16(len(fileName)))
+	createRequest = binary.LittleEndian.AppendUint64(createRequest, 0)
+	createRequest = append(createRequest, fileName...)
+	status, response = client.roundTrip(0x0005, createRequest)
+	require.Equal(t, uint32(0), status)
+	require.Equal(t, uint32(1), binary.LittleEndian.Uint32(response[68:]))
+	require.Equal(t, uint64(4096), binary.LittleEndian.Uint64(response[104:]))
+	require.Equal(t, uint64(13), binary.LittleEndian.Uint64(response[112:]))
+	require.Equal(t, uint32(0x00000001), binary.LittleEndian.Uint32(response[120:]))
+	fileID := response[128:144]
+
+	// Read the contents of the file.
+	leaf.EXPECT().VirtualRead(gomock.Len(1024), uint64(0)).
+		DoAndReturn(func(buf []byte, offset uint64) (int, bool, virtual.Status) {
+			return copy(buf, "Hello, world!"), true, virtual.StatusOK
+		})
+	readRequest := []byte{49, 0, 0, 0}
+	readRequest = binary.LittleEndian.AppendUint32(readRequest, 1024)
+	readRequest = binary.LittleEndian.AppendUint64(readRequest, 0)
+	readRequest = append(readRequest, fileID...)
+	readRequest = append(readRequest, make([]byte, 17)...)
+	status, response = client.roundTrip(0x0008, readRequest)
+	require.Equal(t, uint32(0), status)
+	require.Equal(t, []byte("Hello, world!"), response[80:])
+
+	// Writing to the file should not be permitted, as it was only
+	// opened for reading.
+	writeRequest := []byte{49, 0, 112, 0}
+	writeRequest = binary.LittleEndian.AppendUint32(writeRequest, 5)
+	writeRequest = binary.LittleEndian.AppendUint64(writeRequest, 0)
+	writeRequest = append(writeRequest, fileID...)
+	writeRequest = append(writeRequest, make([]byte, 16)...)
+	writeRequest = append(writeRequest, "Hello"...)
+	status, _ = client.roundTrip(0x0009, writeRequest)
+	require.Equal(t, uint32(0xc0000022), status)
+
+	// Close the file.
+	leaf.EXPECT().VirtualClose(virtual.ShareMaskRead)
+	closeRequest := []byte{24, 0, 0, 0, 0, 0, 0, 0}
+	closeRequest = append(closeRequest, fileID...)
+	status, _ = client.roundTrip(0x0006, closeRequest)
+	require.Equal(t, uint32(0), status)
+
+	// Subsequent attempts to use the file should fail.
+	status, _ = client.roundTrip(0x0008, readRequest)
+	require.Equal(t, uint32(0xc0000128), status)
+
+	require.NoError(t, clientWriter.Close())
+	require.NoError(t, <-errChan)
+}
@@ -0,0 +1,114 @@
+package smb3
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+)
+
+// deriveKey derives a 128-bit key from a session key, using the
+// SP800-108 key derivation function in counter mode with HMAC-SHA256,
+// as described in [MS-SMB2] section 3.1.4.2.
+func deriveKey(sessionKey, label, context []byte) []byte {
+	h := hmac.New(sha256.New, sessionKey)
+	h.Write([]byte{0, 0, 0, 1})
+	h.Write(label)
+	h.Write([]byte{0})
+	h.Write(context)
+	h.Write([]byte{0, 0, 0, 128})
+	return h.Sum(nil)[:16]
+}
+
+// cmacSubkey doubles a value in GF(2^128), as is done to derive the
+// subkeys of AES-CMAC.
+func cmacSubkey(in [aes.BlockSize]byte) (out [aes.BlockSize]byte) {
+	for i := 0; i < aes.BlockSize-1; i++ {
+		out[i] = in[i]<<1 | in[i+1]>>7
+	}
+	out[aes.BlockSize-1] = in[aes.BlockSize-1] << 1
+	if in[0]&0x80 != 0 {
+		out[aes.BlockSize-1] ^= 0x87
+	}
+	return
+}
+
+// computeCMAC computes the AES-CMAC of a message, as described in RFC
+// 4493.
+func computeCMAC(block cipher.Block, message []byte) (mac [aes.BlockSize]byte) {
+	var l [aes.BlockSize]byte
+	block.Encrypt(l[:], l[:])
+	k1 := cmacSubkey(l)
+
+	// Process all blocks except the last one.
+	for len(message) > aes.BlockSize {
+		subtle.XORBytes(mac[:], mac[:], message[:aes.BlockSize])
+		block.Encrypt(mac[:], mac[:])
+		message = message[aes.BlockSize:]
+	}
+
+	// The last block is XORed with the first subkey if it is
+	// complete. Otherwise, it is padded and XORed with the second
+	// subkey.
+	var last [aes.BlockSize]byte
+	copy(last[:], message)
+	if len(message) == aes.BlockSize {
+		subtle.XORBytes(last[:], last[:], k1[:])
+	} else {
+		last[len(message)] = 0x80
+		k2 := cmacSubkey(k1)
+		subtle.XORBytes(last[:], last[:], k2[:])
+	}
+	subtle.XORBytes(mac[:], mac[:], last[:])
+	block.Encrypt(mac[:], mac[:])
+	return
+}
+
+// messageSigner is capable of signing SMB2 messages and validating
+// their signatures using AES-CMAC.
+type messageSigner struct {
+	block cipher.Block
+}
+
+// newMessageSigner creates a messageSigner for a session, deriving
+// the signing key from the session key and the preauthentication
+// integrity hash value, as described in [MS-SMB2] section 3.3.5.5.3.
+func newMessageSigner(sessionKey []byte, preauthIntegrityHashValue []byte) *messageSigner {
+	block, err := aes.NewCipher(deriveKey(sessionKey, []byte("SMBSigningKey\x00"), preauthIntegrityHashValue))
+	if err != nil {
+		panic(err)
+	}
+	return &messageSigner{block: block}
+}
+
+// computeSignature computes the signature of a message. The
+// signature is computed with the Signature field of the header set to
+// zero.
+func (ms *messageSigner) computeSignature(message []byte) [16]byte {
+	var signature [16]byte
+	copy(signature[:], message[48:headerSize])
+	for i := 48; i < headerSize; i++ {
+		message[i] = 0
+	}
+	mac := computeCMAC(ms.block, message)
+	copy(message[48:headerSize], signature[:])
+	return mac
+}
+
+// sign a message in place.
+func (ms *messageSigner) sign(message []byte) {
+	binary.LittleEndian.PutUint32(message[16:], binary.LittleEndian.Uint32(message[16:])|flagsSigned)
+	for i := 48; i < headerSize; i++ {
+		message[i] = 0
+	}
+	mac := computeCMAC(ms.block, message)
+	copy(message[48:headerSize], mac[:])
+}
+
+// verify the signature of a message.
+func (ms *messageSigner) verify(message []byte) bool {
+	signature := ms.computeSignature(message)
+	return hmac.Equal(signature[:], message[48:headerSize])
+}
@@ -0,0 +1,219 @@
+package smb3
+
+import (
+	"bytes"
+)
+
+// Minimal implementation of the Simple and Protected GSSAPI
+// Negotiation Mechanism (SPNEGO), as described in RFC 4178. SMB2
+// clients wrap their authentication tokens in SPNEGO messages. NTLM is
+// the only mechanism that is offered by the server.
+
+var (
+	spnegoOID = []byte{0x2b, 0x06, 0x01, 0x05, 0x05, 0x02}
+	ntlmOID   = []byte{0x2b, 0x06, 0x01, 0x04, 0x01, 0x82, 0x37, 0x02, 0x02, 0x0a}
+)
+
+// ASN.1 DER tags used by SPNEGO.
+const (
+	derTagEnumerated      = 0x0a
+	derTagOctetString     = 0x04
+	derTagObjectID        = 0x06
+	derTagSequence        = 0x30
+	derTagApplication0    = 0x60
+	derTagContextSpecific = 0xa0
+)
+
+// Values of the negState field of NegTokenResp.
+const (
+	spnegoAcceptCompleted  = 0
+	spnegoAcceptIncomplete = 1
+	spnegoReject           = 2
+)
+
+// parseDER parses a single ASN.1 DER encoded element, returning its
+// tag, contents and the data that follows it.
+func parseDER(b []byte) (tag byte, contents, rest []byte, ok bool) {
+	if len(b) < 2 {
+		return 0, nil, nil, false
+	}
+	tag = b[0]
+	length := int(b[1])
+	b = b[2:]
+	if length >= 0x80 {
+		lengthBytes := length & 0x7f
+		if lengthBytes == 0 || lengthBytes > 3 || len(b) < lengthBytes {
+			return 0, nil, nil, false
+		}
+		length = 0
+		for _, c := range b[:lengthBytes] {
+			length = length<<8 | int(c)
+		}
+		b = b[lengthBytes:]
+	}
+	if len(b) < length {
+		return 0, nil, nil, false
+	}
+	return tag, b[:length], b[length:], true
+}
+
+// appendDER appends an ASN.1 DER encoded element to a buffer.
+func appendDER(b []byte, tag byte, contents ...[]byte) []byte {
+	length := 0
+	for _, c := range contents {
+		length += len(c)
+	}
+	b = append(b, tag)
+	switch {
+	case length < 0x80:
+		b = append(b, byte(length))
+	case length < 0x100:
+		b = append(b, 0x81, byte(length))
+	case length < 0x10000:
+		b = append(b, 0x82, byte(length>>8), byte(length))
+	default:
+		b = append(b, 0x83, byte(length>>16), byte(length>>8), byte(length))
+	}
+	for _, c := range contents {
+		b = append(b, c...)
+	}
+	return b
+}
+
+// parseDERSequenceFields parses the fields of a DER sequence
+// consisting of context-specific tagged elements, returning the
+// encoded contents of each of them.
+func parseDERSequenceFields(b []byte) (map[byte][]byte, bool) {
+	tag, contents, rest, ok := parseDER(b)
+	if !ok || tag != derTagSequence || len(rest) != 0 {
+		return nil, false
+	}
+	fields := map[byte][]byte{}
+	for len(contents) > 0 {
+		tag, field, rest, ok := parseDER(contents)
+		if !ok || tag&0xe0 != derTagContextSpecific {
+			return nil, false
+		}
+		fields[tag&0x1f] = field
+		contents = rest
+	}
+	return fields, true
+}
+
+// parseDEROctetString parses a DER encoded octet string.
+func parseDEROctetString(b []byte) ([]byte, bool) {
+	tag, contents, rest, ok := parseDER(b)
+	if !ok || tag != derTagOctetString || len(rest) != 0 {
+		return nil, false
+	}
+	return contents, true
+}
+
+// spnegoToken contains the fields of a NegTokenInit or NegTokenResp
+// message sent by the client that are relevant to the server.
+type spnegoToken struct {
+	// DER encoded list of mechanisms supported by the client. Only
+	// set for NegTokenInit.
+	mechTypes        []byte
+	ntlmIsPreferred  bool
+	ntlmIsSupported  bool
+	mechToken        []byte
+	mechListMICIsSet bool
+}
+
+// parseSPNEGOToken parses a SPNEGO token sent by the client. The
+// initial token sent by the client is a NegTokenInit that is wrapped
+// in a GSS-API InitialContextToken. Subsequent tokens are NegTokenResp
+// messages.
+func parseSPNEGOToken(b []byte) (spnegoToken, bool) {
+	tag, contents, _, ok := parseDER(b)
+	if !ok {
+		return spnegoToken{}, false
+	}
+	switch tag {
+	case derTagApplication0:
+		// InitialContextToken containing a NegTokenInit.
+		tag, oid, rest, ok := parseDER(contents)
+		if !ok || tag != derTagObjectID || !bytes.Equal(oid, spnegoOID) {
+			return spnegoToken{}, false
+		}
+		tag, negTokenInit, _, ok := parseDER(rest)
+		if !ok || tag != derTagContextSpecific|0 {
+			return spnegoToken{}, false
+		}
+		fields, ok := parseDERSequenceFields(negTokenInit)
+		if !ok {
+			return spnegoToken{}, false
+		}
+		token := spnegoToken{mechTypes: fields[0]}
+		tag, mechTypes, _, ok := parseDER(token.mechTypes)
+		if !ok || tag != derTagSequence {
+			return spnegoToken{}, false
+		}
+		for first := true; len(mechTypes) > 0; first = false {
+			tag, mechType, rest, ok := parseDER(mechTypes)
+			if !ok || tag != derTagObjectID {
+				return spnegoToken{}, false
+			}
+			if bytes.Equal(mechType, ntlmOID) {
+				token.ntlmIsSupported = true
+				token.ntlmIsPreferred = first
+			}
+			mechTypes = rest
+		}
+		if mechToken, ok := fields[2]; ok {
+			if token.mechToken, ok = parseDEROctetString(mechToken); !ok {
+				return spnegoToken{}, false
+			}
+		}
+		_, token.mechListMICIsSet = fields[3]
+		return token, true
+	case derTagContextSpecific | 1:
+		// NegTokenResp.
+		fields, ok := parseDERSequenceFields(contents)
+		if !ok {
+			return spnegoToken{}, false
+		}
+		token := spnegoToken{ntlmIsSupported: true, ntlmIsPreferred: true}
+		if responseToken, ok := fields[2]; ok {
+			if token.mechToken, ok = parseDEROctetString(responseToken); !ok {
+				return spnegoToken{}, false
+			}
+		}
+		_, token.mechListMICIsSet = fields[3]
+		return token, true
+	default:
+		return spnegoToken{}, false
+	}
+}
+
+// newSPNEGONegTokenInit creates the initial SPNEGO token that is
+// returned as part of the NEGOTIATE response, announcing that NTLM is
+// the only supported mechanism.
+func newSPNEGONegTokenInit() []byte {
+	return appendDER(nil, derTagApplication0,
+		appendDER(nil, derTagObjectID, spnegoOID),
+		appendDER(nil, derTagContextSpecific|0,
+			appendDER(nil, derTagSequence,
+				appendDER(nil, derTagContextSpecific|0,
+					appendDER(nil, derTagSequence,
+						appendDER(nil, derTagObjectID, ntlmOID))))))
+}
+
+// newSPNEGONegTokenResp creates a SPNEGO token that is returned as
+// part of SESSION_SETUP responses.
+func newSPNEGONegTokenResp(negState byte, supportedMech bool, responseToken, mechListMIC []byte) []byte {
+	fields := [][]byte{
+		appendDER(nil, derTagContextSpecific|0, appendDER(nil, derTagEnumerated, []byte{negState})),
+	}
+	if supportedMech {
+		fields = append(fields, appendDER(nil, derTagContextSpecific|1, appendDER(nil, derTagObjectID, ntlmOID)))
+	}
+	if responseToken != nil {
+		fields = append(fields, appendDER(nil, derTagContextSpecific|2, appendDER(nil, derTagOctetString, responseToken)))
+	}
+	if mechListMIC != nil {
+		fields = append(fields, appendDER(nil, derTagContextSpecific|3, appendDER(nil, derTagOctetString, mechListMIC)))
+	}
+	return appendDER(nil, derTagContextSpecific|1, appendDER(nil, derTagSequence, fields...))
+}
@@ -1,8 +1,11 @@
 package virtual
 
 import (
+	"encoding/binary"
 	"io"
+	"os"
 	"sync"
+	"time"
 
 	"github.com/buildbarn/bb-storage/pkg/digest"
 )
@@ -30,8 +33,8 @@ func NewStatelessHandleAllocatingCASFileFactory(base CASFileFactory, allocation
 	return cff
 }
 
-func (cff *statelessHandleAllocatingCASFileFactory) LookupFile(blobDigest digest.Digest, isExecutable bool, readMonitor FileReadMonitor) NativeLeaf {
-	leaf := cff.base.LookupFile(blobDigest, isExecutable, nil)
+func (cff *statelessHandleAllocatingCASFileFactory) LookupFile(blobDigest digest.Digest, isExecutable bool, lastDataModificationTime time.Time, readMonitor FileReadMonitor) NativeLeaf {
+	leaf := cff.base.LookupFile(blobDigest, isExecutable, lastDataModificationTime, nil)
 	if readMonitor != nil {
 		leaf = &readMonitoringNativeLeaf{
 			NativeLeaf: leaf,
@@ -40,8 +43,9 @@ func (cff *statelessHandleAllocatingCASFileFactory) LookupFile(blobDigest digest
 	}
 	return cff.allocator.
 		New(&casFileID{
-			blobDigest:   blobDigest,
-			isExecutable: isExecutable,
+			blobDigest:               blobDigest,
+			isExecutable:             isExecutable,
+			lastDataModificationTime: lastDataModificationTime,
 		}).
 		AsNativeLeaf(leaf)
 }
@@ -50,8 +54,9 @@ func (cff *statelessHandleAllocatingCASFileFactory) LookupFile(blobDigest digest
 // construct a file through CASFileFactory to a unique identifier to be
 // provided to StatelessHandleAllocator.
 type casFileID struct {
-	blobDigest   digest.Digest
-	isExecutable bool
+	blobDigest               digest.Digest
+	isExecutable             bool
+	lastDataModificationTime time.Time
 }
 
 func (id *casFileID) WriteTo(w io.Writer) (nTotal int64, err error) {
@@ -64,6 +69,14 @@ func (id *casFileID) WriteTo(w io.Writer) (nTotal int64, err error) {
 		n, _ := w.Write([]byte{0})
 		nTotal += int64(n)
 	}
+	if !id.lastDataModificationTime.IsZero() {
+		// Files with differing modification times must not
+		// share the same identifier, as their attributes differ.
+		var timestamp [8]byte
+		binary.LittleEndian.PutUint64(timestamp[:], uint64(id.lastDataModificationTime.UnixNano()))
+		n, _ := w.Write(timestamp[:])
+		nTotal += int64(n)
+	}
 	return
 }
 
@@ -84,3 +97,13 @@ func (l *readMonitoringNativeLeaf) VirtualRead(buf []byte, off uint64) (int, boo
 	l.once.Do(l.reportRead)
 	return l.NativeLeaf.VirtualRead(buf, off)
 }
+
+func (l *readMonitoringNativeLeaf) VirtualOpenPassthroughFile() *os.File {
+	// Reads against passthrough files are processed by the kernel
+	// directly. Conservatively report them as being read.
+	f := l.NativeLeaf.VirtualOpenPassthroughFile()
+	if f != nil {
+		l.once.Do(l.reportRead)
+	}
+	return f
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"testing"
+	"time"
 
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-remote-execution/internal/mock"
@@ -28,6 +29,7 @@ func TestStatelessHandleAllocatingCASFileFactory(t *testing.T) {
 		baseCASFileFactory.EXPECT().LookupFile(
 			blobDigest,
 			/* isExecutable = */ false,
+			/* lastDataModificationTime = */ time.Time{},
 			/* readMonitor = */ nil,
 		).Return(underlyingLeaf)
 		wrappedLeaf := mock.NewMockNativeLeaf(ctrl)
@@ -48,7 +50,7 @@ func TestStatelessHandleAllocatingCASFileFactory(t *testing.T) {
 		})
 		leafHandleAllocation.EXPECT().AsNativeLeaf(underlyingLeaf).Return(wrappedLeaf)
 
-		require.Equal(t, wrappedLeaf, casFileFactory.LookupFile(blobDigest, false, nil))
+		require.Equal(t, wrappedLeaf, casFileFactory.LookupFile(blobDigest, false, time.Time{}, nil))
 	})
 
 	t.Run("Executable", func(t *testing.T) {
@@ -57,6 +59,7 @@ func TestStatelessHandleAllocatingCASFileFactory(t *testing.T) {
 		baseCASFileFactory.EXPECT().LookupFile(
 			blobDigest,
 			/* isExecutable = */ true,
+			/* lastDataModificationTime = */ time.Time{},
 			/* readMonitor = */ nil,
 		).Return(underlyingLeaf)
 		wrappedLeaf := mock.NewMockNativeLeaf(ctrl)
@@ -77,7 +80,42 @@ func TestStatelessHandleAllocatingCASFileFactory(t *testing.T) {
 		})
 		leafHandleAllocation.EXPECT().AsNativeLeaf(underlyingLeaf).Return(wrappedLeaf)
 
-		require.Equal(t, wrappedLeaf, casFileFactory.LookupFile(blobDigest, true, nil))
+		require.Equal(t, wrappedLeaf, casFileFactory.LookupFile(blobDigest, true, time.Time{}, nil))
+	})
+
+	t.Run("LastDataModificationTime", func(t *testing.T) {
+		// Files with an explicit modification time should
+		// receive a different identifier than ones without.
+		blobDigest := digest.MustNewDigest("foobar", remoteexecution.DigestFunction_MD5, "c8a4ddfcd3a5a0caf4cc1d64883df421", 456)
+		lastDataModificationTime := time.Unix(0, 0x0102030405060708)
+		underlyingLeaf := mock.NewMockNativeLeaf(ctrl)
+		baseCASFileFactory.EXPECT().LookupFile(
+			blobDigest,
+			/* isExecutable = */ false,
+			lastDataModificationTime,
+			/* readMonitor = */ nil,
+		).Return(underlyingLeaf)
+		wrappedLeaf := mock.NewMockNativeLeaf(ctrl)
+		leafHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		handleAllocator.EXPECT().New(gomock.Any()).DoAndReturn(func(id io.WriterTo) virtual.StatelessHandleAllocation {
+			idBuf := bytes.NewBuffer(nil)
+			n, err := id.WriteTo(idBuf)
+			require.NoError(t, err)
+			require.Equal(t, int64(55), n)
+			require.Equal(t, []byte(
+				// Length of digest.
+				"\x2d"+
+					// Digest.
+					"3-c8a4ddfcd3a5a0caf4cc1d64883df421-456-foobar"+
+					// Executable flag.
+					"\x00"+
+					// Modification time.
+					"\x08\x07\x06\x05\x04\x03\x02\x01"), idBuf.Bytes())
+			return leafHandleAllocation
+		})
+		leafHandleAllocation.EXPECT().AsNativeLeaf(underlyingLeaf).Return(wrappedLeaf)
+
+		require.Equal(t, wrappedLeaf, casFileFactory.LookupFile(blobDigest, false, lastDataModificationTime, nil))
 	})
 
 	t.Run("WithReadMonitor", func(t *testing.T) {
@@ -90,6 +128,7 @@ func TestStatelessHandleAllocatingCASFileFactory(t *testing.T) {
 		baseCASFileFactory.EXPECT().LookupFile(
 			blobDigest,
 			/* isExecutable = */ true,
+			/* lastDataModificationTime = */ time.Time{},
 			/* readMonitor = */ nil,
 		).Return(underlyingLeaf)
 		wrappedLeaf := mock.NewMockNativeLeaf(ctrl)
@@ -115,7 +154,7 @@ func TestStatelessHandleAllocatingCASFileFactory(t *testing.T) {
 		})
 		fileReadMonitor := mock.NewMockFileReadMonitor(ctrl)
 
-		require.Equal(t, wrappedLeaf, casFileFactory.LookupFile(blobDigest, true, fileReadMonitor.Call))
+		require.Equal(t, wrappedLeaf, casFileFactory.LookupFile(blobDigest, true, time.Time{}, fileReadMonitor.Call))
 
 		// Reading the file's contents should cause it to be reported
 		// as being read. This should only happen just once.
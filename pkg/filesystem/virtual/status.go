@@ -29,6 +29,9 @@ const (
 	// StatusErrNoEnt indicate sthat the operation failed due to a
 	// file not existing.
 	StatusErrNoEnt
+	// StatusErrNoXAttr indicates that an extended attribute with
+	// the provided name does not exist.
+	StatusErrNoXAttr
 	// StatusErrNotDir indicates that a request is made against a
 	// leaf when the current operation does not allow a leaf as a
 	// target.
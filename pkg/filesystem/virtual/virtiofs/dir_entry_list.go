@@ -0,0 +1,101 @@
+//go:build linux
+// +build linux
+
+package virtiofs
+
+import (
+	"encoding/binary"
+	"syscall"
+	"unsafe"
+
+	"github.com/hanwen/go-fuse/v2/fuse"
+)
+
+const (
+	// Size of struct fuse_dirent, excluding the name.
+	direntSize = 24
+	// Size of struct fuse_entry_out, which precedes every entry
+	// returned by READDIRPLUS.
+	entryOutSize = int(unsafe.Sizeof(fuse.EntryOut{}))
+	// Inode number that is reported by the kernel if none is
+	// provided. Corresponds to FUSE_UNKNOWN_INO.
+	unknownInodeNumber = 0xffffffff
+)
+
+// dirEntryList is an implementation of fuse.ReadDirEntryList and
+// fuse.ReadDirPlusEntryList that encodes directory entries into a
+// buffer, using the format that is expected by the kernel.
+type dirEntryList struct {
+	buf        []byte
+	size       int
+	plus       bool
+	lastDirent int
+	entries    []*fuse.EntryOut
+}
+
+var (
+	_ fuse.ReadDirEntryList     = (*dirEntryList)(nil)
+	_ fuse.ReadDirPlusEntryList = (*dirEntryList)(nil)
+)
+
+func newDirEntryList(buf []byte, plus bool) *dirEntryList {
+	return &dirEntryList{
+		buf:        buf,
+		plus:       plus,
+		lastDirent: -1,
+	}
+}
+
+func (l *dirEntryList) add(e fuse.DirEntry, off uint64) (int, bool) {
+	// Entries are padded to a multiple of eight bytes.
+	entrySize := (direntSize + len(e.Name) + 7) &^ 7
+	start := l.size
+	if l.plus {
+		entrySize += entryOutSize
+	}
+	if start+entrySize > len(l.buf) {
+		return 0, false
+	}
+	b := l.buf[start : start+entrySize]
+	for i := range b {
+		b[i] = 0
+	}
+	if l.plus {
+		b = b[entryOutSize:]
+	}
+
+	ino := e.Ino
+	if ino == 0 {
+		ino = unknownInodeNumber
+	}
+	binary.LittleEndian.PutUint64(b[0:], ino)
+	binary.LittleEndian.PutUint64(b[8:], off)
+	binary.LittleEndian.PutUint32(b[16:], uint32(len(e.Name)))
+	binary.LittleEndian.PutUint32(b[20:], (e.Mode&syscall.S_IFMT)>>12)
+	copy(b[direntSize:], e.Name)
+
+	l.size += entrySize
+	l.lastDirent = l.size - len(b)
+	return start, true
+}
+
+func (l *dirEntryList) AddDirEntry(e fuse.DirEntry, off uint64) bool {
+	_, ok := l.add(e, off)
+	return ok
+}
+
+func (l *dirEntryList) AddDirLookupEntry(e fuse.DirEntry, off uint64) *fuse.EntryOut {
+	start, ok := l.add(e, off)
+	if !ok {
+		return nil
+	}
+	entry := (*fuse.EntryOut)(unsafe.Pointer(&l.buf[start]))
+	l.entries = append(l.entries, entry)
+	return entry
+}
+
+func (l *dirEntryList) FixMode(mode uint32) {
+	if l.lastDirent >= 0 {
+		binary.LittleEndian.PutUint32(l.buf[l.lastDirent+20:], (mode&syscall.S_IFMT)>>12)
+	}
+}
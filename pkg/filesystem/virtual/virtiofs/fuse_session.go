@@ -0,0 +1,604 @@
+//go:build linux
+// +build linux
+
+package virtiofs
+
+import (
+	"bytes"
+	"sync"
+	"unsafe"
+
+	"github.com/hanwen/go-fuse/v2/fuse"
+)
+
+// FUSE opcodes that are processed by fuseSession.
+const (
+	opLookup        uint32 = 1
+	opForget        uint32 = 2
+	opGetattr       uint32 = 3
+	opSetattr       uint32 = 4
+	opReadlink      uint32 = 5
+	opSymlink       uint32 = 6
+	opMknod         uint32 = 8
+	opMkdir         uint32 = 9
+	opUnlink        uint32 = 10
+	opRmdir         uint32 = 11
+	opRename        uint32 = 12
+	opLink          uint32 = 13
+	opOpen          uint32 = 14
+	opRead          uint32 = 15
+	opWrite         uint32 = 16
+	opStatfs        uint32 = 17
+	opRelease       uint32 = 18
+	opFsync         uint32 = 20
+	opFlush         uint32 = 25
+	opInit          uint32 = 26
+	opOpendir       uint32 = 27
+	opReaddir       uint32 = 28
+	opReleasedir    uint32 = 29
+	opFsyncdir      uint32 = 30
+	opAccess        uint32 = 34
+	opCreate        uint32 = 35
+	opInterrupt     uint32 = 36
+	opDestroy       uint32 = 38
+	opBatchForget   uint32 = 42
+	opFallocate     uint32 = 43
+	opReaddirplus   uint32 = 44
+	opRename2       uint32 = 45
+	opLseek         uint32 = 46
+	opCopyFileRange uint32 = 47
+)
+
+const (
+	// The FUSE protocol version that is implemented. Guests need to
+	// support at least this version, which is the case for every
+	// kernel that ships with a virtio-fs driver.
+	fuseKernelVersion      = 7
+	fuseKernelMinorVersion = 28
+
+	// The maximum size of the payload of WRITE requests.
+	maximumWriteSizeBytes = 1 << 20
+
+	// Capabilities that are announced to the guest, if supported by
+	// it. Capabilities that require splicing or POSIX locking are
+	// omitted, as they are not supported.
+	supportedCapabilities = fuse.CAP_ASYNC_READ |
+		fuse.CAP_BIG_WRITES |
+		fuse.CAP_AUTO_INVAL_DATA |
+		fuse.CAP_READDIRPLUS |
+		fuse.CAP_PARALLEL_DIROPS |
+		fuse.CAP_MAX_PAGES
+
+	outHeaderSize = int(unsafe.Sizeof(fuse.OutHeader{}))
+)
+
+// forgetOne corresponds to struct fuse_forget_one, which is used by
+// BATCH_FORGET requests.
+type forgetOne struct {
+	NodeID  uint64
+	Nlookup uint64
+}
+
+// batchForgetIn corresponds to struct fuse_batch_forget_in.
+type batchForgetIn struct {
+	fuse.InHeader
+	Count uint32
+	Dummy uint32
+}
+
+// decodeInput decodes the fixed size portion of a FUSE request. It
+// also returns the remainder of the request, which may contain names
+// or data.
+func decodeInput[T any](request []byte) (*T, []byte, bool) {
+	var v T
+	size := int(unsafe.Sizeof(v))
+	if len(request) < size {
+		return nil, nil, false
+	}
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(&v)), size), request)
+	return &v, request[size:], true
+}
+
+// encodeOutput encodes the fixed size portion of a FUSE response.
+func encodeOutput[T any](response []byte, v *T) (int, fuse.Status) {
+	size := int(unsafe.Sizeof(*v))
+	if len(response) < size {
+		return 0, fuse.EINVAL
+	}
+	return copy(response, unsafe.Slice((*byte)(unsafe.Pointer(v)), size)), fuse.OK
+}
+
+// parseNames parses a sequence of null terminated strings that is
+// part of a FUSE request.
+func parseNames(data []byte, count int) ([]string, bool) {
+	names := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		n := bytes.IndexByte(data, 0)
+		if n < 0 {
+			return nil, false
+		}
+		names = append(names, string(data[:n]))
+		data = data[n+1:]
+	}
+	return names, true
+}
+
+// fuseSession processes FUSE requests sent by a single guest, by
+// forwarding them to a RawFileSystem.
+//
+// As guests may disconnect at any point in time, the session keeps
+// track of lookup counts of nodes that have been returned to the
+// guest. This permits releasing them upon disconnection, so that
+// the RawFileSystem doesn't leak nodes.
+type fuseSession struct {
+	rawFileSystem fuse.RawFileSystem
+
+	lock         sync.Mutex
+	lookupCounts map[uint64]uint64
+	inFlight     map[uint64]chan struct{}
+}
+
+func newFUSESession(rawFileSystem fuse.RawFileSystem) *fuseSession {
+	return &fuseSession{
+		rawFileSystem: rawFileSystem,
+		lookupCounts:  map[uint64]uint64{},
+		inFlight:      map[uint64]chan struct{}{},
+	}
+}
+
+func (s *fuseSession) incrementLookupCount(nodeID uint64) {
+	if nodeID != 0 {
+		s.lock.Lock()
+		s.lookupCounts[nodeID]++
+		s.lock.Unlock()
+	}
+}
+
+func (s *fuseSession) forget(nodeID, nlookup uint64) {
+	// Ignore requests for nodes that were never returned to the
+	// guest, as forwarding these would cause the RawFileSystem to
+	// panic.
+	s.lock.Lock()
+	count := s.lookupCounts[nodeID]
+	if nlookup > count {
+		nlookup = count
+	}
+	if count == nlookup {
+		delete(s.lookupCounts, nodeID)
+	} else {
+		s.lookupCounts[nodeID] = count - nlookup
+	}
+	s.lock.Unlock()
+
+	if nlookup > 0 {
+		s.rawFileSystem.Forget(nodeID, nlookup)
+	}
+}
+
+// forgetAll releases all nodes that are still referenced by the guest.
+// This is called when the guest unmounts the file system or
+// disconnects.
+func (s *fuseSession) forgetAll() {
+	s.lock.Lock()
+	lookupCounts := s.lookupCounts
+	s.lookupCounts = map[uint64]uint64{}
+	s.lock.Unlock()
+
+	for nodeID, nlookup := range lookupCounts {
+		s.rawFileSystem.Forget(nodeID, nlookup)
+	}
+}
+
+func (s *fuseSession) interrupt(unique uint64) {
+	s.lock.Lock()
+	if cancel, ok := s.inFlight[unique]; ok {
+		close(cancel)
+		delete(s.inFlight, unique)
+	}
+	s.lock.Unlock()
+}
+
+// handleRequest processes a single FUSE request. The response is
+// written into the provided buffer. The size of the response is
+// returned, which is zero for requests that don't have a response.
+func (s *fuseSession) handleRequest(request, response []byte) int {
+	header, args, ok := decodeInput[fuse.InHeader](request)
+	if !ok || len(response) < outHeaderSize {
+		return 0
+	}
+	headerSize := len(request) - len(args)
+	if length := int(header.Length); length >= headerSize && length < len(request) {
+		request = request[:length]
+		args = request[headerSize:]
+	}
+
+	// Requests that don't have a response.
+	switch header.Opcode {
+	case opForget:
+		if in, _, ok := decodeInput[fuse.ForgetIn](request); ok {
+			s.forget(header.NodeId, in.Nlookup)
+		}
+		return 0
+	case opBatchForget:
+		if in, entries, ok := decodeInput[batchForgetIn](request); ok {
+			for i := uint32(0); i < in.Count; i++ {
+				entry, remainder, ok := decodeInput[forgetOne](entries)
+				if !ok {
+					break
+				}
+				s.forget(entry.NodeID, entry.Nlookup)
+				entries = remainder
+			}
+		}
+		return 0
+	case opInterrupt:
+		if in, _, ok := decodeInput[fuse.InterruptIn](request); ok {
+			s.interrupt(in.Unique)
+		}
+		return 0
+	}
+
+	cancel := make(chan struct{})
+	s.lock.Lock()
+	s.inFlight[header.Unique] = cancel
+	s.lock.Unlock()
+
+	length, st := s.dispatch(cancel, header, request, args, response[outHeaderSize:])
+
+	s.lock.Lock()
+	delete(s.inFlight, header.Unique)
+	s.lock.Unlock()
+
+	if st != fuse.OK {
+		length = 0
+	}
+	outHeader := fuse.OutHeader{
+		Length: uint32(outHeaderSize + length),
+		Status: -int32(st),
+		Unique: header.Unique,
+	}
+	encodeOutput(response, &outHeader)
+	return outHeaderSize + length
+}
+
+func (s *fuseSession) dispatch(cancel <-chan struct{}, header *fuse.InHeader, request, args, out []byte) (int, fuse.Status) {
+	rfs := s.rawFileSystem
+	switch header.Opcode {
+	case opInit:
+		in, _, ok := decodeInput[fuse.InitIn](request)
+		if !ok || in.Major != fuseKernelVersion || in.Minor < fuseKernelMinorVersion {
+			return 0, fuse.EIO
+		}
+		return encodeOutput(out, &fuse.InitOut{
+			Major:               fuseKernelVersion,
+			Minor:               fuseKernelMinorVersion,
+			MaxReadAhead:        in.MaxReadAhead,
+			Flags:               in.Flags & supportedCapabilities,
+			MaxBackground:       12,
+			CongestionThreshold: 9,
+			MaxWrite:            maximumWriteSizeBytes,
+			TimeGran:            1,
+			MaxPages:            maximumWriteSizeBytes / 4096,
+		})
+	case opDestroy:
+		s.forgetAll()
+		return 0, fuse.OK
+
+	case opLookup:
+		names, ok := parseNames(args, 1)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		var entryOut fuse.EntryOut
+		if st := rfs.Lookup(cancel, header, names[0], &entryOut); st != fuse.OK {
+			return 0, st
+		}
+		s.incrementLookupCount(entryOut.NodeId)
+		return encodeOutput(out, &entryOut)
+	case opGetattr:
+		in, _, ok := decodeInput[fuse.GetAttrIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		var attrOut fuse.AttrOut
+		if st := rfs.GetAttr(cancel, in, &attrOut); st != fuse.OK {
+			return 0, st
+		}
+		return encodeOutput(out, &attrOut)
+	case opSetattr:
+		in, _, ok := decodeInput[fuse.SetAttrIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		var attrOut fuse.AttrOut
+		if st := rfs.SetAttr(cancel, in, &attrOut); st != fuse.OK {
+			return 0, st
+		}
+		return encodeOutput(out, &attrOut)
+	case opReadlink:
+		target, st := rfs.Readlink(cancel, header)
+		if st != fuse.OK {
+			return 0, st
+		}
+		if len(target) > len(out) {
+			return 0, fuse.EINVAL
+		}
+		return copy(out, target), fuse.OK
+	case opSymlink:
+		names, ok := parseNames(args, 2)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		var entryOut fuse.EntryOut
+		if st := rfs.Symlink(cancel, header, names[1], names[0], &entryOut); st != fuse.OK {
+			return 0, st
+		}
+		s.incrementLookupCount(entryOut.NodeId)
+		return encodeOutput(out, &entryOut)
+	case opMknod:
+		in, remainder, ok := decodeInput[fuse.MknodIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		names, ok := parseNames(remainder, 1)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		var entryOut fuse.EntryOut
+		if st := rfs.Mknod(cancel, in, names[0], &entryOut); st != fuse.OK {
+			return 0, st
+		}
+		s.incrementLookupCount(entryOut.NodeId)
+		return encodeOutput(out, &entryOut)
+	case opMkdir:
+		in, remainder, ok := decodeInput[fuse.MkdirIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		names, ok := parseNames(remainder, 1)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		var entryOut fuse.EntryOut
+		if st := rfs.Mkdir(cancel, in, names[0], &entryOut); st != fuse.OK {
+			return 0, st
+		}
+		s.incrementLookupCount(entryOut.NodeId)
+		return encodeOutput(out, &entryOut)
+	case opUnlink:
+		names, ok := parseNames(args, 1)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		return 0, rfs.Unlink(cancel, header, names[0])
+	case opRmdir:
+		names, ok := parseNames(args, 1)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		return 0, rfs.Rmdir(cancel, header, names[0])
+	case opRename:
+		in, remainder, ok := decodeInput[fuse.Rename1In](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		names, ok := parseNames(remainder, 2)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		return 0, rfs.Rename(cancel, &fuse.RenameIn{
+			InHeader: in.InHeader,
+			Newdir:   in.Newdir,
+		}, names[0], names[1])
+	case opRename2:
+		in, remainder, ok := decodeInput[fuse.RenameIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		names, ok := parseNames(remainder, 2)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		return 0, rfs.Rename(cancel, in, names[0], names[1])
+	case opLink:
+		in, remainder, ok := decodeInput[fuse.LinkIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		names, ok := parseNames(remainder, 1)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		var entryOut fuse.EntryOut
+		if st := rfs.Link(cancel, in, names[0], &entryOut); st != fuse.OK {
+			return 0, st
+		}
+		s.incrementLookupCount(entryOut.NodeId)
+		return encodeOutput(out, &entryOut)
+	case opOpen, opOpendir:
+		in, _, ok := decodeInput[fuse.OpenIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		var openOut fuse.OpenOut
+		var st fuse.Status
+		if header.Opcode == opOpen {
+			st = rfs.Open(cancel, in, &openOut)
+		} else {
+			st = rfs.OpenDir(cancel, in, &openOut)
+		}
+		if st != fuse.OK {
+			return 0, st
+		}
+		return encodeOutput(out, &openOut)
+	case opCreate:
+		in, remainder, ok := decodeInput[fuse.CreateIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		names, ok := parseNames(remainder, 1)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		var createOut fuse.CreateOut
+		if st := rfs.Create(cancel, in, names[0], &createOut); st != fuse.OK {
+			return 0, st
+		}
+		s.incrementLookupCount(createOut.NodeId)
+		return encodeOutput(out, &createOut)
+	case opRead:
+		in, _, ok := decodeInput[fuse.ReadIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		buf := out
+		if int(in.Size) < len(buf) {
+			buf = buf[:in.Size]
+		}
+		readResult, st := rfs.Read(cancel, in, buf)
+		if st != fuse.OK {
+			return 0, st
+		}
+		data, st := readResult.Bytes(buf)
+		n := copy(buf, data)
+		readResult.Done()
+		return n, st
+	case opWrite:
+		in, data, ok := decodeInput[fuse.WriteIn](request)
+		if !ok || len(data) < int(in.Size) {
+			return 0, fuse.EINVAL
+		}
+		written, st := rfs.Write(cancel, in, data[:in.Size])
+		if st != fuse.OK {
+			return 0, st
+		}
+		return encodeOutput(out, &fuse.WriteOut{Size: written})
+	case opStatfs:
+		var statfsOut fuse.StatfsOut
+		if st := rfs.StatFs(cancel, header, &statfsOut); st != fuse.OK {
+			return 0, st
+		}
+		return encodeOutput(out, &statfsOut)
+	case opRelease, opReleasedir:
+		in, _, ok := decodeInput[fuse.ReleaseIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		if header.Opcode == opRelease {
+			rfs.Release(cancel, in)
+		} else {
+			rfs.ReleaseDir(in)
+		}
+		return 0, fuse.OK
+	case opFsync, opFsyncdir:
+		in, _, ok := decodeInput[fuse.FsyncIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		if header.Opcode == opFsync {
+			return 0, rfs.Fsync(cancel, in)
+		}
+		return 0, rfs.FsyncDir(cancel, in)
+	case opFlush:
+		in, _, ok := decodeInput[fuse.FlushIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		return 0, rfs.Flush(cancel, in)
+	case opReaddir, opReaddirplus:
+		in, _, ok := decodeInput[fuse.ReadIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		buf := out
+		if int(in.Size) < len(buf) {
+			buf = buf[:in.Size]
+		}
+		l := newDirEntryList(buf, header.Opcode == opReaddirplus)
+		if l.plus {
+			if st := rfs.ReadDirPlus(cancel, in, l); st != fuse.OK {
+				return 0, st
+			}
+			for _, entryOut := range l.entries {
+				s.incrementLookupCount(entryOut.NodeId)
+			}
+		} else if st := rfs.ReadDir(cancel, in, l); st != fuse.OK {
+			return 0, st
+		}
+		return l.size, fuse.OK
+	case opAccess:
+		in, _, ok := decodeInput[fuse.AccessIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		return 0, rfs.Access(cancel, in)
+	case opFallocate:
+		in, _, ok := decodeInput[fuse.FallocateIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		return 0, rfs.Fallocate(cancel, in)
+	case opLseek:
+		in, _, ok := decodeInput[fuse.LseekIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		var lseekOut fuse.LseekOut
+		if st := rfs.Lseek(cancel, in, &lseekOut); st != fuse.OK {
+			return 0, st
+		}
+		return encodeOutput(out, &lseekOut)
+	case opCopyFileRange:
+		in, _, ok := decodeInput[fuse.CopyFileRangeIn](request)
+		if !ok {
+			return 0, fuse.EINVAL
+		}
+		written, st := rfs.CopyFileRange(cancel, in)
+		if st != fuse.OK {
+			return 0, st
+		}
+		return encodeOutput(out, &fuse.WriteOut{Size: written})
+	default:
+		// Extended attributes, file locking and ioctl() are
+		// not supported.
+		return 0, fuse.ENOSYS
+	}
+}
+
+// encodeNotification encodes an invalidation as a FUSE notification
+// message, so that it can be sent to the guest over the notification
+// queue.
+func encodeNotification(i *invalidation) []byte {
+	var body []byte
+	switch i.code {
+	case notifyInvalInode:
+		body = make([]byte, unsafe.Sizeof(fuse.NotifyInvalInodeOut{}))
+		encodeOutput(body, &fuse.NotifyInvalInodeOut{
+			Ino:    i.node,
+			Off:    i.offset,
+			Length: i.length,
+		})
+	case notifyInvalEntry:
+		body = make([]byte, unsafe.Sizeof(fuse.NotifyInvalEntryOut{}))
+		encodeOutput(body, &fuse.NotifyInvalEntryOut{
+			Parent:  i.node,
+			NameLen: uint32(len(i.name)),
+		})
+		body = append(append(body, i.name...), 0)
+	case notifyDelete:
+		body = make([]byte, unsafe.Sizeof(fuse.NotifyInvalDeleteOut{}))
+		encodeOutput(body, &fuse.NotifyInvalDeleteOut{
+			Parent:  i.node,
+			Child:   i.child,
+			NameLen: uint32(len(i.name)),
+		})
+		body = append(append(body, i.name...), 0)
+	}
+
+	message := make([]byte, outHeaderSize, outHeaderSize+len(body))
+	encodeOutput(message, &fuse.OutHeader{
+		Length: uint32(outHeaderSize + len(body)),
+		Status: int32(i.code),
+	})
+	return append(message, body...)
+}
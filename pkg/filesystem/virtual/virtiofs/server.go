@@ -0,0 +1,689 @@
+//go:build linux
+// +build linux
+
+package virtiofs
+
+import (
+	"encoding/binary"
+	"io"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/hanwen/go-fuse/v2/fuse"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Types of messages that may be sent by the vhost-user frontend.
+const (
+	vhostUserGetFeatures         = 1
+	vhostUserSetFeatures         = 2
+	vhostUserSetOwner            = 3
+	vhostUserResetOwner          = 4
+	vhostUserSetMemTable         = 5
+	vhostUserSetVringNum         = 8
+	vhostUserSetVringAddr        = 9
+	vhostUserSetVringBase        = 10
+	vhostUserGetVringBase        = 11
+	vhostUserSetVringKick        = 12
+	vhostUserSetVringCall        = 13
+	vhostUserSetVringErr         = 14
+	vhostUserGetProtocolFeatures = 15
+	vhostUserSetProtocolFeatures = 16
+	vhostUserGetQueueNum         = 17
+	vhostUserSetVringEnable      = 18
+)
+
+const (
+	vhostUserHeaderSize         = 12
+	vhostUserMaximumPayloadSize = 4096
+	vhostUserMaximumFiles       = 8
+
+	vhostUserFlagVersion   = 0x1
+	vhostUserFlagReply     = 0x4
+	vhostUserFlagNeedReply = 0x8
+
+	// Payloads of SET_VRING_KICK, SET_VRING_CALL and SET_VRING_ERR
+	// store the index of the virtqueue in the lower bits. A separate
+	// bit indicates that no file descriptor is provided.
+	vringIndexMask        = 0xff
+	vringNoFileDescriptor = 0x100
+
+	maximumVirtqueueSize = 32768
+)
+
+// Device feature bits.
+const (
+	featureVirtioFSNotification  = 1 << 0
+	featureRingIndirectDesc      = 1 << 28
+	featureVhostUserProtocol     = 1 << 30
+	featureVersion1              = 1 << 32
+	supportedFeatures            = featureVirtioFSNotification | featureRingIndirectDesc | featureVhostUserProtocol | featureVersion1
+	protocolFeatureMultipleQueue = 1 << 0
+	protocolFeatureReplyAck      = 1 << 3
+	supportedProtocolFeatures    = protocolFeatureMultipleQueue | protocolFeatureReplyAck
+)
+
+// Server of the virtio-fs protocol, implemented as a vhost-user backend.
+// Virtual machine monitors such as QEMU and Cloud Hypervisor can
+// connect to it over a UNIX socket, and expose the virtual file system
+// to the guest through a vhost-user-fs device. This permits mounting
+// the input root of an action inside a virtual machine, without
+// needing to copy it into a disk image.
+//
+// Every connection corresponds to a separate FUSE session. Nodes that
+// are still referenced by a guest are released when its virtual machine
+// disconnects.
+type Server struct {
+	rawFileSystem        fuse.RawFileSystem
+	versionTable         *VersionTable
+	maximumRequestQueues uint32
+}
+
+// NewServer creates a virtio-fs server that forwards FUSE requests
+// sent by guests to a RawFileSystem. Invalidations reported by the
+// RawFileSystem are recorded in a VersionTable, from which they are
+// forwarded to guests that support the notification queue.
+//
+// The Linux virtio-fs driver does not support the notification queue
+// at the time of writing. Guests are thus only able to observe changes
+// made outside of the guest after cached directory entries and inode
+// attributes expire.
+func NewServer(rawFileSystem fuse.RawFileSystem, versionTable *VersionTable, maximumRequestQueues uint32) *Server {
+	rawFileSystem.Init(versionTable)
+	return &Server{
+		rawFileSystem:        rawFileSystem,
+		versionTable:         versionTable,
+		maximumRequestQueues: maximumRequestQueues,
+	}
+}
+
+// HandleConnection processes vhost-user messages sent by a virtual
+// machine monitor until it disconnects.
+func (s *Server) HandleConnection(conn *net.UnixConn) error {
+	c := &connection{
+		server:              s,
+		conn:                conn,
+		session:             newFUSESession(s.rawFileSystem),
+		vrings:              make([]vring, 2+s.maximumRequestQueues),
+		notificationVersion: s.versionTable.getCurrentVersion(),
+	}
+	defer c.close()
+
+	for {
+		if err := c.processMessage(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// vring contains the state of a single virtqueue, as configured by the
+// vhost-user frontend.
+type vring struct {
+	size               uint16
+	descriptorsAddress uint64
+	availableAddress   uint64
+	usedAddress        uint64
+	hasAddresses       bool
+	baseIndex          uint16
+	kick               *os.File
+	call               *os.File
+	enabled            atomic.Bool
+	running            *runningVring
+}
+
+// runningVring contains the state of a virtqueue that is being
+// processed.
+type runningVring struct {
+	queue *virtqueue
+	kicks chan struct{}
+	stop  chan struct{}
+	wait  sync.WaitGroup
+
+	lock sync.Mutex
+	call *os.File
+}
+
+// complete the processing of a descriptor chain, notifying the driver
+// if needed.
+func (r *runningVring) complete(head uint16, length uint32) {
+	r.lock.Lock()
+	notify := r.queue.push(head, length)
+	call := r.call
+	r.lock.Unlock()
+
+	if notify && call != nil {
+		var value [8]byte
+		binary.LittleEndian.PutUint64(value[:], 1)
+		call.Write(value[:])
+	}
+}
+
+// waitForKick blocks until the driver adds buffers to the virtqueue,
+// or until processing of the virtqueue is stopped.
+func (r *runningVring) waitForKick() bool {
+	select {
+	case <-r.kicks:
+		return true
+	case <-r.stop:
+		return false
+	}
+}
+
+type connection struct {
+	server  *Server
+	conn    *net.UnixConn
+	session *fuseSession
+
+	features         uint64
+	protocolFeatures uint64
+	memory           guestMemory
+	mappings         [][]byte
+	vrings           []vring
+
+	// The version of the VersionTable up to which invalidations have
+	// been sent to the guest. Only accessed by the goroutine that
+	// processes the notification queue.
+	notificationVersion uint64
+}
+
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
+
+func (c *connection) readMessage() (uint32, uint32, []byte, []*os.File, error) {
+	var header [vhostUserHeaderSize]byte
+	oob := make([]byte, unix.CmsgSpace(4*vhostUserMaximumFiles))
+	n, oobn, _, _, err := c.conn.ReadMsgUnix(header[:], oob)
+	if err != nil {
+		return 0, 0, nil, nil, err
+	}
+	if n == 0 {
+		return 0, 0, nil, nil, io.EOF
+	}
+
+	// Extract file descriptors that were sent along with the message.
+	var files []*os.File
+	controlMessages, err := unix.ParseSocketControlMessage(oob[:oobn])
+	if err != nil {
+		return 0, 0, nil, nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to parse socket control message")
+	}
+	for _, controlMessage := range controlMessages {
+		fds, err := unix.ParseUnixRights(&controlMessage)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			files = append(files, os.NewFile(uintptr(fd), "vhost-user"))
+		}
+	}
+
+	if _, err := io.ReadFull(c.conn, header[n:]); err != nil {
+		closeFiles(files)
+		return 0, 0, nil, nil, util.StatusWrap(err, "Failed to read message header")
+	}
+	request := binary.LittleEndian.Uint32(header[0:])
+	flags := binary.LittleEndian.Uint32(header[4:])
+	size := binary.LittleEndian.Uint32(header[8:])
+	if size > vhostUserMaximumPayloadSize {
+		closeFiles(files)
+		return 0, 0, nil, nil, status.Errorf(codes.InvalidArgument, "Message has payload size %d, while at most %d bytes are supported", size, vhostUserMaximumPayloadSize)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(c.conn, payload); err != nil {
+		closeFiles(files)
+		return 0, 0, nil, nil, util.StatusWrap(err, "Failed to read message payload")
+	}
+	return request, flags, payload, files, nil
+}
+
+func (c *connection) writeReply(request uint32, payload []byte) error {
+	message := make([]byte, vhostUserHeaderSize, vhostUserHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(message[0:], request)
+	binary.LittleEndian.PutUint32(message[4:], vhostUserFlagVersion|vhostUserFlagReply)
+	binary.LittleEndian.PutUint32(message[8:], uint32(len(payload)))
+	if _, err := c.conn.Write(append(message, payload...)); err != nil {
+		return util.StatusWrap(err, "Failed to write reply")
+	}
+	return nil
+}
+
+func (c *connection) writeUint64Reply(request uint32, value uint64) error {
+	var payload [8]byte
+	binary.LittleEndian.PutUint64(payload[:], value)
+	return c.writeReply(request, payload[:])
+}
+
+func (c *connection) processMessage() error {
+	request, flags, payload, files, err := c.readMessage()
+	if err != nil {
+		return err
+	}
+	hasReply, err := c.handleMessage(request, payload, files)
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to process message of type %d", request)
+	}
+	if !hasReply && flags&vhostUserFlagNeedReply != 0 && c.protocolFeatures&protocolFeatureReplyAck != 0 {
+		return c.writeUint64Reply(request, 0)
+	}
+	return nil
+}
+
+func parseUint64(payload []byte) (uint64, error) {
+	if len(payload) < 8 {
+		return 0, status.Error(codes.InvalidArgument, "Payload too small")
+	}
+	return binary.LittleEndian.Uint64(payload), nil
+}
+
+func (c *connection) getVring(index uint32) (*vring, error) {
+	if index >= uint32(len(c.vrings)) {
+		return nil, status.Errorf(codes.InvalidArgument, "Virtqueue index %d exceeds the number of virtqueues %d", index, len(c.vrings))
+	}
+	return &c.vrings[index], nil
+}
+
+// parseVringState parses a struct vhost_vring_state.
+func (c *connection) parseVringState(payload []byte) (uint32, *vring, uint32, error) {
+	if len(payload) < 8 {
+		return 0, nil, 0, status.Error(codes.InvalidArgument, "Payload too small")
+	}
+	index := binary.LittleEndian.Uint32(payload[0:])
+	v, err := c.getVring(index)
+	return index, v, binary.LittleEndian.Uint32(payload[4:]), err
+}
+
+// parseVringFile parses the payload of SET_VRING_KICK, SET_VRING_CALL
+// and SET_VRING_ERR, and extracts the file descriptor.
+func (c *connection) parseVringFile(payload []byte, files []*os.File) (uint32, *vring, *os.File, error) {
+	value, err := parseUint64(payload)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	index := uint32(value & vringIndexMask)
+	v, err := c.getVring(index)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if value&vringNoFileDescriptor != 0 {
+		closeFiles(files)
+		return index, v, nil, nil
+	}
+	if len(files) != 1 {
+		closeFiles(files)
+		return 0, nil, nil, status.Errorf(codes.InvalidArgument, "Expected 1 file descriptor, while %d were provided", len(files))
+	}
+	return index, v, files[0], nil
+}
+
+func (c *connection) handleMessage(request uint32, payload []byte, files []*os.File) (bool, error) {
+	switch request {
+	case vhostUserSetMemTable:
+		return false, c.setMemTable(payload, files)
+	case vhostUserSetVringKick, vhostUserSetVringCall, vhostUserSetVringErr:
+		// Handled below.
+	default:
+		// No other messages carry file descriptors.
+		closeFiles(files)
+	}
+
+	switch request {
+	case vhostUserGetFeatures:
+		return true, c.writeUint64Reply(request, supportedFeatures)
+	case vhostUserSetFeatures:
+		features, err := parseUint64(payload)
+		if err != nil {
+			return false, err
+		}
+		c.features = features & supportedFeatures
+		return false, nil
+	case vhostUserSetOwner:
+		return false, nil
+	case vhostUserResetOwner:
+		c.stopAllVrings()
+		return false, nil
+	case vhostUserSetVringNum:
+		_, v, size, err := c.parseVringState(payload)
+		if err != nil {
+			return false, err
+		}
+		if size == 0 || size > maximumVirtqueueSize {
+			return false, status.Errorf(codes.InvalidArgument, "Invalid virtqueue size %d", size)
+		}
+		v.size = uint16(size)
+		return false, nil
+	case vhostUserSetVringAddr:
+		// struct vhost_vring_addr.
+		if len(payload) < 40 {
+			return false, status.Error(codes.InvalidArgument, "Payload too small")
+		}
+		v, err := c.getVring(binary.LittleEndian.Uint32(payload[0:]))
+		if err != nil {
+			return false, err
+		}
+		v.descriptorsAddress = binary.LittleEndian.Uint64(payload[8:])
+		v.usedAddress = binary.LittleEndian.Uint64(payload[16:])
+		v.availableAddress = binary.LittleEndian.Uint64(payload[24:])
+		v.hasAddresses = true
+		return false, nil
+	case vhostUserSetVringBase:
+		_, v, base, err := c.parseVringState(payload)
+		if err != nil {
+			return false, err
+		}
+		v.baseIndex = uint16(base)
+		return false, nil
+	case vhostUserGetVringBase:
+		index, v, _, err := c.parseVringState(payload)
+		if err != nil {
+			return false, err
+		}
+		c.stopVring(v)
+		if v.kick != nil {
+			v.kick.Close()
+			v.kick = nil
+		}
+		var reply [8]byte
+		binary.LittleEndian.PutUint32(reply[0:], index)
+		binary.LittleEndian.PutUint32(reply[4:], uint32(v.baseIndex))
+		return true, c.writeReply(request, reply[:])
+	case vhostUserSetVringKick:
+		index, v, file, err := c.parseVringFile(payload, files)
+		if err != nil {
+			return false, err
+		}
+		c.stopVring(v)
+		if v.kick != nil {
+			v.kick.Close()
+			v.kick = nil
+		}
+		if file != nil {
+			// Make the file descriptor non-blocking, so
+			// that reads can be interrupted.
+			fd, err := unix.Dup(int(file.Fd()))
+			file.Close()
+			if err != nil {
+				return false, util.StatusWrap(err, "Failed to duplicate kick file descriptor")
+			}
+			if err := unix.SetNonblock(fd, true); err != nil {
+				unix.Close(fd)
+				return false, util.StatusWrap(err, "Failed to make kick file descriptor non-blocking")
+			}
+			v.kick = os.NewFile(uintptr(fd), "kick")
+			return false, c.startVring(index, v)
+		}
+		return false, nil
+	case vhostUserSetVringCall:
+		_, v, file, err := c.parseVringFile(payload, files)
+		if err != nil {
+			return false, err
+		}
+		if r := v.running; r != nil {
+			r.lock.Lock()
+			r.call = file
+			r.lock.Unlock()
+		}
+		if v.call != nil {
+			v.call.Close()
+		}
+		v.call = file
+		return false, nil
+	case vhostUserSetVringErr:
+		// Errors are never reported to the frontend.
+		_, _, file, err := c.parseVringFile(payload, files)
+		if file != nil {
+			file.Close()
+		}
+		return false, err
+	case vhostUserGetProtocolFeatures:
+		return true, c.writeUint64Reply(request, supportedProtocolFeatures)
+	case vhostUserSetProtocolFeatures:
+		protocolFeatures, err := parseUint64(payload)
+		if err != nil {
+			return false, err
+		}
+		c.protocolFeatures = protocolFeatures & supportedProtocolFeatures
+		return false, nil
+	case vhostUserGetQueueNum:
+		return true, c.writeUint64Reply(request, uint64(len(c.vrings)))
+	case vhostUserSetVringEnable:
+		_, v, enable, err := c.parseVringState(payload)
+		if err != nil {
+			return false, err
+		}
+		v.enabled.Store(enable != 0)
+		if r := v.running; r != nil {
+			select {
+			case r.kicks <- struct{}{}:
+			default:
+			}
+		}
+		return false, nil
+	default:
+		return false, status.Error(codes.Unimplemented, "Message type not supported")
+	}
+}
+
+func (c *connection) setMemTable(payload []byte, files []*os.File) error {
+	defer closeFiles(files)
+
+	// struct vhost_user_memory.
+	if len(payload) < 8 {
+		return status.Error(codes.InvalidArgument, "Payload too small")
+	}
+	regionsCount := int(binary.LittleEndian.Uint32(payload[0:]))
+	if len(payload) < 8+32*regionsCount || len(files) != regionsCount {
+		return status.Errorf(codes.InvalidArgument, "Memory table has %d regions, while %d file descriptors were provided", regionsCount, len(files))
+	}
+
+	memory := make(guestMemory, 0, regionsCount)
+	mappings := make([][]byte, 0, regionsCount)
+	for i := 0; i < regionsCount; i++ {
+		region := payload[8+32*i:]
+		size := binary.LittleEndian.Uint64(region[8:])
+		mmapOffset := binary.LittleEndian.Uint64(region[24:])
+		data, err := unix.Mmap(int(files[i].Fd()), 0, int(size+mmapOffset), unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED)
+		if err != nil {
+			for _, mapping := range mappings {
+				unix.Munmap(mapping)
+			}
+			return util.StatusWrapf(err, "Failed to map memory region %d", i)
+		}
+		mappings = append(mappings, data)
+		memory = append(memory, memoryRegion{
+			guestAddress: binary.LittleEndian.Uint64(region[0:]),
+			userAddress:  binary.LittleEndian.Uint64(region[16:]),
+			data:         data[mmapOffset:],
+		})
+	}
+
+	// Virtqueues refer to the old memory regions. Temporarily stop
+	// processing them, so that the old memory regions can be
+	// released.
+	var running []int
+	for i := range c.vrings {
+		if v := &c.vrings[i]; v.running != nil {
+			c.stopVring(v)
+			running = append(running, i)
+		}
+	}
+	c.unmapMemory()
+	c.memory = memory
+	c.mappings = mappings
+	for _, i := range running {
+		if err := c.startVring(uint32(i), &c.vrings[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *connection) unmapMemory() {
+	for _, mapping := range c.mappings {
+		unix.Munmap(mapping)
+	}
+	c.memory = nil
+	c.mappings = nil
+}
+
+func (c *connection) startVring(index uint32, v *vring) error {
+	if !v.hasAddresses || v.size == 0 {
+		return status.Errorf(codes.FailedPrecondition, "Virtqueue %d has not been configured", index)
+	}
+	queue, err := newVirtqueue(c.memory, v.size, v.descriptorsAddress, v.availableAddress, v.usedAddress, v.baseIndex)
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to create virtqueue %d", index)
+	}
+	// If VHOST_USER_F_PROTOCOL_FEATURES has not been negotiated,
+	// virtqueues are enabled implicitly.
+	if c.features&featureVhostUserProtocol == 0 {
+		v.enabled.Store(true)
+	}
+
+	r := &runningVring{
+		queue: queue,
+		kicks: make(chan struct{}, 1),
+		stop:  make(chan struct{}),
+		call:  v.call,
+	}
+	v.running = r
+
+	// Convert reads from the kick event file descriptor to
+	// notifications on a channel.
+	kick := v.kick
+	kick.SetReadDeadline(time.Time{})
+	r.wait.Add(2)
+	go func() {
+		defer r.wait.Done()
+		var value [8]byte
+		for {
+			if _, err := kick.Read(value[:]); err != nil {
+				return
+			}
+			select {
+			case r.kicks <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	// The first virtqueue is the high priority queue, followed by
+	// the notification queue if negotiated. All other virtqueues are
+	// request queues.
+	if index == 1 && c.features&featureVirtioFSNotification != 0 {
+		go func() {
+			defer r.wait.Done()
+			c.processNotifications(v, r)
+		}()
+	} else {
+		go func() {
+			defer r.wait.Done()
+			c.processRequests(index, v, r)
+		}()
+	}
+	r.kicks <- struct{}{}
+	return nil
+}
+
+func (c *connection) stopVring(v *vring) {
+	r := v.running
+	if r == nil {
+		return
+	}
+	close(r.stop)
+	v.kick.SetReadDeadline(time.Unix(0, 0))
+	r.wait.Wait()
+	v.baseIndex = r.queue.lastAvailableIndex
+	v.running = nil
+}
+
+func (c *connection) stopAllVrings() {
+	for i := range c.vrings {
+		c.stopVring(&c.vrings[i])
+	}
+}
+
+func (c *connection) processRequests(index uint32, v *vring, r *runningVring) {
+	for r.waitForKick() {
+		for v.enabled.Load() {
+			chain, ok, err := r.queue.pop()
+			if err != nil {
+				log.Printf("Failed to process virtqueue %d: %s", index, err)
+				return
+			}
+			if !ok {
+				break
+			}
+			r.wait.Add(1)
+			go func() {
+				defer r.wait.Done()
+				request := chain.gatherReadable()
+				response := make([]byte, totalSize(chain.writable))
+				length := c.session.handleRequest(request, response)
+				chain.scatterWritable(response[:length])
+				r.complete(chain.head, uint32(length))
+			}()
+		}
+	}
+}
+
+func (c *connection) processNotifications(v *vring, r *runningVring) {
+	for {
+		invalidations, nextVersion, wakeup := c.server.versionTable.getInvalidations(c.notificationVersion)
+		for i := range invalidations {
+			message := encodeNotification(&invalidations[i])
+			for {
+				if v.enabled.Load() {
+					chain, ok, err := r.queue.pop()
+					if err != nil {
+						log.Print("Failed to process notification virtqueue: ", err)
+						return
+					}
+					if ok {
+						chain.scatterWritable(message)
+						r.complete(chain.head, uint32(min(len(message), totalSize(chain.writable))))
+						break
+					}
+				}
+				if !r.waitForKick() {
+					return
+				}
+			}
+			c.notificationVersion = nextVersion - uint64(len(invalidations)-i-1)
+		}
+		c.notificationVersion = nextVersion
+
+		select {
+		case <-wakeup:
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+func (c *connection) close() {
+	c.stopAllVrings()
+	c.session.forgetAll()
+	c.unmapMemory()
+	for i := range c.vrings {
+		v := &c.vrings[i]
+		if v.kick != nil {
+			v.kick.Close()
+		}
+		if v.call != nil {
+			v.call.Close()
+		}
+	}
+}
@@ -0,0 +1,314 @@
+//go:build linux
+// +build linux
+
+package virtiofs_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual/virtiofs"
+	"github.com/golang/mock/gomock"
+	"github.com/hanwen/go-fuse/v2/fuse"
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	guestMemorySize    = 1 << 20
+	guestUserAddress   = 0x10000000
+	descriptorsAddress = 0x1000
+	availableAddress   = 0x2000
+	usedAddress        = 0x3000
+	requestAddress     = 0x10000
+	responseAddress    = 0x20000
+)
+
+// frontend is a minimal vhost-user frontend that is used to exercise
+// the virtio-fs server, playing the role of the virtual machine
+// monitor and the guest's virtio-fs driver.
+type frontend struct {
+	t      *testing.T
+	conn   *os.File
+	memory []byte
+	kickFD int
+	callFD int
+	unique uint64
+}
+
+func (f *frontend) sendMessage(request uint32, payload []byte, fds ...int) {
+	message := make([]byte, 12, 12+len(payload))
+	binary.LittleEndian.PutUint32(message[0:], request)
+	binary.LittleEndian.PutUint32(message[4:], 0x1)
+	binary.LittleEndian.PutUint32(message[8:], uint32(len(payload)))
+	message = append(message, payload...)
+	var oob []byte
+	if len(fds) > 0 {
+		oob = unix.UnixRights(fds...)
+	}
+	require.NoError(f.t, unix.Sendmsg(int(f.conn.Fd()), message, oob, nil, 0))
+}
+
+func (f *frontend) readReply(request uint32) []byte {
+	var header [12]byte
+	_, err := f.conn.Read(header[:])
+	require.NoError(f.t, err)
+	require.Equal(f.t, request, binary.LittleEndian.Uint32(header[0:]))
+	require.Equal(f.t, uint32(0x5), binary.LittleEndian.Uint32(header[4:]))
+	payload := make([]byte, binary.LittleEndian.Uint32(header[8:]))
+	_, err = f.conn.Read(payload)
+	require.NoError(f.t, err)
+	return payload
+}
+
+func encodeUint64(v uint64) []byte {
+	return binary.LittleEndian.AppendUint64(nil, v)
+}
+
+func encodeVringState(index, num uint32) []byte {
+	return binary.LittleEndian.AppendUint32(binary.LittleEndian.AppendUint32(nil, index), num)
+}
+
+// request sends a FUSE request to the server over the first request
+// queue and waits for the response.
+func (f *frontend) request(opcode uint32, nodeID uint64, in any, out any) int32 {
+	f.unique++
+	var request bytes.Buffer
+	if in != nil {
+		require.NoError(f.t, binary.Write(&request, binary.LittleEndian, in))
+	}
+	header := fuse.InHeader{
+		Length: uint32(40 + request.Len()),
+		Opcode: opcode,
+		Unique: f.unique,
+		NodeId: nodeID,
+	}
+	var message bytes.Buffer
+	require.NoError(f.t, binary.Write(&message, binary.LittleEndian, &header))
+	message.Write(request.Bytes())
+	copy(f.memory[requestAddress:], message.Bytes())
+
+	// Create a descriptor chain consisting of a readable and a
+	// writable buffer, and make it available.
+	descriptors := f.memory[descriptorsAddress:]
+	binary.LittleEndian.PutUint64(descriptors[0:], requestAddress)
+	binary.LittleEndian.PutUint32(descriptors[8:], uint32(message.Len()))
+	binary.LittleEndian.PutUint16(descriptors[12:], 1)
+	binary.LittleEndian.PutUint16(descriptors[14:], 1)
+	binary.LittleEndian.PutUint64(descriptors[16:], responseAddress)
+	binary.LittleEndian.PutUint32(descriptors[24:], 4096)
+	binary.LittleEndian.PutUint16(descriptors[28:], 2)
+
+	available := f.memory[availableAddress:]
+	availableIndex := binary.LittleEndian.Uint16(available[2:])
+	binary.LittleEndian.PutUint16(available[4+2*(availableIndex%8):], 0)
+	binary.LittleEndian.PutUint16(available[2:], availableIndex+1)
+	_, err := unix.Write(f.kickFD, encodeUint64(1))
+	require.NoError(f.t, err)
+
+	// Wait for the server to place the chain in the used ring.
+	var value [8]byte
+	_, err = unix.Read(f.callFD, value[:])
+	require.NoError(f.t, err)
+	used := f.memory[usedAddress:]
+	require.Equal(f.t, availableIndex+1, binary.LittleEndian.Uint16(used[2:]))
+	element := used[4+8*(availableIndex%8):]
+	require.Equal(f.t, uint32(0), binary.LittleEndian.Uint32(element[0:]))
+	length := binary.LittleEndian.Uint32(element[4:])
+
+	var outHeader fuse.OutHeader
+	response := bytes.NewReader(f.memory[responseAddress : responseAddress+length])
+	require.NoError(f.t, binary.Read(response, binary.LittleEndian, &outHeader))
+	require.Equal(f.t, length, outHeader.Length)
+	require.Equal(f.t, f.unique, outHeader.Unique)
+	if out != nil && outHeader.Status == 0 {
+		require.NoError(f.t, binary.Read(response, binary.LittleEndian, out))
+	}
+	return outHeader.Status
+}
+
+func TestServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	rawFileSystem := mock.NewMockRawFileSystem(ctrl)
+	rawFileSystem.EXPECT().Init(gomock.Any())
+	server := virtiofs.NewServer(rawFileSystem, virtiofs.NewVersionTable(100), 1)
+
+	// Connect a frontend to the server.
+	fds, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_STREAM, 0)
+	require.NoError(t, err)
+	backendFile := os.NewFile(uintptr(fds[1]), "backend")
+	backendConn, err := net.FileConn(backendFile)
+	require.NoError(t, err)
+	backendFile.Close()
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.HandleConnection(backendConn.(*net.UnixConn))
+	}()
+
+	memoryFD, err := unix.MemfdCreate("guest", 0)
+	require.NoError(t, err)
+	defer unix.Close(memoryFD)
+	require.NoError(t, unix.Ftruncate(memoryFD, guestMemorySize))
+	memory, err := unix.Mmap(memoryFD, 0, guestMemorySize, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED)
+	require.NoError(t, err)
+	defer unix.Munmap(memory)
+	kick, err := unix.Eventfd(0, 0)
+	require.NoError(t, err)
+	defer unix.Close(kick)
+	call, err := unix.Eventfd(0, 0)
+	require.NoError(t, err)
+	defer unix.Close(call)
+
+	f := &frontend{
+		t:      t,
+		conn:   os.NewFile(uintptr(fds[0]), "frontend"),
+		memory: memory,
+		kickFD: kick,
+		callFD: call,
+	}
+
+	// Negotiate features and set up the first request queue.
+	f.sendMessage(1, nil)
+	features := binary.LittleEndian.Uint64(f.readReply(1))
+	require.NotZero(t, features&(1<<32))
+	f.sendMessage(2, encodeUint64(1<<32))
+
+	f.sendMessage(17, nil)
+	require.Equal(t, uint64(3), binary.LittleEndian.Uint64(f.readReply(17)))
+
+	memoryTable := binary.LittleEndian.AppendUint64(nil, 1)
+	memoryTable = binary.LittleEndian.AppendUint64(memoryTable, 0)
+	memoryTable = binary.LittleEndian.AppendUint64(memoryTable, guestMemorySize)
+	memoryTable = binary.LittleEndian.AppendUint64(memoryTable, guestUserAddress)
+	memoryTable = binary.LittleEndian.AppendUint64(memoryTable, 0)
+	f.sendMessage(5, memoryTable, memoryFD)
+
+	f.sendMessage(8, encodeVringState(2, 8))
+	vringAddress := binary.LittleEndian.AppendUint64(encodeVringState(2, 0), guestUserAddress+descriptorsAddress)
+	vringAddress = binary.LittleEndian.AppendUint64(vringAddress, guestUserAddress+usedAddress)
+	vringAddress = binary.LittleEndian.AppendUint64(vringAddress, guestUserAddress+availableAddress)
+	vringAddress = binary.LittleEndian.AppendUint64(vringAddress, 0)
+	f.sendMessage(9, vringAddress)
+	f.sendMessage(10, encodeVringState(2, 0))
+	f.sendMessage(13, encodeUint64(2), call)
+	f.sendMessage(12, encodeUint64(2), kick)
+
+	t.Run("Init", func(t *testing.T) {
+		var initOut fuse.InitOut
+		require.Equal(t, int32(0), f.request(26, 0, &struct {
+			Major        uint32
+			Minor        uint32
+			MaxReadAhead uint32
+			Flags        uint32
+		}{
+			Major:        7,
+			Minor:        38,
+			MaxReadAhead: 131072,
+			Flags:        fuse.CAP_ASYNC_READ | fuse.CAP_READDIRPLUS | fuse.CAP_POSIX_LOCKS,
+		}, &initOut))
+		require.Equal(t, uint32(7), initOut.Major)
+		require.Equal(t, uint32(28), initOut.Minor)
+		require.Equal(t, uint32(fuse.CAP_ASYNC_READ|fuse.CAP_READDIRPLUS), initOut.Flags)
+	})
+
+	t.Run("InitTooOld", func(t *testing.T) {
+		require.Equal(t, -int32(fuse.EIO), f.request(26, 0, &struct {
+			Major        uint32
+			Minor        uint32
+			MaxReadAhead uint32
+			Flags        uint32
+		}{
+			Major: 7,
+			Minor: 12,
+		}, nil))
+	})
+
+	t.Run("LookupFailure", func(t *testing.T) {
+		rawFileSystem.EXPECT().Lookup(gomock.Any(), gomock.Any(), "nonexistent", gomock.Any()).Return(fuse.ENOENT)
+
+		require.Equal(t, -int32(fuse.ENOENT), f.request(1, 1, []byte("nonexistent\x00"), nil))
+	})
+
+	t.Run("LookupSuccess", func(t *testing.T) {
+		rawFileSystem.EXPECT().Lookup(gomock.Any(), gomock.Any(), "hello", gomock.Any()).DoAndReturn(
+			func(cancel <-chan struct{}, header *fuse.InHeader, name string, out *fuse.EntryOut) fuse.Status {
+				require.Equal(t, uint64(1), header.NodeId)
+				out.NodeId = 123
+				out.Mode = fuse.S_IFREG | 0o644
+				out.Size = 42
+				return fuse.OK
+			})
+
+		var entryOut fuse.EntryOut
+		require.Equal(t, int32(0), f.request(1, 1, []byte("hello\x00"), &entryOut))
+		require.Equal(t, uint64(123), entryOut.NodeId)
+		require.Equal(t, uint32(fuse.S_IFREG|0o644), entryOut.Mode)
+		require.Equal(t, uint64(42), entryOut.Size)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		// Extended attributes are not supported.
+		require.Equal(t, -int32(fuse.ENOSYS), f.request(23, 123, &fuse.GetXAttrOut{}, nil))
+	})
+
+	t.Run("ReadDirPlus", func(t *testing.T) {
+		rawFileSystem.EXPECT().ReadDirPlus(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(cancel <-chan struct{}, input *fuse.ReadIn, out fuse.ReadDirPlusEntryList) fuse.Status {
+				require.Equal(t, uint64(7), input.Fh)
+				require.NotNil(t, out.AddDirLookupEntry(fuse.DirEntry{Mode: fuse.S_IFDIR, Name: "."}, 1))
+				e := out.AddDirLookupEntry(fuse.DirEntry{Mode: fuse.S_IFREG, Name: "file", Ino: 456}, 2)
+				require.NotNil(t, e)
+				e.NodeId = 456
+				return fuse.OK
+			})
+
+		var entries struct {
+			DotEntry    fuse.EntryOut
+			DotIno      uint64
+			DotOff      uint64
+			DotNameLen  uint32
+			DotType     uint32
+			DotName     [8]byte
+			FileEntry   fuse.EntryOut
+			FileIno     uint64
+			FileOff     uint64
+			FileNameLen uint32
+			FileType    uint32
+			FileName    [8]byte
+		}
+		require.Equal(t, int32(0), f.request(44, 1, &struct {
+			Fh        uint64
+			Offset    uint64
+			Size      uint32
+			ReadFlags uint32
+			LockOwner uint64
+			Flags     uint32
+			Padding   uint32
+		}{
+			Fh:   7,
+			Size: 4096,
+		}, &entries))
+		require.Equal(t, uint64(0xffffffff), entries.DotIno)
+		require.Equal(t, uint32(1), entries.DotNameLen)
+		require.Equal(t, uint32(4), entries.DotType)
+		require.Equal(t, [8]byte{'.'}, entries.DotName)
+		require.Equal(t, uint64(456), entries.FileEntry.NodeId)
+		require.Equal(t, uint64(456), entries.FileIno)
+		require.Equal(t, uint64(2), entries.FileOff)
+		require.Equal(t, uint32(8), entries.FileType)
+		require.Equal(t, [8]byte{'f', 'i', 'l', 'e'}, entries.FileName)
+	})
+
+	// Upon disconnection, nodes that are still referenced by the
+	// guest should be released.
+	rawFileSystem.EXPECT().Forget(uint64(123), uint64(1))
+	rawFileSystem.EXPECT().Forget(uint64(456), uint64(1))
+	require.NoError(t, f.conn.Close())
+	require.NoError(t, <-errChan)
+}
@@ -0,0 +1,161 @@
+//go:build linux
+// +build linux
+
+package virtiofs
+
+import (
+	"sync"
+	"syscall"
+
+	"github.com/hanwen/go-fuse/v2/fuse"
+)
+
+// Codes of notification messages that may be sent to the guest over
+// the notification queue. These correspond to enum fuse_notify_code.
+const (
+	notifyInvalInode uint32 = 2
+	notifyInvalEntry uint32 = 3
+	notifyDelete     uint32 = 6
+)
+
+// invalidation of a directory entry or inode attributes that was
+// reported by the virtual file system.
+type invalidation struct {
+	code   uint32
+	node   uint64
+	child  uint64
+	name   string
+	offset int64
+	length int64
+}
+
+// VersionTable keeps track of invalidations of directory entries and
+// inode attributes that are reported by the virtual file system.
+//
+// Unlike a FUSE mount, a single virtio-fs server may have many guests
+// attached to it, each of which may be caching different parts of the
+// file system. Instead of sending invalidations to every guest
+// synchronously, invalidations are appended to a log that is shared by
+// all sessions. Every session keeps track of the last version it has
+// processed, allowing it to forward invalidations to its guest at its
+// own pace. This makes it cheap to invalidate the contents of an input
+// root when a virtual machine is reused to run another action.
+//
+// The log has a bounded size. Sessions that fall too far behind will
+// miss invalidations. They are expected to rely on directory entry and
+// inode attribute validity instead.
+type VersionTable struct {
+	maximumSize int
+
+	lock          sync.Mutex
+	firstVersion  uint64
+	invalidations []invalidation
+	wakeup        chan struct{}
+}
+
+var _ fuse.ServerCallbacks = (*VersionTable)(nil)
+
+// NewVersionTable creates a VersionTable that retains up to a given
+// number of invalidations.
+func NewVersionTable(maximumSize int) *VersionTable {
+	return &VersionTable{
+		maximumSize: maximumSize,
+		wakeup:      make(chan struct{}),
+	}
+}
+
+func (vt *VersionTable) append(i invalidation) {
+	vt.lock.Lock()
+	defer vt.lock.Unlock()
+
+	vt.invalidations = append(vt.invalidations, i)
+	if excess := len(vt.invalidations) - vt.maximumSize; excess > 0 {
+		vt.firstVersion += uint64(excess)
+		vt.invalidations = append(vt.invalidations[:0], vt.invalidations[excess:]...)
+	}
+
+	// Wake up all sessions waiting for new invalidations.
+	close(vt.wakeup)
+	vt.wakeup = make(chan struct{})
+}
+
+// getCurrentVersion returns the version of the next invalidation that
+// is appended to the log. It is used by sessions to determine the
+// starting point, as newly attached guests have nothing cached.
+func (vt *VersionTable) getCurrentVersion() uint64 {
+	vt.lock.Lock()
+	defer vt.lock.Unlock()
+	return vt.firstVersion + uint64(len(vt.invalidations))
+}
+
+// getInvalidations returns all invalidations that have been appended
+// to the log since a given version, the version that should be
+// provided on the next call, and a channel that is closed when new
+// invalidations are appended.
+func (vt *VersionTable) getInvalidations(version uint64) ([]invalidation, uint64, <-chan struct{}) {
+	vt.lock.Lock()
+	defer vt.lock.Unlock()
+
+	if version < vt.firstVersion {
+		version = vt.firstVersion
+	}
+	invalidations := append([]invalidation(nil), vt.invalidations[version-vt.firstVersion:]...)
+	return invalidations, vt.firstVersion + uint64(len(vt.invalidations)), vt.wakeup
+}
+
+// DeleteNotify records that a directory entry has been removed.
+func (vt *VersionTable) DeleteNotify(parent, child uint64, name string) fuse.Status {
+	vt.append(invalidation{
+		code:  notifyDelete,
+		node:  parent,
+		child: child,
+		name:  name,
+	})
+	return fuse.OK
+}
+
+// EntryNotify records that a directory entry has been changed.
+func (vt *VersionTable) EntryNotify(parent uint64, name string) fuse.Status {
+	vt.append(invalidation{
+		code: notifyInvalEntry,
+		node: parent,
+		name: name,
+	})
+	return fuse.OK
+}
+
+// InodeNotify records that the attributes or contents of an inode have
+// been changed.
+func (vt *VersionTable) InodeNotify(node uint64, off, length int64) fuse.Status {
+	vt.append(invalidation{
+		code:   notifyInvalInode,
+		node:   node,
+		offset: off,
+		length: length,
+	})
+	return fuse.OK
+}
+
+// InodeRetrieveCache is not supported, as there is no single guest
+// whose page cache can be inspected.
+func (vt *VersionTable) InodeRetrieveCache(node uint64, offset int64, dest []byte) (int, fuse.Status) {
+	return 0, fuse.ENOSYS
+}
+
+// InodeNotifyStoreCache is not supported, as there is no single guest
+// whose page cache can be populated.
+func (vt *VersionTable) InodeNotifyStoreCache(node uint64, offset int64, data []byte) fuse.Status {
+	return fuse.ENOSYS
+}
+
+// RegisterBackingFd is not supported, as files of the host cannot be
+// made accessible to the guest directly.
+func (vt *VersionTable) RegisterBackingFd(backingMap *fuse.BackingMap) (int32, syscall.Errno) {
+	return 0, syscall.ENOSYS
+}
+
+// UnregisterBackingFd is not supported, as RegisterBackingFd never
+// hands out backing IDs.
+func (vt *VersionTable) UnregisterBackingFd(backingID int32) syscall.Errno {
+	return syscall.ENOSYS
+}
@@ -0,0 +1,221 @@
+//go:build linux
+// +build linux
+
+package virtiofs
+
+import (
+	"encoding/binary"
+	"sync/atomic"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Flags of virtqueue descriptors.
+const (
+	descriptorFlagNext     = 1
+	descriptorFlagWrite    = 2
+	descriptorFlagIndirect = 4
+
+	descriptorSize = 16
+
+	// VRING_AVAIL_F_NO_INTERRUPT.
+	availableFlagNoInterrupt = 1
+)
+
+// memoryRegion of the guest that has been mapped into our address
+// space.
+type memoryRegion struct {
+	guestAddress uint64
+	userAddress  uint64
+	data         []byte
+}
+
+// guestMemory contains the memory regions of the guest. Virtqueue
+// descriptors refer to buffers using guest physical addresses, while
+// the addresses of the virtqueues themselves are provided using
+// addresses in the frontend's address space.
+type guestMemory []memoryRegion
+
+func translateAddress(address, length uint64, regionAddress func(r *memoryRegion) uint64, regions guestMemory) ([]byte, error) {
+	for i := range regions {
+		r := &regions[i]
+		start := regionAddress(r)
+		if address >= start && address-start <= uint64(len(r.data)) && length <= uint64(len(r.data))-(address-start) {
+			offset := address - start
+			return r.data[offset : offset+length : offset+length], nil
+		}
+	}
+	return nil, status.Errorf(codes.InvalidArgument, "Address range [0x%x, 0x%x) is not part of guest memory", address, address+length)
+}
+
+func (m guestMemory) translateGuestAddress(address, length uint64) ([]byte, error) {
+	return translateAddress(address, length, func(r *memoryRegion) uint64 { return r.guestAddress }, m)
+}
+
+func (m guestMemory) translateUserAddress(address, length uint64) ([]byte, error) {
+	return translateAddress(address, length, func(r *memoryRegion) uint64 { return r.userAddress }, m)
+}
+
+// memoryBarrier ensures that loads and stores of shared memory are not
+// reordered across it. Atomic operations in Go are sequentially
+// consistent, meaning that they act as a full barrier.
+var memoryBarrierCounter atomic.Uint32
+
+func memoryBarrier() {
+	memoryBarrierCounter.Add(1)
+}
+
+// descriptorChain contains the buffers of a single request that has
+// been placed in a virtqueue. Readable buffers contain the request,
+// while writable buffers are used to store the response.
+type descriptorChain struct {
+	head     uint16
+	readable [][]byte
+	writable [][]byte
+}
+
+func totalSize(buffers [][]byte) int {
+	size := 0
+	for _, b := range buffers {
+		size += len(b)
+	}
+	return size
+}
+
+// gatherReadable copies the contents of all readable buffers into a
+// single contiguous buffer.
+func (c *descriptorChain) gatherReadable() []byte {
+	data := make([]byte, 0, totalSize(c.readable))
+	for _, b := range c.readable {
+		data = append(data, b...)
+	}
+	return data
+}
+
+// scatterWritable copies data into the writable buffers.
+func (c *descriptorChain) scatterWritable(data []byte) {
+	for _, b := range c.writable {
+		if len(data) == 0 {
+			break
+		}
+		data = data[copy(b, data):]
+	}
+}
+
+// virtqueue is an implementation of the device side of a split
+// virtqueue, as described in chapter 2.7 of the Virtual I/O Device
+// (VIRTIO) specification, version 1.2.
+type virtqueue struct {
+	memory      guestMemory
+	size        uint16
+	descriptors []byte
+	available   []byte
+	used        []byte
+
+	lastAvailableIndex uint16
+	usedIndex          uint16
+}
+
+func newVirtqueue(memory guestMemory, size uint16, descriptorsAddress, availableAddress, usedAddress uint64, baseIndex uint16) (*virtqueue, error) {
+	if size == 0 || size&(size-1) != 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Virtqueue size %d is not a power of two", size)
+	}
+	descriptors, err := memory.translateUserAddress(descriptorsAddress, uint64(size)*descriptorSize)
+	if err != nil {
+		return nil, err
+	}
+	available, err := memory.translateUserAddress(availableAddress, 6+2*uint64(size))
+	if err != nil {
+		return nil, err
+	}
+	used, err := memory.translateUserAddress(usedAddress, 6+8*uint64(size))
+	if err != nil {
+		return nil, err
+	}
+	return &virtqueue{
+		memory:             memory,
+		size:               size,
+		descriptors:        descriptors,
+		available:          available,
+		used:               used,
+		lastAvailableIndex: baseIndex,
+		usedIndex:          baseIndex,
+	}, nil
+}
+
+// pop the next descriptor chain that has been made available by the
+// driver.
+func (q *virtqueue) pop() (*descriptorChain, bool, error) {
+	availableIndex := binary.LittleEndian.Uint16(q.available[2:])
+	memoryBarrier()
+	if availableIndex == q.lastAvailableIndex {
+		return nil, false, nil
+	}
+	head := binary.LittleEndian.Uint16(q.available[4+2*uint32(q.lastAvailableIndex%q.size):])
+	q.lastAvailableIndex++
+
+	chain := &descriptorChain{head: head}
+	if err := q.walkDescriptors(chain, q.descriptors, head, true); err != nil {
+		return nil, false, err
+	}
+	return chain, true, nil
+}
+
+func (q *virtqueue) walkDescriptors(chain *descriptorChain, table []byte, index uint16, allowIndirect bool) error {
+	count := len(table) / descriptorSize
+	for i := 0; ; i++ {
+		// Prevent loops in the chain from causing us to spin.
+		if i >= count || int(index) >= count {
+			return status.Error(codes.InvalidArgument, "Invalid descriptor chain")
+		}
+		descriptor := table[int(index)*descriptorSize:]
+		address := binary.LittleEndian.Uint64(descriptor[0:])
+		length := binary.LittleEndian.Uint32(descriptor[8:])
+		flags := binary.LittleEndian.Uint16(descriptor[12:])
+		next := binary.LittleEndian.Uint16(descriptor[14:])
+
+		if flags&descriptorFlagIndirect != 0 {
+			if !allowIndirect || length%descriptorSize != 0 {
+				return status.Error(codes.InvalidArgument, "Invalid indirect descriptor table")
+			}
+			indirectTable, err := q.memory.translateGuestAddress(address, uint64(length))
+			if err != nil {
+				return err
+			}
+			if err := q.walkDescriptors(chain, indirectTable, 0, false); err != nil {
+				return err
+			}
+		} else {
+			buffer, err := q.memory.translateGuestAddress(address, uint64(length))
+			if err != nil {
+				return err
+			}
+			if flags&descriptorFlagWrite != 0 {
+				chain.writable = append(chain.writable, buffer)
+			} else if len(chain.writable) > 0 {
+				return status.Error(codes.InvalidArgument, "Readable descriptor follows writable descriptor")
+			} else {
+				chain.readable = append(chain.readable, buffer)
+			}
+		}
+
+		if flags&descriptorFlagNext == 0 {
+			return nil
+		}
+		index = next
+	}
+}
+
+// push a descriptor chain that has been processed into the used ring.
+// The return value indicates whether the driver needs to be notified.
+func (q *virtqueue) push(head uint16, length uint32) bool {
+	element := q.used[4+8*uint32(q.usedIndex%q.size):]
+	binary.LittleEndian.PutUint32(element[0:], uint32(head))
+	binary.LittleEndian.PutUint32(element[4:], length)
+	memoryBarrier()
+	q.usedIndex++
+	binary.LittleEndian.PutUint16(q.used[2:], q.usedIndex)
+	memoryBarrier()
+	return binary.LittleEndian.Uint16(q.available[0:])&availableFlagNoInterrupt == 0
+}
@@ -0,0 +1,134 @@
+package filesystem
+
+import (
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+)
+
+type writeBackFilePool struct {
+	base                   FilePool
+	maximumBufferSizeBytes int
+}
+
+// NewWriteBackFilePool creates a decorator for FilePool that buffers
+// writes in memory, only forwarding them to the underlying FilePool
+// once they can no longer be coalesced. Tools like compilers tend to
+// emit object files using many small sequential writes. Buffering
+// these reduces the number of operations performed against the
+// underlying FilePool considerably.
+//
+// Buffered data is flushed when a write is performed that is not
+// contiguous with the buffered data, when the buffer is full, and
+// prior to any other operation on the file, such as reads, truncations
+// and calls to Sync(). Callers that need errors of buffered writes to
+// be reported (e.g., when handling fsync()) must call Sync(). If the
+// file is closed while data is still buffered, the data is discarded
+// without ever being written to the underlying FilePool.
+func NewWriteBackFilePool(base FilePool, maximumBufferSizeBytes int) FilePool {
+	return &writeBackFilePool{
+		base:                   base,
+		maximumBufferSizeBytes: maximumBufferSizeBytes,
+	}
+}
+
+func (fp *writeBackFilePool) NewFile() (filesystem.FileReadWriter, error) {
+	f, err := fp.base.NewFile()
+	if err != nil {
+		return nil, err
+	}
+	return &writeBackFile{
+		pool: fp,
+		base: f,
+	}, nil
+}
+
+// writeBackFile is the file type returned by writeBackFilePool. The
+// buffer is only allocated while data is pending, so that files that
+// are idle don't consume any memory.
+type writeBackFile struct {
+	pool         *writeBackFilePool
+	base         filesystem.FileReadWriter
+	buffer       []byte
+	bufferOffset int64
+}
+
+// flush any buffered data to the underlying file. The buffer is
+// released, even if writing fails, as there is no way to recover from
+// such failures.
+func (f *writeBackFile) flush() error {
+	if len(f.buffer) == 0 {
+		return nil
+	}
+	buffer, bufferOffset := f.buffer, f.bufferOffset
+	f.buffer = nil
+	_, err := f.base.WriteAt(buffer, bufferOffset)
+	return err
+}
+
+func (f *writeBackFile) Close() error {
+	f.buffer = nil
+	return f.base.Close()
+}
+
+func (f *writeBackFile) Deallocate(off, size int64) error {
+	if err := f.flush(); err != nil {
+		return err
+	}
+	return DeallocateFile(f.base, off, size)
+}
+
+func (f *writeBackFile) GetAllocatedSizeBytes() (int64, error) {
+	if err := f.flush(); err != nil {
+		return 0, err
+	}
+	return GetFileAllocatedSizeBytes(f.base)
+}
+
+func (f *writeBackFile) GetNextRegionOffset(off int64, regionType filesystem.RegionType) (int64, error) {
+	if err := f.flush(); err != nil {
+		return 0, err
+	}
+	return f.base.GetNextRegionOffset(off, regionType)
+}
+
+func (f *writeBackFile) ReadAt(p []byte, off int64) (int, error) {
+	if err := f.flush(); err != nil {
+		return 0, err
+	}
+	return f.base.ReadAt(p, off)
+}
+
+func (f *writeBackFile) Sync() error {
+	if err := f.flush(); err != nil {
+		return err
+	}
+	return f.base.Sync()
+}
+
+func (f *writeBackFile) Truncate(size int64) error {
+	if err := f.flush(); err != nil {
+		return err
+	}
+	return f.base.Truncate(size)
+}
+
+func (f *writeBackFile) WriteAt(p []byte, off int64) (int, error) {
+	maximumBufferSizeBytes := f.pool.maximumBufferSizeBytes
+	if len(f.buffer) > 0 && off == f.bufferOffset+int64(len(f.buffer)) && len(f.buffer)+len(p) <= maximumBufferSizeBytes {
+		// Write is contiguous with the buffered data.
+		f.buffer = append(f.buffer, p...)
+		return len(p), nil
+	}
+
+	if err := f.flush(); err != nil {
+		return 0, err
+	}
+	if len(p) >= maximumBufferSizeBytes || off < 0 {
+		// Large writes don't benefit from buffering. Writes at
+		// negative offsets are forwarded, so that they fail
+		// immediately.
+		return f.base.WriteAt(p, off)
+	}
+	f.buffer = append(make([]byte, 0, maximumBufferSizeBytes), p...)
+	f.bufferOffset = off
+	return len(p), nil
+}
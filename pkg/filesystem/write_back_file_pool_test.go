@@ -0,0 +1,109 @@
+package filesystem_test
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBackFilePool(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	basePool := mock.NewMockFilePool(ctrl)
+	pool := re_filesystem.NewWriteBackFilePool(basePool, 10)
+
+	t.Run("CoalesceWrites", func(t *testing.T) {
+		baseFile := mock.NewMockFileReadWriter(ctrl)
+		basePool.EXPECT().NewFile().Return(baseFile, nil)
+		f, err := pool.NewFile()
+		require.NoError(t, err)
+
+		// Contiguous writes should be coalesced.
+		n, err := f.WriteAt([]byte("Hel"), 3)
+		require.Equal(t, 3, n)
+		require.NoError(t, err)
+		n, err = f.WriteAt([]byte("lo"), 6)
+		require.Equal(t, 2, n)
+		require.NoError(t, err)
+		n, err = f.WriteAt([]byte(", wo"), 8)
+		require.Equal(t, 4, n)
+		require.NoError(t, err)
+
+		// A write that doesn't fit in the buffer causes the
+		// buffered data to be flushed.
+		baseFile.EXPECT().WriteAt([]byte("Hello, wo"), int64(3)).Return(9, nil)
+		n, err = f.WriteAt([]byte("rld"), 12)
+		require.Equal(t, 3, n)
+		require.NoError(t, err)
+
+		// Reads should observe the buffered data.
+		baseFile.EXPECT().WriteAt([]byte("rld"), int64(12)).Return(3, nil)
+		baseFile.EXPECT().ReadAt(gomock.Len(4), int64(12)).Return(3, nil)
+		var p [4]byte
+		n, err = f.ReadAt(p[:], 12)
+		require.Equal(t, 3, n)
+		require.NoError(t, err)
+
+		baseFile.EXPECT().Close()
+		require.NoError(t, f.Close())
+	})
+
+	t.Run("LargeWrite", func(t *testing.T) {
+		baseFile := mock.NewMockFileReadWriter(ctrl)
+		basePool.EXPECT().NewFile().Return(baseFile, nil)
+		f, err := pool.NewFile()
+		require.NoError(t, err)
+
+		// Writes that are at least as large as the buffer
+		// should be forwarded directly.
+		baseFile.EXPECT().WriteAt([]byte("Hello, world"), int64(0)).Return(12, nil)
+		n, err := f.WriteAt([]byte("Hello, world"), 0)
+		require.Equal(t, 12, n)
+		require.NoError(t, err)
+
+		baseFile.EXPECT().Close()
+		require.NoError(t, f.Close())
+	})
+
+	t.Run("SyncFailure", func(t *testing.T) {
+		baseFile := mock.NewMockFileReadWriter(ctrl)
+		basePool.EXPECT().NewFile().Return(baseFile, nil)
+		f, err := pool.NewFile()
+		require.NoError(t, err)
+
+		n, err := f.WriteAt([]byte("Hello"), 0)
+		require.Equal(t, 5, n)
+		require.NoError(t, err)
+
+		// Errors of buffered writes should be reported by
+		// Sync().
+		baseFile.EXPECT().WriteAt([]byte("Hello"), int64(0)).Return(0, syscall.ENOSPC)
+		require.Equal(t, syscall.ENOSPC, f.Sync())
+
+		baseFile.EXPECT().Sync()
+		require.NoError(t, f.Sync())
+
+		baseFile.EXPECT().Close()
+		require.NoError(t, f.Close())
+	})
+
+	t.Run("CloseDiscardsBuffer", func(t *testing.T) {
+		baseFile := mock.NewMockFileReadWriter(ctrl)
+		basePool.EXPECT().NewFile().Return(baseFile, nil)
+		f, err := pool.NewFile()
+		require.NoError(t, err)
+
+		n, err := f.WriteAt([]byte("Hello"), 0)
+		require.Equal(t, 5, n)
+		require.NoError(t, err)
+
+		// Data of files that are closed never needs to be
+		// written.
+		baseFile.EXPECT().Close()
+		require.NoError(t, f.Close())
+	})
+}
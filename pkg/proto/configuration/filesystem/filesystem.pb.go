@@ -32,7 +32,12 @@ type FilePoolConfiguration struct {
 	//	*FilePoolConfiguration_InMemory
 	//	*FilePoolConfiguration_DirectoryPath
 	//	*FilePoolConfiguration_BlockDevice
+	//	*FilePoolConfiguration_Spilling
+	//	*FilePoolConfiguration_DirectIoBlockDevicePath
+	//	*FilePoolConfiguration_Directory
+	//	*FilePoolConfiguration_WriteBack
 	Backend isFilePoolConfiguration_Backend `protobuf_oneof:"backend"`
+	Name    string                          `protobuf:"bytes,8,opt,name=name,proto3" json:"name,omitempty"`
 }
 
 func (x *FilePoolConfiguration) Reset() {
@@ -95,6 +100,41 @@ func (x *FilePoolConfiguration) GetBlockDevice() *blockdevice.Configuration {
 	return nil
 }
 
+func (x *FilePoolConfiguration) GetSpilling() *SpillingFilePoolConfiguration {
+	if x, ok := x.GetBackend().(*FilePoolConfiguration_Spilling); ok {
+		return x.Spilling
+	}
+	return nil
+}
+
+func (x *FilePoolConfiguration) GetDirectIoBlockDevicePath() string {
+	if x, ok := x.GetBackend().(*FilePoolConfiguration_DirectIoBlockDevicePath); ok {
+		return x.DirectIoBlockDevicePath
+	}
+	return ""
+}
+
+func (x *FilePoolConfiguration) GetDirectory() *DirectoryFilePoolConfiguration {
+	if x, ok := x.GetBackend().(*FilePoolConfiguration_Directory); ok {
+		return x.Directory
+	}
+	return nil
+}
+
+func (x *FilePoolConfiguration) GetWriteBack() *WriteBackFilePoolConfiguration {
+	if x, ok := x.GetBackend().(*FilePoolConfiguration_WriteBack); ok {
+		return x.WriteBack
+	}
+	return nil
+}
+
+func (x *FilePoolConfiguration) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
 type isFilePoolConfiguration_Backend interface {
 	isFilePoolConfiguration_Backend()
 }
@@ -111,12 +151,209 @@ type FilePoolConfiguration_BlockDevice struct {
 	BlockDevice *blockdevice.Configuration `protobuf:"bytes,3,opt,name=block_device,json=blockDevice,proto3,oneof"`
 }
 
+type FilePoolConfiguration_Spilling struct {
+	Spilling *SpillingFilePoolConfiguration `protobuf:"bytes,4,opt,name=spilling,proto3,oneof"`
+}
+
+type FilePoolConfiguration_DirectIoBlockDevicePath struct {
+	DirectIoBlockDevicePath string `protobuf:"bytes,5,opt,name=direct_io_block_device_path,json=directIoBlockDevicePath,proto3,oneof"`
+}
+
+type FilePoolConfiguration_Directory struct {
+	Directory *DirectoryFilePoolConfiguration `protobuf:"bytes,6,opt,name=directory,proto3,oneof"`
+}
+
+type FilePoolConfiguration_WriteBack struct {
+	WriteBack *WriteBackFilePoolConfiguration `protobuf:"bytes,7,opt,name=write_back,json=writeBack,proto3,oneof"`
+}
+
 func (*FilePoolConfiguration_InMemory) isFilePoolConfiguration_Backend() {}
 
 func (*FilePoolConfiguration_DirectoryPath) isFilePoolConfiguration_Backend() {}
 
 func (*FilePoolConfiguration_BlockDevice) isFilePoolConfiguration_Backend() {}
 
+func (*FilePoolConfiguration_Spilling) isFilePoolConfiguration_Backend() {}
+
+func (*FilePoolConfiguration_DirectIoBlockDevicePath) isFilePoolConfiguration_Backend() {}
+
+func (*FilePoolConfiguration_Directory) isFilePoolConfiguration_Backend() {}
+
+func (*FilePoolConfiguration_WriteBack) isFilePoolConfiguration_Backend() {}
+
+type WriteBackFilePoolConfiguration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Backend                *FilePoolConfiguration `protobuf:"bytes,1,opt,name=backend,proto3" json:"backend,omitempty"`
+	MaximumBufferSizeBytes uint32                 `protobuf:"varint,2,opt,name=maximum_buffer_size_bytes,json=maximumBufferSizeBytes,proto3" json:"maximum_buffer_size_bytes,omitempty"`
+}
+
+func (x *WriteBackFilePoolConfiguration) Reset() {
+	*x = WriteBackFilePoolConfiguration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_configuration_filesystem_filesystem_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WriteBackFilePoolConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteBackFilePoolConfiguration) ProtoMessage() {}
+
+func (x *WriteBackFilePoolConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_configuration_filesystem_filesystem_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteBackFilePoolConfiguration.ProtoReflect.Descriptor instead.
+func (*WriteBackFilePoolConfiguration) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_filesystem_filesystem_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *WriteBackFilePoolConfiguration) GetBackend() *FilePoolConfiguration {
+	if x != nil {
+		return x.Backend
+	}
+	return nil
+}
+
+func (x *WriteBackFilePoolConfiguration) GetMaximumBufferSizeBytes() uint32 {
+	if x != nil {
+		return x.MaximumBufferSizeBytes
+	}
+	return 0
+}
+
+type DirectoryFilePoolConfiguration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path                   string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	MaximumDeletionBacklog uint32 `protobuf:"varint,2,opt,name=maximum_deletion_backlog,json=maximumDeletionBacklog,proto3" json:"maximum_deletion_backlog,omitempty"`
+}
+
+func (x *DirectoryFilePoolConfiguration) Reset() {
+	*x = DirectoryFilePoolConfiguration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_configuration_filesystem_filesystem_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DirectoryFilePoolConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DirectoryFilePoolConfiguration) ProtoMessage() {}
+
+func (x *DirectoryFilePoolConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_configuration_filesystem_filesystem_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DirectoryFilePoolConfiguration.ProtoReflect.Descriptor instead.
+func (*DirectoryFilePoolConfiguration) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_filesystem_filesystem_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DirectoryFilePoolConfiguration) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DirectoryFilePoolConfiguration) GetMaximumDeletionBacklog() uint32 {
+	if x != nil {
+		return x.MaximumDeletionBacklog
+	}
+	return 0
+}
+
+type SpillingFilePoolConfiguration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Backend                       *FilePoolConfiguration `protobuf:"bytes,1,opt,name=backend,proto3" json:"backend,omitempty"`
+	MaximumInMemoryFileSizeBytes  int64                  `protobuf:"varint,2,opt,name=maximum_in_memory_file_size_bytes,json=maximumInMemoryFileSizeBytes,proto3" json:"maximum_in_memory_file_size_bytes,omitempty"`
+	MaximumInMemoryTotalSizeBytes int64                  `protobuf:"varint,3,opt,name=maximum_in_memory_total_size_bytes,json=maximumInMemoryTotalSizeBytes,proto3" json:"maximum_in_memory_total_size_bytes,omitempty"`
+}
+
+func (x *SpillingFilePoolConfiguration) Reset() {
+	*x = SpillingFilePoolConfiguration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_configuration_filesystem_filesystem_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SpillingFilePoolConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpillingFilePoolConfiguration) ProtoMessage() {}
+
+func (x *SpillingFilePoolConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_configuration_filesystem_filesystem_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpillingFilePoolConfiguration.ProtoReflect.Descriptor instead.
+func (*SpillingFilePoolConfiguration) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_filesystem_filesystem_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SpillingFilePoolConfiguration) GetBackend() *FilePoolConfiguration {
+	if x != nil {
+		return x.Backend
+	}
+	return nil
+}
+
+func (x *SpillingFilePoolConfiguration) GetMaximumInMemoryFileSizeBytes() int64 {
+	if x != nil {
+		return x.MaximumInMemoryFileSizeBytes
+	}
+	return 0
+}
+
+func (x *SpillingFilePoolConfiguration) GetMaximumInMemoryTotalSizeBytes() int64 {
+	if x != nil {
+		return x.MaximumInMemoryTotalSizeBytes
+	}
+	return 0
+}
+
 var File_pkg_proto_configuration_filesystem_filesystem_proto protoreflect.FileDescriptor
 
 var file_pkg_proto_configuration_filesystem_filesystem_proto_rawDesc = []byte{
@@ -130,7 +367,7 @@ var file_pkg_proto_configuration_filesystem_filesystem_proto_rawDesc = []byte{
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x35, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f,
 	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x62, 0x6c, 0x6f, 0x63,
-	0x6b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xdb, 0x01,
+	0x6b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd9, 0x04,
 	0x0a, 0x15, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
 	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x09, 0x69, 0x6e, 0x5f, 0x6d, 0x65,
 	0x6d, 0x6f, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
@@ -144,13 +381,71 @@ var file_pkg_proto_configuration_filesystem_filesystem_proto_rawDesc = []byte{
 	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x64, 0x65, 0x76,
 	0x69, 0x63, 0x65, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
 	0x6e, 0x48, 0x00, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
-	0x42, 0x09, 0x0a, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x42, 0x4d, 0x5a, 0x4b, 0x67,
-	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
-	0x61, 0x72, 0x6e, 0x2f, 0x62, 0x62, 0x2d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2d, 0x65, 0x78,
-	0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f,
-	0x66, 0x69, 0x6c, 0x65, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x12, 0x5f, 0x0a, 0x08, 0x73, 0x70, 0x69, 0x6c, 0x6c, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x41, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x66, 0x69, 0x6c,
+	0x65, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x2e, 0x53, 0x70, 0x69, 0x6c, 0x6c, 0x69, 0x6e, 0x67,
+	0x46, 0x69, 0x6c, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x08, 0x73, 0x70, 0x69, 0x6c, 0x6c, 0x69, 0x6e,
+	0x67, 0x12, 0x3e, 0x0a, 0x1b, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x69, 0x6f, 0x5f, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x17, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x49, 0x6f, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x61, 0x74,
+	0x68, 0x12, 0x62, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x42, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e,
+	0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x66,
+	0x69, 0x6c, 0x65, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x63, 0x0a, 0x0a, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x62,
+	0x61, 0x63, 0x6b, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x42, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x2e, 0x57,
+	0x72, 0x69, 0x74, 0x65, 0x42, 0x61, 0x63, 0x6b, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x6f, 0x6f, 0x6c,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52,
+	0x09, 0x77, 0x72, 0x69, 0x74, 0x65, 0x42, 0x61, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x09,
+	0x0a, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x22, 0xb0, 0x01, 0x0a, 0x1e, 0x57, 0x72,
+	0x69, 0x74, 0x65, 0x42, 0x61, 0x63, 0x6b, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x53, 0x0a, 0x07,
+	0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x39, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x79, 0x73, 0x74,
+	0x65, 0x6d, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e,
+	0x64, 0x12, 0x39, 0x0a, 0x19, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x62, 0x75, 0x66,
+	0x66, 0x65, 0x72, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x16, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x42, 0x75, 0x66,
+	0x66, 0x65, 0x72, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x6e, 0x0a, 0x1e,
+	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x6f, 0x6f,
+	0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12,
+	0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x12, 0x38, 0x0a, 0x18, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x64, 0x65,
+	0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x6c, 0x6f, 0x67, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x16, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x61, 0x63, 0x6b, 0x6c, 0x6f, 0x67, 0x22, 0x88, 0x02, 0x0a,
+	0x1d, 0x53, 0x70, 0x69, 0x6c, 0x6c, 0x69, 0x6e, 0x67, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x6f, 0x6f,
+	0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x53,
+	0x0a, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x39, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x62, 0x61, 0x63, 0x6b,
+	0x65, 0x6e, 0x64, 0x12, 0x47, 0x0a, 0x21, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x69,
+	0x6e, 0x5f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x1c,
+	0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x49, 0x6e, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x46,
+	0x69, 0x6c, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x49, 0x0a, 0x22,
+	0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x69, 0x6e, 0x5f, 0x6d, 0x65, 0x6d, 0x6f, 0x72,
+	0x79, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x1d, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75,
+	0x6d, 0x49, 0x6e, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x69,
+	0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x42, 0x4d, 0x5a, 0x4b, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2f,
+	0x62, 0x62, 0x2d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2d, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74,
+	0x69, 0x6f, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x66, 0x69, 0x6c, 0x65,
+	0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -165,20 +460,28 @@ func file_pkg_proto_configuration_filesystem_filesystem_proto_rawDescGZIP() []by
 	return file_pkg_proto_configuration_filesystem_filesystem_proto_rawDescData
 }
 
-var file_pkg_proto_configuration_filesystem_filesystem_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_pkg_proto_configuration_filesystem_filesystem_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_pkg_proto_configuration_filesystem_filesystem_proto_goTypes = []interface{}{
-	(*FilePoolConfiguration)(nil),     // 0: buildbarn.configuration.filesystem.FilePoolConfiguration
-	(*emptypb.Empty)(nil),             // 1: google.protobuf.Empty
-	(*blockdevice.Configuration)(nil), // 2: buildbarn.configuration.blockdevice.Configuration
+	(*FilePoolConfiguration)(nil),          // 0: buildbarn.configuration.filesystem.FilePoolConfiguration
+	(*WriteBackFilePoolConfiguration)(nil), // 1: buildbarn.configuration.filesystem.WriteBackFilePoolConfiguration
+	(*DirectoryFilePoolConfiguration)(nil), // 2: buildbarn.configuration.filesystem.DirectoryFilePoolConfiguration
+	(*SpillingFilePoolConfiguration)(nil),  // 3: buildbarn.configuration.filesystem.SpillingFilePoolConfiguration
+	(*emptypb.Empty)(nil),                  // 4: google.protobuf.Empty
+	(*blockdevice.Configuration)(nil),      // 5: buildbarn.configuration.blockdevice.Configuration
 }
 var file_pkg_proto_configuration_filesystem_filesystem_proto_depIdxs = []int32{
-	1, // 0: buildbarn.configuration.filesystem.FilePoolConfiguration.in_memory:type_name -> google.protobuf.Empty
-	2, // 1: buildbarn.configuration.filesystem.FilePoolConfiguration.block_device:type_name -> buildbarn.configuration.blockdevice.Configuration
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	4, // 0: buildbarn.configuration.filesystem.FilePoolConfiguration.in_memory:type_name -> google.protobuf.Empty
+	5, // 1: buildbarn.configuration.filesystem.FilePoolConfiguration.block_device:type_name -> buildbarn.configuration.blockdevice.Configuration
+	3, // 2: buildbarn.configuration.filesystem.FilePoolConfiguration.spilling:type_name -> buildbarn.configuration.filesystem.SpillingFilePoolConfiguration
+	2, // 3: buildbarn.configuration.filesystem.FilePoolConfiguration.directory:type_name -> buildbarn.configuration.filesystem.DirectoryFilePoolConfiguration
+	1, // 4: buildbarn.configuration.filesystem.FilePoolConfiguration.write_back:type_name -> buildbarn.configuration.filesystem.WriteBackFilePoolConfiguration
+	0, // 5: buildbarn.configuration.filesystem.WriteBackFilePoolConfiguration.backend:type_name -> buildbarn.configuration.filesystem.FilePoolConfiguration
+	0, // 6: buildbarn.configuration.filesystem.SpillingFilePoolConfiguration.backend:type_name -> buildbarn.configuration.filesystem.FilePoolConfiguration
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_pkg_proto_configuration_filesystem_filesystem_proto_init() }
@@ -199,11 +502,51 @@ func file_pkg_proto_configuration_filesystem_filesystem_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_proto_configuration_filesystem_filesystem_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WriteBackFilePoolConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_configuration_filesystem_filesystem_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DirectoryFilePoolConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_configuration_filesystem_filesystem_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SpillingFilePoolConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_pkg_proto_configuration_filesystem_filesystem_proto_msgTypes[0].OneofWrappers = []interface{}{
 		(*FilePoolConfiguration_InMemory)(nil),
 		(*FilePoolConfiguration_DirectoryPath)(nil),
 		(*FilePoolConfiguration_BlockDevice)(nil),
+		(*FilePoolConfiguration_Spilling)(nil),
+		(*FilePoolConfiguration_DirectIoBlockDevicePath)(nil),
+		(*FilePoolConfiguration_Directory)(nil),
+		(*FilePoolConfiguration_WriteBack)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -211,7 +554,7 @@ func file_pkg_proto_configuration_filesystem_filesystem_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_proto_configuration_filesystem_filesystem_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
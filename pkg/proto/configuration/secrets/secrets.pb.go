@@ -0,0 +1,495 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        v4.23.4
+// source: pkg/proto/configuration/secrets/secrets.proto
+
+package secrets
+
+import (
+	http "github.com/buildbarn/bb-storage/pkg/proto/configuration/http"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ProviderConfiguration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Backend:
+	//
+	//	*ProviderConfiguration_Static
+	//	*ProviderConfiguration_Vault
+	//	*ProviderConfiguration_GcpSecretManager
+	Backend       isProviderConfiguration_Backend `protobuf_oneof:"backend"`
+	CacheDuration *durationpb.Duration            `protobuf:"bytes,4,opt,name=cache_duration,json=cacheDuration,proto3" json:"cache_duration,omitempty"`
+}
+
+func (x *ProviderConfiguration) Reset() {
+	*x = ProviderConfiguration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_configuration_secrets_secrets_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProviderConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderConfiguration) ProtoMessage() {}
+
+func (x *ProviderConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_configuration_secrets_secrets_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderConfiguration.ProtoReflect.Descriptor instead.
+func (*ProviderConfiguration) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_secrets_secrets_proto_rawDescGZIP(), []int{0}
+}
+
+func (m *ProviderConfiguration) GetBackend() isProviderConfiguration_Backend {
+	if m != nil {
+		return m.Backend
+	}
+	return nil
+}
+
+func (x *ProviderConfiguration) GetStatic() *StaticProviderConfiguration {
+	if x, ok := x.GetBackend().(*ProviderConfiguration_Static); ok {
+		return x.Static
+	}
+	return nil
+}
+
+func (x *ProviderConfiguration) GetVault() *VaultProviderConfiguration {
+	if x, ok := x.GetBackend().(*ProviderConfiguration_Vault); ok {
+		return x.Vault
+	}
+	return nil
+}
+
+func (x *ProviderConfiguration) GetGcpSecretManager() *GCPSecretManagerProviderConfiguration {
+	if x, ok := x.GetBackend().(*ProviderConfiguration_GcpSecretManager); ok {
+		return x.GcpSecretManager
+	}
+	return nil
+}
+
+func (x *ProviderConfiguration) GetCacheDuration() *durationpb.Duration {
+	if x != nil {
+		return x.CacheDuration
+	}
+	return nil
+}
+
+type isProviderConfiguration_Backend interface {
+	isProviderConfiguration_Backend()
+}
+
+type ProviderConfiguration_Static struct {
+	Static *StaticProviderConfiguration `protobuf:"bytes,1,opt,name=static,proto3,oneof"`
+}
+
+type ProviderConfiguration_Vault struct {
+	Vault *VaultProviderConfiguration `protobuf:"bytes,2,opt,name=vault,proto3,oneof"`
+}
+
+type ProviderConfiguration_GcpSecretManager struct {
+	GcpSecretManager *GCPSecretManagerProviderConfiguration `protobuf:"bytes,3,opt,name=gcp_secret_manager,json=gcpSecretManager,proto3,oneof"`
+}
+
+func (*ProviderConfiguration_Static) isProviderConfiguration_Backend() {}
+
+func (*ProviderConfiguration_Vault) isProviderConfiguration_Backend() {}
+
+func (*ProviderConfiguration_GcpSecretManager) isProviderConfiguration_Backend() {}
+
+type StaticProviderConfiguration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Secrets map[string]string `protobuf:"bytes,1,rep,name=secrets,proto3" json:"secrets,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *StaticProviderConfiguration) Reset() {
+	*x = StaticProviderConfiguration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_configuration_secrets_secrets_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StaticProviderConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StaticProviderConfiguration) ProtoMessage() {}
+
+func (x *StaticProviderConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_configuration_secrets_secrets_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StaticProviderConfiguration.ProtoReflect.Descriptor instead.
+func (*StaticProviderConfiguration) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_secrets_secrets_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StaticProviderConfiguration) GetSecrets() map[string]string {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+type VaultProviderConfiguration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address    string                    `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	MountPath  string                    `protobuf:"bytes,2,opt,name=mount_path,json=mountPath,proto3" json:"mount_path,omitempty"`
+	TokenPath  string                    `protobuf:"bytes,3,opt,name=token_path,json=tokenPath,proto3" json:"token_path,omitempty"`
+	HttpClient *http.ClientConfiguration `protobuf:"bytes,4,opt,name=http_client,json=httpClient,proto3" json:"http_client,omitempty"`
+}
+
+func (x *VaultProviderConfiguration) Reset() {
+	*x = VaultProviderConfiguration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_configuration_secrets_secrets_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VaultProviderConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VaultProviderConfiguration) ProtoMessage() {}
+
+func (x *VaultProviderConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_configuration_secrets_secrets_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VaultProviderConfiguration.ProtoReflect.Descriptor instead.
+func (*VaultProviderConfiguration) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_secrets_secrets_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VaultProviderConfiguration) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *VaultProviderConfiguration) GetMountPath() string {
+	if x != nil {
+		return x.MountPath
+	}
+	return ""
+}
+
+func (x *VaultProviderConfiguration) GetTokenPath() string {
+	if x != nil {
+		return x.TokenPath
+	}
+	return ""
+}
+
+func (x *VaultProviderConfiguration) GetHttpClient() *http.ClientConfiguration {
+	if x != nil {
+		return x.HttpClient
+	}
+	return nil
+}
+
+type GCPSecretManagerProviderConfiguration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Project    string                    `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	HttpClient *http.ClientConfiguration `protobuf:"bytes,2,opt,name=http_client,json=httpClient,proto3" json:"http_client,omitempty"`
+}
+
+func (x *GCPSecretManagerProviderConfiguration) Reset() {
+	*x = GCPSecretManagerProviderConfiguration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_configuration_secrets_secrets_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GCPSecretManagerProviderConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GCPSecretManagerProviderConfiguration) ProtoMessage() {}
+
+func (x *GCPSecretManagerProviderConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_configuration_secrets_secrets_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GCPSecretManagerProviderConfiguration.ProtoReflect.Descriptor instead.
+func (*GCPSecretManagerProviderConfiguration) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_secrets_secrets_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GCPSecretManagerProviderConfiguration) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *GCPSecretManagerProviderConfiguration) GetHttpClient() *http.ClientConfiguration {
+	if x != nil {
+		return x.HttpClient
+	}
+	return nil
+}
+
+var File_pkg_proto_configuration_secrets_secrets_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_configuration_secrets_secrets_proto_rawDesc = []byte{
+	0x0a, 0x2d, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x73, 0x2f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x1f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73,
+	0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x27, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x68, 0x74, 0x74, 0x70, 0x2f, 0x68,
+	0x74, 0x74, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x89, 0x03, 0x0a, 0x15, 0x50, 0x72,
+	0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x56, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x69, 0x63, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x3c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x69, 0x63, 0x50, 0x72, 0x6f, 0x76,
+	0x69, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x69, 0x63, 0x12, 0x53, 0x0a, 0x05, 0x76,
+	0x61, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3b, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x2e, 0x56, 0x61, 0x75,
+	0x6c, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x05, 0x76, 0x61, 0x75, 0x6c, 0x74,
+	0x12, 0x76, 0x0a, 0x12, 0x67, 0x63, 0x70, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x5f, 0x6d,
+	0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x46, 0x2e, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x2e, 0x47,
+	0x43, 0x50, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x10, 0x67, 0x63, 0x70, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x12, 0x40, 0x0a, 0x0e, 0x63, 0x61, 0x63, 0x68,
+	0x65, 0x5f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x63, 0x61, 0x63,
+	0x68, 0x65, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x09, 0x0a, 0x07, 0x62, 0x61,
+	0x63, 0x6b, 0x65, 0x6e, 0x64, 0x22, 0xbe, 0x01, 0x0a, 0x1b, 0x53, 0x74, 0x61, 0x74, 0x69, 0x63,
+	0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x63, 0x0a, 0x07, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x49, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61,
+	0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x69, 0x63, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x07, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x1a, 0x3a, 0x0a, 0x0c, 0x53, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xc8, 0x01, 0x0a, 0x1a, 0x56, 0x61, 0x75, 0x6c, 0x74,
+	0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12,
+	0x1d, 0x0a, 0x0a, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1d,
+	0x0a, 0x0a, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x12, 0x52, 0x0a,
+	0x0b, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x31, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x68, 0x74, 0x74,
+	0x70, 0x2e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x68, 0x74, 0x74, 0x70, 0x43, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x22, 0x95, 0x01, 0x0a, 0x25, 0x47, 0x43, 0x50, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4d,
+	0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x70,
+	0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x52, 0x0a, 0x0b, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x63, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x68, 0x74, 0x74, 0x70, 0x2e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x68,
+	0x74, 0x74, 0x70, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x42, 0x4a, 0x5a, 0x48, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72,
+	0x6e, 0x2f, 0x62, 0x62, 0x2d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2d, 0x65, 0x78, 0x65, 0x63,
+	0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x73, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_configuration_secrets_secrets_proto_rawDescOnce sync.Once
+	file_pkg_proto_configuration_secrets_secrets_proto_rawDescData = file_pkg_proto_configuration_secrets_secrets_proto_rawDesc
+)
+
+func file_pkg_proto_configuration_secrets_secrets_proto_rawDescGZIP() []byte {
+	file_pkg_proto_configuration_secrets_secrets_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_configuration_secrets_secrets_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_configuration_secrets_secrets_proto_rawDescData)
+	})
+	return file_pkg_proto_configuration_secrets_secrets_proto_rawDescData
+}
+
+var file_pkg_proto_configuration_secrets_secrets_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_pkg_proto_configuration_secrets_secrets_proto_goTypes = []interface{}{
+	(*ProviderConfiguration)(nil),                 // 0: buildbarn.configuration.secrets.ProviderConfiguration
+	(*StaticProviderConfiguration)(nil),           // 1: buildbarn.configuration.secrets.StaticProviderConfiguration
+	(*VaultProviderConfiguration)(nil),            // 2: buildbarn.configuration.secrets.VaultProviderConfiguration
+	(*GCPSecretManagerProviderConfiguration)(nil), // 3: buildbarn.configuration.secrets.GCPSecretManagerProviderConfiguration
+	nil,                              // 4: buildbarn.configuration.secrets.StaticProviderConfiguration.SecretsEntry
+	(*durationpb.Duration)(nil),      // 5: google.protobuf.Duration
+	(*http.ClientConfiguration)(nil), // 6: buildbarn.configuration.http.ClientConfiguration
+}
+var file_pkg_proto_configuration_secrets_secrets_proto_depIdxs = []int32{
+	1, // 0: buildbarn.configuration.secrets.ProviderConfiguration.static:type_name -> buildbarn.configuration.secrets.StaticProviderConfiguration
+	2, // 1: buildbarn.configuration.secrets.ProviderConfiguration.vault:type_name -> buildbarn.configuration.secrets.VaultProviderConfiguration
+	3, // 2: buildbarn.configuration.secrets.ProviderConfiguration.gcp_secret_manager:type_name -> buildbarn.configuration.secrets.GCPSecretManagerProviderConfiguration
+	5, // 3: buildbarn.configuration.secrets.ProviderConfiguration.cache_duration:type_name -> google.protobuf.Duration
+	4, // 4: buildbarn.configuration.secrets.StaticProviderConfiguration.secrets:type_name -> buildbarn.configuration.secrets.StaticProviderConfiguration.SecretsEntry
+	6, // 5: buildbarn.configuration.secrets.VaultProviderConfiguration.http_client:type_name -> buildbarn.configuration.http.ClientConfiguration
+	6, // 6: buildbarn.configuration.secrets.GCPSecretManagerProviderConfiguration.http_client:type_name -> buildbarn.configuration.http.ClientConfiguration
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_configuration_secrets_secrets_proto_init() }
+func file_pkg_proto_configuration_secrets_secrets_proto_init() {
+	if File_pkg_proto_configuration_secrets_secrets_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_proto_configuration_secrets_secrets_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProviderConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_configuration_secrets_secrets_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StaticProviderConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_configuration_secrets_secrets_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VaultProviderConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_configuration_secrets_secrets_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GCPSecretManagerProviderConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_pkg_proto_configuration_secrets_secrets_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*ProviderConfiguration_Static)(nil),
+		(*ProviderConfiguration_Vault)(nil),
+		(*ProviderConfiguration_GcpSecretManager)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_proto_configuration_secrets_secrets_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pkg_proto_configuration_secrets_secrets_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_configuration_secrets_secrets_proto_depIdxs,
+		MessageInfos:      file_pkg_proto_configuration_secrets_secrets_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_configuration_secrets_secrets_proto = out.File
+	file_pkg_proto_configuration_secrets_secrets_proto_rawDesc = nil
+	file_pkg_proto_configuration_secrets_secrets_proto_goTypes = nil
+	file_pkg_proto_configuration_secrets_secrets_proto_depIdxs = nil
+}
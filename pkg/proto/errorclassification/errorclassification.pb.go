@@ -0,0 +1,226 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        v4.23.4
+// source: pkg/proto/errorclassification/errorclassification.proto
+
+package errorclassification
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Domain int32
+
+const (
+	Domain_UNKNOWN        Domain = 0
+	Domain_INPUT_FETCH    Domain = 1
+	Domain_SANDBOX_SETUP  Domain = 2
+	Domain_EXECUTION      Domain = 3
+	Domain_OUTPUT_UPLOAD  Domain = 4
+	Domain_INFRASTRUCTURE Domain = 5
+	Domain_RESOURCE_LIMIT Domain = 6
+)
+
+// Enum value maps for Domain.
+var (
+	Domain_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "INPUT_FETCH",
+		2: "SANDBOX_SETUP",
+		3: "EXECUTION",
+		4: "OUTPUT_UPLOAD",
+		5: "INFRASTRUCTURE",
+		6: "RESOURCE_LIMIT",
+	}
+	Domain_value = map[string]int32{
+		"UNKNOWN":        0,
+		"INPUT_FETCH":    1,
+		"SANDBOX_SETUP":  2,
+		"EXECUTION":      3,
+		"OUTPUT_UPLOAD":  4,
+		"INFRASTRUCTURE": 5,
+		"RESOURCE_LIMIT": 6,
+	}
+)
+
+func (x Domain) Enum() *Domain {
+	p := new(Domain)
+	*p = x
+	return p
+}
+
+func (x Domain) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Domain) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_errorclassification_errorclassification_proto_enumTypes[0].Descriptor()
+}
+
+func (Domain) Type() protoreflect.EnumType {
+	return &file_pkg_proto_errorclassification_errorclassification_proto_enumTypes[0]
+}
+
+func (x Domain) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Domain.Descriptor instead.
+func (Domain) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_errorclassification_errorclassification_proto_rawDescGZIP(), []int{0}
+}
+
+type ErrorClassification struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Domain Domain `protobuf:"varint,1,opt,name=domain,proto3,enum=buildbarn.errorclassification.Domain" json:"domain,omitempty"`
+}
+
+func (x *ErrorClassification) Reset() {
+	*x = ErrorClassification{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_errorclassification_errorclassification_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ErrorClassification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorClassification) ProtoMessage() {}
+
+func (x *ErrorClassification) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_errorclassification_errorclassification_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorClassification.ProtoReflect.Descriptor instead.
+func (*ErrorClassification) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_errorclassification_errorclassification_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ErrorClassification) GetDomain() Domain {
+	if x != nil {
+		return x.Domain
+	}
+	return Domain_UNKNOWN
+}
+
+var File_pkg_proto_errorclassification_errorclassification_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_errorclassification_errorclassification_proto_rawDesc = []byte{
+	0x0a, 0x37, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1d, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2e, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x54, 0x0a, 0x13, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x3d, 0x0a, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x25, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x2a, 0x83,
+	0x01, 0x0a, 0x06, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b,
+	0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x49, 0x4e, 0x50, 0x55, 0x54, 0x5f,
+	0x46, 0x45, 0x54, 0x43, 0x48, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x41, 0x4e, 0x44, 0x42,
+	0x4f, 0x58, 0x5f, 0x53, 0x45, 0x54, 0x55, 0x50, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x45, 0x58,
+	0x45, 0x43, 0x55, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x03, 0x12, 0x11, 0x0a, 0x0d, 0x4f, 0x55, 0x54,
+	0x50, 0x55, 0x54, 0x5f, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x10, 0x04, 0x12, 0x12, 0x0a, 0x0e,
+	0x49, 0x4e, 0x46, 0x52, 0x41, 0x53, 0x54, 0x52, 0x55, 0x43, 0x54, 0x55, 0x52, 0x45, 0x10, 0x05,
+	0x12, 0x12, 0x0a, 0x0e, 0x52, 0x45, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x5f, 0x4c, 0x49, 0x4d,
+	0x49, 0x54, 0x10, 0x06, 0x42, 0x48, 0x5a, 0x46, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2f, 0x62, 0x62, 0x2d,
+	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2d, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e,
+	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x63, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_errorclassification_errorclassification_proto_rawDescOnce sync.Once
+	file_pkg_proto_errorclassification_errorclassification_proto_rawDescData = file_pkg_proto_errorclassification_errorclassification_proto_rawDesc
+)
+
+func file_pkg_proto_errorclassification_errorclassification_proto_rawDescGZIP() []byte {
+	file_pkg_proto_errorclassification_errorclassification_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_errorclassification_errorclassification_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_errorclassification_errorclassification_proto_rawDescData)
+	})
+	return file_pkg_proto_errorclassification_errorclassification_proto_rawDescData
+}
+
+var file_pkg_proto_errorclassification_errorclassification_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_proto_errorclassification_errorclassification_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_pkg_proto_errorclassification_errorclassification_proto_goTypes = []interface{}{
+	(Domain)(0),                 // 0: buildbarn.errorclassification.Domain
+	(*ErrorClassification)(nil), // 1: buildbarn.errorclassification.ErrorClassification
+}
+var file_pkg_proto_errorclassification_errorclassification_proto_depIdxs = []int32{
+	0, // 0: buildbarn.errorclassification.ErrorClassification.domain:type_name -> buildbarn.errorclassification.Domain
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_errorclassification_errorclassification_proto_init() }
+func file_pkg_proto_errorclassification_errorclassification_proto_init() {
+	if File_pkg_proto_errorclassification_errorclassification_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_proto_errorclassification_errorclassification_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ErrorClassification); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_proto_errorclassification_errorclassification_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pkg_proto_errorclassification_errorclassification_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_errorclassification_errorclassification_proto_depIdxs,
+		EnumInfos:         file_pkg_proto_errorclassification_errorclassification_proto_enumTypes,
+		MessageInfos:      file_pkg_proto_errorclassification_errorclassification_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_errorclassification_errorclassification_proto = out.File
+	file_pkg_proto_errorclassification_errorclassification_proto_rawDesc = nil
+	file_pkg_proto_errorclassification_errorclassification_proto_goTypes = nil
+	file_pkg_proto_errorclassification_errorclassification_proto_depIdxs = nil
+}
@@ -0,0 +1,240 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        v4.23.4
+// source: pkg/proto/hermeticity/hermeticity.proto
+
+package hermeticity
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type HermeticityReport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EnvironmentVariableOverrides []*HermeticityReport_EnvironmentVariableOverride `protobuf:"bytes,1,rep,name=environment_variable_overrides,json=environmentVariableOverrides,proto3" json:"environment_variable_overrides,omitempty"`
+}
+
+func (x *HermeticityReport) Reset() {
+	*x = HermeticityReport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_hermeticity_hermeticity_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HermeticityReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HermeticityReport) ProtoMessage() {}
+
+func (x *HermeticityReport) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_hermeticity_hermeticity_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HermeticityReport.ProtoReflect.Descriptor instead.
+func (*HermeticityReport) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_hermeticity_hermeticity_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HermeticityReport) GetEnvironmentVariableOverrides() []*HermeticityReport_EnvironmentVariableOverride {
+	if x != nil {
+		return x.EnvironmentVariableOverrides
+	}
+	return nil
+}
+
+type HermeticityReport_EnvironmentVariableOverride struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	WorkerValue string `protobuf:"bytes,2,opt,name=worker_value,json=workerValue,proto3" json:"worker_value,omitempty"`
+	ActionValue string `protobuf:"bytes,3,opt,name=action_value,json=actionValue,proto3" json:"action_value,omitempty"`
+}
+
+func (x *HermeticityReport_EnvironmentVariableOverride) Reset() {
+	*x = HermeticityReport_EnvironmentVariableOverride{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_hermeticity_hermeticity_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HermeticityReport_EnvironmentVariableOverride) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HermeticityReport_EnvironmentVariableOverride) ProtoMessage() {}
+
+func (x *HermeticityReport_EnvironmentVariableOverride) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_hermeticity_hermeticity_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HermeticityReport_EnvironmentVariableOverride.ProtoReflect.Descriptor instead.
+func (*HermeticityReport_EnvironmentVariableOverride) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_hermeticity_hermeticity_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *HermeticityReport_EnvironmentVariableOverride) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *HermeticityReport_EnvironmentVariableOverride) GetWorkerValue() string {
+	if x != nil {
+		return x.WorkerValue
+	}
+	return ""
+}
+
+func (x *HermeticityReport_EnvironmentVariableOverride) GetActionValue() string {
+	if x != nil {
+		return x.ActionValue
+	}
+	return ""
+}
+
+var File_pkg_proto_hermeticity_hermeticity_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_hermeticity_hermeticity_proto_rawDesc = []byte{
+	0x0a, 0x27, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x68, 0x65, 0x72, 0x6d,
+	0x65, 0x74, 0x69, 0x63, 0x69, 0x74, 0x79, 0x2f, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x74, 0x69, 0x63,
+	0x69, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x15, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2e, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x74, 0x69, 0x63, 0x69, 0x74, 0x79,
+	0x22, 0x99, 0x02, 0x0a, 0x11, 0x48, 0x65, 0x72, 0x6d, 0x65, 0x74, 0x69, 0x63, 0x69, 0x74, 0x79,
+	0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x8a, 0x01, 0x0a, 0x1e, 0x65, 0x6e, 0x76, 0x69, 0x72,
+	0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x5f,
+	0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x44, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x68, 0x65, 0x72, 0x6d,
+	0x65, 0x74, 0x69, 0x63, 0x69, 0x74, 0x79, 0x2e, 0x48, 0x65, 0x72, 0x6d, 0x65, 0x74, 0x69, 0x63,
+	0x69, 0x74, 0x79, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x45, 0x6e, 0x76, 0x69, 0x72, 0x6f,
+	0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x4f, 0x76, 0x65,
+	0x72, 0x72, 0x69, 0x64, 0x65, 0x52, 0x1c, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65,
+	0x6e, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69,
+	0x64, 0x65, 0x73, 0x1a, 0x77, 0x0a, 0x1b, 0x45, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65,
+	0x6e, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69,
+	0x64, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72,
+	0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x77, 0x6f,
+	0x72, 0x6b, 0x65, 0x72, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x40, 0x5a, 0x3e,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2f, 0x62, 0x62, 0x2d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2d, 0x65,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2f, 0x68, 0x65, 0x72, 0x6d, 0x65, 0x74, 0x69, 0x63, 0x69, 0x74, 0x79, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_hermeticity_hermeticity_proto_rawDescOnce sync.Once
+	file_pkg_proto_hermeticity_hermeticity_proto_rawDescData = file_pkg_proto_hermeticity_hermeticity_proto_rawDesc
+)
+
+func file_pkg_proto_hermeticity_hermeticity_proto_rawDescGZIP() []byte {
+	file_pkg_proto_hermeticity_hermeticity_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_hermeticity_hermeticity_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_hermeticity_hermeticity_proto_rawDescData)
+	})
+	return file_pkg_proto_hermeticity_hermeticity_proto_rawDescData
+}
+
+var file_pkg_proto_hermeticity_hermeticity_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_pkg_proto_hermeticity_hermeticity_proto_goTypes = []interface{}{
+	(*HermeticityReport)(nil),                             // 0: buildbarn.hermeticity.HermeticityReport
+	(*HermeticityReport_EnvironmentVariableOverride)(nil), // 1: buildbarn.hermeticity.HermeticityReport.EnvironmentVariableOverride
+}
+var file_pkg_proto_hermeticity_hermeticity_proto_depIdxs = []int32{
+	1, // 0: buildbarn.hermeticity.HermeticityReport.environment_variable_overrides:type_name -> buildbarn.hermeticity.HermeticityReport.EnvironmentVariableOverride
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_hermeticity_hermeticity_proto_init() }
+func file_pkg_proto_hermeticity_hermeticity_proto_init() {
+	if File_pkg_proto_hermeticity_hermeticity_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_proto_hermeticity_hermeticity_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HermeticityReport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_hermeticity_hermeticity_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HermeticityReport_EnvironmentVariableOverride); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_proto_hermeticity_hermeticity_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pkg_proto_hermeticity_hermeticity_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_hermeticity_hermeticity_proto_depIdxs,
+		MessageInfos:      file_pkg_proto_hermeticity_hermeticity_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_hermeticity_hermeticity_proto = out.File
+	file_pkg_proto_hermeticity_hermeticity_proto_rawDesc = nil
+	file_pkg_proto_hermeticity_hermeticity_proto_goTypes = nil
+	file_pkg_proto_hermeticity_hermeticity_proto_depIdxs = nil
+}
@@ -0,0 +1,420 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        v4.23.4
+// source: pkg/proto/initialsizeclasscache/initialsizeclasscache.proto
+
+package initialsizeclasscache
+
+import (
+	context "context"
+	v2 "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	iscc "github.com/buildbarn/bb-storage/pkg/proto/iscc"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetPreviousExecutionStatsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InstanceName   string                  `protobuf:"bytes,1,opt,name=instance_name,json=instanceName,proto3" json:"instance_name,omitempty"`
+	DigestFunction v2.DigestFunction_Value `protobuf:"varint,2,opt,name=digest_function,json=digestFunction,proto3,enum=build.bazel.remote.execution.v2.DigestFunction_Value" json:"digest_function,omitempty"`
+	// Types that are assignable to Digest:
+	//
+	//	*GetPreviousExecutionStatsRequest_ActionDigest
+	//	*GetPreviousExecutionStatsRequest_ReducedActionDigest
+	Digest isGetPreviousExecutionStatsRequest_Digest `protobuf_oneof:"digest"`
+}
+
+func (x *GetPreviousExecutionStatsRequest) Reset() {
+	*x = GetPreviousExecutionStatsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPreviousExecutionStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPreviousExecutionStatsRequest) ProtoMessage() {}
+
+func (x *GetPreviousExecutionStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPreviousExecutionStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetPreviousExecutionStatsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetPreviousExecutionStatsRequest) GetInstanceName() string {
+	if x != nil {
+		return x.InstanceName
+	}
+	return ""
+}
+
+func (x *GetPreviousExecutionStatsRequest) GetDigestFunction() v2.DigestFunction_Value {
+	if x != nil {
+		return x.DigestFunction
+	}
+	return v2.DigestFunction_Value(0)
+}
+
+func (m *GetPreviousExecutionStatsRequest) GetDigest() isGetPreviousExecutionStatsRequest_Digest {
+	if m != nil {
+		return m.Digest
+	}
+	return nil
+}
+
+func (x *GetPreviousExecutionStatsRequest) GetActionDigest() *v2.Digest {
+	if x, ok := x.GetDigest().(*GetPreviousExecutionStatsRequest_ActionDigest); ok {
+		return x.ActionDigest
+	}
+	return nil
+}
+
+func (x *GetPreviousExecutionStatsRequest) GetReducedActionDigest() *v2.Digest {
+	if x, ok := x.GetDigest().(*GetPreviousExecutionStatsRequest_ReducedActionDigest); ok {
+		return x.ReducedActionDigest
+	}
+	return nil
+}
+
+type isGetPreviousExecutionStatsRequest_Digest interface {
+	isGetPreviousExecutionStatsRequest_Digest()
+}
+
+type GetPreviousExecutionStatsRequest_ActionDigest struct {
+	ActionDigest *v2.Digest `protobuf:"bytes,3,opt,name=action_digest,json=actionDigest,proto3,oneof"`
+}
+
+type GetPreviousExecutionStatsRequest_ReducedActionDigest struct {
+	ReducedActionDigest *v2.Digest `protobuf:"bytes,4,opt,name=reduced_action_digest,json=reducedActionDigest,proto3,oneof"`
+}
+
+func (*GetPreviousExecutionStatsRequest_ActionDigest) isGetPreviousExecutionStatsRequest_Digest() {}
+
+func (*GetPreviousExecutionStatsRequest_ReducedActionDigest) isGetPreviousExecutionStatsRequest_Digest() {
+}
+
+type GetPreviousExecutionStatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReducedActionDigest    *v2.Digest                   `protobuf:"bytes,1,opt,name=reduced_action_digest,json=reducedActionDigest,proto3" json:"reduced_action_digest,omitempty"`
+	PreviousExecutionStats *iscc.PreviousExecutionStats `protobuf:"bytes,2,opt,name=previous_execution_stats,json=previousExecutionStats,proto3" json:"previous_execution_stats,omitempty"`
+}
+
+func (x *GetPreviousExecutionStatsResponse) Reset() {
+	*x = GetPreviousExecutionStatsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPreviousExecutionStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPreviousExecutionStatsResponse) ProtoMessage() {}
+
+func (x *GetPreviousExecutionStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPreviousExecutionStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetPreviousExecutionStatsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetPreviousExecutionStatsResponse) GetReducedActionDigest() *v2.Digest {
+	if x != nil {
+		return x.ReducedActionDigest
+	}
+	return nil
+}
+
+func (x *GetPreviousExecutionStatsResponse) GetPreviousExecutionStats() *iscc.PreviousExecutionStats {
+	if x != nil {
+		return x.PreviousExecutionStats
+	}
+	return nil
+}
+
+var File_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_rawDesc = []byte{
+	0x0a, 0x3b, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x69, 0x6e, 0x69, 0x74,
+	0x69, 0x61, 0x6c, 0x73, 0x69, 0x7a, 0x65, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x63, 0x61, 0x63, 0x68,
+	0x65, 0x2f, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x69, 0x7a, 0x65, 0x63, 0x6c, 0x61,
+	0x73, 0x73, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1f, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c,
+	0x73, 0x69, 0x7a, 0x65, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x63, 0x61, 0x63, 0x68, 0x65, 0x1a, 0x36,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x2f, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2f, 0x72, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x2f, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x76, 0x32, 0x2f,
+	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x19, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2f, 0x69, 0x73, 0x63, 0x63, 0x2f, 0x69, 0x73, 0x63, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0xe0, 0x02, 0x0a, 0x20, 0x47, 0x65, 0x74, 0x50, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75,
+	0x73, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x5e, 0x0a, 0x0f, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x35, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a,
+	0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0e, 0x64, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x4e, 0x0a, 0x0d, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c,
+	0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f,
+	0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x0c, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x5d, 0x0a, 0x15, 0x72,
+	0x65, 0x64, 0x75, 0x63, 0x65, 0x64, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e,
+	0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x13, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x64, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x42, 0x08, 0x0a, 0x06, 0x64, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x22, 0xe2, 0x01, 0x0a, 0x21, 0x47, 0x65, 0x74, 0x50, 0x72, 0x65, 0x76,
+	0x69, 0x6f, 0x75, 0x73, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5b, 0x0a, 0x15, 0x72, 0x65,
+	0x64, 0x75, 0x63, 0x65, 0x64, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x52, 0x13, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x64, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x60, 0x0a, 0x18, 0x70, 0x72, 0x65, 0x76, 0x69,
+	0x6f, 0x75, 0x73, 0x5f, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x74,
+	0x61, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x69, 0x73, 0x63, 0x63, 0x2e, 0x50, 0x72, 0x65, 0x76, 0x69,
+	0x6f, 0x75, 0x73, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x52, 0x16, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x45, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x32, 0xbc, 0x01, 0x0a, 0x15, 0x49, 0x6e,
+	0x69, 0x74, 0x69, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x43, 0x61,
+	0x63, 0x68, 0x65, 0x12, 0xa2, 0x01, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x50, 0x72, 0x65, 0x76, 0x69,
+	0x6f, 0x75, 0x73, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x12, 0x41, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x69, 0x6e,
+	0x69, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x69, 0x7a, 0x65, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x63, 0x61,
+	0x63, 0x68, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x45,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x42, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e,
+	0x2e, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x69, 0x7a, 0x65, 0x63, 0x6c, 0x61, 0x73,
+	0x73, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x65, 0x76, 0x69, 0x6f,
+	0x75, 0x73, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x4a, 0x5a, 0x48, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e,
+	0x2f, 0x62, 0x62, 0x2d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2d, 0x65, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x69,
+	0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x69, 0x7a, 0x65, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x63,
+	0x61, 0x63, 0x68, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_rawDescOnce sync.Once
+	file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_rawDescData = file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_rawDesc
+)
+
+func file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_rawDescGZIP() []byte {
+	file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_rawDescData)
+	})
+	return file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_rawDescData
+}
+
+var file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_goTypes = []interface{}{
+	(*GetPreviousExecutionStatsRequest)(nil),  // 0: buildbarn.initialsizeclasscache.GetPreviousExecutionStatsRequest
+	(*GetPreviousExecutionStatsResponse)(nil), // 1: buildbarn.initialsizeclasscache.GetPreviousExecutionStatsResponse
+	(v2.DigestFunction_Value)(0),              // 2: build.bazel.remote.execution.v2.DigestFunction.Value
+	(*v2.Digest)(nil),                         // 3: build.bazel.remote.execution.v2.Digest
+	(*iscc.PreviousExecutionStats)(nil),       // 4: buildbarn.iscc.PreviousExecutionStats
+}
+var file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_depIdxs = []int32{
+	2, // 0: buildbarn.initialsizeclasscache.GetPreviousExecutionStatsRequest.digest_function:type_name -> build.bazel.remote.execution.v2.DigestFunction.Value
+	3, // 1: buildbarn.initialsizeclasscache.GetPreviousExecutionStatsRequest.action_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	3, // 2: buildbarn.initialsizeclasscache.GetPreviousExecutionStatsRequest.reduced_action_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	3, // 3: buildbarn.initialsizeclasscache.GetPreviousExecutionStatsResponse.reduced_action_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	4, // 4: buildbarn.initialsizeclasscache.GetPreviousExecutionStatsResponse.previous_execution_stats:type_name -> buildbarn.iscc.PreviousExecutionStats
+	0, // 5: buildbarn.initialsizeclasscache.InitialSizeClassCache.GetPreviousExecutionStats:input_type -> buildbarn.initialsizeclasscache.GetPreviousExecutionStatsRequest
+	1, // 6: buildbarn.initialsizeclasscache.InitialSizeClassCache.GetPreviousExecutionStats:output_type -> buildbarn.initialsizeclasscache.GetPreviousExecutionStatsResponse
+	6, // [6:7] is the sub-list for method output_type
+	5, // [5:6] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_init() }
+func file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_init() {
+	if File_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPreviousExecutionStatsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPreviousExecutionStatsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*GetPreviousExecutionStatsRequest_ActionDigest)(nil),
+		(*GetPreviousExecutionStatsRequest_ReducedActionDigest)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_depIdxs,
+		MessageInfos:      file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto = out.File
+	file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_rawDesc = nil
+	file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_goTypes = nil
+	file_pkg_proto_initialsizeclasscache_initialsizeclasscache_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// InitialSizeClassCacheClient is the client API for InitialSizeClassCache service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type InitialSizeClassCacheClient interface {
+	GetPreviousExecutionStats(ctx context.Context, in *GetPreviousExecutionStatsRequest, opts ...grpc.CallOption) (*GetPreviousExecutionStatsResponse, error)
+}
+
+type initialSizeClassCacheClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInitialSizeClassCacheClient(cc grpc.ClientConnInterface) InitialSizeClassCacheClient {
+	return &initialSizeClassCacheClient{cc}
+}
+
+func (c *initialSizeClassCacheClient) GetPreviousExecutionStats(ctx context.Context, in *GetPreviousExecutionStatsRequest, opts ...grpc.CallOption) (*GetPreviousExecutionStatsResponse, error) {
+	out := new(GetPreviousExecutionStatsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.initialsizeclasscache.InitialSizeClassCache/GetPreviousExecutionStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InitialSizeClassCacheServer is the server API for InitialSizeClassCache service.
+type InitialSizeClassCacheServer interface {
+	GetPreviousExecutionStats(context.Context, *GetPreviousExecutionStatsRequest) (*GetPreviousExecutionStatsResponse, error)
+}
+
+// UnimplementedInitialSizeClassCacheServer can be embedded to have forward compatible implementations.
+type UnimplementedInitialSizeClassCacheServer struct {
+}
+
+func (*UnimplementedInitialSizeClassCacheServer) GetPreviousExecutionStats(context.Context, *GetPreviousExecutionStatsRequest) (*GetPreviousExecutionStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPreviousExecutionStats not implemented")
+}
+
+func RegisterInitialSizeClassCacheServer(s grpc.ServiceRegistrar, srv InitialSizeClassCacheServer) {
+	s.RegisterService(&_InitialSizeClassCache_serviceDesc, srv)
+}
+
+func _InitialSizeClassCache_GetPreviousExecutionStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPreviousExecutionStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InitialSizeClassCacheServer).GetPreviousExecutionStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.initialsizeclasscache.InitialSizeClassCache/GetPreviousExecutionStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InitialSizeClassCacheServer).GetPreviousExecutionStats(ctx, req.(*GetPreviousExecutionStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _InitialSizeClassCache_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "buildbarn.initialsizeclasscache.InitialSizeClassCache",
+	HandlerType: (*InitialSizeClassCacheServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPreviousExecutionStats",
+			Handler:    _InitialSizeClassCache_GetPreviousExecutionStats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/proto/initialsizeclasscache/initialsizeclasscache.proto",
+}
@@ -7,6 +7,8 @@
 package resourceusage
 
 import (
+	v2 "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	status "google.golang.org/genproto/googleapis/rpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	durationpb "google.golang.org/protobuf/types/known/durationpb"
@@ -26,14 +28,17 @@ type FilePoolResourceUsage struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	FilesCreated       uint64 `protobuf:"varint,1,opt,name=files_created,json=filesCreated,proto3" json:"files_created,omitempty"`
-	FilesCountPeak     uint64 `protobuf:"varint,2,opt,name=files_count_peak,json=filesCountPeak,proto3" json:"files_count_peak,omitempty"`
-	FilesSizeBytesPeak uint64 `protobuf:"varint,3,opt,name=files_size_bytes_peak,json=filesSizeBytesPeak,proto3" json:"files_size_bytes_peak,omitempty"`
-	ReadsCount         uint64 `protobuf:"varint,4,opt,name=reads_count,json=readsCount,proto3" json:"reads_count,omitempty"`
-	ReadsSizeBytes     uint64 `protobuf:"varint,5,opt,name=reads_size_bytes,json=readsSizeBytes,proto3" json:"reads_size_bytes,omitempty"`
-	WritesCount        uint64 `protobuf:"varint,6,opt,name=writes_count,json=writesCount,proto3" json:"writes_count,omitempty"`
-	WritesSizeBytes    uint64 `protobuf:"varint,7,opt,name=writes_size_bytes,json=writesSizeBytes,proto3" json:"writes_size_bytes,omitempty"`
-	TruncatesCount     uint64 `protobuf:"varint,8,opt,name=truncates_count,json=truncatesCount,proto3" json:"truncates_count,omitempty"`
+	FilesCreated                uint64 `protobuf:"varint,1,opt,name=files_created,json=filesCreated,proto3" json:"files_created,omitempty"`
+	FilesCountPeak              uint64 `protobuf:"varint,2,opt,name=files_count_peak,json=filesCountPeak,proto3" json:"files_count_peak,omitempty"`
+	FilesSizeBytesPeak          uint64 `protobuf:"varint,3,opt,name=files_size_bytes_peak,json=filesSizeBytesPeak,proto3" json:"files_size_bytes_peak,omitempty"`
+	ReadsCount                  uint64 `protobuf:"varint,4,opt,name=reads_count,json=readsCount,proto3" json:"reads_count,omitempty"`
+	ReadsSizeBytes              uint64 `protobuf:"varint,5,opt,name=reads_size_bytes,json=readsSizeBytes,proto3" json:"reads_size_bytes,omitempty"`
+	WritesCount                 uint64 `protobuf:"varint,6,opt,name=writes_count,json=writesCount,proto3" json:"writes_count,omitempty"`
+	WritesSizeBytes             uint64 `protobuf:"varint,7,opt,name=writes_size_bytes,json=writesSizeBytes,proto3" json:"writes_size_bytes,omitempty"`
+	TruncatesCount              uint64 `protobuf:"varint,8,opt,name=truncates_count,json=truncatesCount,proto3" json:"truncates_count,omitempty"`
+	DeallocatesCount            uint64 `protobuf:"varint,9,opt,name=deallocates_count,json=deallocatesCount,proto3" json:"deallocates_count,omitempty"`
+	DeallocatesSizeBytes        uint64 `protobuf:"varint,10,opt,name=deallocates_size_bytes,json=deallocatesSizeBytes,proto3" json:"deallocates_size_bytes,omitempty"`
+	FilesAllocatedSizeBytesPeak uint64 `protobuf:"varint,11,opt,name=files_allocated_size_bytes_peak,json=filesAllocatedSizeBytesPeak,proto3" json:"files_allocated_size_bytes_peak,omitempty"`
 }
 
 func (x *FilePoolResourceUsage) Reset() {
@@ -124,6 +129,27 @@ func (x *FilePoolResourceUsage) GetTruncatesCount() uint64 {
 	return 0
 }
 
+func (x *FilePoolResourceUsage) GetDeallocatesCount() uint64 {
+	if x != nil {
+		return x.DeallocatesCount
+	}
+	return 0
+}
+
+func (x *FilePoolResourceUsage) GetDeallocatesSizeBytes() uint64 {
+	if x != nil {
+		return x.DeallocatesSizeBytes
+	}
+	return 0
+}
+
+func (x *FilePoolResourceUsage) GetFilesAllocatedSizeBytesPeak() uint64 {
+	if x != nil {
+		return x.FilesAllocatedSizeBytesPeak
+	}
+	return 0
+}
+
 type POSIXResourceUsage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -385,17 +411,17 @@ func (x *InputRootResourceUsage) GetFilesRead() uint64 {
 	return 0
 }
 
-type MonetaryResourceUsage_Expense struct {
+type InputRootReadFiles struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Currency string  `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
-	Cost     float64 `protobuf:"fixed64,2,opt,name=cost,proto3" json:"cost,omitempty"`
+	PathsDigest *v2.Digest `protobuf:"bytes,1,opt,name=paths_digest,json=pathsDigest,proto3" json:"paths_digest,omitempty"`
+	PathsCount  uint64     `protobuf:"varint,2,opt,name=paths_count,json=pathsCount,proto3" json:"paths_count,omitempty"`
 }
 
-func (x *MonetaryResourceUsage_Expense) Reset() {
-	*x = MonetaryResourceUsage_Expense{}
+func (x *InputRootReadFiles) Reset() {
+	*x = InputRootReadFiles{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -403,13 +429,13 @@ func (x *MonetaryResourceUsage_Expense) Reset() {
 	}
 }
 
-func (x *MonetaryResourceUsage_Expense) String() string {
+func (x *InputRootReadFiles) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MonetaryResourceUsage_Expense) ProtoMessage() {}
+func (*InputRootReadFiles) ProtoMessage() {}
 
-func (x *MonetaryResourceUsage_Expense) ProtoReflect() protoreflect.Message {
+func (x *InputRootReadFiles) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -421,225 +447,2552 @@ func (x *MonetaryResourceUsage_Expense) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MonetaryResourceUsage_Expense.ProtoReflect.Descriptor instead.
-func (*MonetaryResourceUsage_Expense) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{2, 0}
+// Deprecated: Use InputRootReadFiles.ProtoReflect.Descriptor instead.
+func (*InputRootReadFiles) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *MonetaryResourceUsage_Expense) GetCurrency() string {
+func (x *InputRootReadFiles) GetPathsDigest() *v2.Digest {
 	if x != nil {
-		return x.Currency
+		return x.PathsDigest
 	}
-	return ""
+	return nil
 }
 
-func (x *MonetaryResourceUsage_Expense) GetCost() float64 {
+func (x *InputRootReadFiles) GetPathsCount() uint64 {
 	if x != nil {
-		return x.Cost
+		return x.PathsCount
 	}
 	return 0
 }
 
-var File_pkg_proto_resourceusage_resourceusage_proto protoreflect.FileDescriptor
+type MemoryResourceUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_pkg_proto_resourceusage_resourceusage_proto_rawDesc = []byte{
-	0x0a, 0x2b, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x65, 0x73, 0x6f,
-	0x75, 0x72, 0x63, 0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x2f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72,
-	0x63, 0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x17, 0x62,
-	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
-	0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xdc, 0x02, 0x0a, 0x15, 0x46, 0x69, 0x6c, 0x65, 0x50,
-	0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65,
-	0x12, 0x23, 0x0a, 0x0d, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x43, 0x72,
-	0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x28, 0x0a, 0x10, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f, 0x63,
-	0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x65, 0x61, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x0e, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x65, 0x61, 0x6b, 0x12,
-	0x31, 0x0a, 0x15, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79,
-	0x74, 0x65, 0x73, 0x5f, 0x70, 0x65, 0x61, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12,
-	0x66, 0x69, 0x6c, 0x65, 0x73, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50, 0x65,
-	0x61, 0x6b, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x61, 0x64, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
-	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x72, 0x65, 0x61, 0x64, 0x73, 0x43, 0x6f,
-	0x75, 0x6e, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x72, 0x65, 0x61, 0x64, 0x73, 0x5f, 0x73, 0x69, 0x7a,
-	0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x72,
-	0x65, 0x61, 0x64, 0x73, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x21, 0x0a,
-	0x0c, 0x77, 0x72, 0x69, 0x74, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x0b, 0x77, 0x72, 0x69, 0x74, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74,
-	0x12, 0x2a, 0x0a, 0x11, 0x77, 0x72, 0x69, 0x74, 0x65, 0x73, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f,
-	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x77, 0x72, 0x69,
-	0x74, 0x65, 0x73, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f,
-	0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18,
-	0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x73,
-	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xcb, 0x05, 0x0a, 0x12, 0x50, 0x4f, 0x53, 0x49, 0x58, 0x52,
-	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x36, 0x0a, 0x09,
-	0x75, 0x73, 0x65, 0x72, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72,
-	0x54, 0x69, 0x6d, 0x65, 0x12, 0x3a, 0x0a, 0x0b, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x74,
-	0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x54, 0x69, 0x6d, 0x65,
-	0x12, 0x39, 0x0a, 0x19, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x72, 0x65, 0x73, 0x69,
-	0x64, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x65, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x16, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x52, 0x65, 0x73, 0x69,
-	0x64, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x70,
-	0x61, 0x67, 0x65, 0x5f, 0x72, 0x65, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x73, 0x18, 0x07, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x0c, 0x70, 0x61, 0x67, 0x65, 0x52, 0x65, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x73,
-	0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x18,
-	0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x46, 0x61, 0x75, 0x6c, 0x74,
-	0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x77, 0x61, 0x70, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x05, 0x73, 0x77, 0x61, 0x70, 0x73, 0x12, 0x34, 0x0a, 0x16, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
-	0x5f, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x5f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x6e,
-	0x70, 0x75, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x36, 0x0a,
-	0x17, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x6f, 0x70,
-	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x15,
-	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x73, 0x5f, 0x73, 0x65, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x53, 0x65, 0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x6d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x18,
-	0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x52,
-	0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x73, 0x69, 0x67, 0x6e, 0x61,
-	0x6c, 0x73, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x0f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
-	0x65, 0x64, 0x12, 0x3c, 0x0a, 0x1a, 0x76, 0x6f, 0x6c, 0x75, 0x6e, 0x74, 0x61, 0x72, 0x79, 0x5f,
-	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x5f, 0x73, 0x77, 0x69, 0x74, 0x63, 0x68, 0x65, 0x73,
-	0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x18, 0x76, 0x6f, 0x6c, 0x75, 0x6e, 0x74, 0x61, 0x72,
-	0x79, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x53, 0x77, 0x69, 0x74, 0x63, 0x68, 0x65, 0x73,
-	0x12, 0x40, 0x0a, 0x1c, 0x69, 0x6e, 0x76, 0x6f, 0x6c, 0x75, 0x6e, 0x74, 0x61, 0x72, 0x79, 0x5f,
-	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x5f, 0x73, 0x77, 0x69, 0x74, 0x63, 0x68, 0x65, 0x73,
-	0x18, 0x10, 0x20, 0x01, 0x28, 0x03, 0x52, 0x1a, 0x69, 0x6e, 0x76, 0x6f, 0x6c, 0x75, 0x6e, 0x74,
-	0x61, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x53, 0x77, 0x69, 0x74, 0x63, 0x68,
-	0x65, 0x73, 0x12, 0x2d, 0x0a, 0x12, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11,
-	0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x6e, 0x61,
-	0x6c, 0x4a, 0x04, 0x08, 0x04, 0x10, 0x05, 0x4a, 0x04, 0x08, 0x05, 0x10, 0x06, 0x4a, 0x04, 0x08,
-	0x06, 0x10, 0x07, 0x22, 0xa1, 0x02, 0x0a, 0x15, 0x4d, 0x6f, 0x6e, 0x65, 0x74, 0x61, 0x72, 0x79,
-	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x58, 0x0a,
-	0x08, 0x65, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x3c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x72, 0x65, 0x73, 0x6f,
-	0x75, 0x72, 0x63, 0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x4d, 0x6f, 0x6e, 0x65, 0x74, 0x61,
-	0x72, 0x79, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x2e,
-	0x45, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x65,
-	0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x1a, 0x39, 0x0a, 0x07, 0x45, 0x78, 0x70, 0x65, 0x6e,
-	0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x12,
-	0x0a, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x63, 0x6f,
-	0x73, 0x74, 0x1a, 0x73, 0x0a, 0x0d, 0x45, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x45, 0x6e,
-	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x4c, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x36, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e,
-	0x2e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x4d,
-	0x6f, 0x6e, 0x65, 0x74, 0x61, 0x72, 0x79, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55,
-	0x73, 0x61, 0x67, 0x65, 0x2e, 0x45, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x52, 0x05, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x95, 0x01, 0x0a, 0x16, 0x49, 0x6e, 0x70, 0x75,
-	0x74, 0x52, 0x6f, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61,
-	0x67, 0x65, 0x12, 0x31, 0x0a, 0x14, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65,
-	0x73, 0x5f, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x13, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73,
-	0x6f, 0x6c, 0x76, 0x65, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f,
-	0x72, 0x69, 0x65, 0x73, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x0f, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x61, 0x64,
-	0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x61, 0x64, 0x42,
-	0x42, 0x5a, 0x40, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75,
-	0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2f, 0x62, 0x62, 0x2d, 0x72, 0x65, 0x6d, 0x6f, 0x74,
-	0x65, 0x2d, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x75, 0x73,
-	0x61, 0x67, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	MemoryPeakBytes int64 `protobuf:"varint,1,opt,name=memory_peak_bytes,json=memoryPeakBytes,proto3" json:"memory_peak_bytes,omitempty"`
 }
 
-var (
-	file_pkg_proto_resourceusage_resourceusage_proto_rawDescOnce sync.Once
-	file_pkg_proto_resourceusage_resourceusage_proto_rawDescData = file_pkg_proto_resourceusage_resourceusage_proto_rawDesc
-)
+func (x *MemoryResourceUsage) Reset() {
+	*x = MemoryResourceUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP() []byte {
-	file_pkg_proto_resourceusage_resourceusage_proto_rawDescOnce.Do(func() {
-		file_pkg_proto_resourceusage_resourceusage_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_resourceusage_resourceusage_proto_rawDescData)
-	})
-	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescData
+func (x *MemoryResourceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_pkg_proto_resourceusage_resourceusage_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
-var file_pkg_proto_resourceusage_resourceusage_proto_goTypes = []interface{}{
-	(*FilePoolResourceUsage)(nil),         // 0: buildbarn.resourceusage.FilePoolResourceUsage
-	(*POSIXResourceUsage)(nil),            // 1: buildbarn.resourceusage.POSIXResourceUsage
-	(*MonetaryResourceUsage)(nil),         // 2: buildbarn.resourceusage.MonetaryResourceUsage
-	(*InputRootResourceUsage)(nil),        // 3: buildbarn.resourceusage.InputRootResourceUsage
-	(*MonetaryResourceUsage_Expense)(nil), // 4: buildbarn.resourceusage.MonetaryResourceUsage.Expense
-	nil,                                   // 5: buildbarn.resourceusage.MonetaryResourceUsage.ExpensesEntry
-	(*durationpb.Duration)(nil),           // 6: google.protobuf.Duration
+func (*MemoryResourceUsage) ProtoMessage() {}
+
+func (x *MemoryResourceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_pkg_proto_resourceusage_resourceusage_proto_depIdxs = []int32{
-	6, // 0: buildbarn.resourceusage.POSIXResourceUsage.user_time:type_name -> google.protobuf.Duration
-	6, // 1: buildbarn.resourceusage.POSIXResourceUsage.system_time:type_name -> google.protobuf.Duration
-	5, // 2: buildbarn.resourceusage.MonetaryResourceUsage.expenses:type_name -> buildbarn.resourceusage.MonetaryResourceUsage.ExpensesEntry
-	4, // 3: buildbarn.resourceusage.MonetaryResourceUsage.ExpensesEntry.value:type_name -> buildbarn.resourceusage.MonetaryResourceUsage.Expense
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+
+// Deprecated: Use MemoryResourceUsage.ProtoReflect.Descriptor instead.
+func (*MemoryResourceUsage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{5}
 }
 
-func init() { file_pkg_proto_resourceusage_resourceusage_proto_init() }
-func file_pkg_proto_resourceusage_resourceusage_proto_init() {
-	if File_pkg_proto_resourceusage_resourceusage_proto != nil {
-		return
+func (x *MemoryResourceUsage) GetMemoryPeakBytes() int64 {
+	if x != nil {
+		return x.MemoryPeakBytes
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FilePoolResourceUsage); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return 0
+}
+
+type SwapResourceUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SwapPeakBytes          int64 `protobuf:"varint,1,opt,name=swap_peak_bytes,json=swapPeakBytes,proto3" json:"swap_peak_bytes,omitempty"`
+	SwapLimitExceededCount int64 `protobuf:"varint,2,opt,name=swap_limit_exceeded_count,json=swapLimitExceededCount,proto3" json:"swap_limit_exceeded_count,omitempty"`
+	ZswapStores            int64 `protobuf:"varint,3,opt,name=zswap_stores,json=zswapStores,proto3" json:"zswap_stores,omitempty"`
+	ZswapLoads             int64 `protobuf:"varint,4,opt,name=zswap_loads,json=zswapLoads,proto3" json:"zswap_loads,omitempty"`
+	ZswapWritebacks        int64 `protobuf:"varint,5,opt,name=zswap_writebacks,json=zswapWritebacks,proto3" json:"zswap_writebacks,omitempty"`
+}
+
+func (x *SwapResourceUsage) Reset() {
+	*x = SwapResourceUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SwapResourceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SwapResourceUsage) ProtoMessage() {}
+
+func (x *SwapResourceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*POSIXResourceUsage); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SwapResourceUsage.ProtoReflect.Descriptor instead.
+func (*SwapResourceUsage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SwapResourceUsage) GetSwapPeakBytes() int64 {
+	if x != nil {
+		return x.SwapPeakBytes
+	}
+	return 0
+}
+
+func (x *SwapResourceUsage) GetSwapLimitExceededCount() int64 {
+	if x != nil {
+		return x.SwapLimitExceededCount
+	}
+	return 0
+}
+
+func (x *SwapResourceUsage) GetZswapStores() int64 {
+	if x != nil {
+		return x.ZswapStores
+	}
+	return 0
+}
+
+func (x *SwapResourceUsage) GetZswapLoads() int64 {
+	if x != nil {
+		return x.ZswapLoads
+	}
+	return 0
+}
+
+func (x *SwapResourceUsage) GetZswapWritebacks() int64 {
+	if x != nil {
+		return x.ZswapWritebacks
+	}
+	return 0
+}
+
+type BlockIOResourceUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Devices           []*BlockIOResourceUsage_Device `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	ReadBytes         int64                          `protobuf:"varint,2,opt,name=read_bytes,json=readBytes,proto3" json:"read_bytes,omitempty"`
+	WriteBytes        int64                          `protobuf:"varint,3,opt,name=write_bytes,json=writeBytes,proto3" json:"write_bytes,omitempty"`
+	ReadOperations    int64                          `protobuf:"varint,4,opt,name=read_operations,json=readOperations,proto3" json:"read_operations,omitempty"`
+	WriteOperations   int64                          `protobuf:"varint,5,opt,name=write_operations,json=writeOperations,proto3" json:"write_operations,omitempty"`
+	DiscardBytes      int64                          `protobuf:"varint,6,opt,name=discard_bytes,json=discardBytes,proto3" json:"discard_bytes,omitempty"`
+	DiscardOperations int64                          `protobuf:"varint,7,opt,name=discard_operations,json=discardOperations,proto3" json:"discard_operations,omitempty"`
+}
+
+func (x *BlockIOResourceUsage) Reset() {
+	*x = BlockIOResourceUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockIOResourceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockIOResourceUsage) ProtoMessage() {}
+
+func (x *BlockIOResourceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MonetaryResourceUsage); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockIOResourceUsage.ProtoReflect.Descriptor instead.
+func (*BlockIOResourceUsage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BlockIOResourceUsage) GetDevices() []*BlockIOResourceUsage_Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *BlockIOResourceUsage) GetReadBytes() int64 {
+	if x != nil {
+		return x.ReadBytes
+	}
+	return 0
+}
+
+func (x *BlockIOResourceUsage) GetWriteBytes() int64 {
+	if x != nil {
+		return x.WriteBytes
+	}
+	return 0
+}
+
+func (x *BlockIOResourceUsage) GetReadOperations() int64 {
+	if x != nil {
+		return x.ReadOperations
+	}
+	return 0
+}
+
+func (x *BlockIOResourceUsage) GetWriteOperations() int64 {
+	if x != nil {
+		return x.WriteOperations
+	}
+	return 0
+}
+
+func (x *BlockIOResourceUsage) GetDiscardBytes() int64 {
+	if x != nil {
+		return x.DiscardBytes
+	}
+	return 0
+}
+
+func (x *BlockIOResourceUsage) GetDiscardOperations() int64 {
+	if x != nil {
+		return x.DiscardOperations
+	}
+	return 0
+}
+
+type BuildDirectoryResourceUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SizeBytesPeak    int64 `protobuf:"varint,1,opt,name=size_bytes_peak,json=sizeBytesPeak,proto3" json:"size_bytes_peak,omitempty"`
+	MaximumSizeBytes int64 `protobuf:"varint,2,opt,name=maximum_size_bytes,json=maximumSizeBytes,proto3" json:"maximum_size_bytes,omitempty"`
+}
+
+func (x *BuildDirectoryResourceUsage) Reset() {
+	*x = BuildDirectoryResourceUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BuildDirectoryResourceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildDirectoryResourceUsage) ProtoMessage() {}
+
+func (x *BuildDirectoryResourceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InputRootResourceUsage); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildDirectoryResourceUsage.ProtoReflect.Descriptor instead.
+func (*BuildDirectoryResourceUsage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BuildDirectoryResourceUsage) GetSizeBytesPeak() int64 {
+	if x != nil {
+		return x.SizeBytesPeak
+	}
+	return 0
+}
+
+func (x *BuildDirectoryResourceUsage) GetMaximumSizeBytes() int64 {
+	if x != nil {
+		return x.MaximumSizeBytes
+	}
+	return 0
+}
+
+type PIDsResourceUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PidsPeak               int64 `protobuf:"varint,1,opt,name=pids_peak,json=pidsPeak,proto3" json:"pids_peak,omitempty"`
+	PidsLimitExceededCount int64 `protobuf:"varint,2,opt,name=pids_limit_exceeded_count,json=pidsLimitExceededCount,proto3" json:"pids_limit_exceeded_count,omitempty"`
+}
+
+func (x *PIDsResourceUsage) Reset() {
+	*x = PIDsResourceUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PIDsResourceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PIDsResourceUsage) ProtoMessage() {}
+
+func (x *PIDsResourceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PIDsResourceUsage.ProtoReflect.Descriptor instead.
+func (*PIDsResourceUsage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PIDsResourceUsage) GetPidsPeak() int64 {
+	if x != nil {
+		return x.PidsPeak
+	}
+	return 0
+}
+
+func (x *PIDsResourceUsage) GetPidsLimitExceededCount() int64 {
+	if x != nil {
+		return x.PidsLimitExceededCount
+	}
+	return 0
+}
+
+type ProcessTreeResourceUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Processes        []*ProcessTreeResourceUsage_Process `protobuf:"bytes,1,rep,name=processes,proto3" json:"processes,omitempty"`
+	ProcessesOmitted int64                               `protobuf:"varint,2,opt,name=processes_omitted,json=processesOmitted,proto3" json:"processes_omitted,omitempty"`
+}
+
+func (x *ProcessTreeResourceUsage) Reset() {
+	*x = ProcessTreeResourceUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProcessTreeResourceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessTreeResourceUsage) ProtoMessage() {}
+
+func (x *ProcessTreeResourceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessTreeResourceUsage.ProtoReflect.Descriptor instead.
+func (*ProcessTreeResourceUsage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ProcessTreeResourceUsage) GetProcesses() []*ProcessTreeResourceUsage_Process {
+	if x != nil {
+		return x.Processes
+	}
+	return nil
+}
+
+func (x *ProcessTreeResourceUsage) GetProcessesOmitted() int64 {
+	if x != nil {
+		return x.ProcessesOmitted
+	}
+	return 0
+}
+
+type InputRootMinimizationReport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InputRootFilesCount    uint64     `protobuf:"varint,1,opt,name=input_root_files_count,json=inputRootFilesCount,proto3" json:"input_root_files_count,omitempty"`
+	ReadFilesCount         uint64     `protobuf:"varint,2,opt,name=read_files_count,json=readFilesCount,proto3" json:"read_files_count,omitempty"`
+	UnneededPathsDigest    *v2.Digest `protobuf:"bytes,3,opt,name=unneeded_paths_digest,json=unneededPathsDigest,proto3" json:"unneeded_paths_digest,omitempty"`
+	UnneededPathsCount     uint64     `protobuf:"varint,4,opt,name=unneeded_paths_count,json=unneededPathsCount,proto3" json:"unneeded_paths_count,omitempty"`
+	MinimalInputRootDigest *v2.Digest `protobuf:"bytes,5,opt,name=minimal_input_root_digest,json=minimalInputRootDigest,proto3" json:"minimal_input_root_digest,omitempty"`
+	ExecutionsCount        uint32     `protobuf:"varint,6,opt,name=executions_count,json=executionsCount,proto3" json:"executions_count,omitempty"`
+	Complete               bool       `protobuf:"varint,7,opt,name=complete,proto3" json:"complete,omitempty"`
+}
+
+func (x *InputRootMinimizationReport) Reset() {
+	*x = InputRootMinimizationReport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InputRootMinimizationReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InputRootMinimizationReport) ProtoMessage() {}
+
+func (x *InputRootMinimizationReport) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InputRootMinimizationReport.ProtoReflect.Descriptor instead.
+func (*InputRootMinimizationReport) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *InputRootMinimizationReport) GetInputRootFilesCount() uint64 {
+	if x != nil {
+		return x.InputRootFilesCount
+	}
+	return 0
+}
+
+func (x *InputRootMinimizationReport) GetReadFilesCount() uint64 {
+	if x != nil {
+		return x.ReadFilesCount
+	}
+	return 0
+}
+
+func (x *InputRootMinimizationReport) GetUnneededPathsDigest() *v2.Digest {
+	if x != nil {
+		return x.UnneededPathsDigest
+	}
+	return nil
+}
+
+func (x *InputRootMinimizationReport) GetUnneededPathsCount() uint64 {
+	if x != nil {
+		return x.UnneededPathsCount
+	}
+	return 0
+}
+
+func (x *InputRootMinimizationReport) GetMinimalInputRootDigest() *v2.Digest {
+	if x != nil {
+		return x.MinimalInputRootDigest
+	}
+	return nil
+}
+
+func (x *InputRootMinimizationReport) GetExecutionsCount() uint32 {
+	if x != nil {
+		return x.ExecutionsCount
+	}
+	return 0
+}
+
+func (x *InputRootMinimizationReport) GetComplete() bool {
+	if x != nil {
+		return x.Complete
+	}
+	return false
+}
+
+type ExecutionTimeoutCompensation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SuspendedDuration *durationpb.Duration `protobuf:"bytes,1,opt,name=suspended_duration,json=suspendedDuration,proto3" json:"suspended_duration,omitempty"`
+}
+
+func (x *ExecutionTimeoutCompensation) Reset() {
+	*x = ExecutionTimeoutCompensation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecutionTimeoutCompensation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionTimeoutCompensation) ProtoMessage() {}
+
+func (x *ExecutionTimeoutCompensation) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionTimeoutCompensation.ProtoReflect.Descriptor instead.
+func (*ExecutionTimeoutCompensation) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ExecutionTimeoutCompensation) GetSuspendedDuration() *durationpb.Duration {
+	if x != nil {
+		return x.SuspendedDuration
+	}
+	return nil
+}
+
+type EgressProxyResourceUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Requests                []*EgressProxyResourceUsage_Request `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	UnrecordedRequestsCount uint64                              `protobuf:"varint,2,opt,name=unrecorded_requests_count,json=unrecordedRequestsCount,proto3" json:"unrecorded_requests_count,omitempty"`
+}
+
+func (x *EgressProxyResourceUsage) Reset() {
+	*x = EgressProxyResourceUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EgressProxyResourceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EgressProxyResourceUsage) ProtoMessage() {}
+
+func (x *EgressProxyResourceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EgressProxyResourceUsage.ProtoReflect.Descriptor instead.
+func (*EgressProxyResourceUsage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *EgressProxyResourceUsage) GetRequests() []*EgressProxyResourceUsage_Request {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+func (x *EgressProxyResourceUsage) GetUnrecordedRequestsCount() uint64 {
+	if x != nil {
+		return x.UnrecordedRequestsCount
+	}
+	return 0
+}
+
+type EnergyResourceUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EstimatedEnergyJoules float64 `protobuf:"fixed64,1,opt,name=estimated_energy_joules,json=estimatedEnergyJoules,proto3" json:"estimated_energy_joules,omitempty"`
+	SystemEnergyJoules    float64 `protobuf:"fixed64,2,opt,name=system_energy_joules,json=systemEnergyJoules,proto3" json:"system_energy_joules,omitempty"`
+}
+
+func (x *EnergyResourceUsage) Reset() {
+	*x = EnergyResourceUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnergyResourceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnergyResourceUsage) ProtoMessage() {}
+
+func (x *EnergyResourceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnergyResourceUsage.ProtoReflect.Descriptor instead.
+func (*EnergyResourceUsage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *EnergyResourceUsage) GetEstimatedEnergyJoules() float64 {
+	if x != nil {
+		return x.EstimatedEnergyJoules
+	}
+	return 0
+}
+
+func (x *EnergyResourceUsage) GetSystemEnergyJoules() float64 {
+	if x != nil {
+		return x.SystemEnergyJoules
+	}
+	return 0
+}
+
+type VirtualFileSystemResourceUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Lookup        *VirtualFileSystemResourceUsage_Operation `protobuf:"bytes,1,opt,name=lookup,proto3" json:"lookup,omitempty"`
+	GetAttributes *VirtualFileSystemResourceUsage_Operation `protobuf:"bytes,2,opt,name=get_attributes,json=getAttributes,proto3" json:"get_attributes,omitempty"`
+	Read          *VirtualFileSystemResourceUsage_Operation `protobuf:"bytes,3,opt,name=read,proto3" json:"read,omitempty"`
+	ReadDir       *VirtualFileSystemResourceUsage_Operation `protobuf:"bytes,4,opt,name=read_dir,json=readDir,proto3" json:"read_dir,omitempty"`
+}
+
+func (x *VirtualFileSystemResourceUsage) Reset() {
+	*x = VirtualFileSystemResourceUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VirtualFileSystemResourceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VirtualFileSystemResourceUsage) ProtoMessage() {}
+
+func (x *VirtualFileSystemResourceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VirtualFileSystemResourceUsage.ProtoReflect.Descriptor instead.
+func (*VirtualFileSystemResourceUsage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *VirtualFileSystemResourceUsage) GetLookup() *VirtualFileSystemResourceUsage_Operation {
+	if x != nil {
+		return x.Lookup
+	}
+	return nil
+}
+
+func (x *VirtualFileSystemResourceUsage) GetGetAttributes() *VirtualFileSystemResourceUsage_Operation {
+	if x != nil {
+		return x.GetAttributes
+	}
+	return nil
+}
+
+func (x *VirtualFileSystemResourceUsage) GetRead() *VirtualFileSystemResourceUsage_Operation {
+	if x != nil {
+		return x.Read
+	}
+	return nil
+}
+
+func (x *VirtualFileSystemResourceUsage) GetReadDir() *VirtualFileSystemResourceUsage_Operation {
+	if x != nil {
+		return x.ReadDir
+	}
+	return nil
+}
+
+type ExecutionPhaseDurations struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DirectoryFetchDuration   *durationpb.Duration `protobuf:"bytes,1,opt,name=directory_fetch_duration,json=directoryFetchDuration,proto3" json:"directory_fetch_duration,omitempty"`
+	FileFetchDuration        *durationpb.Duration `protobuf:"bytes,2,opt,name=file_fetch_duration,json=fileFetchDuration,proto3" json:"file_fetch_duration,omitempty"`
+	OutputHashingDuration    *durationpb.Duration `protobuf:"bytes,3,opt,name=output_hashing_duration,json=outputHashingDuration,proto3" json:"output_hashing_duration,omitempty"`
+	FindMissingBlobsDuration *durationpb.Duration `protobuf:"bytes,4,opt,name=find_missing_blobs_duration,json=findMissingBlobsDuration,proto3" json:"find_missing_blobs_duration,omitempty"`
+	UploadDuration           *durationpb.Duration `protobuf:"bytes,5,opt,name=upload_duration,json=uploadDuration,proto3" json:"upload_duration,omitempty"`
+}
+
+func (x *ExecutionPhaseDurations) Reset() {
+	*x = ExecutionPhaseDurations{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecutionPhaseDurations) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionPhaseDurations) ProtoMessage() {}
+
+func (x *ExecutionPhaseDurations) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionPhaseDurations.ProtoReflect.Descriptor instead.
+func (*ExecutionPhaseDurations) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ExecutionPhaseDurations) GetDirectoryFetchDuration() *durationpb.Duration {
+	if x != nil {
+		return x.DirectoryFetchDuration
+	}
+	return nil
+}
+
+func (x *ExecutionPhaseDurations) GetFileFetchDuration() *durationpb.Duration {
+	if x != nil {
+		return x.FileFetchDuration
+	}
+	return nil
+}
+
+func (x *ExecutionPhaseDurations) GetOutputHashingDuration() *durationpb.Duration {
+	if x != nil {
+		return x.OutputHashingDuration
+	}
+	return nil
+}
+
+func (x *ExecutionPhaseDurations) GetFindMissingBlobsDuration() *durationpb.Duration {
+	if x != nil {
+		return x.FindMissingBlobsDuration
+	}
+	return nil
+}
+
+func (x *ExecutionPhaseDurations) GetUploadDuration() *durationpb.Duration {
+	if x != nil {
+		return x.UploadDuration
+	}
+	return nil
+}
+
+type TruncatedCommandOutputs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stdout *TruncatedCommandOutputs_Output `protobuf:"bytes,1,opt,name=stdout,proto3" json:"stdout,omitempty"`
+	Stderr *TruncatedCommandOutputs_Output `protobuf:"bytes,2,opt,name=stderr,proto3" json:"stderr,omitempty"`
+}
+
+func (x *TruncatedCommandOutputs) Reset() {
+	*x = TruncatedCommandOutputs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TruncatedCommandOutputs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TruncatedCommandOutputs) ProtoMessage() {}
+
+func (x *TruncatedCommandOutputs) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TruncatedCommandOutputs.ProtoReflect.Descriptor instead.
+func (*TruncatedCommandOutputs) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TruncatedCommandOutputs) GetStdout() *TruncatedCommandOutputs_Output {
+	if x != nil {
+		return x.Stdout
+	}
+	return nil
+}
+
+func (x *TruncatedCommandOutputs) GetStderr() *TruncatedCommandOutputs_Output {
+	if x != nil {
+		return x.Stderr
+	}
+	return nil
+}
+
+type LeakedProcessesResourceUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TotalProcesses uint32                                      `protobuf:"varint,1,opt,name=total_processes,json=totalProcesses,proto3" json:"total_processes,omitempty"`
+	CommandLines   []*LeakedProcessesResourceUsage_CommandLine `protobuf:"bytes,2,rep,name=command_lines,json=commandLines,proto3" json:"command_lines,omitempty"`
+}
+
+func (x *LeakedProcessesResourceUsage) Reset() {
+	*x = LeakedProcessesResourceUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LeakedProcessesResourceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeakedProcessesResourceUsage) ProtoMessage() {}
+
+func (x *LeakedProcessesResourceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeakedProcessesResourceUsage.ProtoReflect.Descriptor instead.
+func (*LeakedProcessesResourceUsage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *LeakedProcessesResourceUsage) GetTotalProcesses() uint32 {
+	if x != nil {
+		return x.TotalProcesses
+	}
+	return 0
+}
+
+func (x *LeakedProcessesResourceUsage) GetCommandLines() []*LeakedProcessesResourceUsage_CommandLine {
+	if x != nil {
+		return x.CommandLines
+	}
+	return nil
+}
+
+type CoreDumpsResourceUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CoreDumps []*CoreDumpsResourceUsage_CoreDump `protobuf:"bytes,1,rep,name=core_dumps,json=coreDumps,proto3" json:"core_dumps,omitempty"`
+}
+
+func (x *CoreDumpsResourceUsage) Reset() {
+	*x = CoreDumpsResourceUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CoreDumpsResourceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoreDumpsResourceUsage) ProtoMessage() {}
+
+func (x *CoreDumpsResourceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoreDumpsResourceUsage.ProtoReflect.Descriptor instead.
+func (*CoreDumpsResourceUsage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CoreDumpsResourceUsage) GetCoreDumps() []*CoreDumpsResourceUsage_CoreDump {
+	if x != nil {
+		return x.CoreDumps
+	}
+	return nil
+}
+
+type TraceResourceUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RateLimited   bool       `protobuf:"varint,1,opt,name=rate_limited,json=rateLimited,proto3" json:"rate_limited,omitempty"`
+	ServerLogName string     `protobuf:"bytes,2,opt,name=server_log_name,json=serverLogName,proto3" json:"server_log_name,omitempty"`
+	Digest        *v2.Digest `protobuf:"bytes,3,opt,name=digest,proto3" json:"digest,omitempty"`
+}
+
+func (x *TraceResourceUsage) Reset() {
+	*x = TraceResourceUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TraceResourceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceResourceUsage) ProtoMessage() {}
+
+func (x *TraceResourceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceResourceUsage.ProtoReflect.Descriptor instead.
+func (*TraceResourceUsage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TraceResourceUsage) GetRateLimited() bool {
+	if x != nil {
+		return x.RateLimited
+	}
+	return false
+}
+
+func (x *TraceResourceUsage) GetServerLogName() string {
+	if x != nil {
+		return x.ServerLogName
+	}
+	return ""
+}
+
+func (x *TraceResourceUsage) GetDigest() *v2.Digest {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+type SizeClassEscalation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FailedAttempts           []*SizeClassEscalation_FailedAttempt `protobuf:"bytes,1,rep,name=failed_attempts,json=failedAttempts,proto3" json:"failed_attempts,omitempty"`
+	ResourceLimitsMultiplier float64                              `protobuf:"fixed64,2,opt,name=resource_limits_multiplier,json=resourceLimitsMultiplier,proto3" json:"resource_limits_multiplier,omitempty"`
+}
+
+func (x *SizeClassEscalation) Reset() {
+	*x = SizeClassEscalation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SizeClassEscalation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SizeClassEscalation) ProtoMessage() {}
+
+func (x *SizeClassEscalation) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SizeClassEscalation.ProtoReflect.Descriptor instead.
+func (*SizeClassEscalation) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SizeClassEscalation) GetFailedAttempts() []*SizeClassEscalation_FailedAttempt {
+	if x != nil {
+		return x.FailedAttempts
+	}
+	return nil
+}
+
+func (x *SizeClassEscalation) GetResourceLimitsMultiplier() float64 {
+	if x != nil {
+		return x.ResourceLimitsMultiplier
+	}
+	return 0
+}
+
+type MonetaryResourceUsage_Expense struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Currency string  `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	Cost     float64 `protobuf:"fixed64,2,opt,name=cost,proto3" json:"cost,omitempty"`
+}
+
+func (x *MonetaryResourceUsage_Expense) Reset() {
+	*x = MonetaryResourceUsage_Expense{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MonetaryResourceUsage_Expense) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MonetaryResourceUsage_Expense) ProtoMessage() {}
+
+func (x *MonetaryResourceUsage_Expense) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MonetaryResourceUsage_Expense.ProtoReflect.Descriptor instead.
+func (*MonetaryResourceUsage_Expense) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{2, 0}
+}
+
+func (x *MonetaryResourceUsage_Expense) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *MonetaryResourceUsage_Expense) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+type BlockIOResourceUsage_Device struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Major             uint32 `protobuf:"varint,1,opt,name=major,proto3" json:"major,omitempty"`
+	Minor             uint32 `protobuf:"varint,2,opt,name=minor,proto3" json:"minor,omitempty"`
+	ReadBytes         int64  `protobuf:"varint,3,opt,name=read_bytes,json=readBytes,proto3" json:"read_bytes,omitempty"`
+	WriteBytes        int64  `protobuf:"varint,4,opt,name=write_bytes,json=writeBytes,proto3" json:"write_bytes,omitempty"`
+	ReadOperations    int64  `protobuf:"varint,5,opt,name=read_operations,json=readOperations,proto3" json:"read_operations,omitempty"`
+	WriteOperations   int64  `protobuf:"varint,6,opt,name=write_operations,json=writeOperations,proto3" json:"write_operations,omitempty"`
+	DiscardBytes      int64  `protobuf:"varint,7,opt,name=discard_bytes,json=discardBytes,proto3" json:"discard_bytes,omitempty"`
+	DiscardOperations int64  `protobuf:"varint,8,opt,name=discard_operations,json=discardOperations,proto3" json:"discard_operations,omitempty"`
+}
+
+func (x *BlockIOResourceUsage_Device) Reset() {
+	*x = BlockIOResourceUsage_Device{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockIOResourceUsage_Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockIOResourceUsage_Device) ProtoMessage() {}
+
+func (x *BlockIOResourceUsage_Device) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockIOResourceUsage_Device.ProtoReflect.Descriptor instead.
+func (*BlockIOResourceUsage_Device) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{7, 0}
+}
+
+func (x *BlockIOResourceUsage_Device) GetMajor() uint32 {
+	if x != nil {
+		return x.Major
+	}
+	return 0
+}
+
+func (x *BlockIOResourceUsage_Device) GetMinor() uint32 {
+	if x != nil {
+		return x.Minor
+	}
+	return 0
+}
+
+func (x *BlockIOResourceUsage_Device) GetReadBytes() int64 {
+	if x != nil {
+		return x.ReadBytes
+	}
+	return 0
+}
+
+func (x *BlockIOResourceUsage_Device) GetWriteBytes() int64 {
+	if x != nil {
+		return x.WriteBytes
+	}
+	return 0
+}
+
+func (x *BlockIOResourceUsage_Device) GetReadOperations() int64 {
+	if x != nil {
+		return x.ReadOperations
+	}
+	return 0
+}
+
+func (x *BlockIOResourceUsage_Device) GetWriteOperations() int64 {
+	if x != nil {
+		return x.WriteOperations
+	}
+	return 0
+}
+
+func (x *BlockIOResourceUsage_Device) GetDiscardBytes() int64 {
+	if x != nil {
+		return x.DiscardBytes
+	}
+	return 0
+}
+
+func (x *BlockIOResourceUsage_Device) GetDiscardOperations() int64 {
+	if x != nil {
+		return x.DiscardOperations
+	}
+	return 0
+}
+
+type ProcessTreeResourceUsage_Process struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pid                    int64  `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+	ParentPid              int64  `protobuf:"varint,2,opt,name=parent_pid,json=parentPid,proto3" json:"parent_pid,omitempty"`
+	Executable             string `protobuf:"bytes,3,opt,name=executable,proto3" json:"executable,omitempty"`
+	ExitCode               int32  `protobuf:"varint,4,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	TerminationSignal      string `protobuf:"bytes,5,opt,name=termination_signal,json=terminationSignal,proto3" json:"termination_signal,omitempty"`
+	MaximumResidentSetSize int64  `protobuf:"varint,6,opt,name=maximum_resident_set_size,json=maximumResidentSetSize,proto3" json:"maximum_resident_set_size,omitempty"`
+}
+
+func (x *ProcessTreeResourceUsage_Process) Reset() {
+	*x = ProcessTreeResourceUsage_Process{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProcessTreeResourceUsage_Process) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessTreeResourceUsage_Process) ProtoMessage() {}
+
+func (x *ProcessTreeResourceUsage_Process) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessTreeResourceUsage_Process.ProtoReflect.Descriptor instead.
+func (*ProcessTreeResourceUsage_Process) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{10, 0}
+}
+
+func (x *ProcessTreeResourceUsage_Process) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *ProcessTreeResourceUsage_Process) GetParentPid() int64 {
+	if x != nil {
+		return x.ParentPid
+	}
+	return 0
+}
+
+func (x *ProcessTreeResourceUsage_Process) GetExecutable() string {
+	if x != nil {
+		return x.Executable
+	}
+	return ""
+}
+
+func (x *ProcessTreeResourceUsage_Process) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *ProcessTreeResourceUsage_Process) GetTerminationSignal() string {
+	if x != nil {
+		return x.TerminationSignal
+	}
+	return ""
+}
+
+func (x *ProcessTreeResourceUsage_Process) GetMaximumResidentSetSize() int64 {
+	if x != nil {
+		return x.MaximumResidentSetSize
+	}
+	return 0
+}
+
+type EgressProxyResourceUsage_Request struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Method  string `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Url     string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Allowed bool   `protobuf:"varint,3,opt,name=allowed,proto3" json:"allowed,omitempty"`
+}
+
+func (x *EgressProxyResourceUsage_Request) Reset() {
+	*x = EgressProxyResourceUsage_Request{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EgressProxyResourceUsage_Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EgressProxyResourceUsage_Request) ProtoMessage() {}
+
+func (x *EgressProxyResourceUsage_Request) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EgressProxyResourceUsage_Request.ProtoReflect.Descriptor instead.
+func (*EgressProxyResourceUsage_Request) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{13, 0}
+}
+
+func (x *EgressProxyResourceUsage_Request) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *EgressProxyResourceUsage_Request) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *EgressProxyResourceUsage_Request) GetAllowed() bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return false
+}
+
+type VirtualFileSystemResourceUsage_Operation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count           uint64               `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	TotalDuration   *durationpb.Duration `protobuf:"bytes,2,opt,name=total_duration,json=totalDuration,proto3" json:"total_duration,omitempty"`
+	P50Duration     *durationpb.Duration `protobuf:"bytes,3,opt,name=p50_duration,json=p50Duration,proto3" json:"p50_duration,omitempty"`
+	P90Duration     *durationpb.Duration `protobuf:"bytes,4,opt,name=p90_duration,json=p90Duration,proto3" json:"p90_duration,omitempty"`
+	P99Duration     *durationpb.Duration `protobuf:"bytes,5,opt,name=p99_duration,json=p99Duration,proto3" json:"p99_duration,omitempty"`
+	MaximumDuration *durationpb.Duration `protobuf:"bytes,6,opt,name=maximum_duration,json=maximumDuration,proto3" json:"maximum_duration,omitempty"`
+}
+
+func (x *VirtualFileSystemResourceUsage_Operation) Reset() {
+	*x = VirtualFileSystemResourceUsage_Operation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VirtualFileSystemResourceUsage_Operation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VirtualFileSystemResourceUsage_Operation) ProtoMessage() {}
+
+func (x *VirtualFileSystemResourceUsage_Operation) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VirtualFileSystemResourceUsage_Operation.ProtoReflect.Descriptor instead.
+func (*VirtualFileSystemResourceUsage_Operation) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{15, 0}
+}
+
+func (x *VirtualFileSystemResourceUsage_Operation) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *VirtualFileSystemResourceUsage_Operation) GetTotalDuration() *durationpb.Duration {
+	if x != nil {
+		return x.TotalDuration
+	}
+	return nil
+}
+
+func (x *VirtualFileSystemResourceUsage_Operation) GetP50Duration() *durationpb.Duration {
+	if x != nil {
+		return x.P50Duration
+	}
+	return nil
+}
+
+func (x *VirtualFileSystemResourceUsage_Operation) GetP90Duration() *durationpb.Duration {
+	if x != nil {
+		return x.P90Duration
+	}
+	return nil
+}
+
+func (x *VirtualFileSystemResourceUsage_Operation) GetP99Duration() *durationpb.Duration {
+	if x != nil {
+		return x.P99Duration
+	}
+	return nil
+}
+
+func (x *VirtualFileSystemResourceUsage_Operation) GetMaximumDuration() *durationpb.Duration {
+	if x != nil {
+		return x.MaximumDuration
+	}
+	return nil
+}
+
+type TruncatedCommandOutputs_Output struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OriginalSizeBytes uint64     `protobuf:"varint,1,opt,name=original_size_bytes,json=originalSizeBytes,proto3" json:"original_size_bytes,omitempty"`
+	OriginalDigest    *v2.Digest `protobuf:"bytes,2,opt,name=original_digest,json=originalDigest,proto3" json:"original_digest,omitempty"`
+}
+
+func (x *TruncatedCommandOutputs_Output) Reset() {
+	*x = TruncatedCommandOutputs_Output{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TruncatedCommandOutputs_Output) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TruncatedCommandOutputs_Output) ProtoMessage() {}
+
+func (x *TruncatedCommandOutputs_Output) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TruncatedCommandOutputs_Output.ProtoReflect.Descriptor instead.
+func (*TruncatedCommandOutputs_Output) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{17, 0}
+}
+
+func (x *TruncatedCommandOutputs_Output) GetOriginalSizeBytes() uint64 {
+	if x != nil {
+		return x.OriginalSizeBytes
+	}
+	return 0
+}
+
+func (x *TruncatedCommandOutputs_Output) GetOriginalDigest() *v2.Digest {
+	if x != nil {
+		return x.OriginalDigest
+	}
+	return nil
+}
+
+type LeakedProcessesResourceUsage_CommandLine struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Arguments []string `protobuf:"bytes,1,rep,name=arguments,proto3" json:"arguments,omitempty"`
+	Processes uint32   `protobuf:"varint,2,opt,name=processes,proto3" json:"processes,omitempty"`
+}
+
+func (x *LeakedProcessesResourceUsage_CommandLine) Reset() {
+	*x = LeakedProcessesResourceUsage_CommandLine{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LeakedProcessesResourceUsage_CommandLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeakedProcessesResourceUsage_CommandLine) ProtoMessage() {}
+
+func (x *LeakedProcessesResourceUsage_CommandLine) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeakedProcessesResourceUsage_CommandLine.ProtoReflect.Descriptor instead.
+func (*LeakedProcessesResourceUsage_CommandLine) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{18, 0}
+}
+
+func (x *LeakedProcessesResourceUsage_CommandLine) GetArguments() []string {
+	if x != nil {
+		return x.Arguments
+	}
+	return nil
+}
+
+func (x *LeakedProcessesResourceUsage_CommandLine) GetProcesses() uint32 {
+	if x != nil {
+		return x.Processes
+	}
+	return 0
+}
+
+type CoreDumpsResourceUsage_CoreDump struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path             string     `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	SizeBytes        uint64     `protobuf:"varint,2,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	ServerLogName    string     `protobuf:"bytes,3,opt,name=server_log_name,json=serverLogName,proto3" json:"server_log_name,omitempty"`
+	CompressedDigest *v2.Digest `protobuf:"bytes,4,opt,name=compressed_digest,json=compressedDigest,proto3" json:"compressed_digest,omitempty"`
+}
+
+func (x *CoreDumpsResourceUsage_CoreDump) Reset() {
+	*x = CoreDumpsResourceUsage_CoreDump{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CoreDumpsResourceUsage_CoreDump) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoreDumpsResourceUsage_CoreDump) ProtoMessage() {}
+
+func (x *CoreDumpsResourceUsage_CoreDump) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoreDumpsResourceUsage_CoreDump.ProtoReflect.Descriptor instead.
+func (*CoreDumpsResourceUsage_CoreDump) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{19, 0}
+}
+
+func (x *CoreDumpsResourceUsage_CoreDump) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *CoreDumpsResourceUsage_CoreDump) GetSizeBytes() uint64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *CoreDumpsResourceUsage_CoreDump) GetServerLogName() string {
+	if x != nil {
+		return x.ServerLogName
+	}
+	return ""
+}
+
+func (x *CoreDumpsResourceUsage_CoreDump) GetCompressedDigest() *v2.Digest {
+	if x != nil {
+		return x.CompressedDigest
+	}
+	return nil
+}
+
+type SizeClassEscalation_FailedAttempt struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SizeClass uint32               `protobuf:"varint,1,opt,name=size_class,json=sizeClass,proto3" json:"size_class,omitempty"`
+	Timeout   *durationpb.Duration `protobuf:"bytes,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Status    *status.Status       `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	ExitCode  int32                `protobuf:"varint,4,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+}
+
+func (x *SizeClassEscalation_FailedAttempt) Reset() {
+	*x = SizeClassEscalation_FailedAttempt{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SizeClassEscalation_FailedAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SizeClassEscalation_FailedAttempt) ProtoMessage() {}
+
+func (x *SizeClassEscalation_FailedAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SizeClassEscalation_FailedAttempt.ProtoReflect.Descriptor instead.
+func (*SizeClassEscalation_FailedAttempt) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP(), []int{21, 0}
+}
+
+func (x *SizeClassEscalation_FailedAttempt) GetSizeClass() uint32 {
+	if x != nil {
+		return x.SizeClass
+	}
+	return 0
+}
+
+func (x *SizeClassEscalation_FailedAttempt) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *SizeClassEscalation_FailedAttempt) GetStatus() *status.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *SizeClassEscalation_FailedAttempt) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+var File_pkg_proto_resourceusage_resourceusage_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_resourceusage_resourceusage_proto_rawDesc = []byte{
+	0x0a, 0x2b, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x2f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x17, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x36, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2f, 0x62, 0x61,
+	0x7a, 0x65, 0x6c, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2f, 0x65, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x76, 0x32, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x65,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x85, 0x04, 0x0a, 0x15, 0x46, 0x69, 0x6c, 0x65,
+	0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x23, 0x0a, 0x0d, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x28, 0x0a, 0x10, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x65, 0x61, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0e, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x65, 0x61, 0x6b,
+	0x12, 0x31, 0x0a, 0x15, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62,
+	0x79, 0x74, 0x65, 0x73, 0x5f, 0x70, 0x65, 0x61, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x12, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50,
+	0x65, 0x61, 0x6b, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x61, 0x64, 0x73, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x72, 0x65, 0x61, 0x64, 0x73, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x72, 0x65, 0x61, 0x64, 0x73, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e,
+	0x72, 0x65, 0x61, 0x64, 0x73, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x21,
+	0x0a, 0x0c, 0x77, 0x72, 0x69, 0x74, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x77, 0x72, 0x69, 0x74, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x2a, 0x0a, 0x11, 0x77, 0x72, 0x69, 0x74, 0x65, 0x73, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x77, 0x72,
+	0x69, 0x74, 0x65, 0x73, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x27, 0x0a,
+	0x0f, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65,
+	0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x64, 0x65, 0x61, 0x6c, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x10, 0x64, 0x65, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x73, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x34, 0x0a, 0x16, 0x64, 0x65, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74,
+	0x65, 0x73, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x14, 0x64, 0x65, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x73,
+	0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x44, 0x0a, 0x1f, 0x66, 0x69, 0x6c,
+	0x65, 0x73, 0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x70, 0x65, 0x61, 0x6b, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x1b, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74,
+	0x65, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50, 0x65, 0x61, 0x6b, 0x22,
+	0xcb, 0x05, 0x0a, 0x12, 0x50, 0x4f, 0x53, 0x49, 0x58, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x36, 0x0a, 0x09, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x3a,
+	0x0a, 0x0b, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a,
+	0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x39, 0x0a, 0x19, 0x6d, 0x61,
+	0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x72, 0x65, 0x73, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x73,
+	0x65, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x16, 0x6d,
+	0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x52, 0x65, 0x73, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x53, 0x65,
+	0x74, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x72, 0x65,
+	0x63, 0x6c, 0x61, 0x69, 0x6d, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x70, 0x61,
+	0x67, 0x65, 0x52, 0x65, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x61,
+	0x67, 0x65, 0x5f, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0a, 0x70, 0x61, 0x67, 0x65, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x77, 0x61, 0x70, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x77, 0x61, 0x70,
+	0x73, 0x12, 0x34, 0x0a, 0x16, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x69, 0x6e, 0x70, 0x75, 0x74,
+	0x5f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x14, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x4f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x36, 0x0a, 0x17, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x15, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x4f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12,
+	0x23, 0x0a, 0x0d, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x5f, 0x73, 0x65, 0x6e, 0x74,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73,
+	0x53, 0x65, 0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73,
+	0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x10, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x64, 0x12, 0x29, 0x0a, 0x10, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x73, 0x5f, 0x72, 0x65, 0x63,
+	0x65, 0x69, 0x76, 0x65, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x3c, 0x0a, 0x1a,
+	0x76, 0x6f, 0x6c, 0x75, 0x6e, 0x74, 0x61, 0x72, 0x79, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78,
+	0x74, 0x5f, 0x73, 0x77, 0x69, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x18, 0x76, 0x6f, 0x6c, 0x75, 0x6e, 0x74, 0x61, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x74, 0x65,
+	0x78, 0x74, 0x53, 0x77, 0x69, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x40, 0x0a, 0x1c, 0x69, 0x6e,
+	0x76, 0x6f, 0x6c, 0x75, 0x6e, 0x74, 0x61, 0x72, 0x79, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78,
+	0x74, 0x5f, 0x73, 0x77, 0x69, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x1a, 0x69, 0x6e, 0x76, 0x6f, 0x6c, 0x75, 0x6e, 0x74, 0x61, 0x72, 0x79, 0x43, 0x6f, 0x6e,
+	0x74, 0x65, 0x78, 0x74, 0x53, 0x77, 0x69, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x2d, 0x0a, 0x12,
+	0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x6c, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x4a, 0x04, 0x08, 0x04, 0x10,
+	0x05, 0x4a, 0x04, 0x08, 0x05, 0x10, 0x06, 0x4a, 0x04, 0x08, 0x06, 0x10, 0x07, 0x22, 0xa1, 0x02,
+	0x0a, 0x15, 0x4d, 0x6f, 0x6e, 0x65, 0x74, 0x61, 0x72, 0x79, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x58, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x65, 0x6e,
+	0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3c, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x75, 0x73,
+	0x61, 0x67, 0x65, 0x2e, 0x4d, 0x6f, 0x6e, 0x65, 0x74, 0x61, 0x72, 0x79, 0x52, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x45, 0x78, 0x70, 0x65, 0x6e, 0x73,
+	0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x65, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65,
+	0x73, 0x1a, 0x39, 0x0a, 0x07, 0x45, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x73, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x1a, 0x73, 0x0a, 0x0d,
+	0x45, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x4c, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x36,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x72, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x4d, 0x6f, 0x6e, 0x65, 0x74, 0x61, 0x72,
+	0x79, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x45,
+	0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0x95, 0x01, 0x0a, 0x16, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x52,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x31, 0x0a, 0x14,
+	0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x5f, 0x72, 0x65, 0x73, 0x6f,
+	0x6c, 0x76, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x13, 0x64, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x12,
+	0x29, 0x0a, 0x10, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x5f, 0x72,
+	0x65, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x61, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x69,
+	0x6c, 0x65, 0x73, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09,
+	0x66, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x61, 0x64, 0x22, 0x81, 0x01, 0x0a, 0x12, 0x49, 0x6e,
+	0x70, 0x75, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x52, 0x65, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73,
+	0x12, 0x4a, 0x0a, 0x0c, 0x70, 0x61, 0x74, 0x68, 0x73, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62,
+	0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63,
+	0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52,
+	0x0b, 0x70, 0x61, 0x74, 0x68, 0x73, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b,
+	0x70, 0x61, 0x74, 0x68, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0a, 0x70, 0x61, 0x74, 0x68, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x41, 0x0a,
+	0x13, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x70,
+	0x65, 0x61, 0x6b, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x50, 0x65, 0x61, 0x6b, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x22, 0xe5, 0x01, 0x0a, 0x11, 0x53, 0x77, 0x61, 0x70, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x73, 0x77, 0x61, 0x70, 0x5f, 0x70,
+	0x65, 0x61, 0x6b, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0d, 0x73, 0x77, 0x61, 0x70, 0x50, 0x65, 0x61, 0x6b, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x39,
+	0x0a, 0x19, 0x73, 0x77, 0x61, 0x70, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x65, 0x78, 0x63,
+	0x65, 0x65, 0x64, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x16, 0x73, 0x77, 0x61, 0x70, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x45, 0x78, 0x63, 0x65,
+	0x65, 0x64, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x7a, 0x73, 0x77,
+	0x61, 0x70, 0x5f, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0b, 0x7a, 0x73, 0x77, 0x61, 0x70, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x7a, 0x73, 0x77, 0x61, 0x70, 0x5f, 0x6c, 0x6f, 0x61, 0x64, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0a, 0x7a, 0x73, 0x77, 0x61, 0x70, 0x4c, 0x6f, 0x61, 0x64, 0x73, 0x12, 0x29, 0x0a,
+	0x10, 0x7a, 0x73, 0x77, 0x61, 0x70, 0x5f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x62, 0x61, 0x63, 0x6b,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x7a, 0x73, 0x77, 0x61, 0x70, 0x57, 0x72,
+	0x69, 0x74, 0x65, 0x62, 0x61, 0x63, 0x6b, 0x73, 0x22, 0xed, 0x04, 0x0a, 0x14, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x49, 0x4f, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x4e, 0x0a, 0x07, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x34, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x72,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x49, 0x4f, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x07, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x72, 0x65, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x77, 0x72, 0x69, 0x74, 0x65, 0x42, 0x79, 0x74, 0x65,
+	0x73, 0x12, 0x27, 0x0a, 0x0f, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x72, 0x65, 0x61, 0x64,
+	0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x77, 0x72,
+	0x69, 0x74, 0x65, 0x5f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64,
+	0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x64, 0x69,
+	0x73, 0x63, 0x61, 0x72, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x2d, 0x0a, 0x12, 0x64, 0x69,
+	0x73, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x4f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x1a, 0x9c, 0x02, 0x0a, 0x06, 0x44, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x61, 0x6a, 0x6f, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x05, 0x6d, 0x61, 0x6a, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x69,
+	0x6e, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6d, 0x69, 0x6e, 0x6f, 0x72,
+	0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x72, 0x65, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12,
+	0x1f, 0x0a, 0x0b, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x77, 0x72, 0x69, 0x74, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x12, 0x27, 0x0a, 0x0f, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x72, 0x65, 0x61, 0x64, 0x4f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x77, 0x72, 0x69,
+	0x74, 0x65, 0x5f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x5f,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x64, 0x69, 0x73,
+	0x63, 0x61, 0x72, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x2d, 0x0a, 0x12, 0x64, 0x69, 0x73,
+	0x63, 0x61, 0x72, 0x64, 0x5f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x4f, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x73, 0x0a, 0x1b, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x73, 0x69, 0x7a, 0x65, 0x5f,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x70, 0x65, 0x61, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0d, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50, 0x65, 0x61, 0x6b, 0x12,
+	0x2c, 0x0a, 0x12, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x6d, 0x61, 0x78,
+	0x69, 0x6d, 0x75, 0x6d, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x6b, 0x0a,
+	0x11, 0x50, 0x49, 0x44, 0x73, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x69, 0x64, 0x73, 0x5f, 0x70, 0x65, 0x61, 0x6b, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x70, 0x69, 0x64, 0x73, 0x50, 0x65, 0x61, 0x6b, 0x12,
+	0x39, 0x0a, 0x19, 0x70, 0x69, 0x64, 0x73, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x65, 0x78,
+	0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x16, 0x70, 0x69, 0x64, 0x73, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x45, 0x78, 0x63,
+	0x65, 0x65, 0x64, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x84, 0x03, 0x0a, 0x18, 0x50,
+	0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x57, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65,
+	0x73, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x39, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x75,
+	0x73, 0x61, 0x67, 0x65, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x54, 0x72, 0x65, 0x65,
+	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x50, 0x72,
+	0x6f, 0x63, 0x65, 0x73, 0x73, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x73,
+	0x12, 0x2b, 0x0a, 0x11, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x73, 0x5f, 0x6f, 0x6d,
+	0x69, 0x74, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x70, 0x72, 0x6f,
+	0x63, 0x65, 0x73, 0x73, 0x65, 0x73, 0x4f, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x1a, 0xe1, 0x01,
+	0x0a, 0x07, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x70, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x70,
+	0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x70, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x09, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x50, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x78,
+	0x69, 0x74, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x65,
+	0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x2d, 0x0a, 0x12, 0x74, 0x65, 0x72, 0x6d, 0x69,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x11, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x12, 0x39, 0x0a, 0x19, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75,
+	0x6d, 0x5f, 0x72, 0x65, 0x73, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x65, 0x74, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x16, 0x6d, 0x61, 0x78, 0x69, 0x6d,
+	0x75, 0x6d, 0x52, 0x65, 0x73, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x74, 0x53, 0x69, 0x7a,
+	0x65, 0x22, 0xb6, 0x03, 0x0a, 0x1b, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x4d,
+	0x69, 0x6e, 0x69, 0x6d, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72,
+	0x74, 0x12, 0x33, 0x0a, 0x16, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x5f,
+	0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x13, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x46, 0x69, 0x6c, 0x65,
+	0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x66,
+	0x69, 0x6c, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0e, 0x72, 0x65, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x5b, 0x0a, 0x15, 0x75, 0x6e, 0x6e, 0x65, 0x65, 0x64, 0x65, 0x64, 0x5f, 0x70, 0x61, 0x74,
+	0x68, 0x73, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76,
+	0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x13, 0x75, 0x6e, 0x6e, 0x65, 0x65, 0x64,
+	0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x30, 0x0a,
+	0x14, 0x75, 0x6e, 0x6e, 0x65, 0x65, 0x64, 0x65, 0x64, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x5f,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x75, 0x6e, 0x6e,
+	0x65, 0x65, 0x64, 0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x62, 0x0a, 0x19, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x5f, 0x69, 0x6e, 0x70, 0x75, 0x74,
+	0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c,
+	0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f,
+	0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x16, 0x6d, 0x69, 0x6e,
+	0x69, 0x6d, 0x61, 0x6c, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x44, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x65,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x08, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x22, 0x68, 0x0a, 0x1c, 0x45, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x43, 0x6f,
+	0x6d, 0x70, 0x65, 0x6e, 0x73, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x48, 0x0a, 0x12, 0x73, 0x75,
+	0x73, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x5f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x11, 0x73, 0x75, 0x73, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x22, 0xfc, 0x01, 0x0a, 0x18, 0x45, 0x67, 0x72, 0x65, 0x73, 0x73, 0x50,
+	0x72, 0x6f, 0x78, 0x79, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x55, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x39, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x45, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x08,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x3a, 0x0a, 0x19, 0x75, 0x6e, 0x72, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x5f,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x17, 0x75, 0x6e, 0x72,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x1a, 0x4d, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x61, 0x6c, 0x6c, 0x6f,
+	0x77, 0x65, 0x64, 0x22, 0x7f, 0x0a, 0x13, 0x45, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x52, 0x65, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x36, 0x0a, 0x17, 0x65, 0x73,
+	0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f, 0x6a,
+	0x6f, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x15, 0x65, 0x73, 0x74,
+	0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x45, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x4a, 0x6f, 0x75, 0x6c,
+	0x65, 0x73, 0x12, 0x30, 0x0a, 0x14, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x65, 0x6e, 0x65,
+	0x72, 0x67, 0x79, 0x5f, 0x6a, 0x6f, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x12, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x45, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x4a, 0x6f,
+	0x75, 0x6c, 0x65, 0x73, 0x22, 0x80, 0x06, 0x0a, 0x1e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
+	0x46, 0x69, 0x6c, 0x65, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x59, 0x0a, 0x06, 0x6c, 0x6f, 0x6f, 0x6b, 0x75,
+	0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x41, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
+	0x61, 0x72, 0x6e, 0x2e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x75, 0x73, 0x61, 0x67,
+	0x65, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x79, 0x73,
+	0x74, 0x65, 0x6d, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65,
+	0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x6c, 0x6f, 0x6f, 0x6b,
+	0x75, 0x70, 0x12, 0x68, 0x0a, 0x0e, 0x67, 0x65, 0x74, 0x5f, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62,
+	0x75, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x41, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x75,
+	0x73, 0x61, 0x67, 0x65, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x46, 0x69, 0x6c, 0x65,
+	0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73,
+	0x61, 0x67, 0x65, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x67,
+	0x65, 0x74, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12, 0x55, 0x0a, 0x04,
+	0x72, 0x65, 0x61, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x41, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x75,
+	0x73, 0x61, 0x67, 0x65, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x46, 0x69, 0x6c, 0x65,
+	0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73,
+	0x61, 0x67, 0x65, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x72,
+	0x65, 0x61, 0x64, 0x12, 0x5c, 0x0a, 0x08, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x64, 0x69, 0x72, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x41, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72,
+	0x6e, 0x2e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x2e,
+	0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x79, 0x73, 0x74, 0x65,
+	0x6d, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x4f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x72, 0x65, 0x61, 0x64, 0x44, 0x69,
+	0x72, 0x1a, 0xe3, 0x02, 0x0a, 0x09, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x40, 0x0a, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x64,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3c, 0x0a, 0x0c, 0x70, 0x35, 0x30, 0x5f, 0x64,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x70, 0x35, 0x30, 0x44, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3c, 0x0a, 0x0c, 0x70, 0x39, 0x30, 0x5f, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x70, 0x39, 0x30, 0x44, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x3c, 0x0a, 0x0c, 0x70, 0x39, 0x39, 0x5f, 0x64, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x70, 0x39, 0x39, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x44, 0x0a, 0x10, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0f, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xaa, 0x03, 0x0a, 0x17, 0x45, 0x78, 0x65, 0x63,
+	0x75, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x68, 0x61, 0x73, 0x65, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x53, 0x0a, 0x18, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
+	0x5f, 0x66, 0x65, 0x74, 0x63, 0x68, 0x5f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x16, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x46, 0x65, 0x74, 0x63, 0x68,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x49, 0x0a, 0x13, 0x66, 0x69, 0x6c, 0x65,
+	0x5f, 0x66, 0x65, 0x74, 0x63, 0x68, 0x5f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x11, 0x66, 0x69, 0x6c, 0x65, 0x46, 0x65, 0x74, 0x63, 0x68, 0x44, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x51, 0x0a, 0x17, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x68, 0x61,
+	0x73, 0x68, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x15, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x48, 0x61, 0x73, 0x68, 0x69, 0x6e, 0x67, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x58, 0x0a, 0x1b, 0x66, 0x69, 0x6e, 0x64, 0x5f, 0x6d,
+	0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x5f, 0x62, 0x6c, 0x6f, 0x62, 0x73, 0x5f, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x18, 0x66, 0x69, 0x6e, 0x64, 0x4d, 0x69, 0x73, 0x73,
+	0x69, 0x6e, 0x67, 0x42, 0x6c, 0x6f, 0x62, 0x73, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x42, 0x0a, 0x0f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x64, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x22, 0xc8, 0x02, 0x0a, 0x17, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74,
+	0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73,
+	0x12, 0x4f, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x37, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x72, 0x65, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x54, 0x72, 0x75, 0x6e, 0x63,
+	0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x73, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x52, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75,
+	0x74, 0x12, 0x4f, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x65, 0x72, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x37, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x72, 0x65,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x54, 0x72, 0x75, 0x6e,
+	0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x73, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x52, 0x06, 0x73, 0x74, 0x64, 0x65,
+	0x72, 0x72, 0x1a, 0x8a, 0x01, 0x0a, 0x06, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x2e, 0x0a,
+	0x13, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62,
+	0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x6f, 0x72, 0x69, 0x67,
+	0x69, 0x6e, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x50, 0x0a,
+	0x0f, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62,
+	0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63,
+	0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52,
+	0x0e, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22,
+	0xfa, 0x01, 0x0a, 0x1c, 0x4c, 0x65, 0x61, 0x6b, 0x65, 0x64, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x27, 0x0a, 0x0f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x66, 0x0a, 0x0d, 0x63, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x41, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x72, 0x65, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x4c, 0x65, 0x61, 0x6b, 0x65,
+	0x64, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4c,
+	0x69, 0x6e, 0x65, 0x52, 0x0c, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4c, 0x69, 0x6e, 0x65,
+	0x73, 0x1a, 0x49, 0x0a, 0x0b, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4c, 0x69, 0x6e, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x61, 0x72, 0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x09, 0x61, 0x72, 0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1c,
+	0x0a, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x73, 0x22, 0xaf, 0x02, 0x0a,
+	0x16, 0x43, 0x6f, 0x72, 0x65, 0x44, 0x75, 0x6d, 0x70, 0x73, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x57, 0x0a, 0x0a, 0x63, 0x6f, 0x72, 0x65, 0x5f,
+	0x64, 0x75, 0x6d, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x38, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x75, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x6f, 0x72, 0x65, 0x44, 0x75, 0x6d, 0x70, 0x73, 0x52,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x6f, 0x72,
+	0x65, 0x44, 0x75, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x6f, 0x72, 0x65, 0x44, 0x75, 0x6d, 0x70, 0x73,
+	0x1a, 0xbb, 0x01, 0x0a, 0x08, 0x43, 0x6f, 0x72, 0x65, 0x44, 0x75, 0x6d, 0x70, 0x12, 0x12, 0x0a,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x12, 0x26, 0x0a, 0x0f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x5f, 0x6c, 0x6f, 0x67, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x4c, 0x6f, 0x67, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x54, 0x0a, 0x11, 0x63, 0x6f, 0x6d, 0x70,
+	0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65,
+	0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x10, 0x63, 0x6f,
+	0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0xa0,
+	0x01, 0x0a, 0x12, 0x54, 0x72, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x72, 0x61, 0x74,
+	0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x0f, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x5f, 0x6c, 0x6f, 0x67, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x3f, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x22, 0xe7, 0x02, 0x0a, 0x13, 0x53, 0x69, 0x7a, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x45,
+	0x73, 0x63, 0x61, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x63, 0x0a, 0x0f, 0x66, 0x61, 0x69,
+	0x6c, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x3a, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x72,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x53, 0x69, 0x7a,
+	0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x45, 0x73, 0x63, 0x61, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x52, 0x0e,
+	0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x3c,
+	0x0a, 0x1a, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x73, 0x5f, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x18, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x73, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x1a, 0xac, 0x01, 0x0a,
+	0x0d, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x33, 0x0a,
+	0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f,
+	0x75, 0x74, 0x12, 0x2a, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1b,
+	0x0a, 0x09, 0x65, 0x78, 0x69, 0x74, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x65, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x42, 0x42, 0x5a, 0x40, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
+	0x61, 0x72, 0x6e, 0x2f, 0x62, 0x62, 0x2d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2d, 0x65, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x75, 0x73, 0x61, 0x67, 0x65, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_resourceusage_resourceusage_proto_rawDescOnce sync.Once
+	file_pkg_proto_resourceusage_resourceusage_proto_rawDescData = file_pkg_proto_resourceusage_resourceusage_proto_rawDesc
+)
+
+func file_pkg_proto_resourceusage_resourceusage_proto_rawDescGZIP() []byte {
+	file_pkg_proto_resourceusage_resourceusage_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_resourceusage_resourceusage_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_resourceusage_resourceusage_proto_rawDescData)
+	})
+	return file_pkg_proto_resourceusage_resourceusage_proto_rawDescData
+}
+
+var file_pkg_proto_resourceusage_resourceusage_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
+var file_pkg_proto_resourceusage_resourceusage_proto_goTypes = []interface{}{
+	(*FilePoolResourceUsage)(nil),                    // 0: buildbarn.resourceusage.FilePoolResourceUsage
+	(*POSIXResourceUsage)(nil),                       // 1: buildbarn.resourceusage.POSIXResourceUsage
+	(*MonetaryResourceUsage)(nil),                    // 2: buildbarn.resourceusage.MonetaryResourceUsage
+	(*InputRootResourceUsage)(nil),                   // 3: buildbarn.resourceusage.InputRootResourceUsage
+	(*InputRootReadFiles)(nil),                       // 4: buildbarn.resourceusage.InputRootReadFiles
+	(*MemoryResourceUsage)(nil),                      // 5: buildbarn.resourceusage.MemoryResourceUsage
+	(*SwapResourceUsage)(nil),                        // 6: buildbarn.resourceusage.SwapResourceUsage
+	(*BlockIOResourceUsage)(nil),                     // 7: buildbarn.resourceusage.BlockIOResourceUsage
+	(*BuildDirectoryResourceUsage)(nil),              // 8: buildbarn.resourceusage.BuildDirectoryResourceUsage
+	(*PIDsResourceUsage)(nil),                        // 9: buildbarn.resourceusage.PIDsResourceUsage
+	(*ProcessTreeResourceUsage)(nil),                 // 10: buildbarn.resourceusage.ProcessTreeResourceUsage
+	(*InputRootMinimizationReport)(nil),              // 11: buildbarn.resourceusage.InputRootMinimizationReport
+	(*ExecutionTimeoutCompensation)(nil),             // 12: buildbarn.resourceusage.ExecutionTimeoutCompensation
+	(*EgressProxyResourceUsage)(nil),                 // 13: buildbarn.resourceusage.EgressProxyResourceUsage
+	(*EnergyResourceUsage)(nil),                      // 14: buildbarn.resourceusage.EnergyResourceUsage
+	(*VirtualFileSystemResourceUsage)(nil),           // 15: buildbarn.resourceusage.VirtualFileSystemResourceUsage
+	(*ExecutionPhaseDurations)(nil),                  // 16: buildbarn.resourceusage.ExecutionPhaseDurations
+	(*TruncatedCommandOutputs)(nil),                  // 17: buildbarn.resourceusage.TruncatedCommandOutputs
+	(*LeakedProcessesResourceUsage)(nil),             // 18: buildbarn.resourceusage.LeakedProcessesResourceUsage
+	(*CoreDumpsResourceUsage)(nil),                   // 19: buildbarn.resourceusage.CoreDumpsResourceUsage
+	(*TraceResourceUsage)(nil),                       // 20: buildbarn.resourceusage.TraceResourceUsage
+	(*SizeClassEscalation)(nil),                      // 21: buildbarn.resourceusage.SizeClassEscalation
+	(*MonetaryResourceUsage_Expense)(nil),            // 22: buildbarn.resourceusage.MonetaryResourceUsage.Expense
+	nil,                                              // 23: buildbarn.resourceusage.MonetaryResourceUsage.ExpensesEntry
+	(*BlockIOResourceUsage_Device)(nil),              // 24: buildbarn.resourceusage.BlockIOResourceUsage.Device
+	(*ProcessTreeResourceUsage_Process)(nil),         // 25: buildbarn.resourceusage.ProcessTreeResourceUsage.Process
+	(*EgressProxyResourceUsage_Request)(nil),         // 26: buildbarn.resourceusage.EgressProxyResourceUsage.Request
+	(*VirtualFileSystemResourceUsage_Operation)(nil), // 27: buildbarn.resourceusage.VirtualFileSystemResourceUsage.Operation
+	(*TruncatedCommandOutputs_Output)(nil),           // 28: buildbarn.resourceusage.TruncatedCommandOutputs.Output
+	(*LeakedProcessesResourceUsage_CommandLine)(nil), // 29: buildbarn.resourceusage.LeakedProcessesResourceUsage.CommandLine
+	(*CoreDumpsResourceUsage_CoreDump)(nil),          // 30: buildbarn.resourceusage.CoreDumpsResourceUsage.CoreDump
+	(*SizeClassEscalation_FailedAttempt)(nil),        // 31: buildbarn.resourceusage.SizeClassEscalation.FailedAttempt
+	(*durationpb.Duration)(nil),                      // 32: google.protobuf.Duration
+	(*v2.Digest)(nil),                                // 33: build.bazel.remote.execution.v2.Digest
+	(*status.Status)(nil),                            // 34: google.rpc.Status
+}
+var file_pkg_proto_resourceusage_resourceusage_proto_depIdxs = []int32{
+	32, // 0: buildbarn.resourceusage.POSIXResourceUsage.user_time:type_name -> google.protobuf.Duration
+	32, // 1: buildbarn.resourceusage.POSIXResourceUsage.system_time:type_name -> google.protobuf.Duration
+	23, // 2: buildbarn.resourceusage.MonetaryResourceUsage.expenses:type_name -> buildbarn.resourceusage.MonetaryResourceUsage.ExpensesEntry
+	33, // 3: buildbarn.resourceusage.InputRootReadFiles.paths_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	24, // 4: buildbarn.resourceusage.BlockIOResourceUsage.devices:type_name -> buildbarn.resourceusage.BlockIOResourceUsage.Device
+	25, // 5: buildbarn.resourceusage.ProcessTreeResourceUsage.processes:type_name -> buildbarn.resourceusage.ProcessTreeResourceUsage.Process
+	33, // 6: buildbarn.resourceusage.InputRootMinimizationReport.unneeded_paths_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	33, // 7: buildbarn.resourceusage.InputRootMinimizationReport.minimal_input_root_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	32, // 8: buildbarn.resourceusage.ExecutionTimeoutCompensation.suspended_duration:type_name -> google.protobuf.Duration
+	26, // 9: buildbarn.resourceusage.EgressProxyResourceUsage.requests:type_name -> buildbarn.resourceusage.EgressProxyResourceUsage.Request
+	27, // 10: buildbarn.resourceusage.VirtualFileSystemResourceUsage.lookup:type_name -> buildbarn.resourceusage.VirtualFileSystemResourceUsage.Operation
+	27, // 11: buildbarn.resourceusage.VirtualFileSystemResourceUsage.get_attributes:type_name -> buildbarn.resourceusage.VirtualFileSystemResourceUsage.Operation
+	27, // 12: buildbarn.resourceusage.VirtualFileSystemResourceUsage.read:type_name -> buildbarn.resourceusage.VirtualFileSystemResourceUsage.Operation
+	27, // 13: buildbarn.resourceusage.VirtualFileSystemResourceUsage.read_dir:type_name -> buildbarn.resourceusage.VirtualFileSystemResourceUsage.Operation
+	32, // 14: buildbarn.resourceusage.ExecutionPhaseDurations.directory_fetch_duration:type_name -> google.protobuf.Duration
+	32, // 15: buildbarn.resourceusage.ExecutionPhaseDurations.file_fetch_duration:type_name -> google.protobuf.Duration
+	32, // 16: buildbarn.resourceusage.ExecutionPhaseDurations.output_hashing_duration:type_name -> google.protobuf.Duration
+	32, // 17: buildbarn.resourceusage.ExecutionPhaseDurations.find_missing_blobs_duration:type_name -> google.protobuf.Duration
+	32, // 18: buildbarn.resourceusage.ExecutionPhaseDurations.upload_duration:type_name -> google.protobuf.Duration
+	28, // 19: buildbarn.resourceusage.TruncatedCommandOutputs.stdout:type_name -> buildbarn.resourceusage.TruncatedCommandOutputs.Output
+	28, // 20: buildbarn.resourceusage.TruncatedCommandOutputs.stderr:type_name -> buildbarn.resourceusage.TruncatedCommandOutputs.Output
+	29, // 21: buildbarn.resourceusage.LeakedProcessesResourceUsage.command_lines:type_name -> buildbarn.resourceusage.LeakedProcessesResourceUsage.CommandLine
+	30, // 22: buildbarn.resourceusage.CoreDumpsResourceUsage.core_dumps:type_name -> buildbarn.resourceusage.CoreDumpsResourceUsage.CoreDump
+	33, // 23: buildbarn.resourceusage.TraceResourceUsage.digest:type_name -> build.bazel.remote.execution.v2.Digest
+	31, // 24: buildbarn.resourceusage.SizeClassEscalation.failed_attempts:type_name -> buildbarn.resourceusage.SizeClassEscalation.FailedAttempt
+	22, // 25: buildbarn.resourceusage.MonetaryResourceUsage.ExpensesEntry.value:type_name -> buildbarn.resourceusage.MonetaryResourceUsage.Expense
+	32, // 26: buildbarn.resourceusage.VirtualFileSystemResourceUsage.Operation.total_duration:type_name -> google.protobuf.Duration
+	32, // 27: buildbarn.resourceusage.VirtualFileSystemResourceUsage.Operation.p50_duration:type_name -> google.protobuf.Duration
+	32, // 28: buildbarn.resourceusage.VirtualFileSystemResourceUsage.Operation.p90_duration:type_name -> google.protobuf.Duration
+	32, // 29: buildbarn.resourceusage.VirtualFileSystemResourceUsage.Operation.p99_duration:type_name -> google.protobuf.Duration
+	32, // 30: buildbarn.resourceusage.VirtualFileSystemResourceUsage.Operation.maximum_duration:type_name -> google.protobuf.Duration
+	33, // 31: buildbarn.resourceusage.TruncatedCommandOutputs.Output.original_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	33, // 32: buildbarn.resourceusage.CoreDumpsResourceUsage.CoreDump.compressed_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	32, // 33: buildbarn.resourceusage.SizeClassEscalation.FailedAttempt.timeout:type_name -> google.protobuf.Duration
+	34, // 34: buildbarn.resourceusage.SizeClassEscalation.FailedAttempt.status:type_name -> google.rpc.Status
+	35, // [35:35] is the sub-list for method output_type
+	35, // [35:35] is the sub-list for method input_type
+	35, // [35:35] is the sub-list for extension type_name
+	35, // [35:35] is the sub-list for extension extendee
+	0,  // [0:35] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_resourceusage_resourceusage_proto_init() }
+func file_pkg_proto_resourceusage_resourceusage_proto_init() {
+	if File_pkg_proto_resourceusage_resourceusage_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FilePoolResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*POSIXResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MonetaryResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InputRootResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InputRootReadFiles); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
 		}
-		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MemoryResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SwapResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockIOResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildDirectoryResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PIDsResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProcessTreeResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InputRootMinimizationReport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecutionTimeoutCompensation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EgressProxyResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnergyResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VirtualFileSystemResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecutionPhaseDurations); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TruncatedCommandOutputs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LeakedProcessesResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CoreDumpsResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TraceResourceUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SizeClassEscalation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*MonetaryResourceUsage_Expense); i {
 			case 0:
 				return &v.state
@@ -651,6 +3004,102 @@ func file_pkg_proto_resourceusage_resourceusage_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockIOResourceUsage_Device); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProcessTreeResourceUsage_Process); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EgressProxyResourceUsage_Request); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VirtualFileSystemResourceUsage_Operation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TruncatedCommandOutputs_Output); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LeakedProcessesResourceUsage_CommandLine); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CoreDumpsResourceUsage_CoreDump); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_resourceusage_resourceusage_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SizeClassEscalation_FailedAttempt); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -658,7 +3107,7 @@ func file_pkg_proto_resourceusage_resourceusage_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_proto_resourceusage_resourceusage_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   32,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
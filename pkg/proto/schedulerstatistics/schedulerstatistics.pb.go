@@ -0,0 +1,484 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        v4.23.4
+// source: pkg/proto/schedulerstatistics/schedulerstatistics.proto
+
+package schedulerstatistics
+
+import (
+	buildqueuestate "github.com/buildbarn/bb-remote-execution/pkg/proto/buildqueuestate"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Statistics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SizeClassQueues []*SizeClassQueue `protobuf:"bytes,1,rep,name=size_class_queues,json=sizeClassQueues,proto3" json:"size_class_queues,omitempty"`
+}
+
+func (x *Statistics) Reset() {
+	*x = Statistics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Statistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Statistics) ProtoMessage() {}
+
+func (x *Statistics) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Statistics.ProtoReflect.Descriptor instead.
+func (*Statistics) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Statistics) GetSizeClassQueues() []*SizeClassQueue {
+	if x != nil {
+		return x.SizeClassQueues
+	}
+	return nil
+}
+
+type SizeClassQueue struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SizeClassQueueName            *buildqueuestate.SizeClassQueueName         `protobuf:"bytes,1,opt,name=size_class_queue_name,json=sizeClassQueueName,proto3" json:"size_class_queue_name,omitempty"`
+	TasksArrived                  *ExponentiallyDecayingSum                   `protobuf:"bytes,2,opt,name=tasks_arrived,json=tasksArrived,proto3" json:"tasks_arrived,omitempty"`
+	TasksExecuted                 *ExponentiallyDecayingSum                   `protobuf:"bytes,3,opt,name=tasks_executed,json=tasksExecuted,proto3" json:"tasks_executed,omitempty"`
+	TasksExecutingDurationSeconds *ExponentiallyDecayingSum                   `protobuf:"bytes,4,opt,name=tasks_executing_duration_seconds,json=tasksExecutingDurationSeconds,proto3" json:"tasks_executing_duration_seconds,omitempty"`
+	ExecutingDurationHistogram    *buildqueuestate.ExecutingDurationHistogram `protobuf:"bytes,5,opt,name=executing_duration_histogram,json=executingDurationHistogram,proto3" json:"executing_duration_histogram,omitempty"`
+	Workers                       []*Worker                                   `protobuf:"bytes,6,rep,name=workers,proto3" json:"workers,omitempty"`
+}
+
+func (x *SizeClassQueue) Reset() {
+	*x = SizeClassQueue{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SizeClassQueue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SizeClassQueue) ProtoMessage() {}
+
+func (x *SizeClassQueue) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SizeClassQueue.ProtoReflect.Descriptor instead.
+func (*SizeClassQueue) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SizeClassQueue) GetSizeClassQueueName() *buildqueuestate.SizeClassQueueName {
+	if x != nil {
+		return x.SizeClassQueueName
+	}
+	return nil
+}
+
+func (x *SizeClassQueue) GetTasksArrived() *ExponentiallyDecayingSum {
+	if x != nil {
+		return x.TasksArrived
+	}
+	return nil
+}
+
+func (x *SizeClassQueue) GetTasksExecuted() *ExponentiallyDecayingSum {
+	if x != nil {
+		return x.TasksExecuted
+	}
+	return nil
+}
+
+func (x *SizeClassQueue) GetTasksExecutingDurationSeconds() *ExponentiallyDecayingSum {
+	if x != nil {
+		return x.TasksExecutingDurationSeconds
+	}
+	return nil
+}
+
+func (x *SizeClassQueue) GetExecutingDurationHistogram() *buildqueuestate.ExecutingDurationHistogram {
+	if x != nil {
+		return x.ExecutingDurationHistogram
+	}
+	return nil
+}
+
+func (x *SizeClassQueue) GetWorkers() []*Worker {
+	if x != nil {
+		return x.Workers
+	}
+	return nil
+}
+
+type ExponentiallyDecayingSum struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value      float64                `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	LastUpdate *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=last_update,json=lastUpdate,proto3" json:"last_update,omitempty"`
+}
+
+func (x *ExponentiallyDecayingSum) Reset() {
+	*x = ExponentiallyDecayingSum{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExponentiallyDecayingSum) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExponentiallyDecayingSum) ProtoMessage() {}
+
+func (x *ExponentiallyDecayingSum) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExponentiallyDecayingSum.ProtoReflect.Descriptor instead.
+func (*ExponentiallyDecayingSum) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ExponentiallyDecayingSum) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *ExponentiallyDecayingSum) GetLastUpdate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdate
+	}
+	return nil
+}
+
+type Worker struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WorkerId                       map[string]string      `protobuf:"bytes,1,rep,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	RecentTaskInfrastructureErrors []bool                 `protobuf:"varint,2,rep,packed,name=recent_task_infrastructure_errors,json=recentTaskInfrastructureErrors,proto3" json:"recent_task_infrastructure_errors,omitempty"`
+	QuarantinedUntil               *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=quarantined_until,json=quarantinedUntil,proto3" json:"quarantined_until,omitempty"`
+}
+
+func (x *Worker) Reset() {
+	*x = Worker{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Worker) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Worker) ProtoMessage() {}
+
+func (x *Worker) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Worker.ProtoReflect.Descriptor instead.
+func (*Worker) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Worker) GetWorkerId() map[string]string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return nil
+}
+
+func (x *Worker) GetRecentTaskInfrastructureErrors() []bool {
+	if x != nil {
+		return x.RecentTaskInfrastructureErrors
+	}
+	return nil
+}
+
+func (x *Worker) GetQuarantinedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.QuarantinedUntil
+	}
+	return nil
+}
+
+var File_pkg_proto_schedulerstatistics_schedulerstatistics_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_rawDesc = []byte{
+	0x0a, 0x37, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x72, 0x73, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x2f,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x73, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74,
+	0x69, 0x63, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1d, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x73, 0x74,
+	0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x71, 0x75, 0x65, 0x75, 0x65, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x71, 0x75, 0x65, 0x75, 0x65, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x67, 0x0a, 0x0a, 0x53, 0x74,
+	0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x59, 0x0a, 0x11, 0x73, 0x69, 0x7a, 0x65,
+	0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x5f, 0x71, 0x75, 0x65, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x73, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74,
+	0x69, 0x63, 0x73, 0x2e, 0x53, 0x69, 0x7a, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x51, 0x75, 0x65,
+	0x75, 0x65, 0x52, 0x0f, 0x73, 0x69, 0x7a, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x51, 0x75, 0x65,
+	0x75, 0x65, 0x73, 0x22, 0xed, 0x04, 0x0a, 0x0e, 0x53, 0x69, 0x7a, 0x65, 0x43, 0x6c, 0x61, 0x73,
+	0x73, 0x51, 0x75, 0x65, 0x75, 0x65, 0x12, 0x60, 0x0a, 0x15, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x63,
+	0x6c, 0x61, 0x73, 0x73, 0x5f, 0x71, 0x75, 0x65, 0x75, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72,
+	0x6e, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x71, 0x75, 0x65, 0x75, 0x65, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x2e, 0x53, 0x69, 0x7a, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x51, 0x75, 0x65, 0x75, 0x65,
+	0x4e, 0x61, 0x6d, 0x65, 0x52, 0x12, 0x73, 0x69, 0x7a, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x51,
+	0x75, 0x65, 0x75, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x5c, 0x0a, 0x0d, 0x74, 0x61, 0x73, 0x6b,
+	0x73, 0x5f, 0x61, 0x72, 0x72, 0x69, 0x76, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x37, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x73, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x72, 0x73, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x2e,
+	0x45, 0x78, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x6c, 0x79, 0x44, 0x65, 0x63,
+	0x61, 0x79, 0x69, 0x6e, 0x67, 0x53, 0x75, 0x6d, 0x52, 0x0c, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x41,
+	0x72, 0x72, 0x69, 0x76, 0x65, 0x64, 0x12, 0x5e, 0x0a, 0x0e, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x5f,
+	0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x37,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x73, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x72, 0x73, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x2e, 0x45,
+	0x78, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x6c, 0x79, 0x44, 0x65, 0x63, 0x61,
+	0x79, 0x69, 0x6e, 0x67, 0x53, 0x75, 0x6d, 0x52, 0x0d, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x45, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x65, 0x64, 0x12, 0x80, 0x01, 0x0a, 0x20, 0x74, 0x61, 0x73, 0x6b, 0x73,
+	0x5f, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x37, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x73, 0x63,
+	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x73, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63,
+	0x73, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x6c, 0x79, 0x44,
+	0x65, 0x63, 0x61, 0x79, 0x69, 0x6e, 0x67, 0x53, 0x75, 0x6d, 0x52, 0x1d, 0x74, 0x61, 0x73, 0x6b,
+	0x73, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x77, 0x0a, 0x1c, 0x65, 0x78, 0x65,
+	0x63, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x68, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x35, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x71, 0x75, 0x65, 0x75, 0x65, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x45, 0x78, 0x65, 0x63,
+	0x75, 0x74, 0x69, 0x6e, 0x67, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x52, 0x1a, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6e,
+	0x67, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72,
+	0x61, 0x6d, 0x12, 0x3f, 0x0a, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x06, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x73, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74,
+	0x69, 0x63, 0x73, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x07, 0x77, 0x6f, 0x72, 0x6b,
+	0x65, 0x72, 0x73, 0x22, 0x6d, 0x0a, 0x18, 0x45, 0x78, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x69,
+	0x61, 0x6c, 0x6c, 0x79, 0x44, 0x65, 0x63, 0x61, 0x79, 0x69, 0x6e, 0x67, 0x53, 0x75, 0x6d, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x3b, 0x0a, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x22, 0xab, 0x02, 0x0a, 0x06, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x12, 0x50, 0x0a,
+	0x09, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x33, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x73, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x72, 0x73, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73,
+	0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x49, 0x64,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x49, 0x64, 0x12,
+	0x49, 0x0a, 0x21, 0x72, 0x65, 0x63, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x61, 0x73, 0x6b, 0x5f, 0x69,
+	0x6e, 0x66, 0x72, 0x61, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x08, 0x52, 0x1e, 0x72, 0x65, 0x63, 0x65,
+	0x6e, 0x74, 0x54, 0x61, 0x73, 0x6b, 0x49, 0x6e, 0x66, 0x72, 0x61, 0x73, 0x74, 0x72, 0x75, 0x63,
+	0x74, 0x75, 0x72, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x47, 0x0a, 0x11, 0x71, 0x75,
+	0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64, 0x5f, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x10, 0x71, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64, 0x55, 0x6e,
+	0x74, 0x69, 0x6c, 0x1a, 0x3b, 0x0a, 0x0d, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x49, 0x64, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x42, 0x48, 0x5a, 0x46, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2f, 0x62, 0x62, 0x2d, 0x72, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x2d, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x70, 0x6b, 0x67,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x72,
+	0x73, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_rawDescOnce sync.Once
+	file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_rawDescData = file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_rawDesc
+)
+
+func file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_rawDescGZIP() []byte {
+	file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_rawDescData)
+	})
+	return file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_rawDescData
+}
+
+var file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_goTypes = []interface{}{
+	(*Statistics)(nil),               // 0: buildbarn.schedulerstatistics.Statistics
+	(*SizeClassQueue)(nil),           // 1: buildbarn.schedulerstatistics.SizeClassQueue
+	(*ExponentiallyDecayingSum)(nil), // 2: buildbarn.schedulerstatistics.ExponentiallyDecayingSum
+	(*Worker)(nil),                   // 3: buildbarn.schedulerstatistics.Worker
+	nil,                              // 4: buildbarn.schedulerstatistics.Worker.WorkerIdEntry
+	(*buildqueuestate.SizeClassQueueName)(nil),         // 5: buildbarn.buildqueuestate.SizeClassQueueName
+	(*buildqueuestate.ExecutingDurationHistogram)(nil), // 6: buildbarn.buildqueuestate.ExecutingDurationHistogram
+	(*timestamppb.Timestamp)(nil),                      // 7: google.protobuf.Timestamp
+}
+var file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_depIdxs = []int32{
+	1,  // 0: buildbarn.schedulerstatistics.Statistics.size_class_queues:type_name -> buildbarn.schedulerstatistics.SizeClassQueue
+	5,  // 1: buildbarn.schedulerstatistics.SizeClassQueue.size_class_queue_name:type_name -> buildbarn.buildqueuestate.SizeClassQueueName
+	2,  // 2: buildbarn.schedulerstatistics.SizeClassQueue.tasks_arrived:type_name -> buildbarn.schedulerstatistics.ExponentiallyDecayingSum
+	2,  // 3: buildbarn.schedulerstatistics.SizeClassQueue.tasks_executed:type_name -> buildbarn.schedulerstatistics.ExponentiallyDecayingSum
+	2,  // 4: buildbarn.schedulerstatistics.SizeClassQueue.tasks_executing_duration_seconds:type_name -> buildbarn.schedulerstatistics.ExponentiallyDecayingSum
+	6,  // 5: buildbarn.schedulerstatistics.SizeClassQueue.executing_duration_histogram:type_name -> buildbarn.buildqueuestate.ExecutingDurationHistogram
+	3,  // 6: buildbarn.schedulerstatistics.SizeClassQueue.workers:type_name -> buildbarn.schedulerstatistics.Worker
+	7,  // 7: buildbarn.schedulerstatistics.ExponentiallyDecayingSum.last_update:type_name -> google.protobuf.Timestamp
+	4,  // 8: buildbarn.schedulerstatistics.Worker.worker_id:type_name -> buildbarn.schedulerstatistics.Worker.WorkerIdEntry
+	7,  // 9: buildbarn.schedulerstatistics.Worker.quarantined_until:type_name -> google.protobuf.Timestamp
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_init() }
+func file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_init() {
+	if File_pkg_proto_schedulerstatistics_schedulerstatistics_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Statistics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SizeClassQueue); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExponentiallyDecayingSum); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Worker); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_depIdxs,
+		MessageInfos:      file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_schedulerstatistics_schedulerstatistics_proto = out.File
+	file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_rawDesc = nil
+	file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_goTypes = nil
+	file_pkg_proto_schedulerstatistics_schedulerstatistics_proto_depIdxs = nil
+}
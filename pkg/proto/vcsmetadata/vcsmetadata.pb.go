@@ -0,0 +1,157 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        v4.23.4
+// source: pkg/proto/vcsmetadata/vcsmetadata.proto
+
+package vcsmetadata
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type VcsMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CommitSha string `protobuf:"bytes,1,opt,name=commit_sha,json=commitSha,proto3" json:"commit_sha,omitempty"`
+	Dirty     bool   `protobuf:"varint,2,opt,name=dirty,proto3" json:"dirty,omitempty"`
+}
+
+func (x *VcsMetadata) Reset() {
+	*x = VcsMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_vcsmetadata_vcsmetadata_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VcsMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VcsMetadata) ProtoMessage() {}
+
+func (x *VcsMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_vcsmetadata_vcsmetadata_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VcsMetadata.ProtoReflect.Descriptor instead.
+func (*VcsMetadata) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_vcsmetadata_vcsmetadata_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *VcsMetadata) GetCommitSha() string {
+	if x != nil {
+		return x.CommitSha
+	}
+	return ""
+}
+
+func (x *VcsMetadata) GetDirty() bool {
+	if x != nil {
+		return x.Dirty
+	}
+	return false
+}
+
+var File_pkg_proto_vcsmetadata_vcsmetadata_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_vcsmetadata_vcsmetadata_proto_rawDesc = []byte{
+	0x0a, 0x27, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x63, 0x73, 0x6d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2f, 0x76, 0x63, 0x73, 0x6d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x15, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2e, 0x76, 0x63, 0x73, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x22, 0x42, 0x0a, 0x0b, 0x56, 0x63, 0x73, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12,
+	0x1d, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x73, 0x68, 0x61, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61, 0x12, 0x14,
+	0x0a, 0x05, 0x64, 0x69, 0x72, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x64,
+	0x69, 0x72, 0x74, 0x79, 0x42, 0x40, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2f, 0x62, 0x62, 0x2d,
+	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2d, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e,
+	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x63, 0x73, 0x6d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_vcsmetadata_vcsmetadata_proto_rawDescOnce sync.Once
+	file_pkg_proto_vcsmetadata_vcsmetadata_proto_rawDescData = file_pkg_proto_vcsmetadata_vcsmetadata_proto_rawDesc
+)
+
+func file_pkg_proto_vcsmetadata_vcsmetadata_proto_rawDescGZIP() []byte {
+	file_pkg_proto_vcsmetadata_vcsmetadata_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_vcsmetadata_vcsmetadata_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_vcsmetadata_vcsmetadata_proto_rawDescData)
+	})
+	return file_pkg_proto_vcsmetadata_vcsmetadata_proto_rawDescData
+}
+
+var file_pkg_proto_vcsmetadata_vcsmetadata_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_pkg_proto_vcsmetadata_vcsmetadata_proto_goTypes = []interface{}{
+	(*VcsMetadata)(nil), // 0: buildbarn.vcsmetadata.VcsMetadata
+}
+var file_pkg_proto_vcsmetadata_vcsmetadata_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_vcsmetadata_vcsmetadata_proto_init() }
+func file_pkg_proto_vcsmetadata_vcsmetadata_proto_init() {
+	if File_pkg_proto_vcsmetadata_vcsmetadata_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_proto_vcsmetadata_vcsmetadata_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VcsMetadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_proto_vcsmetadata_vcsmetadata_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pkg_proto_vcsmetadata_vcsmetadata_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_vcsmetadata_vcsmetadata_proto_depIdxs,
+		MessageInfos:      file_pkg_proto_vcsmetadata_vcsmetadata_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_vcsmetadata_vcsmetadata_proto = out.File
+	file_pkg_proto_vcsmetadata_vcsmetadata_proto_rawDesc = nil
+	file_pkg_proto_vcsmetadata_vcsmetadata_proto_goTypes = nil
+	file_pkg_proto_vcsmetadata_vcsmetadata_proto_depIdxs = nil
+}
@@ -0,0 +1,940 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        v4.23.4
+// source: pkg/proto/workeradmin/workeradmin.proto
+
+package workeradmin
+
+import (
+	context "context"
+	v2 "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	runner "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RunnerState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RunnerIndex uint32       `protobuf:"varint,1,opt,name=runner_index,json=runnerIndex,proto3" json:"runner_index,omitempty"`
+	Platform    *v2.Platform `protobuf:"bytes,2,opt,name=platform,proto3" json:"platform,omitempty"`
+	SizeClass   uint32       `protobuf:"varint,3,opt,name=size_class,json=sizeClass,proto3" json:"size_class,omitempty"`
+}
+
+func (x *RunnerState) Reset() {
+	*x = RunnerState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunnerState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunnerState) ProtoMessage() {}
+
+func (x *RunnerState) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunnerState.ProtoReflect.Descriptor instead.
+func (*RunnerState) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_workeradmin_workeradmin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RunnerState) GetRunnerIndex() uint32 {
+	if x != nil {
+		return x.RunnerIndex
+	}
+	return 0
+}
+
+func (x *RunnerState) GetPlatform() *v2.Platform {
+	if x != nil {
+		return x.Platform
+	}
+	return nil
+}
+
+func (x *RunnerState) GetSizeClass() uint32 {
+	if x != nil {
+		return x.SizeClass
+	}
+	return 0
+}
+
+type ListRunnersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Runners []*RunnerState `protobuf:"bytes,1,rep,name=runners,proto3" json:"runners,omitempty"`
+}
+
+func (x *ListRunnersResponse) Reset() {
+	*x = ListRunnersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListRunnersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRunnersResponse) ProtoMessage() {}
+
+func (x *ListRunnersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRunnersResponse.ProtoReflect.Descriptor instead.
+func (*ListRunnersResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_workeradmin_workeradmin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListRunnersResponse) GetRunners() []*RunnerState {
+	if x != nil {
+		return x.Runners
+	}
+	return nil
+}
+
+type SetRunnerPlatformRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RunnerIndex uint32       `protobuf:"varint,1,opt,name=runner_index,json=runnerIndex,proto3" json:"runner_index,omitempty"`
+	Platform    *v2.Platform `protobuf:"bytes,2,opt,name=platform,proto3" json:"platform,omitempty"`
+	SizeClass   uint32       `protobuf:"varint,3,opt,name=size_class,json=sizeClass,proto3" json:"size_class,omitempty"`
+}
+
+func (x *SetRunnerPlatformRequest) Reset() {
+	*x = SetRunnerPlatformRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetRunnerPlatformRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRunnerPlatformRequest) ProtoMessage() {}
+
+func (x *SetRunnerPlatformRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRunnerPlatformRequest.ProtoReflect.Descriptor instead.
+func (*SetRunnerPlatformRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_workeradmin_workeradmin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SetRunnerPlatformRequest) GetRunnerIndex() uint32 {
+	if x != nil {
+		return x.RunnerIndex
+	}
+	return 0
+}
+
+func (x *SetRunnerPlatformRequest) GetPlatform() *v2.Platform {
+	if x != nil {
+		return x.Platform
+	}
+	return nil
+}
+
+func (x *SetRunnerPlatformRequest) GetSizeClass() uint32 {
+	if x != nil {
+		return x.SizeClass
+	}
+	return 0
+}
+
+type ExecutionStage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	Duration  *durationpb.Duration   `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+}
+
+func (x *ExecutionStage) Reset() {
+	*x = ExecutionStage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecutionStage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionStage) ProtoMessage() {}
+
+func (x *ExecutionStage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionStage.ProtoReflect.Descriptor instead.
+func (*ExecutionStage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_workeradmin_workeradmin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ExecutionStage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExecutionStage) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ExecutionStage) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+type ExecutingAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ActionId           uint64                  `protobuf:"varint,1,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	RunnerIndex        uint32                  `protobuf:"varint,2,opt,name=runner_index,json=runnerIndex,proto3" json:"runner_index,omitempty"`
+	InstanceName       string                  `protobuf:"bytes,3,opt,name=instance_name,json=instanceName,proto3" json:"instance_name,omitempty"`
+	DigestFunction     v2.DigestFunction_Value `protobuf:"varint,4,opt,name=digest_function,json=digestFunction,proto3,enum=build.bazel.remote.execution.v2.DigestFunction_Value" json:"digest_function,omitempty"`
+	ActionDigest       *v2.Digest              `protobuf:"bytes,5,opt,name=action_digest,json=actionDigest,proto3" json:"action_digest,omitempty"`
+	Stages             []*ExecutionStage       `protobuf:"bytes,6,rep,name=stages,proto3" json:"stages,omitempty"`
+	FilePoolFilesCount uint64                  `protobuf:"varint,7,opt,name=file_pool_files_count,json=filePoolFilesCount,proto3" json:"file_pool_files_count,omitempty"`
+	FilePoolSizeBytes  uint64                  `protobuf:"varint,8,opt,name=file_pool_size_bytes,json=filePoolSizeBytes,proto3" json:"file_pool_size_bytes,omitempty"`
+}
+
+func (x *ExecutingAction) Reset() {
+	*x = ExecutingAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecutingAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutingAction) ProtoMessage() {}
+
+func (x *ExecutingAction) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutingAction.ProtoReflect.Descriptor instead.
+func (*ExecutingAction) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_workeradmin_workeradmin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ExecutingAction) GetActionId() uint64 {
+	if x != nil {
+		return x.ActionId
+	}
+	return 0
+}
+
+func (x *ExecutingAction) GetRunnerIndex() uint32 {
+	if x != nil {
+		return x.RunnerIndex
+	}
+	return 0
+}
+
+func (x *ExecutingAction) GetInstanceName() string {
+	if x != nil {
+		return x.InstanceName
+	}
+	return ""
+}
+
+func (x *ExecutingAction) GetDigestFunction() v2.DigestFunction_Value {
+	if x != nil {
+		return x.DigestFunction
+	}
+	return v2.DigestFunction_Value(0)
+}
+
+func (x *ExecutingAction) GetActionDigest() *v2.Digest {
+	if x != nil {
+		return x.ActionDigest
+	}
+	return nil
+}
+
+func (x *ExecutingAction) GetStages() []*ExecutionStage {
+	if x != nil {
+		return x.Stages
+	}
+	return nil
+}
+
+func (x *ExecutingAction) GetFilePoolFilesCount() uint64 {
+	if x != nil {
+		return x.FilePoolFilesCount
+	}
+	return 0
+}
+
+func (x *ExecutingAction) GetFilePoolSizeBytes() uint64 {
+	if x != nil {
+		return x.FilePoolSizeBytes
+	}
+	return 0
+}
+
+type ListExecutingActionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Actions []*ExecutingAction `protobuf:"bytes,1,rep,name=actions,proto3" json:"actions,omitempty"`
+}
+
+func (x *ListExecutingActionsResponse) Reset() {
+	*x = ListExecutingActionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListExecutingActionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListExecutingActionsResponse) ProtoMessage() {}
+
+func (x *ListExecutingActionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListExecutingActionsResponse.ProtoReflect.Descriptor instead.
+func (*ListExecutingActionsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_workeradmin_workeradmin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListExecutingActionsResponse) GetActions() []*ExecutingAction {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+type GetActionProcessTreeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ActionId uint64 `protobuf:"varint,1,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+}
+
+func (x *GetActionProcessTreeRequest) Reset() {
+	*x = GetActionProcessTreeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetActionProcessTreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetActionProcessTreeRequest) ProtoMessage() {}
+
+func (x *GetActionProcessTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetActionProcessTreeRequest.ProtoReflect.Descriptor instead.
+func (*GetActionProcessTreeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_workeradmin_workeradmin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetActionProcessTreeRequest) GetActionId() uint64 {
+	if x != nil {
+		return x.ActionId
+	}
+	return 0
+}
+
+var File_pkg_proto_workeradmin_workeradmin_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_workeradmin_workeradmin_proto_rawDesc = []byte{
+	0x0a, 0x27, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x77, 0x6f, 0x72, 0x6b,
+	0x65, 0x72, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2f, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x61, 0x64,
+	0x6d, 0x69, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x15, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x61, 0x64, 0x6d, 0x69, 0x6e,
+	0x1a, 0x36, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2f, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2f, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x2f, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x76,
+	0x32, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1d, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2f, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x2f, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x96, 0x01, 0x0a, 0x0b, 0x52, 0x75, 0x6e, 0x6e, 0x65, 0x72,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x5f,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x72, 0x75, 0x6e,
+	0x6e, 0x65, 0x72, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x45, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74,
+	0x66, 0x6f, 0x72, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e,
+	0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x50, 0x6c, 0x61,
+	0x74, 0x66, 0x6f, 0x72, 0x6d, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12,
+	0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x22, 0x53,
+	0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x07, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61,
+	0x72, 0x6e, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x52,
+	0x75, 0x6e, 0x6e, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x07, 0x72, 0x75, 0x6e, 0x6e,
+	0x65, 0x72, 0x73, 0x22, 0xa3, 0x01, 0x0a, 0x18, 0x53, 0x65, 0x74, 0x52, 0x75, 0x6e, 0x6e, 0x65,
+	0x72, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x21, 0x0a, 0x0c, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x12, 0x45, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61,
+	0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d,
+	0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69,
+	0x7a, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09,
+	0x73, 0x69, 0x7a, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x22, 0x96, 0x01, 0x0a, 0x0e, 0x45, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x64,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0xc7, 0x03, 0x0a, 0x0f, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6e, 0x67,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x72, 0x75, 0x6e, 0x6e, 0x65,
+	0x72, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x5e, 0x0a, 0x0f, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x35, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a,
+	0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0e, 0x64, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x4c, 0x0a, 0x0d, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c,
+	0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f,
+	0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x0c, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x3d, 0x0a, 0x06, 0x73, 0x74, 0x61,
+	0x67, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x61, 0x64, 0x6d, 0x69,
+	0x6e, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x67, 0x65,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x67, 0x65, 0x73, 0x12, 0x31, 0x0a, 0x15, 0x66, 0x69, 0x6c, 0x65,
+	0x5f, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x66, 0x69, 0x6c, 0x65, 0x50, 0x6f, 0x6f,
+	0x6c, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x14, 0x66,
+	0x69, 0x6c, 0x65, 0x5f, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79,
+	0x74, 0x65, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x66, 0x69, 0x6c, 0x65, 0x50,
+	0x6f, 0x6f, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x60, 0x0a, 0x1c,
+	0x4c, 0x69, 0x73, 0x74, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x41, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x07,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72,
+	0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x41,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x3a,
+	0x0a, 0x1b, 0x47, 0x65, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x63, 0x65,
+	0x73, 0x73, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a,
+	0x09, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x08, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x32, 0x99, 0x03, 0x0a, 0x0b, 0x57,
+	0x6f, 0x72, 0x6b, 0x65, 0x72, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x51, 0x0a, 0x0b, 0x4c, 0x69,
+	0x73, 0x74, 0x52, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x1a, 0x2a, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x77, 0x6f,
+	0x72, 0x6b, 0x65, 0x72, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x75,
+	0x6e, 0x6e, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a,
+	0x11, 0x53, 0x65, 0x74, 0x52, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f,
+	0x72, 0x6d, 0x12, 0x2f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x77,
+	0x6f, 0x72, 0x6b, 0x65, 0x72, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x53, 0x65, 0x74, 0x52, 0x75,
+	0x6e, 0x6e, 0x65, 0x72, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x63, 0x0a, 0x14, 0x4c,
+	0x69, 0x73, 0x74, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x33, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x61, 0x64,
+	0x6d, 0x69, 0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6e,
+	0x67, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x74, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f,
+	0x63, 0x65, 0x73, 0x73, 0x54, 0x72, 0x65, 0x65, 0x12, 0x32, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x61, 0x64, 0x6d, 0x69, 0x6e,
+	0x2e, 0x47, 0x65, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x2e,
+	0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x40, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2f, 0x62,
+	0x62, 0x2d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2d, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69,
+	0x6f, 0x6e, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x77, 0x6f, 0x72,
+	0x6b, 0x65, 0x72, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_workeradmin_workeradmin_proto_rawDescOnce sync.Once
+	file_pkg_proto_workeradmin_workeradmin_proto_rawDescData = file_pkg_proto_workeradmin_workeradmin_proto_rawDesc
+)
+
+func file_pkg_proto_workeradmin_workeradmin_proto_rawDescGZIP() []byte {
+	file_pkg_proto_workeradmin_workeradmin_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_workeradmin_workeradmin_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_workeradmin_workeradmin_proto_rawDescData)
+	})
+	return file_pkg_proto_workeradmin_workeradmin_proto_rawDescData
+}
+
+var file_pkg_proto_workeradmin_workeradmin_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_pkg_proto_workeradmin_workeradmin_proto_goTypes = []interface{}{
+	(*RunnerState)(nil),                   // 0: buildbarn.workeradmin.RunnerState
+	(*ListRunnersResponse)(nil),           // 1: buildbarn.workeradmin.ListRunnersResponse
+	(*SetRunnerPlatformRequest)(nil),      // 2: buildbarn.workeradmin.SetRunnerPlatformRequest
+	(*ExecutionStage)(nil),                // 3: buildbarn.workeradmin.ExecutionStage
+	(*ExecutingAction)(nil),               // 4: buildbarn.workeradmin.ExecutingAction
+	(*ListExecutingActionsResponse)(nil),  // 5: buildbarn.workeradmin.ListExecutingActionsResponse
+	(*GetActionProcessTreeRequest)(nil),   // 6: buildbarn.workeradmin.GetActionProcessTreeRequest
+	(*v2.Platform)(nil),                   // 7: build.bazel.remote.execution.v2.Platform
+	(*timestamppb.Timestamp)(nil),         // 8: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),           // 9: google.protobuf.Duration
+	(v2.DigestFunction_Value)(0),          // 10: build.bazel.remote.execution.v2.DigestFunction.Value
+	(*v2.Digest)(nil),                     // 11: build.bazel.remote.execution.v2.Digest
+	(*emptypb.Empty)(nil),                 // 12: google.protobuf.Empty
+	(*runner.GetProcessTreeResponse)(nil), // 13: buildbarn.runner.GetProcessTreeResponse
+}
+var file_pkg_proto_workeradmin_workeradmin_proto_depIdxs = []int32{
+	7,  // 0: buildbarn.workeradmin.RunnerState.platform:type_name -> build.bazel.remote.execution.v2.Platform
+	0,  // 1: buildbarn.workeradmin.ListRunnersResponse.runners:type_name -> buildbarn.workeradmin.RunnerState
+	7,  // 2: buildbarn.workeradmin.SetRunnerPlatformRequest.platform:type_name -> build.bazel.remote.execution.v2.Platform
+	8,  // 3: buildbarn.workeradmin.ExecutionStage.start_time:type_name -> google.protobuf.Timestamp
+	9,  // 4: buildbarn.workeradmin.ExecutionStage.duration:type_name -> google.protobuf.Duration
+	10, // 5: buildbarn.workeradmin.ExecutingAction.digest_function:type_name -> build.bazel.remote.execution.v2.DigestFunction.Value
+	11, // 6: buildbarn.workeradmin.ExecutingAction.action_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	3,  // 7: buildbarn.workeradmin.ExecutingAction.stages:type_name -> buildbarn.workeradmin.ExecutionStage
+	4,  // 8: buildbarn.workeradmin.ListExecutingActionsResponse.actions:type_name -> buildbarn.workeradmin.ExecutingAction
+	12, // 9: buildbarn.workeradmin.WorkerAdmin.ListRunners:input_type -> google.protobuf.Empty
+	2,  // 10: buildbarn.workeradmin.WorkerAdmin.SetRunnerPlatform:input_type -> buildbarn.workeradmin.SetRunnerPlatformRequest
+	12, // 11: buildbarn.workeradmin.WorkerAdmin.ListExecutingActions:input_type -> google.protobuf.Empty
+	6,  // 12: buildbarn.workeradmin.WorkerAdmin.GetActionProcessTree:input_type -> buildbarn.workeradmin.GetActionProcessTreeRequest
+	1,  // 13: buildbarn.workeradmin.WorkerAdmin.ListRunners:output_type -> buildbarn.workeradmin.ListRunnersResponse
+	12, // 14: buildbarn.workeradmin.WorkerAdmin.SetRunnerPlatform:output_type -> google.protobuf.Empty
+	5,  // 15: buildbarn.workeradmin.WorkerAdmin.ListExecutingActions:output_type -> buildbarn.workeradmin.ListExecutingActionsResponse
+	13, // 16: buildbarn.workeradmin.WorkerAdmin.GetActionProcessTree:output_type -> buildbarn.runner.GetProcessTreeResponse
+	13, // [13:17] is the sub-list for method output_type
+	9,  // [9:13] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_workeradmin_workeradmin_proto_init() }
+func file_pkg_proto_workeradmin_workeradmin_proto_init() {
+	if File_pkg_proto_workeradmin_workeradmin_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunnerState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListRunnersResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetRunnerPlatformRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecutionStage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecutingAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListExecutingActionsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_workeradmin_workeradmin_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetActionProcessTreeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_proto_workeradmin_workeradmin_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_proto_workeradmin_workeradmin_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_workeradmin_workeradmin_proto_depIdxs,
+		MessageInfos:      file_pkg_proto_workeradmin_workeradmin_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_workeradmin_workeradmin_proto = out.File
+	file_pkg_proto_workeradmin_workeradmin_proto_rawDesc = nil
+	file_pkg_proto_workeradmin_workeradmin_proto_goTypes = nil
+	file_pkg_proto_workeradmin_workeradmin_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// WorkerAdminClient is the client API for WorkerAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type WorkerAdminClient interface {
+	ListRunners(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListRunnersResponse, error)
+	SetRunnerPlatform(ctx context.Context, in *SetRunnerPlatformRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListExecutingActions(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListExecutingActionsResponse, error)
+	GetActionProcessTree(ctx context.Context, in *GetActionProcessTreeRequest, opts ...grpc.CallOption) (*runner.GetProcessTreeResponse, error)
+}
+
+type workerAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWorkerAdminClient(cc grpc.ClientConnInterface) WorkerAdminClient {
+	return &workerAdminClient{cc}
+}
+
+func (c *workerAdminClient) ListRunners(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListRunnersResponse, error) {
+	out := new(ListRunnersResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.workeradmin.WorkerAdmin/ListRunners", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerAdminClient) SetRunnerPlatform(ctx context.Context, in *SetRunnerPlatformRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.workeradmin.WorkerAdmin/SetRunnerPlatform", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerAdminClient) ListExecutingActions(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListExecutingActionsResponse, error) {
+	out := new(ListExecutingActionsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.workeradmin.WorkerAdmin/ListExecutingActions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerAdminClient) GetActionProcessTree(ctx context.Context, in *GetActionProcessTreeRequest, opts ...grpc.CallOption) (*runner.GetProcessTreeResponse, error) {
+	out := new(runner.GetProcessTreeResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.workeradmin.WorkerAdmin/GetActionProcessTree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WorkerAdminServer is the server API for WorkerAdmin service.
+type WorkerAdminServer interface {
+	ListRunners(context.Context, *emptypb.Empty) (*ListRunnersResponse, error)
+	SetRunnerPlatform(context.Context, *SetRunnerPlatformRequest) (*emptypb.Empty, error)
+	ListExecutingActions(context.Context, *emptypb.Empty) (*ListExecutingActionsResponse, error)
+	GetActionProcessTree(context.Context, *GetActionProcessTreeRequest) (*runner.GetProcessTreeResponse, error)
+}
+
+// UnimplementedWorkerAdminServer can be embedded to have forward compatible implementations.
+type UnimplementedWorkerAdminServer struct {
+}
+
+func (*UnimplementedWorkerAdminServer) ListRunners(context.Context, *emptypb.Empty) (*ListRunnersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRunners not implemented")
+}
+func (*UnimplementedWorkerAdminServer) SetRunnerPlatform(context.Context, *SetRunnerPlatformRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRunnerPlatform not implemented")
+}
+func (*UnimplementedWorkerAdminServer) ListExecutingActions(context.Context, *emptypb.Empty) (*ListExecutingActionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListExecutingActions not implemented")
+}
+func (*UnimplementedWorkerAdminServer) GetActionProcessTree(context.Context, *GetActionProcessTreeRequest) (*runner.GetProcessTreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActionProcessTree not implemented")
+}
+
+func RegisterWorkerAdminServer(s grpc.ServiceRegistrar, srv WorkerAdminServer) {
+	s.RegisterService(&_WorkerAdmin_serviceDesc, srv)
+}
+
+func _WorkerAdmin_ListRunners_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerAdminServer).ListRunners(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.workeradmin.WorkerAdmin/ListRunners",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerAdminServer).ListRunners(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkerAdmin_SetRunnerPlatform_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRunnerPlatformRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerAdminServer).SetRunnerPlatform(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.workeradmin.WorkerAdmin/SetRunnerPlatform",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerAdminServer).SetRunnerPlatform(ctx, req.(*SetRunnerPlatformRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkerAdmin_ListExecutingActions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerAdminServer).ListExecutingActions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.workeradmin.WorkerAdmin/ListExecutingActions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerAdminServer).ListExecutingActions(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkerAdmin_GetActionProcessTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetActionProcessTreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerAdminServer).GetActionProcessTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.workeradmin.WorkerAdmin/GetActionProcessTree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerAdminServer).GetActionProcessTree(ctx, req.(*GetActionProcessTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _WorkerAdmin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "buildbarn.workeradmin.WorkerAdmin",
+	HandlerType: (*WorkerAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListRunners",
+			Handler:    _WorkerAdmin_ListRunners_Handler,
+		},
+		{
+			MethodName: "SetRunnerPlatform",
+			Handler:    _WorkerAdmin_SetRunnerPlatform_Handler,
+		},
+		{
+			MethodName: "ListExecutingActions",
+			Handler:    _WorkerAdmin_ListExecutingActions_Handler,
+		},
+		{
+			MethodName: "GetActionProcessTree",
+			Handler:    _WorkerAdmin_GetActionProcessTree_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/proto/workeradmin/workeradmin.proto",
+}
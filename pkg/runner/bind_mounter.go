@@ -0,0 +1,10 @@
+package runner
+
+// BindMounter can be used to bind mount files and directories that
+// are present on the host into an action's input root.
+type BindMounter interface {
+	// BindMountReadOnly creates a read-only bind mount of source
+	// at target. Both paths must be absolute. Bind mounts can be
+	// removed by calling Directory.Unmount().
+	BindMountReadOnly(source, target string) error
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+// +build !linux
+
+package runner
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewLocalBindMounter creates a BindMounter that creates bind mounts
+// using the mount() system call. On this operating system this
+// functionality is not available.
+func NewLocalBindMounter() (BindMounter, error) {
+	return nil, status.Error(codes.Unimplemented, "Bind mounts are not supported on this platform")
+}
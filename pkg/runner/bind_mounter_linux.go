@@ -0,0 +1,34 @@
+//go:build linux
+// +build linux
+
+package runner
+
+import (
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sys/unix"
+
+	"google.golang.org/grpc/codes"
+)
+
+type localBindMounter struct{}
+
+// NewLocalBindMounter creates a BindMounter that creates bind mounts
+// using the mount() system call.
+func NewLocalBindMounter() (BindMounter, error) {
+	return localBindMounter{}, nil
+}
+
+func (localBindMounter) BindMountReadOnly(source, target string) error {
+	if err := unix.Mount(source, target, "", unix.MS_BIND, ""); err != nil {
+		return util.StatusWrapfWithCode(err, codes.Internal, "Failed to bind mount %#v at %#v", source, target)
+	}
+
+	// The read-only flag is ignored when creating bind mounts. It
+	// can only be applied by remounting.
+	if err := unix.Mount("", target, "", unix.MS_BIND|unix.MS_REMOUNT|unix.MS_RDONLY, ""); err != nil {
+		unix.Unmount(target, 0)
+		return util.StatusWrapfWithCode(err, codes.Internal, "Failed to make bind mount at %#v read-only", target)
+	}
+	return nil
+}
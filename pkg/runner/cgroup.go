@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"os/exec"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// CgroupCreator can be provided to NewLocalRunner() to let every
+// command be run inside its own control group. This allows resource
+// limits to be applied to individual commands, and resource usage
+// statistics to be gathered for the command and all of its
+// descendants.
+//
+// The size class for which the command was scheduled is provided, so
+// that limits may be applied that differ between size classes.
+type CgroupCreator interface {
+	NewCgroup(sizeClass uint32) (Cgroup, error)
+}
+
+// Cgroup that was created by CgroupCreator, in which a single command
+// is run.
+type Cgroup interface {
+	// AttachToCommand adjusts a command that has not been started
+	// yet, so that it is launched inside the cgroup.
+	AttachToCommand(cmd *exec.Cmd)
+
+	// SetCPUWeight adjusts the share of CPU time that processes in
+	// the cgroup receive relative to those in sibling cgroups when
+	// the system is contended. It is called before the command is
+	// started.
+	SetCPUWeight(weight uint32) error
+
+	// GetResourceUsage returns resource usage statistics that were
+	// gathered by the cgroup. It is called after the command has
+	// terminated. Statistics may be returned in the form of multiple
+	// messages, one for each controller.
+	GetResourceUsage() ([]proto.Message, error)
+
+	// UpdatePOSIXResourceUsage adjusts fields of a
+	// POSIXResourceUsage message that was obtained through wait4()
+	// to account for all processes in the cgroup. Unlike the values
+	// returned by wait4(), these also include processes that were
+	// not waited for, such as ones that were reparented. It is
+	// called after the command has terminated.
+	UpdatePOSIXResourceUsage(resourceUsage *resourceusage.POSIXResourceUsage) error
+
+	// Close terminates any processes that remain in the cgroup and
+	// removes it.
+	Close() error
+}
@@ -0,0 +1,16 @@
+//go:build darwin || freebsd || windows
+// +build darwin freebsd windows
+
+package runner
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewCgroupV2Creator creates a CgroupCreator that creates cgroups
+// underneath a directory in a cgroup v2 hierarchy. On this operating
+// system this functionality is not available.
+func NewCgroupV2Creator(parentPath string, settings map[string]string, sizeClassSettings map[uint32]map[string]string) (CgroupCreator, error) {
+	return nil, status.Error(codes.Unimplemented, "Cgroups are not supported on this platform")
+}
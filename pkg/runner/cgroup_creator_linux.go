@@ -0,0 +1,430 @@
+//go:build linux
+// +build linux
+
+package runner
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/google/uuid"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type cgroupSetting struct {
+	name  string
+	value string
+}
+
+type cgroupV2Creator struct {
+	parentFD          int
+	settings          []cgroupSetting
+	sizeClassSettings map[uint32][]cgroupSetting
+}
+
+// NewCgroupV2Creator creates a CgroupCreator that creates cgroups
+// underneath a directory in a cgroup v2 hierarchy. Every cgroup that
+// is created is initialized with a set of settings, such as
+// memory.swap.max and pids.max. Settings may be overridden for
+// individual size classes. Resource usage statistics are reported in
+// the form of SwapResourceUsage, MemoryResourceUsage,
+// PIDsResourceUsage and BlockIOResourceUsage messages.
+func NewCgroupV2Creator(parentPath string, settings map[string]string, sizeClassSettings map[uint32]map[string]string) (CgroupCreator, error) {
+	parentFD, err := unix.Open(parentPath, unix.O_CLOEXEC|unix.O_DIRECTORY|unix.O_RDONLY, 0)
+	if err != nil {
+		return nil, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Failed to open cgroup directory %#v", parentPath)
+	}
+
+	cc := &cgroupV2Creator{
+		parentFD:          parentFD,
+		settings:          sortCgroupSettings(settings),
+		sizeClassSettings: map[uint32][]cgroupSetting{},
+	}
+	for sizeClass, overrides := range sizeClassSettings {
+		merged := make(map[string]string, len(settings)+len(overrides))
+		for name, value := range settings {
+			merged[name] = value
+		}
+		for name, value := range overrides {
+			merged[name] = value
+		}
+		cc.sizeClassSettings[sizeClass] = sortCgroupSettings(merged)
+	}
+	return cc, nil
+}
+
+// sortCgroupSettings converts a map of cgroup settings to a list, so
+// that settings are applied in a deterministic order.
+func sortCgroupSettings(settings map[string]string) []cgroupSetting {
+	sortedSettings := make([]cgroupSetting, 0, len(settings))
+	for name, value := range settings {
+		sortedSettings = append(sortedSettings, cgroupSetting{
+			name:  name,
+			value: value,
+		})
+	}
+	sort.Slice(sortedSettings, func(i, j int) bool {
+		return sortedSettings[i].name < sortedSettings[j].name
+	})
+	return sortedSettings
+}
+
+func (cc *cgroupV2Creator) NewCgroup(sizeClass uint32) (Cgroup, error) {
+	settings, ok := cc.sizeClassSettings[sizeClass]
+	if !ok {
+		settings = cc.settings
+	}
+
+	name := uuid.Must(uuid.NewRandom()).String()
+	if err := unix.Mkdirat(cc.parentFD, name, 0o755); err != nil {
+		return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to create cgroup %#v", name)
+	}
+	fd, err := unix.Openat(cc.parentFD, name, unix.O_CLOEXEC|unix.O_DIRECTORY|unix.O_RDONLY, 0)
+	if err != nil {
+		unix.Unlinkat(cc.parentFD, name, unix.AT_REMOVEDIR)
+		return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to open cgroup %#v", name)
+	}
+	cg := &cgroupV2{
+		parentFD: cc.parentFD,
+		name:     name,
+		fd:       fd,
+	}
+	for _, setting := range settings {
+		if err := writeCgroupFile(fd, setting.name, setting.value); err != nil {
+			cg.Close()
+			return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to set %#v of cgroup %#v to %#v", setting.name, name, setting.value)
+		}
+	}
+	return cg, nil
+}
+
+// writeCgroupFile writes a value into one of the interface files of a
+// cgroup.
+func writeCgroupFile(dirFD int, name, value string) error {
+	fd, err := unix.Openat(dirFD, name, unix.O_CLOEXEC|unix.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	f := os.NewFile(uintptr(fd), name)
+	_, err = f.Write([]byte(value))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// readCgroupFile reads the contents of one of the interface files of a
+// cgroup.
+func readCgroupFile(dirFD int, name string) ([]byte, error) {
+	fd, err := unix.Openat(dirFD, name, unix.O_CLOEXEC|unix.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	f := os.NewFile(uintptr(fd), name)
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// parseFlatKeyedCgroupFile parses the contents of cgroup interface
+// files that contain lines of the form "key value", such as
+// memory.stat and memory.swap.events. Entries with values that are
+// not integers are ignored.
+func parseFlatKeyedCgroupFile(data []byte) map[string]int64 {
+	values := map[string]int64{}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if fields := bytes.Fields(line); len(fields) == 2 {
+			if value, err := strconv.ParseInt(string(fields[1]), 10, 64); err == nil {
+				values[string(fields[0])] = value
+			}
+		}
+	}
+	return values
+}
+
+type cgroupV2 struct {
+	parentFD int
+	name     string
+	fd       int
+}
+
+func (cg *cgroupV2) AttachToCommand(cmd *exec.Cmd) {
+	// The SysProcAttr may be shared by many commands, so we must
+	// not modify it in place.
+	var sysProcAttr syscall.SysProcAttr
+	if cmd.SysProcAttr != nil {
+		sysProcAttr = *cmd.SysProcAttr
+	}
+	sysProcAttr.UseCgroupFD = true
+	sysProcAttr.CgroupFD = cg.fd
+	cmd.SysProcAttr = &sysProcAttr
+}
+
+func (cg *cgroupV2) SetCPUWeight(weight uint32) error {
+	value := strconv.FormatUint(uint64(weight), 10)
+	if err := writeCgroupFile(cg.fd, "cpu.weight", value); err != nil {
+		return util.StatusWrapfWithCode(err, codes.Internal, "Failed to set \"cpu.weight\" of cgroup %#v to %#v", cg.name, value)
+	}
+	return nil
+}
+
+func (cg *cgroupV2) GetResourceUsage() ([]proto.Message, error) {
+	swapResourceUsage, err := cg.getSwapResourceUsage()
+	if err != nil {
+		return nil, err
+	}
+	resourceUsage := []proto.Message{swapResourceUsage}
+
+	// memory.peak is only available on Linux 5.19 and later.
+	if data, err := readCgroupFile(cg.fd, "memory.peak"); err == nil {
+		memoryPeakBytes, err := strconv.ParseInt(string(bytes.TrimSpace(data)), 10, 64)
+		if err != nil {
+			return nil, util.StatusWrapWithCode(err, codes.Internal, "Invalid value in memory.peak")
+		}
+		resourceUsage = append(resourceUsage, &resourceusage.MemoryResourceUsage{
+			MemoryPeakBytes: memoryPeakBytes,
+		})
+	} else if !os.IsNotExist(err) {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to read memory.peak")
+	}
+
+	// pids.events is only present if the pids controller is
+	// enabled.
+	pidsEvents, err := readCgroupFile(cg.fd, "pids.events")
+	if err == nil {
+		pidsResourceUsage := resourceusage.PIDsResourceUsage{
+			PidsLimitExceededCount: parseFlatKeyedCgroupFile(pidsEvents)["max"],
+		}
+		// pids.peak is not available on older versions of Linux.
+		if data, err := readCgroupFile(cg.fd, "pids.peak"); err == nil {
+			pidsPeak, err := strconv.ParseInt(string(bytes.TrimSpace(data)), 10, 64)
+			if err != nil {
+				return nil, util.StatusWrapWithCode(err, codes.Internal, "Invalid value in pids.peak")
+			}
+			pidsResourceUsage.PidsPeak = pidsPeak
+		} else if !os.IsNotExist(err) {
+			return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to read pids.peak")
+		}
+		resourceUsage = append(resourceUsage, &pidsResourceUsage)
+	} else if !os.IsNotExist(err) {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to read pids.events")
+	}
+
+	// io.stat is only present if the io controller is enabled.
+	if ioStat, err := readCgroupFile(cg.fd, "io.stat"); err == nil {
+		blockIOResourceUsage, err := parseIOStatCgroupFile(ioStat)
+		if err != nil {
+			return nil, util.StatusWrapWithCode(err, codes.Internal, "Invalid value in io.stat")
+		}
+		resourceUsage = append(resourceUsage, blockIOResourceUsage)
+	} else if !os.IsNotExist(err) {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to read io.stat")
+	}
+	return resourceUsage, nil
+}
+
+func (cg *cgroupV2) UpdatePOSIXResourceUsage(resourceUsage *resourceusage.POSIXResourceUsage) error {
+	// CPU time of all processes that ever ran in the cgroup.
+	cpuStat, err := readCgroupFile(cg.fd, "cpu.stat")
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to read cpu.stat")
+	}
+	cpuStatValues := parseFlatKeyedCgroupFile(cpuStat)
+	if userUsec, ok := cpuStatValues["user_usec"]; ok {
+		resourceUsage.UserTime = durationpb.New(time.Duration(userUsec) * time.Microsecond)
+	}
+	if systemUsec, ok := cpuStatValues["system_usec"]; ok {
+		resourceUsage.SystemTime = durationpb.New(time.Duration(systemUsec) * time.Microsecond)
+	}
+
+	// memory.stat is only present if the memory controller is
+	// enabled. Only major faults require I/O, meaning that all
+	// other faults are page reclaims.
+	if memoryStat, err := readCgroupFile(cg.fd, "memory.stat"); err == nil {
+		memoryStatValues := parseFlatKeyedCgroupFile(memoryStat)
+		resourceUsage.PageFaults = memoryStatValues["pgmajfault"]
+		resourceUsage.PageReclaims = memoryStatValues["pgfault"] - resourceUsage.PageFaults
+	} else if !os.IsNotExist(err) {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to read memory.stat")
+	}
+
+	// cgroups do not track context switches. Processes that are
+	// still part of the cgroup have not been waited for, meaning
+	// they are not accounted for by wait4(). Add the context
+	// switches of all of their threads.
+	threads, err := readCgroupFile(cg.fd, "cgroup.threads")
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to read cgroup.threads")
+	}
+	for _, thread := range bytes.Fields(threads) {
+		statusPath := filepath.Join("/proc", string(thread), "status")
+		procStatus, err := os.ReadFile(statusPath)
+		if err != nil {
+			// The thread may have terminated in the meantime.
+			if os.IsNotExist(err) || errors.Is(err, syscall.ESRCH) {
+				continue
+			}
+			return util.StatusWrapfWithCode(err, codes.Internal, "Failed to read %#v", statusPath)
+		}
+		// Lines in /proc/*/status have the form "key:\tvalue".
+		statusValues := parseFlatKeyedCgroupFile(procStatus)
+		resourceUsage.VoluntaryContextSwitches += statusValues["voluntary_ctxt_switches:"]
+		resourceUsage.InvoluntaryContextSwitches += statusValues["nonvoluntary_ctxt_switches:"]
+	}
+	return nil
+}
+
+// parseIOStatCgroupFile parses the contents of io.stat, which contains
+// lines of the form "major:minor key=value ...", one for each block
+// device.
+func parseIOStatCgroupFile(data []byte) (*resourceusage.BlockIOResourceUsage, error) {
+	var resourceUsage resourceusage.BlockIOResourceUsage
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		fields := bytes.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		majorStr, minorStr, ok := bytes.Cut(fields[0], []byte(":"))
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid device number %#v", string(fields[0]))
+		}
+		major, err := strconv.ParseUint(string(majorStr), 10, 32)
+		if err != nil {
+			return nil, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid device number %#v", string(fields[0]))
+		}
+		minor, err := strconv.ParseUint(string(minorStr), 10, 32)
+		if err != nil {
+			return nil, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid device number %#v", string(fields[0]))
+		}
+
+		device := resourceusage.BlockIOResourceUsage_Device{
+			Major: uint32(major),
+			Minor: uint32(minor),
+		}
+		for _, field := range fields[1:] {
+			key, valueStr, ok := bytes.Cut(field, []byte("="))
+			if !ok {
+				continue
+			}
+			value, err := strconv.ParseInt(string(valueStr), 10, 64)
+			if err != nil {
+				// Ignore keys with non-integer values that
+				// may be added by future versions of Linux.
+				continue
+			}
+			switch string(key) {
+			case "rbytes":
+				device.ReadBytes = value
+			case "wbytes":
+				device.WriteBytes = value
+			case "rios":
+				device.ReadOperations = value
+			case "wios":
+				device.WriteOperations = value
+			case "dbytes":
+				device.DiscardBytes = value
+			case "dios":
+				device.DiscardOperations = value
+			}
+		}
+		resourceUsage.ReadBytes += device.ReadBytes
+		resourceUsage.WriteBytes += device.WriteBytes
+		resourceUsage.ReadOperations += device.ReadOperations
+		resourceUsage.WriteOperations += device.WriteOperations
+		resourceUsage.DiscardBytes += device.DiscardBytes
+		resourceUsage.DiscardOperations += device.DiscardOperations
+		resourceUsage.Devices = append(resourceUsage.Devices, &device)
+	}
+	return &resourceUsage, nil
+}
+
+func (cg *cgroupV2) getSwapResourceUsage() (*resourceusage.SwapResourceUsage, error) {
+	var resourceUsage resourceusage.SwapResourceUsage
+
+	// memory.swap.peak is only available on Linux 6.5 and later.
+	if data, err := readCgroupFile(cg.fd, "memory.swap.peak"); err == nil {
+		swapPeakBytes, err := strconv.ParseInt(string(bytes.TrimSpace(data)), 10, 64)
+		if err != nil {
+			return nil, util.StatusWrapWithCode(err, codes.Internal, "Invalid value in memory.swap.peak")
+		}
+		resourceUsage.SwapPeakBytes = swapPeakBytes
+	} else if !os.IsNotExist(err) {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to read memory.swap.peak")
+	}
+
+	swapEvents, err := readCgroupFile(cg.fd, "memory.swap.events")
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to read memory.swap.events")
+	}
+	resourceUsage.SwapLimitExceededCount = parseFlatKeyedCgroupFile(swapEvents)["max"]
+
+	memoryStat, err := readCgroupFile(cg.fd, "memory.stat")
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to read memory.stat")
+	}
+	memoryStatValues := parseFlatKeyedCgroupFile(memoryStat)
+	resourceUsage.ZswapStores = memoryStatValues["zswpout"]
+	resourceUsage.ZswapLoads = memoryStatValues["zswpin"]
+	resourceUsage.ZswapWritebacks = memoryStatValues["zswpwb"]
+	return &resourceUsage, nil
+}
+
+// waitForUnpopulated blocks until all processes in the cgroup have
+// terminated. The kernel signals changes to cgroup.events by
+// generating POLLPRI events.
+func (cg *cgroupV2) waitForUnpopulated() error {
+	fd, err := unix.Openat(cg.fd, "cgroup.events", unix.O_CLOEXEC|unix.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+
+	var buf [4096]byte
+	for {
+		n, err := unix.Pread(fd, buf[:], 0)
+		if err != nil {
+			return err
+		}
+		if parseFlatKeyedCgroupFile(buf[:n])["populated"] == 0 {
+			return nil
+		}
+		if _, err := unix.Poll([]unix.PollFd{{Fd: int32(fd), Events: unix.POLLPRI}}, -1); err != nil && err != unix.EINTR {
+			return err
+		}
+	}
+}
+
+func (cg *cgroupV2) Close() error {
+	// Terminate any processes that the command left behind, so that
+	// the cgroup can be removed. cgroup.kill is only available on
+	// Linux 5.14 and later.
+	if err := writeCgroupFile(cg.fd, "cgroup.kill", "1"); err == nil {
+		if err := cg.waitForUnpopulated(); err != nil {
+			unix.Close(cg.fd)
+			return util.StatusWrapfWithCode(err, codes.Internal, "Failed to wait for processes in cgroup %#v to terminate", cg.name)
+		}
+	} else if !os.IsNotExist(err) {
+		unix.Close(cg.fd)
+		return util.StatusWrapfWithCode(err, codes.Internal, "Failed to kill processes in cgroup %#v", cg.name)
+	}
+
+	unix.Close(cg.fd)
+	if err := unix.Unlinkat(cg.parentFD, cg.name, unix.AT_REMOVEDIR); err != nil {
+		return util.StatusWrapfWithCode(err, codes.Internal, "Failed to remove cgroup %#v", cg.name)
+	}
+	return nil
+}
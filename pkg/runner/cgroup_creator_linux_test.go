@@ -0,0 +1,223 @@
+//go:build linux
+// +build linux
+
+package runner_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// newFakeCgroup creates a cgroup underneath a directory that is not
+// part of a cgroup hierarchy, and populates it with interface files
+// copied from testdata/cgroup_v2.
+func newFakeCgroup(t *testing.T, fixtures []string, threads string) (runner.Cgroup, func()) {
+	parentPath := t.TempDir()
+	cgroupCreator, err := runner.NewCgroupV2Creator(parentPath, nil, nil)
+	require.NoError(t, err)
+	cgroup, err := cgroupCreator.NewCgroup(0)
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(parentPath)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	cgroupPath := filepath.Join(parentPath, entries[0].Name())
+	for _, fixture := range fixtures {
+		data, err := os.ReadFile(filepath.Join("testdata/cgroup_v2", fixture))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(cgroupPath, fixture), data, 0o644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(cgroupPath, "cgroup.threads"), []byte(threads), 0o644))
+
+	// Interface files need to be removed before the cgroup can be
+	// closed, as the directory would otherwise not be empty.
+	return cgroup, func() {
+		for _, fixture := range append(fixtures, "cgroup.threads") {
+			require.NoError(t, os.Remove(filepath.Join(cgroupPath, fixture)))
+		}
+		require.NoError(t, cgroup.Close())
+	}
+}
+
+// getContextSwitches returns the number of voluntary and involuntary
+// context switches of a thread, as reported by /proc.
+func getContextSwitches(t *testing.T, pid int) (int64, int64) {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.FormatInt(int64(pid), 10), "status"))
+	require.NoError(t, err)
+	values := map[string]int64{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			if n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+				values[key] = n
+			}
+		}
+	}
+	return values["voluntary_ctxt_switches"], values["nonvoluntary_ctxt_switches"]
+}
+
+func TestCgroupV2CreatorGetResourceUsage(t *testing.T) {
+	t.Run("AllControllers", func(t *testing.T) {
+		cgroup, closeCgroup := newFakeCgroup(t, []string{
+			"io.stat",
+			"memory.peak",
+			"memory.stat",
+			"memory.swap.events",
+			"memory.swap.peak",
+			"pids.events",
+			"pids.peak",
+		}, "")
+		defer closeCgroup()
+
+		resourceUsage, err := cgroup.GetResourceUsage()
+		require.NoError(t, err)
+		require.Len(t, resourceUsage, 4)
+		testutil.RequireEqualProto(t, &resourceusage.SwapResourceUsage{
+			SwapPeakBytes:          4096,
+			SwapLimitExceededCount: 3,
+			ZswapStores:            34,
+			ZswapLoads:             12,
+			ZswapWritebacks:        5,
+		}, resourceUsage[0])
+		testutil.RequireEqualProto(t, &resourceusage.MemoryResourceUsage{
+			MemoryPeakBytes: 73400320,
+		}, resourceUsage[1])
+		testutil.RequireEqualProto(t, &resourceusage.PIDsResourceUsage{
+			PidsPeak:               25,
+			PidsLimitExceededCount: 7,
+		}, resourceUsage[2])
+		testutil.RequireEqualProto(t, &resourceusage.BlockIOResourceUsage{
+			Devices: []*resourceusage.BlockIOResourceUsage_Device{
+				{
+					Major:           259,
+					Minor:           0,
+					ReadBytes:       1048576,
+					WriteBytes:      4194304,
+					ReadOperations:  16,
+					WriteOperations: 64,
+				},
+				{
+					Major:             8,
+					Minor:             16,
+					ReadBytes:         2097152,
+					ReadOperations:    32,
+					DiscardBytes:      65536,
+					DiscardOperations: 1,
+				},
+			},
+			ReadBytes:         3145728,
+			WriteBytes:        4194304,
+			ReadOperations:    48,
+			WriteOperations:   64,
+			DiscardBytes:      65536,
+			DiscardOperations: 1,
+		}, resourceUsage[3])
+	})
+
+	t.Run("MinimalControllers", func(t *testing.T) {
+		// On older versions of Linux, or if the pids and io
+		// controllers are not enabled, only swap usage should be
+		// reported.
+		cgroup, closeCgroup := newFakeCgroup(t, []string{
+			"memory.stat",
+			"memory.swap.events",
+		}, "")
+		defer closeCgroup()
+
+		resourceUsage, err := cgroup.GetResourceUsage()
+		require.NoError(t, err)
+		require.Len(t, resourceUsage, 1)
+		testutil.RequireEqualProto(t, &resourceusage.SwapResourceUsage{
+			SwapLimitExceededCount: 3,
+			ZswapStores:            34,
+			ZswapLoads:             12,
+			ZswapWritebacks:        5,
+		}, resourceUsage[0])
+	})
+}
+
+func TestCgroupV2CreatorUpdatePOSIXResourceUsage(t *testing.T) {
+	t.Run("NoRemainingProcesses", func(t *testing.T) {
+		// CPU times and page faults should be obtained from the
+		// cgroup. All other values should be left alone. In
+		// particular, memory.peak should not be reported as the
+		// maximum resident set size.
+		cgroup, closeCgroup := newFakeCgroup(t, []string{
+			"cpu.stat",
+			"memory.peak",
+			"memory.stat",
+		}, "")
+		defer closeCgroup()
+
+		resourceUsage := resourceusage.POSIXResourceUsage{
+			UserTime:                   durationpb.New(time.Second),
+			SystemTime:                 durationpb.New(time.Second / 10),
+			MaximumResidentSetSize:     8388608,
+			PageReclaims:               1000,
+			PageFaults:                 10,
+			VoluntaryContextSwitches:   100,
+			InvoluntaryContextSwitches: 20,
+		}
+		require.NoError(t, cgroup.UpdatePOSIXResourceUsage(&resourceUsage))
+		testutil.RequireEqualProto(t, &resourceusage.POSIXResourceUsage{
+			UserTime:                   durationpb.New(1523456 * time.Microsecond),
+			SystemTime:                 durationpb.New(319754 * time.Microsecond),
+			MaximumResidentSetSize:     8388608,
+			PageReclaims:               48000,
+			PageFaults:                 213,
+			VoluntaryContextSwitches:   100,
+			InvoluntaryContextSwitches: 20,
+		}, &resourceUsage)
+	})
+
+	t.Run("RemainingProcesses", func(t *testing.T) {
+		// Context switches of threads that are still part of
+		// the cgroup should be added to the ones returned by
+		// wait4(). Threads that terminated in the meantime
+		// should be ignored.
+		terminatedCmd := exec.Command("/bin/true")
+		require.NoError(t, terminatedCmd.Run())
+
+		remainingCmd := exec.Command("/bin/sleep", "60")
+		require.NoError(t, remainingCmd.Start())
+		defer func() {
+			remainingCmd.Process.Kill()
+			remainingCmd.Wait()
+		}()
+		statusPath := filepath.Join("/proc", strconv.FormatInt(int64(remainingCmd.Process.Pid), 10), "status")
+		require.Eventually(t, func() bool {
+			data, err := os.ReadFile(statusPath)
+			return err == nil && strings.Contains(string(data), "Name:\tsleep\n") && strings.Contains(string(data), "State:\tS (sleeping)\n")
+		}, 10*time.Second, 10*time.Millisecond)
+		voluntaryContextSwitches, involuntaryContextSwitches := getContextSwitches(t, remainingCmd.Process.Pid)
+		require.NotZero(t, voluntaryContextSwitches)
+
+		cgroup, closeCgroup := newFakeCgroup(t, []string{
+			"cpu.stat",
+		}, strconv.FormatInt(int64(terminatedCmd.Process.Pid), 10)+"\n"+strconv.FormatInt(int64(remainingCmd.Process.Pid), 10)+"\n")
+		defer closeCgroup()
+
+		resourceUsage := resourceusage.POSIXResourceUsage{
+			VoluntaryContextSwitches:   100,
+			InvoluntaryContextSwitches: 20,
+		}
+		require.NoError(t, cgroup.UpdatePOSIXResourceUsage(&resourceUsage))
+		testutil.RequireEqualProto(t, &resourceusage.POSIXResourceUsage{
+			UserTime:                   durationpb.New(1523456 * time.Microsecond),
+			SystemTime:                 durationpb.New(319754 * time.Microsecond),
+			VoluntaryContextSwitches:   100 + voluntaryContextSwitches,
+			InvoluntaryContextSwitches: 20 + involuntaryContextSwitches,
+		}, &resourceUsage)
+	})
+}
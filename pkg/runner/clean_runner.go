@@ -50,3 +50,7 @@ func (r *cleanRunner) CheckReadiness(ctx context.Context, request *runner_pb.Che
 	}
 	return response, err2
 }
+
+func (r *cleanRunner) GetProcessTree(ctx context.Context, request *runner_pb.GetProcessTreeRequest) (*runner_pb.GetProcessTreeResponse, error) {
+	return r.base.GetProcessTree(ctx, request)
+}
@@ -0,0 +1,111 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// NewCommandVirtualMachineLauncher creates a VirtualMachineLauncher
+// that launches virtual machines by running a command. This command
+// may, for example, be a script that starts a Firecracker microVM or
+// creates an instance through a cloud provider's API.
+//
+// Once the virtual machine is capable of running actions, the command
+// must write the address of the gRPC server of the bb_runner instance
+// running inside the virtual machine to standard output, followed by a
+// newline character. The virtual machine must be shut down when the
+// command's standard input is closed, after which the command must
+// terminate.
+func NewCommandVirtualMachineLauncher(command string, args []string) VirtualMachineLauncher {
+	return func(ctx context.Context) (VirtualMachine, error) {
+		cmd := exec.Command(command, args...)
+		cmd.Stderr = os.Stderr
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to create standard input pipe")
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to create standard output pipe")
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to start launch command")
+		}
+
+		// Wait for the command to report the address of
+		// bb_runner running inside the virtual machine. Any
+		// further output is discarded. As cmd.Wait() closes the
+		// pipe, it may only be called after all output has been
+		// read.
+		addressChan := make(chan string, 1)
+		stdoutDone := make(chan struct{})
+		go func() {
+			address, _ := bufio.NewReader(stdout).ReadString('\n')
+			addressChan <- strings.TrimSpace(address)
+			io.Copy(io.Discard, stdout)
+			close(stdoutDone)
+		}()
+
+		abort := func() {
+			stdin.Close()
+			cmd.Process.Kill()
+			<-stdoutDone
+			cmd.Wait()
+		}
+		var address string
+		select {
+		case <-ctx.Done():
+			abort()
+			return nil, util.StatusFromContext(ctx)
+		case address = <-addressChan:
+		}
+		if address == "" {
+			abort()
+			return nil, status.Error(codes.Internal, "Launch command did not report the address of the virtual machine")
+		}
+
+		conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			abort()
+			return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to connect to virtual machine at address %#v", address)
+		}
+		return &commandVirtualMachine{
+			RunnerClient: runner_pb.NewRunnerClient(conn),
+			conn:         conn,
+			cmd:          cmd,
+			stdin:        stdin,
+			stdoutDone:   stdoutDone,
+		}, nil
+	}
+}
+
+type commandVirtualMachine struct {
+	runner_pb.RunnerClient
+
+	conn       *grpc.ClientConn
+	cmd        *exec.Cmd
+	stdin      io.Closer
+	stdoutDone <-chan struct{}
+}
+
+func (vm *commandVirtualMachine) Terminate() error {
+	vm.conn.Close()
+	vm.stdin.Close()
+	<-vm.stdoutDone
+	if err := vm.cmd.Wait(); err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Launch command failed")
+	}
+	return nil
+}
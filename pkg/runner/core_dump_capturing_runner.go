@@ -0,0 +1,208 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"math"
+	"os"
+	"regexp"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/klauspost/compress/zstd"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var coreDumpsDirectoryName = path.MustNewComponent("core_dumps")
+
+type coreDumpCapturingRunner struct {
+	base             runner_pb.RunnerServer
+	buildDirectory   filesystem.Directory
+	fileNamePattern  *regexp.Regexp
+	maximumSizeBytes int64
+}
+
+// NewCoreDumpCapturingRunner creates a decorator for Runner that
+// captures core dumps written by processes of commands that crash.
+// After a command exits with a non-zero exit code, the working
+// directory of the command is scanned for files whose names match a
+// pattern. These are compressed using Zstandard, moved into the server
+// logs directory of the command, and reported in the form of a
+// CoreDumpsResourceUsage message.
+//
+// This decorator does not enable the generation of core dumps. It
+// assumes that the kernel is configured to write core dumps into the
+// working directory of the crashing process.
+func NewCoreDumpCapturingRunner(base runner_pb.RunnerServer, buildDirectory filesystem.Directory, fileNamePattern *regexp.Regexp, maximumSizeBytes int64) runner_pb.RunnerServer {
+	return &coreDumpCapturingRunner{
+		base:             base,
+		buildDirectory:   buildDirectory,
+		fileNamePattern:  fileNamePattern,
+		maximumSizeBytes: maximumSizeBytes,
+	}
+}
+
+// openDirectory opens a directory, given a path relative to the build
+// directory. The path may optionally be followed by additional paths
+// that are resolved relative to the resulting directory.
+func (r *coreDumpCapturingRunner) openDirectory(paths ...string) (*buildDirectoryPathResolver, error) {
+	resolver := &buildDirectoryPathResolver{
+		stack: util.NewNonEmptyStack(filesystem.NopDirectoryCloser(r.buildDirectory)),
+	}
+	for _, p := range paths {
+		resolver.TerminalName = nil
+		if err := path.Resolve(p, path.NewRelativeScopeWalker(resolver)); err != nil {
+			resolver.closeAll()
+			return nil, err
+		}
+		if name := resolver.TerminalName; name != nil {
+			d, err := resolver.stack.Peek().EnterDirectory(*name)
+			if err != nil {
+				resolver.closeAll()
+				return nil, err
+			}
+			resolver.stack.Push(d)
+		}
+	}
+	return resolver, nil
+}
+
+// captureCoreDump compresses a single core dump and writes it into
+// the server logs directory. The original core dump is removed, so
+// that it does not end up being part of the outputs of the action.
+func (r *coreDumpCapturingRunner) captureCoreDump(workingDirectory, coreDumpsDirectory filesystem.Directory, name path.Component) (*resourceusage.CoreDumpsResourceUsage_CoreDump, error) {
+	coreDump := &resourceusage.CoreDumpsResourceUsage_CoreDump{
+		Path: name.String(),
+	}
+	f, err := workingDirectory.OpenRead(name)
+	if err != nil {
+		return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to open core dump %#v", name.String())
+	}
+	defer f.Close()
+
+	compressedName := path.MustNewComponent(name.String() + ".zst")
+	w, err := coreDumpsDirectory.OpenAppend(compressedName, filesystem.CreateExcl(0o666))
+	if err != nil {
+		return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to create compressed core dump %#v", compressedName.String())
+	}
+	encoder, err := zstd.NewWriter(w)
+	if err != nil {
+		w.Close()
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to create Zstandard encoder")
+	}
+	sizeBytes, copyErr := io.Copy(encoder, io.LimitReader(io.NewSectionReader(f, 0, math.MaxInt64), r.maximumSizeBytes+1))
+	if err := encoder.Close(); err != nil && copyErr == nil {
+		copyErr = err
+	}
+	if err := w.Close(); err != nil && copyErr == nil {
+		copyErr = err
+	}
+	if copyErr != nil {
+		coreDumpsDirectory.Remove(compressedName)
+		return nil, util.StatusWrapfWithCode(copyErr, codes.Internal, "Failed to compress core dump %#v", name.String())
+	}
+
+	if sizeBytes > r.maximumSizeBytes {
+		// Core dump is too large to be stored. Only report
+		// its presence.
+		if err := coreDumpsDirectory.Remove(compressedName); err != nil {
+			return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to remove compressed core dump %#v", compressedName.String())
+		}
+	} else {
+		coreDump.SizeBytes = uint64(sizeBytes)
+		coreDump.ServerLogName = coreDumpsDirectoryName.String() + "/" + compressedName.String()
+	}
+
+	if err := workingDirectory.Remove(name); err != nil {
+		return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to remove core dump %#v", name.String())
+	}
+	return coreDump, nil
+}
+
+func (r *coreDumpCapturingRunner) captureCoreDumps(request *runner_pb.RunRequest) ([]*resourceusage.CoreDumpsResourceUsage_CoreDump, error) {
+	workingDirectoryResolver, err := r.openDirectory(request.InputRootDirectory, request.WorkingDirectory)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to open working directory")
+	}
+	defer workingDirectoryResolver.closeAll()
+	workingDirectory := workingDirectoryResolver.stack.Peek()
+
+	files, err := workingDirectory.ReadDir()
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to read working directory")
+	}
+	var names []path.Component
+	for _, file := range files {
+		if file.Type() == filesystem.FileTypeRegularFile && r.fileNamePattern.MatchString(file.Name().String()) {
+			names = append(names, file.Name())
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	serverLogsDirectoryResolver, err := r.openDirectory(request.ServerLogsDirectory)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to open server logs directory")
+	}
+	defer serverLogsDirectoryResolver.closeAll()
+	serverLogsDirectory := serverLogsDirectoryResolver.stack.Peek()
+	if err := serverLogsDirectory.Mkdir(coreDumpsDirectoryName, 0o777); err != nil && !os.IsExist(err) {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to create core dumps directory")
+	}
+	coreDumpsDirectory, err := serverLogsDirectory.EnterDirectory(coreDumpsDirectoryName)
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to enter core dumps directory")
+	}
+	defer coreDumpsDirectory.Close()
+
+	coreDumps := make([]*resourceusage.CoreDumpsResourceUsage_CoreDump, 0, len(names))
+	for _, name := range names {
+		coreDump, err := r.captureCoreDump(workingDirectory, coreDumpsDirectory, name)
+		if err != nil {
+			return nil, err
+		}
+		coreDumps = append(coreDumps, coreDump)
+	}
+	return coreDumps, nil
+}
+
+func (r *coreDumpCapturingRunner) Run(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+	response, err := r.base.Run(ctx, request)
+	if err != nil || response.ExitCode == 0 || request.ServerLogsDirectory == "" {
+		// Only scan for core dumps if the command failed, as
+		// successful commands may legitimately create files
+		// matching the pattern.
+		return response, err
+	}
+
+	coreDumps, err := r.captureCoreDumps(request)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to capture core dumps")
+	}
+	if len(coreDumps) == 0 {
+		return response, nil
+	}
+	resourceUsage, err := anypb.New(&resourceusage.CoreDumpsResourceUsage{
+		CoreDumps: coreDumps,
+	})
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to marshal core dumps resource usage")
+	}
+	response.ResourceUsage = append(response.ResourceUsage, resourceUsage)
+	return response, nil
+}
+
+func (r *coreDumpCapturingRunner) CheckReadiness(ctx context.Context, request *runner_pb.CheckReadinessRequest) (*emptypb.Empty, error) {
+	return r.base.CheckReadiness(ctx, request)
+}
+
+func (r *coreDumpCapturingRunner) GetProcessTree(ctx context.Context, request *runner_pb.GetProcessTreeRequest) (*runner_pb.GetProcessTreeResponse, error) {
+	return r.base.GetProcessTree(ctx, request)
+}
@@ -0,0 +1,107 @@
+package runner_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestCoreDumpCapturingRunner(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	buildDirectoryPath := t.TempDir()
+	workingDirectoryPath := filepath.Join(buildDirectoryPath, "root", "src")
+	require.NoError(t, os.MkdirAll(workingDirectoryPath, 0o777))
+	serverLogsDirectoryPath := filepath.Join(buildDirectoryPath, "server_logs")
+	require.NoError(t, os.Mkdir(serverLogsDirectoryPath, 0o777))
+	buildDirectory, err := filesystem.NewLocalDirectory(buildDirectoryPath)
+	require.NoError(t, err)
+	defer buildDirectory.Close()
+
+	baseRunner := mock.NewMockRunnerServer(ctrl)
+	runnerServer := runner.NewCoreDumpCapturingRunner(baseRunner, buildDirectory, regexp.MustCompile(`^core(\.[0-9]+)?$`), 10)
+
+	request := &runner_pb.RunRequest{
+		Arguments:           []string{"cc", "-c", "hello.c"},
+		InputRootDirectory:  "root",
+		WorkingDirectory:    "src",
+		ServerLogsDirectory: "server_logs",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		// Files matching the pattern should be left alone if
+		// the command succeeded, as they may be outputs.
+		require.NoError(t, os.WriteFile(filepath.Join(workingDirectoryPath, "core"), []byte("Hello"), 0o666))
+		baseRunner.EXPECT().Run(ctx, request).Return(&runner_pb.RunResponse{}, nil)
+
+		response, err := runnerServer.Run(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &runner_pb.RunResponse{}, response)
+		require.FileExists(t, filepath.Join(workingDirectoryPath, "core"))
+		require.NoError(t, os.Remove(filepath.Join(workingDirectoryPath, "core")))
+	})
+
+	t.Run("NoCoreDumps", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(workingDirectoryPath, "hello.c"), []byte("int main;"), 0o666))
+		baseRunner.EXPECT().Run(ctx, request).Return(&runner_pb.RunResponse{ExitCode: 1}, nil)
+
+		response, err := runnerServer.Run(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &runner_pb.RunResponse{ExitCode: 1}, response)
+	})
+
+	t.Run("CoreDumps", func(t *testing.T) {
+		// Core dumps should be compressed and moved into the
+		// server logs directory. Core dumps that are too large
+		// should only be reported.
+		require.NoError(t, os.WriteFile(filepath.Join(workingDirectoryPath, "core.123"), []byte("Hello"), 0o666))
+		require.NoError(t, os.WriteFile(filepath.Join(workingDirectoryPath, "core.456"), []byte("Hello, world"), 0o666))
+		baseRunner.EXPECT().Run(ctx, request).Return(&runner_pb.RunResponse{ExitCode: 139}, nil)
+
+		response, err := runnerServer.Run(ctx, request)
+		require.NoError(t, err)
+		resourceUsage, err := anypb.New(&resourceusage.CoreDumpsResourceUsage{
+			CoreDumps: []*resourceusage.CoreDumpsResourceUsage_CoreDump{
+				{
+					Path:          "core.123",
+					SizeBytes:     5,
+					ServerLogName: "core_dumps/core.123.zst",
+				},
+				{
+					Path: "core.456",
+				},
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &runner_pb.RunResponse{
+			ExitCode:      139,
+			ResourceUsage: []*anypb.Any{resourceUsage},
+		}, response)
+
+		require.NoFileExists(t, filepath.Join(workingDirectoryPath, "core.123"))
+		require.NoFileExists(t, filepath.Join(workingDirectoryPath, "core.456"))
+		require.NoFileExists(t, filepath.Join(serverLogsDirectoryPath, "core_dumps", "core.456.zst"))
+		compressed, err := os.ReadFile(filepath.Join(serverLogsDirectoryPath, "core_dumps", "core.123.zst"))
+		require.NoError(t, err)
+		decoder, err := zstd.NewReader(nil)
+		require.NoError(t, err)
+		defer decoder.Close()
+		decompressed, err := decoder.DecodeAll(compressed, nil)
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hello"), decompressed)
+	})
+}
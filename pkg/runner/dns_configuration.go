@@ -0,0 +1,72 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// HostsEntry is a single line in a hosts file, mapping an IP address
+// to one or more host names.
+type HostsEntry struct {
+	Address   string
+	Hostnames []string
+}
+
+// GenerateResolvConf generates the contents of a resolv.conf file,
+// using the provided name servers, search domains and resolver
+// options. When no name servers are provided, the resulting file
+// causes name resolution through DNS to fail.
+func GenerateResolvConf(nameservers, searchDomains, options []string) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("# Generated by bb_runner.\n")
+	for _, nameserver := range nameservers {
+		if net.ParseIP(nameserver) == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Name server %#v is not a valid IP address", nameserver)
+		}
+		fmt.Fprintf(&b, "nameserver %s\n", nameserver)
+	}
+	if len(searchDomains) > 0 {
+		for _, searchDomain := range searchDomains {
+			if searchDomain == "" || strings.ContainsAny(searchDomain, " \t\n") {
+				return nil, status.Errorf(codes.InvalidArgument, "Invalid search domain %#v", searchDomain)
+			}
+		}
+		fmt.Fprintf(&b, "search %s\n", strings.Join(searchDomains, " "))
+	}
+	if len(options) > 0 {
+		for _, option := range options {
+			if option == "" || strings.ContainsAny(option, " \t\n") {
+				return nil, status.Errorf(codes.InvalidArgument, "Invalid resolver option %#v", option)
+			}
+		}
+		fmt.Fprintf(&b, "options %s\n", strings.Join(options, " "))
+	}
+	return b.Bytes(), nil
+}
+
+// GenerateHosts generates the contents of a hosts file. Entries for
+// localhost are always included, followed by the provided entries.
+func GenerateHosts(entries []HostsEntry) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("# Generated by bb_runner.\n127.0.0.1 localhost\n::1 localhost ip6-localhost ip6-loopback\n")
+	for _, entry := range entries {
+		if net.ParseIP(entry.Address) == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Hosts entry address %#v is not a valid IP address", entry.Address)
+		}
+		if len(entry.Hostnames) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "Hosts entry for address %#v has no host names", entry.Address)
+		}
+		for _, hostname := range entry.Hostnames {
+			if hostname == "" || strings.ContainsAny(hostname, " \t\n#") {
+				return nil, status.Errorf(codes.InvalidArgument, "Invalid host name %#v for address %#v", hostname, entry.Address)
+			}
+		}
+		fmt.Fprintf(&b, "%s %s\n", entry.Address, strings.Join(entry.Hostnames, " "))
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,225 @@
+package runner
+
+import (
+	"context"
+	"os"
+
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var (
+	etcDirectoryName          = path.MustNewComponent("etc")
+	hostsFileName             = path.MustNewComponent("hosts")
+	resolvConfFileName        = path.MustNewComponent("resolv.conf")
+	dnsConfigurationFileNames = []path.Component{resolvConfFileName, hostsFileName}
+)
+
+// activeFileMount keeps track of a file that was bind mounted by
+// dnsConfigurationMountingRunner, so that the input root can be
+// restored after the action completes.
+type activeFileMount struct {
+	name              path.Component
+	createdMountpoint bool
+	symlinkTarget     *string
+}
+
+// dnsConfigurationMounts keeps track of all files that were bind
+// mounted inside the /etc directory of a single action's input root.
+type dnsConfigurationMounts struct {
+	etcDirectory        filesystem.DirectoryCloser
+	createdEtcDirectory bool
+	mounts              []activeFileMount
+}
+
+func (m *dnsConfigurationMounts) unmountAll(inputRoot filesystem.Directory) error {
+	var firstErr error
+	for i := len(m.mounts) - 1; i >= 0; i-- {
+		mount := m.mounts[i]
+		if err := m.etcDirectory.Unmount(mount.name); err != nil {
+			if firstErr == nil {
+				firstErr = util.StatusWrapfWithCode(err, codes.Internal, "Failed to unmount \"/etc/%s\"", mount.name)
+			}
+			continue
+		}
+		if mount.createdMountpoint {
+			if err := m.etcDirectory.Remove(mount.name); err != nil {
+				if firstErr == nil {
+					firstErr = util.StatusWrapfWithCode(err, codes.Internal, "Failed to remove mountpoint \"/etc/%s\"", mount.name)
+				}
+				continue
+			}
+			if mount.symlinkTarget != nil {
+				if err := m.etcDirectory.Symlink(*mount.symlinkTarget, mount.name); err != nil && firstErr == nil {
+					firstErr = util.StatusWrapfWithCode(err, codes.Internal, "Failed to restore symbolic link \"/etc/%s\"", mount.name)
+				}
+			}
+		}
+	}
+	m.mounts = nil
+
+	if m.etcDirectory != nil {
+		if err := m.etcDirectory.Close(); err != nil && firstErr == nil {
+			firstErr = util.StatusWrapWithCode(err, codes.Internal, "Failed to close directory \"/etc\"")
+		}
+		m.etcDirectory = nil
+	}
+	if m.createdEtcDirectory && firstErr == nil {
+		if err := inputRoot.Remove(etcDirectoryName); err != nil {
+			firstErr = util.StatusWrapWithCode(err, codes.Internal, "Failed to remove directory \"/etc\"")
+		}
+	}
+	return firstErr
+}
+
+type dnsConfigurationMountingRunner struct {
+	base               runner_pb.RunnerServer
+	buildDirectory     filesystem.Directory
+	buildDirectoryPath *path.Builder
+	bindMounter        BindMounter
+	sourcePaths        map[path.Component]string
+}
+
+// NewDNSConfigurationMountingRunner creates a decorator for Runner
+// that bind mounts a resolv.conf and hosts file at /etc/resolv.conf
+// and /etc/hosts inside the input root of an action. When combined
+// with chrooting into the input root, this ensures that actions
+// use a DNS configuration that is provided by the worker's
+// configuration, as opposed to one that is part of the input root
+// or the host system. It also permits injecting fake host names
+// that are needed by tests.
+//
+// Files and symbolic links that already exist at these locations
+// are shadowed for the duration of the action. Mountpoints that need
+// to be created are removed after the action completes, so that the
+// input root is restored to its original state.
+func NewDNSConfigurationMountingRunner(base runner_pb.RunnerServer, buildDirectory filesystem.Directory, buildDirectoryPath *path.Builder, bindMounter BindMounter, resolvConfPath, hostsPath string) runner_pb.RunnerServer {
+	return &dnsConfigurationMountingRunner{
+		base:               base,
+		buildDirectory:     buildDirectory,
+		buildDirectoryPath: buildDirectoryPath,
+		bindMounter:        bindMounter,
+		sourcePaths: map[path.Component]string{
+			resolvConfFileName: resolvConfPath,
+			hostsFileName:      hostsPath,
+		},
+	}
+}
+
+func (r *dnsConfigurationMountingRunner) mountAll(inputRoot filesystem.Directory, inputRootPath *path.Builder, m *dnsConfigurationMounts) error {
+	// Create /etc if the input root does not contain it.
+	if err := inputRoot.Mkdir(etcDirectoryName, 0o755); err == nil {
+		m.createdEtcDirectory = true
+	} else if !os.IsExist(err) {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to create directory \"/etc\"")
+	}
+	etcDirectory, err := inputRoot.EnterDirectory(etcDirectoryName)
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to enter directory \"/etc\"")
+	}
+	m.etcDirectory = etcDirectory
+
+	for _, name := range dnsConfigurationFileNames {
+		// Bind mounts can only be placed on top of existing
+		// files. Symbolic links are replaced by empty files, as
+		// mounting on top of them would cause the symbolic link
+		// to be followed, which may point outside the input root.
+		mount := activeFileMount{name: name}
+		fileInfo, err := etcDirectory.Lstat(name)
+		if err == nil {
+			switch fileInfo.Type() {
+			case filesystem.FileTypeRegularFile:
+			case filesystem.FileTypeSymlink:
+				target, err := etcDirectory.Readlink(name)
+				if err != nil {
+					return util.StatusWrapfWithCode(err, codes.Internal, "Failed to read symbolic link \"/etc/%s\"", name)
+				}
+				if err := etcDirectory.Remove(name); err != nil {
+					return util.StatusWrapfWithCode(err, codes.Internal, "Failed to remove symbolic link \"/etc/%s\"", name)
+				}
+				mount.symlinkTarget = &target
+			default:
+				return status.Errorf(codes.InvalidArgument, "\"/etc/%s\" in the input root is not a regular file or symbolic link", name)
+			}
+		} else if !os.IsNotExist(err) {
+			return util.StatusWrapfWithCode(err, codes.Internal, "Failed to obtain file information for \"/etc/%s\"", name)
+		}
+		if err != nil || mount.symlinkTarget != nil {
+			f, err := etcDirectory.OpenWrite(name, filesystem.CreateExcl(0o644))
+			if err != nil {
+				if mount.symlinkTarget != nil {
+					etcDirectory.Symlink(*mount.symlinkTarget, name)
+				}
+				return util.StatusWrapfWithCode(err, codes.Internal, "Failed to create mountpoint \"/etc/%s\"", name)
+			}
+			f.Close()
+			mount.createdMountpoint = true
+		}
+
+		targetPath, scopeWalker := inputRootPath.Join(path.VoidScopeWalker)
+		if err := path.Resolve(etcDirectoryName.String()+"/"+name.String(), scopeWalker); err != nil {
+			panic("Failed to resolve path consisting of valid components: " + err.Error())
+		}
+		if err := r.bindMounter.BindMountReadOnly(r.sourcePaths[name], targetPath.String()); err != nil {
+			if mount.createdMountpoint {
+				etcDirectory.Remove(name)
+				if mount.symlinkTarget != nil {
+					etcDirectory.Symlink(*mount.symlinkTarget, name)
+				}
+			}
+			return err
+		}
+		m.mounts = append(m.mounts, mount)
+	}
+	return nil
+}
+
+func (r *dnsConfigurationMountingRunner) Run(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+	// Open the input root directory, and compute its absolute path
+	// for creating bind mounts.
+	inputRootResolver := buildDirectoryPathResolver{
+		stack: util.NewNonEmptyStack(filesystem.NopDirectoryCloser(r.buildDirectory)),
+	}
+	defer inputRootResolver.closeAll()
+	if err := path.Resolve(request.InputRootDirectory, path.NewRelativeScopeWalker(&inputRootResolver)); err != nil {
+		return nil, util.StatusWrap(err, "Failed to resolve input root directory")
+	}
+	if name := inputRootResolver.TerminalName; name != nil {
+		inputRoot, err := inputRootResolver.stack.Peek().EnterDirectory(*name)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to enter input root directory")
+		}
+		inputRootResolver.stack.Push(inputRoot)
+	}
+	inputRootPath, scopeWalker := r.buildDirectoryPath.Join(path.VoidScopeWalker)
+	if err := path.Resolve(request.InputRootDirectory, scopeWalker); err != nil {
+		return nil, util.StatusWrap(err, "Failed to resolve input root directory")
+	}
+
+	inputRoot := inputRootResolver.stack.Peek()
+	var m dnsConfigurationMounts
+	if err := r.mountAll(inputRoot, inputRootPath, &m); err != nil {
+		m.unmountAll(inputRoot)
+		return nil, err
+	}
+
+	response, err := r.base.Run(ctx, request)
+	if unmountErr := m.unmountAll(inputRoot); unmountErr != nil && err == nil {
+		return nil, unmountErr
+	}
+	return response, err
+}
+
+func (r *dnsConfigurationMountingRunner) CheckReadiness(ctx context.Context, request *runner_pb.CheckReadinessRequest) (*emptypb.Empty, error) {
+	return r.base.CheckReadiness(ctx, request)
+}
+
+func (r *dnsConfigurationMountingRunner) GetProcessTree(ctx context.Context, request *runner_pb.GetProcessTreeRequest) (*runner_pb.GetProcessTreeResponse, error) {
+	return r.base.GetProcessTree(ctx, request)
+}
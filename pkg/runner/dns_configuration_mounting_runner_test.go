@@ -0,0 +1,130 @@
+package runner_test
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDNSConfigurationMountingRunner(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	buildDirectory := mock.NewMockDirectory(ctrl)
+	buildDirectoryPath, scopeWalker := path.EmptyBuilder.Join(path.VoidScopeWalker)
+	require.NoError(t, path.Resolve("/worker/build", scopeWalker))
+	bindMounter := mock.NewMockBindMounter(ctrl)
+	baseRunner := mock.NewMockRunnerServer(ctrl)
+	mountingRunner := runner.NewDNSConfigurationMountingRunner(
+		baseRunner,
+		buildDirectory,
+		buildDirectoryPath,
+		bindMounter,
+		"/etc/bb_runner/resolv.conf",
+		"/etc/bb_runner/hosts")
+
+	request := &runner_pb.RunRequest{
+		Arguments:          []string{"ping", "build-cache.test"},
+		WorkingDirectory:   "a/root/subdir",
+		StdoutPath:         "a/stdout",
+		StderrPath:         "a/stderr",
+		InputRootDirectory: "a/root",
+		TemporaryDirectory: "a/tmp",
+	}
+
+	t.Run("MountFailure", func(t *testing.T) {
+		// Failures to create bind mounts should cause the
+		// action to fail. Files and directories that were
+		// created should be removed.
+		directoryA := mock.NewMockDirectoryCloser(ctrl)
+		buildDirectory.EXPECT().EnterDirectory(path.MustNewComponent("a")).Return(directoryA, nil)
+		inputRoot := mock.NewMockDirectoryCloser(ctrl)
+		directoryA.EXPECT().EnterDirectory(path.MustNewComponent("root")).Return(inputRoot, nil)
+		inputRoot.EXPECT().Mkdir(path.MustNewComponent("etc"), os.FileMode(0o755))
+		etcDirectory := mock.NewMockDirectoryCloser(ctrl)
+		inputRoot.EXPECT().EnterDirectory(path.MustNewComponent("etc")).Return(etcDirectory, nil)
+		etcDirectory.EXPECT().Lstat(path.MustNewComponent("resolv.conf")).Return(filesystem.FileInfo{}, syscall.ENOENT)
+		resolvConf := mock.NewMockFileWriter(ctrl)
+		etcDirectory.EXPECT().OpenWrite(path.MustNewComponent("resolv.conf"), filesystem.CreateExcl(0o644)).Return(resolvConf, nil)
+		resolvConf.EXPECT().Close()
+		bindMounter.EXPECT().BindMountReadOnly("/etc/bb_runner/resolv.conf", "/worker/build/a/root/etc/resolv.conf").
+			Return(status.Error(codes.Internal, "Failed to bind mount \"/etc/bb_runner/resolv.conf\" at \"/worker/build/a/root/etc/resolv.conf\": Operation not permitted"))
+		etcDirectory.EXPECT().Remove(path.MustNewComponent("resolv.conf"))
+		etcDirectory.EXPECT().Close()
+		inputRoot.EXPECT().Remove(path.MustNewComponent("etc"))
+		inputRoot.EXPECT().Close()
+		directoryA.EXPECT().Close()
+
+		_, err := mountingRunner.Run(ctx, request)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to bind mount \"/etc/bb_runner/resolv.conf\" at \"/worker/build/a/root/etc/resolv.conf\": Operation not permitted"), err)
+	})
+
+	t.Run("InvalidFileType", func(t *testing.T) {
+		// Files can only be mounted on top of regular files.
+		directoryA := mock.NewMockDirectoryCloser(ctrl)
+		buildDirectory.EXPECT().EnterDirectory(path.MustNewComponent("a")).Return(directoryA, nil)
+		inputRoot := mock.NewMockDirectoryCloser(ctrl)
+		directoryA.EXPECT().EnterDirectory(path.MustNewComponent("root")).Return(inputRoot, nil)
+		inputRoot.EXPECT().Mkdir(path.MustNewComponent("etc"), os.FileMode(0o755)).Return(syscall.EEXIST)
+		etcDirectory := mock.NewMockDirectoryCloser(ctrl)
+		inputRoot.EXPECT().EnterDirectory(path.MustNewComponent("etc")).Return(etcDirectory, nil)
+		etcDirectory.EXPECT().Lstat(path.MustNewComponent("resolv.conf")).
+			Return(filesystem.NewFileInfo(path.MustNewComponent("resolv.conf"), filesystem.FileTypeDirectory, false), nil)
+		etcDirectory.EXPECT().Close()
+		inputRoot.EXPECT().Close()
+		directoryA.EXPECT().Close()
+
+		_, err := mountingRunner.Run(ctx, request)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "\"/etc/resolv.conf\" in the input root is not a regular file or symbolic link"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Symbolic links should be replaced by files for the
+		// duration of the action, while existing files can be
+		// used as mountpoints directly.
+		directoryA := mock.NewMockDirectoryCloser(ctrl)
+		buildDirectory.EXPECT().EnterDirectory(path.MustNewComponent("a")).Return(directoryA, nil)
+		inputRoot := mock.NewMockDirectoryCloser(ctrl)
+		directoryA.EXPECT().EnterDirectory(path.MustNewComponent("root")).Return(inputRoot, nil)
+		inputRoot.EXPECT().Mkdir(path.MustNewComponent("etc"), os.FileMode(0o755)).Return(syscall.EEXIST)
+		etcDirectory := mock.NewMockDirectoryCloser(ctrl)
+		inputRoot.EXPECT().EnterDirectory(path.MustNewComponent("etc")).Return(etcDirectory, nil)
+		etcDirectory.EXPECT().Lstat(path.MustNewComponent("resolv.conf")).
+			Return(filesystem.NewFileInfo(path.MustNewComponent("resolv.conf"), filesystem.FileTypeSymlink, false), nil)
+		etcDirectory.EXPECT().Readlink(path.MustNewComponent("resolv.conf")).Return("../run/systemd/resolve/stub-resolv.conf", nil)
+		etcDirectory.EXPECT().Remove(path.MustNewComponent("resolv.conf"))
+		resolvConf := mock.NewMockFileWriter(ctrl)
+		etcDirectory.EXPECT().OpenWrite(path.MustNewComponent("resolv.conf"), filesystem.CreateExcl(0o644)).Return(resolvConf, nil)
+		resolvConf.EXPECT().Close()
+		bindMounter.EXPECT().BindMountReadOnly("/etc/bb_runner/resolv.conf", "/worker/build/a/root/etc/resolv.conf")
+		etcDirectory.EXPECT().Lstat(path.MustNewComponent("hosts")).
+			Return(filesystem.NewFileInfo(path.MustNewComponent("hosts"), filesystem.FileTypeRegularFile, false), nil)
+		bindMounter.EXPECT().BindMountReadOnly("/etc/bb_runner/hosts", "/worker/build/a/root/etc/hosts")
+
+		baseRunner.EXPECT().Run(ctx, request).Return(&runner_pb.RunResponse{ExitCode: 0}, nil)
+
+		etcDirectory.EXPECT().Unmount(path.MustNewComponent("hosts"))
+		etcDirectory.EXPECT().Unmount(path.MustNewComponent("resolv.conf"))
+		etcDirectory.EXPECT().Remove(path.MustNewComponent("resolv.conf"))
+		etcDirectory.EXPECT().Symlink("../run/systemd/resolve/stub-resolv.conf", path.MustNewComponent("resolv.conf"))
+		etcDirectory.EXPECT().Close()
+		inputRoot.EXPECT().Close()
+		directoryA.EXPECT().Close()
+
+		response, err := mountingRunner.Run(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &runner_pb.RunResponse{ExitCode: 0}, response)
+	})
+}
@@ -0,0 +1,58 @@
+package runner_test
+
+import (
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGenerateResolvConf(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		resolvConf, err := runner.GenerateResolvConf(nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, "# Generated by bb_runner.\n", string(resolvConf))
+	})
+
+	t.Run("InvalidNameserver", func(t *testing.T) {
+		_, err := runner.GenerateResolvConf([]string{"dns.example.com"}, nil, nil)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Name server \"dns.example.com\" is not a valid IP address"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		resolvConf, err := runner.GenerateResolvConf(
+			[]string{"10.0.0.53", "2001:db8::53"},
+			[]string{"build.example.com", "example.com"},
+			[]string{"ndots:1", "timeout:2"})
+		require.NoError(t, err)
+		require.Equal(t, `# Generated by bb_runner.
+nameserver 10.0.0.53
+nameserver 2001:db8::53
+search build.example.com example.com
+options ndots:1 timeout:2
+`, string(resolvConf))
+	})
+}
+
+func TestGenerateHosts(t *testing.T) {
+	t.Run("NoHostnames", func(t *testing.T) {
+		_, err := runner.GenerateHosts([]runner.HostsEntry{{Address: "10.0.0.1"}})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Hosts entry for address \"10.0.0.1\" has no host names"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		hosts, err := runner.GenerateHosts([]runner.HostsEntry{
+			{Address: "10.0.0.1", Hostnames: []string{"build-cache.test", "cache"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, `# Generated by bb_runner.
+127.0.0.1 localhost
+::1 localhost ip6-localhost ip6-loopback
+10.0.0.1 build-cache.test cache
+`, string(hosts))
+	})
+}
@@ -0,0 +1,360 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Names of environment variables that are set to point to the egress
+// proxy. Both upper and lower case variants are provided, as tools
+// differ in which of them they respect.
+var egressProxyEnvironmentVariableNames = []string{
+	"HTTP_PROXY",
+	"HTTPS_PROXY",
+	"http_proxy",
+	"https_proxy",
+}
+
+// Headers that only apply to the connection between the client and
+// the proxy, which should not be forwarded.
+var egressProxyHopByHopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+type egressProxyRunner struct {
+	base                    runner_pb.RunnerServer
+	allowedHostnames        map[string]struct{}
+	allowedDomainSuffixes   []string
+	allowedConnectPorts     map[uint16]struct{}
+	maximumRecordedRequests int
+	transport               http.RoundTripper
+}
+
+// normalizeEgressProxyHostname converts a host name to lower case and
+// strips the trailing dot of fully qualified names, so that host names
+// can be compared by value.
+func normalizeEgressProxyHostname(hostname string) string {
+	return strings.TrimSuffix(strings.ToLower(hostname), ".")
+}
+
+// NewEgressProxyRunner creates a decorator for Runner that launches an
+// HTTP(S) proxy for every command that is executed. Environment
+// variables are set to let commands use this proxy. The proxy only
+// permits access to host names that match the provided allowlist.
+// Tunnels created through CONNECT are only permitted to the provided
+// list of ports.
+//
+// Every command is run in a network namespace of its own that only has
+// a loopback interface, on which the proxy listens. This ensures that
+// commands cannot bypass the proxy by connecting to the network
+// directly. As joining the network namespace is performed by
+// NewPIDNamespaceCommandCreator(), this decorator can only be used in
+// combination with it.
+//
+// Requests received by the proxy are recorded, and are returned as
+// part of the resource usage of the command. This allows auditing the
+// network access of actions.
+func NewEgressProxyRunner(base runner_pb.RunnerServer, allowedDomains []string, allowedConnectPorts []uint32, maximumRecordedRequests int) (runner_pb.RunnerServer, error) {
+	allowedHostnames := map[string]struct{}{}
+	var allowedDomainSuffixes []string
+	for _, domain := range allowedDomains {
+		// Wildcards are only permitted as the leftmost label,
+		// so that patterns always match on a label boundary.
+		normalizedDomain := normalizeEgressProxyHostname(domain)
+		if parentDomain, ok := strings.CutPrefix(normalizedDomain, "*."); ok {
+			if parentDomain == "" || strings.Contains(parentDomain, "*") {
+				return nil, status.Errorf(codes.InvalidArgument, "Invalid allowed domain %#v: Wildcard domains must have the form \"*.example.com\"", domain)
+			}
+			allowedDomainSuffixes = append(allowedDomainSuffixes, "."+parentDomain)
+		} else {
+			if normalizedDomain == "" || strings.Contains(normalizedDomain, "*") {
+				return nil, status.Errorf(codes.InvalidArgument, "Invalid allowed domain %#v: Wildcard domains must have the form \"*.example.com\"", domain)
+			}
+			allowedHostnames[normalizedDomain] = struct{}{}
+		}
+	}
+
+	allowedConnectPortsSet := map[uint16]struct{}{}
+	for _, port := range allowedConnectPorts {
+		if port == 0 || port > math.MaxUint16 {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid allowed CONNECT port %d", port)
+		}
+		allowedConnectPortsSet[uint16(port)] = struct{}{}
+	}
+
+	return &egressProxyRunner{
+		base:                    base,
+		allowedHostnames:        allowedHostnames,
+		allowedDomainSuffixes:   allowedDomainSuffixes,
+		allowedConnectPorts:     allowedConnectPortsSet,
+		maximumRecordedRequests: maximumRecordedRequests,
+		// Don't let outgoing requests use any proxy that is
+		// configured for bb_runner itself.
+		transport: &http.Transport{},
+	}, nil
+}
+
+func (r *egressProxyRunner) Run(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+	// Launch a proxy that is only used by this command, so that
+	// requests can be attributed to it. The proxy listens inside
+	// the command's network namespace, while connections to
+	// upstream servers are made from the host's network namespace.
+	networkNamespace, listener, err := newLoopbackOnlyNetworkNamespace()
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to create network namespace for egress proxy")
+	}
+	defer networkNamespace.Close()
+	proxy := &egressProxy{
+		runner:  r,
+		tunnels: map[net.Conn]struct{}{},
+	}
+	server := &http.Server{Handler: proxy}
+	go server.Serve(listener)
+
+	newRequest := proto.Clone(request).(*runner_pb.RunRequest)
+	if newRequest.EnvironmentVariables == nil {
+		newRequest.EnvironmentVariables = map[string]string{}
+	}
+	proxyURL := "http://" + listener.Addr().String()
+	for _, name := range egressProxyEnvironmentVariableNames {
+		newRequest.EnvironmentVariables[name] = proxyURL
+	}
+	response, err := r.base.Run(NewContextWithNetworkNamespace(ctx, networkNamespace), newRequest)
+
+	// Terminate any requests that are still in flight, as these
+	// may have been made by processes left behind by the command.
+	server.Close()
+	proxy.closeTunnels()
+	if err != nil {
+		return nil, err
+	}
+
+	proxy.lock.Lock()
+	resourceUsage, err := anypb.New(&proxy.resourceUsage)
+	proxy.lock.Unlock()
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to marshal egress proxy resource usage")
+	}
+	response.ResourceUsage = append(response.ResourceUsage, resourceUsage)
+	return response, nil
+}
+
+func (r *egressProxyRunner) CheckReadiness(ctx context.Context, request *runner_pb.CheckReadinessRequest) (*emptypb.Empty, error) {
+	return r.base.CheckReadiness(ctx, request)
+}
+
+func (r *egressProxyRunner) GetProcessTree(ctx context.Context, request *runner_pb.GetProcessTreeRequest) (*runner_pb.GetProcessTreeResponse, error) {
+	return r.base.GetProcessTree(ctx, request)
+}
+
+// isAllowedHostname returns whether a host name matches the domain
+// allowlist.
+func (r *egressProxyRunner) isAllowedHostname(hostname string) bool {
+	hostname = normalizeEgressProxyHostname(hostname)
+	if _, ok := r.allowedHostnames[hostname]; ok {
+		return true
+	}
+	for _, suffix := range r.allowedDomainSuffixes {
+		if strings.HasSuffix(hostname, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAllowedConnectPort returns whether a port is permitted to be used
+// for tunnels created through CONNECT.
+func (r *egressProxyRunner) isAllowedConnectPort(port string) bool {
+	portNumber, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return false
+	}
+	_, ok := r.allowedConnectPorts[uint16(portNumber)]
+	return ok
+}
+
+// egressProxy is the HTTP handler of the proxy that is launched for a
+// single command.
+type egressProxy struct {
+	runner *egressProxyRunner
+
+	lock          sync.Mutex
+	resourceUsage resourceusage.EgressProxyResourceUsage
+	tunnels       map[net.Conn]struct{}
+	closed        bool
+}
+
+func (p *egressProxy) record(method, url string, allowed bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if len(p.resourceUsage.Requests) < p.runner.maximumRecordedRequests {
+		p.resourceUsage.Requests = append(p.resourceUsage.Requests, &resourceusage.EgressProxyResourceUsage_Request{
+			Method:  method,
+			Url:     url,
+			Allowed: allowed,
+		})
+	} else {
+		p.resourceUsage.UnrecordedRequestsCount++
+	}
+}
+
+func (p *egressProxy) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var url, host string
+	if req.Method == http.MethodConnect {
+		url = req.Host
+		host = req.Host
+	} else {
+		if !req.URL.IsAbs() {
+			http.Error(w, "Only requests for absolute URLs are supported", http.StatusBadRequest)
+			return
+		}
+		url = req.URL.String()
+		host = req.URL.Host
+	}
+	hostname, port := host, ""
+	if splitHostname, splitPort, err := net.SplitHostPort(host); err == nil {
+		hostname, port = splitHostname, splitPort
+	}
+
+	if !p.runner.isAllowedHostname(hostname) {
+		p.record(req.Method, url, false)
+		http.Error(w, "Host is not permitted by the egress proxy allowlist", http.StatusForbidden)
+		return
+	}
+	if req.Method == http.MethodConnect {
+		if !p.runner.isAllowedConnectPort(port) {
+			p.record(req.Method, url, false)
+			http.Error(w, "Port is not permitted by the egress proxy allowlist", http.StatusForbidden)
+			return
+		}
+		p.record(req.Method, url, true)
+		p.tunnel(w, req)
+	} else {
+		p.record(req.Method, url, true)
+		p.forward(w, req)
+	}
+}
+
+// forward a plain HTTP request to its destination.
+func (p *egressProxy) forward(w http.ResponseWriter, req *http.Request) {
+	outgoingRequest := req.Clone(req.Context())
+	outgoingRequest.RequestURI = ""
+	for _, header := range egressProxyHopByHopHeaders {
+		outgoingRequest.Header.Del(header)
+	}
+	response, err := p.runner.transport.RoundTrip(outgoingRequest)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer response.Body.Close()
+
+	for _, header := range egressProxyHopByHopHeaders {
+		response.Header.Del(header)
+	}
+	for name, values := range response.Header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(response.StatusCode)
+	io.Copy(w, response.Body)
+}
+
+// tunnel a connection to its destination, as requested through
+// CONNECT. This is used by clients to access HTTPS URLs.
+func (p *egressProxy) tunnel(w http.ResponseWriter, req *http.Request) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "Connection does not support tunneling", http.StatusInternalServerError)
+		return
+	}
+	var dialer net.Dialer
+	upstream, err := dialer.DialContext(req.Context(), "tcp", req.Host)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	client, clientBuffer, err := hijacker.Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	if !p.addTunnel(client, upstream) {
+		return
+	}
+	if _, err := client.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n")); err != nil {
+		p.removeTunnel(client, upstream)
+		return
+	}
+
+	// Copy data in both directions. Closing both connections when
+	// either direction completes causes the other to complete too.
+	go func() {
+		io.Copy(upstream, clientBuffer)
+		p.removeTunnel(client, upstream)
+	}()
+	io.Copy(client, upstream)
+	p.removeTunnel(client, upstream)
+}
+
+func (p *egressProxy) addTunnel(client, upstream net.Conn) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.closed {
+		client.Close()
+		upstream.Close()
+		return false
+	}
+	p.tunnels[client] = struct{}{}
+	p.tunnels[upstream] = struct{}{}
+	return true
+}
+
+func (p *egressProxy) removeTunnel(client, upstream net.Conn) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	client.Close()
+	upstream.Close()
+	delete(p.tunnels, client)
+	delete(p.tunnels, upstream)
+}
+
+// closeTunnels closes all tunnels that are still open, and prevents
+// new ones from being created. This is needed, as closing the
+// http.Server does not close connections that have been hijacked.
+func (p *egressProxy) closeTunnels() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.closed = true
+	for conn := range p.tunnels {
+		conn.Close()
+	}
+	p.tunnels = map[net.Conn]struct{}{}
+}
@@ -0,0 +1,253 @@
+//go:build linux
+// +build linux
+
+package runner_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// dialInNetworkNamespace connects to a TCP address from within a
+// network namespace, similar to how commands connect to the egress
+// proxy.
+func dialInNetworkNamespace(networkNamespace *os.File, address string) (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		runtime.LockOSThread()
+		originalNetworkNamespace, err := os.Open("/proc/thread-self/ns/net")
+		if err != nil {
+			runtime.UnlockOSThread()
+			results <- result{err: err}
+			return
+		}
+		defer originalNetworkNamespace.Close()
+		if err := unix.Setns(int(networkNamespace.Fd()), unix.CLONE_NEWNET); err != nil {
+			runtime.UnlockOSThread()
+			results <- result{err: err}
+			return
+		}
+		conn, err := net.Dial("tcp", address)
+		if unix.Setns(int(originalNetworkNamespace.Fd()), unix.CLONE_NEWNET) == nil {
+			runtime.UnlockOSThread()
+		}
+		results <- result{conn: conn, err: err}
+	}()
+	r := <-results
+	return r.conn, r.err
+}
+
+func TestEgressProxyRunner(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("Creating network namespaces requires root privileges")
+	}
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Plain"))
+	}))
+	defer httpServer.Close()
+	httpsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Secure"))
+	}))
+	defer httpsServer.Close()
+
+	_, httpsServerPort, err := net.SplitHostPort(httpsServer.Listener.Addr().String())
+	require.NoError(t, err)
+	allowedConnectPort, err := strconv.ParseUint(httpsServerPort, 10, 16)
+	require.NoError(t, err)
+
+	// Creates an HTTP client that uses the proxy that is provided
+	// to the command through environment variables. Connections
+	// are made from within the network namespace of the command.
+	newClient := func(t *testing.T, ctx context.Context, request *runner_pb.RunRequest) *http.Client {
+		proxyURL, err := url.Parse(request.EnvironmentVariables["HTTPS_PROXY"])
+		require.NoError(t, err)
+		require.Equal(t, proxyURL.String(), request.EnvironmentVariables["http_proxy"])
+		networkNamespace := runner.GetNetworkNamespaceFromContext(ctx)
+		require.NotNil(t, networkNamespace)
+		transport := httpsServer.Client().Transport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyURL(proxyURL)
+		transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+			return dialInNetworkNamespace(networkNamespace, address)
+		}
+		return &http.Client{Transport: transport}
+	}
+	get := func(t *testing.T, client *http.Client, url string) (int, string) {
+		response, err := client.Get(url)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		return response.StatusCode, string(body)
+	}
+
+	baseRunner := mock.NewMockRunnerServer(ctrl)
+
+	t.Run("InvalidAllowedDomain", func(t *testing.T) {
+		// Wildcards are only permitted as the leftmost label, as
+		// patterns like "*example.com" would also match
+		// "evilexample.com".
+		_, err := runner.NewEgressProxyRunner(baseRunner, []string{"*example.com"}, []uint32{443}, 2)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid allowed domain \"*example.com\": Wildcard domains must have the form \"*.example.com\""), err)
+
+		_, err = runner.NewEgressProxyRunner(baseRunner, []string{"*.*.example.com"}, []uint32{443}, 2)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid allowed domain \"*.*.example.com\": Wildcard domains must have the form \"*.example.com\""), err)
+	})
+
+	t.Run("InvalidAllowedConnectPort", func(t *testing.T) {
+		_, err := runner.NewEgressProxyRunner(baseRunner, []string{"example.com"}, []uint32{65536}, 2)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid allowed CONNECT port 65536"), err)
+	})
+
+	egressProxyRunner, err := runner.NewEgressProxyRunner(baseRunner, []string{"127.0.0.1", "*.example.com"}, []uint32{uint32(allowedConnectPort)}, 2)
+	require.NoError(t, err)
+
+	t.Run("Failure", func(t *testing.T) {
+		// Errors of the base runner should be propagated.
+		baseRunner.EXPECT().Run(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Internal, "Failed to start process"))
+
+		_, err := egressProxyRunner.Run(ctx, &runner_pb.RunRequest{
+			Arguments: []string{"curl", "http://example.com/"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to start process"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Requests for hosts on the allowlist should be
+		// forwarded, both for plain HTTP and HTTPS. Requests
+		// for other hosts should be denied. All of them should
+		// be recorded, up to the configured maximum.
+		baseRunner.EXPECT().Run(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+			require.Equal(t, "/bin", request.EnvironmentVariables["PATH"])
+			client := newClient(t, ctx, request)
+
+			statusCode, body := get(t, client, httpServer.URL+"/hello")
+			require.Equal(t, http.StatusOK, statusCode)
+			require.Equal(t, "Plain", body)
+
+			statusCode, body = get(t, client, httpsServer.URL+"/world")
+			require.Equal(t, http.StatusOK, statusCode)
+			require.Equal(t, "Secure", body)
+
+			statusCode, _ = get(t, client, strings.Replace(httpServer.URL, "127.0.0.1", "localhost", 1))
+			require.Equal(t, http.StatusForbidden, statusCode)
+			return &runner_pb.RunResponse{ExitCode: 0}, nil
+		})
+
+		response, err := egressProxyRunner.Run(ctx, &runner_pb.RunRequest{
+			Arguments:            []string{"curl", "http://example.com/"},
+			EnvironmentVariables: map[string]string{"PATH": "/bin"},
+		})
+		require.NoError(t, err)
+		require.Len(t, response.ResourceUsage, 1)
+		var resourceUsage resourceusage.EgressProxyResourceUsage
+		require.NoError(t, response.ResourceUsage[0].UnmarshalTo(&resourceUsage))
+		testutil.RequireEqualProto(t, &resourceusage.EgressProxyResourceUsage{
+			Requests: []*resourceusage.EgressProxyResourceUsage_Request{
+				{Method: "GET", Url: httpServer.URL + "/hello", Allowed: true},
+				{Method: "CONNECT", Url: strings.TrimPrefix(httpsServer.URL, "https://"), Allowed: true},
+			},
+			UnrecordedRequestsCount: 1,
+		}, &resourceUsage)
+	})
+
+	t.Run("Subdomain", func(t *testing.T) {
+		// Wildcard entries should match subdomains, but not
+		// the domain itself. Matching should be performed on
+		// label boundaries.
+		baseRunner.EXPECT().Run(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+			client := newClient(t, ctx, request)
+			statusCode, _ := get(t, client, "http://example.com/")
+			require.Equal(t, http.StatusForbidden, statusCode)
+			statusCode, _ = get(t, client, "http://evilexample.com/")
+			require.Equal(t, http.StatusForbidden, statusCode)
+			return &runner_pb.RunResponse{ExitCode: 1}, nil
+		})
+
+		response, err := egressProxyRunner.Run(ctx, &runner_pb.RunRequest{
+			Arguments: []string{"curl", "http://example.com/"},
+		})
+		require.NoError(t, err)
+		resourceUsage, err := anypb.New(&resourceusage.EgressProxyResourceUsage{
+			Requests: []*resourceusage.EgressProxyResourceUsage_Request{
+				{Method: "GET", Url: "http://example.com/", Allowed: false},
+				{Method: "GET", Url: "http://evilexample.com/", Allowed: false},
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &runner_pb.RunResponse{
+			ExitCode:      1,
+			ResourceUsage: []*anypb.Any{resourceUsage},
+		}, response)
+	})
+
+	t.Run("ConnectPort", func(t *testing.T) {
+		// Tunnels should only be created to ports on the
+		// allowlist, even if the host is permitted.
+		baseRunner.EXPECT().Run(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+			client := newClient(t, ctx, request)
+			_, err := client.Get(strings.Replace(httpServer.URL, "http://", "https://", 1))
+			require.ErrorContains(t, err, "Forbidden")
+			return &runner_pb.RunResponse{ExitCode: 1}, nil
+		})
+
+		response, err := egressProxyRunner.Run(ctx, &runner_pb.RunRequest{
+			Arguments: []string{"curl", "https://127.0.0.1:22/"},
+		})
+		require.NoError(t, err)
+		var resourceUsage resourceusage.EgressProxyResourceUsage
+		require.Len(t, response.ResourceUsage, 1)
+		require.NoError(t, response.ResourceUsage[0].UnmarshalTo(&resourceUsage))
+		testutil.RequireEqualProto(t, &resourceusage.EgressProxyResourceUsage{
+			Requests: []*resourceusage.EgressProxyResourceUsage_Request{
+				{Method: "CONNECT", Url: strings.TrimPrefix(httpServer.URL, "http://"), Allowed: false},
+			},
+		}, &resourceUsage)
+	})
+
+	t.Run("DirectConnection", func(t *testing.T) {
+		// Commands should not be able to bypass the proxy by
+		// connecting to servers directly, as their network
+		// namespace only contains a loopback interface.
+		baseRunner.EXPECT().Run(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+			networkNamespace := runner.GetNetworkNamespaceFromContext(ctx)
+			require.NotNil(t, networkNamespace)
+			_, err := dialInNetworkNamespace(networkNamespace, httpServer.Listener.Addr().String())
+			require.ErrorIs(t, err, unix.ECONNREFUSED)
+			return &runner_pb.RunResponse{ExitCode: 7}, nil
+		})
+
+		response, err := egressProxyRunner.Run(ctx, &runner_pb.RunRequest{
+			Arguments: []string{"curl", "--noproxy", "*", "http://127.0.0.1/"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(7), response.ExitCode)
+	})
+}
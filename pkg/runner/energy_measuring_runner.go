@@ -0,0 +1,102 @@
+package runner
+
+import (
+	"context"
+	"sync"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type energyMeasuringRunner struct {
+	base  runner_pb.RunnerServer
+	meter EnergyMeter
+
+	lock sync.Mutex
+	// The amount of energy consumed by the system, as observed
+	// when the meter was last read.
+	lastSystemEnergyJoules float64
+	// The number of actions that are currently running.
+	runningActions int
+	// The cumulative amount of energy attributed to every running
+	// action. The energy consumed by an action is the difference
+	// between the values observed when it started and completed.
+	energyPerActionJoules float64
+}
+
+// NewEnergyMeasuringRunner creates a decorator for Runner that
+// estimates the amount of energy consumed by every command that is
+// executed. As energy meters typically only cover the system as a
+// whole, the energy consumed while multiple commands run concurrently
+// is distributed equally across these commands. The estimate is
+// returned as part of the resource usage of the command.
+func NewEnergyMeasuringRunner(base runner_pb.RunnerServer, meter EnergyMeter) runner_pb.RunnerServer {
+	return &energyMeasuringRunner{
+		base:  base,
+		meter: meter,
+	}
+}
+
+// update the energy attributed to every running action, based on the
+// energy consumed by the system since the meter was last read.
+func (r *energyMeasuringRunner) update() error {
+	systemEnergyJoules, err := r.meter.GetEnergyConsumedJoules()
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to read energy meter")
+	}
+	if r.runningActions > 0 {
+		r.energyPerActionJoules += (systemEnergyJoules - r.lastSystemEnergyJoules) / float64(r.runningActions)
+	}
+	r.lastSystemEnergyJoules = systemEnergyJoules
+	return nil
+}
+
+func (r *energyMeasuringRunner) Run(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+	r.lock.Lock()
+	if err := r.update(); err != nil {
+		r.lock.Unlock()
+		return nil, err
+	}
+	startSystemEnergyJoules := r.lastSystemEnergyJoules
+	startEnergyPerActionJoules := r.energyPerActionJoules
+	r.runningActions++
+	r.lock.Unlock()
+
+	response, err := r.base.Run(ctx, request)
+
+	r.lock.Lock()
+	updateErr := r.update()
+	r.runningActions--
+	endSystemEnergyJoules := r.lastSystemEnergyJoules
+	endEnergyPerActionJoules := r.energyPerActionJoules
+	r.lock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if updateErr != nil {
+		return nil, updateErr
+	}
+
+	resourceUsage, err := anypb.New(&resourceusage.EnergyResourceUsage{
+		EstimatedEnergyJoules: endEnergyPerActionJoules - startEnergyPerActionJoules,
+		SystemEnergyJoules:    endSystemEnergyJoules - startSystemEnergyJoules,
+	})
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to marshal energy resource usage")
+	}
+	response.ResourceUsage = append(response.ResourceUsage, resourceUsage)
+	return response, nil
+}
+
+func (r *energyMeasuringRunner) CheckReadiness(ctx context.Context, request *runner_pb.CheckReadinessRequest) (*emptypb.Empty, error) {
+	return r.base.CheckReadiness(ctx, request)
+}
+
+func (r *energyMeasuringRunner) GetProcessTree(ctx context.Context, request *runner_pb.GetProcessTreeRequest) (*runner_pb.GetProcessTreeResponse, error) {
+	return r.base.GetProcessTree(ctx, request)
+}
@@ -0,0 +1,85 @@
+package runner_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestEnergyMeasuringRunner(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseRunner := mock.NewMockRunnerServer(ctrl)
+	energyMeter := mock.NewMockEnergyMeter(ctrl)
+	runnerServer := runner.NewEnergyMeasuringRunner(baseRunner, energyMeter)
+
+	requestA := &runner_pb.RunRequest{
+		Arguments:          []string{"cc", "-c", "a.c"},
+		InputRootDirectory: "a/root",
+	}
+	requestB := &runner_pb.RunRequest{
+		Arguments:          []string{"cc", "-c", "b.c"},
+		InputRootDirectory: "b/root",
+	}
+
+	t.Run("MeterFailure", func(t *testing.T) {
+		energyMeter.EXPECT().GetEnergyConsumedJoules().
+			Return(0.0, status.Error(codes.PermissionDenied, "Failed to read \"/sys/class/powercap/intel-rapl:0/energy_uj\": Permission denied"))
+
+		_, err := runnerServer.Run(ctx, requestA)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to read energy meter: Failed to read \"/sys/class/powercap/intel-rapl:0/energy_uj\": Permission denied"), err)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		// Action A runs on its own between 100 J and 130 J,
+		// together with action B between 130 J and 190 J,
+		// and on its own again between 190 J and 200 J.
+		energyMeter.EXPECT().GetEnergyConsumedJoules().Return(100.0, nil)
+		baseRunner.EXPECT().Run(ctx, requestA).DoAndReturn(
+			func(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+				energyMeter.EXPECT().GetEnergyConsumedJoules().Return(130.0, nil)
+				baseRunner.EXPECT().Run(ctx, requestB).DoAndReturn(
+					func(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+						energyMeter.EXPECT().GetEnergyConsumedJoules().Return(190.0, nil)
+						return &runner_pb.RunResponse{ExitCode: 1}, nil
+					})
+
+				responseB, err := runnerServer.Run(ctx, requestB)
+				require.NoError(t, err)
+				resourceUsageB, err := anypb.New(&resourceusage.EnergyResourceUsage{
+					EstimatedEnergyJoules: 30,
+					SystemEnergyJoules:    60,
+				})
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, &runner_pb.RunResponse{
+					ExitCode:      1,
+					ResourceUsage: []*anypb.Any{resourceUsageB},
+				}, responseB)
+
+				energyMeter.EXPECT().GetEnergyConsumedJoules().Return(200.0, nil)
+				return &runner_pb.RunResponse{}, nil
+			})
+
+		responseA, err := runnerServer.Run(ctx, requestA)
+		require.NoError(t, err)
+		resourceUsageA, err := anypb.New(&resourceusage.EnergyResourceUsage{
+			EstimatedEnergyJoules: 70,
+			SystemEnergyJoules:    100,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &runner_pb.RunResponse{
+			ResourceUsage: []*anypb.Any{resourceUsageA},
+		}, responseA)
+	})
+}
@@ -0,0 +1,11 @@
+package runner
+
+// EnergyMeter can be used to obtain the amount of energy that has been
+// consumed by the system.
+type EnergyMeter interface {
+	// GetEnergyConsumedJoules returns the total amount of energy
+	// consumed by the system, in joules. The value is only
+	// meaningful when compared against values returned by earlier
+	// calls.
+	GetEnergyConsumedJoules() (float64, error)
+}
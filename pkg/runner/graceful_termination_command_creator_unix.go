@@ -0,0 +1,49 @@
+//go:build darwin || freebsd || linux
+// +build darwin freebsd linux
+
+package runner
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+)
+
+// NewGracefulTerminationCommandCreator creates a decorator for
+// CommandCreator that causes commands to be terminated gracefully
+// when the context associated with them is canceled (e.g., because
+// the client is no longer interested in the results of the action).
+//
+// Commands are run in a process group of their own. Upon cancelation,
+// SIGTERM is sent to all processes in the process group. If the main
+// process of the command does not terminate within the provided grace
+// period, it is sent SIGKILL. Processes that remain after that are
+// cleaned up by the cgroup or PID namespace of the command, if
+// configured.
+func NewGracefulTerminationCommandCreator(base CommandCreator, gracePeriod time.Duration) (CommandCreator, error) {
+	return func(ctx context.Context, arguments []string, inputRootDirectory *path.Builder, workingDirectory, pathVariable string) (*exec.Cmd, error) {
+		cmd, err := base(ctx, arguments, inputRootDirectory, workingDirectory, pathVariable)
+		if err != nil {
+			return nil, err
+		}
+
+		// The SysProcAttr may be shared by many commands, so we
+		// must not modify it in place.
+		var sysProcAttr syscall.SysProcAttr
+		if cmd.SysProcAttr != nil {
+			sysProcAttr = *cmd.SysProcAttr
+		}
+		sysProcAttr.Setpgid = true
+		sysProcAttr.Pgid = 0
+		cmd.SysProcAttr = &sysProcAttr
+
+		cmd.Cancel = func() error {
+			return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+		}
+		cmd.WaitDelay = gracePeriod
+		return cmd, nil
+	}, nil
+}
@@ -0,0 +1,75 @@
+//go:build darwin || freebsd || linux
+// +build darwin freebsd linux
+
+package runner_test
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGracefulTerminationCommandCreator(t *testing.T) {
+	t.Run("BaseFailure", func(t *testing.T) {
+		commandCreator, err := runner.NewGracefulTerminationCommandCreator(
+			func(ctx context.Context, arguments []string, inputRootDirectory *path.Builder, workingDirectory, pathVariable string) (*exec.Cmd, error) {
+				return nil, status.Error(codes.InvalidArgument, "Cannot find executable \"cc\"")
+			},
+			time.Minute)
+		require.NoError(t, err)
+
+		_, err = commandCreator(context.Background(), []string{"cc"}, &path.EmptyBuilder, "", "/usr/bin")
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Cannot find executable \"cc\""), err)
+	})
+
+	t.Run("SIGTERM", func(t *testing.T) {
+		// Commands should be terminated using SIGTERM when the
+		// context is canceled. The SysProcAttr of the base
+		// CommandCreator should be left untouched.
+		sysProcAttr := &syscall.SysProcAttr{}
+		commandCreator, err := runner.NewGracefulTerminationCommandCreator(
+			runner.NewPlainCommandCreator(sysProcAttr),
+			time.Minute)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cmd, err := commandCreator(ctx, []string{"sleep", "60"}, &path.RootBuilder, "", "/bin:/usr/bin")
+		require.NoError(t, err)
+		require.Equal(t, &syscall.SysProcAttr{}, sysProcAttr)
+		require.NoError(t, cmd.Start())
+		cancel()
+
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, cmd.Wait(), &exitErr)
+		require.Equal(t, syscall.SIGTERM, cmd.ProcessState.Sys().(syscall.WaitStatus).Signal())
+	})
+
+	t.Run("SIGKILL", func(t *testing.T) {
+		// Commands that ignore SIGTERM should be terminated
+		// using SIGKILL after the grace period.
+		commandCreator, err := runner.NewGracefulTerminationCommandCreator(
+			runner.NewPlainCommandCreator(&syscall.SysProcAttr{}),
+			100*time.Millisecond)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cmd, err := commandCreator(ctx, []string{"sh", "-c", "trap '' TERM; while :; do :; done"}, &path.RootBuilder, "", "/bin:/usr/bin")
+		require.NoError(t, err)
+		require.NoError(t, cmd.Start())
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+
+		cmd.Wait()
+		require.Equal(t, syscall.SIGKILL, cmd.ProcessState.Sys().(syscall.WaitStatus).Signal())
+	})
+}
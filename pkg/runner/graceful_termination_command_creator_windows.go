@@ -0,0 +1,19 @@
+//go:build windows
+// +build windows
+
+package runner
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewGracefulTerminationCommandCreator creates a decorator for
+// CommandCreator that causes commands to be terminated gracefully
+// when the context associated with them is canceled. On this operating
+// system this functionality is not available.
+func NewGracefulTerminationCommandCreator(base CommandCreator, gracePeriod time.Duration) (CommandCreator, error) {
+	return nil, status.Error(codes.Unimplemented, "Graceful termination of commands is not supported on this platform")
+}
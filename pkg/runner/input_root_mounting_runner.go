@@ -0,0 +1,300 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// DevShmSizePlatformPropertyName is the name of the platform property
+// that may be used by actions to request the size in bytes of the
+// tmpfs that is mounted at /dev/shm.
+const DevShmSizePlatformPropertyName = "resources:dev-shm"
+
+var (
+	devDirectoryName  = path.MustNewComponent("dev")
+	procDirectoryName = path.MustNewComponent("proc")
+	shmDirectoryName  = path.MustNewComponent("shm")
+	tmpDirectoryName  = path.MustNewComponent("tmp")
+)
+
+// activeMount keeps track of a file system that was mounted by
+// inputRootMountingRunner, so that it can be unmounted after the
+// action completes.
+type activeMount struct {
+	parent            filesystem.Directory
+	name              path.Component
+	createdMountpoint bool
+
+	// Handle to the root directory of the mounted file system, if
+	// opened. It needs to be closed prior to unmounting.
+	directory filesystem.DirectoryCloser
+}
+
+// inputRootMounts keeps track of all file systems that were mounted
+// inside the input root of a single action.
+type inputRootMounts struct {
+	mounts []activeMount
+}
+
+func (m *inputRootMounts) mount(parent filesystem.Directory, name path.Component, fstype string) error {
+	return m.mountUsing(parent, name, func() error {
+		return parent.Mount(name, fstype, fstype)
+	})
+}
+
+func (m *inputRootMounts) mountUsing(parent filesystem.Directory, name path.Component, doMount func() error) error {
+	// Create the mountpoint if it does not exist already, so that
+	// file systems can be mounted inside input roots that don't
+	// contain a full userland installation.
+	createdMountpoint := true
+	if err := parent.Mkdir(name, 0o755); err != nil {
+		if !os.IsExist(err) {
+			return util.StatusWrapfWithCode(err, codes.Internal, "Failed to create mountpoint %#v", name.String())
+		}
+		createdMountpoint = false
+	}
+	if err := doMount(); err != nil {
+		if createdMountpoint {
+			parent.Remove(name)
+		}
+		return util.StatusWrapfWithCode(err, codes.Internal, "Failed to mount %#v", name.String())
+	}
+	m.mounts = append(m.mounts, activeMount{
+		parent:            parent,
+		name:              name,
+		createdMountpoint: createdMountpoint,
+	})
+	return nil
+}
+
+// enter the root directory of the file system that was mounted most
+// recently.
+func (m *inputRootMounts) enter() (filesystem.Directory, error) {
+	mount := &m.mounts[len(m.mounts)-1]
+	directory, err := mount.parent.EnterDirectory(mount.name)
+	if err != nil {
+		return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to enter directory %#v", mount.name.String())
+	}
+	mount.directory = directory
+	return directory, nil
+}
+
+// unmountAll unmounts all file systems in reverse order. Mountpoints
+// that were created by mount() are removed afterwards, so that the
+// input root is restored to its original state.
+func (m *inputRootMounts) unmountAll() error {
+	var firstErr error
+	for i := len(m.mounts) - 1; i >= 0; i-- {
+		mount := m.mounts[i]
+		if mount.directory != nil {
+			if err := mount.directory.Close(); err != nil && firstErr == nil {
+				firstErr = util.StatusWrapfWithCode(err, codes.Internal, "Failed to close directory %#v", mount.name.String())
+			}
+		}
+		if err := mount.parent.Unmount(mount.name); err != nil {
+			if firstErr == nil {
+				firstErr = util.StatusWrapfWithCode(err, codes.Internal, "Failed to unmount %#v", mount.name.String())
+			}
+			continue
+		}
+		if mount.createdMountpoint {
+			if err := mount.parent.Remove(mount.name); err != nil && firstErr == nil {
+				firstErr = util.StatusWrapfWithCode(err, codes.Internal, "Failed to remove mountpoint %#v", mount.name.String())
+			}
+		}
+	}
+	m.mounts = nil
+	return firstErr
+}
+
+// DevShmConfiguration contains options for the tmpfs that is mounted
+// at /dev/shm by NewInputRootMountingRunner().
+type DevShmConfiguration struct {
+	// The size of the tmpfs in bytes. If zero, the kernel's
+	// default of half the amount of physical memory is used.
+	SizeBytes uint64
+
+	// Overrides of SizeBytes for actions of a given size class.
+	SizeBytesPerSizeClass map[uint32]uint64
+
+	// The maximum size in bytes that actions may request through
+	// the "resources:dev-shm" platform property. If zero, actions
+	// may not request a size.
+	MaximumSizeBytes uint64
+
+	// Used to mount instances of tmpfs whose size is not zero. As
+	// it operates on absolute paths, the path of the build
+	// directory needs to be provided as well.
+	TmpfsMounter       TmpfsMounter
+	BuildDirectoryPath *path.Builder
+}
+
+// getSizeBytes returns the size of the tmpfs that should be mounted at
+// /dev/shm for a given action. Sizes requested through platform
+// properties take precedence over ones based on the size class.
+func (c *DevShmConfiguration) getSizeBytes(request *runner_pb.RunRequest) (uint64, error) {
+	for _, property := range request.Platform.GetProperties() {
+		if property.Name == DevShmSizePlatformPropertyName {
+			sizeBytes, err := strconv.ParseUint(property.Value, 10, 64)
+			if err != nil {
+				return 0, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid value for platform property %#v", DevShmSizePlatformPropertyName)
+			}
+			if sizeBytes == 0 || sizeBytes > c.MaximumSizeBytes {
+				return 0, status.Errorf(codes.InvalidArgument, "Platform property %#v must be between 1 and %d bytes", DevShmSizePlatformPropertyName, c.MaximumSizeBytes)
+			}
+			return sizeBytes, nil
+		}
+	}
+	if sizeBytes, ok := c.SizeBytesPerSizeClass[request.SizeClass]; ok {
+		return sizeBytes, nil
+	}
+	return c.SizeBytes, nil
+}
+
+type inputRootMountingRunner struct {
+	base                runner_pb.RunnerServer
+	buildDirectory      filesystem.Directory
+	mountProc           bool
+	devCharacterDevices map[path.Component]filesystem.DeviceNumber
+	devShm              *DevShmConfiguration
+	mountTmp            bool
+}
+
+// NewInputRootMountingRunner creates a decorator for Runner that
+// mounts file systems inside the input root of an action, so that
+// actions that are run in a chroot have access to a hermetic /proc,
+// /dev and /tmp. The following file systems may be mounted:
+//
+//   - An instance of procfs at /proc.
+//   - A tmpfs at /dev, containing only the provided character devices
+//     (e.g., null, zero and urandom), optionally having another tmpfs
+//     mounted at /dev/shm. The size of the latter may depend on the
+//     size class of the action, or be requested by the action through
+//     a platform property. This prevents actions from contending on
+//     the host's /dev/shm.
+//   - A tmpfs at /tmp that is private to the action.
+//
+// All of these file systems are unmounted after the action completes.
+func NewInputRootMountingRunner(base runner_pb.RunnerServer, buildDirectory filesystem.Directory, mountProc bool, devCharacterDevices map[path.Component]filesystem.DeviceNumber, devShm *DevShmConfiguration, mountTmp bool) runner_pb.RunnerServer {
+	return &inputRootMountingRunner{
+		base:                base,
+		buildDirectory:      buildDirectory,
+		mountProc:           mountProc,
+		devCharacterDevices: devCharacterDevices,
+		devShm:              devShm,
+		mountTmp:            mountTmp,
+	}
+}
+
+// mountDevShm mounts a tmpfs at /dev/shm, having the size that is
+// configured for the action.
+func (r *inputRootMountingRunner) mountDevShm(devDirectory filesystem.Directory, request *runner_pb.RunRequest, m *inputRootMounts) error {
+	sizeBytes, err := r.devShm.getSizeBytes(request)
+	if err != nil {
+		return err
+	}
+	if sizeBytes == 0 {
+		return m.mount(devDirectory, shmDirectoryName, "tmpfs")
+	}
+
+	if r.devShm.TmpfsMounter == nil {
+		return status.Error(codes.InvalidArgument, "Mounting /dev/shm with an explicit size is not supported by this runner")
+	}
+	inputRootPath, scopeWalker := r.devShm.BuildDirectoryPath.Join(path.VoidScopeWalker)
+	if err := path.Resolve(request.InputRootDirectory, scopeWalker); err != nil {
+		return util.StatusWrap(err, "Failed to resolve input root directory")
+	}
+	targetPath, scopeWalker := inputRootPath.Join(path.VoidScopeWalker)
+	if err := path.Resolve(devDirectoryName.String()+"/"+shmDirectoryName.String(), scopeWalker); err != nil {
+		panic("Failed to resolve path consisting of valid components: " + err.Error())
+	}
+	return m.mountUsing(devDirectory, shmDirectoryName, func() error {
+		return r.devShm.TmpfsMounter.MountTmpfs(targetPath.String(), sizeBytes)
+	})
+}
+
+func (r *inputRootMountingRunner) mountAll(inputRoot filesystem.Directory, request *runner_pb.RunRequest, m *inputRootMounts) error {
+	if r.mountProc {
+		if err := m.mount(inputRoot, procDirectoryName, "proc"); err != nil {
+			return err
+		}
+	}
+
+	if len(r.devCharacterDevices) > 0 || r.devShm != nil {
+		// Instead of exposing the host's /dev, create a new
+		// /dev that only contains the devices that are needed
+		// by actions.
+		if err := m.mount(inputRoot, devDirectoryName, "tmpfs"); err != nil {
+			return err
+		}
+		devDirectory, err := m.enter()
+		if err != nil {
+			return err
+		}
+		for name, deviceNumber := range r.devCharacterDevices {
+			if err := devDirectory.Mknod(name, os.ModeDevice|os.ModeCharDevice|0o666, deviceNumber); err != nil {
+				return util.StatusWrapfWithCode(err, codes.Internal, "Failed to create character device %#v", name.String())
+			}
+		}
+		if r.devShm != nil {
+			if err := r.mountDevShm(devDirectory, request, m); err != nil {
+				return err
+			}
+		}
+	}
+
+	if r.mountTmp {
+		if err := m.mount(inputRoot, tmpDirectoryName, "tmpfs"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *inputRootMountingRunner) Run(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+	// Open the input root directory.
+	inputRootResolver := buildDirectoryPathResolver{
+		stack: util.NewNonEmptyStack(filesystem.NopDirectoryCloser(r.buildDirectory)),
+	}
+	defer inputRootResolver.closeAll()
+	if err := path.Resolve(request.InputRootDirectory, path.NewRelativeScopeWalker(&inputRootResolver)); err != nil {
+		return nil, util.StatusWrap(err, "Failed to resolve input root directory")
+	}
+	if name := inputRootResolver.TerminalName; name != nil {
+		inputRoot, err := inputRootResolver.stack.Peek().EnterDirectory(*name)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to enter input root directory")
+		}
+		inputRootResolver.stack.Push(inputRoot)
+	}
+
+	var m inputRootMounts
+	if err := r.mountAll(inputRootResolver.stack.Peek(), request, &m); err != nil {
+		m.unmountAll()
+		return nil, err
+	}
+
+	response, err := r.base.Run(ctx, request)
+	if unmountErr := m.unmountAll(); unmountErr != nil && err == nil {
+		return nil, unmountErr
+	}
+	return response, err
+}
+
+func (r *inputRootMountingRunner) CheckReadiness(ctx context.Context, request *runner_pb.CheckReadinessRequest) (*emptypb.Empty, error) {
+	return r.base.CheckReadiness(ctx, request)
+}
+
+func (r *inputRootMountingRunner) GetProcessTree(ctx context.Context, request *runner_pb.GetProcessTreeRequest) (*runner_pb.GetProcessTreeResponse, error) {
+	return r.base.GetProcessTree(ctx, request)
+}
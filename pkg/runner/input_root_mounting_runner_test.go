@@ -0,0 +1,243 @@
+package runner_test
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestInputRootMountingRunner(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	buildDirectory := mock.NewMockDirectory(ctrl)
+	baseRunner := mock.NewMockRunnerServer(ctrl)
+	nullDevice := filesystem.NewDeviceNumberFromMajorMinor(1, 3)
+	mountingRunner := runner.NewInputRootMountingRunner(
+		baseRunner,
+		buildDirectory,
+		/* mountProc = */ true,
+		map[path.Component]filesystem.DeviceNumber{
+			path.MustNewComponent("null"): nullDevice,
+		},
+		&runner.DevShmConfiguration{},
+		/* mountTmp = */ true)
+
+	request := &runner_pb.RunRequest{
+		Arguments:          []string{"cc", "-o", "hello.o", "hello.c"},
+		WorkingDirectory:   "a/root/subdir",
+		StdoutPath:         "a/stdout",
+		StderrPath:         "a/stderr",
+		InputRootDirectory: "a/root",
+		TemporaryDirectory: "a/tmp",
+	}
+
+	t.Run("MountFailure", func(t *testing.T) {
+		// Failures to mount file systems should cause the
+		// action to fail. Mountpoints that were created should
+		// be removed.
+		directoryA := mock.NewMockDirectoryCloser(ctrl)
+		buildDirectory.EXPECT().EnterDirectory(path.MustNewComponent("a")).Return(directoryA, nil)
+		inputRoot := mock.NewMockDirectoryCloser(ctrl)
+		directoryA.EXPECT().EnterDirectory(path.MustNewComponent("root")).Return(inputRoot, nil)
+		inputRoot.EXPECT().Mkdir(path.MustNewComponent("proc"), os.FileMode(0o755))
+		inputRoot.EXPECT().Mount(path.MustNewComponent("proc"), "proc", "proc").Return(syscall.EPERM)
+		inputRoot.EXPECT().Remove(path.MustNewComponent("proc"))
+		inputRoot.EXPECT().Close()
+		directoryA.EXPECT().Close()
+
+		_, err := mountingRunner.Run(ctx, request)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to mount \"proc\": operation not permitted"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Successfully mount all file systems, run the action,
+		// and unmount all file systems in reverse order.
+		directoryA := mock.NewMockDirectoryCloser(ctrl)
+		buildDirectory.EXPECT().EnterDirectory(path.MustNewComponent("a")).Return(directoryA, nil)
+		inputRoot := mock.NewMockDirectoryCloser(ctrl)
+		directoryA.EXPECT().EnterDirectory(path.MustNewComponent("root")).Return(inputRoot, nil)
+
+		// The input root already contains a "proc" directory,
+		// meaning that it should not be removed afterwards.
+		inputRoot.EXPECT().Mkdir(path.MustNewComponent("proc"), os.FileMode(0o755)).Return(syscall.EEXIST)
+		inputRoot.EXPECT().Mount(path.MustNewComponent("proc"), "proc", "proc")
+		inputRoot.EXPECT().Mkdir(path.MustNewComponent("dev"), os.FileMode(0o755))
+		inputRoot.EXPECT().Mount(path.MustNewComponent("dev"), "tmpfs", "tmpfs")
+		devDirectory := mock.NewMockDirectoryCloser(ctrl)
+		inputRoot.EXPECT().EnterDirectory(path.MustNewComponent("dev")).Return(devDirectory, nil)
+		devDirectory.EXPECT().Mknod(path.MustNewComponent("null"), os.ModeDevice|os.ModeCharDevice|0o666, nullDevice)
+		devDirectory.EXPECT().Mkdir(path.MustNewComponent("shm"), os.FileMode(0o755))
+		devDirectory.EXPECT().Mount(path.MustNewComponent("shm"), "tmpfs", "tmpfs")
+		inputRoot.EXPECT().Mkdir(path.MustNewComponent("tmp"), os.FileMode(0o755))
+		inputRoot.EXPECT().Mount(path.MustNewComponent("tmp"), "tmpfs", "tmpfs")
+
+		response := &runner_pb.RunResponse{
+			ExitCode: 123,
+		}
+		baseRunner.EXPECT().Run(ctx, testutil.EqProto(t, request)).Return(response, nil)
+
+		gomock.InOrder(
+			inputRoot.EXPECT().Unmount(path.MustNewComponent("tmp")),
+			inputRoot.EXPECT().Remove(path.MustNewComponent("tmp")),
+			devDirectory.EXPECT().Unmount(path.MustNewComponent("shm")),
+			devDirectory.EXPECT().Remove(path.MustNewComponent("shm")),
+			devDirectory.EXPECT().Close(),
+			inputRoot.EXPECT().Unmount(path.MustNewComponent("dev")),
+			inputRoot.EXPECT().Remove(path.MustNewComponent("dev")),
+			inputRoot.EXPECT().Unmount(path.MustNewComponent("proc")),
+			inputRoot.EXPECT().Close(),
+		)
+		directoryA.EXPECT().Close()
+
+		observedResponse, err := mountingRunner.Run(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, response, observedResponse)
+	})
+
+	t.Run("UnmountFailure", func(t *testing.T) {
+		// Failing to unmount file systems after the action
+		// completes should be reported, as it may leave the
+		// input root in an inconsistent state.
+		mountingRunner := runner.NewInputRootMountingRunner(
+			baseRunner,
+			buildDirectory,
+			/* mountProc = */ false,
+			nil,
+			/* devShm = */ nil,
+			/* mountTmp = */ true)
+
+		directoryA := mock.NewMockDirectoryCloser(ctrl)
+		buildDirectory.EXPECT().EnterDirectory(path.MustNewComponent("a")).Return(directoryA, nil)
+		inputRoot := mock.NewMockDirectoryCloser(ctrl)
+		directoryA.EXPECT().EnterDirectory(path.MustNewComponent("root")).Return(inputRoot, nil)
+		inputRoot.EXPECT().Mkdir(path.MustNewComponent("tmp"), os.FileMode(0o755))
+		inputRoot.EXPECT().Mount(path.MustNewComponent("tmp"), "tmpfs", "tmpfs")
+		baseRunner.EXPECT().Run(ctx, testutil.EqProto(t, request)).Return(&runner_pb.RunResponse{}, nil)
+		inputRoot.EXPECT().Unmount(path.MustNewComponent("tmp")).Return(syscall.EBUSY)
+		inputRoot.EXPECT().Close()
+		directoryA.EXPECT().Close()
+
+		_, err := mountingRunner.Run(ctx, request)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to unmount \"tmp\": device or resource busy"), err)
+	})
+
+	t.Run("DevShmSize", func(t *testing.T) {
+		// The size of /dev/shm should be based on the size
+		// class of the action, unless the action requests a
+		// size through a platform property.
+		buildDirectoryPath, scopeWalker := path.EmptyBuilder.Join(path.VoidScopeWalker)
+		require.NoError(t, path.Resolve("/worker/build", scopeWalker))
+		tmpfsMounter := mock.NewMockTmpfsMounter(ctrl)
+		mountingRunner := runner.NewInputRootMountingRunner(
+			baseRunner,
+			buildDirectory,
+			/* mountProc = */ false,
+			nil,
+			&runner.DevShmConfiguration{
+				SizeBytes:             64 * 1024 * 1024,
+				SizeBytesPerSizeClass: map[uint32]uint64{8: 1024 * 1024 * 1024},
+				MaximumSizeBytes:      4 * 1024 * 1024 * 1024,
+				TmpfsMounter:          tmpfsMounter,
+				BuildDirectoryPath:    buildDirectoryPath,
+			},
+			/* mountTmp = */ false)
+
+		for _, tc := range []struct {
+			sizeClass         uint32
+			platformSizeBytes string
+			expectedSizeBytes uint64
+		}{
+			{0, "", 64 * 1024 * 1024},
+			{8, "", 1024 * 1024 * 1024},
+			{8, "2147483648", 2 * 1024 * 1024 * 1024},
+		} {
+			request := &runner_pb.RunRequest{
+				Arguments:          []string{"python3", "train.py"},
+				InputRootDirectory: "a/root",
+				SizeClass:          tc.sizeClass,
+			}
+			if tc.platformSizeBytes != "" {
+				request.Platform = &remoteexecution.Platform{
+					Properties: []*remoteexecution.Platform_Property{
+						{Name: "resources:dev-shm", Value: tc.platformSizeBytes},
+					},
+				}
+			}
+
+			directoryA := mock.NewMockDirectoryCloser(ctrl)
+			buildDirectory.EXPECT().EnterDirectory(path.MustNewComponent("a")).Return(directoryA, nil)
+			inputRoot := mock.NewMockDirectoryCloser(ctrl)
+			directoryA.EXPECT().EnterDirectory(path.MustNewComponent("root")).Return(inputRoot, nil)
+			inputRoot.EXPECT().Mkdir(path.MustNewComponent("dev"), os.FileMode(0o755))
+			inputRoot.EXPECT().Mount(path.MustNewComponent("dev"), "tmpfs", "tmpfs")
+			devDirectory := mock.NewMockDirectoryCloser(ctrl)
+			inputRoot.EXPECT().EnterDirectory(path.MustNewComponent("dev")).Return(devDirectory, nil)
+			devDirectory.EXPECT().Mkdir(path.MustNewComponent("shm"), os.FileMode(0o755))
+			tmpfsMounter.EXPECT().MountTmpfs("/worker/build/a/root/dev/shm", tc.expectedSizeBytes)
+			baseRunner.EXPECT().Run(ctx, testutil.EqProto(t, request)).Return(&runner_pb.RunResponse{}, nil)
+			devDirectory.EXPECT().Unmount(path.MustNewComponent("shm"))
+			devDirectory.EXPECT().Remove(path.MustNewComponent("shm"))
+			devDirectory.EXPECT().Close()
+			inputRoot.EXPECT().Unmount(path.MustNewComponent("dev"))
+			inputRoot.EXPECT().Remove(path.MustNewComponent("dev"))
+			inputRoot.EXPECT().Close()
+			directoryA.EXPECT().Close()
+
+			_, err := mountingRunner.Run(ctx, request)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("DevShmSizeTooLarge", func(t *testing.T) {
+		// Actions should not be permitted to request more
+		// shared memory than configured.
+		mountingRunner := runner.NewInputRootMountingRunner(
+			baseRunner,
+			buildDirectory,
+			/* mountProc = */ false,
+			nil,
+			&runner.DevShmConfiguration{
+				MaximumSizeBytes: 1024 * 1024 * 1024,
+			},
+			/* mountTmp = */ false)
+
+		directoryA := mock.NewMockDirectoryCloser(ctrl)
+		buildDirectory.EXPECT().EnterDirectory(path.MustNewComponent("a")).Return(directoryA, nil)
+		inputRoot := mock.NewMockDirectoryCloser(ctrl)
+		directoryA.EXPECT().EnterDirectory(path.MustNewComponent("root")).Return(inputRoot, nil)
+		inputRoot.EXPECT().Mkdir(path.MustNewComponent("dev"), os.FileMode(0o755))
+		inputRoot.EXPECT().Mount(path.MustNewComponent("dev"), "tmpfs", "tmpfs")
+		devDirectory := mock.NewMockDirectoryCloser(ctrl)
+		inputRoot.EXPECT().EnterDirectory(path.MustNewComponent("dev")).Return(devDirectory, nil)
+		devDirectory.EXPECT().Close()
+		inputRoot.EXPECT().Unmount(path.MustNewComponent("dev"))
+		inputRoot.EXPECT().Remove(path.MustNewComponent("dev"))
+		inputRoot.EXPECT().Close()
+		directoryA.EXPECT().Close()
+
+		_, err := mountingRunner.Run(ctx, &runner_pb.RunRequest{
+			Arguments:          []string{"python3", "train.py"},
+			InputRootDirectory: "a/root",
+			Platform: &remoteexecution.Platform{
+				Properties: []*remoteexecution.Platform_Property{
+					{Name: "resources:dev-shm", Value: "2147483648"},
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Platform property \"resources:dev-shm\" must be between 1 and 1073741824 bytes"), err)
+	})
+}
@@ -0,0 +1,113 @@
+package runner
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/cleaner"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type leakedProcessReportingRunner struct {
+	base                runner_pb.RunnerServer
+	processTable        cleaner.ProcessTable
+	clock               clock.Clock
+	maximumCommandLines int
+}
+
+// NewLeakedProcessReportingRunner creates a decorator for Runner that
+// reports processes that are left behind by commands. After a command
+// completes, the process table is scanned for processes that were
+// created while the command ran. These are attached to the resource
+// usage of the command in the form of a LeakedProcessesResourceUsage
+// message.
+//
+// This decorator should be placed underneath NewCleanRunner(), so that
+// processes are reported before they are killed. The process table
+// should only contain processes that may have been spawned by
+// commands (e.g., ones running as the user ID of commands). When
+// multiple commands are run concurrently, processes belonging to
+// other commands that are still running may be reported as well.
+func NewLeakedProcessReportingRunner(base runner_pb.RunnerServer, processTable cleaner.ProcessTable, clock clock.Clock, maximumCommandLines int) runner_pb.RunnerServer {
+	return &leakedProcessReportingRunner{
+		base:                base,
+		processTable:        processTable,
+		clock:               clock,
+		maximumCommandLines: maximumCommandLines,
+	}
+}
+
+func (r *leakedProcessReportingRunner) Run(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+	startTime := r.clock.Now()
+	response, err := r.base.Run(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	completionTime := r.clock.Now()
+
+	processes, err := r.processTable.GetProcesses()
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to obtain process table")
+	}
+
+	// Group processes that were created while the command ran by
+	// command line.
+	totalProcesses := 0
+	commandLineIndices := map[string]int{}
+	var commandLines []*resourceusage.LeakedProcessesResourceUsage_CommandLine
+	for _, process := range processes {
+		if process.CreationTime.Before(startTime) || process.CreationTime.After(completionTime) {
+			continue
+		}
+		totalProcesses++
+		key := strings.Join(process.CommandLine, "\x00")
+		if i, ok := commandLineIndices[key]; ok {
+			commandLines[i].Processes++
+		} else {
+			commandLineIndices[key] = len(commandLines)
+			commandLines = append(commandLines, &resourceusage.LeakedProcessesResourceUsage_CommandLine{
+				Arguments: process.CommandLine,
+				Processes: 1,
+			})
+		}
+	}
+	if totalProcesses == 0 {
+		return response, nil
+	}
+
+	sort.SliceStable(commandLines, func(i, j int) bool {
+		if commandLines[i].Processes != commandLines[j].Processes {
+			return commandLines[i].Processes > commandLines[j].Processes
+		}
+		return strings.Join(commandLines[i].Arguments, "\x00") < strings.Join(commandLines[j].Arguments, "\x00")
+	})
+	if len(commandLines) > r.maximumCommandLines {
+		commandLines = commandLines[:r.maximumCommandLines]
+	}
+
+	resourceUsage, err := anypb.New(&resourceusage.LeakedProcessesResourceUsage{
+		TotalProcesses: uint32(totalProcesses),
+		CommandLines:   commandLines,
+	})
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to marshal leaked processes resource usage")
+	}
+	response.ResourceUsage = append(response.ResourceUsage, resourceUsage)
+	return response, nil
+}
+
+func (r *leakedProcessReportingRunner) CheckReadiness(ctx context.Context, request *runner_pb.CheckReadinessRequest) (*emptypb.Empty, error) {
+	return r.base.CheckReadiness(ctx, request)
+}
+
+func (r *leakedProcessReportingRunner) GetProcessTree(ctx context.Context, request *runner_pb.GetProcessTreeRequest) (*runner_pb.GetProcessTreeResponse, error) {
+	return r.base.GetProcessTree(ctx, request)
+}
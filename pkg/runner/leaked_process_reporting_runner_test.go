@@ -0,0 +1,98 @@
+package runner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/cleaner"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestLeakedProcessReportingRunner(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseRunner := mock.NewMockRunnerServer(ctrl)
+	processTable := mock.NewMockProcessTable(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	runnerServer := runner.NewLeakedProcessReportingRunner(baseRunner, processTable, clock, 2)
+
+	request := &runner_pb.RunRequest{
+		Arguments:          []string{"go", "test", "./..."},
+		InputRootDirectory: "a/root",
+	}
+
+	t.Run("RunFailure", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		baseRunner.EXPECT().Run(ctx, request).Return(nil, status.Error(codes.InvalidArgument, "Cannot find executable \"go\""))
+
+		_, err := runnerServer.Run(ctx, request)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Cannot find executable \"go\""), err)
+	})
+
+	t.Run("ProcessTableFailure", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		baseRunner.EXPECT().Run(ctx, request).Return(&runner_pb.RunResponse{}, nil)
+		clock.EXPECT().Now().Return(time.Unix(1010, 0))
+		processTable.EXPECT().GetProcesses().Return(nil, status.Error(codes.Internal, "Failed to open /proc"))
+
+		_, err := runnerServer.Run(ctx, request)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to obtain process table: Failed to open /proc"), err)
+	})
+
+	t.Run("NoLeakedProcesses", func(t *testing.T) {
+		// Processes created before the command started and
+		// after it completed should not be reported.
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		baseRunner.EXPECT().Run(ctx, request).Return(&runner_pb.RunResponse{ExitCode: 1}, nil)
+		clock.EXPECT().Now().Return(time.Unix(1010, 0))
+		processTable.EXPECT().GetProcesses().Return([]cleaner.Process{
+			{ProcessID: 10, CreationTime: time.Unix(900, 0), CommandLine: []string{"bb_runner"}},
+			{ProcessID: 30, CreationTime: time.Unix(1020, 0), CommandLine: []string{"cc"}},
+		}, nil)
+
+		response, err := runnerServer.Run(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &runner_pb.RunResponse{ExitCode: 1}, response)
+	})
+
+	t.Run("LeakedProcesses", func(t *testing.T) {
+		// Processes should be grouped by command line, and be
+		// sorted by the number of processes. Only the two most
+		// frequent command lines should be reported.
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		baseRunner.EXPECT().Run(ctx, request).Return(&runner_pb.RunResponse{}, nil)
+		clock.EXPECT().Now().Return(time.Unix(1010, 0))
+		processTable.EXPECT().GetProcesses().Return([]cleaner.Process{
+			{ProcessID: 10, CreationTime: time.Unix(900, 0), CommandLine: []string{"bb_runner"}},
+			{ProcessID: 11, CreationTime: time.Unix(1001, 0), CommandLine: []string{"sleep", "infinity"}},
+			{ProcessID: 12, CreationTime: time.Unix(1002, 0), CommandLine: []string{"redis-server", "--port", "6379"}},
+			{ProcessID: 13, CreationTime: time.Unix(1003, 0)},
+			{ProcessID: 14, CreationTime: time.Unix(1004, 0), CommandLine: []string{"redis-server", "--port", "6379"}},
+		}, nil)
+
+		response, err := runnerServer.Run(ctx, request)
+		require.NoError(t, err)
+		resourceUsage, err := anypb.New(&resourceusage.LeakedProcessesResourceUsage{
+			TotalProcesses: 4,
+			CommandLines: []*resourceusage.LeakedProcessesResourceUsage_CommandLine{
+				{Arguments: []string{"redis-server", "--port", "6379"}, Processes: 2},
+				{Processes: 1},
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &runner_pb.RunResponse{
+			ResourceUsage: []*anypb.Any{resourceUsage},
+		}, response)
+	})
+}
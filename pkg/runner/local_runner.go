@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os/exec"
 	"path/filepath"
+	"sync"
 
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
 	"github.com/buildbarn/bb-storage/pkg/filesystem"
@@ -13,6 +14,7 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -67,6 +69,14 @@ type localRunner struct {
 	buildDirectoryPath           *path.Builder
 	commandCreator               CommandCreator
 	setTmpdirEnvironmentVariable bool
+	cgroupCreator                CgroupCreator
+	processTreeTracer            ProcessTreeTracer
+	schedulingPriorities         schedulingPriorityRanges
+
+	// IDs of the processes of commands that are currently running,
+	// keyed by input root directory.
+	processesLock sync.Mutex
+	processes     map[string]int
 }
 
 func (r *localRunner) openLog(logPath string) (filesystem.FileAppender, error) {
@@ -90,13 +100,26 @@ func (r *localRunner) openLog(logPath string) (filesystem.FileAppender, error) {
 type CommandCreator func(ctx context.Context, arguments []string, inputRootDirectory *path.Builder, workingDirectory, pathVariable string) (*exec.Cmd, error)
 
 // NewLocalRunner returns a Runner capable of running commands on the
-// local system directly.
-func NewLocalRunner(buildDirectory filesystem.Directory, buildDirectoryPath *path.Builder, commandCreator CommandCreator, setTmpdirEnvironmentVariable bool) runner.RunnerServer {
+// local system directly. If a CgroupCreator is provided, every command
+// is run inside its own cgroup. If a ProcessTreeTracer is provided,
+// details on all processes spawned by every command are reported.
+//
+// Scheduling priorities may be provided to adjust the nice value, I/O
+// priority and CPU weight of commands, based on the REv2 execution
+// priority of the action. The nice value and I/O priority are applied
+// right after the command has been started, meaning that processes it
+// spawns before that point retain the scheduling priority of
+// bb_runner.
+func NewLocalRunner(buildDirectory filesystem.Directory, buildDirectoryPath *path.Builder, commandCreator CommandCreator, setTmpdirEnvironmentVariable bool, cgroupCreator CgroupCreator, processTreeTracer ProcessTreeTracer, schedulingPriorities []SchedulingPriorityRange) runner.RunnerServer {
 	return &localRunner{
 		buildDirectory:               buildDirectory,
 		buildDirectoryPath:           buildDirectoryPath,
 		commandCreator:               commandCreator,
 		setTmpdirEnvironmentVariable: setTmpdirEnvironmentVariable,
+		cgroupCreator:                cgroupCreator,
+		processTreeTracer:            processTreeTracer,
+		schedulingPriorities:         newSchedulingPriorityRanges(schedulingPriorities),
+		processes:                    map[string]int{},
 	}
 }
 
@@ -144,12 +167,42 @@ func (r *localRunner) Run(ctx context.Context, request *runner.RunRequest) (*run
 	}
 	cmd.Stderr = stderr
 
+	// Place the subprocess in a cgroup of its own, so that resource
+	// limits may be applied to it.
+	schedulingPriority := r.schedulingPriorities.lookup(request.Priority)
+	var cgroup Cgroup
+	if r.cgroupCreator != nil {
+		cgroup, err = r.cgroupCreator.NewCgroup(request.SizeClass)
+		if err != nil {
+			stdout.Close()
+			stderr.Close()
+			return nil, util.StatusWrap(err, "Failed to create cgroup")
+		}
+		if schedulingPriority != nil && schedulingPriority.CPUWeight != 0 {
+			if err := cgroup.SetCPUWeight(schedulingPriority.CPUWeight); err != nil {
+				cgroup.Close()
+				stdout.Close()
+				stderr.Close()
+				return nil, util.StatusWrap(err, "Failed to set CPU weight of cgroup")
+			}
+		}
+		cgroup.AttachToCommand(cmd)
+	}
+
 	// Start the subprocess. We can already close the output files
 	// while the process is running.
-	err = cmd.Start()
+	var tracedProcessTree TracedProcessTree
+	if r.processTreeTracer != nil {
+		tracedProcessTree, err = r.processTreeTracer.Start(cmd)
+	} else {
+		err = cmd.Start()
+	}
 	stdout.Close()
 	stderr.Close()
 	if err != nil {
+		if cgroup != nil {
+			cgroup.Close()
+		}
 		code := codes.Internal
 		for _, invalidArgumentErr := range invalidArgumentErrs {
 			if errors.Is(err, invalidArgumentErr) {
@@ -160,21 +213,103 @@ func (r *localRunner) Run(ctx context.Context, request *runner.RunRequest) (*run
 		return nil, util.StatusWrapWithCode(err, code, "Failed to start process")
 	}
 
+	// Permit inspecting the processes of the command while it runs.
+	r.processesLock.Lock()
+	r.processes[request.InputRootDirectory] = cmd.Process.Pid
+	r.processesLock.Unlock()
+	defer func() {
+		r.processesLock.Lock()
+		delete(r.processes, request.InputRootDirectory)
+		r.processesLock.Unlock()
+	}()
+
+	// Adjust the scheduling priority of the process. If this fails,
+	// terminate the process, as it would otherwise compete with
+	// actions having a higher priority.
+	var schedulingPriorityErr error
+	if schedulingPriority != nil {
+		if schedulingPriorityErr = setProcessSchedulingPriority(cmd.Process.Pid, schedulingPriority); schedulingPriorityErr != nil {
+			cmd.Process.Kill()
+		}
+	}
+
 	// Wait for execution to complete. Permit non-zero exit codes.
+	// When tracing, the main process can only be waited for after
+	// tracing has completed.
+	var processTreeResourceUsage proto.Message
+	var processTreeErr error
+	if tracedProcessTree != nil {
+		processTreeResourceUsage, processTreeErr = tracedProcessTree.Wait()
+	}
 	if err := cmd.Wait(); err != nil {
 		if _, ok := err.(*exec.ExitError); !ok {
+			if cgroup != nil {
+				cgroup.Close()
+			}
 			return nil, err
 		}
 	}
+	if processTreeErr != nil {
+		if cgroup != nil {
+			cgroup.Close()
+		}
+		return nil, util.StatusWrap(processTreeErr, "Failed to trace process tree")
+	}
+	if schedulingPriorityErr != nil {
+		if cgroup != nil {
+			cgroup.Close()
+		}
+		return nil, util.StatusWrap(schedulingPriorityErr, "Failed to set scheduling priority of process")
+	}
 
-	// Attach rusage information to the response.
-	posixResourceUsage, err := anypb.New(getPOSIXResourceUsage(cmd))
+	// Attach rusage information to the response. wait4() only
+	// accounts for processes that were waited for. If the command
+	// ran in a cgroup, account for all of its processes instead.
+	posixResourceUsage := getPOSIXResourceUsage(cmd)
+	if cgroup != nil {
+		if err := cgroup.UpdatePOSIXResourceUsage(posixResourceUsage); err != nil {
+			cgroup.Close()
+			return nil, util.StatusWrap(err, "Failed to obtain POSIX resource usage of cgroup")
+		}
+	}
+	posixResourceUsageAny, err := anypb.New(posixResourceUsage)
 	if err != nil {
+		if cgroup != nil {
+			cgroup.Close()
+		}
 		return nil, util.StatusWrap(err, "Failed to marshal POSIX resource usage")
 	}
+	resourceUsage := []*anypb.Any{posixResourceUsageAny}
+
+	if cgroup != nil {
+		cgroupResourceUsage, err := cgroup.GetResourceUsage()
+		if err != nil {
+			cgroup.Close()
+			return nil, util.StatusWrap(err, "Failed to obtain resource usage of cgroup")
+		}
+		if err := cgroup.Close(); err != nil {
+			return nil, util.StatusWrap(err, "Failed to close cgroup")
+		}
+		for _, message := range cgroupResourceUsage {
+			cgroupResourceUsageAny, err := anypb.New(message)
+			if err != nil {
+				return nil, util.StatusWrap(err, "Failed to marshal resource usage of cgroup")
+			}
+			resourceUsage = append(resourceUsage, cgroupResourceUsageAny)
+		}
+	}
+
+	if processTreeResourceUsage != nil {
+		processTreeResourceUsageAny, err := anypb.New(processTreeResourceUsage)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to marshal resource usage of process tree")
+		}
+		resourceUsage = append(resourceUsage, processTreeResourceUsageAny)
+	}
+
 	return &runner.RunResponse{
 		ExitCode:      int32(cmd.ProcessState.ExitCode()),
-		ResourceUsage: []*anypb.Any{posixResourceUsage},
+		ResourceUsage: resourceUsage,
 	}, nil
 }
 
@@ -198,3 +333,20 @@ func (r *localRunner) CheckReadiness(ctx context.Context, request *runner.CheckR
 
 	return &emptypb.Empty{}, nil
 }
+
+func (r *localRunner) GetProcessTree(ctx context.Context, request *runner.GetProcessTreeRequest) (*runner.GetProcessTreeResponse, error) {
+	r.processesLock.Lock()
+	processID, ok := r.processes[request.InputRootDirectory]
+	r.processesLock.Unlock()
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "No command is running in input root directory %#v", request.InputRootDirectory)
+	}
+
+	processes, err := listProcessTree(int64(processID))
+	if err != nil {
+		return nil, err
+	}
+	return &runner.GetProcessTreeResponse{
+		Processes: processes,
+	}, nil
+}
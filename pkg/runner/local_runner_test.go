@@ -3,6 +3,7 @@ package runner_test
 import (
 	"context"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -21,13 +22,14 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestLocalRunnerCheckReadiness(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
 	buildDirectory := mock.NewMockDirectory(ctrl)
-	runner := runner.NewLocalRunner(buildDirectory, &path.EmptyBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false)
+	runner := runner.NewLocalRunner(buildDirectory, &path.EmptyBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, nil, nil, nil)
 
 	t.Run("NoPathSpecified", func(t *testing.T) {
 		_, err := runner.CheckReadiness(ctx, &runner_pb.CheckReadinessRequest{})
@@ -114,7 +116,7 @@ func TestLocalRunnerRun(t *testing.T) {
 		// variables should cause the process to be executed in
 		// an empty environment. It should not inherit the
 		// environment of the runner.
-		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false)
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, nil, nil, nil)
 		response, err := runner.Run(context.Background(), &runner_pb.RunRequest{
 			Arguments:          getEnvCommand,
 			StdoutPath:         "EmptyEnvironment/stdout",
@@ -144,7 +146,7 @@ func TestLocalRunnerRun(t *testing.T) {
 		// The environment variables provided in the RunRequest
 		// should be respected. If automatic injection of TMPDIR
 		// is enabled, that variable should also be added.
-		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), true)
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), true, nil, nil, nil)
 		response, err := runner.Run(context.Background(), &runner_pb.RunRequest{
 			Arguments: getEnvCommand,
 			EnvironmentVariables: map[string]string{
@@ -202,7 +204,7 @@ func TestLocalRunnerRun(t *testing.T) {
 
 		// Automatic injection of TMPDIR should have no effect
 		// if the command to be run provides its own TMPDIR.
-		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), true)
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), true, nil, nil, nil)
 		response, err := runner.Run(context.Background(), &runner_pb.RunRequest{
 			Arguments:            getEnvCommand,
 			EnvironmentVariables: envMap,
@@ -246,7 +248,7 @@ func TestLocalRunnerRun(t *testing.T) {
 		} else {
 			exit255Command = []string{"/bin/sh", "-c", "exit 255"}
 		}
-		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false)
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, nil, nil, nil)
 		response, err := runner.Run(context.Background(), &runner_pb.RunRequest{
 			Arguments:          exit255Command,
 			StdoutPath:         "NonZeroExitCode/stdout",
@@ -279,7 +281,7 @@ func TestLocalRunnerRun(t *testing.T) {
 		// If the process terminates due to a signal, the name
 		// of the signal should be set as part of the POSIX
 		// resource usage message.
-		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false)
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, nil, nil, nil)
 		response, err := runner.Run(context.Background(), &runner_pb.RunRequest{
 			Arguments:          []string{"/bin/sh", "-c", "kill -s KILL $$"},
 			StdoutPath:         "SigKill/stdout",
@@ -304,6 +306,223 @@ func TestLocalRunnerRun(t *testing.T) {
 		require.Empty(t, stderr)
 	})
 
+	t.Run("Cgroup", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			return
+		}
+
+		testPath := filepath.Join(buildDirectoryPath, "Cgroup")
+		require.NoError(t, os.Mkdir(testPath, 0o777))
+		require.NoError(t, os.Mkdir(filepath.Join(testPath, "root"), 0o777))
+		require.NoError(t, os.Mkdir(filepath.Join(testPath, "tmp"), 0o777))
+
+		// If a CgroupCreator is provided, the command should be
+		// launched inside a cgroup for the size class provided
+		// in the request. Resource usage statistics gathered by
+		// the cgroup should be returned, and take precedence over
+		// the ones obtained through wait4().
+		cgroupCreator := mock.NewMockCgroupCreator(ctrl)
+		cgroup := mock.NewMockCgroup(ctrl)
+		cgroupCreator.EXPECT().NewCgroup(uint32(4)).Return(cgroup, nil)
+		cgroup.EXPECT().AttachToCommand(gomock.Any())
+		cgroup.EXPECT().UpdatePOSIXResourceUsage(gomock.Any()).DoAndReturn(func(resourceUsage *resourceusage.POSIXResourceUsage) error {
+			resourceUsage.PageReclaims = 1000
+			resourceUsage.PageFaults = 42
+			return nil
+		})
+		cgroup.EXPECT().GetResourceUsage().Return([]proto.Message{
+			&resourceusage.SwapResourceUsage{
+				SwapPeakBytes:          4096,
+				SwapLimitExceededCount: 3,
+			},
+			&resourceusage.PIDsResourceUsage{
+				PidsPeak:               1024,
+				PidsLimitExceededCount: 17,
+			},
+		}, nil)
+		cgroup.EXPECT().Close()
+
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, cgroupCreator, nil, nil)
+		response, err := runner.Run(context.Background(), &runner_pb.RunRequest{
+			Arguments:          []string{"/bin/sh", "-c", "exit 0"},
+			StdoutPath:         "Cgroup/stdout",
+			StderrPath:         "Cgroup/stderr",
+			InputRootDirectory: "Cgroup/root",
+			TemporaryDirectory: "Cgroup/tmp",
+			SizeClass:          4,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(0), response.ExitCode)
+
+		require.Len(t, response.ResourceUsage, 3)
+		var posixResourceUsage resourceusage.POSIXResourceUsage
+		require.NoError(t, response.ResourceUsage[0].UnmarshalTo(&posixResourceUsage))
+		require.Equal(t, int64(1000), posixResourceUsage.PageReclaims)
+		require.Equal(t, int64(42), posixResourceUsage.PageFaults)
+		var swapResourceUsage resourceusage.SwapResourceUsage
+		require.NoError(t, response.ResourceUsage[1].UnmarshalTo(&swapResourceUsage))
+		testutil.RequireEqualProto(t, &resourceusage.SwapResourceUsage{
+			SwapPeakBytes:          4096,
+			SwapLimitExceededCount: 3,
+		}, &swapResourceUsage)
+		var pidsResourceUsage resourceusage.PIDsResourceUsage
+		require.NoError(t, response.ResourceUsage[2].UnmarshalTo(&pidsResourceUsage))
+		testutil.RequireEqualProto(t, &resourceusage.PIDsResourceUsage{
+			PidsPeak:               1024,
+			PidsLimitExceededCount: 17,
+		}, &pidsResourceUsage)
+	})
+
+	t.Run("CgroupCreationFailure", func(t *testing.T) {
+		testPath := filepath.Join(buildDirectoryPath, "CgroupCreationFailure")
+		require.NoError(t, os.Mkdir(testPath, 0o777))
+		require.NoError(t, os.Mkdir(filepath.Join(testPath, "root"), 0o777))
+		require.NoError(t, os.Mkdir(filepath.Join(testPath, "tmp"), 0o777))
+
+		// Failures to create a cgroup should be propagated.
+		cgroupCreator := mock.NewMockCgroupCreator(ctrl)
+		cgroupCreator.EXPECT().NewCgroup(uint32(0)).Return(nil, status.Error(codes.Internal, "Permission denied"))
+
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, cgroupCreator, nil, nil)
+		_, err := runner.Run(context.Background(), &runner_pb.RunRequest{
+			Arguments:          getEnvCommand,
+			StdoutPath:         "CgroupCreationFailure/stdout",
+			StderrPath:         "CgroupCreationFailure/stderr",
+			InputRootDirectory: "CgroupCreationFailure/root",
+			TemporaryDirectory: "CgroupCreationFailure/tmp",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to create cgroup: Permission denied"), err)
+	})
+
+	t.Run("SchedulingPriority", func(t *testing.T) {
+		if runtime.GOOS != "linux" {
+			return
+		}
+
+		testPath := filepath.Join(buildDirectoryPath, "SchedulingPriority")
+		require.NoError(t, os.Mkdir(testPath, 0o777))
+		require.NoError(t, os.Mkdir(filepath.Join(testPath, "root"), 0o777))
+		require.NoError(t, os.Mkdir(filepath.Join(testPath, "tmp"), 0o777))
+
+		// The scheduling priority having the highest minimum
+		// priority that does not exceed the priority of the
+		// request should be applied. The CPU weight should be
+		// written into the cgroup before the command starts.
+		cgroupCreator := mock.NewMockCgroupCreator(ctrl)
+		cgroup := mock.NewMockCgroup(ctrl)
+		cgroupCreator.EXPECT().NewCgroup(uint32(0)).Return(cgroup, nil)
+		cgroup.EXPECT().SetCPUWeight(uint32(20))
+		cgroup.EXPECT().AttachToCommand(gomock.Any())
+		cgroup.EXPECT().UpdatePOSIXResourceUsage(gomock.Any())
+		cgroup.EXPECT().GetResourceUsage()
+		cgroup.EXPECT().Close()
+
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, cgroupCreator, nil, []runner.SchedulingPriorityRange{
+			{
+				MinimumPriority: 1000,
+				SchedulingPriority: runner.SchedulingPriority{
+					Nice:      19,
+					CPUWeight: 1,
+				},
+			},
+			{
+				MinimumPriority: 0,
+				SchedulingPriority: runner.SchedulingPriority{
+					CPUWeight: 100,
+				},
+			},
+			{
+				MinimumPriority: 100,
+				SchedulingPriority: runner.SchedulingPriority{
+					Nice:      10,
+					CPUWeight: 20,
+				},
+			},
+		})
+		response, err := runner.Run(context.Background(), &runner_pb.RunRequest{
+			Arguments:          []string{"/bin/sh", "-c", "exit 0"},
+			StdoutPath:         "SchedulingPriority/stdout",
+			StderrPath:         "SchedulingPriority/stderr",
+			InputRootDirectory: "SchedulingPriority/root",
+			TemporaryDirectory: "SchedulingPriority/tmp",
+			Priority:           500,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(0), response.ExitCode)
+	})
+
+	t.Run("ProcessTreeTracer", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			return
+		}
+
+		testPath := filepath.Join(buildDirectoryPath, "ProcessTreeTracer")
+		require.NoError(t, os.Mkdir(testPath, 0o777))
+		require.NoError(t, os.Mkdir(filepath.Join(testPath, "root"), 0o777))
+		require.NoError(t, os.Mkdir(filepath.Join(testPath, "tmp"), 0o777))
+
+		// If a ProcessTreeTracer is provided, it should be used
+		// to start the command. Details on all processes
+		// gathered by it should be returned.
+		processTreeTracer := mock.NewMockProcessTreeTracer(ctrl)
+		tracedProcessTree := mock.NewMockTracedProcessTree(ctrl)
+		processTreeTracer.EXPECT().Start(gomock.Any()).DoAndReturn(func(cmd *exec.Cmd) (runner.TracedProcessTree, error) {
+			if err := cmd.Start(); err != nil {
+				return nil, err
+			}
+			return tracedProcessTree, nil
+		})
+		tracedProcessTree.EXPECT().Wait().Return(&resourceusage.ProcessTreeResourceUsage{
+			Processes: []*resourceusage.ProcessTreeResourceUsage_Process{
+				{
+					Pid:                    1235,
+					ParentPid:              1234,
+					Executable:             "/usr/bin/cc1",
+					TerminationSignal:      "SEGV",
+					MaximumResidentSetSize: 1048576,
+				},
+				{
+					Pid:                    1234,
+					ParentPid:              1,
+					Executable:             "/bin/sh",
+					MaximumResidentSetSize: 2097152,
+				},
+			},
+		}, nil)
+
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, nil, processTreeTracer, nil)
+		response, err := runner.Run(context.Background(), &runner_pb.RunRequest{
+			Arguments:          []string{"/bin/sh", "-c", "exit 0"},
+			StdoutPath:         "ProcessTreeTracer/stdout",
+			StderrPath:         "ProcessTreeTracer/stderr",
+			InputRootDirectory: "ProcessTreeTracer/root",
+			TemporaryDirectory: "ProcessTreeTracer/tmp",
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(0), response.ExitCode)
+
+		require.Len(t, response.ResourceUsage, 2)
+		var processTreeResourceUsage resourceusage.ProcessTreeResourceUsage
+		require.NoError(t, response.ResourceUsage[1].UnmarshalTo(&processTreeResourceUsage))
+		testutil.RequireEqualProto(t, &resourceusage.ProcessTreeResourceUsage{
+			Processes: []*resourceusage.ProcessTreeResourceUsage_Process{
+				{
+					Pid:                    1235,
+					ParentPid:              1234,
+					Executable:             "/usr/bin/cc1",
+					TerminationSignal:      "SEGV",
+					MaximumResidentSetSize: 1048576,
+				},
+				{
+					Pid:                    1234,
+					ParentPid:              1,
+					Executable:             "/bin/sh",
+					MaximumResidentSetSize: 2097152,
+				},
+			},
+		}, &processTreeResourceUsage)
+	})
+
 	t.Run("UnknownCommandWithEmptyPath", func(t *testing.T) {
 		testPath := filepath.Join(buildDirectoryPath, "UnknownCommandWithEmptyPath")
 		require.NoError(t, os.Mkdir(testPath, 0o777))
@@ -314,7 +533,7 @@ func TestLocalRunnerRun(t *testing.T) {
 		// against $PATH need to be performed. If PATH is not
 		// set, the action should fail with a non-retriable
 		// error.
-		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false)
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, nil, nil, nil)
 		_, err := runner.Run(context.Background(), &runner_pb.RunRequest{
 			Arguments:          []string{"nonexistent_command"},
 			StdoutPath:         "UnknownCommandWithEmptyPath/stdout",
@@ -333,7 +552,7 @@ func TestLocalRunnerRun(t *testing.T) {
 
 		// Even invoking known shell utilities shouldn't be
 		// permitted if PATH points to a nonexistent location.
-		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false)
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, nil, nil, nil)
 		_, err := runner.Run(context.Background(), &runner_pb.RunRequest{
 			Arguments:            []string{"sh", "-c", "exit 123"},
 			EnvironmentVariables: map[string]string{"PATH": "/nonexistent"},
@@ -361,7 +580,7 @@ func TestLocalRunnerRun(t *testing.T) {
 		// working directory. Because the search path is
 		// relative, execve() should be called with a relative
 		// path as well.
-		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false)
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, nil, nil, nil)
 		response, err := runner.Run(context.Background(), &runner_pb.RunRequest{
 			Arguments:            []string{"hello.sh"},
 			EnvironmentVariables: map[string]string{"PATH": "subdirectory"},
@@ -392,7 +611,7 @@ func TestLocalRunnerRun(t *testing.T) {
 		// of multiple components, no $PATH lookup is performed.
 		// If the path does not exist, the action should fail
 		// with a non-retriable error.
-		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false)
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, nil, nil, nil)
 		_, err := runner.Run(context.Background(), &runner_pb.RunRequest{
 			Arguments:          []string{"./nonexistent_command"},
 			StdoutPath:         "UnknownCommandRelative/stdout",
@@ -411,7 +630,7 @@ func TestLocalRunnerRun(t *testing.T) {
 
 		// If argv[0] is an absolute path that does not exist,
 		// we should also return a non-retriable error.
-		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false)
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, nil, nil, nil)
 		_, err := runner.Run(context.Background(), &runner_pb.RunRequest{
 			Arguments:          []string{"/nonexistent_command"},
 			StdoutPath:         "UnknownCommandAbsolute/stdout",
@@ -434,7 +653,7 @@ func TestLocalRunnerRun(t *testing.T) {
 		// If argv[0] is a binary that cannot be executed we
 		// should also return a non-retriable error. In this
 		// case it's a JPEG file.
-		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false)
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, nil, nil, nil)
 		_, err := runner.Run(context.Background(), &runner_pb.RunRequest{
 			Arguments:          []string{"./not_a.binary"},
 			StdoutPath:         "ExecFormatErrorJPEG/stdout",
@@ -491,7 +710,7 @@ func TestLocalRunnerRun(t *testing.T) {
 		//
 		// Test this by attempting to run a tiny Mach-O
 		// executable that uses CPU_TYPE_VAX.
-		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false)
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, nil, nil, nil)
 		_, err := runner.Run(context.Background(), &runner_pb.RunRequest{
 			Arguments:          []string{"./not_a.binary"},
 			StdoutPath:         "ExecFormatErrorMachOBadArch/stdout",
@@ -510,7 +729,7 @@ func TestLocalRunnerRun(t *testing.T) {
 
 		// If argv[0] refers to a directory, we should also
 		// return a non-retriable error.
-		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false)
+		runner := runner.NewLocalRunner(buildDirectory, buildDirectoryPathBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, nil, nil, nil)
 		_, err := runner.Run(context.Background(), &runner_pb.RunRequest{
 			Arguments:          []string{"/"},
 			StdoutPath:         "UnknownCommandDirectory/stdout",
@@ -531,7 +750,7 @@ func TestLocalRunnerRun(t *testing.T) {
 		// privileges. It shouldn't be possible to trick the
 		// runner into opening files outside the build
 		// directory.
-		runner := runner.NewLocalRunner(buildDirectory, &path.EmptyBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false)
+		runner := runner.NewLocalRunner(buildDirectory, &path.EmptyBuilder, runner.NewPlainCommandCreator(&syscall.SysProcAttr{}), false, nil, nil, nil)
 		_, err := runner.Run(context.Background(), &runner_pb.RunRequest{
 			Arguments:          getEnvCommand,
 			StdoutPath:         "hello/../../../../../../etc/passwd",
@@ -0,0 +1,24 @@
+package runner
+
+import (
+	"context"
+	"os"
+)
+
+type networkNamespaceKey struct{}
+
+// NewContextWithNetworkNamespace returns a Context to which a network
+// namespace is attached. Commands created by CommandCreators that are
+// called with this context are run inside this network namespace. This
+// is only supported by NewPIDNamespaceCommandCreator().
+func NewContextWithNetworkNamespace(ctx context.Context, networkNamespace *os.File) context.Context {
+	return context.WithValue(ctx, networkNamespaceKey{}, networkNamespace)
+}
+
+// GetNetworkNamespaceFromContext returns the network namespace that
+// was attached to a Context using NewContextWithNetworkNamespace(). If
+// no network namespace is attached, nil is returned.
+func GetNetworkNamespaceFromContext(ctx context.Context) *os.File {
+	networkNamespace, _ := ctx.Value(networkNamespaceKey{}).(*os.File)
+	return networkNamespace
+}
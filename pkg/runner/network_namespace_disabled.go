@@ -0,0 +1,16 @@
+//go:build !linux
+// +build !linux
+
+package runner
+
+import (
+	"net"
+	"os"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newLoopbackOnlyNetworkNamespace() (*os.File, net.Listener, error) {
+	return nil, nil, status.Error(codes.Unimplemented, "Network namespaces are not supported on this platform")
+}
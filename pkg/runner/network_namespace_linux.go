@@ -0,0 +1,98 @@
+//go:build linux
+// +build linux
+
+package runner
+
+import (
+	"net"
+	"os"
+	"runtime"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+)
+
+// newLoopbackOnlyNetworkNamespace creates a network namespace that
+// only has a loopback interface, and creates a TCP listening socket
+// bound to 127.0.0.1 inside of it.
+//
+// Network namespaces are a property of threads. The namespace is thus
+// created on a dedicated thread that is moved back into the original
+// network namespace afterwards. If that fails, the thread is not
+// unlocked, causing the Go runtime to terminate it.
+func newLoopbackOnlyNetworkNamespace() (*os.File, net.Listener, error) {
+	type result struct {
+		networkNamespace *os.File
+		listener         net.Listener
+		err              error
+	}
+	results := make(chan result, 1)
+	go func() {
+		runtime.LockOSThread()
+		originalNetworkNamespace, err := os.Open("/proc/thread-self/ns/net")
+		if err != nil {
+			runtime.UnlockOSThread()
+			results <- result{err: util.StatusWrapWithCode(err, codes.Internal, "Failed to open original network namespace")}
+			return
+		}
+		defer originalNetworkNamespace.Close()
+
+		networkNamespace, listener, err := enterLoopbackOnlyNetworkNamespace()
+		if err := unix.Setns(int(originalNetworkNamespace.Fd()), unix.CLONE_NEWNET); err != nil {
+			if networkNamespace != nil {
+				networkNamespace.Close()
+				listener.Close()
+			}
+			results <- result{err: util.StatusWrapWithCode(err, codes.Internal, "Failed to return to original network namespace")}
+			return
+		}
+		runtime.UnlockOSThread()
+		results <- result{
+			networkNamespace: networkNamespace,
+			listener:         listener,
+			err:              err,
+		}
+	}()
+	r := <-results
+	return r.networkNamespace, r.listener, r.err
+}
+
+// enterLoopbackOnlyNetworkNamespace moves the current thread into a
+// new network namespace, brings up its loopback interface, and creates
+// a TCP listening socket inside of it.
+func enterLoopbackOnlyNetworkNamespace() (*os.File, net.Listener, error) {
+	if err := unix.Unshare(unix.CLONE_NEWNET); err != nil {
+		return nil, nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to create network namespace")
+	}
+
+	// The loopback interface of a new network namespace is down.
+	s, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to create socket for configuring loopback interface")
+	}
+	defer unix.Close(s)
+	ifreq, err := unix.NewIfreq("lo")
+	if err != nil {
+		return nil, nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to create interface request")
+	}
+	if err := unix.IoctlIfreq(s, unix.SIOCGIFFLAGS, ifreq); err != nil {
+		return nil, nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to get flags of loopback interface")
+	}
+	ifreq.SetUint16(ifreq.Uint16() | unix.IFF_UP)
+	if err := unix.IoctlIfreq(s, unix.SIOCSIFFLAGS, ifreq); err != nil {
+		return nil, nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to bring up loopback interface")
+	}
+
+	networkNamespace, err := os.Open("/proc/thread-self/ns/net")
+	if err != nil {
+		return nil, nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to open network namespace")
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		networkNamespace.Close()
+		return nil, nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to create listening socket")
+	}
+	return networkNamespace, listener, nil
+}
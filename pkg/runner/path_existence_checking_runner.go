@@ -56,3 +56,7 @@ func (r *pathExistenceCheckingRunner) Run(ctx context.Context, request *runner_p
 	}
 	return response, nil
 }
+
+func (r *pathExistenceCheckingRunner) GetProcessTree(ctx context.Context, request *runner_pb.GetProcessTreeRequest) (*runner_pb.GetProcessTreeResponse, error) {
+	return r.base.GetProcessTree(ctx, request)
+}
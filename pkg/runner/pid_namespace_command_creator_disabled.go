@@ -0,0 +1,31 @@
+//go:build !linux
+// +build !linux
+
+package runner
+
+import (
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PIDNamespaceInitArgv0 is the value of argv[0] that is used when
+// bb_runner re-executes itself to act as PID 1 of a PID namespace.
+const PIDNamespaceInitArgv0 = "bb_runner_pid_namespace_init"
+
+// NewPIDNamespaceCommandCreator creates a decorator for CommandCreator
+// that causes commands to be run in a PID namespace of their own. On
+// this operating system this functionality is not available.
+func NewPIDNamespaceCommandCreator(base CommandCreator) (CommandCreator, error) {
+	return nil, status.Error(codes.Unimplemented, "PID namespaces are not supported on this platform")
+}
+
+// RunPIDNamespaceInit is the entry point of the init process that is
+// launched by commands created by NewPIDNamespaceCommandCreator(). On
+// this operating system this functionality is not available.
+func RunPIDNamespaceInit(rawConfiguration string) {
+	fmt.Fprintln(os.Stderr, "PID namespaces are not supported on this platform")
+	os.Exit(127)
+}
@@ -0,0 +1,195 @@
+//go:build linux
+// +build linux
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"syscall"
+
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sys/unix"
+)
+
+// PIDNamespaceInitArgv0 is the value of argv[0] that is used when
+// bb_runner re-executes itself to act as PID 1 of a PID namespace.
+// Programs that make use of NewPIDNamespaceCommandCreator() must call
+// RunPIDNamespaceInit() as early as possible when invoked with this
+// value.
+const PIDNamespaceInitArgv0 = "bb_runner_pid_namespace_init"
+
+// pidNamespaceInitConfiguration contains the properties of the command
+// that is launched by RunPIDNamespaceInit(). It is passed on as a
+// command line argument in JSON form.
+type pidNamespaceInitConfiguration struct {
+	Path       string
+	Args       []string
+	Dir        string
+	Chroot     string
+	Credential *syscall.Credential
+
+	// The number of files at file descriptor 3 and higher that
+	// should be passed on to the command.
+	ExtraFiles int
+	// If non-zero, the file descriptor of a network namespace that
+	// the command should be run in.
+	NetworkNamespaceFD int
+}
+
+// NewPIDNamespaceCommandCreator creates a decorator for CommandCreator
+// that causes commands to be run in a PID namespace of their own. When
+// the command terminates, the kernel kills all processes that remain
+// in the namespace. This ensures that processes that daemonize don't
+// outlive the action that spawned them.
+//
+// Instead of running the command as PID 1 directly, bb_runner
+// re-executes itself to act as a minimal init process. Unlike arbitrary
+// commands, it reaps processes that are reparented to it and forwards
+// signals to the command, which would otherwise not have any default
+// signal handlers installed.
+//
+// If a network namespace is attached to the Context using
+// NewContextWithNetworkNamespace(), the init process launches the
+// command inside of it.
+func NewPIDNamespaceCommandCreator(base CommandCreator) (CommandCreator, error) {
+	return func(ctx context.Context, arguments []string, inputRootDirectory *path.Builder, workingDirectory, pathVariable string) (*exec.Cmd, error) {
+		cmd, err := base(ctx, arguments, inputRootDirectory, workingDirectory, pathVariable)
+		if err != nil {
+			return nil, err
+		}
+
+		// The SysProcAttr may be shared by many commands, so we
+		// must not modify it in place.
+		var sysProcAttr syscall.SysProcAttr
+		if cmd.SysProcAttr != nil {
+			sysProcAttr = *cmd.SysProcAttr
+		}
+
+		// Let the init process apply chroot() and credentials, so
+		// that it can still access its own executable, and it
+		// retains the privileges to forward signals.
+		configuration := pidNamespaceInitConfiguration{
+			Path:       cmd.Path,
+			Args:       cmd.Args,
+			Dir:        cmd.Dir,
+			Chroot:     sysProcAttr.Chroot,
+			Credential: sysProcAttr.Credential,
+			ExtraFiles: len(cmd.ExtraFiles),
+		}
+		if networkNamespace := GetNetworkNamespaceFromContext(ctx); networkNamespace != nil {
+			configuration.NetworkNamespaceFD = 3 + len(cmd.ExtraFiles)
+			cmd.ExtraFiles = append(cmd.ExtraFiles, networkNamespace)
+		}
+		marshaledConfiguration, err := json.Marshal(&configuration)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to marshal init process configuration")
+		}
+		sysProcAttr.Chroot = ""
+		sysProcAttr.Credential = nil
+		sysProcAttr.Cloneflags |= syscall.CLONE_NEWPID
+		cmd.SysProcAttr = &sysProcAttr
+
+		cmd.Path = "/proc/self/exe"
+		cmd.Args = []string{PIDNamespaceInitArgv0, string(marshaledConfiguration)}
+		cmd.Dir = ""
+		return cmd, nil
+	}, nil
+}
+
+func exitPIDNamespaceInit(format string, a ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", a...)
+	os.Exit(127)
+}
+
+// RunPIDNamespaceInit is the entry point of the init process that is
+// launched by commands created by NewPIDNamespaceCommandCreator(). It
+// launches the command in a process group of its own, forwards all
+// signals to it and reaps all processes in the PID namespace. When the
+// command terminates, the init process terminates with the same exit
+// code. If the command is terminated by a signal, the exit code is 128
+// plus the signal number. This function never returns.
+//
+// If a network namespace is provided, the command is launched inside of
+// it. As network namespaces are a property of threads, the init process
+// joins it from the thread that launches the command.
+func RunPIDNamespaceInit(rawConfiguration string) {
+	var configuration pidNamespaceInitConfiguration
+	if err := json.Unmarshal([]byte(rawConfiguration), &configuration); err != nil {
+		exitPIDNamespaceInit("Failed to unmarshal init process configuration: %s", err)
+	}
+
+	// Install signal handlers before launching the command, so
+	// that no signals or terminations of processes get lost.
+	signals := make(chan os.Signal, 32)
+	signal.Notify(signals)
+
+	files := []*os.File{os.Stdin, os.Stdout, os.Stderr}
+	for i := 0; i < configuration.ExtraFiles; i++ {
+		files = append(files, os.NewFile(uintptr(3+i), "extra file"))
+	}
+	if fd := configuration.NetworkNamespaceFD; fd != 0 {
+		runtime.LockOSThread()
+		err := unix.Setns(fd, unix.CLONE_NEWNET)
+		unix.Close(fd)
+		if err != nil {
+			exitPIDNamespaceInit("Failed to join network namespace: %s", err)
+		}
+	}
+
+	process, err := os.StartProcess(configuration.Path, configuration.Args, &os.ProcAttr{
+		Dir:   configuration.Dir,
+		Env:   os.Environ(),
+		Files: files,
+		Sys: &syscall.SysProcAttr{
+			Chroot:     configuration.Chroot,
+			Credential: configuration.Credential,
+			Setpgid:    true,
+		},
+	})
+	if err != nil {
+		exitPIDNamespaceInit("Failed to start process: %s", err)
+	}
+	pid := process.Pid
+
+	for s := range signals {
+		switch s {
+		case syscall.SIGCHLD:
+			// Multiple terminations may be coalesced into a
+			// single SIGCHLD, so reap until none are left.
+			for {
+				var waitStatus syscall.WaitStatus
+				reapedPID, err := syscall.Wait4(-1, &waitStatus, syscall.WNOHANG, nil)
+				if err == syscall.EINTR {
+					continue
+				}
+				if err != nil || reapedPID <= 0 {
+					break
+				}
+				if reapedPID == pid {
+					if waitStatus.Signaled() {
+						os.Exit(128 + int(waitStatus.Signal()))
+					}
+					os.Exit(waitStatus.ExitStatus())
+				}
+			}
+		case syscall.SIGURG:
+			// Used by the Go runtime for preempting
+			// goroutines. Not meant for the command.
+		default:
+			// Forward the signal to the process group of
+			// the command, falling back to the command
+			// itself if it moved to another process group.
+			if err := syscall.Kill(-pid, s.(syscall.Signal)); err == syscall.ESRCH {
+				syscall.Kill(pid, s.(syscall.Signal))
+			}
+		}
+	}
+}
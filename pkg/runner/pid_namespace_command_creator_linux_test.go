@@ -0,0 +1,179 @@
+//go:build linux
+// +build linux
+
+package runner_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMain(m *testing.M) {
+	// Commands created by NewPIDNamespaceCommandCreator() re-execute
+	// the current executable to act as init process.
+	if len(os.Args) == 2 && os.Args[0] == runner.PIDNamespaceInitArgv0 {
+		runner.RunPIDNamespaceInit(os.Args[1])
+	}
+	os.Exit(m.Run())
+}
+
+func TestPIDNamespaceCommandCreator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("BaseFailure", func(t *testing.T) {
+		commandCreator, err := runner.NewPIDNamespaceCommandCreator(
+			func(ctx context.Context, arguments []string, inputRootDirectory *path.Builder, workingDirectory, pathVariable string) (*exec.Cmd, error) {
+				return nil, status.Error(codes.InvalidArgument, "Cannot find executable \"cc\"")
+			})
+		require.NoError(t, err)
+
+		_, err = commandCreator(ctx, []string{"cc"}, &path.EmptyBuilder, "", "/usr/bin")
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Cannot find executable \"cc\""), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The SysProcAttr of the base CommandCreator should be
+		// left untouched, as it may be shared by many commands.
+		// Credentials and chroot() should be applied by the init
+		// process, as opposed to being applied to the init process.
+		sysProcAttr := &syscall.SysProcAttr{
+			Setpgid:    true,
+			Credential: &syscall.Credential{Uid: 1000, Gid: 1000},
+		}
+		commandCreator, err := runner.NewPIDNamespaceCommandCreator(
+			runner.NewPlainCommandCreator(sysProcAttr))
+		require.NoError(t, err)
+
+		cmd, err := commandCreator(ctx, []string{"/bin/true"}, &path.RootBuilder, "", "")
+		require.NoError(t, err)
+		require.Equal(t, "/proc/self/exe", cmd.Path)
+		require.Equal(t, []string{
+			runner.PIDNamespaceInitArgv0,
+			`{"Path":"/bin/true","Args":["/bin/true"],"Dir":"/","Chroot":"","Credential":{"Uid":1000,"Gid":1000,"Groups":null,"NoSetGroups":false},"ExtraFiles":0,"NetworkNamespaceFD":0}`,
+		}, cmd.Args)
+		require.Equal(t, "", cmd.Dir)
+		require.Equal(t, &syscall.SysProcAttr{
+			Setpgid:    true,
+			Cloneflags: syscall.CLONE_NEWPID,
+		}, cmd.SysProcAttr)
+		require.Equal(t, &syscall.SysProcAttr{
+			Setpgid:    true,
+			Credential: &syscall.Credential{Uid: 1000, Gid: 1000},
+		}, sysProcAttr)
+	})
+
+	commandCreator, err := runner.NewPIDNamespaceCommandCreator(
+		runner.NewPlainCommandCreator(&syscall.SysProcAttr{}))
+	require.NoError(t, err)
+	createCommand := func(t *testing.T, script string) *exec.Cmd {
+		if os.Geteuid() != 0 {
+			t.Skip("Creating PID namespaces requires root privileges")
+		}
+		cmd, err := commandCreator(ctx, []string{"/bin/sh", "-c", script}, &path.RootBuilder, "", "")
+		require.NoError(t, err)
+		return cmd
+	}
+
+	t.Run("ExitCode", func(t *testing.T) {
+		// The exit code of the command should be propagated. The
+		// command should not run as PID 1.
+		cmd := createCommand(t, "test $$ -ne 1 && exit 42")
+		require.Error(t, cmd.Run())
+		require.Equal(t, 42, cmd.ProcessState.ExitCode())
+	})
+
+	t.Run("TerminatedBySignal", func(t *testing.T) {
+		cmd := createCommand(t, "kill -KILL $$")
+		require.Error(t, cmd.Run())
+		require.Equal(t, 128+int(syscall.SIGKILL), cmd.ProcessState.ExitCode())
+	})
+
+	t.Run("StartFailure", func(t *testing.T) {
+		if os.Geteuid() != 0 {
+			t.Skip("Creating PID namespaces requires root privileges")
+		}
+		cmd, err := commandCreator(ctx, []string{"/nonexistent"}, &path.RootBuilder, "", "")
+		require.NoError(t, err)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		require.Error(t, cmd.Run())
+		require.Equal(t, 127, cmd.ProcessState.ExitCode())
+		require.Equal(t, "Failed to start process: fork/exec /nonexistent: no such file or directory\n", stderr.String())
+	})
+
+	t.Run("SignalForwarding", func(t *testing.T) {
+		// Signals delivered to the init process should be
+		// forwarded to the command, which should be able to
+		// handle them.
+		readyPath := filepath.Join(t.TempDir(), "ready")
+		cmd := createCommand(t, "trap 'exit 5' TERM; touch "+readyPath+"; while :; do sleep 0.01; done")
+		require.NoError(t, cmd.Start())
+		require.Eventually(t, func() bool {
+			_, err := os.Stat(readyPath)
+			return err == nil
+		}, 10*time.Second, 10*time.Millisecond)
+		require.NoError(t, cmd.Process.Signal(syscall.SIGTERM))
+		require.Error(t, cmd.Wait())
+		require.Equal(t, 5, cmd.ProcessState.ExitCode())
+	})
+
+	t.Run("ZombieReaping", func(t *testing.T) {
+		// Processes that are orphaned are reparented to the init
+		// process, which should reap them once they terminate.
+		// As signal 0 can be sent to zombies, the command below
+		// only terminates if the orphan gets reaped.
+		pidPath := filepath.Join(t.TempDir(), "pid")
+		cmd := createCommand(t, "(sh -c 'echo $$ > "+pidPath+"' &); while [ ! -s "+pidPath+" ]; do sleep 0.01; done; while kill -0 $(cat "+pidPath+") 2> /dev/null; do sleep 0.01; done")
+		require.NoError(t, cmd.Start())
+		timer := time.AfterFunc(10*time.Second, func() { cmd.Process.Kill() })
+		defer timer.Stop()
+		require.NoError(t, cmd.Wait())
+	})
+
+	t.Run("NetworkNamespace", func(t *testing.T) {
+		// If a network namespace is attached to the Context, the
+		// init process should launch the command inside of it.
+		if os.Geteuid() != 0 {
+			t.Skip("Creating PID namespaces requires root privileges")
+		}
+		networkNamespaceHolder := exec.Command("/bin/sleep", "60")
+		networkNamespaceHolder.SysProcAttr = &syscall.SysProcAttr{Cloneflags: syscall.CLONE_NEWNET}
+		require.NoError(t, networkNamespaceHolder.Start())
+		defer func() {
+			networkNamespaceHolder.Process.Kill()
+			networkNamespaceHolder.Wait()
+		}()
+		networkNamespacePath := fmt.Sprintf("/proc/%d/ns/net", networkNamespaceHolder.Process.Pid)
+		networkNamespace, err := os.Open(networkNamespacePath)
+		require.NoError(t, err)
+		defer networkNamespace.Close()
+		expectedNetworkNamespace, err := os.Readlink(networkNamespacePath)
+		require.NoError(t, err)
+
+		cmd, err := commandCreator(
+			runner.NewContextWithNetworkNamespace(ctx, networkNamespace),
+			[]string{"/bin/readlink", "/proc/self/ns/net"},
+			&path.RootBuilder,
+			"",
+			"")
+		require.NoError(t, err)
+		output, err := cmd.Output()
+		require.NoError(t, err)
+		require.Equal(t, expectedNetworkNamespace+"\n", string(output))
+	})
+}
@@ -0,0 +1,281 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// PinnedToolchainPlatformPropertyPrefix is the prefix of the names of
+// platform properties that may be used by actions to request that a
+// pinned toolchain is mounted inside the input root. The remainder of
+// the name is the path at which the toolchain is mounted. The value is
+// the digest of the toolchain's root Directory message, having the
+// form "${hash}-${size_bytes}".
+const PinnedToolchainPlatformPropertyPrefix = "pinned-toolchain:"
+
+// pinnedToolchainDirectory refers to a directory inside the input root
+// that was created or mounted by pinnedToolchainMountingRunner.
+type pinnedToolchainDirectory struct {
+	parent filesystem.Directory
+	name   path.Component
+}
+
+// pinnedToolchainMounts keeps track of all toolchains that were
+// mounted inside the input root of a single action, and the
+// mountpoints that needed to be created for them.
+type pinnedToolchainMounts struct {
+	openedDirectories  []filesystem.DirectoryCloser
+	createdDirectories []pinnedToolchainDirectory
+	mounts             []pinnedToolchainDirectory
+}
+
+// unmountAll unmounts all toolchains in reverse order. Mountpoints
+// that were created are removed afterwards, so that the input root is
+// restored to its original state.
+func (m *pinnedToolchainMounts) unmountAll() error {
+	var firstErr error
+	unmountedAll := true
+	for i := len(m.mounts) - 1; i >= 0; i-- {
+		mount := m.mounts[i]
+		if err := mount.parent.Unmount(mount.name); err != nil {
+			unmountedAll = false
+			if firstErr == nil {
+				firstErr = util.StatusWrapfWithCode(err, codes.Internal, "Failed to unmount %#v", mount.name.String())
+			}
+		}
+	}
+	m.mounts = nil
+
+	if unmountedAll {
+		for i := len(m.createdDirectories) - 1; i >= 0; i-- {
+			directory := m.createdDirectories[i]
+			if err := directory.parent.Remove(directory.name); err != nil && firstErr == nil {
+				firstErr = util.StatusWrapfWithCode(err, codes.Internal, "Failed to remove mountpoint %#v", directory.name.String())
+			}
+		}
+	}
+	m.createdDirectories = nil
+
+	for i := len(m.openedDirectories) - 1; i >= 0; i-- {
+		if err := m.openedDirectories[i].Close(); err != nil && firstErr == nil {
+			firstErr = util.StatusWrapWithCode(err, codes.Internal, "Failed to close directory")
+		}
+	}
+	m.openedDirectories = nil
+	return firstErr
+}
+
+// pinnedToolchain is a toolchain that is requested by an action
+// through platform properties.
+type pinnedToolchain struct {
+	mountpoint []path.Component
+	name       path.Component
+}
+
+type pinnedToolchainMountingRunner struct {
+	base                    runner_pb.RunnerServer
+	buildDirectory          filesystem.Directory
+	buildDirectoryPath      *path.Builder
+	bindMounter             BindMounter
+	toolchainsDirectory     filesystem.Directory
+	toolchainsDirectoryPath *path.Builder
+}
+
+// NewPinnedToolchainMountingRunner creates a decorator for Runner that
+// bind mounts toolchains into the input root of an action, at paths
+// requested through platform properties. Toolchains are read from a
+// directory that is maintained by bb_worker, containing a fixed set
+// of toolchains that are each named after the digest of their root
+// Directory message.
+//
+// This prevents large toolchains from needing to be part of the input
+// root of every action, while keeping actions content addressed. The
+// toolchains are mounted read-only, so that actions cannot tamper
+// with them.
+func NewPinnedToolchainMountingRunner(base runner_pb.RunnerServer, buildDirectory filesystem.Directory, buildDirectoryPath *path.Builder, bindMounter BindMounter, toolchainsDirectory filesystem.Directory, toolchainsDirectoryPath *path.Builder) runner_pb.RunnerServer {
+	return &pinnedToolchainMountingRunner{
+		base:                    base,
+		buildDirectory:          buildDirectory,
+		buildDirectoryPath:      buildDirectoryPath,
+		bindMounter:             bindMounter,
+		toolchainsDirectory:     toolchainsDirectory,
+		toolchainsDirectoryPath: toolchainsDirectoryPath,
+	}
+}
+
+// getPinnedToolchains returns the toolchains that are requested by an
+// action, sorted by mountpoint.
+func (r *pinnedToolchainMountingRunner) getPinnedToolchains(request *runner_pb.RunRequest) ([]pinnedToolchain, error) {
+	var toolchains []pinnedToolchain
+	for _, property := range request.Platform.GetProperties() {
+		mountpointStr, ok := strings.CutPrefix(property.Name, PinnedToolchainPlatformPropertyPrefix)
+		if !ok {
+			continue
+		}
+		mountpointPath, scopeWalker := path.RootBuilder.Join(path.VoidScopeWalker)
+		if err := path.Resolve(mountpointStr, scopeWalker); err != nil {
+			return nil, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid mountpoint for platform property %#v", property.Name)
+		}
+		var mountpoint []path.Component
+		for _, component := range strings.Split(strings.TrimPrefix(mountpointPath.String(), "/"), "/") {
+			if component != "" {
+				mountpoint = append(mountpoint, path.MustNewComponent(component))
+			}
+		}
+		if len(mountpoint) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "Platform property %#v cannot mount a toolchain at the root directory", property.Name)
+		}
+
+		name, ok := path.NewComponent(property.Value)
+		if !ok || strings.HasPrefix(property.Value, ".") {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid toolchain %#v for platform property %#v", property.Value, property.Name)
+		}
+		if fileInfo, err := r.toolchainsDirectory.Lstat(name); err != nil {
+			if os.IsNotExist(err) {
+				return nil, status.Errorf(codes.FailedPrecondition, "Toolchain %#v is not pinned by this worker", property.Value)
+			}
+			return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to obtain file information for toolchain %#v", property.Value)
+		} else if fileInfo.Type() != filesystem.FileTypeDirectory {
+			return nil, status.Errorf(codes.FailedPrecondition, "Toolchain %#v is not a directory", property.Value)
+		}
+		toolchains = append(toolchains, pinnedToolchain{
+			mountpoint: mountpoint,
+			name:       name,
+		})
+	}
+	sort.Slice(toolchains, func(i, j int) bool {
+		a, b := toolchains[i].mountpoint, toolchains[j].mountpoint
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k].String() < b[k].String()
+			}
+		}
+		return len(a) < len(b)
+	})
+	return toolchains, nil
+}
+
+// enterOrCreateDirectory enters a directory inside the input root,
+// creating it if it does not exist. Symbolic links are not followed,
+// as they may point to locations outside the input root.
+func (m *pinnedToolchainMounts) enterOrCreateDirectory(parent filesystem.Directory, name path.Component) (filesystem.Directory, error) {
+	if err := parent.Mkdir(name, 0o755); err == nil {
+		m.createdDirectories = append(m.createdDirectories, pinnedToolchainDirectory{
+			parent: parent,
+			name:   name,
+		})
+	} else if os.IsExist(err) {
+		fileInfo, err := parent.Lstat(name)
+		if err != nil {
+			return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to obtain file information for %#v", name.String())
+		}
+		if fileInfo.Type() != filesystem.FileTypeDirectory {
+			return nil, status.Errorf(codes.InvalidArgument, "%#v in the input root is not a directory", name.String())
+		}
+	} else {
+		return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to create directory %#v", name.String())
+	}
+
+	directory, err := parent.EnterDirectory(name)
+	if err != nil {
+		return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to enter directory %#v", name.String())
+	}
+	m.openedDirectories = append(m.openedDirectories, directory)
+	return directory, nil
+}
+
+func (r *pinnedToolchainMountingRunner) mountAll(inputRoot filesystem.Directory, inputRootPath *path.Builder, toolchains []pinnedToolchain, m *pinnedToolchainMounts) error {
+	for _, toolchain := range toolchains {
+		parent := inputRoot
+		components := make([]string, 0, len(toolchain.mountpoint))
+		for _, component := range toolchain.mountpoint {
+			directory, err := m.enterOrCreateDirectory(parent, component)
+			if err != nil {
+				return err
+			}
+			if len(components) < len(toolchain.mountpoint)-1 {
+				parent = directory
+			}
+			components = append(components, component.String())
+		}
+
+		sourcePath, scopeWalker := r.toolchainsDirectoryPath.Join(path.VoidScopeWalker)
+		if err := path.Resolve(toolchain.name.String(), scopeWalker); err != nil {
+			panic("Failed to resolve path consisting of valid components: " + err.Error())
+		}
+		targetPath, scopeWalker := inputRootPath.Join(path.VoidScopeWalker)
+		if err := path.Resolve(strings.Join(components, "/"), scopeWalker); err != nil {
+			panic("Failed to resolve path consisting of valid components: " + err.Error())
+		}
+		if err := r.bindMounter.BindMountReadOnly(sourcePath.String(), targetPath.String()); err != nil {
+			return err
+		}
+		m.mounts = append(m.mounts, pinnedToolchainDirectory{
+			parent: parent,
+			name:   toolchain.mountpoint[len(toolchain.mountpoint)-1],
+		})
+	}
+	return nil
+}
+
+func (r *pinnedToolchainMountingRunner) Run(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+	toolchains, err := r.getPinnedToolchains(request)
+	if err != nil {
+		return nil, err
+	}
+	if len(toolchains) == 0 {
+		return r.base.Run(ctx, request)
+	}
+
+	// Open the input root directory, and compute its absolute path
+	// for creating bind mounts.
+	inputRootResolver := buildDirectoryPathResolver{
+		stack: util.NewNonEmptyStack(filesystem.NopDirectoryCloser(r.buildDirectory)),
+	}
+	defer inputRootResolver.closeAll()
+	if err := path.Resolve(request.InputRootDirectory, path.NewRelativeScopeWalker(&inputRootResolver)); err != nil {
+		return nil, util.StatusWrap(err, "Failed to resolve input root directory")
+	}
+	if name := inputRootResolver.TerminalName; name != nil {
+		inputRoot, err := inputRootResolver.stack.Peek().EnterDirectory(*name)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to enter input root directory")
+		}
+		inputRootResolver.stack.Push(inputRoot)
+	}
+	inputRootPath, scopeWalker := r.buildDirectoryPath.Join(path.VoidScopeWalker)
+	if err := path.Resolve(request.InputRootDirectory, scopeWalker); err != nil {
+		return nil, util.StatusWrap(err, "Failed to resolve input root directory")
+	}
+
+	var m pinnedToolchainMounts
+	if err := r.mountAll(inputRootResolver.stack.Peek(), inputRootPath, toolchains, &m); err != nil {
+		m.unmountAll()
+		return nil, util.StatusWrap(err, "Failed to mount pinned toolchains")
+	}
+
+	response, err := r.base.Run(ctx, request)
+	if unmountErr := m.unmountAll(); unmountErr != nil && err == nil {
+		return nil, util.StatusWrap(unmountErr, "Failed to unmount pinned toolchains")
+	}
+	return response, err
+}
+
+func (r *pinnedToolchainMountingRunner) CheckReadiness(ctx context.Context, request *runner_pb.CheckReadinessRequest) (*emptypb.Empty, error) {
+	return r.base.CheckReadiness(ctx, request)
+}
+
+func (r *pinnedToolchainMountingRunner) GetProcessTree(ctx context.Context, request *runner_pb.GetProcessTreeRequest) (*runner_pb.GetProcessTreeResponse, error) {
+	return r.base.GetProcessTree(ctx, request)
+}
@@ -0,0 +1,152 @@
+package runner_test
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPinnedToolchainMountingRunner(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	buildDirectory := mock.NewMockDirectory(ctrl)
+	buildDirectoryPath, scopeWalker := path.EmptyBuilder.Join(path.VoidScopeWalker)
+	require.NoError(t, path.Resolve("/worker/build", scopeWalker))
+	bindMounter := mock.NewMockBindMounter(ctrl)
+	toolchainsDirectory := mock.NewMockDirectory(ctrl)
+	toolchainsDirectoryPath, scopeWalker := path.EmptyBuilder.Join(path.VoidScopeWalker)
+	require.NoError(t, path.Resolve("/worker/toolchains", scopeWalker))
+	baseRunner := mock.NewMockRunnerServer(ctrl)
+	mountingRunner := runner.NewPinnedToolchainMountingRunner(
+		baseRunner,
+		buildDirectory,
+		buildDirectoryPath,
+		bindMounter,
+		toolchainsDirectory,
+		toolchainsDirectoryPath)
+
+	newRequest := func(properties ...*remoteexecution.Platform_Property) *runner_pb.RunRequest {
+		return &runner_pb.RunRequest{
+			Arguments:          []string{"cc", "hello.c"},
+			WorkingDirectory:   "a/root",
+			StdoutPath:         "a/stdout",
+			StderrPath:         "a/stderr",
+			InputRootDirectory: "a/root",
+			TemporaryDirectory: "a/tmp",
+			Platform: &remoteexecution.Platform{
+				Properties: properties,
+			},
+		}
+	}
+
+	t.Run("NoToolchains", func(t *testing.T) {
+		// Actions that don't request any toolchains should be
+		// forwarded without touching the input root.
+		request := newRequest(&remoteexecution.Platform_Property{Name: "OSFamily", Value: "linux"})
+		baseRunner.EXPECT().Run(ctx, request).Return(&runner_pb.RunResponse{ExitCode: 0}, nil)
+
+		response, err := mountingRunner.Run(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &runner_pb.RunResponse{ExitCode: 0}, response)
+	})
+
+	t.Run("InvalidToolchain", func(t *testing.T) {
+		// Toolchain names are not permitted to refer to
+		// anything outside the toolchains directory.
+		_, err := mountingRunner.Run(ctx, newRequest(&remoteexecution.Platform_Property{
+			Name:  "pinned-toolchain:opt/toolchain",
+			Value: "..",
+		}))
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid toolchain \"..\" for platform property \"pinned-toolchain:opt/toolchain\""), err)
+	})
+
+	t.Run("ToolchainNotPinned", func(t *testing.T) {
+		// Toolchains that are not present on the worker cannot
+		// be mounted.
+		toolchainsDirectory.EXPECT().Lstat(path.MustNewComponent("8b1a9953c4611296a827abf8c47804d7-123")).
+			Return(filesystem.FileInfo{}, syscall.ENOENT)
+
+		_, err := mountingRunner.Run(ctx, newRequest(&remoteexecution.Platform_Property{
+			Name:  "pinned-toolchain:opt/toolchain",
+			Value: "8b1a9953c4611296a827abf8c47804d7-123",
+		}))
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Toolchain \"8b1a9953c4611296a827abf8c47804d7-123\" is not pinned by this worker"), err)
+	})
+
+	t.Run("MountFailure", func(t *testing.T) {
+		// Failures to create bind mounts should cause the
+		// action to fail. Directories that were created should
+		// be removed.
+		toolchainsDirectory.EXPECT().Lstat(path.MustNewComponent("8b1a9953c4611296a827abf8c47804d7-123")).
+			Return(filesystem.NewFileInfo(path.MustNewComponent("8b1a9953c4611296a827abf8c47804d7-123"), filesystem.FileTypeDirectory, false), nil)
+		directoryA := mock.NewMockDirectoryCloser(ctrl)
+		buildDirectory.EXPECT().EnterDirectory(path.MustNewComponent("a")).Return(directoryA, nil)
+		inputRoot := mock.NewMockDirectoryCloser(ctrl)
+		directoryA.EXPECT().EnterDirectory(path.MustNewComponent("root")).Return(inputRoot, nil)
+		inputRoot.EXPECT().Mkdir(path.MustNewComponent("opt"), os.FileMode(0o755))
+		optDirectory := mock.NewMockDirectoryCloser(ctrl)
+		inputRoot.EXPECT().EnterDirectory(path.MustNewComponent("opt")).Return(optDirectory, nil)
+		optDirectory.EXPECT().Mkdir(path.MustNewComponent("toolchain"), os.FileMode(0o755))
+		toolchainDirectory := mock.NewMockDirectoryCloser(ctrl)
+		optDirectory.EXPECT().EnterDirectory(path.MustNewComponent("toolchain")).Return(toolchainDirectory, nil)
+		bindMounter.EXPECT().BindMountReadOnly("/worker/toolchains/8b1a9953c4611296a827abf8c47804d7-123", "/worker/build/a/root/opt/toolchain").
+			Return(status.Error(codes.Internal, "Operation not permitted"))
+		optDirectory.EXPECT().Remove(path.MustNewComponent("toolchain"))
+		inputRoot.EXPECT().Remove(path.MustNewComponent("opt"))
+		toolchainDirectory.EXPECT().Close()
+		optDirectory.EXPECT().Close()
+		inputRoot.EXPECT().Close()
+		directoryA.EXPECT().Close()
+
+		_, err := mountingRunner.Run(ctx, newRequest(&remoteexecution.Platform_Property{
+			Name:  "pinned-toolchain:opt/toolchain",
+			Value: "8b1a9953c4611296a827abf8c47804d7-123",
+		}))
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to mount pinned toolchains: Operation not permitted"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Mount a toolchain in a directory that already exists
+		// in the input root. The directory should be left
+		// behind after unmounting.
+		toolchainsDirectory.EXPECT().Lstat(path.MustNewComponent("8b1a9953c4611296a827abf8c47804d7-123")).
+			Return(filesystem.NewFileInfo(path.MustNewComponent("8b1a9953c4611296a827abf8c47804d7-123"), filesystem.FileTypeDirectory, false), nil)
+		directoryA := mock.NewMockDirectoryCloser(ctrl)
+		buildDirectory.EXPECT().EnterDirectory(path.MustNewComponent("a")).Return(directoryA, nil)
+		inputRoot := mock.NewMockDirectoryCloser(ctrl)
+		directoryA.EXPECT().EnterDirectory(path.MustNewComponent("root")).Return(inputRoot, nil)
+		inputRoot.EXPECT().Mkdir(path.MustNewComponent("toolchain"), os.FileMode(0o755)).Return(syscall.EEXIST)
+		inputRoot.EXPECT().Lstat(path.MustNewComponent("toolchain")).
+			Return(filesystem.NewFileInfo(path.MustNewComponent("toolchain"), filesystem.FileTypeDirectory, false), nil)
+		toolchainDirectory := mock.NewMockDirectoryCloser(ctrl)
+		inputRoot.EXPECT().EnterDirectory(path.MustNewComponent("toolchain")).Return(toolchainDirectory, nil)
+		bindMounter.EXPECT().BindMountReadOnly("/worker/toolchains/8b1a9953c4611296a827abf8c47804d7-123", "/worker/build/a/root/toolchain")
+		request := newRequest(&remoteexecution.Platform_Property{
+			Name:  "pinned-toolchain:toolchain",
+			Value: "8b1a9953c4611296a827abf8c47804d7-123",
+		})
+		baseRunner.EXPECT().Run(ctx, request).Return(&runner_pb.RunResponse{ExitCode: 0}, nil)
+		inputRoot.EXPECT().Unmount(path.MustNewComponent("toolchain"))
+		toolchainDirectory.EXPECT().Close()
+		inputRoot.EXPECT().Close()
+		directoryA.EXPECT().Close()
+
+		response, err := mountingRunner.Run(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &runner_pb.RunResponse{ExitCode: 0}, response)
+	})
+}
@@ -0,0 +1,102 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// powercapTopLevelZonePattern matches the names of powercap zones that
+// correspond to entire CPU packages. Subzones (e.g., "intel-rapl:0:0")
+// are excluded, as their energy is included in that of their parents.
+var powercapTopLevelZonePattern = regexp.MustCompile(`^intel-rapl:[0-9]+$`)
+
+type powercapZone struct {
+	path                  string
+	maximumEnergyRange    uint64
+	lastEnergyMicrojoules uint64
+}
+
+type powercapEnergyMeter struct {
+	lock                   sync.Mutex
+	zones                  []powercapZone
+	totalEnergyMicrojoules uint64
+}
+
+// NewPowercapEnergyMeter creates an EnergyMeter that reads the energy
+// counters of CPU packages, as exposed by Linux's powercap framework
+// (typically at /sys/class/powercap). Counters of individual zones
+// wrap around when reaching their maximum value. The meter accounts
+// for this, under the assumption that it is called at least once
+// every wraparound period.
+func NewPowercapEnergyMeter(powercapPath string) (EnergyMeter, error) {
+	entries, err := os.ReadDir(powercapPath)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to read directory %#v", powercapPath)
+	}
+	em := &powercapEnergyMeter{}
+	for _, entry := range entries {
+		if !powercapTopLevelZonePattern.MatchString(entry.Name()) {
+			continue
+		}
+		zonePath := filepath.Join(powercapPath, entry.Name())
+		maximumEnergyRange, err := readPowercapCounter(filepath.Join(zonePath, "max_energy_range_uj"))
+		if err != nil {
+			return nil, err
+		}
+		energy, err := readPowercapCounter(filepath.Join(zonePath, "energy_uj"))
+		if err != nil {
+			return nil, err
+		}
+		em.zones = append(em.zones, powercapZone{
+			path:                  zonePath,
+			maximumEnergyRange:    maximumEnergyRange,
+			lastEnergyMicrojoules: energy,
+		})
+	}
+	if len(em.zones) == 0 {
+		return nil, status.Errorf(codes.NotFound, "Directory %#v does not contain any powercap zones for CPU packages", powercapPath)
+	}
+	return em, nil
+}
+
+func readPowercapCounter(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, util.StatusWrapf(err, "Failed to read %#v", path)
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid value in %#v", path)
+	}
+	return value, nil
+}
+
+func (em *powercapEnergyMeter) GetEnergyConsumedJoules() (float64, error) {
+	em.lock.Lock()
+	defer em.lock.Unlock()
+
+	for i := range em.zones {
+		zone := &em.zones[i]
+		energy, err := readPowercapCounter(filepath.Join(zone.path, "energy_uj"))
+		if err != nil {
+			return 0, err
+		}
+		if energy >= zone.lastEnergyMicrojoules {
+			em.totalEnergyMicrojoules += energy - zone.lastEnergyMicrojoules
+		} else {
+			// The counter wrapped around.
+			em.totalEnergyMicrojoules += zone.maximumEnergyRange - zone.lastEnergyMicrojoules + energy
+		}
+		zone.lastEnergyMicrojoules = energy
+	}
+	return float64(em.totalEnergyMicrojoules) / 1e6, nil
+}
@@ -0,0 +1,54 @@
+package runner_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPowercapEnergyMeter(t *testing.T) {
+	writeCounter := func(t *testing.T, path, value string) {
+		require.NoError(t, os.WriteFile(path, []byte(value+"\n"), 0o644))
+	}
+
+	t.Run("NoZones", func(t *testing.T) {
+		powercapPath := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(powercapPath, "dtpm"), 0o755))
+
+		_, err := runner.NewPowercapEnergyMeter(powercapPath)
+		testutil.RequireEqualStatus(t, status.Errorf(codes.NotFound, "Directory %#v does not contain any powercap zones for CPU packages", powercapPath), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Create two packages, each having a subzone. Only the
+		// packages themselves should be taken into account.
+		powercapPath := t.TempDir()
+		for _, zone := range []string{"intel-rapl:0", "intel-rapl:0:0", "intel-rapl:1", "intel-rapl:1:0"} {
+			zonePath := filepath.Join(powercapPath, zone)
+			require.NoError(t, os.Mkdir(zonePath, 0o755))
+			writeCounter(t, filepath.Join(zonePath, "max_energy_range_uj"), "1000000000")
+			writeCounter(t, filepath.Join(zonePath, "energy_uj"), "999000000")
+		}
+
+		energyMeter, err := runner.NewPowercapEnergyMeter(powercapPath)
+		require.NoError(t, err)
+		energy, err := energyMeter.GetEnergyConsumedJoules()
+		require.NoError(t, err)
+		require.Equal(t, 0.0, energy)
+
+		// The counter of the first package wraps around.
+		writeCounter(t, filepath.Join(powercapPath, "intel-rapl:0", "energy_uj"), "2000000")
+		writeCounter(t, filepath.Join(powercapPath, "intel-rapl:0:0", "energy_uj"), "999500000")
+		writeCounter(t, filepath.Join(powercapPath, "intel-rapl:1", "energy_uj"), "999500000")
+		energy, err = energyMeter.GetEnergyConsumedJoules()
+		require.NoError(t, err)
+		require.Equal(t, 3.5, energy)
+	})
+}
@@ -0,0 +1,155 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PrerequisiteChecker is a callback function that is used to determine
+// whether the current system meets a requirement for executing build
+// actions (e.g., a specific version of Xcode being installed, or a
+// license server being reachable). Workers whose runner does not meet
+// all of its prerequisites fail readiness checks, thereby preventing
+// them from picking up actions.
+type PrerequisiteChecker func(ctx context.Context) error
+
+// NewCommandPrerequisiteChecker creates a PrerequisiteChecker that
+// runs a command, requiring that it terminates successfully. If an
+// output pattern is provided, the standard output of the command must
+// match it as well. This can, for example, be used to require that
+// "xcodebuild -version" prints a specific version.
+func NewCommandPrerequisiteChecker(arguments []string, outputPattern *regexp.Regexp) PrerequisiteChecker {
+	return func(ctx context.Context) error {
+		cmd := exec.CommandContext(ctx, arguments[0], arguments[1:]...)
+		output, err := cmd.Output()
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				return status.Errorf(codes.FailedPrecondition, "Command %#v failed with output %#v", arguments[0], strings.TrimSpace(string(exitErr.Stderr)))
+			}
+			return util.StatusWrapfWithCode(err, codes.FailedPrecondition, "Failed to run command %#v", arguments[0])
+		}
+		if outputPattern != nil && !outputPattern.Match(output) {
+			return status.Errorf(codes.FailedPrecondition, "Output %#v of command %#v does not match pattern %#v", strings.TrimSpace(string(output)), arguments[0], outputPattern.String())
+		}
+		return nil
+	}
+}
+
+// NewPathExistencePrerequisiteChecker creates a PrerequisiteChecker
+// that requires that a path on disk exists. This can, for example, be
+// used to require that a kernel module is loaded by checking for the
+// presence of /sys/module/${name}.
+func NewPathExistencePrerequisiteChecker(path string) PrerequisiteChecker {
+	return func(ctx context.Context) error {
+		if _, err := os.Stat(path); err != nil {
+			return util.StatusWrapfWithCode(err, codes.FailedPrecondition, "Path %#v", path)
+		}
+		return nil
+	}
+}
+
+// NewTCPConnectPrerequisiteChecker creates a PrerequisiteChecker that
+// requires that a TCP connection can be established to a given
+// address. This can, for example, be used to require that a license
+// server is reachable.
+func NewTCPConnectPrerequisiteChecker(address string) PrerequisiteChecker {
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return util.StatusWrapfWithCode(err, codes.Unavailable, "Failed to connect to %#v", address)
+		}
+		conn.Close()
+		return nil
+	}
+}
+
+// NewTimeoutPrerequisiteChecker creates a decorator for
+// PrerequisiteChecker that limits the amount of time a single check
+// may take, so that a hanging check does not stall readiness checking
+// indefinitely.
+func NewTimeoutPrerequisiteChecker(base PrerequisiteChecker, timeout time.Duration) PrerequisiteChecker {
+	return func(ctx context.Context) error {
+		ctxWithTimeout, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return base(ctxWithTimeout)
+	}
+}
+
+// NewNamedPrerequisiteChecker creates a decorator for
+// PrerequisiteChecker that prefixes errors with the name of the
+// prerequisite, so that administrators can easily identify which of
+// the prerequisites are not met.
+func NewNamedPrerequisiteChecker(base PrerequisiteChecker, name string) PrerequisiteChecker {
+	return func(ctx context.Context) error {
+		if err := base(ctx); err != nil {
+			return util.StatusWrapf(err, "Prerequisite %#v", name)
+		}
+		return nil
+	}
+}
+
+// NewChainedPrerequisiteChecker creates a PrerequisiteChecker that
+// requires that all of the provided prerequisites are met. All
+// prerequisites are checked, even if some of them fail, so that all
+// problems with the current system are reported at once.
+func NewChainedPrerequisiteChecker(checkers []PrerequisiteChecker) PrerequisiteChecker {
+	return func(ctx context.Context) error {
+		var firstErr error
+		var messages []string
+		for _, checker := range checkers {
+			if err := checker(ctx); err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				messages = append(messages, status.Convert(err).Message())
+			}
+		}
+		if firstErr == nil {
+			return nil
+		}
+		return status.Error(status.Code(firstErr), strings.Join(messages, "; "))
+	}
+}
+
+// NewCachingPrerequisiteChecker creates a decorator for
+// PrerequisiteChecker that caches the result of the last check for a
+// fixed amount of time. This prevents expensive checks from being
+// performed every time bb_worker calls into CheckReadiness().
+func NewCachingPrerequisiteChecker(base PrerequisiteChecker, clock clock.Clock, cacheDuration time.Duration) PrerequisiteChecker {
+	var lock sync.Mutex
+	var cachedErr error
+	var cacheExpiration time.Time
+
+	return func(ctx context.Context) error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if now := clock.Now(); now.Before(cacheExpiration) {
+			return cachedErr
+		}
+		err := base(ctx)
+		if ctx.Err() != nil {
+			// Don't cache results of checks that were
+			// interrupted by the caller.
+			return err
+		}
+		cachedErr = err
+		cacheExpiration = clock.Now().Add(cacheDuration)
+		return err
+	}
+}
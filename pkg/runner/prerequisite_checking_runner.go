@@ -0,0 +1,42 @@
+package runner
+
+import (
+	"context"
+
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type prerequisiteCheckingRunner struct {
+	base    runner_pb.RunnerServer
+	checker PrerequisiteChecker
+}
+
+// NewPrerequisiteCheckingRunner creates a decorator of RunnerServer
+// that is only healthy when all prerequisites for executing build
+// actions are met. This prevents actions from being dispatched to
+// workers that are missing required tools, such as a specific version
+// of Xcode.
+func NewPrerequisiteCheckingRunner(base runner_pb.RunnerServer, checker PrerequisiteChecker) runner_pb.RunnerServer {
+	return &prerequisiteCheckingRunner{
+		base:    base,
+		checker: checker,
+	}
+}
+
+func (r *prerequisiteCheckingRunner) CheckReadiness(ctx context.Context, request *runner_pb.CheckReadinessRequest) (*emptypb.Empty, error) {
+	if err := r.checker(ctx); err != nil {
+		return nil, util.StatusWrap(err, "Execution prerequisites not met")
+	}
+	return r.base.CheckReadiness(ctx, request)
+}
+
+func (r *prerequisiteCheckingRunner) Run(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+	return r.base.Run(ctx, request)
+}
+
+func (r *prerequisiteCheckingRunner) GetProcessTree(ctx context.Context, request *runner_pb.GetProcessTreeRequest) (*runner_pb.GetProcessTreeResponse, error) {
+	return r.base.GetProcessTree(ctx, request)
+}
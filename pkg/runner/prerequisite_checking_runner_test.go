@@ -0,0 +1,133 @@
+package runner_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestPrerequisiteCheckingRunner(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	mockRunner := mock.NewMockRunnerServer(ctrl)
+	var checkErr error
+	runnerServer := runner.NewPrerequisiteCheckingRunner(
+		mockRunner,
+		func(ctx context.Context) error { return checkErr })
+
+	t.Run("NotMet", func(t *testing.T) {
+		// Readiness checks should fail without calling into
+		// the base runner if prerequisites are not met.
+		checkErr = status.Error(codes.FailedPrecondition, "Prerequisite \"xcode\": Command \"xcodebuild\" failed with output \"\"")
+		_, err := runnerServer.CheckReadiness(ctx, &runner_pb.CheckReadinessRequest{})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Execution prerequisites not met: Prerequisite \"xcode\": Command \"xcodebuild\" failed with output \"\""), err)
+	})
+
+	t.Run("Met", func(t *testing.T) {
+		checkErr = nil
+		request := &runner_pb.CheckReadinessRequest{Path: "check_readiness"}
+		mockRunner.EXPECT().CheckReadiness(ctx, request).Return(&emptypb.Empty{}, nil)
+		_, err := runnerServer.CheckReadiness(ctx, request)
+		require.NoError(t, err)
+	})
+}
+
+func TestChainedPrerequisiteChecker(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("AllMet", func(t *testing.T) {
+		checker := runner.NewChainedPrerequisiteChecker([]runner.PrerequisiteChecker{
+			func(ctx context.Context) error { return nil },
+			func(ctx context.Context) error { return nil },
+		})
+		require.NoError(t, checker(ctx))
+	})
+
+	t.Run("MultipleNotMet", func(t *testing.T) {
+		// All failures should be reported, using the code of
+		// the first failure.
+		checker := runner.NewChainedPrerequisiteChecker([]runner.PrerequisiteChecker{
+			runner.NewNamedPrerequisiteChecker(
+				func(ctx context.Context) error {
+					return status.Error(codes.FailedPrecondition, "Path \"/sys/module/kvm\": no such file or directory")
+				},
+				"kvm"),
+			func(ctx context.Context) error { return nil },
+			runner.NewNamedPrerequisiteChecker(
+				func(ctx context.Context) error {
+					return status.Error(codes.Unavailable, "Failed to connect to \"license:27000\"")
+				},
+				"license_server"),
+		})
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Prerequisite \"kvm\": Path \"/sys/module/kvm\": no such file or directory; Prerequisite \"license_server\": Failed to connect to \"license:27000\""),
+			checker(ctx))
+	})
+}
+
+func TestCachingPrerequisiteChecker(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	clock := mock.NewMockClock(ctrl)
+	calls := 0
+	checker := runner.NewCachingPrerequisiteChecker(
+		func(ctx context.Context) error {
+			calls++
+			if calls == 1 {
+				return status.Error(codes.FailedPrecondition, "Xcode not installed")
+			}
+			return nil
+		},
+		clock,
+		time.Minute)
+
+	// The first call should invoke the base checker.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).Times(2)
+	testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Xcode not installed"), checker(ctx))
+
+	// Successive calls within the cache duration should return the
+	// cached result, even if it is a failure.
+	clock.EXPECT().Now().Return(time.Unix(1059, 0))
+	testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Xcode not installed"), checker(ctx))
+	require.Equal(t, 1, calls)
+
+	// Once the cache duration has passed, the check should be
+	// performed once more.
+	clock.EXPECT().Now().Return(time.Unix(1060, 0)).Times(2)
+	require.NoError(t, checker(ctx))
+	require.Equal(t, 2, calls)
+}
+
+func TestCommandPrerequisiteChecker(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		checker := runner.NewCommandPrerequisiteChecker([]string{"sh", "-c", "echo Xcode 15.2"}, nil)
+		require.NoError(t, checker(ctx))
+	})
+
+	t.Run("NonZeroExitCode", func(t *testing.T) {
+		checker := runner.NewCommandPrerequisiteChecker([]string{"sh", "-c", "echo not installed >&2; exit 1"}, nil)
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Command \"sh\" failed with output \"not installed\""), checker(ctx))
+	})
+
+	t.Run("OutputMismatch", func(t *testing.T) {
+		checker := runner.NewCommandPrerequisiteChecker(
+			[]string{"sh", "-c", "echo Xcode 14.3"},
+			regexp.MustCompile(`(?m)^Xcode 15\.2$`))
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Output \"Xcode 14.3\" of command \"sh\" does not match pattern \"(?m)^Xcode 15\\\\.2$\""), checker(ctx))
+	})
+}
@@ -0,0 +1,18 @@
+//go:build darwin || freebsd || windows
+// +build darwin freebsd windows
+
+package runner
+
+import (
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// listProcessTree returns details on a process and all of its
+// descendants. On this operating system this functionality is not
+// available.
+func listProcessTree(rootProcessID int64) ([]*runner_pb.Process, error) {
+	return nil, status.Error(codes.Unimplemented, "Listing process trees is not supported on this platform")
+}
@@ -0,0 +1,109 @@
+//go:build linux
+// +build linux
+
+package runner
+
+import (
+	"bytes"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// readProcess obtains details on a single process from procfs. As the
+// process may terminate at any point in time, the caller should ignore
+// errors.
+func readProcess(processID int64) (*runner_pb.Process, error) {
+	processDirectory := "/proc/" + strconv.FormatInt(processID, 10)
+	stat, err := os.ReadFile(processDirectory + "/stat")
+	if err != nil {
+		return nil, err
+	}
+
+	// The name of the executable is enclosed in parentheses and
+	// may contain spaces. Skip it, as the full command line is
+	// read separately.
+	commEnd := bytes.LastIndexByte(stat, ')')
+	if commEnd < 0 {
+		return nil, status.Error(codes.InvalidArgument, "Process status does not contain the name of the executable")
+	}
+	fields := strings.Fields(string(stat[commEnd+1:]))
+	if len(fields) < 22 {
+		return nil, status.Error(codes.InvalidArgument, "Process status contains too few fields")
+	}
+	parentProcessID, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid parent process ID")
+	}
+	residentSetSizePages, err := strconv.ParseInt(fields[21], 10, 64)
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid resident set size")
+	}
+
+	var arguments []string
+	if cmdline, err := os.ReadFile(processDirectory + "/cmdline"); err == nil && len(cmdline) > 0 {
+		arguments = strings.Split(strings.TrimSuffix(string(cmdline), "\x00"), "\x00")
+	}
+
+	return &runner_pb.Process{
+		ProcessId:            processID,
+		ParentProcessId:      parentProcessID,
+		Arguments:            arguments,
+		State:                fields[0],
+		ResidentSetSizeBytes: residentSetSizePages * int64(os.Getpagesize()),
+	}, nil
+}
+
+// listProcessTree returns details on a process and all of its
+// descendants by scanning procfs.
+func listProcessTree(rootProcessID int64) ([]*runner_pb.Process, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to list processes")
+	}
+	processes := map[int64]*runner_pb.Process{}
+	children := map[int64][]int64{}
+	for _, entry := range entries {
+		processID, err := strconv.ParseInt(entry.Name(), 10, 64)
+		if err != nil {
+			continue
+		}
+		process, err := readProcess(processID)
+		if err != nil {
+			// Process terminated while scanning.
+			continue
+		}
+		processes[processID] = process
+		children[process.ParentProcessId] = append(children[process.ParentProcessId], processID)
+	}
+
+	rootProcess, ok := processes[rootProcessID]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "Process %d no longer exists", rootProcessID)
+	}
+
+	// Return all descendants of the root process in depth-first
+	// order.
+	processTree := []*runner_pb.Process{rootProcess}
+	stack := []int64{rootProcessID}
+	for len(stack) > 0 {
+		processID := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		childProcessIDs := children[processID]
+		sort.Slice(childProcessIDs, func(i, j int) bool {
+			return childProcessIDs[i] > childProcessIDs[j]
+		})
+		stack = append(stack, childProcessIDs...)
+		if processID != rootProcessID {
+			processTree = append(processTree, processes[processID])
+		}
+	}
+	return processTree, nil
+}
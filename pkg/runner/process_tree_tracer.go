@@ -0,0 +1,27 @@
+package runner
+
+import (
+	"os/exec"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ProcessTreeTracer can be provided to NewLocalRunner() to let it
+// gather details on all processes spawned by a command, as opposed to
+// only the command's main process. This makes it possible to identify
+// child processes that crashed, even if their parent discarded their
+// exit status.
+type ProcessTreeTracer interface {
+	// Start a command, tracing it and all of its descendants.
+	Start(cmd *exec.Cmd) (TracedProcessTree, error)
+}
+
+// TracedProcessTree corresponds to a command that was started by
+// ProcessTreeTracer.
+type TracedProcessTree interface {
+	// Wait for the command's main process to terminate, and return
+	// details on all processes that terminated in the meantime. As
+	// the command's main process cannot be waited for while being
+	// traced, this function must be called before exec.Cmd.Wait().
+	Wait() (proto.Message, error)
+}
@@ -0,0 +1,16 @@
+//go:build darwin || freebsd || windows
+// +build darwin freebsd windows
+
+package runner
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewPtraceProcessTreeTracer creates a ProcessTreeTracer that uses
+// ptrace() to gather details on all processes spawned by a command. On
+// this operating system this functionality is not available.
+func NewPtraceProcessTreeTracer(maximumProcesses int) (ProcessTreeTracer, error) {
+	return nil, status.Error(codes.Unimplemented, "Process tree tracing is not supported on this platform")
+}
@@ -0,0 +1,278 @@
+//go:build linux
+// +build linux
+
+package runner
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+// Values of si_code for SIGCHLD, as declared in <signal.h>.
+const (
+	cldExited = 1
+	cldKilled = 2
+	cldDumped = 3
+)
+
+// siginfoChld contains the leading fields of siginfo_t, as filled in
+// by waitid(). The zero-length array ensures that the union containing
+// si_pid is aligned in the same way as it is by the kernel.
+type siginfoChld struct {
+	signo  int32
+	errno  int32
+	code   int32
+	_      [0]uintptr
+	pid    int32
+	uid    uint32
+	status int32
+}
+
+type ptraceProcessTreeTracer struct {
+	maximumProcesses int
+}
+
+// NewPtraceProcessTreeTracer creates a ProcessTreeTracer that uses
+// ptrace() to gather the exit status and peak resident set size of
+// every process spawned by a command. Exit statuses are captured
+// through PTRACE_EVENT_EXIT, meaning they are reported even if the
+// parent of a process never waits for it.
+//
+// Details are reported in the form of ProcessTreeResourceUsage
+// messages. If maximumProcesses is non-zero, no more than the provided
+// number of processes are reported.
+func NewPtraceProcessTreeTracer(maximumProcesses int) (ProcessTreeTracer, error) {
+	return &ptraceProcessTreeTracer{
+		maximumProcesses: maximumProcesses,
+	}, nil
+}
+
+func (pt *ptraceProcessTreeTracer) Start(cmd *exec.Cmd) (TracedProcessTree, error) {
+	// The SysProcAttr may be shared by many commands, so we must
+	// not modify it in place.
+	var sysProcAttr syscall.SysProcAttr
+	if cmd.SysProcAttr != nil {
+		sysProcAttr = *cmd.SysProcAttr
+	}
+	sysProcAttr.Ptrace = true
+	cmd.SysProcAttr = &sysProcAttr
+
+	tpt := &ptraceTracedProcessTree{
+		maximumProcesses: pt.maximumProcesses,
+		done:             make(chan struct{}),
+	}
+	startErr := make(chan error, 1)
+	go func() {
+		// All ptrace() calls need to be made from the thread
+		// that started the command. Don't unlock the thread
+		// afterwards, so that it gets terminated once tracing
+		// completes. This causes the kernel to detach from any
+		// processes that are still being traced, and lets
+		// another thread adopt the command's main process.
+		runtime.LockOSThread()
+		if err := cmd.Start(); err != nil {
+			startErr <- err
+			return
+		}
+		startErr <- nil
+		tpt.err = tpt.trace(cmd.Process.Pid)
+		close(tpt.done)
+	}()
+	if err := <-startErr; err != nil {
+		return nil, err
+	}
+	return tpt, nil
+}
+
+type ptraceTraceeState int
+
+const (
+	// The tracee has reported its initial stop, and is being traced
+	// with the desired options.
+	ptraceTraceeAttached ptraceTraceeState = iota + 1
+	// Details on the tracee have already been recorded, as it
+	// reported PTRACE_EVENT_EXIT.
+	ptraceTraceeTerminated
+)
+
+type ptraceTracedProcessTree struct {
+	maximumProcesses int
+	done             chan struct{}
+
+	// Fields that are set by trace(). They may only be accessed
+	// after done is closed.
+	processes []*resourceusage.ProcessTreeResourceUsage_Process
+	err       error
+}
+
+// trace the command's main process and all of its descendants until
+// the main process terminates.
+func (tpt *ptraceTracedProcessTree) trace(rootPID int) error {
+	tracees := map[int]ptraceTraceeState{}
+	for {
+		// Determine which process changed state without
+		// reaping it. The command's main process needs to be
+		// reaped by exec.Cmd.Wait() instead.
+		var info unix.Siginfo
+		if err := unix.Waitid(unix.P_ALL, 0, &info, unix.WEXITED|unix.WSTOPPED|unix.WNOWAIT|unix.WALL|unix.WNOTHREAD, nil); err != nil {
+			if err == unix.EINTR {
+				continue
+			}
+			return util.StatusWrapWithCode(err, codes.Internal, "Failed to wait for traced processes")
+		}
+		siginfo := (*siginfoChld)(unsafe.Pointer(&info))
+		pid := int(siginfo.pid)
+		if pid == rootPID {
+			switch siginfo.code {
+			case cldExited:
+				// The main process terminated without
+				// reporting PTRACE_EVENT_EXIT, which may
+				// happen when it is killed by SIGKILL.
+				// Convert si_status to a wait status.
+				tpt.addProcess(pid, unix.WaitStatus(siginfo.status<<8), 0)
+				return nil
+			case cldKilled, cldDumped:
+				tpt.addProcess(pid, unix.WaitStatus(siginfo.status), 0)
+				return nil
+			}
+		}
+
+		var waitStatus unix.WaitStatus
+		var rusage unix.Rusage
+		if _, err := unix.Wait4(pid, &waitStatus, unix.WALL|unix.WNOTHREAD, &rusage); err != nil {
+			if err == unix.EINTR {
+				continue
+			}
+			return util.StatusWrapfWithCode(err, codes.Internal, "Failed to wait for traced process %d", pid)
+		}
+		if waitStatus.Exited() || waitStatus.Signaled() {
+			// A descendant terminated. Only record it if it
+			// didn't report PTRACE_EVENT_EXIT.
+			if tracees[pid] != ptraceTraceeTerminated {
+				tpt.addProcess(pid, waitStatus, int64(rusage.Maxrss)*maximumResidentSetSizeUnit)
+			}
+			delete(tracees, pid)
+			continue
+		}
+		if !waitStatus.Stopped() {
+			continue
+		}
+
+		signal := 0
+		if _, ok := tracees[pid]; !ok {
+			// Initial stop of a process. The main process
+			// stops with SIGTRAP after calling execve(),
+			// while its descendants stop with SIGSTOP.
+			// Descendants inherit the options of their
+			// parent, so these only need to be set once.
+			if pid == rootPID {
+				if err := unix.PtraceSetOptions(pid, unix.PTRACE_O_TRACEEXEC|unix.PTRACE_O_TRACEEXIT|unix.PTRACE_O_TRACEFORK|unix.PTRACE_O_TRACEVFORK); err != nil {
+					return util.StatusWrapWithCode(err, codes.Internal, "Failed to set ptrace options")
+				}
+			}
+			tracees[pid] = ptraceTraceeAttached
+		} else if waitStatus.StopSignal() == unix.SIGTRAP && waitStatus.TrapCause() == unix.PTRACE_EVENT_EXIT {
+			// The process is about to terminate. Its
+			// memory is still mapped, meaning its peak
+			// resident set size can still be obtained.
+			if exitStatus, err := unix.PtraceGetEventMsg(pid); err == nil {
+				tpt.addProcess(pid, unix.WaitStatus(exitStatus), 0)
+				tracees[pid] = ptraceTraceeTerminated
+			}
+			if pid == rootPID {
+				unix.PtraceDetach(pid)
+				return nil
+			}
+		} else if waitStatus.StopSignal() != unix.SIGTRAP || waitStatus.TrapCause() == 0 {
+			// Signal-delivery-stop. Deliver the signal
+			// to the process, as it would have been when
+			// not being traced.
+			signal = int(waitStatus.StopSignal())
+		}
+
+		// Let the process continue. This may fail if the
+		// process got killed in the meantime.
+		unix.PtraceCont(pid, signal)
+	}
+}
+
+// addProcess records details on a process that terminated.
+func (tpt *ptraceTracedProcessTree) addProcess(pid int, waitStatus unix.WaitStatus, maximumResidentSetSize int64) {
+	process := &resourceusage.ProcessTreeResourceUsage_Process{
+		Pid:                    int64(pid),
+		MaximumResidentSetSize: maximumResidentSetSize,
+	}
+	if waitStatus.Signaled() {
+		if s, ok := strings.CutPrefix(unix.SignalName(waitStatus.Signal()), "SIG"); ok {
+			process.TerminationSignal = s
+		}
+	} else {
+		process.ExitCode = int32(waitStatus.ExitStatus())
+	}
+
+	// Obtain the parent process ID and peak resident set size from
+	// /proc. The latter is only reported if the process has not
+	// released its memory yet.
+	procPath := "/proc/" + strconv.FormatInt(int64(pid), 10)
+	if data, err := os.ReadFile(procPath + "/status"); err == nil {
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			if fields := bytes.Fields(line); len(fields) >= 2 {
+				value, err := strconv.ParseInt(string(fields[1]), 10, 64)
+				if err != nil {
+					continue
+				}
+				switch string(fields[0]) {
+				case "PPid:":
+					process.ParentPid = value
+				case "VmHWM:":
+					process.MaximumResidentSetSize = value * 1024
+				}
+			}
+		}
+	}
+	if executable, err := os.Readlink(procPath + "/exe"); err == nil {
+		process.Executable = executable
+	}
+	tpt.processes = append(tpt.processes, process)
+}
+
+func (tpt *ptraceTracedProcessTree) Wait() (proto.Message, error) {
+	<-tpt.done
+	if tpt.err != nil {
+		return nil, tpt.err
+	}
+
+	// If the number of processes exceeds the limit, omit processes
+	// that terminated successfully first, as those are the least
+	// likely to be of interest.
+	processes := tpt.processes
+	if tpt.maximumProcesses > 0 && len(processes) > tpt.maximumProcesses {
+		successfulToOmit := len(processes) - tpt.maximumProcesses
+		processes = make([]*resourceusage.ProcessTreeResourceUsage_Process, 0, len(tpt.processes))
+		for _, process := range tpt.processes {
+			if successfulToOmit > 0 && process.ExitCode == 0 && process.TerminationSignal == "" {
+				successfulToOmit--
+				continue
+			}
+			processes = append(processes, process)
+		}
+		processes = processes[:tpt.maximumProcesses]
+	}
+	return &resourceusage.ProcessTreeResourceUsage{
+		Processes:        processes,
+		ProcessesOmitted: int64(len(tpt.processes) - len(processes)),
+	}, nil
+}
@@ -0,0 +1,147 @@
+package runner
+
+import (
+	"context"
+	"strings"
+	"text/template"
+
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// SandboxCommandParameters contains the values that may be referenced
+// from the templates of the arguments of a sandbox command. All paths
+// are absolute.
+type SandboxCommandParameters struct {
+	BuildDirectory     string
+	InputRootDirectory string
+	WorkingDirectory   string
+	TemporaryDirectory string
+	StdoutPath         string
+	StderrPath         string
+}
+
+type sandboxingRunner struct {
+	base                    runner_pb.RunnerServer
+	buildDirectoryPath      *path.Builder
+	commandTemplates        []*template.Template
+	sandboxFailureExitCodes map[int32]struct{}
+	exitCodeMapping         map[int32]int32
+}
+
+// NewSandboxingRunner creates a decorator for Runner that runs every
+// command through an external sandbox binary (e.g., Bazel's
+// linux-sandbox). This allows sites that already have hardened sandbox
+// tooling to use it, without needing to make changes to bb_runner.
+//
+// The arguments of the sandbox command are prepended to the arguments
+// of the command. Each of them is expanded as a Go template, having
+// SandboxCommandParameters as its data. The sandbox binary is expected
+// to run the command with the arguments that follow, and to exit with
+// the exit code of the command.
+//
+// As sandbox binaries may fail themselves, exit codes that are listed
+// in sandboxFailureExitCodes cause execution to fail with an error,
+// as opposed to being reported as the exit code of the command. Other
+// exit codes may be translated using exitCodeMapping.
+func NewSandboxingRunner(base runner_pb.RunnerServer, buildDirectoryPath *path.Builder, command []string, sandboxFailureExitCodes map[int32]struct{}, exitCodeMapping map[int32]int32) (runner_pb.RunnerServer, error) {
+	if len(command) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "No sandbox command provided")
+	}
+	commandTemplates := make([]*template.Template, 0, len(command))
+	for i, argument := range command {
+		commandTemplate, err := template.New("argument").Option("missingkey=error").Parse(argument)
+		if err != nil {
+			return nil, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid template for sandbox command argument at index %d", i)
+		}
+		commandTemplates = append(commandTemplates, commandTemplate)
+	}
+	return &sandboxingRunner{
+		base:                    base,
+		buildDirectoryPath:      buildDirectoryPath,
+		commandTemplates:        commandTemplates,
+		sandboxFailureExitCodes: sandboxFailureExitCodes,
+		exitCodeMapping:         exitCodeMapping,
+	}, nil
+}
+
+// resolve a path provided by bb_worker that is relative to the build
+// directory, and convert it to an absolute path.
+func (r *sandboxingRunner) resolve(base *path.Builder, p string) (*path.Builder, error) {
+	resolvedPath, scopeWalker := base.Join(path.VoidScopeWalker)
+	if err := path.Resolve(p, scopeWalker); err != nil {
+		return nil, err
+	}
+	return resolvedPath, nil
+}
+
+func (r *sandboxingRunner) Run(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+	inputRootDirectory, err := r.resolve(r.buildDirectoryPath, request.InputRootDirectory)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to resolve input root directory")
+	}
+	workingDirectory, err := r.resolve(inputRootDirectory, request.WorkingDirectory)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to resolve working directory")
+	}
+	temporaryDirectory, err := r.resolve(r.buildDirectoryPath, request.TemporaryDirectory)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to resolve temporary directory")
+	}
+	stdoutPath, err := r.resolve(r.buildDirectoryPath, request.StdoutPath)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to resolve stdout path")
+	}
+	stderrPath, err := r.resolve(r.buildDirectoryPath, request.StderrPath)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to resolve stderr path")
+	}
+	parameters := SandboxCommandParameters{
+		BuildDirectory:     r.buildDirectoryPath.String(),
+		InputRootDirectory: inputRootDirectory.String(),
+		WorkingDirectory:   workingDirectory.String(),
+		TemporaryDirectory: temporaryDirectory.String(),
+		StdoutPath:         stdoutPath.String(),
+		StderrPath:         stderrPath.String(),
+	}
+
+	// Prepend the arguments of the sandbox command.
+	arguments := make([]string, 0, len(r.commandTemplates)+len(request.Arguments))
+	for i, commandTemplate := range r.commandTemplates {
+		var argument strings.Builder
+		if err := commandTemplate.Execute(&argument, &parameters); err != nil {
+			return nil, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Failed to expand sandbox command argument at index %d", i)
+		}
+		arguments = append(arguments, argument.String())
+	}
+	arguments = append(arguments, request.Arguments...)
+
+	newRequest := proto.Clone(request).(*runner_pb.RunRequest)
+	newRequest.Arguments = arguments
+	response, err := r.base.Run(ctx, newRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := r.sandboxFailureExitCodes[response.ExitCode]; ok {
+		return nil, status.Errorf(codes.Internal, "Sandbox failed with exit code %d", response.ExitCode)
+	}
+	if exitCode, ok := r.exitCodeMapping[response.ExitCode]; ok {
+		response.ExitCode = exitCode
+	}
+	return response, nil
+}
+
+func (r *sandboxingRunner) CheckReadiness(ctx context.Context, request *runner_pb.CheckReadinessRequest) (*emptypb.Empty, error) {
+	return r.base.CheckReadiness(ctx, request)
+}
+
+func (r *sandboxingRunner) GetProcessTree(ctx context.Context, request *runner_pb.GetProcessTreeRequest) (*runner_pb.GetProcessTreeResponse, error) {
+	return r.base.GetProcessTree(ctx, request)
+}
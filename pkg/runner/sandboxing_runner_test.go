@@ -0,0 +1,119 @@
+package runner_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSandboxingRunner(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	buildDirectory, scopeWalker := path.EmptyBuilder.Join(path.VoidScopeWalker)
+	require.NoError(t, path.Resolve("/worker/build", scopeWalker))
+
+	t.Run("InvalidTemplate", func(t *testing.T) {
+		baseRunner := mock.NewMockRunnerServer(ctrl)
+		_, err := runner.NewSandboxingRunner(baseRunner, buildDirectory, []string{"/usr/bin/sandbox", "{{.InputRootDirectory"}, nil, nil)
+		testutil.RequirePrefixedStatus(t, status.Error(codes.InvalidArgument, "Invalid template for sandbox command argument at index 1: "), err)
+	})
+
+	baseRunner := mock.NewMockRunnerServer(ctrl)
+	runner, err := runner.NewSandboxingRunner(
+		baseRunner,
+		buildDirectory,
+		[]string{
+			"/usr/bin/sandbox",
+			"-W", "{{.WorkingDirectory}}",
+			"-w", "{{.InputRootDirectory}}",
+			"-w", "{{.TemporaryDirectory}}",
+			"--stdout={{.StdoutPath}}",
+			"--",
+		},
+		map[int32]struct{}{
+			1: {},
+		},
+		map[int32]int32{
+			134: 6,
+		})
+	require.NoError(t, err)
+
+	request := &runner_pb.RunRequest{
+		Arguments:          []string{"cc", "-o", "hello.o", "hello.c"},
+		WorkingDirectory:   "subdir",
+		StdoutPath:         "a/stdout",
+		StderrPath:         "a/stderr",
+		InputRootDirectory: "a/root",
+		TemporaryDirectory: "a/tmp",
+	}
+	expectedRequest := &runner_pb.RunRequest{
+		Arguments: []string{
+			"/usr/bin/sandbox",
+			"-W", "/worker/build/a/root/subdir",
+			"-w", "/worker/build/a/root",
+			"-w", "/worker/build/a/tmp",
+			"--stdout=/worker/build/a/stdout",
+			"--",
+			"cc", "-o", "hello.o", "hello.c",
+		},
+		WorkingDirectory:   "subdir",
+		StdoutPath:         "a/stdout",
+		StderrPath:         "a/stderr",
+		InputRootDirectory: "a/root",
+		TemporaryDirectory: "a/tmp",
+	}
+
+	t.Run("InvalidTemporaryDirectory", func(t *testing.T) {
+		_, err := runner.Run(ctx, &runner_pb.RunRequest{
+			Arguments:          []string{"cc", "-o", "hello.o", "hello.c"},
+			WorkingDirectory:   "subdir",
+			StdoutPath:         "a/stdout",
+			StderrPath:         "a/stderr",
+			InputRootDirectory: "a/root",
+			TemporaryDirectory: "a/\x00tmp",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to resolve temporary directory: Path contains a null byte"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The arguments of the sandbox command should be
+		// prepended to those of the command, with all paths
+		// made absolute. Exit codes that aren't listed should
+		// be passed on literally.
+		baseRunner.EXPECT().Run(ctx, testutil.EqProto(t, expectedRequest)).
+			Return(&runner_pb.RunResponse{ExitCode: 2}, nil)
+
+		response, err := runner.Run(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &runner_pb.RunResponse{ExitCode: 2}, response)
+	})
+
+	t.Run("SandboxFailure", func(t *testing.T) {
+		// Exit codes that indicate the sandbox itself failed
+		// should be converted to errors.
+		baseRunner.EXPECT().Run(ctx, testutil.EqProto(t, expectedRequest)).
+			Return(&runner_pb.RunResponse{ExitCode: 1}, nil)
+
+		_, err := runner.Run(ctx, request)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Sandbox failed with exit code 1"), err)
+	})
+
+	t.Run("ExitCodeMapping", func(t *testing.T) {
+		baseRunner.EXPECT().Run(ctx, testutil.EqProto(t, expectedRequest)).
+			Return(&runner_pb.RunResponse{ExitCode: 134}, nil)
+
+		response, err := runner.Run(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &runner_pb.RunResponse{ExitCode: 6}, response)
+	})
+}
@@ -0,0 +1,58 @@
+package runner
+
+import (
+	"sort"
+)
+
+// SchedulingPriority contains the operating system scheduling
+// parameters with which a command is run.
+type SchedulingPriority struct {
+	// The nice value of the command, ranging from -20 (highest
+	// priority) to 19 (lowest priority).
+	Nice int
+
+	// The I/O scheduling class and the priority level within that
+	// class, using the values accepted by Linux's ioprio_set(). If
+	// the class is zero, the I/O priority is left unchanged.
+	IOPriorityClass int
+	IOPriorityLevel int
+
+	// Value to write to cpu.weight of the cgroup in which the
+	// command is run. If zero, the value is left unchanged. This
+	// has no effect if no CgroupCreator is provided.
+	CPUWeight uint32
+}
+
+// SchedulingPriorityRange is a SchedulingPriority that is applied to
+// commands whose REv2 execution priority is at least MinimumPriority.
+type SchedulingPriorityRange struct {
+	MinimumPriority    int32
+	SchedulingPriority SchedulingPriority
+}
+
+// schedulingPriorityRanges is a list of SchedulingPriorityRange
+// entries, sorted by MinimumPriority.
+type schedulingPriorityRanges []SchedulingPriorityRange
+
+func newSchedulingPriorityRanges(ranges []SchedulingPriorityRange) schedulingPriorityRanges {
+	sortedRanges := append(schedulingPriorityRanges(nil), ranges...)
+	sort.SliceStable(sortedRanges, func(i, j int) bool {
+		return sortedRanges[i].MinimumPriority < sortedRanges[j].MinimumPriority
+	})
+	return sortedRanges
+}
+
+// lookup returns the SchedulingPriority that should be applied to a
+// command having a given REv2 execution priority. It returns the entry
+// with the highest MinimumPriority that does not exceed the priority.
+// If no such entry exists, nil is returned, meaning the scheduling
+// priority of the command is left unchanged.
+func (r schedulingPriorityRanges) lookup(priority int32) *SchedulingPriority {
+	i := sort.Search(len(r), func(i int) bool {
+		return r[i].MinimumPriority > priority
+	})
+	if i == 0 {
+		return nil
+	}
+	return &r[i-1].SchedulingPriority
+}
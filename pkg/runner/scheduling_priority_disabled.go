@@ -0,0 +1,16 @@
+//go:build darwin || freebsd || windows
+// +build darwin freebsd windows
+
+package runner
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// setProcessSchedulingPriority adjusts the nice value and I/O
+// priority of a process that has just been started. On this operating
+// system this functionality is not available.
+func setProcessSchedulingPriority(pid int, schedulingPriority *SchedulingPriority) error {
+	return status.Error(codes.Unimplemented, "Adjusting scheduling priorities is not supported on this platform")
+}
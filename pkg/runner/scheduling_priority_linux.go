@@ -0,0 +1,32 @@
+//go:build linux
+// +build linux
+
+package runner
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// ioprioWhoProcess corresponds to IOPRIO_WHO_PROCESS, which causes
+// ioprio_set() to apply to a single process.
+const ioprioWhoProcess = 1
+
+// ioprioClassShift corresponds to IOPRIO_CLASS_SHIFT, which is the
+// number of bits by which the I/O scheduling class is shifted when
+// combined with the priority level.
+const ioprioClassShift = 13
+
+// setProcessSchedulingPriority adjusts the nice value and I/O
+// priority of a process that has just been started.
+func setProcessSchedulingPriority(pid int, schedulingPriority *SchedulingPriority) error {
+	if err := unix.Setpriority(unix.PRIO_PROCESS, pid, schedulingPriority.Nice); err != nil {
+		return err
+	}
+	if schedulingPriority.IOPriorityClass != 0 {
+		ioprio := schedulingPriority.IOPriorityClass<<ioprioClassShift | schedulingPriority.IOPriorityLevel
+		if _, _, errno := unix.Syscall(unix.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(pid), uintptr(ioprio)); errno != 0 {
+			return errno
+		}
+	}
+	return nil
+}
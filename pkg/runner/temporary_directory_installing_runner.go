@@ -45,3 +45,7 @@ func (r *temporaryDirectoryInstallingRunner) CheckReadiness(ctx context.Context,
 	}
 	return r.base.CheckReadiness(ctx, request)
 }
+
+func (r *temporaryDirectoryInstallingRunner) GetProcessTree(ctx context.Context, request *runner_pb.GetProcessTreeRequest) (*runner_pb.GetProcessTreeResponse, error) {
+	return r.base.GetProcessTree(ctx, request)
+}
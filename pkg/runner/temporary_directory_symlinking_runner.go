@@ -86,3 +86,7 @@ func (r *temporaryDirectorySymlinkingRunner) CheckReadiness(ctx context.Context,
 
 	return r.base.CheckReadiness(ctx, request)
 }
+
+func (r *temporaryDirectorySymlinkingRunner) GetProcessTree(ctx context.Context, request *runner_pb.GetProcessTreeRequest) (*runner_pb.GetProcessTreeResponse, error) {
+	return r.base.GetProcessTree(ctx, request)
+}
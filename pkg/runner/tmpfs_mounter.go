@@ -0,0 +1,11 @@
+package runner
+
+// TmpfsMounter can be used to mount instances of tmpfs having an
+// explicit size limit into an action's input root.
+type TmpfsMounter interface {
+	// MountTmpfs mounts a tmpfs at target, whose size is limited to
+	// a given number of bytes. The target path must be absolute.
+	// The file system can be removed by calling
+	// Directory.Unmount().
+	MountTmpfs(target string, sizeBytes uint64) error
+}
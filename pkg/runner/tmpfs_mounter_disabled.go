@@ -0,0 +1,16 @@
+//go:build !linux
+// +build !linux
+
+package runner
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewLocalTmpfsMounter creates a TmpfsMounter that mounts instances of
+// tmpfs using the mount() system call. On this operating system this
+// functionality is not available.
+func NewLocalTmpfsMounter() (TmpfsMounter, error) {
+	return nil, status.Error(codes.Unimplemented, "Mounting tmpfs is not supported on this platform")
+}
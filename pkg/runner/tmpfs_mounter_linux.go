@@ -0,0 +1,29 @@
+//go:build linux
+// +build linux
+
+package runner
+
+import (
+	"strconv"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sys/unix"
+
+	"google.golang.org/grpc/codes"
+)
+
+type localTmpfsMounter struct{}
+
+// NewLocalTmpfsMounter creates a TmpfsMounter that mounts instances of
+// tmpfs using the mount() system call.
+func NewLocalTmpfsMounter() (TmpfsMounter, error) {
+	return localTmpfsMounter{}, nil
+}
+
+func (localTmpfsMounter) MountTmpfs(target string, sizeBytes uint64) error {
+	if err := unix.Mount("tmpfs", target, "tmpfs", unix.MS_NOEXEC|unix.MS_NOSUID|unix.MS_NODEV, "size="+strconv.FormatUint(sizeBytes, 10)); err != nil {
+		return util.StatusWrapfWithCode(err, codes.Internal, "Failed to mount tmpfs at %#v", target)
+	}
+	return nil
+}
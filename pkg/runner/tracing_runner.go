@@ -0,0 +1,134 @@
+package runner
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// TracePlatformPropertyName is the name of the platform property that
+// may be used by actions to request that they are run under a tracer.
+const TracePlatformPropertyName = "trace"
+
+var traceFileName = path.MustNewComponent("trace")
+
+type tracingRunner struct {
+	base                 runner_pb.RunnerServer
+	buildDirectoryPath   *path.Builder
+	tracerArguments      []string
+	clock                clock.Clock
+	maximumTracedActions int
+	rateLimitInterval    time.Duration
+
+	lock        sync.Mutex
+	recentTimes []time.Time
+}
+
+// NewTracingRunner creates a decorator for Runner that runs commands
+// under a tracer (e.g., strace) if they set platform property "trace"
+// to "true". The trace is written into the server logs directory of
+// the command, and is reported in the form of a TraceResourceUsage
+// message.
+//
+// As tracing is expensive, the number of commands that may be traced
+// within a given interval is limited. Commands exceeding this limit
+// are run without being traced.
+func NewTracingRunner(base runner_pb.RunnerServer, buildDirectoryPath *path.Builder, tracerArguments []string, clock clock.Clock, maximumTracedActions int, rateLimitInterval time.Duration) runner_pb.RunnerServer {
+	return &tracingRunner{
+		base:                 base,
+		buildDirectoryPath:   buildDirectoryPath,
+		tracerArguments:      tracerArguments,
+		clock:                clock,
+		maximumTracedActions: maximumTracedActions,
+		rateLimitInterval:    rateLimitInterval,
+	}
+}
+
+// tryAcquire returns true if the rate limit permits tracing another
+// command.
+func (r *tracingRunner) tryAcquire() bool {
+	now := r.clock.Now()
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	cutoff := now.Add(-r.rateLimitInterval)
+	i := 0
+	for i < len(r.recentTimes) && !r.recentTimes[i].After(cutoff) {
+		i++
+	}
+	r.recentTimes = r.recentTimes[i:]
+	if len(r.recentTimes) >= r.maximumTracedActions {
+		return false
+	}
+	r.recentTimes = append(r.recentTimes, now)
+	return true
+}
+
+func (r *tracingRunner) Run(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+	traceRequested := false
+	for _, property := range request.Platform.GetProperties() {
+		if property.Name == TracePlatformPropertyName && property.Value == "true" {
+			traceRequested = true
+		}
+	}
+	if !traceRequested || request.ServerLogsDirectory == "" {
+		return r.base.Run(ctx, request)
+	}
+
+	var traceResourceUsage resourceusage.TraceResourceUsage
+	var response *runner_pb.RunResponse
+	if r.tryAcquire() {
+		// Compute the absolute path of the trace file, and
+		// prefix the arguments of the command with the tracer.
+		serverLogsDirectoryPath, scopeWalker := r.buildDirectoryPath.Join(path.VoidScopeWalker)
+		if err := path.Resolve(request.ServerLogsDirectory, scopeWalker); err != nil {
+			return nil, util.StatusWrap(err, "Failed to resolve server logs directory")
+		}
+		arguments := make([]string, 0, len(r.tracerArguments)+1+len(request.Arguments))
+		arguments = append(arguments, r.tracerArguments...)
+		arguments = append(arguments, serverLogsDirectoryPath.String()+"/"+traceFileName.String())
+		arguments = append(arguments, request.Arguments...)
+
+		newRequest := proto.Clone(request).(*runner_pb.RunRequest)
+		newRequest.Arguments = arguments
+		var err error
+		response, err = r.base.Run(ctx, newRequest)
+		if err != nil {
+			return nil, err
+		}
+		traceResourceUsage.ServerLogName = traceFileName.String()
+	} else {
+		var err error
+		response, err = r.base.Run(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		traceResourceUsage.RateLimited = true
+	}
+
+	resourceUsage, err := anypb.New(&traceResourceUsage)
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to marshal trace resource usage")
+	}
+	response.ResourceUsage = append(response.ResourceUsage, resourceUsage)
+	return response, nil
+}
+
+func (r *tracingRunner) CheckReadiness(ctx context.Context, request *runner_pb.CheckReadinessRequest) (*emptypb.Empty, error) {
+	return r.base.CheckReadiness(ctx, request)
+}
+
+func (r *tracingRunner) GetProcessTree(ctx context.Context, request *runner_pb.GetProcessTreeRequest) (*runner_pb.GetProcessTreeResponse, error) {
+	return r.base.GetProcessTree(ctx, request)
+}
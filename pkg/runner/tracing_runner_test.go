@@ -0,0 +1,102 @@
+package runner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestTracingRunner(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseRunner := mock.NewMockRunnerServer(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	buildDirectoryPath, scopeWalker := path.EmptyBuilder.Join(path.NewAbsoluteScopeWalker(path.VoidComponentWalker))
+	require.NoError(t, path.Resolve("/worker/build", scopeWalker))
+	runnerServer := runner.NewTracingRunner(baseRunner, buildDirectoryPath, []string{"/usr/bin/strace", "-f", "-o"}, clock, 1, time.Minute)
+
+	t.Run("NotRequested", func(t *testing.T) {
+		// Commands that don't request tracing should be run
+		// as is.
+		request := &runner_pb.RunRequest{
+			Arguments:           []string{"cc", "-c", "hello.c"},
+			ServerLogsDirectory: "0000000001/server_logs",
+		}
+		baseRunner.EXPECT().Run(ctx, request).Return(&runner_pb.RunResponse{}, nil)
+
+		response, err := runnerServer.Run(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &runner_pb.RunResponse{}, response)
+	})
+
+	request := &runner_pb.RunRequest{
+		Arguments:           []string{"cc", "-c", "hello.c"},
+		ServerLogsDirectory: "0000000001/server_logs",
+		Platform: &remoteexecution.Platform{
+			Properties: []*remoteexecution.Platform_Property{
+				{Name: "trace", Value: "true"},
+			},
+		},
+	}
+
+	t.Run("Traced", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		baseRunner.EXPECT().Run(ctx, testutil.EqProto(t, &runner_pb.RunRequest{
+			Arguments:           []string{"/usr/bin/strace", "-f", "-o", "/worker/build/0000000001/server_logs/trace", "cc", "-c", "hello.c"},
+			ServerLogsDirectory: "0000000001/server_logs",
+			Platform: &remoteexecution.Platform{
+				Properties: []*remoteexecution.Platform_Property{
+					{Name: "trace", Value: "true"},
+				},
+			},
+		})).Return(&runner_pb.RunResponse{ExitCode: 1}, nil)
+
+		response, err := runnerServer.Run(ctx, request)
+		require.NoError(t, err)
+		resourceUsage, err := anypb.New(&resourceusage.TraceResourceUsage{
+			ServerLogName: "trace",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &runner_pb.RunResponse{
+			ExitCode:      1,
+			ResourceUsage: []*anypb.Any{resourceUsage},
+		}, response)
+	})
+
+	t.Run("RateLimited", func(t *testing.T) {
+		// Only a single command may be traced per minute.
+		clock.EXPECT().Now().Return(time.Unix(1030, 0))
+		baseRunner.EXPECT().Run(ctx, request).Return(&runner_pb.RunResponse{}, nil)
+
+		response, err := runnerServer.Run(ctx, request)
+		require.NoError(t, err)
+		resourceUsage, err := anypb.New(&resourceusage.TraceResourceUsage{
+			RateLimited: true,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &runner_pb.RunResponse{
+			ResourceUsage: []*anypb.Any{resourceUsage},
+		}, response)
+	})
+
+	t.Run("RateLimitExpired", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1061, 0))
+		baseRunner.EXPECT().Run(ctx, gomock.Any()).Return(&runner_pb.RunResponse{}, nil)
+
+		response, err := runnerServer.Run(ctx, request)
+		require.NoError(t, err)
+		require.Len(t, response.ResourceUsage, 1)
+	})
+}
@@ -0,0 +1,160 @@
+package runner
+
+import (
+	"context"
+	"sync"
+
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// VirtualMachine is a handle to a running virtual machine that runs an
+// instance of bb_runner. The build directory is expected to be shared
+// with the virtual machine (e.g., using virtio-fs), so that paths
+// contained in requests can be forwarded to it without modification.
+type VirtualMachine interface {
+	runner_pb.RunnerClient
+
+	Terminate() error
+}
+
+// VirtualMachineLauncher is called into by the RunnerServer returned
+// by NewVirtualMachineRunner() to launch a new virtual machine.
+type VirtualMachineLauncher func(ctx context.Context) (VirtualMachine, error)
+
+type virtualMachineRunner struct {
+	launcher VirtualMachineLauncher
+
+	lock            sync.Mutex
+	virtualMachines map[string]VirtualMachine
+	hasLaunched     bool
+	lastLaunchErr   error
+
+	readinessProbeLock sync.Mutex
+}
+
+// NewVirtualMachineRunner creates a RunnerServer that launches a
+// short-lived virtual machine for every action, forwarding the request
+// to an instance of bb_runner running inside of it. The virtual machine
+// is terminated once the action completes.
+//
+// This can be used to run actions with a higher degree of isolation
+// than what can be provided by containers, as no kernel state is
+// shared between actions.
+func NewVirtualMachineRunner(launcher VirtualMachineLauncher) runner_pb.RunnerServer {
+	return &virtualMachineRunner{
+		launcher:        launcher,
+		virtualMachines: map[string]VirtualMachine{},
+	}
+}
+
+func (r *virtualMachineRunner) launch(ctx context.Context) (VirtualMachine, error) {
+	vm, err := r.launcher(ctx)
+	if err != nil {
+		err = util.StatusWrap(err, "Failed to launch virtual machine")
+	}
+
+	// Retain the outcome of the launch, so that readiness checks
+	// can be performed without launching virtual machines.
+	if status.Code(err) != codes.Canceled {
+		r.lock.Lock()
+		r.hasLaunched = true
+		r.lastLaunchErr = err
+		r.lock.Unlock()
+	}
+	return vm, err
+}
+
+func terminateVirtualMachine(vm VirtualMachine, err error) error {
+	if terminateErr := vm.Terminate(); terminateErr != nil && err == nil {
+		return util.StatusWrap(terminateErr, "Failed to terminate virtual machine")
+	}
+	return err
+}
+
+// getReadinessState returns a virtual machine against which readiness
+// checks may be performed, or the outcome of the most recent attempt
+// to launch a virtual machine.
+func (r *virtualMachineRunner) getReadinessState() (VirtualMachine, bool, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, vm := range r.virtualMachines {
+		return vm, true, nil
+	}
+	return nil, r.hasLaunched, r.lastLaunchErr
+}
+
+func (r *virtualMachineRunner) CheckReadiness(ctx context.Context, request *runner_pb.CheckReadinessRequest) (*emptypb.Empty, error) {
+	// Readiness checks are performed frequently. Instead of
+	// launching a virtual machine for every check, forward the
+	// check to a virtual machine that is running an action, or
+	// report the outcome of the most recent launch.
+	if vm, hasLaunched, err := r.getReadinessState(); vm != nil {
+		return vm.CheckReadiness(ctx, request)
+	} else if hasLaunched {
+		if err != nil {
+			return nil, err
+		}
+		return &emptypb.Empty{}, nil
+	}
+
+	// No virtual machine has been launched yet. Launch one to
+	// validate that the launcher and the image of the virtual
+	// machine are functional. Prevent concurrent checks from
+	// launching multiple virtual machines.
+	r.readinessProbeLock.Lock()
+	defer r.readinessProbeLock.Unlock()
+	if _, hasLaunched, err := r.getReadinessState(); hasLaunched {
+		if err != nil {
+			return nil, err
+		}
+		return &emptypb.Empty{}, nil
+	}
+
+	vm, err := r.launch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	response, err := vm.CheckReadiness(ctx, request)
+	if err = terminateVirtualMachine(vm, err); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (r *virtualMachineRunner) Run(ctx context.Context, request *runner_pb.RunRequest) (*runner_pb.RunResponse, error) {
+	vm, err := r.launch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep track of the virtual machine, so that calls to
+	// GetProcessTree() can be forwarded to it.
+	r.lock.Lock()
+	r.virtualMachines[request.InputRootDirectory] = vm
+	r.lock.Unlock()
+	response, err := vm.Run(ctx, request)
+	r.lock.Lock()
+	delete(r.virtualMachines, request.InputRootDirectory)
+	r.lock.Unlock()
+
+	if err = terminateVirtualMachine(vm, err); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (r *virtualMachineRunner) GetProcessTree(ctx context.Context, request *runner_pb.GetProcessTreeRequest) (*runner_pb.GetProcessTreeResponse, error) {
+	r.lock.Lock()
+	vm, ok := r.virtualMachines[request.InputRootDirectory]
+	r.lock.Unlock()
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "No virtual machine is running a command in input root directory %#v", request.InputRootDirectory)
+	}
+	return vm.GetProcessTree(ctx, request)
+}
@@ -0,0 +1,166 @@
+package runner_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	runner_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/runner"
+	"github.com/buildbarn/bb-remote-execution/pkg/runner"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestVirtualMachineRunner(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	launcher := mock.NewMockVirtualMachineLauncher(ctrl)
+	runnerServer := runner.NewVirtualMachineRunner(launcher.Call)
+
+	runRequest := &runner_pb.RunRequest{
+		Arguments:          []string{"cc", "-o", "hello.o", "hello.c"},
+		InputRootDirectory: "1/root",
+	}
+
+	t.Run("LaunchFailure", func(t *testing.T) {
+		launcher.EXPECT().Call(ctx).Return(nil, status.Error(codes.ResourceExhausted, "Out of quota"))
+
+		_, err := runnerServer.Run(ctx, runRequest)
+		testutil.RequireEqualStatus(t, status.Error(codes.ResourceExhausted, "Failed to launch virtual machine: Out of quota"), err)
+	})
+
+	t.Run("RunFailure", func(t *testing.T) {
+		// Even if running the action fails, the virtual
+		// machine should be terminated.
+		vm := mock.NewMockVirtualMachine(ctrl)
+		launcher.EXPECT().Call(ctx).Return(vm, nil)
+		vm.EXPECT().Run(ctx, runRequest).Return(nil, status.Error(codes.Unavailable, "Connection refused"))
+		vm.EXPECT().Terminate()
+
+		_, err := runnerServer.Run(ctx, runRequest)
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Connection refused"), err)
+	})
+
+	t.Run("TerminateFailure", func(t *testing.T) {
+		// Failing to terminate the virtual machine should be
+		// reported, as it may leak resources.
+		vm := mock.NewMockVirtualMachine(ctrl)
+		launcher.EXPECT().Call(ctx).Return(vm, nil)
+		vm.EXPECT().Run(ctx, runRequest).Return(&runner_pb.RunResponse{ExitCode: 0}, nil)
+		vm.EXPECT().Terminate().Return(status.Error(codes.Internal, "Launch command failed: exit status 1"))
+
+		_, err := runnerServer.Run(ctx, runRequest)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to terminate virtual machine: Launch command failed: exit status 1"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		vm := mock.NewMockVirtualMachine(ctrl)
+		launcher.EXPECT().Call(ctx).Return(vm, nil)
+		vm.EXPECT().Run(ctx, runRequest).Return(&runner_pb.RunResponse{ExitCode: 1}, nil)
+		vm.EXPECT().Terminate()
+
+		response, err := runnerServer.Run(ctx, runRequest)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &runner_pb.RunResponse{ExitCode: 1}, response)
+	})
+}
+
+func TestVirtualMachineRunnerCheckReadiness(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	launcher := mock.NewMockVirtualMachineLauncher(ctrl)
+	runnerServer := runner.NewVirtualMachineRunner(launcher.Call)
+
+	checkReadinessRequest := &runner_pb.CheckReadinessRequest{
+		Path: "readiness_check",
+	}
+	runRequest := &runner_pb.RunRequest{
+		Arguments:          []string{"cc", "-o", "hello.o", "hello.c"},
+		InputRootDirectory: "1/root",
+	}
+
+	t.Run("InitialLaunch", func(t *testing.T) {
+		// As no virtual machines have been launched yet, the
+		// first readiness check should launch one.
+		vm := mock.NewMockVirtualMachine(ctrl)
+		launcher.EXPECT().Call(ctx).Return(vm, nil)
+		vm.EXPECT().CheckReadiness(ctx, checkReadinessRequest).Return(&emptypb.Empty{}, nil)
+		vm.EXPECT().Terminate()
+
+		_, err := runnerServer.CheckReadiness(ctx, checkReadinessRequest)
+		require.NoError(t, err)
+	})
+
+	t.Run("CachedSuccess", func(t *testing.T) {
+		// Successive readiness checks should not launch any
+		// virtual machines.
+		_, err := runnerServer.CheckReadiness(ctx, checkReadinessRequest)
+		require.NoError(t, err)
+	})
+
+	t.Run("RunningVirtualMachine", func(t *testing.T) {
+		// While an action is running, readiness checks should
+		// be forwarded to its virtual machine.
+		vm := mock.NewMockVirtualMachine(ctrl)
+		launcher.EXPECT().Call(ctx).Return(vm, nil)
+		vm.EXPECT().Run(ctx, runRequest).DoAndReturn(
+			func(ctx context.Context, request *runner_pb.RunRequest, opts ...grpc.CallOption) (*runner_pb.RunResponse, error) {
+				_, err := runnerServer.CheckReadiness(ctx, checkReadinessRequest)
+				testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Runner not ready"), err)
+				return &runner_pb.RunResponse{ExitCode: 0}, nil
+			})
+		vm.EXPECT().CheckReadiness(ctx, checkReadinessRequest).Return(nil, status.Error(codes.Unavailable, "Runner not ready"))
+		vm.EXPECT().Terminate()
+
+		_, err := runnerServer.Run(ctx, runRequest)
+		require.NoError(t, err)
+	})
+
+	t.Run("CachedFailure", func(t *testing.T) {
+		// If the most recent launch failed, readiness checks
+		// should fail as well.
+		launcher.EXPECT().Call(ctx).Return(nil, status.Error(codes.ResourceExhausted, "Out of quota"))
+
+		_, err := runnerServer.Run(ctx, runRequest)
+		testutil.RequireEqualStatus(t, status.Error(codes.ResourceExhausted, "Failed to launch virtual machine: Out of quota"), err)
+
+		_, err = runnerServer.CheckReadiness(ctx, checkReadinessRequest)
+		testutil.RequireEqualStatus(t, status.Error(codes.ResourceExhausted, "Failed to launch virtual machine: Out of quota"), err)
+	})
+}
+
+func TestCommandVirtualMachineLauncher(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	ctx := context.Background()
+
+	t.Run("NoAddress", func(t *testing.T) {
+		// Launch commands that terminate without reporting an
+		// address should be treated as failures.
+		_, err := runner.NewCommandVirtualMachineLauncher("/bin/sh", []string{"-c", "exit 0"})(ctx)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Launch command did not report the address of the virtual machine"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The launch command should be able to write additional
+		// output after reporting the address. It should
+		// terminate once its standard input is closed.
+		vm, err := runner.NewCommandVirtualMachineLauncher("/bin/sh", []string{"-c", "echo 127.0.0.1:1; echo Booted; cat > /dev/null; echo Shut down"})(ctx)
+		require.NoError(t, err)
+		require.NoError(t, vm.Terminate())
+	})
+
+	t.Run("TerminateFailure", func(t *testing.T) {
+		vm, err := runner.NewCommandVirtualMachineLauncher("/bin/sh", []string{"-c", "echo 127.0.0.1:1; cat > /dev/null; exit 1"})(ctx)
+		require.NoError(t, err)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Launch command failed: exit status 1"), vm.Terminate())
+	})
+}
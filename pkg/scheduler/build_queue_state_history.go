@@ -0,0 +1,184 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/buildqueuestate"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// BuildQueueStateSample contains the utilization of a single size
+// class queue at a given point in time.
+type BuildQueueStateSample struct {
+	Timestamp             time.Time `json:"timestamp"`
+	QueuedOperationsCount uint32    `json:"queued_operations_count"`
+	ExecutingWorkersCount uint32    `json:"executing_workers_count"`
+	WorkersCount          uint32    `json:"workers_count"`
+	// The average amount of time tasks that were dispatched to
+	// workers since the previous sample spent in the QUEUED
+	// stage. Zero if no tasks were dispatched.
+	DispatchLatencySeconds float64 `json:"dispatch_latency_seconds"`
+}
+
+// GetWorkerUtilization returns the fraction of workers that was
+// executing a task at the time the sample was taken.
+func (s *BuildQueueStateSample) GetWorkerUtilization() float64 {
+	if s.WorkersCount == 0 {
+		return 0
+	}
+	return float64(s.ExecutingWorkersCount) / float64(s.WorkersCount)
+}
+
+// SizeClassQueueHistory contains the samples that were taken for a
+// single size class queue, in chronological order.
+type SizeClassQueueHistory struct {
+	Name    *buildqueuestate.SizeClassQueueName
+	Samples []BuildQueueStateSample
+}
+
+type sizeClassQueueHistory struct {
+	name *buildqueuestate.SizeClassQueueName
+
+	// Ring buffer of samples. Once the buffer is full, samples
+	// are overwritten starting at index firstSample.
+	samples     []BuildQueueStateSample
+	firstSample int
+
+	// The values of the cumulative counters at the time the
+	// previous sample was taken, used to compute the dispatch
+	// latency.
+	lastDispatchedTasksCount          uint64
+	lastDispatchedTasksQueuedDuration time.Duration
+}
+
+// BuildQueueStateHistory periodically samples the utilization of all
+// size class queues of a build queue, retaining a fixed number of
+// samples per size class queue. This allows displaying time series of
+// queue lengths, worker utilization and dispatch latency, without
+// depending on an external monitoring system.
+type BuildQueueStateHistory struct {
+	buildQueue        buildqueuestate.BuildQueueStateServer
+	clock             clock.Clock
+	maximumSamples    int
+	lock              sync.Mutex
+	sizeClassQueues   map[string]*sizeClassQueueHistory
+	sizeClassQueueIDs []string
+}
+
+// NewBuildQueueStateHistory creates a BuildQueueStateHistory that
+// obtains the state of a build queue through its BuildQueueState
+// service. For every size class queue, up to maximumSamples samples
+// are retained.
+func NewBuildQueueStateHistory(buildQueue buildqueuestate.BuildQueueStateServer, clock clock.Clock, maximumSamples int) *BuildQueueStateHistory {
+	return &BuildQueueStateHistory{
+		buildQueue:      buildQueue,
+		clock:           clock,
+		maximumSamples:  maximumSamples,
+		sizeClassQueues: map[string]*sizeClassQueueHistory{},
+	}
+}
+
+// RecordSample takes a sample of the utilization of all size class
+// queues. History of size class queues that no longer exist is
+// discarded.
+func (h *BuildQueueStateHistory) RecordSample(ctx context.Context) error {
+	now := h.clock.Now()
+	platformQueues, err := h.buildQueue.ListPlatformQueues(ctx, &emptypb.Empty{})
+	if err != nil {
+		return util.StatusWrap(err, "Failed to list platform queues")
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	sizeClassQueues := make(map[string]*sizeClassQueueHistory, len(h.sizeClassQueues))
+	var sizeClassQueueIDs []string
+	for _, platformQueue := range platformQueues.PlatformQueues {
+		for _, sizeClassQueue := range platformQueue.SizeClassQueues {
+			name := &buildqueuestate.SizeClassQueueName{
+				PlatformQueueName: platformQueue.Name,
+				SizeClass:         sizeClassQueue.SizeClass,
+			}
+			id := protojson.Format(name)
+			history, ok := h.sizeClassQueues[id]
+			if !ok {
+				history = &sizeClassQueueHistory{
+					name:                              name,
+					lastDispatchedTasksCount:          sizeClassQueue.DispatchedTasksCount,
+					lastDispatchedTasksQueuedDuration: sizeClassQueue.DispatchedTasksQueuedDuration.AsDuration(),
+				}
+			}
+			sizeClassQueues[id] = history
+			sizeClassQueueIDs = append(sizeClassQueueIDs, id)
+
+			sample := BuildQueueStateSample{
+				Timestamp:             now,
+				QueuedOperationsCount: sizeClassQueue.RootInvocation.GetQueuedOperationsCount(),
+				ExecutingWorkersCount: sizeClassQueue.RootInvocation.GetExecutingWorkersCount(),
+				WorkersCount:          sizeClassQueue.WorkersCount,
+			}
+			dispatchedTasksQueuedDuration := sizeClassQueue.DispatchedTasksQueuedDuration.AsDuration()
+			if dispatchedTasksCount := sizeClassQueue.DispatchedTasksCount; dispatchedTasksCount > history.lastDispatchedTasksCount {
+				sample.DispatchLatencySeconds = (dispatchedTasksQueuedDuration - history.lastDispatchedTasksQueuedDuration).Seconds() /
+					float64(dispatchedTasksCount-history.lastDispatchedTasksCount)
+			}
+			history.lastDispatchedTasksCount = sizeClassQueue.DispatchedTasksCount
+			history.lastDispatchedTasksQueuedDuration = dispatchedTasksQueuedDuration
+
+			if len(history.samples) < h.maximumSamples {
+				history.samples = append(history.samples, sample)
+			} else {
+				history.samples[history.firstSample] = sample
+				history.firstSample = (history.firstSample + 1) % len(history.samples)
+			}
+		}
+	}
+	h.sizeClassQueues = sizeClassQueues
+	h.sizeClassQueueIDs = sizeClassQueueIDs
+	return nil
+}
+
+// RecordSamplesPeriodically takes samples at a fixed interval, until
+// the provided context is canceled. Failures to take samples are
+// logged, but are not fatal.
+func (h *BuildQueueStateHistory) RecordSamplesPeriodically(ctx context.Context, interval time.Duration, errorLogger util.ErrorLogger) {
+	for {
+		timer, timerChannel := h.clock.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timerChannel:
+		}
+
+		if err := h.RecordSample(ctx); err != nil && ctx.Err() == nil {
+			errorLogger.Log(util.StatusWrap(err, "Failed to record build queue state sample"))
+		}
+	}
+}
+
+// GetHistory returns the samples of all size class queues, in the
+// order in which ListPlatformQueues() returned them.
+func (h *BuildQueueStateHistory) GetHistory() []SizeClassQueueHistory {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	histories := make([]SizeClassQueueHistory, 0, len(h.sizeClassQueueIDs))
+	for _, id := range h.sizeClassQueueIDs {
+		history := h.sizeClassQueues[id]
+		samples := make([]BuildQueueStateSample, 0, len(history.samples))
+		samples = append(samples, history.samples[history.firstSample:]...)
+		samples = append(samples, history.samples[:history.firstSample]...)
+		histories = append(histories, SizeClassQueueHistory{
+			Name:    history.name,
+			Samples: samples,
+		})
+	}
+	return histories
+}
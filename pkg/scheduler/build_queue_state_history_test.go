@@ -0,0 +1,155 @@
+package scheduler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/buildqueuestate"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestBuildQueueStateHistory(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	buildQueue := mock.NewMockBuildQueueStateServer(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	history := scheduler.NewBuildQueueStateHistory(buildQueue, clock, 2)
+
+	platformQueueName := &buildqueuestate.PlatformQueueName{
+		InstanceNamePrefix: "main",
+		Platform: &remoteexecution.Platform{
+			Properties: []*remoteexecution.Platform_Property{
+				{Name: "os", Value: "linux"},
+			},
+		},
+	}
+	getResponse := func(queuedOperationsCount, executingWorkersCount, workersCount uint32, dispatchedTasksCount uint64, dispatchedTasksQueuedDuration time.Duration) *buildqueuestate.ListPlatformQueuesResponse {
+		return &buildqueuestate.ListPlatformQueuesResponse{
+			PlatformQueues: []*buildqueuestate.PlatformQueueState{{
+				Name: platformQueueName,
+				SizeClassQueues: []*buildqueuestate.SizeClassQueueState{{
+					SizeClass: 4,
+					RootInvocation: &buildqueuestate.InvocationState{
+						QueuedOperationsCount: queuedOperationsCount,
+						ExecutingWorkersCount: executingWorkersCount,
+					},
+					WorkersCount:                  workersCount,
+					DispatchedTasksCount:          dispatchedTasksCount,
+					DispatchedTasksQueuedDuration: durationpb.New(dispatchedTasksQueuedDuration),
+				}},
+			}},
+		}
+	}
+	sizeClassQueueName := &buildqueuestate.SizeClassQueueName{
+		PlatformQueueName: platformQueueName,
+		SizeClass:         4,
+	}
+
+	t.Run("ListPlatformQueuesFailure", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		buildQueue.EXPECT().ListPlatformQueues(ctx, testutil.EqProto(t, &emptypb.Empty{})).
+			Return(nil, status.Error(codes.Unavailable, "Scheduler is shutting down"))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Unavailable, "Failed to list platform queues: Scheduler is shutting down"),
+			history.RecordSample(ctx))
+		require.Empty(t, history.GetHistory())
+	})
+
+	t.Run("InitialSample", func(t *testing.T) {
+		// The dispatch latency cannot be computed for the first
+		// sample, as the counters are cumulative.
+		clock.EXPECT().Now().Return(time.Unix(1001, 0))
+		buildQueue.EXPECT().ListPlatformQueues(ctx, testutil.EqProto(t, &emptypb.Empty{})).
+			Return(getResponse(5, 3, 4, 10, 100*time.Second), nil)
+
+		require.NoError(t, history.RecordSample(ctx))
+		histories := history.GetHistory()
+		require.Len(t, histories, 1)
+		testutil.RequireEqualProto(t, sizeClassQueueName, histories[0].Name)
+		require.Equal(t, []scheduler.BuildQueueStateSample{
+			{
+				Timestamp:             time.Unix(1001, 0),
+				QueuedOperationsCount: 5,
+				ExecutingWorkersCount: 3,
+				WorkersCount:          4,
+			},
+		}, histories[0].Samples)
+		require.Equal(t, 0.75, histories[0].Samples[0].GetWorkerUtilization())
+	})
+
+	t.Run("DispatchLatency", func(t *testing.T) {
+		// Two tasks got dispatched since the previous sample,
+		// having spent 12 seconds in the queue in total.
+		clock.EXPECT().Now().Return(time.Unix(1011, 0))
+		buildQueue.EXPECT().ListPlatformQueues(ctx, testutil.EqProto(t, &emptypb.Empty{})).
+			Return(getResponse(2, 4, 4, 12, 112*time.Second), nil)
+
+		require.NoError(t, history.RecordSample(ctx))
+		histories := history.GetHistory()
+		require.Len(t, histories, 1)
+		require.Equal(t, []scheduler.BuildQueueStateSample{
+			{
+				Timestamp:             time.Unix(1001, 0),
+				QueuedOperationsCount: 5,
+				ExecutingWorkersCount: 3,
+				WorkersCount:          4,
+			},
+			{
+				Timestamp:              time.Unix(1011, 0),
+				QueuedOperationsCount:  2,
+				ExecutingWorkersCount:  4,
+				WorkersCount:           4,
+				DispatchLatencySeconds: 6,
+			},
+		}, histories[0].Samples)
+	})
+
+	t.Run("OldestSampleDiscarded", func(t *testing.T) {
+		// As at most two samples are retained, adding a third
+		// sample should cause the first one to be discarded.
+		clock.EXPECT().Now().Return(time.Unix(1021, 0))
+		buildQueue.EXPECT().ListPlatformQueues(ctx, testutil.EqProto(t, &emptypb.Empty{})).
+			Return(getResponse(0, 0, 4, 12, 112*time.Second), nil)
+
+		require.NoError(t, history.RecordSample(ctx))
+		histories := history.GetHistory()
+		require.Len(t, histories, 1)
+		require.Equal(t, []scheduler.BuildQueueStateSample{
+			{
+				Timestamp:              time.Unix(1011, 0),
+				QueuedOperationsCount:  2,
+				ExecutingWorkersCount:  4,
+				WorkersCount:           4,
+				DispatchLatencySeconds: 6,
+			},
+			{
+				Timestamp:    time.Unix(1021, 0),
+				WorkersCount: 4,
+			},
+		}, histories[0].Samples)
+	})
+
+	t.Run("SizeClassQueueRemoved", func(t *testing.T) {
+		// History of size class queues that no longer exist
+		// should be discarded.
+		clock.EXPECT().Now().Return(time.Unix(1031, 0))
+		buildQueue.EXPECT().ListPlatformQueues(ctx, testutil.EqProto(t, &emptypb.Empty{})).
+			Return(&buildqueuestate.ListPlatformQueuesResponse{}, nil)
+
+		require.NoError(t, history.RecordSample(ctx))
+		require.Empty(t, history.GetHistory())
+	})
+}
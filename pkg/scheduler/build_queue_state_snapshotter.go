@@ -0,0 +1,164 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/buildqueuestate"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// snapshotPageSize is the number of operations and workers that are
+// requested from the build queue at once while creating a snapshot.
+// Using pagination prevents the build queue from being locked for a
+// long amount of time.
+const snapshotPageSize = 1000
+
+// BuildQueueStateSnapshotter is capable of capturing the full state of
+// a build queue, and writing it into the Content Addressable Storage
+// in the form of a Snapshot message. These snapshots can be used to
+// analyze what was queued and executing after an incident occurred.
+type BuildQueueStateSnapshotter struct {
+	buildQueue                buildqueuestate.BuildQueueStateServer
+	contentAddressableStorage blobstore.BlobAccess
+	digestFunction            digest.Function
+	clock                     clock.Clock
+}
+
+// NewBuildQueueStateSnapshotter creates a BuildQueueStateSnapshotter
+// that obtains the state of a build queue through its BuildQueueState
+// service.
+func NewBuildQueueStateSnapshotter(buildQueue buildqueuestate.BuildQueueStateServer, contentAddressableStorage blobstore.BlobAccess, digestFunction digest.Function, clock clock.Clock) *BuildQueueStateSnapshotter {
+	return &BuildQueueStateSnapshotter{
+		buildQueue:                buildQueue,
+		contentAddressableStorage: contentAddressableStorage,
+		digestFunction:            digestFunction,
+		clock:                     clock,
+	}
+}
+
+// CreateSnapshot captures the state of the build queue and writes it
+// into the Content Addressable Storage. The digest of the snapshot is
+// returned.
+func (s *BuildQueueStateSnapshotter) CreateSnapshot(ctx context.Context) (digest.Digest, error) {
+	snapshot := buildqueuestate.Snapshot{
+		Timestamp: timestamppb.New(s.clock.Now()),
+	}
+
+	platformQueues, err := s.buildQueue.ListPlatformQueues(ctx, &emptypb.Empty{})
+	if err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to list platform queues")
+	}
+	snapshot.PlatformQueues = platformQueues.PlatformQueues
+
+	for _, platformQueue := range platformQueues.PlatformQueues {
+		for _, sizeClassQueue := range platformQueue.SizeClassQueues {
+			sizeClassQueueName := &buildqueuestate.SizeClassQueueName{
+				PlatformQueueName: platformQueue.Name,
+				SizeClass:         sizeClassQueue.SizeClass,
+			}
+			sizeClassQueueSnapshot, err := s.getSizeClassQueueSnapshot(ctx, sizeClassQueueName)
+			if err != nil {
+				return digest.BadDigest, util.StatusWrapf(err, "Failed to capture size class queue with instance name prefix %#v and size class %d", platformQueue.Name.GetInstanceNamePrefix(), sizeClassQueue.SizeClass)
+			}
+			snapshot.SizeClassQueues = append(snapshot.SizeClassQueues, sizeClassQueueSnapshot)
+		}
+	}
+
+	request := &buildqueuestate.ListOperationsRequest{PageSize: snapshotPageSize}
+	for {
+		response, err := s.buildQueue.ListOperations(ctx, request)
+		if err != nil {
+			return digest.BadDigest, util.StatusWrap(err, "Failed to list operations")
+		}
+		snapshot.Operations = append(snapshot.Operations, response.Operations...)
+		if len(response.Operations) < snapshotPageSize {
+			break
+		}
+		request.StartAfter = &buildqueuestate.ListOperationsRequest_StartAfter{
+			OperationName: response.Operations[len(response.Operations)-1].Name,
+		}
+	}
+
+	data, err := proto.Marshal(&snapshot)
+	if err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to marshal snapshot")
+	}
+	digestGenerator := s.digestFunction.NewGenerator(int64(len(data)))
+	if _, err := digestGenerator.Write(data); err != nil {
+		panic(err)
+	}
+	snapshotDigest := digestGenerator.Sum()
+	if err := s.contentAddressableStorage.Put(ctx, snapshotDigest, buffer.NewValidatedBufferFromByteSlice(data)); err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to store snapshot")
+	}
+	return snapshotDigest, nil
+}
+
+func (s *BuildQueueStateSnapshotter) getSizeClassQueueSnapshot(ctx context.Context, sizeClassQueueName *buildqueuestate.SizeClassQueueName) (*buildqueuestate.Snapshot_SizeClassQueue, error) {
+	sizeClassQueueSnapshot := &buildqueuestate.Snapshot_SizeClassQueue{
+		Name: sizeClassQueueName,
+	}
+
+	request := &buildqueuestate.ListWorkersRequest{
+		Filter: &buildqueuestate.ListWorkersRequest_Filter{
+			Type: &buildqueuestate.ListWorkersRequest_Filter_All{
+				All: sizeClassQueueName,
+			},
+		},
+		PageSize: snapshotPageSize,
+	}
+	for {
+		response, err := s.buildQueue.ListWorkers(ctx, request)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to list workers")
+		}
+		sizeClassQueueSnapshot.Workers = append(sizeClassQueueSnapshot.Workers, response.Workers...)
+		if len(response.Workers) < snapshotPageSize {
+			break
+		}
+		request.StartAfter = &buildqueuestate.ListWorkersRequest_StartAfter{
+			WorkerId: response.Workers[len(response.Workers)-1].Id,
+		}
+	}
+
+	drains, err := s.buildQueue.ListDrains(ctx, &buildqueuestate.ListDrainsRequest{
+		SizeClassQueueName: sizeClassQueueName,
+	})
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to list drains")
+	}
+	sizeClassQueueSnapshot.Drains = drains.Drains
+	return sizeClassQueueSnapshot, nil
+}
+
+// CreateSnapshotsPeriodically creates snapshots at a fixed interval,
+// until the provided context is canceled. Digests of snapshots are
+// logged, so that they can be retrieved at a later point in time.
+// Failures to create snapshots are logged as well, but are not fatal.
+func (s *BuildQueueStateSnapshotter) CreateSnapshotsPeriodically(ctx context.Context, interval time.Duration, errorLogger util.ErrorLogger) {
+	for {
+		timer, timerChannel := s.clock.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timerChannel:
+		}
+
+		if snapshotDigest, err := s.CreateSnapshot(ctx); err == nil {
+			log.Printf("Created build queue state snapshot %s", snapshotDigest)
+		} else if ctx.Err() == nil {
+			errorLogger.Log(util.StatusWrap(err, "Failed to create build queue state snapshot"))
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package scheduler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/buildqueuestate"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestBuildQueueStateSnapshotterCreateSnapshot(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	buildQueue := mock.NewMockBuildQueueStateServer(ctrl)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	digestFunction := digest.MustNewFunction("snapshots", remoteexecution.DigestFunction_SHA256)
+	snapshotter := scheduler.NewBuildQueueStateSnapshotter(buildQueue, contentAddressableStorage, digestFunction, clock)
+
+	platformQueueName := &buildqueuestate.PlatformQueueName{
+		InstanceNamePrefix: "main",
+		Platform: &remoteexecution.Platform{
+			Properties: []*remoteexecution.Platform_Property{
+				{Name: "os", Value: "linux"},
+			},
+		},
+	}
+	sizeClassQueueName := &buildqueuestate.SizeClassQueueName{
+		PlatformQueueName: platformQueueName,
+		SizeClass:         4,
+	}
+
+	t.Run("ListPlatformQueuesFailure", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		buildQueue.EXPECT().ListPlatformQueues(ctx, testutil.EqProto(t, &emptypb.Empty{})).
+			Return(nil, status.Error(codes.Unavailable, "Scheduler is shutting down"))
+
+		_, err := snapshotter.CreateSnapshot(ctx)
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Failed to list platform queues: Scheduler is shutting down"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1001, 0))
+		buildQueue.EXPECT().ListPlatformQueues(ctx, testutil.EqProto(t, &emptypb.Empty{})).
+			Return(&buildqueuestate.ListPlatformQueuesResponse{
+				PlatformQueues: []*buildqueuestate.PlatformQueueState{{
+					Name: platformQueueName,
+					SizeClassQueues: []*buildqueuestate.SizeClassQueueState{{
+						SizeClass:    4,
+						WorkersCount: 1,
+						DrainsCount:  1,
+					}},
+				}},
+			}, nil)
+		buildQueue.EXPECT().ListWorkers(ctx, testutil.EqProto(t, &buildqueuestate.ListWorkersRequest{
+			Filter: &buildqueuestate.ListWorkersRequest_Filter{
+				Type: &buildqueuestate.ListWorkersRequest_Filter_All{
+					All: sizeClassQueueName,
+				},
+			},
+			PageSize: 1000,
+		})).Return(&buildqueuestate.ListWorkersResponse{
+			Workers: []*buildqueuestate.WorkerState{{
+				Id: map[string]string{"hostname": "worker1"},
+			}},
+			PaginationInfo: &buildqueuestate.PaginationInfo{TotalEntries: 1},
+		}, nil)
+		buildQueue.EXPECT().ListDrains(ctx, testutil.EqProto(t, &buildqueuestate.ListDrainsRequest{
+			SizeClassQueueName: sizeClassQueueName,
+		})).Return(&buildqueuestate.ListDrainsResponse{
+			Drains: []*buildqueuestate.DrainState{{
+				WorkerIdPattern: map[string]string{"hostname": "worker2"},
+			}},
+		}, nil)
+		buildQueue.EXPECT().ListOperations(ctx, testutil.EqProto(t, &buildqueuestate.ListOperationsRequest{
+			PageSize: 1000,
+		})).Return(&buildqueuestate.ListOperationsResponse{
+			Operations: []*buildqueuestate.OperationState{{
+				Name:  "b7a5a6e4-3d70-4b4b-9bfa-4ebdc3d1e8b4",
+				Stage: &buildqueuestate.OperationState_Queued{Queued: &emptypb.Empty{}},
+			}},
+			PaginationInfo: &buildqueuestate.PaginationInfo{TotalEntries: 1},
+		}, nil)
+
+		var storedDigest digest.Digest
+		contentAddressableStorage.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				storedDigest = blobDigest
+				snapshot, err := b.ToProto(&buildqueuestate.Snapshot{}, 10000)
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, &buildqueuestate.Snapshot{
+					Timestamp: &timestamppb.Timestamp{Seconds: 1001},
+					PlatformQueues: []*buildqueuestate.PlatformQueueState{{
+						Name: platformQueueName,
+						SizeClassQueues: []*buildqueuestate.SizeClassQueueState{{
+							SizeClass:    4,
+							WorkersCount: 1,
+							DrainsCount:  1,
+						}},
+					}},
+					SizeClassQueues: []*buildqueuestate.Snapshot_SizeClassQueue{{
+						Name: sizeClassQueueName,
+						Workers: []*buildqueuestate.WorkerState{{
+							Id: map[string]string{"hostname": "worker1"},
+						}},
+						Drains: []*buildqueuestate.DrainState{{
+							WorkerIdPattern: map[string]string{"hostname": "worker2"},
+						}},
+					}},
+					Operations: []*buildqueuestate.OperationState{{
+						Name:  "b7a5a6e4-3d70-4b4b-9bfa-4ebdc3d1e8b4",
+						Stage: &buildqueuestate.OperationState_Queued{Queued: &emptypb.Empty{}},
+					}},
+				}, snapshot)
+				return nil
+			})
+
+		snapshotDigest, err := snapshotter.CreateSnapshot(ctx)
+		require.NoError(t, err)
+		require.Equal(t, storedDigest, snapshotDigest)
+		require.Equal(t, digest.MustNewInstanceName("snapshots"), snapshotDigest.GetInstanceName())
+	})
+}
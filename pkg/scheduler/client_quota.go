@@ -0,0 +1,194 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	scheduler_invocation "github.com/buildbarn/bb-remote-execution/pkg/scheduler/invocation"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ClientQuota limits the amount of work that a single client may
+// submit to InMemoryBuildQueue. It can be used to prevent untrusted
+// clients from overloading a shared cluster.
+type ClientQuota struct {
+	// MaximumRequestsPerMinute is the maximum number of Execute()
+	// and WaitExecution() requests a client may perform per minute.
+	// Clients may perform short bursts of up to this many requests.
+	// If zero, the rate of requests is not limited.
+	MaximumRequestsPerMinute int
+
+	// MaximumConcurrentOperations is the maximum number of
+	// operations created by a client that may be queued or
+	// executing at the same time. If zero, the number of operations
+	// is not limited.
+	MaximumConcurrentOperations int
+}
+
+// clientQuotaKey identifies the usage of a quota by a single client.
+type clientQuotaKey struct {
+	quota  *ClientQuota
+	client scheduler_invocation.Key
+}
+
+// clientQuotaState tracks the usage of a quota by a single client.
+// Requests per minute are limited by using a token bucket, which is
+// refilled lazily.
+type clientQuotaState struct {
+	key                  clientQuotaKey
+	availableRequests    float64
+	lastRefill           time.Time
+	concurrentOperations int
+
+	// Used to remove the state once no operations are present and
+	// the token bucket has been refilled completely, as it is then
+	// indistinguishable from newly created state.
+	cleanupKey cleanupKey
+}
+
+// getClientQuotaKey returns the key of the quota that applies to a
+// request. As obtaining the quota may call into authorizers, this
+// function must be called without holding the lock.
+func (bq *InMemoryBuildQueue) getClientQuotaKey(ctx context.Context, instanceName digest.InstanceName) (*clientQuotaKey, error) {
+	if bq.configuration.GetClientQuota == nil {
+		return nil, nil
+	}
+	quota := bq.configuration.GetClientQuota(ctx, instanceName)
+	if quota == nil {
+		return nil, nil
+	}
+	client, err := scheduler_invocation.AuthenticationMetadataKeyExtractor.ExtractKey(ctx, nil)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to extract client key for quota")
+	}
+	return &clientQuotaKey{
+		quota:  quota,
+		client: client,
+	}, nil
+}
+
+// consumeClientQuotaRequest charges a single request against the
+// quota of a client, returning the state of the quota, so that
+// operations created by the request may be charged against it as
+// well. Nil is returned if the request is not subject to any quota.
+func (bq *InMemoryBuildQueue) consumeClientQuotaRequest(key *clientQuotaKey) (*clientQuotaState, error) {
+	if key == nil {
+		return nil, nil
+	}
+	cqs, ok := bq.clientQuotaStates[*key]
+	if !ok {
+		cqs = &clientQuotaState{
+			key:               *key,
+			availableRequests: float64(key.quota.MaximumRequestsPerMinute),
+			lastRefill:        bq.now,
+		}
+		bq.clientQuotaStates[*key] = cqs
+	}
+
+	if maximum := key.quota.MaximumRequestsPerMinute; maximum > 0 {
+		cqs.availableRequests += bq.now.Sub(cqs.lastRefill).Minutes() * float64(maximum)
+		if cqs.availableRequests > float64(maximum) {
+			cqs.availableRequests = float64(maximum)
+		}
+		cqs.lastRefill = bq.now
+		if cqs.availableRequests < 1 {
+			cqs.maybeStartCleanup(bq)
+			retryDelay := time.Duration((1 - cqs.availableRequests) / float64(maximum) * float64(time.Minute))
+			return nil, newClientQuotaExceededError(
+				status.Newf(codes.ResourceExhausted, "Client has exceeded its quota of %d Execute() and WaitExecution() requests per minute", maximum),
+				"requests_per_minute",
+				&errdetails.RetryInfo{
+					RetryDelay: durationpb.New(retryDelay),
+				})
+		}
+		cqs.availableRequests--
+	}
+	cqs.maybeStartCleanup(bq)
+	return cqs, nil
+}
+
+// checkConcurrentOperations returns an error if the client has
+// already reached the maximum number of queued or executing
+// operations. It is safe to call this method on a nil receiver.
+func (cqs *clientQuotaState) checkConcurrentOperations() error {
+	if cqs == nil {
+		return nil
+	}
+	if maximum := cqs.key.quota.MaximumConcurrentOperations; maximum > 0 && cqs.concurrentOperations >= maximum {
+		return newClientQuotaExceededError(
+			status.Newf(codes.ResourceExhausted, "Client already has %d queued or executing operations, which is the maximum permitted", cqs.concurrentOperations),
+			"concurrent_operations",
+			nil)
+	}
+	return nil
+}
+
+// maybeStartCleanup schedules the removal of the state of the quota
+// if the client has no queued or executing operations. The state is
+// removed at the time the token bucket would be refilled completely.
+func (cqs *clientQuotaState) maybeStartCleanup(bq *InMemoryBuildQueue) {
+	if cqs.cleanupKey.isActive() {
+		bq.cleanupQueue.remove(cqs.cleanupKey)
+	}
+	if cqs.concurrentOperations == 0 {
+		removalTime := cqs.lastRefill
+		if maximum := cqs.key.quota.MaximumRequestsPerMinute; maximum > 0 {
+			removalTime = removalTime.Add(time.Duration((float64(maximum) - cqs.availableRequests) / float64(maximum) * float64(time.Minute)))
+		}
+		bq.cleanupQueue.add(&cqs.cleanupKey, removalTime, func() {
+			delete(bq.clientQuotaStates, cqs.key)
+		})
+	}
+}
+
+// newClientQuotaExceededError attaches details to an error that is
+// returned when a client has exceeded its quota, so that clients may
+// programmatically determine which quota was exceeded.
+func newClientQuotaExceededError(s *status.Status, subject string, retryInfo *errdetails.RetryInfo) error {
+	sWithDetails, err := s.WithDetails(&errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{{
+			Subject:     subject,
+			Description: s.Message(),
+		}},
+	})
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to attach quota failure to error")
+	}
+	if retryInfo != nil {
+		sWithDetails, err = sWithDetails.WithDetails(retryInfo)
+		if err != nil {
+			return util.StatusWrapWithCode(err, codes.Internal, "Failed to attach retry information to error")
+		}
+	}
+	return sWithDetails.Err()
+}
+
+// acquireClientQuota charges the operation against the quota of the
+// client that created it, until it is completed or removed.
+func (o *operation) acquireClientQuota(bq *InMemoryBuildQueue, cqs *clientQuotaState) {
+	if cqs == nil {
+		return
+	}
+	o.clientQuotaState = cqs
+	cqs.concurrentOperations++
+	cqs.maybeStartCleanup(bq)
+}
+
+// releaseClientQuota stops charging the operation against the quota
+// of the client that created it. It is safe to call this method
+// multiple times.
+func (o *operation) releaseClientQuota(bq *InMemoryBuildQueue) {
+	cqs := o.clientQuotaState
+	if cqs == nil {
+		return
+	}
+	o.clientQuotaState = nil
+	cqs.concurrentOperations--
+	cqs.maybeStartCleanup(bq)
+}
@@ -0,0 +1,308 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/platform"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/builder"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+)
+
+// federatedOperationNameSeparator is placed between the name of a
+// federated cluster and the name of an operation created by that
+// cluster. Names of operations created by InMemoryBuildQueue are UUIDs,
+// meaning that they never contain this separator.
+const federatedOperationNameSeparator = "/"
+
+// FederatedClusterMatcher matches execution requests that should be
+// forwarded to a federated cluster.
+type FederatedClusterMatcher struct {
+	// The instance name of the request must start with this prefix.
+	InstanceNamePrefix digest.InstanceName
+
+	// All of these platform properties must be present in the
+	// platform of the action. If empty, the action is not loaded
+	// from the Content Addressable Storage.
+	PlatformProperties []*remoteexecution.Platform_Property
+}
+
+// FederatedCluster is a downstream scheduler to which
+// FederatingBuildQueue may forward execution requests.
+type FederatedCluster struct {
+	// Name of the cluster, which is prepended to the names of
+	// operations created by the cluster, so that WaitExecution()
+	// requests can be forwarded to the same cluster. It may not
+	// contain slashes.
+	Name string
+
+	BuildQueue builder.BuildQueue
+
+	// Execution requests that match any of these matchers are
+	// forwarded to this cluster.
+	Matchers []FederatedClusterMatcher
+}
+
+type federatingBuildQueue struct {
+	localBuildQueue           builder.BuildQueue
+	clusters                  []FederatedCluster
+	clustersByName            map[string]*FederatedCluster
+	contentAddressableStorage blobstore.BlobAccess
+	maximumMessageSizeBytes   int
+	platformKeyExtractor      platform.KeyExtractor
+}
+
+// NewFederatingBuildQueue creates a BuildQueue that forwards execution
+// requests to one of several downstream scheduler clusters (e.g., one
+// per region or platform family), based on the instance name and the
+// platform properties of the action. This permits clients to use a
+// single endpoint, regardless of where their actions run.
+//
+// Clusters are evaluated in order, and the first cluster having a
+// matching matcher is used. Requests that match none of the clusters
+// are handled by the local build queue. The capabilities reported by
+// the local build queue and all clusters that may serve an instance
+// name are merged.
+func NewFederatingBuildQueue(localBuildQueue builder.BuildQueue, clusters []FederatedCluster, contentAddressableStorage blobstore.BlobAccess, maximumMessageSizeBytes int, platformKeyExtractor platform.KeyExtractor) (builder.BuildQueue, error) {
+	clustersByName := make(map[string]*FederatedCluster, len(clusters))
+	for i := range clusters {
+		cluster := &clusters[i]
+		if cluster.Name == "" || strings.Contains(cluster.Name, federatedOperationNameSeparator) {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid federated cluster name %#v", cluster.Name)
+		}
+		if _, ok := clustersByName[cluster.Name]; ok {
+			return nil, status.Errorf(codes.InvalidArgument, "Multiple federated clusters have name %#v", cluster.Name)
+		}
+		clustersByName[cluster.Name] = cluster
+	}
+	return &federatingBuildQueue{
+		localBuildQueue:           localBuildQueue,
+		clusters:                  clusters,
+		clustersByName:            clustersByName,
+		contentAddressableStorage: contentAddressableStorage,
+		maximumMessageSizeBytes:   maximumMessageSizeBytes,
+		platformKeyExtractor:      platformKeyExtractor,
+	}, nil
+}
+
+func (bq *federatingBuildQueue) GetCapabilities(ctx context.Context, instanceName digest.InstanceName) (*remoteexecution.ServerCapabilities, error) {
+	// Query the local build queue and all clusters that may receive
+	// requests for this instance name in parallel.
+	buildQueues := []builder.BuildQueue{bq.localBuildQueue}
+	for _, cluster := range bq.clusters {
+		for _, matcher := range cluster.Matchers {
+			if instanceNameHasPrefix(instanceName, matcher.InstanceNamePrefix) {
+				buildQueues = append(buildQueues, cluster.BuildQueue)
+				break
+			}
+		}
+	}
+	results := make([]*remoteexecution.ServerCapabilities, len(buildQueues))
+	errs := make([]error, len(buildQueues))
+	group, groupCtx := errgroup.WithContext(ctx)
+	for iIter, buildQueueIter := range buildQueues {
+		i, buildQueue := iIter, buildQueueIter
+		group.Go(func() error {
+			capabilities, err := buildQueue.GetCapabilities(groupCtx, instanceName)
+			switch status.Code(err) {
+			case codes.OK:
+				results[i] = capabilities
+				return nil
+			case codes.InvalidArgument, codes.NotFound, codes.PermissionDenied:
+				// Other clusters may still support
+				// this instance name.
+				errs[i] = err
+				return nil
+			default:
+				return err
+			}
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	// Merge the execution capabilities. Clients can only use
+	// digest functions that are supported by all clusters, as they
+	// cannot predict to which cluster actions are forwarded.
+	var merged *remoteexecution.ExecutionCapabilities
+	for _, result := range results {
+		executionCapabilities := result.GetExecutionCapabilities()
+		if executionCapabilities == nil {
+			continue
+		}
+		if merged == nil {
+			merged = proto.Clone(executionCapabilities).(*remoteexecution.ExecutionCapabilities)
+			continue
+		}
+		merged.ExecEnabled = merged.ExecEnabled || executionCapabilities.ExecEnabled
+		digestFunctions := merged.DigestFunctions[:0]
+		for _, digestFunction := range merged.DigestFunctions {
+			for _, otherDigestFunction := range executionCapabilities.DigestFunctions {
+				if digestFunction == otherDigestFunction {
+					digestFunctions = append(digestFunctions, digestFunction)
+					break
+				}
+			}
+		}
+		merged.DigestFunctions = digestFunctions
+	}
+	if merged == nil {
+		nonNilErrs := make([]error, 0, len(errs))
+		for _, err := range errs {
+			if err != nil {
+				nonNilErrs = append(nonNilErrs, err)
+			}
+		}
+		return nil, util.StatusFromMultiple(nonNilErrs)
+	}
+
+	if len(merged.DigestFunctions) > 0 {
+		// Ensure the preferred digest function is supported by
+		// all clusters.
+		preferredDigestFunctionSupported := false
+		for _, digestFunction := range merged.DigestFunctions {
+			if digestFunction == merged.DigestFunction {
+				preferredDigestFunctionSupported = true
+				break
+			}
+		}
+		if !preferredDigestFunctionSupported {
+			merged.DigestFunction = merged.DigestFunctions[0]
+		}
+	}
+	return &remoteexecution.ServerCapabilities{
+		ExecutionCapabilities: merged,
+	}, nil
+}
+
+// getCluster returns the federated cluster to which an execution
+// request should be forwarded, or nil if it should be handled by the
+// local build queue.
+func (bq *federatingBuildQueue) getCluster(ctx context.Context, in *remoteexecution.ExecuteRequest) (*FederatedCluster, error) {
+	instanceName, err := digest.NewInstanceName(in.InstanceName)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Invalid instance name %#v", in.InstanceName)
+	}
+
+	// Only load the platform properties of the action if needed.
+	var platformProperties []*remoteexecution.Platform_Property
+	platformPropertiesLoaded := false
+	for i := range bq.clusters {
+		cluster := &bq.clusters[i]
+		for _, matcher := range cluster.Matchers {
+			if !instanceNameHasPrefix(instanceName, matcher.InstanceNamePrefix) {
+				continue
+			}
+			if len(matcher.PlatformProperties) > 0 && !platformPropertiesLoaded {
+				platformProperties, err = bq.getPlatformProperties(ctx, instanceName, in)
+				if err != nil {
+					return nil, err
+				}
+				platformPropertiesLoaded = true
+			}
+			if platformPropertiesContain(platformProperties, matcher.PlatformProperties) {
+				return cluster, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// getPlatformProperties loads the action of an execution request from
+// the Content Addressable Storage, and extracts its platform
+// properties.
+func (bq *federatingBuildQueue) getPlatformProperties(ctx context.Context, instanceName digest.InstanceName, in *remoteexecution.ExecuteRequest) ([]*remoteexecution.Platform_Property, error) {
+	digestFunction, err := instanceName.GetDigestFunction(in.DigestFunction, len(in.ActionDigest.GetHash()))
+	if err != nil {
+		return nil, err
+	}
+	actionDigest, err := digestFunction.NewDigestFromProto(in.ActionDigest)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to extract digest for action")
+	}
+	actionMessage, err := bq.contentAddressableStorage.Get(ctx, actionDigest).ToProto(&remoteexecution.Action{}, bq.maximumMessageSizeBytes)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to obtain action")
+	}
+	platformKey, err := bq.platformKeyExtractor.ExtractKey(ctx, digestFunction, actionMessage.(*remoteexecution.Action))
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to extract platform key")
+	}
+	return platformKey.GetPlatformQueueName().Platform.GetProperties(), nil
+}
+
+// platformPropertiesContain returns whether all of the required
+// platform properties are present.
+func platformPropertiesContain(properties, requiredProperties []*remoteexecution.Platform_Property) bool {
+	for _, requiredProperty := range requiredProperties {
+		found := false
+		for _, property := range properties {
+			if property.Name == requiredProperty.Name && property.Value == requiredProperty.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (bq *federatingBuildQueue) Execute(in *remoteexecution.ExecuteRequest, out remoteexecution.Execution_ExecuteServer) error {
+	cluster, err := bq.getCluster(out.Context(), in)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return bq.localBuildQueue.Execute(in, out)
+	}
+	return cluster.BuildQueue.Execute(in, &federatedOperationNamePrepender{
+		Execution_ExecuteServer: out,
+		prefix:                  cluster.Name + federatedOperationNameSeparator,
+	})
+}
+
+func (bq *federatingBuildQueue) WaitExecution(in *remoteexecution.WaitExecutionRequest, out remoteexecution.Execution_WaitExecutionServer) error {
+	target := strings.SplitN(in.Name, federatedOperationNameSeparator, 2)
+	if len(target) != 2 {
+		return bq.localBuildQueue.WaitExecution(in, out)
+	}
+	cluster, ok := bq.clustersByName[target[0]]
+	if !ok {
+		return status.Errorf(codes.NotFound, "Operation name %#v refers to unknown federated cluster %#v", in.Name, target[0])
+	}
+
+	var requestCopy remoteexecution.WaitExecutionRequest
+	proto.Merge(&requestCopy, in)
+	requestCopy.Name = target[1]
+	return cluster.BuildQueue.WaitExecution(&requestCopy, &federatedOperationNamePrepender{
+		Execution_ExecuteServer: out,
+		prefix:                  target[0] + federatedOperationNameSeparator,
+	})
+}
+
+// federatedOperationNamePrepender prepends the name of a federated
+// cluster to the names of operations returned by it.
+type federatedOperationNamePrepender struct {
+	remoteexecution.Execution_ExecuteServer
+	prefix string
+}
+
+func (np *federatedOperationNamePrepender) Send(operation *longrunningpb.Operation) error {
+	var operationCopy longrunningpb.Operation
+	proto.Merge(&operationCopy, operation)
+	operationCopy.Name = np.prefix + operation.Name
+	return np.Execution_ExecuteServer.Send(&operationCopy)
+}
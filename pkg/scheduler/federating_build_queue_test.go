@@ -0,0 +1,229 @@
+package scheduler_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/platform"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+)
+
+func TestFederatingBuildQueue(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	localBuildQueue := mock.NewMockBuildQueue(ctrl)
+	linuxBuildQueue := mock.NewMockBuildQueue(ctrl)
+	europeBuildQueue := mock.NewMockBuildQueue(ctrl)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	platformKeyExtractor := mock.NewMockPlatformKeyExtractor(ctrl)
+	buildQueue, err := scheduler.NewFederatingBuildQueue(
+		localBuildQueue,
+		[]scheduler.FederatedCluster{
+			{
+				Name:       "linux",
+				BuildQueue: linuxBuildQueue,
+				Matchers: []scheduler.FederatedClusterMatcher{{
+					InstanceNamePrefix: digest.MustNewInstanceName("main"),
+					PlatformProperties: []*remoteexecution.Platform_Property{
+						{Name: "os", Value: "linux"},
+					},
+				}},
+			},
+			{
+				Name:       "europe",
+				BuildQueue: europeBuildQueue,
+				Matchers: []scheduler.FederatedClusterMatcher{{
+					InstanceNamePrefix: digest.MustNewInstanceName("europe"),
+				}},
+			},
+		},
+		contentAddressableStorage,
+		10000,
+		platformKeyExtractor)
+	require.NoError(t, err)
+
+	t.Run("InvalidClusterName", func(t *testing.T) {
+		_, err := scheduler.NewFederatingBuildQueue(
+			localBuildQueue,
+			[]scheduler.FederatedCluster{{
+				Name:       "us/east",
+				BuildQueue: linuxBuildQueue,
+			}},
+			contentAddressableStorage,
+			10000,
+			platformKeyExtractor)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid federated cluster name \"us/east\""), err)
+	})
+
+	t.Run("GetCapabilities", func(t *testing.T) {
+		// Only the local build queue and the cluster matching
+		// the instance name should be queried. Only digest
+		// functions supported by both should be reported.
+		localBuildQueue.EXPECT().GetCapabilities(gomock.Any(), digest.MustNewInstanceName("europe/hello")).
+			Return(&remoteexecution.ServerCapabilities{
+				ExecutionCapabilities: &remoteexecution.ExecutionCapabilities{
+					DigestFunction: remoteexecution.DigestFunction_SHA256,
+					DigestFunctions: []remoteexecution.DigestFunction_Value{
+						remoteexecution.DigestFunction_SHA256,
+						remoteexecution.DigestFunction_SHA1,
+					},
+				},
+			}, nil)
+		europeBuildQueue.EXPECT().GetCapabilities(gomock.Any(), digest.MustNewInstanceName("europe/hello")).
+			Return(&remoteexecution.ServerCapabilities{
+				ExecutionCapabilities: &remoteexecution.ExecutionCapabilities{
+					DigestFunction: remoteexecution.DigestFunction_SHA1,
+					DigestFunctions: []remoteexecution.DigestFunction_Value{
+						remoteexecution.DigestFunction_SHA1,
+						remoteexecution.DigestFunction_MD5,
+					},
+					ExecEnabled: true,
+				},
+			}, nil)
+
+		capabilities, err := buildQueue.GetCapabilities(ctx, digest.MustNewInstanceName("europe/hello"))
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteexecution.ServerCapabilities{
+			ExecutionCapabilities: &remoteexecution.ExecutionCapabilities{
+				DigestFunction: remoteexecution.DigestFunction_SHA1,
+				DigestFunctions: []remoteexecution.DigestFunction_Value{
+					remoteexecution.DigestFunction_SHA1,
+				},
+				ExecEnabled: true,
+			},
+		}, capabilities)
+	})
+
+	t.Run("ExecuteLocal", func(t *testing.T) {
+		// Requests not matching any of the clusters should be
+		// handled locally, without loading the action.
+		request := &remoteexecution.ExecuteRequest{
+			InstanceName: "other",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+				SizeBytes: 123,
+			},
+		}
+		out := mock.NewMockExecution_ExecuteServer(ctrl)
+		out.EXPECT().Context().Return(ctx).AnyTimes()
+		localBuildQueue.EXPECT().Execute(request, out).Return(nil)
+
+		require.NoError(t, buildQueue.Execute(request, out))
+	})
+
+	t.Run("ExecuteByPlatform", func(t *testing.T) {
+		// Requests for Linux actions should be forwarded to
+		// the Linux cluster. Names of operations should be
+		// prefixed with the name of the cluster.
+		request := &remoteexecution.ExecuteRequest{
+			InstanceName: "main",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+				SizeBytes: 123,
+			},
+		}
+		action := &remoteexecution.Action{
+			Platform: &remoteexecution.Platform{
+				Properties: []*remoteexecution.Platform_Property{
+					{Name: "cpu", Value: "x86_64"},
+					{Name: "os", Value: "linux"},
+				},
+			},
+		}
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123),
+		).Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided))
+		platformKeyExtractor.EXPECT().ExtractKey(gomock.Any(), gomock.Any(), testutil.EqProto(t, action)).
+			Return(platform.MustNewKey("main", action.Platform), nil)
+		out := mock.NewMockExecution_ExecuteServer(ctrl)
+		out.EXPECT().Context().Return(ctx).AnyTimes()
+		linuxBuildQueue.EXPECT().Execute(request, gomock.Any()).DoAndReturn(
+			func(in *remoteexecution.ExecuteRequest, out remoteexecution.Execution_ExecuteServer) error {
+				return out.Send(&longrunningpb.Operation{Name: "5bd4e5a2-1fc4-4b4c-9b02-8d0a9c9f1e1a"})
+			})
+		out.EXPECT().Send(testutil.EqProto(t, &longrunningpb.Operation{Name: "linux/5bd4e5a2-1fc4-4b4c-9b02-8d0a9c9f1e1a"}))
+
+		require.NoError(t, buildQueue.Execute(request, out))
+	})
+
+	t.Run("ExecutePlatformMismatch", func(t *testing.T) {
+		// Actions with other platform properties should be
+		// handled locally.
+		request := &remoteexecution.ExecuteRequest{
+			InstanceName: "main",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+				SizeBytes: 123,
+			},
+		}
+		action := &remoteexecution.Action{
+			Platform: &remoteexecution.Platform{
+				Properties: []*remoteexecution.Platform_Property{
+					{Name: "os", Value: "windows"},
+				},
+			},
+		}
+		contentAddressableStorage.EXPECT().Get(gomock.Any(), gomock.Any()).
+			Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided))
+		platformKeyExtractor.EXPECT().ExtractKey(gomock.Any(), gomock.Any(), testutil.EqProto(t, action)).
+			Return(platform.MustNewKey("main", action.Platform), nil)
+		out := mock.NewMockExecution_ExecuteServer(ctrl)
+		out.EXPECT().Context().Return(ctx).AnyTimes()
+		localBuildQueue.EXPECT().Execute(request, out).Return(nil)
+
+		require.NoError(t, buildQueue.Execute(request, out))
+	})
+
+	t.Run("WaitExecutionLocal", func(t *testing.T) {
+		request := &remoteexecution.WaitExecutionRequest{
+			Name: "5bd4e5a2-1fc4-4b4c-9b02-8d0a9c9f1e1a",
+		}
+		out := mock.NewMockExecution_WaitExecutionServer(ctrl)
+		localBuildQueue.EXPECT().WaitExecution(request, out).Return(nil)
+
+		require.NoError(t, buildQueue.WaitExecution(request, out))
+	})
+
+	t.Run("WaitExecutionUnknownCluster", func(t *testing.T) {
+		out := mock.NewMockExecution_WaitExecutionServer(ctrl)
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.NotFound, "Operation name \"asia/5bd4e5a2-1fc4-4b4c-9b02-8d0a9c9f1e1a\" refers to unknown federated cluster \"asia\""),
+			buildQueue.WaitExecution(&remoteexecution.WaitExecutionRequest{
+				Name: "asia/5bd4e5a2-1fc4-4b4c-9b02-8d0a9c9f1e1a",
+			}, out))
+	})
+
+	t.Run("WaitExecutionFederated", func(t *testing.T) {
+		// The name of the cluster should be stripped from the
+		// operation name prior to forwarding the request.
+		out := mock.NewMockExecution_WaitExecutionServer(ctrl)
+		europeBuildQueue.EXPECT().WaitExecution(
+			testutil.EqProto(t, &remoteexecution.WaitExecutionRequest{
+				Name: "5bd4e5a2-1fc4-4b4c-9b02-8d0a9c9f1e1a",
+			}),
+			gomock.Any(),
+		).DoAndReturn(func(in *remoteexecution.WaitExecutionRequest, out remoteexecution.Execution_WaitExecutionServer) error {
+			return out.Send(&longrunningpb.Operation{Name: "5bd4e5a2-1fc4-4b4c-9b02-8d0a9c9f1e1a", Done: true})
+		})
+		out.EXPECT().Send(testutil.EqProto(t, &longrunningpb.Operation{Name: "europe/5bd4e5a2-1fc4-4b4c-9b02-8d0a9c9f1e1a", Done: true}))
+
+		require.NoError(t, buildQueue.WaitExecution(&remoteexecution.WaitExecutionRequest{
+			Name: "europe/5bd4e5a2-1fc4-4b4c-9b02-8d0a9c9f1e1a",
+		}, out))
+	})
+}
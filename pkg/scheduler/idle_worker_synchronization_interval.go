@@ -0,0 +1,36 @@
+package scheduler
+
+import (
+	"math"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/random"
+)
+
+// NewExponentialIdleWorkerSynchronizationInterval creates a function
+// that may be used as
+// InMemoryBuildQueueConfiguration.GetIdleWorkerSynchronizationInterval.
+// It causes workers that repeatedly fail to obtain work to block for
+// exponentially longer amounts of time, reducing the load that an idle
+// fleet of workers places on the scheduler. As idle workers are woken
+// up as soon as work appears, this has no effect on latency. The
+// interval is reset as soon as a worker is assigned a task.
+//
+// Jitter is added to the interval to ensure synchronization requests
+// get smeared out over time. The interval returned is at least half of
+// the computed interval, so that backing off remains effective.
+//
+// The function that is returned must be called with the build queue's
+// lock held, as the random number generator is not thread-safe.
+func NewExponentialIdleWorkerSynchronizationInterval(generator random.SingleThreadedGenerator, initialInterval, maximumInterval time.Duration, multiplier float64) func(consecutiveTimeouts int) time.Duration {
+	return func(consecutiveTimeouts int) time.Duration {
+		interval := maximumInterval
+		if f := float64(initialInterval) * math.Pow(multiplier, float64(consecutiveTimeouts)); f < float64(maximumInterval) {
+			interval = time.Duration(f)
+		}
+		if interval < 2 {
+			return interval
+		}
+		return interval/2 + random.Duration(generator, interval/2)
+	}
+}
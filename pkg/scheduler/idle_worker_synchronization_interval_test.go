@@ -0,0 +1,43 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExponentialIdleWorkerSynchronizationInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	generator := mock.NewMockSingleThreadedGenerator(ctrl)
+	getInterval := scheduler.NewExponentialIdleWorkerSynchronizationInterval(generator, time.Minute, 10*time.Minute, 2.0)
+
+	t.Run("Initial", func(t *testing.T) {
+		// Workers that were recently assigned a task should
+		// block between 30 and 60 seconds.
+		generator.EXPECT().Int63n(int64(30 * time.Second)).Return(int64(5 * time.Second))
+		require.Equal(t, 35*time.Second, getInterval(0))
+	})
+
+	t.Run("BackingOff", func(t *testing.T) {
+		generator.EXPECT().Int63n(int64(time.Minute)).Return(int64(0))
+		require.Equal(t, time.Minute, getInterval(1))
+
+		generator.EXPECT().Int63n(int64(4 * time.Minute)).Return(int64(4*time.Minute - 1))
+		require.Equal(t, 8*time.Minute-1, getInterval(3))
+	})
+
+	t.Run("Maximum", func(t *testing.T) {
+		// The interval should not grow beyond the maximum,
+		// even if the computation overflows.
+		generator.EXPECT().Int63n(int64(5 * time.Minute)).Return(int64(time.Minute))
+		require.Equal(t, 6*time.Minute, getInterval(4))
+
+		generator.EXPECT().Int63n(int64(5 * time.Minute)).Return(int64(time.Minute))
+		require.Equal(t, 6*time.Minute, getInterval(10000))
+	})
+}
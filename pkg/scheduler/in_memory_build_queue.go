@@ -8,13 +8,19 @@ import (
 	"math"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	re_builder "github.com/buildbarn/bb-remote-execution/pkg/builder"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/buildqueuestate"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/errorclassification"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/operationjournal"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/schedulerstatistics"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/vcsmetadata"
 	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/initialsizeclass"
 	scheduler_invocation "github.com/buildbarn/bb-remote-execution/pkg/scheduler/invocation"
 	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/platform"
@@ -26,12 +32,15 @@ import (
 	"github.com/buildbarn/bb-storage/pkg/clock"
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/otel"
+	auth_pb "github.com/buildbarn/bb-storage/pkg/proto/auth"
 	"github.com/buildbarn/bb-storage/pkg/util"
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	status_pb "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
@@ -55,6 +64,15 @@ var (
 		},
 		[]string{"instance_name_prefix", "platform", "size_class", "outcome"})
 
+	inMemoryBuildQueueInputRootAffinityTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "builder",
+			Name:      "in_memory_build_queue_input_root_affinity_total",
+			Help:      "Number of times a task was assigned to an idle worker, and whether the worker recently executed a task with the same input root.",
+		},
+		[]string{"instance_name_prefix", "platform", "size_class", "outcome"})
+
 	inMemoryBuildQueueInvocationsCreatedTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "buildbarn",
@@ -132,6 +150,22 @@ var (
 			Buckets:   util.DecimalExponentialBuckets(-3, 6, 2),
 		},
 		[]string{"instance_name_prefix", "platform", "size_class"})
+	inMemoryBuildQueueTasksPreemptedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "builder",
+			Name:      "in_memory_build_queue_tasks_preempted_total",
+			Help:      "Number of times a task that was executing was preempted and requeued, so that an operation having a higher priority could be executed instead.",
+		},
+		[]string{"instance_name_prefix", "platform", "size_class"})
+	inMemoryBuildQueueTasksRejectedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "builder",
+			Name:      "in_memory_build_queue_tasks_rejected_total",
+			Help:      "Number of times a task was declined by a worker lacking the memory to execute it, causing it to be requeued.",
+		},
+		[]string{"instance_name_prefix", "platform", "size_class"})
 
 	inMemoryBuildQueueWorkersCreatedTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -149,6 +183,14 @@ var (
 			Help:      "Number of workers that have entered the terminating state.",
 		},
 		[]string{"instance_name_prefix", "platform", "size_class"})
+	inMemoryBuildQueueWorkersQuarantinedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "builder",
+			Name:      "in_memory_build_queue_workers_quarantined_total",
+			Help:      "Number of times a worker was quarantined, due to too many of its tasks failing with infrastructure errors.",
+		},
+		[]string{"instance_name_prefix", "platform", "size_class"})
 	inMemoryBuildQueueWorkersRemovedTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "buildbarn",
@@ -198,9 +240,12 @@ type InMemoryBuildQueueConfiguration struct {
 	// passed, the worker is instructed to resynchronize, as a form
 	// of health checking.
 	//
-	// Implementations may add jitter to this value to ensure
-	// synchronization requests get smeared out over time.
-	GetIdleWorkerSynchronizationInterval func() time.Duration
+	// The number of consecutive synchronizations of the worker
+	// that timed out without obtaining work is provided, permitting
+	// implementations to let idle workers synchronize less
+	// frequently. Implementations may add jitter to this value to
+	// ensure synchronization requests get smeared out over time.
+	GetIdleWorkerSynchronizationInterval func(consecutiveTimeouts int) time.Duration
 
 	// WorkerTaskRetryCount specifies how many times a worker may
 	// redundantly request that a single task is started. By
@@ -212,6 +257,212 @@ type InMemoryBuildQueueConfiguration struct {
 	// worker may remain registered by InMemoryBuildQueue when no
 	// Synchronize() calls are received.
 	WorkerWithNoSynchronizationsTimeout time.Duration
+
+	// MaximumExecutionDelay specifies how far into the future
+	// clients may request the execution of an action to start,
+	// using the "buildbarn-earliest-start-time" header. When zero,
+	// delayed execution is not permitted.
+	MaximumExecutionDelay time.Duration
+
+	// GetDigestFunctions returns the digest functions that clients
+	// may use for a given instance name. The first digest function
+	// is reported as the preferred one. If this function is not
+	// set or returns an empty list, all digest functions supported
+	// by digest.Digest may be used. The list may only contain
+	// digest functions that are part of
+	// digest.SupportedDigestFunctions.
+	GetDigestFunctions func(instanceName digest.InstanceName) []remoteexecution.DigestFunction_Value
+
+	// GetCacheOnlyMessage returns whether a given instance name is
+	// only intended to be used for caching. Execute() requests for
+	// such instance names are rejected, using an error that contains
+	// the returned message. If this function is not set, remote
+	// execution is permitted for all instance names.
+	GetCacheOnlyMessage func(instanceName digest.InstanceName) (string, bool)
+
+	// GetInvocationWeight returns the weight of the invocations
+	// that are created for an execution request. Invocations with a
+	// higher weight are permitted to run proportionally more
+	// operations concurrently than ones sharing the same size class
+	// queue, thereby preventing a single tenant from starving
+	// others. If this function is not set, all invocations have
+	// weight 1.
+	GetInvocationWeight func(ctx context.Context, instanceName digest.InstanceName) float64
+
+	// PriorityAgingInterval is the amount of time an operation
+	// needs to be queued for its priority to be raised by one
+	// level. This prevents operations with a low priority from
+	// being starved by a continuous stream of operations with a
+	// higher priority. If zero, priorities remain constant.
+	PriorityAgingInterval time.Duration
+
+	// DeadlineAwareScheduling causes queued operations having the
+	// same priority to be ordered by the latest point in time at
+	// which they need to start executing to complete before their
+	// deadline, as opposed to by expected duration. The deadline of
+	// an operation is the point in time at which its action times
+	// out, or the deadline of the client's Execute() call if it is
+	// earlier. This causes short actions that would otherwise
+	// expire to be dispatched first.
+	DeadlineAwareScheduling bool
+
+	// WorkerInputRootAffinityCount is the number of input root
+	// digests of recently executed tasks that are tracked for every
+	// worker. When a task is scheduled while multiple workers are
+	// idle, a worker that recently executed a task with the same
+	// input root is preferred. This maximizes the effectiveness of
+	// caches that are local to workers. If zero, input roots are
+	// not tracked.
+	WorkerInputRootAffinityCount int
+
+	// InFlightDeduplicationAcrossInstanceNames permits Execute()
+	// requests to be deduplicated against tasks that are in flight
+	// for the same action, even if they were created using a
+	// different instance name. Deduplication only takes place if
+	// both requests would be placed in the same platform queue.
+	//
+	// The action is only executed once, using the instance name of
+	// the first request. This option should therefore only be
+	// enabled if all instance names sharing a platform queue are
+	// backed by the same storage.
+	InFlightDeduplicationAcrossInstanceNames bool
+
+	// OperationJournal is used to persist the state of operations
+	// created through Execute(), so that they can be recovered by
+	// calling RecoverOperations() after the scheduler restarts. If
+	// not set, all operations are lost when the scheduler restarts.
+	OperationJournal OperationJournal
+
+	// PlatformQueueHealthReporter is notified whenever platform
+	// queues transition between having and not having any workers
+	// that are capable of executing operations. If not set, the
+	// health of platform queues is not reported.
+	PlatformQueueHealthReporter PlatformQueueHealthReporter
+
+	// WorkerCountRecommendationWindow enables the computation of
+	// the recommended number of workers for every size class
+	// queue, which may be used to drive autoscaling. The arrival
+	// rate and execution duration of tasks are estimated using
+	// exponentially decaying averages having this time constant.
+	// The recommendation is chosen such that the current queue can
+	// be drained within this amount of time, while keeping up with
+	// the arrival rate. If zero, no recommendations are computed.
+	WorkerCountRecommendationWindow time.Duration
+
+	// QueueFullRetryDelay is the amount of time clients are
+	// requested to wait before retrying Execute() requests that
+	// are rejected due to platform queues containing the maximum
+	// number of queued operations.
+	QueueFullRetryDelay time.Duration
+
+	// MaximumExecutingOperationsPerInvocation limits the number of
+	// operations belonging to a single invocation that may execute
+	// concurrently within a size class queue. This prevents a single
+	// large build from monopolizing all workers, while other
+	// invocations are starved. The limit applies to invocations at
+	// all levels of the hierarchy (e.g., both to correlated
+	// invocations IDs and tool invocation IDs), but not to the root
+	// invocation. If zero, the number of executing operations is
+	// not limited.
+	MaximumExecutingOperationsPerInvocation int
+
+	// GetClientQuota returns the quota that applies to the client
+	// performing an Execute() or WaitExecution() request. Quota
+	// usage is tracked separately for every user, based on the
+	// publicly displayable part of the authentication metadata.
+	// Requests for which this function returns nil, or all requests
+	// if this function is not set, are not subject to any quota.
+	GetClientQuota func(ctx context.Context, instanceName digest.InstanceName) *ClientQuota
+
+	// WorkerQuarantinePolicy causes workers to no longer receive
+	// tasks if too many of the tasks they completed recently
+	// failed due to infrastructure errors. If not set, workers are
+	// never quarantined.
+	WorkerQuarantinePolicy *WorkerQuarantinePolicy
+
+	// WorkerReservationPolicy permits invocations to reserve a
+	// number of workers for their duration, by setting the
+	// "reservedWorkers" platform property. If not set, this
+	// platform property has no special meaning.
+	WorkerReservationPolicy *WorkerReservationPolicy
+
+	// PlatformPropertyMetricLabels contains the names of platform
+	// properties (e.g., "os", "arch", "pool") whose values are
+	// used as labels of additional Prometheus metrics for queue
+	// length, dispatch latency and execution duration. Unlike the
+	// regular metrics, which are labeled by the full Platform
+	// message, these metrics are aggregated across all platform
+	// queues whose selected properties are identical. If empty,
+	// these metrics are not exported.
+	PlatformPropertyMetricLabels []string
+}
+
+// WorkerQuarantinePolicy contains the parameters that determine when
+// workers are quarantined. A worker is quarantined if more than
+// MaximumInfrastructureErrorRate of the last WindowSize tasks that it
+// completed failed due to infrastructure errors. This prevents a single
+// worker with, for example, a corrupted local cache from causing a
+// large number of actions to fail.
+type WorkerQuarantinePolicy struct {
+	WindowSize                     int
+	MaximumInfrastructureErrorRate float64
+	QuarantineDuration             time.Duration
+}
+
+// ReservedWorkersPlatformPropertyName is the name of the platform
+// property that may be used by actions to request that workers are
+// reserved for the invocation to which they belong. This gives
+// iterative builds (e.g., a developer repeatedly running tests) warm
+// workers with populated caches. Its value is a positive decimal
+// number.
+const ReservedWorkersPlatformPropertyName = "reservedWorkers"
+
+// WorkerReservationPolicy contains the parameters that limit the
+// reservation of workers by invocations. Once a worker has executed an
+// operation of an invocation that requested a reservation, it is
+// reserved for that invocation, meaning it no longer executes
+// operations of other invocations. The reservation is released if no
+// operations of the invocation are started for IdleTimeout.
+//
+// At most MaximumWorkersPerInvocation workers are reserved for a
+// single invocation, while at most MaximumReservedWorkersRatio of the
+// workers of a size class queue are reserved across all invocations.
+// This ensures reservations can't exhaust the pool of workers.
+type WorkerReservationPolicy struct {
+	MaximumWorkersPerInvocation int
+	MaximumReservedWorkersRatio float64
+	IdleTimeout                 time.Duration
+}
+
+// getReservedWorkersCount returns the number of workers an action
+// requests to be reserved for its invocation. It also returns the
+// platform properties of the action with the "reservedWorkers"
+// property removed, so that the property does not influence which
+// platform queue is selected.
+func (p *WorkerReservationPolicy) getReservedWorkersCount(action *remoteexecution.Action) (int, *remoteexecution.Action, error) {
+	properties := action.Platform.GetProperties()
+	for i, property := range properties {
+		if property.Name == ReservedWorkersPlatformPropertyName {
+			count, err := strconv.ParseUint(property.Value, 10, 16)
+			if err != nil {
+				return 0, nil, util.StatusWrapfWithCode(err, codes.InvalidArgument, "Invalid value for platform property %#v", ReservedWorkersPlatformPropertyName)
+			}
+			if count == 0 {
+				return 0, nil, status.Errorf(codes.InvalidArgument, "Platform property %#v must be positive", ReservedWorkersPlatformPropertyName)
+			}
+			if maximum := p.MaximumWorkersPerInvocation; int(count) > maximum {
+				count = uint64(maximum)
+			}
+
+			strippedProperties := make([]*remoteexecution.Platform_Property, 0, len(properties)-1)
+			strippedProperties = append(strippedProperties, properties[:i]...)
+			strippedProperties = append(strippedProperties, properties[i+1:]...)
+			strippedAction := proto.Clone(action).(*remoteexecution.Action)
+			strippedAction.Platform.Properties = strippedProperties
+			return int(count), strippedAction, nil
+		}
+	}
+	return 0, action, nil
 }
 
 // InMemoryBuildQueue implements a BuildQueue that can distribute
@@ -228,6 +479,7 @@ type InMemoryBuildQueue struct {
 	platformQueueAbsenceHardFailureTime time.Time
 	maximumMessageSizeBytes             int
 	actionRouter                        routing.ActionRouter
+	platformPropertyMetrics             *platformPropertyMetrics
 
 	lock               sync.Mutex
 	platformQueuesTrie *platform.Trie
@@ -241,8 +493,9 @@ type InMemoryBuildQueue struct {
 
 	// Map of each task that does not have DoNotCache set by digest.
 	// This map is used to deduplicate concurrent requests for the
-	// same action.
-	inFlightDeduplicationMap map[digest.Digest]*task
+	// same action. Keys are obtained through
+	// getInFlightDeduplicationKey().
+	inFlightDeduplicationMap map[string]*task
 
 	// Time value that is updated during every mutation of build
 	// queue state. This reduces the number of clock accesses, while
@@ -253,6 +506,16 @@ type InMemoryBuildQueue struct {
 	// platform queues and operations.
 	cleanupQueue cleanupQueue
 
+	// Usage of quotas by clients, keyed by the quota and the user
+	// to which they apply.
+	clientQuotaStates map[clientQuotaKey]*clientQuotaState
+
+	// Size class queues in which workers are registered. Workers
+	// may change their platform properties and size class at
+	// runtime, meaning this is needed to remove them from the size
+	// class queue they were registered in previously.
+	workerSizeClassQueues map[workerKey]*sizeClassQueue
+
 	// Authorizer used to allow/deny access for certain users
 	// to perform Execute and WaitExecution calls.
 	executeAuthorizer auth.Authorizer
@@ -264,20 +527,91 @@ type InMemoryBuildQueue struct {
 	// Authorizer used to allow/deny access for certain users to
 	// perform KillOperations calls.
 	killOperationsAuthorizer auth.Authorizer
+
+	// Statistics of size class queues that were persisted by a
+	// previous instance of the scheduler, which are applied when
+	// the size class queue is created.
+	recoveredSizeClassQueueStatistics map[sizeClassKey]*schedulerstatistics.SizeClassQueue
 }
 
-var inMemoryBuildQueueCapabilitiesProvider = capabilities.NewStaticProvider(&remoteexecution.ServerCapabilities{
-	ExecutionCapabilities: &remoteexecution.ExecutionCapabilities{
-		DigestFunction:  remoteexecution.DigestFunction_SHA256,
-		DigestFunctions: digest.SupportedDigestFunctions,
-		ExecEnabled:     true,
-		ExecutionPriorityCapabilities: &remoteexecution.PriorityCapabilities{
-			Priorities: []*remoteexecution.PriorityCapabilities_PriorityRange{
-				{MinPriority: math.MinInt32, MaxPriority: math.MaxInt32},
+type inMemoryBuildQueueCapabilitiesProvider struct {
+	configuration *InMemoryBuildQueueConfiguration
+}
+
+func (cp inMemoryBuildQueueCapabilitiesProvider) GetCapabilities(ctx context.Context, instanceName digest.InstanceName) (*remoteexecution.ServerCapabilities, error) {
+	digestFunctions, preferredDigestFunction := cp.configuration.getDigestFunctions(instanceName)
+	return &remoteexecution.ServerCapabilities{
+		ExecutionCapabilities: &remoteexecution.ExecutionCapabilities{
+			DigestFunction:  preferredDigestFunction,
+			DigestFunctions: digestFunctions,
+			ExecEnabled:     !cp.configuration.isCacheOnly(instanceName),
+			ExecutionPriorityCapabilities: &remoteexecution.PriorityCapabilities{
+				Priorities: []*remoteexecution.PriorityCapabilities_PriorityRange{
+					{MinPriority: math.MinInt32, MaxPriority: math.MaxInt32},
+				},
 			},
 		},
-	},
-})
+	}, nil
+}
+
+// getDigestFunctions returns the digest functions that may be used
+// by clients for a given instance name, and the one that should be
+// reported as being preferred.
+func (c *InMemoryBuildQueueConfiguration) getDigestFunctions(instanceName digest.InstanceName) ([]remoteexecution.DigestFunction_Value, remoteexecution.DigestFunction_Value) {
+	if c.GetDigestFunctions != nil {
+		if digestFunctions := c.GetDigestFunctions(instanceName); len(digestFunctions) > 0 {
+			return digestFunctions, digestFunctions[0]
+		}
+	}
+	return digest.SupportedDigestFunctions, remoteexecution.DigestFunction_SHA256
+}
+
+// isCacheOnly returns whether remote execution is disabled for a given
+// instance name.
+func (c *InMemoryBuildQueueConfiguration) isCacheOnly(instanceName digest.InstanceName) bool {
+	if c.GetCacheOnlyMessage == nil {
+		return false
+	}
+	_, cacheOnly := c.GetCacheOnlyMessage(instanceName)
+	return cacheOnly
+}
+
+// getInvocationWeight returns the weight of the invocations that are
+// created for an execution request.
+func (c *InMemoryBuildQueueConfiguration) getInvocationWeight(ctx context.Context, instanceName digest.InstanceName) float64 {
+	if c.GetInvocationWeight == nil {
+		return 1
+	}
+	return c.GetInvocationWeight(ctx, instanceName)
+}
+
+// getEffectivePriority returns the priority of an operation, adjusted
+// for the point in time at which it was queued.
+//
+// Instead of raising the priority of queued operations as time
+// progresses, which would require all heaps of queued operations to be
+// reordered continuously, the priority of operations is lowered based
+// on how late they were queued. This yields the same relative order,
+// while ensuring that the effective priority of an operation remains
+// constant while it is queued.
+func (c *InMemoryBuildQueueConfiguration) getEffectivePriority(priority int32, queuedTimestamp *timestamppb.Timestamp) float64 {
+	if c.PriorityAgingInterval <= 0 {
+		return float64(priority)
+	}
+	return float64(priority) + float64(queuedTimestamp.AsTime().UnixNano())/float64(c.PriorityAgingInterval)
+}
+
+// isDigestFunctionPermitted returns whether clients may use a given
+// digest function for a given instance name.
+func (c *InMemoryBuildQueueConfiguration) isDigestFunctionPermitted(instanceName digest.InstanceName, digestFunction remoteexecution.DigestFunction_Value) bool {
+	digestFunctions, _ := c.getDigestFunctions(instanceName)
+	for _, permittedDigestFunction := range digestFunctions {
+		if permittedDigestFunction == digestFunction {
+			return true
+		}
+	}
+	return false
+}
 
 // NewInMemoryBuildQueue creates a new InMemoryBuildQueue that is in the
 // initial state. It does not have any queues, workers or queued
@@ -285,6 +619,7 @@ var inMemoryBuildQueueCapabilitiesProvider = capabilities.NewStaticProvider(&rem
 func NewInMemoryBuildQueue(contentAddressableStorage blobstore.BlobAccess, clock clock.Clock, uuidGenerator util.UUIDGenerator, configuration *InMemoryBuildQueueConfiguration, maximumMessageSizeBytes int, actionRouter routing.ActionRouter, executeAuthorizer, modifyDrainsAuthorizer, killOperationsAuthorizer auth.Authorizer) *InMemoryBuildQueue {
 	inMemoryBuildQueuePrometheusMetrics.Do(func() {
 		prometheus.MustRegister(inMemoryBuildQueueInFlightDeduplicationsTotal)
+		prometheus.MustRegister(inMemoryBuildQueueInputRootAffinityTotal)
 
 		prometheus.MustRegister(inMemoryBuildQueueInvocationsCreatedTotal)
 		prometheus.MustRegister(inMemoryBuildQueueInvocationsActivatedTotal)
@@ -296,16 +631,24 @@ func NewInMemoryBuildQueue(contentAddressableStorage blobstore.BlobAccess, clock
 		prometheus.MustRegister(inMemoryBuildQueueTasksExecutingDurationSeconds)
 		prometheus.MustRegister(inMemoryBuildQueueTasksExecutingRetries)
 		prometheus.MustRegister(inMemoryBuildQueueTasksCompletedDurationSeconds)
+		prometheus.MustRegister(inMemoryBuildQueueTasksPreemptedTotal)
+		prometheus.MustRegister(inMemoryBuildQueueTasksRejectedTotal)
 
 		prometheus.MustRegister(inMemoryBuildQueueWorkersCreatedTotal)
 		prometheus.MustRegister(inMemoryBuildQueueWorkersTerminatingTotal)
+		prometheus.MustRegister(inMemoryBuildQueueWorkersQuarantinedTotal)
 		prometheus.MustRegister(inMemoryBuildQueueWorkersRemovedTotal)
 
 		prometheus.MustRegister(inMemoryBuildQueueWorkerInvocationStickinessRetained)
 	})
 
+	var platformPropertyMetrics *platformPropertyMetrics
+	if len(configuration.PlatformPropertyMetricLabels) > 0 {
+		platformPropertyMetrics = newPlatformPropertyMetrics(configuration.PlatformPropertyMetricLabels)
+	}
+
 	return &InMemoryBuildQueue{
-		Provider: capabilities.NewAuthorizingProvider(inMemoryBuildQueueCapabilitiesProvider, executeAuthorizer),
+		Provider: capabilities.NewAuthorizingProvider(inMemoryBuildQueueCapabilitiesProvider{configuration: configuration}, executeAuthorizer),
 
 		contentAddressableStorage:           contentAddressableStorage,
 		clock:                               clock,
@@ -314,10 +657,14 @@ func NewInMemoryBuildQueue(contentAddressableStorage blobstore.BlobAccess, clock
 		platformQueueAbsenceHardFailureTime: clock.Now().Add(configuration.PlatformQueueWithNoWorkersTimeout),
 		maximumMessageSizeBytes:             maximumMessageSizeBytes,
 		actionRouter:                        actionRouter,
+		platformPropertyMetrics:             platformPropertyMetrics,
 		platformQueuesTrie:                  platform.NewTrie(),
 		sizeClassQueues:                     map[sizeClassKey]*sizeClassQueue{},
 		operationsNameMap:                   map[string]*operation{},
-		inFlightDeduplicationMap:            map[digest.Digest]*task{},
+		inFlightDeduplicationMap:            map[string]*task{},
+		clientQuotaStates:                   map[clientQuotaKey]*clientQuotaState{},
+		workerSizeClassQueues:               map[workerKey]*sizeClassQueue{},
+		recoveredSizeClassQueueStatistics:   map[sizeClassKey]*schedulerstatistics.SizeClassQueue{},
 		executeAuthorizer:                   executeAuthorizer,
 		modifyDrainsAuthorizer:              modifyDrainsAuthorizer,
 		killOperationsAuthorizer:            killOperationsAuthorizer,
@@ -338,7 +685,17 @@ var (
 // capable of using multiple size classes, as a maximum size class and
 // initialsizeclass.Analyzer can be provided for specifying how
 // operations are assigned to size classes.
-func (bq *InMemoryBuildQueue) RegisterPredeclaredPlatformQueue(instanceNamePrefix digest.InstanceName, platformMessage *remoteexecution.Platform, workerInvocationStickinessLimits []time.Duration, maximumQueuedBackgroundLearningOperations int, backgroundLearningOperationPriority int32, maximumSizeClass uint32) error {
+//
+// If maximumQueuedOperations is positive, Execute() requests that would
+// cause more operations to be queued in the platform queue are rejected
+// with RESOURCE_EXHAUSTED, so that clients back off. If preemptionPolicy
+// is set, tasks executing in the platform queue may be preempted in
+// favor of operations having a higher priority. Workers only receive
+// tasks if the resources they report meet the requirements of their
+// size class in workerResourceRequirements. If sizeClassEscalationPolicy
+// is set, tasks that are retried on the largest size class after
+// failing on a smaller one are granted additional time and resources.
+func (bq *InMemoryBuildQueue) RegisterPredeclaredPlatformQueue(instanceNamePrefix digest.InstanceName, platformMessage *remoteexecution.Platform, workerInvocationStickinessLimits []time.Duration, maximumQueuedBackgroundLearningOperations int, backgroundLearningOperationPriority int32, maximumSizeClass uint32, maximumQueuedOperations int, preemptionPolicy *PreemptionPolicy, workerResourceRequirements map[uint32]WorkerResourceRequirements, sizeClassEscalationPolicy *SizeClassEscalationPolicy) error {
 	platformKey, err := platform.NewKey(instanceNamePrefix, platformMessage)
 	if err != nil {
 		return err
@@ -351,11 +708,219 @@ func (bq *InMemoryBuildQueue) RegisterPredeclaredPlatformQueue(instanceNamePrefi
 		return status.Error(codes.AlreadyExists, "A queue with the same instance name prefix or platform already exists")
 	}
 
-	pq := bq.addPlatformQueue(platformKey, workerInvocationStickinessLimits, maximumQueuedBackgroundLearningOperations, backgroundLearningOperationPriority)
+	pq := bq.addPlatformQueue(platformKey, workerInvocationStickinessLimits, maximumQueuedBackgroundLearningOperations, backgroundLearningOperationPriority, maximumQueuedOperations, preemptionPolicy, workerResourceRequirements, sizeClassEscalationPolicy)
 	pq.addSizeClassQueue(bq, maximumSizeClass, false)
 	return nil
 }
 
+// RecoverOperations recreates operations that were persisted in the
+// operation journal prior to the scheduler restarting. Clients may
+// reattach to these operations by calling WaitExecution(). This method
+// needs to be called after all predeclared platform queues have been
+// registered, and before any RPCs are processed.
+//
+// Tasks that were executing prior to the scheduler restarting are not
+// scheduled immediately. Instead, the worker that was executing them
+// is given the opportunity to reattach to them, for the same amount of
+// time a worker may go without synchronizing against the scheduler.
+func (bq *InMemoryBuildQueue) RecoverOperations(operations []*operationjournal.Operation) error {
+	bq.enter(bq.clock.Now())
+	defer bq.leave()
+
+	// Group operations by task, as multiple operations may have
+	// been deduplicated against the same task.
+	type recoveredTaskKey struct {
+		sizeClassQueue *sizeClassQueue
+		actionDigest   digest.Digest
+	}
+	type recoveredTask struct {
+		task              *task
+		earliestStartTime time.Time
+		workerKey         workerKey
+	}
+	recoveredTasksMap := map[recoveredTaskKey]*recoveredTask{}
+	var recoveredTasks []*recoveredTask
+	for _, recoveredOperation := range operations {
+		if _, ok := bq.operationsNameMap[recoveredOperation.Name]; ok {
+			return status.Errorf(codes.AlreadyExists, "Operation %#v already exists", recoveredOperation.Name)
+		}
+		desiredState := recoveredOperation.DesiredState
+		if desiredState.GetAction() == nil {
+			return status.Errorf(codes.InvalidArgument, "Operation %#v does not have an action", recoveredOperation.Name)
+		}
+		instanceName, err := digest.NewInstanceName(recoveredOperation.InstanceName)
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid instance name %#v for operation %#v", recoveredOperation.InstanceName, recoveredOperation.Name)
+		}
+		actionInstanceName, err := digest.NewInstanceName(recoveredOperation.ActionInstanceName)
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid action instance name %#v for operation %#v", recoveredOperation.ActionInstanceName, recoveredOperation.Name)
+		}
+		digestFunction, err := actionInstanceName.GetDigestFunction(desiredState.DigestFunction, len(desiredState.ActionDigest.GetHash()))
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid digest function for operation %#v", recoveredOperation.Name)
+		}
+		actionDigest, err := digestFunction.NewDigestFromProto(desiredState.ActionDigest)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to extract digest for action of operation %#v", recoveredOperation.Name)
+		}
+		sizeClassKey, err := newSizeClassKeyFromName(recoveredOperation.SizeClassQueueName)
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid size class queue name for operation %#v", recoveredOperation.Name)
+		}
+		invocationKeys := make([]scheduler_invocation.Key, 0, len(recoveredOperation.InvocationIds))
+		for _, invocationID := range recoveredOperation.InvocationIds {
+			invocationKey, err := scheduler_invocation.NewKey(invocationID)
+			if err != nil {
+				return util.StatusWrapf(err, "Invalid invocation ID for operation %#v", recoveredOperation.Name)
+			}
+			invocationKeys = append(invocationKeys, invocationKey)
+		}
+		actionWeight, err := re_builder.GetActionWeight(desiredState.Action.GetPlatform())
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid weight for action of operation %#v", recoveredOperation.Name)
+		}
+		scq, err := bq.getOrCreateSizeClassQueueForRecovery(sizeClassKey)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to obtain size class queue for operation %#v", recoveredOperation.Name)
+		}
+
+		key := recoveredTaskKey{
+			sizeClassQueue: scq,
+			actionDigest:   actionDigest,
+		}
+		rt, ok := recoveredTasksMap[key]
+		if !ok {
+			rt = &recoveredTask{
+				task: &task{
+					operations:   map[*invocation]*operation{},
+					actionDigest: actionDigest,
+					desiredState: remoteworker.DesiredState_Executing{
+						ActionDigest:       desiredState.ActionDigest,
+						Action:             desiredState.Action,
+						QueuedTimestamp:    desiredState.QueuedTimestamp,
+						AuxiliaryMetadata:  desiredState.AuxiliaryMetadata,
+						InstanceNameSuffix: desiredState.InstanceNameSuffix,
+						DigestFunction:     desiredState.DigestFunction,
+						W3CTraceContext:    desiredState.W3CTraceContext,
+
+						AuthenticationMetadata: desiredState.AuthenticationMetadata,
+					},
+					targetID:                recoveredOperation.TargetId,
+					actionMnemonic:          recoveredOperation.ActionMnemonic,
+					weight:                  actionWeight,
+					expectedDuration:        recoveredOperation.ExpectedDuration.AsDuration(),
+					initialSizeClassLearner: recoveredInitialSizeClassLearner{},
+					stageChangeWakeup:       make(chan struct{}),
+				},
+			}
+			recoveredTasksMap[key] = rt
+			recoveredTasks = append(recoveredTasks, rt)
+		}
+
+		t := rt.task
+		weight := recoveredOperation.InvocationWeight
+		if weight <= 0 {
+			weight = 1
+		}
+		i := scq.getOrCreateInvocation(bq, invocationKeys, weight)
+		if _, ok := t.operations[i]; ok {
+			return status.Errorf(codes.InvalidArgument, "Operation %#v is part of the same invocation as another operation of the same task", recoveredOperation.Name)
+		}
+
+		// Operations of delayed tasks may exist without
+		// waiters, as is the case for ones created through
+		// Execute().
+		earliestStartTimestamp := recoveredOperation.EarliestStartTimestamp
+		o := t.newOperationWithName(bq, recoveredOperation.Name, recoveredOperation.Priority, i, earliestStartTimestamp != nil)
+		o.instanceName = instanceName
+		o.journaled = bq.configuration.OperationJournal != nil
+		if earliestStartTimestamp != nil {
+			if earliestStartTime := earliestStartTimestamp.AsTime(); earliestStartTime.After(rt.earliestStartTime) {
+				rt.earliestStartTime = earliestStartTime
+			}
+		}
+		if len(recoveredOperation.WorkerId) > 0 {
+			rt.workerKey = newWorkerKey(recoveredOperation.WorkerId)
+		}
+	}
+
+	for _, rt := range recoveredTasks {
+		t := rt.task
+		if rt.workerKey != "" {
+			// The task was executing prior to the scheduler
+			// restarting. Delay scheduling it, so that the
+			// worker may reattach to it.
+			if reattachTime := bq.now.Add(bq.configuration.WorkerWithNoSynchronizationsTimeout); reattachTime.After(rt.earliestStartTime) {
+				rt.earliestStartTime = reattachTime
+			}
+		}
+		if rt.earliestStartTime.After(bq.now) {
+			scq := t.getCurrentSizeClassQueue()
+			if _, ok := scq.recoveredTasks[rt.workerKey]; rt.workerKey != "" && !ok {
+				scq.recoveredTasks[rt.workerKey] = t
+				t.recoveredWorkerKey = rt.workerKey
+			}
+			t.delay(bq, rt.earliestStartTime)
+		} else {
+			t.maybeAddToInFlightDeduplicationMap(bq)
+			t.schedule(bq)
+		}
+
+		// Give clients the opportunity to reattach to the
+		// operations by calling WaitExecution().
+		for _, o := range t.operations {
+			o.maybeStartCleanup(bq)
+		}
+	}
+	return nil
+}
+
+// getOrCreateSizeClassQueueForRecovery returns the size class queue in
+// which an operation recovered from the operation journal needs to be
+// placed. If no workers for the size class queue have synchronized
+// against the scheduler yet, it is created in the same way as
+// Synchronize() would. It is removed if no workers appear.
+func (bq *InMemoryBuildQueue) getOrCreateSizeClassQueueForRecovery(sizeClassKey sizeClassKey) (*sizeClassQueue, error) {
+	if scq, ok := bq.sizeClassQueues[sizeClassKey]; ok {
+		return scq, nil
+	}
+
+	var pq *platformQueue
+	if platformQueueIndex := bq.platformQueuesTrie.GetExact(sizeClassKey.platformKey); platformQueueIndex >= 0 {
+		pq = bq.platformQueues[platformQueueIndex]
+		if maximumSizeClassQueue := pq.sizeClassQueues[len(pq.sizeClassQueues)-1]; maximumSizeClassQueue.mayBeRemoved {
+			return nil, status.Error(codes.InvalidArgument, "Cannot add multiple size classes to a platform queue that is not predeclared")
+		} else if maximumSizeClass := pq.sizeClasses[len(pq.sizeClasses)-1]; sizeClassKey.sizeClass > maximumSizeClass {
+			return nil, status.Errorf(codes.InvalidArgument, "Size class %d exceeds the predeclared maximum of %d", sizeClassKey.sizeClass, maximumSizeClass)
+		}
+	} else {
+		pq = bq.addPlatformQueue(sizeClassKey.platformKey, nil, 0, 0, 0, nil, nil, nil)
+	}
+	scq := pq.addSizeClassQueue(bq, sizeClassKey.sizeClass, true)
+	bq.cleanupQueue.add(&scq.cleanupKey, bq.now.Add(bq.configuration.PlatformQueueWithNoWorkersTimeout), func() {
+		scq.remove(bq)
+	})
+	return scq, nil
+}
+
+// recoveredInitialSizeClassLearner is the initialsizeclass.Learner that
+// is used by tasks that are recovered from the operation journal. As
+// the state of the original learner is lost, the outcome of executing
+// these tasks is not used for learning, and tasks are not retried on
+// other size classes.
+type recoveredInitialSizeClassLearner struct{}
+
+func (recoveredInitialSizeClassLearner) Succeeded(duration time.Duration, sizeClasses []uint32) (int, time.Duration, time.Duration, initialsizeclass.Learner) {
+	return 0, 0, 0, nil
+}
+
+func (recoveredInitialSizeClassLearner) Failed(timedOut bool) (time.Duration, time.Duration, initialsizeclass.Learner) {
+	return 0, 0, nil
+}
+
+func (recoveredInitialSizeClassLearner) Abandoned() {}
+
 // getRequestMetadata extracts the RequestMetadata message stored in the
 // gRPC request headers. This message contains the invocation ID that is
 // used to group incoming requests by client, so that tasks can be
@@ -372,6 +937,51 @@ func getRequestMetadata(ctx context.Context) *remoteexecution.RequestMetadata {
 	return nil
 }
 
+// getVCSMetadata extracts version control system state of the client's
+// source tree from the gRPC request headers. The commit SHA is
+// mandatory. If absent, no metadata is returned, as actions cannot
+// make meaningful use of only the dirty bit.
+func getVCSMetadata(ctx context.Context) (*vcsmetadata.VcsMetadata, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	commitSHAs := md.Get("buildbarn-vcs-commit-sha")
+	if len(commitSHAs) == 0 {
+		return nil, nil
+	}
+	vcsMetadata := &vcsmetadata.VcsMetadata{
+		CommitSha: commitSHAs[0],
+	}
+	if dirtyValues := md.Get("buildbarn-vcs-dirty"); len(dirtyValues) > 0 {
+		dirty, err := strconv.ParseBool(dirtyValues[0])
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid value for header \"buildbarn-vcs-dirty\": %#v", dirtyValues[0])
+		}
+		vcsMetadata.Dirty = dirty
+	}
+	return vcsMetadata, nil
+}
+
+// getEarliestStartTime extracts the point in time at which the client
+// wants execution of the action to start from the gRPC request
+// headers. This permits clients to submit actions ahead of time (e.g.,
+// nightly rebuilds), without needing to keep connections open until
+// the action starts. A zero value is returned if no such header is
+// present.
+func getEarliestStartTime(ctx context.Context) (time.Time, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("buildbarn-earliest-start-time"); len(values) > 0 {
+			earliestStartTime, err := time.Parse(time.RFC3339, values[0])
+			if err != nil {
+				return time.Time{}, status.Errorf(codes.InvalidArgument, "Invalid value for header \"buildbarn-earliest-start-time\": %#v", values[0])
+			}
+			return earliestStartTime, nil
+		}
+	}
+	return time.Time{}, nil
+}
+
 // Execute an action by scheduling it in the build queue. This call
 // blocks until the action is completed.
 func (bq *InMemoryBuildQueue) Execute(in *remoteexecution.ExecuteRequest, out remoteexecution.Execution_ExecuteServer) error {
@@ -392,11 +1002,22 @@ func (bq *InMemoryBuildQueue) Execute(in *remoteexecution.ExecuteRequest, out re
 	if err := auth.AuthorizeSingleInstanceName(ctx, bq.executeAuthorizer, instanceName); err != nil {
 		return util.StatusWrap(err, "Authorization")
 	}
+	if bq.configuration.GetCacheOnlyMessage != nil {
+		if message, cacheOnly := bq.configuration.GetCacheOnlyMessage(instanceName); cacheOnly {
+			if message == "" {
+				return status.Errorf(codes.PermissionDenied, "Instance name %#v is cache-only, and does not permit remote execution", instanceName.String())
+			}
+			return status.Errorf(codes.PermissionDenied, "Instance name %#v is cache-only, and does not permit remote execution: %s", instanceName.String(), message)
+		}
+	}
 
 	digestFunction, err := instanceName.GetDigestFunction(in.DigestFunction, len(in.ActionDigest.GetHash()))
 	if err != nil {
 		return err
 	}
+	if digestFunctionValue := digestFunction.GetEnumValue(); !bq.configuration.isDigestFunctionPermitted(instanceName, digestFunctionValue) {
+		return status.Errorf(codes.InvalidArgument, "Digest function %s is not permitted for instance name %#v", digestFunctionValue, instanceName.String())
+	}
 	actionDigest, err := digestFunction.NewDigestFromProto(in.ActionDigest)
 	if err != nil {
 		return util.StatusWrap(err, "Failed to extract digest for action")
@@ -412,8 +1033,11 @@ func (bq *InMemoryBuildQueue) Execute(in *remoteexecution.ExecuteRequest, out re
 	}
 
 	// Forward the client-provided authentication and request
-	// metadata, so that the worker logs it.
-	auxiliaryMetadata := make([]*anypb.Any, 0, 2)
+	// metadata, so that the worker logs it. VCS metadata is forwarded
+	// as well, so that workers may expose it to the action. As
+	// identical actions are deduplicated, actions only observe the
+	// VCS metadata of the client that caused them to be queued.
+	auxiliaryMetadata := make([]*anypb.Any, 0, 3)
 	if authenticationMetadata, shouldDisplay := auth.AuthenticationMetadataFromContext(ctx).GetPublicProto(); shouldDisplay {
 		authenticationMetadataAny, err := anypb.New(authenticationMetadata)
 		if err != nil {
@@ -429,38 +1053,107 @@ func (bq *InMemoryBuildQueue) Execute(in *remoteexecution.ExecuteRequest, out re
 		}
 		auxiliaryMetadata = append(auxiliaryMetadata, requestMetadataAny)
 	}
+	vcsMetadata, err := getVCSMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	if vcsMetadata != nil {
+		vcsMetadataAny, err := anypb.New(vcsMetadata)
+		if err != nil {
+			return util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to marshal VCS metadata")
+		}
+		auxiliaryMetadata = append(auxiliaryMetadata, vcsMetadataAny)
+	}
 	w3cTraceContext := otel.W3CTraceContextFromContext(ctx)
 
-	platformKey, invocationKeys, initialSizeClassSelector, err := bq.actionRouter.RouteAction(ctx, actionDigest.GetDigestFunction(), action, requestMetadata)
+	// Forward the full authentication metadata, so that workers can
+	// perform requests on behalf of the client (e.g., for actions
+	// that use nested execution).
+	var authenticationMetadata *auth_pb.AuthenticationMetadata
+	if fullAuthenticationMetadata := auth.AuthenticationMetadataFromContext(ctx).GetFullProto(); fullAuthenticationMetadata.Public != nil || fullAuthenticationMetadata.Private != nil {
+		authenticationMetadata = fullAuthenticationMetadata
+	}
+
+	earliestStartTime, err := getEarliestStartTime(ctx)
+	if err != nil {
+		return err
+	}
+	actionWeight, err := re_builder.GetActionWeight(action.Platform)
+	if err != nil {
+		return err
+	}
+	routedAction := action
+	reservedWorkersCount := 0
+	if policy := bq.configuration.WorkerReservationPolicy; policy != nil {
+		reservedWorkersCount, routedAction, err = policy.getReservedWorkersCount(action)
+		if err != nil {
+			return err
+		}
+	}
+
+	platformKey, invocationKeys, initialSizeClassSelector, err := bq.actionRouter.RouteAction(ctx, actionDigest.GetDigestFunction(), routedAction, requestMetadata)
 	if err != nil {
 		return util.StatusWrap(err, "Failed to route action")
 	}
+	invocationWeight := bq.configuration.getInvocationWeight(ctx, instanceName)
+	clientQuotaKey, err := bq.getClientQuotaKey(ctx, instanceName)
+	if err != nil {
+		initialSizeClassSelector.Abandoned()
+		return err
+	}
 
 	bq.enter(bq.clock.Now())
 	defer bq.leave()
 
-	if t, ok := bq.inFlightDeduplicationMap[actionDigest]; ok {
+	cqs, err := bq.consumeClientQuotaRequest(clientQuotaKey)
+	if err != nil {
+		initialSizeClassSelector.Abandoned()
+		return err
+	}
+
+	// Actions whose execution is delayed are not deduplicated
+	// against tasks that are in flight, as that would cause them
+	// to run before the requested start time.
+	isDelayed := earliestStartTime.After(bq.now)
+	if isDelayed {
+		if maximumStartTime := bq.now.Add(bq.configuration.MaximumExecutionDelay); earliestStartTime.After(maximumStartTime) {
+			initialSizeClassSelector.Abandoned()
+			return status.Errorf(codes.InvalidArgument, "Earliest start time %s exceeds the maximum permitted start time %s", earliestStartTime.UTC().Format(time.RFC3339), maximumStartTime.UTC().Format(time.RFC3339))
+		}
+	} else if t, ok := bq.getInFlightDeduplicationTask(actionDigest, platformKey); ok {
 		// A task for the same action digest already exists
 		// against which we may deduplicate. No need to create a
 		// task.
 		initialSizeClassSelector.Abandoned()
 		scq := t.getCurrentSizeClassQueue()
-		i := scq.getOrCreateInvocation(bq, invocationKeys)
+		i := scq.getOrCreateInvocation(bq, invocationKeys, invocationWeight)
 		if o, ok := t.operations[i]; ok {
 			// Task is already associated with the current
 			// invocation. Simply wait on the operation that
 			// already exists.
 			scq.inFlightDeduplicationsSameInvocation.Inc()
+			i.requestWorkerReservation(bq, reservedWorkersCount)
 			return o.waitExecution(bq, out)
 		}
 
 		// Create an additional operation for this task.
+		if err := cqs.checkConcurrentOperations(); err != nil {
+			// Remove the invocation that was created above,
+			// as it won't have any operations.
+			for i.removeIfEmpty() {
+				i = i.parent
+			}
+			return err
+		}
 		o := t.newOperation(bq, in.ExecutionPolicy.GetPriority(), i, false)
+		o.acquireClientQuota(bq, cqs)
+		o.instanceName = instanceName
+		o.clientDeadline, _ = ctx.Deadline()
 		switch t.getStage() {
 		case remoteexecution.ExecutionStage_QUEUED:
 			// The request has been deduplicated against a
 			// task that is still queued.
-			o.enqueue()
+			o.enqueue(bq)
 		case remoteexecution.ExecutionStage_EXECUTING:
 			// The request has been deduplicated against a
 			// task that is already in the executing stage.
@@ -469,6 +1162,9 @@ func (bq *InMemoryBuildQueue) Execute(in *remoteexecution.ExecuteRequest, out re
 			panic("Task in unexpected stage")
 		}
 		scq.inFlightDeduplicationsOtherInvocation.Inc()
+		i.requestWorkerReservation(bq, reservedWorkersCount)
+		o.journaled = bq.configuration.OperationJournal != nil
+		o.journal(bq)
 		return o.waitExecution(bq, out)
 	}
 
@@ -493,6 +1189,17 @@ func (bq *InMemoryBuildQueue) Execute(in *remoteexecution.ExecuteRequest, out re
 		return status.Errorf(code, "No workers exist for instance name prefix %#v platform %s", platformKey.GetInstanceNamePrefix().String(), platformKey.GetPlatformString())
 	}
 	pq := bq.platformQueues[platformQueueIndex]
+	if pq.maximumQueuedOperations > 0 && pq.getQueuedTasksCount() >= pq.maximumQueuedOperations {
+		// Apply backpressure, so that the amount of memory
+		// used by queued operations remains bounded when
+		// workers are unable to keep up.
+		initialSizeClassSelector.Abandoned()
+		return bq.newQueueFullError(pq)
+	}
+	if err := cqs.checkConcurrentOperations(); err != nil {
+		initialSizeClassSelector.Abandoned()
+		return err
+	}
 	sizeClassIndex, expectedDuration, timeout, initialSizeClassLearner := initialSizeClassSelector.Select(pq.sizeClasses)
 	scq := pq.sizeClassQueues[sizeClassIndex]
 
@@ -510,22 +1217,93 @@ func (bq *InMemoryBuildQueue) Execute(in *remoteexecution.ExecuteRequest, out re
 			InstanceNameSuffix: pq.instanceNamePatcher.PatchInstanceName(instanceName).String(),
 			DigestFunction:     digestFunction.GetEnumValue(),
 			W3CTraceContext:    w3cTraceContext,
+
+			AuthenticationMetadata: authenticationMetadata,
 		},
 		targetID:                requestMetadata.GetTargetId(),
+		actionMnemonic:          requestMetadata.GetActionMnemonic(),
+		weight:                  actionWeight,
 		expectedDuration:        expectedDuration,
 		initialSizeClassLearner: initialSizeClassLearner,
 		stageChangeWakeup:       make(chan struct{}),
 	}
-	if !action.DoNotCache {
-		bq.inFlightDeduplicationMap[actionDigest] = t
+	if !action.DoNotCache && !isDelayed {
+		bq.inFlightDeduplicationMap[bq.getInFlightDeduplicationKey(actionDigest)] = t
 		scq.inFlightDeduplicationsNew.Inc()
 	}
-	i := scq.getOrCreateInvocation(bq, invocationKeys)
-	o := t.newOperation(bq, in.ExecutionPolicy.GetPriority(), i, false)
-	t.schedule(bq)
+	// Operations of delayed tasks may exist without waiters until
+	// completion, so that clients don't need to remain connected
+	// while waiting for execution to start. Clients may reattach
+	// to the operation by calling WaitExecution().
+	i := scq.getOrCreateInvocation(bq, invocationKeys, invocationWeight)
+	o := t.newOperation(bq, in.ExecutionPolicy.GetPriority(), i, isDelayed)
+	o.acquireClientQuota(bq, cqs)
+	o.clientDeadline, _ = ctx.Deadline()
+	i.requestWorkerReservation(bq, reservedWorkersCount)
+	o.journaled = bq.configuration.OperationJournal != nil
+	if isDelayed {
+		t.delay(bq, earliestStartTime)
+		o.journal(bq)
+	} else {
+		// Persist the operation before scheduling it, as
+		// scheduling may assign it to a worker directly, which
+		// causes the operation to be journaled once more.
+		o.journal(bq)
+		t.schedule(bq)
+	}
 	return o.waitExecution(bq, out)
 }
 
+// newQueueFullError creates the error that is returned by Execute()
+// when a platform queue already contains the maximum number of queued
+// operations. The error contains a RetryInfo message, indicating when
+// the client may retry.
+func (bq *InMemoryBuildQueue) newQueueFullError(pq *platformQueue) error {
+	s, err := status.Newf(
+		codes.ResourceExhausted,
+		"Platform queue for instance name prefix %#v platform %s already contains %d queued operations, which is the maximum permitted",
+		pq.platformKey.GetInstanceNamePrefix().String(),
+		pq.platformKey.GetPlatformString(),
+		pq.maximumQueuedOperations,
+	).WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(bq.configuration.QueueFullRetryDelay),
+	})
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to attach retry information to error")
+	}
+	return s.Err()
+}
+
+// getInFlightDeduplicationKey returns the key under which a task for a
+// given action is stored in the in-flight deduplication map.
+func (bq *InMemoryBuildQueue) getInFlightDeduplicationKey(actionDigest digest.Digest) string {
+	if bq.configuration.InFlightDeduplicationAcrossInstanceNames {
+		return actionDigest.GetKey(digest.KeyWithoutInstance)
+	}
+	return actionDigest.GetKey(digest.KeyWithInstance)
+}
+
+// getInFlightDeduplicationTask returns a task that is in flight against
+// which an Execute() request for a given action may be deduplicated.
+func (bq *InMemoryBuildQueue) getInFlightDeduplicationTask(actionDigest digest.Digest, platformKey platform.Key) (*task, bool) {
+	t, ok := bq.inFlightDeduplicationMap[bq.getInFlightDeduplicationKey(actionDigest)]
+	if !ok {
+		return nil, false
+	}
+	if t.actionDigest != actionDigest {
+		// The task was created using a different instance
+		// name. Only deduplicate against it if the request
+		// would have ended up in the same platform queue, as
+		// the request may otherwise end up running on workers
+		// that are not intended to be used by this client.
+		platformQueueIndex := bq.platformQueuesTrie.GetLongestPrefix(platformKey)
+		if platformQueueIndex < 0 || bq.platformQueues[platformQueueIndex] != t.getCurrentSizeClassQueue().platformQueue {
+			return nil, false
+		}
+	}
+	return t, true
+}
+
 // WaitExecution attaches to an existing operation that was created by
 // Execute(). This call can be used by the client to reattach to an
 // operation in case of network failure.
@@ -537,7 +1315,7 @@ func (bq *InMemoryBuildQueue) WaitExecution(in *remoteexecution.WaitExecutionReq
 			bq.leave()
 			return status.Errorf(codes.NotFound, "Operation with name %#v not found", in.Name)
 		}
-		instanceName := o.task.actionDigest.GetInstanceName()
+		instanceName := o.instanceName
 
 		// Ensure that the caller is permitted to access this operation.
 		// This must be done without holding any locks, as the authorizer
@@ -546,10 +1324,17 @@ func (bq *InMemoryBuildQueue) WaitExecution(in *remoteexecution.WaitExecutionReq
 		if err := auth.AuthorizeSingleInstanceName(out.Context(), bq.executeAuthorizer, instanceName); err != nil {
 			return util.StatusWrap(err, "Authorization")
 		}
+		clientQuotaKey, err := bq.getClientQuotaKey(out.Context(), instanceName)
+		if err != nil {
+			return err
+		}
 
 		bq.enter(bq.clock.Now())
 		if bq.operationsNameMap[in.Name] == o {
 			defer bq.leave()
+			if _, err := bq.consumeClientQuotaRequest(clientQuotaKey); err != nil {
+				return err
+			}
 			return o.waitExecution(bq, out)
 		}
 	}
@@ -608,7 +1393,7 @@ func (bq *InMemoryBuildQueue) Synchronize(ctx context.Context, request *remotewo
 			// pair has not been observed before. Create a
 			// new platform queue containing a single size
 			// class queue.
-			pq = bq.addPlatformQueue(platformKey, nil, 0, 0)
+			pq = bq.addPlatformQueue(platformKey, nil, 0, 0, 0, nil, nil, nil)
 		}
 		scq = pq.addSizeClassQueue(bq, request.SizeClass, true)
 	}
@@ -622,9 +1407,23 @@ func (bq *InMemoryBuildQueue) Synchronize(ctx context.Context, request *remotewo
 		}
 		bq.cleanupQueue.remove(w.cleanupKey)
 	} else {
-		// First time we're seeing this worker. As this worker
-		// has never run an action before (that we know about),
-		// associate it with the root invocation.
+		if previousScq, ok := bq.workerSizeClassQueues[workerKey]; ok {
+			// Worker changed its platform properties or
+			// size class. Remove it from the size class
+			// queue it was registered in previously, so
+			// that it no longer receives work from there.
+			previousW := previousScq.workers[workerKey]
+			if !previousW.cleanupKey.isActive() {
+				return nil, status.Error(codes.ResourceExhausted, "Worker is already synchronizing with the scheduler")
+			}
+			bq.cleanupQueue.remove(previousW.cleanupKey)
+			previousScq.removeStaleWorker(bq, workerKey, bq.now)
+		}
+
+		// First time we're seeing this worker in this size
+		// class queue. As this worker has never run an action
+		// before (that we know about), associate it with the
+		// root invocation.
 		i := &scq.rootInvocation
 		w = &worker{
 			workerKey:               workerKey,
@@ -635,6 +1434,22 @@ func (bq *InMemoryBuildQueue) Synchronize(ctx context.Context, request *remotewo
 		i.idleWorkersCount++
 		scq.workers[workerKey] = w
 		scq.workersCreatedTotal.Inc()
+		if !w.isDrained(scq, workerKey.getWorkerID()) {
+			scq.undrainedWorkersCount++
+			pq.updateHealth(bq)
+		}
+		scq.maybeRecoverWorkerStatistics(bq, w)
+		bq.workerSizeClassQueues[workerKey] = scq
+	}
+
+	w.resources = request.Resources
+	w.executionSlots = request.ExecutionSlots
+	w.expectedTerminationTime = time.Time{}
+	if expectedTerminationTime := request.ExpectedTerminationTime; expectedTerminationTime != nil {
+		if err := expectedTerminationTime.CheckValid(); err != nil {
+			return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid expected termination time")
+		}
+		w.expectedTerminationTime = expectedTerminationTime.AsTime()
 	}
 
 	// Install cleanup handlers to ensure stale workers and queues
@@ -652,6 +1467,7 @@ func (bq *InMemoryBuildQueue) Synchronize(ctx context.Context, request *remotewo
 	if currentState == nil {
 		return nil, status.Error(codes.InvalidArgument, "Worker did not provide its current state")
 	}
+	w.maybeReattachRecoveredTask(bq, scq, currentState)
 	switch workerState := currentState.WorkerState.(type) {
 	case *remoteworker.CurrentState_Idle:
 		return w.getCurrentOrNextTask(ctx, bq, scq, request.WorkerId, request.PreferBeingIdle)
@@ -663,8 +1479,10 @@ func (bq *InMemoryBuildQueue) Synchronize(ctx context.Context, request *remotewo
 		switch executionState := executing.ExecutionState.(type) {
 		case *remoteworker.CurrentState_Executing_Completed:
 			return w.completeTask(ctx, bq, scq, request.WorkerId, executing.ActionDigest, executionState.Completed, request.PreferBeingIdle)
+		case *remoteworker.CurrentState_Executing_Rejected:
+			return w.rejectTask(ctx, bq, scq, request.WorkerId, executing.ActionDigest, request.PreferBeingIdle)
 		default:
-			return w.updateTask(bq, scq, request.WorkerId, executing.ActionDigest, request.PreferBeingIdle)
+			return w.updateTask(bq, scq, request.WorkerId, executing.ActionDigest, request.PreferBeingIdle, request.AcceptTentativeAssignments)
 		}
 	default:
 		return nil, status.Error(codes.InvalidArgument, "Worker provided an unknown current state")
@@ -687,11 +1505,19 @@ func (bq *InMemoryBuildQueue) ListPlatformQueues(ctx context.Context, request *e
 		sizeClassQueues := make([]*buildqueuestate.SizeClassQueueState, 0, len(pq.sizeClassQueues))
 		for i, scq := range pq.sizeClassQueues {
 			sizeClassQueues = append(sizeClassQueues, &buildqueuestate.SizeClassQueueState{
-				SizeClass:      pq.sizeClasses[i],
-				Timeout:        bq.cleanupQueue.getTimestamp(scq.cleanupKey),
-				RootInvocation: scq.rootInvocation.getInvocationState(bq),
-				WorkersCount:   uint32(len(scq.workers)),
-				DrainsCount:    uint32(len(scq.drains)),
+				SizeClass:           pq.sizeClasses[i],
+				Timeout:             bq.cleanupQueue.getTimestamp(scq.cleanupKey),
+				RootInvocation:      scq.rootInvocation.getInvocationState(bq),
+				WorkersCount:        uint32(len(scq.workers)),
+				DrainsCount:         uint32(len(scq.drains)),
+				DrainedWorkersCount: uint32(len(scq.workers) - scq.undrainedWorkersCount),
+
+				RecommendedWorkersCount: scq.getRecommendedWorkersCount(bq),
+
+				DispatchedTasksCount:          scq.tasksDispatchedCount,
+				DispatchedTasksQueuedDuration: durationpb.New(scq.tasksDispatchedQueuedDuration),
+
+				ExecutingDurationHistogram: scq.getExecutingDurationHistogram(),
 			})
 		}
 		platformQueues = append(platformQueues, &buildqueuestate.PlatformQueueState{
@@ -793,11 +1619,90 @@ func (bq *InMemoryBuildQueue) KillOperations(ctx context.Context, request *build
 		}
 		scq.rootInvocation.cancelAllQueuedOperations(bq, request.Status)
 		return &emptypb.Empty{}, nil
+	case *buildqueuestate.KillOperationsRequest_Filter_InvocationId:
+		invocationKey, err := scheduler_invocation.NewKey(filter.InvocationId)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Invalid invocation key")
+		}
+		return bq.killMatchingTasks(ctx, request.Status, func(o *operation) bool {
+			return o.invocation.hasInvocationKey(invocationKey)
+		})
+	case *buildqueuestate.KillOperationsRequest_Filter_InstanceNamePrefix:
+		instanceNamePrefix, err := digest.NewInstanceName(filter.InstanceNamePrefix)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Invalid instance name prefix %#v", filter.InstanceNamePrefix)
+		}
+		return bq.killMatchingTasks(ctx, request.Status, func(o *operation) bool {
+			return instanceNameHasPrefix(o.instanceName, instanceNamePrefix)
+		})
 	default:
 		return nil, status.Error(codes.InvalidArgument, "Unknown filter provided")
 	}
 }
 
+// getMatchingTasks returns all tasks in the QUEUED or EXECUTING stage
+// that have one or more operations for which a filter returns true.
+func (bq *InMemoryBuildQueue) getMatchingTasks(filter func(o *operation) bool) map[*task]struct{} {
+	tasks := map[*task]struct{}{}
+	for _, o := range bq.operationsNameMap {
+		if t := o.task; t.getStage() != remoteexecution.ExecutionStage_COMPLETED && filter(o) {
+			tasks[t] = struct{}{}
+		}
+	}
+	return tasks
+}
+
+// killMatchingTasks kills all tasks in the QUEUED or EXECUTING stage
+// that have one or more operations for which a filter returns true.
+// The caller must be authorized to kill operations for all instance
+// name prefixes of the size class queues in which these tasks reside.
+func (bq *InMemoryBuildQueue) killMatchingTasks(ctx context.Context, status *status_pb.Status, filter func(o *operation) bool) (*emptypb.Empty, error) {
+	// Extract the instance name prefixes of the size class queues
+	// to which the tasks belong.
+	bq.enter(bq.clock.Now())
+	instanceNamePrefixes := map[string]digest.InstanceName{}
+	for t := range bq.getMatchingTasks(filter) {
+		instanceNamePrefix := t.getCurrentSizeClassQueue().getKey().platformKey.GetInstanceNamePrefix()
+		instanceNamePrefixes[instanceNamePrefix.String()] = instanceNamePrefix
+	}
+	bq.leave()
+
+	// Perform authorization checks without holding any locks.
+	for _, instanceNamePrefix := range instanceNamePrefixes {
+		if err := auth.AuthorizeSingleInstanceName(ctx, bq.killOperationsAuthorizer, instanceNamePrefix); err != nil {
+			return nil, util.StatusWrap(err, "Authorization")
+		}
+	}
+
+	// Kill the tasks that still match after reacquiring the lock.
+	// Tasks that were created in the meantime in size class queues
+	// for which no authorization checks were performed are left
+	// intact.
+	bq.enter(bq.clock.Now())
+	defer bq.leave()
+	for t := range bq.getMatchingTasks(filter) {
+		if _, ok := instanceNamePrefixes[t.getCurrentSizeClassQueue().getKey().platformKey.GetInstanceNamePrefix().String()]; ok {
+			t.complete(bq, &remoteexecution.ExecuteResponse{Status: status}, false)
+		}
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// instanceNameHasPrefix returns whether an instance name is equal to
+// or is located underneath a given instance name prefix.
+func instanceNameHasPrefix(instanceName, instanceNamePrefix digest.InstanceName) bool {
+	components, prefixComponents := instanceName.GetComponents(), instanceNamePrefix.GetComponents()
+	if len(components) < len(prefixComponents) {
+		return false
+	}
+	for i, component := range prefixComponents {
+		if components[i] != component {
+			return false
+		}
+	}
+	return true
+}
+
 // ListOperations returns detailed information about all of the
 // operations tracked by the InMemoryBuildQueue.
 func (bq *InMemoryBuildQueue) ListOperations(ctx context.Context, request *buildqueuestate.ListOperationsRequest) (*buildqueuestate.ListOperationsResponse, error) {
@@ -810,14 +1715,29 @@ func (bq *InMemoryBuildQueue) ListOperations(ctx context.Context, request *build
 		invocationKey = &key
 	}
 
+	var minimumStageDuration time.Duration
+	if request.FilterMinimumStageDuration != nil {
+		if err := request.FilterMinimumStageDuration.CheckValid(); err != nil {
+			return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid minimum stage duration")
+		}
+		minimumStageDuration = request.FilterMinimumStageDuration.AsDuration()
+	}
+	actionDigestHash := strings.ToLower(request.FilterActionDigestHash)
+
 	bq.enter(bq.clock.Now())
 	defer bq.leave()
 
 	// Obtain operation names in sorted order.
 	nameList := make([]string, 0, len(bq.operationsNameMap))
 	for name, o := range bq.operationsNameMap {
+		t := o.task
 		if (invocationKey == nil || o.invocation.hasInvocationKey(*invocationKey)) &&
-			(request.FilterStage == remoteexecution.ExecutionStage_UNKNOWN || request.FilterStage == o.task.getStage()) {
+			(request.FilterStage == remoteexecution.ExecutionStage_UNKNOWN || request.FilterStage == t.getStage()) &&
+			(request.FilterActionMnemonic == "" || request.FilterActionMnemonic == t.actionMnemonic) &&
+			strings.Contains(t.targetID, request.FilterTargetId) &&
+			(len(request.FilterWorkerId) == 0 || (t.currentWorker != nil && workerIDContains(t.currentWorker.workerKey.getWorkerID(), request.FilterWorkerId))) &&
+			(request.FilterMinimumStageDuration == nil || (t.getStage() != remoteexecution.ExecutionStage_COMPLETED && bq.now.Sub(t.currentStageStartTime) >= minimumStageDuration)) &&
+			strings.Contains(t.actionDigest.GetHashString(), actionDigestHash) {
 			nameList = append(nameList, name)
 		}
 	}
@@ -839,6 +1759,17 @@ func (bq *InMemoryBuildQueue) ListOperations(ctx context.Context, request *build
 	}, nil
 }
 
+// workerIDContains returns whether a worker ID contains all of the
+// key-value pairs of another worker ID.
+func workerIDContains(workerID, filter map[string]string) bool {
+	for key, value := range filter {
+		if workerValue, ok := workerID[key]; !ok || workerValue != value {
+			return false
+		}
+	}
+	return true
+}
+
 func (bq *InMemoryBuildQueue) getSizeClassQueueByName(name *buildqueuestate.SizeClassQueueName) (*sizeClassQueue, error) {
 	sizeClassKey, err := newSizeClassKeyFromName(name)
 	if err != nil {
@@ -949,6 +1880,8 @@ func (bq *InMemoryBuildQueue) ListQueuedOperations(ctx context.Context, request
 
 	startAfter := request.StartAfter
 	var startAfterExpectedDuration time.Duration
+	var startAfterEffectivePriority float64
+	var startAfterLatestStartTime time.Time
 	var startAfterQueuedTimestamp time.Time
 	if startAfter != nil {
 		if err := startAfter.ExpectedDuration.CheckValid(); err != nil {
@@ -960,6 +1893,14 @@ func (bq *InMemoryBuildQueue) ListQueuedOperations(ctx context.Context, request
 			return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid queued timestamp")
 		}
 		startAfterQueuedTimestamp = startAfter.QueuedTimestamp.AsTime()
+		startAfterEffectivePriority = bq.configuration.getEffectivePriority(startAfter.Priority, startAfter.QueuedTimestamp)
+
+		if startAfter.LatestStartTimestamp != nil {
+			if err := startAfter.LatestStartTimestamp.CheckValid(); err != nil {
+				return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid latest start timestamp")
+			}
+			startAfterLatestStartTime = startAfter.LatestStartTimestamp.AsTime()
+		}
 	}
 
 	// As every sorted list is also a valid binary heap, simply sort
@@ -967,10 +1908,16 @@ func (bq *InMemoryBuildQueue) ListQueuedOperations(ctx context.Context, request
 	sort.Sort(i.queuedOperations)
 	paginationInfo, endIndex := getPaginationInfo(i.queuedOperations.Len(), request.PageSize, func(idx int) bool {
 		o := i.queuedOperations[idx]
-		if startAfter == nil || o.priority > startAfter.Priority {
+		if startAfter == nil || o.effectivePriority > startAfterEffectivePriority {
 			return true
 		}
-		if o.priority < startAfter.Priority {
+		if o.effectivePriority < startAfterEffectivePriority {
+			return false
+		}
+		if o.latestStartTime.After(startAfterLatestStartTime) {
+			return true
+		}
+		if o.latestStartTime.Before(startAfterLatestStartTime) {
 			return false
 		}
 		t := o.task
@@ -1077,12 +2024,21 @@ func (bq *InMemoryBuildQueue) ListWorkers(ctx context.Context, request *buildque
 			currentOperation.InvocationName = nil
 			currentOperation.Stage = nil
 		}
+		var quarantinedUntil *timestamppb.Timestamp
+		if w.isQuarantined(bq) {
+			quarantinedUntil = timestamppb.New(w.quarantinedUntil)
+		}
 		workerID := workerKey.getWorkerID()
 		workers = append(workers, &buildqueuestate.WorkerState{
-			Id:               workerID,
-			Timeout:          bq.cleanupQueue.getTimestamp(w.cleanupKey),
-			CurrentOperation: currentOperation,
-			Drained:          w.isDrained(scq, workerID),
+			Id:                              workerID,
+			Timeout:                         bq.cleanupQueue.getTimestamp(w.cleanupKey),
+			CurrentOperation:                currentOperation,
+			Drained:                         w.isDrained(scq, workerID),
+			Resources:                       w.resources,
+			InsufficientResources:           w.hasInsufficientResources(scq),
+			RecentTasksCount:                uint32(len(w.recentTaskInfrastructureErrors)),
+			RecentInfrastructureErrorsCount: uint32(w.getRecentInfrastructureErrorsCount()),
+			QuarantinedUntil:                quarantinedUntil,
 		})
 	}
 	return &buildqueuestate.ListWorkersResponse{
@@ -1141,6 +2097,17 @@ func (bq *InMemoryBuildQueue) modifyDrain(ctx context.Context, request *buildque
 		return nil, status.Error(codes.NotFound, "Size class queue not found")
 	}
 	modifyFunc(scq, string(drainKey))
+
+	// Adding or removing drains may change whether the platform
+	// queue has any workers that are capable of executing
+	// operations.
+	scq.undrainedWorkersCount = 0
+	for workerKey, w := range scq.workers {
+		if !w.isDrained(scq, workerKey.getWorkerID()) {
+			scq.undrainedWorkersCount++
+		}
+	}
+	scq.platformQueue.updateHealth(bq)
 	return &emptypb.Empty{}, nil
 }
 
@@ -1187,7 +2154,7 @@ func (bq *InMemoryBuildQueue) TerminateWorkers(ctx context.Context, request *bui
 	for _, scq := range bq.sizeClassQueues {
 		for workerKey, w := range scq.workers {
 			if workerMatchesPattern(workerKey.getWorkerID(), request.WorkerIdPattern) {
-				scq.markWorkerTerminating(w)
+				scq.markWorkerTerminating(bq, w)
 				if t := w.currentTask; t != nil {
 					// The task will be at the
 					// EXECUTING stage, so it can
@@ -1261,16 +2228,23 @@ func (bq *InMemoryBuildQueue) getIdleSynchronizeResponse() *remoteworker.Synchro
 }
 
 // addPlatformQueue creates a new platform queue for a given platform.
-func (bq *InMemoryBuildQueue) addPlatformQueue(platformKey platform.Key, workerInvocationStickinessLimits []time.Duration, maximumQueuedBackgroundLearningOperations int, backgroundLearningOperationPriority int32) *platformQueue {
+func (bq *InMemoryBuildQueue) addPlatformQueue(platformKey platform.Key, workerInvocationStickinessLimits []time.Duration, maximumQueuedBackgroundLearningOperations int, backgroundLearningOperationPriority int32, maximumQueuedOperations int, preemptionPolicy *PreemptionPolicy, workerResourceRequirements map[uint32]WorkerResourceRequirements, sizeClassEscalationPolicy *SizeClassEscalationPolicy) *platformQueue {
 	pq := &platformQueue{
 		platformKey:                               platformKey,
 		instanceNamePatcher:                       digest.NewInstanceNamePatcher(platformKey.GetInstanceNamePrefix(), digest.EmptyInstanceName),
 		workerInvocationStickinessLimits:          workerInvocationStickinessLimits,
 		maximumQueuedBackgroundLearningOperations: maximumQueuedBackgroundLearningOperations,
 		backgroundLearningOperationPriority:       backgroundLearningOperationPriority,
+		maximumQueuedOperations:                   maximumQueuedOperations,
+		preemptionPolicy:                          preemptionPolicy,
+		workerResourceRequirements:                workerResourceRequirements,
+		sizeClassEscalationPolicy:                 sizeClassEscalationPolicy,
 	}
 	bq.platformQueuesTrie.Set(platformKey, len(bq.platformQueues))
 	bq.platformQueues = append(bq.platformQueues, pq)
+	if reporter := bq.configuration.PlatformQueueHealthReporter; reporter != nil {
+		reporter.SetServingStatus(GetPlatformQueueHealthServiceName(platformKey), grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	}
 	return pq
 }
 
@@ -1343,9 +2317,42 @@ type platformQueue struct {
 	workerInvocationStickinessLimits          []time.Duration
 	maximumQueuedBackgroundLearningOperations int
 	backgroundLearningOperationPriority       int32
+	maximumQueuedOperations                   int
+	preemptionPolicy                          *PreemptionPolicy
+	workerResourceRequirements                map[uint32]WorkerResourceRequirements
+	sizeClassEscalationPolicy                 *SizeClassEscalationPolicy
 
 	sizeClasses     []uint32
 	sizeClassQueues []*sizeClassQueue
+
+	// Whether the platform queue was last reported as being
+	// healthy to PlatformQueueHealthReporter.
+	healthy bool
+}
+
+// updateHealth reports whether the platform queue has any workers that
+// are capable of executing operations, if this has changed since the
+// last time it was reported.
+func (pq *platformQueue) updateHealth(bq *InMemoryBuildQueue) {
+	reporter := bq.configuration.PlatformQueueHealthReporter
+	if reporter == nil {
+		return
+	}
+	healthy := false
+	for _, scq := range pq.sizeClassQueues {
+		if scq.undrainedWorkersCount > 0 {
+			healthy = true
+			break
+		}
+	}
+	if pq.healthy != healthy {
+		pq.healthy = healthy
+		servingStatus := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+		if healthy {
+			servingStatus = grpc_health_v1.HealthCheckResponse_SERVING
+		}
+		reporter.SetServingStatus(GetPlatformQueueHealthServiceName(pq.platformKey), servingStatus)
+	}
 }
 
 // getSizeClassQueueLabels returns the set of label values to attach to
@@ -1356,6 +2363,69 @@ func (pq *platformQueue) getSizeClassQueueLabels(sizeClass uint32) (string, stri
 		strconv.FormatUint(uint64(sizeClass), 10)
 }
 
+// getQueuedTasksCount returns the number of tasks that are queued
+// across all size classes of the platform queue.
+func (pq *platformQueue) getQueuedTasksCount() int {
+	queuedTasksCount := 0
+	for _, scq := range pq.sizeClassQueues {
+		queuedTasksCount += scq.queuedTasksCount
+	}
+	return queuedTasksCount
+}
+
+// PreemptionPolicy specifies under which conditions InMemoryBuildQueue
+// may preempt tasks that are executing within a platform queue, so
+// that operations having a higher priority can be executed instead.
+// Preempted tasks are requeued, meaning they are executed once more at
+// a later point in time.
+type PreemptionPolicy struct {
+	// MaximumPriority is the highest REv2 execution priority value
+	// of operations that may cause other tasks to be preempted. As
+	// REv2 priorities are inverted, this permits limiting
+	// preemption to important (e.g., interactive) operations.
+	MaximumPriority int32
+
+	// QueuedDuration is the amount of time such an operation needs
+	// to be queued before a task is preempted.
+	QueuedDuration time.Duration
+}
+
+// SizeClassEscalationPolicy specifies how tasks are adjusted when they
+// are retried on the largest size class of a platform queue, after
+// failing on a smaller size class. Such failures may have been caused
+// by the task running out of time or resources, meaning that granting
+// it more of them increases the probability that the retry succeeds.
+//
+// Tasks that are retried under this policy have a SizeClassEscalation
+// message attached to their auxiliary metadata. This message describes
+// the attempts that failed, and communicates the resource limits
+// multiplier to workers.
+type SizeClassEscalationPolicy struct {
+	// TimeoutMultiplier is the factor by which the timeout of the
+	// task is scaled.
+	TimeoutMultiplier float64
+
+	// ResourceLimitsMultiplier is the factor by which workers scale
+	// resource limits of the task, such as the maximum size of
+	// files allocated from the FilePool.
+	ResourceLimitsMultiplier float64
+}
+
+// WorkerResourceRequirements specifies the resources that workers in a
+// given size class need to report to be available to them, for them to
+// receive tasks. This prevents tasks from being assigned to workers that
+// are, for example, low on disk space due to other workloads running on
+// the same system.
+type WorkerResourceRequirements struct {
+	MinimumBuildDirectoryFreeBytes uint64
+	MinimumAvailableMemoryBytes    uint64
+
+	// MaximumLoadAverage is the highest one minute load average
+	// workers may report. If zero, the load average is not taken
+	// into account.
+	MaximumLoadAverage float64
+}
+
 func (pq *platformQueue) addSizeClassQueue(bq *InMemoryBuildQueue, sizeClass uint32, mayBeRemoved bool) *sizeClassQueue {
 	instanceNamePrefix, platformStr, sizeClassStr := pq.getSizeClassQueueLabels(sizeClass)
 	platformLabels := map[string]string{
@@ -1370,10 +2440,16 @@ func (pq *platformQueue) addSizeClassQueue(bq *InMemoryBuildQueue, sizeClass uin
 		mayBeRemoved:  mayBeRemoved,
 
 		rootInvocation: invocation{
+			weight:           1,
 			children:         map[scheduler_invocation.Key]*invocation{},
 			executingWorkers: map[*worker]int{},
 		},
-		workers: map[workerKey]*worker{},
+		workers:          map[workerKey]*worker{},
+		delayedTasks:     map[*task]struct{}{},
+		recoveredTasks:   map[workerKey]*task{},
+		recoveredWorkers: map[workerKey]*schedulerstatistics.Worker{},
+
+		executingDurationHistogramCounts: make([]uint64, len(executingDurationHistogramUpperBounds)+1),
 
 		drains:        map[string]*buildqueuestate.DrainState{},
 		undrainWakeup: make(chan struct{}),
@@ -1382,20 +2458,29 @@ func (pq *platformQueue) addSizeClassQueue(bq *InMemoryBuildQueue, sizeClass uin
 		inFlightDeduplicationsOtherInvocation: inMemoryBuildQueueInFlightDeduplicationsTotal.WithLabelValues(instanceNamePrefix, platformStr, sizeClassStr, "OtherInvocation"),
 		inFlightDeduplicationsNew:             inMemoryBuildQueueInFlightDeduplicationsTotal.WithLabelValues(instanceNamePrefix, platformStr, sizeClassStr, "New"),
 
+		inputRootAffinityHit:  inMemoryBuildQueueInputRootAffinityTotal.WithLabelValues(instanceNamePrefix, platformStr, sizeClassStr, "Hit"),
+		inputRootAffinityMiss: inMemoryBuildQueueInputRootAffinityTotal.WithLabelValues(instanceNamePrefix, platformStr, sizeClassStr, "Miss"),
+
 		tasksScheduledWorker:          newTasksScheduledCounterVec(tasksScheduledTotal, "Worker"),
 		tasksScheduledQueue:           newTasksScheduledCounterVec(tasksScheduledTotal, "Queue"),
 		tasksQueuedDurationSeconds:    inMemoryBuildQueueTasksQueuedDurationSeconds.WithLabelValues(instanceNamePrefix, platformStr, sizeClassStr),
 		tasksExecutingDurationSeconds: inMemoryBuildQueueTasksExecutingDurationSeconds.MustCurryWith(platformLabels),
 		tasksExecutingRetries:         inMemoryBuildQueueTasksExecutingRetries.MustCurryWith(platformLabels),
 		tasksCompletedDurationSeconds: inMemoryBuildQueueTasksCompletedDurationSeconds.WithLabelValues(instanceNamePrefix, platformStr, sizeClassStr),
+		tasksPreemptedTotal:           inMemoryBuildQueueTasksPreemptedTotal.WithLabelValues(instanceNamePrefix, platformStr, sizeClassStr),
+		tasksRejectedTotal:            inMemoryBuildQueueTasksRejectedTotal.WithLabelValues(instanceNamePrefix, platformStr, sizeClassStr),
 
 		workersCreatedTotal:          inMemoryBuildQueueWorkersCreatedTotal.WithLabelValues(instanceNamePrefix, platformStr, sizeClassStr),
 		workersTerminatingTotal:      inMemoryBuildQueueWorkersTerminatingTotal.WithLabelValues(instanceNamePrefix, platformStr, sizeClassStr),
+		workersQuarantinedTotal:      inMemoryBuildQueueWorkersQuarantinedTotal.WithLabelValues(instanceNamePrefix, platformStr, sizeClassStr),
 		workersRemovedIdleTotal:      inMemoryBuildQueueWorkersRemovedTotal.WithLabelValues(instanceNamePrefix, platformStr, sizeClassStr, "Idle"),
 		workersRemovedExecutingTotal: inMemoryBuildQueueWorkersRemovedTotal.WithLabelValues(instanceNamePrefix, platformStr, sizeClassStr, "Executing"),
 
 		workerInvocationStickinessRetained: inMemoryBuildQueueWorkerInvocationStickinessRetained.WithLabelValues(instanceNamePrefix, platformStr, sizeClassStr),
 	}
+	if m := bq.platformPropertyMetrics; m != nil {
+		scq.platformPropertyMetrics = m.newSizeClassQueueMetrics(instanceNamePrefix, pq.platformKey.GetPlatformQueueName().Platform, sizeClassStr)
+	}
 	scq.rootInvocation.sizeClassQueue = scq
 	scq.incrementInvocationsCreatedTotal(0)
 
@@ -1421,6 +2506,7 @@ func (pq *platformQueue) addSizeClassQueue(bq *InMemoryBuildQueue, sizeClass uin
 	pq.sizeClassQueues[i] = scq
 
 	bq.sizeClassQueues[scq.getKey()] = scq
+	scq.maybeRecoverStatistics(bq)
 
 	return scq
 }
@@ -1461,14 +2547,58 @@ type sizeClassQueue struct {
 	workers        map[workerKey]*worker
 	cleanupKey     cleanupKey
 
+	// Tasks whose scheduling is delayed until their earliest start
+	// time is reached.
+	delayedTasks map[*task]struct{}
+
+	// Tasks recovered from the operation journal that were
+	// executing prior to the scheduler restarting, keyed by the
+	// worker that was executing them. These tasks are delayed,
+	// giving the worker the opportunity to reattach to them.
+	recoveredTasks map[workerKey]*task
+
 	drains        map[string]*buildqueuestate.DrainState
 	undrainWakeup chan struct{}
 
+	// The number of workers that are neither drained, nor
+	// terminating. Used to determine the health of the platform
+	// queue without iterating over all workers.
+	undrainedWorkersCount int
+
+	// The number of workers in this size class queue that are
+	// reserved for an invocation.
+	reservedWorkersCount int
+
+	// Statistics used to compute the recommended number of
+	// workers for this size class queue.
+	queuedTasksCount          int
+	tasksArrived              exponentiallyDecayingSum
+	tasksExecuted             exponentiallyDecayingSum
+	tasksExecutingDurationSum exponentiallyDecayingSum
+
+	// Histogram of the amount of time tasks spent in the
+	// EXECUTING stage. It contains one more bucket than
+	// executingDurationHistogramUpperBounds.
+	executingDurationHistogramCounts []uint64
+
+	// Statistics of workers that were persisted by a previous
+	// instance of the scheduler, which are applied when the worker
+	// synchronizes against the scheduler for the first time.
+	recoveredWorkers map[workerKey]*schedulerstatistics.Worker
+
+	// Cumulative statistics on tasks leaving the QUEUED stage,
+	// which may be sampled to compute the dispatch latency.
+	tasksDispatchedCount          uint64
+	tasksDispatchedQueuedDuration time.Duration
+
 	// Prometheus metrics.
 	inFlightDeduplicationsSameInvocation  prometheus.Counter
 	inFlightDeduplicationsOtherInvocation prometheus.Counter
 	inFlightDeduplicationsNew             prometheus.Counter
 
+	inputRootAffinityHit  prometheus.Counter
+	inputRootAffinityMiss prometheus.Counter
+
 	invocationsMetrics []invocationsMetrics
 
 	tasksScheduledWorker          tasksScheduledCounterVec
@@ -1477,13 +2607,20 @@ type sizeClassQueue struct {
 	tasksExecutingDurationSeconds prometheus.ObserverVec
 	tasksExecutingRetries         prometheus.ObserverVec
 	tasksCompletedDurationSeconds prometheus.Observer
+	tasksPreemptedTotal           prometheus.Counter
+	tasksRejectedTotal            prometheus.Counter
 
 	workersCreatedTotal          prometheus.Counter
 	workersTerminatingTotal      prometheus.Counter
+	workersQuarantinedTotal      prometheus.Counter
 	workersRemovedIdleTotal      prometheus.Counter
 	workersRemovedExecutingTotal prometheus.Counter
 
 	workerInvocationStickinessRetained prometheus.Observer
+
+	// Prometheus metrics labeled by selected platform properties.
+	// Only set if PlatformPropertyMetricLabels is configured.
+	platformPropertyMetrics *sizeClassQueuePlatformPropertyMetrics
 }
 
 func (scq *sizeClassQueue) getKey() sizeClassKey {
@@ -1493,6 +2630,45 @@ func (scq *sizeClassQueue) getKey() sizeClassKey {
 	}
 }
 
+// preemptLowerPriorityTask preempts the task with the lowest priority
+// that is executing within the size class queue, so that an operation
+// having a given priority can be executed instead. Preemption only
+// takes place if no idle workers are available, and if the task has a
+// strictly lower priority than the operation. When multiple tasks have
+// the same priority, the one that started executing most recently is
+// preempted, as this causes the least amount of work to be lost.
+//
+// The preempted task is requeued. The worker executing it is
+// instructed to execute another task the next time it synchronizes
+// against the scheduler.
+func (scq *sizeClassQueue) preemptLowerPriorityTask(bq *InMemoryBuildQueue, priority int32) bool {
+	var victim *task
+	var victimPriority int32
+	for _, w := range scq.workers {
+		t := w.currentTask
+		if t == nil {
+			if !w.isDrained(scq, w.workerKey.getWorkerID()) {
+				// Idle workers are available. There is
+				// no need to preempt anything.
+				return false
+			}
+			continue
+		}
+		taskPriority := t.getPriority()
+		if taskPriority > priority && (victim == nil || taskPriority > victimPriority || (taskPriority == victimPriority && t.currentStageStartTime.After(victim.currentStageStartTime))) {
+			victim = t
+			victimPriority = taskPriority
+		}
+	}
+	if victim == nil {
+		return false
+	}
+
+	victim.currentWorker.requeueCurrentTask(bq, scq)
+	scq.tasksPreemptedTotal.Inc()
+	return true
+}
+
 // remove is invoked when Synchronize() isn't being invoked by any
 // worker for a given platform quickly enough. It causes the platform
 // queue and all associated queued operations to be removed from the
@@ -1504,6 +2680,17 @@ func (scq *sizeClassQueue) remove(bq *InMemoryBuildQueue) {
 			codes.Unavailable,
 			"Workers for this instance name, platform and size class disappeared while task was queued",
 		).Proto())
+	for t := range scq.delayedTasks {
+		t.complete(
+			bq,
+			&remoteexecution.ExecuteResponse{
+				Status: status.New(
+					codes.Unavailable,
+					"Workers for this instance name, platform and size class disappeared while task was delayed",
+				).Proto(),
+			},
+			/* completedByWorker = */ false)
+	}
 	scq.invocationsMetrics[0].removedTotal.Inc()
 
 	delete(bq.sizeClassQueues, scq.getKey())
@@ -1526,6 +2713,9 @@ func (scq *sizeClassQueue) remove(bq *InMemoryBuildQueue) {
 		bq.platformQueues = bq.platformQueues[:newLength]
 		bq.platformQueuesTrie.Set(lastPQ.platformKey, index)
 		bq.platformQueuesTrie.Remove(pq.platformKey)
+		if reporter := bq.configuration.PlatformQueueHealthReporter; reporter != nil {
+			reporter.SetServingStatus(GetPlatformQueueHealthServiceName(pq.platformKey), grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN)
+		}
 	}
 }
 
@@ -1534,7 +2724,7 @@ func (scq *sizeClassQueue) remove(bq *InMemoryBuildQueue) {
 // the InMemoryBuildQueue.
 func (scq *sizeClassQueue) removeStaleWorker(bq *InMemoryBuildQueue, workerKey workerKey, removalTime time.Time) {
 	w := scq.workers[workerKey]
-	scq.markWorkerTerminating(w)
+	scq.markWorkerTerminating(bq, w)
 	if t := w.currentTask; t == nil {
 		scq.workersRemovedIdleTotal.Inc()
 	} else {
@@ -1543,8 +2733,14 @@ func (scq *sizeClassQueue) removeStaleWorker(bq *InMemoryBuildQueue, workerKey w
 			Status: status.Newf(codes.Unavailable, "Worker %s disappeared while task was executing", workerKey).Proto(),
 		}, false)
 	}
+	if r := w.reservation; r != nil {
+		r.removeWorker(w)
+	}
 	w.clearLastInvocation()
 	delete(scq.workers, workerKey)
+	if bq.workerSizeClassQueues[workerKey] == scq {
+		delete(bq.workerSizeClassQueues, workerKey)
+	}
 
 	// Trigger platform queue removal if necessary.
 	if len(scq.workers) == 0 && scq.mayBeRemoved {
@@ -1561,7 +2757,9 @@ func (scq *sizeClassQueue) removeStaleWorker(bq *InMemoryBuildQueue, workerKey w
 // As the invocation may be just created, the caller must either queue
 // an operation, increase the executing operations count, place a worker
 // in the invocation or call invocation.removeIfEmpty().
-func (scq *sizeClassQueue) getOrCreateInvocation(bq *InMemoryBuildQueue, invocationKeys []scheduler_invocation.Key) *invocation {
+//
+// The provided weight is only applied to invocations that are created.
+func (scq *sizeClassQueue) getOrCreateInvocation(bq *InMemoryBuildQueue, invocationKeys []scheduler_invocation.Key, weight float64) *invocation {
 	i := &scq.rootInvocation
 	for depth, invocationKey := range invocationKeys {
 		iChild, ok := i.children[invocationKey]
@@ -1570,6 +2768,8 @@ func (scq *sizeClassQueue) getOrCreateInvocation(bq *InMemoryBuildQueue, invocat
 				sizeClassQueue:                        scq,
 				invocationKeys:                        invocationKeys[:depth+1],
 				parent:                                i,
+				weight:                                weight,
+				maximumExecutingWorkers:               bq.configuration.MaximumExecutingOperationsPerInvocation,
 				children:                              map[scheduler_invocation.Key]*invocation{},
 				queuedChildrenIndex:                   -1,
 				executingWorkers:                      map[*worker]int{},
@@ -1608,10 +2808,31 @@ func (scq *sizeClassQueue) incrementInvocationsCreatedTotal(depth int) {
 	scq.invocationsMetrics[depth].createdTotal.Inc()
 }
 
-func (scq *sizeClassQueue) markWorkerTerminating(w *worker) {
+// peekNextQueuedTask returns the queued task that is most likely to be
+// assigned to the next worker requesting work, without unqueuing it.
+// Unlike worker.assignNextQueuedTask(), this ignores worker invocation
+// stickiness.
+func (scq *sizeClassQueue) peekNextQueuedTask() *task {
+	i := &scq.rootInvocation
+	for {
+		if len(i.queuedOperations) > 0 {
+			return i.queuedOperations[0].task
+		} else if len(i.queuedChildren) > 0 && !i.queuedChildren[0].isAtConcurrencyLimit() {
+			i = i.queuedChildren[0]
+		} else {
+			return nil
+		}
+	}
+}
+
+func (scq *sizeClassQueue) markWorkerTerminating(bq *InMemoryBuildQueue, w *worker) {
 	if !w.terminating {
 		scq.workersTerminatingTotal.Inc()
+		if !w.isDrained(scq, w.workerKey.getWorkerID()) {
+			scq.undrainedWorkersCount--
+		}
 		w.terminating = true
+		scq.platformQueue.updateHealth(bq)
 	}
 }
 
@@ -1650,6 +2871,12 @@ func (h queuedChildrenHeap) Len() int {
 }
 
 func (h queuedChildrenHeap) Less(i, j int) bool {
+	// Invocations that have reached their concurrency limit are
+	// placed at the end, as none of their operations may be
+	// started.
+	if li, lj := h[i].isAtConcurrencyLimit(), h[j].isAtConcurrencyLimit(); li != lj {
+		return lj
+	}
 	return h[i].isPreferred(h[j], h[i].lastOperationStarted.Before(h[j].lastOperationStarted))
 }
 
@@ -1760,10 +2987,20 @@ type invocation struct {
 	sizeClassQueue *sizeClassQueue
 	invocationKeys []scheduler_invocation.Key
 	parent         *invocation
+	// The weight of the invocation relative to its siblings. An
+	// invocation with weight 2 is permitted to run twice as many
+	// operations concurrently as an invocation with weight 1.
+	weight float64
+	// The maximum number of workers that may execute operations
+	// belonging to this invocation concurrently. If zero, the
+	// number of workers is not limited.
+	maximumExecutingWorkers int
 
 	// Heap of operations that are part of this invocation that are
-	// currently in the QUEUED stage.
-	queuedOperations queuedOperationsHeap
+	// currently in the QUEUED stage. The same operations are also
+	// indexed by expected duration.
+	queuedOperations                   queuedOperationsHeap
+	queuedOperationsByExpectedDuration queuedOperationsDurationIndex
 
 	// All nested invocations for which one or more operations or
 	// workers exist.
@@ -1782,12 +3019,12 @@ type invocation struct {
 	// sizeClassQueue.idleSynchronizingWorkersChildren heap.
 	idleSynchronizingWorkersChildrenIndex int
 
-	// The priority of the operation that is either queued in this
-	// invocation or one of its children that is expected to be
-	// executed next. This value needs to be tracked to ensure the
-	// queuedChildren heap of the parent invocation is ordered
-	// correctly.
-	firstQueuedOperationPriority int32
+	// The effective priority of the operation that is either
+	// queued in this invocation or one of its children that is
+	// expected to be executed next. This value needs to be tracked
+	// to ensure the queuedChildren heap of the parent invocation is
+	// ordered correctly.
+	firstQueuedOperationPriority float64
 
 	// Number of workers that are executing operations that belong
 	// to this invocation. This equals the number of operations that
@@ -1802,10 +3039,19 @@ type invocation struct {
 	// Number of workers that are idle and most recently completed
 	// an operation belonging to this invocation.
 	idleWorkersCount uint32
+	// Number of operations belonging to this invocation or one of
+	// its children whose execution is delayed. These operations are
+	// not queued, but still prevent the invocation from being
+	// removed.
+	delayedOperationsCount uint
 	// List of workers that are idle and most recently executed an
 	// operation belonging to this invocation and are currently
 	// synchronizing against the scheduler.
 	idleSynchronizingWorkers idleSynchronizingWorkersList
+	// If set, operations of this invocation requested that workers
+	// are reserved for it. The reservation prevents the invocation
+	// from being removed until it is released.
+	workerReservation *workerReservation
 }
 
 // isQueued returns whether an invocation has one or more queued
@@ -1815,6 +3061,17 @@ func (i *invocation) isQueued() bool {
 	return i.queuedOperations.Len() > 0 || i.queuedChildren.Len() > 0
 }
 
+// isAtConcurrencyLimit returns whether the number of workers executing
+// operations belonging to this invocation has reached its limit,
+// meaning no further operations of this invocation may be started.
+//
+// As workers executing operations of an invocation are also accounted
+// to its parents, an invocation can only be at its limit if all of its
+// children are at their limit as well.
+func (i *invocation) isAtConcurrencyLimit() bool {
+	return i.maximumExecutingWorkers > 0 && len(i.executingWorkers) >= i.maximumExecutingWorkers
+}
+
 // isActive returns whether an invocation has one or more queued or
 // executing operations. These are generally the ones that users of the
 // BuildQueueState service want to view.
@@ -1826,7 +3083,7 @@ func (i *invocation) isActive() bool {
 // containing any operations or workers). If so, it removes the
 // invocation from the size class queue in which it is contained.
 func (i *invocation) removeIfEmpty() bool {
-	if i.parent != nil && !i.isActive() && i.idleWorkersCount == 0 {
+	if i.parent != nil && !i.isActive() && i.idleWorkersCount == 0 && i.delayedOperationsCount == 0 && i.workerReservation == nil {
 		depth := len(i.invocationKeys)
 		invocationKey := i.invocationKeys[depth-1]
 		if i.parent.children[invocationKey] != i {
@@ -1839,6 +3096,25 @@ func (i *invocation) removeIfEmpty() bool {
 	return false
 }
 
+// incrementDelayedOperationsCount increments the number of delayed
+// operations of the invocation and all of its parents.
+func (i *invocation) incrementDelayedOperationsCount() {
+	for ; i != nil; i = i.parent {
+		i.delayedOperationsCount++
+	}
+}
+
+// decrementDelayedOperationsCount decrements the number of delayed
+// operations of the invocation and all of its parents.
+func (i *invocation) decrementDelayedOperationsCount() {
+	for ; i != nil; i = i.parent {
+		if i.delayedOperationsCount == 0 {
+			panic("Invalid delayed operations count")
+		}
+		i.delayedOperationsCount--
+	}
+}
+
 func (i *invocation) getInvocationState(bq *InMemoryBuildQueue) *buildqueuestate.InvocationState {
 	activeInvocationsCount := uint32(0)
 	for _, iChild := range i.children {
@@ -1943,7 +3219,7 @@ func (i *invocation) isPreferred(j *invocation, tieBreaker bool) bool {
 	// according to the following expression, where the invocation
 	// with the lowest score is most favourable.
 	//
-	// S = (executingWorkersCount + 1) * b^priority
+	// S = (executingWorkersCount + 1) / weight * b^priority
 	//
 	// Note that REv2 priorities are inverted; the lower the integer
 	// value, the higher the priority. The '+ 1' part has been added
@@ -1954,9 +3230,13 @@ func (i *invocation) isPreferred(j *invocation, tieBreaker bool) bool {
 	// 2^0.01 =~ 1.007. This means that if the difference in
 	// priority between two builds is 100, one build will be allowed
 	// to run twice as many operations as the other.
-	ei, ej := float64(len(i.executingWorkers)+1), float64(len(j.executingWorkers)+1)
+	//
+	// The number of executing operations is divided by the weight
+	// of the invocation, so that invocations with a higher weight
+	// receive a proportionally larger share of the workers.
+	ei, ej := float64(len(i.executingWorkers)+1)/i.weight, float64(len(j.executingWorkers)+1)/j.weight
 	var si, sj float64
-	if pi, pj := float64(i.firstQueuedOperationPriority), float64(j.firstQueuedOperationPriority); pi < pj {
+	if pi, pj := i.firstQueuedOperationPriority, j.firstQueuedOperationPriority; pi < pj {
 		// Invocation i has a higher priority. Give invocation j
 		// a penalty based on the difference in priority.
 		si, sj = ei, ej*math.Pow(priorityExponentiationBase, pj-pi)
@@ -1991,7 +3271,7 @@ func (i *invocation) updateFirstOperationPriority() {
 	// operations stored directly underneath an invocation over ones
 	// stored in child invocations, so we do the same thing here.
 	if i.queuedOperations.Len() > 0 {
-		i.firstQueuedOperationPriority = i.queuedOperations[0].priority
+		i.firstQueuedOperationPriority = i.queuedOperations[0].effectivePriority
 	} else if len(i.queuedChildren) > 0 {
 		i.firstQueuedOperationPriority = i.queuedChildren[0].firstQueuedOperationPriority
 	}
@@ -2006,24 +3286,7 @@ func (h queuedOperationsHeap) Len() int {
 }
 
 func (h queuedOperationsHeap) Less(i, j int) bool {
-	// Lexicographic order on priority, expected duration and queued
-	// timestamp. By executing operations with a higher expected
-	// duration first, we reduce the probability of having poor
-	// concurrency at the final stages of a build.
-	if h[i].priority < h[j].priority {
-		return true
-	}
-	if h[i].priority > h[j].priority {
-		return false
-	}
-	ti, tj := h[i].task, h[j].task
-	if ti.expectedDuration > tj.expectedDuration {
-		return true
-	}
-	if ti.expectedDuration < tj.expectedDuration {
-		return false
-	}
-	return ti.desiredState.QueuedTimestamp.AsTime().Before(tj.desiredState.QueuedTimestamp.AsTime())
+	return h[i].isMorePreferableThan(h[j])
 }
 
 func (h queuedOperationsHeap) Swap(i, j int) {
@@ -2057,6 +3320,201 @@ func (h *queuedOperationsHeap) Pop() interface{} {
 	return o
 }
 
+// isMorePreferableThan returns whether an operation needs to be
+// assigned to a worker before another operation.
+func (o *operation) isMorePreferableThan(other *operation) bool {
+	// Lexicographic order on effective priority, latest start
+	// time, expected duration and queued timestamp. By executing
+	// operations with a higher expected duration first, we reduce
+	// the probability of having poor concurrency at the final
+	// stages of a build.
+	if o.effectivePriority < other.effectivePriority {
+		return true
+	}
+	if o.effectivePriority > other.effectivePriority {
+		return false
+	}
+	if o.latestStartTime.Before(other.latestStartTime) {
+		return true
+	}
+	if o.latestStartTime.After(other.latestStartTime) {
+		return false
+	}
+	ti, tj := o.task, other.task
+	if ti.expectedDuration > tj.expectedDuration {
+		return true
+	}
+	if ti.expectedDuration < tj.expectedDuration {
+		return false
+	}
+	return ti.desiredState.QueuedTimestamp.AsTime().Before(tj.desiredState.QueuedTimestamp.AsTime())
+}
+
+// queuedOperationsDurationIndex is a treap of queued operations, sorted
+// by expected duration. Every node tracks the most preferable operation
+// contained in its subtree. This makes it possible to find the most
+// preferable operation that is expected to complete within a given
+// amount of time in O(log n) time, which is needed to assign operations
+// to workers that are expected to terminate soon.
+//
+// Operations having the same expected duration are sorted by
+// preferability in reverse order. This causes the most preferable
+// operation to be the rightmost one having the maximum expected
+// duration.
+type queuedOperationsDurationIndex struct {
+	root               *operation
+	nextNodePriorities uint64
+}
+
+// queuedOperationsDurationIndexNode contains the state of an operation
+// that is needed to store it in queuedOperationsDurationIndex.
+type queuedOperationsDurationIndexNode struct {
+	left           *operation
+	right          *operation
+	priority       uint64
+	mostPreferable *operation
+}
+
+// isLessByExpectedDuration returns whether an operation is placed
+// before another operation in queuedOperationsDurationIndex.
+func (o *operation) isLessByExpectedDuration(other *operation) bool {
+	if o.task.expectedDuration < other.task.expectedDuration {
+		return true
+	}
+	if o.task.expectedDuration > other.task.expectedDuration {
+		return false
+	}
+	if other.isMorePreferableThan(o) {
+		return true
+	}
+	if o.isMorePreferableThan(other) {
+		return false
+	}
+	// Operations are otherwise equal. Use the operation name to
+	// obtain a total order.
+	return o.name < other.name
+}
+
+// getMorePreferable returns the most preferable of two operations,
+// either of which may be nil.
+func getMorePreferable(a, b *operation) *operation {
+	if a == nil || (b != nil && b.isMorePreferableThan(a)) {
+		return b
+	}
+	return a
+}
+
+// updateMostPreferable recomputes the most preferable operation in the
+// subtree rooted at a node, after its children have been altered.
+func (o *operation) updateMostPreferable() {
+	n := &o.durationIndexNode
+	n.mostPreferable = o
+	if n.left != nil {
+		n.mostPreferable = getMorePreferable(n.mostPreferable, n.left.durationIndexNode.mostPreferable)
+	}
+	if n.right != nil {
+		n.mostPreferable = getMorePreferable(n.mostPreferable, n.right.durationIndexNode.mostPreferable)
+	}
+}
+
+// split a subtree into nodes that are placed before an operation and
+// nodes that are placed after it.
+func (idx *queuedOperationsDurationIndex) split(t, o *operation) (*operation, *operation) {
+	if t == nil {
+		return nil, nil
+	}
+	if t.isLessByExpectedDuration(o) {
+		left, right := idx.split(t.durationIndexNode.right, o)
+		t.durationIndexNode.right = left
+		t.updateMostPreferable()
+		return t, right
+	}
+	left, right := idx.split(t.durationIndexNode.left, o)
+	t.durationIndexNode.left = right
+	t.updateMostPreferable()
+	return left, t
+}
+
+// merge two subtrees, where all nodes in the left subtree are placed
+// before all nodes in the right subtree.
+func (idx *queuedOperationsDurationIndex) merge(left, right *operation) *operation {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	if left.durationIndexNode.priority > right.durationIndexNode.priority {
+		left.durationIndexNode.right = idx.merge(left.durationIndexNode.right, right)
+		left.updateMostPreferable()
+		return left
+	}
+	right.durationIndexNode.left = idx.merge(left, right.durationIndexNode.left)
+	right.updateMostPreferable()
+	return right
+}
+
+// insert an operation into the index.
+func (idx *queuedOperationsDurationIndex) insert(o *operation) {
+	// Derive node priorities from a counter using SplitMix64, so
+	// that the treap remains balanced regardless of the order in
+	// which operations are inserted.
+	idx.nextNodePriorities += 0x9e3779b97f4a7c15
+	z := idx.nextNodePriorities
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	o.durationIndexNode = queuedOperationsDurationIndexNode{
+		priority: z ^ (z >> 31),
+	}
+	o.updateMostPreferable()
+
+	left, right := idx.split(idx.root, o)
+	idx.root = idx.merge(idx.merge(left, o), right)
+}
+
+// remove an operation from the index.
+func (idx *queuedOperationsDurationIndex) remove(o *operation) {
+	idx.root = idx.removeFromSubtree(idx.root, o)
+	o.durationIndexNode = queuedOperationsDurationIndexNode{}
+}
+
+func (idx *queuedOperationsDurationIndex) removeFromSubtree(t, o *operation) *operation {
+	if t == nil {
+		panic("Operation is not present in the index")
+	}
+	if t == o {
+		return idx.merge(o.durationIndexNode.left, o.durationIndexNode.right)
+	}
+	if o.isLessByExpectedDuration(t) {
+		t.durationIndexNode.left = idx.removeFromSubtree(t.durationIndexNode.left, o)
+	} else {
+		t.durationIndexNode.right = idx.removeFromSubtree(t.durationIndexNode.right, o)
+	}
+	t.updateMostPreferable()
+	return t
+}
+
+// getMostPreferableWithExpectedDurationAtMost returns the most
+// preferable operation whose expected duration does not exceed a
+// given value.
+func (idx *queuedOperationsDurationIndex) getMostPreferableWithExpectedDurationAtMost(maximumExpectedDuration time.Duration) (*operation, bool) {
+	var best *operation
+	for t := idx.root; t != nil; {
+		if t.task.expectedDuration <= maximumExpectedDuration {
+			// This node and its entire left subtree
+			// qualify. Continue searching to the right.
+			best = getMorePreferable(best, t)
+			if left := t.durationIndexNode.left; left != nil {
+				best = getMorePreferable(best, left.durationIndexNode.mostPreferable)
+			}
+			t = t.durationIndexNode.right
+		} else {
+			t = t.durationIndexNode.left
+		}
+	}
+	return best, best != nil
+}
+
 // operation that a client can use to reference a task.
 //
 // The difference between operations and tasks is that tasks manage the
@@ -2073,25 +3531,61 @@ type operation struct {
 	task     *task
 	priority int32
 
+	// The instance name that was provided by the client when
+	// creating this operation. This may differ from the instance
+	// name of the task if in-flight deduplication across instance
+	// names is enabled.
+	instanceName digest.InstanceName
+
+	// The priority of the operation, adjusted for the point in time
+	// at which it was queued. This value is computed when the
+	// operation is enqueued, and is used to order the queued
+	// operations heap.
+	effectivePriority float64
+
+	// The deadline of the client's Execute() call, if any.
+	clientDeadline time.Time
+
+	// The latest point in time at which the operation needs to
+	// start executing to complete before its deadline. This value
+	// is computed when the operation is enqueued, and is left at
+	// the zero value if deadline-aware scheduling is disabled.
+	latestStartTime time.Time
+
 	// The invocation of which this operation is a part. queueIndex
 	// contains the index at which the operation is stored in the
 	// invocation's queuedOperations heap. When negative, it means
 	// that the operation is no longer in the queued stage (and thus
-	// either in the executing or completed stage).
+	// either in the executing or completed stage). durationIndexNode
+	// contains the position of the operation in the invocation's
+	// queuedOperationsByExpectedDuration index.
 	//
 	// Because invocations are managed per size class, an operation
 	// may move from one invocation to another one it is retried as
 	// part of a different size class. All invocations of which this
 	// operation is a part during its lifetime will have the same
 	// invocation ID.
-	invocation *invocation
-	queueIndex int
+	invocation        *invocation
+	queueIndex        int
+	durationIndexNode queuedOperationsDurationIndexNode
 
 	// Number of clients that are calling Execute() or
 	// WaitExecution() on this operation.
 	waiters                uint
 	mayExistWithoutWaiters bool
 	cleanupKey             cleanupKey
+
+	// Used to preempt a task of lower priority if the operation
+	// remains queued for too long.
+	preemptionKey cleanupKey
+
+	// Whether the state of this operation is persisted in the
+	// operation journal.
+	journaled bool
+
+	// The quota of the client that created this operation, which is
+	// charged while the operation is queued or executing.
+	clientQuotaState *clientQuotaState
 }
 
 // waitExecution periodically streams a series of longrunningpb.Operation
@@ -2171,9 +3665,13 @@ func (o *operation) waitExecution(bq *InMemoryBuildQueue, out remoteexecution.Ex
 // state from the invocation. If the invocation no longer has any queued
 // operations, it will be removed from the queued invocations heap in
 // the containing platform queue.
-func (o *operation) removeQueuedFromInvocation() {
+func (o *operation) removeQueuedFromInvocation(bq *InMemoryBuildQueue) {
+	if o.preemptionKey.isActive() {
+		bq.cleanupQueue.remove(o.preemptionKey)
+	}
 	i := o.invocation
 	heap.Remove(&i.queuedOperations, o.queueIndex)
+	i.queuedOperationsByExpectedDuration.remove(o)
 	i.maybeDeactivate()
 	for i.parent != nil {
 		i.updateFirstOperationPriority()
@@ -2187,20 +3685,51 @@ func (o *operation) removeQueuedFromInvocation() {
 // an invocation. This method is called whenever an operation can't be
 // assigned to a worker immediately, due to no idle synchronizing
 // workers for this size class queue being available.
-func (o *operation) enqueue() {
+func (o *operation) enqueue(bq *InMemoryBuildQueue) {
+	t := o.task
+	o.effectivePriority = bq.configuration.getEffectivePriority(o.priority, t.desiredState.QueuedTimestamp)
+	if bq.configuration.DeadlineAwareScheduling {
+		deadline := t.desiredState.QueuedTimestamp.AsTime().Add(t.desiredState.Action.Timeout.AsDuration())
+		if !o.clientDeadline.IsZero() && o.clientDeadline.Before(deadline) {
+			deadline = o.clientDeadline
+		}
+		o.latestStartTime = deadline.Add(-t.expectedDuration)
+	}
+
 	i := o.invocation
 	i.maybeActivate()
 	heap.Push(&i.queuedOperations, o)
+	i.queuedOperationsByExpectedDuration.insert(o)
 	for i.parent != nil {
 		i.updateFirstOperationPriority()
 		i.parent.maybeActivate()
 		heapPushOrFix(&i.parent.queuedChildren, i.queuedChildrenIndex, i)
 		i = i.parent
 	}
+	o.maybeSchedulePreemption(bq)
+}
+
+// maybeSchedulePreemption schedules the preemption of a task having a
+// lower priority than a queued operation, if the operation remains
+// queued for longer than permitted by the platform queue's preemption
+// policy.
+func (o *operation) maybeSchedulePreemption(bq *InMemoryBuildQueue) {
+	scq := o.task.getCurrentSizeClassQueue()
+	if policy := scq.platformQueue.preemptionPolicy; policy != nil && o.priority <= policy.MaximumPriority {
+		bq.cleanupQueue.add(&o.preemptionKey, bq.now.Add(policy.QueuedDuration), func() {
+			if !scq.preemptLowerPriorityTask(bq, o.priority) {
+				// No task could be preempted. Try again
+				// later, as workers may have become busy
+				// by then.
+				o.maybeSchedulePreemption(bq)
+			}
+		})
+	}
 }
 
 func (o *operation) remove(bq *InMemoryBuildQueue) {
 	delete(bq.operationsNameMap, o.name)
+	o.removeFromJournal(bq)
 
 	t := o.task
 	if len(t.operations) == 1 {
@@ -2216,13 +3745,14 @@ func (o *operation) remove(bq *InMemoryBuildQueue) {
 		i := o.invocation
 		switch t.getStage() {
 		case remoteexecution.ExecutionStage_QUEUED:
-			o.removeQueuedFromInvocation()
+			o.removeQueuedFromInvocation(bq)
 			for i.removeIfEmpty() {
 				i = i.parent
 			}
 		case remoteexecution.ExecutionStage_EXECUTING:
 			i.decrementExecutingWorkersCount(bq, t.currentWorker)
 		}
+		o.releaseClientQuota(bq)
 	}
 	delete(t.operations, o.invocation)
 }
@@ -2249,6 +3779,10 @@ func (o *operation) getOperationState(bq *InMemoryBuildQueue) *buildqueuestate.O
 		Priority:           o.priority,
 		InstanceNameSuffix: t.desiredState.InstanceNameSuffix,
 		DigestFunction:     t.desiredState.DigestFunction,
+		ActionMnemonic:     t.actionMnemonic,
+	}
+	if !o.latestStartTime.IsZero() {
+		s.LatestStartTimestamp = timestamppb.New(o.latestStartTime)
 	}
 	switch t.getStage() {
 	case remoteexecution.ExecutionStage_QUEUED:
@@ -2267,6 +3801,51 @@ func (o *operation) getOperationState(bq *InMemoryBuildQueue) *buildqueuestate.O
 	return s
 }
 
+// journal writes the current state of the operation to the operation
+// journal, if the operation is persisted.
+func (o *operation) journal(bq *InMemoryBuildQueue) {
+	if !o.journaled {
+		return
+	}
+	i := o.invocation
+	t := o.task
+	sizeClassKey := t.getCurrentSizeClassQueue().getKey()
+	invocationIDs := make([]*anypb.Any, 0, len(i.invocationKeys))
+	for _, invocationKey := range i.invocationKeys {
+		invocationIDs = append(invocationIDs, invocationKey.GetID())
+	}
+	operation := &operationjournal.Operation{
+		Name:               o.name,
+		InstanceName:       o.instanceName.String(),
+		Priority:           o.priority,
+		InvocationIds:      invocationIDs,
+		InvocationWeight:   i.weight,
+		SizeClassQueueName: sizeClassKey.getSizeClassQueueName(),
+		DesiredState:       &t.desiredState,
+		ExpectedDuration:   durationpb.New(t.expectedDuration),
+		TargetId:           t.targetID,
+		ActionInstanceName: t.actionDigest.GetInstanceName().String(),
+		ActionMnemonic:     t.actionMnemonic,
+	}
+	if w := t.currentWorker; w != nil {
+		operation.WorkerId = w.workerKey.getWorkerID()
+	}
+	if t.isDelayed() {
+		operation.EarliestStartTimestamp = bq.cleanupQueue.getTimestamp(t.delayKey)
+	}
+	bq.configuration.OperationJournal.PutOperation(operation)
+}
+
+// removeFromJournal removes the operation from the operation journal,
+// if the operation is persisted. This is done when the operation
+// completes or is removed.
+func (o *operation) removeFromJournal(bq *InMemoryBuildQueue) {
+	if o.journaled {
+		bq.configuration.OperationJournal.RemoveOperation(o.name)
+		o.journaled = false
+	}
+}
+
 func (o *operation) maybeStartCleanup(bq *InMemoryBuildQueue) {
 	if o.waiters == 0 && !o.mayExistWithoutWaiters {
 		bq.cleanupQueue.add(&o.cleanupKey, bq.now.Add(bq.configuration.OperationWithNoWaitersTimeout), func() {
@@ -2289,7 +3868,8 @@ type task struct {
 	// field is not strictly necessary to implement the BuildQueue
 	// and OperationQueueServer interfaces. It needs to be present
 	// to implement BuildQueueState.
-	targetID string
+	targetID       string
+	actionMnemonic string
 
 	// currentStageStartTime is used by register*StageFinished() to
 	// obtain Prometheus metrics.
@@ -2302,10 +3882,24 @@ type task struct {
 	currentWorker *worker
 	retryCount    int
 
+	// The number of execution slots of a worker that the task
+	// consumes, as specified by the "resources:cores" platform
+	// property.
+	weight int
+
 	expectedDuration        time.Duration
 	initialSizeClassLearner initialsizeclass.Learner
 	mayExistWithoutWaiters  bool
 
+	// When active, the task is delayed and will be scheduled once
+	// the corresponding entry in the cleanup queue expires.
+	delayKey cleanupKey
+
+	// If the task is delayed because it was recovered from the
+	// operation journal, the worker that was executing it prior to
+	// the scheduler restarting.
+	recoveredWorkerKey workerKey
+
 	executeResponse   *remoteexecution.ExecuteResponse
 	stageChangeWakeup chan struct{}
 }
@@ -2316,10 +3910,18 @@ type task struct {
 // additional operations to an existing task in case of in-flight
 // deduplication.
 func (t *task) newOperation(bq *InMemoryBuildQueue, priority int32, i *invocation, mayExistWithoutWaiters bool) *operation {
+	return t.newOperationWithName(bq, uuid.Must(bq.uuidGenerator()).String(), priority, i, mayExistWithoutWaiters)
+}
+
+// newOperationWithName is identical to newOperation, except that the
+// name of the operation is provided by the caller. This is used to
+// recover operations from the operation journal.
+func (t *task) newOperationWithName(bq *InMemoryBuildQueue, name string, priority int32, i *invocation, mayExistWithoutWaiters bool) *operation {
 	o := &operation{
-		name:                   uuid.Must(bq.uuidGenerator()).String(),
+		name:                   name,
 		task:                   t,
 		priority:               priority,
+		instanceName:           t.actionDigest.GetInstanceName(),
 		invocation:             i,
 		mayExistWithoutWaiters: mayExistWithoutWaiters,
 		queueIndex:             -1,
@@ -2332,6 +3934,86 @@ func (t *task) newOperation(bq *InMemoryBuildQueue, priority int32, i *invocatio
 	return o
 }
 
+// recordSizeClassEscalation attaches a SizeClassEscalation message to
+// the auxiliary metadata of the task, describing an attempt that failed
+// on a smaller size class. Attempts recorded previously are retained,
+// so that the full chain of escalations is reported.
+func (t *task) recordSizeClassEscalation(sizeClass uint32, executeResponse *remoteexecution.ExecuteResponse, resourceLimitsMultiplier float64) {
+	var escalation resourceusage.SizeClassEscalation
+	auxiliaryMetadata := make([]*anypb.Any, 0, len(t.desiredState.AuxiliaryMetadata)+1)
+	for _, metadata := range t.desiredState.AuxiliaryMetadata {
+		if metadata.MessageIs(&escalation) && metadata.UnmarshalTo(&escalation) == nil {
+			continue
+		}
+		auxiliaryMetadata = append(auxiliaryMetadata, metadata)
+	}
+	escalation.FailedAttempts = append(escalation.FailedAttempts, &resourceusage.SizeClassEscalation_FailedAttempt{
+		SizeClass: sizeClass,
+		Timeout:   t.desiredState.Action.Timeout,
+		Status:    executeResponse.Status,
+		ExitCode:  executeResponse.Result.GetExitCode(),
+	})
+	escalation.ResourceLimitsMultiplier = resourceLimitsMultiplier
+	if escalationAny, err := anypb.New(&escalation); err == nil {
+		auxiliaryMetadata = append(auxiliaryMetadata, escalationAny)
+	}
+	t.desiredState.AuxiliaryMetadata = auxiliaryMetadata
+}
+
+// journal writes the current state of all operations of the task to
+// the operation journal.
+func (t *task) journal(bq *InMemoryBuildQueue) {
+	for _, o := range t.operations {
+		o.journal(bq)
+	}
+}
+
+// getPriority returns the priority of the task, which is the highest
+// priority of all operations associated with it.
+func (t *task) getPriority() int32 {
+	first := true
+	var priority int32
+	for _, o := range t.operations {
+		if first || o.priority < priority {
+			priority = o.priority
+			first = false
+		}
+	}
+	return priority
+}
+
+// getExecutingDesiredState returns the desired state that is sent to
+// the worker that executes the task. The priority of the task is filled
+// in at this point, as it may change while the task is queued due to
+// operations being associated with it or being abandoned.
+func (t *task) getExecutingDesiredState() *remoteworker.DesiredState_Executing {
+	t.desiredState.Priority = t.getPriority()
+	return &t.desiredState
+}
+
+// isAtConcurrencyLimit returns whether the task may not be started
+// due to the concurrency limits of invocations. This is the case if
+// for every operation of the task, the invocation of the operation or
+// one of its parents has reached its concurrency limit.
+func (t *task) isAtConcurrencyLimit() bool {
+	if len(t.operations) == 0 {
+		return false
+	}
+	for i := range t.operations {
+		limited := false
+		for ; i != nil; i = i.parent {
+			if i.isAtConcurrencyLimit() {
+				limited = true
+				break
+			}
+		}
+		if !limited {
+			return false
+		}
+	}
+	return true
+}
+
 // reportNonFinalStageChange can be used to wake up clients that are
 // calling Execute() or WaitExecution(), causing them to receive another
 // non-final stage change update.
@@ -2345,6 +4027,14 @@ func (t *task) reportNonFinalStageChange() {
 // When no such worker exists, it will queue the operation, so that a
 // worker may pick it up later.
 func (t *task) schedule(bq *InMemoryBuildQueue) {
+	if t.isAtConcurrencyLimit() {
+		// All invocations of the task have reached their
+		// concurrency limit. The task may only be started once
+		// other operations of these invocations complete.
+		t.enqueue(bq)
+		return
+	}
+
 	// Check whether there are idle workers that are synchronizing
 	// against the scheduler on which we can schedule the operation
 	// directly.
@@ -2354,6 +4044,18 @@ func (t *task) schedule(bq *InMemoryBuildQueue) {
 	// so that locality is improved. Scan the tree of invocations
 	// bottom up, breadth first to find an appropriate worker.
 	scq := t.getCurrentSizeClassQueue()
+	for i := range t.operations {
+		// Prefer workers that are reserved for any of the
+		// invocations of the task, as they are most likely to
+		// have populated caches.
+		if r := i.workerReservation; r != nil {
+			if workerIndex, ok := r.idleSynchronizingWorkers.getIndexAbleToExecuteTask(bq, t); ok {
+				t.registerQueuedStageStarted(bq, &scq.tasksScheduledWorker)
+				r.idleSynchronizingWorkers[workerIndex].worker.assignUnqueuedTaskAndWakeUp(bq, t, 0)
+				return
+			}
+		}
+	}
 	invocations := make([]*invocation, 0, len(t.operations))
 	for i := range t.operations {
 		invocations = append(invocations, i)
@@ -2373,8 +4075,31 @@ func (t *task) schedule(bq *InMemoryBuildQueue) {
 				// TODO: Do we want to provide a histogram
 				// on how far the new invocation is removed
 				// from the original one?
+				workerIndex := 0
+				if bq.configuration.WorkerInputRootAffinityCount > 0 {
+					if affinityIndex, ok := i.idleSynchronizingWorkers.getIndexByRecentInputRoot(t.desiredState.Action.InputRootDigest); ok {
+						workerIndex = affinityIndex
+						scq.inputRootAffinityHit.Inc()
+					} else {
+						scq.inputRootAffinityMiss.Inc()
+					}
+				}
+				if !i.idleSynchronizingWorkers[workerIndex].worker.canExecuteTask(bq, t) {
+					// The worker is expected to
+					// terminate before the task
+					// completes, or has too few
+					// execution slots available.
+					// Pick another worker, or queue
+					// the task if none of the workers
+					// are able to execute it.
+					var ok bool
+					if workerIndex, ok = i.idleSynchronizingWorkers.getIndexAbleToExecuteTask(bq, t); !ok {
+						t.enqueue(bq)
+						return
+					}
+				}
 				t.registerQueuedStageStarted(bq, &scq.tasksScheduledWorker)
-				i.idleSynchronizingWorkers[0].worker.assignUnqueuedTaskAndWakeUp(bq, t, 0)
+				i.idleSynchronizingWorkers[workerIndex].worker.assignUnqueuedTaskAndWakeUp(bq, t, 0)
 				return
 			}
 			if i.parent == nil {
@@ -2385,10 +4110,7 @@ func (t *task) schedule(bq *InMemoryBuildQueue) {
 				// Queue the operation, so that workers
 				// can pick it up when they become
 				// available.
-				t.registerQueuedStageStarted(bq, &scq.tasksScheduledQueue)
-				for _, o := range t.operations {
-					o.enqueue()
-				}
+				t.enqueue(bq)
 				return
 			}
 			invocations[idx] = i.parent
@@ -2396,6 +4118,76 @@ func (t *task) schedule(bq *InMemoryBuildQueue) {
 	}
 }
 
+// enqueue all operations of a task, so that a worker may pick it up
+// later.
+func (t *task) enqueue(bq *InMemoryBuildQueue) {
+	scq := t.getCurrentSizeClassQueue()
+	t.registerQueuedStageStarted(bq, &scq.tasksScheduledQueue)
+	for _, o := range t.operations {
+		o.enqueue(bq)
+	}
+	scq.queuedTasksCount++
+	if m := scq.platformPropertyMetrics; m != nil {
+		m.tasksQueued.Inc()
+	}
+}
+
+// delay the scheduling of a newly created task until a given point in
+// time. Until then, the task's operations are not part of any queue,
+// meaning that workers cannot pick them up. Clients observe the task
+// being in the QUEUED stage.
+func (t *task) delay(bq *InMemoryBuildQueue, earliestStartTime time.Time) {
+	scq := t.getCurrentSizeClassQueue()
+	scq.delayedTasks[t] = struct{}{}
+	for i := range t.operations {
+		i.incrementDelayedOperationsCount()
+	}
+	t.currentStageStartTime = bq.now
+	bq.cleanupQueue.add(&t.delayKey, earliestStartTime, func() {
+		t.undelay(bq)
+	})
+}
+
+// isDelayed returns whether the scheduling of the task is delayed.
+func (t *task) isDelayed() bool {
+	return t.delayKey.isActive()
+}
+
+// removeDelayBookkeeping removes all state that is tracked for a task
+// while its scheduling is delayed.
+func (t *task) removeDelayBookkeeping() {
+	scq := t.getCurrentSizeClassQueue()
+	delete(scq.delayedTasks, t)
+	for i := range t.operations {
+		i.decrementDelayedOperationsCount()
+	}
+	if t.recoveredWorkerKey != "" {
+		delete(scq.recoveredTasks, t.recoveredWorkerKey)
+		t.recoveredWorkerKey = ""
+	}
+}
+
+// undelay is called when the earliest start time of a delayed task is
+// reached, causing it to be scheduled.
+func (t *task) undelay(bq *InMemoryBuildQueue) {
+	t.removeDelayBookkeeping()
+	t.maybeAddToInFlightDeduplicationMap(bq)
+	t.desiredState.QueuedTimestamp = bq.getCurrentTime()
+	t.schedule(bq)
+}
+
+// maybeAddToInFlightDeduplicationMap permits other clients to
+// deduplicate against a task that is eligible for execution, unless
+// another task for the same action is already in flight.
+func (t *task) maybeAddToInFlightDeduplicationMap(bq *InMemoryBuildQueue) {
+	if !t.desiredState.Action.DoNotCache {
+		key := bq.getInFlightDeduplicationKey(t.actionDigest)
+		if _, ok := bq.inFlightDeduplicationMap[key]; !ok {
+			bq.inFlightDeduplicationMap[key] = t
+		}
+	}
+}
+
 // getStage returns whether the task is in the queued, executing or
 // completed stage.
 func (t *task) getStage() remoteexecution.ExecutionStage_Value {
@@ -2418,7 +4210,16 @@ func (t *task) complete(bq *InMemoryBuildQueue, executeResponse *remoteexecution
 		// on which we start the task, so that we can go through
 		// the regular completion code below.
 		var w worker
-		w.assignQueuedTask(bq, t, 0)
+		if t.isDelayed() {
+			// The operations of delayed tasks aren't
+			// queued. Prevent the task from being
+			// scheduled later on.
+			bq.cleanupQueue.remove(t.delayKey)
+			t.removeDelayBookkeeping()
+			w.assignUnqueuedTask(bq, t, 0)
+		} else {
+			w.assignQueuedTask(bq, t, 0)
+		}
 	case remoteexecution.ExecutionStage_EXECUTING:
 		// Task is executing on a worker. Make sure to preserve
 		// worker.lastInvocation.
@@ -2491,7 +4292,7 @@ func (t *task) complete(bq *InMemoryBuildQueue, executeResponse *remoteexecution
 				backgroundInitialSizeClassLearner.Abandoned()
 			} else {
 				backgroundSCQ := pq.sizeClassQueues[backgroundSizeClassIndex]
-				backgroundInvocation := backgroundSCQ.getOrCreateInvocation(bq, scheduler_invocation.BackgroundLearningKeys)
+				backgroundInvocation := backgroundSCQ.getOrCreateInvocation(bq, scheduler_invocation.BackgroundLearningKeys, 1)
 				if backgroundInvocation.queuedOperations.Len() >= pq.maximumQueuedBackgroundLearningOperations {
 					// Already running too many background tasks.
 					backgroundInitialSizeClassLearner.Abandoned()
@@ -2504,6 +4305,8 @@ func (t *task) complete(bq *InMemoryBuildQueue, executeResponse *remoteexecution
 						actionDigest:            t.actionDigest,
 						desiredState:            t.desiredState,
 						targetID:                t.targetID,
+						actionMnemonic:          t.actionMnemonic,
+						weight:                  t.weight,
 						expectedDuration:        backgroundExpectedDuration,
 						initialSizeClassLearner: backgroundInitialSizeClassLearner,
 						stageChangeWakeup:       make(chan struct{}),
@@ -2530,6 +4333,10 @@ func (t *task) complete(bq *InMemoryBuildQueue, executeResponse *remoteexecution
 		// requested, using the original timeout value.
 		// Transplant all operations to the other size class
 		// queue and reschedule.
+		if policy := pq.sizeClassEscalationPolicy; policy != nil {
+			t.recordSizeClassEscalation(currentSCQ.sizeClass, executeResponse, policy.ResourceLimitsMultiplier)
+			timeout = time.Duration(float64(timeout) * policy.TimeoutMultiplier)
+		}
 		t.expectedDuration = expectedDuration
 		t.desiredState.Action.Timeout = durationpb.New(timeout)
 		t.registerCompletedStageFinished(bq)
@@ -2537,10 +4344,11 @@ func (t *task) complete(bq *InMemoryBuildQueue, executeResponse *remoteexecution
 		operations := t.operations
 		t.operations = make(map[*invocation]*operation, len(operations))
 		for oldI, o := range operations {
-			i := largestSCQ.getOrCreateInvocation(bq, oldI.invocationKeys)
+			i := largestSCQ.getOrCreateInvocation(bq, oldI.invocationKeys, oldI.weight)
 			t.operations[i] = o
 			o.invocation = i
 		}
+		t.journal(bq)
 		t.schedule(bq)
 		t.reportNonFinalStageChange()
 	} else {
@@ -2551,16 +4359,22 @@ func (t *task) complete(bq *InMemoryBuildQueue, executeResponse *remoteexecution
 		// after completion. This reduces memory usage
 		// significantly. Keep the Action digest, so that
 		// there's still a way to figure out what the task was.
-		delete(bq.inFlightDeduplicationMap, t.actionDigest)
+		if key := bq.getInFlightDeduplicationKey(t.actionDigest); bq.inFlightDeduplicationMap[key] == t {
+			delete(bq.inFlightDeduplicationMap, key)
+		}
 		t.executeResponse = executeResponse
 		t.desiredState.Action = nil
 		close(t.stageChangeWakeup)
+		for _, o := range t.operations {
+			o.removeFromJournal(bq)
+			o.releaseClientQuota(bq)
+		}
 		t.stageChangeWakeup = nil
 
-		// Background learning tasks may continue to exist, even
-		// if no clients wait for the results. Now that this
-		// task is completed, it must go through the regular
-		// cleanup process.
+		// Background learning tasks and delayed tasks may
+		// continue to exist, even if no clients wait for the
+		// results. Now that this task is completed, it must go
+		// through the regular cleanup process.
 		for _, o := range t.operations {
 			if o.mayExistWithoutWaiters {
 				o.mayExistWithoutWaiters = false
@@ -2578,6 +4392,9 @@ func (t *task) registerQueuedStageStarted(bq *InMemoryBuildQueue, tasksScheduled
 	} else {
 		tasksScheduledCounterVec.doNotCacheFalse.Inc()
 	}
+	if window := bq.configuration.WorkerCountRecommendationWindow; window > 0 {
+		t.getCurrentSizeClassQueue().tasksArrived.add(bq.now, window, 1)
+	}
 	t.currentStageStartTime = bq.now
 }
 
@@ -2585,7 +4402,13 @@ func (t *task) registerQueuedStageStarted(bq *InMemoryBuildQueue, tasksScheduled
 // task finishing the QUEUED stage.
 func (t *task) registerQueuedStageFinished(bq *InMemoryBuildQueue) {
 	scq := t.getCurrentSizeClassQueue()
-	scq.tasksQueuedDurationSeconds.Observe(bq.now.Sub(t.currentStageStartTime).Seconds())
+	queuedDuration := bq.now.Sub(t.currentStageStartTime)
+	scq.tasksQueuedDurationSeconds.Observe(queuedDuration.Seconds())
+	if m := scq.platformPropertyMetrics; m != nil {
+		m.tasksQueuedDurationSeconds.Observe(queuedDuration.Seconds())
+	}
+	scq.tasksDispatchedCount++
+	scq.tasksDispatchedQueuedDuration += queuedDuration
 	t.currentStageStartTime = bq.now
 }
 
@@ -2593,8 +4416,17 @@ func (t *task) registerQueuedStageFinished(bq *InMemoryBuildQueue) {
 // the task finishing the EXECUTING stage.
 func (t *task) registerExecutingStageFinished(bq *InMemoryBuildQueue, result, grpcCode string) {
 	scq := t.getCurrentSizeClassQueue()
-	scq.tasksExecutingDurationSeconds.WithLabelValues(result, grpcCode).Observe(bq.now.Sub(t.currentStageStartTime).Seconds())
+	executingDurationSeconds := bq.now.Sub(t.currentStageStartTime).Seconds()
+	scq.tasksExecutingDurationSeconds.WithLabelValues(result, grpcCode).Observe(executingDurationSeconds)
+	scq.executingDurationHistogramCounts[sort.SearchFloat64s(executingDurationHistogramUpperBounds, executingDurationSeconds)]++
 	scq.tasksExecutingRetries.WithLabelValues(result, grpcCode).Observe(float64(t.retryCount))
+	if m := scq.platformPropertyMetrics; m != nil {
+		m.tasksExecutingDurationSeconds.WithLabelValues(result, grpcCode).Observe(bq.now.Sub(t.currentStageStartTime).Seconds())
+	}
+	if window := bq.configuration.WorkerCountRecommendationWindow; window > 0 {
+		scq.tasksExecuted.add(bq.now, window, 1)
+		scq.tasksExecutingDurationSum.add(bq.now, window, bq.now.Sub(t.currentStageStartTime).Seconds())
+	}
 	t.currentStageStartTime = bq.now
 }
 
@@ -2656,6 +4488,45 @@ type worker struct {
 	// current invocation. These values are used to determine
 	// whether the stickiness limit has been reached.
 	stickinessStartingTimes []time.Time
+	// The number of consecutive blocking Synchronize() calls
+	// performed by this worker that timed out without a task being
+	// assigned to it. This is used to let idle workers synchronize
+	// less frequently.
+	idleSynchronizationTimeouts int
+	// Input root digests of the tasks that this worker executed
+	// most recently, ordered from most to least recent. This is
+	// used to prefer assigning tasks to workers that are likely to
+	// have the task's input files in their local caches.
+	//
+	// The scheduler has no access to the contents of input roots
+	// without reading them from the Content Addressable Storage,
+	// meaning that only identical input roots are matched.
+	recentInputRoots []*remoteexecution.Digest
+	// The resources that the worker reported to be available to it
+	// during its last call to Synchronize(), if any.
+	resources *remoteworker.WorkerResources
+	// The execution slots that the worker reported during its last
+	// call to Synchronize(), if any.
+	executionSlots *remoteworker.ExecutionSlots
+	// The time at which the worker reported it is expected to
+	// terminate during its last call to Synchronize(), if any.
+	expectedTerminationTime time.Time
+	// Whether the tasks that this worker completed most recently
+	// failed due to infrastructure errors, ordered from most to
+	// least recent. This is used to quarantine workers that are
+	// misbehaving.
+	recentTaskInfrastructureErrors []bool
+	// If set, the worker is quarantined, and should not receive
+	// any tasks until this time.
+	quarantinedUntil time.Time
+	// If set, the worker is reserved for an invocation, meaning it
+	// only executes operations belonging to that invocation.
+	reservation *workerReservation
+	// If set, the worker is idle and synchronizing against the
+	// scheduler, and is placed in the list of idle synchronizing
+	// workers of its reservation, as opposed to the one of
+	// lastInvocation.
+	idleReservation *workerReservation
 }
 
 func workerMatchesPattern(workerID, workerIDPattern map[string]string) bool {
@@ -2681,17 +4552,150 @@ func (w *worker) isDrained(scq *sizeClassQueue, workerID map[string]string) bool
 	return false
 }
 
+// hasInsufficientResources returns whether the resources reported by
+// the worker don't meet the requirements of the size class queue.
+// Workers that don't report any resources are assumed to be capable of
+// executing any task.
+func (w *worker) hasInsufficientResources(scq *sizeClassQueue) bool {
+	requirements, ok := scq.platformQueue.workerResourceRequirements[scq.sizeClass]
+	resources := w.resources
+	if !ok || resources == nil {
+		return false
+	}
+	return resources.BuildDirectoryFreeBytes < requirements.MinimumBuildDirectoryFreeBytes ||
+		resources.AvailableMemoryBytes < requirements.MinimumAvailableMemoryBytes ||
+		(requirements.MaximumLoadAverage > 0 && resources.LoadAverage > requirements.MaximumLoadAverage)
+}
+
+// outlivesTask returns whether the worker is expected to remain
+// available until a task completes, based on the task's expected
+// duration. Workers that don't report when they are expected to
+// terminate are assumed to outlive any task.
+func (w *worker) outlivesTask(bq *InMemoryBuildQueue, t *task) bool {
+	return w.expectedTerminationTime.IsZero() || !bq.now.Add(t.expectedDuration).After(w.expectedTerminationTime)
+}
+
+// hasSufficientExecutionSlots returns whether the worker has enough
+// execution slots available to accommodate the weight of a task.
+// Tasks whose weight exceeds the total number of slots may run on
+// workers that have all of their slots available. Workers that don't
+// report any execution slots are assumed to fit any task.
+//
+// Workers whose actions hold multiple slots share their runner with
+// workers of other size classes. These may receive tasks as long as
+// any slots are available. The task then claims the runner, causing
+// it to stop accepting tasks for other size classes until enough slots
+// have been released. Waiting for all slots to become available before
+// assigning the task could starve it.
+func (w *worker) hasSufficientExecutionSlots(t *task) bool {
+	executionSlots := w.executionSlots
+	if executionSlots == nil {
+		return true
+	}
+	if executionSlots.SlotsPerAction > 1 {
+		return executionSlots.Available > 0
+	}
+	return uint32(t.weight) <= executionSlots.Available ||
+		(executionSlots.Available > 0 && executionSlots.Available == executionSlots.Total)
+}
+
+// canExecuteTask returns whether a task may be assigned to the worker,
+// based on when the worker is expected to terminate and the number of
+// execution slots it has available.
+func (w *worker) canExecuteTask(bq *InMemoryBuildQueue, t *task) bool {
+	return w.outlivesTask(bq, t) && w.hasSufficientExecutionSlots(t)
+}
+
+// isQuarantined returns whether the worker is quarantined, due to too
+// many of its recently completed tasks failing with infrastructure
+// errors.
+func (w *worker) isQuarantined(bq *InMemoryBuildQueue) bool {
+	return bq.now.Before(w.quarantinedUntil)
+}
+
+// mayReceiveTasks returns whether tasks may be assigned to the worker.
+// This is not the case if the worker is drained, quarantined, or if it
+// reported that it has insufficient resources available.
+func (w *worker) mayReceiveTasks(bq *InMemoryBuildQueue, scq *sizeClassQueue, workerID map[string]string) bool {
+	return !w.isDrained(scq, workerID) && !w.isQuarantined(bq) && !w.hasInsufficientResources(scq)
+}
+
+// getRecentInfrastructureErrorsCount returns the number of tasks that
+// the worker completed recently that failed due to infrastructure
+// errors.
+func (w *worker) getRecentInfrastructureErrorsCount() int {
+	count := 0
+	for _, infrastructureError := range w.recentTaskInfrastructureErrors {
+		if infrastructureError {
+			count++
+		}
+	}
+	return count
+}
+
+// recordTaskOutcome records whether a task completed by the worker
+// failed due to an infrastructure error. If the worker's infrastructure
+// error rate exceeds the limit of the quarantine policy, the worker is
+// quarantined.
+func (w *worker) recordTaskOutcome(bq *InMemoryBuildQueue, scq *sizeClassQueue, infrastructureError bool) {
+	policy := bq.configuration.WorkerQuarantinePolicy
+	if policy == nil || policy.WindowSize <= 0 {
+		return
+	}
+	recentTaskInfrastructureErrors := make([]bool, 0, policy.WindowSize)
+	recentTaskInfrastructureErrors = append(recentTaskInfrastructureErrors, infrastructureError)
+	for _, e := range w.recentTaskInfrastructureErrors {
+		if len(recentTaskInfrastructureErrors) >= policy.WindowSize {
+			break
+		}
+		recentTaskInfrastructureErrors = append(recentTaskInfrastructureErrors, e)
+	}
+	w.recentTaskInfrastructureErrors = recentTaskInfrastructureErrors
+
+	if len(recentTaskInfrastructureErrors) >= policy.WindowSize &&
+		float64(w.getRecentInfrastructureErrorsCount()) > policy.MaximumInfrastructureErrorRate*float64(policy.WindowSize) {
+		// Start computing the infrastructure error rate from
+		// scratch once the quarantine ends, so that the
+		// worker isn't quarantined again immediately.
+		w.quarantinedUntil = bq.now.Add(policy.QuarantineDuration)
+		w.recentTaskInfrastructureErrors = nil
+		scq.workersQuarantinedTotal.Inc()
+	}
+}
+
+// isInfrastructureError returns whether an ExecuteResponse returned by
+// a worker indicates that the action failed due to a problem with the
+// worker, as opposed to a problem with the action itself (e.g., a
+// timeout or a non-zero exit code) or with its inputs.
+func isInfrastructureError(executeResponse *remoteexecution.ExecuteResponse) bool {
+	switch status.FromProto(executeResponse.Status).Code() {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.DeadlineExceeded, codes.FailedPrecondition:
+		return false
+	}
+	switch re_builder.GetErrorDomainFromExecuteResponse(executeResponse) {
+	case errorclassification.Domain_EXECUTION, errorclassification.Domain_RESOURCE_LIMIT:
+		return false
+	default:
+		return true
+	}
+}
+
 // dequeue a worker. This method is either called by the worker itself
 // at the end of Synchronize(), or when a worker needs to be woken up.
 func (w *worker) dequeue(scq *sizeClassQueue) {
-	i := w.lastInvocation
-	i.idleSynchronizingWorkers.dequeue(w.listIndex)
-	for i.parent != nil {
-		heapRemoveOrFix(
-			&i.parent.idleSynchronizingWorkersChildren,
-			i.idleSynchronizingWorkersChildrenIndex,
-			len(i.idleSynchronizingWorkers)+i.idleSynchronizingWorkersChildren.Len())
-		i = i.parent
+	if r := w.idleReservation; r != nil {
+		r.idleSynchronizingWorkers.dequeue(w.listIndex)
+		w.idleReservation = nil
+	} else {
+		i := w.lastInvocation
+		i.idleSynchronizingWorkers.dequeue(w.listIndex)
+		for i.parent != nil {
+			heapRemoveOrFix(
+				&i.parent.idleSynchronizingWorkersChildren,
+				i.idleSynchronizingWorkersChildrenIndex,
+				len(i.idleSynchronizingWorkers)+i.idleSynchronizingWorkersChildren.Len())
+			i = i.parent
+		}
 	}
 	w.wakeup = nil
 }
@@ -2727,11 +4731,53 @@ func (w *worker) assignUnqueuedTask(bq *InMemoryBuildQueue, t *task, stickinessR
 	t.retryCount = 0
 	for i := range t.operations {
 		i.incrementExecutingWorkersCount(bq, w)
+		if r := i.workerReservation; r != nil {
+			r.extend(bq)
+			r.maybeAddWorker(bq, w)
+		}
 	}
 	w.clearLastInvocation()
 	for i := stickinessRetained; i < len(w.stickinessStartingTimes); i++ {
 		w.stickinessStartingTimes[i] = bq.now
 	}
+	if n := bq.configuration.WorkerInputRootAffinityCount; n > 0 {
+		w.addRecentInputRoot(t.desiredState.Action.InputRootDigest, n)
+	}
+}
+
+// addRecentInputRoot records that the worker has been assigned a task
+// having a given input root. Only the provided number of most recently
+// used input roots are retained.
+func (w *worker) addRecentInputRoot(inputRootDigest *remoteexecution.Digest, count int) {
+	if inputRootDigest == nil {
+		return
+	}
+	recentInputRoots := make([]*remoteexecution.Digest, 0, count)
+	recentInputRoots = append(recentInputRoots, inputRootDigest)
+	for _, d := range w.recentInputRoots {
+		if len(recentInputRoots) >= count {
+			break
+		}
+		if !inputRootDigestsEqual(d, inputRootDigest) {
+			recentInputRoots = append(recentInputRoots, d)
+		}
+	}
+	w.recentInputRoots = recentInputRoots
+}
+
+// hasRecentInputRoot returns whether the worker was recently assigned a
+// task having a given input root.
+func (w *worker) hasRecentInputRoot(inputRootDigest *remoteexecution.Digest) bool {
+	for _, d := range w.recentInputRoots {
+		if inputRootDigestsEqual(d, inputRootDigest) {
+			return true
+		}
+	}
+	return false
+}
+
+func inputRootDigestsEqual(a, b *remoteexecution.Digest) bool {
+	return a.Hash == b.Hash && a.SizeBytes == b.SizeBytes
 }
 
 // assignQueuedTask assigns a task that is queued to a worker. The task
@@ -2740,7 +4786,12 @@ func (w *worker) assignQueuedTask(bq *InMemoryBuildQueue, t *task, stickinessRet
 	w.assignUnqueuedTask(bq, t, stickinessRetained)
 
 	for _, o := range t.operations {
-		o.removeQueuedFromInvocation()
+		o.removeQueuedFromInvocation(bq)
+	}
+	scq := t.getCurrentSizeClassQueue()
+	scq.queuedTasksCount--
+	if m := scq.platformPropertyMetrics; m != nil {
+		m.tasksQueued.Dec()
 	}
 	t.reportNonFinalStageChange()
 }
@@ -2753,6 +4804,16 @@ func (w *worker) assignNextQueuedTask(bq *InMemoryBuildQueue, scq *sizeClassQueu
 	workerInvocationStickinessLimits := pq.workerInvocationStickinessLimits
 	stickinessStartingTimes := w.stickinessStartingTimes
 	i := &scq.rootInvocation
+	if r := w.reservation; r != nil {
+		// Reserved workers may only execute operations of the
+		// invocation for which they are reserved. Stickiness
+		// is irrelevant in that case.
+		i = r.invocation
+		if i.isAtConcurrencyLimit() {
+			return false
+		}
+		lastInvocationKeys = nil
+	}
 	stickinessRetained := 0
 	for {
 		// Even though an invocation can both have directly
@@ -2764,8 +4825,34 @@ func (w *worker) assignNextQueuedTask(bq *InMemoryBuildQueue, scq *sizeClassQueu
 			// One or more operations are enqueued in this
 			// invocation directly. Pick the most preferable
 			// operation.
+			t := i.queuedOperations[0].task
+			if !w.hasSufficientExecutionSlots(t) {
+				// The worker has too few execution
+				// slots available to run the most
+				// preferable operation. Don't pick a
+				// lighter operation instead, as that
+				// could starve heavier ones. Leave it
+				// to another worker, or wait for
+				// actions on this worker to complete.
+				return false
+			}
+			if !w.outlivesTask(bq, t) {
+				// The worker is expected to terminate
+				// before the most preferable operation
+				// completes. Pick the most preferable
+				// operation that does complete in time,
+				// leaving longer running operations to
+				// other workers.
+				o, ok := i.queuedOperationsByExpectedDuration.getMostPreferableWithExpectedDurationAtMost(
+					w.expectedTerminationTime.Sub(bq.now))
+				if !ok || !w.hasSufficientExecutionSlots(o.task) {
+					return false
+				}
+				t = o.task
+			}
 			scq.workerInvocationStickinessRetained.Observe(float64(stickinessRetained))
-			w.assignQueuedTask(bq, i.queuedOperations[0].task, stickinessRetained)
+			w.assignQueuedTask(bq, t, stickinessRetained)
+			t.journal(bq)
 			return true
 		} else if len(i.queuedChildren) > 0 {
 			// One or more operations are enqueued in a
@@ -2786,10 +4873,19 @@ func (w *worker) assignNextQueuedTask(bq *InMemoryBuildQueue, scq *sizeClassQueu
 			// last executed task. This reduces the startup
 			// overhead of actions that leave state behind
 			// on the worker.
+			//
+			// Invocations that have reached their
+			// concurrency limit are placed at the end of
+			// the heap. If the first invocation has reached
+			// its limit, none of the queued operations may
+			// be started.
 			iBest := i.queuedChildren[0]
+			if iBest.isAtConcurrencyLimit() {
+				return false
+			}
 			if len(lastInvocationKeys) > 0 && len(workerInvocationStickinessLimits) > 0 {
 				iSticky := i.children[lastInvocationKeys[0]]
-				if iSticky.isQueued() && iSticky.isPreferred(iBest, w.stickinessStartingTimes[0].Add(workerInvocationStickinessLimits[0]).After(bq.now)) {
+				if iSticky.isQueued() && !iSticky.isAtConcurrencyLimit() && iSticky.isPreferred(iBest, w.stickinessStartingTimes[0].Add(workerInvocationStickinessLimits[0]).After(bq.now)) {
 					iBest = iSticky
 				}
 				if iBest == iSticky {
@@ -2856,17 +4952,19 @@ func (w *worker) assignUnqueuedTaskAndWakeUp(bq *InMemoryBuildQueue, t *task, st
 	// worker is queued.
 	w.wakeUp(t.getCurrentSizeClassQueue())
 	w.assignUnqueuedTask(bq, t, stickinessRetained)
+	t.journal(bq)
 }
 
 // getExecutingSynchronizeResponse returns a synchronization response
 // that instructs a worker to start executing a task.
 func (w *worker) getExecutingSynchronizeResponse(bq *InMemoryBuildQueue) *remoteworker.SynchronizeResponse {
 	t := w.currentTask
+	w.idleSynchronizationTimeouts = 0
 	return &remoteworker.SynchronizeResponse{
 		NextSynchronizationAt: bq.getNextSynchronizationAtDelay(),
 		DesiredState: &remoteworker.DesiredState{
 			WorkerState: &remoteworker.DesiredState_Executing_{
-				Executing: &t.desiredState,
+				Executing: t.getExecutingDesiredState(),
 			},
 		},
 	}
@@ -2885,8 +4983,8 @@ func (w *worker) getNextTask(ctx context.Context, bq *InMemoryBuildQueue, scq *s
 		return bq.getIdleSynchronizeResponse(), nil
 	}
 
-	isDrained := w.isDrained(scq, workerID)
-	if !isDrained && w.assignNextQueuedTask(bq, scq, workerID) {
+	mayReceiveTasks := w.mayReceiveTasks(bq, scq, workerID)
+	if mayReceiveTasks && w.assignNextQueuedTask(bq, scq, workerID) {
 		return w.getExecutingSynchronizeResponse(bq), nil
 	}
 
@@ -2899,13 +4997,17 @@ func (w *worker) getNextTask(ctx context.Context, bq *InMemoryBuildQueue, scq *s
 		return bq.getIdleSynchronizeResponse(), nil
 	}
 
-	timeoutTimer, timeoutChannel := bq.clock.NewTimer(bq.configuration.GetIdleWorkerSynchronizationInterval())
+	timeoutTimer, timeoutChannel := bq.clock.NewTimer(bq.configuration.GetIdleWorkerSynchronizationInterval(w.idleSynchronizationTimeouts))
 	defer timeoutTimer.Stop()
 
 	for {
-		if isDrained {
-			// The worker is drained. Simply wait until
-			// undrain operations occur.
+		if !mayReceiveTasks {
+			// The worker is drained, quarantined or has
+			// insufficient resources. Simply wait until
+			// undrain operations occur. Changes to the
+			// resources of the worker and the end of its
+			// quarantine are observed the next time it
+			// synchronizes.
 			undrainWakeup := scq.undrainWakeup
 			bq.leave()
 
@@ -2913,6 +5015,7 @@ func (w *worker) getNextTask(ctx context.Context, bq *InMemoryBuildQueue, scq *s
 			case t := <-timeoutChannel:
 				// Timeout has been reached.
 				bq.enter(t)
+				w.idleSynchronizationTimeouts++
 				return bq.getIdleSynchronizeResponse(), nil
 			case <-ctx.Done():
 				// Worker has canceled the request.
@@ -2941,14 +5044,25 @@ func (w *worker) getNextTask(ctx context.Context, bq *InMemoryBuildQueue, scq *s
 			}
 			wakeup := make(chan struct{})
 			w.wakeup = wakeup
-			i := w.lastInvocation
-			i.idleSynchronizingWorkers.enqueue(&idleSynchronizingWorker{
-				worker:    w,
-				listIndex: &w.listIndex,
-			})
-			for i.parent != nil {
-				heapPushOrFix(&i.parent.idleSynchronizingWorkersChildren, i.idleSynchronizingWorkersChildrenIndex, i)
-				i = i.parent
+			if r := w.reservation; r != nil {
+				// Reserved workers may only be woken up
+				// by operations of the invocation for
+				// which they are reserved.
+				w.idleReservation = r
+				r.idleSynchronizingWorkers.enqueue(&idleSynchronizingWorker{
+					worker:    w,
+					listIndex: &w.listIndex,
+				})
+			} else {
+				i := w.lastInvocation
+				i.idleSynchronizingWorkers.enqueue(&idleSynchronizingWorker{
+					worker:    w,
+					listIndex: &w.listIndex,
+				})
+				for i.parent != nil {
+					heapPushOrFix(&i.parent.idleSynchronizingWorkersChildren, i.idleSynchronizingWorkersChildrenIndex, i)
+					i = i.parent
+				}
 			}
 			bq.leave()
 
@@ -2960,6 +5074,7 @@ func (w *worker) getNextTask(ctx context.Context, bq *InMemoryBuildQueue, scq *s
 				if w.currentTask != nil {
 					return w.getExecutingSynchronizeResponse(bq), nil
 				}
+				w.idleSynchronizationTimeouts++
 				return bq.getIdleSynchronizeResponse(), nil
 			case <-ctx.Done():
 				// Worker has canceled the request.
@@ -2975,7 +5090,7 @@ func (w *worker) getNextTask(ctx context.Context, bq *InMemoryBuildQueue, scq *s
 				}
 			}
 		}
-		isDrained = w.isDrained(scq, workerID)
+		mayReceiveTasks = w.mayReceiveTasks(bq, scq, workerID)
 	}
 }
 
@@ -2991,11 +5106,12 @@ func (w *worker) getCurrentOrNextTask(ctx context.Context, bq *InMemoryBuildQueu
 				NextSynchronizationAt: bq.getNextSynchronizationAtDelay(),
 				DesiredState: &remoteworker.DesiredState{
 					WorkerState: &remoteworker.DesiredState_Executing_{
-						Executing: &t.desiredState,
+						Executing: t.getExecutingDesiredState(),
 					},
 				},
 			}, nil
 		}
+		w.recordTaskOutcome(bq, scq, true)
 		t.complete(bq, &remoteexecution.ExecuteResponse{
 			Status: status.Newf(
 				codes.Internal,
@@ -3007,6 +5123,29 @@ func (w *worker) getCurrentOrNextTask(ctx context.Context, bq *InMemoryBuildQueu
 	return w.getNextTask(ctx, bq, scq, workerID, preferBeingIdle)
 }
 
+// maybeReattachRecoveredTask is called when a worker synchronizes
+// against the scheduler. If the worker was executing a task prior to
+// the scheduler restarting that was recovered from the operation
+// journal, the task is reassigned to the worker if it reports that it
+// is still executing it. Otherwise, the task is scheduled regularly.
+func (w *worker) maybeReattachRecoveredTask(bq *InMemoryBuildQueue, scq *sizeClassQueue, currentState *remoteworker.CurrentState) {
+	t, ok := scq.recoveredTasks[w.workerKey]
+	if !ok || w.currentTask != nil {
+		return
+	}
+	bq.cleanupQueue.remove(t.delayKey)
+	if executing := currentState.GetExecuting(); executing != nil && proto.Equal(executing.ActionDigest, t.desiredState.ActionDigest) {
+		t.removeDelayBookkeeping()
+		t.maybeAddToInFlightDeduplicationMap(bq)
+		t.registerQueuedStageStarted(bq, &scq.tasksScheduledWorker)
+		w.assignUnqueuedTask(bq, t, 0)
+		t.journal(bq)
+		t.reportNonFinalStageChange()
+	} else {
+		t.undelay(bq)
+	}
+}
+
 // isRunningCorrectTask determines whether the worker is actually
 // running the task the scheduler instructed it to run previously.
 func (w *worker) isRunningCorrectTask(actionDigest *remoteexecution.Digest) bool {
@@ -3020,29 +5159,168 @@ func (w *worker) isRunningCorrectTask(actionDigest *remoteexecution.Digest) bool
 
 // updateTask processes execution status updates from the worker that do
 // not equal the 'completed' state.
-func (w *worker) updateTask(bq *InMemoryBuildQueue, scq *sizeClassQueue, workerID map[string]string, actionDigest *remoteexecution.Digest, preferBeingIdle bool) (*remoteworker.SynchronizeResponse, error) {
+func (w *worker) updateTask(bq *InMemoryBuildQueue, scq *sizeClassQueue, workerID map[string]string, actionDigest *remoteexecution.Digest, preferBeingIdle, acceptTentativeAssignments bool) (*remoteworker.SynchronizeResponse, error) {
 	if !w.isRunningCorrectTask(actionDigest) {
 		return w.getCurrentOrNextTask(nil, bq, scq, workerID, preferBeingIdle)
 	}
 	// The worker is doing fine. Allow it to continue with what it's
 	// doing right now.
-	return &remoteworker.SynchronizeResponse{
+	response := &remoteworker.SynchronizeResponse{
 		NextSynchronizationAt: bq.getNextSynchronizationAtDelay(),
-	}, nil
+	}
+
+	// If the worker is going to pick up more work afterwards,
+	// announce the task that is likely to be assigned to it next,
+	// so that it may already prefetch its inputs.
+	if acceptTentativeAssignments && !preferBeingIdle && !w.isDrained(scq, workerID) {
+		if t := scq.peekNextQueuedTask(); t != nil {
+			response.TentativeAssignment = &t.desiredState
+		}
+	}
+	return response, nil
 }
 
 // completeTask processes execution status updates from the worker that
 // equal the 'completed' state. It causes the execute response to be
 // preserved and communicated to clients that are waiting on the
 // completion of the task.
+// requeueCurrentTask detaches the task that is currently assigned to
+// the worker, and places it back in the queue, so that it may be
+// executed by another worker.
+func (w *worker) requeueCurrentTask(bq *InMemoryBuildQueue, scq *sizeClassQueue) {
+	t := w.currentTask
+	w.setLastInvocation(&scq.rootInvocation)
+	for i := range t.operations {
+		i.decrementExecutingWorkersCount(bq, w)
+	}
+	w.currentTask = nil
+	t.currentWorker = nil
+
+	// Invocations may have been removed as part of decrementing
+	// the executing workers count. Reobtain them.
+	operations := t.operations
+	t.operations = make(map[*invocation]*operation, len(operations))
+	for oldI, o := range operations {
+		i := scq.getOrCreateInvocation(bq, oldI.invocationKeys, oldI.weight)
+		t.operations[i] = o
+		o.invocation = i
+	}
+
+	t.journal(bq)
+	t.schedule(bq)
+	t.reportNonFinalStageChange()
+}
+
+// rejectTask is invoked when a worker declines to execute the task
+// assigned to it, due to it lacking the memory to do so. The task is
+// requeued without counting this as an attempt to execute it.
+func (w *worker) rejectTask(ctx context.Context, bq *InMemoryBuildQueue, scq *sizeClassQueue, workerID map[string]string, actionDigest *remoteexecution.Digest, preferBeingIdle bool) (*remoteworker.SynchronizeResponse, error) {
+	if !w.isRunningCorrectTask(actionDigest) {
+		return w.getCurrentOrNextTask(ctx, bq, scq, workerID, preferBeingIdle)
+	}
+	w.requeueCurrentTask(bq, scq)
+	scq.tasksRejectedTotal.Inc()
+	return w.getNextTask(ctx, bq, scq, workerID, preferBeingIdle)
+}
+
 func (w *worker) completeTask(ctx context.Context, bq *InMemoryBuildQueue, scq *sizeClassQueue, workerID map[string]string, actionDigest *remoteexecution.Digest, executeResponse *remoteexecution.ExecuteResponse, preferBeingIdle bool) (*remoteworker.SynchronizeResponse, error) {
 	if !w.isRunningCorrectTask(actionDigest) {
 		return w.getCurrentOrNextTask(ctx, bq, scq, workerID, preferBeingIdle)
 	}
+	w.recordTaskOutcome(bq, scq, isInfrastructureError(executeResponse))
 	w.currentTask.complete(bq, executeResponse, true)
 	return w.getNextTask(ctx, bq, scq, workerID, preferBeingIdle)
 }
 
+// workerReservation keeps track of the workers that are reserved for
+// an invocation, as requested through the "reservedWorkers" platform
+// property.
+type workerReservation struct {
+	invocation     *invocation
+	maximumWorkers int
+	workers        map[*worker]struct{}
+	// List of reserved workers that are idle and currently
+	// synchronizing against the scheduler. These workers are only
+	// woken up to execute operations of the invocation.
+	idleSynchronizingWorkers idleSynchronizingWorkersList
+	// Used to release the reservation once no operations of the
+	// invocation have been started for some time.
+	cleanupKey cleanupKey
+}
+
+// requestWorkerReservation is called when an operation belonging to
+// the invocation requests that workers are reserved for it. If no
+// reservation exists, it is created. Workers are only added to the
+// reservation once they start executing operations of the invocation.
+func (i *invocation) requestWorkerReservation(bq *InMemoryBuildQueue, count int) {
+	if count == 0 {
+		return
+	}
+	r := i.workerReservation
+	if r == nil {
+		r = &workerReservation{
+			invocation: i,
+			workers:    map[*worker]struct{}{},
+		}
+		i.workerReservation = r
+	}
+	if r.maximumWorkers < count {
+		r.maximumWorkers = count
+	}
+	r.extend(bq)
+}
+
+// extend the reservation, so that it's only released once its idle
+// timeout has elapsed again.
+func (r *workerReservation) extend(bq *InMemoryBuildQueue) {
+	if r.cleanupKey.isActive() {
+		bq.cleanupQueue.remove(r.cleanupKey)
+	}
+	bq.cleanupQueue.add(&r.cleanupKey, bq.now.Add(bq.configuration.WorkerReservationPolicy.IdleTimeout), func() {
+		r.release(bq)
+	})
+}
+
+// maybeAddWorker adds a worker that is about to execute an operation
+// of the invocation to the reservation, if the limits of the worker
+// reservation policy permit it.
+func (r *workerReservation) maybeAddWorker(bq *InMemoryBuildQueue, w *worker) {
+	scq := r.invocation.sizeClassQueue
+	if w.reservation == nil &&
+		len(r.workers) < r.maximumWorkers &&
+		float64(scq.reservedWorkersCount+1) <= bq.configuration.WorkerReservationPolicy.MaximumReservedWorkersRatio*float64(len(scq.workers)) {
+		r.workers[w] = struct{}{}
+		w.reservation = r
+		scq.reservedWorkersCount++
+	}
+}
+
+// removeWorker removes a worker from the reservation. If the worker is
+// idle and synchronizing against the scheduler, it is woken up, so
+// that it may pick up operations belonging to other invocations.
+func (r *workerReservation) removeWorker(w *worker) {
+	scq := r.invocation.sizeClassQueue
+	if w.idleReservation == r {
+		w.wakeUp(scq)
+	}
+	delete(r.workers, w)
+	w.reservation = nil
+	scq.reservedWorkersCount--
+}
+
+// release the reservation, making all of its workers available to
+// other invocations again.
+func (r *workerReservation) release(bq *InMemoryBuildQueue) {
+	for w := range r.workers {
+		r.removeWorker(w)
+	}
+	i := r.invocation
+	i.workerReservation = nil
+	for i.removeIfEmpty() {
+		i = i.parent
+	}
+}
+
 type idleSynchronizingWorker struct {
 	worker    *worker
 	listIndex *int
@@ -3055,6 +5333,46 @@ func (l *idleSynchronizingWorkersList) enqueue(entry *idleSynchronizingWorker) {
 	*l = append(*l, *entry)
 }
 
+// getIndexAbleToExecuteTask returns the index of a worker in the list
+// that is expected to remain available until a task completes, and has
+// enough execution slots available to run it.
+//
+// Tasks are packed onto workers based on their expected duration.
+// Workers that are expected to terminate the soonest, while still
+// outliving the task, are preferred. This keeps workers that remain
+// available for a longer amount of time idle, so that they can pick
+// up tasks that have a higher expected duration.
+func (l idleSynchronizingWorkersList) getIndexAbleToExecuteTask(bq *InMemoryBuildQueue, t *task) (int, bool) {
+	bestIndex := -1
+	for idx, entry := range l {
+		if w := entry.worker; w.canExecuteTask(bq, t) {
+			if bestIndex < 0 {
+				bestIndex = idx
+			} else if bestTerminationTime := l[bestIndex].worker.expectedTerminationTime; !w.expectedTerminationTime.IsZero() && (bestTerminationTime.IsZero() || w.expectedTerminationTime.Before(bestTerminationTime)) {
+				bestIndex = idx
+			}
+		}
+	}
+	if bestIndex < 0 {
+		return 0, false
+	}
+	return bestIndex, true
+}
+
+// getIndexByRecentInputRoot returns the index of the first worker in
+// the list that was recently assigned a task having a given input root.
+func (l idleSynchronizingWorkersList) getIndexByRecentInputRoot(inputRootDigest *remoteexecution.Digest) (int, bool) {
+	if inputRootDigest == nil {
+		return 0, false
+	}
+	for idx, entry := range l {
+		if entry.worker.hasRecentInputRoot(inputRootDigest) {
+			return idx, true
+		}
+	}
+	return 0, false
+}
+
 func (l *idleSynchronizingWorkersList) dequeue(listIndex int) {
 	w := (*l)[listIndex].worker
 	(*l)[listIndex] = (*l)[len(*l)-1]
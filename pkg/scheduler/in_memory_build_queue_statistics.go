@@ -0,0 +1,276 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/buildqueuestate"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/schedulerstatistics"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// executingDurationHistogramUpperBounds contains the upper bounds of
+// the buckets of the histogram of execution durations that is tracked
+// for every size class queue.
+var executingDurationHistogramUpperBounds = util.DecimalExponentialBuckets(-3, 6, 2)
+
+func newExponentiallyDecayingSumFromProto(s *schedulerstatistics.ExponentiallyDecayingSum) exponentiallyDecayingSum {
+	if s == nil {
+		return exponentiallyDecayingSum{}
+	}
+	var lastUpdate time.Time
+	if s.LastUpdate != nil {
+		lastUpdate = s.LastUpdate.AsTime()
+	}
+	return exponentiallyDecayingSum{
+		value:      s.Value,
+		lastUpdate: lastUpdate,
+	}
+}
+
+func (s *exponentiallyDecayingSum) toProto() *schedulerstatistics.ExponentiallyDecayingSum {
+	if s.lastUpdate.IsZero() {
+		return nil
+	}
+	return &schedulerstatistics.ExponentiallyDecayingSum{
+		Value:      s.value,
+		LastUpdate: timestamppb.New(s.lastUpdate),
+	}
+}
+
+// getExecutingDurationHistogram returns the histogram of the amount of
+// time tasks in the size class queue spent in the EXECUTING stage.
+func (scq *sizeClassQueue) getExecutingDurationHistogram() *buildqueuestate.ExecutingDurationHistogram {
+	return &buildqueuestate.ExecutingDurationHistogram{
+		UpperBoundsSeconds: executingDurationHistogramUpperBounds,
+		Counts:             append([]uint64(nil), scq.executingDurationHistogramCounts...),
+	}
+}
+
+// maybeRecoverStatistics applies statistics of the size class queue
+// that were persisted by a previous instance of the scheduler.
+func (scq *sizeClassQueue) maybeRecoverStatistics(bq *InMemoryBuildQueue) {
+	key := scq.getKey()
+	statistics, ok := bq.recoveredSizeClassQueueStatistics[key]
+	if !ok {
+		return
+	}
+	delete(bq.recoveredSizeClassQueueStatistics, key)
+
+	scq.tasksArrived = newExponentiallyDecayingSumFromProto(statistics.TasksArrived)
+	scq.tasksExecuted = newExponentiallyDecayingSumFromProto(statistics.TasksExecuted)
+	scq.tasksExecutingDurationSum = newExponentiallyDecayingSumFromProto(statistics.TasksExecutingDurationSeconds)
+
+	// Only restore the histogram if its buckets are identical to
+	// the ones used by this version of the scheduler.
+	if histogram := statistics.ExecutingDurationHistogram; histogram != nil && len(histogram.Counts) == len(scq.executingDurationHistogramCounts) {
+		upperBounds := histogram.UpperBoundsSeconds
+		identical := len(upperBounds) == len(executingDurationHistogramUpperBounds)
+		for i := 0; identical && i < len(upperBounds); i++ {
+			identical = upperBounds[i] == executingDurationHistogramUpperBounds[i]
+		}
+		if identical {
+			copy(scq.executingDurationHistogramCounts, histogram.Counts)
+		}
+	}
+
+	for _, worker := range statistics.Workers {
+		scq.recoveredWorkers[newWorkerKey(worker.WorkerId)] = worker
+	}
+}
+
+// maybeRecoverWorkerStatistics applies statistics of a worker that
+// were persisted by a previous instance of the scheduler. This is
+// called when the worker synchronizes against the scheduler for the
+// first time, so that workers that were quarantined remain
+// quarantined.
+func (scq *sizeClassQueue) maybeRecoverWorkerStatistics(bq *InMemoryBuildQueue, w *worker) {
+	statistics, ok := scq.recoveredWorkers[w.workerKey]
+	if !ok {
+		return
+	}
+	delete(scq.recoveredWorkers, w.workerKey)
+
+	if policy := bq.configuration.WorkerQuarantinePolicy; policy != nil && policy.WindowSize > 0 {
+		recentTaskInfrastructureErrors := statistics.RecentTaskInfrastructureErrors
+		if len(recentTaskInfrastructureErrors) > policy.WindowSize {
+			recentTaskInfrastructureErrors = recentTaskInfrastructureErrors[:policy.WindowSize]
+		}
+		w.recentTaskInfrastructureErrors = recentTaskInfrastructureErrors
+	}
+	if quarantinedUntil := statistics.QuarantinedUntil; quarantinedUntil != nil {
+		w.quarantinedUntil = quarantinedUntil.AsTime()
+	}
+}
+
+// GetStatistics returns statistics that the scheduler gathered while
+// executing operations, such as the execution duration of tasks and
+// the infrastructure error history of workers. These statistics may be
+// persisted, and provided to RecoverStatistics() when the scheduler
+// starts, so that restarting the scheduler does not reset its learned
+// behavior.
+func (bq *InMemoryBuildQueue) GetStatistics() *schedulerstatistics.Statistics {
+	bq.enter(bq.clock.Now())
+	defer bq.leave()
+
+	sizeClassQueues := make([]*schedulerstatistics.SizeClassQueue, 0, len(bq.sizeClassQueues))
+	for _, pq := range bq.platformQueues {
+		for _, scq := range pq.sizeClassQueues {
+			// Sort workers by key, so that the results are
+			// deterministic.
+			workerKeys := make([]workerKey, 0, len(scq.workers))
+			for workerKey := range scq.workers {
+				workerKeys = append(workerKeys, workerKey)
+			}
+			sort.Slice(workerKeys, func(i, j int) bool { return workerKeys[i] < workerKeys[j] })
+
+			var workers []*schedulerstatistics.Worker
+			for _, workerKey := range workerKeys {
+				w := scq.workers[workerKey]
+				if len(w.recentTaskInfrastructureErrors) == 0 && !w.isQuarantined(bq) {
+					continue
+				}
+				worker := &schedulerstatistics.Worker{
+					WorkerId:                       workerKey.getWorkerID(),
+					RecentTaskInfrastructureErrors: append([]bool(nil), w.recentTaskInfrastructureErrors...),
+				}
+				if w.isQuarantined(bq) {
+					worker.QuarantinedUntil = timestamppb.New(w.quarantinedUntil)
+				}
+				workers = append(workers, worker)
+			}
+			sizeClassKey := scq.getKey()
+			sizeClassQueues = append(sizeClassQueues, &schedulerstatistics.SizeClassQueue{
+				SizeClassQueueName:            sizeClassKey.getSizeClassQueueName(),
+				TasksArrived:                  scq.tasksArrived.toProto(),
+				TasksExecuted:                 scq.tasksExecuted.toProto(),
+				TasksExecutingDurationSeconds: scq.tasksExecutingDurationSum.toProto(),
+				ExecutingDurationHistogram:    scq.getExecutingDurationHistogram(),
+				Workers:                       workers,
+			})
+		}
+	}
+	return &schedulerstatistics.Statistics{
+		SizeClassQueues: sizeClassQueues,
+	}
+}
+
+// RecoverStatistics applies statistics that were obtained by calling
+// GetStatistics() on a previous instance of the scheduler. Statistics
+// of size class queues that do not exist yet are applied once they
+// are created. This method needs to be called after all predeclared
+// platform queues have been registered, and before any RPCs are
+// processed.
+func (bq *InMemoryBuildQueue) RecoverStatistics(statistics *schedulerstatistics.Statistics) error {
+	bq.enter(bq.clock.Now())
+	defer bq.leave()
+
+	for _, sizeClassQueueStatistics := range statistics.SizeClassQueues {
+		sizeClassKey, err := newSizeClassKeyFromName(sizeClassQueueStatistics.SizeClassQueueName)
+		if err != nil {
+			return util.StatusWrap(err, "Invalid size class queue name")
+		}
+		bq.recoveredSizeClassQueueStatistics[sizeClassKey] = sizeClassQueueStatistics
+		if scq, ok := bq.sizeClassQueues[sizeClassKey]; ok {
+			scq.maybeRecoverStatistics(bq)
+		}
+	}
+	return nil
+}
+
+// ReadStatisticsFile reads statistics of the scheduler that were
+// written by WriteStatisticsFile(). If the file does not exist, empty
+// statistics are returned.
+func ReadStatisticsFile(path string) (*schedulerstatistics.Statistics, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &schedulerstatistics.Statistics{}, nil
+	} else if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to read statistics")
+	}
+	var statistics schedulerstatistics.Statistics
+	if err := proto.Unmarshal(data, &statistics); err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.DataLoss, "Failed to unmarshal statistics")
+	}
+	return &statistics, nil
+}
+
+// WriteStatisticsFile writes statistics of the scheduler to a file. The
+// file is replaced atomically, so that a crash while writing does not
+// cause previously written statistics to be lost.
+func WriteStatisticsFile(path string, statistics *schedulerstatistics.Statistics) error {
+	data, err := proto.Marshal(statistics)
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to marshal statistics")
+	}
+	temporaryPath := path + ".tmp"
+	f, err := os.OpenFile(temporaryPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o666)
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to create temporary file")
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to write temporary file")
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to synchronize temporary file")
+	}
+	if err := f.Close(); err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to close temporary file")
+	}
+	if err := os.Rename(temporaryPath, path); err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to rename temporary file")
+	}
+	return synchronizeDirectory(filepath.Dir(path))
+}
+
+// WriteStatisticsFilePeriodically writes statistics of the scheduler to
+// a file at a fixed interval, until the provided context is canceled.
+// This ensures that statistics are retained if the scheduler crashes.
+// Statistics are written one final time before returning, so that no
+// statistics are lost when the scheduler shuts down gracefully.
+//
+// Failures to write statistics periodically are logged, but are not
+// fatal. Failure to write statistics the final time is returned.
+func (bq *InMemoryBuildQueue) WriteStatisticsFilePeriodically(ctx context.Context, path string, interval time.Duration, errorLogger util.ErrorLogger) error {
+	for {
+		timer, timerChannel := bq.clock.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if err := WriteStatisticsFile(path, bq.GetStatistics()); err != nil {
+				return util.StatusWrapf(err, "Failed to write statistics to %#v", path)
+			}
+			return nil
+		case <-timerChannel:
+		}
+
+		if err := WriteStatisticsFile(path, bq.GetStatistics()); err != nil {
+			errorLogger.Log(util.StatusWrapf(err, "Failed to write statistics to %#v", path))
+		}
+	}
+}
+
+// synchronizeDirectory flushes the contents of a directory to storage.
+// This needs to be called after renaming files, as the rename is
+// otherwise not guaranteed to persist across crashes of the operating
+// system.
+func synchronizeDirectory(path string) error {
+	d, err := os.Open(path)
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to open directory")
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to synchronize directory")
+	}
+	return nil
+}
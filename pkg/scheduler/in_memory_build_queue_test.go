@@ -4,14 +4,22 @@ import (
 	"context"
 	"io"
 	"net"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-remote-execution/internal/mock"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/buildqueuestate"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/operationjournal"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/resourceusage"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/schedulerstatistics"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/vcsmetadata"
 	"github.com/buildbarn/bb-remote-execution/pkg/scheduler"
 	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/invocation"
 	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/platform"
@@ -20,11 +28,16 @@ import (
 	"github.com/buildbarn/bb-storage/pkg/builder"
 	"github.com/buildbarn/bb-storage/pkg/clock"
 	"github.com/buildbarn/bb-storage/pkg/digest"
+	bb_grpc "github.com/buildbarn/bb-storage/pkg/grpc"
+	auth_pb "github.com/buildbarn/bb-storage/pkg/proto/auth"
 	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/buildbarn/bb-storage/pkg/util"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -34,8 +47,11 @@ import (
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	prom_testutil "github.com/prometheus/client_golang/prometheus/testutil"
+
 	"cloud.google.com/go/longrunning/autogen/longrunningpb"
 )
 
@@ -44,7 +60,7 @@ var buildQueueConfigurationForTesting = scheduler.InMemoryBuildQueueConfiguratio
 	OperationWithNoWaitersTimeout:        time.Minute,
 	PlatformQueueWithNoWorkersTimeout:    15 * time.Minute,
 	BusyWorkerSynchronizationInterval:    10 * time.Second,
-	GetIdleWorkerSynchronizationInterval: func() time.Duration { return time.Minute },
+	GetIdleWorkerSynchronizationInterval: func(int) time.Duration { return time.Minute },
 	WorkerTaskRetryCount:                 9,
 	WorkerWithNoSynchronizationsTimeout:  time.Minute,
 }
@@ -1368,7 +1384,120 @@ func TestInMemoryBuildQueueKillOperationsSizeClassQueueWithoutWorkers(t *testing
 	})
 }
 
-func TestInMemoryBuildQueueIdleWorkerSynchronizationTimeout(t *testing.T) {
+func TestInMemoryBuildQueueKillOperationsInvocationID(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
+		digest.MustNewInstanceName("main"),
+		platformForTesting,
+		/* workerInvocationStickinessLimits = */ nil,
+		/* maximumQueuedBackgroundLearningOperations = */ 0,
+		/* backgroundLearningOperationPriority = */ 0,
+		/* maximumSizeClass = */ 0,
+		/* maximumQueuedOperations = */ 0,
+		/* preemptionPolicy = */ nil,
+		/* workerResourceRequirements = */ nil,
+		/* sizeClassEscalationPolicy = */ nil))
+
+	// Let clients enqueue two operations that are part of the same
+	// correlated invocation, and one operation that is part of
+	// another one.
+	execute := func(actionHash, operationName, correlatedInvocationsID string) (remoteexecution.Execution_ExecuteClient, *mock.MockLearner) {
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, actionHash, 123),
+		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+				SizeBytes: 456,
+			},
+		}, buffer.UserProvided))
+		invocationID, err := anypb.New(&remoteexecution.RequestMetadata{
+			CorrelatedInvocationsId: correlatedInvocationsID,
+		})
+		require.NoError(t, err)
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(
+			platform.MustNewKey("main", platformForTesting),
+			[]invocation.Key{invocation.MustNewKey(invocationID)},
+			initialSizeClassSelector,
+			nil,
+		)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, 30*time.Second, time.Minute, initialSizeClassLearner)
+		clock.EXPECT().Now().Return(time.Unix(1001, 0))
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		timer.EXPECT().Stop().Return(true).MaxTimes(1)
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(operationName))
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: "main",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      actionHash,
+				SizeBytes: 123,
+			},
+		})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.NoError(t, err)
+		return stream, initialSizeClassLearner
+	}
+	streamA1, learnerA1 := execute("da39a3ee5e6b4b0d3255bfef95601890afd80709", "36ebab65-3c4f-4faf-818b-2eabb4cd1b02", "A")
+	streamA2, learnerA2 := execute("c6d0f1eb3ef1ae5b0ea6bb6bc4ff6e49b6ab1ad2", "b6b8e5b4-5d63-4a5c-9b8a-6e5b5d6bd0e7", "A")
+	execute("4a0e4a5c1d0b4e0f9b3a4e2a4c0f8f3b6d2e1a0c", "a2d4e1a9-1c2b-4f7c-8f2a-3e5d6c7b8a90", "B")
+
+	// Killing all operations of the first invocation should only
+	// cause the first two operations to complete.
+	learnerA1.EXPECT().Abandoned()
+	learnerA2.EXPECT().Abandoned()
+	clock.EXPECT().Now().Return(time.Unix(1002, 0)).Times(6)
+	invocationID, err := anypb.New(&remoteexecution.RequestMetadata{
+		CorrelatedInvocationsId: "A",
+	})
+	require.NoError(t, err)
+	_, err = buildQueue.KillOperations(ctx, &buildqueuestate.KillOperationsRequest{
+		Filter: &buildqueuestate.KillOperationsRequest_Filter{
+			Type: &buildqueuestate.KillOperationsRequest_Filter_InvocationId{
+				InvocationId: invocationID,
+			},
+		},
+		Status: status.New(codes.Unavailable, "Invocation was killed administratively").Proto(),
+	})
+	require.NoError(t, err)
+
+	executeResponse, err := anypb.New(&remoteexecution.ExecuteResponse{
+		Status: status.New(codes.Unavailable, "Invocation was killed administratively").Proto(),
+	})
+	require.NoError(t, err)
+	for _, stream := range []remoteexecution.Execution_ExecuteClient{streamA1, streamA2} {
+		update, err := stream.Recv()
+		require.NoError(t, err)
+		require.True(t, update.Done)
+		testutil.RequireEqualProto(t, executeResponse, update.GetResponse())
+	}
+
+	// The operation of the other invocation should still be queued.
+	clock.EXPECT().Now().Return(time.Unix(1003, 0))
+	response, err := buildQueue.ListOperations(ctx, &buildqueuestate.ListOperationsRequest{
+		PageSize:    10,
+		FilterStage: remoteexecution.ExecutionStage_QUEUED,
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Operations, 1)
+	require.Equal(t, "a2d4e1a9-1c2b-4f7c-8f2a-3e5d6c7b8a90", response.Operations[0].Name)
+}
+
+func TestInMemoryBuildQueueListOperationsFilters(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
@@ -1377,6 +1506,168 @@ func TestInMemoryBuildQueueIdleWorkerSynchronizationTimeout(t *testing.T) {
 	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
 	actionRouter := mock.NewMockActionRouter(ctrl)
 	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
+		digest.MustNewInstanceName("main"),
+		platformForTesting,
+		/* workerInvocationStickinessLimits = */ nil,
+		/* maximumQueuedBackgroundLearningOperations = */ 0,
+		/* backgroundLearningOperationPriority = */ 0,
+		/* maximumSizeClass = */ 0,
+		/* maximumQueuedOperations = */ 0,
+		/* preemptionPolicy = */ nil,
+		/* workerResourceRequirements = */ nil,
+		/* sizeClassEscalationPolicy = */ nil))
+
+	// Create two operations for different actions, mnemonics and
+	// targets.
+	execute := func(actionHash, operationName, actionMnemonic, targetID string, priority int32, now int64) remoteexecution.Execution_ExecuteClient {
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, actionHash, 123),
+		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+				SizeBytes: 456,
+			},
+		}, buffer.UserProvided))
+		requestMetadata := &remoteexecution.RequestMetadata{
+			ActionMnemonic: actionMnemonic,
+			TargetId:       targetID,
+		}
+		requestMetadataBin, err := proto.Marshal(requestMetadata)
+		require.NoError(t, err)
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), testutil.EqProto(t, requestMetadata)).Return(
+			platform.MustNewKey("main", platformForTesting),
+			nil,
+			initialSizeClassSelector,
+			nil,
+		)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, 30*time.Second, time.Minute, initialSizeClassLearner)
+		clock.EXPECT().Now().Return(time.Unix(now, 0))
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		timer.EXPECT().Stop().Return(true).MaxTimes(1)
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(operationName))
+		stream, err := executionClient.Execute(
+			metadata.AppendToOutgoingContext(
+				ctx,
+				"build.bazel.remote.execution.v2.requestmetadata-bin",
+				string(requestMetadataBin)),
+			&remoteexecution.ExecuteRequest{
+				InstanceName: "main",
+				ActionDigest: &remoteexecution.Digest{
+					Hash:      actionHash,
+					SizeBytes: 123,
+				},
+				ExecutionPolicy: &remoteexecution.ExecutionPolicy{
+					Priority: priority,
+				},
+			})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.NoError(t, err)
+		return stream
+	}
+	stream1 := execute("da39a3ee5e6b4b0d3255bfef95601890afd80709", "36ebab65-3c4f-4faf-818b-2eabb4cd1b02", "CppCompile", "//foo:bar", 0, 1001)
+	execute("c6d0f1eb3ef1ae5b0ea6bb6bc4ff6e49b6ab1ad2", "b6b8e5b4-5d63-4a5c-9b8a-6e5b5d6bd0e7", "GoLink", "//baz:qux", 1, 1005)
+
+	// Let a worker pick up the first operation.
+	clock.EXPECT().Now().Return(time.Unix(1010, 0)).Times(2)
+	timer := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+	timer.EXPECT().Stop().Return(true).MaxTimes(1)
+	_, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "0",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = stream1.Recv()
+	require.NoError(t, err)
+
+	listOperationNames := func(request *buildqueuestate.ListOperationsRequest) []string {
+		clock.EXPECT().Now().Return(time.Unix(1020, 0))
+		request.PageSize = 10
+		response, err := buildQueue.ListOperations(ctx, request)
+		require.NoError(t, err)
+		names := []string{}
+		for _, operation := range response.Operations {
+			names = append(names, operation.Name)
+		}
+		return names
+	}
+
+	t.Run("ActionMnemonic", func(t *testing.T) {
+		require.Equal(t, []string{"b6b8e5b4-5d63-4a5c-9b8a-6e5b5d6bd0e7"}, listOperationNames(&buildqueuestate.ListOperationsRequest{
+			FilterActionMnemonic: "GoLink",
+		}))
+	})
+
+	t.Run("TargetID", func(t *testing.T) {
+		require.Equal(t, []string{"36ebab65-3c4f-4faf-818b-2eabb4cd1b02"}, listOperationNames(&buildqueuestate.ListOperationsRequest{
+			FilterTargetId: "//foo",
+		}))
+	})
+
+	t.Run("WorkerID", func(t *testing.T) {
+		require.Equal(t, []string{"36ebab65-3c4f-4faf-818b-2eabb4cd1b02"}, listOperationNames(&buildqueuestate.ListOperationsRequest{
+			FilterWorkerId: map[string]string{"thread": "0"},
+		}))
+		require.Empty(t, listOperationNames(&buildqueuestate.ListOperationsRequest{
+			FilterWorkerId: map[string]string{"thread": "1"},
+		}))
+	})
+
+	t.Run("MinimumStageDuration", func(t *testing.T) {
+		// The second operation has been queued for 15 seconds,
+		// while the first operation has been executing for 10
+		// seconds.
+		require.Equal(t, []string{"b6b8e5b4-5d63-4a5c-9b8a-6e5b5d6bd0e7"}, listOperationNames(&buildqueuestate.ListOperationsRequest{
+			FilterMinimumStageDuration: &durationpb.Duration{Seconds: 15},
+		}))
+		require.Equal(t, []string{"36ebab65-3c4f-4faf-818b-2eabb4cd1b02"}, listOperationNames(&buildqueuestate.ListOperationsRequest{
+			FilterStage:                remoteexecution.ExecutionStage_EXECUTING,
+			FilterMinimumStageDuration: &durationpb.Duration{Seconds: 10},
+		}))
+		require.Empty(t, listOperationNames(&buildqueuestate.ListOperationsRequest{
+			FilterMinimumStageDuration: &durationpb.Duration{Seconds: 16},
+		}))
+	})
+
+	t.Run("ActionDigestHash", func(t *testing.T) {
+		require.Equal(t, []string{"b6b8e5b4-5d63-4a5c-9b8a-6e5b5d6bd0e7"}, listOperationNames(&buildqueuestate.ListOperationsRequest{
+			FilterActionDigestHash: "F1EB3E",
+		}))
+	})
+}
+
+func TestInMemoryBuildQueueIdleWorkerSynchronizationTimeout(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.GetIdleWorkerSynchronizationInterval = func(consecutiveTimeouts int) time.Duration {
+		return time.Duration(consecutiveTimeouts+1) * time.Minute
+	}
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
 
 	// When no work appears, workers should still be woken up
 	// periodically to resynchronize. This ensures that workers that
@@ -1388,7 +1679,7 @@ func TestInMemoryBuildQueueIdleWorkerSynchronizationTimeout(t *testing.T) {
 	timerChannel <- time.Unix(1060, 0)
 	timer.EXPECT().Stop()
 	clock.EXPECT().NewTimer(time.Minute).Return(timer, timerChannel)
-	response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+	synchronizeRequest := &remoteworker.SynchronizeRequest{
 		WorkerId: map[string]string{
 			"hostname": "worker123",
 			"thread":   "42",
@@ -1405,7 +1696,8 @@ func TestInMemoryBuildQueueIdleWorkerSynchronizationTimeout(t *testing.T) {
 				Idle: &emptypb.Empty{},
 			},
 		},
-	})
+	}
+	response, err := buildQueue.Synchronize(ctx, synchronizeRequest)
 	require.NoError(t, err)
 	testutil.RequireEqualProto(t, response, &remoteworker.SynchronizeResponse{
 		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1060},
@@ -1415,6 +1707,24 @@ func TestInMemoryBuildQueueIdleWorkerSynchronizationTimeout(t *testing.T) {
 			},
 		},
 	})
+
+	// As the previous synchronization timed out without any work
+	// being assigned, the next synchronization should be permitted
+	// to block for a longer amount of time.
+	clock.EXPECT().Now().Return(time.Unix(1060, 0))
+	timerChannel <- time.Unix(1180, 0)
+	timer.EXPECT().Stop()
+	clock.EXPECT().NewTimer(2*time.Minute).Return(timer, timerChannel)
+	response, err = buildQueue.Synchronize(ctx, synchronizeRequest)
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, response, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1180},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	})
 }
 
 func TestInMemoryBuildQueueDrainedWorker(t *testing.T) {
@@ -2119,11 +2429,7 @@ func TestInMemoryBuildQueueInvocationFairness(t *testing.T) {
 	}
 }
 
-// Test what happens when multiple operations are in-flight deduplicated
-// against the same underlying task, and are subsequently abandoned
-// while being in the QUEUED stage. This should cause all associated
-// operations and invocations to be removed eventually.
-func TestInMemoryBuildQueueInFlightDeduplicationAbandonQueued(t *testing.T) {
+func TestInMemoryBuildQueueInvocationWeights(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
@@ -2131,7 +2437,15 @@ func TestInMemoryBuildQueueInFlightDeduplicationAbandonQueued(t *testing.T) {
 	clock.EXPECT().Now().Return(time.Unix(0, 0))
 	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
 	actionRouter := mock.NewMockActionRouter(ctrl)
-	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.GetInvocationWeight = func(ctx context.Context, instanceName digest.InstanceName) float64 {
+		// Give tenant "a" a larger share of the workers.
+		if instanceName.String() == "main/a" {
+			return 2.5
+		}
+		return 1
+	}
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
 	executionClient := getExecutionClient(t, buildQueue)
 
 	// Announce a new worker, which creates a queue for operations.
@@ -2158,163 +2472,146 @@ func TestInMemoryBuildQueueInFlightDeduplicationAbandonQueued(t *testing.T) {
 		},
 	})
 	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+	testutil.RequireEqualProto(t, response, &remoteworker.SynchronizeResponse{
 		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1000},
 		DesiredState: &remoteworker.DesiredState{
 			WorkerState: &remoteworker.DesiredState_Idle{
 				Idle: &emptypb.Empty{},
 			},
 		},
-	}, response)
+	})
 
-	// Let ten clients create ten operations. Because they all refer
-	// to the same action, all requests should be deduplicated into
-	// the same task. This means that we create ten initial size
-	// class selectors, of which the last nine are abandoned
-	// immediately.
 	operationParameters := [...]struct {
-		invocationID  string
+		tenant        string
+		actionHash    string
+		commandHash   string
 		operationName string
 	}{
-		{"0f0f22ec-908a-4ea7-8a78-b92ab4188e78", "b4667823-9f8e-451d-a3e4-4481ec67329f"},
-		{"0f67bd82-2867-45ec-9412-f058f27d2686", "1b9e4aaf-b984-4ebc-9b51-0e31bf1b0edb"},
-		{"3e3975fa-d723-42c6-bccb-a3358793f656", "e662fb47-f162-41b8-b29c-45b24fe9e273"},
-		{"557cd041-1d24-423c-9733-f94c8d2916b2", "def137ac-7724-43ff-98f9-b16a3ba01dcd"},
-		{"56a827ff-d0bb-4f90-839d-eb55d8060269", "64943e71-86c3-4153-a760-76c0ff30cd68"},
-		{"849810af-2e0b-45ae-965d-28642d6c6453", "da009be0-93fe-40ad-9e03-a14e2bee2ff9"},
-		{"9cadf0eb-1e28-49ea-b052-5d05cdc50303", "e0f4e177-369d-4412-a19c-b7b1969dd46e"},
-		{"9ff4fd36-7123-4b59-90e2-7f49cd0af05e", "34f633ac-c418-4a1d-8a69-796990008e9c"},
-		{"d0438436-cff3-45e1-9c0b-7e5af632c0a4", "46cdaa7c-6bfa-49e2-822e-31be760c51c5"},
-		{"e4896008-d596-44c7-8df6-6ced53dff6b0", "88929b3e-f664-4f11-873d-40324d06378e"},
+		{"a", "f4d362da1f854e54984275aa78e2d9f8", "5fbd808d5cf24a219824664040a95c44", "93e2738a-837c-4524-9f0f-430b47caa889"},
+		{"a", "25b997dcfbe34f95bbbab10bc02e1a61", "2ac9ddc1a64442fabd819358a206909e", "fadbaf2f-669f-47ee-bce4-35f255d2ba16"},
+		{"a", "deb57a22d6b149b2b83d26dbc30d8f28", "5def73fca7c945d3998d984194879f5f", "1d320c02-399a-40bb-bb9a-031960c7e542"},
+		{"b", "d35515d9ebe349639957da438a21bd3c", "9f58a3e3feb044e2a31ffad73479185b", "e7922f00-fc9b-4390-97fe-8564a00d190a"},
+		{"b", "f8362f9b02b04765b4873afce32aa7df", "6196409166614f5691f34b18affaa3a8", "76f7be3b-a685-4ac0-b770-d4305aabab5f"},
+		{"b", "1ee144ab7e7c43cfaa46ca1ccecad26e", "391d52750e9c494cac10dc72e8f7e3db", "7d206d16-9e83-4f91-a515-0ee8132026ee"},
 	}
-	initialSizeClassLearner := mock.NewMockLearner(ctrl)
+
+	// Let two tenants enqueue three operations each, using
+	// separate instance names that share the same platform queue.
+	streams := make([]remoteexecution.Execution_ExecuteClient, 0, len(operationParameters))
 	for i, p := range operationParameters {
+		instanceName := "main/" + p.tenant
 		contentAddressableStorage.EXPECT().Get(
 			gomock.Any(),
-			digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA256, "fc96ea0eee854b45950d3a7448332445730886691b992cb7917da0853664f7c2", 123),
+			digest.MustNewDigest(instanceName, remoteexecution.DigestFunction_MD5, p.actionHash, 123),
 		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
 			CommandDigest: &remoteexecution.Digest{
-				Hash:      "f7a3ac7c17e535bc9b54ab13dbbb95a52ca1f1edaf9503ce23ccb3eca331a4f5",
+				Hash:      p.commandHash,
 				SizeBytes: 456,
 			},
 		}, buffer.UserProvided))
 
-		initialSizeClassSelector := mock.NewMockSelector(ctrl)
-		requestMetadata := &remoteexecution.RequestMetadata{
-			ToolInvocationId: p.invocationID,
-		}
-		requestMetadataAny, err := anypb.New(requestMetadata)
-		require.NoError(t, err)
-		requestMetadataBin, err := proto.Marshal(&remoteexecution.RequestMetadata{
-			ToolInvocationId: p.invocationID,
+		invocationID, err := anypb.New(&remoteexecution.RequestMetadata{
+			ToolInvocationId: p.tenant,
 		})
 		require.NoError(t, err)
-		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), testutil.EqProto(t, requestMetadata)).Return(
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(
 			platform.MustNewKey("main", platformForTesting),
-			[]invocation.Key{invocation.MustNewKey(requestMetadataAny)},
+			[]invocation.Key{invocation.MustNewKey(invocationID)},
 			initialSizeClassSelector,
 			nil,
 		)
-		if i == 0 {
-			initialSizeClassSelector.EXPECT().Select([]uint32{0}).
-				Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
-		} else {
-			initialSizeClassSelector.EXPECT().Abandoned()
-		}
-
-		ctxWithCancel, cancel := context.WithCancel(ctx)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
 
 		clock.EXPECT().Now().Return(time.Unix(1010+int64(i), 0))
 		timer := mock.NewMockTimer(ctrl)
 		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
 		timer.EXPECT().Stop().Return(true)
 		uuidGenerator.EXPECT().Call().Return(uuid.Parse(p.operationName))
-		stream, err := executionClient.Execute(
-			metadata.AppendToOutgoingContext(
-				ctxWithCancel,
-				"build.bazel.remote.execution.v2.requestmetadata-bin",
-				string(requestMetadataBin)),
-			&remoteexecution.ExecuteRequest{
-				InstanceName: "main",
-				ActionDigest: &remoteexecution.Digest{
-					Hash:      "fc96ea0eee854b45950d3a7448332445730886691b992cb7917da0853664f7c2",
-					SizeBytes: 123,
-				},
-			})
-		require.NoError(t, err)
-		update, err := stream.Recv()
-		require.NoError(t, err)
-		metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
-			Stage: remoteexecution.ExecutionStage_QUEUED,
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: instanceName,
 			ActionDigest: &remoteexecution.Digest{
-				Hash:      "fc96ea0eee854b45950d3a7448332445730886691b992cb7917da0853664f7c2",
+				Hash:      p.actionHash,
 				SizeBytes: 123,
 			},
 		})
 		require.NoError(t, err)
-		testutil.RequireEqualProto(t, &longrunningpb.Operation{
-			Name:     p.operationName,
-			Metadata: metadata,
-		}, update)
-
-		// Immediately cancel the request. The operation should
-		// still be valid for the next minute.
-		//
-		// Because cancelling the RPC happens asynchronously, we
-		// wait on clock.Now() to be called to ensure
-		// InMemoryBuildQueue has detected the cancelation.
-		cancelWait := make(chan struct{})
-		clock.EXPECT().Now().Return(time.Unix(1010+int64(i), 0)).Do(func() {
-			cancelWait <- struct{}{}
-		})
-		cancel()
-		<-cancelWait
+		streams = append(streams, stream)
+		_, err = stream.Recv()
+		require.NoError(t, err)
 	}
 
-	// Get listings of invocations known by the scheduler. Because
-	// we're requesting this one minute after the operations were
-	// created, we should gradually see this list shrink. Eventually
-	// all invocations should be removed.
-	initialSizeClassLearner.EXPECT().Abandoned()
-	invocationName := &buildqueuestate.InvocationName{
-		SizeClassQueueName: &buildqueuestate.SizeClassQueueName{
-			PlatformQueueName: &buildqueuestate.PlatformQueueName{
-				InstanceNamePrefix: "main",
-				Platform:           platformForTesting,
+	// Let six workers pick up the operations. As tenant "a" has a
+	// weight of 2.5, it should be permitted to run two operations
+	// before tenant "b" gets to run its first. Afterwards, its
+	// score of 3/2.5 is still preferred over tenant "b"'s score of
+	// 2/1.
+	for i, j := range []int{0, 1, 3, 2, 4, 5} {
+		p := operationParameters[j]
+		clock.EXPECT().Now().Return(time.Unix(1020+int64(i), 0)).Times(2)
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		// The client is still waiting for the operation to
+		// complete when the test finishes.
+		timer.EXPECT().Stop().Return(true).MaxTimes(1)
+		response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: map[string]string{
+				"hostname": "worker123",
+				"thread":   strconv.FormatInt(int64(i), 10),
+			},
+			InstanceNamePrefix: "main",
+			Platform:           platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Idle{
+					Idle: &emptypb.Empty{},
+				},
 			},
-		},
-	}
-	for i := 0; i <= len(operationParameters); i++ {
-		clock.EXPECT().Now().Return(time.Unix(1069+int64(i), 0)).Times(3)
-
-		invocationStates, err := buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
-			InvocationName: invocationName,
-			Filter:         buildqueuestate.ListInvocationChildrenRequest_ALL,
 		})
 		require.NoError(t, err)
-		require.Len(t, invocationStates.Children, len(operationParameters)-i)
+		testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1030 + int64(i)},
+			DesiredState: &remoteworker.DesiredState{
+				WorkerState: &remoteworker.DesiredState_Executing_{
+					Executing: &remoteworker.DesiredState_Executing{
+						DigestFunction: remoteexecution.DigestFunction_MD5,
+						ActionDigest: &remoteexecution.Digest{
+							Hash:      p.actionHash,
+							SizeBytes: 123,
+						},
+						Action: &remoteexecution.Action{
+							CommandDigest: &remoteexecution.Digest{
+								Hash:      p.commandHash,
+								SizeBytes: 456,
+							},
+							Timeout: &durationpb.Duration{Seconds: 1800},
+						},
+						QueuedTimestamp:    &timestamppb.Timestamp{Seconds: 1010 + int64(j)},
+						InstanceNameSuffix: p.tenant,
+					},
+				},
+			},
+		}, response)
 
-		invocationStates, err = buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
-			InvocationName: invocationName,
-			Filter:         buildqueuestate.ListInvocationChildrenRequest_ACTIVE,
-		})
+		update, err := streams[j].Recv()
 		require.NoError(t, err)
-		require.Len(t, invocationStates.Children, len(operationParameters)-i)
-
-		invocationStates, err = buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
-			InvocationName: invocationName,
-			Filter:         buildqueuestate.ListInvocationChildrenRequest_QUEUED,
+		metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+			Stage: remoteexecution.ExecutionStage_EXECUTING,
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      p.actionHash,
+				SizeBytes: 123,
+			},
 		})
 		require.NoError(t, err)
-		require.Len(t, invocationStates.Children, len(operationParameters)-i)
+		testutil.RequireEqualProto(t, &longrunningpb.Operation{
+			Name:     p.operationName,
+			Metadata: metadata,
+		}, update)
 	}
 }
 
-// This test is identical to the previous one, except that the operation
-// is placed in the EXECUTING stage when being abandoned. The logic for
-// removing such operations is different from operations in the QUEUED
-// stage.
-func TestInMemoryBuildQueueInFlightDeduplicationAbandonExecuting(t *testing.T) {
+func TestInMemoryBuildQueueDeadlineAwareScheduling(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
@@ -2322,7 +2619,9 @@ func TestInMemoryBuildQueueInFlightDeduplicationAbandonExecuting(t *testing.T) {
 	clock.EXPECT().Now().Return(time.Unix(0, 0))
 	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
 	actionRouter := mock.NewMockActionRouter(ctrl)
-	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.DeadlineAwareScheduling = true
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
 	executionClient := getExecutionClient(t, buildQueue)
 
 	// Announce a new worker, which creates a queue for operations.
@@ -2349,96 +2648,127 @@ func TestInMemoryBuildQueueInFlightDeduplicationAbandonExecuting(t *testing.T) {
 		},
 	})
 	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+	testutil.RequireEqualProto(t, response, &remoteworker.SynchronizeResponse{
 		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1000},
 		DesiredState: &remoteworker.DesiredState{
 			WorkerState: &remoteworker.DesiredState_Idle{
 				Idle: &emptypb.Empty{},
 			},
 		},
-	}, response)
+	})
 
-	// Let ten clients create ten operations. Because they all refer
-	// to the same action, all requests should be deduplicated into
-	// the same task. This means that we create ten initial size
-	// class selectors, of which the last nine are abandoned
-	// immediately.
 	operationParameters := [...]struct {
-		invocationID  string
-		operationName string
+		queuedSeconds    int64
+		expectedDuration time.Duration
+		timeout          time.Duration
+		actionHash       string
+		commandHash      string
+		operationName    string
 	}{
-		{"0f0f22ec-908a-4ea7-8a78-b92ab4188e78", "b4667823-9f8e-451d-a3e4-4481ec67329f"},
-		{"0f67bd82-2867-45ec-9412-f058f27d2686", "1b9e4aaf-b984-4ebc-9b51-0e31bf1b0edb"},
-		{"3e3975fa-d723-42c6-bccb-a3358793f656", "e662fb47-f162-41b8-b29c-45b24fe9e273"},
-		{"557cd041-1d24-423c-9733-f94c8d2916b2", "def137ac-7724-43ff-98f9-b16a3ba01dcd"},
-		{"56a827ff-d0bb-4f90-839d-eb55d8060269", "64943e71-86c3-4153-a760-76c0ff30cd68"},
-		{"849810af-2e0b-45ae-965d-28642d6c6453", "da009be0-93fe-40ad-9e03-a14e2bee2ff9"},
-		{"9cadf0eb-1e28-49ea-b052-5d05cdc50303", "e0f4e177-369d-4412-a19c-b7b1969dd46e"},
-		{"9ff4fd36-7123-4b59-90e2-7f49cd0af05e", "34f633ac-c418-4a1d-8a69-796990008e9c"},
-		{"d0438436-cff3-45e1-9c0b-7e5af632c0a4", "46cdaa7c-6bfa-49e2-822e-31be760c51c5"},
-		{"e4896008-d596-44c7-8df6-6ced53dff6b0", "88929b3e-f664-4f11-873d-40324d06378e"},
+		{1010, 10 * time.Minute, 30 * time.Minute, "f4d362da1f854e54984275aa78e2d9f8", "5fbd808d5cf24a219824664040a95c44", "93e2738a-837c-4524-9f0f-430b47caa889"},
+		{1020, time.Minute, 5 * time.Minute, "25b997dcfbe34f95bbbab10bc02e1a61", "2ac9ddc1a64442fabd819358a206909e", "fadbaf2f-669f-47ee-bce4-35f255d2ba16"},
+		{1030, 20 * time.Minute, 30 * time.Minute, "deb57a22d6b149b2b83d26dbc30d8f28", "5def73fca7c945d3998d984194879f5f", "1d320c02-399a-40bb-bb9a-031960c7e542"},
 	}
-	initialSizeClassLearner := mock.NewMockLearner(ctrl)
-	for i, p := range operationParameters {
+
+	// Enqueue three operations as part of the same invocation,
+	// having different expected durations and timeouts.
+	streams := make([]remoteexecution.Execution_ExecuteClient, 0, len(operationParameters))
+	for _, p := range operationParameters {
 		contentAddressableStorage.EXPECT().Get(
 			gomock.Any(),
-			digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA256, "fc96ea0eee854b45950d3a7448332445730886691b992cb7917da0853664f7c2", 123),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_MD5, p.actionHash, 123),
 		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
 			CommandDigest: &remoteexecution.Digest{
-				Hash:      "f7a3ac7c17e535bc9b54ab13dbbb95a52ca1f1edaf9503ce23ccb3eca331a4f5",
+				Hash:      p.commandHash,
 				SizeBytes: 456,
 			},
-			Platform: platformForTesting,
 		}, buffer.UserProvided))
 
 		initialSizeClassSelector := mock.NewMockSelector(ctrl)
-		requestMetadata := &remoteexecution.RequestMetadata{
-			ToolInvocationId: p.invocationID,
-			TargetId:         "//:hello_world",
-		}
-		requestMetadataAny, err := anypb.New(requestMetadata)
-		require.NoError(t, err)
-		requestMetadataBin, err := proto.Marshal(requestMetadata)
-		require.NoError(t, err)
-		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), testutil.EqProto(t, requestMetadata)).Return(
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(
 			platform.MustNewKey("main", platformForTesting),
-			[]invocation.Key{invocation.MustNewKey(requestMetadataAny)},
+			nil,
 			initialSizeClassSelector,
 			nil,
 		)
-		if i == 0 {
-			initialSizeClassSelector.EXPECT().Select([]uint32{0}).
-				Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
-		} else {
-			initialSizeClassSelector.EXPECT().Abandoned()
-		}
-
-		ctxWithCancel, cancel := context.WithCancel(ctx)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, p.expectedDuration, p.timeout, initialSizeClassLearner)
 
-		clock.EXPECT().Now().Return(time.Unix(1010+int64(i), 0))
+		clock.EXPECT().Now().Return(time.Unix(p.queuedSeconds, 0))
 		timer := mock.NewMockTimer(ctrl)
 		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
 		timer.EXPECT().Stop().Return(true)
 		uuidGenerator.EXPECT().Call().Return(uuid.Parse(p.operationName))
-		stream, err := executionClient.Execute(
-			metadata.AppendToOutgoingContext(
-				ctxWithCancel,
-				"build.bazel.remote.execution.v2.requestmetadata-bin",
-				string(requestMetadataBin)),
-			&remoteexecution.ExecuteRequest{
-				InstanceName: "main",
-				ActionDigest: &remoteexecution.Digest{
-					Hash:      "fc96ea0eee854b45950d3a7448332445730886691b992cb7917da0853664f7c2",
-					SizeBytes: 123,
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: "main",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      p.actionHash,
+				SizeBytes: 123,
+			},
+		})
+		require.NoError(t, err)
+		streams = append(streams, stream)
+		_, err = stream.Recv()
+		require.NoError(t, err)
+	}
+
+	// Let three workers pick up the operations. Instead of
+	// preferring operations with the highest expected duration,
+	// operations should be picked up in order of the latest time at
+	// which they need to start to complete before their timeout:
+	// 1260, 1630 and 2210 seconds, respectively.
+	for i, j := range []int{1, 2, 0} {
+		p := operationParameters[j]
+		clock.EXPECT().Now().Return(time.Unix(1200+int64(i), 0)).Times(2)
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		// The client is still waiting for the operation to
+		// complete when the test finishes.
+		timer.EXPECT().Stop().Return(true).MaxTimes(1)
+		response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: map[string]string{
+				"hostname": "worker123",
+				"thread":   strconv.FormatInt(int64(i), 10),
+			},
+			InstanceNamePrefix: "main",
+			Platform:           platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Idle{
+					Idle: &emptypb.Empty{},
 				},
-			})
+			},
+		})
 		require.NoError(t, err)
-		update, err := stream.Recv()
+		testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1210 + int64(i)},
+			DesiredState: &remoteworker.DesiredState{
+				WorkerState: &remoteworker.DesiredState_Executing_{
+					Executing: &remoteworker.DesiredState_Executing{
+						DigestFunction: remoteexecution.DigestFunction_MD5,
+						ActionDigest: &remoteexecution.Digest{
+							Hash:      p.actionHash,
+							SizeBytes: 123,
+						},
+						Action: &remoteexecution.Action{
+							CommandDigest: &remoteexecution.Digest{
+								Hash:      p.commandHash,
+								SizeBytes: 456,
+							},
+							Timeout: durationpb.New(p.timeout),
+						},
+						QueuedTimestamp: &timestamppb.Timestamp{Seconds: p.queuedSeconds},
+					},
+				},
+			},
+		}, response)
+
+		update, err := streams[j].Recv()
 		require.NoError(t, err)
 		metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
-			Stage: remoteexecution.ExecutionStage_QUEUED,
+			Stage: remoteexecution.ExecutionStage_EXECUTING,
 			ActionDigest: &remoteexecution.Digest{
-				Hash:      "fc96ea0eee854b45950d3a7448332445730886691b992cb7917da0853664f7c2",
+				Hash:      p.actionHash,
 				SizeBytes: 123,
 			},
 		})
@@ -2447,26 +2777,25 @@ func TestInMemoryBuildQueueInFlightDeduplicationAbandonExecuting(t *testing.T) {
 			Name:     p.operationName,
 			Metadata: metadata,
 		}, update)
-
-		// Immediately cancel the request. The operation should
-		// still be valid for the next minute.
-		//
-		// Because cancelling the RPC happens asynchronously, we
-		// wait on clock.Now() to be called to ensure
-		// InMemoryBuildQueue has detected the cancelation.
-		cancelWait := make(chan struct{})
-		clock.EXPECT().Now().Return(time.Unix(1010+int64(i), 0)).Do(func() {
-			cancelWait <- struct{}{}
-		})
-		cancel()
-		<-cancelWait
 	}
+}
 
-	// Let one worker execute the task. Because of in-flight
-	// deduplication, all ten operations should now be in the
-	// EXECUTING stage.
-	clock.EXPECT().Now().Return(time.Unix(1065, 0))
-	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+func TestInMemoryBuildQueuePriorityAging(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.PriorityAgingInterval = time.Second
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	// Announce a new worker, which creates a queue for operations.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
 		WorkerId: map[string]string{
 			"hostname": "worker123",
 			"thread":   "42",
@@ -2474,82 +2803,155 @@ func TestInMemoryBuildQueueInFlightDeduplicationAbandonExecuting(t *testing.T) {
 		InstanceNamePrefix: "main",
 		Platform:           platformForTesting,
 		CurrentState: &remoteworker.CurrentState{
-			WorkerState: &remoteworker.CurrentState_Idle{
-				Idle: &emptypb.Empty{},
-			},
-		},
-	})
-	require.NoError(t, err)
-	requestMetadata, err := anypb.New(&remoteexecution.RequestMetadata{
-		ToolInvocationId: "0f0f22ec-908a-4ea7-8a78-b92ab4188e78",
-		TargetId:         "//:hello_world",
-	})
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1075},
-		DesiredState: &remoteworker.DesiredState{
-			WorkerState: &remoteworker.DesiredState_Executing_{
-				Executing: &remoteworker.DesiredState_Executing{
-					DigestFunction: remoteexecution.DigestFunction_SHA256,
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
 					ActionDigest: &remoteexecution.Digest{
-						Hash:      "fc96ea0eee854b45950d3a7448332445730886691b992cb7917da0853664f7c2",
+						Hash:      "099a3f6dc1e8e91dbcca4ea964cd2237d4b11733",
 						SizeBytes: 123,
 					},
-					Action: &remoteexecution.Action{
-						CommandDigest: &remoteexecution.Digest{
-							Hash:      "f7a3ac7c17e535bc9b54ab13dbbb95a52ca1f1edaf9503ce23ccb3eca331a4f5",
-							SizeBytes: 456,
-						},
-						Platform: platformForTesting,
-						Timeout:  &durationpb.Duration{Seconds: 1800},
+					ExecutionState: &remoteworker.CurrentState_Executing_FetchingInputs{
+						FetchingInputs: &emptypb.Empty{},
 					},
-					QueuedTimestamp:   &timestamppb.Timestamp{Seconds: 1010},
-					AuxiliaryMetadata: []*anypb.Any{requestMetadata},
 				},
 			},
 		},
-	}, response)
-
-	// Get listings of invocations known by the scheduler. Because
-	// we're requesting this one minute after the operations were
-	// created, we should gradually see this list shrink. Eventually
-	// all invocations should be removed.
-	initialSizeClassLearner.EXPECT().Abandoned()
-	invocationName := &buildqueuestate.InvocationName{
-		SizeClassQueueName: &buildqueuestate.SizeClassQueueName{
-			PlatformQueueName: &buildqueuestate.PlatformQueueName{
-				InstanceNamePrefix: "main",
-				Platform:           platformForTesting,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, response, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1000},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
 			},
 		},
+	})
+
+	operationParameters := [...]struct {
+		priority      int32
+		queuedSeconds int64
+		actionHash    string
+		commandHash   string
+		operationName string
+	}{
+		{50, 1010, "f4d362da1f854e54984275aa78e2d9f8", "5fbd808d5cf24a219824664040a95c44", "93e2738a-837c-4524-9f0f-430b47caa889"},
+		{0, 1020, "25b997dcfbe34f95bbbab10bc02e1a61", "2ac9ddc1a64442fabd819358a206909e", "fadbaf2f-669f-47ee-bce4-35f255d2ba16"},
+		{0, 1100, "deb57a22d6b149b2b83d26dbc30d8f28", "5def73fca7c945d3998d984194879f5f", "1d320c02-399a-40bb-bb9a-031960c7e542"},
 	}
-	for i := 0; i <= len(operationParameters); i++ {
-		clock.EXPECT().Now().Return(time.Unix(1069+int64(i), 0)).Times(3)
 
-		invocationStates, err := buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
-			InvocationName: invocationName,
-			Filter:         buildqueuestate.ListInvocationChildrenRequest_ALL,
+	// Enqueue three operations as part of the same invocation.
+	streams := make([]remoteexecution.Execution_ExecuteClient, 0, len(operationParameters))
+	for _, p := range operationParameters {
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_MD5, p.actionHash, 123),
+		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      p.commandHash,
+				SizeBytes: 456,
+			},
+		}, buffer.UserProvided))
+
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(
+			platform.MustNewKey("main", platformForTesting),
+			nil,
+			initialSizeClassSelector,
+			nil,
+		)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
+
+		clock.EXPECT().Now().Return(time.Unix(p.queuedSeconds, 0))
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		timer.EXPECT().Stop().Return(true)
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(p.operationName))
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: "main",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      p.actionHash,
+				SizeBytes: 123,
+			},
+			ExecutionPolicy: &remoteexecution.ExecutionPolicy{
+				Priority: p.priority,
+			},
 		})
 		require.NoError(t, err)
-		require.Len(t, invocationStates.Children, len(operationParameters)-i)
+		streams = append(streams, stream)
+		_, err = stream.Recv()
+		require.NoError(t, err)
+	}
 
-		invocationStates, err = buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
-			InvocationName: invocationName,
-			Filter:         buildqueuestate.ListInvocationChildrenRequest_ACTIVE,
+	// Let three workers pick up the operations. The operation with
+	// priority 50 has been queued 90 seconds longer than the last
+	// operation with priority 0. With an aging interval of one
+	// second, it should therefore be executed before it, even
+	// though it has a lower priority.
+	for i, j := range []int{1, 0, 2} {
+		p := operationParameters[j]
+		clock.EXPECT().Now().Return(time.Unix(1200+int64(i), 0)).Times(2)
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		// The client is still waiting for the operation to
+		// complete when the test finishes.
+		timer.EXPECT().Stop().Return(true).MaxTimes(1)
+		response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: map[string]string{
+				"hostname": "worker123",
+				"thread":   strconv.FormatInt(int64(i), 10),
+			},
+			InstanceNamePrefix: "main",
+			Platform:           platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Idle{
+					Idle: &emptypb.Empty{},
+				},
+			},
 		})
 		require.NoError(t, err)
-		require.Len(t, invocationStates.Children, len(operationParameters)-i)
+		testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1210 + int64(i)},
+			DesiredState: &remoteworker.DesiredState{
+				WorkerState: &remoteworker.DesiredState_Executing_{
+					Executing: &remoteworker.DesiredState_Executing{
+						DigestFunction: remoteexecution.DigestFunction_MD5,
+						ActionDigest: &remoteexecution.Digest{
+							Hash:      p.actionHash,
+							SizeBytes: 123,
+						},
+						Action: &remoteexecution.Action{
+							CommandDigest: &remoteexecution.Digest{
+								Hash:      p.commandHash,
+								SizeBytes: 456,
+							},
+							Timeout: &durationpb.Duration{Seconds: 1800},
+						},
+						QueuedTimestamp: &timestamppb.Timestamp{Seconds: p.queuedSeconds},
+						Priority:        p.priority,
+					},
+				},
+			},
+		}, response)
 
-		invocationStates, err = buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
-			InvocationName: invocationName,
-			Filter:         buildqueuestate.ListInvocationChildrenRequest_QUEUED,
+		update, err := streams[j].Recv()
+		require.NoError(t, err)
+		metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+			Stage: remoteexecution.ExecutionStage_EXECUTING,
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      p.actionHash,
+				SizeBytes: 123,
+			},
 		})
 		require.NoError(t, err)
-		require.Empty(t, invocationStates.Children)
+		testutil.RequireEqualProto(t, &longrunningpb.Operation{
+			Name:     p.operationName,
+			Metadata: metadata,
+		}, update)
 	}
 }
 
-func TestInMemoryBuildQueuePreferBeingIdle(t *testing.T) {
+func TestInMemoryBuildQueueInFlightDeduplicationAcrossInstanceNames(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
@@ -2557,7 +2959,9 @@ func TestInMemoryBuildQueuePreferBeingIdle(t *testing.T) {
 	clock.EXPECT().Now().Return(time.Unix(0, 0))
 	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
 	actionRouter := mock.NewMockActionRouter(ctrl)
-	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.InFlightDeduplicationAcrossInstanceNames = true
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
 	executionClient := getExecutionClient(t, buildQueue)
 
 	// Announce a new worker, which creates a queue for operations.
@@ -2570,208 +2974,163 @@ func TestInMemoryBuildQueuePreferBeingIdle(t *testing.T) {
 		InstanceNamePrefix: "main",
 		Platform:           platformForTesting,
 		CurrentState: &remoteworker.CurrentState{
-			WorkerState: &remoteworker.CurrentState_Idle{
-				Idle: &emptypb.Empty{},
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "099a3f6dc1e8e91dbcca4ea964cd2237d4b11733",
+						SizeBytes: 123,
+					},
+					ExecutionState: &remoteworker.CurrentState_Executing_FetchingInputs{
+						FetchingInputs: &emptypb.Empty{},
+					},
+				},
 			},
 		},
-		PreferBeingIdle: true,
 	})
 	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+	testutil.RequireEqualProto(t, response, &remoteworker.SynchronizeResponse{
 		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1000},
 		DesiredState: &remoteworker.DesiredState{
 			WorkerState: &remoteworker.DesiredState_Idle{
 				Idle: &emptypb.Empty{},
 			},
 		},
-	}, response)
+	})
 
-	// Let a client enqueue an operation.
-	contentAddressableStorage.EXPECT().Get(
-		gomock.Any(),
-		digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123),
-	).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+	// Let two tenants submit the same action, using different
+	// instance names that share the same platform queue. Only the
+	// first request should cause a task to be created. The second
+	// request should be deduplicated against it.
+	action := &remoteexecution.Action{
 		CommandDigest: &remoteexecution.Digest{
-			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+			Hash:      "5fbd808d5cf24a219824664040a95c44",
 			SizeBytes: 456,
 		},
-	}, buffer.UserProvided))
-	initialSizeClassSelector := mock.NewMockSelector(ctrl)
-	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, &remoteexecution.Action{
-		CommandDigest: &remoteexecution.Digest{
-			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
-			SizeBytes: 456,
+	}
+	actionDigest := &remoteexecution.Digest{
+		Hash:      "f4d362da1f854e54984275aa78e2d9f8",
+		SizeBytes: 123,
+	}
+	operationParameters := [...]struct {
+		tenant        string
+		operationName string
+	}{
+		{"a", "93e2738a-837c-4524-9f0f-430b47caa889"},
+		{"b", "fadbaf2f-669f-47ee-bce4-35f255d2ba16"},
+	}
+	streams := make([]remoteexecution.Execution_ExecuteClient, 0, len(operationParameters))
+	for i, p := range operationParameters {
+		instanceName := "main/" + p.tenant
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest(instanceName, remoteexecution.DigestFunction_MD5, actionDigest.Hash, actionDigest.SizeBytes),
+		).Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided))
+		invocationID, err := anypb.New(&remoteexecution.RequestMetadata{
+			ToolInvocationId: p.tenant,
+		})
+		require.NoError(t, err)
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(
+			platform.MustNewKey(instanceName, platformForTesting),
+			[]invocation.Key{invocation.MustNewKey(invocationID)},
+			initialSizeClassSelector,
+			nil,
+		)
+		if i == 0 {
+			initialSizeClassLearner := mock.NewMockLearner(ctrl)
+			initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+				Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
+		} else {
+			initialSizeClassSelector.EXPECT().Abandoned()
+		}
+
+		clock.EXPECT().Now().Return(time.Unix(1010+int64(i), 0))
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		timer.EXPECT().Stop().Return(true)
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(p.operationName))
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: instanceName,
+			ActionDigest: actionDigest,
+		})
+		require.NoError(t, err)
+		streams = append(streams, stream)
+		update, err := stream.Recv()
+		require.NoError(t, err)
+		metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+			Stage:        remoteexecution.ExecutionStage_QUEUED,
+			ActionDigest: actionDigest,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &longrunningpb.Operation{
+			Name:     p.operationName,
+			Metadata: metadata,
+		}, update)
+	}
+
+	// Let a worker pick up the task. It should be executed using
+	// the instance name of the first tenant.
+	clock.EXPECT().Now().Return(time.Unix(1020, 0)).Times(3)
+	for range operationParameters {
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		// The client is still waiting for the operation to
+		// complete when the test finishes.
+		timer.EXPECT().Stop().Return(true).MaxTimes(1)
+	}
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
 		},
-	}), nil).Return(platform.MustNewKey("main", platformForTesting), nil, initialSizeClassSelector, nil)
-	initialSizeClassLearner := mock.NewMockLearner(ctrl)
-	initialSizeClassSelector.EXPECT().Select([]uint32{0}).
-		Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
-	clock.EXPECT().Now().Return(time.Unix(1001, 0))
-	timer := mock.NewMockTimer(ctrl)
-	clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
-	timer.EXPECT().Stop().Return(true)
-	uuidGenerator.EXPECT().Call().Return(uuid.Parse("b9bb6e2c-04ff-4fbd-802b-105be93a8fb7"))
-	stream, err := executionClient.Execute(
-		ctx,
-		&remoteexecution.ExecuteRequest{
-			InstanceName: "main",
-			ActionDigest: &remoteexecution.Digest{
-				Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-				SizeBytes: 123,
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
 			},
-		})
-	require.NoError(t, err)
-	update, err := stream.Recv()
-	require.NoError(t, err)
-	metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
-		Stage: remoteexecution.ExecutionStage_QUEUED,
-		ActionDigest: &remoteexecution.Digest{
-			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-			SizeBytes: 123,
-		},
-	})
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &longrunningpb.Operation{
-		Name:     "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7",
-		Metadata: metadata,
-	}, update)
-
-	// Let a worker pick up the operation.
-	clock.EXPECT().Now().Return(time.Unix(1002, 0)).Times(2)
-	timer = mock.NewMockTimer(ctrl)
-	clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
-	timer.EXPECT().Stop().Return(true)
-	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
-		WorkerId: map[string]string{
-			"hostname": "worker123",
-			"thread":   "42",
-		},
-		InstanceNamePrefix: "main",
-		Platform:           platformForTesting,
-		CurrentState: &remoteworker.CurrentState{
-			WorkerState: &remoteworker.CurrentState_Idle{
-				Idle: &emptypb.Empty{},
-			},
-		},
-	})
-	// The client should be informed the operation has started executing.
-	update, err = stream.Recv()
-	require.NoError(t, err)
-	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
-		Stage: remoteexecution.ExecutionStage_EXECUTING,
-		ActionDigest: &remoteexecution.Digest{
-			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-			SizeBytes: 123,
-		},
-	})
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &longrunningpb.Operation{
-		Name:     "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7",
-		Metadata: metadata,
-	}, update)
-
+		},
+	})
 	require.NoError(t, err)
 	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1012},
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1030},
 		DesiredState: &remoteworker.DesiredState{
 			WorkerState: &remoteworker.DesiredState_Executing_{
 				Executing: &remoteworker.DesiredState_Executing{
-					DigestFunction: remoteexecution.DigestFunction_SHA1,
-					ActionDigest: &remoteexecution.Digest{
-						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-						SizeBytes: 123,
-					},
+					DigestFunction: remoteexecution.DigestFunction_MD5,
+					ActionDigest:   actionDigest,
 					Action: &remoteexecution.Action{
-						CommandDigest: &remoteexecution.Digest{
-							Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
-							SizeBytes: 456,
-						},
-						Timeout: &durationpb.Duration{Seconds: 1800},
-					},
-					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1001},
-				},
-			},
-		},
-	}, response)
-
-	// Let the worker complete the execution of the operation.
-	// Normally this would be a blocking call, as it would wait
-	// until more work is available. However, because
-	// PreferBeingIdle is set, the call will return immediately,
-	// explicitly forcing the worker to the idle state. This allows
-	// workers to terminate gracefully.
-	initialSizeClassLearner.EXPECT().Succeeded(10*time.Second, []uint32{0})
-	clock.EXPECT().Now().Return(time.Unix(1003, 0)).Times(3)
-	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
-		WorkerId: map[string]string{
-			"hostname": "worker123",
-			"thread":   "42",
-		},
-		InstanceNamePrefix: "main",
-		Platform:           platformForTesting,
-		CurrentState: &remoteworker.CurrentState{
-			WorkerState: &remoteworker.CurrentState_Executing_{
-				Executing: &remoteworker.CurrentState_Executing{
-					ActionDigest: &remoteexecution.Digest{
-						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-						SizeBytes: 123,
-					},
-					ExecutionState: &remoteworker.CurrentState_Executing_Completed{
-						Completed: &remoteexecution.ExecuteResponse{
-							Result: &remoteexecution.ActionResult{
-								ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
-									VirtualExecutionDuration: &durationpb.Duration{Seconds: 10},
-								},
-							},
-						},
+						CommandDigest: action.CommandDigest,
+						Timeout:       &durationpb.Duration{Seconds: 1800},
 					},
+					QueuedTimestamp:    &timestamppb.Timestamp{Seconds: 1010},
+					InstanceNameSuffix: "a",
 				},
 			},
 		},
-		PreferBeingIdle: true,
-	})
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1003},
-		DesiredState: &remoteworker.DesiredState{
-			WorkerState: &remoteworker.DesiredState_Idle{
-				Idle: &emptypb.Empty{},
-			},
-		},
 	}, response)
 
-	// The client should be informed the operation has completed. This
-	// should be the last message to be returned.
-	update, err = stream.Recv()
-	require.NoError(t, err)
-	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
-		Stage: remoteexecution.ExecutionStage_COMPLETED,
-		ActionDigest: &remoteexecution.Digest{
-			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-			SizeBytes: 123,
-		},
-	})
-	require.NoError(t, err)
-	executeResponse, err := anypb.New(&remoteexecution.ExecuteResponse{
-		Result: &remoteexecution.ActionResult{
-			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
-				VirtualExecutionDuration: &durationpb.Duration{Seconds: 10},
-			},
-		},
+	// Both clients should be informed that the action is executing.
+	metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage:        remoteexecution.ExecutionStage_EXECUTING,
+		ActionDigest: actionDigest,
 	})
 	require.NoError(t, err)
-	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
-		Name:     "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7",
-		Metadata: metadata,
-		Done:     true,
-		Result:   &longrunningpb.Operation_Response{Response: executeResponse},
-	})
-
-	_, err = stream.Recv()
-	require.Equal(t, io.EOF, err)
+	for i, p := range operationParameters {
+		update, err := streams[i].Recv()
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &longrunningpb.Operation{
+			Name:     p.operationName,
+			Metadata: metadata,
+		}, update)
+	}
 }
 
-func TestInMemoryBuildQueueMultipleSizeClasses(t *testing.T) {
+// Test what happens when multiple operations are in-flight deduplicated
+// against the same underlying task, and are subsequently abandoned
+// while being in the QUEUED stage. This should cause all associated
+// operations and invocations to be removed eventually.
+func TestInMemoryBuildQueueInFlightDeduplicationAbandonQueued(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
@@ -2782,40 +3141,8 @@ func TestInMemoryBuildQueueMultipleSizeClasses(t *testing.T) {
 	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
 	executionClient := getExecutionClient(t, buildQueue)
 
-	// Register a platform queue that allows workers up to size
-	// class 8. The maximum needs to be provided to ensure that the
-	// execution strategy remains deterministic.
+	// Announce a new worker, which creates a queue for operations.
 	clock.EXPECT().Now().Return(time.Unix(1000, 0))
-	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
-		digest.MustNewInstanceName("main"),
-		platformForTesting,
-		/* workerInvocationStickinessLimits = */ nil,
-		/* maximumQueuedBackgroundLearningOperations = */ 0,
-		/* backgroundLearningOperationPriority = */ 0,
-		/* maximumSizeClass = */ 8))
-
-	// Workers with a higher size class should be rejected, as no
-	// requests will end up getting sent to them.
-	clock.EXPECT().Now().Return(time.Unix(1001, 0))
-	_, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
-		WorkerId: map[string]string{
-			"hostname": "worker123",
-			"thread":   "42",
-		},
-		InstanceNamePrefix: "main",
-		Platform:           platformForTesting,
-		SizeClass:          9,
-		CurrentState: &remoteworker.CurrentState{
-			WorkerState: &remoteworker.CurrentState_Idle{
-				Idle: &emptypb.Empty{},
-			},
-		},
-	})
-	testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Worker provided size class 9, which exceeds the predeclared maximum of 8"), err)
-
-	// Announce a worker with a smaller size class, which should be
-	// permitted.
-	clock.EXPECT().Now().Return(time.Unix(1002, 0))
 	response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
 		WorkerId: map[string]string{
 			"hostname": "worker123",
@@ -2823,7 +3150,6 @@ func TestInMemoryBuildQueueMultipleSizeClasses(t *testing.T) {
 		},
 		InstanceNamePrefix: "main",
 		Platform:           platformForTesting,
-		SizeClass:          3,
 		CurrentState: &remoteworker.CurrentState{
 			WorkerState: &remoteworker.CurrentState_Executing_{
 				Executing: &remoteworker.CurrentState_Executing{
@@ -2840,7 +3166,7 @@ func TestInMemoryBuildQueueMultipleSizeClasses(t *testing.T) {
 	})
 	require.NoError(t, err)
 	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1002},
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1000},
 		DesiredState: &remoteworker.DesiredState{
 			WorkerState: &remoteworker.DesiredState_Idle{
 				Idle: &emptypb.Empty{},
@@ -2848,305 +3174,389 @@ func TestInMemoryBuildQueueMultipleSizeClasses(t *testing.T) {
 		},
 	}, response)
 
-	// Let a client enqueue a new operation, which we'll schedule on
-	// the smaller size class.
-	contentAddressableStorage.EXPECT().Get(
-		gomock.Any(),
-		digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123),
-	).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
-		CommandDigest: &remoteexecution.Digest{
-			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
-			SizeBytes: 456,
-		},
-	}, buffer.UserProvided))
-	initialSizeClassSelector := mock.NewMockSelector(ctrl)
-	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, &remoteexecution.Action{
-		CommandDigest: &remoteexecution.Digest{
-			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
-			SizeBytes: 456,
-		},
-	}), nil).Return(platform.MustNewKey("main", platformForTesting), nil, initialSizeClassSelector, nil)
-	initialSizeClassLearner1 := mock.NewMockLearner(ctrl)
-	initialSizeClassSelector.EXPECT().Select([]uint32{3, 8}).
-		Return(0, 3*time.Minute, 7*time.Minute, initialSizeClassLearner1)
-	clock.EXPECT().Now().Return(time.Unix(1003, 0))
-	timer1 := mock.NewMockTimer(ctrl)
-	clock.EXPECT().NewTimer(time.Minute).Return(timer1, nil)
-	uuidGenerator.EXPECT().Call().Return(uuid.Parse("36ebab65-3c4f-4faf-818b-2eabb4cd1b02"))
+	// Let ten clients create ten operations. Because they all refer
+	// to the same action, all requests should be deduplicated into
+	// the same task. This means that we create ten initial size
+	// class selectors, of which the last nine are abandoned
+	// immediately.
+	operationParameters := [...]struct {
+		invocationID  string
+		operationName string
+	}{
+		{"0f0f22ec-908a-4ea7-8a78-b92ab4188e78", "b4667823-9f8e-451d-a3e4-4481ec67329f"},
+		{"0f67bd82-2867-45ec-9412-f058f27d2686", "1b9e4aaf-b984-4ebc-9b51-0e31bf1b0edb"},
+		{"3e3975fa-d723-42c6-bccb-a3358793f656", "e662fb47-f162-41b8-b29c-45b24fe9e273"},
+		{"557cd041-1d24-423c-9733-f94c8d2916b2", "def137ac-7724-43ff-98f9-b16a3ba01dcd"},
+		{"56a827ff-d0bb-4f90-839d-eb55d8060269", "64943e71-86c3-4153-a760-76c0ff30cd68"},
+		{"849810af-2e0b-45ae-965d-28642d6c6453", "da009be0-93fe-40ad-9e03-a14e2bee2ff9"},
+		{"9cadf0eb-1e28-49ea-b052-5d05cdc50303", "e0f4e177-369d-4412-a19c-b7b1969dd46e"},
+		{"9ff4fd36-7123-4b59-90e2-7f49cd0af05e", "34f633ac-c418-4a1d-8a69-796990008e9c"},
+		{"d0438436-cff3-45e1-9c0b-7e5af632c0a4", "46cdaa7c-6bfa-49e2-822e-31be760c51c5"},
+		{"e4896008-d596-44c7-8df6-6ced53dff6b0", "88929b3e-f664-4f11-873d-40324d06378e"},
+	}
+	initialSizeClassLearner := mock.NewMockLearner(ctrl)
+	for i, p := range operationParameters {
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA256, "fc96ea0eee854b45950d3a7448332445730886691b992cb7917da0853664f7c2", 123),
+		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "f7a3ac7c17e535bc9b54ab13dbbb95a52ca1f1edaf9503ce23ccb3eca331a4f5",
+				SizeBytes: 456,
+			},
+		}, buffer.UserProvided))
 
-	stream1, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
-		InstanceName: "main",
-		ActionDigest: &remoteexecution.Digest{
-			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-			SizeBytes: 123,
-		},
-	})
-	require.NoError(t, err)
-	update, err := stream1.Recv()
-	require.NoError(t, err)
-	metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
-		Stage: remoteexecution.ExecutionStage_QUEUED,
-		ActionDigest: &remoteexecution.Digest{
-			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-			SizeBytes: 123,
-		},
-	})
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
-		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
-		Metadata: metadata,
-	})
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		requestMetadata := &remoteexecution.RequestMetadata{
+			ToolInvocationId: p.invocationID,
+		}
+		requestMetadataAny, err := anypb.New(requestMetadata)
+		require.NoError(t, err)
+		requestMetadataBin, err := proto.Marshal(&remoteexecution.RequestMetadata{
+			ToolInvocationId: p.invocationID,
+		})
+		require.NoError(t, err)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), testutil.EqProto(t, requestMetadata)).Return(
+			platform.MustNewKey("main", platformForTesting),
+			[]invocation.Key{invocation.MustNewKey(requestMetadataAny)},
+			initialSizeClassSelector,
+			nil,
+		)
+		if i == 0 {
+			initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+				Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
+		} else {
+			initialSizeClassSelector.EXPECT().Abandoned()
+		}
 
-	// Let the worker for the small size class pick it up.
-	timer1.EXPECT().Stop().Return(true)
-	clock.EXPECT().Now().Return(time.Unix(1004, 0)).Times(2)
-	timer2 := mock.NewMockTimer(ctrl)
-	clock.EXPECT().NewTimer(time.Minute).Return(timer2, nil)
-	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
-		WorkerId: map[string]string{
-			"hostname": "worker123",
-			"thread":   "42",
-		},
-		InstanceNamePrefix: "main",
-		Platform:           platformForTesting,
-		SizeClass:          3,
-		CurrentState: &remoteworker.CurrentState{
-			WorkerState: &remoteworker.CurrentState_Idle{
-				Idle: &emptypb.Empty{},
-			},
-		},
-	})
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1014},
-		DesiredState: &remoteworker.DesiredState{
-			WorkerState: &remoteworker.DesiredState_Executing_{
-				Executing: &remoteworker.DesiredState_Executing{
-					DigestFunction: remoteexecution.DigestFunction_SHA1,
-					ActionDigest: &remoteexecution.Digest{
-						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-						SizeBytes: 123,
-					},
-					Action: &remoteexecution.Action{
-						CommandDigest: &remoteexecution.Digest{
-							Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
-							SizeBytes: 456,
-						},
-						Timeout: &durationpb.Duration{Seconds: 420},
-					},
-					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1003},
+		ctxWithCancel, cancel := context.WithCancel(ctx)
+
+		clock.EXPECT().Now().Return(time.Unix(1010+int64(i), 0))
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		timer.EXPECT().Stop().Return(true)
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(p.operationName))
+		stream, err := executionClient.Execute(
+			metadata.AppendToOutgoingContext(
+				ctxWithCancel,
+				"build.bazel.remote.execution.v2.requestmetadata-bin",
+				string(requestMetadataBin)),
+			&remoteexecution.ExecuteRequest{
+				InstanceName: "main",
+				ActionDigest: &remoteexecution.Digest{
+					Hash:      "fc96ea0eee854b45950d3a7448332445730886691b992cb7917da0853664f7c2",
+					SizeBytes: 123,
 				},
+			})
+		require.NoError(t, err)
+		update, err := stream.Recv()
+		require.NoError(t, err)
+		metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+			Stage: remoteexecution.ExecutionStage_QUEUED,
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "fc96ea0eee854b45950d3a7448332445730886691b992cb7917da0853664f7c2",
+				SizeBytes: 123,
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &longrunningpb.Operation{
+			Name:     p.operationName,
+			Metadata: metadata,
+		}, update)
+
+		// Immediately cancel the request. The operation should
+		// still be valid for the next minute.
+		//
+		// Because cancelling the RPC happens asynchronously, we
+		// wait on clock.Now() to be called to ensure
+		// InMemoryBuildQueue has detected the cancelation.
+		cancelWait := make(chan struct{})
+		clock.EXPECT().Now().Return(time.Unix(1010+int64(i), 0)).Do(func() {
+			cancelWait <- struct{}{}
+		})
+		cancel()
+		<-cancelWait
+	}
+
+	// Get listings of invocations known by the scheduler. Because
+	// we're requesting this one minute after the operations were
+	// created, we should gradually see this list shrink. Eventually
+	// all invocations should be removed.
+	initialSizeClassLearner.EXPECT().Abandoned()
+	invocationName := &buildqueuestate.InvocationName{
+		SizeClassQueueName: &buildqueuestate.SizeClassQueueName{
+			PlatformQueueName: &buildqueuestate.PlatformQueueName{
+				InstanceNamePrefix: "main",
+				Platform:           platformForTesting,
 			},
 		},
-	}, response)
-	update, err = stream1.Recv()
-	require.NoError(t, err)
-	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
-		Stage: remoteexecution.ExecutionStage_EXECUTING,
-		ActionDigest: &remoteexecution.Digest{
-			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-			SizeBytes: 123,
-		},
-	})
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &longrunningpb.Operation{
-		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
-		Metadata: metadata,
-	}, update)
+	}
+	for i := 0; i <= len(operationParameters); i++ {
+		clock.EXPECT().Now().Return(time.Unix(1069+int64(i), 0)).Times(3)
 
-	// The action fails on the worker of the small size class,
-	// meaning that we retry it on the largest size class. An update
-	// should be sent back to the client that the operation has
-	// moved back to the QUEUED stage.
-	initialSizeClassLearner2 := mock.NewMockLearner(ctrl)
-	initialSizeClassLearner1.EXPECT().Failed(false).
-		Return(2*time.Minute, 5*time.Minute, initialSizeClassLearner2)
-	timer2.EXPECT().Stop().Return(true)
-	clock.EXPECT().Now().Return(time.Unix(1005, 0)).Times(2)
-	timer3 := mock.NewMockTimer(ctrl)
-	clock.EXPECT().NewTimer(time.Minute).Return(timer3, nil)
-	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		invocationStates, err := buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
+			InvocationName: invocationName,
+			Filter:         buildqueuestate.ListInvocationChildrenRequest_ALL,
+		})
+		require.NoError(t, err)
+		require.Len(t, invocationStates.Children, len(operationParameters)-i)
+
+		invocationStates, err = buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
+			InvocationName: invocationName,
+			Filter:         buildqueuestate.ListInvocationChildrenRequest_ACTIVE,
+		})
+		require.NoError(t, err)
+		require.Len(t, invocationStates.Children, len(operationParameters)-i)
+
+		invocationStates, err = buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
+			InvocationName: invocationName,
+			Filter:         buildqueuestate.ListInvocationChildrenRequest_QUEUED,
+		})
+		require.NoError(t, err)
+		require.Len(t, invocationStates.Children, len(operationParameters)-i)
+	}
+}
+
+// This test is identical to the previous one, except that the operation
+// is placed in the EXECUTING stage when being abandoned. The logic for
+// removing such operations is different from operations in the QUEUED
+// stage.
+func TestInMemoryBuildQueueInFlightDeduplicationAbandonExecuting(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	// Announce a new worker, which creates a queue for operations.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
 		WorkerId: map[string]string{
 			"hostname": "worker123",
 			"thread":   "42",
 		},
 		InstanceNamePrefix: "main",
 		Platform:           platformForTesting,
-		SizeClass:          3,
 		CurrentState: &remoteworker.CurrentState{
 			WorkerState: &remoteworker.CurrentState_Executing_{
 				Executing: &remoteworker.CurrentState_Executing{
 					ActionDigest: &remoteexecution.Digest{
-						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						Hash:      "099a3f6dc1e8e91dbcca4ea964cd2237d4b11733",
 						SizeBytes: 123,
 					},
-					ExecutionState: &remoteworker.CurrentState_Executing_Completed{
-						Completed: &remoteexecution.ExecuteResponse{
-							Result: &remoteexecution.ActionResult{
-								ExitCode: 137,
-							},
-						},
+					ExecutionState: &remoteworker.CurrentState_Executing_FetchingInputs{
+						FetchingInputs: &emptypb.Empty{},
 					},
 				},
 			},
 		},
-		PreferBeingIdle: true,
 	})
 	require.NoError(t, err)
 	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1005},
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1000},
 		DesiredState: &remoteworker.DesiredState{
 			WorkerState: &remoteworker.DesiredState_Idle{
 				Idle: &emptypb.Empty{},
 			},
 		},
 	}, response)
-	update, err = stream1.Recv()
-	require.NoError(t, err)
-	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
-		Stage: remoteexecution.ExecutionStage_QUEUED,
-		ActionDigest: &remoteexecution.Digest{
-			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-			SizeBytes: 123,
-		},
-	})
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
-		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
-		Metadata: metadata,
-	})
 
-	// Let a worker for the largest size class pick it up once more.
-	// The client should get notified that the operation is in the
-	// EXECUTING stage once again.
-	timer3.EXPECT().Stop().Return(true)
-	clock.EXPECT().Now().Return(time.Unix(1006, 0)).Times(2)
-	timer4 := mock.NewMockTimer(ctrl)
-	clock.EXPECT().NewTimer(time.Minute).Return(timer4, nil)
-	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
-		WorkerId: map[string]string{
-			"hostname": "worker456",
-			"thread":   "42",
-		},
-		InstanceNamePrefix: "main",
-		Platform:           platformForTesting,
-		SizeClass:          8,
-		CurrentState: &remoteworker.CurrentState{
-			WorkerState: &remoteworker.CurrentState_Idle{
-				Idle: &emptypb.Empty{},
-			},
-		},
-	})
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1016},
-		DesiredState: &remoteworker.DesiredState{
-			WorkerState: &remoteworker.DesiredState_Executing_{
-				Executing: &remoteworker.DesiredState_Executing{
-					DigestFunction: remoteexecution.DigestFunction_SHA1,
-					ActionDigest: &remoteexecution.Digest{
-						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-						SizeBytes: 123,
-					},
-					Action: &remoteexecution.Action{
-						CommandDigest: &remoteexecution.Digest{
-							Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
-							SizeBytes: 456,
-						},
-						Timeout: &durationpb.Duration{Seconds: 300},
-					},
-					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1003},
+	// Let ten clients create ten operations. Because they all refer
+	// to the same action, all requests should be deduplicated into
+	// the same task. This means that we create ten initial size
+	// class selectors, of which the last nine are abandoned
+	// immediately.
+	operationParameters := [...]struct {
+		invocationID  string
+		operationName string
+	}{
+		{"0f0f22ec-908a-4ea7-8a78-b92ab4188e78", "b4667823-9f8e-451d-a3e4-4481ec67329f"},
+		{"0f67bd82-2867-45ec-9412-f058f27d2686", "1b9e4aaf-b984-4ebc-9b51-0e31bf1b0edb"},
+		{"3e3975fa-d723-42c6-bccb-a3358793f656", "e662fb47-f162-41b8-b29c-45b24fe9e273"},
+		{"557cd041-1d24-423c-9733-f94c8d2916b2", "def137ac-7724-43ff-98f9-b16a3ba01dcd"},
+		{"56a827ff-d0bb-4f90-839d-eb55d8060269", "64943e71-86c3-4153-a760-76c0ff30cd68"},
+		{"849810af-2e0b-45ae-965d-28642d6c6453", "da009be0-93fe-40ad-9e03-a14e2bee2ff9"},
+		{"9cadf0eb-1e28-49ea-b052-5d05cdc50303", "e0f4e177-369d-4412-a19c-b7b1969dd46e"},
+		{"9ff4fd36-7123-4b59-90e2-7f49cd0af05e", "34f633ac-c418-4a1d-8a69-796990008e9c"},
+		{"d0438436-cff3-45e1-9c0b-7e5af632c0a4", "46cdaa7c-6bfa-49e2-822e-31be760c51c5"},
+		{"e4896008-d596-44c7-8df6-6ced53dff6b0", "88929b3e-f664-4f11-873d-40324d06378e"},
+	}
+	initialSizeClassLearner := mock.NewMockLearner(ctrl)
+	for i, p := range operationParameters {
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA256, "fc96ea0eee854b45950d3a7448332445730886691b992cb7917da0853664f7c2", 123),
+		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "f7a3ac7c17e535bc9b54ab13dbbb95a52ca1f1edaf9503ce23ccb3eca331a4f5",
+				SizeBytes: 456,
+			},
+			Platform: platformForTesting,
+		}, buffer.UserProvided))
+
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		requestMetadata := &remoteexecution.RequestMetadata{
+			ToolInvocationId: p.invocationID,
+			TargetId:         "//:hello_world",
+		}
+		requestMetadataAny, err := anypb.New(requestMetadata)
+		require.NoError(t, err)
+		requestMetadataBin, err := proto.Marshal(requestMetadata)
+		require.NoError(t, err)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), testutil.EqProto(t, requestMetadata)).Return(
+			platform.MustNewKey("main", platformForTesting),
+			[]invocation.Key{invocation.MustNewKey(requestMetadataAny)},
+			initialSizeClassSelector,
+			nil,
+		)
+		if i == 0 {
+			initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+				Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
+		} else {
+			initialSizeClassSelector.EXPECT().Abandoned()
+		}
+
+		ctxWithCancel, cancel := context.WithCancel(ctx)
+
+		clock.EXPECT().Now().Return(time.Unix(1010+int64(i), 0))
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		timer.EXPECT().Stop().Return(true)
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(p.operationName))
+		stream, err := executionClient.Execute(
+			metadata.AppendToOutgoingContext(
+				ctxWithCancel,
+				"build.bazel.remote.execution.v2.requestmetadata-bin",
+				string(requestMetadataBin)),
+			&remoteexecution.ExecuteRequest{
+				InstanceName: "main",
+				ActionDigest: &remoteexecution.Digest{
+					Hash:      "fc96ea0eee854b45950d3a7448332445730886691b992cb7917da0853664f7c2",
+					SizeBytes: 123,
 				},
+			})
+		require.NoError(t, err)
+		update, err := stream.Recv()
+		require.NoError(t, err)
+		metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+			Stage: remoteexecution.ExecutionStage_QUEUED,
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "fc96ea0eee854b45950d3a7448332445730886691b992cb7917da0853664f7c2",
+				SizeBytes: 123,
 			},
-		},
-	}, response)
-	update, err = stream1.Recv()
-	require.NoError(t, err)
-	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
-		Stage: remoteexecution.ExecutionStage_EXECUTING,
-		ActionDigest: &remoteexecution.Digest{
-			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-			SizeBytes: 123,
-		},
-	})
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &longrunningpb.Operation{
-		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
-		Metadata: metadata,
-	}, update)
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &longrunningpb.Operation{
+			Name:     p.operationName,
+			Metadata: metadata,
+		}, update)
 
-	// Let the action succeed on the largest size class. This should
-	// cause the executing time on the largest size class to be
-	// provided to the learner, and completion to be reported to the
-	// client.
-	initialSizeClassLearner2.EXPECT().Succeeded(3*time.Second, []uint32{3, 8})
-	clock.EXPECT().Now().Return(time.Unix(1019, 0)).Times(3)
-	timer4.EXPECT().Stop().Return(true)
+		// Immediately cancel the request. The operation should
+		// still be valid for the next minute.
+		//
+		// Because cancelling the RPC happens asynchronously, we
+		// wait on clock.Now() to be called to ensure
+		// InMemoryBuildQueue has detected the cancelation.
+		cancelWait := make(chan struct{})
+		clock.EXPECT().Now().Return(time.Unix(1010+int64(i), 0)).Do(func() {
+			cancelWait <- struct{}{}
+		})
+		cancel()
+		<-cancelWait
+	}
+
+	// Let one worker execute the task. Because of in-flight
+	// deduplication, all ten operations should now be in the
+	// EXECUTING stage.
+	clock.EXPECT().Now().Return(time.Unix(1065, 0))
 	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
 		WorkerId: map[string]string{
-			"hostname": "worker456",
+			"hostname": "worker123",
 			"thread":   "42",
 		},
 		InstanceNamePrefix: "main",
 		Platform:           platformForTesting,
-		SizeClass:          8,
 		CurrentState: &remoteworker.CurrentState{
-			WorkerState: &remoteworker.CurrentState_Executing_{
-				Executing: &remoteworker.CurrentState_Executing{
-					ActionDigest: &remoteexecution.Digest{
-						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-						SizeBytes: 123,
-					},
-					ExecutionState: &remoteworker.CurrentState_Executing_Completed{
-						Completed: &remoteexecution.ExecuteResponse{
-							Result: &remoteexecution.ActionResult{
-								ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
-									VirtualExecutionDuration: &durationpb.Duration{Seconds: 3},
-								},
-							},
-						},
-					},
-				},
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
 			},
 		},
-		PreferBeingIdle: true,
+	})
+	require.NoError(t, err)
+	requestMetadata, err := anypb.New(&remoteexecution.RequestMetadata{
+		ToolInvocationId: "0f0f22ec-908a-4ea7-8a78-b92ab4188e78",
+		TargetId:         "//:hello_world",
 	})
 	require.NoError(t, err)
 	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1019},
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1075},
 		DesiredState: &remoteworker.DesiredState{
-			WorkerState: &remoteworker.DesiredState_Idle{
-				Idle: &emptypb.Empty{},
+			WorkerState: &remoteworker.DesiredState_Executing_{
+				Executing: &remoteworker.DesiredState_Executing{
+					DigestFunction: remoteexecution.DigestFunction_SHA256,
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "fc96ea0eee854b45950d3a7448332445730886691b992cb7917da0853664f7c2",
+						SizeBytes: 123,
+					},
+					Action: &remoteexecution.Action{
+						CommandDigest: &remoteexecution.Digest{
+							Hash:      "f7a3ac7c17e535bc9b54ab13dbbb95a52ca1f1edaf9503ce23ccb3eca331a4f5",
+							SizeBytes: 456,
+						},
+						Platform: platformForTesting,
+						Timeout:  &durationpb.Duration{Seconds: 1800},
+					},
+					QueuedTimestamp:   &timestamppb.Timestamp{Seconds: 1010},
+					AuxiliaryMetadata: []*anypb.Any{requestMetadata},
+				},
 			},
 		},
 	}, response)
-	update, err = stream1.Recv()
-	require.NoError(t, err)
-	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
-		Stage: remoteexecution.ExecutionStage_COMPLETED,
-		ActionDigest: &remoteexecution.Digest{
-			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-			SizeBytes: 123,
-		},
-	})
-	require.NoError(t, err)
-	executeResponse, err := anypb.New(&remoteexecution.ExecuteResponse{
-		Result: &remoteexecution.ActionResult{
-			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
-				VirtualExecutionDuration: &durationpb.Duration{Seconds: 3},
+
+	// Get listings of invocations known by the scheduler. Because
+	// we're requesting this one minute after the operations were
+	// created, we should gradually see this list shrink. Eventually
+	// all invocations should be removed.
+	initialSizeClassLearner.EXPECT().Abandoned()
+	invocationName := &buildqueuestate.InvocationName{
+		SizeClassQueueName: &buildqueuestate.SizeClassQueueName{
+			PlatformQueueName: &buildqueuestate.PlatformQueueName{
+				InstanceNamePrefix: "main",
+				Platform:           platformForTesting,
 			},
 		},
-	})
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
-		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
-		Metadata: metadata,
-		Done:     true,
-		Result:   &longrunningpb.Operation_Response{Response: executeResponse},
-	})
+	}
+	for i := 0; i <= len(operationParameters); i++ {
+		clock.EXPECT().Now().Return(time.Unix(1069+int64(i), 0)).Times(3)
+
+		invocationStates, err := buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
+			InvocationName: invocationName,
+			Filter:         buildqueuestate.ListInvocationChildrenRequest_ALL,
+		})
+		require.NoError(t, err)
+		require.Len(t, invocationStates.Children, len(operationParameters)-i)
+
+		invocationStates, err = buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
+			InvocationName: invocationName,
+			Filter:         buildqueuestate.ListInvocationChildrenRequest_ACTIVE,
+		})
+		require.NoError(t, err)
+		require.Len(t, invocationStates.Children, len(operationParameters)-i)
+
+		invocationStates, err = buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
+			InvocationName: invocationName,
+			Filter:         buildqueuestate.ListInvocationChildrenRequest_QUEUED,
+		})
+		require.NoError(t, err)
+		require.Empty(t, invocationStates.Children)
+	}
 }
 
-func TestInMemoryBuildQueueBackgroundRun(t *testing.T) {
+func TestInMemoryBuildQueuePreferBeingIdle(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
@@ -3157,19 +3567,8 @@ func TestInMemoryBuildQueueBackgroundRun(t *testing.T) {
 	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
 	executionClient := getExecutionClient(t, buildQueue)
 
-	// Register a platform queue that allows workers up to size
-	// class 8. The maximum needs to be provided to ensure that the
-	// execution strategy remains deterministic.
+	// Announce a new worker, which creates a queue for operations.
 	clock.EXPECT().Now().Return(time.Unix(1000, 0))
-	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
-		digest.MustNewInstanceName("main"),
-		platformForTesting,
-		/* workerInvocationStickinessLimits = */ nil,
-		/* maximumQueuedBackgroundLearningOperations = */ 10,
-		/* backgroundLearningOperationPriority = */ 100,
-		/* maximumSizeClass = */ 8))
-
-	clock.EXPECT().Now().Return(time.Unix(1002, 0))
 	response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
 		WorkerId: map[string]string{
 			"hostname": "worker123",
@@ -3177,24 +3576,16 @@ func TestInMemoryBuildQueueBackgroundRun(t *testing.T) {
 		},
 		InstanceNamePrefix: "main",
 		Platform:           platformForTesting,
-		SizeClass:          3,
 		CurrentState: &remoteworker.CurrentState{
-			WorkerState: &remoteworker.CurrentState_Executing_{
-				Executing: &remoteworker.CurrentState_Executing{
-					ActionDigest: &remoteexecution.Digest{
-						Hash:      "099a3f6dc1e8e91dbcca4ea964cd2237d4b11733",
-						SizeBytes: 123,
-					},
-					ExecutionState: &remoteworker.CurrentState_Executing_FetchingInputs{
-						FetchingInputs: &emptypb.Empty{},
-					},
-				},
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
 			},
 		},
+		PreferBeingIdle: true,
 	})
 	require.NoError(t, err)
 	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1002},
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1000},
 		DesiredState: &remoteworker.DesiredState{
 			WorkerState: &remoteworker.DesiredState_Idle{
 				Idle: &emptypb.Empty{},
@@ -3202,8 +3593,7 @@ func TestInMemoryBuildQueueBackgroundRun(t *testing.T) {
 		},
 	}, response)
 
-	// Let a client enqueue a new operation, which we'll initially
-	// schedule on the largest size class.
+	// Let a client enqueue an operation.
 	contentAddressableStorage.EXPECT().Get(
 		gomock.Any(),
 		digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123),
@@ -3220,23 +3610,25 @@ func TestInMemoryBuildQueueBackgroundRun(t *testing.T) {
 			SizeBytes: 456,
 		},
 	}), nil).Return(platform.MustNewKey("main", platformForTesting), nil, initialSizeClassSelector, nil)
-	initialSizeClassLearner1 := mock.NewMockLearner(ctrl)
-	initialSizeClassSelector.EXPECT().Select([]uint32{3, 8}).
-		Return(1, 3*time.Minute, 7*time.Minute, initialSizeClassLearner1)
-	clock.EXPECT().Now().Return(time.Unix(1003, 0))
-	timer1 := mock.NewMockTimer(ctrl)
-	clock.EXPECT().NewTimer(time.Minute).Return(timer1, nil)
-	uuidGenerator.EXPECT().Call().Return(uuid.Parse("36ebab65-3c4f-4faf-818b-2eabb4cd1b02"))
-
-	stream1, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
-		InstanceName: "main",
-		ActionDigest: &remoteexecution.Digest{
-			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-			SizeBytes: 123,
-		},
-	})
+	initialSizeClassLearner := mock.NewMockLearner(ctrl)
+	initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+		Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
+	clock.EXPECT().Now().Return(time.Unix(1001, 0))
+	timer := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+	timer.EXPECT().Stop().Return(true)
+	uuidGenerator.EXPECT().Call().Return(uuid.Parse("b9bb6e2c-04ff-4fbd-802b-105be93a8fb7"))
+	stream, err := executionClient.Execute(
+		ctx,
+		&remoteexecution.ExecuteRequest{
+			InstanceName: "main",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+				SizeBytes: 123,
+			},
+		})
 	require.NoError(t, err)
-	update, err := stream1.Recv()
+	update, err := stream.Recv()
 	require.NoError(t, err)
 	metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
 		Stage: remoteexecution.ExecutionStage_QUEUED,
@@ -3246,33 +3638,48 @@ func TestInMemoryBuildQueueBackgroundRun(t *testing.T) {
 		},
 	})
 	require.NoError(t, err)
-	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
-		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+	testutil.RequireEqualProto(t, &longrunningpb.Operation{
+		Name:     "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7",
 		Metadata: metadata,
-	})
+	}, update)
 
-	// Let a worker for the largest size class pick it up.
-	timer1.EXPECT().Stop().Return(true)
-	clock.EXPECT().Now().Return(time.Unix(1004, 0)).Times(2)
-	timer2 := mock.NewMockTimer(ctrl)
-	clock.EXPECT().NewTimer(time.Minute).Return(timer2, nil)
+	// Let a worker pick up the operation.
+	clock.EXPECT().Now().Return(time.Unix(1002, 0)).Times(2)
+	timer = mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+	timer.EXPECT().Stop().Return(true)
 	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
 		WorkerId: map[string]string{
-			"hostname": "worker456",
+			"hostname": "worker123",
 			"thread":   "42",
 		},
 		InstanceNamePrefix: "main",
 		Platform:           platformForTesting,
-		SizeClass:          8,
 		CurrentState: &remoteworker.CurrentState{
 			WorkerState: &remoteworker.CurrentState_Idle{
 				Idle: &emptypb.Empty{},
 			},
 		},
 	})
+	// The client should be informed the operation has started executing.
+	update, err = stream.Recv()
+	require.NoError(t, err)
+	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage: remoteexecution.ExecutionStage_EXECUTING,
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &longrunningpb.Operation{
+		Name:     "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7",
+		Metadata: metadata,
+	}, update)
+
 	require.NoError(t, err)
 	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1014},
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1012},
 		DesiredState: &remoteworker.DesiredState{
 			WorkerState: &remoteworker.DesiredState_Executing_{
 				Executing: &remoteworker.DesiredState_Executing{
@@ -3286,47 +3693,29 @@ func TestInMemoryBuildQueueBackgroundRun(t *testing.T) {
 							Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
 							SizeBytes: 456,
 						},
-						Timeout: &durationpb.Duration{Seconds: 420},
+						Timeout: &durationpb.Duration{Seconds: 1800},
 					},
-					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1003},
+					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1001},
 				},
 			},
 		},
 	}, response)
-	update, err = stream1.Recv()
-	require.NoError(t, err)
-	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
-		Stage: remoteexecution.ExecutionStage_EXECUTING,
-		ActionDigest: &remoteexecution.Digest{
-			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-			SizeBytes: 123,
-		},
-	})
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &longrunningpb.Operation{
-		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
-		Metadata: metadata,
-	}, update)
 
-	// The action succeeds on the worker of the largest size class.
-	// In response, request that the same action is rerun on the
-	// smaller size class. Because we don't want to leave the client
-	// blocked on that, this should be done as part of a separate
-	// task.
-	initialSizeClassLearner2 := mock.NewMockLearner(ctrl)
-	initialSizeClassLearner1.EXPECT().Succeeded(3*time.Second, []uint32{3, 8}).
-		Return(0, 30*time.Second, time.Minute, initialSizeClassLearner2)
-	uuidGenerator.EXPECT().Call().Return(uuid.Parse("30326ed7-101a-4bf2-93eb-fcb6e7672415"))
-	timer2.EXPECT().Stop().Return(true)
-	clock.EXPECT().Now().Return(time.Unix(1005, 0)).Times(3)
+	// Let the worker complete the execution of the operation.
+	// Normally this would be a blocking call, as it would wait
+	// until more work is available. However, because
+	// PreferBeingIdle is set, the call will return immediately,
+	// explicitly forcing the worker to the idle state. This allows
+	// workers to terminate gracefully.
+	initialSizeClassLearner.EXPECT().Succeeded(10*time.Second, []uint32{0})
+	clock.EXPECT().Now().Return(time.Unix(1003, 0)).Times(3)
 	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
 		WorkerId: map[string]string{
-			"hostname": "worker456",
+			"hostname": "worker123",
 			"thread":   "42",
 		},
 		InstanceNamePrefix: "main",
 		Platform:           platformForTesting,
-		SizeClass:          8,
 		CurrentState: &remoteworker.CurrentState{
 			WorkerState: &remoteworker.CurrentState_Executing_{
 				Executing: &remoteworker.CurrentState_Executing{
@@ -3338,7 +3727,7 @@ func TestInMemoryBuildQueueBackgroundRun(t *testing.T) {
 						Completed: &remoteexecution.ExecuteResponse{
 							Result: &remoteexecution.ActionResult{
 								ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
-									VirtualExecutionDuration: &durationpb.Duration{Seconds: 3},
+									VirtualExecutionDuration: &durationpb.Duration{Seconds: 10},
 								},
 							},
 						},
@@ -3350,14 +3739,17 @@ func TestInMemoryBuildQueueBackgroundRun(t *testing.T) {
 	})
 	require.NoError(t, err)
 	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1005},
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1003},
 		DesiredState: &remoteworker.DesiredState{
 			WorkerState: &remoteworker.DesiredState_Idle{
 				Idle: &emptypb.Empty{},
 			},
 		},
 	}, response)
-	update, err = stream1.Recv()
+
+	// The client should be informed the operation has completed. This
+	// should be the last message to be returned.
+	update, err = stream.Recv()
 	require.NoError(t, err)
 	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
 		Stage: remoteexecution.ExecutionStage_COMPLETED,
@@ -3370,67 +3762,72 @@ func TestInMemoryBuildQueueBackgroundRun(t *testing.T) {
 	executeResponse, err := anypb.New(&remoteexecution.ExecuteResponse{
 		Result: &remoteexecution.ActionResult{
 			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
-				VirtualExecutionDuration: &durationpb.Duration{Seconds: 3},
+				VirtualExecutionDuration: &durationpb.Duration{Seconds: 10},
 			},
 		},
 	})
 	require.NoError(t, err)
 	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
-		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+		Name:     "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7",
 		Metadata: metadata,
 		Done:     true,
 		Result:   &longrunningpb.Operation_Response{Response: executeResponse},
 	})
 
-	// Let the worker for the smaller size class pick up the
-	// background task. The action should be identical to the
-	// original one, except that the timeout is altered and
-	// do_not_cache is set.
-	clock.EXPECT().Now().Return(time.Unix(1006, 0))
-	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+	_, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestInMemoryBuildQueueMultipleSizeClasses(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	// Register a platform queue that allows workers up to size
+	// class 8. The maximum needs to be provided to ensure that the
+	// execution strategy remains deterministic.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
+		digest.MustNewInstanceName("main"),
+		platformForTesting,
+		/* workerInvocationStickinessLimits = */ nil,
+		/* maximumQueuedBackgroundLearningOperations = */ 0,
+		/* backgroundLearningOperationPriority = */ 0,
+		/* maximumSizeClass = */ 8,
+		/* maximumQueuedOperations = */ 0,
+		/* preemptionPolicy = */ nil,
+		/* workerResourceRequirements = */ nil,
+		/* sizeClassEscalationPolicy = */ nil))
+
+	// Workers with a higher size class should be rejected, as no
+	// requests will end up getting sent to them.
+	clock.EXPECT().Now().Return(time.Unix(1001, 0))
+	_, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
 		WorkerId: map[string]string{
 			"hostname": "worker123",
 			"thread":   "42",
 		},
 		InstanceNamePrefix: "main",
 		Platform:           platformForTesting,
-		SizeClass:          3,
+		SizeClass:          9,
 		CurrentState: &remoteworker.CurrentState{
 			WorkerState: &remoteworker.CurrentState_Idle{
 				Idle: &emptypb.Empty{},
 			},
 		},
 	})
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1016},
-		DesiredState: &remoteworker.DesiredState{
-			WorkerState: &remoteworker.DesiredState_Executing_{
-				Executing: &remoteworker.DesiredState_Executing{
-					DigestFunction: remoteexecution.DigestFunction_SHA1,
-					ActionDigest: &remoteexecution.Digest{
-						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-						SizeBytes: 123,
-					},
-					Action: &remoteexecution.Action{
-						CommandDigest: &remoteexecution.Digest{
-							Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
-							SizeBytes: 456,
-						},
-						Timeout:    &durationpb.Duration{Seconds: 60},
-						DoNotCache: true,
-					},
-					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1003},
-				},
-			},
-		},
-	}, response)
+	testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Worker provided size class 9, which exceeds the predeclared maximum of 8"), err)
 
-	// Let the action succeed on the smaller size class. This should
-	// cause the initial size class learner to be finalized.
-	initialSizeClassLearner2.EXPECT().Succeeded(3*time.Second, []uint32{3, 8})
-	clock.EXPECT().Now().Return(time.Unix(1019, 0))
-	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+	// Announce a worker with a smaller size class, which should be
+	// permitted.
+	clock.EXPECT().Now().Return(time.Unix(1002, 0))
+	response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
 		WorkerId: map[string]string{
 			"hostname": "worker123",
 			"thread":   "42",
@@ -3442,450 +3839,4430 @@ func TestInMemoryBuildQueueBackgroundRun(t *testing.T) {
 			WorkerState: &remoteworker.CurrentState_Executing_{
 				Executing: &remoteworker.CurrentState_Executing{
 					ActionDigest: &remoteexecution.Digest{
-						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						Hash:      "099a3f6dc1e8e91dbcca4ea964cd2237d4b11733",
 						SizeBytes: 123,
 					},
-					ExecutionState: &remoteworker.CurrentState_Executing_Completed{
-						Completed: &remoteexecution.ExecuteResponse{
-							Result: &remoteexecution.ActionResult{
-								ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
-									VirtualExecutionDuration: &durationpb.Duration{Seconds: 3},
-								},
-							},
-						},
+					ExecutionState: &remoteworker.CurrentState_Executing_FetchingInputs{
+						FetchingInputs: &emptypb.Empty{},
 					},
 				},
 			},
 		},
-		PreferBeingIdle: true,
 	})
 	require.NoError(t, err)
 	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1019},
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1002},
 		DesiredState: &remoteworker.DesiredState{
 			WorkerState: &remoteworker.DesiredState_Idle{
 				Idle: &emptypb.Empty{},
 			},
 		},
 	}, response)
-}
-
-func TestInMemoryBuildQueueIdleSynchronizingWorkers(t *testing.T) {
-	ctrl, ctx := gomock.WithContext(context.Background(), t)
-
-	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
-	mockClock := mock.NewMockClock(ctrl)
-	mockClock.EXPECT().Now().Return(time.Unix(0, 0))
-	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
-	actionRouter := mock.NewMockActionRouter(ctrl)
-	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, mockClock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
-	executionClient := getExecutionClient(t, buildQueue)
 
-	// Common values used by steps below.
-	action := &remoteexecution.Action{
+	// Let a client enqueue a new operation, which we'll schedule on
+	// the smaller size class.
+	contentAddressableStorage.EXPECT().Get(
+		gomock.Any(),
+		digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123),
+	).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
 		CommandDigest: &remoteexecution.Digest{
 			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
 			SizeBytes: 456,
 		},
-		DoNotCache: true,
-		Timeout:    &durationpb.Duration{Seconds: 420},
-	}
-	actionDigest := &remoteexecution.Digest{
-		Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-		SizeBytes: 123,
-	}
-	invocationID1, err := anypb.New(&remoteexecution.RequestMetadata{
-		ToolInvocationId: "33b38903-d456-4417-951b-bd8a2681c136",
-	})
-	require.NoError(t, err)
-	invocationID2, err := anypb.New(&remoteexecution.RequestMetadata{
-		ToolInvocationId: "75b27319-2704-4fa0-84c9-6881ea5b93ad",
-	})
-	require.NoError(t, err)
-	invocationID3, err := anypb.New(&remoteexecution.RequestMetadata{
-		ToolInvocationId: "d6be714f-cef6-408f-b3c7-dfeeae48f63f",
-	})
-	require.NoError(t, err)
-	workerID1 := map[string]string{
-		"hostname": "worker123",
-		"thread":   "42",
-	}
-	workerID2 := map[string]string{
-		"hostname": "worker123",
-		"thread":   "43",
-	}
-	sizeClassQueueName := &buildqueuestate.SizeClassQueueName{
-		PlatformQueueName: &buildqueuestate.PlatformQueueName{
-			Platform: platformForTesting,
+	}, buffer.UserProvided))
+	initialSizeClassSelector := mock.NewMockSelector(ctrl)
+	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, &remoteexecution.Action{
+		CommandDigest: &remoteexecution.Digest{
+			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+			SizeBytes: 456,
 		},
-	}
-	metadataExecuting, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
-		Stage:        remoteexecution.ExecutionStage_EXECUTING,
-		ActionDigest: actionDigest,
-	})
-	require.NoError(t, err)
-	metadataCompleted, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
-		Stage:        remoteexecution.ExecutionStage_COMPLETED,
-		ActionDigest: actionDigest,
-	})
-	require.NoError(t, err)
-	executeResponse, err := anypb.New(&remoteexecution.ExecuteResponse{
-		Result: &remoteexecution.ActionResult{},
-	})
-	require.NoError(t, err)
-
-	contentAddressableStorage.EXPECT().Get(
-		gomock.Any(),
-		digest.MustNewDigest("", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123),
-	).Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided)).AnyTimes()
-
-	// Create a worker that does a blocking Synchronize() call
-	// against the scheduler.
-	mockClock.EXPECT().Now().Return(time.Unix(1000, 0))
-	timer1 := mock.NewMockTimer(ctrl)
-	wait1 := make(chan struct{}, 1)
-	mockClock.EXPECT().NewTimer(time.Minute).DoAndReturn(func(d time.Duration) (clock.Timer, <-chan time.Time) {
-		wait1 <- struct{}{}
-		return timer1, nil
-	})
-	var response1 *remoteworker.SynchronizeResponse
-	var err1 error
-	wait2 := make(chan struct{}, 1)
-	go func() {
-		response1, err1 = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
-			WorkerId: workerID1,
-			Platform: platformForTesting,
-			CurrentState: &remoteworker.CurrentState{
-				WorkerState: &remoteworker.CurrentState_Idle{
-					Idle: &emptypb.Empty{},
-				},
-			},
-		})
-		wait2 <- struct{}{}
-	}()
-	<-wait1
-
-	// Assign a task to it. The worker should be woken up directly.
-	// The client should immediately receive an EXECUTING update.
-	// There is no need to return QUEUED.
-	initialSizeClassSelector1 := mock.NewMockSelector(ctrl)
-	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).Return(
-		platform.MustNewKey("", platformForTesting),
-		[]invocation.Key{invocation.MustNewKey(invocationID1)},
-		initialSizeClassSelector1,
-		nil,
-	)
+	}), nil).Return(platform.MustNewKey("main", platformForTesting), nil, initialSizeClassSelector, nil)
 	initialSizeClassLearner1 := mock.NewMockLearner(ctrl)
-	initialSizeClassSelector1.EXPECT().Select([]uint32{0}).
+	initialSizeClassSelector.EXPECT().Select([]uint32{3, 8}).
 		Return(0, 3*time.Minute, 7*time.Minute, initialSizeClassLearner1)
-	mockClock.EXPECT().Now().Return(time.Unix(1001, 0)).Times(2)
-	timer2 := mock.NewMockTimer(ctrl)
-	mockClock.EXPECT().NewTimer(time.Minute).Return(timer2, nil)
+	clock.EXPECT().Now().Return(time.Unix(1003, 0))
+	timer1 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer1, nil)
 	uuidGenerator.EXPECT().Call().Return(uuid.Parse("36ebab65-3c4f-4faf-818b-2eabb4cd1b02"))
-	timer1.EXPECT().Stop()
 
 	stream1, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
-		ActionDigest: actionDigest,
+		InstanceName: "main",
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
 	})
 	require.NoError(t, err)
 	update, err := stream1.Recv()
 	require.NoError(t, err)
+	metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage: remoteexecution.ExecutionStage_QUEUED,
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
 	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
 		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
-		Metadata: metadataExecuting,
+		Metadata: metadata,
 	})
 
-	// The worker should get unblocked.
-	<-wait2
-	require.NoError(t, err1)
+	// Let the worker for the small size class pick it up.
+	timer1.EXPECT().Stop().Return(true)
+	clock.EXPECT().Now().Return(time.Unix(1004, 0)).Times(2)
+	timer2 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer2, nil)
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		SizeClass:          3,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	})
+	require.NoError(t, err)
 	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1011},
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1014},
 		DesiredState: &remoteworker.DesiredState{
 			WorkerState: &remoteworker.DesiredState_Executing_{
 				Executing: &remoteworker.DesiredState_Executing{
-					DigestFunction:  remoteexecution.DigestFunction_SHA1,
-					ActionDigest:    actionDigest,
-					Action:          action,
-					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1001},
+					DigestFunction: remoteexecution.DigestFunction_SHA1,
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						SizeBytes: 123,
+					},
+					Action: &remoteexecution.Action{
+						CommandDigest: &remoteexecution.Digest{
+							Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+							SizeBytes: 456,
+						},
+						Timeout: &durationpb.Duration{Seconds: 420},
+					},
+					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1003},
 				},
 			},
 		},
-	}, response1)
+	}, response)
+	update, err = stream1.Recv()
+	require.NoError(t, err)
+	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage: remoteexecution.ExecutionStage_EXECUTING,
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &longrunningpb.Operation{
+		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+		Metadata: metadata,
+	}, update)
 
-	// Let the worker complete the operation. This should wake up
-	// the client.
-	mockClock.EXPECT().Now().Return(time.Unix(1002, 0)).Times(3)
-	initialSizeClassLearner1.EXPECT().Succeeded(time.Duration(0), []uint32{0})
-	timer2.EXPECT().Stop()
+	// The action fails on the worker of the small size class,
+	// meaning that we retry it on the largest size class. An update
+	// should be sent back to the client that the operation has
+	// moved back to the QUEUED stage.
+	initialSizeClassLearner2 := mock.NewMockLearner(ctrl)
+	initialSizeClassLearner1.EXPECT().Failed(false).
+		Return(2*time.Minute, 5*time.Minute, initialSizeClassLearner2)
+	timer2.EXPECT().Stop().Return(true)
+	clock.EXPECT().Now().Return(time.Unix(1005, 0)).Times(2)
 	timer3 := mock.NewMockTimer(ctrl)
-	wait3 := make(chan struct{}, 1)
-	mockClock.EXPECT().NewTimer(time.Minute).DoAndReturn(func(d time.Duration) (clock.Timer, <-chan time.Time) {
-		wait3 <- struct{}{}
-		return timer3, nil
-	})
-	var response2 *remoteworker.SynchronizeResponse
-	var err2 error
-	wait4 := make(chan struct{}, 1)
-	go func() {
-		response2, err2 = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
-			WorkerId: workerID1,
-			Platform: platformForTesting,
-			CurrentState: &remoteworker.CurrentState{
-				WorkerState: &remoteworker.CurrentState_Executing_{
-					Executing: &remoteworker.CurrentState_Executing{
-						ActionDigest: actionDigest,
-						ExecutionState: &remoteworker.CurrentState_Executing_Completed{
-							Completed: &remoteexecution.ExecuteResponse{
-								Result: &remoteexecution.ActionResult{},
+	clock.EXPECT().NewTimer(time.Minute).Return(timer3, nil)
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		SizeClass:          3,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						SizeBytes: 123,
+					},
+					ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+						Completed: &remoteexecution.ExecuteResponse{
+							Result: &remoteexecution.ActionResult{
+								ExitCode: 137,
 							},
 						},
 					},
 				},
 			},
-		})
-		wait4 <- struct{}{}
-	}()
-	<-wait3
-
+		},
+		PreferBeingIdle: true,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1005},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, response)
 	update, err = stream1.Recv()
 	require.NoError(t, err)
+	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage: remoteexecution.ExecutionStage_QUEUED,
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
 	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
 		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
-		Metadata: metadataCompleted,
-		Done:     true,
-		Result:   &longrunningpb.Operation_Response{Response: executeResponse},
+		Metadata: metadata,
 	})
+
+	// Let a worker for the largest size class pick it up once more.
+	// The client should get notified that the operation is in the
+	// EXECUTING stage once again.
+	timer3.EXPECT().Stop().Return(true)
+	clock.EXPECT().Now().Return(time.Unix(1006, 0)).Times(2)
+	timer4 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer4, nil)
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker456",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		SizeClass:          8,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1016},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Executing_{
+				Executing: &remoteworker.DesiredState_Executing{
+					DigestFunction: remoteexecution.DigestFunction_SHA1,
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						SizeBytes: 123,
+					},
+					Action: &remoteexecution.Action{
+						CommandDigest: &remoteexecution.Digest{
+							Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+							SizeBytes: 456,
+						},
+						Timeout: &durationpb.Duration{Seconds: 300},
+					},
+					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1003},
+				},
+			},
+		},
+	}, response)
+	update, err = stream1.Recv()
+	require.NoError(t, err)
+	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage: remoteexecution.ExecutionStage_EXECUTING,
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &longrunningpb.Operation{
+		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+		Metadata: metadata,
+	}, update)
+
+	// Let the action succeed on the largest size class. This should
+	// cause the executing time on the largest size class to be
+	// provided to the learner, and completion to be reported to the
+	// client.
+	initialSizeClassLearner2.EXPECT().Succeeded(3*time.Second, []uint32{3, 8})
+	clock.EXPECT().Now().Return(time.Unix(1019, 0)).Times(3)
+	timer4.EXPECT().Stop().Return(true)
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker456",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		SizeClass:          8,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						SizeBytes: 123,
+					},
+					ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+						Completed: &remoteexecution.ExecuteResponse{
+							Result: &remoteexecution.ActionResult{
+								ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+									VirtualExecutionDuration: &durationpb.Duration{Seconds: 3},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		PreferBeingIdle: true,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1019},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, response)
+	update, err = stream1.Recv()
+	require.NoError(t, err)
+	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage: remoteexecution.ExecutionStage_COMPLETED,
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	executeResponse, err := anypb.New(&remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+				VirtualExecutionDuration: &durationpb.Duration{Seconds: 3},
+			},
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
+		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+		Metadata: metadata,
+		Done:     true,
+		Result:   &longrunningpb.Operation_Response{Response: executeResponse},
+	})
+}
+
+func TestInMemoryBuildQueueSizeClassEscalation(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	// Register a platform queue that doubles the timeout of tasks
+	// that are retried on the largest size class.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
+		digest.MustNewInstanceName("main"),
+		platformForTesting,
+		/* workerInvocationStickinessLimits = */ nil,
+		/* maximumQueuedBackgroundLearningOperations = */ 0,
+		/* backgroundLearningOperationPriority = */ 0,
+		/* maximumSizeClass = */ 8,
+		/* maximumQueuedOperations = */ 0,
+		/* preemptionPolicy = */ nil,
+		/* workerResourceRequirements = */ nil,
+		&scheduler.SizeClassEscalationPolicy{
+			TimeoutMultiplier:        2,
+			ResourceLimitsMultiplier: 1.5,
+		}))
+
+	// Announce a worker with a smaller size class.
+	clock.EXPECT().Now().Return(time.Unix(1001, 0))
+	response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		SizeClass:          3,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "099a3f6dc1e8e91dbcca4ea964cd2237d4b11733",
+						SizeBytes: 123,
+					},
+					ExecutionState: &remoteworker.CurrentState_Executing_FetchingInputs{
+						FetchingInputs: &emptypb.Empty{},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1001},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, response)
+
+	// Let a client enqueue a new operation, which we'll schedule on
+	// the smaller size class.
+	contentAddressableStorage.EXPECT().Get(
+		gomock.Any(),
+		digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123),
+	).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+		CommandDigest: &remoteexecution.Digest{
+			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+			SizeBytes: 456,
+		},
+	}, buffer.UserProvided))
+	initialSizeClassSelector := mock.NewMockSelector(ctrl)
+	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).
+		Return(platform.MustNewKey("main", platformForTesting), nil, initialSizeClassSelector, nil)
+	initialSizeClassLearner1 := mock.NewMockLearner(ctrl)
+	initialSizeClassSelector.EXPECT().Select([]uint32{3, 8}).
+		Return(0, 3*time.Minute, 7*time.Minute, initialSizeClassLearner1)
+	clock.EXPECT().Now().Return(time.Unix(1003, 0))
+	timer1 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer1, nil)
+	uuidGenerator.EXPECT().Call().Return(uuid.Parse("36ebab65-3c4f-4faf-818b-2eabb4cd1b02"))
+
+	stream1, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+		InstanceName: "main",
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
 	_, err = stream1.Recv()
-	require.Equal(t, io.EOF, err)
+	require.NoError(t, err)
+
+	// Let the worker for the small size class pick it up.
+	timer1.EXPECT().Stop().Return(true)
+	clock.EXPECT().Now().Return(time.Unix(1004, 0)).Times(2)
+	timer2 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer2, nil)
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		SizeClass:          3,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &durationpb.Duration{Seconds: 420}, response.DesiredState.GetExecuting().Action.Timeout)
+	_, err = stream1.Recv()
+	require.NoError(t, err)
+
+	// The action fails on the worker of the small size class. It
+	// should be retried on the largest size class.
+	initialSizeClassLearner2 := mock.NewMockLearner(ctrl)
+	initialSizeClassLearner1.EXPECT().Failed(false).
+		Return(2*time.Minute, 5*time.Minute, initialSizeClassLearner2)
+	timer2.EXPECT().Stop().Return(true)
+	clock.EXPECT().Now().Return(time.Unix(1005, 0)).Times(2)
+	timer3 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer3, nil)
+	_, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		SizeClass:          3,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						SizeBytes: 123,
+					},
+					ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+						Completed: &remoteexecution.ExecuteResponse{
+							Result: &remoteexecution.ActionResult{
+								ExitCode: 137,
+							},
+						},
+					},
+				},
+			},
+		},
+		PreferBeingIdle: true,
+	})
+	require.NoError(t, err)
+	_, err = stream1.Recv()
+	require.NoError(t, err)
+
+	// When a worker for the largest size class picks it up, the
+	// timeout should be scaled by the multiplier. The failed
+	// attempt should be recorded in the auxiliary metadata, so that
+	// the worker can scale its resource limits as well.
+	timer3.EXPECT().Stop().Return(true)
+	clock.EXPECT().Now().Return(time.Unix(1006, 0)).Times(2)
+	timer4 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer4, nil)
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker456",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		SizeClass:          8,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	})
+	require.NoError(t, err)
+	escalation, err := anypb.New(&resourceusage.SizeClassEscalation{
+		FailedAttempts: []*resourceusage.SizeClassEscalation_FailedAttempt{
+			{
+				SizeClass: 3,
+				Timeout:   &durationpb.Duration{Seconds: 420},
+				ExitCode:  137,
+			},
+		},
+		ResourceLimitsMultiplier: 1.5,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1016},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Executing_{
+				Executing: &remoteworker.DesiredState_Executing{
+					DigestFunction: remoteexecution.DigestFunction_SHA1,
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						SizeBytes: 123,
+					},
+					Action: &remoteexecution.Action{
+						CommandDigest: &remoteexecution.Digest{
+							Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+							SizeBytes: 456,
+						},
+						Timeout: &durationpb.Duration{Seconds: 600},
+					},
+					QueuedTimestamp:   &timestamppb.Timestamp{Seconds: 1003},
+					AuxiliaryMetadata: []*anypb.Any{escalation},
+				},
+			},
+		},
+	}, response)
+	_, err = stream1.Recv()
+	require.NoError(t, err)
+}
+
+func TestInMemoryBuildQueueBackgroundRun(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	// Register a platform queue that allows workers up to size
+	// class 8. The maximum needs to be provided to ensure that the
+	// execution strategy remains deterministic.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
+		digest.MustNewInstanceName("main"),
+		platformForTesting,
+		/* workerInvocationStickinessLimits = */ nil,
+		/* maximumQueuedBackgroundLearningOperations = */ 10,
+		/* backgroundLearningOperationPriority = */ 100,
+		/* maximumSizeClass = */ 8,
+		/* maximumQueuedOperations = */ 0,
+		/* preemptionPolicy = */ nil,
+		/* workerResourceRequirements = */ nil,
+		/* sizeClassEscalationPolicy = */ nil))
+
+	clock.EXPECT().Now().Return(time.Unix(1002, 0))
+	response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		SizeClass:          3,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "099a3f6dc1e8e91dbcca4ea964cd2237d4b11733",
+						SizeBytes: 123,
+					},
+					ExecutionState: &remoteworker.CurrentState_Executing_FetchingInputs{
+						FetchingInputs: &emptypb.Empty{},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1002},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, response)
+
+	// Let a client enqueue a new operation, which we'll initially
+	// schedule on the largest size class.
+	contentAddressableStorage.EXPECT().Get(
+		gomock.Any(),
+		digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123),
+	).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+		CommandDigest: &remoteexecution.Digest{
+			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+			SizeBytes: 456,
+		},
+	}, buffer.UserProvided))
+	initialSizeClassSelector := mock.NewMockSelector(ctrl)
+	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, &remoteexecution.Action{
+		CommandDigest: &remoteexecution.Digest{
+			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+			SizeBytes: 456,
+		},
+	}), nil).Return(platform.MustNewKey("main", platformForTesting), nil, initialSizeClassSelector, nil)
+	initialSizeClassLearner1 := mock.NewMockLearner(ctrl)
+	initialSizeClassSelector.EXPECT().Select([]uint32{3, 8}).
+		Return(1, 3*time.Minute, 7*time.Minute, initialSizeClassLearner1)
+	clock.EXPECT().Now().Return(time.Unix(1003, 0))
+	timer1 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer1, nil)
+	uuidGenerator.EXPECT().Call().Return(uuid.Parse("36ebab65-3c4f-4faf-818b-2eabb4cd1b02"))
+
+	stream1, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+		InstanceName: "main",
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	update, err := stream1.Recv()
+	require.NoError(t, err)
+	metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage: remoteexecution.ExecutionStage_QUEUED,
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
+		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+		Metadata: metadata,
+	})
+
+	// Let a worker for the largest size class pick it up.
+	timer1.EXPECT().Stop().Return(true)
+	clock.EXPECT().Now().Return(time.Unix(1004, 0)).Times(2)
+	timer2 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer2, nil)
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker456",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		SizeClass:          8,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1014},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Executing_{
+				Executing: &remoteworker.DesiredState_Executing{
+					DigestFunction: remoteexecution.DigestFunction_SHA1,
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						SizeBytes: 123,
+					},
+					Action: &remoteexecution.Action{
+						CommandDigest: &remoteexecution.Digest{
+							Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+							SizeBytes: 456,
+						},
+						Timeout: &durationpb.Duration{Seconds: 420},
+					},
+					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1003},
+				},
+			},
+		},
+	}, response)
+	update, err = stream1.Recv()
+	require.NoError(t, err)
+	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage: remoteexecution.ExecutionStage_EXECUTING,
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &longrunningpb.Operation{
+		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+		Metadata: metadata,
+	}, update)
+
+	// The action succeeds on the worker of the largest size class.
+	// In response, request that the same action is rerun on the
+	// smaller size class. Because we don't want to leave the client
+	// blocked on that, this should be done as part of a separate
+	// task.
+	initialSizeClassLearner2 := mock.NewMockLearner(ctrl)
+	initialSizeClassLearner1.EXPECT().Succeeded(3*time.Second, []uint32{3, 8}).
+		Return(0, 30*time.Second, time.Minute, initialSizeClassLearner2)
+	uuidGenerator.EXPECT().Call().Return(uuid.Parse("30326ed7-101a-4bf2-93eb-fcb6e7672415"))
+	timer2.EXPECT().Stop().Return(true)
+	clock.EXPECT().Now().Return(time.Unix(1005, 0)).Times(3)
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker456",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		SizeClass:          8,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						SizeBytes: 123,
+					},
+					ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+						Completed: &remoteexecution.ExecuteResponse{
+							Result: &remoteexecution.ActionResult{
+								ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+									VirtualExecutionDuration: &durationpb.Duration{Seconds: 3},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		PreferBeingIdle: true,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1005},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, response)
+	update, err = stream1.Recv()
+	require.NoError(t, err)
+	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage: remoteexecution.ExecutionStage_COMPLETED,
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	executeResponse, err := anypb.New(&remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{
+			ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+				VirtualExecutionDuration: &durationpb.Duration{Seconds: 3},
+			},
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
+		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+		Metadata: metadata,
+		Done:     true,
+		Result:   &longrunningpb.Operation_Response{Response: executeResponse},
+	})
+
+	// Let the worker for the smaller size class pick up the
+	// background task. The action should be identical to the
+	// original one, except that the timeout is altered and
+	// do_not_cache is set.
+	clock.EXPECT().Now().Return(time.Unix(1006, 0))
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		SizeClass:          3,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1016},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Executing_{
+				Executing: &remoteworker.DesiredState_Executing{
+					DigestFunction: remoteexecution.DigestFunction_SHA1,
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						SizeBytes: 123,
+					},
+					Action: &remoteexecution.Action{
+						CommandDigest: &remoteexecution.Digest{
+							Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+							SizeBytes: 456,
+						},
+						Timeout:    &durationpb.Duration{Seconds: 60},
+						DoNotCache: true,
+					},
+					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1003},
+					Priority:        100,
+				},
+			},
+		},
+	}, response)
+
+	// Let the action succeed on the smaller size class. This should
+	// cause the initial size class learner to be finalized.
+	initialSizeClassLearner2.EXPECT().Succeeded(3*time.Second, []uint32{3, 8})
+	clock.EXPECT().Now().Return(time.Unix(1019, 0))
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		SizeClass:          3,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						SizeBytes: 123,
+					},
+					ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+						Completed: &remoteexecution.ExecuteResponse{
+							Result: &remoteexecution.ActionResult{
+								ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+									VirtualExecutionDuration: &durationpb.Duration{Seconds: 3},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		PreferBeingIdle: true,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1019},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, response)
+}
+
+func TestInMemoryBuildQueueIdleSynchronizingWorkers(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	mockClock := mock.NewMockClock(ctrl)
+	mockClock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, mockClock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	// Common values used by steps below.
+	action := &remoteexecution.Action{
+		CommandDigest: &remoteexecution.Digest{
+			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+			SizeBytes: 456,
+		},
+		DoNotCache: true,
+		Timeout:    &durationpb.Duration{Seconds: 420},
+	}
+	actionDigest := &remoteexecution.Digest{
+		Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+		SizeBytes: 123,
+	}
+	invocationID1, err := anypb.New(&remoteexecution.RequestMetadata{
+		ToolInvocationId: "33b38903-d456-4417-951b-bd8a2681c136",
+	})
+	require.NoError(t, err)
+	invocationID2, err := anypb.New(&remoteexecution.RequestMetadata{
+		ToolInvocationId: "75b27319-2704-4fa0-84c9-6881ea5b93ad",
+	})
+	require.NoError(t, err)
+	invocationID3, err := anypb.New(&remoteexecution.RequestMetadata{
+		ToolInvocationId: "d6be714f-cef6-408f-b3c7-dfeeae48f63f",
+	})
+	require.NoError(t, err)
+	workerID1 := map[string]string{
+		"hostname": "worker123",
+		"thread":   "42",
+	}
+	workerID2 := map[string]string{
+		"hostname": "worker123",
+		"thread":   "43",
+	}
+	sizeClassQueueName := &buildqueuestate.SizeClassQueueName{
+		PlatformQueueName: &buildqueuestate.PlatformQueueName{
+			Platform: platformForTesting,
+		},
+	}
+	metadataExecuting, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage:        remoteexecution.ExecutionStage_EXECUTING,
+		ActionDigest: actionDigest,
+	})
+	require.NoError(t, err)
+	metadataCompleted, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage:        remoteexecution.ExecutionStage_COMPLETED,
+		ActionDigest: actionDigest,
+	})
+	require.NoError(t, err)
+	executeResponse, err := anypb.New(&remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{},
+	})
+	require.NoError(t, err)
+
+	contentAddressableStorage.EXPECT().Get(
+		gomock.Any(),
+		digest.MustNewDigest("", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123),
+	).Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided)).AnyTimes()
+
+	// Create a worker that does a blocking Synchronize() call
+	// against the scheduler.
+	mockClock.EXPECT().Now().Return(time.Unix(1000, 0))
+	timer1 := mock.NewMockTimer(ctrl)
+	wait1 := make(chan struct{}, 1)
+	mockClock.EXPECT().NewTimer(time.Minute).DoAndReturn(func(d time.Duration) (clock.Timer, <-chan time.Time) {
+		wait1 <- struct{}{}
+		return timer1, nil
+	})
+	var response1 *remoteworker.SynchronizeResponse
+	var err1 error
+	wait2 := make(chan struct{}, 1)
+	go func() {
+		response1, err1 = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: workerID1,
+			Platform: platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Idle{
+					Idle: &emptypb.Empty{},
+				},
+			},
+		})
+		wait2 <- struct{}{}
+	}()
+	<-wait1
+
+	// Assign a task to it. The worker should be woken up directly.
+	// The client should immediately receive an EXECUTING update.
+	// There is no need to return QUEUED.
+	initialSizeClassSelector1 := mock.NewMockSelector(ctrl)
+	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).Return(
+		platform.MustNewKey("", platformForTesting),
+		[]invocation.Key{invocation.MustNewKey(invocationID1)},
+		initialSizeClassSelector1,
+		nil,
+	)
+	initialSizeClassLearner1 := mock.NewMockLearner(ctrl)
+	initialSizeClassSelector1.EXPECT().Select([]uint32{0}).
+		Return(0, 3*time.Minute, 7*time.Minute, initialSizeClassLearner1)
+	mockClock.EXPECT().Now().Return(time.Unix(1001, 0)).Times(2)
+	timer2 := mock.NewMockTimer(ctrl)
+	mockClock.EXPECT().NewTimer(time.Minute).Return(timer2, nil)
+	uuidGenerator.EXPECT().Call().Return(uuid.Parse("36ebab65-3c4f-4faf-818b-2eabb4cd1b02"))
+	timer1.EXPECT().Stop()
+
+	stream1, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+		ActionDigest: actionDigest,
+	})
+	require.NoError(t, err)
+	update, err := stream1.Recv()
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
+		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+		Metadata: metadataExecuting,
+	})
+
+	// The worker should get unblocked.
+	<-wait2
+	require.NoError(t, err1)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1011},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Executing_{
+				Executing: &remoteworker.DesiredState_Executing{
+					DigestFunction:  remoteexecution.DigestFunction_SHA1,
+					ActionDigest:    actionDigest,
+					Action:          action,
+					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1001},
+				},
+			},
+		},
+	}, response1)
+
+	// Let the worker complete the operation. This should wake up
+	// the client.
+	mockClock.EXPECT().Now().Return(time.Unix(1002, 0)).Times(3)
+	initialSizeClassLearner1.EXPECT().Succeeded(time.Duration(0), []uint32{0})
+	timer2.EXPECT().Stop()
+	timer3 := mock.NewMockTimer(ctrl)
+	wait3 := make(chan struct{}, 1)
+	mockClock.EXPECT().NewTimer(time.Minute).DoAndReturn(func(d time.Duration) (clock.Timer, <-chan time.Time) {
+		wait3 <- struct{}{}
+		return timer3, nil
+	})
+	var response2 *remoteworker.SynchronizeResponse
+	var err2 error
+	wait4 := make(chan struct{}, 1)
+	go func() {
+		response2, err2 = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: workerID1,
+			Platform: platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Executing_{
+					Executing: &remoteworker.CurrentState_Executing{
+						ActionDigest: actionDigest,
+						ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+							Completed: &remoteexecution.ExecuteResponse{
+								Result: &remoteexecution.ActionResult{},
+							},
+						},
+					},
+				},
+			},
+		})
+		wait4 <- struct{}{}
+	}()
+	<-wait3
+
+	update, err = stream1.Recv()
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
+		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+		Metadata: metadataCompleted,
+		Done:     true,
+		Result:   &longrunningpb.Operation_Response{Response: executeResponse},
+	})
+	_, err = stream1.Recv()
+	require.Equal(t, io.EOF, err)
+
+	// Even though there are no longer any active or queued
+	// invocations, the scheduler should keep track of the
+	// invocation belonging to the previously completed action,
+	// keeping track of how which workers are associated with it.
+	mockClock.EXPECT().Now().Return(time.Unix(1003, 0)).Times(3)
+	invocationName := &buildqueuestate.InvocationName{
+		SizeClassQueueName: sizeClassQueueName,
+	}
+	invocationStates, err := buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
+		InvocationName: invocationName,
+		Filter:         buildqueuestate.ListInvocationChildrenRequest_ALL,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &buildqueuestate.ListInvocationChildrenResponse{
+		Children: []*buildqueuestate.InvocationChildState{
+			{
+				Id: invocationID1,
+				State: &buildqueuestate.InvocationState{
+					IdleWorkersCount:              1,
+					IdleSynchronizingWorkersCount: 1,
+				},
+			},
+		},
+	}, invocationStates)
+
+	invocationStates, err = buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
+		InvocationName: invocationName,
+		Filter:         buildqueuestate.ListInvocationChildrenRequest_ACTIVE,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &buildqueuestate.ListInvocationChildrenResponse{}, invocationStates)
+
+	invocationStates, err = buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
+		InvocationName: invocationName,
+		Filter:         buildqueuestate.ListInvocationChildrenRequest_QUEUED,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &buildqueuestate.ListInvocationChildrenResponse{}, invocationStates)
+
+	// Create a second worker that issues a blocking Synchronize()
+	// call against the scheduler.
+	mockClock.EXPECT().Now().Return(time.Unix(1004, 0))
+	timer4 := mock.NewMockTimer(ctrl)
+	wait5 := make(chan struct{}, 1)
+	mockClock.EXPECT().NewTimer(time.Minute).DoAndReturn(func(d time.Duration) (clock.Timer, <-chan time.Time) {
+		wait5 <- struct{}{}
+		return timer4, nil
+	})
+	var response3 *remoteworker.SynchronizeResponse
+	var err3 error
+	wait6 := make(chan struct{}, 1)
+	go func() {
+		response3, err3 = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: workerID2,
+			Platform: platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Idle{
+					Idle: &emptypb.Empty{},
+				},
+			},
+		})
+		wait6 <- struct{}{}
+	}()
+	<-wait5
+
+	// Schedule another operation. Because this operation uses a
+	// different invocation ID, it must be scheduled on the second
+	// worker. We want to keep the first worker available for
+	// actions for the same invocation ID.
+	initialSizeClassSelector2 := mock.NewMockSelector(ctrl)
+	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).Return(
+		platform.MustNewKey("", platformForTesting),
+		[]invocation.Key{invocation.MustNewKey(invocationID2)},
+		initialSizeClassSelector2,
+		nil,
+	)
+	initialSizeClassLearner2 := mock.NewMockLearner(ctrl)
+	initialSizeClassSelector2.EXPECT().Select([]uint32{0}).
+		Return(0, 3*time.Minute, 7*time.Minute, initialSizeClassLearner2)
+	mockClock.EXPECT().Now().Return(time.Unix(1005, 0)).Times(2)
+	timer5 := mock.NewMockTimer(ctrl)
+	mockClock.EXPECT().NewTimer(time.Minute).Return(timer5, nil)
+	uuidGenerator.EXPECT().Call().Return(uuid.Parse("e98bb734-0ec7-4cc5-bb98-bb3d5c0788c2"))
+	timer4.EXPECT().Stop()
+
+	stream2, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+		ActionDigest: actionDigest,
+	})
+	require.NoError(t, err)
+	update, err = stream2.Recv()
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
+		Name:     "e98bb734-0ec7-4cc5-bb98-bb3d5c0788c2",
+		Metadata: metadataExecuting,
+	})
+
+	<-wait6
+	require.NoError(t, err3)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1015},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Executing_{
+				Executing: &remoteworker.DesiredState_Executing{
+					DigestFunction:  remoteexecution.DigestFunction_SHA1,
+					ActionDigest:    actionDigest,
+					Action:          action,
+					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1005},
+				},
+			},
+		},
+	}, response3)
+
+	// Let the second worker complete the operation.
+	mockClock.EXPECT().Now().Return(time.Unix(1006, 0)).Times(3)
+	initialSizeClassLearner2.EXPECT().Succeeded(time.Duration(0), []uint32{0})
+	timer5.EXPECT().Stop()
+	timer6 := mock.NewMockTimer(ctrl)
+	wait7 := make(chan struct{}, 1)
+	mockClock.EXPECT().NewTimer(time.Minute).DoAndReturn(func(d time.Duration) (clock.Timer, <-chan time.Time) {
+		wait7 <- struct{}{}
+		return timer6, nil
+	})
+	go func() {
+		buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: workerID2,
+			Platform: platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Executing_{
+					Executing: &remoteworker.CurrentState_Executing{
+						ActionDigest: actionDigest,
+						ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+							Completed: &remoteexecution.ExecuteResponse{
+								Result: &remoteexecution.ActionResult{},
+							},
+						},
+					},
+				},
+			},
+		})
+	}()
+	<-wait7
+
+	update, err = stream2.Recv()
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
+		Name:     "e98bb734-0ec7-4cc5-bb98-bb3d5c0788c2",
+		Metadata: metadataCompleted,
+		Done:     true,
+		Result:   &longrunningpb.Operation_Response{Response: executeResponse},
+	})
+	_, err = stream1.Recv()
+	require.Equal(t, io.EOF, err)
+
+	// Both invocations should now have one worker.
+	mockClock.EXPECT().Now().Return(time.Unix(1007, 0))
+	invocationStates, err = buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
+		InvocationName: invocationName,
+		Filter:         buildqueuestate.ListInvocationChildrenRequest_ALL,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &buildqueuestate.ListInvocationChildrenResponse{
+		Children: []*buildqueuestate.InvocationChildState{
+			{
+				Id: invocationID1,
+				State: &buildqueuestate.InvocationState{
+					IdleWorkersCount:              1,
+					IdleSynchronizingWorkersCount: 1,
+				},
+			},
+			{
+				Id: invocationID2,
+				State: &buildqueuestate.InvocationState{
+					IdleWorkersCount:              1,
+					IdleSynchronizingWorkersCount: 1,
+				},
+			},
+		},
+	}, invocationStates)
+
+	// When submitting a third operation that uses an unknown
+	// invocation, we should execute it on the first worker, as that
+	// worker is associated with an invocation that is least
+	// recently seen.
+	initialSizeClassSelector3 := mock.NewMockSelector(ctrl)
+	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).Return(
+		platform.MustNewKey("", platformForTesting),
+		[]invocation.Key{invocation.MustNewKey(invocationID3)},
+		initialSizeClassSelector3,
+		nil,
+	)
+	initialSizeClassLearner3 := mock.NewMockLearner(ctrl)
+	initialSizeClassSelector3.EXPECT().Select([]uint32{0}).
+		Return(0, 3*time.Minute, 7*time.Minute, initialSizeClassLearner3)
+	mockClock.EXPECT().Now().Return(time.Unix(1008, 0)).Times(2)
+	timer7 := mock.NewMockTimer(ctrl)
+	mockClock.EXPECT().NewTimer(time.Minute).Return(timer7, nil)
+	uuidGenerator.EXPECT().Call().Return(uuid.Parse("a0942b25-9c84-42da-93cb-cbd16cf61917"))
+	timer3.EXPECT().Stop()
+
+	stream3, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+		ActionDigest: actionDigest,
+	})
+	require.NoError(t, err)
+	update, err = stream3.Recv()
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
+		Name:     "a0942b25-9c84-42da-93cb-cbd16cf61917",
+		Metadata: metadataExecuting,
+	})
+
+	<-wait4
+	require.NoError(t, err2)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1018},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Executing_{
+				Executing: &remoteworker.DesiredState_Executing{
+					DigestFunction:  remoteexecution.DigestFunction_SHA1,
+					ActionDigest:    actionDigest,
+					Action:          action,
+					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1008},
+				},
+			},
+		},
+	}, response2)
+}
+
+func TestInMemoryBuildQueueWorkerInputRootAffinity(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	mockClock := mock.NewMockClock(ctrl)
+	mockClock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.ExecutionUpdateInterval = time.Hour
+	buildQueueConfiguration.WorkerInputRootAffinityCount = 2
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, mockClock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	// Timers created by Execute() are not relevant to this test.
+	executionUpdateTimer := mock.NewMockTimer(ctrl)
+	executionUpdateTimer.EXPECT().Stop().Return(true).AnyTimes()
+	mockClock.EXPECT().NewTimer(time.Hour).Return(executionUpdateTimer, nil).AnyTimes()
+
+	// Create three actions. The first two have different input
+	// roots, while the third has the same input root as the second.
+	inputRootDigest1 := &remoteexecution.Digest{
+		Hash:      "09e9d1b3b7a6b9c4a5e2b7a5d1b3d2b1",
+		SizeBytes: 100,
+	}
+	inputRootDigest2 := &remoteexecution.Digest{
+		Hash:      "1b2b0c4c3ef4e86e1d8c6b1c9c5b4a1a",
+		SizeBytes: 200,
+	}
+	actionParameters := [...]struct {
+		actionHash       string
+		inputRootDigest  *remoteexecution.Digest
+		expectedDuration time.Duration
+		operationName    string
+	}{
+		{"8aa28fe4a1b0b1d2ac7b3f6c50fa6e10", inputRootDigest1, 2 * time.Minute, "bf3b1e35-8cd8-4a65-9a5b-9e0e3bf3b6f2"},
+		{"2a4f5e6c0d3b4a1e9f8c7b6a5d4e3f2a", inputRootDigest2, time.Minute, "5c0e37c1-1e5b-4fd7-a3c4-0d4dc1a0a8b5"},
+		{"6d1b0b2b3c4d5e6f7a8b9c0d1e2f3a4b", inputRootDigest2, time.Minute, "e0b4c7a9-6d1b-4a52-8b8e-5f2a4c0d9e13"},
+	}
+	actions := make([]*remoteexecution.Action, 0, len(actionParameters))
+	for _, p := range actionParameters {
+		action := &remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "61c585c297d00409bd477b6b80759c94",
+				SizeBytes: 456,
+			},
+			InputRootDigest: p.inputRootDigest,
+			DoNotCache:      true,
+		}
+		actions = append(actions, action)
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, p.actionHash, 123),
+		).Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided)).AnyTimes()
+
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).Return(
+			platform.MustNewKey("", platformForTesting),
+			nil,
+			initialSizeClassSelector,
+			nil,
+		)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, p.expectedDuration, 30*time.Minute, initialSizeClassLearner)
+		initialSizeClassLearner.EXPECT().Succeeded(time.Duration(0), []uint32{0}).AnyTimes()
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(p.operationName))
+	}
+	execute := func(i int) remoteexecution.Execution_ExecuteClient {
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      actionParameters[i].actionHash,
+				SizeBytes: 123,
+			},
+		})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.NoError(t, err)
+		return stream
+	}
+	getExecutingSynchronizeResponse := func(i int) *remoteworker.SynchronizeResponse {
+		action := proto.Clone(actions[i]).(*remoteexecution.Action)
+		action.Timeout = &durationpb.Duration{Seconds: 1800}
+		return &remoteworker.SynchronizeResponse{
+			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1010},
+			DesiredState: &remoteworker.DesiredState{
+				WorkerState: &remoteworker.DesiredState_Executing_{
+					Executing: &remoteworker.DesiredState_Executing{
+						DigestFunction: remoteexecution.DigestFunction_MD5,
+						ActionDigest: &remoteexecution.Digest{
+							Hash:      actionParameters[i].actionHash,
+							SizeBytes: 123,
+						},
+						Action:          action,
+						QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1000},
+					},
+				},
+			},
+		}
+	}
+	workerIDs := []map[string]string{
+		{"hostname": "worker123", "thread": "1"},
+		{"hostname": "worker123", "thread": "2"},
+	}
+
+	// Announce two workers, which creates a queue for operations.
+	for _, workerID := range workerIDs {
+		response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: workerID,
+			Platform: platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Executing_{
+					Executing: &remoteworker.CurrentState_Executing{
+						ActionDigest: &remoteexecution.Digest{
+							Hash:      "099a3f6dc1e8e91dbcca4ea964cd2237",
+							SizeBytes: 123,
+						},
+						ExecutionState: &remoteworker.CurrentState_Executing_FetchingInputs{
+							FetchingInputs: &emptypb.Empty{},
+						},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1000},
+			DesiredState: &remoteworker.DesiredState{
+				WorkerState: &remoteworker.DesiredState_Idle{
+					Idle: &emptypb.Empty{},
+				},
+			},
+		}, response)
+	}
+
+	// Enqueue the first two actions, and let each of the workers
+	// pick up one of them.
+	execute(0)
+	execute(1)
+	for i, workerID := range workerIDs {
+		response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: workerID,
+			Platform: platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Idle{
+					Idle: &emptypb.Empty{},
+				},
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, getExecutingSynchronizeResponse(i), response)
+	}
+
+	// Let both workers complete their actions. As no further
+	// actions are queued, both of them should block.
+	ctx1, cancel1 := context.WithCancel(ctx)
+	timers := make([]*mock.MockTimer, 0, len(workerIDs))
+	responses := make([]*remoteworker.SynchronizeResponse, len(workerIDs))
+	errs := make([]error, len(workerIDs))
+	waits := make([]chan struct{}, 0, len(workerIDs))
+	for i, workerID := range workerIDs {
+		timer := mock.NewMockTimer(ctrl)
+		timers = append(timers, timer)
+		blocked := make(chan struct{}, 1)
+		mockClock.EXPECT().NewTimer(time.Minute).DoAndReturn(func(d time.Duration) (clock.Timer, <-chan time.Time) {
+			blocked <- struct{}{}
+			return timer, nil
+		})
+		wait := make(chan struct{}, 1)
+		waits = append(waits, wait)
+		synchronizeCtx := ctx
+		if i == 0 {
+			synchronizeCtx = ctx1
+		}
+		go func(i int, workerID map[string]string) {
+			responses[i], errs[i] = buildQueue.Synchronize(synchronizeCtx, &remoteworker.SynchronizeRequest{
+				WorkerId: workerID,
+				Platform: platformForTesting,
+				CurrentState: &remoteworker.CurrentState{
+					WorkerState: &remoteworker.CurrentState_Executing_{
+						Executing: &remoteworker.CurrentState_Executing{
+							ActionDigest: &remoteexecution.Digest{
+								Hash:      actionParameters[i].actionHash,
+								SizeBytes: 123,
+							},
+							ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+								Completed: &remoteexecution.ExecuteResponse{
+									Result: &remoteexecution.ActionResult{},
+								},
+							},
+						},
+					},
+				},
+			})
+			wait <- struct{}{}
+		}(i, workerID)
+		<-blocked
+	}
+
+	// Enqueue the third action. Even though the first worker has
+	// been idle for the longest amount of time, the second worker
+	// should be woken up, as it recently executed an action with
+	// the same input root.
+	timers[1].EXPECT().Stop()
+	execute(2)
+	<-waits[1]
+	require.NoError(t, errs[1])
+	testutil.RequireEqualProto(t, getExecutingSynchronizeResponse(2), responses[1])
+
+	// Interrupt the Synchronize() call of the first worker.
+	timers[0].EXPECT().Stop()
+	cancel1()
+	<-waits[0]
+	testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "context canceled"), errs[0])
+}
+
+func TestInMemoryBuildQueueWorkerInvocationStickinessLimit(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	// Register a platform queue that has a small amount of worker
+	// invocation stickiness. This should workers to prefer picking
+	// up operations belonging to the same invocation as the last
+	// executed task, if the difference in queueing time is small.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
+		digest.EmptyInstanceName,
+		platformForTesting,
+		/* workerInvocationStickinessLimits = */ []time.Duration{3 * time.Second},
+		/* maximumQueuedBackgroundLearningOperations = */ 10,
+		/* backgroundLearningOperationPriority = */ 100,
+		/* maximumSizeClass = */ 0,
+		/* maximumQueuedOperations = */ 0,
+		/* preemptionPolicy = */ nil,
+		/* workerResourceRequirements = */ nil,
+		/* sizeClassEscalationPolicy = */ nil))
+
+	operationParameters := []struct {
+		operationName    string
+		toolInvocationID string
+	}{
+		{"716f2e32-d273-49e7-a842-82282e89d1a3", "76d8f589-8d22-4541-a612-39e4c670e531"},
+		{"0a656823-9f91-4220-9dcd-58503e62e6e8", "76d8f589-8d22-4541-a612-39e4c670e531"},
+		{"175ead9a-a095-43a5-9f2c-e3a293938ff3", "76d8f589-8d22-4541-a612-39e4c670e531"},
+		{"d354a1ea-0945-4c84-be17-ac01b8058d06", "0175ce91-1363-4cc9-8eb9-e67e82f9fdbb"},
+		{"5fe458ce-a28d-4478-9ced-7ee23a539cb6", "0175ce91-1363-4cc9-8eb9-e67e82f9fdbb"},
+		{"30e6d53c-a737-47bc-8b06-8e8f9cf7a3b3", "0175ce91-1363-4cc9-8eb9-e67e82f9fdbb"},
+		{"77d52264-940c-461b-b67f-e3e68cb5b7f0", "8414fe9e-67a9-4b0b-854a-e7f8f56cca50"},
+		{"201d6ff3-ffe3-4126-928b-cba38938ebb5", "8414fe9e-67a9-4b0b-854a-e7f8f56cca50"},
+		{"303fa653-8516-4a4f-9b6b-60c3f1f45f29", "8414fe9e-67a9-4b0b-854a-e7f8f56cca50"},
+	}
+	type streamHandle struct {
+		timer                   *mock.MockTimer
+		stream                  remoteexecution.Execution_ExecuteClient
+		initialSizeClassLearner *mock.MockLearner
+	}
+	var streamHandles []streamHandle
+
+	// Schedule some actions belonging to three different invocation IDs.
+	for i, p := range operationParameters {
+		action := &remoteexecution.Action{
+			DoNotCache: true,
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "9b818e201c59f31954cb1e126cc67562ec545ab4",
+				SizeBytes: 456,
+			},
+		}
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("", remoteexecution.DigestFunction_SHA1, "0474d2f48968a56da4de20718d8ac23aafd80709", 123),
+		).Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided))
+		requestMetadata := &remoteexecution.RequestMetadata{
+			ToolInvocationId: p.toolInvocationID,
+		}
+		requestMetadataAny, err := anypb.New(requestMetadata)
+		require.NoError(t, err)
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).Return(
+			platform.MustNewKey("", platformForTesting),
+			[]invocation.Key{invocation.MustNewKey(requestMetadataAny)},
+			initialSizeClassSelector,
+			nil,
+		)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, 30*time.Second, time.Minute, initialSizeClassLearner)
+		clock.EXPECT().Now().Return(time.Unix(1010+int64(i), 0))
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(p.operationName))
+
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "0474d2f48968a56da4de20718d8ac23aafd80709",
+				SizeBytes: 123,
+			},
+		})
+		require.NoError(t, err)
+		update, err := stream.Recv()
+		require.NoError(t, err)
+		metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+			Stage: remoteexecution.ExecutionStage_QUEUED,
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "0474d2f48968a56da4de20718d8ac23aafd80709",
+				SizeBytes: 123,
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
+			Name:     p.operationName,
+			Metadata: metadata,
+		})
+
+		streamHandles = append(streamHandles, streamHandle{
+			timer:                   timer,
+			stream:                  stream,
+			initialSizeClassLearner: initialSizeClassLearner,
+		})
+	}
+
+	// Let a worker run the actions sequentially. The order in which
+	// execution takes place differs from the queueing order,
+	// because of stickiness. Every invocation is permitted to run
+	// for at least three seconds before switching to tasks
+	// belonging to another invocation.
+	for i, operationIndex := range []int{0, 1, 3, 4, 6, 7, 2, 5, 8} {
+		// Starting execution should cause the client to receive
+		// an EXECUTING message.
+		clock.EXPECT().Now().Return(time.Unix(1030+int64(i)*2, 0)).Times(2)
+		streamHandles[operationIndex].timer.EXPECT().Stop()
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: map[string]string{
+				"hostname": "worker123",
+				"thread":   "42",
+			},
+			Platform: platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Idle{
+					Idle: &emptypb.Empty{},
+				},
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1040 + int64(i)*2},
+			DesiredState: &remoteworker.DesiredState{
+				WorkerState: &remoteworker.DesiredState_Executing_{
+					Executing: &remoteworker.DesiredState_Executing{
+						DigestFunction: remoteexecution.DigestFunction_SHA1,
+						ActionDigest: &remoteexecution.Digest{
+							Hash:      "0474d2f48968a56da4de20718d8ac23aafd80709",
+							SizeBytes: 123,
+						},
+						Action: &remoteexecution.Action{
+							CommandDigest: &remoteexecution.Digest{
+								Hash:      "9b818e201c59f31954cb1e126cc67562ec545ab4",
+								SizeBytes: 456,
+							},
+							DoNotCache: true,
+							Timeout:    &durationpb.Duration{Seconds: 60},
+						},
+						QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1010 + int64(operationIndex)},
+					},
+				},
+			},
+		}, response)
+
+		stream := streamHandles[operationIndex].stream
+		update, err := stream.Recv()
+		require.NoError(t, err)
+		metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+			Stage: remoteexecution.ExecutionStage_EXECUTING,
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "0474d2f48968a56da4de20718d8ac23aafd80709",
+				SizeBytes: 123,
+			},
+		})
+		require.NoError(t, err)
+		operationName := operationParameters[operationIndex].operationName
+		testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
+			Name:     operationName,
+			Metadata: metadata,
+		})
+
+		// Finishing execution should cause the client to
+		// receive a COMPLETED message.
+		streamHandles[operationIndex].initialSizeClassLearner.EXPECT().Succeeded(time.Duration(0), []uint32{0})
+		clock.EXPECT().Now().Return(time.Unix(1021+int64(i)*2, 0)).Times(3)
+		timer.EXPECT().Stop()
+		response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: map[string]string{
+				"hostname": "worker123",
+				"thread":   "42",
+			},
+			Platform: platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Executing_{
+					Executing: &remoteworker.CurrentState_Executing{
+						ActionDigest: &remoteexecution.Digest{
+							Hash:      "0474d2f48968a56da4de20718d8ac23aafd80709",
+							SizeBytes: 123,
+						},
+						ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+							Completed: &remoteexecution.ExecuteResponse{
+								Result: &remoteexecution.ActionResult{},
+							},
+						},
+					},
+				},
+			},
+			PreferBeingIdle: true,
+		})
+
+		update, err = stream.Recv()
+		require.NoError(t, err)
+		metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
+			Stage: remoteexecution.ExecutionStage_COMPLETED,
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "0474d2f48968a56da4de20718d8ac23aafd80709",
+				SizeBytes: 123,
+			},
+		})
+		require.NoError(t, err)
+		executeResponse, err := anypb.New(&remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
+			Name:     operationName,
+			Metadata: metadata,
+			Done:     true,
+			Result:   &longrunningpb.Operation_Response{Response: executeResponse},
+		})
+	}
+}
+
+func TestInMemoryBuildQueueAuthorization(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0)).AnyTimes()
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	authorizer := mock.NewMockAuthorizer(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, authorizer, authorizer, authorizer)
+	beepboop := digest.MustNewInstanceName("beepboop")
+
+	t.Run("GetCapabilities-NotAuthorized", func(t *testing.T) {
+		authorizer.EXPECT().Authorize(gomock.Any(), []digest.InstanceName{beepboop}).Return([]error{status.Error(codes.PermissionDenied, "You shall not pass")})
+		_, err := buildQueue.GetCapabilities(ctx, beepboop)
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Authorization: You shall not pass"), err)
+	})
+
+	t.Run("GetCapabilities-Error", func(t *testing.T) {
+		authorizer.EXPECT().Authorize(gomock.Any(), []digest.InstanceName{beepboop}).Return([]error{status.Error(codes.Internal, "I fell over")})
+		_, err := buildQueue.GetCapabilities(ctx, beepboop)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Authorization: I fell over"), err)
+	})
+
+	t.Run("Execute-NotAuthorized", func(t *testing.T) {
+		executionClient := getExecutionClient(t, buildQueue)
+		authorizer.EXPECT().Authorize(gomock.Any(), []digest.InstanceName{beepboop}).Return([]error{status.Error(codes.PermissionDenied, "You shall not pass")})
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+				SizeBytes: 456,
+			},
+			InstanceName: "beepboop",
+		})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Authorization: You shall not pass"), err)
+	})
+
+	t.Run("WaitExecution", func(t *testing.T) {
+		buildQueue.RegisterPredeclaredPlatformQueue(
+			digest.MustNewInstanceName(""),
+			&remoteexecution.Platform{},
+			/* workerInvocationStickinessLimits = */ nil,
+			/* maximumQueuedBackgroundLearningOperations = */ 0,
+			/* backgroundLearningOperationPriority = */ 0,
+			/* maximumSizeClass = */ 0,
+			/* maximumQueuedOperations = */ 0,
+			/* preemptionPolicy = */ nil,
+			/* workerResourceRequirements = */ nil,
+			/* sizeClassEscalationPolicy = */ nil)
+
+		// Allow the Execute
+		authorizer.EXPECT().Authorize(gomock.Any(), []digest.InstanceName{beepboop}).Return([]error{nil})
+
+		action := &remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+				SizeBytes: 123,
+			},
+			Platform: &remoteexecution.Platform{},
+		}
+
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("beepboop", remoteexecution.DigestFunction_SHA1, "61c585c297d00409bd477b6b80759c94ec545ab4", 456),
+		).Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided))
+
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).
+			Return(platform.MustNewKey("beepboop", &remoteexecution.Platform{}), nil, initialSizeClassSelector, nil)
+
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
+
+		timer1 := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer1, nil)
+
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse("36ebab65-3c4f-4faf-818b-2eabb4cd1b02"))
+
+		executionClient := getExecutionClient(t, buildQueue)
+
+		// Error on the WaitExecution
+		authorizer.EXPECT().Authorize(gomock.Any(), []digest.InstanceName{beepboop}).Return([]error{status.Error(codes.PermissionDenied, "You shall not pass")})
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+				SizeBytes: 456,
+			},
+			InstanceName: "beepboop",
+		})
+		require.NoError(t, err)
+		update, err := stream.Recv()
+		require.NoError(t, err)
+		metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+			Stage: remoteexecution.ExecutionStage_QUEUED,
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+				SizeBytes: 456,
+			},
+		})
+		testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
+			Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+			Metadata: metadata,
+		})
+
+		stream2, err := executionClient.WaitExecution(ctx, &remoteexecution.WaitExecutionRequest{
+			Name: "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+		})
+		require.NoError(t, err)
+		_, err = stream2.Recv()
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Authorization: You shall not pass"), err)
+	})
+}
+
+func TestInMemoryBuildQueueNestedInvocationsSynchronization(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	mockClock := mock.NewMockClock(ctrl)
+	mockClock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, mockClock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	mockClock.EXPECT().Now().Return(time.Unix(1000, 0))
+	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
+		digest.EmptyInstanceName,
+		platformForTesting,
+		/* workerInvocationStickinessLimits = */ nil,
+		/* maximumQueuedBackgroundLearningOperations = */ 0,
+		/* backgroundLearningOperationPriority = */ 0,
+		/* maximumSizeClass = */ 0,
+		/* maximumQueuedOperations = */ 0,
+		/* preemptionPolicy = */ nil,
+		/* workerResourceRequirements = */ nil,
+		/* sizeClassEscalationPolicy = */ nil))
+
+	// Create ten workers. Let all of them complete a task that
+	// belonged to the same correlated invocations ID, but a
+	// different tool invocation ID.
+	correlatedInvocationsID := &remoteexecution.RequestMetadata{
+		CorrelatedInvocationsId: "7e5d4921-a6d2-480e-8e2d-a4afecd7ab33",
+	}
+	correlatedInvocationsIDAny, err := anypb.New(correlatedInvocationsID)
+	require.NoError(t, err)
+	for i, p := range []struct {
+		operationName    string
+		toolInvocationID string
+	}{
+		{"716f2e32-d273-49e7-a842-82282e89d1a3", "93b8f304-4a3d-4832-91b2-e596e8a08f42"},
+		{"0a656823-9f91-4220-9dcd-58503e62e6e8", "e3658358-6325-4405-a43c-b3f81ca6e52a"},
+		{"175ead9a-a095-43a5-9f2c-e3a293938ff3", "a76ecbfc-4a3a-4822-99c5-30c0ff7dbbb8"},
+		{"d354a1ea-0945-4c84-be17-ac01b8058d06", "167fda12-0c84-4936-978f-2b54ebe3b600"},
+		{"5fe458ce-a28d-4478-9ced-7ee23a539cb6", "02c0d7d2-2e0a-4664-8030-60ad5e9026bd"},
+		{"30e6d53c-a737-47bc-8b06-8e8f9cf7a3b3", "892d2891-8344-4cb0-88b6-852106b2b329"},
+		{"77d52264-940c-461b-b67f-e3e68cb5b7f0", "1ccd7a67-0b55-4242-a4a1-942064a79341"},
+		{"201d6ff3-ffe3-4126-928b-cba38938ebb5", "cbb6455f-ede1-4314-ba63-a44f18188672"},
+		{"303fa653-8516-4a4f-9b6b-60c3f1f45f29", "3c5c6475-5ce6-42ef-b8ba-aa0a67348174"},
+		{"ca172285-ba23-42fd-a714-b582cad3bf32", "fd345278-02e8-48d8-8ff6-4f467c0cd942"},
+	} {
+		action := &remoteexecution.Action{
+			DoNotCache: true,
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "9b818e201c59f31954cb1e126cc67562ec545ab4",
+				SizeBytes: 456,
+			},
+		}
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("", remoteexecution.DigestFunction_SHA1, "0474d2f48968a56da4de20718d8ac23aafd80709", 123),
+		).Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided))
+		toolInvocationID := &remoteexecution.RequestMetadata{
+			ToolInvocationId: p.toolInvocationID,
+		}
+		toolInvocationIDAny, err := anypb.New(toolInvocationID)
+		require.NoError(t, err)
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).Return(
+			platform.MustNewKey("", platformForTesting),
+			[]invocation.Key{
+				invocation.MustNewKey(correlatedInvocationsIDAny),
+				invocation.MustNewKey(toolInvocationIDAny),
+			},
+			initialSizeClassSelector,
+			nil,
+		)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, 30*time.Second, time.Minute, initialSizeClassLearner)
+		mockClock.EXPECT().Now().Return(time.Unix(1010+int64(i), 0))
+		timer1 := mock.NewMockTimer(ctrl)
+		mockClock.EXPECT().NewTimer(time.Minute).Return(timer1, nil)
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(p.operationName))
+
+		actionDigest := &remoteexecution.Digest{
+			Hash:      "0474d2f48968a56da4de20718d8ac23aafd80709",
+			SizeBytes: 123,
+		}
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			ActionDigest: actionDigest,
+		})
+		require.NoError(t, err)
+		update, err := stream.Recv()
+		require.NoError(t, err)
+		metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+			Stage:        remoteexecution.ExecutionStage_QUEUED,
+			ActionDigest: actionDigest,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &longrunningpb.Operation{
+			Name:     p.operationName,
+			Metadata: metadata,
+		}, update)
+
+		mockClock.EXPECT().Now().Return(time.Unix(1010+int64(i), 1)).Times(2)
+		timer1.EXPECT().Stop()
+		timer2 := mock.NewMockTimer(ctrl)
+		mockClock.EXPECT().NewTimer(time.Minute).Return(timer2, nil)
+		workerID := map[string]string{
+			"hostname": "worker123",
+			"thread":   strconv.FormatInt(int64(i), 10),
+		}
+		response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: workerID,
+			Platform: platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Idle{
+					Idle: &emptypb.Empty{},
+				},
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1020 + int64(i), Nanos: 1},
+			DesiredState: &remoteworker.DesiredState{
+				WorkerState: &remoteworker.DesiredState_Executing_{
+					Executing: &remoteworker.DesiredState_Executing{
+						DigestFunction: remoteexecution.DigestFunction_SHA1,
+						ActionDigest:   actionDigest,
+						Action: &remoteexecution.Action{
+							DoNotCache: true,
+							CommandDigest: &remoteexecution.Digest{
+								Hash:      "9b818e201c59f31954cb1e126cc67562ec545ab4",
+								SizeBytes: 456,
+							},
+							Timeout: &durationpb.Duration{Seconds: 60},
+						},
+						QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1010 + int64(i)},
+					},
+				},
+			},
+		}, response)
+
+		update, err = stream.Recv()
+		require.NoError(t, err)
+		metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
+			Stage:        remoteexecution.ExecutionStage_EXECUTING,
+			ActionDigest: actionDigest,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
+			Name:     p.operationName,
+			Metadata: metadata,
+		})
+
+		initialSizeClassLearner.EXPECT().Succeeded(time.Duration(0), []uint32{0})
+		mockClock.EXPECT().Now().Return(time.Unix(1010+int64(i), 2)).Times(3)
+		timer2.EXPECT().Stop()
+		response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: workerID,
+			Platform: platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Executing_{
+					Executing: &remoteworker.CurrentState_Executing{
+						ActionDigest: actionDigest,
+						ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+							Completed: &remoteexecution.ExecuteResponse{
+								Result: &remoteexecution.ActionResult{},
+							},
+						},
+					},
+				},
+			},
+			PreferBeingIdle: true,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1010 + int64(i), Nanos: 2},
+			DesiredState: &remoteworker.DesiredState{
+				WorkerState: &remoteworker.DesiredState_Idle{
+					Idle: &emptypb.Empty{},
+				},
+			},
+		}, response)
+
+		update, err = stream.Recv()
+		require.NoError(t, err)
+		metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
+			Stage:        remoteexecution.ExecutionStage_COMPLETED,
+			ActionDigest: actionDigest,
+		})
+		require.NoError(t, err)
+		executeResponse, err := anypb.New(&remoteexecution.ExecuteResponse{
+			Result: &remoteexecution.ActionResult{},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &longrunningpb.Operation{
+			Name:     p.operationName,
+			Metadata: metadata,
+			Done:     true,
+			Result:   &longrunningpb.Operation_Response{Response: executeResponse},
+		}, update)
+	}
+
+	// At the top level, we should see an invocation with ten children.
+	invocationName := &buildqueuestate.InvocationName{
+		SizeClassQueueName: &buildqueuestate.SizeClassQueueName{
+			PlatformQueueName: &buildqueuestate.PlatformQueueName{
+				Platform: platformForTesting,
+			},
+		},
+	}
+	mockClock.EXPECT().Now().Return(time.Unix(1030, 0))
+	invocationStates, err := buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
+		InvocationName: invocationName,
+		Filter:         buildqueuestate.ListInvocationChildrenRequest_ALL,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &buildqueuestate.ListInvocationChildrenResponse{
+		Children: []*buildqueuestate.InvocationChildState{
+			{
+				Id: correlatedInvocationsIDAny,
+				State: &buildqueuestate.InvocationState{
+					IdleWorkersCount: 10,
+					ChildrenCount:    10,
+				},
+			},
+		},
+	}, invocationStates)
+
+	// Let all ten workers perform a blocking Synchronize() call.
+	allWorkersWait := make(chan struct{})
+	timers := make([]*mock.MockTimer, 0, 10)
+	wakeups := make([]chan<- time.Time, 0, 10)
+	for iIter := 0; iIter < 10; iIter++ {
+		i := iIter
+		mockClock.EXPECT().Now().Return(time.Unix(1040+int64(i), 0))
+		timer := mock.NewMockTimer(ctrl)
+		wakeup := make(chan time.Time, 1)
+		timerCreationWait := make(chan struct{})
+		mockClock.EXPECT().NewTimer(time.Minute).DoAndReturn(func(d time.Duration) (clock.Timer, <-chan time.Time) {
+			timerCreationWait <- struct{}{}
+			return timer, wakeup
+		})
+
+		go func() {
+			response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+				WorkerId: map[string]string{
+					"hostname": "worker123",
+					"thread":   strconv.FormatInt(int64(i), 10),
+				},
+				Platform: platformForTesting,
+				CurrentState: &remoteworker.CurrentState{
+					WorkerState: &remoteworker.CurrentState_Idle{
+						Idle: &emptypb.Empty{},
+					},
+				},
+			})
+			require.NoError(t, err)
+			testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+				NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1100 + int64(i)},
+				DesiredState: &remoteworker.DesiredState{
+					WorkerState: &remoteworker.DesiredState_Idle{
+						Idle: &emptypb.Empty{},
+					},
+				},
+			}, response)
+
+			allWorkersWait <- struct{}{}
+		}()
+
+		<-timerCreationWait
+		timers = append(timers, timer)
+		wakeups = append(wakeups, wakeup)
+	}
+
+	// Wake up all ten workers without receiving any new work. This
+	// should not cause any crashes.
+	for i := 0; i < 10; i++ {
+		timers[i].EXPECT().Stop()
+		wakeups[i] <- time.Unix(1100+int64(i), 0)
+		<-allWorkersWait
+	}
+}
+
+func TestInMemoryBuildQueueTentativeAssignment(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	timer := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer, nil).AnyTimes()
+	timer.EXPECT().Stop().Return(true).AnyTimes()
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	// Announce a new worker, which creates a queue for operations.
+	workerID := map[string]string{
+		"hostname": "worker123",
+		"thread":   "42",
+	}
+	response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+		PreferBeingIdle:            true,
+		AcceptTentativeAssignments: true,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1000},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, response)
+
+	// Let a client enqueue two operations.
+	enqueue := func(actionHash, commandHash, operationName string) {
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, actionHash, 123),
+		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      commandHash,
+				SizeBytes: 456,
+			},
+		}, buffer.UserProvided))
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, &remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      commandHash,
+				SizeBytes: 456,
+			},
+		}), nil).Return(platform.MustNewKey("main", platformForTesting), nil, initialSizeClassSelector, nil)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(operationName))
+		stream, err := executionClient.Execute(
+			ctx,
+			&remoteexecution.ExecuteRequest{
+				InstanceName: "main",
+				ActionDigest: &remoteexecution.Digest{
+					Hash:      actionHash,
+					SizeBytes: 123,
+				},
+			})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.NoError(t, err)
+	}
+	enqueue("da39a3ee5e6b4b0d3255bfef95601890afd80709", "61c585c297d00409bd477b6b80759c94ec545ab4", "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7")
+	enqueue("8c7bdf20235417b8e3bfa695407e1ff0b43e8223", "11483c42a98269d01673aa3157836d2882aad5de", "9dd6e6c4-2e1e-4fd7-9a5f-4e0e7e1a3b6c")
+
+	// Let the worker pick up the first operation.
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+		AcceptTentativeAssignments: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "da39a3ee5e6b4b0d3255bfef95601890afd80709", response.DesiredState.GetExecuting().ActionDigest.Hash)
+	require.Nil(t, response.TentativeAssignment)
+
+	// While executing, the worker should be informed of the
+	// operation that is likely to be assigned to it next.
+	currentStateExecuting := &remoteworker.CurrentState{
+		WorkerState: &remoteworker.CurrentState_Executing_{
+			Executing: &remoteworker.CurrentState_Executing{
+				ActionDigest: &remoteexecution.Digest{
+					Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+					SizeBytes: 123,
+				},
+				ExecutionState: &remoteworker.CurrentState_Executing_Running{
+					Running: &emptypb.Empty{},
+				},
+			},
+		},
+	}
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId:                   workerID,
+		InstanceNamePrefix:         "main",
+		Platform:                   platformForTesting,
+		CurrentState:               currentStateExecuting,
+		AcceptTentativeAssignments: true,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1010},
+		TentativeAssignment: &remoteworker.DesiredState_Executing{
+			DigestFunction: remoteexecution.DigestFunction_SHA1,
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "8c7bdf20235417b8e3bfa695407e1ff0b43e8223",
+				SizeBytes: 123,
+			},
+			Action: &remoteexecution.Action{
+				CommandDigest: &remoteexecution.Digest{
+					Hash:      "11483c42a98269d01673aa3157836d2882aad5de",
+					SizeBytes: 456,
+				},
+				Timeout: &durationpb.Duration{Seconds: 1800},
+			},
+			QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1000},
+		},
+	}, response)
+
+	// Workers that don't accept tentative assignments, or that
+	// prefer becoming idle, should not receive them.
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState:       currentStateExecuting,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1010},
+	}, response)
+
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId:                   workerID,
+		InstanceNamePrefix:         "main",
+		Platform:                   platformForTesting,
+		CurrentState:               currentStateExecuting,
+		PreferBeingIdle:            true,
+		AcceptTentativeAssignments: true,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1010},
+	}, response)
+}
+
+func TestInMemoryBuildQueueVCSMetadata(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	timer := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer, nil).AnyTimes()
+	timer.EXPECT().Stop().Return(true).AnyTimes()
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	action := &remoteexecution.Action{
+		CommandDigest: &remoteexecution.Digest{
+			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+			SizeBytes: 456,
+		},
+	}
+	executeRequest := &remoteexecution.ExecuteRequest{
+		InstanceName: "main",
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	}
+	actionDigest := digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123)
+
+	t.Run("InvalidDirtyHeader", func(t *testing.T) {
+		contentAddressableStorage.EXPECT().Get(gomock.Any(), actionDigest).
+			Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided))
+
+		stream, err := executionClient.Execute(
+			metadata.AppendToOutgoingContext(
+				ctx,
+				"buildbarn-vcs-commit-sha", "3f786850e387550fdab836ed7e6dc881de23001b",
+				"buildbarn-vcs-dirty", "maybe"),
+			executeRequest)
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid value for header \"buildbarn-vcs-dirty\": \"maybe\""), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Announce a new worker, which creates a queue for
+		// operations.
+		workerID := map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		}
+		synchronizeRequest := &remoteworker.SynchronizeRequest{
+			WorkerId:           workerID,
+			InstanceNamePrefix: "main",
+			Platform:           platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Idle{
+					Idle: &emptypb.Empty{},
+				},
+			},
+			PreferBeingIdle: true,
+		}
+		_, err := buildQueue.Synchronize(ctx, synchronizeRequest)
+		require.NoError(t, err)
+
+		// Let a client enqueue an operation, providing VCS
+		// metadata through request headers.
+		contentAddressableStorage.EXPECT().Get(gomock.Any(), actionDigest).
+			Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided))
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).
+			Return(platform.MustNewKey("main", platformForTesting), nil, initialSizeClassSelector, nil)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse("b9bb6e2c-04ff-4fbd-802b-105be93a8fb7"))
+		stream, err := executionClient.Execute(
+			metadata.AppendToOutgoingContext(
+				ctx,
+				"buildbarn-vcs-commit-sha", "3f786850e387550fdab836ed7e6dc881de23001b",
+				"buildbarn-vcs-dirty", "true"),
+			executeRequest)
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.NoError(t, err)
+
+		// The VCS metadata should be forwarded to the worker as
+		// part of the auxiliary metadata.
+		synchronizeRequest.PreferBeingIdle = false
+		response, err := buildQueue.Synchronize(ctx, synchronizeRequest)
+		require.NoError(t, err)
+		vcsMetadata, err := anypb.New(&vcsmetadata.VcsMetadata{
+			CommitSha: "3f786850e387550fdab836ed7e6dc881de23001b",
+			Dirty:     true,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1010},
+			DesiredState: &remoteworker.DesiredState{
+				WorkerState: &remoteworker.DesiredState_Executing_{
+					Executing: &remoteworker.DesiredState_Executing{
+						DigestFunction: remoteexecution.DigestFunction_SHA1,
+						ActionDigest:   executeRequest.ActionDigest,
+						Action: &remoteexecution.Action{
+							CommandDigest: action.CommandDigest,
+							Timeout:       &durationpb.Duration{Seconds: 1800},
+						},
+						QueuedTimestamp:   &timestamppb.Timestamp{Seconds: 1000},
+						AuxiliaryMetadata: []*anypb.Any{vcsMetadata},
+					},
+				},
+			},
+		}, response)
+	})
+}
+
+func TestInMemoryBuildQueueAuthenticationMetadata(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	timer := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer, nil).AnyTimes()
+	timer.EXPECT().Stop().Return(true).AnyTimes()
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+
+	// Let clients be authenticated, so that authentication
+	// metadata is attached to the context of Execute().
+	authenticationMetadata := &auth_pb.AuthenticationMetadata{
+		Public:  structpb.NewStringValue("alice"),
+		Private: structpb.NewStringValue("secret-token"),
+	}
+	conn := bufconn.Listen(1)
+	server := grpc.NewServer(
+		grpc.StreamInterceptor(
+			bb_grpc.NewAuthenticatingStreamInterceptor(
+				bb_grpc.NewAllowAuthenticator(
+					auth.MustNewAuthenticationMetadataFromProto(authenticationMetadata)))))
+	remoteexecution.RegisterExecutionServer(server, buildQueue)
+	go func() {
+		require.NoError(t, server.Serve(conn))
+	}()
+	client, err := grpc.Dial(
+		"myself",
+		grpc.WithDialer(func(string, time.Duration) (net.Conn, error) {
+			return conn.Dial()
+		}),
+		grpc.WithInsecure())
+	require.NoError(t, err)
+	executionClient := remoteexecution.NewExecutionClient(client)
+
+	// Announce a new worker, which creates a queue for operations.
+	workerID := map[string]string{
+		"hostname": "worker123",
+		"thread":   "42",
+	}
+	synchronizeRequest := &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+		PreferBeingIdle: true,
+	}
+	_, err = buildQueue.Synchronize(ctx, synchronizeRequest)
+	require.NoError(t, err)
+
+	// Let a client enqueue an operation.
+	action := &remoteexecution.Action{
+		CommandDigest: &remoteexecution.Digest{
+			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+			SizeBytes: 456,
+		},
+	}
+	executeRequest := &remoteexecution.ExecuteRequest{
+		InstanceName: "main",
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	}
+	contentAddressableStorage.EXPECT().Get(gomock.Any(), digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123)).
+		Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided))
+	initialSizeClassSelector := mock.NewMockSelector(ctrl)
+	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).
+		Return(platform.MustNewKey("main", platformForTesting), nil, initialSizeClassSelector, nil)
+	initialSizeClassLearner := mock.NewMockLearner(ctrl)
+	initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+		Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
+	uuidGenerator.EXPECT().Call().Return(uuid.Parse("b9bb6e2c-04ff-4fbd-802b-105be93a8fb7"))
+	stream, err := executionClient.Execute(ctx, executeRequest)
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	// The worker should receive the full authentication metadata,
+	// so that it can perform requests on behalf of the client. The
+	// auxiliary metadata should only contain the public part.
+	synchronizeRequest.PreferBeingIdle = false
+	response, err := buildQueue.Synchronize(ctx, synchronizeRequest)
+	require.NoError(t, err)
+	publicAuthenticationMetadata, err := anypb.New(&auth_pb.AuthenticationMetadata{
+		Public: structpb.NewStringValue("alice"),
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1010},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Executing_{
+				Executing: &remoteworker.DesiredState_Executing{
+					DigestFunction: remoteexecution.DigestFunction_SHA1,
+					ActionDigest:   executeRequest.ActionDigest,
+					Action: &remoteexecution.Action{
+						CommandDigest: action.CommandDigest,
+						Timeout:       &durationpb.Duration{Seconds: 1800},
+					},
+					QueuedTimestamp:        &timestamppb.Timestamp{Seconds: 1000},
+					AuxiliaryMetadata:      []*anypb.Any{publicAuthenticationMetadata},
+					AuthenticationMetadata: authenticationMetadata,
+				},
+			},
+		},
+	}, response)
+}
+
+func TestInMemoryBuildQueueDelayedExecution(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	var nowLock sync.Mutex
+	now := time.Unix(1000, 0)
+	clock.EXPECT().Now().DoAndReturn(func() time.Time {
+		nowLock.Lock()
+		defer nowLock.Unlock()
+		return now
+	}).AnyTimes()
+	setNow := func(t time.Time) {
+		nowLock.Lock()
+		now = t
+		nowLock.Unlock()
+	}
+	timer := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer, nil).AnyTimes()
+	timer.EXPECT().Stop().Return(true).AnyTimes()
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.MaximumExecutionDelay = time.Hour
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	action := &remoteexecution.Action{
+		CommandDigest: &remoteexecution.Digest{
+			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+			SizeBytes: 456,
+		},
+	}
+	executeRequest := &remoteexecution.ExecuteRequest{
+		InstanceName: "main",
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	}
+	actionDigest := digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123)
+
+	// Announce a new worker, which creates a queue for operations.
+	workerID := map[string]string{
+		"hostname": "worker123",
+		"thread":   "42",
+	}
+	synchronizeRequest := &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+		PreferBeingIdle: true,
+	}
+	_, err := buildQueue.Synchronize(ctx, synchronizeRequest)
+	require.NoError(t, err)
+
+	t.Run("InvalidHeader", func(t *testing.T) {
+		contentAddressableStorage.EXPECT().Get(gomock.Any(), actionDigest).
+			Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided))
+
+		stream, err := executionClient.Execute(
+			metadata.AppendToOutgoingContext(ctx, "buildbarn-earliest-start-time", "tomorrow"),
+			executeRequest)
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid value for header \"buildbarn-earliest-start-time\": \"tomorrow\""), err)
+	})
+
+	t.Run("TooFarInTheFuture", func(t *testing.T) {
+		contentAddressableStorage.EXPECT().Get(gomock.Any(), actionDigest).
+			Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided))
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).
+			Return(platform.MustNewKey("main", platformForTesting), nil, initialSizeClassSelector, nil)
+		initialSizeClassSelector.EXPECT().Abandoned()
+
+		stream, err := executionClient.Execute(
+			metadata.AppendToOutgoingContext(ctx, "buildbarn-earliest-start-time", "1970-01-01T02:00:00Z"),
+			executeRequest)
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Earliest start time 1970-01-01T02:00:00Z exceeds the maximum permitted start time 1970-01-01T01:16:40Z"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Let a client enqueue an operation that should only
+		// start executing a minute from now.
+		contentAddressableStorage.EXPECT().Get(gomock.Any(), actionDigest).
+			Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided))
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).
+			Return(platform.MustNewKey("main", platformForTesting), nil, initialSizeClassSelector, nil)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse("b9bb6e2c-04ff-4fbd-802b-105be93a8fb7"))
+		ctxWithCancel, cancel := context.WithCancel(ctx)
+		stream, err := executionClient.Execute(
+			metadata.AppendToOutgoingContext(ctxWithCancel, "buildbarn-earliest-start-time", "1970-01-01T00:17:40Z"),
+			executeRequest)
+		require.NoError(t, err)
+		update, err := stream.Recv()
+		require.NoError(t, err)
+		queuedMetadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+			Stage:        remoteexecution.ExecutionStage_QUEUED,
+			ActionDigest: executeRequest.ActionDigest,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &longrunningpb.Operation{
+			Name:     "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7",
+			Metadata: queuedMetadata,
+		}, update)
+
+		// The client may disconnect without causing the
+		// operation to be removed.
+		cancel()
+
+		// Before the earliest start time, workers should not
+		// be able to pick up the operation.
+		setNow(time.Unix(1030, 0))
+		response, err := buildQueue.Synchronize(ctx, synchronizeRequest)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1030},
+			DesiredState: &remoteworker.DesiredState{
+				WorkerState: &remoteworker.DesiredState_Idle{
+					Idle: &emptypb.Empty{},
+				},
+			},
+		}, response)
+
+		// Once the earliest start time is reached, the
+		// operation should be handed out to the worker.
+		setNow(time.Unix(1060, 0))
+		synchronizeRequest.PreferBeingIdle = false
+		response, err = buildQueue.Synchronize(ctx, synchronizeRequest)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1070},
+			DesiredState: &remoteworker.DesiredState{
+				WorkerState: &remoteworker.DesiredState_Executing_{
+					Executing: &remoteworker.DesiredState_Executing{
+						DigestFunction: remoteexecution.DigestFunction_SHA1,
+						ActionDigest:   executeRequest.ActionDigest,
+						Action: &remoteexecution.Action{
+							CommandDigest: action.CommandDigest,
+							Timeout:       &durationpb.Duration{Seconds: 1800},
+						},
+						QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1060},
+					},
+				},
+			},
+		}, response)
+
+		// The client should be able to reattach to the
+		// operation.
+		stream2, err := executionClient.WaitExecution(ctx, &remoteexecution.WaitExecutionRequest{
+			Name: "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7",
+		})
+		require.NoError(t, err)
+		update, err = stream2.Recv()
+		require.NoError(t, err)
+		executingMetadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+			Stage:        remoteexecution.ExecutionStage_EXECUTING,
+			ActionDigest: executeRequest.ActionDigest,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &longrunningpb.Operation{
+			Name:     "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7",
+			Metadata: executingMetadata,
+		}, update)
+	})
+}
+
+func TestInMemoryBuildQueueDigestFunctions(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.GetDigestFunctions = func(instanceName digest.InstanceName) []remoteexecution.DigestFunction_Value {
+		if instanceName.String() == "tree" {
+			return []remoteexecution.DigestFunction_Value{
+				remoteexecution.DigestFunction_SHA256TREE,
+				remoteexecution.DigestFunction_SHA256,
+			}
+		}
+		return nil
+	}
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+
+	t.Run("GetCapabilitiesDefault", func(t *testing.T) {
+		// Instance names for which no restrictions are
+		// configured should allow all digest functions.
+		capabilities, err := buildQueue.GetCapabilities(ctx, digest.MustNewInstanceName("main"))
+		require.NoError(t, err)
+		require.Equal(t, remoteexecution.DigestFunction_SHA256, capabilities.ExecutionCapabilities.DigestFunction)
+		require.Equal(t, digest.SupportedDigestFunctions, capabilities.ExecutionCapabilities.DigestFunctions)
+	})
+
+	t.Run("GetCapabilitiesRestricted", func(t *testing.T) {
+		capabilities, err := buildQueue.GetCapabilities(ctx, digest.MustNewInstanceName("tree"))
+		require.NoError(t, err)
+		require.Equal(t, remoteexecution.DigestFunction_SHA256TREE, capabilities.ExecutionCapabilities.DigestFunction)
+		require.Equal(t, []remoteexecution.DigestFunction_Value{
+			remoteexecution.DigestFunction_SHA256TREE,
+			remoteexecution.DigestFunction_SHA256,
+		}, capabilities.ExecutionCapabilities.DigestFunctions)
+	})
+
+	t.Run("ExecuteNotPermitted", func(t *testing.T) {
+		// Execute() requests using a digest function that is
+		// not permitted should be rejected before the action is
+		// loaded from the Content Addressable Storage.
+		executionClient := getExecutionClient(t, buildQueue)
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: "tree",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+				SizeBytes: 123,
+			},
+			DigestFunction: remoteexecution.DigestFunction_SHA1,
+		})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Digest function SHA1 is not permitted for instance name \"tree\""), err)
+	})
+
+	t.Run("ExecuteBLAKE3", func(t *testing.T) {
+		// BLAKE3 is supported by default. Execute() requests
+		// using it should cause the action to be loaded from
+		// the Content Addressable Storage.
+		capabilities, err := buildQueue.GetCapabilities(ctx, digest.MustNewInstanceName("main"))
+		require.NoError(t, err)
+		require.Contains(t, capabilities.ExecutionCapabilities.DigestFunctions, remoteexecution.DigestFunction_BLAKE3)
+
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_BLAKE3, "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262", 123),
+		).Return(buffer.NewBufferFromError(status.Error(codes.FailedPrecondition, "Blob not found")))
+
+		executionClient := getExecutionClient(t, buildQueue)
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: "main",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262",
+				SizeBytes: 123,
+			},
+			DigestFunction: remoteexecution.DigestFunction_BLAKE3,
+		})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Failed to obtain action: Blob not found"), err)
+	})
+}
+
+func TestInMemoryBuildQueueCacheOnly(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.GetCacheOnlyMessage = func(instanceName digest.InstanceName) (string, bool) {
+		switch instanceName.String() {
+		case "cache":
+			return "Use instance name \"remote\" instead", true
+		case "cache-silent":
+			return "", true
+		}
+		return "", false
+	}
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	t.Run("GetCapabilities", func(t *testing.T) {
+		capabilities, err := buildQueue.GetCapabilities(ctx, digest.MustNewInstanceName("remote"))
+		require.NoError(t, err)
+		require.True(t, capabilities.ExecutionCapabilities.ExecEnabled)
+
+		capabilities, err = buildQueue.GetCapabilities(ctx, digest.MustNewInstanceName("cache"))
+		require.NoError(t, err)
+		require.False(t, capabilities.ExecutionCapabilities.ExecEnabled)
+	})
+
+	t.Run("ExecuteWithMessage", func(t *testing.T) {
+		// Execute() requests should be rejected immediately,
+		// without loading the action from the Content
+		// Addressable Storage.
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: "cache",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+				SizeBytes: 123,
+			},
+		})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Instance name \"cache\" is cache-only, and does not permit remote execution: Use instance name \"remote\" instead"), err)
+	})
+
+	t.Run("ExecuteWithoutMessage", func(t *testing.T) {
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: "cache-silent",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+				SizeBytes: 123,
+			},
+		})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Instance name \"cache-silent\" is cache-only, and does not permit remote execution"), err)
+	})
+}
+
+func TestInMemoryBuildQueueOperationJournal(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	operationJournal := mock.NewMockOperationJournal(ctrl)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.OperationJournal = operationJournal
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	// Announce a new worker, which creates a queue for operations.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+		PreferBeingIdle: true,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1000},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, response)
+
+	// Let a client enqueue an operation. This should cause the
+	// operation to be written to the journal.
+	contentAddressableStorage.EXPECT().Get(
+		gomock.Any(),
+		digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123),
+	).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+		CommandDigest: &remoteexecution.Digest{
+			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+			SizeBytes: 456,
+		},
+	}, buffer.UserProvided))
+	initialSizeClassSelector := mock.NewMockSelector(ctrl)
+	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).
+		Return(platform.MustNewKey("main", platformForTesting), nil, initialSizeClassSelector, nil)
+	initialSizeClassLearner := mock.NewMockLearner(ctrl)
+	initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+		Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
+	clock.EXPECT().Now().Return(time.Unix(1001, 0))
+	timer := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+	timer.EXPECT().Stop().Return(true)
+	uuidGenerator.EXPECT().Call().Return(uuid.Parse("b9bb6e2c-04ff-4fbd-802b-105be93a8fb7"))
+	var queuedOperation *operationjournal.Operation
+	operationJournal.EXPECT().PutOperation(gomock.Any()).Do(func(operation *operationjournal.Operation) {
+		queuedOperation = proto.Clone(operation).(*operationjournal.Operation)
+	})
+	stream, err := executionClient.Execute(
+		ctx,
+		&remoteexecution.ExecuteRequest{
+			InstanceName: "main",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+				SizeBytes: 123,
+			},
+		})
+	require.NoError(t, err)
+	update, err := stream.Recv()
+	require.NoError(t, err)
+	metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage: remoteexecution.ExecutionStage_QUEUED,
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &longrunningpb.Operation{
+		Name:     "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7",
+		Metadata: metadata,
+	}, update)
+
+	desiredState := &remoteworker.DesiredState_Executing{
+		DigestFunction: remoteexecution.DigestFunction_SHA1,
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+		Action: &remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+				SizeBytes: 456,
+			},
+			Timeout: &durationpb.Duration{Seconds: 1800},
+		},
+		QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1001},
+	}
+	testutil.RequireEqualProto(t, &operationjournal.Operation{
+		Name:             "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7",
+		InstanceName:     "main",
+		InvocationWeight: 1,
+		SizeClassQueueName: &buildqueuestate.SizeClassQueueName{
+			PlatformQueueName: &buildqueuestate.PlatformQueueName{
+				InstanceNamePrefix: "main",
+				Platform:           platformForTesting,
+			},
+		},
+		DesiredState:       desiredState,
+		ExpectedDuration:   &durationpb.Duration{Seconds: 900},
+		ActionInstanceName: "main",
+	}, queuedOperation)
+
+	// Let a worker pick up the operation. The journal should be
+	// updated to contain the ID of the worker.
+	clock.EXPECT().Now().Return(time.Unix(1002, 0)).Times(2)
+	timer = mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+	timer.EXPECT().Stop().Return(true).MaxTimes(1)
+	var executingOperation *operationjournal.Operation
+	operationJournal.EXPECT().PutOperation(gomock.Any()).Do(func(operation *operationjournal.Operation) {
+		executingOperation = proto.Clone(operation).(*operationjournal.Operation)
+	})
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1012},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Executing_{
+				Executing: desiredState,
+			},
+		},
+	}, response)
+
+	update, err = stream.Recv()
+	require.NoError(t, err)
+	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage: remoteexecution.ExecutionStage_EXECUTING,
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &longrunningpb.Operation{
+		Name:     "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7",
+		Metadata: metadata,
+	}, update)
+
+	expectedExecutingOperation := proto.Clone(queuedOperation).(*operationjournal.Operation)
+	expectedExecutingOperation.WorkerId = map[string]string{
+		"hostname": "worker123",
+		"thread":   "42",
+	}
+	testutil.RequireEqualProto(t, expectedExecutingOperation, executingOperation)
+
+	// Simulate a restart of the scheduler by creating another build
+	// queue, and recovering the operation from the journal. The
+	// operation should be reported as being queued, as the worker
+	// has not reattached to it yet.
+	clock.EXPECT().Now().Return(time.Unix(2000, 0)).Times(2)
+	operationJournal2 := mock.NewMockOperationJournal(ctrl)
+	buildQueueConfiguration2 := buildQueueConfigurationForTesting
+	buildQueueConfiguration2.OperationJournal = operationJournal2
+	buildQueue2 := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration2, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	require.NoError(t, buildQueue2.RecoverOperations([]*operationjournal.Operation{executingOperation}))
+	executionClient2 := getExecutionClient(t, buildQueue2)
+
+	clock.EXPECT().Now().Return(time.Unix(2001, 0)).Times(2)
+	timer = mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+	timer.EXPECT().Stop().Return(true)
+	stream2, err := executionClient2.WaitExecution(ctx, &remoteexecution.WaitExecutionRequest{
+		Name: "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7",
+	})
+	require.NoError(t, err)
+	update, err = stream2.Recv()
+	require.NoError(t, err)
+	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage: remoteexecution.ExecutionStage_QUEUED,
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &longrunningpb.Operation{
+		Name:     "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7",
+		Metadata: metadata,
+	}, update)
+
+	// Let the worker synchronize against the new scheduler,
+	// reporting that it's still executing the operation. This
+	// should cause the worker to be reattached to the operation,
+	// as opposed to being instructed to execute something else.
+	clock.EXPECT().Now().Return(time.Unix(2002, 0)).Times(2)
+	timer = mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+	timer.EXPECT().Stop().Return(true)
+	operationJournal2.EXPECT().PutOperation(testutil.EqProto(t, executingOperation))
+	response, err = buildQueue2.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						SizeBytes: 123,
+					},
+					ExecutionState: &remoteworker.CurrentState_Executing_Running{
+						Running: &emptypb.Empty{},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 2012},
+	}, response)
+
+	update, err = stream2.Recv()
+	require.NoError(t, err)
+	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage: remoteexecution.ExecutionStage_EXECUTING,
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &longrunningpb.Operation{
+		Name:     "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7",
+		Metadata: metadata,
+	}, update)
+
+	// Let the worker complete the execution of the operation. This
+	// should cause the operation to be removed from the journal.
+	clock.EXPECT().Now().Return(time.Unix(2003, 0)).Times(3)
+	operationJournal2.EXPECT().RemoveOperation("b9bb6e2c-04ff-4fbd-802b-105be93a8fb7")
+	response, err = buildQueue2.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						SizeBytes: 123,
+					},
+					ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+						Completed: &remoteexecution.ExecuteResponse{
+							Result: &remoteexecution.ActionResult{},
+						},
+					},
+				},
+			},
+		},
+		PreferBeingIdle: true,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 2003},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, response)
+
+	update, err = stream2.Recv()
+	require.NoError(t, err)
+	metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage: remoteexecution.ExecutionStage_COMPLETED,
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	executeResponse, err := anypb.New(&remoteexecution.ExecuteResponse{
+		Result: &remoteexecution.ActionResult{},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &longrunningpb.Operation{
+		Name:     "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7",
+		Metadata: metadata,
+		Done:     true,
+		Result:   &longrunningpb.Operation_Response{Response: executeResponse},
+	}, update)
+
+	_, err = stream2.Recv()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestInMemoryBuildQueueRecommendedWorkersCount(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.WorkerCountRecommendationWindow = time.Minute
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	getRecommendedWorkersCount := func(now time.Time) uint32 {
+		clock.EXPECT().Now().Return(now)
+		response, err := buildQueue.ListPlatformQueues(ctx, &emptypb.Empty{})
+		require.NoError(t, err)
+		require.Len(t, response.PlatformQueues, 1)
+		require.Len(t, response.PlatformQueues[0].SizeClassQueues, 1)
+		return response.PlatformQueues[0].SizeClassQueues[0].RecommendedWorkersCount
+	}
+
+	// Announce a new worker, which creates a queue for operations.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+		PreferBeingIdle: true,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1000},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, response)
+
+	// Without any queued operations, no workers are needed.
+	require.Equal(t, uint32(0), getRecommendedWorkersCount(time.Unix(1000, 0)))
+
+	operationParameters := [...]struct {
+		actionHash    string
+		commandHash   string
+		operationName string
+	}{
+		{"f4d362da1f854e54984275aa78e2d9f8", "5fbd808d5cf24a219824664040a95c44", "93e2738a-837c-4524-9f0f-430b47caa889"},
+		{"25b997dcfbe34f95bbbab10bc02e1a61", "2ac9ddc1a64442fabd819358a206909e", "fadbaf2f-669f-47ee-bce4-35f255d2ba16"},
+		{"deb57a22d6b149b2b83d26dbc30d8f28", "5def73fca7c945d3998d984194879f5f", "1d320c02-399a-40bb-bb9a-031960c7e542"},
+	}
+
+	// Enqueue three operations, having decreasing priorities.
+	initialSizeClassLearners := make([]*mock.MockLearner, 0, len(operationParameters))
+	streams := make([]remoteexecution.Execution_ExecuteClient, 0, len(operationParameters))
+	for i, p := range operationParameters {
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_MD5, p.actionHash, 123),
+		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      p.commandHash,
+				SizeBytes: 456,
+			},
+		}, buffer.UserProvided))
+
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(
+			platform.MustNewKey("main", platformForTesting),
+			nil,
+			initialSizeClassSelector,
+			nil,
+		)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassLearners = append(initialSizeClassLearners, initialSizeClassLearner)
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
+
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		timer.EXPECT().Stop().Return(true).MaxTimes(1)
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(p.operationName))
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: "main",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      p.actionHash,
+				SizeBytes: 123,
+			},
+			ExecutionPolicy: &remoteexecution.ExecutionPolicy{
+				Priority: int32(i),
+			},
+		})
+		require.NoError(t, err)
+		streams = append(streams, stream)
+		_, err = stream.Recv()
+		require.NoError(t, err)
+	}
+
+	// Three operations arrived in the last minute, and three are
+	// queued. As no operations have completed yet, they are assumed
+	// to take the full window of one minute to execute. Three
+	// workers are needed to keep up with the arrival rate, while
+	// another three are needed to drain the queue within a minute.
+	require.Equal(t, uint32(6), getRecommendedWorkersCount(time.Unix(1000, 0)))
+
+	// Let the worker pick up the first operation.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).Times(2)
+	timer := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+	timer.EXPECT().Stop().Return(true)
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteexecution.Digest{
+		Hash:      operationParameters[0].actionHash,
+		SizeBytes: 123,
+	}, response.DesiredState.GetExecuting().GetActionDigest())
+	_, err = streams[0].Recv()
+	require.NoError(t, err)
+
+	// Let the worker complete the first operation after 30 seconds
+	// and pick up the second operation.
+	initialSizeClassLearners[0].EXPECT().Succeeded(30*time.Second, []uint32{0})
+	clock.EXPECT().Now().Return(time.Unix(1030, 0)).Times(4)
+	timer = mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+	timer.EXPECT().Stop().Return(true).MaxTimes(1)
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      operationParameters[0].actionHash,
+						SizeBytes: 123,
+					},
+					ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+						Completed: &remoteexecution.ExecuteResponse{
+							Result: &remoteexecution.ActionResult{
+								ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+									VirtualExecutionDuration: &durationpb.Duration{Seconds: 30},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteexecution.Digest{
+		Hash:      operationParameters[1].actionHash,
+		SizeBytes: 123,
+	}, response.DesiredState.GetExecuting().GetActionDigest())
+	update, err := streams[0].Recv()
+	require.NoError(t, err)
+	require.True(t, update.Done)
+	_, err = streams[1].Recv()
+	require.NoError(t, err)
+
+	// The arrival rate has decayed to 3·e^(-1/2) operations per
+	// minute, while operations now take 30 seconds to execute. This
+	// means 0.91 workers are needed to keep up with the arrival
+	// rate, while 0.5 workers are needed to drain the single queued
+	// operation within a minute.
+	require.Equal(t, uint32(2), getRecommendedWorkersCount(time.Unix(1030, 0)))
+}
+
+func TestInMemoryBuildQueueMaximumQueuedOperations(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.QueueFullRetryDelay = 30 * time.Second
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
+		digest.MustNewInstanceName("main"),
+		platformForTesting,
+		/* workerInvocationStickinessLimits = */ nil,
+		/* maximumQueuedBackgroundLearningOperations = */ 0,
+		/* backgroundLearningOperationPriority = */ 0,
+		/* maximumSizeClass = */ 0,
+		/* maximumQueuedOperations = */ 1,
+		/* preemptionPolicy = */ nil,
+		/* workerResourceRequirements = */ nil,
+		/* sizeClassEscalationPolicy = */ nil))
+
+	expectExecute := func(actionHash string) *mock.MockSelector {
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, actionHash, 123),
+		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+				SizeBytes: 456,
+			},
+		}, buffer.UserProvided))
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(
+			platform.MustNewKey("main", platformForTesting),
+			nil,
+			initialSizeClassSelector,
+			nil,
+		)
+		clock.EXPECT().Now().Return(time.Unix(1001, 0))
+		return initialSizeClassSelector
+	}
+
+	// The first operation may be queued, as the platform queue is
+	// still empty.
+	initialSizeClassSelector := expectExecute("da39a3ee5e6b4b0d3255bfef95601890afd80709")
+	initialSizeClassLearner := mock.NewMockLearner(ctrl)
+	initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+		Return(0, 30*time.Second, time.Minute, initialSizeClassLearner)
+	timer1 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer1, nil)
+	timer1.EXPECT().Stop().Return(true).MaxTimes(1)
+	uuidGenerator.EXPECT().Call().Return(uuid.Parse("36ebab65-3c4f-4faf-818b-2eabb4cd1b02"))
+	stream1, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+		InstanceName: "main",
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	update, err := stream1.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "36ebab65-3c4f-4faf-818b-2eabb4cd1b02", update.Name)
+
+	// A second operation for a different action would cause the
+	// limit to be exceeded. The request should be rejected, and the
+	// client should be instructed to retry later.
+	initialSizeClassSelector = expectExecute("c6d0f1eb3ef1ae5b0ea6bb6bc4ff6e49b6ab1ad2")
+	initialSizeClassSelector.EXPECT().Abandoned()
+	stream2, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+		InstanceName: "main",
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "c6d0f1eb3ef1ae5b0ea6bb6bc4ff6e49b6ab1ad2",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	_, err = stream2.Recv()
+	expectedStatus, err2 := status.New(
+		codes.ResourceExhausted,
+		"Platform queue for instance name prefix \"main\" platform {\"properties\":[{\"name\":\"cpu\",\"value\":\"armv6\"},{\"name\":\"os\",\"value\":\"linux\"}]} already contains 1 queued operations, which is the maximum permitted",
+	).WithDetails(&errdetails.RetryInfo{
+		RetryDelay: &durationpb.Duration{Seconds: 30},
+	})
+	require.NoError(t, err2)
+	testutil.RequireEqualStatus(t, expectedStatus.Err(), err)
+
+	// Requests that can be deduplicated against the operation
+	// that is already queued should not be rejected.
+	initialSizeClassSelector = expectExecute("da39a3ee5e6b4b0d3255bfef95601890afd80709")
+	initialSizeClassSelector.EXPECT().Abandoned()
+	timer3 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer3, nil)
+	timer3.EXPECT().Stop().Return(true).MaxTimes(1)
+	stream3, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+		InstanceName: "main",
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	update, err = stream3.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "36ebab65-3c4f-4faf-818b-2eabb4cd1b02", update.Name)
+}
+
+func TestInMemoryBuildQueuePreemption(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	// Create a platform queue in which operations with priority
+	// zero or less may cause other tasks to be preempted after
+	// being queued for ten seconds.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
+		digest.MustNewInstanceName("main"),
+		platformForTesting,
+		/* workerInvocationStickinessLimits = */ nil,
+		/* maximumQueuedBackgroundLearningOperations = */ 0,
+		/* backgroundLearningOperationPriority = */ 0,
+		/* maximumSizeClass = */ 0,
+		/* maximumQueuedOperations = */ 0,
+		&scheduler.PreemptionPolicy{
+			MaximumPriority: 0,
+			QueuedDuration:  10 * time.Second,
+		},
+		/* workerResourceRequirements = */ nil,
+		/* sizeClassEscalationPolicy = */ nil))
+
+	execute := func(actionHash, operationName string, priority int32, now int64) (remoteexecution.Execution_ExecuteClient, *mock.MockTimer) {
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, actionHash, 123),
+		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+				SizeBytes: 456,
+			},
+		}, buffer.UserProvided))
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(
+			platform.MustNewKey("main", platformForTesting),
+			nil,
+			initialSizeClassSelector,
+			nil,
+		)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, 30*time.Second, time.Minute, initialSizeClassLearner)
+		clock.EXPECT().Now().Return(time.Unix(now, 0))
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(operationName))
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: "main",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      actionHash,
+				SizeBytes: 123,
+			},
+			ExecutionPolicy: &remoteexecution.ExecutionPolicy{
+				Priority: priority,
+			},
+		})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.NoError(t, err)
+		return stream, timer
+	}
+	requireStage := func(stream remoteexecution.Execution_ExecuteClient, stage remoteexecution.ExecutionStage_Value) {
+		update, err := stream.Recv()
+		require.NoError(t, err)
+		var metadata remoteexecution.ExecuteOperationMetadata
+		require.NoError(t, update.Metadata.UnmarshalTo(&metadata))
+		require.Equal(t, stage, metadata.Stage)
+	}
+	synchronize := func(currentState *remoteworker.CurrentState) *remoteworker.SynchronizeResponse {
+		response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: map[string]string{
+				"hostname": "worker123",
+				"thread":   "42",
+			},
+			InstanceNamePrefix: "main",
+			Platform:           platformForTesting,
+			CurrentState:       currentState,
+		})
+		require.NoError(t, err)
+		return response
+	}
+
+	// Let a worker execute an operation having a low priority.
+	lowPriorityStream, lowPriorityTimer1 := execute("da39a3ee5e6b4b0d3255bfef95601890afd80709", "36ebab65-3c4f-4faf-818b-2eabb4cd1b02", 100, 1001)
+	lowPriorityTimer1.EXPECT().Stop().Return(true)
+	clock.EXPECT().Now().Return(time.Unix(1002, 0)).Times(2)
+	lowPriorityTimer2 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(lowPriorityTimer2, nil)
+	lowPriorityTimer2.EXPECT().Stop().Return(true)
+	response := synchronize(&remoteworker.CurrentState{
+		WorkerState: &remoteworker.CurrentState_Idle{
+			Idle: &emptypb.Empty{},
+		},
+	})
+	testutil.RequireEqualProto(t, &remoteexecution.Digest{
+		Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+		SizeBytes: 123,
+	}, response.DesiredState.GetExecuting().GetActionDigest())
+	requireStage(lowPriorityStream, remoteexecution.ExecutionStage_EXECUTING)
+
+	// Enqueue an operation having a high priority. As no workers
+	// are idle, it remains queued.
+	highPriorityStream, highPriorityTimer1 := execute("c6d0f1eb3ef1ae5b0ea6bb6bc4ff6e49b6ab1ad2", "b6b8e5b4-5d63-4a5c-9b8a-6e5b5d6bd0e7", 0, 1003)
+	highPriorityTimer1.EXPECT().Stop().Return(true)
+
+	// Once the operation has been queued for more than ten seconds,
+	// the task having a low priority is preempted and requeued. The
+	// worker should be instructed to execute the operation having a
+	// high priority instead.
+	clock.EXPECT().Now().Return(time.Unix(1014, 0)).Times(3)
+	for i := 0; i < 2; i++ {
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		// The clients are still waiting for the operations to
+		// complete when the test finishes.
+		timer.EXPECT().Stop().Return(true).MaxTimes(1)
+	}
+	response = synchronize(&remoteworker.CurrentState{
+		WorkerState: &remoteworker.CurrentState_Executing_{
+			Executing: &remoteworker.CurrentState_Executing{
+				ActionDigest: &remoteexecution.Digest{
+					Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+					SizeBytes: 123,
+				},
+				ExecutionState: &remoteworker.CurrentState_Executing_Running{
+					Running: &emptypb.Empty{},
+				},
+			},
+		},
+	})
+	testutil.RequireEqualProto(t, &remoteexecution.Digest{
+		Hash:      "c6d0f1eb3ef1ae5b0ea6bb6bc4ff6e49b6ab1ad2",
+		SizeBytes: 123,
+	}, response.DesiredState.GetExecuting().GetActionDigest())
+	requireStage(lowPriorityStream, remoteexecution.ExecutionStage_QUEUED)
+	requireStage(highPriorityStream, remoteexecution.ExecutionStage_EXECUTING)
+}
+
+func TestInMemoryBuildQueueMaximumExecutingOperationsPerInvocation(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.MaximumExecutingOperationsPerInvocation = 1
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	synchronize := func(thread string, currentState *remoteworker.CurrentState, preferBeingIdle bool) *remoteworker.SynchronizeResponse {
+		response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: map[string]string{
+				"hostname": "worker123",
+				"thread":   thread,
+			},
+			InstanceNamePrefix: "main",
+			Platform:           platformForTesting,
+			CurrentState:       currentState,
+			PreferBeingIdle:    preferBeingIdle,
+		})
+		require.NoError(t, err)
+		return response
+	}
+	idleState := &remoteworker.CurrentState{
+		WorkerState: &remoteworker.CurrentState_Idle{
+			Idle: &emptypb.Empty{},
+		},
+	}
+
+	// Announce two workers, which creates a queue for operations.
+	for _, thread := range []string{"0", "1"} {
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		require.NotNil(t, synchronize(thread, idleState, true).DesiredState.GetIdle())
+	}
+
+	initialSizeClassLearners := map[string]*mock.MockLearner{}
+	execute := func(actionHash, operationName, correlatedInvocationsID string, priority int32, now int64) remoteexecution.Execution_ExecuteClient {
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, actionHash, 123),
+		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+				SizeBytes: 456,
+			},
+		}, buffer.UserProvided))
+		invocationID, err := anypb.New(&remoteexecution.RequestMetadata{
+			CorrelatedInvocationsId: correlatedInvocationsID,
+		})
+		require.NoError(t, err)
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(
+			platform.MustNewKey("main", platformForTesting),
+			[]invocation.Key{invocation.MustNewKey(invocationID)},
+			initialSizeClassSelector,
+			nil,
+		)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassLearners[actionHash] = initialSizeClassLearner
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, 30*time.Second, time.Minute, initialSizeClassLearner)
+		clock.EXPECT().Now().Return(time.Unix(now, 0))
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		timer.EXPECT().Stop().Return(true).MaxTimes(1)
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(operationName))
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: "main",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      actionHash,
+				SizeBytes: 123,
+			},
+			ExecutionPolicy: &remoteexecution.ExecutionPolicy{
+				Priority: priority,
+			},
+		})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.NoError(t, err)
+		return stream
+	}
+	requireExecuting := func(response *remoteworker.SynchronizeResponse, actionHash string) {
+		testutil.RequireEqualProto(t, &remoteexecution.Digest{
+			Hash:      actionHash,
+			SizeBytes: 123,
+		}, response.DesiredState.GetExecuting().GetActionDigest())
+	}
+	expectStreamWakeup := func() {
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		// The client is still waiting for the operation to
+		// complete when the test finishes.
+		timer.EXPECT().Stop().Return(true).MaxTimes(1)
+	}
+
+	// Enqueue two operations belonging to the same invocation.
+	streamA1 := execute("da39a3ee5e6b4b0d3255bfef95601890afd80709", "36ebab65-3c4f-4faf-818b-2eabb4cd1b02", "A", 0, 1010)
+	streamA2 := execute("c6d0f1eb3ef1ae5b0ea6bb6bc4ff6e49b6ab1ad2", "b6b8e5b4-5d63-4a5c-9b8a-6e5b5d6bd0e7", "A", 1, 1011)
+
+	// The first worker may execute one of the operations.
+	clock.EXPECT().Now().Return(time.Unix(1020, 0)).Times(2)
+	expectStreamWakeup()
+	requireExecuting(synchronize("0", idleState, false), "da39a3ee5e6b4b0d3255bfef95601890afd80709")
+	_, err := streamA1.Recv()
+	require.NoError(t, err)
+
+	// The second worker may not execute the other operation, as
+	// the invocation has reached its concurrency limit.
+	clock.EXPECT().Now().Return(time.Unix(1021, 0))
+	require.NotNil(t, synchronize("1", idleState, true).DesiredState.GetIdle())
+
+	// Operations belonging to other invocations may still be
+	// executed by the second worker.
+	streamB1 := execute("4a0e4a5c1d0b4e0f9b3a4e2a4c0f8f3b6d2e1a0c", "a2d4e1a9-1c2b-4f7c-8f2a-3e5d6c7b8a90", "B", 0, 1022)
+	clock.EXPECT().Now().Return(time.Unix(1023, 0)).Times(2)
+	expectStreamWakeup()
+	requireExecuting(synchronize("1", idleState, false), "4a0e4a5c1d0b4e0f9b3a4e2a4c0f8f3b6d2e1a0c")
+	_, err = streamB1.Recv()
+	require.NoError(t, err)
+
+	// Once the first operation completes, the first worker may
+	// execute the second operation of the invocation.
+	initialSizeClassLearners["da39a3ee5e6b4b0d3255bfef95601890afd80709"].EXPECT().Succeeded(10*time.Second, []uint32{0})
+	clock.EXPECT().Now().Return(time.Unix(1030, 0)).Times(4)
+	expectStreamWakeup()
+	requireExecuting(synchronize("0", &remoteworker.CurrentState{
+		WorkerState: &remoteworker.CurrentState_Executing_{
+			Executing: &remoteworker.CurrentState_Executing{
+				ActionDigest: &remoteexecution.Digest{
+					Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+					SizeBytes: 123,
+				},
+				ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+					Completed: &remoteexecution.ExecuteResponse{
+						Result: &remoteexecution.ActionResult{
+							ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+								VirtualExecutionDuration: &durationpb.Duration{Seconds: 10},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, false), "c6d0f1eb3ef1ae5b0ea6bb6bc4ff6e49b6ab1ad2")
+	update, err := streamA1.Recv()
+	require.NoError(t, err)
+	require.True(t, update.Done)
+	_, err = streamA2.Recv()
+	require.NoError(t, err)
+}
 
-	// Even though there are no longer any active or queued
-	// invocations, the scheduler should keep track of the
-	// invocation belonging to the previously completed action,
-	// keeping track of how which workers are associated with it.
-	mockClock.EXPECT().Now().Return(time.Unix(1003, 0)).Times(3)
-	invocationName := &buildqueuestate.InvocationName{
-		SizeClassQueueName: sizeClassQueueName,
+func TestInMemoryBuildQueueWorkerReservation(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.WorkerReservationPolicy = &scheduler.WorkerReservationPolicy{
+		MaximumWorkersPerInvocation: 1,
+		MaximumReservedWorkersRatio: 0.5,
+		IdleTimeout:                 20 * time.Second,
 	}
-	invocationStates, err := buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
-		InvocationName: invocationName,
-		Filter:         buildqueuestate.ListInvocationChildrenRequest_ALL,
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	synchronize := func(thread string, currentState *remoteworker.CurrentState, preferBeingIdle bool) *remoteworker.SynchronizeResponse {
+		response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: map[string]string{
+				"hostname": "worker123",
+				"thread":   thread,
+			},
+			InstanceNamePrefix: "main",
+			Platform:           platformForTesting,
+			CurrentState:       currentState,
+			PreferBeingIdle:    preferBeingIdle,
+		})
+		require.NoError(t, err)
+		return response
+	}
+	idleState := &remoteworker.CurrentState{
+		WorkerState: &remoteworker.CurrentState_Idle{
+			Idle: &emptypb.Empty{},
+		},
+	}
+	completedState := func(actionHash string) *remoteworker.CurrentState {
+		return &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      actionHash,
+						SizeBytes: 123,
+					},
+					ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+						Completed: &remoteexecution.ExecuteResponse{
+							Result: &remoteexecution.ActionResult{
+								ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
+									VirtualExecutionDuration: &durationpb.Duration{Seconds: 1},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// Announce two workers, which creates a queue for operations.
+	for _, thread := range []string{"0", "1"} {
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		require.NotNil(t, synchronize(thread, idleState, true).DesiredState.GetIdle())
+	}
+
+	initialSizeClassLearners := map[string]*mock.MockLearner{}
+	execute := func(actionHash, operationName, correlatedInvocationsID string, reservedWorkers string, now int64) remoteexecution.Execution_ExecuteClient {
+		// The "reservedWorkers" platform property should be
+		// removed prior to routing the action, so that it does
+		// not cause the action to end up in another platform
+		// queue.
+		routedAction := &remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+				SizeBytes: 456,
+			},
+			Platform: &remoteexecution.Platform{
+				Properties: []*remoteexecution.Platform_Property{
+					{Name: "cpu", Value: "armv6"},
+					{Name: "os", Value: "linux"},
+				},
+			},
+		}
+		action := proto.Clone(routedAction).(*remoteexecution.Action)
+		if reservedWorkers != "" {
+			action.Platform.Properties = append(action.Platform.Properties, &remoteexecution.Platform_Property{
+				Name:  "reservedWorkers",
+				Value: reservedWorkers,
+			})
+		}
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, actionHash, 123),
+		).Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided))
+		invocationID, err := anypb.New(&remoteexecution.RequestMetadata{
+			CorrelatedInvocationsId: correlatedInvocationsID,
+		})
+		require.NoError(t, err)
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, routedAction), nil).Return(
+			platform.MustNewKey("main", platformForTesting),
+			[]invocation.Key{invocation.MustNewKey(invocationID)},
+			initialSizeClassSelector,
+			nil,
+		)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassLearners[actionHash] = initialSizeClassLearner
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, 30*time.Second, time.Minute, initialSizeClassLearner)
+		clock.EXPECT().Now().Return(time.Unix(now, 0))
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		timer.EXPECT().Stop().Return(true).MaxTimes(1)
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(operationName))
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: "main",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      actionHash,
+				SizeBytes: 123,
+			},
+		})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.NoError(t, err)
+		return stream
+	}
+	requireExecuting := func(response *remoteworker.SynchronizeResponse, actionHash string) {
+		testutil.RequireEqualProto(t, &remoteexecution.Digest{
+			Hash:      actionHash,
+			SizeBytes: 123,
+		}, response.DesiredState.GetExecuting().GetActionDigest())
+	}
+	expectStreamWakeup := func() {
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		// The client is still waiting for the operation to
+		// complete when the test finishes.
+		timer.EXPECT().Stop().Return(true).MaxTimes(1)
+	}
+	expectIdleSynchronizationTimeout := func(now int64) {
+		timer := mock.NewMockTimer(ctrl)
+		timerChannel := make(chan time.Time, 1)
+		timerChannel <- time.Unix(now, 0)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, timerChannel)
+		timer.EXPECT().Stop()
+	}
+
+	// Invocation A requests a reserved worker. The first worker to
+	// execute one of its operations becomes reserved.
+	streamA1 := execute("da39a3ee5e6b4b0d3255bfef95601890afd80709", "36ebab65-3c4f-4faf-818b-2eabb4cd1b02", "A", "1", 1010)
+	clock.EXPECT().Now().Return(time.Unix(1011, 0)).Times(2)
+	expectStreamWakeup()
+	requireExecuting(synchronize("0", idleState, false), "da39a3ee5e6b4b0d3255bfef95601890afd80709")
+	_, err := streamA1.Recv()
+	require.NoError(t, err)
+
+	initialSizeClassLearners["da39a3ee5e6b4b0d3255bfef95601890afd80709"].EXPECT().Succeeded(time.Second, []uint32{0})
+	clock.EXPECT().Now().Return(time.Unix(1012, 0)).Times(3)
+	require.NotNil(t, synchronize("0", completedState("da39a3ee5e6b4b0d3255bfef95601890afd80709"), true).DesiredState.GetIdle())
+	update, err := streamA1.Recv()
+	require.NoError(t, err)
+	require.True(t, update.Done)
+
+	// Operations of other invocations should not be executed by
+	// the reserved worker, even if it is idle.
+	streamB1 := execute("4a0e4a5c1d0b4e0f9b3a4e2a4c0f8f3b6d2e1a0c", "a2d4e1a9-1c2b-4f7c-8f2a-3e5d6c7b8a90", "B", "", 1013)
+	clock.EXPECT().Now().Return(time.Unix(1014, 0))
+	expectIdleSynchronizationTimeout(1015)
+	require.NotNil(t, synchronize("0", idleState, false).DesiredState.GetIdle())
+
+	clock.EXPECT().Now().Return(time.Unix(1016, 0)).Times(2)
+	expectStreamWakeup()
+	requireExecuting(synchronize("1", idleState, false), "4a0e4a5c1d0b4e0f9b3a4e2a4c0f8f3b6d2e1a0c")
+	_, err = streamB1.Recv()
+	require.NoError(t, err)
+
+	// Additional operations of invocation A may be executed by the
+	// reserved worker.
+	streamA2 := execute("c6d0f1eb3ef1ae5b0ea6bb6bc4ff6e49b6ab1ad2", "b6b8e5b4-5d63-4a5c-9b8a-6e5b5d6bd0e7", "A", "1", 1017)
+	clock.EXPECT().Now().Return(time.Unix(1018, 0)).Times(2)
+	expectStreamWakeup()
+	requireExecuting(synchronize("0", idleState, false), "c6d0f1eb3ef1ae5b0ea6bb6bc4ff6e49b6ab1ad2")
+	_, err = streamA2.Recv()
+	require.NoError(t, err)
+
+	initialSizeClassLearners["c6d0f1eb3ef1ae5b0ea6bb6bc4ff6e49b6ab1ad2"].EXPECT().Succeeded(time.Second, []uint32{0})
+	clock.EXPECT().Now().Return(time.Unix(1019, 0)).Times(3)
+	require.NotNil(t, synchronize("0", completedState("c6d0f1eb3ef1ae5b0ea6bb6bc4ff6e49b6ab1ad2"), true).DesiredState.GetIdle())
+	update, err = streamA2.Recv()
+	require.NoError(t, err)
+	require.True(t, update.Done)
+
+	// Once no operations of invocation A have been started for the
+	// idle timeout, the reservation should be released, permitting
+	// the worker to execute operations of other invocations.
+	streamB2 := execute("0f6cf6c2e8ad1a7f9b7c1f4f2ad3a4d6e5c3b2a1", "6a1c8b6e-0e3b-4a3f-9d5f-2c4b1e8d7a6f", "B", "", 1020)
+	clock.EXPECT().Now().Return(time.Unix(1039, 0)).Times(2)
+	expectStreamWakeup()
+	requireExecuting(synchronize("0", idleState, false), "0f6cf6c2e8ad1a7f9b7c1f4f2ad3a4d6e5c3b2a1")
+	_, err = streamB2.Recv()
+	require.NoError(t, err)
+}
+
+func TestInMemoryBuildQueueClientQuota(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	clientQuota := scheduler.ClientQuota{
+		MaximumRequestsPerMinute:    2,
+		MaximumConcurrentOperations: 1,
+	}
+	buildQueueConfiguration.GetClientQuota = func(ctx context.Context, instanceName digest.InstanceName) *scheduler.ClientQuota {
+		return &clientQuota
+	}
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
+		digest.MustNewInstanceName("main"),
+		platformForTesting,
+		/* workerInvocationStickinessLimits = */ nil,
+		/* maximumQueuedBackgroundLearningOperations = */ 0,
+		/* backgroundLearningOperationPriority = */ 0,
+		/* maximumSizeClass = */ 0,
+		/* maximumQueuedOperations = */ 0,
+		/* preemptionPolicy = */ nil,
+		/* workerResourceRequirements = */ nil,
+		/* sizeClassEscalationPolicy = */ nil))
+
+	expectExecute := func(actionHash string, now int64) *mock.MockSelector {
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, actionHash, 123),
+		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+				SizeBytes: 456,
+			},
+		}, buffer.UserProvided))
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(
+			platform.MustNewKey("main", platformForTesting),
+			nil,
+			initialSizeClassSelector,
+			nil,
+		)
+		clock.EXPECT().Now().Return(time.Unix(now, 0))
+		return initialSizeClassSelector
+	}
+	execute := func(actionHash string) remoteexecution.Execution_ExecuteClient {
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: "main",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      actionHash,
+				SizeBytes: 123,
+			},
+		})
+		require.NoError(t, err)
+		return stream
+	}
+
+	// The first operation may be created, as the client has not
+	// used any of its quota yet.
+	initialSizeClassSelector := expectExecute("da39a3ee5e6b4b0d3255bfef95601890afd80709", 1001)
+	initialSizeClassLearner := mock.NewMockLearner(ctrl)
+	initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+		Return(0, 30*time.Second, time.Minute, initialSizeClassLearner)
+	timer1 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer1, nil)
+	timer1.EXPECT().Stop().Return(true).MaxTimes(1)
+	uuidGenerator.EXPECT().Call().Return(uuid.Parse("36ebab65-3c4f-4faf-818b-2eabb4cd1b02"))
+	stream1 := execute("da39a3ee5e6b4b0d3255bfef95601890afd80709")
+	update, err := stream1.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "36ebab65-3c4f-4faf-818b-2eabb4cd1b02", update.Name)
+
+	// A second operation would cause the client to exceed the
+	// maximum number of concurrent operations.
+	initialSizeClassSelector = expectExecute("c6d0f1eb3ef1ae5b0ea6bb6bc4ff6e49b6ab1ad2", 1001)
+	initialSizeClassSelector.EXPECT().Abandoned()
+	_, err = execute("c6d0f1eb3ef1ae5b0ea6bb6bc4ff6e49b6ab1ad2").Recv()
+	expectedStatus, err2 := status.New(
+		codes.ResourceExhausted,
+		"Client already has 1 queued or executing operations, which is the maximum permitted",
+	).WithDetails(&errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{{
+			Subject:     "concurrent_operations",
+			Description: "Client already has 1 queued or executing operations, which is the maximum permitted",
+		}},
 	})
+	require.NoError(t, err2)
+	testutil.RequireEqualStatus(t, expectedStatus.Err(), err)
+
+	// Both requests performed above count towards the maximum
+	// number of requests per minute. Even though a third request
+	// could be deduplicated against the existing operation, it
+	// should be rejected. The client should be instructed to retry
+	// once the quota has been replenished.
+	initialSizeClassSelector = expectExecute("da39a3ee5e6b4b0d3255bfef95601890afd80709", 1001)
+	initialSizeClassSelector.EXPECT().Abandoned()
+	_, err = execute("da39a3ee5e6b4b0d3255bfef95601890afd80709").Recv()
+	expectedStatus, err2 = status.New(
+		codes.ResourceExhausted,
+		"Client has exceeded its quota of 2 Execute() and WaitExecution() requests per minute",
+	).WithDetails(
+		&errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{{
+				Subject:     "requests_per_minute",
+				Description: "Client has exceeded its quota of 2 Execute() and WaitExecution() requests per minute",
+			}},
+		},
+		&errdetails.RetryInfo{
+			RetryDelay: &durationpb.Duration{Seconds: 30},
+		})
+	require.NoError(t, err2)
+	testutil.RequireEqualStatus(t, expectedStatus.Err(), err)
+
+	// After 30 seconds, the client may perform another request.
+	initialSizeClassSelector = expectExecute("da39a3ee5e6b4b0d3255bfef95601890afd80709", 1031)
+	initialSizeClassSelector.EXPECT().Abandoned()
+	timer2 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer2, nil)
+	timer2.EXPECT().Stop().Return(true).MaxTimes(1)
+	update, err = execute("da39a3ee5e6b4b0d3255bfef95601890afd80709").Recv()
 	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &buildqueuestate.ListInvocationChildrenResponse{
-		Children: []*buildqueuestate.InvocationChildState{
-			{
-				Id: invocationID1,
-				State: &buildqueuestate.InvocationState{
-					IdleWorkersCount:              1,
-					IdleSynchronizingWorkersCount: 1,
+	require.Equal(t, "36ebab65-3c4f-4faf-818b-2eabb4cd1b02", update.Name)
+}
+
+func TestInMemoryBuildQueueWorkerPlatformChange(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+
+	// Announce a new worker, which creates a queue for operations.
+	workerID := map[string]string{
+		"hostname": "worker123",
+		"thread":   "42",
+	}
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+		PreferBeingIdle: true,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1000},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, response)
+
+	// Let the same worker announce itself with different platform
+	// properties. This should cause it to be moved to another
+	// platform queue.
+	otherPlatform := &remoteexecution.Platform{
+		Properties: []*remoteexecution.Platform_Property{
+			{Name: "cpu", Value: "aarch64"},
+			{Name: "os", Value: "linux"},
+		},
+	}
+	clock.EXPECT().Now().Return(time.Unix(1001, 0))
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "main",
+		Platform:           otherPlatform,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+		PreferBeingIdle: true,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1001},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, response)
+
+	// The worker should no longer be part of the original platform
+	// queue, so that it doesn't get any work assigned from there.
+	clock.EXPECT().Now().Return(time.Unix(1002, 0))
+	workerState, err := buildQueue.ListWorkers(ctx, &buildqueuestate.ListWorkersRequest{
+		Filter: &buildqueuestate.ListWorkersRequest_Filter{
+			Type: &buildqueuestate.ListWorkersRequest_Filter_All{
+				All: &buildqueuestate.SizeClassQueueName{
+					PlatformQueueName: &buildqueuestate.PlatformQueueName{
+						InstanceNamePrefix: "main",
+						Platform:           platformForTesting,
+					},
 				},
 			},
 		},
-	}, invocationStates)
-
-	invocationStates, err = buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
-		InvocationName: invocationName,
-		Filter:         buildqueuestate.ListInvocationChildrenRequest_ACTIVE,
+		PageSize: 1000,
 	})
 	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &buildqueuestate.ListInvocationChildrenResponse{}, invocationStates)
+	testutil.RequireEqualProto(t, &buildqueuestate.ListWorkersResponse{
+		PaginationInfo: &buildqueuestate.PaginationInfo{},
+	}, workerState)
 
-	invocationStates, err = buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
-		InvocationName: invocationName,
-		Filter:         buildqueuestate.ListInvocationChildrenRequest_QUEUED,
+	clock.EXPECT().Now().Return(time.Unix(1003, 0))
+	workerState, err = buildQueue.ListWorkers(ctx, &buildqueuestate.ListWorkersRequest{
+		Filter: &buildqueuestate.ListWorkersRequest_Filter{
+			Type: &buildqueuestate.ListWorkersRequest_Filter_All{
+				All: &buildqueuestate.SizeClassQueueName{
+					PlatformQueueName: &buildqueuestate.PlatformQueueName{
+						InstanceNamePrefix: "main",
+						Platform:           otherPlatform,
+					},
+				},
+			},
+		},
+		PageSize: 1000,
 	})
 	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &buildqueuestate.ListInvocationChildrenResponse{}, invocationStates)
+	testutil.RequireEqualProto(t, &buildqueuestate.ListWorkersResponse{
+		Workers: []*buildqueuestate.WorkerState{
+			{
+				Id:      workerID,
+				Timeout: &timestamppb.Timestamp{Seconds: 1061},
+			},
+		},
+		PaginationInfo: &buildqueuestate.PaginationInfo{
+			TotalEntries: 1,
+		},
+	}, workerState)
+}
 
-	// Create a second worker that issues a blocking Synchronize()
-	// call against the scheduler.
-	mockClock.EXPECT().Now().Return(time.Unix(1004, 0))
-	timer4 := mock.NewMockTimer(ctrl)
-	wait5 := make(chan struct{}, 1)
-	mockClock.EXPECT().NewTimer(time.Minute).DoAndReturn(func(d time.Duration) (clock.Timer, <-chan time.Time) {
-		wait5 <- struct{}{}
-		return timer4, nil
-	})
-	var response3 *remoteworker.SynchronizeResponse
-	var err3 error
-	wait6 := make(chan struct{}, 1)
-	go func() {
-		response3, err3 = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
-			WorkerId: workerID2,
-			Platform: platformForTesting,
-			CurrentState: &remoteworker.CurrentState{
-				WorkerState: &remoteworker.CurrentState_Idle{
-					Idle: &emptypb.Empty{},
-				},
+func TestInMemoryBuildQueueWorkerResourceRequirements(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	// Register a platform queue that requires workers to have at
+	// least 1 GiB of free disk space and a load average of at most 4.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
+		digest.MustNewInstanceName("main"),
+		platformForTesting,
+		/* workerInvocationStickinessLimits = */ nil,
+		/* maximumQueuedBackgroundLearningOperations = */ 0,
+		/* backgroundLearningOperationPriority = */ 0,
+		/* maximumSizeClass = */ 0,
+		/* maximumQueuedOperations = */ 0,
+		/* preemptionPolicy = */ nil,
+		map[uint32]scheduler.WorkerResourceRequirements{
+			0: {
+				MinimumBuildDirectoryFreeBytes: 1024 * 1024 * 1024,
+				MaximumLoadAverage:             4,
 			},
-		})
-		wait6 <- struct{}{}
-	}()
-	<-wait5
+		},
+		/* sizeClassEscalationPolicy = */ nil))
 
-	// Schedule another operation. Because this operation uses a
-	// different invocation ID, it must be scheduled on the second
-	// worker. We want to keep the first worker available for
-	// actions for the same invocation ID.
-	initialSizeClassSelector2 := mock.NewMockSelector(ctrl)
-	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).Return(
-		platform.MustNewKey("", platformForTesting),
-		[]invocation.Key{invocation.MustNewKey(invocationID2)},
-		initialSizeClassSelector2,
+	// Let a client enqueue an operation.
+	contentAddressableStorage.EXPECT().Get(
+		gomock.Any(),
+		digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123),
+	).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+		CommandDigest: &remoteexecution.Digest{
+			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+			SizeBytes: 456,
+		},
+	}, buffer.UserProvided))
+	initialSizeClassSelector := mock.NewMockSelector(ctrl)
+	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(
+		platform.MustNewKey("main", platformForTesting),
+		nil,
+		initialSizeClassSelector,
 		nil,
 	)
-	initialSizeClassLearner2 := mock.NewMockLearner(ctrl)
-	initialSizeClassSelector2.EXPECT().Select([]uint32{0}).
-		Return(0, 3*time.Minute, 7*time.Minute, initialSizeClassLearner2)
-	mockClock.EXPECT().Now().Return(time.Unix(1005, 0)).Times(2)
-	timer5 := mock.NewMockTimer(ctrl)
-	mockClock.EXPECT().NewTimer(time.Minute).Return(timer5, nil)
-	uuidGenerator.EXPECT().Call().Return(uuid.Parse("e98bb734-0ec7-4cc5-bb98-bb3d5c0788c2"))
-	timer4.EXPECT().Stop()
-
-	stream2, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
-		ActionDigest: actionDigest,
+	initialSizeClassLearner := mock.NewMockLearner(ctrl)
+	initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+		Return(0, 30*time.Second, time.Minute, initialSizeClassLearner)
+	clock.EXPECT().Now().Return(time.Unix(1001, 0))
+	operationTimer := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(operationTimer, nil)
+	uuidGenerator.EXPECT().Call().Return(uuid.Parse("36ebab65-3c4f-4faf-818b-2eabb4cd1b02"))
+	stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+		InstanceName: "main",
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
 	})
 	require.NoError(t, err)
-	update, err = stream2.Recv()
+	_, err = stream.Recv()
 	require.NoError(t, err)
-	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
-		Name:     "e98bb734-0ec7-4cc5-bb98-bb3d5c0788c2",
-		Metadata: metadataExecuting,
-	})
 
-	<-wait6
-	require.NoError(t, err3)
+	// A worker that is low on disk space should not receive the
+	// operation. It should remain idle until its synchronization
+	// times out.
+	workerID := map[string]string{
+		"hostname": "worker123",
+		"thread":   "42",
+	}
+	insufficientResources := &remoteworker.WorkerResources{
+		BuildDirectoryFreeBytes: 512 * 1024 * 1024,
+		AvailableMemoryBytes:    8 * 1024 * 1024 * 1024,
+		LoadAverage:             1,
+	}
+	clock.EXPECT().Now().Return(time.Unix(1002, 0))
+	synchronizationTimer := mock.NewMockTimer(ctrl)
+	synchronizationTimerChannel := make(chan time.Time, 1)
+	synchronizationTimerChannel <- time.Unix(1062, 0)
+	synchronizationTimer.EXPECT().Stop()
+	clock.EXPECT().NewTimer(time.Minute).Return(synchronizationTimer, synchronizationTimerChannel)
+	response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+		Resources: insufficientResources,
+	})
+	require.NoError(t, err)
 	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1015},
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1062},
 		DesiredState: &remoteworker.DesiredState{
-			WorkerState: &remoteworker.DesiredState_Executing_{
-				Executing: &remoteworker.DesiredState_Executing{
-					DigestFunction:  remoteexecution.DigestFunction_SHA1,
-					ActionDigest:    actionDigest,
-					Action:          action,
-					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1005},
-				},
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
 			},
 		},
-	}, response3)
+	}, response)
 
-	// Let the second worker complete the operation.
-	mockClock.EXPECT().Now().Return(time.Unix(1006, 0)).Times(3)
-	initialSizeClassLearner2.EXPECT().Succeeded(time.Duration(0), []uint32{0})
-	timer5.EXPECT().Stop()
-	timer6 := mock.NewMockTimer(ctrl)
-	wait7 := make(chan struct{}, 1)
-	mockClock.EXPECT().NewTimer(time.Minute).DoAndReturn(func(d time.Duration) (clock.Timer, <-chan time.Time) {
-		wait7 <- struct{}{}
-		return timer6, nil
-	})
-	go func() {
-		buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
-			WorkerId: workerID2,
-			Platform: platformForTesting,
-			CurrentState: &remoteworker.CurrentState{
-				WorkerState: &remoteworker.CurrentState_Executing_{
-					Executing: &remoteworker.CurrentState_Executing{
-						ActionDigest: actionDigest,
-						ExecutionState: &remoteworker.CurrentState_Executing_Completed{
-							Completed: &remoteexecution.ExecuteResponse{
-								Result: &remoteexecution.ActionResult{},
-							},
-						},
+	// The resources of the worker should be visible through
+	// ListWorkers(), together with the fact that they are
+	// insufficient.
+	clock.EXPECT().Now().Return(time.Unix(1063, 0))
+	workerState, err := buildQueue.ListWorkers(ctx, &buildqueuestate.ListWorkersRequest{
+		Filter: &buildqueuestate.ListWorkersRequest_Filter{
+			Type: &buildqueuestate.ListWorkersRequest_Filter_All{
+				All: &buildqueuestate.SizeClassQueueName{
+					PlatformQueueName: &buildqueuestate.PlatformQueueName{
+						InstanceNamePrefix: "main",
+						Platform:           platformForTesting,
 					},
 				},
 			},
-		})
-	}()
-	<-wait7
-
-	update, err = stream2.Recv()
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
-		Name:     "e98bb734-0ec7-4cc5-bb98-bb3d5c0788c2",
-		Metadata: metadataCompleted,
-		Done:     true,
-		Result:   &longrunningpb.Operation_Response{Response: executeResponse},
-	})
-	_, err = stream1.Recv()
-	require.Equal(t, io.EOF, err)
-
-	// Both invocations should now have one worker.
-	mockClock.EXPECT().Now().Return(time.Unix(1007, 0))
-	invocationStates, err = buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
-		InvocationName: invocationName,
-		Filter:         buildqueuestate.ListInvocationChildrenRequest_ALL,
+		},
+		PageSize: 1000,
 	})
 	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &buildqueuestate.ListInvocationChildrenResponse{
-		Children: []*buildqueuestate.InvocationChildState{
-			{
-				Id: invocationID1,
-				State: &buildqueuestate.InvocationState{
-					IdleWorkersCount:              1,
-					IdleSynchronizingWorkersCount: 1,
-				},
-			},
+	testutil.RequireEqualProto(t, &buildqueuestate.ListWorkersResponse{
+		Workers: []*buildqueuestate.WorkerState{
 			{
-				Id: invocationID2,
-				State: &buildqueuestate.InvocationState{
-					IdleWorkersCount:              1,
-					IdleSynchronizingWorkersCount: 1,
-				},
+				Id:                    workerID,
+				Timeout:               &timestamppb.Timestamp{Seconds: 1122},
+				Resources:             insufficientResources,
+				InsufficientResources: true,
 			},
 		},
-	}, invocationStates)
-
-	// When submitting a third operation that uses an unknown
-	// invocation, we should execute it on the first worker, as that
-	// worker is associated with an invocation that is least
-	// recently seen.
-	initialSizeClassSelector3 := mock.NewMockSelector(ctrl)
-	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).Return(
-		platform.MustNewKey("", platformForTesting),
-		[]invocation.Key{invocation.MustNewKey(invocationID3)},
-		initialSizeClassSelector3,
-		nil,
-	)
-	initialSizeClassLearner3 := mock.NewMockLearner(ctrl)
-	initialSizeClassSelector3.EXPECT().Select([]uint32{0}).
-		Return(0, 3*time.Minute, 7*time.Minute, initialSizeClassLearner3)
-	mockClock.EXPECT().Now().Return(time.Unix(1008, 0)).Times(2)
-	timer7 := mock.NewMockTimer(ctrl)
-	mockClock.EXPECT().NewTimer(time.Minute).Return(timer7, nil)
-	uuidGenerator.EXPECT().Call().Return(uuid.Parse("a0942b25-9c84-42da-93cb-cbd16cf61917"))
-	timer3.EXPECT().Stop()
+		PaginationInfo: &buildqueuestate.PaginationInfo{
+			TotalEntries: 1,
+		},
+	}, workerState)
 
-	stream3, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
-		ActionDigest: actionDigest,
-	})
-	require.NoError(t, err)
-	update, err = stream3.Recv()
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
-		Name:     "a0942b25-9c84-42da-93cb-cbd16cf61917",
-		Metadata: metadataExecuting,
+	// Once disk space has been freed up, the worker should be
+	// able to pick up the operation.
+	clock.EXPECT().Now().Return(time.Unix(1064, 0)).Times(2)
+	operationTimer.EXPECT().Stop().Return(true)
+	clock.EXPECT().NewTimer(time.Minute).Return(nil, nil)
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+		Resources: &remoteworker.WorkerResources{
+			BuildDirectoryFreeBytes: 2 * 1024 * 1024 * 1024,
+			AvailableMemoryBytes:    8 * 1024 * 1024 * 1024,
+			LoadAverage:             1,
+		},
 	})
-
-	<-wait4
-	require.NoError(t, err2)
+	require.NoError(t, err)
 	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1018},
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1074},
 		DesiredState: &remoteworker.DesiredState{
 			WorkerState: &remoteworker.DesiredState_Executing_{
 				Executing: &remoteworker.DesiredState_Executing{
-					DigestFunction:  remoteexecution.DigestFunction_SHA1,
-					ActionDigest:    actionDigest,
-					Action:          action,
-					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1008},
+					DigestFunction: remoteexecution.DigestFunction_SHA1,
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						SizeBytes: 123,
+					},
+					Action: &remoteexecution.Action{
+						CommandDigest: &remoteexecution.Digest{
+							Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+							SizeBytes: 456,
+						},
+						Timeout: &durationpb.Duration{Seconds: 60},
+					},
+					QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1001},
 				},
 			},
 		},
-	}, response2)
+	}, response)
+	update, err := stream.Recv()
+	require.NoError(t, err)
+	metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
+		Stage: remoteexecution.ExecutionStage_EXECUTING,
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &longrunningpb.Operation{
+		Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
+		Metadata: metadata,
+	}, update)
 }
 
-func TestInMemoryBuildQueueWorkerInvocationStickinessLimit(t *testing.T) {
+func TestInMemoryBuildQueueWorkerQuarantine(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
@@ -3893,336 +8270,490 @@ func TestInMemoryBuildQueueWorkerInvocationStickinessLimit(t *testing.T) {
 	clock.EXPECT().Now().Return(time.Unix(0, 0))
 	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
 	actionRouter := mock.NewMockActionRouter(ctrl)
-	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.WorkerQuarantinePolicy = &scheduler.WorkerQuarantinePolicy{
+		WindowSize:                     1,
+		MaximumInfrastructureErrorRate: 0,
+		QuarantineDuration:             time.Minute,
+	}
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
 	executionClient := getExecutionClient(t, buildQueue)
 
-	// Register a platform queue that has a small amount of worker
-	// invocation stickiness. This should workers to prefer picking
-	// up operations belonging to the same invocation as the last
-	// executed task, if the difference in queueing time is small.
-	clock.EXPECT().Now().Return(time.Unix(1000, 0))
-	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
-		digest.EmptyInstanceName,
-		platformForTesting,
-		/* workerInvocationStickinessLimits = */ []time.Duration{3 * time.Second},
-		/* maximumQueuedBackgroundLearningOperations = */ 10,
-		/* backgroundLearningOperationPriority = */ 100,
-		/* maximumSizeClass = */ 0))
-
-	operationParameters := []struct {
-		operationName    string
-		toolInvocationID string
-	}{
-		{"716f2e32-d273-49e7-a842-82282e89d1a3", "76d8f589-8d22-4541-a612-39e4c670e531"},
-		{"0a656823-9f91-4220-9dcd-58503e62e6e8", "76d8f589-8d22-4541-a612-39e4c670e531"},
-		{"175ead9a-a095-43a5-9f2c-e3a293938ff3", "76d8f589-8d22-4541-a612-39e4c670e531"},
-		{"d354a1ea-0945-4c84-be17-ac01b8058d06", "0175ce91-1363-4cc9-8eb9-e67e82f9fdbb"},
-		{"5fe458ce-a28d-4478-9ced-7ee23a539cb6", "0175ce91-1363-4cc9-8eb9-e67e82f9fdbb"},
-		{"30e6d53c-a737-47bc-8b06-8e8f9cf7a3b3", "0175ce91-1363-4cc9-8eb9-e67e82f9fdbb"},
-		{"77d52264-940c-461b-b67f-e3e68cb5b7f0", "8414fe9e-67a9-4b0b-854a-e7f8f56cca50"},
-		{"201d6ff3-ffe3-4126-928b-cba38938ebb5", "8414fe9e-67a9-4b0b-854a-e7f8f56cca50"},
-		{"303fa653-8516-4a4f-9b6b-60c3f1f45f29", "8414fe9e-67a9-4b0b-854a-e7f8f56cca50"},
+	workerID := map[string]string{
+		"hostname": "worker123",
+		"thread":   "42",
 	}
-	type streamHandle struct {
-		timer                   *mock.MockTimer
-		stream                  remoteexecution.Execution_ExecuteClient
-		initialSizeClassLearner *mock.MockLearner
+	sizeClassQueueName := &buildqueuestate.SizeClassQueueName{
+		PlatformQueueName: &buildqueuestate.PlatformQueueName{
+			InstanceNamePrefix: "main",
+			Platform:           platformForTesting,
+		},
 	}
-	var streamHandles []streamHandle
-
-	// Schedule some actions belonging to three different invocation IDs.
-	for i, p := range operationParameters {
-		action := &remoteexecution.Action{
-			DoNotCache: true,
+	execute := func(actionHash, operationName string, now int64) (remoteexecution.Execution_ExecuteClient, *mock.MockLearner, *mock.MockTimer) {
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, actionHash, 123),
+		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
 			CommandDigest: &remoteexecution.Digest{
-				Hash:      "9b818e201c59f31954cb1e126cc67562ec545ab4",
+				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
 				SizeBytes: 456,
 			},
-		}
-		contentAddressableStorage.EXPECT().Get(
-			gomock.Any(),
-			digest.MustNewDigest("", remoteexecution.DigestFunction_SHA1, "0474d2f48968a56da4de20718d8ac23aafd80709", 123),
-		).Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided))
-		requestMetadata := &remoteexecution.RequestMetadata{
-			ToolInvocationId: p.toolInvocationID,
-		}
-		requestMetadataAny, err := anypb.New(requestMetadata)
-		require.NoError(t, err)
+		}, buffer.UserProvided))
 		initialSizeClassSelector := mock.NewMockSelector(ctrl)
-		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).Return(
-			platform.MustNewKey("", platformForTesting),
-			[]invocation.Key{invocation.MustNewKey(requestMetadataAny)},
-			initialSizeClassSelector,
-			nil,
-		)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).
+			Return(platform.MustNewKey("main", platformForTesting), nil, initialSizeClassSelector, nil)
 		initialSizeClassLearner := mock.NewMockLearner(ctrl)
 		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
-			Return(0, 30*time.Second, time.Minute, initialSizeClassLearner)
-		clock.EXPECT().Now().Return(time.Unix(1010+int64(i), 0))
+			Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
+		clock.EXPECT().Now().Return(time.Unix(now, 0))
 		timer := mock.NewMockTimer(ctrl)
 		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
-		uuidGenerator.EXPECT().Call().Return(uuid.Parse(p.operationName))
-
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(operationName))
 		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: "main",
 			ActionDigest: &remoteexecution.Digest{
-				Hash:      "0474d2f48968a56da4de20718d8ac23aafd80709",
+				Hash:      actionHash,
 				SizeBytes: 123,
 			},
 		})
 		require.NoError(t, err)
-		update, err := stream.Recv()
-		require.NoError(t, err)
-		metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
-			Stage: remoteexecution.ExecutionStage_QUEUED,
-			ActionDigest: &remoteexecution.Digest{
-				Hash:      "0474d2f48968a56da4de20718d8ac23aafd80709",
-				SizeBytes: 123,
-			},
-		})
+		_, err = stream.Recv()
 		require.NoError(t, err)
-		testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
-			Name:     p.operationName,
-			Metadata: metadata,
-		})
-
-		streamHandles = append(streamHandles, streamHandle{
-			timer:                   timer,
-			stream:                  stream,
-			initialSizeClassLearner: initialSizeClassLearner,
-		})
+		return stream, initialSizeClassLearner, timer
 	}
 
-	// Let a worker run the actions sequentially. The order in which
-	// execution takes place differs from the queueing order,
-	// because of stickiness. Every invocation is permitted to run
-	// for at least three seconds before switching to tasks
-	// belonging to another invocation.
-	for i, operationIndex := range []int{0, 1, 3, 4, 6, 7, 2, 5, 8} {
-		// Starting execution should cause the client to receive
-		// an EXECUTING message.
-		clock.EXPECT().Now().Return(time.Unix(1030+int64(i)*2, 0)).Times(2)
-		streamHandles[operationIndex].timer.EXPECT().Stop()
-		timer := mock.NewMockTimer(ctrl)
-		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
-		response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
-			WorkerId: map[string]string{
-				"hostname": "worker123",
-				"thread":   "42",
+	// Announce a new worker, which creates a queue for operations.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	_, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
 			},
-			Platform: platformForTesting,
-			CurrentState: &remoteworker.CurrentState{
-				WorkerState: &remoteworker.CurrentState_Idle{
-					Idle: &emptypb.Empty{},
-				},
+		},
+		PreferBeingIdle: true,
+	})
+	require.NoError(t, err)
+
+	// Let the worker pick up an operation.
+	stream1, learner1, timer1 := execute("da39a3ee5e6b4b0d3255bfef95601890afd80709", "b9bb6e2c-04ff-4fbd-802b-105be93a8fb7", 1001)
+	clock.EXPECT().Now().Return(time.Unix(1002, 0)).Times(2)
+	timer1.EXPECT().Stop().Return(true)
+	timer2 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer2, nil)
+	response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
 			},
-		})
-		require.NoError(t, err)
-		testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1040 + int64(i)*2},
-			DesiredState: &remoteworker.DesiredState{
-				WorkerState: &remoteworker.DesiredState_Executing_{
-					Executing: &remoteworker.DesiredState_Executing{
-						DigestFunction: remoteexecution.DigestFunction_SHA1,
-						ActionDigest: &remoteexecution.Digest{
-							Hash:      "0474d2f48968a56da4de20718d8ac23aafd80709",
-							SizeBytes: 123,
-						},
-						Action: &remoteexecution.Action{
-							CommandDigest: &remoteexecution.Digest{
-								Hash:      "9b818e201c59f31954cb1e126cc67562ec545ab4",
-								SizeBytes: 456,
-							},
-							DoNotCache: true,
-							Timeout:    &durationpb.Duration{Seconds: 60},
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, response.DesiredState.GetExecuting())
+	_, err = stream1.Recv()
+	require.NoError(t, err)
+
+	// Let the worker report that the operation failed due to an
+	// infrastructure error. As the worker exceeds the maximum
+	// infrastructure error rate, it should get quarantined.
+	learner1.EXPECT().Failed(false).Return(time.Duration(0), time.Duration(0), nil)
+	clock.EXPECT().Now().Return(time.Unix(1003, 0)).Times(3)
+	timer2.EXPECT().Stop().Return(true)
+	_, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Executing_{
+				Executing: &remoteworker.CurrentState_Executing{
+					ActionDigest: &remoteexecution.Digest{
+						Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+						SizeBytes: 123,
+					},
+					ExecutionState: &remoteworker.CurrentState_Executing_Completed{
+						Completed: &remoteexecution.ExecuteResponse{
+							Status: status.New(codes.Internal, "Failed to read from local file cache: Input/output error").Proto(),
 						},
-						QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1010 + int64(operationIndex)},
 					},
 				},
 			},
-		}, response)
+		},
+		PreferBeingIdle: true,
+	})
+	require.NoError(t, err)
+	update, err := stream1.Recv()
+	require.NoError(t, err)
+	require.True(t, update.Done)
 
-		stream := streamHandles[operationIndex].stream
-		update, err := stream.Recv()
-		require.NoError(t, err)
-		metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
-			Stage: remoteexecution.ExecutionStage_EXECUTING,
-			ActionDigest: &remoteexecution.Digest{
-				Hash:      "0474d2f48968a56da4de20718d8ac23aafd80709",
-				SizeBytes: 123,
+	clock.EXPECT().Now().Return(time.Unix(1004, 0))
+	workerState, err := buildQueue.ListWorkers(ctx, &buildqueuestate.ListWorkersRequest{
+		Filter: &buildqueuestate.ListWorkersRequest_Filter{
+			Type: &buildqueuestate.ListWorkersRequest_Filter_All{
+				All: sizeClassQueueName,
 			},
-		})
-		require.NoError(t, err)
-		operationName := operationParameters[operationIndex].operationName
-		testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
-			Name:     operationName,
-			Metadata: metadata,
-		})
+		},
+		PageSize: 1000,
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &buildqueuestate.ListWorkersResponse{
+		Workers: []*buildqueuestate.WorkerState{
+			{
+				Id:               workerID,
+				Timeout:          &timestamppb.Timestamp{Seconds: 1063},
+				QuarantinedUntil: &timestamppb.Timestamp{Seconds: 1063},
+			},
+		},
+		PaginationInfo: &buildqueuestate.PaginationInfo{
+			TotalEntries: 1,
+		},
+	}, workerState)
 
-		// Finishing execution should cause the client to
-		// receive a COMPLETED message.
-		streamHandles[operationIndex].initialSizeClassLearner.EXPECT().Succeeded(time.Duration(0), []uint32{0})
-		clock.EXPECT().Now().Return(time.Unix(1021+int64(i)*2, 0)).Times(3)
-		timer.EXPECT().Stop()
-		response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
-			WorkerId: map[string]string{
-				"hostname": "worker123",
-				"thread":   "42",
+	// While quarantined, the worker should not receive any
+	// operations.
+	stream2, _, timer3 := execute("c6d0f1eb3ef1ae5b0ea6bb6bc4ff6e49b6ab1ad2", "36ebab65-3c4f-4faf-818b-2eabb4cd1b02", 1005)
+	clock.EXPECT().Now().Return(time.Unix(1006, 0))
+	synchronizationTimer := mock.NewMockTimer(ctrl)
+	synchronizationTimerChannel := make(chan time.Time, 1)
+	synchronizationTimerChannel <- time.Unix(1066, 0)
+	synchronizationTimer.EXPECT().Stop()
+	clock.EXPECT().NewTimer(time.Minute).Return(synchronizationTimer, synchronizationTimerChannel)
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
 			},
-			Platform: platformForTesting,
-			CurrentState: &remoteworker.CurrentState{
-				WorkerState: &remoteworker.CurrentState_Executing_{
-					Executing: &remoteworker.CurrentState_Executing{
-						ActionDigest: &remoteexecution.Digest{
-							Hash:      "0474d2f48968a56da4de20718d8ac23aafd80709",
-							SizeBytes: 123,
-						},
-						ExecutionState: &remoteworker.CurrentState_Executing_Completed{
-							Completed: &remoteexecution.ExecuteResponse{
-								Result: &remoteexecution.ActionResult{},
-							},
-						},
-					},
-				},
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1066},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
 			},
-			PreferBeingIdle: true,
-		})
+		},
+	}, response)
 
-		update, err = stream.Recv()
-		require.NoError(t, err)
-		metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
-			Stage: remoteexecution.ExecutionStage_COMPLETED,
-			ActionDigest: &remoteexecution.Digest{
-				Hash:      "0474d2f48968a56da4de20718d8ac23aafd80709",
-				SizeBytes: 123,
+	// Once the quarantine has ended, the worker should be able to
+	// pick up the operation.
+	clock.EXPECT().Now().Return(time.Unix(1067, 0)).Times(2)
+	timer3.EXPECT().Stop().Return(true)
+	clock.EXPECT().NewTimer(time.Minute).Return(nil, nil)
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId:           workerID,
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
 			},
-		})
-		require.NoError(t, err)
-		executeResponse, err := anypb.New(&remoteexecution.ExecuteResponse{
-			Result: &remoteexecution.ActionResult{},
-		})
-		require.NoError(t, err)
-		testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
-			Name:     operationName,
-			Metadata: metadata,
-			Done:     true,
-			Result:   &longrunningpb.Operation_Response{Response: executeResponse},
-		})
-	}
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteexecution.Digest{
+		Hash:      "c6d0f1eb3ef1ae5b0ea6bb6bc4ff6e49b6ab1ad2",
+		SizeBytes: 123,
+	}, response.DesiredState.GetExecuting().GetActionDigest())
+	update, err = stream2.Recv()
+	require.NoError(t, err)
+	require.False(t, update.Done)
 }
 
-func TestInMemoryBuildQueueAuthorization(t *testing.T) {
+func TestInMemoryBuildQueueWorkerExpectedTerminationTime(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
 	clock := mock.NewMockClock(ctrl)
-	clock.EXPECT().Now().Return(time.Unix(0, 0)).AnyTimes()
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
 	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
 	actionRouter := mock.NewMockActionRouter(ctrl)
-	authorizer := mock.NewMockAuthorizer(ctrl)
-	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, authorizer, authorizer, authorizer)
-	beepboop := digest.MustNewInstanceName("beepboop")
-
-	t.Run("GetCapabilities-NotAuthorized", func(t *testing.T) {
-		authorizer.EXPECT().Authorize(gomock.Any(), []digest.InstanceName{beepboop}).Return([]error{status.Error(codes.PermissionDenied, "You shall not pass")})
-		_, err := buildQueue.GetCapabilities(ctx, beepboop)
-		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Authorization: You shall not pass"), err)
-	})
-
-	t.Run("GetCapabilities-Error", func(t *testing.T) {
-		authorizer.EXPECT().Authorize(gomock.Any(), []digest.InstanceName{beepboop}).Return([]error{status.Error(codes.Internal, "I fell over")})
-		_, err := buildQueue.GetCapabilities(ctx, beepboop)
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Authorization: I fell over"), err)
-	})
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
 
-	t.Run("Execute-NotAuthorized", func(t *testing.T) {
-		executionClient := getExecutionClient(t, buildQueue)
-		authorizer.EXPECT().Authorize(gomock.Any(), []digest.InstanceName{beepboop}).Return([]error{status.Error(codes.PermissionDenied, "You shall not pass")})
-		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
-			ActionDigest: &remoteexecution.Digest{
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
+		digest.MustNewInstanceName("main"),
+		platformForTesting,
+		/* workerInvocationStickinessLimits = */ nil,
+		/* maximumQueuedBackgroundLearningOperations = */ 0,
+		/* backgroundLearningOperationPriority = */ 0,
+		/* maximumSizeClass = */ 0,
+		/* maximumQueuedOperations = */ 0,
+		/* preemptionPolicy = */ nil,
+		/* workerResourceRequirements = */ nil,
+		/* sizeClassEscalationPolicy = */ nil))
+
+	// Let a client enqueue a long running operation, followed by a
+	// short running one. As operations with a higher expected
+	// duration are preferred, the long running one should normally
+	// be executed first.
+	operationTimers := map[string]*mock.MockTimer{}
+	streams := map[string]remoteexecution.Execution_ExecuteClient{}
+	for _, operation := range []struct {
+		actionHash       string
+		operationName    string
+		expectedDuration time.Duration
+		timeout          time.Duration
+	}{
+		{"da39a3ee5e6b4b0d3255bfef95601890afd80709", "36ebab65-3c4f-4faf-818b-2eabb4cd1b02", 300 * time.Second, 10 * time.Minute},
+		{"f572d396fae9206628714fb2ce00f72e94f2258f", "b4667823-9f8e-451d-a3e4-4481ec67329f", 20 * time.Second, time.Minute},
+	} {
+		contentAddressableStorage.EXPECT().Get(
+			gomock.Any(),
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, operation.actionHash, 123),
+		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
 				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
 				SizeBytes: 456,
 			},
-			InstanceName: "beepboop",
+		}, buffer.UserProvided))
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(
+			platform.MustNewKey("main", platformForTesting),
+			nil,
+			initialSizeClassSelector,
+			nil,
+		)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, operation.expectedDuration, operation.timeout, initialSizeClassLearner)
+		clock.EXPECT().Now().Return(time.Unix(1001, 0))
+		operationTimer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(operationTimer, nil)
+		operationTimers[operation.actionHash] = operationTimer
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(operation.operationName))
+		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: "main",
+			ActionDigest: &remoteexecution.Digest{
+				Hash:      operation.actionHash,
+				SizeBytes: 123,
+			},
 		})
 		require.NoError(t, err)
 		_, err = stream.Recv()
-		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Authorization: You shall not pass"), err)
-	})
+		require.NoError(t, err)
+		streams[operation.actionHash] = stream
+	}
 
-	t.Run("WaitExecution", func(t *testing.T) {
-		buildQueue.RegisterPredeclaredPlatformQueue(
-			digest.MustNewInstanceName(""),
-			&remoteexecution.Platform{},
-			/* workerInvocationStickinessLimits = */ nil,
-			/* maximumQueuedBackgroundLearningOperations = */ 0,
-			/* backgroundLearningOperationPriority = */ 0,
-			/* maximumSizeClass = */ 0)
+	// A worker that is expected to terminate in one minute should
+	// not receive the long running operation, as it is not expected
+	// to complete in time. It should receive the short running
+	// operation instead.
+	clock.EXPECT().Now().Return(time.Unix(1002, 0)).Times(2)
+	operationTimers["f572d396fae9206628714fb2ce00f72e94f2258f"].EXPECT().Stop().Return(true)
+	clock.EXPECT().NewTimer(time.Minute).Return(nil, nil)
+	response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+		ExpectedTerminationTime: &timestamppb.Timestamp{Seconds: 1062},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteexecution.Digest{
+		Hash:      "f572d396fae9206628714fb2ce00f72e94f2258f",
+		SizeBytes: 123,
+	}, response.DesiredState.GetExecuting().GetActionDigest())
+	_, err = streams["f572d396fae9206628714fb2ce00f72e94f2258f"].Recv()
+	require.NoError(t, err)
 
-		// Allow the Execute
-		authorizer.EXPECT().Authorize(gomock.Any(), []digest.InstanceName{beepboop}).Return([]error{nil})
+	// Another worker that is expected to terminate in one minute
+	// should not receive any operation.
+	clock.EXPECT().Now().Return(time.Unix(1003, 0))
+	synchronizationTimer := mock.NewMockTimer(ctrl)
+	synchronizationTimerChannel := make(chan time.Time, 1)
+	synchronizationTimerChannel <- time.Unix(1033, 0)
+	synchronizationTimer.EXPECT().Stop()
+	clock.EXPECT().NewTimer(time.Minute).Return(synchronizationTimer, synchronizationTimerChannel)
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker123",
+			"thread":   "43",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+		ExpectedTerminationTime: &timestamppb.Timestamp{Seconds: 1063},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1033},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, response)
 
-		action := &remoteexecution.Action{
-			CommandDigest: &remoteexecution.Digest{
-				Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
-				SizeBytes: 123,
+	// A worker that is not expected to terminate should receive the
+	// long running operation.
+	clock.EXPECT().Now().Return(time.Unix(1034, 0)).Times(2)
+	operationTimers["da39a3ee5e6b4b0d3255bfef95601890afd80709"].EXPECT().Stop().Return(true)
+	clock.EXPECT().NewTimer(time.Minute).Return(nil, nil)
+	response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+		WorkerId: map[string]string{
+			"hostname": "worker456",
+			"thread":   "42",
+		},
+		InstanceNamePrefix: "main",
+		Platform:           platformForTesting,
+		CurrentState: &remoteworker.CurrentState{
+			WorkerState: &remoteworker.CurrentState_Idle{
+				Idle: &emptypb.Empty{},
 			},
-			Platform: &remoteexecution.Platform{},
-		}
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteexecution.Digest{
+		Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+		SizeBytes: 123,
+	}, response.DesiredState.GetExecuting().GetActionDigest())
+	_, err = streams["da39a3ee5e6b4b0d3255bfef95601890afd80709"].Recv()
+	require.NoError(t, err)
+}
 
+func TestInMemoryBuildQueueWorkerExpectedTerminationTimePacking(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
+		digest.MustNewInstanceName("main"),
+		platformForTesting,
+		/* workerInvocationStickinessLimits = */ nil,
+		/* maximumQueuedBackgroundLearningOperations = */ 0,
+		/* backgroundLearningOperationPriority = */ 0,
+		/* maximumSizeClass = */ 0,
+		/* maximumQueuedOperations = */ 0,
+		/* preemptionPolicy = */ nil,
+		/* workerResourceRequirements = */ nil,
+		/* sizeClassEscalationPolicy = */ nil))
+
+	// Enqueue operations having a variety of expected durations,
+	// in an order that differs from the order in which they are
+	// sorted.
+	operationTimers := map[string]*mock.MockTimer{}
+	streams := map[string]remoteexecution.Execution_ExecuteClient{}
+	for _, operation := range []struct {
+		actionHash       string
+		operationName    string
+		expectedDuration time.Duration
+	}{
+		{"0000000000000000000000000000000000000020", "00000000-0000-0000-0000-000000000020", 20 * time.Second},
+		{"0000000000000000000000000000000000000300", "00000000-0000-0000-0000-000000000300", 300 * time.Second},
+		{"0000000000000000000000000000000000000010", "00000000-0000-0000-0000-000000000010", 10 * time.Second},
+		{"0000000000000000000000000000000000000040", "00000000-0000-0000-0000-000000000040", 40 * time.Second},
+		{"0000000000000000000000000000000000000050", "00000000-0000-0000-0000-000000000050", 50 * time.Second},
+	} {
 		contentAddressableStorage.EXPECT().Get(
 			gomock.Any(),
-			digest.MustNewDigest("beepboop", remoteexecution.DigestFunction_SHA1, "61c585c297d00409bd477b6b80759c94ec545ab4", 456),
-		).Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided))
-
+			digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, operation.actionHash, 123),
+		).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+				SizeBytes: 456,
+			},
+		}, buffer.UserProvided))
 		initialSizeClassSelector := mock.NewMockSelector(ctrl)
-		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).
-			Return(platform.MustNewKey("beepboop", &remoteexecution.Platform{}), nil, initialSizeClassSelector, nil)
-
-		initialSizeClassLearner := mock.NewMockLearner(ctrl)
-		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
-			Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
-
-		timer1 := mock.NewMockTimer(ctrl)
-		clock.EXPECT().NewTimer(time.Minute).Return(timer1, nil)
-
-		uuidGenerator.EXPECT().Call().Return(uuid.Parse("36ebab65-3c4f-4faf-818b-2eabb4cd1b02"))
-
-		executionClient := getExecutionClient(t, buildQueue)
-
-		// Error on the WaitExecution
-		authorizer.EXPECT().Authorize(gomock.Any(), []digest.InstanceName{beepboop}).Return([]error{status.Error(codes.PermissionDenied, "You shall not pass")})
+		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(
+			platform.MustNewKey("main", platformForTesting),
+			nil,
+			initialSizeClassSelector,
+			nil,
+		)
+		initialSizeClassLearner := mock.NewMockLearner(ctrl)
+		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+			Return(0, operation.expectedDuration, 10*time.Minute, initialSizeClassLearner)
+		clock.EXPECT().Now().Return(time.Unix(1001, 0))
+		operationTimer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(operationTimer, nil)
+		operationTimers[operation.actionHash] = operationTimer
+		uuidGenerator.EXPECT().Call().Return(uuid.Parse(operation.operationName))
 		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+			InstanceName: "main",
 			ActionDigest: &remoteexecution.Digest{
-				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
-				SizeBytes: 456,
+				Hash:      operation.actionHash,
+				SizeBytes: 123,
 			},
-			InstanceName: "beepboop",
 		})
 		require.NoError(t, err)
-		update, err := stream.Recv()
+		_, err = stream.Recv()
 		require.NoError(t, err)
-		metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
-			Stage: remoteexecution.ExecutionStage_QUEUED,
-			ActionDigest: &remoteexecution.Digest{
-				Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
-				SizeBytes: 456,
-			},
-		})
-		testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
-			Name:     "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
-			Metadata: metadata,
-		})
+		streams[operation.actionHash] = stream
+	}
 
-		stream2, err := executionClient.WaitExecution(ctx, &remoteexecution.WaitExecutionRequest{
-			Name: "36ebab65-3c4f-4faf-818b-2eabb4cd1b02",
-		})
+	// Workers that are expected to terminate soon should receive
+	// the operation having the highest expected duration that
+	// still completes in time, so that their remaining lifetime is
+	// used as efficiently as possible.
+	for _, worker := range []struct {
+		thread                  string
+		expectedTerminationTime int64
+		expectedActionHash      string
+	}{
+		{"1", 1047, "0000000000000000000000000000000000000040"},
+		{"2", 1047, "0000000000000000000000000000000000000020"},
+		{"3", 1100, "0000000000000000000000000000000000000050"},
+		{"4", 1012, "0000000000000000000000000000000000000010"},
+		{"5", 0, "0000000000000000000000000000000000000300"},
+	} {
+		clock.EXPECT().Now().Return(time.Unix(1002, 0)).Times(2)
+		operationTimers[worker.expectedActionHash].EXPECT().Stop().Return(true)
+		clock.EXPECT().NewTimer(time.Minute).Return(nil, nil)
+		request := &remoteworker.SynchronizeRequest{
+			WorkerId: map[string]string{
+				"hostname": "worker123",
+				"thread":   worker.thread,
+			},
+			InstanceNamePrefix: "main",
+			Platform:           platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Idle{
+					Idle: &emptypb.Empty{},
+				},
+			},
+		}
+		if worker.expectedTerminationTime != 0 {
+			request.ExpectedTerminationTime = &timestamppb.Timestamp{Seconds: worker.expectedTerminationTime}
+		}
+		response, err := buildQueue.Synchronize(ctx, request)
 		require.NoError(t, err)
-		_, err = stream2.Recv()
-		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Authorization: You shall not pass"), err)
-	})
+		testutil.RequireEqualProto(t, &remoteexecution.Digest{
+			Hash:      worker.expectedActionHash,
+			SizeBytes: 123,
+		}, response.DesiredState.GetExecuting().GetActionDigest())
+		_, err = streams[worker.expectedActionHash].Recv()
+		require.NoError(t, err)
+	}
 }
 
-func TestInMemoryBuildQueueNestedInvocationsSynchronization(t *testing.T) {
+func TestInMemoryBuildQueueWorkerExpectedTerminationTimeIdleWorkers(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
 	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
@@ -4233,269 +8764,558 @@ func TestInMemoryBuildQueueNestedInvocationsSynchronization(t *testing.T) {
 	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, mockClock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
 	executionClient := getExecutionClient(t, buildQueue)
 
-	mockClock.EXPECT().Now().Return(time.Unix(1000, 0))
+	// Let four workers do a blocking Synchronize() call against
+	// the scheduler. The first worker is expected to terminate
+	// soon, while the others are expected to remain available for
+	// varying amounts of time.
+	workersCtx, cancelWorkers := context.WithCancel(ctx)
+	defer cancelWorkers()
+	workerTimers := map[string]*mock.MockTimer{}
+	workerErrors := map[string]chan error{}
+	for _, worker := range []struct {
+		thread                  string
+		expectedTerminationTime int64
+	}{
+		{"1", 1030},
+		{"2", 0},
+		{"3", 1600},
+		{"4", 1100},
+	} {
+		mockClock.EXPECT().Now().Return(time.Unix(1000, 0))
+		workerTimer := mock.NewMockTimer(ctrl)
+		workerTimers[worker.thread] = workerTimer
+		waiting := make(chan struct{}, 1)
+		mockClock.EXPECT().NewTimer(time.Minute).DoAndReturn(func(d time.Duration) (clock.Timer, <-chan time.Time) {
+			waiting <- struct{}{}
+			return workerTimer, nil
+		})
+		request := &remoteworker.SynchronizeRequest{
+			WorkerId: map[string]string{
+				"hostname": "worker123",
+				"thread":   worker.thread,
+			},
+			Platform: platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Idle{
+					Idle: &emptypb.Empty{},
+				},
+			},
+		}
+		if worker.expectedTerminationTime != 0 {
+			request.ExpectedTerminationTime = &timestamppb.Timestamp{Seconds: worker.expectedTerminationTime}
+		}
+		workerError := make(chan error, 1)
+		workerErrors[worker.thread] = workerError
+		go func() {
+			_, err := buildQueue.Synchronize(workersCtx, request)
+			workerError <- err
+		}()
+		<-waiting
+	}
+
+	// Submit an operation that is expected to run for one minute.
+	// The first worker won't be able to complete it in time. Of
+	// the remaining workers, the one that is expected to terminate
+	// the soonest should receive it, so that the others remain
+	// available for operations that run longer.
+	contentAddressableStorage.EXPECT().Get(
+		gomock.Any(),
+		digest.MustNewDigest("", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123),
+	).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+		CommandDigest: &remoteexecution.Digest{
+			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+			SizeBytes: 456,
+		},
+	}, buffer.UserProvided))
+	initialSizeClassSelector := mock.NewMockSelector(ctrl)
+	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(
+		platform.MustNewKey("", platformForTesting),
+		nil,
+		initialSizeClassSelector,
+		nil,
+	)
+	initialSizeClassLearner := mock.NewMockLearner(ctrl)
+	initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+		Return(0, time.Minute, 10*time.Minute, initialSizeClassLearner)
+	mockClock.EXPECT().Now().Return(time.Unix(1001, 0)).Times(2)
+	mockClock.EXPECT().NewTimer(time.Minute).Return(nil, nil)
+	uuidGenerator.EXPECT().Call().Return(uuid.Parse("36ebab65-3c4f-4faf-818b-2eabb4cd1b02"))
+	workerTimers["4"].EXPECT().Stop()
+
+	stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	update, err := stream.Recv()
+	require.NoError(t, err)
+	metadata, err := update.Metadata.UnmarshalNew()
+	require.NoError(t, err)
+	require.Equal(t, remoteexecution.ExecutionStage_EXECUTING, metadata.(*remoteexecution.ExecuteOperationMetadata).Stage)
+	require.NoError(t, <-workerErrors["4"])
+
+	// The other workers should still be waiting.
+	for _, thread := range []string{"1", "2", "3"} {
+		mockClock.EXPECT().Now().Return(time.Unix(1002, 0))
+		workerTimers[thread].EXPECT().Stop()
+	}
+	cancelWorkers()
+	for _, thread := range []string{"1", "2", "3"} {
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "context canceled"), <-workerErrors[thread])
+	}
+}
+
+func TestInMemoryBuildQueueWorkerRejection(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfigurationForTesting, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
 	require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
-		digest.EmptyInstanceName,
+		digest.MustNewInstanceName("main"),
 		platformForTesting,
 		/* workerInvocationStickinessLimits = */ nil,
 		/* maximumQueuedBackgroundLearningOperations = */ 0,
 		/* backgroundLearningOperationPriority = */ 0,
-		/* maximumSizeClass = */ 0))
+		/* maximumSizeClass = */ 0,
+		/* maximumQueuedOperations = */ 0,
+		/* preemptionPolicy = */ nil,
+		/* workerResourceRequirements = */ nil,
+		/* sizeClassEscalationPolicy = */ nil))
 
-	// Create ten workers. Let all of them complete a task that
-	// belonged to the same correlated invocations ID, but a
-	// different tool invocation ID.
-	correlatedInvocationsID := &remoteexecution.RequestMetadata{
-		CorrelatedInvocationsId: "7e5d4921-a6d2-480e-8e2d-a4afecd7ab33",
-	}
-	correlatedInvocationsIDAny, err := anypb.New(correlatedInvocationsID)
+	// Let a client enqueue an operation.
+	contentAddressableStorage.EXPECT().Get(
+		gomock.Any(),
+		digest.MustNewDigest("main", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123),
+	).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+		CommandDigest: &remoteexecution.Digest{
+			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+			SizeBytes: 456,
+		},
+	}, buffer.UserProvided))
+	initialSizeClassSelector := mock.NewMockSelector(ctrl)
+	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(
+		platform.MustNewKey("main", platformForTesting),
+		nil,
+		initialSizeClassSelector,
+		nil,
+	)
+	initialSizeClassLearner := mock.NewMockLearner(ctrl)
+	initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+		Return(0, 30*time.Second, time.Minute, initialSizeClassLearner)
+	clock.EXPECT().Now().Return(time.Unix(1001, 0))
+	timer1 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer1, nil)
+	timer1.EXPECT().Stop().Return(true)
+	uuidGenerator.EXPECT().Call().Return(uuid.Parse("36ebab65-3c4f-4faf-818b-2eabb4cd1b02"))
+	stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+		InstanceName: "main",
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	_, err = stream.Recv()
 	require.NoError(t, err)
-	for i, p := range []struct {
-		operationName    string
-		toolInvocationID string
-	}{
-		{"716f2e32-d273-49e7-a842-82282e89d1a3", "93b8f304-4a3d-4832-91b2-e596e8a08f42"},
-		{"0a656823-9f91-4220-9dcd-58503e62e6e8", "e3658358-6325-4405-a43c-b3f81ca6e52a"},
-		{"175ead9a-a095-43a5-9f2c-e3a293938ff3", "a76ecbfc-4a3a-4822-99c5-30c0ff7dbbb8"},
-		{"d354a1ea-0945-4c84-be17-ac01b8058d06", "167fda12-0c84-4936-978f-2b54ebe3b600"},
-		{"5fe458ce-a28d-4478-9ced-7ee23a539cb6", "02c0d7d2-2e0a-4664-8030-60ad5e9026bd"},
-		{"30e6d53c-a737-47bc-8b06-8e8f9cf7a3b3", "892d2891-8344-4cb0-88b6-852106b2b329"},
-		{"77d52264-940c-461b-b67f-e3e68cb5b7f0", "1ccd7a67-0b55-4242-a4a1-942064a79341"},
-		{"201d6ff3-ffe3-4126-928b-cba38938ebb5", "cbb6455f-ede1-4314-ba63-a44f18188672"},
-		{"303fa653-8516-4a4f-9b6b-60c3f1f45f29", "3c5c6475-5ce6-42ef-b8ba-aa0a67348174"},
-		{"ca172285-ba23-42fd-a714-b582cad3bf32", "fd345278-02e8-48d8-8ff6-4f467c0cd942"},
-	} {
-		action := &remoteexecution.Action{
-			DoNotCache: true,
-			CommandDigest: &remoteexecution.Digest{
-				Hash:      "9b818e201c59f31954cb1e126cc67562ec545ab4",
-				SizeBytes: 456,
-			},
-		}
-		contentAddressableStorage.EXPECT().Get(
-			gomock.Any(),
-			digest.MustNewDigest("", remoteexecution.DigestFunction_SHA1, "0474d2f48968a56da4de20718d8ac23aafd80709", 123),
-		).Return(buffer.NewProtoBufferFromProto(action, buffer.UserProvided))
-		toolInvocationID := &remoteexecution.RequestMetadata{
-			ToolInvocationId: p.toolInvocationID,
-		}
-		toolInvocationIDAny, err := anypb.New(toolInvocationID)
-		require.NoError(t, err)
-		initialSizeClassSelector := mock.NewMockSelector(ctrl)
-		actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), testutil.EqProto(t, action), nil).Return(
-			platform.MustNewKey("", platformForTesting),
-			[]invocation.Key{
-				invocation.MustNewKey(correlatedInvocationsIDAny),
-				invocation.MustNewKey(toolInvocationIDAny),
-			},
-			initialSizeClassSelector,
-			nil,
-		)
-		initialSizeClassLearner := mock.NewMockLearner(ctrl)
-		initialSizeClassSelector.EXPECT().Select([]uint32{0}).
-			Return(0, 30*time.Second, time.Minute, initialSizeClassLearner)
-		mockClock.EXPECT().Now().Return(time.Unix(1010+int64(i), 0))
-		timer1 := mock.NewMockTimer(ctrl)
-		mockClock.EXPECT().NewTimer(time.Minute).Return(timer1, nil)
-		uuidGenerator.EXPECT().Call().Return(uuid.Parse(p.operationName))
 
-		actionDigest := &remoteexecution.Digest{
-			Hash:      "0474d2f48968a56da4de20718d8ac23aafd80709",
-			SizeBytes: 123,
-		}
-		stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
-			ActionDigest: actionDigest,
-		})
-		require.NoError(t, err)
+	requireStage := func(stage remoteexecution.ExecutionStage_Value) {
 		update, err := stream.Recv()
 		require.NoError(t, err)
-		metadata, err := anypb.New(&remoteexecution.ExecuteOperationMetadata{
-			Stage:        remoteexecution.ExecutionStage_QUEUED,
-			ActionDigest: actionDigest,
+		var metadata remoteexecution.ExecuteOperationMetadata
+		require.NoError(t, update.Metadata.UnmarshalTo(&metadata))
+		require.Equal(t, stage, metadata.Stage)
+	}
+	synchronize := func(thread string, currentState *remoteworker.CurrentState, preferBeingIdle bool) *remoteworker.SynchronizeResponse {
+		response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: map[string]string{
+				"hostname": "worker123",
+				"thread":   thread,
+			},
+			InstanceNamePrefix: "main",
+			Platform:           platformForTesting,
+			CurrentState:       currentState,
+			PreferBeingIdle:    preferBeingIdle,
 		})
 		require.NoError(t, err)
-		testutil.RequireEqualProto(t, &longrunningpb.Operation{
-			Name:     p.operationName,
-			Metadata: metadata,
-		}, update)
+		return response
+	}
+
+	// Let the first worker thread pick up the operation.
+	clock.EXPECT().Now().Return(time.Unix(1002, 0)).Times(2)
+	timer2 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer2, nil)
+	timer2.EXPECT().Stop().Return(true)
+	response := synchronize("1", &remoteworker.CurrentState{
+		WorkerState: &remoteworker.CurrentState_Idle{
+			Idle: &emptypb.Empty{},
+		},
+	}, false)
+	testutil.RequireEqualProto(t, &remoteexecution.Digest{
+		Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+		SizeBytes: 123,
+	}, response.DesiredState.GetExecuting().GetActionDigest())
+	requireStage(remoteexecution.ExecutionStage_EXECUTING)
+
+	// If the worker thread declines to execute the operation, it
+	// should be requeued. The worker thread should go back to idle.
+	clock.EXPECT().Now().Return(time.Unix(1003, 0)).Times(2)
+	timer3 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer3, nil)
+	timer3.EXPECT().Stop().Return(true)
+	response = synchronize("1", &remoteworker.CurrentState{
+		WorkerState: &remoteworker.CurrentState_Executing_{
+			Executing: &remoteworker.CurrentState_Executing{
+				ActionDigest: &remoteexecution.Digest{
+					Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+					SizeBytes: 123,
+				},
+				ExecutionState: &remoteworker.CurrentState_Executing_Rejected{
+					Rejected: &emptypb.Empty{},
+				},
+			},
+		},
+	}, true)
+	testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
+		NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1003},
+		DesiredState: &remoteworker.DesiredState{
+			WorkerState: &remoteworker.DesiredState_Idle{
+				Idle: &emptypb.Empty{},
+			},
+		},
+	}, response)
+	requireStage(remoteexecution.ExecutionStage_QUEUED)
+
+	// The operation should be handed out to another worker thread,
+	// without considering the rejection to be a failed attempt.
+	clock.EXPECT().Now().Return(time.Unix(1004, 0)).Times(2)
+	timer4 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer4, nil)
+	// The client is still waiting for the operation to complete
+	// when the test finishes.
+	timer4.EXPECT().Stop().Return(true).MaxTimes(1)
+	response = synchronize("2", &remoteworker.CurrentState{
+		WorkerState: &remoteworker.CurrentState_Idle{
+			Idle: &emptypb.Empty{},
+		},
+	}, false)
+	testutil.RequireEqualProto(t, &remoteexecution.Digest{
+		Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+		SizeBytes: 123,
+	}, response.DesiredState.GetExecuting().GetActionDigest())
+	requireStage(remoteexecution.ExecutionStage_EXECUTING)
+}
+
+func TestInMemoryBuildQueuePlatformPropertyMetrics(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
-		mockClock.EXPECT().Now().Return(time.Unix(1010+int64(i), 1)).Times(2)
-		timer1.EXPECT().Stop()
-		timer2 := mock.NewMockTimer(ctrl)
-		mockClock.EXPECT().NewTimer(time.Minute).Return(timer2, nil)
-		workerID := map[string]string{
-			"hostname": "worker123",
-			"thread":   strconv.FormatInt(int64(i), 10),
-		}
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.PlatformPropertyMetricLabels = []string{"os", "container-image"}
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+	executionClient := getExecutionClient(t, buildQueue)
+
+	workerID := map[string]string{
+		"hostname": "worker123",
+		"thread":   "42",
+	}
+	synchronizeIdle := func(preferBeingIdle bool) *remoteworker.SynchronizeResponse {
 		response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
-			WorkerId: workerID,
-			Platform: platformForTesting,
+			WorkerId:           workerID,
+			InstanceNamePrefix: "metrics",
+			Platform:           platformForTesting,
 			CurrentState: &remoteworker.CurrentState{
 				WorkerState: &remoteworker.CurrentState_Idle{
 					Idle: &emptypb.Empty{},
 				},
 			},
+			PreferBeingIdle: preferBeingIdle,
 		})
 		require.NoError(t, err)
-		testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1020 + int64(i), Nanos: 1},
-			DesiredState: &remoteworker.DesiredState{
-				WorkerState: &remoteworker.DesiredState_Executing_{
-					Executing: &remoteworker.DesiredState_Executing{
-						DigestFunction: remoteexecution.DigestFunction_SHA1,
-						ActionDigest:   actionDigest,
-						Action: &remoteexecution.Action{
-							DoNotCache: true,
-							CommandDigest: &remoteexecution.Digest{
-								Hash:      "9b818e201c59f31954cb1e126cc67562ec545ab4",
-								SizeBytes: 456,
-							},
-							Timeout: &durationpb.Duration{Seconds: 60},
-						},
-						QueuedTimestamp: &timestamppb.Timestamp{Seconds: 1010 + int64(i)},
+		return response
+	}
+	requireTasksQueued := func(value string) {
+		require.NoError(t, prom_testutil.GatherAndCompare(prometheus.DefaultGatherer, strings.NewReader(`
+# HELP buildbarn_builder_in_memory_build_queue_platform_property_tasks_queued Number of tasks that are currently queued, labeled by selected platform properties.
+# TYPE buildbarn_builder_in_memory_build_queue_platform_property_tasks_queued gauge
+buildbarn_builder_in_memory_build_queue_platform_property_tasks_queued{instance_name_prefix="metrics",platform_container_image="",platform_os="linux",size_class="0"} `+value+`
+`), "buildbarn_builder_in_memory_build_queue_platform_property_tasks_queued"))
+	}
+
+	// Announce a new worker, which creates a queue for operations.
+	// The metrics should be labeled by the values of the selected
+	// platform properties.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	synchronizeIdle(true)
+	requireTasksQueued("0")
+
+	// Queue an operation.
+	contentAddressableStorage.EXPECT().Get(
+		gomock.Any(),
+		digest.MustNewDigest("metrics", remoteexecution.DigestFunction_SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709", 123),
+	).Return(buffer.NewProtoBufferFromProto(&remoteexecution.Action{
+		CommandDigest: &remoteexecution.Digest{
+			Hash:      "61c585c297d00409bd477b6b80759c94ec545ab4",
+			SizeBytes: 456,
+		},
+	}, buffer.UserProvided))
+	initialSizeClassSelector := mock.NewMockSelector(ctrl)
+	actionRouter.EXPECT().RouteAction(gomock.Any(), gomock.Any(), gomock.Any(), nil).
+		Return(platform.MustNewKey("metrics", platformForTesting), nil, initialSizeClassSelector, nil)
+	initialSizeClassLearner := mock.NewMockLearner(ctrl)
+	initialSizeClassSelector.EXPECT().Select([]uint32{0}).
+		Return(0, 15*time.Minute, 30*time.Minute, initialSizeClassLearner)
+	clock.EXPECT().Now().Return(time.Unix(1001, 0))
+	timer1 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer1, nil)
+	uuidGenerator.EXPECT().Call().Return(uuid.Parse("b9bb6e2c-04ff-4fbd-802b-105be93a8fb7"))
+	stream, err := executionClient.Execute(ctx, &remoteexecution.ExecuteRequest{
+		InstanceName: "metrics",
+		ActionDigest: &remoteexecution.Digest{
+			Hash:      "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			SizeBytes: 123,
+		},
+	})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	requireTasksQueued("1")
+
+	// Let the worker pick up the operation, which should cause the
+	// number of queued tasks to drop back to zero.
+	clock.EXPECT().Now().Return(time.Unix(1002, 0)).Times(2)
+	timer1.EXPECT().Stop().Return(true)
+	timer2 := mock.NewMockTimer(ctrl)
+	clock.EXPECT().NewTimer(time.Minute).Return(timer2, nil)
+	// The client is still waiting for the operation to complete
+	// when the test finishes.
+	timer2.EXPECT().Stop().Return(true).MaxTimes(1)
+	response := synchronizeIdle(false)
+	require.NotNil(t, response.DesiredState.GetExecuting())
+	requireTasksQueued("0")
+}
+
+func TestInMemoryBuildQueueStatistics(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	uuidGenerator := mock.NewMockUUIDGenerator(ctrl)
+	actionRouter := mock.NewMockActionRouter(ctrl)
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.WorkerQuarantinePolicy = &scheduler.WorkerQuarantinePolicy{
+		WindowSize:                     3,
+		MaximumInfrastructureErrorRate: 0.5,
+		QuarantineDuration:             time.Minute,
+	}
+	buildQueue := scheduler.NewInMemoryBuildQueue(contentAddressableStorage, clock, uuidGenerator.Call, &buildQueueConfiguration, 10000, actionRouter, allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+
+	sizeClassQueueName := &buildqueuestate.SizeClassQueueName{
+		PlatformQueueName: &buildqueuestate.PlatformQueueName{
+			InstanceNamePrefix: "main",
+			Platform:           platformForTesting,
+		},
+	}
+	workerID1 := map[string]string{"hostname": "worker1"}
+	workerID2 := map[string]string{"hostname": "worker2"}
+	histogramUpperBounds := util.DecimalExponentialBuckets(-3, 6, 2)
+	histogramCounts := make([]uint64, len(histogramUpperBounds)+1)
+	histogramCounts[3] = 5
+	histogramCounts[len(histogramUpperBounds)] = 1
+	statistics := &schedulerstatistics.Statistics{
+		SizeClassQueues: []*schedulerstatistics.SizeClassQueue{
+			{
+				SizeClassQueueName: sizeClassQueueName,
+				TasksArrived: &schedulerstatistics.ExponentiallyDecayingSum{
+					Value:      12,
+					LastUpdate: &timestamppb.Timestamp{Seconds: 990},
+				},
+				TasksExecuted: &schedulerstatistics.ExponentiallyDecayingSum{
+					Value:      6,
+					LastUpdate: &timestamppb.Timestamp{Seconds: 980},
+				},
+				TasksExecutingDurationSeconds: &schedulerstatistics.ExponentiallyDecayingSum{
+					Value:      123.5,
+					LastUpdate: &timestamppb.Timestamp{Seconds: 980},
+				},
+				ExecutingDurationHistogram: &buildqueuestate.ExecutingDurationHistogram{
+					UpperBoundsSeconds: histogramUpperBounds,
+					Counts:             histogramCounts,
+				},
+				Workers: []*schedulerstatistics.Worker{
+					{
+						WorkerId:                       workerID1,
+						RecentTaskInfrastructureErrors: []bool{true, true, false},
+						QuarantinedUntil:               &timestamppb.Timestamp{Seconds: 1100},
+					},
+					{
+						WorkerId:                       workerID2,
+						RecentTaskInfrastructureErrors: []bool{false, true},
 					},
 				},
 			},
-		}, response)
+		},
+	}
 
-		update, err = stream.Recv()
+	t.Run("File", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "statistics")
+
+		// Reading statistics prior to them being written
+		// should yield empty statistics.
+		emptyStatistics, err := scheduler.ReadStatisticsFile(path)
 		require.NoError(t, err)
-		metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
-			Stage:        remoteexecution.ExecutionStage_EXECUTING,
-			ActionDigest: actionDigest,
-		})
+		testutil.RequireEqualProto(t, &schedulerstatistics.Statistics{}, emptyStatistics)
+
+		require.NoError(t, scheduler.WriteStatisticsFile(path, statistics))
+		readStatistics, err := scheduler.ReadStatisticsFile(path)
 		require.NoError(t, err)
-		testutil.RequireEqualProto(t, update, &longrunningpb.Operation{
-			Name:     p.operationName,
-			Metadata: metadata,
-		})
+		testutil.RequireEqualProto(t, statistics, readStatistics)
+	})
 
-		initialSizeClassLearner.EXPECT().Succeeded(time.Duration(0), []uint32{0})
-		mockClock.EXPECT().Now().Return(time.Unix(1010+int64(i), 2)).Times(3)
-		timer2.EXPECT().Stop()
-		response, err = buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
-			WorkerId: workerID,
-			Platform: platformForTesting,
+	// As the size class queue does not exist yet, statistics are
+	// only applied once the first worker synchronizes.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	require.NoError(t, buildQueue.RecoverStatistics(statistics))
+
+	for _, workerID := range []map[string]string{workerID1, workerID2} {
+		clock.EXPECT().Now().Return(time.Unix(1001, 0))
+		_, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId:           workerID,
+			InstanceNamePrefix: "main",
+			Platform:           platformForTesting,
 			CurrentState: &remoteworker.CurrentState{
-				WorkerState: &remoteworker.CurrentState_Executing_{
-					Executing: &remoteworker.CurrentState_Executing{
-						ActionDigest: actionDigest,
-						ExecutionState: &remoteworker.CurrentState_Executing_Completed{
-							Completed: &remoteexecution.ExecuteResponse{
-								Result: &remoteexecution.ActionResult{},
-							},
-						},
-					},
-				},
-			},
-			PreferBeingIdle: true,
-		})
-		require.NoError(t, err)
-		testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-			NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1010 + int64(i), Nanos: 2},
-			DesiredState: &remoteworker.DesiredState{
-				WorkerState: &remoteworker.DesiredState_Idle{
+				WorkerState: &remoteworker.CurrentState_Idle{
 					Idle: &emptypb.Empty{},
 				},
 			},
-		}, response)
-
-		update, err = stream.Recv()
-		require.NoError(t, err)
-		metadata, err = anypb.New(&remoteexecution.ExecuteOperationMetadata{
-			Stage:        remoteexecution.ExecutionStage_COMPLETED,
-			ActionDigest: actionDigest,
-		})
-		require.NoError(t, err)
-		executeResponse, err := anypb.New(&remoteexecution.ExecuteResponse{
-			Result: &remoteexecution.ActionResult{},
+			PreferBeingIdle: true,
 		})
 		require.NoError(t, err)
-		testutil.RequireEqualProto(t, &longrunningpb.Operation{
-			Name:     p.operationName,
-			Metadata: metadata,
-			Done:     true,
-			Result:   &longrunningpb.Operation_Response{Response: executeResponse},
-		}, update)
 	}
 
-	// At the top level, we should see an invocation with ten children.
-	invocationName := &buildqueuestate.InvocationName{
-		SizeClassQueueName: &buildqueuestate.SizeClassQueueName{
-			PlatformQueueName: &buildqueuestate.PlatformQueueName{
-				Platform: platformForTesting,
+	// The first worker should still be quarantined.
+	clock.EXPECT().Now().Return(time.Unix(1002, 0))
+	workerState, err := buildQueue.ListWorkers(ctx, &buildqueuestate.ListWorkersRequest{
+		Filter: &buildqueuestate.ListWorkersRequest_Filter{
+			Type: &buildqueuestate.ListWorkersRequest_Filter_All{
+				All: sizeClassQueueName,
 			},
 		},
-	}
-	mockClock.EXPECT().Now().Return(time.Unix(1030, 0))
-	invocationStates, err := buildQueue.ListInvocationChildren(ctx, &buildqueuestate.ListInvocationChildrenRequest{
-		InvocationName: invocationName,
-		Filter:         buildqueuestate.ListInvocationChildrenRequest_ALL,
+		PageSize: 1000,
 	})
 	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &buildqueuestate.ListInvocationChildrenResponse{
-		Children: []*buildqueuestate.InvocationChildState{
+	testutil.RequireEqualProto(t, &buildqueuestate.ListWorkersResponse{
+		Workers: []*buildqueuestate.WorkerState{
 			{
-				Id: correlatedInvocationsIDAny,
-				State: &buildqueuestate.InvocationState{
-					IdleWorkersCount: 10,
-					ChildrenCount:    10,
-				},
+				Id:                              workerID1,
+				Timeout:                         &timestamppb.Timestamp{Seconds: 1061},
+				RecentTasksCount:                3,
+				RecentInfrastructureErrorsCount: 2,
+				QuarantinedUntil:                &timestamppb.Timestamp{Seconds: 1100},
+			},
+			{
+				Id:                              workerID2,
+				Timeout:                         &timestamppb.Timestamp{Seconds: 1061},
+				RecentTasksCount:                2,
+				RecentInfrastructureErrorsCount: 1,
 			},
 		},
-	}, invocationStates)
+		PaginationInfo: &buildqueuestate.PaginationInfo{
+			TotalEntries: 2,
+		},
+	}, workerState)
 
-	// Let all ten workers perform a blocking Synchronize() call.
-	allWorkersWait := make(chan struct{})
-	timers := make([]*mock.MockTimer, 0, 10)
-	wakeups := make([]chan<- time.Time, 0, 10)
-	for iIter := 0; iIter < 10; iIter++ {
-		i := iIter
-		mockClock.EXPECT().Now().Return(time.Unix(1040+int64(i), 0))
-		timer := mock.NewMockTimer(ctrl)
-		wakeup := make(chan time.Time, 1)
-		timerCreationWait := make(chan struct{})
-		mockClock.EXPECT().NewTimer(time.Minute).DoAndReturn(func(d time.Duration) (clock.Timer, <-chan time.Time) {
-			timerCreationWait <- struct{}{}
-			return timer, wakeup
-		})
+	// The histogram should be reported through the BuildQueueState
+	// service.
+	clock.EXPECT().Now().Return(time.Unix(1003, 0))
+	platformQueues, err := buildQueue.ListPlatformQueues(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, platformQueues.PlatformQueues, 1)
+	require.Len(t, platformQueues.PlatformQueues[0].SizeClassQueues, 1)
+	testutil.RequireEqualProto(t, &buildqueuestate.ExecutingDurationHistogram{
+		UpperBoundsSeconds: histogramUpperBounds,
+		Counts:             histogramCounts,
+	}, platformQueues.PlatformQueues[0].SizeClassQueues[0].ExecutingDurationHistogram)
 
-		go func() {
-			response, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
-				WorkerId: map[string]string{
-					"hostname": "worker123",
-					"thread":   strconv.FormatInt(int64(i), 10),
-				},
-				Platform: platformForTesting,
-				CurrentState: &remoteworker.CurrentState{
-					WorkerState: &remoteworker.CurrentState_Idle{
-						Idle: &emptypb.Empty{},
-					},
-				},
-			})
+	// As no tasks have been executed, the statistics returned by
+	// the scheduler should be identical to the ones it recovered.
+	clock.EXPECT().Now().Return(time.Unix(1004, 0))
+	testutil.RequireEqualProto(t, statistics, buildQueue.GetStatistics())
+
+	t.Run("WritePeriodically", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "statistics")
+		errorLogger := mock.NewMockErrorLogger(ctrl)
+		ctxWithCancel, cancel := context.WithCancel(ctx)
+
+		// Statistics should be written every time the timer
+		// fires, without waiting for the scheduler to shut down.
+		timer1 := mock.NewMockTimer(ctrl)
+		timerChannel1 := make(chan time.Time, 1)
+		timerChannel1 <- time.Unix(1005, 0)
+		clock.EXPECT().NewTimer(30*time.Second).Return(timer1, timerChannel1)
+		clock.EXPECT().Now().Return(time.Unix(1005, 0))
+
+		timer2 := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(30*time.Second).Return(timer2, nil).Do(func(d time.Duration) {
+			readStatistics, err := scheduler.ReadStatisticsFile(path)
 			require.NoError(t, err)
-			testutil.RequireEqualProto(t, &remoteworker.SynchronizeResponse{
-				NextSynchronizationAt: &timestamppb.Timestamp{Seconds: 1100 + int64(i)},
-				DesiredState: &remoteworker.DesiredState{
-					WorkerState: &remoteworker.DesiredState_Idle{
-						Idle: &emptypb.Empty{},
-					},
-				},
-			}, response)
+			testutil.RequireEqualProto(t, statistics, readStatistics)
+			require.NoError(t, os.Remove(path))
 
-			allWorkersWait <- struct{}{}
-		}()
+			cancel()
+		})
 
-		<-timerCreationWait
-		timers = append(timers, timer)
-		wakeups = append(wakeups, wakeup)
-	}
+		// Statistics should be written one final time when the
+		// context is canceled.
+		timer2.EXPECT().Stop().Return(true)
+		clock.EXPECT().Now().Return(time.Unix(1006, 0))
 
-	// Wake up all ten workers without receiving any new work. This
-	// should not cause any crashes.
-	for i := 0; i < 10; i++ {
-		timers[i].EXPECT().Stop()
-		wakeups[i] <- time.Unix(1100+int64(i), 0)
-		<-allWorkersWait
-	}
+		require.NoError(t, buildQueue.WriteStatisticsFilePeriodically(ctxWithCancel, path, 30*time.Second, errorLogger))
+		readStatistics, err := scheduler.ReadStatisticsFile(path)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, statistics, readStatistics)
+	})
+
+	t.Run("WritePeriodicallyFailure", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nonexistent", "statistics")
+		errorLogger := mock.NewMockErrorLogger(ctrl)
+		ctxWithCancel, cancel := context.WithCancel(ctx)
+
+		// Failures to write statistics periodically should
+		// only be logged.
+		timer1 := mock.NewMockTimer(ctrl)
+		timerChannel1 := make(chan time.Time, 1)
+		timerChannel1 <- time.Unix(1007, 0)
+		clock.EXPECT().NewTimer(30*time.Second).Return(timer1, timerChannel1)
+		clock.EXPECT().Now().Return(time.Unix(1007, 0))
+		errorLogger.EXPECT().Log(gomock.Any()).Do(func(err error) {
+			require.Equal(t, codes.Internal, status.Code(err))
+			require.Contains(t, status.Convert(err).Message(), "Failed to write statistics to")
+
+			cancel()
+		})
+
+		// Failing to write statistics upon shutdown should be
+		// reported, as the statistics would otherwise be lost.
+		timer2 := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(30*time.Second).Return(timer2, nil)
+		timer2.EXPECT().Stop().Return(true)
+		clock.EXPECT().Now().Return(time.Unix(1008, 0))
+
+		err := buildQueue.WriteStatisticsFilePeriodically(ctxWithCancel, path, 30*time.Second, errorLogger)
+		require.Equal(t, codes.Internal, status.Code(err))
+	})
 }
@@ -2,7 +2,10 @@ package initialsizeclass
 
 import (
 	pb "github.com/buildbarn/bb-remote-execution/pkg/proto/configuration/scheduler"
+	initialsizeclass_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/initialsizeclass"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
 	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/grpc"
 	"github.com/buildbarn/bb-storage/pkg/random"
 	"github.com/buildbarn/bb-storage/pkg/util"
 
@@ -12,7 +15,7 @@ import (
 
 // NewAnalyzerFromConfiguration creates a new initial size class
 // analyzer based on options provided in a configuration file.
-func NewAnalyzerFromConfiguration(configuration *pb.InitialSizeClassAnalyzerConfiguration, previousExecutionStatsStore PreviousExecutionStatsStore) (Analyzer, error) {
+func NewAnalyzerFromConfiguration(configuration *pb.InitialSizeClassAnalyzerConfiguration, previousExecutionStatsStore PreviousExecutionStatsStore, grpcClientFactory grpc.ClientFactory, contentAddressableStorage blobstore.BlobAccess, maximumMessageSizeBytes int) (Analyzer, error) {
 	if configuration == nil {
 		return nil, status.Error(codes.InvalidArgument, "No initial size class analyzer configuration provided")
 	}
@@ -29,6 +32,7 @@ func NewAnalyzerFromConfiguration(configuration *pb.InitialSizeClassAnalyzerConf
 		defaultExecutionTimeout.AsDuration(),
 		maximumExecutionTimeout.AsDuration())
 
+	analyzer := NewFallbackAnalyzer(actionTimeoutExtractor)
 	if fdConfiguration := configuration.FeedbackDriven; fdConfiguration != nil {
 		if previousExecutionStatsStore == nil {
 			return nil, status.Error(codes.InvalidArgument, "Feedback driven analysis can only be enabled if an Initial Size Class Cache (ISCC) is configured")
@@ -51,14 +55,37 @@ func NewAnalyzerFromConfiguration(configuration *pb.InitialSizeClassAnalyzerConf
 				pageRankConfiguration.MaximumConvergenceError)
 		}
 
-		return NewFeedbackDrivenAnalyzer(
+		analyzer = NewFeedbackDrivenAnalyzer(
 			previousExecutionStatsStore,
 			random.NewFastSingleThreadedGenerator(),
 			clock.SystemClock,
 			actionTimeoutExtractor,
 			failureCacheDuration.AsDuration(),
 			strategyCalculator,
-			int(fdConfiguration.HistorySize)), nil
+			int(fdConfiguration.HistorySize))
 	}
-	return NewFallbackAnalyzer(actionTimeoutExtractor), nil
+
+	if predictorConfiguration := configuration.Predictor; predictorConfiguration != nil {
+		predictionTimeout := predictorConfiguration.Timeout
+		if err := predictionTimeout.CheckValid(); err != nil {
+			return nil, util.StatusWrap(err, "Invalid prediction timeout")
+		}
+		client, err := grpcClientFactory.NewClientFromConfiguration(predictorConfiguration.Endpoint)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to create predictor RPC client")
+		}
+		var commandContentAddressableStorage blobstore.BlobAccess
+		if predictorConfiguration.IncludeCommand {
+			commandContentAddressableStorage = contentAddressableStorage
+		}
+		analyzer = NewPredictingAnalyzer(
+			analyzer,
+			initialsizeclass_pb.NewPredictorClient(client),
+			commandContentAddressableStorage,
+			maximumMessageSizeBytes,
+			actionTimeoutExtractor,
+			predictionTimeout.AsDuration(),
+			util.DefaultErrorLogger)
+	}
+	return analyzer, nil
 }
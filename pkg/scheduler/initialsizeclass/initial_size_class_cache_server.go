@@ -0,0 +1,92 @@
+package initialsizeclass
+
+import (
+	"context"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/initialsizeclasscache"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/proto/iscc"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type initialSizeClassCacheServer struct {
+	initialSizeClassCache     blobstore.BlobAccess
+	contentAddressableStorage blobstore.BlobAccess
+	maximumMessageSizeBytes   int
+}
+
+// NewInitialSizeClassCacheServer creates a gRPC server for the
+// InitialSizeClassCache service, which permits inspecting statistics
+// on previous executions of actions stored in the Initial Size Class
+// Cache (ISCC).
+//
+// This service reads statistics from the ISCC directly, as opposed to
+// going through PreviousExecutionStatsStore. Accessing handles returned
+// by the latter requires holding the lock of the build queue.
+func NewInitialSizeClassCacheServer(initialSizeClassCache, contentAddressableStorage blobstore.BlobAccess, maximumMessageSizeBytes int) initialsizeclasscache.InitialSizeClassCacheServer {
+	return &initialSizeClassCacheServer{
+		initialSizeClassCache:     initialSizeClassCache,
+		contentAddressableStorage: contentAddressableStorage,
+		maximumMessageSizeBytes:   maximumMessageSizeBytes,
+	}
+}
+
+func (s *initialSizeClassCacheServer) getReducedActionDigest(ctx context.Context, instanceName digest.InstanceName, request *initialsizeclasscache.GetPreviousExecutionStatsRequest) (digest.Digest, error) {
+	switch d := request.Digest.(type) {
+	case *initialsizeclasscache.GetPreviousExecutionStatsRequest_ActionDigest:
+		digestFunction, err := instanceName.GetDigestFunction(request.DigestFunction, len(d.ActionDigest.GetHash()))
+		if err != nil {
+			return digest.BadDigest, err
+		}
+		actionDigest, err := digestFunction.NewDigestFromProto(d.ActionDigest)
+		if err != nil {
+			return digest.BadDigest, util.StatusWrap(err, "Invalid action digest")
+		}
+		actionMessage, err := s.contentAddressableStorage.Get(ctx, actionDigest).ToProto(&remoteexecution.Action{}, s.maximumMessageSizeBytes)
+		if err != nil {
+			return digest.BadDigest, util.StatusWrap(err, "Failed to obtain action")
+		}
+		reducedActionDigest, err := blobstore.GetReducedActionDigest(digestFunction, actionMessage.(*remoteexecution.Action))
+		if err != nil {
+			return digest.BadDigest, util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to obtain reduced action digest")
+		}
+		return reducedActionDigest, nil
+	case *initialsizeclasscache.GetPreviousExecutionStatsRequest_ReducedActionDigest:
+		digestFunction, err := instanceName.GetDigestFunction(request.DigestFunction, len(d.ReducedActionDigest.GetHash()))
+		if err != nil {
+			return digest.BadDigest, err
+		}
+		reducedActionDigest, err := digestFunction.NewDigestFromProto(d.ReducedActionDigest)
+		if err != nil {
+			return digest.BadDigest, util.StatusWrap(err, "Invalid reduced action digest")
+		}
+		return reducedActionDigest, nil
+	default:
+		return digest.BadDigest, status.Error(codes.InvalidArgument, "No action digest or reduced action digest provided")
+	}
+}
+
+func (s *initialSizeClassCacheServer) GetPreviousExecutionStats(ctx context.Context, request *initialsizeclasscache.GetPreviousExecutionStatsRequest) (*initialsizeclasscache.GetPreviousExecutionStatsResponse, error) {
+	instanceName, err := digest.NewInstanceName(request.InstanceName)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Invalid instance name %#v", request.InstanceName)
+	}
+	reducedActionDigest, err := s.getReducedActionDigest(ctx, instanceName, request)
+	if err != nil {
+		return nil, err
+	}
+
+	previousExecutionStats, err := s.initialSizeClassCache.Get(ctx, reducedActionDigest).ToProto(&iscc.PreviousExecutionStats{}, s.maximumMessageSizeBytes)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to obtain previous execution stats for reduced action digest %#v", reducedActionDigest.String())
+	}
+	return &initialsizeclasscache.GetPreviousExecutionStatsResponse{
+		ReducedActionDigest:    reducedActionDigest.GetProto(),
+		PreviousExecutionStats: previousExecutionStats.(*iscc.PreviousExecutionStats),
+	}, nil
+}
@@ -0,0 +1,110 @@
+package initialsizeclass_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/initialsizeclasscache"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/initialsizeclass"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/proto/iscc"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestInitialSizeClassCacheServer(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	initialSizeClassCache := mock.NewMockBlobAccess(ctrl)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	server := initialsizeclass.NewInitialSizeClassCacheServer(initialSizeClassCache, contentAddressableStorage, 10000)
+
+	exampleStats := &iscc.PreviousExecutionStats{
+		SizeClasses: map[uint32]*iscc.PerSizeClassStats{
+			1: {
+				PreviousExecutions: []*iscc.PreviousExecution{
+					{Outcome: &iscc.PreviousExecution_Failed{Failed: &emptypb.Empty{}}},
+					{Outcome: &iscc.PreviousExecution_TimedOut{TimedOut: &durationpb.Duration{Seconds: 60}}},
+				},
+			},
+			8: {
+				PreviousExecutions: []*iscc.PreviousExecution{
+					{Outcome: &iscc.PreviousExecution_Succeeded{Succeeded: &durationpb.Duration{Seconds: 42}}},
+				},
+			},
+		},
+	}
+
+	t.Run("NoDigest", func(t *testing.T) {
+		_, err := server.GetPreviousExecutionStats(ctx, &initialsizeclasscache.GetPreviousExecutionStatsRequest{
+			InstanceName: "hello",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "No action digest or reduced action digest provided"), err)
+	})
+
+	t.Run("ReducedActionDigestNotFound", func(t *testing.T) {
+		initialSizeClassCache.EXPECT().Get(ctx, digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "a1c87e5d1f0e7beb8dc5b1b1f9c3a3a8", 144)).
+			Return(buffer.NewBufferFromError(status.Error(codes.NotFound, "Object not found")))
+
+		_, err := server.GetPreviousExecutionStats(ctx, &initialsizeclasscache.GetPreviousExecutionStatsRequest{
+			InstanceName:   "hello",
+			DigestFunction: remoteexecution.DigestFunction_MD5,
+			Digest: &initialsizeclasscache.GetPreviousExecutionStatsRequest_ReducedActionDigest{
+				ReducedActionDigest: &remoteexecution.Digest{
+					Hash:      "a1c87e5d1f0e7beb8dc5b1b1f9c3a3a8",
+					SizeBytes: 144,
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Failed to obtain previous execution stats for reduced action digest \"3-a1c87e5d1f0e7beb8dc5b1b1f9c3a3a8-144-hello\": Object not found"), err)
+	})
+
+	t.Run("ActionDigest", func(t *testing.T) {
+		// When an action digest is provided, the action should be
+		// loaded from the CAS to compute its reduced action digest.
+		exampleAction := &remoteexecution.Action{
+			CommandDigest: &remoteexecution.Digest{
+				Hash:      "bf4d2b56ee892c3fc862cdb863a6c2f4",
+				SizeBytes: 720,
+			},
+			InputRootDigest: &remoteexecution.Digest{
+				Hash:      "9a0be105e682022830da33578b909521",
+				SizeBytes: 951,
+			},
+		}
+		contentAddressableStorage.EXPECT().Get(ctx, digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8a5a6c5e0d1a4b6e6b24e7e1b26f4e13", 123)).
+			Return(buffer.NewProtoBufferFromProto(exampleAction, buffer.UserProvided))
+		reducedActionDigest, err := blobstore.GetReducedActionDigest(
+			digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5),
+			exampleAction)
+		require.NoError(t, err)
+		initialSizeClassCache.EXPECT().Get(ctx, reducedActionDigest).
+			Return(buffer.NewProtoBufferFromProto(exampleStats, buffer.UserProvided))
+
+		response, err := server.GetPreviousExecutionStats(ctx, &initialsizeclasscache.GetPreviousExecutionStatsRequest{
+			InstanceName:   "hello",
+			DigestFunction: remoteexecution.DigestFunction_MD5,
+			Digest: &initialsizeclasscache.GetPreviousExecutionStatsRequest_ActionDigest{
+				ActionDigest: &remoteexecution.Digest{
+					Hash:      "8a5a6c5e0d1a4b6e6b24e7e1b26f4e13",
+					SizeBytes: 123,
+				},
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &initialsizeclasscache.GetPreviousExecutionStatsResponse{
+			ReducedActionDigest:    reducedActionDigest.GetProto(),
+			PreviousExecutionStats: exampleStats,
+		}, response)
+	})
+}
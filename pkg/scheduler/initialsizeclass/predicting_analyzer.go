@@ -0,0 +1,173 @@
+package initialsizeclass
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	initialsizeclass_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/initialsizeclass"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	predictingAnalyzerPrometheusMetrics sync.Once
+
+	predictingAnalyzerPredictionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "initial_size_class",
+			Name:      "predicting_analyzer_predictions_total",
+			Help:      "Number of times an external predictor was consulted to select the initial size class of an action.",
+		},
+		[]string{"result"})
+	predictingAnalyzerPredictionsPredicted    = predictingAnalyzerPredictionsTotal.WithLabelValues("Predicted")
+	predictingAnalyzerPredictionsNoPrediction = predictingAnalyzerPredictionsTotal.WithLabelValues("NoPrediction")
+	predictingAnalyzerPredictionsFailed       = predictingAnalyzerPredictionsTotal.WithLabelValues("Failed")
+)
+
+type predictingAnalyzer struct {
+	base                      Analyzer
+	predictor                 initialsizeclass_pb.PredictorClient
+	contentAddressableStorage blobstore.BlobAccess
+	maximumMessageSizeBytes   int
+	actionTimeoutExtractor    *ActionTimeoutExtractor
+	predictionTimeout         time.Duration
+	errorLogger               util.ErrorLogger
+}
+
+// NewPredictingAnalyzer creates a decorator for Analyzer that consults
+// an external predictor to select the size class on which actions are
+// initially run. This permits the use of custom models (e.g., ones
+// trained on mnemonics and input root sizes), as opposed to only
+// relying on the analysis that is performed by FeedbackDrivenAnalyzer.
+//
+// If the predictor fails or has no opinion on an action, the base
+// Analyzer is used. If contentAddressableStorage is not nil, the
+// Command message of the action is provided to the predictor as well.
+func NewPredictingAnalyzer(base Analyzer, predictor initialsizeclass_pb.PredictorClient, contentAddressableStorage blobstore.BlobAccess, maximumMessageSizeBytes int, actionTimeoutExtractor *ActionTimeoutExtractor, predictionTimeout time.Duration, errorLogger util.ErrorLogger) Analyzer {
+	predictingAnalyzerPrometheusMetrics.Do(func() {
+		prometheus.MustRegister(predictingAnalyzerPredictionsTotal)
+	})
+
+	return &predictingAnalyzer{
+		base:                      base,
+		predictor:                 predictor,
+		contentAddressableStorage: contentAddressableStorage,
+		maximumMessageSizeBytes:   maximumMessageSizeBytes,
+		actionTimeoutExtractor:    actionTimeoutExtractor,
+		predictionTimeout:         predictionTimeout,
+		errorLogger:               errorLogger,
+	}
+}
+
+func (a *predictingAnalyzer) predict(ctx context.Context, digestFunction digest.Function, action *remoteexecution.Action) (*initialsizeclass_pb.PredictResponse_Prediction, error) {
+	reducedActionDigest, err := blobstore.GetReducedActionDigest(digestFunction, action)
+	if err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to obtain reduced action digest")
+	}
+	request := &initialsizeclass_pb.PredictRequest{
+		InstanceName:        digestFunction.GetInstanceName().String(),
+		DigestFunction:      digestFunction.GetEnumValue(),
+		Action:              action,
+		ReducedActionDigest: reducedActionDigest.GetProto(),
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, a.predictionTimeout)
+	defer cancel()
+	if a.contentAddressableStorage != nil {
+		commandDigest, err := digestFunction.NewDigestFromProto(action.CommandDigest)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to extract digest for command")
+		}
+		commandMessage, err := a.contentAddressableStorage.Get(ctxWithTimeout, commandDigest).ToProto(&remoteexecution.Command{}, a.maximumMessageSizeBytes)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to obtain command")
+		}
+		request.Command = commandMessage.(*remoteexecution.Command)
+	}
+
+	response, err := a.predictor.Predict(ctxWithTimeout, request)
+	if err != nil {
+		return nil, err
+	}
+	prediction := response.Prediction
+	if prediction == nil {
+		return nil, nil
+	}
+	if err := prediction.ExpectedDuration.CheckValid(); prediction.ExpectedDuration != nil && err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid expected duration")
+	}
+	if err := prediction.Timeout.CheckValid(); prediction.Timeout != nil && err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid timeout")
+	}
+	return prediction, nil
+}
+
+func (a *predictingAnalyzer) Analyze(ctx context.Context, digestFunction digest.Function, action *remoteexecution.Action) (Selector, error) {
+	timeout, err := a.actionTimeoutExtractor.ExtractTimeout(action)
+	if err != nil {
+		return nil, err
+	}
+
+	prediction, err := a.predict(ctx, digestFunction, action)
+	if err != nil {
+		predictingAnalyzerPredictionsFailed.Inc()
+		a.errorLogger.Log(util.StatusWrap(err, "Failed to obtain initial size class prediction"))
+		return a.base.Analyze(ctx, digestFunction, action)
+	}
+	if prediction == nil {
+		predictingAnalyzerPredictionsNoPrediction.Inc()
+		return a.base.Analyze(ctx, digestFunction, action)
+	}
+	predictingAnalyzerPredictionsPredicted.Inc()
+
+	s := &predictingSelector{
+		sizeClass:        prediction.SizeClass,
+		expectedDuration: timeout,
+		smallerTimeout:   timeout,
+		originalTimeout:  timeout,
+	}
+	if prediction.ExpectedDuration != nil {
+		if d := prediction.ExpectedDuration.AsDuration(); d > 0 && d < timeout {
+			s.expectedDuration = d
+		}
+	}
+	if prediction.Timeout != nil {
+		if d := prediction.Timeout.AsDuration(); d > 0 && d < timeout {
+			s.smallerTimeout = d
+		}
+	}
+	return s, nil
+}
+
+type predictingSelector struct {
+	sizeClass        uint32
+	expectedDuration time.Duration
+	smallerTimeout   time.Duration
+	originalTimeout  time.Duration
+}
+
+func (s *predictingSelector) Select(sizeClasses []uint32) (int, time.Duration, time.Duration, Learner) {
+	// Pick the smallest size class that is at least as large as the
+	// one that is predicted.
+	for i, sizeClass := range sizeClasses[:len(sizeClasses)-1] {
+		if sizeClass >= s.sizeClass {
+			expectedDuration := s.expectedDuration
+			if expectedDuration > s.smallerTimeout {
+				expectedDuration = s.smallerTimeout
+			}
+			return i, expectedDuration, s.smallerTimeout, smallerFallbackLearner{
+				timeout: s.originalTimeout,
+			}
+		}
+	}
+	return len(sizeClasses) - 1, s.expectedDuration, s.originalTimeout, largestFallbackLearner{}
+}
+
+func (predictingSelector) Abandoned() {}
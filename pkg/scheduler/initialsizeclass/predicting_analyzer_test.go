@@ -0,0 +1,138 @@
+package initialsizeclass_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	initialsizeclass_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/initialsizeclass"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/initialsizeclass"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestPredictingAnalyzer(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseAnalyzer := mock.NewMockAnalyzer(ctrl)
+	predictor := mock.NewMockPredictorClient(ctrl)
+	errorLogger := mock.NewMockErrorLogger(ctrl)
+	analyzer := initialsizeclass.NewPredictingAnalyzer(
+		baseAnalyzer,
+		predictor,
+		/* contentAddressableStorage = */ nil,
+		/* maximumMessageSizeBytes = */ 10000,
+		initialsizeclass.NewActionTimeoutExtractor(
+			30*time.Minute,
+			60*time.Minute),
+		time.Second,
+		errorLogger)
+
+	exampleDigestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_MD5)
+	exampleAction := &remoteexecution.Action{
+		CommandDigest: &remoteexecution.Digest{
+			Hash:      "bf4d2b56ee892c3fc862cdb863a6c2f4",
+			SizeBytes: 720,
+		},
+		InputRootDigest: &remoteexecution.Digest{
+			Hash:      "9a0be105e682022830da33578b909521",
+			SizeBytes: 951,
+		},
+		Timeout: &durationpb.Duration{Seconds: 300},
+	}
+
+	t.Run("PredictorFailure", func(t *testing.T) {
+		// Failures of the predictor should be logged, and cause
+		// the base analyzer to be used.
+		predictor.EXPECT().Predict(gomock.Any(), gomock.Any()).
+			Return(nil, status.Error(codes.Unavailable, "Connection refused"))
+		errorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.Unavailable, "Failed to obtain initial size class prediction: Connection refused")))
+		baseSelector := mock.NewMockSelector(ctrl)
+		baseAnalyzer.EXPECT().Analyze(ctx, exampleDigestFunction, exampleAction).Return(baseSelector, nil)
+
+		selector, err := analyzer.Analyze(ctx, exampleDigestFunction, exampleAction)
+		require.NoError(t, err)
+		require.Equal(t, baseSelector, selector)
+	})
+
+	t.Run("NoPrediction", func(t *testing.T) {
+		// If the predictor has no opinion on the action, the
+		// base analyzer should be used silently.
+		predictor.EXPECT().Predict(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, request *initialsizeclass_pb.PredictRequest, opts ...interface{}) (*initialsizeclass_pb.PredictResponse, error) {
+				require.Equal(t, "hello", request.InstanceName)
+				require.Equal(t, remoteexecution.DigestFunction_MD5, request.DigestFunction)
+				testutil.RequireEqualProto(t, exampleAction, request.Action)
+				require.Nil(t, request.Command)
+				return &initialsizeclass_pb.PredictResponse{}, nil
+			})
+		baseSelector := mock.NewMockSelector(ctrl)
+		baseAnalyzer.EXPECT().Analyze(ctx, exampleDigestFunction, exampleAction).Return(baseSelector, nil)
+
+		selector, err := analyzer.Analyze(ctx, exampleDigestFunction, exampleAction)
+		require.NoError(t, err)
+		require.Equal(t, baseSelector, selector)
+	})
+
+	t.Run("SmallerSizeClassFailure", func(t *testing.T) {
+		// A prediction for a size class that is not the largest
+		// should use the predicted timeout. Upon failure, the
+		// action should be retried on the largest size class
+		// using the original timeout.
+		predictor.EXPECT().Predict(gomock.Any(), gomock.Any()).Return(&initialsizeclass_pb.PredictResponse{
+			Prediction: &initialsizeclass_pb.PredictResponse_Prediction{
+				SizeClass:        3,
+				ExpectedDuration: &durationpb.Duration{Seconds: 20},
+				Timeout:          &durationpb.Duration{Seconds: 60},
+			},
+		}, nil)
+
+		selector, err := analyzer.Analyze(ctx, exampleDigestFunction, exampleAction)
+		require.NoError(t, err)
+
+		sizeClassIndex, expectedDuration1, timeout1, learner1 := selector.Select([]uint32{1, 2, 4, 8})
+		require.Equal(t, 2, sizeClassIndex)
+		require.Equal(t, 20*time.Second, expectedDuration1)
+		require.Equal(t, 60*time.Second, timeout1)
+
+		expectedDuration2, timeout2, learner2 := learner1.Failed(true)
+		require.NotNil(t, learner2)
+		require.Equal(t, 300*time.Second, expectedDuration2)
+		require.Equal(t, 300*time.Second, timeout2)
+
+		_, _, learner3 := learner2.Failed(false)
+		require.Nil(t, learner3)
+	})
+
+	t.Run("LargestSizeClass", func(t *testing.T) {
+		// Predictions exceeding the largest size class should
+		// cause the largest size class to be used, using the
+		// original timeout of the action.
+		predictor.EXPECT().Predict(gomock.Any(), gomock.Any()).Return(&initialsizeclass_pb.PredictResponse{
+			Prediction: &initialsizeclass_pb.PredictResponse_Prediction{
+				SizeClass:        16,
+				ExpectedDuration: &durationpb.Duration{Seconds: 120},
+				Timeout:          &durationpb.Duration{Seconds: 200},
+			},
+		}, nil)
+
+		selector, err := analyzer.Analyze(ctx, exampleDigestFunction, exampleAction)
+		require.NoError(t, err)
+
+		sizeClassIndex, expectedDuration, timeout, learner1 := selector.Select([]uint32{1, 2, 4, 8})
+		require.Equal(t, 3, sizeClassIndex)
+		require.Equal(t, 120*time.Second, expectedDuration)
+		require.Equal(t, 300*time.Second, timeout)
+
+		_, _, _, learner2 := learner1.Succeeded(100*time.Second, []uint32{1, 2, 4, 8})
+		require.Nil(t, learner2)
+	})
+}
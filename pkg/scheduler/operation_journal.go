@@ -0,0 +1,349 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/operationjournal"
+	"github.com/buildbarn/bb-storage/pkg/program"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// OperationJournal is used by InMemoryBuildQueue to persist the state
+// of operations that are created through Execute(), so that they can
+// be recovered after the scheduler restarts.
+//
+// Methods are called while InMemoryBuildQueue holds its lock, meaning
+// that calls are serialized. Implementations must not retain the
+// messages provided to PutOperation(), as they may reference state
+// that is mutated by InMemoryBuildQueue afterwards.
+type OperationJournal interface {
+	PutOperation(operation *operationjournal.Operation)
+	RemoveOperation(name string)
+}
+
+// fileOperationJournalMinimumCompactionSizeBytes is the minimum number
+// of bytes of stale records that need to be present in the operation
+// journal before it is compacted.
+const fileOperationJournalMinimumCompactionSizeBytes = 1 << 20
+
+// fileOperationJournalQueueSize is the maximum number of records that
+// may be queued for writing. When exceeded, calls to PutOperation()
+// and RemoveOperation() block until the writer catches up.
+const fileOperationJournalQueueSize = 1024
+
+// fileOperationJournalRecord is a record that is handed over by
+// fileOperationJournal to the goroutine that writes it to disk.
+type fileOperationJournalRecord struct {
+	name   string
+	data   []byte
+	remove bool
+}
+
+type fileOperationJournal struct {
+	errorLogger util.ErrorLogger
+	records     chan<- fileOperationJournalRecord
+}
+
+// NewFileOperationJournal creates an OperationJournal that appends
+// records to a file on disk. Upon creation, the records contained in
+// an existing file are replayed, and the operations that are still
+// present are returned, so that they may be recovered by calling
+// InMemoryBuildQueue.RecoverOperations(). If the file contains a
+// record that is corrupted, the journal is truncated at that point.
+//
+// Records are written by a goroutine that is launched in the provided
+// group, meaning that callers of PutOperation() and RemoveOperation()
+// don't perform any I/O. The goroutine writes records in batches,
+// synchronizing them to storage after every batch. When the group
+// terminates, all queued records are written before the journal is
+// closed. Records that are still queued when the operating system
+// crashes are lost. The file is compacted whenever it contains a large
+// number of stale records.
+func NewFileOperationJournal(path string, errorLogger util.ErrorLogger, group program.Group) (OperationJournal, []*operationjournal.Operation, error) {
+	w := &fileOperationJournalWriter{
+		path:        path,
+		errorLogger: errorLogger,
+		operations:  map[string][]byte{},
+	}
+	operations, err := w.replay()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := w.compact(); err != nil {
+		return nil, nil, err
+	}
+
+	records := make(chan fileOperationJournalRecord, fileOperationJournalQueueSize)
+	group.Go(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+		return w.run(ctx, records)
+	})
+	return &fileOperationJournal{
+		errorLogger: errorLogger,
+		records:     records,
+	}, operations, nil
+}
+
+func (oj *fileOperationJournal) PutOperation(operation *operationjournal.Operation) {
+	data, err := marshalOperationJournalRecord(&operationjournal.Record{
+		Kind: &operationjournal.Record_PutOperation{
+			PutOperation: operation,
+		},
+	})
+	if err != nil {
+		oj.errorLogger.Log(util.StatusWrapf(err, "Failed to marshal operation %#v", operation.Name))
+		return
+	}
+	oj.records <- fileOperationJournalRecord{
+		name: operation.Name,
+		data: data,
+	}
+}
+
+func (oj *fileOperationJournal) RemoveOperation(name string) {
+	oj.records <- fileOperationJournalRecord{
+		name:   name,
+		remove: true,
+	}
+}
+
+// fileOperationJournalWriter holds the state of the goroutine that
+// writes records of the operation journal to disk.
+type fileOperationJournalWriter struct {
+	path        string
+	errorLogger util.ErrorLogger
+
+	file *os.File
+	// Records of operations that are still present, stored in
+	// marshaled form. These are used to compact the journal.
+	operations    map[string][]byte
+	liveSizeBytes int
+	fileSizeBytes int
+	// Records that have been received, but not yet written.
+	pendingData []byte
+}
+
+// replay the records contained in an existing journal file, returning
+// all operations that have not been removed.
+func (w *fileOperationJournalWriter) replay() ([]*operationjournal.Operation, error) {
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, util.StatusWrapf(err, "Failed to read operation journal %#v", w.path)
+	}
+
+	operations := map[string]*operationjournal.Operation{}
+replayRecords:
+	for offset := 0; offset < len(data); {
+		size, n := protowire.ConsumeVarint(data[offset:])
+		if n < 0 || uint64(len(data)-offset-n) < size {
+			// The scheduler terminated while appending
+			// the final record. Discard it.
+			w.errorLogger.Log(status.Errorf(codes.DataLoss, "Operation journal %#v contains a truncated record at offset %d, discarding %d bytes", w.path, offset, len(data)-offset))
+			break replayRecords
+		}
+		var record operationjournal.Record
+		if err := proto.Unmarshal(data[offset+n:offset+n+int(size)], &record); err != nil {
+			w.errorLogger.Log(util.StatusWrapfWithCode(err, codes.DataLoss, "Operation journal %#v contains a corrupted record at offset %d, discarding %d bytes", w.path, offset, len(data)-offset))
+			break replayRecords
+		}
+		switch kind := record.Kind.(type) {
+		case *operationjournal.Record_PutOperation:
+			operations[kind.PutOperation.Name] = kind.PutOperation
+		case *operationjournal.Record_RemoveOperation:
+			delete(operations, kind.RemoveOperation)
+		default:
+			w.errorLogger.Log(status.Errorf(codes.DataLoss, "Operation journal %#v contains a record of an unknown kind at offset %d, discarding %d bytes", w.path, offset, len(data)-offset))
+			break replayRecords
+		}
+		offset += n + int(size)
+	}
+
+	names := make([]string, 0, len(operations))
+	for name := range operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	recoveredOperations := make([]*operationjournal.Operation, 0, len(names))
+	for _, name := range names {
+		operation := operations[name]
+		data, err := marshalOperationJournalRecord(&operationjournal.Record{
+			Kind: &operationjournal.Record_PutOperation{
+				PutOperation: operation,
+			},
+		})
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Failed to marshal operation %#v", name)
+		}
+		w.operations[name] = data
+		w.liveSizeBytes += len(data)
+		recoveredOperations = append(recoveredOperations, operation)
+	}
+	return recoveredOperations, nil
+}
+
+// run the loop that writes records to disk. Records that are queued
+// at the same time are written as a single batch, followed by a single
+// call to fsync().
+func (w *fileOperationJournalWriter) run(ctx context.Context, records <-chan fileOperationJournalRecord) error {
+	for {
+		select {
+		case <-ctx.Done():
+			// Write all records that were queued prior to
+			// shutdown.
+			for {
+				select {
+				case record := <-records:
+					w.apply(record)
+				default:
+					w.flush()
+					return w.file.Close()
+				}
+			}
+		case record := <-records:
+			w.apply(record)
+			for more := true; more; {
+				select {
+				case record := <-records:
+					w.apply(record)
+				default:
+					more = false
+				}
+			}
+			w.flush()
+		}
+	}
+}
+
+// apply a record to the set of operations that are still present, and
+// queue it for writing.
+func (w *fileOperationJournalWriter) apply(record fileOperationJournalRecord) {
+	if record.remove {
+		previousData, ok := w.operations[record.name]
+		if !ok {
+			return
+		}
+		data, err := marshalOperationJournalRecord(&operationjournal.Record{
+			Kind: &operationjournal.Record_RemoveOperation{
+				RemoveOperation: record.name,
+			},
+		})
+		if err != nil {
+			w.errorLogger.Log(util.StatusWrapf(err, "Failed to marshal removal of operation %#v", record.name))
+			return
+		}
+		w.liveSizeBytes -= len(previousData)
+		delete(w.operations, record.name)
+		w.pendingData = append(w.pendingData, data...)
+	} else {
+		w.liveSizeBytes += len(record.data) - len(w.operations[record.name])
+		w.operations[record.name] = record.data
+		w.pendingData = append(w.pendingData, record.data...)
+	}
+}
+
+// flush records that are queued for writing, compacting the journal if
+// it contains too many stale records.
+func (w *fileOperationJournalWriter) flush() {
+	if len(w.pendingData) == 0 {
+		return
+	}
+	_, err := w.file.Write(w.pendingData)
+	pendingSizeBytes := len(w.pendingData)
+	w.pendingData = w.pendingData[:0]
+	if err != nil {
+		// The journal may now contain a partially written
+		// record. Rewrite it entirely to ensure it remains
+		// readable.
+		w.errorLogger.Log(util.StatusWrapf(err, "Failed to write to operation journal %#v", w.path))
+		if err := w.compact(); err != nil {
+			w.errorLogger.Log(err)
+		}
+		return
+	}
+	w.fileSizeBytes += pendingSizeBytes
+	if err := w.file.Sync(); err != nil {
+		w.errorLogger.Log(util.StatusWrapf(err, "Failed to synchronize operation journal %#v", w.path))
+	}
+	if w.fileSizeBytes-w.liveSizeBytes > w.liveSizeBytes+fileOperationJournalMinimumCompactionSizeBytes {
+		if err := w.compact(); err != nil {
+			w.errorLogger.Log(err)
+		}
+	}
+}
+
+// compact the journal by writing the records of all operations that
+// are still present into a new file, and replacing the existing file.
+func (w *fileOperationJournalWriter) compact() error {
+	temporaryPath := w.path + ".tmp"
+	f, err := os.OpenFile(temporaryPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o666)
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to create operation journal %#v", temporaryPath)
+	}
+	if err := w.writeOperations(f); err != nil {
+		f.Close()
+		os.Remove(temporaryPath)
+		return util.StatusWrapf(err, "Failed to write operation journal %#v", temporaryPath)
+	}
+	if err := os.Rename(temporaryPath, w.path); err != nil {
+		f.Close()
+		os.Remove(temporaryPath)
+		return util.StatusWrapf(err, "Failed to rename operation journal %#v to %#v", temporaryPath, w.path)
+	}
+	// Synchronize the parent directory, so that the rename is
+	// persisted as well.
+	if err := syncDirectory(filepath.Dir(w.path)); err != nil {
+		f.Close()
+		return util.StatusWrapf(err, "Failed to synchronize directory containing operation journal %#v", w.path)
+	}
+
+	if w.file != nil {
+		w.file.Close()
+	}
+	w.file = f
+	w.fileSizeBytes = w.liveSizeBytes
+	return nil
+}
+
+func (w *fileOperationJournalWriter) writeOperations(f *os.File) error {
+	data := make([]byte, 0, w.liveSizeBytes)
+	for _, record := range w.operations {
+		data = append(data, record...)
+	}
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+func syncDirectory(path string) error {
+	d, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if closeErr := d.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// marshalOperationJournalRecord marshals a record of the operation
+// journal, prefixing it with its size.
+func marshalOperationJournalRecord(record *operationjournal.Record) ([]byte, error) {
+	data, err := proto.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	return append(protowire.AppendVarint(nil, uint64(len(data))), data...), nil
+}
@@ -0,0 +1,156 @@
+package scheduler_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/operationjournal"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler"
+	"github.com/buildbarn/bb-storage/pkg/program"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFileOperationJournal(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	errorLogger := mock.NewMockErrorLogger(ctrl)
+	path := filepath.Join(t.TempDir(), "operations")
+
+	// runOperationJournal opens the operation journal, and calls
+	// the provided function against it. All records are written
+	// to disk by the time it returns.
+	runOperationJournal := func(t *testing.T, f func(operationJournal scheduler.OperationJournal, operations []*operationjournal.Operation)) {
+		require.NoError(t, program.RunLocal(ctx, func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+			operationJournal, operations, err := scheduler.NewFileOperationJournal(path, errorLogger, dependenciesGroup)
+			if err != nil {
+				return err
+			}
+			f(operationJournal, operations)
+			return nil
+		}))
+	}
+
+	t.Run("Initial", func(t *testing.T) {
+		// Creating a journal when no file is present should
+		// not yield any operations.
+		runOperationJournal(t, func(operationJournal scheduler.OperationJournal, operations []*operationjournal.Operation) {
+			require.Empty(t, operations)
+
+			operationJournal.PutOperation(&operationjournal.Operation{
+				Name:     "a",
+				Priority: 1,
+			})
+			operationJournal.PutOperation(&operationjournal.Operation{
+				Name:     "b",
+				Priority: 2,
+			})
+			operationJournal.PutOperation(&operationjournal.Operation{
+				Name:     "a",
+				Priority: 3,
+			})
+			operationJournal.PutOperation(&operationjournal.Operation{
+				Name:     "c",
+				Priority: 4,
+			})
+			operationJournal.RemoveOperation("b")
+			operationJournal.RemoveOperation("d")
+		})
+	})
+
+	t.Run("Recovery", func(t *testing.T) {
+		// Simulate a crash of the scheduler while appending a
+		// record, leaving a partially written record behind.
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		require.NoError(t, err)
+		offset, err := f.Seek(0, io.SeekEnd)
+		require.NoError(t, err)
+		_, err = f.Write([]byte{0x0a, 0x12, 0x34})
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		// Only the latest state of operations that have not been
+		// removed should be returned. The partially written
+		// record should be discarded.
+		errorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.DataLoss, fmt.Sprintf("Operation journal %#v contains a truncated record at offset %d, discarding 3 bytes", path, offset))))
+		runOperationJournal(t, func(operationJournal scheduler.OperationJournal, operations []*operationjournal.Operation) {
+			require.Len(t, operations, 2)
+			testutil.RequireEqualProto(t, &operationjournal.Operation{
+				Name:     "a",
+				Priority: 3,
+			}, operations[0])
+			testutil.RequireEqualProto(t, &operationjournal.Operation{
+				Name:     "c",
+				Priority: 4,
+			}, operations[1])
+
+			operationJournal.RemoveOperation("a")
+		})
+	})
+
+	t.Run("RecoveryAfterCompaction", func(t *testing.T) {
+		// Records written after the journal was compacted
+		// should also be taken into account. As the journal
+		// was compacted, the partially written record should
+		// no longer be present.
+		runOperationJournal(t, func(operationJournal scheduler.OperationJournal, operations []*operationjournal.Operation) {
+			require.Len(t, operations, 1)
+			testutil.RequireEqualProto(t, &operationjournal.Operation{
+				Name:     "c",
+				Priority: 4,
+			}, operations[0])
+
+			operationJournal.PutOperation(&operationjournal.Operation{
+				Name:     "d",
+				Priority: 5,
+			})
+		})
+	})
+
+	t.Run("CorruptedRecord", func(t *testing.T) {
+		// Append a record that cannot be unmarshaled, followed
+		// by a record that is valid. Replaying should stop at
+		// the corrupted record, discarding everything after it.
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		require.NoError(t, err)
+		offset, err := f.Seek(0, io.SeekEnd)
+		require.NoError(t, err)
+		_, err = f.Write([]byte{0x02, 0xff, 0xff})
+		require.NoError(t, err)
+		_, err = f.Write([]byte{0x03, 0x12, 0x01, 0x63})
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		errorLogger.EXPECT().Log(gomock.Any()).Do(func(err error) {
+			s := status.Convert(err)
+			require.Equal(t, codes.DataLoss, s.Code())
+			require.Contains(t, s.Message(), fmt.Sprintf("Operation journal %#v contains a corrupted record at offset %d, discarding 7 bytes: ", path, offset))
+		})
+		runOperationJournal(t, func(operationJournal scheduler.OperationJournal, operations []*operationjournal.Operation) {
+			require.Len(t, operations, 2)
+			testutil.RequireEqualProto(t, &operationjournal.Operation{
+				Name:     "c",
+				Priority: 4,
+			}, operations[0])
+			testutil.RequireEqualProto(t, &operationjournal.Operation{
+				Name:     "d",
+				Priority: 5,
+			}, operations[1])
+		})
+
+		// The journal should have been truncated, meaning that
+		// reopening it should not report any errors.
+		runOperationJournal(t, func(operationJournal scheduler.OperationJournal, operations []*operationjournal.Operation) {
+			require.Len(t, operations, 2)
+		})
+	})
+}
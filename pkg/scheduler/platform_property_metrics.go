@@ -0,0 +1,128 @@
+package scheduler
+
+import (
+	"errors"
+	"strings"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// platformPropertyMetrics holds Prometheus metrics that are labeled by
+// the values of a configurable set of platform properties, as opposed
+// to the JSON representation of the full Platform message. This makes
+// it possible to create dashboards and alerts that target specific
+// pools of workers (e.g., "os" and "arch"), regardless of any other
+// properties that are part of the platform.
+//
+// As the set of labels depends on the configuration, these metrics
+// cannot be declared at the package level.
+type platformPropertyMetrics struct {
+	propertyNames []string
+
+	tasksQueued                   *prometheus.GaugeVec
+	tasksQueuedDurationSeconds    *prometheus.HistogramVec
+	tasksExecutingDurationSeconds *prometheus.HistogramVec
+}
+
+// getPlatformPropertyLabelName converts the name of a platform
+// property to a Prometheus label name. Characters that are not
+// permitted in label names are replaced by underscores.
+func getPlatformPropertyLabelName(propertyName string) string {
+	return "platform_" + strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, propertyName)
+}
+
+// registerOrReuseCollector registers a Prometheus collector. If an
+// identical collector was registered previously (e.g., by another
+// instance of InMemoryBuildQueue), the existing collector is returned.
+func registerOrReuseCollector(c prometheus.Collector) prometheus.Collector {
+	if err := prometheus.Register(c); err != nil {
+		var alreadyRegisteredError prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegisteredError) {
+			return alreadyRegisteredError.ExistingCollector
+		}
+		panic(err)
+	}
+	return c
+}
+
+func newPlatformPropertyMetrics(propertyNames []string) *platformPropertyMetrics {
+	labelNames := []string{"instance_name_prefix", "size_class"}
+	for _, propertyName := range propertyNames {
+		labelNames = append(labelNames, getPlatformPropertyLabelName(propertyName))
+	}
+
+	return &platformPropertyMetrics{
+		propertyNames: propertyNames,
+
+		tasksQueued: registerOrReuseCollector(prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "buildbarn",
+				Subsystem: "builder",
+				Name:      "in_memory_build_queue_platform_property_tasks_queued",
+				Help:      "Number of tasks that are currently queued, labeled by selected platform properties.",
+			},
+			labelNames)).(*prometheus.GaugeVec),
+		tasksQueuedDurationSeconds: registerOrReuseCollector(prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "buildbarn",
+				Subsystem: "builder",
+				Name:      "in_memory_build_queue_platform_property_tasks_queued_duration_seconds",
+				Help:      "Time in seconds that tasks were queued before executing, labeled by selected platform properties.",
+				Buckets:   util.DecimalExponentialBuckets(-3, 6, 2),
+			},
+			labelNames)).(*prometheus.HistogramVec),
+		tasksExecutingDurationSeconds: registerOrReuseCollector(prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "buildbarn",
+				Subsystem: "builder",
+				Name:      "in_memory_build_queue_platform_property_tasks_executing_duration_seconds",
+				Help:      "Time in seconds that tasks were executing before completing, labeled by selected platform properties.",
+				Buckets:   util.DecimalExponentialBuckets(-3, 6, 2),
+			},
+			append(append([]string(nil), labelNames...), "result", "grpc_code"))).(*prometheus.HistogramVec),
+	}
+}
+
+// sizeClassQueuePlatformPropertyMetrics contains the instances of the
+// metrics in platformPropertyMetrics that apply to a single size class
+// queue. Size class queues whose selected platform properties are
+// identical share the same metrics.
+type sizeClassQueuePlatformPropertyMetrics struct {
+	tasksQueued                   prometheus.Gauge
+	tasksQueuedDurationSeconds    prometheus.Observer
+	tasksExecutingDurationSeconds prometheus.ObserverVec
+}
+
+func (m *platformPropertyMetrics) newSizeClassQueueMetrics(instanceNamePrefix string, platform *remoteexecution.Platform, sizeClass string) *sizeClassQueuePlatformPropertyMetrics {
+	labels := map[string]string{
+		"instance_name_prefix": instanceNamePrefix,
+		"size_class":           sizeClass,
+	}
+	for _, propertyName := range m.propertyNames {
+		// Platform properties may be specified multiple times.
+		// Join all values, so that they are all reflected.
+		var values []string
+		for _, property := range platform.GetProperties() {
+			if property.Name == propertyName {
+				values = append(values, property.Value)
+			}
+		}
+		labels[getPlatformPropertyLabelName(propertyName)] = strings.Join(values, ",")
+	}
+
+	tasksExecutingDurationSeconds := m.tasksExecutingDurationSeconds.MustCurryWith(labels)
+	// Force creation of the metric to make recording rules work.
+	tasksExecutingDurationSeconds.WithLabelValues("Success", "")
+	return &sizeClassQueuePlatformPropertyMetrics{
+		tasksQueued:                   m.tasksQueued.With(labels),
+		tasksQueuedDurationSeconds:    m.tasksQueuedDurationSeconds.With(labels),
+		tasksExecutingDurationSeconds: tasksExecutingDurationSeconds,
+	}
+}
@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/platform"
+	"github.com/golang/protobuf/jsonpb"
+
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// PlatformQueueHealthReporter is called into by InMemoryBuildQueue to
+// report whether platform queues are capable of executing operations.
+// This is the case if at least one of its workers is not drained.
+// Operations submitted to unhealthy platform queues remain queued
+// until workers become available, meaning that load balancers and
+// clients may want to fail over to another cluster instead.
+//
+// This interface is implemented by health.Server of gRPC, allowing
+// the health of platform queues to be exposed through the
+// grpc.health.v1.Health service.
+type PlatformQueueHealthReporter interface {
+	SetServingStatus(service string, servingStatus grpc_health_v1.HealthCheckResponse_ServingStatus)
+}
+
+// GetPlatformQueueHealthServiceName returns the name of the service
+// under which the health of a platform queue is reported. It is equal
+// to the name of the platform queue, encoded as JSON without any
+// whitespace.
+func GetPlatformQueueHealthServiceName(platformKey platform.Key) string {
+	// Don't use protojson.Marshal(), as it injects random
+	// whitespace into its output.
+	marshaler := jsonpb.Marshaler{}
+	serviceName, err := marshaler.MarshalToString(platformKey.GetPlatformQueueName())
+	if err != nil {
+		panic(fmt.Sprintf("Failed to marshal platform queue name: %s", err))
+	}
+	return serviceName
+}
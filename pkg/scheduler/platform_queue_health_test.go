@@ -0,0 +1,177 @@
+package scheduler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/buildqueuestate"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteworker"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/platform"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestGetPlatformQueueHealthServiceName(t *testing.T) {
+	require.Equal(
+		t,
+		`{"instanceNamePrefix":"main","platform":{"properties":[{"name":"cpu","value":"armv6"},{"name":"os","value":"linux"}]}}`,
+		scheduler.GetPlatformQueueHealthServiceName(platform.MustNewKey("main", platformForTesting)))
+	require.Equal(
+		t,
+		`{"platform":{}}`,
+		scheduler.GetPlatformQueueHealthServiceName(platform.MustNewKey("", nil)))
+}
+
+func TestInMemoryBuildQueuePlatformQueueHealth(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(0, 0))
+	healthServer := health.NewServer()
+	buildQueueConfiguration := buildQueueConfigurationForTesting
+	buildQueueConfiguration.PlatformQueueHealthReporter = healthServer
+	buildQueue := scheduler.NewInMemoryBuildQueue(mock.NewMockBlobAccess(ctrl), clock, mock.NewMockUUIDGenerator(ctrl).Call, &buildQueueConfiguration, 10000, mock.NewMockActionRouter(ctrl), allowAllAuthorizer, allowAllAuthorizer, allowAllAuthorizer)
+
+	serviceName := scheduler.GetPlatformQueueHealthServiceName(platform.MustNewKey("main", platformForTesting))
+	requireServingStatus := func(t *testing.T, servingStatus grpc_health_v1.HealthCheckResponse_ServingStatus) {
+		response, err := healthServer.Check(ctx, &grpc_health_v1.HealthCheckRequest{
+			Service: serviceName,
+		})
+		require.NoError(t, err)
+		require.Equal(t, servingStatus, response.Status)
+	}
+	sizeClassQueueName := &buildqueuestate.SizeClassQueueName{
+		PlatformQueueName: &buildqueuestate.PlatformQueueName{
+			InstanceNamePrefix: "main",
+			Platform:           platformForTesting,
+		},
+	}
+	requireDrainedWorkersCount := func(t *testing.T, drainedWorkersCount uint32) {
+		clock.EXPECT().Now().Return(time.Unix(1010, 0))
+		response, err := buildQueue.ListPlatformQueues(ctx, &emptypb.Empty{})
+		require.NoError(t, err)
+		require.Len(t, response.PlatformQueues, 1)
+		require.Len(t, response.PlatformQueues[0].SizeClassQueues, 1)
+		require.Equal(t, uint32(1), response.PlatformQueues[0].SizeClassQueues[0].WorkersCount)
+		require.Equal(t, drainedWorkersCount, response.PlatformQueues[0].SizeClassQueues[0].DrainedWorkersCount)
+	}
+
+	t.Run("Absent", func(t *testing.T) {
+		// Platform queues that don't exist should not be
+		// reported.
+		_, err := healthServer.Check(ctx, &grpc_health_v1.HealthCheckRequest{
+			Service: serviceName,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "unknown service"), err)
+	})
+
+	t.Run("NoWorkers", func(t *testing.T) {
+		// Predeclared platform queues without any workers
+		// should be reported as not serving.
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		require.NoError(t, buildQueue.RegisterPredeclaredPlatformQueue(
+			digest.MustNewInstanceName("main"),
+			platformForTesting,
+			/* workerInvocationStickinessLimits = */ nil,
+			/* maximumQueuedBackgroundLearningOperations = */ 0,
+			/* backgroundLearningOperationPriority = */ 0,
+			/* maximumSizeClass = */ 0,
+			/* maximumQueuedOperations = */ 0,
+			/* preemptionPolicy = */ nil,
+			/* workerResourceRequirements = */ nil,
+			/* sizeClassEscalationPolicy = */ nil))
+		requireServingStatus(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	})
+
+	t.Run("WorkerCreated", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1001, 0))
+		_, err := buildQueue.Synchronize(ctx, &remoteworker.SynchronizeRequest{
+			WorkerId: map[string]string{
+				"hostname": "worker123",
+				"thread":   "42",
+			},
+			InstanceNamePrefix: "main",
+			Platform:           platformForTesting,
+			CurrentState: &remoteworker.CurrentState{
+				WorkerState: &remoteworker.CurrentState_Executing_{
+					Executing: &remoteworker.CurrentState_Executing{
+						ActionDigest: &remoteexecution.Digest{
+							Hash:      "099a3f6dc1e8e91dbcca4ea964cd2237d4b11733",
+							SizeBytes: 123,
+						},
+						ExecutionState: &remoteworker.CurrentState_Executing_FetchingInputs{
+							FetchingInputs: &emptypb.Empty{},
+						},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		requireServingStatus(t, grpc_health_v1.HealthCheckResponse_SERVING)
+		requireDrainedWorkersCount(t, 0)
+	})
+
+	t.Run("Drained", func(t *testing.T) {
+		// Adding a drain that doesn't match the worker should
+		// not affect the health of the platform queue.
+		clock.EXPECT().Now().Return(time.Unix(1002, 0))
+		_, err := buildQueue.AddDrain(ctx, &buildqueuestate.AddOrRemoveDrainRequest{
+			SizeClassQueueName: sizeClassQueueName,
+			WorkerIdPattern: map[string]string{
+				"hostname": "worker124",
+			},
+		})
+		require.NoError(t, err)
+		requireServingStatus(t, grpc_health_v1.HealthCheckResponse_SERVING)
+
+		// Draining the only worker should cause the platform
+		// queue to become unhealthy.
+		clock.EXPECT().Now().Return(time.Unix(1003, 0))
+		_, err = buildQueue.AddDrain(ctx, &buildqueuestate.AddOrRemoveDrainRequest{
+			SizeClassQueueName: sizeClassQueueName,
+			WorkerIdPattern: map[string]string{
+				"hostname": "worker123",
+			},
+		})
+		require.NoError(t, err)
+		requireServingStatus(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+		requireDrainedWorkersCount(t, 1)
+
+		// Removing the drain should make it healthy again.
+		clock.EXPECT().Now().Return(time.Unix(1004, 0))
+		_, err = buildQueue.RemoveDrain(ctx, &buildqueuestate.AddOrRemoveDrainRequest{
+			SizeClassQueueName: sizeClassQueueName,
+			WorkerIdPattern: map[string]string{
+				"hostname": "worker123",
+			},
+		})
+		require.NoError(t, err)
+		requireServingStatus(t, grpc_health_v1.HealthCheckResponse_SERVING)
+		requireDrainedWorkersCount(t, 0)
+	})
+
+	t.Run("Terminating", func(t *testing.T) {
+		// Workers that are terminating are implicitly drained.
+		clock.EXPECT().Now().Return(time.Unix(1005, 0))
+		_, err := buildQueue.TerminateWorkers(ctx, &buildqueuestate.TerminateWorkersRequest{
+			WorkerIdPattern: map[string]string{
+				"hostname": "worker123",
+			},
+		})
+		require.NoError(t, err)
+		requireServingStatus(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+		requireDrainedWorkersCount(t, 1)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/platform"
 	"github.com/buildbarn/bb-storage/pkg/blobstore"
 	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/grpc"
 	"github.com/buildbarn/bb-storage/pkg/util"
 
 	"google.golang.org/grpc/codes"
@@ -15,7 +16,7 @@ import (
 
 // NewActionRouterFromConfiguration creates an ActionRouter based on
 // options specified in a configuration file.
-func NewActionRouterFromConfiguration(configuration *pb.ActionRouterConfiguration, contentAddressableStorage blobstore.BlobAccess, maximumMessageSizeBytes int, previousExecutionStatsStore initialsizeclass.PreviousExecutionStatsStore) (ActionRouter, error) {
+func NewActionRouterFromConfiguration(configuration *pb.ActionRouterConfiguration, contentAddressableStorage blobstore.BlobAccess, maximumMessageSizeBytes int, previousExecutionStatsStore initialsizeclass.PreviousExecutionStatsStore, grpcClientFactory grpc.ClientFactory) (ActionRouter, error) {
 	if configuration == nil {
 		return nil, status.Error(codes.InvalidArgument, "No action router configuration provided")
 	}
@@ -33,7 +34,7 @@ func NewActionRouterFromConfiguration(configuration *pb.ActionRouterConfiguratio
 			}
 			invocationKeyExtractors = append(invocationKeyExtractors, invocationKeyExtractor)
 		}
-		initialSizeClassAnalyzer, err := initialsizeclass.NewAnalyzerFromConfiguration(kind.Simple.InitialSizeClassAnalyzer, previousExecutionStatsStore)
+		initialSizeClassAnalyzer, err := initialsizeclass.NewAnalyzerFromConfiguration(kind.Simple.InitialSizeClassAnalyzer, previousExecutionStatsStore, grpcClientFactory, contentAddressableStorage, maximumMessageSizeBytes)
 		if err != nil {
 			return nil, util.StatusWrap(err, "Failed to create initial size class analyzer")
 		}
@@ -43,7 +44,7 @@ func NewActionRouterFromConfiguration(configuration *pb.ActionRouterConfiguratio
 		if err != nil {
 			return nil, util.StatusWrap(err, "Failed to create platform key extractor")
 		}
-		defaultActionRouter, err := NewActionRouterFromConfiguration(kind.Demultiplexing.DefaultActionRouter, contentAddressableStorage, maximumMessageSizeBytes, previousExecutionStatsStore)
+		defaultActionRouter, err := NewActionRouterFromConfiguration(kind.Demultiplexing.DefaultActionRouter, contentAddressableStorage, maximumMessageSizeBytes, previousExecutionStatsStore, grpcClientFactory)
 		if err != nil {
 			return nil, util.StatusWrap(err, "Failed to create default action router")
 		}
@@ -53,7 +54,7 @@ func NewActionRouterFromConfiguration(configuration *pb.ActionRouterConfiguratio
 			if err != nil {
 				return nil, util.StatusWrapf(err, "Invalid instance name prefix %#v", backend.InstanceNamePrefix)
 			}
-			backendActionRouter, err := NewActionRouterFromConfiguration(backend.ActionRouter, contentAddressableStorage, maximumMessageSizeBytes, previousExecutionStatsStore)
+			backendActionRouter, err := NewActionRouterFromConfiguration(backend.ActionRouter, contentAddressableStorage, maximumMessageSizeBytes, previousExecutionStatsStore, grpcClientFactory)
 			if err != nil {
 				return nil, util.StatusWrap(err, "Failed to create demultiplexing action router backend")
 			}
@@ -62,6 +63,18 @@ func NewActionRouterFromConfiguration(configuration *pb.ActionRouterConfiguratio
 			}
 		}
 		return actionRouter, nil
+	case *pb.ActionRouterConfiguration_Experimenting:
+		baseActionRouter, err := NewActionRouterFromConfiguration(kind.Experimenting.BaseActionRouter, contentAddressableStorage, maximumMessageSizeBytes, previousExecutionStatsStore, grpcClientFactory)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to create base action router")
+		}
+		actionRouter := NewExperimentingActionRouter(baseActionRouter)
+		for _, experiment := range kind.Experimenting.Experiments {
+			if err := actionRouter.RegisterExperiment(experiment.Name, experiment.Fraction, experiment.AdditionalPlatformProperties); err != nil {
+				return nil, util.StatusWrapf(err, "Failed to register experiment %#v", experiment.Name)
+			}
+		}
+		return actionRouter, nil
 	default:
 		return nil, status.Error(codes.InvalidArgument, "Configuration did not contain a supported action router type")
 	}
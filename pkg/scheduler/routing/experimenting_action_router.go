@@ -0,0 +1,142 @@
+package routing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+	"sync"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/initialsizeclass"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/invocation"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/platform"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	experimentingActionRouterPrometheusMetrics sync.Once
+
+	experimentingActionRouterRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "builder",
+			Name:      "experimenting_action_router_requests_total",
+			Help:      "Number of actions that were routed using the experimenting action router, partitioned by the experiment that was selected.",
+		},
+		[]string{"experiment"})
+)
+
+type experimentingActionRouterExperiment struct {
+	upperBound                   float64
+	additionalPlatformProperties []*remoteexecution.Platform_Property
+	requestsTotal                prometheus.Counter
+}
+
+// ExperimentingActionRouter is an implementation of ActionRouter that
+// runs a fraction of all actions as part of an experiment. Actions
+// that are selected for an experiment have additional platform
+// properties added to their platform key, causing them to be executed
+// on a separate set of workers. These workers may use an alternative
+// configuration (e.g., a different runner or sandbox). As the
+// scheduler reports metrics for every platform queue separately, this
+// permits comparing queueing and execution latencies, and outcomes of
+// actions between the experiment and the control group.
+//
+// Actions are selected for an experiment deterministically, based on
+// a hash of the Action message. This ensures that retries of the same
+// action use the same configuration, and that in-flight deduplication
+// remains effective.
+type ExperimentingActionRouter struct {
+	base             ActionRouter
+	experiments      []experimentingActionRouterExperiment
+	controlTotal     prometheus.Counter
+	assignedFraction float64
+}
+
+// NewExperimentingActionRouter creates a new ExperimentingActionRouter
+// that forwards all requests to a base ActionRouter, without running
+// any experiments.
+func NewExperimentingActionRouter(base ActionRouter) *ExperimentingActionRouter {
+	experimentingActionRouterPrometheusMetrics.Do(func() {
+		prometheus.MustRegister(experimentingActionRouterRequestsTotal)
+	})
+
+	return &ExperimentingActionRouter{
+		base:         base,
+		controlTotal: experimentingActionRouterRequestsTotal.WithLabelValues(""),
+	}
+}
+
+var _ ActionRouter = (*ExperimentingActionRouter)(nil)
+
+// RegisterExperiment registers a new experiment, causing the provided
+// fraction of all actions to be routed with additional platform
+// properties. The sum of the fractions of all experiments may not
+// exceed one.
+func (ar *ExperimentingActionRouter) RegisterExperiment(name string, fraction float64, additionalPlatformProperties []*remoteexecution.Platform_Property) error {
+	if name == "" {
+		return status.Error(codes.InvalidArgument, "No experiment name provided")
+	}
+	if len(additionalPlatformProperties) == 0 {
+		return status.Error(codes.InvalidArgument, "Experiments must add at least one platform property, as actions would otherwise run in the control group")
+	}
+	if remaining := 1 - ar.assignedFraction; !(fraction >= 0 && fraction <= remaining) {
+		return status.Errorf(codes.InvalidArgument, "Fraction %g is not within range [0, %g]", fraction, remaining)
+	}
+	ar.assignedFraction += fraction
+	ar.experiments = append(ar.experiments, experimentingActionRouterExperiment{
+		upperBound:                   ar.assignedFraction,
+		additionalPlatformProperties: additionalPlatformProperties,
+		requestsTotal:                experimentingActionRouterRequestsTotal.WithLabelValues(name),
+	})
+	return nil
+}
+
+// RouteAction forwards the request to the base ActionRouter, and adds
+// platform properties to the resulting platform key if the action is
+// selected to be part of an experiment.
+func (ar *ExperimentingActionRouter) RouteAction(ctx context.Context, digestFunction digest.Function, action *remoteexecution.Action, requestMetadata *remoteexecution.RequestMetadata) (platform.Key, []invocation.Key, initialsizeclass.Selector, error) {
+	platformKey, invocationKeys, initialSizeClassSelector, err := ar.base.RouteAction(ctx, digestFunction, action, requestMetadata)
+	if err != nil {
+		return platform.Key{}, nil, nil, err
+	}
+
+	// Determine which experiment, if any, the action is part of.
+	marshaledAction, err := proto.MarshalOptions{Deterministic: true}.Marshal(action)
+	if err != nil {
+		initialSizeClassSelector.Abandoned()
+		return platform.Key{}, nil, nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to marshal action")
+	}
+	hash := sha256.Sum256(marshaledAction)
+	position := float64(binary.BigEndian.Uint64(hash[:])>>11) / (1 << 53)
+	for _, experiment := range ar.experiments {
+		if position < experiment.upperBound {
+			experiment.requestsTotal.Inc()
+
+			// Add the platform properties of the experiment,
+			// while keeping them sorted.
+			platformQueueName := platformKey.GetPlatformQueueName()
+			platformMessage := platformQueueName.Platform
+			platformMessage.Properties = append(platformMessage.Properties, experiment.additionalPlatformProperties...)
+			sort.Slice(platformMessage.Properties, func(i, j int) bool {
+				pi, pj := platformMessage.Properties[i], platformMessage.Properties[j]
+				return pi.Name < pj.Name || (pi.Name == pj.Name && pi.Value < pj.Value)
+			})
+			experimentPlatformKey, err := platform.NewKey(platformKey.GetInstanceNamePrefix(), platformMessage)
+			if err != nil {
+				initialSizeClassSelector.Abandoned()
+				return platform.Key{}, nil, nil, util.StatusWrap(err, "Failed to create platform key for experiment")
+			}
+			return experimentPlatformKey, invocationKeys, initialSizeClassSelector, nil
+		}
+	}
+	ar.controlTotal.Inc()
+	return platformKey, invocationKeys, initialSizeClassSelector, nil
+}
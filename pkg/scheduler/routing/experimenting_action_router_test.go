@@ -0,0 +1,135 @@
+package routing_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/invocation"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/platform"
+	"github.com/buildbarn/bb-remote-execution/pkg/scheduler/routing"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExperimentingActionRouter(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseActionRouter := mock.NewMockActionRouter(ctrl)
+	digestFunction := digest.MustNewFunction("a", remoteexecution.DigestFunction_SHA256)
+	action := &remoteexecution.Action{
+		CommandDigest: &remoteexecution.Digest{
+			Hash:      "a8caa4ed8a0a6ac6e7bf4c85dc0d2d3e4a1e52b5c0e2e0a1d6c4e4ab4dd4c2f8",
+			SizeBytes: 123,
+		},
+	}
+	requestMetadata := &remoteexecution.RequestMetadata{}
+	linuxPlatform := &remoteexecution.Platform{
+		Properties: []*remoteexecution.Platform_Property{
+			{Name: "os", Value: "linux"},
+		},
+	}
+	invocationKeys := []invocation.Key{"hello"}
+
+	t.Run("InvalidExperiments", func(t *testing.T) {
+		actionRouter := routing.NewExperimentingActionRouter(baseActionRouter)
+		experimentProperties := []*remoteexecution.Platform_Property{
+			{Name: "experiment", Value: "sandbox"},
+		}
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.InvalidArgument, "No experiment name provided"),
+			actionRouter.RegisterExperiment("", 0.5, experimentProperties))
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.InvalidArgument, "Experiments must add at least one platform property, as actions would otherwise run in the control group"),
+			actionRouter.RegisterExperiment("sandbox", 0.5, nil))
+		require.NoError(t, actionRouter.RegisterExperiment("sandbox", 0.75, experimentProperties))
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.InvalidArgument, "Fraction 0.5 is not within range [0, 0.25]"),
+			actionRouter.RegisterExperiment("compression", 0.5, experimentProperties))
+	})
+
+	t.Run("BaseFailure", func(t *testing.T) {
+		actionRouter := routing.NewExperimentingActionRouter(baseActionRouter)
+		baseActionRouter.EXPECT().RouteAction(ctx, digestFunction, action, requestMetadata).
+			Return(platform.Key{}, nil, nil, status.Error(codes.Internal, "Server on fire"))
+
+		_, _, _, err := actionRouter.RouteAction(ctx, digestFunction, action, requestMetadata)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Server on fire"), err)
+	})
+
+	t.Run("Control", func(t *testing.T) {
+		// Experiments that have a fraction of zero should never
+		// be selected.
+		actionRouter := routing.NewExperimentingActionRouter(baseActionRouter)
+		require.NoError(t, actionRouter.RegisterExperiment("sandbox", 0, []*remoteexecution.Platform_Property{
+			{Name: "experiment", Value: "sandbox"},
+		}))
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		baseActionRouter.EXPECT().RouteAction(ctx, digestFunction, action, requestMetadata).
+			Return(platform.MustNewKey("a", linuxPlatform), invocationKeys, initialSizeClassSelector, nil)
+
+		platformKey, actualInvocationKeys, actualSelector, err := actionRouter.RouteAction(ctx, digestFunction, action, requestMetadata)
+		require.NoError(t, err)
+		require.Equal(t, platform.MustNewKey("a", linuxPlatform), platformKey)
+		require.Equal(t, invocationKeys, actualInvocationKeys)
+		require.Equal(t, initialSizeClassSelector, actualSelector)
+	})
+
+	t.Run("Experiment", func(t *testing.T) {
+		// Experiments that have a fraction of one should always
+		// be selected. The platform properties of the
+		// experiment should be merged into the platform key,
+		// while retaining the sort order.
+		actionRouter := routing.NewExperimentingActionRouter(baseActionRouter)
+		require.NoError(t, actionRouter.RegisterExperiment("sandbox", 0, []*remoteexecution.Platform_Property{
+			{Name: "experiment", Value: "unused"},
+		}))
+		require.NoError(t, actionRouter.RegisterExperiment("compression", 1, []*remoteexecution.Platform_Property{
+			{Name: "zstd", Value: "true"},
+			{Name: "experiment", Value: "compression"},
+		}))
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		baseActionRouter.EXPECT().RouteAction(ctx, digestFunction, action, requestMetadata).
+			Return(platform.MustNewKey("a", linuxPlatform), invocationKeys, initialSizeClassSelector, nil)
+
+		platformKey, actualInvocationKeys, actualSelector, err := actionRouter.RouteAction(ctx, digestFunction, action, requestMetadata)
+		require.NoError(t, err)
+		require.Equal(t, platform.MustNewKey("a", &remoteexecution.Platform{
+			Properties: []*remoteexecution.Platform_Property{
+				{Name: "experiment", Value: "compression"},
+				{Name: "os", Value: "linux"},
+				{Name: "zstd", Value: "true"},
+			},
+		}), platformKey)
+		require.Equal(t, invocationKeys, actualInvocationKeys)
+		require.Equal(t, initialSizeClassSelector, actualSelector)
+	})
+
+	t.Run("ConflictingProperty", func(t *testing.T) {
+		// Adding a platform property that is already present
+		// leads to a platform that is not in normal form.
+		actionRouter := routing.NewExperimentingActionRouter(baseActionRouter)
+		require.NoError(t, actionRouter.RegisterExperiment("sandbox", 1, []*remoteexecution.Platform_Property{
+			{Name: "os", Value: "linux"},
+		}))
+		initialSizeClassSelector := mock.NewMockSelector(ctrl)
+		baseActionRouter.EXPECT().RouteAction(ctx, digestFunction, action, requestMetadata).
+			Return(platform.MustNewKey("a", linuxPlatform), invocationKeys, initialSizeClassSelector, nil)
+		initialSizeClassSelector.EXPECT().Abandoned()
+
+		_, _, _, err := actionRouter.RouteAction(ctx, digestFunction, action, requestMetadata)
+		// protojson randomizes whitespace in its output, so
+		// only compare the error code.
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
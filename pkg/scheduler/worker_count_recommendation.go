@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+	"math"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var inMemoryBuildQueueRecommendedWorkersDesc = prometheus.NewDesc(
+	prometheus.BuildFQName("buildbarn", "builder", "in_memory_build_queue_recommended_workers"),
+	"Number of workers that is recommended to be present for a size class queue, based on its queue length, arrival rate and average execution duration.",
+	[]string{"instance_name_prefix", "platform", "size_class"},
+	nil)
+
+// exponentiallyDecayingSum is a sum of samples, where the contribution
+// of every sample decays exponentially as time progresses. It is used
+// to estimate rates and averages over a sliding window, without
+// needing to retain individual samples.
+type exponentiallyDecayingSum struct {
+	value      float64
+	lastUpdate time.Time
+}
+
+// get the value of the sum at a given point in time.
+func (s *exponentiallyDecayingSum) get(now time.Time, window time.Duration) float64 {
+	if s.lastUpdate.IsZero() || !now.After(s.lastUpdate) {
+		return s.value
+	}
+	return s.value * math.Exp(-float64(now.Sub(s.lastUpdate))/float64(window))
+}
+
+// add a sample to the sum.
+func (s *exponentiallyDecayingSum) add(now time.Time, window time.Duration, sample float64) {
+	s.value = s.get(now, window) + sample
+	if now.After(s.lastUpdate) {
+		s.lastUpdate = now
+	}
+}
+
+// getRecommendedWorkersCount computes the number of workers that
+// should be present for this size class queue to keep up with the
+// tasks that are being scheduled, and to drain the tasks that are
+// currently queued within the configured window.
+//
+// Let λ be the arrival rate of tasks, T the average execution duration
+// of tasks, Q the number of queued tasks, and W the window. The
+// recommendation is then λ·T + Q·T/W workers. If no tasks have
+// completed execution recently, T is assumed to be equal to W. The
+// recommendation is never lower than the number of workers that are
+// currently executing tasks, as reducing the number of workers below
+// that would cause tasks to be interrupted.
+func (scq *sizeClassQueue) getRecommendedWorkersCount(bq *InMemoryBuildQueue) uint32 {
+	window := bq.configuration.WorkerCountRecommendationWindow
+	if window <= 0 {
+		return 0
+	}
+
+	windowSeconds := window.Seconds()
+	arrivalRate := scq.tasksArrived.get(bq.now, window) / windowSeconds
+	executionDuration := windowSeconds
+	if tasksExecuted := scq.tasksExecuted.get(bq.now, window); tasksExecuted > 0 {
+		executionDuration = scq.tasksExecutingDurationSum.get(bq.now, window) / tasksExecuted
+	}
+	recommendation := math.Ceil(
+		arrivalRate*executionDuration +
+			float64(scq.queuedTasksCount)*executionDuration/windowSeconds)
+
+	if executingWorkersCount := float64(len(scq.rootInvocation.executingWorkers)); recommendation < executingWorkersCount {
+		recommendation = executingWorkersCount
+	}
+	if recommendation > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(recommendation)
+}
+
+type recommendedWorkersCollector struct {
+	bq *InMemoryBuildQueue
+}
+
+// NewRecommendedWorkersCollector returns a Prometheus collector that
+// exposes the recommended number of workers for every size class
+// queue, so that it may be used to drive autoscaling (e.g., using a
+// Kubernetes Horizontal Pod Autoscaler backed by an external metrics
+// adapter). Recommendations are only computed if
+// WorkerCountRecommendationWindow is set.
+//
+// As opposed to the other metrics of InMemoryBuildQueue, this
+// collector is not registered automatically, as its values are
+// computed on demand from the state of a specific instance.
+func (bq *InMemoryBuildQueue) NewRecommendedWorkersCollector() prometheus.Collector {
+	return recommendedWorkersCollector{bq: bq}
+}
+
+func (c recommendedWorkersCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- inMemoryBuildQueueRecommendedWorkersDesc
+}
+
+func (c recommendedWorkersCollector) Collect(ch chan<- prometheus.Metric) {
+	bq := c.bq
+	bq.enter(bq.clock.Now())
+	defer bq.leave()
+
+	for _, scq := range bq.sizeClassQueues {
+		instanceNamePrefix, platformStr, sizeClassStr := scq.platformQueue.getSizeClassQueueLabels(scq.sizeClass)
+		ch <- prometheus.MustNewConstMetric(
+			inMemoryBuildQueueRecommendedWorkersDesc,
+			prometheus.GaugeValue,
+			float64(scq.getRecommendedWorkersCount(bq)),
+			instanceNamePrefix,
+			platformStr,
+			sizeClassStr)
+	}
+}
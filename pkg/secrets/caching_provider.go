@@ -0,0 +1,58 @@
+package secrets
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/clock"
+)
+
+type cachedSecret struct {
+	value      string
+	expiration time.Time
+}
+
+type cachingProvider struct {
+	base          Provider
+	clock         clock.Clock
+	cacheDuration time.Duration
+
+	lock    sync.Mutex
+	secrets map[string]cachedSecret
+}
+
+// NewCachingProvider creates a decorator for Provider that caches
+// values of secrets in memory for a fixed amount of time. This
+// prevents secrets backends from being contacted for every action
+// that is executed. Failures to obtain secrets are not cached.
+func NewCachingProvider(base Provider, clock clock.Clock, cacheDuration time.Duration) Provider {
+	return &cachingProvider{
+		base:          base,
+		clock:         clock,
+		cacheDuration: cacheDuration,
+		secrets:       map[string]cachedSecret{},
+	}
+}
+
+func (p *cachingProvider) GetSecret(ctx context.Context, name string) (string, error) {
+	p.lock.Lock()
+	if secret, ok := p.secrets[name]; ok && p.clock.Now().Before(secret.expiration) {
+		p.lock.Unlock()
+		return secret.value, nil
+	}
+	p.lock.Unlock()
+
+	value, err := p.base.GetSecret(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	p.lock.Lock()
+	p.secrets[name] = cachedSecret{
+		value:      value,
+		expiration: p.clock.Now().Add(p.cacheDuration),
+	}
+	p.lock.Unlock()
+	return value, nil
+}
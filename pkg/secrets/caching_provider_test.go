@@ -0,0 +1,54 @@
+package secrets_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/secrets"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCachingProvider(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseProvider := mock.NewMockProvider(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	provider := secrets.NewCachingProvider(baseProvider, clock, time.Minute)
+
+	t.Run("FailureNotCached", func(t *testing.T) {
+		baseProvider.EXPECT().GetSecret(ctx, "signing-key").Return("", status.Error(codes.Unavailable, "Server offline"))
+		_, err := provider.GetSecret(ctx, "signing-key")
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Server offline"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		baseProvider.EXPECT().GetSecret(ctx, "signing-key").Return("hunter2", nil)
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		value, err := provider.GetSecret(ctx, "signing-key")
+		require.NoError(t, err)
+		require.Equal(t, "hunter2", value)
+	})
+
+	t.Run("Cached", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1059, 0))
+		value, err := provider.GetSecret(ctx, "signing-key")
+		require.NoError(t, err)
+		require.Equal(t, "hunter2", value)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1060, 0))
+		baseProvider.EXPECT().GetSecret(ctx, "signing-key").Return("hunter3", nil)
+		clock.EXPECT().Now().Return(time.Unix(1060, 0))
+		value, err := provider.GetSecret(ctx, "signing-key")
+		require.NoError(t, err)
+		require.Equal(t, "hunter3", value)
+	})
+}
@@ -0,0 +1,57 @@
+package secrets
+
+import (
+	pb "github.com/buildbarn/bb-remote-execution/pkg/proto/configuration/secrets"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	bb_http "github.com/buildbarn/bb-storage/pkg/http"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewProviderFromConfiguration creates a Provider based on parameters
+// provided in a configuration file.
+func NewProviderFromConfiguration(configuration *pb.ProviderConfiguration) (Provider, error) {
+	if configuration == nil {
+		return nil, status.Error(codes.InvalidArgument, "No secrets provider configuration provided")
+	}
+
+	var provider Provider
+	switch backend := configuration.Backend.(type) {
+	case *pb.ProviderConfiguration_Static:
+		provider = NewStaticProvider(backend.Static.Secrets)
+	case *pb.ProviderConfiguration_Vault:
+		if backend.Vault.Address == "" {
+			return nil, status.Error(codes.InvalidArgument, "No Vault address provided")
+		}
+		roundTripper, err := bb_http.NewRoundTripperFromConfiguration(backend.Vault.HttpClient)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to create Vault HTTP client")
+		}
+		mountPath := backend.Vault.MountPath
+		if mountPath == "" {
+			mountPath = "secret"
+		}
+		provider = NewVaultProvider(roundTripper, backend.Vault.Address, mountPath, backend.Vault.TokenPath)
+	case *pb.ProviderConfiguration_GcpSecretManager:
+		if backend.GcpSecretManager.Project == "" {
+			return nil, status.Error(codes.InvalidArgument, "No Google Cloud project provided")
+		}
+		roundTripper, err := bb_http.NewRoundTripperFromConfiguration(backend.GcpSecretManager.HttpClient)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to create Secret Manager HTTP client")
+		}
+		provider = NewGCPSecretManagerProvider(roundTripper, clock.SystemClock, GCPMetadataServerURL, GCPSecretManagerURL, backend.GcpSecretManager.Project)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "Configuration did not contain a supported secrets provider")
+	}
+
+	if cacheDuration := configuration.CacheDuration; cacheDuration != nil {
+		if err := cacheDuration.CheckValid(); err != nil {
+			return nil, util.StatusWrap(err, "Invalid cache duration")
+		}
+		provider = NewCachingProvider(provider, clock.SystemClock, cacheDuration.AsDuration())
+	}
+	return provider, nil
+}
@@ -0,0 +1,142 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// GCPMetadataServerURL is the URL of the metadata server that
+	// is available on Google Compute Engine instances.
+	GCPMetadataServerURL = "http://metadata.google.internal"
+
+	// GCPSecretManagerURL is the URL of the Google Cloud Secret
+	// Manager API.
+	GCPSecretManagerURL = "https://secretmanager.googleapis.com"
+)
+
+type gcpSecretManagerProvider struct {
+	client            *http.Client
+	clock             clock.Clock
+	metadataServerURL string
+	secretManagerURL  string
+	project           string
+
+	lock                  sync.Mutex
+	accessToken           string
+	accessTokenExpiration time.Time
+}
+
+// NewGCPSecretManagerProvider creates a Provider that obtains secrets
+// from Google Cloud Secret Manager. Secret names correspond to secret
+// IDs, optionally followed by "/versions/${version}". When no version
+// is provided, the latest version of the secret is returned.
+//
+// Requests are authenticated using access tokens of the default
+// service account of the instance, which are obtained from the
+// metadata server.
+func NewGCPSecretManagerProvider(roundTripper http.RoundTripper, clock clock.Clock, metadataServerURL, secretManagerURL, project string) Provider {
+	return &gcpSecretManagerProvider{
+		client: &http.Client{
+			Transport: roundTripper,
+		},
+		clock:             clock,
+		metadataServerURL: strings.TrimSuffix(metadataServerURL, "/"),
+		secretManagerURL:  strings.TrimSuffix(secretManagerURL, "/"),
+		project:           project,
+	}
+}
+
+// getAccessToken returns an access token that can be used to call
+// into Secret Manager. Access tokens are cached until shortly before
+// they expire.
+func (p *gcpSecretManagerProvider) getAccessToken(ctx context.Context) (string, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := p.clock.Now()
+	if p.accessToken != "" && now.Before(p.accessTokenExpiration) {
+		return p.accessToken, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.metadataServerURL+"/computeMetadata/v1/instance/service-accounts/default/token", nil)
+	if err != nil {
+		return "", util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to create HTTP request")
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", util.StatusWrapWithCode(err, codes.Unavailable, "Failed to contact metadata server")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", status.Errorf(codes.Unavailable, "Failed to obtain access token from metadata server: HTTP %s", resp.Status)
+	}
+
+	var response struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil || response.AccessToken == "" {
+		return "", status.Error(codes.Internal, "Failed to parse access token response of metadata server")
+	}
+
+	// Renew the access token a minute before it expires, so that
+	// it does not expire while requests are in flight.
+	p.accessToken = response.AccessToken
+	p.accessTokenExpiration = now.Add(time.Duration(response.ExpiresIn)*time.Second - time.Minute)
+	return p.accessToken, nil
+}
+
+func (p *gcpSecretManagerProvider) GetSecret(ctx context.Context, name string) (string, error) {
+	if name == "" {
+		return "", status.Error(codes.InvalidArgument, "Secret name is empty")
+	}
+	accessToken, err := p.getAccessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	versionName := name
+	if !strings.Contains(versionName, "/versions/") {
+		versionName += "/versions/latest"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.secretManagerURL+"/v1/projects/"+p.project+"/secrets/"+versionName+":access", nil)
+	if err != nil {
+		return "", util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to create HTTP request")
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", util.StatusWrapWithCode(err, codes.Unavailable, "Failed to contact Secret Manager")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", newHTTPStatusError(resp, name)
+	}
+
+	var response struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", status.Errorf(codes.Internal, "Failed to parse response for secret %#v", name)
+	}
+	value, err := base64.StdEncoding.DecodeString(response.Payload.Data)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "Failed to decode payload of secret %#v", name)
+	}
+	return string(value), nil
+}
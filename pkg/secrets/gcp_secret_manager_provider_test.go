@@ -0,0 +1,71 @@
+package secrets_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/internal/mock"
+	"github.com/buildbarn/bb-remote-execution/pkg/secrets"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGCPSecretManagerProvider(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	tokenRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/computeMetadata/v1/instance/service-accounts/default/token":
+			require.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+			tokenRequests++
+			w.Write([]byte(`{"access_token": "ya29.token", "expires_in": 3599, "token_type": "Bearer"}`))
+			return
+		}
+		require.Equal(t, "Bearer ya29.token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/v1/projects/my-project/secrets/signing-key/versions/latest:access":
+			w.Write([]byte(`{"name": "projects/123/secrets/signing-key/versions/2", "payload": {"data": "aHVudGVyMg=="}}`))
+		case "/v1/projects/my-project/secrets/signing-key/versions/1:access":
+			w.Write([]byte(`{"name": "projects/123/secrets/signing-key/versions/1", "payload": {"data": "cGFzc3dvcmQ="}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	clock := mock.NewMockClock(ctrl)
+	provider := secrets.NewGCPSecretManagerProvider(http.DefaultTransport, clock, server.URL, server.URL, "my-project")
+
+	t.Run("LatestVersion", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		value, err := provider.GetSecret(ctx, "signing-key")
+		require.NoError(t, err)
+		require.Equal(t, "hunter2", value)
+	})
+
+	t.Run("ExplicitVersion", func(t *testing.T) {
+		// The access token obtained previously should be reused.
+		clock.EXPECT().Now().Return(time.Unix(1100, 0))
+		value, err := provider.GetSecret(ctx, "signing-key/versions/1")
+		require.NoError(t, err)
+		require.Equal(t, "password", value)
+		require.Equal(t, 1, tokenRequests)
+	})
+
+	t.Run("NonExistent", func(t *testing.T) {
+		// The access token is about to expire, meaning a new one
+		// should be obtained.
+		clock.EXPECT().Now().Return(time.Unix(4550, 0))
+		_, err := provider.GetSecret(ctx, "publishing-token")
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Failed to obtain secret \"publishing-token\": HTTP 404 Not Found"), err)
+		require.Equal(t, 2, tokenRequests)
+	})
+}
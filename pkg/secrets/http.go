@@ -0,0 +1,31 @@
+package secrets
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newHTTPStatusError converts the status code of an HTTP response
+// returned by a secrets backend to a gRPC error. The body of the
+// response is deliberately not included in the error, as it may
+// contain sensitive information.
+func newHTTPStatusError(resp *http.Response, name string) error {
+	var code codes.Code
+	switch resp.StatusCode {
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	default:
+		code = codes.Unavailable
+	}
+	return status.Errorf(code, "Failed to obtain secret %#v: HTTP %s", name, resp.Status)
+}
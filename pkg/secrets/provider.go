@@ -0,0 +1,15 @@
+package secrets
+
+import (
+	"context"
+)
+
+// Provider of secrets, such as credentials that are needed by build
+// actions to sign or publish artifacts.
+//
+// Implementations must not include the values of secrets in errors
+// that they return, as these may end up being logged or returned to
+// clients.
+type Provider interface {
+	GetSecret(ctx context.Context, name string) (string, error)
+}
@@ -0,0 +1,29 @@
+package secrets
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type staticProvider struct {
+	secrets map[string]string
+}
+
+// NewStaticProvider creates a Provider that returns secrets from a
+// map that is provided upfront. This is typically used to provide
+// secrets that are stored in the configuration file of the worker.
+func NewStaticProvider(secrets map[string]string) Provider {
+	return &staticProvider{
+		secrets: secrets,
+	}
+}
+
+func (p *staticProvider) GetSecret(ctx context.Context, name string) (string, error) {
+	value, ok := p.secrets[name]
+	if !ok {
+		return "", status.Errorf(codes.NotFound, "Secret %#v does not exist", name)
+	}
+	return value, nil
+}
@@ -0,0 +1,83 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type vaultProvider struct {
+	client    *http.Client
+	address   string
+	mountPath string
+	tokenPath string
+}
+
+// NewVaultProvider creates a Provider that obtains secrets from the
+// KV version 2 secrets engine of HashiCorp Vault. Secret names have
+// the form "path#key", where "path" is the path of the secret within
+// the secrets engine and "key" is the key within the secret whose
+// value should be returned.
+//
+// The Vault token is read from a file every time a secret is
+// obtained, so that it may be rotated by an external process.
+func NewVaultProvider(roundTripper http.RoundTripper, address, mountPath, tokenPath string) Provider {
+	return &vaultProvider{
+		client: &http.Client{
+			Transport: roundTripper,
+		},
+		address:   strings.TrimSuffix(address, "/"),
+		mountPath: strings.Trim(mountPath, "/"),
+		tokenPath: tokenPath,
+	}
+}
+
+func (p *vaultProvider) GetSecret(ctx context.Context, name string) (string, error) {
+	secretPath, key, ok := strings.Cut(name, "#")
+	if !ok || secretPath == "" || key == "" {
+		return "", status.Errorf(codes.InvalidArgument, "Secret name %#v does not have the form \"path#key\"", name)
+	}
+	token, err := os.ReadFile(p.tokenPath)
+	if err != nil {
+		return "", util.StatusWrapWithCode(err, codes.Internal, "Failed to read Vault token")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.address+"/v1/"+p.mountPath+"/data/"+strings.Trim(secretPath, "/"), nil)
+	if err != nil {
+		return "", util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to create HTTP request")
+	}
+	req.Header.Set("X-Vault-Token", strings.TrimSpace(string(token)))
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", util.StatusWrapWithCode(err, codes.Unavailable, "Failed to contact Vault")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", newHTTPStatusError(resp, name)
+	}
+
+	var response struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", status.Errorf(codes.Internal, "Failed to parse response for secret %#v", name)
+	}
+	value, ok := response.Data.Data[key]
+	if !ok {
+		return "", status.Errorf(codes.NotFound, "Secret %#v does not exist", name)
+	}
+	stringValue, ok := value.(string)
+	if !ok {
+		return "", status.Errorf(codes.InvalidArgument, "Secret %#v is not a string", name)
+	}
+	return stringValue, nil
+}
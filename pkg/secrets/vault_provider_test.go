@@ -0,0 +1,73 @@
+package secrets_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/secrets"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestVaultProvider(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv/data/release/signing":
+			w.Write([]byte(`{"data": {"data": {"private_key": "hunter2", "retries": 3}, "metadata": {"version": 4}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("s.token\n"), 0o600))
+	provider := secrets.NewVaultProvider(http.DefaultTransport, server.URL+"/", "/kv/", tokenPath)
+
+	t.Run("InvalidName", func(t *testing.T) {
+		_, err := provider.GetSecret(ctx, "release/signing")
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Secret name \"release/signing\" does not have the form \"path#key\""), err)
+	})
+
+	t.Run("NonExistentPath", func(t *testing.T) {
+		_, err := provider.GetSecret(ctx, "release/publishing#token")
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Failed to obtain secret \"release/publishing#token\": HTTP 404 Not Found"), err)
+	})
+
+	t.Run("NonExistentKey", func(t *testing.T) {
+		_, err := provider.GetSecret(ctx, "release/signing#public_key")
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Secret \"release/signing#public_key\" does not exist"), err)
+	})
+
+	t.Run("NonStringValue", func(t *testing.T) {
+		// The value of the secret should not be part of the error.
+		_, err := provider.GetSecret(ctx, "release/signing#retries")
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Secret \"release/signing#retries\" is not a string"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		value, err := provider.GetSecret(ctx, "release/signing#private_key")
+		require.NoError(t, err)
+		require.Equal(t, "hunter2", value)
+	})
+
+	t.Run("RotatedToken", func(t *testing.T) {
+		// The token is read every time a secret is obtained.
+		require.NoError(t, os.WriteFile(tokenPath, []byte("s.revoked"), 0o600))
+		_, err := provider.GetSecret(ctx, "release/signing#private_key")
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Failed to obtain secret \"release/signing#private_key\": HTTP 403 Forbidden"), err)
+	})
+}
@@ -0,0 +1,100 @@
+package sync
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+type prioritySemaphoreWaiter struct {
+	priority int
+	wakeup   chan struct{}
+}
+
+// PrioritySemaphore is a counting semaphore, where callers that block
+// on acquiring the semaphore have an associated priority. When the
+// semaphore is released, it is handed out to the waiter with the
+// highest priority. Waiters with the same priority are woken up in
+// FIFO order.
+//
+// This type can be used to let different kinds of work share a single
+// bounded pool of resources, while ensuring that latency sensitive work
+// does not get starved by work that is less important.
+type PrioritySemaphore struct {
+	lock      sync.Mutex
+	available int64
+	waiters   []*prioritySemaphoreWaiter
+}
+
+// NewPrioritySemaphore creates a PrioritySemaphore that can be
+// acquired at most n times concurrently.
+func NewPrioritySemaphore(n int64) *PrioritySemaphore {
+	return &PrioritySemaphore{
+		available: n,
+	}
+}
+
+// Acquire the semaphore, blocking if it is not available. If the
+// provided context is canceled before the semaphore is acquired, the
+// context's error is returned.
+func (s *PrioritySemaphore) Acquire(ctx context.Context, priority int) error {
+	s.lock.Lock()
+	if s.available > 0 {
+		// Release() hands out the semaphore to waiters
+		// directly, meaning that there cannot be any waiters if
+		// the semaphore is available.
+		s.available--
+		s.lock.Unlock()
+		return nil
+	}
+
+	// Insert the waiter after all waiters with the same or a
+	// higher priority.
+	w := &prioritySemaphoreWaiter{
+		priority: priority,
+		wakeup:   make(chan struct{}),
+	}
+	i := sort.Search(len(s.waiters), func(i int) bool { return s.waiters[i].priority < priority })
+	s.waiters = append(s.waiters, nil)
+	copy(s.waiters[i+1:], s.waiters[i:])
+	s.waiters[i] = w
+	s.lock.Unlock()
+
+	select {
+	case <-w.wakeup:
+		return nil
+	case <-ctx.Done():
+		s.lock.Lock()
+		select {
+		case <-w.wakeup:
+			// Release() handed out the semaphore to us
+			// while the context got canceled. Return it.
+			s.lock.Unlock()
+			s.Release()
+		default:
+			for i, wOther := range s.waiters {
+				if wOther == w {
+					s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+					break
+				}
+			}
+			s.lock.Unlock()
+		}
+		return ctx.Err()
+	}
+}
+
+// Release the semaphore, thereby waking up the waiter with the highest
+// priority.
+func (s *PrioritySemaphore) Release() {
+	s.lock.Lock()
+	if len(s.waiters) > 0 {
+		w := s.waiters[0]
+		s.waiters[0] = nil
+		s.waiters = s.waiters[1:]
+		close(w.wakeup)
+	} else {
+		s.available++
+	}
+	s.lock.Unlock()
+}
@@ -0,0 +1,40 @@
+package sync_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/sync"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrioritySemaphore(t *testing.T) {
+	ctx := context.Background()
+	semaphore := sync.NewPrioritySemaphore(2)
+
+	// The semaphore can be acquired twice without blocking.
+	require.NoError(t, semaphore.Acquire(ctx, 0))
+	require.NoError(t, semaphore.Acquire(ctx, 5))
+
+	// Further attempts should block until the context is canceled.
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.Equal(t, context.Canceled, semaphore.Acquire(canceledCtx, 10))
+
+	// A waiter that is blocked should be woken up when the
+	// semaphore is released.
+	acquired := make(chan struct{})
+	go func() {
+		require.NoError(t, semaphore.Acquire(ctx, 3))
+		close(acquired)
+	}()
+	semaphore.Release()
+	<-acquired
+
+	// Canceled attempts should not have leaked any capacity.
+	semaphore.Release()
+	semaphore.Release()
+	require.NoError(t, semaphore.Acquire(ctx, 0))
+	require.NoError(t, semaphore.Acquire(ctx, 0))
+	require.Equal(t, context.Canceled, semaphore.Acquire(canceledCtx, 0))
+}